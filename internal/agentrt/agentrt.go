@@ -0,0 +1,227 @@
+// Package agentrt adapts gt to the agent runtimes it runs in tmux
+// sessions: Claude Code, Codex, Gemini CLI, and Aider.
+//
+// Runtimes differ in the process that shows in the pane, the prompt they
+// show when waiting for input, how they take a startup prompt, and how
+// they treat pasted text. An Adapter hides those differences from the code
+// that launches agents, nudges them, and watches for them to finish.
+// Custom runtimes (a town's "agents" or settings/agents.json) get a
+// generic adapter that recognizes the runtime by its command's name.
+package agentrt
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/tmux"
+)
+
+// Adapter drives one runtime in a tmux session.
+type Adapter interface {
+	// Name returns the runtime's name, e.g. "claude".
+	Name() string
+
+	// Launch starts the runtime by typing command into the session's
+	// shell and waits for it to come up. A non-empty prompt is typed in
+	// once the runtime is ready, for runtimes that take no prompt on their
+	// command line (see config.CrewTypedPrompt).
+	Launch(t *tmux.Tmux, session, command, prompt string) error
+
+	// Nudge sends the runtime a message as if typed and submitted.
+	Nudge(t *tmux.Tmux, session, message string) error
+
+	// Running reports whether the runtime is running in the session.
+	Running(t *tmux.Tmux, session string) bool
+
+	// Exited reports whether the runtime has exited, leaving the session
+	// at a shell prompt: the agent's run is complete.
+	Exited(t *tmux.Tmux, session string) bool
+
+	// Ready reports whether a pane's last lines show the runtime waiting
+	// for input.
+	Ready(lines []string) bool
+}
+
+// adapter is an Adapter described by data: every built-in runtime is one.
+type adapter struct {
+	name string
+
+	// processes are the pane commands the runtime runs as.
+	processes []string
+
+	// versioned means the runtime may instead show its version as its
+	// process title (Claude Code's native build does).
+	versioned bool
+
+	// ready reports whether a trimmed pane line is the runtime's prompt.
+	ready func(line string) bool
+
+	// singleLine means the runtime submits at every newline of pasted
+	// text, so nudges are sent as one line.
+	singleLine bool
+}
+
+// builtin are the adapters for the preset runtimes.
+var builtin = map[string]*adapter{
+	string(config.AgentClaude): {
+		name:      string(config.AgentClaude),
+		processes: []string{"node", "claude"},
+		versioned: true,
+		ready:     arrowPrompt,
+	},
+	string(config.AgentCodex): {
+		name:      string(config.AgentCodex),
+		processes: []string{"codex", "node"},
+		ready: func(line string) bool {
+			return strings.HasPrefix(line, "›") || strings.HasPrefix(line, "▌")
+		},
+	},
+	string(config.AgentGemini): {
+		name:      string(config.AgentGemini),
+		processes: []string{"node", "gemini"},
+		ready: func(line string) bool {
+			return arrowPrompt(line) || strings.Contains(line, "Type your message")
+		},
+	},
+	string(config.AgentAider): {
+		name:      string(config.AgentAider),
+		processes: []string{"aider", "python", "python3"},
+		ready: func(line string) bool {
+			// "> ", or the edit format's prompt: "architect> ", "diff> "
+			return strings.HasSuffix(line, ">") && !strings.Contains(line, " ")
+		},
+		singleLine: true,
+	},
+}
+
+// versionTitle matches a process title that is a version, e.g. "2.0.14".
+var versionTitle = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+// arrowPrompt matches the "> " input prompt.
+func arrowPrompt(line string) bool {
+	return strings.HasPrefix(line, "> ") || line == ">"
+}
+
+// For returns the adapter for the named runtime. Runtimes without one of
+// their own get a generic adapter for the town's configuration of them.
+func For(townRoot, name string) Adapter {
+	if a, ok := builtin[name]; ok {
+		return a
+	}
+	command := name
+	_ = config.LoadAgentRegistry(config.DefaultAgentRegistryPath(townRoot))
+	if preset := config.GetAgentPresetByName(name); preset != nil && preset.Command != "" {
+		command = preset.Command
+	}
+	if townSettings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		if rc := townSettings.Agents[name]; rc != nil && rc.Command != "" {
+			command = rc.Command // town custom agents override presets
+		}
+	}
+	return &adapter{
+		name:      name,
+		processes: []string{filepath.Base(command), "node"},
+		ready:     arrowPrompt,
+	}
+}
+
+// ForAgent returns the adapter for the runtime an agent runs, for an
+// address like "gastown/crew/max". Agents run in a sandbox count as
+// running while their container is, and agents run on a remote host while
+// their ssh connection is.
+func ForAgent(townRoot, agent string) Adapter {
+	a := For(townRoot, config.ResolveAgentRuntime(townRoot, agent))
+	if config.AgentSandbox(townRoot, agent) != "" {
+		return sandboxed{a}
+	}
+	if config.AgentHost(townRoot, agent) != "" {
+		return remote{a}
+	}
+	return a
+}
+
+// sandboxed adapts a runtime run in a container, whose pane shows the
+// container client ("docker") rather than the runtime.
+type sandboxed struct {
+	Adapter
+}
+
+func (s sandboxed) Running(t *tmux.Tmux, session string) bool {
+	cmd, err := t.GetPaneCommand(session)
+	return err == nil && cmd == config.SandboxDocker
+}
+
+// remote adapts a runtime run on another host, whose pane shows the ssh
+// client rather than the runtime.
+type remote struct {
+	Adapter
+}
+
+func (r remote) Running(t *tmux.Tmux, session string) bool {
+	cmd, err := t.GetPaneCommand(session)
+	return err == nil && cmd == "ssh"
+}
+
+func (a *adapter) Name() string {
+	return a.name
+}
+
+func (a *adapter) Launch(t *tmux.Tmux, session, command, prompt string) error {
+	if err := t.SendKeys(session, command); err != nil {
+		return fmt.Errorf("starting %s: %w", a.name, err)
+	}
+	if err := t.WaitForCommand(session, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+		return fmt.Errorf("waiting for %s to start: %w", a.name, err)
+	}
+	if prompt == "" {
+		return nil
+	}
+	if err := WaitReady(t, a, session, constants.ClaudeStartTimeout); err != nil {
+		return err
+	}
+	return a.Nudge(t, session, prompt)
+}
+
+func (a *adapter) Nudge(t *tmux.Tmux, session, message string) error {
+	if a.singleLine {
+		message = strings.Join(strings.FieldsFunc(message, func(r rune) bool { return r == '\n' || r == '\r' }), " ")
+	}
+	return t.NudgeSession(session, message)
+}
+
+func (a *adapter) Running(t *tmux.Tmux, session string) bool {
+	cmd, err := t.GetPaneCommand(session)
+	return err == nil && (slices.Contains(a.processes, cmd) || a.versioned && versionTitle.MatchString(cmd))
+}
+
+func (a *adapter) Exited(t *tmux.Tmux, session string) bool {
+	cmd, err := t.GetPaneCommand(session)
+	return err == nil && slices.Contains(constants.SupportedShells, cmd)
+}
+
+func (a *adapter) Ready(lines []string) bool {
+	for _, line := range lines {
+		if a.ready(strings.TrimSpace(line)) {
+			return true
+		}
+	}
+	return false
+}
+
+// WaitReady polls the session until a sees its runtime waiting for input.
+func WaitReady(t *tmux.Tmux, a Adapter, session string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if lines, err := t.CapturePaneLines(session, 10); err == nil && a.Ready(lines) {
+			return nil
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return fmt.Errorf("timeout waiting for %s prompt", a.Name())
+}
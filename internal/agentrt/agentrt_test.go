@@ -0,0 +1,58 @@
+package agentrt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ctiospl/gastown/internal/config"
+)
+
+func TestFor(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, name := range []string{"claude", "codex", "gemini", "aider"} {
+		if got := For(townRoot, name).Name(); got != name {
+			t.Errorf("For(%s).Name() = %s", name, got)
+		}
+	}
+
+	path := config.TownSettingsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"agents": {"opencode": {"command": "/opt/bin/opencode"}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a := For(townRoot, "opencode").(*adapter)
+	if a.Name() != "opencode" || a.processes[0] != "opencode" {
+		t.Errorf("custom adapter = %+v, want opencode recognized by its command", a)
+	}
+}
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		runtime string
+		lines   []string
+		want    bool
+	}{
+		{"claude", []string{"Thinking…"}, false},
+		{"claude", []string{"", "> "}, true},
+		{"codex", []string{"› Ask Codex to do anything"}, true},
+		{"codex", []string{"Working (3s)"}, false},
+		{"gemini", []string{"│ >   Type your message or @path/to/file │"}, true},
+		{"aider", []string{"Aider v0.86.1", "architect> "}, true},
+		{"aider", []string{"> "}, true},
+		{"aider", []string{"Tokens: 4.2k sent > budget"}, false},
+	}
+	for _, tt := range tests {
+		if got := For("", tt.runtime).Ready(tt.lines); got != tt.want {
+			t.Errorf("%s Ready(%q) = %v, want %v", tt.runtime, tt.lines, got, tt.want)
+		}
+	}
+}
+
+func TestVersionTitle(t *testing.T) {
+	if !versionTitle.MatchString("2.0.14") || versionTitle.MatchString("node") || versionTitle.MatchString("v2") {
+		t.Error("versionTitle should match only bare versions")
+	}
+}
@@ -0,0 +1,131 @@
+// Package artifact provides a content-addressed file store shared by the
+// town, used for mail attachments and other blobs agents hand each other.
+package artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxSize is the largest artifact the store accepts.
+const MaxSize = 64 << 20 // 64 MiB
+
+// ErrNotFound is returned when no artifact matches an ID.
+var ErrNotFound = errors.New("artifact not found")
+
+// ErrTooLarge is returned when content exceeds MaxSize.
+var ErrTooLarge = fmt.Errorf("artifact exceeds %d MiB limit", MaxSize>>20)
+
+// Store keeps artifacts under <town>/.artifacts/, one file per content
+// hash. Identical content is stored once no matter how often it is added.
+type Store struct {
+	root string
+}
+
+// NewStore returns the artifact store of the town at townRoot.
+func NewStore(townRoot string) *Store {
+	return &Store{root: filepath.Join(townRoot, ".artifacts")}
+}
+
+// Root returns the store's directory.
+func (s *Store) Root() string {
+	return s.root
+}
+
+// Put copies r into the store and returns its ID (the hex SHA-256 of the
+// content) and size. The content is written to a temporary file and
+// renamed into place, so readers never see a partial artifact.
+func (s *Store) Put(r io.Reader) (id string, size int64, err error) {
+	if err := os.MkdirAll(s.root, 0755); err != nil {
+		return "", 0, fmt.Errorf("creating artifact store: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.root, ".incoming-")
+	if err != nil {
+		return "", 0, fmt.Errorf("creating artifact: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	h := sha256.New()
+	size, err = io.Copy(io.MultiWriter(tmp, h), io.LimitReader(r, MaxSize+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("writing artifact: %w", err)
+	}
+	if size > MaxSize {
+		return "", 0, ErrTooLarge
+	}
+
+	id = hex.EncodeToString(h.Sum(nil))
+	path := s.path(id)
+	if _, err := os.Stat(path); err == nil {
+		return id, size, nil // already stored
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", 0, fmt.Errorf("creating artifact store: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0444); err != nil {
+		return "", 0, fmt.Errorf("writing artifact: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, fmt.Errorf("storing artifact: %w", err)
+	}
+	return id, size, nil
+}
+
+// PutFile adds the file at path to the store.
+func (s *Store) PutFile(path string) (id string, size int64, err error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is chosen by the user attaching it
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	return s.Put(f)
+}
+
+// Path returns the file holding the artifact with the given ID or unique
+// ID prefix (at least 8 characters).
+func (s *Store) Path(id string) (string, error) {
+	id = strings.ToLower(id)
+	if len(id) < 8 || strings.Trim(id, "0123456789abcdef") != "" {
+		return "", fmt.Errorf("%w: %q", ErrNotFound, id)
+	}
+	if len(id) == sha256.Size*2 {
+		path := s.path(id)
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return path, nil
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(s.root, id[:2], id+"*"))
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", ErrNotFound, id)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("artifact ID %s is ambiguous", id)
+	}
+}
+
+// Open opens the artifact with the given ID or ID prefix for reading.
+func (s *Store) Open(id string) (*os.File, error) {
+	path, err := s.Path(id)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path) //nolint:gosec // G304: path is inside the store
+}
+
+// path fans artifacts out by the first two hex digits, like git objects.
+func (s *Store) path(id string) string {
+	return filepath.Join(s.root, id[:2], id)
+}
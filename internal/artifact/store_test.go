@@ -0,0 +1,52 @@
+package artifact
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStorePutAndOpen(t *testing.T) {
+	s := NewStore(t.TempDir())
+
+	id, size, err := s.Put(strings.NewReader("diff --git a/x b/x\n"))
+	if err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	if len(id) != 64 || size != 19 {
+		t.Fatalf("Put() = %q, %d", id, size)
+	}
+
+	// Same content is stored once under the same ID.
+	again, _, err := s.Put(strings.NewReader("diff --git a/x b/x\n"))
+	if err != nil || again != id {
+		t.Fatalf("second Put() = %q, %v; want %q", again, err, id)
+	}
+
+	f, err := s.Open(id[:12])
+	if err != nil {
+		t.Fatalf("Open(prefix) error: %v", err)
+	}
+	defer f.Close()
+	data, _ := io.ReadAll(f)
+	if string(data) != "diff --git a/x b/x\n" {
+		t.Errorf("content = %q", data)
+	}
+
+	if _, err := s.Path("deadbeefdeadbeef"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Path(unknown) error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Path("abc"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Path(short) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStorePutTooLarge(t *testing.T) {
+	s := NewStore(t.TempDir())
+	big := io.LimitReader(bytes.NewReader(make([]byte, MaxSize+1)), MaxSize+1)
+	if _, _, err := s.Put(big); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Put(oversized) error = %v, want ErrTooLarge", err)
+	}
+}
@@ -10,6 +10,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/gtlog"
 )
 
 // Common errors
@@ -241,7 +244,9 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	start := time.Now()
 	err := cmd.Run()
+	gtlog.Exec("bd", fullArgs, b.workDir, start, err)
 	if err != nil {
 		return nil, b.wrapError(err, stderr.String(), args)
 	}
@@ -236,13 +236,15 @@ close_reason: merged`,
 				Description: `branch: polecat/Toast/gt-abc
 target: integration/gt-epic
 source_issue: gt-abc
-worker: Toast`,
+worker: Toast
+stacked_on: polecat/Nux-m3x9k2`,
 			},
 			wantFields: &MRFields{
 				Branch:      "polecat/Toast/gt-abc",
 				Target:      "integration/gt-epic",
 				SourceIssue: "gt-abc",
 				Worker:      "Toast",
+				StackedOn:   "polecat/Nux-m3x9k2",
 			},
 		},
 		{
@@ -357,6 +359,9 @@ source_issue: gt-pqr`,
 			if fields.CloseReason != tt.wantFields.CloseReason {
 				t.Errorf("CloseReason = %q, want %q", fields.CloseReason, tt.wantFields.CloseReason)
 			}
+			if fields.StackedOn != tt.wantFields.StackedOn {
+				t.Errorf("StackedOn = %q, want %q", fields.StackedOn, tt.wantFields.StackedOn)
+			}
 		})
 	}
 }
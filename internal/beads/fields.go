@@ -177,6 +177,9 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+
+	// Stacked work: the branch this one was built on, which merges first
+	StackedOn string
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -251,6 +254,9 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "stacked_on", "stacked-on", "stackedon":
+			fields.StackedOn = value
+			hasFields = true
 		}
 	}
 
@@ -315,6 +321,9 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.StackedOn != "" {
+		lines = append(lines, "stacked_on: "+fields.StackedOn)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -361,6 +370,9 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at":  true,
 		"convoy-created-at":  true,
 		"convoycreatedat":    true,
+		"stacked_on":         true,
+		"stacked-on":         true,
+		"stackedon":          true,
 	}
 
 	// Collect non-MR lines from existing description
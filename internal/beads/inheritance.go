@@ -0,0 +1,160 @@
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/constants"
+)
+
+// InheritedPriorityLabel marks a bead whose priority was raised because a
+// higher-priority issue is blocked on it.
+const InheritedPriorityLabel = "inherited-priority"
+
+// PriorityInheritance records one blocker running at a blocked issue's
+// priority. Lower numbers are higher priority (P0 is most urgent).
+type PriorityInheritance struct {
+	ID        string    `json:"id"`
+	Original  int       `json:"original"`  // priority before the boost
+	Inherited int       `json:"inherited"` // priority it runs at now
+	From      string    `json:"from"`      // blocked issue the priority came from
+	Assignee  string    `json:"assignee,omitempty"`
+	Since     time.Time `json:"since"`
+}
+
+// InheritanceLedger is the set of active priority boosts, persisted so
+// boosts can be reverted once the blocked issue no longer needs them.
+type InheritanceLedger struct {
+	Boosts map[string]*PriorityInheritance `json:"boosts"`
+}
+
+// InheritanceLedgerPath returns the ledger location for a town.
+func InheritanceLedgerPath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "priority-inheritance.json")
+}
+
+// LoadInheritanceLedger reads the town's ledger, returning an empty one if
+// none exists yet.
+func LoadInheritanceLedger(townRoot string) (*InheritanceLedger, error) {
+	ledger := &InheritanceLedger{Boosts: make(map[string]*PriorityInheritance)}
+	data, err := os.ReadFile(InheritanceLedgerPath(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ledger, nil
+		}
+		return nil, fmt.Errorf("reading inheritance ledger: %w", err)
+	}
+	if err := json.Unmarshal(data, ledger); err != nil {
+		return nil, fmt.Errorf("parsing inheritance ledger: %w", err)
+	}
+	if ledger.Boosts == nil {
+		ledger.Boosts = make(map[string]*PriorityInheritance)
+	}
+	return ledger, nil
+}
+
+// Save writes the ledger atomically.
+func (l *InheritanceLedger) Save(townRoot string) error {
+	path := InheritanceLedgerPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling inheritance ledger: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("writing inheritance ledger: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReconcileInheritance computes which open blockers should inherit the
+// priority of the issues they block (transitively, so a whole chain
+// behind a P0 runs at P0) and updates the ledger to match. It returns the
+// boosts to apply (new or changed) and the boosts to revert, whose blocked
+// issue has been unblocked, closed, or lowered.
+//
+// issues maps ID to every non-closed issue, with BlockedBy filled in for
+// blocked ones. Priorities of issues already in the ledger are taken as
+// boosted; their Original is used as the base.
+func ReconcileInheritance(issues map[string]*Issue, ledger *InheritanceLedger, now time.Time) (apply, revert []PriorityInheritance) {
+	base := func(id string) int {
+		if b, ok := ledger.Boosts[id]; ok {
+			return b.Original
+		}
+		return issues[id].Priority
+	}
+
+	// Propagate effective priority along blocked-by edges to a fixpoint.
+	// Each pass can only raise priorities, so this terminates within
+	// len(issues) passes even if the graph has cycles.
+	effective := make(map[string]int, len(issues))
+	from := make(map[string]string)
+	ids := make([]string, 0, len(issues))
+	for id := range issues {
+		effective[id] = base(id)
+		ids = append(ids, id)
+	}
+	sort.Strings(ids) // deterministic choice of From
+	for pass := 0; pass < len(ids); pass++ {
+		changed := false
+		for _, id := range ids {
+			for _, blocker := range issues[id].BlockedBy {
+				if _, open := issues[blocker]; !open {
+					continue
+				}
+				if effective[id] < effective[blocker] {
+					effective[blocker] = effective[id]
+					if f, ok := from[id]; ok {
+						from[blocker] = f // credit the root of the chain
+					} else {
+						from[blocker] = id
+					}
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	for _, id := range ids {
+		issue := issues[id]
+		want, boosted := from[id]
+		current, recorded := ledger.Boosts[id]
+		switch {
+		case boosted && (!recorded || current.Inherited != effective[id] || current.From != want):
+			b := &PriorityInheritance{
+				ID:        id,
+				Original:  base(id),
+				Inherited: effective[id],
+				From:      want,
+				Assignee:  issue.Assignee,
+				Since:     now,
+			}
+			if recorded && current.Inherited == b.Inherited {
+				b.Since = current.Since
+			}
+			ledger.Boosts[id] = b
+			apply = append(apply, *b)
+		case !boosted && recorded:
+			revert = append(revert, *current)
+			delete(ledger.Boosts, id)
+		}
+	}
+
+	// Boosted issues that closed need no revert, only forgetting.
+	for id := range ledger.Boosts {
+		if _, open := issues[id]; !open {
+			delete(ledger.Boosts, id)
+		}
+	}
+	return apply, revert
+}
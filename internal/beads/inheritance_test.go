@@ -0,0 +1,86 @@
+package beads
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconcileInheritance(t *testing.T) {
+	now := time.Now()
+	issues := map[string]*Issue{
+		"gt-1": {ID: "gt-1", Priority: 0, BlockedBy: []string{"gt-2"}},
+		"gt-2": {ID: "gt-2", Priority: 3, BlockedBy: []string{"gt-3", "gt-closed"}, Assignee: "gastown/Toast"},
+		"gt-3": {ID: "gt-3", Priority: 4},
+		"gt-4": {ID: "gt-4", Priority: 1},
+	}
+	ledger := &InheritanceLedger{Boosts: map[string]*PriorityInheritance{}}
+
+	apply, revert := ReconcileInheritance(issues, ledger, now)
+	if len(revert) != 0 || len(apply) != 2 {
+		t.Fatalf("apply = %+v, revert = %+v", apply, revert)
+	}
+	for _, b := range apply {
+		if b.Inherited != 0 || b.From != "gt-1" {
+			t.Errorf("boost %+v, want P0 inherited from gt-1", b)
+		}
+	}
+	if b := ledger.Boosts["gt-2"]; b == nil || b.Original != 3 || b.Assignee != "gastown/Toast" {
+		t.Errorf("ledger gt-2 = %+v", b)
+	}
+
+	// Applied: bd now reports the boosted priorities. Nothing changes.
+	issues["gt-2"].Priority, issues["gt-3"].Priority = 0, 0
+	apply, revert = ReconcileInheritance(issues, ledger, now)
+	if len(apply)+len(revert) != 0 {
+		t.Errorf("steady state: apply = %+v, revert = %+v", apply, revert)
+	}
+
+	// gt-1 closes: gt-2 reverts to P3, and gt-3 now inherits P3 from gt-2.
+	delete(issues, "gt-1")
+	apply, revert = ReconcileInheritance(issues, ledger, now)
+	if len(revert) != 1 || revert[0].ID != "gt-2" || revert[0].Original != 3 {
+		t.Errorf("after close: revert = %+v", revert)
+	}
+	if len(apply) != 1 || apply[0].ID != "gt-3" || apply[0].Inherited != 3 || apply[0].Original != 4 || apply[0].From != "gt-2" {
+		t.Errorf("after close: apply = %+v", apply)
+	}
+
+	// gt-2 closes too: gt-3 reverts to P4 and the ledger empties.
+	issues["gt-3"].Priority = 3
+	delete(issues, "gt-2")
+	apply, revert = ReconcileInheritance(issues, ledger, now)
+	if len(apply) != 0 || len(revert) != 1 || revert[0].Original != 4 {
+		t.Errorf("after second close: apply = %+v, revert = %+v", apply, revert)
+	}
+	if len(ledger.Boosts) != 0 {
+		t.Errorf("ledger not emptied: %+v", ledger.Boosts)
+	}
+}
+
+func TestReconcileInheritanceCycle(t *testing.T) {
+	issues := map[string]*Issue{
+		"a": {ID: "a", Priority: 1, BlockedBy: []string{"b"}},
+		"b": {ID: "b", Priority: 2, BlockedBy: []string{"a"}},
+	}
+	ledger := &InheritanceLedger{Boosts: map[string]*PriorityInheritance{}}
+	apply, _ := ReconcileInheritance(issues, ledger, time.Now())
+	if len(apply) != 1 || apply[0].ID != "b" || apply[0].Inherited != 1 {
+		t.Errorf("apply = %+v", apply)
+	}
+}
+
+func TestInheritanceLedgerRoundTrip(t *testing.T) {
+	town := t.TempDir()
+	ledger, err := LoadInheritanceLedger(town)
+	if err != nil || len(ledger.Boosts) != 0 {
+		t.Fatalf("Load(empty) = %+v, %v", ledger, err)
+	}
+	ledger.Boosts["gt-2"] = &PriorityInheritance{ID: "gt-2", Original: 3, Inherited: 0, From: "gt-1"}
+	if err := ledger.Save(town); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadInheritanceLedger(town)
+	if err != nil || got.Boosts["gt-2"] == nil || got.Boosts["gt-2"].From != "gt-1" {
+		t.Errorf("Load = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,719 @@
+// Package budget keeps the town's cost budgets: 'gt budget set gastown/
+// 50usd/day' caps what a rig's agents, or one agent, may spend in dollars
+// or tokens each day, week, or month.
+//
+// Spend is tracked from what agents' runtimes report as they run: the
+// session cost and tokens of an agent's metrics file, else the cost its
+// runtime shows in its pane. Each check records how much each running
+// session's cumulative figure grew since the last one in a ledger of
+// spend per agent per day, kept in .runtime/budget-spend.json, so spend
+// outlives the sessions it was made in. Periods start at local midnight,
+// on Mondays, and on the first of the month.
+//
+// A check warns, with a "budget" event, as a budget's spend crosses each
+// of its warning thresholds, once per period. Once a budget is exceeded,
+// its running agents are paused (see gt pause) or killed, as the budget
+// says, and agents it paused are resumed when the next period starts or
+// the budget is raised or removed. Budgets are kept in the town's
+// settings/budgets.json, and the daemon checks them every minute.
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// EventBudget is the town log event recorded when spend crosses one of a
+// budget's thresholds (warn severity), when a budget is exceeded and its
+// agents are stopped (error severity), and when they are resumed.
+const EventBudget townlog.EventType = "budget"
+
+// PausedBy is the By of the pause records of agents paused for exceeding
+// a budget, which tells them apart from agents paused by hand.
+const PausedBy = "budget"
+
+// Unit is what a budget counts.
+type Unit string
+
+// Budget units.
+const (
+	USD    Unit = "usd"
+	Tokens Unit = "tokens"
+)
+
+// Period is how long a budget's spend accrues before starting over.
+type Period string
+
+// Budget periods.
+const (
+	Day   Period = "day"
+	Week  Period = "week"
+	Month Period = "month"
+)
+
+// Action is what happens to a budget's agents once it is exceeded.
+type Action string
+
+// Budget actions.
+const (
+	Pause Action = "pause" // freeze them with gt pause (the default)
+	Kill  Action = "kill"  // stop their sessions with gt kill
+	Warn  Action = "warn"  // only log that the budget is exceeded
+)
+
+// DefaultWarn is the threshold, in percent of the budget, at which a budget
+// without thresholds of its own warns.
+const DefaultWarn = 80
+
+// Budget caps the spend of a rig's agents or one agent in a period.
+type Budget struct {
+	Scope     string    `json:"scope"` // "gastown/" for a rig, or an agent's address
+	Amount    float64   `json:"amount"`
+	Unit      Unit      `json:"unit"`
+	Period    Period    `json:"period"`
+	Action    Action    `json:"action,omitempty"` // "" for Pause
+	Warn      []int     `json:"warn,omitempty"`   // percents; nil for DefaultWarn
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+}
+
+// spec matches a budget spec: "50usd/day", "$50/day", "2mtokens/week".
+var spec = regexp.MustCompile(`^(\$?)(\d+(?:\.\d+)?)([km]?)(usd|tokens)?/(day|week|month)$`)
+
+// ParseSpec parses a budget spec, an amount and unit per period: "50usd/day"
+// or "$50/day" for dollars, "2000000tokens/week" or "2mtokens/week" for
+// tokens (k and m multiply by a thousand and a million).
+func ParseSpec(s string) (amount float64, unit Unit, period Period, err error) {
+	m := spec.FindStringSubmatch(strings.ToLower(strings.ReplaceAll(s, " ", "")))
+	if m == nil || (m[1] == "" && m[4] == "") || (m[1] == "$" && m[4] == string(Tokens)) {
+		return 0, "", "", fmt.Errorf("invalid budget %q: use e.g. 50usd/day, $20/week, or 2mtokens/month", s)
+	}
+	amount, _ = strconv.ParseFloat(m[2], 64)
+	switch m[3] {
+	case "k":
+		amount *= 1e3
+	case "m":
+		amount *= 1e6
+	}
+	unit = USD
+	if m[4] == string(Tokens) {
+		unit = Tokens
+	}
+	if amount <= 0 {
+		return 0, "", "", fmt.Errorf("invalid budget %q: the amount must be positive", s)
+	}
+	return amount, unit, Period(m[5]), nil
+}
+
+// Spec returns the budget's amount and unit per period, e.g. "50usd/day"
+// or "2mtokens/week".
+func (b Budget) Spec() string {
+	return FormatAmount(b.Amount, b.Unit) + "/" + string(b.Period)
+}
+
+// FormatAmount formats an amount of unit as a spec does: "12.5usd",
+// "2mtokens", "500ktokens".
+func FormatAmount(amount float64, unit Unit) string {
+	if unit == Tokens {
+		switch {
+		case amount >= 1e6 && math.Mod(amount, 1e5) == 0:
+			return strconv.FormatFloat(amount/1e6, 'f', -1, 64) + "mtokens"
+		case amount >= 1e3 && math.Mod(amount, 1e2) == 0:
+			return strconv.FormatFloat(amount/1e3, 'f', -1, 64) + "ktokens"
+		}
+		return strconv.FormatFloat(amount, 'f', 0, 64) + "tokens"
+	}
+	return strconv.FormatFloat(amount, 'f', -1, 64) + "usd"
+}
+
+// FormatSpend formats spend of unit for people: "$12.34" or "1.2M tokens".
+func FormatSpend(amount float64, unit Unit) string {
+	if unit == Tokens {
+		switch {
+		case amount >= 1e6:
+			return fmt.Sprintf("%.1fM tokens", amount/1e6)
+		case amount >= 1e3:
+			return fmt.Sprintf("%.1fk tokens", amount/1e3)
+		}
+		return fmt.Sprintf("%.0f tokens", amount)
+	}
+	return fmt.Sprintf("$%.2f", amount)
+}
+
+// String describes the budget, e.g. "50usd/day for gastown/".
+func (b Budget) String() string {
+	return b.Spec() + " for " + b.Scope
+}
+
+// Key identifies the budget: one scope has at most one budget of each
+// unit and period.
+func (b Budget) Key() string {
+	return b.Scope + " " + string(b.Unit) + "/" + string(b.Period)
+}
+
+// ActionOrDefault returns what happens once the budget is exceeded.
+func (b Budget) ActionOrDefault() Action {
+	if b.Action == "" {
+		return Pause
+	}
+	return b.Action
+}
+
+// Thresholds returns the budget's warning thresholds, in percent, lowest
+// first.
+func (b Budget) Thresholds() []int {
+	if len(b.Warn) == 0 {
+		return []int{DefaultWarn}
+	}
+	out := append([]int{}, b.Warn...)
+	sort.Ints(out)
+	return out
+}
+
+// NormalizeScope checks a budget scope, a rig as "gastown/" or an agent's
+// address, and returns it in canonical form.
+func NormalizeScope(scope string) (string, error) {
+	if rig, ok := strings.CutSuffix(scope, "/"); ok && rig != "" && !strings.Contains(rig, "/") && rig != "mayor" && rig != "deacon" {
+		return scope, nil
+	}
+	id, err := session.ParseAddress(strings.TrimSuffix(scope, "/"))
+	if err != nil {
+		return "", fmt.Errorf("invalid budget scope %q: use a rig, as gastown/, or an agent's address", scope)
+	}
+	return id.Address(), nil
+}
+
+// Covers reports whether agent's spend counts against the budget.
+func (b Budget) Covers(agent string) bool {
+	if strings.HasSuffix(b.Scope, "/") {
+		return strings.HasPrefix(agent, b.Scope)
+	}
+	return townlog.AgentKey(agent) == townlog.AgentKey(b.Scope)
+}
+
+// Validate checks the budget's scope, amount, unit, period, action, and
+// thresholds.
+func (b Budget) Validate() error {
+	if _, err := NormalizeScope(b.Scope); err != nil {
+		return err
+	}
+	if b.Amount <= 0 {
+		return fmt.Errorf("a budget must be positive")
+	}
+	if b.Unit != USD && b.Unit != Tokens {
+		return fmt.Errorf("unknown budget unit %q: must be %s or %s", b.Unit, USD, Tokens)
+	}
+	if b.Period != Day && b.Period != Week && b.Period != Month {
+		return fmt.Errorf("unknown budget period %q: must be %s, %s, or %s", b.Period, Day, Week, Month)
+	}
+	switch b.ActionOrDefault() {
+	case Pause, Kill, Warn:
+	default:
+		return fmt.Errorf("unknown budget action %q: must be %s, %s, or %s", b.Action, Pause, Kill, Warn)
+	}
+	for _, w := range b.Warn {
+		if w <= 0 || w >= 100 {
+			return fmt.Errorf("warning threshold %d%% must be between 0 and 100", w)
+		}
+	}
+	return nil
+}
+
+// PeriodStart returns when the period of p containing t started, in t's
+// location.
+func PeriodStart(p Period, t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch p {
+	case Week:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)) // back to Monday
+	case Month:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+// Path returns where a town's budgets are kept.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "budgets.json")
+}
+
+type budgetsFile struct {
+	Budgets []Budget `json:"budgets"`
+}
+
+// Load returns the town's budgets, ordered by scope.
+func Load(townRoot string) ([]Budget, error) {
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var f budgetsFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", Path(townRoot), err)
+	}
+	sort.SliceStable(f.Budgets, func(i, k int) bool { return f.Budgets[i].Key() < f.Budgets[k].Key() })
+	return f.Budgets, nil
+}
+
+func save(townRoot string, list []Budget) error {
+	if err := os.MkdirAll(filepath.Dir(Path(townRoot)), 0755); err != nil {
+		return err
+	}
+	if list == nil {
+		list = []Budget{}
+	}
+	return util.AtomicWriteJSON(Path(townRoot), budgetsFile{Budgets: list})
+}
+
+// Set validates b and saves it, replacing the budget of its scope with the
+// same unit and period, which it returns if there was one.
+func Set(townRoot string, b Budget) (*Budget, error) {
+	scope, err := NormalizeScope(b.Scope)
+	if err != nil {
+		return nil, err
+	}
+	b.Scope = scope
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	list, err := Load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var replaced *Budget
+	for i, other := range list {
+		if other.Key() == b.Key() {
+			replaced = &list[i]
+			list = append(list[:i:i], list[i+1:]...)
+			break
+		}
+	}
+	return replaced, save(townRoot, append(list, b))
+}
+
+// Remove deletes the budgets of scope, only those of the given unit and
+// period if they are set, and returns them.
+func Remove(townRoot, scope string, unit Unit, period Period) ([]Budget, error) {
+	scope, err := NormalizeScope(scope)
+	if err != nil {
+		return nil, err
+	}
+	list, err := Load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var removed, rest []Budget
+	for _, b := range list {
+		if b.Scope == scope && (unit == "" || b.Unit == unit) && (period == "" || b.Period == period) {
+			removed = append(removed, b)
+		} else {
+			rest = append(rest, b)
+		}
+	}
+	if len(removed) == 0 {
+		return nil, fmt.Errorf("no budget for %s", scope)
+	}
+	return removed, save(townRoot, rest)
+}
+
+// Spend is what was spent: dollars and tokens.
+type Spend struct {
+	Cost   float64 `json:"cost,omitempty"`
+	Tokens int64   `json:"tokens,omitempty"`
+}
+
+// In returns the spend counted in unit.
+func (s Spend) In(unit Unit) float64 {
+	if unit == Tokens {
+		return float64(s.Tokens)
+	}
+	return s.Cost
+}
+
+// Reading is a running agent session's cumulative spend, as its runtime
+// reports it.
+type Reading struct {
+	Agent   string // e.g. "gastown/crew/max"
+	Session string // e.g. "gt-gastown-crew-max"
+	Spend   Spend
+	Known   bool // whether the runtime reports its spend at all
+}
+
+// Ledger is the spend recorded so far.
+type Ledger struct {
+	// Spend is what each agent spent, by address and then local date
+	// ("2006-01-02").
+	Spend map[string]map[string]Spend `json:"spend"`
+	// Sessions is the cumulative spend last read from each running
+	// session, by session name.
+	Sessions map[string]Spend `json:"sessions"`
+	// Reached is the highest threshold, in percent, each budget's spend
+	// has reached in its current period (100 once exceeded), by the
+	// budget (as String gives it) and the period's start date.
+	Reached map[string]int `json:"reached"`
+	// Stopped is the budget, with its period, each running session was
+	// stopped for, by session name, so it is stopped only once: a session
+	// resumed by hand, or that could not be stopped, is left alone.
+	Stopped map[string]string `json:"stopped,omitempty"`
+}
+
+// LedgerPath returns where a town's spend is recorded.
+func LedgerPath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "budget-spend.json")
+}
+
+// LedgerDays is how many days of spend the ledger keeps: enough for the
+// longest period.
+const LedgerDays = 32
+
+// LoadLedger returns the town's recorded spend.
+func LoadLedger(townRoot string) (*Ledger, error) {
+	l := &Ledger{}
+	data, err := os.ReadFile(LedgerPath(townRoot)) //nolint:gosec // G304: path is within the town
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, l); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", LedgerPath(townRoot), err)
+		}
+	}
+	if l.Spend == nil {
+		l.Spend = map[string]map[string]Spend{}
+	}
+	if l.Sessions == nil {
+		l.Sessions = map[string]Spend{}
+	}
+	if l.Reached == nil {
+		l.Reached = map[string]int{}
+	}
+	if l.Stopped == nil {
+		l.Stopped = map[string]string{}
+	}
+	return l, nil
+}
+
+// SaveLedger writes the town's recorded spend.
+func SaveLedger(townRoot string, l *Ledger) error {
+	if err := os.MkdirAll(filepath.Dir(LedgerPath(townRoot)), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(LedgerPath(townRoot), l)
+}
+
+// Record adds to the ledger what each session read has spent since it was
+// last read, as spent on now's date. A session read for less than before
+// was restarted, and everything it reports is new. Sessions no longer
+// running are forgotten, and days past LedgerDays dropped.
+func (l *Ledger) Record(readings []Reading, now time.Time) {
+	day := now.Format("2006-01-02")
+	seen := make(map[string]bool)
+	for _, r := range readings {
+		seen[r.Session] = true
+		if !r.Known {
+			continue // as when the pane no longer shows its cost
+		}
+		last := l.Sessions[r.Session]
+		delta := r.Spend
+		if r.Spend.Cost >= last.Cost && r.Spend.Tokens >= last.Tokens {
+			delta = Spend{Cost: r.Spend.Cost - last.Cost, Tokens: r.Spend.Tokens - last.Tokens}
+		}
+		l.Sessions[r.Session] = r.Spend
+		if delta == (Spend{}) {
+			continue
+		}
+		days := l.Spend[r.Agent]
+		if days == nil {
+			days = map[string]Spend{}
+			l.Spend[r.Agent] = days
+		}
+		s := days[day]
+		s.Cost += delta.Cost
+		s.Tokens += delta.Tokens
+		days[day] = s
+	}
+	for name := range l.Sessions {
+		if !seen[name] {
+			delete(l.Sessions, name)
+		}
+	}
+	for name := range l.Stopped {
+		if !seen[name] {
+			delete(l.Stopped, name)
+		}
+	}
+	oldest := now.AddDate(0, 0, -LedgerDays).Format("2006-01-02")
+	for agent, days := range l.Spend {
+		for d := range days {
+			if d < oldest {
+				delete(days, d)
+			}
+		}
+		if len(days) == 0 {
+			delete(l.Spend, agent)
+		}
+	}
+}
+
+// Spent returns what the agents b covers have spent in its period
+// containing now.
+func (l *Ledger) Spent(b Budget, now time.Time) Spend {
+	since := PeriodStart(b.Period, now).Format("2006-01-02")
+	var total Spend
+	for agent, days := range l.Spend {
+		if !b.Covers(agent) {
+			continue
+		}
+		for d, s := range days {
+			if d >= since {
+				total.Cost += s.Cost
+				total.Tokens += s.Tokens
+			}
+		}
+	}
+	return total
+}
+
+// Status is where a budget stands in its current period.
+type Status struct {
+	Budget  Budget  `json:"budget"`
+	Spent   float64 `json:"spent"`   // in the budget's unit
+	Percent float64 `json:"percent"` // of the budget
+}
+
+// Exceeded reports whether the budget is used up.
+func (s Status) Exceeded() bool {
+	return s.Spent >= s.Budget.Amount
+}
+
+// String describes the spend, e.g. "$41.20 of 50usd/day (82%)".
+func (s Status) String() string {
+	return fmt.Sprintf("%s of %s (%.0f%%)", FormatSpend(s.Spent, s.Budget.Unit), s.Budget.Spec(), s.Percent)
+}
+
+// Statuses returns where each budget stands in its period containing now.
+func (l *Ledger) Statuses(budgets []Budget, now time.Time) []Status {
+	out := make([]Status, 0, len(budgets))
+	for _, b := range budgets {
+		spent := l.Spent(b, now).In(b.Unit)
+		out = append(out, Status{Budget: b, Spent: spent, Percent: spent / b.Amount * 100})
+	}
+	return out
+}
+
+// Crossing is a threshold of a budget newly reached: a warning, or 100
+// once the budget is exceeded.
+type Crossing struct {
+	Status
+	Threshold int
+}
+
+// key identifies the status's budget in its period containing now.
+func (s Status) key(now time.Time) string {
+	return s.Budget.String() + "@" + PeriodStart(s.Budget.Period, now).Format("2006-01-02")
+}
+
+// Cross returns the thresholds of statuses newly reached in their periods
+// containing now, the highest of each budget's only, and marks them
+// reached.
+func (l *Ledger) Cross(statuses []Status, now time.Time) []Crossing {
+	var out []Crossing
+	current := make(map[string]bool)
+	for _, s := range statuses {
+		key := s.key(now)
+		current[key] = true
+		reached := 0
+		for _, w := range append(s.Budget.Thresholds(), 100) {
+			if s.Percent >= float64(w) {
+				reached = w
+			}
+		}
+		if reached > l.Reached[key] {
+			l.Reached[key] = reached
+			out = append(out, Crossing{Status: s, Threshold: reached})
+		}
+	}
+	for key := range l.Reached {
+		if !current[key] {
+			delete(l.Reached, key)
+		}
+	}
+	return out
+}
+
+// Stopping returns the exceeded budget among statuses that stops agent,
+// if any: one that kills it before one that pauses it.
+func Stopping(statuses []Status, agent string) (Status, bool) {
+	var found Status
+	ok := false
+	for _, s := range statuses {
+		if !s.Exceeded() || !s.Budget.Covers(agent) || s.Budget.ActionOrDefault() == Warn {
+			continue
+		}
+		if !ok || s.Budget.ActionOrDefault() == Kill {
+			found, ok = s, true
+		}
+	}
+	return found, ok
+}
+
+// Enforcement is what a check did to one agent.
+type Enforcement struct {
+	Agent  string
+	Action string // "paused", "killed", or "resumed"
+	Reason string
+	Err    error
+}
+
+// Enforce stops each agent read that an exceeded budget covers, unless it
+// is paused already or was stopped for the budget before (see
+// Ledger.Stopped), and resumes the agents paused for a budget that no
+// longer stops them, logging each.
+func Enforce(t *tmux.Tmux, townRoot string, l *Ledger, statuses []Status, readings []Reading, now time.Time) []Enforcement {
+	pauses, err := session.LoadPauses(townRoot)
+	if err != nil {
+		return []Enforcement{{Action: "checked", Err: err}}
+	}
+	var out []Enforcement
+	for _, r := range readings {
+		s, stop := Stopping(statuses, r.Agent)
+		if _, paused := pauses[r.Session]; !stop || paused || l.Stopped[r.Session] == s.key(now) {
+			continue
+		}
+		l.Stopped[r.Session] = s.key(now)
+		e := Enforcement{Agent: r.Agent, Action: "paused", Reason: s.Reason()}
+		if s.Budget.ActionOrDefault() == Kill {
+			e.Action = "killed"
+			e.Err = kill(townRoot, r.Agent, s)
+		} else {
+			e.Err = session.PauseSession(t, townRoot, session.Pause{
+				Agent:    r.Agent,
+				Session:  r.Session,
+				PausedAt: time.Now(),
+				By:       PausedBy,
+				Reason:   e.Reason,
+			})
+		}
+		out = append(out, e)
+	}
+	for name, p := range pauses {
+		if p.By != PausedBy {
+			continue
+		}
+		if exists, _ := t.HasSession(name); !exists {
+			_, _ = session.ResumeSession(t, townRoot, name) // forget it
+			continue
+		}
+		if _, stop := Stopping(statuses, p.Agent); stop {
+			continue
+		}
+		_, err := session.ResumeSession(t, townRoot, name)
+		out = append(out, Enforcement{Agent: p.Agent, Action: "resumed", Reason: "no budget stops it any more", Err: err})
+	}
+	sort.SliceStable(out, func(i, k int) bool { return out[i].Agent < out[k].Agent })
+	for _, e := range out {
+		if e.Err != nil {
+			Log(townRoot, e.Agent, fmt.Sprintf("could not be %s: %s: %v", e.Action, e.Reason, e.Err), townlog.SeverityError)
+		} else if e.Action != "killed" { // gt kill logs the kill and its reason
+			Log(townRoot, e.Agent, e.Action+": "+e.Reason, townlog.SeverityWarn)
+		}
+	}
+	return out
+}
+
+// Reason returns why an agent of an exceeded budget is stopped, e.g.
+// "budget 50usd/day for gastown/ exceeded ($50.20)".
+func (s Status) Reason() string {
+	return fmt.Sprintf("budget %s exceeded (%s)", s.Budget, FormatSpend(s.Spent, s.Budget.Unit))
+}
+
+// Log records a budget event for agent ("" for the budget's scope).
+func Log(townRoot, agent, context string, severity townlog.Severity) {
+	_ = townlog.NewLogger(townRoot).LogEvent(townlog.Event{
+		Timestamp: time.Now(),
+		Type:      EventBudget,
+		Agent:     agent,
+		Context:   context,
+		Severity:  severity,
+	})
+}
+
+// LogCrossing records a threshold reached in the town log.
+func LogCrossing(townRoot string, c Crossing) {
+	if c.Threshold >= 100 {
+		Log(townRoot, c.Budget.Scope, fmt.Sprintf("%s: %s", c.Reason(), exceededAction(c.Budget)), townlog.SeverityError)
+		return
+	}
+	Log(townRoot, c.Budget.Scope, fmt.Sprintf("%d%% of budget %s used (%s)", c.Threshold, c.Budget, FormatSpend(c.Spent, c.Budget.Unit)), townlog.SeverityWarn)
+}
+
+func exceededAction(b Budget) string {
+	switch b.ActionOrDefault() {
+	case Kill:
+		return "killing its agents"
+	case Warn:
+		return "agents keep running"
+	}
+	return "pausing its agents until the next " + string(b.Period)
+}
+
+// kill stops an agent of an exceeded budget with 'gt kill', from townRoot.
+func kill(townRoot, agent string, s Status) error {
+	cmd := exec.Command("gt", "kill", agent, "--reason", s.Reason()) //nolint:gosec // G204: args come from the town's budgets
+	cmd.Dir = townRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if i := strings.LastIndex(msg, "\n"); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return fmt.Errorf("gt kill: %v: %s", err, msg)
+	}
+	return nil
+}
+
+// NeedsCheck reports whether the town has budgets to check, or agents
+// paused for a budget that may be resumed.
+func NeedsCheck(townRoot string) bool {
+	if list, err := Load(townRoot); err != nil || len(list) > 0 {
+		return true
+	}
+	pauses, _ := session.LoadPauses(townRoot)
+	for _, p := range pauses {
+		if p.By == PausedBy {
+			return true
+		}
+	}
+	return false
+}
+
+// RunCheck runs 'gt budget check' from townRoot, as the daemon does every
+// minute.
+func RunCheck(townRoot string) error {
+	cmd := exec.Command("gt", "budget", "check", "--quiet") //nolint:gosec // G204: fixed args
+	cmd.Dir = townRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if i := strings.LastIndex(msg, "\n"); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return fmt.Errorf("gt budget check: %v: %s", err, msg)
+	}
+	return nil
+}
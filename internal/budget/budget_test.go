@@ -0,0 +1,212 @@
+package budget
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSpec(t *testing.T) {
+	for _, tc := range []struct {
+		spec   string
+		amount float64
+		unit   Unit
+		period Period
+	}{
+		{"50usd/day", 50, USD, Day},
+		{"$20/week", 20, USD, Week},
+		{"12.5USD/month", 12.5, USD, Month},
+		{"2mtokens/week", 2e6, Tokens, Week},
+		{"500k tokens/day", 5e5, Tokens, Day},
+		{"1000tokens/day", 1000, Tokens, Day},
+	} {
+		amount, unit, period, err := ParseSpec(tc.spec)
+		if err != nil || amount != tc.amount || unit != tc.unit || period != tc.period {
+			t.Errorf("ParseSpec(%q) = %v %v %v, %v", tc.spec, amount, unit, period, err)
+		}
+	}
+	for _, bad := range []string{"50/day", "$5tokens/day", "50usd", "50usd/year", "0usd/day", "-5usd/day"} {
+		if _, _, _, err := ParseSpec(bad); err == nil {
+			t.Errorf("ParseSpec(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestSpecRoundTrip(t *testing.T) {
+	for _, spec := range []string{"50usd/day", "12.5usd/week", "2mtokens/week", "2.5mtokens/day", "500ktokens/month", "1234tokens/day"} {
+		amount, unit, period, err := ParseSpec(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := (Budget{Amount: amount, Unit: unit, Period: period}).Spec(); got != spec {
+			t.Errorf("Spec of %q = %q", spec, got)
+		}
+	}
+}
+
+func TestNormalizeScopeAndCovers(t *testing.T) {
+	for in, want := range map[string]string{
+		"gastown/":          "gastown/",
+		"gastown/crew/max/": "gastown/crew/max",
+		"gastown/Toast":     "gastown/polecats/Toast",
+		"mayor/":            "mayor",
+	} {
+		if got, err := NormalizeScope(in); err != nil || got != want {
+			t.Errorf("NormalizeScope(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := NormalizeScope("gastown"); err == nil {
+		t.Error("NormalizeScope accepted a rig without its slash")
+	}
+
+	rig := Budget{Scope: "gastown/"}
+	if !rig.Covers("gastown/crew/max") || !rig.Covers("gastown/witness") || rig.Covers("beads/crew/max") || rig.Covers("mayor") {
+		t.Error("rig budget covers the wrong agents")
+	}
+	agent := Budget{Scope: "gastown/polecats/Toast"}
+	if !agent.Covers("gastown/polecats/Toast") || agent.Covers("gastown/polecats/Nux") {
+		t.Error("agent budget covers the wrong agents")
+	}
+}
+
+func TestPeriodStart(t *testing.T) {
+	at := time.Date(2026, 10, 14, 15, 30, 0, 0, time.Local) // a Wednesday
+	for p, want := range map[Period]time.Time{
+		Day:   time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local),
+		Week:  time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local),
+		Month: time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local),
+	} {
+		if got := PeriodStart(p, at); !got.Equal(want) {
+			t.Errorf("PeriodStart(%s) = %v, want %v", p, got, want)
+		}
+	}
+	sunday := time.Date(2026, 10, 18, 23, 0, 0, 0, time.Local)
+	if got := PeriodStart(Week, sunday); got.Day() != 12 {
+		t.Errorf("week of a Sunday starts on the %d", got.Day())
+	}
+}
+
+func TestLedgerRecord(t *testing.T) {
+	l := &Ledger{Spend: map[string]map[string]Spend{}, Sessions: map[string]Spend{}, Reached: map[string]int{}, Stopped: map[string]string{}}
+	day1 := time.Date(2026, 10, 13, 23, 50, 0, 0, time.Local)
+	day2 := day1.Add(20 * time.Minute)
+	max := func(cost float64) Reading {
+		return Reading{Agent: "gastown/crew/max", Session: "gt-gastown-crew-max", Spend: Spend{Cost: cost}, Known: true}
+	}
+	unknown := Reading{Agent: "gastown/crew/ada", Session: "gt-gastown-crew-ada"}
+
+	l.Record([]Reading{max(3), unknown}, day1)
+	l.Record([]Reading{max(5)}, day2) // $2 more, on the next day
+	l.Record([]Reading{max(1)}, day2) // restarted: $1 new
+	l.Stopped["gt-gastown-crew-max"] = "budget"
+	l.Record(nil, day2)                 // stopped
+	l.Record([]Reading{max(0.5)}, day2) // a new session, forgotten the old
+
+	if got := l.Spend["gastown/crew/max"]["2026-10-13"].Cost; got != 3 {
+		t.Errorf("day 1 spend = %v", got)
+	}
+	if got := l.Spend["gastown/crew/max"]["2026-10-14"].Cost; got != 3.5 {
+		t.Errorf("day 2 spend = %v", got)
+	}
+	if len(l.Stopped) != 0 {
+		t.Errorf("stopped sessions kept once gone: %v", l.Stopped)
+	}
+	if _, ok := l.Spend["gastown/crew/ada"]; ok {
+		t.Error("recorded spend of an agent whose runtime reports none")
+	}
+
+	daily := Budget{Scope: "gastown/", Amount: 4, Unit: USD, Period: Day}
+	weekly := Budget{Scope: "gastown/crew/max", Amount: 10, Unit: USD, Period: Week}
+	if got := l.Spent(daily, day2).Cost; got != 3.5 {
+		t.Errorf("daily spent = %v", got)
+	}
+	if got := l.Spent(weekly, day2).Cost; got != 6.5 {
+		t.Errorf("weekly spent = %v", got)
+	}
+
+	// Days past the ledger's reach are dropped
+	l.Record(nil, day2.AddDate(0, 0, LedgerDays+1))
+	if len(l.Spend) != 0 {
+		t.Errorf("old spend kept: %v", l.Spend)
+	}
+}
+
+func TestLedgerCross(t *testing.T) {
+	l := &Ledger{Reached: map[string]int{}}
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)
+	b := Budget{Scope: "gastown/", Amount: 50, Unit: USD, Period: Day, Warn: []int{80, 50}}
+	at := func(spent float64) []Status {
+		return []Status{{Budget: b, Spent: spent, Percent: spent / b.Amount * 100}}
+	}
+
+	for _, tc := range []struct {
+		spent float64
+		want  int // threshold crossed, 0 for none
+	}{
+		{10, 0}, {26, 50}, {30, 0}, {45, 80}, {46, 0}, {60, 100}, {70, 0},
+	} {
+		got := l.Cross(at(tc.spent), now)
+		if (tc.want == 0) != (len(got) == 0) || (len(got) > 0 && got[0].Threshold != tc.want) {
+			t.Errorf("Cross at $%v = %+v, want threshold %d", tc.spent, got, tc.want)
+		}
+	}
+	// A new period starts over
+	if got := l.Cross(at(30), now.AddDate(0, 0, 1)); len(got) != 1 || got[0].Threshold != 50 {
+		t.Errorf("Cross in the next period = %+v", got)
+	}
+}
+
+func TestStopping(t *testing.T) {
+	exceeded := func(b Budget) Status { return Status{Budget: b, Spent: b.Amount, Percent: 100} }
+	statuses := []Status{
+		exceeded(Budget{Scope: "gastown/", Amount: 50, Unit: USD, Period: Day}),
+		exceeded(Budget{Scope: "gastown/crew/max", Amount: 5, Unit: USD, Period: Day, Action: Kill}),
+		exceeded(Budget{Scope: "beads/", Amount: 5, Unit: USD, Period: Day, Action: Warn}),
+		{Budget: Budget{Scope: "docs/", Amount: 5, Unit: USD, Period: Day}, Spent: 1, Percent: 20},
+	}
+	for agent, want := range map[string]Action{
+		"gastown/crew/max": Kill,
+		"gastown/crew/ada": Pause,
+		"beads/crew/max":   "",
+		"docs/crew/max":    "",
+	} {
+		got := Action("")
+		if s, ok := Stopping(statuses, agent); ok {
+			got = s.Budget.ActionOrDefault()
+		}
+		if got != want {
+			t.Errorf("Stopping(%s) = %q, want %q", agent, got, want)
+		}
+	}
+}
+
+func TestSetAndRemove(t *testing.T) {
+	townRoot := t.TempDir()
+	b := Budget{Scope: "gastown/", Amount: 50, Unit: USD, Period: Day}
+	if replaced, err := Set(townRoot, b); err != nil || replaced != nil {
+		t.Fatalf("Set = %v, %v", replaced, err)
+	}
+	b.Amount = 80
+	if replaced, err := Set(townRoot, b); err != nil || replaced == nil || replaced.Amount != 50 {
+		t.Fatalf("Set again = %v, %v", replaced, err)
+	}
+	if _, err := Set(townRoot, Budget{Scope: "gastown/", Amount: 2e6, Unit: Tokens, Period: Week}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Set(townRoot, Budget{Scope: "gastown/", Amount: 5, Unit: USD, Period: Day, Warn: []int{120}}); err == nil {
+		t.Error("Set accepted a threshold over 100%")
+	}
+	list, err := Load(townRoot)
+	if err != nil || len(list) != 2 {
+		t.Fatalf("Load = %v, %v", list, err)
+	}
+
+	if removed, err := Remove(townRoot, "gastown/", Tokens, ""); err != nil || len(removed) != 1 {
+		t.Fatalf("Remove tokens = %v, %v", removed, err)
+	}
+	if removed, err := Remove(townRoot, "gastown/", "", ""); err != nil || len(removed) != 1 || removed[0].Amount != 80 {
+		t.Fatalf("Remove = %v, %v", removed, err)
+	}
+	if _, err := Remove(townRoot, "gastown/", "", ""); err == nil {
+		t.Error("Remove of no budget succeeded")
+	}
+}
@@ -0,0 +1,229 @@
+package checkpoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// Town log events recorded by gt checkpoint <agent> and gt restore.
+const (
+	EventCheckpoint townlog.EventType = "checkpoint"
+	EventRestore    townlog.EventType = "restore"
+)
+
+// RefPrefix is where snapshot commits are kept in the agent's repository,
+// so git does not collect them.
+const RefPrefix = "refs/gastown/checkpoints/"
+
+// Snapshot is a named checkpoint of an agent, taken with
+// 'gt checkpoint <agent>' and restored with 'gt restore'. Unlike the
+// crash-recovery Checkpoint, which notes what a session was doing, a
+// snapshot records enough to put the agent back where it was: its whole
+// working tree, its transcript position, and its task assignment.
+type Snapshot struct {
+	ID        string    `json:"id"` // e.g. "ckpt-3", numbered per agent
+	Agent     string    `json:"agent"`
+	WorkDir   string    `json:"work_dir"`
+	CreatedAt time.Time `json:"created_at"`
+	Notes     string    `json:"notes,omitempty"`
+
+	// Branch and Head are the branch and commit checked out.
+	Branch string `json:"branch"`
+	Head   string `json:"head"`
+	// Tree is a commit, on top of Head, of the whole working tree:
+	// uncommitted and untracked changes included, ignored files not.
+	Tree string `json:"tree"`
+	// Modified lists the files changed since Head.
+	Modified []string `json:"modified,omitempty"`
+
+	// Transcript and TranscriptLine point at the agent's transcript entry
+	// as of the snapshot; SessionID is the runtime session writing it.
+	Transcript     string `json:"transcript,omitempty"`
+	TranscriptLine int    `json:"transcript_line,omitempty"`
+	SessionID      string `json:"session_id,omitempty"`
+
+	// Tasks are the agent's hooked and in-progress beads.
+	Tasks []Task `json:"tasks,omitempty"`
+}
+
+// Task is a bead assigned to the agent, and its status, at the snapshot.
+type Task struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Title  string `json:"title,omitempty"`
+}
+
+// Ref returns the git ref that keeps the snapshot's tree commit.
+func (s *Snapshot) Ref() string {
+	return RefPrefix + townlog.AgentKey(s.Agent) + "/" + s.ID
+}
+
+// TakeSnapshot records the git state of workDir: its branch, HEAD, and a
+// commit of the whole working tree, made without touching the index or the
+// working tree. The snapshot has no ID until it is saved.
+func TakeSnapshot(agent, workDir string) (*Snapshot, error) {
+	s := &Snapshot{Agent: agent, WorkDir: workDir, CreatedAt: time.Now()}
+	var err error
+	if s.Head, err = gitOutput(workDir, nil, "rev-parse", "HEAD"); err != nil {
+		return nil, fmt.Errorf("%s has no commit to checkpoint: %w", workDir, err)
+	}
+	s.Branch, _ = gitOutput(workDir, nil, "rev-parse", "--abbrev-ref", "HEAD")
+
+	// Stage everything into a scratch index, so the agent's own is left
+	// alone, and commit it.
+	index, err := os.CreateTemp("", "gt-checkpoint-index-")
+	if err != nil {
+		return nil, err
+	}
+	_ = index.Close()
+	defer os.Remove(index.Name())
+	env := []string{"GIT_INDEX_FILE=" + index.Name()}
+	if _, err := gitOutput(workDir, env, "read-tree", "HEAD"); err != nil {
+		return nil, err
+	}
+	if _, err := gitOutput(workDir, env, "add", "-A"); err != nil {
+		return nil, err
+	}
+	tree, err := gitOutput(workDir, env, "write-tree")
+	if err != nil {
+		return nil, err
+	}
+	// A stand-in identity, for clones with none configured: the commit only
+	// holds the snapshot. GIT_AUTHOR_NAME and the like still take precedence.
+	identity := []string{"GIT_CONFIG_COUNT=2", "GIT_CONFIG_KEY_0=user.name", "GIT_CONFIG_VALUE_0=Gas Town",
+		"GIT_CONFIG_KEY_1=user.email", "GIT_CONFIG_VALUE_1=gastown@localhost"}
+	s.Tree, err = gitOutput(workDir, identity, "commit-tree", tree, "-p", s.Head, "-m", "gt checkpoint "+agent)
+	if err != nil {
+		return nil, err
+	}
+	if diff, _ := gitOutput(workDir, nil, "diff", "--no-renames", "--name-only", s.Head, s.Tree); diff != "" {
+		s.Modified = strings.Split(diff, "\n")
+	}
+	return s, nil
+}
+
+// RestoreWorkTree puts the snapshot's working tree back in its work
+// directory: the branch is reset to Head, and the files changed since are
+// restored as uncommitted changes (staged ones come back unstaged).
+// Everything in the working tree since, other than ignored files, is lost;
+// callers take a snapshot first.
+func (s *Snapshot) RestoreWorkTree() error {
+	var steps [][]string
+	if s.Branch == "" || s.Branch == "HEAD" {
+		steps = append(steps, []string{"checkout", "-q", "-f", "--detach", s.Head})
+	} else {
+		steps = append(steps, []string{"checkout", "-q", "-f", "-B", s.Branch, s.Head})
+	}
+	steps = append(steps,
+		[]string{"clean", "-fdq"},
+		[]string{"read-tree", "-u", "--reset", s.Tree},
+		[]string{"reset", "-q"},
+	)
+	for _, args := range steps {
+		if _, err := gitOutput(s.WorkDir, nil, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SnapshotDir returns where an agent's snapshots are kept.
+func SnapshotDir(townRoot, agent string) string {
+	name := strings.ReplaceAll(townlog.AgentKey(agent), "/", "--")
+	return filepath.Join(townRoot, ".runtime", "checkpoints", name)
+}
+
+// SaveSnapshot gives s the agent's next snapshot ID, keeps its tree commit
+// under Ref, and saves it.
+func SaveSnapshot(townRoot string, s *Snapshot) error {
+	existing, err := ListSnapshots(townRoot, s.Agent)
+	if err != nil {
+		return err
+	}
+	max := 0
+	for _, other := range existing {
+		if n := snapshotNumber(other.ID); n > max {
+			max = n
+		}
+	}
+	s.ID = fmt.Sprintf("ckpt-%d", max+1)
+	if _, err := gitOutput(s.WorkDir, nil, "update-ref", s.Ref(), s.Tree); err != nil {
+		return err
+	}
+	dir := SnapshotDir(townRoot, s.Agent)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(filepath.Join(dir, s.ID+".json"), s)
+}
+
+// ListSnapshots returns the agent's snapshots, oldest first.
+func ListSnapshots(townRoot, agent string) ([]*Snapshot, error) {
+	paths, err := filepath.Glob(filepath.Join(SnapshotDir(townRoot, agent), "ckpt-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var list []*Snapshot
+	for _, path := range paths {
+		s, err := readSnapshot(path)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, k int) bool { return snapshotNumber(list[i].ID) < snapshotNumber(list[k].ID) })
+	return list, nil
+}
+
+// LoadSnapshot returns the agent's snapshot with the given ID.
+func LoadSnapshot(townRoot, agent, id string) (*Snapshot, error) {
+	s, err := readSnapshot(filepath.Join(SnapshotDir(townRoot, agent), id+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s has no checkpoint %s (list them with: gt checkpoint list %s)", agent, id, agent)
+	}
+	return s, err
+}
+
+func readSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// snapshotNumber returns the number of a snapshot ID, or 0 if it has none.
+func snapshotNumber(id string) int {
+	var n int
+	_, _ = fmt.Sscanf(id, "ckpt-%d", &n)
+	return n
+}
+
+// gitOutput runs git in dir with extra environment, returning its trimmed
+// output, or an error carrying git's.
+func gitOutput(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
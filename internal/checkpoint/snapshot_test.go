@@ -0,0 +1,150 @@
+package checkpoint
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func gitRepo(t *testing.T) (string, func(args ...string) string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+		return string(out)
+	}
+	run("init", "-q", "-b", "main")
+	write(t, dir, ".gitignore", "build/\n")
+	write(t, dir, "a.go", "package a\n")
+	write(t, dir, "gone.go", "package a\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "initial")
+	return dir, run
+}
+
+func write(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func read(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return "<missing>"
+	}
+	return string(data)
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	townRoot := t.TempDir()
+	dir, run := gitRepo(t)
+	agent := "gastown/crew/max"
+
+	// The state to come back to: a modified, a staged, a deleted, and an
+	// untracked file.
+	write(t, dir, "a.go", "package a // edited\n")
+	write(t, dir, "staged.go", "package a\n")
+	run("add", "staged.go")
+	if err := os.Remove(filepath.Join(dir, "gone.go")); err != nil {
+		t.Fatal(err)
+	}
+	write(t, dir, "notes/todo.txt", "parser next\n")
+	status := run("status", "--porcelain")
+
+	s, err := TakeSnapshot(agent, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Branch != "main" || len(s.Modified) != 4 {
+		t.Errorf("snapshot = branch %q, modified %v", s.Branch, s.Modified)
+	}
+	if got := run("status", "--porcelain"); got != status {
+		t.Errorf("taking a snapshot changed the working tree:\n%s\nwant:\n%s", got, status)
+	}
+	if err := SaveSnapshot(townRoot, s); err != nil {
+		t.Fatal(err)
+	}
+	if s.ID != "ckpt-1" {
+		t.Errorf("ID = %q, want ckpt-1", s.ID)
+	}
+	run("rev-parse", "--verify", s.Ref())
+
+	// A bad run: commits, edits, new files, and build output.
+	run("add", "-A")
+	run("commit", "-q", "-m", "bad")
+	write(t, dir, "a.go", "package a // broken\n")
+	write(t, dir, "junk.go", "package a\n")
+	write(t, dir, "build/out", "binary\n")
+
+	loaded, err := LoadSnapshot(townRoot, agent, "ckpt-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.RestoreWorkTree(); err != nil {
+		t.Fatal(err)
+	}
+	if head := run("rev-parse", "HEAD"); head != s.Head+"\n" {
+		t.Errorf("HEAD = %s, want %s", head, s.Head)
+	}
+	for name, want := range map[string]string{
+		"a.go":           "package a // edited\n",
+		"staged.go":      "package a\n",
+		"gone.go":        "<missing>",
+		"notes/todo.txt": "parser next\n",
+		"junk.go":        "<missing>",
+		"build/out":      "binary\n", // ignored files are left alone
+	} {
+		if got := read(t, dir, name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	want := " M a.go\n D gone.go\n?? notes/\n?? staged.go\n"
+	if got := run("status", "--porcelain"); got != want {
+		t.Errorf("status after restore:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestListSnapshots(t *testing.T) {
+	townRoot := t.TempDir()
+	dir, _ := gitRepo(t)
+	for range 3 {
+		s, err := TakeSnapshot("gastown/crew/max", dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := SaveSnapshot(townRoot, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, err := ListSnapshots(townRoot, "gastown/crew/max")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 || list[0].ID != "ckpt-1" || list[2].ID != "ckpt-3" {
+		t.Errorf("ListSnapshots = %v", list)
+	}
+	if list, _ := ListSnapshots(townRoot, "gastown/crew/joe"); len(list) != 0 {
+		t.Errorf("another agent has snapshots: %v", list)
+	}
+	if _, err := LoadSnapshot(townRoot, "gastown/crew/max", "ckpt-9"); err == nil {
+		t.Error("LoadSnapshot of a missing checkpoint succeeded")
+	}
+}
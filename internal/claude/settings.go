@@ -3,6 +3,7 @@ package claude
 
 import (
 	"embed"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -78,3 +79,35 @@ func EnsureSettings(workDir string, roleType RoleType) error {
 func EnsureSettingsForRole(workDir, role string) error {
 	return EnsureSettings(workDir, RoleTypeFor(role))
 }
+
+// Hooks returns the hook commands of the settings EnsureSettings installs
+// for roleType, by event.
+func Hooks(roleType RoleType) (map[string][]string, error) {
+	templateName := "config/settings-interactive.json"
+	if roleType == Autonomous {
+		templateName = "config/settings-autonomous.json"
+	}
+	content, err := configFS.ReadFile(templateName)
+	if err != nil {
+		return nil, fmt.Errorf("reading template %s: %w", templateName, err)
+	}
+	var settings struct {
+		Hooks map[string][]struct {
+			Hooks []struct {
+				Command string `json:"command"`
+			} `json:"hooks"`
+		} `json:"hooks"`
+	}
+	if err := json.Unmarshal(content, &settings); err != nil {
+		return nil, fmt.Errorf("parsing template %s: %w", templateName, err)
+	}
+	hooks := make(map[string][]string)
+	for event, matchers := range settings.Hooks {
+		for _, m := range matchers {
+			for _, h := range m.Hooks {
+				hooks[event] = append(hooks[event], h.Command)
+			}
+		}
+	}
+	return hooks, nil
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+// initAccessibility turns on accessible output (see style.Accessible) when
+// GT_ACCESSIBLE asks for it, or, when it is unset, when the town's
+// overseer config does.
+func initAccessibility() {
+	if on, set := style.AccessibleFromEnv(); set {
+		style.SetAccessible(on)
+		return
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	if oc, err := config.LoadOverseerConfig(config.OverseerConfigPath(townRoot)); err == nil && oc.Accessible {
+		style.SetAccessible(true)
+	}
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/ctiospl/gastown/internal/metrics"
+)
+
+// readAgentMetrics returns the metrics file an agent wrote into its worktree,
+// or nil if it has not written one (or it cannot be parsed).
+func readAgentMetrics(townRoot, address string) *metrics.Metrics {
+	address = strings.TrimSuffix(address, "/")
+	dirs := []string{agentWorkDir(townRoot, address)}
+	if rig, role, ok := strings.Cut(address, "/"); ok && role == "witness" {
+		// Witnesses without a rig clone run from the witness dir itself
+		dirs = append(dirs, filepath.Join(townRoot, rig, "witness"))
+	}
+	for _, dir := range dirs {
+		if m, err := metrics.Read(dir); err == nil && m != nil {
+			return m
+		}
+	}
+	return nil
+}
@@ -60,7 +60,7 @@ var AgentTypeIcons = map[AgentType]string{
 
 var agentsCmd = &cobra.Command{
 	Use:     "agents",
-	Aliases: []string{"ag"},
+	Aliases: []string{"ag", "agent"},
 	GroupID: GroupAgents,
 	Short:   "Switch between Gas Town agent sessions",
 	Long: `Display a popup menu of core Gas Town agent sessions.
@@ -68,7 +68,8 @@ var agentsCmd = &cobra.Command{
 Shows Mayor, Deacon, Witnesses, Refineries, and Crew workers.
 Polecats are hidden (use 'gt polecat list' to see them).
 
-The menu appears as a tmux popup for quick session switching.`,
+The menu appears as a tmux popup for quick session switching.
+Use 'gt agent info <agent>' to see an agent's runtime capabilities.`,
 	RunE: runAgents,
 }
 
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	agentsDndDaily  string
+	agentsDndFor    string
+	agentsDndUntil  string
+	agentsDndReason string
+)
+
+var agentsDndCmd = &cobra.Command{
+	Use:   "dnd",
+	Short: "Schedule Do Not Disturb windows for agents",
+	Long: `List, add, and remove scheduled Do Not Disturb windows.
+
+While a window is active for an agent, non-urgent nudges are deferred to
+its mailbox, mail arrives without a banner and is held back from the mail
+check hook, and dispatch wake-up nudges skip it. Urgent nudges and urgent
+mail still get through.
+
+Windows target an agent address, a glob ("gastown/polecats/*",
+"gastown/*"), or a role in every rig ("witness", "refinery", "crew",
+"polecats"). They are stored under "dnd" in config/messaging.json.
+
+Unlike 'gt dnd', which toggles the current agent's notification level,
+windows are scheduled ahead of time and end on their own.
+
+Examples:
+  gt agent dnd                                              # List windows
+  gt agent dnd add gastown/polecats/Toast --for 2h --reason "benchmark run"
+  gt agent dnd add witness --daily 22:00-07:00
+  gt agent dnd add gastown/* --until 15:30
+  gt agent dnd remove dnd-2`,
+	Args: cobra.NoArgs,
+	RunE: runAgentsDndList,
+}
+
+var agentsDndAddCmd = &cobra.Command{
+	Use:   "add <agent>",
+	Short: "Add a DND window",
+	Long: `Add a Do Not Disturb window for an agent, glob, or role.
+
+Give exactly one schedule:
+  --daily HH:MM-HH:MM   every day, local time (may wrap past midnight)
+  --for DURATION        starting now (e.g. 90m, 2h, 1d)
+  --until TIME          starting now, until HH:MM (next occurrence) or RFC3339`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentsDndAdd,
+}
+
+var agentsDndRemoveCmd = &cobra.Command{
+	Use:     "remove <id>...",
+	Aliases: []string{"rm"},
+	Short:   "Remove DND windows",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    runAgentsDndRemove,
+}
+
+func init() {
+	agentsDndAddCmd.Flags().StringVar(&agentsDndDaily, "daily", "", "Daily window HH:MM-HH:MM")
+	agentsDndAddCmd.Flags().StringVar(&agentsDndFor, "for", "", "Window length starting now")
+	agentsDndAddCmd.Flags().StringVar(&agentsDndUntil, "until", "", "Window end (HH:MM or RFC3339) starting now")
+	agentsDndAddCmd.Flags().StringVar(&agentsDndReason, "reason", "", "Why the agent should not be disturbed")
+
+	agentsDndCmd.AddCommand(agentsDndAddCmd)
+	agentsDndCmd.AddCommand(agentsDndRemoveCmd)
+	agentsCmd.AddCommand(agentsDndCmd)
+}
+
+// loadDndConfig loads the town's messaging config for editing.
+func loadDndConfig() (string, *config.MessagingConfig, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadOrCreateMessagingConfig(config.MessagingConfigPath(townRoot))
+	if err != nil {
+		return "", nil, fmt.Errorf("loading messaging config: %w", err)
+	}
+	return townRoot, cfg, nil
+}
+
+func runAgentsDndList(cmd *cobra.Command, args []string) error {
+	_, cfg, err := loadDndConfig()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var shown int
+	for _, w := range cfg.DND {
+		if w.Expired(now) {
+			continue
+		}
+		shown++
+		status := style.Dim.Render("○")
+		until := ""
+		if w.Active(now) {
+			status = style.Warning.Render("🔕")
+			until = style.Warning.Render(" active until " + w.Until(now).Format("15:04"))
+		}
+		fmt.Printf("%s %s  %s  %s%s\n", status, style.Bold.Render(w.ID), w.Agent, w.Describe(), until)
+		if w.Reason != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(w.Reason))
+		}
+	}
+	if shown == 0 {
+		fmt.Printf("%s No DND windows scheduled\n", style.Dim.Render("○"))
+	}
+	return nil
+}
+
+func runAgentsDndAdd(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	w, err := buildDndWindow(args[0], agentsDndDaily, agentsDndFor, agentsDndUntil, now)
+	if err != nil {
+		return err
+	}
+	w.Reason = agentsDndReason
+
+	townRoot, cfg, err := loadDndConfig()
+	if err != nil {
+		return err
+	}
+	w.ID = cfg.NextDNDID()
+
+	// Drop one-off windows that have ended while we're rewriting the file
+	kept := cfg.DND[:0]
+	for _, existing := range cfg.DND {
+		if !existing.Expired(now) {
+			kept = append(kept, existing)
+		}
+	}
+	cfg.DND = append(kept, *w)
+
+	if err := config.SaveMessagingConfig(config.MessagingConfigPath(townRoot), cfg); err != nil {
+		return fmt.Errorf("saving messaging config: %w", err)
+	}
+	fmt.Printf("%s Added %s: %s, %s\n", style.Success.Render("✓"), w.ID, w.Agent, w.Describe())
+	return nil
+}
+
+// buildDndWindow turns the add flags into a window. Exactly one of daily,
+// forDur, and until must be set.
+func buildDndWindow(agent, daily, forDur, until string, now time.Time) (*config.DNDWindow, error) {
+	set := 0
+	for _, v := range []string{daily, forDur, until} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("give exactly one of --daily, --for, or --until")
+	}
+
+	w := &config.DNDWindow{Agent: strings.TrimSuffix(agent, "/")}
+	switch {
+	case daily != "":
+		if err := config.ValidateDailyWindow(daily); err != nil {
+			return nil, err
+		}
+		w.Daily = daily
+	case forDur != "":
+		d, err := parseDuration(forDur)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid --for %q: expected a positive duration like 2h", forDur)
+		}
+		w.Start, w.End = now, now.Add(d)
+	default:
+		end, err := parseDndUntil(until, now)
+		if err != nil {
+			return nil, err
+		}
+		w.Start, w.End = now, end
+	}
+	return w, nil
+}
+
+// parseDndUntil parses --until as RFC3339 or as the next occurrence of HH:MM.
+func parseDndUntil(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		if !t.After(now) {
+			return time.Time{}, fmt.Errorf("--until %s is in the past", s)
+		}
+		return t, nil
+	}
+	clock, err := time.Parse("15:04", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --until %q: expected HH:MM or RFC3339", s)
+	}
+	end := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !end.After(now) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end, nil
+}
+
+func runAgentsDndRemove(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadDndConfig()
+	if err != nil {
+		return err
+	}
+
+	remove := make(map[string]bool)
+	for _, id := range args {
+		remove[id] = true
+	}
+	kept := cfg.DND[:0]
+	for _, w := range cfg.DND {
+		if remove[w.ID] {
+			delete(remove, w.ID)
+			continue
+		}
+		kept = append(kept, w)
+	}
+	if len(remove) > 0 {
+		missing := make([]string, 0, len(remove))
+		for id := range remove {
+			missing = append(missing, id)
+		}
+		sort.Strings(missing)
+		return fmt.Errorf("no DND window %s", strings.Join(missing, ", "))
+	}
+	cfg.DND = kept
+
+	if err := config.SaveMessagingConfig(config.MessagingConfigPath(townRoot), cfg); err != nil {
+		return fmt.Errorf("saving messaging config: %w", err)
+	}
+	fmt.Printf("%s Removed %s\n", style.Success.Render("✓"), strings.Join(args, ", "))
+	return nil
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var agentsInfoCmd = &cobra.Command{
+	Use:   "info <agent>",
+	Short: "Show an agent's runtime and supported capabilities",
+	Long: `Show which runtime an agent uses and which gt integrations it supports.
+
+Capabilities are negotiated per agent. A runtime that ran 'gt agents
+handshake' at startup is trusted; otherwise the runtime preset's
+declaration is used, and undeclared runtimes are assumed to support only
+nudges. Features degrade for missing capabilities instead of failing
+silently; the degradation is listed next to each gap.
+
+Examples:
+  gt agent info mayor
+  gt agent info gastown/Toast
+  gt agent info gastown/crew/max`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentsInfo,
+}
+
+var agentsHandshakeCmd = &cobra.Command{
+	Use:   "handshake",
+	Short: "Declare the running runtime's capabilities",
+	Long: `Record the capabilities of the runtime this agent runs on.
+
+Runtimes (or their startup hooks) call this once per session so gt knows
+what they support. The handshake replaces the preset's declaration for
+this agent until the next handshake.
+
+Known capabilities:
+  nudge           acts on text injected into the session
+  token_usage     reports token usage and cost
+  checkpoint      can resume a saved session
+  context_signal  signals context-window pressure
+
+Examples:
+  gt agents handshake --runtime claude --version 1.2.3 --caps nudge,token_usage,checkpoint
+  gt agents handshake --agent gastown/polecats/Toast --runtime aider --caps nudge`,
+	RunE: runAgentsHandshake,
+}
+
+var (
+	handshakeAgent   string
+	handshakeRuntime string
+	handshakeVersion string
+	handshakeCaps    string
+)
+
+func init() {
+	agentsHandshakeCmd.Flags().StringVar(&handshakeAgent, "agent", "", "Agent address (default: $BD_ACTOR)")
+	agentsHandshakeCmd.Flags().StringVar(&handshakeRuntime, "runtime", "", "Runtime name (required)")
+	agentsHandshakeCmd.Flags().StringVar(&handshakeVersion, "version", "", "Runtime version")
+	agentsHandshakeCmd.Flags().StringVar(&handshakeCaps, "caps", "", "Comma-separated capabilities the runtime supports")
+	_ = agentsHandshakeCmd.MarkFlagRequired("runtime")
+
+	agentsCmd.AddCommand(agentsInfoCmd)
+	agentsCmd.AddCommand(agentsHandshakeCmd)
+}
+
+func runAgentsInfo(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	caps := config.ResolveCapabilities(townRoot, args[0])
+	fmt.Printf("%s\n", style.Bold.Render(caps.Agent))
+	runtime := caps.Runtime
+	if caps.Version != "" {
+		runtime += " " + caps.Version
+	}
+	fmt.Printf("  Runtime:      %s\n", runtime)
+	fmt.Printf("  Capabilities: %s\n", style.Dim.Render("from "+describeCapSource(caps.Source)))
+	fmt.Println()
+
+	for _, c := range config.AllCapabilities {
+		if caps.Has(c) {
+			fmt.Printf("  %s %s\n", style.Bold.Render("✓"), c)
+			continue
+		}
+		fmt.Printf("  %s %-15s %s\n", style.Dim.Render("✗"), c, style.Dim.Render(config.CapabilityDegradation[c]))
+	}
+
+	if missing := caps.Missing(); len(missing) > 0 {
+		fmt.Println()
+		fmt.Printf("%s %d capability gap(s); affected features degrade as listed\n", style.WarningPrefix, len(missing))
+	}
+	return nil
+}
+
+// describeCapSource explains where a capability set came from.
+func describeCapSource(source string) string {
+	switch source {
+	case config.CapSourceHandshake:
+		return "runtime handshake"
+	case config.CapSourcePreset:
+		return "runtime preset"
+	default:
+		return "defaults (runtime declares none)"
+	}
+}
+
+func runAgentsHandshake(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	agent := handshakeAgent
+	if agent == "" {
+		agent = os.Getenv("BD_ACTOR")
+	}
+	if agent == "" {
+		return fmt.Errorf("cannot determine agent: use --agent or set BD_ACTOR")
+	}
+
+	var caps []config.Capability
+	for _, name := range strings.Split(handshakeCaps, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		c, err := config.ParseCapability(name)
+		if err != nil {
+			return err
+		}
+		caps = append(caps, c)
+	}
+
+	h := &config.CapabilityHandshake{
+		Agent:        agent,
+		Runtime:      handshakeRuntime,
+		Version:      handshakeVersion,
+		Capabilities: caps,
+		Time:         time.Now(),
+	}
+	if err := config.SaveHandshake(townRoot, h); err != nil {
+		return fmt.Errorf("saving handshake: %w", err)
+	}
+
+	fmt.Printf("%s Recorded %d capability(ies) for %s (%s)\n", style.SuccessPrefix, len(caps), h.Agent, h.Runtime)
+	return nil
+}
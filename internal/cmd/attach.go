@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/tmux"
+)
+
+var attachReadOnly bool
+
+var attachCmd = &cobra.Command{
+	Use:     "attach <agent>",
+	GroupID: GroupAgents,
+	Short:   "Attach your terminal to an agent's session",
+	Long: `Attach your terminal to a running agent's tmux session, found from the
+agent's address, so you need not know how sessions are named.
+
+The agent can be given as an address (gastown/crew/max, gastown/Toast,
+gastown/witness, mayor), a session name (gt-gastown-crew-max), or just a
+name (max) when one running agent has it.
+
+By default you can type into the session. With --read-only you watch it
+without being able to send it input; detach with C-b d as usual.
+
+Inside tmux, gt attach switches your client to the agent's session; with
+--read-only it opens the read-only view in a new window of your session
+instead, so your own client does not become read-only.
+
+Examples:
+  gt attach gastown/crew/max
+  gt attach max --read-only
+  gt attach gastown/witness`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAttach,
+}
+
+func init() {
+	attachCmd.Flags().BoolVarP(&attachReadOnly, "read-only", "r", false, "Watch the session without sending it input")
+	rootCmd.AddCommand(attachCmd)
+}
+
+func runAttach(cmd *cobra.Command, args []string) error {
+	t := tmux.NewTmux()
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+	cmd.SilenceUsage = true
+	sessionName, err := resolveAttachTarget(sessions, args[0])
+	if err != nil {
+		return err
+	}
+	agent := sessionName
+	if id, err := session.ParseSessionName(sessionName); err == nil {
+		agent = id.Address()
+	}
+
+	if tmux.IsInsideTmux() {
+		if isInTmuxSession(sessionName) {
+			fmt.Printf("%s Already in %s's session\n", style.Dim.Render("○"), agent)
+			return nil
+		}
+		if attachReadOnly {
+			// A nested client, so that only it is read-only
+			watch := fmt.Sprintf("TMUX= tmux attach-session -r -t '=%s'", sessionName)
+			if err := exec.Command("tmux", "new-window", "-n", "watch:"+agent, watch).Run(); err != nil { //nolint:gosec // G204: session name comes from tmux
+				return fmt.Errorf("opening read-only view: %w", err)
+			}
+			fmt.Printf("%s Watching %s read-only in a new window; close it with C-b &\n", style.Success.Render("✓"), agent)
+			return nil
+		}
+		return exec.Command("tmux", "switch-client", "-t", "="+sessionName).Run() //nolint:gosec // G204: session name comes from tmux
+	}
+
+	tmuxPath, err := exec.LookPath("tmux")
+	if err != nil {
+		return fmt.Errorf("tmux not found: %w", err)
+	}
+	tmuxArgs := []string{"attach-session", "-t", "=" + sessionName}
+	if attachReadOnly {
+		tmuxArgs = append(tmuxArgs, "-r")
+		fmt.Printf("%s Watching %s read-only; detach with C-b d\n", style.Dim.Render("○"), agent)
+	}
+	attach := exec.Command(tmuxPath, tmuxArgs...) //nolint:gosec // G204: session name comes from tmux
+	attach.Stdin = os.Stdin
+	attach.Stdout = os.Stdout
+	attach.Stderr = os.Stderr
+	return attach.Run()
+}
+
+// resolveAttachTarget returns the running session of the agent target
+// names: a session name, an agent address, or an agent's name alone. A
+// name must match exactly one running agent.
+func resolveAttachTarget(sessions []string, target string) (string, error) {
+	target = strings.TrimSuffix(target, "/")
+	running := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		running[s] = true
+	}
+	if running[target] {
+		return target, nil
+	}
+	id, addrErr := session.ParseAddress(target)
+	if addrErr == nil && running[id.SessionName()] {
+		return id.SessionName(), nil
+	}
+
+	// "max" or "gastown/max": an agent of that name, in that rig if given
+	rig, name := "", target
+	if i := strings.LastIndex(target, "/"); i >= 0 {
+		rig, name = strings.SplitN(target, "/", 2)[0], target[i+1:]
+	}
+	var matches []string
+	for _, s := range sessions {
+		if strings.Count(target, "/") > 1 {
+			break // a full address, not a name
+		}
+		sid, err := session.ParseSessionName(s)
+		if err != nil || sid.Name == "" || sid.Name != name || (rig != "" && sid.Rig != rig) {
+			continue
+		}
+		matches = append(matches, s)
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		if addrErr == nil {
+			return "", fmt.Errorf("%s is not running (start it with gt %s)", id.Address(), strings.Join(id.StartCommand(), " "))
+		}
+		return "", fmt.Errorf("no running agent matches %q (see gt agents)", target)
+	}
+	var agents []string
+	for _, s := range matches {
+		sid, _ := session.ParseSessionName(s)
+		agents = append(agents, sid.Address())
+	}
+	return "", fmt.Errorf("%q matches %d running agents: %s; give its address", target, len(matches), strings.Join(agents, ", "))
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveAttachTarget(t *testing.T) {
+	sessions := []string{"gt-mayor", "gt-gastown-crew-max", "gt-gastown-Toast", "gt-gastown-witness", "gt-infra-crew-ada", "gt-infra-Toast"}
+	tests := []struct {
+		target, want, err string
+	}{
+		{"gastown/crew/max", "gt-gastown-crew-max", ""},
+		{"gastown/crew/max/", "gt-gastown-crew-max", ""},
+		{"gt-gastown-crew-max", "gt-gastown-crew-max", ""},
+		{"mayor", "gt-mayor", ""},
+		{"gastown/Toast", "gt-gastown-Toast", ""},
+		{"gastown/polecats/Toast", "gt-gastown-Toast", ""},
+		{"gastown/witness", "gt-gastown-witness", ""},
+		{"max", "gt-gastown-crew-max", ""},
+		{"gastown/max", "gt-gastown-crew-max", ""},
+		{"ada", "gt-infra-crew-ada", ""},
+		{"Toast", "", "matches 2 running agents: gastown/polecats/Toast, infra/polecats/Toast"},
+		{"infra/Toast", "gt-infra-Toast", ""},
+		{"gastown/crew/zed", "", "gastown/crew/zed is not running (start it with gt crew start gastown zed)"},
+		{"gastown/crew/Toast", "", "gastown/crew/Toast is not running"},
+		{"deacon", "", "deacon is not running"},
+		{"nobody", "", `no running agent matches "nobody"`},
+	}
+	for _, tt := range tests {
+		got, err := resolveAttachTarget(sessions, tt.target)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: err = %v, want %q", tt.target, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v; want %q", tt.target, got, err, tt.want)
+		}
+	}
+}
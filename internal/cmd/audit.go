@@ -23,6 +23,7 @@ import (
 var (
 	auditActor string
 	auditSince string
+	auditUntil string
 	auditLimit int
 	auditJSON  bool
 )
@@ -46,13 +47,15 @@ Examples:
   gt audit --actor=mayor                  # Show mayor's activity
   gt audit --since=24h                    # Show all activity in last 24h
   gt audit --actor=joe --since=1h         # Combined filters
+  gt audit --since=sprint-1 --until=sprint-2  # Between two marks (see 'gt mark')
   gt audit --json                         # Output as JSON`,
 	RunE: runAudit,
 }
 
 func init() {
 	auditCmd.Flags().StringVar(&auditActor, "actor", "", "Filter by actor (agent address or partial match)")
-	auditCmd.Flags().StringVar(&auditSince, "since", "", "Show events since duration (e.g., 1h, 24h, 7d)")
+	auditCmd.Flags().StringVar(&auditSince, "since", "", "Show events since duration (e.g., 1h, 24h, 7d), time (RFC3339, 2006-01-02 15:04), or mark")
+	auditCmd.Flags().StringVar(&auditUntil, "until", "", "Show events up to duration ago, time, or mark")
 	auditCmd.Flags().IntVarP(&auditLimit, "limit", "n", 50, "Maximum number of entries to show")
 	auditCmd.Flags().BoolVar(&auditJSON, "json", false, "Output as JSON")
 
@@ -76,14 +79,20 @@ func runAudit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	// Parse since duration if provided
-	var sinceTime time.Time
+	// Parse since/until boundaries if provided
+	var sinceTime, untilTime time.Time
 	if auditSince != "" {
-		duration, err := parseDuration(auditSince)
-		if err != nil {
-			return fmt.Errorf("invalid --since duration: %w", err)
+		if sinceTime, err = resolveTimeBoundary(townRoot, "since", auditSince); err != nil {
+			return err
 		}
-		sinceTime = time.Now().Add(-duration)
+	}
+	if auditUntil != "" {
+		if untilTime, err = resolveTimeBoundary(townRoot, "until", auditUntil); err != nil {
+			return err
+		}
+	}
+	if err := checkTimeRange(sinceTime, untilTime); err != nil {
+		return err
 	}
 
 	// Collect entries from all sources
@@ -118,6 +127,16 @@ func runAudit(cmd *cobra.Command, args []string) error {
 	}
 	allEntries = append(allEntries, feedEntries...)
 
+	if !untilTime.IsZero() {
+		kept := allEntries[:0]
+		for _, e := range allEntries {
+			if !e.Timestamp.After(untilTime) {
+				kept = append(kept, e)
+			}
+		}
+		allEntries = kept
+	}
+
 	// Sort by timestamp (newest first)
 	sort.Slice(allEntries, func(i, j int) bool {
 		return allEntries[i].Timestamp.After(allEntries[j].Timestamp)
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ctiospl/gastown/internal/style"
+)
+
+// batchStatus is the outcome of one target in a bulk operation.
+type batchStatus string
+
+const (
+	batchOK             batchStatus = "ok"
+	batchFailed         batchStatus = "failed"
+	batchSkipped        batchStatus = "skipped"
+	batchRolledBack     batchStatus = "rolled back"
+	batchRollbackFailed batchStatus = "rollback failed"
+)
+
+// batchResult records what happened to one target.
+type batchResult struct {
+	Target string
+	Status batchStatus
+	Detail string
+	undo   func() error // reverses a successful step; nil if irreversible
+}
+
+// batch tracks a bulk operation (crew start --all, batch sling, nuke --all)
+// so that a partial failure can be rolled back and every target's outcome
+// reported, instead of stopping mid-way with the town in a mixed state.
+type batch struct {
+	Op      string
+	Results []*batchResult
+}
+
+func newBatch(op string) *batch {
+	return &batch{Op: op}
+}
+
+// succeed records a completed target. undo, if non-nil, reverses it.
+func (b *batch) succeed(target, detail string, undo func() error) {
+	b.Results = append(b.Results, &batchResult{Target: target, Status: batchOK, Detail: detail, undo: undo})
+}
+
+// fail records a failed target.
+func (b *batch) fail(target string, err error) {
+	b.Results = append(b.Results, &batchResult{Target: target, Status: batchFailed, Detail: err.Error()})
+}
+
+// skip records a target that needed no action.
+func (b *batch) skip(target, reason string) {
+	b.Results = append(b.Results, &batchResult{Target: target, Status: batchSkipped, Detail: reason})
+}
+
+// count returns the number of targets with status s.
+func (b *batch) count(s batchStatus) int {
+	n := 0
+	for _, r := range b.Results {
+		if r.Status == s {
+			n++
+		}
+	}
+	return n
+}
+
+// rollback reverses completed targets, newest first, if any target failed.
+// Targets without an undo are left as they are.
+func (b *batch) rollback() {
+	if b.count(batchFailed) == 0 {
+		return
+	}
+	for i := len(b.Results) - 1; i >= 0; i-- {
+		r := b.Results[i]
+		if r.Status != batchOK || r.undo == nil {
+			continue
+		}
+		if err := r.undo(); err != nil {
+			r.Status = batchRollbackFailed
+			r.Detail = err.Error()
+		} else {
+			r.Status = batchRolledBack
+		}
+	}
+}
+
+// report prints each target's outcome and a one-line tally.
+func (b *batch) report() {
+	if len(b.Results) == 0 {
+		return
+	}
+	fmt.Printf("\n%s %s: %d/%d succeeded\n", style.Bold.Render("📊"), b.Op, b.count(batchOK)+b.count(batchSkipped), len(b.Results))
+	for _, r := range b.Results {
+		var icon string
+		switch r.Status {
+		case batchOK:
+			icon = style.Success.Render("✓")
+		case batchSkipped:
+			icon = style.Dim.Render("○")
+		case batchRolledBack:
+			icon = style.Warning.Render("↩")
+		default:
+			icon = style.Error.Render("✗")
+		}
+		line := fmt.Sprintf("  %s %-30s %s", icon, r.Target, r.Status)
+		if r.Detail != "" {
+			line += style.Dim.Render(" — " + r.Detail)
+		}
+		fmt.Println(line)
+	}
+	if n := b.count(batchRollbackFailed); n > 0 {
+		fmt.Printf("%s %d target(s) could not be rolled back; clean them up manually\n", style.WarningPrefix, n)
+	}
+}
+
+// err summarizes failures as an error, or returns nil if none failed.
+func (b *batch) err() error {
+	failed := b.count(batchFailed)
+	if failed == 0 {
+		return nil
+	}
+	if rolled := b.count(batchRolledBack); rolled > 0 {
+		return partialFailure("%s: %d of %d target(s) failed; rolled back %d", b.Op, failed, len(b.Results), rolled)
+	}
+	return partialFailure("%s: %d of %d target(s) failed", b.Op, failed, len(b.Results))
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBatchRollback(t *testing.T) {
+	var undone []string
+	undo := func(name string) func() error {
+		return func() error {
+			undone = append(undone, name)
+			return nil
+		}
+	}
+
+	b := newBatch("test")
+	b.succeed("a", "", undo("a"))
+	b.skip("b", "already running")
+	b.succeed("c", "", undo("c"))
+	b.succeed("d", "", nil) // irreversible
+	b.fail("e", errors.New("boom"))
+	b.rollback()
+
+	if len(undone) != 2 || undone[0] != "c" || undone[1] != "a" {
+		t.Errorf("undone = %v, want [c a] (newest first)", undone)
+	}
+	if b.count(batchRolledBack) != 2 || b.count(batchOK) != 1 || b.count(batchSkipped) != 1 {
+		t.Errorf("statuses after rollback: %+v", b.Results)
+	}
+	if err := b.err(); err == nil || err.Error() != "test: 1 of 5 target(s) failed; rolled back 2" {
+		t.Errorf("err() = %v", err)
+	}
+}
+
+func TestBatchNoRollbackWithoutFailure(t *testing.T) {
+	b := newBatch("test")
+	b.succeed("a", "", func() error {
+		t.Error("undo ran with no failures")
+		return nil
+	})
+	b.rollback()
+	if b.err() != nil || b.count(batchOK) != 1 {
+		t.Errorf("clean batch: err=%v results=%+v", b.err(), b.Results)
+	}
+}
+
+func TestBatchRollbackFailure(t *testing.T) {
+	b := newBatch("test")
+	b.succeed("a", "", func() error { return errors.New("stuck") })
+	b.fail("b", errors.New("boom"))
+	b.rollback()
+	if r := b.Results[0]; r.Status != batchRollbackFailed || r.Detail != "stuck" {
+		t.Errorf("result = %+v, want rollback failed with detail", r)
+	}
+}
@@ -123,7 +123,7 @@ func runBroadcast(cmd *cobra.Command, args []string) error {
 		for _, f := range failures {
 			fmt.Printf("  %s\n", style.Dim.Render(f))
 		}
-		return fmt.Errorf("%d nudge(s) failed", failed)
+		return partialFailure("%d nudge(s) failed", failed)
 	}
 
 	fmt.Printf("%s Broadcast complete: %d agent(s) nudged\n", style.SuccessPrefix, succeeded)
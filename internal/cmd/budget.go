@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/budget"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/daemon"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	budgetJSON   bool
+	budgetAction string
+	budgetWarn   []int
+	budgetQuiet  bool
+)
+
+var budgetCmd = &cobra.Command{
+	Use:     "budget",
+	GroupID: GroupServices,
+	Short:   "Cap what agents spend, in dollars or tokens, per day, week, or month",
+	Long: `List, set, and remove the town's cost budgets. A budget caps what a rig's
+agents (gastown/) or one agent (gastown/crew/max) may spend in a period:
+
+  50usd/day          $50 a day ($50/day works too)
+  200usd/week        weeks start on Monday
+  2mtokens/month     two million tokens a month (k for thousands)
+
+Spend is tracked from what agents' runtimes report as they run: the cost
+and tokens in an agent's metrics file (.runtime/metrics.json in its
+worktree), else the cost its runtime shows in its pane — Claude Code's
+"$1.23". Agents whose runtimes report neither are not counted, and token
+budgets count only agents that report tokens. The spend each check sees
+a session add is recorded per agent per day in
+.runtime/budget-spend.json, so it outlives the session; periods start at
+local midnight.
+
+As spend crosses a budget's warning thresholds (--warn, default 80%) a
+"budget" event is logged with warn severity, once per period. Once a
+budget is used up, an event is logged with error severity and its running
+agents are stopped, by --action:
+
+  pause   freeze them with gt pause (the default); the daemon resumes them
+          when the next period starts or the budget is raised or removed
+  kill    stop their sessions with gt kill, logged with the budget as
+          the reason
+  warn    leave them running
+
+Agents started while a budget is used up are stopped too, and the daemon
+raises a budget_limit notification for its scope (see gt notify rules),
+resolved once the scope is back under budget. Each session is stopped once
+per period: one resumed by hand is left running. Budgets are kept in
+settings/budgets.json; a scope has at most one budget of each unit and
+period. The daemon checks them every minute (gt budget check runs a check
+now), so it must be running (gt daemon start) for budgets to be enforced.
+
+Examples:
+  gt budget set gastown/ 50usd/day
+  gt budget set gastown/crew/max 5usd/day --action kill --warn 50,90
+  gt budget set gastown/ 20mtokens/week
+  gt budget                    # Budgets and their spend this period
+  gt budget remove gastown/ usd/day`,
+	Args: cobra.NoArgs,
+	RunE: runBudgetList,
+}
+
+var budgetSetCmd = &cobra.Command{
+	Use:   "set <rig/|agent> <amount/period>",
+	Short: "Set a budget, replacing one of the same unit and period",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runBudgetSet,
+}
+
+var budgetRemoveCmd = &cobra.Command{
+	Use:     "remove <rig/|agent> [unit/period]",
+	Aliases: []string{"rm"},
+	Short:   "Remove a scope's budgets, or only the one of a unit and period (e.g. usd/day)",
+	Args:    cobra.RangeArgs(1, 2),
+	RunE:    runBudgetRemove,
+}
+
+var budgetCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Record agents' spend and enforce budgets now",
+	Long: `Record the spend running agents' runtimes report, warn about budgets
+crossing their thresholds, and stop or resume agents as their budgets
+say. The daemon runs this every minute.`,
+	Args: cobra.NoArgs,
+	RunE: runBudgetCheck,
+}
+
+func init() {
+	budgetCmd.Flags().BoolVar(&budgetJSON, "json", false, "Output as JSON")
+	budgetSetCmd.Flags().StringVar(&budgetAction, "action", string(budget.Pause), "What to do to the agents once the budget is used up: pause, kill, or warn")
+	budgetSetCmd.Flags().IntSliceVar(&budgetWarn, "warn", nil, "Warn as spend reaches these percents of the budget (default 80)")
+	budgetCheckCmd.Flags().BoolVarP(&budgetQuiet, "quiet", "q", false, "Print only what was done")
+
+	budgetCmd.AddCommand(budgetSetCmd)
+	budgetCmd.AddCommand(budgetRemoveCmd)
+	budgetCmd.AddCommand(budgetCheckCmd)
+	rootCmd.AddCommand(budgetCmd)
+}
+
+func runBudgetList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	budgets, err := budget.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	ledger, err := budget.LoadLedger(townRoot)
+	if err != nil {
+		return err
+	}
+	statuses := ledger.Statuses(budgets, time.Now())
+	if budgetJSON {
+		return outputJSON(statuses)
+	}
+	if len(statuses) == 0 {
+		fmt.Printf("%s No budgets\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, s := range statuses {
+		fmt.Println(formatBudgetStatus(s))
+	}
+	if running, _, _ := daemon.IsRunning(townRoot); !running {
+		fmt.Println()
+		style.PrintWarning("the daemon is not running, so budgets are not enforced (gt daemon start)")
+	}
+	return nil
+}
+
+// formatBudgetStatus formats a budget and its spend as gt budget lists it.
+func formatBudgetStatus(s budget.Status) string {
+	icon := style.Success.Render("●")
+	switch {
+	case s.Exceeded():
+		icon = style.Error.Render("●")
+	case s.Percent >= float64(s.Budget.Thresholds()[0]):
+		icon = style.Warning.Render("●")
+	}
+	line := fmt.Sprintf("%s %-24s %-18s %s", icon, s.Budget.Scope, s.Budget.Spec(),
+		fmt.Sprintf("%s (%.0f%%)", budget.FormatSpend(s.Spent, s.Budget.Unit), s.Percent))
+	detail := "then " + string(s.Budget.ActionOrDefault())
+	if len(s.Budget.Warn) > 0 {
+		var ws []string
+		for _, w := range s.Budget.Thresholds() {
+			ws = append(ws, strconv.Itoa(w)+"%")
+		}
+		detail += ", warns at " + strings.Join(ws, ", ")
+	}
+	return line + "  " + style.Dim.Render(detail)
+}
+
+func runBudgetSet(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	scope, err := budget.NormalizeScope(args[0])
+	if err != nil {
+		return err
+	}
+	amount, unit, period, err := budget.ParseSpec(args[1])
+	if err != nil {
+		return err
+	}
+	b := budget.Budget{
+		Scope:     scope,
+		Amount:    amount,
+		Unit:      unit,
+		Period:    period,
+		Action:    budget.Action(strings.ToLower(budgetAction)),
+		Warn:      budgetWarn,
+		CreatedAt: time.Now(),
+		CreatedBy: detectSender(),
+	}
+	if b.Action == budget.Pause {
+		b.Action = ""
+	}
+	replaced, err := budget.Set(townRoot, b)
+	if err != nil {
+		return err
+	}
+
+	if replaced != nil {
+		fmt.Printf("%s Budget %s (was %s)\n", style.Success.Render("✓"), b, replaced.Spec())
+	} else {
+		fmt.Printf("%s Budget %s\n", style.Success.Render("✓"), b)
+	}
+	if ledger, err := budget.LoadLedger(townRoot); err == nil {
+		s := ledger.Statuses([]budget.Budget{b}, time.Now())[0]
+		fmt.Printf("  Spent %s: %s\n", budgetPeriodName(b.Period), s)
+		if s.Exceeded() && b.ActionOrDefault() != budget.Warn {
+			style.PrintWarning("the budget is already used up, so its agents will be %s", budgetActionPast(b.ActionOrDefault()))
+		}
+	}
+	if running, _, _ := daemon.IsRunning(townRoot); !running {
+		style.PrintWarning("the daemon is not running, so budgets are not enforced (gt daemon start)")
+	}
+	return nil
+}
+
+// budgetPeriodName names the current period of p: "today".
+func budgetPeriodName(p budget.Period) string {
+	if p == budget.Day {
+		return "today"
+	}
+	return "this " + string(p)
+}
+
+// budgetActionPast describes what an action does to agents: "paused".
+func budgetActionPast(a budget.Action) string {
+	if a == budget.Kill {
+		return "killed"
+	}
+	return "paused"
+}
+
+func runBudgetRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	var unit budget.Unit
+	var period budget.Period
+	if len(args) == 2 {
+		u, p, ok := strings.Cut(strings.ToLower(args[1]), "/")
+		if !ok {
+			return fmt.Errorf("invalid unit/period %q: use e.g. usd/day or tokens/week", args[1])
+		}
+		unit, period = budget.Unit(u), budget.Period(p)
+	}
+	removed, err := budget.Remove(townRoot, args[0], unit, period)
+	if err != nil {
+		return err
+	}
+	for _, b := range removed {
+		fmt.Printf("%s Removed budget %s\n", style.Success.Render("✓"), b)
+	}
+	return nil
+}
+
+func runBudgetCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cmd.SilenceUsage = true
+	budgets, err := budget.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	ledger, err := budget.LoadLedger(townRoot)
+	if err != nil {
+		return err
+	}
+
+	t := tmux.NewTmux()
+	now := time.Now()
+	readings := budgetReadings(t, townRoot)
+	ledger.Record(readings, now)
+	statuses := ledger.Statuses(budgets, now)
+	crossings := ledger.Cross(statuses, now)
+	enforced := budget.Enforce(t, townRoot, ledger, statuses, readings, now)
+	if err := budget.SaveLedger(townRoot, ledger); err != nil {
+		return fmt.Errorf("recording spend: %w", err)
+	}
+	for _, c := range crossings {
+		budget.LogCrossing(townRoot, c)
+		if c.Threshold >= 100 {
+			fmt.Printf("%s Budget %s used up: %s\n", style.Error.Render("✗"), c.Budget, c.Status)
+		} else {
+			fmt.Printf("%s Budget %s %d%% used: %s\n", style.Warning.Render("⚠"), c.Budget, c.Threshold, c.Status)
+		}
+	}
+
+	failed := 0
+	for _, e := range enforced {
+		if e.Err != nil {
+			fmt.Printf("%s %s could not be %s: %v\n", style.Error.Render("✗"), e.Agent, e.Action, e.Err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s %s %s: %s\n", style.Bold.Render("✓"), strings.ToUpper(e.Action[:1])+e.Action[1:], e.Agent, e.Reason)
+	}
+	if !budgetQuiet {
+		for _, s := range statuses {
+			fmt.Println(formatBudgetStatus(s))
+		}
+	}
+	if failed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// budgetReadings reads the spend each running agent's runtime reports:
+// from its metrics file, else the cost shown in its pane by runtimes that
+// report token usage.
+func budgetReadings(t *tmux.Tmux, townRoot string) []budget.Reading {
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return nil
+	}
+	sort.Strings(sessions)
+	var readings []budget.Reading
+	for _, name := range sessions {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		r := budget.Reading{Agent: id.Address(), Session: name}
+		if m := readAgentMetrics(townRoot, r.Agent); m != nil && (m.CostUSD != nil || m.Tokens != nil) {
+			if m.CostUSD != nil {
+				r.Spend.Cost = *m.CostUSD
+			}
+			if m.Tokens != nil {
+				r.Spend.Tokens = m.Tokens.Total()
+			}
+			r.Known = true
+		} else if config.ResolveCapabilities(townRoot, r.Agent).Has(config.CapTokenUsage) {
+			if content, err := t.CapturePaneAll(name); err == nil && costRegex.MatchString(content) {
+				r.Spend.Cost, r.Known = extractCost(content), true
+			}
+		}
+		readings = append(readings, r)
+	}
+	return readings
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var cacheCleanAll bool
+
+var cacheCmd = &cobra.Command{
+	Use:     "cache",
+	GroupID: GroupWorkspace,
+	Short:   "Show the town's shared dependency caches",
+	Long: `Show the dependency caches shared by every agent worktree.
+
+Rigs opt in with "caches" in the worktree section of
+<rig>/settings/config.json:
+
+  "worktree": {
+    "caches": ["go", "pnpm"],
+    "setup": [{"name": "deps", "run": "pnpm install --frozen-lockfile"}]
+  }
+
+Setup hooks and agent sessions then get GOMODCACHE, GOCACHE,
+npm_config_store_dir and friends pointed at <town>/.cache/deps/, so each
+spawn reuses what earlier spawns downloaded and built. The tools lock or
+write their caches atomically, so concurrent agents are safe.
+
+Examples:
+  gt cache                  # List caches, sizes, and the rigs using them
+  gt cache clean go         # Delete one cache (it refills on next use)
+  gt cache clean --all`,
+	Args: cobra.NoArgs,
+	RunE: runCache,
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean [cache...]",
+	Short: "Delete shared dependency caches",
+	Long: `Delete shared dependency caches to reclaim disk space.
+
+Caches refill on next use. Avoid cleaning while agents are installing
+dependencies; their in-flight installs may fail and need a retry.`,
+	RunE: runCacheClean,
+}
+
+func init() {
+	cacheCleanCmd.Flags().BoolVar(&cacheCleanAll, "all", false, "Delete every cache")
+
+	cacheCmd.AddCommand(cacheCleanCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func runCache(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	users := cacheUsers(townRoot)
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Shared caches"), style.Dim.Render(config.DepCacheRoot(townRoot)))
+
+	var total int64
+	for _, c := range config.DepCaches {
+		size := dirSize(c.Dir(townRoot))
+		total += size
+
+		rigs := users[c.Name]
+		icon, usage := style.Dim.Render("○"), style.Dim.Render("not enabled")
+		if len(rigs) > 0 {
+			icon, usage = style.Success.Render("●"), strings.Join(rigs, ", ")
+		}
+		fmt.Printf("%s %-8s %9s  %s\n", icon, c.Name, formatBytes(size), usage)
+		fmt.Printf("  %s\n", style.Dim.Render(c.Description))
+	}
+	fmt.Printf("\n%s %s\n", style.Bold.Render("Total:"), formatBytes(total))
+	return nil
+}
+
+func runCacheClean(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	names := args
+	if cacheCleanAll {
+		names = config.DepCacheNames()
+	} else if len(names) == 0 {
+		return fmt.Errorf("name the caches to delete, or use --all")
+	}
+
+	for _, name := range names {
+		c, ok := config.LookupDepCache(name)
+		if !ok {
+			return fmt.Errorf("unknown cache %q: known caches are %s", name, strings.Join(config.DepCacheNames(), ", "))
+		}
+		dir := c.Dir(townRoot)
+		size := dirSize(dir)
+		if err := removeCacheDir(dir); err != nil {
+			return fmt.Errorf("deleting %s cache: %w", name, err)
+		}
+		fmt.Printf("%s Deleted %s cache %s\n", style.Success.Render("✓"), name, style.Dim.Render("("+formatBytes(size)+")"))
+	}
+	return nil
+}
+
+// cacheUsers maps each cache name to the rigs that enable it.
+func cacheUsers(townRoot string) map[string][]string {
+	users := make(map[string][]string)
+	for _, rigName := range discoverRigs(townRoot) {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rigName)))
+		if err != nil || settings.Worktree == nil {
+			continue
+		}
+		for _, name := range settings.Worktree.Caches {
+			users[name] = append(users[name], rigName)
+		}
+	}
+	for _, rigs := range users {
+		sort.Strings(rigs)
+	}
+	return users
+}
+
+// removeCacheDir deletes a cache directory. The Go module cache is
+// read-only, so write permission is restored first.
+func removeCacheDir(dir string) error {
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			_ = os.Chmod(path, 0755)
+		}
+		return nil
+	})
+	return os.RemoveAll(dir)
+}
+
+// dirSize returns the total size of regular files under dir.
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// formatBytes renders a size with a binary unit: 512 B, 1.5 KiB, 3.2 GiB.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -3,23 +3,39 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/ctiospl/gastown/internal/beads"
 	"github.com/ctiospl/gastown/internal/checkpoint"
+	"github.com/ctiospl/gastown/internal/session"
 	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
 	"github.com/ctiospl/gastown/internal/workspace"
 )
 
 var checkpointCmd = &cobra.Command{
-	Use:     "checkpoint",
+	Use:     "checkpoint [agent]",
 	GroupID: GroupDiag,
-	Short:   "Manage session checkpoints for crash recovery",
-	Long: `Manage checkpoints for polecat session crash recovery.
+	Short:   "Snapshot an agent, or manage session checkpoints for crash recovery",
+	Long: `Snapshot an agent to restore later, or manage checkpoints for polecat
+session crash recovery.
 
-Checkpoints capture the current work state so that if a session crashes,
-the next session can resume from where it left off.
+gt checkpoint <agent> snapshots the agent so that after a bad run it can be
+put back where it was with gt restore:
+- Its working tree: branch, HEAD, and every uncommitted and untracked
+  change (ignored files are not kept), as a commit under
+  refs/gastown/checkpoints/ in the agent's repository
+- Its transcript position: the runtime session and transcript line
+- Its task assignment: hooked and in-progress beads
+
+Snapshots are numbered per agent (ckpt-1, ckpt-2, ...) and kept in
+.runtime/checkpoints/; gt checkpoint list <agent> lists them.
+
+Crash-recovery checkpoints (gt checkpoint write) capture the current work
+state so that if a session crashes, the next session can resume from where
+it left off.
 
 Checkpoint data includes:
 - Current molecule and step
@@ -28,7 +44,22 @@ Checkpoint data includes:
 - Git branch and last commit
 - Timestamp
 
-Checkpoints are stored in .polecat-checkpoint.json in the polecat directory.`,
+Checkpoints are stored in .polecat-checkpoint.json in the polecat directory.
+
+Examples:
+  gt checkpoint gastown/crew/max --notes "before the parser rewrite"
+  gt checkpoint list gastown/crew/max
+  gt restore gastown/crew/max --from ckpt-1
+  gt checkpoint write              # Crash-recovery checkpoint of this session`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCheckpointAgent,
+}
+
+var checkpointListCmd = &cobra.Command{
+	Use:   "list <agent>",
+	Short: "List an agent's snapshots",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCheckpointList,
 }
 
 var checkpointWriteCmd = &cobra.Command{
@@ -69,6 +100,10 @@ func init() {
 	checkpointCmd.AddCommand(checkpointWriteCmd)
 	checkpointCmd.AddCommand(checkpointReadCmd)
 	checkpointCmd.AddCommand(checkpointClearCmd)
+	checkpointCmd.AddCommand(checkpointListCmd)
+
+	checkpointCmd.Flags().StringVar(&checkpointNotes, "notes", "",
+		"Add notes to the snapshot")
 
 	checkpointWriteCmd.Flags().StringVar(&checkpointNotes, "notes", "",
 		"Add notes to the checkpoint")
@@ -218,6 +253,126 @@ func runCheckpointClear(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runCheckpointAgent(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.Help()
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	id, err := session.ParseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	agent := id.Address()
+
+	s, err := snapshotAgent(townRoot, agent, checkpointNotes)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Checkpointed %s as %s\n", style.Success.Render("✓"), agent, style.Bold.Render(s.ID))
+	printSnapshot(s)
+	fmt.Printf("\n  Restore with: gt restore %s --from %s\n", agent, s.ID)
+	return nil
+}
+
+// snapshotAgent takes, saves, and logs a snapshot of the agent.
+func snapshotAgent(townRoot, agent, notes string) (*checkpoint.Snapshot, error) {
+	workDir := agentWorkDir(townRoot, agent)
+	s, err := checkpoint.TakeSnapshot(agent, workDir)
+	if err != nil {
+		return nil, err
+	}
+	s.Notes = notes
+	if path := latestTranscript(townRoot, agent); path != "" {
+		s.Transcript = path
+		s.TranscriptLine, _ = transcriptLineAt(path, s.CreatedAt)
+		s.SessionID = strings.TrimSuffix(filepath.Base(path), ".jsonl")
+	}
+	s.Tasks = agentTasks(workDir, agent)
+
+	if err := checkpoint.SaveSnapshot(townRoot, s); err != nil {
+		return nil, fmt.Errorf("saving checkpoint: %w", err)
+	}
+	context := s.ID
+	if notes != "" {
+		context += ": " + notes
+	}
+	_ = townlog.NewLogger(townRoot).LogEvent(townlog.Event{
+		Timestamp: s.CreatedAt,
+		Type:      checkpoint.EventCheckpoint,
+		Agent:     agent,
+		Context:   context,
+	})
+	return s, nil
+}
+
+// agentTasks returns the agent's hooked and in-progress beads.
+func agentTasks(workDir, agent string) []checkpoint.Task {
+	b := beads.New(workDir)
+	var tasks []checkpoint.Task
+	for _, status := range []string{beads.StatusHooked, "in_progress"} {
+		issues, err := b.List(beads.ListOptions{Status: status, Assignee: agent, Priority: -1})
+		if err != nil {
+			continue
+		}
+		for _, issue := range issues {
+			tasks = append(tasks, checkpoint.Task{ID: issue.ID, Status: status, Title: issue.Title})
+		}
+	}
+	return tasks
+}
+
+func printSnapshot(s *checkpoint.Snapshot) {
+	fmt.Printf("  Branch: %s at %s\n", s.Branch, s.Head[:min(12, len(s.Head))])
+	if len(s.Modified) > 0 {
+		fmt.Printf("  Uncommitted: %d file(s)\n", len(s.Modified))
+	}
+	if s.Transcript != "" {
+		fmt.Printf("  Transcript: %s:%d\n", s.Transcript, s.TranscriptLine)
+	}
+	for _, t := range s.Tasks {
+		fmt.Printf("  Task: %s [%s] %s\n", t.ID, t.Status, t.Title)
+	}
+	if s.Notes != "" {
+		fmt.Printf("  Notes: %s\n", s.Notes)
+	}
+}
+
+func runCheckpointList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	id, err := session.ParseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	list, err := checkpoint.ListSnapshots(townRoot, id.Address())
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		fmt.Printf("%s No checkpoints for %s\n", style.Dim.Render("○"), id.Address())
+		return nil
+	}
+	for _, s := range list {
+		line := fmt.Sprintf("%s at %s", s.Branch, s.Head[:min(12, len(s.Head))])
+		if len(s.Modified) > 0 {
+			line += fmt.Sprintf(", %d uncommitted", len(s.Modified))
+		}
+		if len(s.Tasks) > 0 {
+			line += fmt.Sprintf(", %d task(s)", len(s.Tasks))
+		}
+		fmt.Printf("%s  %s  %s\n", style.Bold.Render(s.ID), s.CreatedAt.Local().Format("2006-01-02 15:04"), line)
+		if s.Notes != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(s.Notes))
+		}
+	}
+	return nil
+}
+
 // detectMoleculeContext tries to detect the current molecule and step from beads.
 func detectMoleculeContext(workDir string, ctx RoleInfo) (moleculeID, stepID, stepTitle string) {
 	b := beads.New(workDir)
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/crew"
+	"github.com/ctiospl/gastown/internal/git"
+	"github.com/ctiospl/gastown/internal/handoff"
+	"github.com/ctiospl/gastown/internal/labels"
+	"github.com/ctiospl/gastown/internal/preempt"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	cloneMessage string
+	cloneAccount string
+)
+
+var cloneCmd = &cobra.Command{
+	Use:     "clone <agent> <new-name>",
+	GroupID: GroupAgents,
+	Short:   "Duplicate a crew agent into a new one, to try another approach",
+	Long: `Create a crew agent that starts where another one stands, and start it.
+
+The new crew member, in the same rig, gets the agent's:
+
+  configuration   template, role, runtime, and sandbox bindings, priority,
+                  and labels, as gt spawn would have given them
+  branch point    its worktree is reset to the commit the agent's worktree
+                  is at, pushed or not; uncommitted changes are not copied
+  context         a summary of the agent's session (recent commits,
+                  uncommitted changes, open tasks, and the notes, marks,
+                  and nudges the town log recorded for it), shown by the
+                  clone's first gt prime as after gt handoff --summarize
+
+The agent is left running, so the two can try different approaches to the
+same task in parallel; --message tells the clone what to do differently.
+The clone counts toward max_concurrent_agents like any spawn, and is
+queued if it does not fit (see gt spawn --help).
+
+Only crew members can be cloned: polecats are managed by their witness.
+
+Examples:
+  gt clone gastown/crew/max max-alt
+  gt clone gastown/crew/max max-stream -m "Try streaming the results instead of batching"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runClone,
+}
+
+func init() {
+	cloneCmd.Flags().StringVarP(&cloneMessage, "message", "m", "", "What the clone should do differently, added to its context")
+	cloneCmd.Flags().StringVar(&cloneAccount, "account", "", "Claude Code account handle to use (overrides the template's)")
+	rootCmd.AddCommand(cloneCmd)
+}
+
+func runClone(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	src, err := session.ParseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	if src.Role != session.RoleCrew {
+		return fmt.Errorf("%s is not a crew member: only crew can be cloned", src.Address())
+	}
+	if strings.Contains(args[1], "/") {
+		return fmt.Errorf("the new name %q is a name, not an address: the clone joins %s's rig", args[1], src.Address())
+	}
+	if _, _, err := parseSpawnAddress(src.Rig + "/crew/" + args[1]); err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	crewMgr, r, err := getCrewManager(src.Rig)
+	if err != nil {
+		return err
+	}
+	worker, err := crewMgr.Get(src.Name)
+	if err == crew.ErrCrewNotFound {
+		return fmt.Errorf("%s has no crew workspace", src.Address())
+	} else if err != nil {
+		return fmt.Errorf("getting crew worker: %w", err)
+	}
+	if _, err := crewMgr.Get(args[1]); err == nil {
+		return fmt.Errorf("%s/crew/%s already exists", src.Rig, args[1])
+	}
+	t, err := cloneTarget(townRoot, r.Path, src, args[1])
+	if err != nil {
+		return err
+	}
+	head, err := git.NewGit(worker.ClonePath).Rev("HEAD")
+	if err != nil {
+		return fmt.Errorf("reading %s's commit: %w", src.Address(), err)
+	}
+
+	if err := createSpawnWorkspace(t); err != nil {
+		return err
+	}
+	g := git.NewGit(filepath.Join(r.Path, "crew", t.Name))
+	if err := g.FetchBranch(worker.ClonePath, "HEAD"); err != nil {
+		return fmt.Errorf("fetching %s's commit: %w", src.Address(), err)
+	}
+	if err := resetHard(g, head); err != nil {
+		return fmt.Errorf("resetting to %s's commit: %w", src.Address(), err)
+	}
+	fmt.Printf("%s Branch point: %s, where %s stands\n", style.Bold.Render("✓"), shortSHA(head), src.Address())
+
+	summary := handoff.Build(townRoot, worker.ClonePath, src.Address(), cloneMessage, time.Now())
+	summary.Agent, summary.From = t.Address(), src.Address()
+	if err := handoff.Save(townRoot, summary); err != nil {
+		style.PrintWarning("could not hand the clone %s's context: %v", src.Address(), err)
+	} else {
+		fmt.Printf("%s Context: %d commit(s), %d changed file(s), %d task(s), %d note(s) from %s\n", style.Bold.Render("✓"),
+			len(summary.Commits), len(summary.Uncommitted), len(summary.Tasks), len(summary.Decisions), src.Address())
+	}
+
+	_, over, why, err := makeRoom(townRoot, []spawnTarget{t})
+	if err != nil {
+		return err
+	}
+	if len(over) > 0 {
+		_, err := queueSpawns(townRoot, over, why)
+		return err
+	}
+	if err := startSpawnSession(cmd, t); err != nil {
+		return err
+	}
+	return recordSpawn(townRoot, t)
+}
+
+// cloneTarget returns the spawn target of a clone of src named name:
+// bound as src is, with its priority and labels.
+func cloneTarget(townRoot, rigPath string, src *session.AgentIdentity, name string) (spawnTarget, error) {
+	binding := config.ReadCrewBinding(rigPath, src.Name)
+	if binding.Host != "" {
+		return spawnTarget{}, fmt.Errorf("%s runs on host %s, so its worktree there cannot be cloned", src.Address(), binding.Host)
+	}
+	t := spawnTarget{Rig: src.Rig, Name: name, Account: cloneAccount, Runtime: binding.Runtime, Sandbox: binding.Sandbox}
+	if binding.Template != "" {
+		tmpl, err := config.LoadAgentTemplate(townRoot, binding.Template)
+		if err != nil {
+			return spawnTarget{}, fmt.Errorf("%s's template: %w", src.Address(), err)
+		}
+		t.Template = tmpl
+		if t.Account == "" {
+			t.Account = tmpl.Account
+		}
+	}
+	if binding.Role != "" {
+		role, err := config.LoadAgentRole(townRoot, binding.Role)
+		if err != nil {
+			return spawnTarget{}, fmt.Errorf("%s's role: %w", src.Address(), err)
+		}
+		t.Role = role
+	}
+	if priorities, err := preempt.Load(townRoot); err == nil {
+		t.Priority = priorities.Of(src.Address())
+	} else {
+		t.Priority = preempt.Normal
+	}
+	if store, err := labels.Load(townRoot); err == nil {
+		t.Labels = store.Of(src.Address())
+	}
+	return t, nil
+}
+
+// shortSHA abbreviates a commit hash for display.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/labels"
+	"github.com/ctiospl/gastown/internal/preempt"
+	"github.com/ctiospl/gastown/internal/session"
+)
+
+func TestCloneTarget(t *testing.T) {
+	town := t.TempDir()
+	rigPath := filepath.Join(town, "gastown")
+	if err := config.SetCrewBinding(rigPath, "max", config.CrewBinding{Runtime: "codex", Sandbox: "docker"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := preempt.Set(town, "gastown/crew/max", preempt.High); err != nil {
+		t.Fatal(err)
+	}
+	if err := labels.Set(town, "gastown/crew/max", labels.Labels{"team": "api"}); err != nil {
+		t.Fatal(err)
+	}
+
+	src := &session.AgentIdentity{Role: session.RoleCrew, Rig: "gastown", Name: "max"}
+	got, err := cloneTarget(town, rigPath, src, "max-alt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Address() != "gastown/crew/max-alt" {
+		t.Errorf("Address() = %q, want gastown/crew/max-alt", got.Address())
+	}
+	if got.Runtime != "codex" || got.Sandbox != "docker" || got.Template != nil {
+		t.Errorf("bindings = %q, %q, %v; want codex, docker, no template", got.Runtime, got.Sandbox, got.Template)
+	}
+	if got.Priority != preempt.High {
+		t.Errorf("Priority = %q, want high", got.Priority)
+	}
+	if got.Labels["team"] != "api" {
+		t.Errorf("Labels = %v, want team=api", got.Labels)
+	}
+}
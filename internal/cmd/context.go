@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/retrieval"
+	"github.com/ctiospl/gastown/internal/rig"
+	"github.com/ctiospl/gastown/internal/style"
+)
+
+var (
+	contextIndexNoCode bool
+	contextQueryRig    string
+	contextQueryBudget int
+	contextQueryJSON   bool
+)
+
+var contextCmd = &cobra.Command{
+	Use:     "context",
+	GroupID: GroupWork,
+	Short:   "Retrieve related code, notes, and handoffs for a task",
+	Long: `Manage a rig's context index, used to give new tasks the most relevant
+snippets of the rig's code, agent notes, and past handoffs.
+
+The index is optional. Once built, 'gt prime' adds the snippets most
+similar to an agent's hooked work to its startup context, within the rig's
+token budget.
+
+Configure the embeddings model in <rig>/settings/config.json:
+
+  "context": {
+    "embeddings": {"provider": "openai", "model": "nomic-embed-text",
+                   "url": "http://localhost:11434/v1"},
+    "budget_tokens": 2000
+  }
+
+Providers:
+  local     built-in keyword hashing; no model or network (default)
+  openai    any OpenAI-compatible /embeddings API: OpenAI ("api_key_env":
+            "OPENAI_API_KEY"), or a local server such as ollama or llama.cpp
+  command   a program ("command": [...]) reading a JSON array of texts on
+            stdin and writing a JSON array of vectors on stdout
+
+Changing the model requires rebuilding the index.`,
+	RunE: requireSubcommand,
+}
+
+var contextIndexCmd = &cobra.Command{
+	Use:   "index [rig]",
+	Short: "Build or rebuild a rig's context index",
+	Long: `Build the rig's context index from:
+  - the tracked text files of the rig's repo (mayor/rig)
+  - notes in polecat and crew checkpoints
+  - handoff mail agents of the rig sent to their next session
+
+Vendored, binary, and large files are skipped. Rebuild after significant
+changes; the index is a snapshot.
+
+Examples:
+  gt context index             # Rig of the current directory
+  gt context index gastown
+  gt context index --no-code   # Only notes and handoffs`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runContextIndex,
+}
+
+var contextQueryCmd = &cobra.Command{
+	Use:   "query <text...>",
+	Short: "Show the snippets the index retrieves for a task",
+	Long: `Search the rig's context index and print the most relevant snippets,
+best first, as many as fit in the token budget.
+
+Examples:
+  gt context query "retry failed merges in the refinery"
+  gt context query --budget 500 mail router banner
+  gt context query --rig beads --json flaky sync test`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runContextQuery,
+}
+
+func init() {
+	contextIndexCmd.Flags().BoolVar(&contextIndexNoCode, "no-code", false, "Index only notes and handoffs")
+
+	contextQueryCmd.Flags().StringVar(&contextQueryRig, "rig", "", "Rig to search (default: rig of the current directory)")
+	contextQueryCmd.Flags().IntVar(&contextQueryBudget, "budget", 0, "Token budget (default: the rig's budget_tokens)")
+	contextQueryCmd.Flags().BoolVar(&contextQueryJSON, "json", false, "Output as JSON")
+
+	contextCmd.AddCommand(contextIndexCmd)
+	contextCmd.AddCommand(contextQueryCmd)
+	rootCmd.AddCommand(contextCmd)
+}
+
+// rigContextConfig returns a rig's context settings, or empty ones.
+func rigContextConfig(r *rig.Rig) *config.ContextConfig {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil || settings.Context == nil {
+		return &config.ContextConfig{}
+	}
+	return settings.Context
+}
+
+func runContextIndex(cmd *cobra.Command, args []string) error {
+	var rigName string
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	townRoot, r, err := getRigOrCwd(rigName)
+	if err != nil {
+		return err
+	}
+	cfg := rigContextConfig(r)
+	embedder, err := retrieval.NewEmbedder(cfg.Embeddings)
+	if err != nil {
+		return err
+	}
+
+	var docs []retrieval.Document
+	if !contextIndexNoCode {
+		code, err := retrieval.CodeDocuments(filepath.Join(r.Path, "mayor", "rig"))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  %d files\n", len(code))
+		docs = append(docs, code...)
+	}
+	notes := retrieval.NoteDocuments(r.Path)
+	fmt.Printf("  %d checkpoint notes\n", len(notes))
+	docs = append(docs, notes...)
+	handoffs, err := retrieval.HandoffDocuments(townRoot, r.Name)
+	if err != nil {
+		fmt.Printf("  %s skipping handoffs: %v\n", style.WarningPrefix, err)
+	} else {
+		fmt.Printf("  %d handoffs\n", len(handoffs))
+		docs = append(docs, handoffs...)
+	}
+
+	fmt.Printf("Embedding with %s...\n", embedder.Name())
+	ix, err := retrieval.Build(contextOf(cmd), docs, embedder)
+	if err != nil {
+		return fmt.Errorf("building context index: %w", err)
+	}
+	if err := ix.Save(r.Path); err != nil {
+		return err
+	}
+	fmt.Printf("%s Indexed %d chunks for %s\n", style.Success.Render("✓"), len(ix.Chunks), r.Name)
+	return nil
+}
+
+func runContextQuery(cmd *cobra.Command, args []string) error {
+	_, r, err := getRigOrCwd(contextQueryRig)
+	if err != nil {
+		return err
+	}
+	hits, err := retrieveContext(contextOf(cmd), r, strings.Join(args, " "), contextQueryBudget)
+	if err != nil {
+		return err
+	}
+
+	if contextQueryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(hits)
+	}
+	if len(hits) == 0 {
+		fmt.Printf("%s Nothing in the index matches\n", style.Dim.Render("○"))
+		return nil
+	}
+	printContextHits(hits)
+	return nil
+}
+
+// retrieveContext searches a rig's index for query. A budget of zero uses
+// the rig's configured budget.
+func retrieveContext(ctx context.Context, r *rig.Rig, query string, budget int) ([]retrieval.Hit, error) {
+	cfg := rigContextConfig(r)
+	if budget <= 0 {
+		budget = cfg.BudgetTokens
+	}
+	if budget <= 0 {
+		budget = config.DefaultContextBudgetTokens
+	}
+	ix, err := retrieval.Load(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	embedder, err := retrieval.NewEmbedder(cfg.Embeddings)
+	if err != nil {
+		return nil, err
+	}
+	return ix.Search(ctx, embedder, query, budget)
+}
+
+// printContextHits prints retrieved snippets with where they came from.
+func printContextHits(hits []retrieval.Hit) {
+	for _, h := range hits {
+		where := h.Path
+		if h.Source == retrieval.SourceCode {
+			where = fmt.Sprintf("%s:%d", h.Path, h.Line)
+		}
+		fmt.Printf("%s %s %s\n", style.Bold.Render("──"), where,
+			style.Dim.Render(fmt.Sprintf("(%s, score %.2f, ~%d tokens)", h.Source, h.Score, h.Tokens)))
+		fmt.Println(h.Text)
+		fmt.Println()
+	}
+}
+
+// contextOf returns the command's context, or a background one.
+func contextOf(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
@@ -16,6 +16,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/focus"
 	"github.com/ctiospl/gastown/internal/style"
 	"github.com/ctiospl/gastown/internal/tui/convoy"
 	"github.com/ctiospl/gastown/internal/workspace"
@@ -77,6 +78,11 @@ var convoyCmd = &cobra.Command{
 	Short:   "Track batches of work across rigs",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if convoyInteractive {
+			if style.Accessible() {
+				// The tree view is full-screen; list the same tree linearly
+				convoyListTree = true
+				return runConvoyList(cmd, nil)
+			}
 			return runConvoyTUI()
 		}
 		return requireSubcommand(cmd, args)
@@ -133,7 +139,8 @@ var convoyStatusCmd = &cobra.Command{
 	Long: `Show detailed status for a convoy.
 
 Displays convoy metadata, tracked issues, and completion progress.
-Without an ID, shows status of all active convoys.`,
+Without an ID, shows the focused convoy (see gt focus), or all active
+convoys when none is focused.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runConvoyStatus,
 }
@@ -710,13 +717,16 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// If no ID provided, show all active convoys
-	if len(args) == 0 {
+	// If no ID provided, show the focused convoy or all active convoys
+	var convoyID string
+	if len(args) > 0 {
+		convoyID = args[0]
+	} else if f := focus.Current(filepath.Dir(townBeads)); f != nil && f.Convoy != "" {
+		convoyID = f.Convoy
+	} else {
 		return showAllConvoyStatus(townBeads)
 	}
 
-	convoyID := args[0]
-
 	// Check if it's a numeric shortcut (e.g., "1" instead of "hq-cv-xyz")
 	if n, err := strconv.Atoi(convoyID); err == nil && n > 0 {
 		resolved, err := resolveConvoyNumber(townBeads, n)
@@ -972,10 +982,15 @@ func printConvoyTree(townBeads string, convoys []struct {
 		if total > 0 {
 			progress = fmt.Sprintf(" (%d/%d)", completed, total)
 		}
-		fmt.Printf("🚚 %s: %s%s\n", c.ID, c.Title, progress)
+		fmt.Print(style.Plain(fmt.Sprintf("🚚 %s: %s%s\n", c.ID, c.Title, progress)))
 
 		// Print tracked issues as tree children
 		for i, t := range tracked {
+			if style.Accessible() {
+				fmt.Printf("  %s: %s (%s)\n", t.ID, t.Title, t.Status)
+				continue
+			}
+
 			// Determine tree connector
 			isLast := i == len(tracked)-1
 			connector := "├──"
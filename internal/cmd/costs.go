@@ -12,9 +12,11 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
 	"github.com/ctiospl/gastown/internal/constants"
 	"github.com/ctiospl/gastown/internal/style"
 	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/workspace"
 )
 
 var (
@@ -36,6 +38,8 @@ var costsCmd = &cobra.Command{
 	Long: `Display costs for Claude Code sessions in Gas Town.
 
 By default, shows live costs scraped from running tmux sessions.
+Agents that report cost_usd in their metrics file (.runtime/metrics.json
+in the worktree) are shown with that value instead.
 
 Examples:
   gt costs              # Live costs from running sessions
@@ -84,6 +88,10 @@ type SessionCost struct {
 	Worker  string  `json:"worker,omitempty"`
 	Cost    float64 `json:"cost_usd"`
 	Running bool    `json:"running"`
+
+	// Unsupported is set when the agent's runtime lacks the token_usage
+	// capability, so Cost is unknown rather than zero.
+	Unsupported bool `json:"unsupported,omitempty"`
 }
 
 // CostEntry is a ledger entry for historical cost tracking.
@@ -131,6 +139,7 @@ func runLiveCosts() error {
 
 	var costs []SessionCost
 	var total float64
+	townRoot, _ := workspace.FindFromCwd()
 
 	for _, session := range sessions {
 		// Only process Gas Town sessions (start with "gt-")
@@ -141,6 +150,37 @@ func runLiveCosts() error {
 		// Parse session name to get role/rig/worker
 		role, rig, worker := parseSessionName(session)
 
+		// A cost reported in the agent's metrics file beats scraping the pane
+		if townRoot != "" {
+			agentPath := buildAgentPath(role, rig, worker)
+			if m := readAgentMetrics(townRoot, agentPath); m != nil && m.CostUSD != nil {
+				costs = append(costs, SessionCost{
+					Session: session,
+					Role:    role,
+					Rig:     rig,
+					Worker:  worker,
+					Cost:    *m.CostUSD,
+					Running: t.IsClaudeRunning(session),
+				})
+				total += *m.CostUSD
+				continue
+			}
+
+			// Runtimes without usage reporting have no cost to scrape
+			caps := config.ResolveCapabilities(townRoot, agentPath)
+			if !caps.Has(config.CapTokenUsage) {
+				costs = append(costs, SessionCost{
+					Session:     session,
+					Role:        role,
+					Rig:         rig,
+					Worker:      worker,
+					Running:     t.IsClaudeRunning(session),
+					Unsupported: true,
+				})
+				continue
+			}
+		}
+
 		// Capture pane content
 		content, err := t.CapturePaneAll(session)
 		if err != nil {
@@ -449,6 +489,7 @@ func outputCostsHuman(costs []SessionCost, total float64) error {
 	fmt.Println(strings.Repeat("─", 75))
 
 	// Print each session
+	unsupported := 0
 	for _, c := range costs {
 		statusIcon := style.Success.Render("●")
 		if !c.Running {
@@ -464,17 +505,26 @@ func outputCostsHuman(costs []SessionCost, total float64) error {
 			}
 		}
 
+		cost := fmt.Sprintf("$%.2f", c.Cost)
+		if c.Unsupported {
+			cost = "n/a"
+			unsupported++
+		}
+
 		fmt.Printf("%-25s %-10s %-15s %10s %8s\n",
 			c.Session,
 			c.Role,
 			rigWorker,
-			fmt.Sprintf("$%.2f", c.Cost),
+			cost,
 			statusIcon)
 	}
 
 	// Print total
 	fmt.Println(strings.Repeat("─", 75))
 	fmt.Printf("%s %s\n", style.Bold.Render("Total:"), fmt.Sprintf("$%.2f", total))
+	if unsupported > 0 {
+		fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("(excludes %d session(s) whose runtime does not report token usage)", unsupported)))
+	}
 
 	return nil
 }
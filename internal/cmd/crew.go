@@ -8,16 +8,17 @@ import (
 
 // Crew command flags
 var (
-	crewRig      string
-	crewBranch   bool
-	crewJSON     bool
-	crewForce    bool
-	crewNoTmux   bool
-	crewDetached bool
-	crewMessage  string
-	crewAccount  string
-	crewAll      bool
-	crewDryRun   bool
+	crewRig         string
+	crewBranch      bool
+	crewJSON        bool
+	crewForce       bool
+	crewNoTmux      bool
+	crewDetached    bool
+	crewMessage     string
+	crewAccount     string
+	crewAll         bool
+	crewDryRun      bool
+	crewKeepPartial bool
 )
 
 var crewCmd = &cobra.Command{
@@ -250,6 +251,10 @@ to start just that worker, or use --all to start all crew members in the rig.
 
 The crew session starts in the background with Claude running and ready.
 
+Starting several workers is all-or-nothing: if any fails, the sessions this
+run started are stopped again (workspaces are kept) and a per-worker summary
+is printed. Use --keep-partial to leave the successful starts running.
+
 Examples:
   gt crew start gastown joe       # Start joe in gastown rig
   gt crew start gastown --all     # Start all crew in gastown rig
@@ -336,6 +341,7 @@ func init() {
 
 	crewStartCmd.Flags().BoolVar(&crewAll, "all", false, "Start all crew members in the rig")
 	crewStartCmd.Flags().StringVar(&crewAccount, "account", "", "Claude Code account handle to use")
+	crewStartCmd.Flags().BoolVar(&crewKeepPartial, "keep-partial", false, "Leave started sessions running if another start fails")
 
 	crewStopCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to use (filter when using --all)")
 	crewStopCmd.Flags().BoolVar(&crewAll, "all", false, "Stop all running crew sessions")
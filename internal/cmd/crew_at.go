@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/agentrt"
 	"github.com/ctiospl/gastown/internal/config"
 	"github.com/ctiospl/gastown/internal/constants"
 	"github.com/ctiospl/gastown/internal/crew"
@@ -78,6 +79,7 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 	// Check if session exists
 	t := tmux.NewTmux()
 	sessionID := crewSessionName(r.Name, name)
+	rt := agentrt.ForAgent(townRoot, r.Name+"/crew/"+name)
 	hasSession, err := t.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
@@ -154,16 +156,17 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 		if err := t.RespawnPane(paneID, claudeCmd); err != nil {
 			return fmt.Errorf("starting claude: %w", err)
 		}
+		typeCrewPrompt(t, rt, sessionID, config.CrewTypedPrompt(r.Name, name, r.Path, "gt prime"))
 
 		fmt.Printf("%s Created session for %s/%s\n",
 			style.Bold.Render("✓"), r.Name, name)
 	} else {
-		// Session exists - check if Claude is still running
+		// Session exists - check if the runtime is still running
 		// Uses both pane command check and UI marker detection to avoid
 		// restarting when user is in a subshell spawned from Claude
-		if !t.IsClaudeRunning(sessionID) {
-			// Claude has exited, restart it using respawn-pane
-			fmt.Printf("Claude exited, restarting...\n")
+		if !rt.Running(t, sessionID) {
+			// The runtime has exited, restart it using respawn-pane
+			fmt.Printf("%s exited, restarting...\n", rt.Name())
 
 			// Get pane ID for respawn
 			paneID, err := t.GetPaneID(sessionID)
@@ -178,6 +181,7 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 			if err := t.RespawnPane(paneID, claudeCmd); err != nil {
 				return fmt.Errorf("restarting claude: %w", err)
 			}
+			typeCrewPrompt(t, rt, sessionID, config.CrewTypedPrompt(r.Name, name, r.Path, "gt prime"))
 		}
 	}
 
@@ -205,3 +209,18 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 	// Attach to session
 	return attachToTmuxSession(sessionID)
 }
+
+// typeCrewPrompt types the startup prompt into a session just started with
+// a runtime that takes none on its command line, once it is ready.
+func typeCrewPrompt(t *tmux.Tmux, rt agentrt.Adapter, sessionID, prompt string) {
+	if prompt == "" {
+		return
+	}
+	if err := agentrt.WaitReady(t, rt, sessionID, constants.ClaudeStartTimeout); err != nil {
+		style.PrintWarning("%v", err)
+		return
+	}
+	if err := rt.Nudge(t, sessionID, prompt); err != nil {
+		style.PrintWarning("Could not send startup prompt: %v", err)
+	}
+}
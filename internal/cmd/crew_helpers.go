@@ -11,13 +11,15 @@ import (
 	"github.com/ctiospl/gastown/internal/config"
 	"github.com/ctiospl/gastown/internal/constants"
 	"github.com/ctiospl/gastown/internal/crew"
+	"github.com/ctiospl/gastown/internal/focus"
 	"github.com/ctiospl/gastown/internal/git"
 	"github.com/ctiospl/gastown/internal/rig"
 	"github.com/ctiospl/gastown/internal/style"
 	"github.com/ctiospl/gastown/internal/workspace"
 )
 
-// inferRigFromCwd tries to determine the rig from the current directory.
+// inferRigFromCwd tries to determine the rig from the current directory,
+// falling back to the focused rig (see gt focus) outside any rig.
 func inferRigFromCwd(townRoot string) (string, error) {
 	cwd, err := filepath.Abs(".")
 	if err != nil {
@@ -38,6 +40,11 @@ func inferRigFromCwd(townRoot string) (string, error) {
 		return parts[0], nil
 	}
 
+	// Outside any rig, default to the operator's focus
+	if f := focus.Current(townRoot); f != nil && f.Rig != "" {
+		return f.Rig, nil
+	}
+
 	return "", fmt.Errorf("could not infer rig from current directory")
 }
 
@@ -13,6 +13,7 @@ import (
 	"github.com/ctiospl/gastown/internal/config"
 	"github.com/ctiospl/gastown/internal/constants"
 	"github.com/ctiospl/gastown/internal/crew"
+	"github.com/ctiospl/gastown/internal/drift"
 	"github.com/ctiospl/gastown/internal/mail"
 	"github.com/ctiospl/gastown/internal/session"
 	"github.com/ctiospl/gastown/internal/style"
@@ -246,9 +247,10 @@ func runCrewStart(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Start each crew member
-	var lastErr error
-	startedCount := 0
+	// Start each crew member. If any fails, stop the sessions this run
+	// started so the rig is left as it was (unless --keep-partial).
+	t := tmux.NewTmux()
+	b := newBatch("crew start")
 	for _, name := range crewNames {
 		// Set the start.go flags before calling runStartCrew
 		startCrewRig = rigName
@@ -256,20 +258,32 @@ func runCrewStart(cmd *cobra.Command, args []string) error {
 
 		// Use rig/name format for runStartCrew
 		fullName := rigName + "/" + name
+		sessionID := crewSessionName(rigName, name)
+		wasRunning, _ := t.HasSession(sessionID)
 		if err := runStartCrew(cmd, []string{fullName}); err != nil {
-			fmt.Printf("Error starting %s/%s: %v\n", rigName, name, err)
-			lastErr = err
-		} else {
-			startedCount++
+			fmt.Printf("Error starting %s: %v\n", fullName, err)
+			b.fail(fullName, err)
+			continue
 		}
+		if wasRunning {
+			b.skip(fullName, "already running")
+			continue
+		}
+		b.succeed(fullName, "started", func() error {
+			return t.KillSession(sessionID)
+		})
 	}
 
-	if startedCount > 0 {
-		fmt.Printf("\n%s Started %d crew member(s) in %s\n",
-			style.Bold.Render("✓"), startedCount, r.Name)
+	if !crewKeepPartial {
+		b.rollback()
+	}
+	if len(crewNames) > 1 {
+		b.report()
+	} else if b.err() == nil {
+		fmt.Printf("\n%s Started crew member in %s\n", style.Bold.Render("✓"), r.Name)
 	}
 
-	return lastErr
+	return b.err()
 }
 
 func runCrewRestart(cmd *cobra.Command, args []string) error {
@@ -474,7 +488,7 @@ func runCrewRestartAll() error {
 		for _, f := range failures {
 			fmt.Printf("  %s\n", style.Dim.Render(f))
 		}
-		return fmt.Errorf("%d restart(s) failed", failed)
+		return partialFailure("%d restart(s) failed", failed)
 	}
 
 	fmt.Printf("%s Restart complete: %d crew session(s) restarted\n", style.SuccessPrefix, succeeded)
@@ -591,6 +605,9 @@ func runCrewStop(cmd *cobra.Command, args []string) error {
 			style.SuccessPrefix,
 			r.Name, name)
 
+		// Baseline for detecting edits made while the session is down
+		_ = drift.Record(filepath.Join(r.Path, "crew", name))
+
 		// Log kill event to town log
 		townRoot, _ := workspace.Find(r.Path)
 		if townRoot != "" {
@@ -683,6 +700,7 @@ func runCrewStopAll() error {
 		if townRoot != "" {
 			logger := townlog.NewLogger(townRoot)
 			_ = logger.Log(townlog.EventKill, agentName, "gt crew stop --all")
+			_ = drift.Record(filepath.Join(townRoot, agent.Rig, "crew", agent.AgentName))
 		}
 
 		// Log captured output (truncated)
@@ -701,7 +719,7 @@ func runCrewStopAll() error {
 		for _, f := range failures {
 			fmt.Printf("  %s\n", style.Dim.Render(f))
 		}
-		return fmt.Errorf("%d stop(s) failed", failed)
+		return partialFailure("%d stop(s) failed", failed)
 	}
 
 	fmt.Printf("%s Stop complete: %d crew session(s) stopped\n", style.SuccessPrefix, succeeded)
@@ -9,6 +9,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/ctiospl/gastown/internal/daemon"
+	"github.com/ctiospl/gastown/internal/errcode"
+	"github.com/ctiospl/gastown/internal/notify"
 	"github.com/ctiospl/gastown/internal/style"
 	"github.com/ctiospl/gastown/internal/workspace"
 )
@@ -24,8 +26,22 @@ The daemon is a simple Go process that:
 - Pokes agents periodically (heartbeat)
 - Processes lifecycle requests (cycle, restart, shutdown)
 - Restarts sessions when agents request cycling
+- Raises blockers of high-priority issues to that priority (priority
+  inheritance), mails their assignee, and reverts the boost when the
+  blocked issue closes; active boosts are listed in
+  .runtime/priority-inheritance.json and labelled inherited-priority
 
-The daemon is a "dumb scheduler" - all intelligence is in agents.`,
+The daemon is a "dumb scheduler" - all intelligence is in agents.
+
+Chaos mode (staging towns only):
+  Set daemon.chaos in mayor/config.json to inject faults each heartbeat:
+
+    "daemon": {"chaos": {"enabled": true, "kill_probability": 0.1,
+               "corrupt_probability": 0.05, "nudge_delay": "30s"}}
+
+  The daemon then randomly kills agent sessions (never mayor or crew),
+  appends conflict markers to polecat worktree files, and delays nudges,
+  so restart policies and rescue paths get exercised.`,
 }
 
 var daemonStartCmd = &cobra.Command{
@@ -47,8 +63,9 @@ var daemonStopCmd = &cobra.Command{
 var daemonStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show daemon status",
-	Long:  `Show the current status of the Gas Town daemon.`,
-	RunE:  runDaemonStatus,
+	Long: `Show the current status of the Gas Town daemon, including its liveness
+heartbeat and whether it is installed as a systemd or launchd user service.`,
+	RunE: runDaemonStatus,
 }
 
 var daemonLogsCmd = &cobra.Command{
@@ -58,6 +75,25 @@ var daemonLogsCmd = &cobra.Command{
 	RunE:  runDaemonLogs,
 }
 
+var daemonCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check daemon liveness and page on-call if it is down",
+	Long: `Check that the daemon is running and heartbeating.
+
+If the daemon is down (or has stopped writing its liveness heartbeat), this raises
+the "daemon-down" condition through the notification rules, which can open a
+PagerDuty or Opsgenie incident. When the daemon is healthy again, the
+incident is resolved. Repeat checks while down do not re-page.
+
+Intended to run from cron or a systemd timer, outside the daemon itself:
+  */5 * * * * cd ~/gt && gt daemon check
+
+Exits 1 when the daemon is down.`,
+	SilenceUsage:  true, // exit code is the signal; don't print usage
+	SilenceErrors: true,
+	RunE:          runDaemonCheck,
+}
+
 var daemonRunCmd = &cobra.Command{
 	Use:    "run",
 	Short:  "Run daemon in foreground (internal)",
@@ -66,7 +102,7 @@ var daemonRunCmd = &cobra.Command{
 }
 
 var (
-	daemonLogLines int
+	daemonLogLines  int
 	daemonLogFollow bool
 )
 
@@ -75,6 +111,7 @@ func init() {
 	daemonCmd.AddCommand(daemonStopCmd)
 	daemonCmd.AddCommand(daemonStatusCmd)
 	daemonCmd.AddCommand(daemonLogsCmd)
+	daemonCmd.AddCommand(daemonCheckCmd)
 	daemonCmd.AddCommand(daemonRunCmd)
 
 	daemonLogsCmd.Flags().IntVarP(&daemonLogLines, "lines", "n", 50, "Number of lines to show")
@@ -105,7 +142,7 @@ func runDaemonStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding executable: %w", err)
 	}
 
-	daemonCmd := exec.Command(gtPath, "daemon", "run")
+	daemonCmd := exec.Command(gtPath, append([]string{"daemon", "run"}, internalLogArgs()...)...)
 	daemonCmd.Dir = townRoot
 
 	// Detach from terminal
@@ -153,7 +190,7 @@ func runDaemonStop(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("checking daemon status: %w", err)
 	}
 	if !running {
-		return fmt.Errorf("daemon is not running")
+		return errcode.New(errcode.DaemonNotRunning, "daemon is not running")
 	}
 
 	if err := daemon.StopDaemon(townRoot); err != nil {
@@ -161,6 +198,10 @@ func runDaemonStop(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s Daemon stopped (was PID %d)\n", style.Bold.Render("✓"), pid)
+	if svc, err := queryServiceStatus(townRoot); err == nil && svc.Active {
+		fmt.Printf("  %s %s will restart it; use 'gt daemon uninstall' to stop supervision\n",
+			style.WarningPrefix, svc.Manager)
+	}
 	return nil
 }
 
@@ -175,11 +216,17 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("checking daemon status: %w", err)
 	}
 
+	health := daemon.CheckHealth(townRoot)
+
 	if running {
 		fmt.Printf("%s Daemon is %s (PID %d)\n",
 			style.Bold.Render("●"),
 			style.Bold.Render("running"),
 			pid)
+		if health.Status == daemon.HealthHung {
+			fmt.Printf("  %s Liveness heartbeat stale (last %s ago) - daemon may be hung\n",
+				style.WarningPrefix, time.Since(health.LastBeat).Round(time.Second))
+		}
 
 		// Load state for more details
 		state, err := daemon.LoadState(townRoot)
@@ -205,12 +252,87 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s Daemon is %s\n",
 			style.Dim.Render("○"),
 			"not running")
+		if health.Status == daemon.HealthDead {
+			fmt.Printf("  %s Daemon died without a clean stop (last heartbeat %s)\n",
+				style.WarningPrefix, health.LastBeat.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	if svc, err := queryServiceStatus(townRoot); err == nil {
+		if svc.Installed {
+			state := "inactive"
+			if svc.Active {
+				state = "active"
+			}
+			enabled := "disabled"
+			if svc.Enabled {
+				enabled = "enabled"
+			}
+			fmt.Printf("  Service: %s (%s, %s, %s)\n", svc.Name, svc.Manager, enabled, state)
+		} else if !running {
+			fmt.Printf("  Service: %s\n", style.Dim.Render("not installed (gt daemon install)"))
+		}
+	}
+
+	if !running {
 		fmt.Printf("\nStart with: %s\n", style.Dim.Render("gt daemon start"))
 	}
 
 	return nil
 }
 
+// daemonStaleAfter is how old the last heartbeat may be before a running
+// daemon is considered hung. The recovery heartbeat runs every 3 minutes.
+const daemonStaleAfter = 10 * time.Minute
+
+func runDaemonCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	running, pid, err := daemon.IsRunning(townRoot)
+	if err != nil {
+		return fmt.Errorf("checking daemon status: %w", err)
+	}
+
+	problem := ""
+	if !running {
+		problem = "daemon is not running"
+	} else if health := daemon.CheckHealth(townRoot); health.Status == daemon.HealthHung {
+		problem = fmt.Sprintf("daemon (PID %d) last heartbeat %s ago", pid, time.Since(health.LastBeat).Round(time.Second))
+	} else if state, err := daemon.LoadState(townRoot); err == nil && !state.LastHeartbeat.IsZero() {
+		// Beating but not finishing recovery passes is hung too
+		if age := time.Since(state.LastHeartbeat); age > daemonStaleAfter {
+			problem = fmt.Sprintf("daemon (PID %d) last recovery pass %s ago", pid, age.Round(time.Second))
+		}
+	}
+
+	if problem == "" {
+		if _, err := notify.Clear(townRoot, notify.ConditionDaemonDown, "daemon healthy"); err != nil {
+			style.PrintWarning("resolving daemon-down incident: %v", err)
+		}
+		fmt.Printf("%s Daemon healthy (PID %d)\n", style.SuccessPrefix, pid)
+		return nil
+	}
+
+	deliveries, err := notify.Raise(townRoot, notify.ConditionDaemonDown, &notify.Notification{
+		Event:    notify.EventDaemonDown,
+		Severity: notify.SeverityCritical,
+		Source:   "daemon",
+		Subject:  "Gas Town daemon down: " + problem,
+		Body:     fmt.Sprintf("Town: %s\nRestart with: gt daemon start", townRoot),
+	})
+	if err != nil {
+		style.PrintWarning("raising daemon-down incident: %v", err)
+	}
+	fmt.Printf("%s %s\n", style.ErrorPrefix, problem)
+	for _, d := range deliveries {
+		fmt.Printf("  Notified %s (%s)\n", d.Channel, d.Rule)
+	}
+	return NewSilentExit(1)
+}
+
 // getBinaryModTime returns the modification time of the current executable
 func getBinaryModTime() (time.Time, error) {
 	exePath, err := os.Executable()
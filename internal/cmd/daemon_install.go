@@ -0,0 +1,384 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/daemon"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	daemonInstallPrint    bool
+	daemonInstallNoEnable bool
+	daemonServiceManager  string
+)
+
+var daemonInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the daemon as a user service (systemd or launchd)",
+	Long: `Install the Gas Town daemon as a persistent user service.
+
+On Linux this writes a systemd user unit:
+  ~/.config/systemd/user/gastown-daemon-<town>.service
+which runs 'gt daemon run' with Type=notify and WatchdogSec. The daemon pings
+the watchdog every 30s while its recovery heartbeat keeps finishing passes;
+if it hangs or dies, systemd restarts it. To keep it running after you log
+out, enable lingering once: loginctl enable-linger $USER
+
+On macOS this writes a launchd user agent:
+  ~/Library/LaunchAgents/com.gastown.daemon.<town>.plist
+with KeepAlive, so launchd restarts the daemon if it exits.
+
+The unit records the absolute gt path, the town root as working directory,
+and the current PATH and HOME, so agent runtimes resolve the same way they
+do in your shell. Re-run install after moving gt or changing PATH.
+
+By default the service is enabled and started immediately. A daemon started
+with 'gt daemon start' is stopped first so the service manager can take over.
+Use 'gt daemon status' to see whether the service is installed and active.
+
+Examples:
+  gt daemon install                    # Write, enable, and start the service
+  gt daemon install --print            # Show the unit without installing
+  gt daemon install --no-enable        # Write the unit only
+  gt daemon install --manager launchd --print`,
+	RunE: runDaemonInstall,
+}
+
+var daemonUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the daemon user service",
+	Long: `Stop and disable the daemon user service and delete its unit file.
+
+The daemon is no longer supervised afterwards; run 'gt daemon start' to
+run it by hand.
+
+Examples:
+  gt daemon uninstall`,
+	RunE: runDaemonUninstall,
+}
+
+func init() {
+	daemonInstallCmd.Flags().BoolVar(&daemonInstallPrint, "print", false, "Print the unit file instead of installing it")
+	daemonInstallCmd.Flags().BoolVar(&daemonInstallNoEnable, "no-enable", false, "Write the unit without enabling or starting it")
+	daemonInstallCmd.Flags().StringVar(&daemonServiceManager, "manager", "", "Service manager: systemd or launchd (default: detect from OS)")
+	daemonUninstallCmd.Flags().StringVar(&daemonServiceManager, "manager", "", "Service manager: systemd or launchd (default: detect from OS)")
+	daemonCmd.AddCommand(daemonInstallCmd)
+	daemonCmd.AddCommand(daemonUninstallCmd)
+}
+
+func runDaemonInstall(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	manager, err := resolveServiceManager()
+	if err != nil {
+		return err
+	}
+	spec, err := daemonServiceSpec(townRoot)
+	if err != nil {
+		return err
+	}
+
+	unit := daemon.ServiceUnit(manager, spec)
+	if daemonInstallPrint {
+		fmt.Print(unit)
+		return nil
+	}
+
+	if err := requireServiceTool(manager); err != nil {
+		return err
+	}
+
+	unitPath, err := serviceUnitPath(manager, spec.TownName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(unitPath), 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(unitPath), err)
+	}
+	if err := os.WriteFile(unitPath, []byte(unit), 0644); err != nil { //nolint:gosec // G306: unit files are not secret
+		return fmt.Errorf("writing unit: %w", err)
+	}
+	fmt.Printf("%s Wrote %s\n", style.SuccessPrefix, unitPath)
+
+	if manager == daemon.ServiceSystemd {
+		if err := systemctlUser("daemon-reload"); err != nil {
+			return err
+		}
+	}
+	if daemonInstallNoEnable {
+		fmt.Printf("  Enable with: %s\n", style.Dim.Render(serviceEnableHint(manager, unitPath)))
+		return nil
+	}
+
+	// A daemon started with 'gt daemon start' holds the lock; hand over.
+	if running, _, _ := daemon.IsRunning(townRoot); running {
+		fmt.Printf("  Stopping existing daemon so %s can take over...\n", manager)
+		if err := daemon.StopDaemon(townRoot); err != nil {
+			return fmt.Errorf("stopping existing daemon: %w", err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	switch manager {
+	case daemon.ServiceLaunchd:
+		// Reload so a re-install picks up the new plist.
+		_ = launchctl("bootout", launchdTarget(spec.TownName))
+		if err := launchctl("bootstrap", launchdDomain(), unitPath); err != nil {
+			return err
+		}
+	default:
+		if err := systemctlUser("enable", "--now", filepath.Base(unitPath)); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("%s Enabled and started %s\n", style.SuccessPrefix, filepath.Base(unitPath))
+	return nil
+}
+
+func runDaemonUninstall(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	manager, err := resolveServiceManager()
+	if err != nil {
+		return err
+	}
+	spec, err := daemonServiceSpec(townRoot)
+	if err != nil {
+		return err
+	}
+	unitPath, err := serviceUnitPath(manager, spec.TownName)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(unitPath); os.IsNotExist(err) {
+		fmt.Printf("%s No %s service installed for this town\n", style.Dim.Render("○"), manager)
+		return nil
+	}
+
+	if err := requireServiceTool(manager); err != nil {
+		return err
+	}
+	switch manager {
+	case daemon.ServiceLaunchd:
+		// bootout fails if the agent is not loaded; that's fine.
+		_ = launchctl("bootout", launchdTarget(spec.TownName))
+	default:
+		if err := systemctlUser("disable", "--now", filepath.Base(unitPath)); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Remove(unitPath); err != nil {
+		return fmt.Errorf("removing unit: %w", err)
+	}
+	if manager == daemon.ServiceSystemd {
+		_ = systemctlUser("daemon-reload")
+	}
+	fmt.Printf("%s Removed %s\n", style.SuccessPrefix, unitPath)
+	return nil
+}
+
+// serviceStatus describes the installed service for 'gt daemon status'.
+type serviceStatus struct {
+	Manager   daemon.ServiceManager
+	Name      string
+	UnitPath  string
+	Installed bool
+	Enabled   bool
+	Active    bool
+}
+
+// queryServiceStatus reports whether this town's daemon service is installed
+// and active. Errors from the service manager count as "not active".
+func queryServiceStatus(townRoot string) (*serviceStatus, error) {
+	manager, err := resolveServiceManager()
+	if err != nil {
+		return nil, err
+	}
+	spec, err := daemonServiceSpec(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	unitPath, err := serviceUnitPath(manager, spec.TownName)
+	if err != nil {
+		return nil, err
+	}
+
+	st := &serviceStatus{
+		Manager:  manager,
+		Name:     filepath.Base(unitPath),
+		UnitPath: unitPath,
+	}
+	if _, err := os.Stat(unitPath); err != nil {
+		return st, nil
+	}
+	st.Installed = true
+	if requireServiceTool(manager) != nil {
+		return st, nil
+	}
+
+	switch manager {
+	case daemon.ServiceLaunchd:
+		// Loaded agents print their state; RunAtLoad means loaded == enabled.
+		out, err := exec.Command("launchctl", "print", launchdTarget(spec.TownName)).CombinedOutput()
+		st.Enabled = err == nil
+		st.Active = err == nil && strings.Contains(string(out), "state = running")
+	default:
+		st.Enabled = systemctlUser("is-enabled", "--quiet", st.Name) == nil
+		st.Active = systemctlUser("is-active", "--quiet", st.Name) == nil
+	}
+	return st, nil
+}
+
+// resolveServiceManager returns the --manager flag value or the OS default.
+func resolveServiceManager() (daemon.ServiceManager, error) {
+	if daemonServiceManager != "" {
+		return daemon.ParseServiceManager(daemonServiceManager)
+	}
+	return daemon.DetectServiceManager()
+}
+
+// requireServiceTool checks that the manager's control tool is on PATH.
+func requireServiceTool(manager daemon.ServiceManager) error {
+	tool := "systemctl"
+	if manager == daemon.ServiceLaunchd {
+		tool = "launchctl"
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return fmt.Errorf("%s not found: %s user services are required (use --print to inspect the unit)", tool, manager)
+	}
+	return nil
+}
+
+// serviceUnitPath returns where the unit file for this town lives.
+func serviceUnitPath(manager daemon.ServiceManager, townName string) (string, error) {
+	var dir string
+	if manager == daemon.ServiceLaunchd {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("finding home directory: %w", err)
+		}
+		dir = filepath.Join(home, "Library", "LaunchAgents")
+	} else {
+		var err error
+		if dir, err = systemdUserDir(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, daemon.ServiceFileName(manager, townName)), nil
+}
+
+func serviceEnableHint(manager daemon.ServiceManager, unitPath string) string {
+	if manager == daemon.ServiceLaunchd {
+		return "launchctl bootstrap " + launchdDomain() + " " + unitPath
+	}
+	return "systemctl --user enable --now " + filepath.Base(unitPath)
+}
+
+// daemonServiceSpec describes this town's daemon for service managers.
+func daemonServiceSpec(townRoot string) (daemon.ServiceSpec, error) {
+	gtPath, err := os.Executable()
+	if err != nil {
+		return daemon.ServiceSpec{}, fmt.Errorf("finding executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(gtPath); err == nil {
+		gtPath = resolved
+	}
+
+	townName := filepath.Base(townRoot)
+	if townConfig, err := config.LoadTownConfig(constants.MayorTownPath(townRoot)); err == nil && townConfig.Name != "" {
+		townName = townConfig.Name
+	}
+
+	spec := daemon.ServiceSpec{
+		TownName: townName,
+		TownRoot: townRoot,
+		GTPath:   gtPath,
+		Path:     os.Getenv("PATH"),
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		spec.Home = home
+	}
+	if u, err := user.Current(); err == nil {
+		spec.User = u.Username
+	}
+	return spec, nil
+}
+
+// systemdUserDir returns the systemd user unit directory.
+func systemdUserDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "systemd", "user"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "systemd", "user"), nil
+}
+
+func systemctlUser(args ...string) error {
+	out, err := exec.Command("systemctl", append([]string{"--user"}, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("systemctl --user %s: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// launchdDomain returns the per-user GUI domain, e.g. "gui/501".
+func launchdDomain() string {
+	return fmt.Sprintf("gui/%d", os.Getuid())
+}
+
+// launchdTarget returns the service target for this town's agent.
+func launchdTarget(townName string) string {
+	return launchdDomain() + "/" + daemon.LaunchdLabel(townName)
+}
+
+func launchctl(args ...string) error {
+	out, err := exec.Command("launchctl", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("launchctl %s: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// warnIfDaemonDown prints a warning when the daemon died or hung without a
+// clean stop, since that silently disables all supervision. Runs before
+// every command; it only reads a small file, so it adds no visible latency.
+func warnIfDaemonDown(cmd *cobra.Command, args []string) {
+	// Daemon commands report health themselves.
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == daemonCmd {
+			return
+		}
+	}
+
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+
+	health := daemon.CheckHealth(townRoot)
+	if !health.Unhealthy() {
+		return
+	}
+	ago := time.Since(health.LastBeat).Round(time.Second)
+	fmt.Fprintf(os.Stderr, "%s daemon is %s (last heartbeat %s ago) - supervision is disabled. Run 'gt daemon start'\n",
+		style.WarningPrefix, health.Status, ago)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/ctiospl/gastown/internal/config"
 	"github.com/ctiospl/gastown/internal/constants"
 	"github.com/ctiospl/gastown/internal/deacon"
+	"github.com/ctiospl/gastown/internal/errcode"
 	"github.com/ctiospl/gastown/internal/polecat"
 	"github.com/ctiospl/gastown/internal/session"
 	"github.com/ctiospl/gastown/internal/style"
@@ -136,9 +137,9 @@ The detection protocol:
 4. After N consecutive failures (default 3), recommend force-kill
 
 Exit codes:
-  0 - Agent responded or is in cooldown (no action needed)
-  1 - Error occurred
-  2 - Agent should be force-killed (consecutive failures exceeded)
+  0  - Agent responded or is in cooldown (no action needed)
+  10 - Agent should be force-killed (consecutive failures exceeded)
+  Any other status is an error, classified as described in 'gt errors'.
 
 Examples:
   gt deacon health-check gastown/polecats/max
@@ -186,7 +187,6 @@ This helps the Deacon understand which agents may need attention.`,
 	RunE: runDeaconHealthState,
 }
 
-
 var (
 	triggerTimeout time.Duration
 
@@ -687,7 +687,7 @@ func runDeaconHealthCheck(cmd *cobra.Command, args []string) error {
 	// Check if force-kill threshold reached
 	if agentState.ShouldForceKill(healthCheckFailures) {
 		fmt.Printf("%s Agent %s should be force-killed\n", style.Bold.Render("✗"), agent)
-		os.Exit(2) // Exit code 2 = should force-kill
+		return NewSilentExit(errcode.ExitCommandStatus) // should force-kill
 	}
 
 	return nil
@@ -907,4 +907,3 @@ func updateAgentBeadState(townRoot, agent, state, _ string) { // reason unused b
 	cmd.Dir = townRoot
 	_ = cmd.Run() // Best effort
 }
-
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/drift"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	diffOutside bool
+	diffPatch   bool
+	diffJSON    bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:     "diff [agent]",
+	GroupID: GroupWork,
+	Short:   "Show uncommitted changes in an agent's worktree",
+	Long: `List the uncommitted files in an agent's worktree, marking those changed
+outside the agent's sessions.
+
+When an agent's session ends its worktree is snapshotted. Edits made
+before the next session starts (by a human, another tool, a commit) are
+reported to the agent when it primes and marked here as "outside". While
+the agent is between sessions they are computed live.
+
+The agent defaults to the worktree of the current directory.
+
+Examples:
+  gt diff                          # Current worktree
+  gt diff gastown/nux              # A polecat's worktree
+  gt diff gastown/crew/max --outside --patch
+  gt diff --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().BoolVar(&diffOutside, "outside", false, "Only files changed outside the agent's sessions")
+	diffCmd.Flags().BoolVarP(&diffPatch, "patch", "p", false, "Show the full diff against HEAD")
+	diffCmd.Flags().BoolVar(&diffJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(diffCmd)
+}
+
+// diffFile is one uncommitted file in 'gt diff' output.
+type diffFile struct {
+	Path    string `json:"path"`
+	Status  string `json:"status"`
+	Outside bool   `json:"outside,omitempty"`
+}
+
+// diffJSONOutput is the --json form of 'gt diff'.
+type diffJSONOutput struct {
+	Worktree string        `json:"worktree"`
+	Files    []diffFile    `json:"files"`
+	Outside  *drift.Report `json:"outside,omitempty"`
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	worktree, err := diffWorktree(args)
+	if err != nil {
+		return err
+	}
+
+	snap, err := drift.Take(worktree)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", worktree, err)
+	}
+	report, err := drift.Peek(worktree)
+	if err != nil {
+		return err
+	}
+	files := diffFiles(snap, report, diffOutside)
+
+	if diffJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diffJSONOutput{Worktree: worktree, Files: files, Outside: report})
+	}
+
+	if report != nil && len(report.Commits) > 0 {
+		fmt.Printf("%s %d commit(s) made outside the agent's sessions since %s:\n",
+			style.Warning.Render("⚠"), len(report.Commits), report.Since.Local().Format("2006-01-02 15:04"))
+		for _, c := range report.Commits {
+			fmt.Printf("  %s\n", c)
+		}
+		fmt.Println()
+	}
+	if len(files) == 0 {
+		if diffOutside {
+			fmt.Printf("%s No uncommitted files changed outside the agent's sessions\n", style.Dim.Render("○"))
+		} else {
+			fmt.Printf("%s No uncommitted changes\n", style.Dim.Render("○"))
+		}
+		return nil
+	}
+	for _, f := range files {
+		line := fmt.Sprintf("  %s %s", f.Status, f.Path)
+		if f.Outside {
+			line += " " + style.Warning.Render("(changed outside session)")
+		}
+		fmt.Println(line)
+	}
+	if report != nil && !diffOutside {
+		fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("Outside changes detected %s ago; see them alone with --outside",
+			time.Since(report.DetectedAt).Round(time.Minute))))
+	}
+
+	if diffPatch {
+		paths := make([]string, 0, len(files))
+		for _, f := range files {
+			paths = append(paths, f.Path)
+		}
+		fmt.Println()
+		gitDiff := exec.Command("git", append([]string{"diff", "HEAD", "--"}, paths...)...)
+		gitDiff.Dir = worktree
+		gitDiff.Stdout = os.Stdout
+		gitDiff.Stderr = os.Stderr
+		return gitDiff.Run()
+	}
+	return nil
+}
+
+// diffWorktree resolves the worktree 'gt diff' looks at.
+func diffWorktree(args []string) (string, error) {
+	if len(args) == 0 {
+		root, err := getGitRoot()
+		if err != nil {
+			return "", fmt.Errorf("not in a git worktree (pass an agent address)")
+		}
+		return root, nil
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	dir := agentWorkDir(townRoot, args[0])
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("no worktree for %s at %s", args[0], dir)
+	}
+	return dir, nil
+}
+
+// diffFiles lists the snapshot's uncommitted files, marking those in the
+// outside report. Files the report saw that are now clean are left out.
+func diffFiles(snap *drift.Snapshot, report *drift.Report, outsideOnly bool) []diffFile {
+	outside := make(map[string]bool)
+	for _, p := range report.Paths() {
+		outside[p] = true
+	}
+	var files []diffFile
+	for path, st := range snap.Files {
+		if outsideOnly && !outside[path] {
+			continue
+		}
+		files = append(files, diffFile{Path: path, Status: st.Status, Outside: outside[path]})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
@@ -26,7 +26,8 @@ Subcommands:
 
 Without arguments, toggles DND mode.
 
-Related: gt notify - for fine-grained notification level control`,
+Related: gt notify - for fine-grained notification level control
+         gt agent dnd - for scheduled DND windows that end on their own`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runDnd,
 }
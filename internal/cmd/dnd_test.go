@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"testing"
+	"time"
 )
 
 func TestAddressToAgentBeadID(t *testing.T) {
@@ -32,3 +33,31 @@ func TestAddressToAgentBeadID(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildDndWindow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 14, 0, 0, 0, time.Local)
+
+	w, err := buildDndWindow("gastown/polecats/Toast/", "", "2h", "", now)
+	if err != nil {
+		t.Fatalf("--for: %v", err)
+	}
+	if w.Agent != "gastown/polecats/Toast" || !w.End.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("--for window = %+v", w)
+	}
+
+	// A clock time already past today means tomorrow
+	w, err = buildDndWindow("witness", "", "", "09:30", now)
+	if err != nil {
+		t.Fatalf("--until: %v", err)
+	}
+	if want := time.Date(2026, 3, 2, 9, 30, 0, 0, time.Local); !w.End.Equal(want) {
+		t.Errorf("--until end = %v, want %v", w.End, want)
+	}
+
+	if _, err := buildDndWindow("mayor", "22:00-07:00", "1h", "", now); err == nil {
+		t.Error("expected error for two schedules")
+	}
+	if _, err := buildDndWindow("mayor", "22:00", "", "", now); err == nil {
+		t.Error("expected error for malformed daily window")
+	}
+}
@@ -57,6 +57,7 @@ var (
 	doneExit          bool
 	donePhaseComplete bool
 	doneGate          string
+	doneUsage         usageFlags
 )
 
 // Valid exit types for gt done
@@ -74,6 +75,7 @@ func init() {
 	doneCmd.Flags().BoolVar(&doneExit, "exit", false, "Exit Claude session after MR submission (self-terminate)")
 	doneCmd.Flags().BoolVar(&donePhaseComplete, "phase-complete", false, "Signal phase complete - await gate before continuing")
 	doneCmd.Flags().StringVar(&doneGate, "gate", "", "Gate bead ID to wait on (with --phase-complete)")
+	addUsageFlags(doneCmd, &doneUsage)
 
 	rootCmd.AddCommand(doneCmd)
 }
@@ -180,6 +182,9 @@ func runDone(cmd *cobra.Command, args []string) error {
 		if aheadCount == 0 {
 			return fmt.Errorf("branch '%s' has 0 commits ahead of %s; nothing to merge", branch, defaultBranch)
 		}
+		if files, err := g.ChangedFiles(defaultBranch, branch); err == nil {
+			warnOutOfScope(townRoot, rigName, files)
+		}
 
 		if issueID == "" {
 			return fmt.Errorf("cannot determine source issue from branch '%s'; use --issue to specify", branch)
@@ -232,6 +237,10 @@ func runDone(cmd *cobra.Command, args []string) error {
 			if agentBeadID != "" {
 				description += fmt.Sprintf("\nagent_bead: %s", agentBeadID)
 			}
+			stacked, isStacked := stackedOn(filepath.Join(townRoot, rigName), branch)
+			if isStacked {
+				description += fmt.Sprintf("\nstacked_on: %s", stacked.Parent)
+			}
 
 			// Add conflict resolution tracking fields (initialized, updated by Refinery)
 			description += "\nretry_count: 0"
@@ -250,6 +259,11 @@ func runDone(cmd *cobra.Command, args []string) error {
 			}
 			mrID = mrIssue.ID
 
+			// Stacked work merges after the branch it is built on
+			if isStacked {
+				holdStackedMR(bd, stacked, mrID)
+			}
+
 			// Update agent bead with active_mr reference (for traceability)
 			if agentBeadID != "" {
 				if err := bd.UpdateAgentActiveMR(agentBeadID, mrID); err != nil {
@@ -331,9 +345,18 @@ func runDone(cmd *cobra.Command, args []string) error {
 	}
 
 	// Log done event (townlog and activity feed)
-	_ = LogDone(townRoot, sender, issueID)
+	_ = LogDone(townRoot, sender, issueID, doneUsage.usage(cwd), archiveTranscript(townRoot, sender, "done "+issueID))
 	_ = events.LogFeed(events.TypeDone, sender, events.DonePayload(issueID, branch))
 
+	// Start the rig's post-done verify suite; it reports on its own
+	if exitType == ExitCompleted {
+		if logPath, err := startVerify(townRoot, rigName, cwd, sender, issueID); err != nil {
+			style.PrintWarning("could not start verify checks: %v", err)
+		} else if logPath != "" {
+			fmt.Printf("%s Verify checks started (output: %s)\n", style.Bold.Render("✓"), logPath)
+		}
+	}
+
 	// Update agent bead state (ZFC: self-report completion)
 	updateAgentStateOnDone(cwd, townRoot, exitType, issueID)
 
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// dryRunPlan is what a lifecycle command (gt spawn, gt kill, gt handoff)
+// would do to one agent, printed by its --dry-run.
+type dryRunPlan struct {
+	Action   string            // e.g. "Would spawn gastown/crew/max"
+	Session  string            // the tmux session
+	Worktree string            // the agent's working directory
+	Details  [][2]string       // more labelled lines, in order
+	Env      map[string]string // the environment set for the runtime
+	Command  string            // typed into the session's shell
+	Prompt   string            // the runtime's first prompt
+}
+
+// String renders the plan as labelled, indented lines.
+func (p dryRunPlan) String() string {
+	var b strings.Builder
+	line := func(label, value string) {
+		fmt.Fprintf(&b, "  %-9s %s\n", label+":", value)
+	}
+	b.WriteString(p.Action + ":\n")
+	if p.Session != "" {
+		line("Session", p.Session)
+	}
+	if p.Worktree != "" {
+		line("Worktree", p.Worktree)
+	}
+	for _, d := range p.Details {
+		line(d[0], d[1])
+	}
+	if len(p.Env) > 0 {
+		names := make([]string, 0, len(p.Env))
+		for k := range p.Env {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for i, k := range names {
+			label := ""
+			if i == 0 {
+				label = "Env:"
+			}
+			fmt.Fprintf(&b, "  %-9s %s=%s\n", label, k, p.Env[k])
+		}
+	}
+	if p.Command != "" {
+		line("Command", p.Command)
+	}
+	if p.Prompt != "" {
+		// Continuation lines under the first
+		lines := strings.Split(p.Prompt, "\n")
+		for i := 1; i < len(lines); i++ {
+			if lines[i] != "" {
+				lines[i] = strings.Repeat(" ", 12) + lines[i]
+			}
+		}
+		line("Prompt", strings.Join(lines, "\n"))
+	}
+	return b.String()
+}
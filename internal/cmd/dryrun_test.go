@@ -0,0 +1,29 @@
+package cmd
+
+import "testing"
+
+func TestDryRunPlanString(t *testing.T) {
+	plan := dryRunPlan{
+		Action:   "Would spawn gastown/crew/max",
+		Session:  "gt-gastown-crew-max",
+		Worktree: "/town/gastown/crew/max (would be created)",
+		Details:  [][2]string{{"Runtime", "claude"}},
+		Env:      map[string]string{"GT_ROLE": "crew", "BD_ACTOR": "gastown/crew/max"},
+		Command:  "export GT_ROLE=crew && claude",
+		Prompt:   "You review pull requests.\n\ngt prime",
+	}
+	want := `Would spawn gastown/crew/max:
+  Session:  gt-gastown-crew-max
+  Worktree: /town/gastown/crew/max (would be created)
+  Runtime:  claude
+  Env:      BD_ACTOR=gastown/crew/max
+            GT_ROLE=crew
+  Command:  export GT_ROLE=crew && claude
+  Prompt:   You review pull requests.
+
+            gt prime
+`
+	if got := plan.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -1,6 +1,32 @@
 package cmd
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/beads"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/crew"
+	"github.com/ctiospl/gastown/internal/errcode"
+	"github.com/ctiospl/gastown/internal/git"
+	"github.com/ctiospl/gastown/internal/lock"
+	"github.com/ctiospl/gastown/internal/mail"
+	"github.com/ctiospl/gastown/internal/oplock"
+	"github.com/ctiospl/gastown/internal/polecat"
+	"github.com/ctiospl/gastown/internal/refinery"
+	"github.com/ctiospl/gastown/internal/rig"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/swarm"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/witness"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
 
 // SilentExitError signals that the command should exit with a specific code
 // without printing an error message. This is used for scripting purposes
@@ -29,3 +55,238 @@ func IsSilentExit(err error) (int, bool) {
 	}
 	return 0, false
 }
+
+// errorSentinels maps the errors other packages return to their codes, so
+// commands that wrap them with fmt.Errorf("...: %w") report a code without
+// naming one.
+var errorSentinels = []struct {
+	err  error
+	code errcode.Code
+}{
+	{workspace.ErrNotFound, errcode.WorkspaceNotFound},
+	{config.ErrNotFound, errcode.ConfigNotFound},
+	{config.ErrInvalidVersion, errcode.ConfigInvalid},
+	{config.ErrInvalidType, errcode.ConfigInvalid},
+	{config.ErrMissingField, errcode.ConfigInvalid},
+	{rig.ErrRigNotFound, errcode.RigNotFound},
+	{rig.ErrRigExists, errcode.RigExists},
+	{oplock.ErrBusy, errcode.TownBusy},
+	{townlog.ErrChainBroken, errcode.LogChainBroken},
+	{tmux.ErrNoServer, errcode.TmuxNotRunning},
+	{tmux.ErrSessionExists, errcode.SessionExists},
+	{session.ErrSessionRunning, errcode.SessionExists},
+	{tmux.ErrSessionNotFound, errcode.SessionDead},
+	{session.ErrSessionNotFound, errcode.SessionDead},
+	{polecat.ErrPolecatNotFound, errcode.PolecatNotFound},
+	{session.ErrPolecatNotFound, errcode.PolecatNotFound},
+	{crew.ErrCrewNotFound, errcode.CrewNotFound},
+	{polecat.ErrHasChanges, errcode.UncommittedChanges},
+	{polecat.ErrHasUncommittedWork, errcode.UncommittedChanges},
+	{crew.ErrHasChanges, errcode.UncommittedChanges},
+	{lock.ErrLocked, errcode.WorkerLocked},
+	{refinery.ErrAlreadyRunning, errcode.AgentAlreadyRunning},
+	{witness.ErrAlreadyRunning, errcode.AgentAlreadyRunning},
+	{git.ErrNotARepo, errcode.NotAGitRepo},
+	{git.ErrMergeConflict, errcode.MergeConflict},
+	{git.ErrRebaseConflict, errcode.MergeConflict},
+	{swarm.ErrMergeConflict, errcode.MergeConflict},
+	{git.ErrAuthFailure, errcode.GitAuthFailed},
+	{beads.ErrNotInstalled, errcode.BdNotInstalled},
+	{beads.ErrNotARepo, errcode.BeadsNotFound},
+	{beads.ErrNotFound, errcode.IssueNotFound},
+	{mail.ErrMessageNotFound, errcode.MessageNotFound},
+	{mail.ErrUnknownList, errcode.UnknownMailGroup},
+	{mail.ErrUnknownQueue, errcode.UnknownMailGroup},
+	{mail.ErrUnknownAnnounce, errcode.UnknownMailGroup},
+	{refinery.ErrNotRunning, errcode.RefineryNotRunning},
+	{witness.ErrNotRunning, errcode.WitnessNotRunning},
+}
+
+// cobraUsageErrors start the bad-command-line errors cobra returns without
+// passing them through an Args validator or the flag error func, which
+// markUsageErrors covers.
+var cobraUsageErrors = []string{
+	"unknown command ",
+	"required flag(s) ",
+	"if any flags in the group ",
+	"at least one of the flags in the group ",
+}
+
+// errorCode returns the code of err: the one it carries, or that of a
+// known error it wraps. It returns "" for errors without one.
+func errorCode(err error) errcode.Code {
+	if code := errcode.Of(err); code != "" {
+		return code
+	}
+	for _, s := range errorSentinels {
+		if errors.Is(err, s.err) {
+			return s.code
+		}
+	}
+	for _, prefix := range cobraUsageErrors {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return errcode.BadUsage
+		}
+	}
+	return ""
+}
+
+// exitStatus returns the process exit status for a command's error.
+func exitStatus(err error) int {
+	if err == nil {
+		return errcode.ExitOK
+	}
+	if code, ok := IsSilentExit(err); ok {
+		return code
+	}
+	return errcode.ExitStatus(errorCode(err))
+}
+
+// markUsageErrors gives the errors of c's and its subcommands' argument
+// validators, and of flag parsing, the bad-usage code, so they exit with
+// errcode.ExitUsage.
+func markUsageErrors(c *cobra.Command) {
+	if validate := c.Args; validate != nil {
+		c.Args = func(cmd *cobra.Command, args []string) error {
+			return errcode.Wrap(errcode.BadUsage, validate(cmd, args))
+		}
+	}
+	c.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return errcode.Wrap(errcode.BadUsage, err)
+	})
+	for _, sub := range c.Commands() {
+		markUsageErrors(sub)
+	}
+}
+
+// partialFailure returns the error of a command acting on several targets
+// when some of them failed. The command reports which ones itself.
+func partialFailure(format string, args ...interface{}) error {
+	return errcode.New(errcode.PartialFailure, format, args...)
+}
+
+// commandErrorJSON is how a failed command reports its error with --json.
+type commandErrorJSON struct {
+	Error struct {
+		Code    errcode.Code `json:"code,omitempty"`
+		Name    string       `json:"name,omitempty"`
+		Message string       `json:"message"`
+		Hint    string       `json:"hint,omitempty"`
+		Exit    int          `json:"exit"`
+	} `json:"error"`
+}
+
+// reportCommandError prints the error a command failed with, as cobra
+// would, and follows it with the error code and remediation hint. When the
+// command was asked for --json, the error is also written to stdout as a
+// JSON object so scripts reading the output see it.
+func reportCommandError(cmd *cobra.Command, err error) {
+	info, coded := errcode.Lookup(string(errorCode(err)))
+
+	if f := cmd.Flags().Lookup("json"); f != nil && f.Value.String() == "true" {
+		var out commandErrorJSON
+		out.Error.Message = err.Error()
+		out.Error.Exit = exitStatus(err)
+		if coded {
+			out.Error.Code, out.Error.Name, out.Error.Hint = info.Code, info.Name, info.Hint
+		}
+		_ = json.NewEncoder(os.Stdout).Encode(out)
+	}
+
+	if cmd.CalledAs() == "" {
+		// The command line did not name a command.
+		rootCmd.PrintErrln(cmd.ErrPrefix(), err.Error())
+		rootCmd.PrintErrf("Run '%v --help' for usage.\n", cmd.CommandPath())
+		return
+	}
+	if !cmd.SilenceErrors {
+		rootCmd.PrintErrln(cmd.ErrPrefix(), err.Error())
+		if coded {
+			rootCmd.PrintErrln(style.Dim.Render(fmt.Sprintf("%s: %s (see 'gt errors %s')", info.Code, info.Hint, info.Code)))
+		}
+	}
+	if cmd == rootCmd || !cmd.SilenceUsage {
+		rootCmd.Println(cmd.UsageString())
+	}
+}
+
+var errorsJSON bool
+
+var errorsCmd = &cobra.Command{
+	Use:     "errors [code]",
+	GroupID: GroupDiag,
+	Short:   "Explain gt error codes",
+	Long: `Explain the error codes gt prints with its errors.
+
+Errors that gt recognizes carry a stable code, such as GT1001 for
+"not in a Gas Town workspace", printed after the message with a one-line
+hint. Scripts can branch on the code instead of the message text: with
+--json, a failed command writes {"error": {"code": ..., "message": ...}}
+to stdout.
+
+Without arguments, all codes are listed. Codes are grouped by area:
+1xxx workspace and configuration, 2xxx agents and sessions, 3xxx git,
+4xxx beads and mail, 5xxx services, 6xxx how the command was run.
+
+Every command exits with a status that classifies its error, so wrappers
+need not parse stderr:
+
+  0  success
+  1  an error without a more specific class
+  2  bad usage: unknown command or flag, wrong arguments
+  3  not inside a Gas Town workspace
+  4  the rig, agent, session, issue, or message does not exist
+  5  partial failure: some of several targets failed
+  6  conflict: already running, locked, uncommitted work, merge conflicts
+  7  a tool or service gt needs is not installed or not running
+
+Each code's class is shown with it. Statuses from 10 up are results that
+specific commands document, such as 'gt deacon health-check'; a few
+query commands, like 'gt mail check', also use 1 as a plain "no".
+
+Examples:
+  gt errors                  # List codes
+  gt errors GT2003           # Explain one
+  gt errors session-dead     # By name
+  gt errors --json           # Machine-readable catalog`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runErrors,
+}
+
+func init() {
+	errorsCmd.Flags().BoolVar(&errorsJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(errorsCmd)
+}
+
+func runErrors(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		infos := errcode.All()
+		if errorsJSON {
+			return outputJSON(infos)
+		}
+		width := 0
+		for _, info := range infos {
+			width = max(width, len(info.Name))
+		}
+		for _, info := range infos {
+			fmt.Printf("  %s  %-*s  exit %d  %s\n", style.Bold.Render(string(info.Code)), width, info.Name, info.Exit, info.Summary)
+		}
+		fmt.Printf("\n%s\n", style.Dim.Render("Explain one with: gt errors <code>"))
+		return nil
+	}
+
+	info, ok := errcode.Lookup(args[0])
+	if !ok {
+		return fmt.Errorf("unknown error code %q; run 'gt errors' to list codes", args[0])
+	}
+	if errorsJSON {
+		return outputJSON(info)
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render(string(info.Code)), info.Name)
+	fmt.Printf("%s\n\n", info.Summary)
+	fmt.Printf("%s\n\n", strings.TrimSpace(info.Details))
+	fmt.Printf("%s %s\n", style.Bold.Render("Fix:"), info.Hint)
+	fmt.Printf("%s %d\n", style.Bold.Render("Exit status:"), info.Exit)
+	return nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/errcode"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want errcode.Code
+	}{
+		{fmt.Errorf("not in a Gas Town workspace: %w", workspace.ErrNotFound), errcode.WorkspaceNotFound},
+		{fmt.Errorf("capturing: %w", tmux.ErrSessionNotFound), errcode.SessionDead},
+		// An explicit code wins over a wrapped sentinel.
+		{errcode.Wrap(errcode.CrewNotFound, tmux.ErrSessionNotFound), errcode.CrewNotFound},
+		{errors.New("something else"), ""},
+	}
+	for _, tt := range tests {
+		if got := errorCode(tt.err); got != tt.want {
+			t.Errorf("errorCode(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+	for _, s := range errorSentinels {
+		if _, ok := errcode.Lookup(string(s.code)); !ok {
+			t.Errorf("sentinel %q maps to undocumented code %s", s.err, s.code)
+		}
+	}
+}
+
+// Hints are printed with errors, so the gt commands they suggest must exist.
+func TestErrorHintCommandsExist(t *testing.T) {
+	quoted := regexp.MustCompile(`'(gt [^']+)'`)
+	for _, info := range errcode.All() {
+		for _, m := range quoted.FindAllStringSubmatch(info.Hint+" "+info.Details, -1) {
+			var words []string
+			for _, f := range strings.Fields(m[1])[1:] {
+				if strings.HasPrefix(f, "<") || strings.HasPrefix(f, "-") {
+					break
+				}
+				words = append(words, f)
+			}
+			if c, _, err := rootCmd.Find(words); err != nil || c == rootCmd {
+				t.Errorf("%s: %q: no such command", info.Code, m[1])
+			}
+		}
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	newTree := func() *cobra.Command {
+		root := &cobra.Command{Use: "gt", SilenceErrors: true, SilenceUsage: true}
+		leaf := &cobra.Command{
+			Use:  "leaf <name>",
+			Args: cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				switch args[0] {
+				case "ok":
+					return nil
+				case "missing":
+					return fmt.Errorf("looking up: %w", workspace.ErrNotFound)
+				case "some":
+					return partialFailure("1 of 2 target(s) failed")
+				case "quiet":
+					return NewSilentExit(errcode.ExitCommandStatus)
+				}
+				return errors.New("boom")
+			},
+		}
+		var n int
+		leaf.Flags().IntVar(&n, "n", 0, "")
+		root.AddCommand(leaf)
+		markUsageErrors(root)
+		return root
+	}
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"leaf", "ok"}, errcode.ExitOK},
+		{[]string{"leaf", "other"}, errcode.ExitFailure},
+		{[]string{"leaf"}, errcode.ExitUsage},
+		{[]string{"leaf", "ok", "--n", "x"}, errcode.ExitUsage},
+		{[]string{"leaf", "ok", "--bogus"}, errcode.ExitUsage},
+		{[]string{"nope"}, errcode.ExitUsage},
+		{[]string{"leaf", "missing"}, errcode.ExitNoTown},
+		{[]string{"leaf", "some"}, errcode.ExitPartial},
+		{[]string{"leaf", "quiet"}, errcode.ExitCommandStatus},
+	}
+	for _, tt := range tests {
+		root := newTree()
+		root.SetArgs(tt.args)
+		root.SetOut(io.Discard)
+		if got := exitStatus(root.Execute()); got != tt.want {
+			t.Errorf("gt %s: exit %d, want %d", strings.Join(tt.args, " "), got, tt.want)
+		}
+	}
+}
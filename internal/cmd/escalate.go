@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/ctiospl/gastown/internal/events"
 	"github.com/ctiospl/gastown/internal/mail"
+	"github.com/ctiospl/gastown/internal/notify"
 	"github.com/ctiospl/gastown/internal/style"
 	"github.com/ctiospl/gastown/internal/workspace"
 )
@@ -48,7 +49,8 @@ Severity levels:
 
 The escalation creates an audit trail bead and sends mail to the overseer
 with appropriate priority. All molecular algebra edge cases should escalate
-here rather than failing silently.
+here rather than failing silently. Escalations are also routed through the
+notification rules (see 'gt notify rules') as event type "escalation".
 
 Examples:
   gt escalate "Database migration failed"
@@ -162,6 +164,17 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	}
 	_ = events.LogFeed(events.TypeEscalationSent, agentID, payload)
 
+	// Route through notification rules (paging, quiet hours, escalation chains)
+	notifySeverity, _ := notify.ParseSeverity(severity)
+	deliveries := routeNotification(townRoot, &notify.Notification{
+		Event:    "escalation",
+		Rig:      rigFromAgentID(agentID),
+		Severity: notifySeverity,
+		Source:   agentID,
+		Subject:  topic,
+		Body:     body,
+	})
+
 	// Print confirmation with severity-appropriate styling
 	var emoji string
 	switch severity {
@@ -178,10 +191,23 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	if beadID != "" {
 		fmt.Printf("   Bead:  %s\n", beadID)
 	}
+	for _, d := range deliveries {
+		fmt.Printf("   Routed: %s → %s\n", d.Rule, d.Channel)
+	}
 
 	return nil
 }
 
+// rigFromAgentID extracts the rig from an agent address like "gastown/nux".
+// Town-level agents (mayor, deacon) have no rig.
+func rigFromAgentID(agentID string) string {
+	rig, _, ok := strings.Cut(agentID, "/")
+	if !ok || rig == "mayor" || rig == "deacon" {
+		return ""
+	}
+	return rig
+}
+
 // detectAgentIdentity returns the current agent's identity string.
 func detectAgentIdentity() (string, error) {
 	// Try GT_ROLE first
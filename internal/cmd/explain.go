@@ -0,0 +1,443 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/gtlog"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/transcript"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var explainWindow time.Duration
+
+var explainCmd = &cobra.Command{
+	Use:     "explain <event-id>",
+	GroupID: GroupDiag,
+	Short:   "Explain why a logged event happened",
+	Long: `Explain a town log event with its surrounding context.
+
+Given an event ID (shown by 'gt log --ids'), explain assembles:
+  - the event itself
+  - what triggered it: an explicit gt command, a daemon heartbeat step
+    (auto-nudge, restart, stale-agent cleanup), chaos mode, or the tmux
+    pane-died hook when the agent process exited on its own
+  - the previous and next events for the same agent
+  - daemon log lines around the event that mention the agent
+  - the agent's transcript position at the time of the event
+
+Correlation comes from the gt-internal log (logs/gt-internal.log) and the
+daemon log. Run the town with GT_DEBUG=1 to record daemon steps in detail.
+
+Use "last" as the ID to explain the most recent event.
+
+Examples:
+  gt log --ids --type kill      # Find the event
+  gt explain 3fa2c1d0           # Explain it ("why was this agent killed?")
+  gt explain 3fa2               # IDs may be abbreviated
+  gt explain last               # Explain the most recent event`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExplain,
+}
+
+func init() {
+	explainCmd.Flags().DurationVar(&explainWindow, "window", 10*time.Second, "How far before the event to look for its trigger")
+	rootCmd.AddCommand(explainCmd)
+}
+
+// eventTrigger is the best explanation for what caused an event.
+type eventTrigger struct {
+	Kind    string // command, daemon, chaos, hook, interrupt, unknown
+	Summary string
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	events, err := townlog.ReadEvents(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("town log is empty")
+	}
+
+	idx := len(events) - 1
+	if args[0] != "last" {
+		if idx, err = townlog.FindEvent(events, args[0]); err != nil {
+			return err
+		}
+	}
+	e := events[idx]
+
+	// Log timestamps have second resolution; widen the window by a second.
+	records, _ := gtlog.ReadRecords(townRoot, e.Timestamp.Add(-explainWindow), e.Timestamp.Add(2*time.Second))
+
+	fmt.Printf("%s %s\n", style.Bold.Render("Event"), e.ID())
+	fmt.Printf("  ")
+	printEvent(e)
+	if notes, _ := townlog.ReadAnnotations(townRoot); len(notes) > 0 {
+		printAnnotations(townlog.AttachAnnotations([]townlog.Event{e}, notes)[0])
+	}
+	fmt.Println()
+
+	trigger := explainTrigger(e, records)
+	fmt.Printf("%s %s\n", style.Bold.Render("Trigger:"), trigger.Summary)
+	if proc := correlatedProcess(e, records); proc != "" && trigger.Kind != "command" && trigger.Kind != "daemon" {
+		fmt.Printf("  %s\n", style.Dim.Render(proc))
+	}
+
+	if prev := relatedEvent(events, idx, -1); prev != nil {
+		fmt.Printf("\n%s %s before\n  ", style.Bold.Render("Previous:"), formatGap(e.Timestamp.Sub(prev.Timestamp)))
+		printEvent(*prev)
+	} else {
+		fmt.Printf("\n%s %s\n", style.Bold.Render("Previous:"), style.Dim.Render("(first event for this agent)"))
+	}
+	if next := relatedEvent(events, idx, +1); next != nil {
+		fmt.Printf("%s %s after\n  ", style.Bold.Render("Next:"), formatGap(next.Timestamp.Sub(e.Timestamp)))
+		printEvent(*next)
+	}
+
+	if lines := daemonLogContext(townRoot, e, explainWindow); len(lines) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Daemon log:"))
+		for _, line := range lines {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+
+	fmt.Printf("\n%s ", style.Bold.Render("Transcript:"))
+	if pos := transcriptPosition(townRoot, e.Agent, e.Timestamp); pos != "" {
+		fmt.Println(pos)
+	} else {
+		fmt.Println(style.Dim.Render("(no transcript found for this agent)"))
+	}
+	if e.Transcript != "" {
+		fmt.Printf("%s %s\n", style.Bold.Render("Archived transcript:"), filepath.Join(townRoot, filepath.FromSlash(e.Transcript)))
+	}
+	return nil
+}
+
+// explainTrigger classifies the cause of an event from its context and
+// the internal log. Rules run in order; the first match wins.
+func explainTrigger(e townlog.Event, records []gtlog.Record) eventTrigger {
+	ctx := e.Context
+
+	switch {
+	case ctx == "chaos injection":
+		return eventTrigger{"chaos", "daemon chaos mode (daemon.chaos in mayor/config.json)"}
+	case e.Type == townlog.EventKill && strings.HasPrefix(ctx, "interrupted"):
+		return eventTrigger{"interrupt", "Ctrl+C in the agent's tmux session"}
+	case strings.HasPrefix(ctx, "exit code") || ctx == "exited normally":
+		return eventTrigger{"hook", fmt.Sprintf("agent process exited on its own (%s); recorded by the tmux pane-died hook", ctx)}
+	case strings.HasPrefix(ctx, "gt "):
+		return eventTrigger{"command", fmt.Sprintf("command '%s'", ctx)}
+	}
+
+	if rec := triggeringCommand(e, records); rec != nil {
+		return eventTrigger{"command", fmt.Sprintf("command '%s' (pid %d)", rec.String("cmd"), rec.PID)}
+	}
+	if summary := triggeringHeartbeat(e, records); summary != "" {
+		return eventTrigger{"daemon", summary}
+	}
+	return eventTrigger{"unknown", style.Dim.Render("no correlated command or daemon step in logs/gt-internal.log")}
+}
+
+// triggeringCommand returns the CLI command that was running when the event
+// was logged: the latest "command start" before the event whose process had
+// not finished yet.
+func triggeringCommand(e townlog.Event, records []gtlog.Record) *gtlog.Record {
+	eventEnd := e.Timestamp.Add(time.Second)
+	finished := make(map[int]time.Time)
+	for _, r := range records {
+		if r.Proc == "cli" && r.Msg != "command start" && strings.HasPrefix(r.Msg, "command ") {
+			finished[r.PID] = r.Time
+		}
+	}
+	var best *gtlog.Record
+	for i := range records {
+		r := &records[i]
+		if r.Proc != "cli" || r.Msg != "command start" || r.Time.After(eventEnd) {
+			continue
+		}
+		if end, ok := finished[r.PID]; ok && end.Before(e.Timestamp) {
+			continue
+		}
+		if isDiagnosticCommand(r.String("cmd")) {
+			continue
+		}
+		best = r
+	}
+	return best
+}
+
+// isDiagnosticCommand reports whether cmd only reads town state, so it
+// can't have caused an event. "gt log crash" records events rather than
+// causing them.
+func isDiagnosticCommand(cmd string) bool {
+	for _, prefix := range []string{"gt log", "gt explain", "gt status", "gt daemon status", "gt daemon check"} {
+		if cmd == prefix || strings.HasPrefix(cmd, prefix+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// triggeringHeartbeat describes the daemon heartbeat (and step, when debug
+// records exist) in progress when the event was logged.
+func triggeringHeartbeat(e townlog.Event, records []gtlog.Record) string {
+	eventStart, eventEnd := e.Timestamp, e.Timestamp.Add(time.Second)
+	spans := func(r gtlog.Record) bool {
+		return !r.Time.Before(eventStart) && !r.Time.Add(-r.Duration("duration")).After(eventEnd)
+	}
+
+	var step, beat string
+	for _, r := range records {
+		if r.Proc != "daemon" || !spans(r) {
+			continue
+		}
+		switch r.Msg {
+		case "heartbeat step":
+			if step == "" {
+				step = r.String("step")
+			}
+		case "daemon heartbeat":
+			if beat == "" {
+				beat = fmt.Sprintf("daemon heartbeat #%v (pid %d)", r.Attrs["n"], r.PID)
+			}
+		}
+	}
+	switch {
+	case step != "" && beat != "":
+		return fmt.Sprintf("%s, step %q%s", beat, step, stepHint(step, e.Type))
+	case step != "":
+		return fmt.Sprintf("daemon heartbeat step %q%s", step, stepHint(step, e.Type))
+	default:
+		return beat
+	}
+}
+
+// stepHint explains what a heartbeat step does to agents.
+func stepHint(step string, eventType townlog.EventType) string {
+	switch step {
+	case "pending-spawns":
+		if eventType == townlog.EventNudge {
+			return " — auto-nudge of a freshly spawned polecat"
+		}
+	case "deacon-heartbeat", "boot":
+		return " — deacon liveness check"
+	case "witnesses", "refineries":
+		return " — restart of a dead rig agent"
+	case "stale-agents", "polecat-health":
+		return " — cleanup of an agent that stopped responding"
+	case "lifecycle":
+		return " — agent-requested cycle/restart/shutdown"
+	case "chaos":
+		return " — chaos mode fault injection"
+	}
+	return ""
+}
+
+// correlatedProcess summarizes any gt process active at the event time, for
+// triggers that were classified from the event context alone.
+func correlatedProcess(e townlog.Event, records []gtlog.Record) string {
+	if rec := triggeringCommand(e, records); rec != nil {
+		return fmt.Sprintf("during command '%s' (pid %d)", rec.String("cmd"), rec.PID)
+	}
+	if summary := triggeringHeartbeat(e, records); summary != "" {
+		return "during " + summary
+	}
+	return ""
+}
+
+// relatedEvent returns the nearest event for the same agent in direction dir.
+func relatedEvent(events []townlog.Event, idx, dir int) *townlog.Event {
+	for i := idx + dir; i >= 0 && i < len(events); i += dir {
+		if events[i].Agent == events[idx].Agent {
+			return &events[i]
+		}
+	}
+	return nil
+}
+
+// formatGap renders a duration between two events.
+func formatGap(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%.1fh", d.Hours())
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// daemonLogContext returns daemon log lines within window before (and a
+// few seconds after) the event that mention the agent.
+func daemonLogContext(townRoot string, e townlog.Event, window time.Duration) []string {
+	f, err := os.Open(filepath.Join(townRoot, "daemon", "daemon.log"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	needles := []string{e.Agent}
+	if name := filepath.Base(e.Agent); len(name) >= 3 && name != e.Agent {
+		needles = append(needles, name)
+	}
+
+	from, to := e.Timestamp.Add(-window), e.Timestamp.Add(5*time.Second)
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) < 19 {
+			continue
+		}
+		ts, err := time.ParseInLocation("2006/01/02 15:04:05", line[:19], time.Local)
+		if err != nil || ts.Before(from) || ts.After(to) {
+			continue
+		}
+		for _, n := range needles {
+			if strings.Contains(line, n) {
+				lines = append(lines, line)
+				break
+			}
+		}
+	}
+	const maxLines = 10
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+	return lines
+}
+
+// agentWorkDir maps an agent address to its working directory.
+func agentWorkDir(townRoot, agent string) string {
+	parts := strings.Split(strings.TrimSuffix(agent, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "mayor":
+		return townRoot
+	case len(parts) == 1:
+		return filepath.Join(townRoot, parts[0])
+	case len(parts) == 2 && (parts[1] == "witness" || parts[1] == "refinery"):
+		return filepath.Join(townRoot, parts[0], parts[1], "rig")
+	case len(parts) == 2:
+		return filepath.Join(townRoot, parts[0], "polecats", parts[1])
+	default:
+		return filepath.Join(append([]string{townRoot}, parts...)...)
+	}
+}
+
+var nonPathChars = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// transcriptFiles returns the agent's Claude transcripts.
+func transcriptFiles(townRoot, agent string) []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	// Claude stores transcripts per working directory, with path separators
+	// and dots replaced by dashes.
+	projectDir := filepath.Join(home, ".claude", "projects", nonPathChars.ReplaceAllString(agentWorkDir(townRoot, agent), "-"))
+	files, _ := filepath.Glob(filepath.Join(projectDir, "*.jsonl"))
+	return files
+}
+
+// latestTranscript returns the agent's most recently written transcript,
+// or "".
+func latestTranscript(townRoot, agent string) string {
+	var latest string
+	var latestTime time.Time
+	for _, path := range transcriptFiles(townRoot, agent) {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(latestTime) {
+			latest, latestTime = path, info.ModTime()
+		}
+	}
+	return latest
+}
+
+// transcriptPosition locates the agent's Claude transcript entry closest to
+// (at or before) t, returning "path:line (timestamp)" or "".
+func transcriptPosition(townRoot, agent string, t time.Time) string {
+	files := transcriptFiles(townRoot, agent)
+
+	var bestPath string
+	var bestLine int
+	var bestTime time.Time
+	for _, path := range files {
+		if info, err := os.Stat(path); err != nil || info.ModTime().Before(t.Add(-24*time.Hour)) {
+			continue // transcript ended well before the event
+		}
+		line, ts := transcriptLineAt(path, t)
+		if line > 0 && ts.After(bestTime) {
+			bestPath, bestLine, bestTime = path, line, ts
+		}
+	}
+	if bestPath == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d %s", bestPath, bestLine,
+		style.Dim.Render(fmt.Sprintf("(entry at %s)", bestTime.Local().Format("15:04:05"))))
+}
+
+// transcriptLineAt returns the 1-based line number and timestamp of the last
+// transcript entry at or before t.
+func transcriptLineAt(path string, t time.Time) (int, time.Time) {
+	f, err := os.Open(path) //nolint:gosec // G304: transcript path from the agent's project dir
+	if err != nil {
+		return 0, time.Time{}
+	}
+	defer f.Close()
+
+	limit := t.Add(time.Second)
+	var line, bestLine int
+	var bestTime time.Time
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line++
+		var entry struct {
+			Timestamp time.Time `json:"timestamp"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil || entry.Timestamp.IsZero() {
+			continue
+		}
+		if entry.Timestamp.After(limit) {
+			break
+		}
+		bestLine, bestTime = line, entry.Timestamp
+	}
+	return bestLine, bestTime
+}
+
+// archiveTranscript copies the agent's current Claude transcript into the
+// town's transcript archive (see the transcript package) and returns its
+// path relative to the town root, for the done or handoff event to link
+// to. It returns "" if the agent has no transcript or archiving failed.
+func archiveTranscript(townRoot, agent, reason string) string {
+	src := latestTranscript(townRoot, agent)
+	if src == "" {
+		return ""
+	}
+	path, err := transcript.Archive(townRoot, agent, src, strings.TrimSpace(reason), time.Now())
+	if err != nil {
+		style.PrintWarning("could not archive transcript: %v", err)
+		return ""
+	}
+	fmt.Printf("%s Transcript archived to %s\n", style.Bold.Render("✓"), path)
+	return path
+}
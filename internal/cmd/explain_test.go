@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/gtlog"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+func TestExplainTrigger(t *testing.T) {
+	at := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	kill := townlog.Event{Timestamp: at, Type: townlog.EventKill, Agent: "gastown/polecats/Toast"}
+
+	chaos := kill
+	chaos.Context = "chaos injection"
+	if got := explainTrigger(chaos, nil); got.Kind != "chaos" {
+		t.Errorf("chaos kill: got %+v", got)
+	}
+
+	records := []gtlog.Record{
+		{Time: at.Add(-3 * time.Second), Msg: "command start", Proc: "cli", PID: 10, Attrs: map[string]interface{}{"cmd": "gt status"}},
+		{Time: at.Add(-2 * time.Second), Msg: "command start", Proc: "cli", PID: 11, Attrs: map[string]interface{}{"cmd": "gt polecat nuke"}},
+	}
+	if got := explainTrigger(kill, records); got.Kind != "command" || !strings.Contains(got.Summary, "gt polecat nuke") {
+		t.Errorf("command kill: got %+v", got)
+	}
+
+	beat := []gtlog.Record{
+		{Time: at.Add(500 * time.Millisecond), Msg: "heartbeat step", Proc: "daemon", PID: 5,
+			Attrs: map[string]interface{}{"step": "stale-agents", "duration": float64(2 * time.Second)}},
+	}
+	if got := explainTrigger(kill, beat); got.Kind != "daemon" || !strings.Contains(got.Summary, "stale-agents") {
+		t.Errorf("daemon kill: got %+v", got)
+	}
+
+	if got := explainTrigger(kill, nil); got.Kind != "unknown" {
+		t.Errorf("uncorrelated kill: got %+v", got)
+	}
+}
+
+func TestAgentWorkDir(t *testing.T) {
+	tests := map[string]string{
+		"mayor":                  "/town",
+		"deacon":                 "/town/deacon",
+		"gastown/witness":        "/town/gastown/witness/rig",
+		"gastown/Toast":          "/town/gastown/polecats/Toast",
+		"gastown/crew/max":       "/town/gastown/crew/max",
+		"gastown/polecats/Toast": "/town/gastown/polecats/Toast",
+	}
+	for agent, want := range tests {
+		if got := agentWorkDir("/town", agent); got != want {
+			t.Errorf("agentWorkDir(%q) = %q, want %q", agent, got, want)
+		}
+	}
+}
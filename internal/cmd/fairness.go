@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/fairness"
+	"github.com/ctiospl/gastown/internal/git"
+	"github.com/ctiospl/gastown/internal/polecat"
+	"github.com/ctiospl/gastown/internal/rig"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	fairnessSince string
+	fairnessBy    string
+	fairnessJSON  bool
+)
+
+var fairnessCmd = &cobra.Command{
+	Use:     "fairness",
+	GroupID: GroupDiag,
+	Short:   "Show each rig's share of polecat capacity",
+	Long: `Show how polecat capacity is shared between rigs: each rig's weight,
+guaranteed slots, and quota, and the share of polecat time it actually
+used over time.
+
+Fairness is configured in settings/config.json:
+
+  "fairness": {
+    "max_polecats": 6,
+    "window": "24h",
+    "rigs": {
+      "gastown": {"weight": 2},
+      "beads":   {"weight": 1, "quota": "20h"}
+    }
+  }
+
+With max_polecats set, no more than that many polecats run across the
+town, and each rig is guaranteed its weighted share of the slots (here 4
+and 2). A rig may borrow the slots of rigs that ran no polecats during
+the window, but not slots owed to a rig that is working. A quota caps the polecat time a rig may use per
+window. Spawns that would break either rule fail their preflight check.
+Rigs not listed have weight 1 and no quota.
+
+Polecat time is measured from the town log: a polecat runs from its spawn
+or wake until its done, crash, or kill.
+
+Examples:
+  gt fairness                   # Shares per day over the last week
+  gt fairness --since 24h --by 1h
+  gt fairness --json`,
+	Args: cobra.NoArgs,
+	RunE: runFairness,
+}
+
+func init() {
+	fairnessCmd.Flags().StringVar(&fairnessSince, "since", "7d", "Start of the report: duration, time, or mark")
+	fairnessCmd.Flags().StringVar(&fairnessBy, "by", "1d", "Length of each report period (e.g. 1h, 1d)")
+	fairnessCmd.Flags().BoolVar(&fairnessJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(fairnessCmd)
+}
+
+// fairnessState is what the fairness policy is enforced against.
+type fairnessState struct {
+	Policy     *fairness.Policy
+	Configured bool // false if the town has no fairness policy, so Policy is the default
+	Rigs       []string
+	Running    map[string]int           // polecats per rig
+	Used       map[string]time.Duration // polecat time per rig in the quota window
+}
+
+// loadFairnessState loads the town's fairness policy and the current
+// polecats and quota usage of every rig. A town without a policy gets one
+// that weighs every rig equally and limits nothing.
+func loadFairnessState(townRoot string) (*fairnessState, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	policy, err := fairness.FromConfig(settings.Fairness)
+	if err != nil {
+		return nil, err
+	}
+	s := &fairnessState{Policy: policy, Configured: policy != nil, Running: make(map[string]int)}
+	if policy == nil {
+		s.Policy = &fairness.Policy{Window: fairness.DefaultWindow}
+	}
+	if rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json")); err == nil {
+		for name := range rigsConfig.Rigs {
+			s.Rigs = append(s.Rigs, name)
+		}
+	}
+	sort.Strings(s.Rigs)
+	for _, name := range s.Rigs {
+		r := &rig.Rig{Name: name, Path: filepath.Join(townRoot, name)}
+		if polecats, err := polecat.NewManager(r, git.NewGit(r.Path)).List(); err == nil {
+			s.Running[name] = len(polecats)
+		}
+	}
+
+	events, err := townlog.ReadEvents(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading town log: %w", err)
+	}
+	now := time.Now()
+	s.Used = fairness.Usage(events, now.Add(-s.Policy.Window), now)
+	return s, nil
+}
+
+// fairnessReport is the output of gt fairness.
+type fairnessReport struct {
+	Configured  bool               `json:"configured"`
+	MaxPolecats int                `json:"max_polecats,omitempty"`
+	Window      string             `json:"window"`
+	Rigs        []fairnessRig      `json:"rigs"`
+	Periods     []fairness.Period  `json:"periods"`
+	Share       map[string]float64 `json:"share"` // over the whole report
+}
+
+type fairnessRig struct {
+	Name       string  `json:"name"`
+	Weight     float64 `json:"weight"`
+	Target     float64 `json:"target"`               // weighted fraction of capacity
+	Guaranteed int     `json:"guaranteed,omitempty"` // slots of max_polecats
+	Running    int     `json:"running"`
+	Used       string  `json:"used"`            // polecat time in the quota window
+	Quota      string  `json:"quota,omitempty"` // polecat time allowed per window
+}
+
+func runFairness(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	from, err := resolveTimeBoundary(townRoot, "since", fairnessSince)
+	if err != nil {
+		return err
+	}
+	step, err := parseDuration(fairnessBy)
+	if err != nil || step <= 0 {
+		return fmt.Errorf("invalid --by %q: use a duration such as 1h or 1d", fairnessBy)
+	}
+	now := time.Now()
+	if now.Sub(from)/step > 400 {
+		return fmt.Errorf("--by %s splits the report into too many periods; use a longer one", fairnessBy)
+	}
+
+	state, err := loadFairnessState(townRoot)
+	if err != nil {
+		return err
+	}
+	events, err := townlog.ReadArchivedEvents(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading town log: %w", err)
+	}
+
+	report := fairnessReport{
+		Configured:  state.Configured,
+		MaxPolecats: state.Policy.MaxPolecats,
+		Window:      fairness.FormatHours(state.Policy.Window),
+		Periods:     fairness.Report(events, from, now, step),
+		Share:       fairness.Shares(fairness.Usage(events, from, now)),
+	}
+	rigs := append([]string{}, state.Rigs...)
+	for name := range report.Share {
+		if !slices.Contains(rigs, name) {
+			rigs = append(rigs, name)
+		}
+	}
+	sort.Strings(rigs)
+	targets := state.Policy.Targets(rigs)
+	guaranteed := state.Policy.Guaranteed(rigs)
+	for _, name := range rigs {
+		fr := fairnessRig{
+			Name:       name,
+			Weight:     state.Policy.Weight(name),
+			Target:     targets[name],
+			Guaranteed: guaranteed[name],
+			Running:    state.Running[name],
+			Used:       fairness.FormatHours(state.Used[name]),
+		}
+		if q, ok := state.Policy.Quotas[name]; ok {
+			fr.Quota = fairness.FormatHours(q)
+		}
+		report.Rigs = append(report.Rigs, fr)
+	}
+
+	if fairnessJSON {
+		return outputJSON(report)
+	}
+	printFairnessReport(report, step)
+	return nil
+}
+
+func printFairnessReport(report fairnessReport, step time.Duration) {
+	if len(report.Rigs) == 0 {
+		fmt.Printf("%s No rigs\n", style.Dim.Render("○"))
+		return
+	}
+	limit := "no town-wide polecat limit"
+	if !report.Configured {
+		limit = "not configured (see gt fairness --help)"
+	} else if report.MaxPolecats > 0 {
+		limit = fmt.Sprintf("max %d polecats", report.MaxPolecats)
+	}
+	fmt.Printf("%s %s, quotas per %s\n\n", style.Bold.Render("Fairness:"), limit, report.Window)
+
+	fmt.Printf("%-16s %6s %7s %6s %8s %9s %7s\n", "Rig", "Weight", "Target", "Slots", "Polecats", "Used", "Quota")
+	fmt.Println(strings.Repeat("─", 65))
+	for _, r := range report.Rigs {
+		slots, quota := "-", "-"
+		if report.MaxPolecats > 0 {
+			slots = fmt.Sprintf("%d", r.Guaranteed)
+		}
+		if r.Quota != "" {
+			quota = r.Quota
+		}
+		fmt.Printf("%-16s %6g %6.0f%% %6s %8d %9s %7s\n", r.Name, r.Weight, r.Target*100, slots, r.Running, r.Used, quota)
+	}
+
+	layout := "01-02"
+	if step < 24*time.Hour {
+		layout = "01-02 15:04"
+	}
+	fmt.Printf("\n%s\n", style.Bold.Render("Actual share of polecat time"))
+	fmt.Printf("%-16s", "Rig")
+	for _, p := range report.Periods {
+		fmt.Printf(" %*s", len(layout), p.Start.Local().Format(layout))
+	}
+	fmt.Printf(" %*s\n", len(layout), "Total")
+	for _, r := range report.Rigs {
+		fmt.Printf("%-16s", r.Name)
+		for _, p := range report.Periods {
+			fmt.Printf(" %*s", len(layout), shareText(p.Share, r.Name))
+		}
+		fmt.Printf(" %*s\n", len(layout), shareText(report.Share, r.Name))
+	}
+}
+
+// shareText renders a rig's share of a period, or "-" if the period had
+// no polecat time at all.
+func shareText(shares map[string]float64, rig string) string {
+	if len(shares) == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", shares[rig]*100)
+}
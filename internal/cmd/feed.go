@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/focus"
+	"github.com/ctiospl/gastown/internal/style"
 	"github.com/ctiospl/gastown/internal/tmux"
 	"github.com/ctiospl/gastown/internal/tui/feed"
 	"github.com/ctiospl/gastown/internal/workspace"
@@ -52,6 +55,9 @@ By default, launches an interactive TUI dashboard with:
   - Convoy panel (middle): Shows in-progress and recently landed convoys
   - Event stream (bottom): Chronological feed you can scroll through
   - Vim-style navigation: j/k to scroll, tab to switch panels, 1/2/3 for panels, q to quit
+  - Command palette: press : (or ctrl+p) and type a few letters to spawn,
+    nudge or kill the agent selected in the tree (j/k), open its transcript,
+    or filter the view; / or f filters directly, esc clears the filter
 
 The feed combines multiple event sources:
   - Beads activity: Issue creates, updates, completions (from bd activity)
@@ -103,6 +109,13 @@ func runFeed(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting current directory: %w", err)
 	}
 
+	// From the town root, default to the focused rig
+	if feedRig == "" && filepath.Clean(workDir) == filepath.Clean(townRoot) {
+		if f := focus.Current(townRoot); f != nil {
+			feedRig = f.Rig
+		}
+	}
+
 	// If --rig specified, find that rig's beads directory
 	if feedRig != "" {
 		// Try common beads locations for the rig
@@ -133,8 +146,9 @@ func runFeed(cmd *cobra.Command, args []string) error {
 		return runFeedInWindow(workDir, bdArgs)
 	}
 
-	// Use TUI by default if running in a terminal and not --plain
-	useTUI := !feedPlain && term.IsTerminal(int(os.Stdout.Fd()))
+	// Use TUI by default if running in a terminal and not --plain or
+	// accessible output
+	useTUI := !feedPlain && !style.Accessible() && term.IsTerminal(int(os.Stdout.Fd()))
 
 	if useTUI {
 		return runFeedTUI(workDir)
@@ -232,6 +246,10 @@ func runFeedTUI(workDir string) error {
 	m := feed.NewModel()
 	m.SetEventChannel(multiSource.Events())
 	m.SetTownRoot(townRoot)
+	m.SetTranscriptFinder(func(agent string) string { return latestTranscript(townRoot, agent) })
+	if f := focus.Current(townRoot); f != nil {
+		m.SetFocus(f.Rig, f.Convoy)
+	}
 
 	// Run the TUI
 	p := tea.NewProgram(m, tea.WithAltScreen())
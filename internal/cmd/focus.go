@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/focus"
+	"github.com/ctiospl/gastown/internal/notify"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	focusRig    string
+	focusConvoy string
+)
+
+var focusCmd = &cobra.Command{
+	Use:     "focus [rig|convoy-id]",
+	GroupID: GroupWorkspace,
+	Short:   "Pin a rig or convoy as the default scope",
+	Long: `Pin a rig or convoy so you can work on it without repeating yourself.
+
+While focused:
+  - Commands that infer a rig from the current directory default to the
+    focused rig when run from outside any rig
+  - gt convoy status with no ID shows the focused convoy
+  - gt feed shows only the focused rig and convoy
+  - Notifications from other rigs are held and delivered as one digest
+    every 30 minutes instead of one by one (critical and high severity
+    notifications are always delivered immediately)
+
+A bare argument is taken as a rig name if one exists, otherwise as a
+convoy ID. Run with no arguments to show the current focus.
+
+Examples:
+  gt focus                        # Show current focus
+  gt focus gastown                # Focus on the gastown rig
+  gt focus hq-cv-abc              # Focus on a convoy
+  gt focus --rig gastown --convoy hq-cv-abc
+  gt focus clear                  # Exit focus and deliver held notifications`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFocus,
+}
+
+var focusClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Exit focus mode",
+	Long: `Exit focus mode and deliver any notifications held while focused.`,
+	Args: cobra.NoArgs,
+	RunE: runFocusClear,
+}
+
+func init() {
+	focusCmd.Flags().StringVar(&focusRig, "rig", "", "Rig to focus on")
+	focusCmd.Flags().StringVar(&focusConvoy, "convoy", "", "Convoy to focus on")
+
+	focusCmd.AddCommand(focusClearCmd)
+	rootCmd.AddCommand(focusCmd)
+}
+
+func runFocus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	f := &focus.Focus{Rig: focusRig, Convoy: focusConvoy}
+	if len(args) > 0 {
+		if isKnownRig(townRoot, args[0]) {
+			f.Rig = args[0]
+		} else {
+			f.Convoy = args[0]
+		}
+	}
+
+	if f.Rig == "" && f.Convoy == "" {
+		return showFocus(townRoot)
+	}
+	if f.Rig != "" && !isKnownRig(townRoot, f.Rig) {
+		return fmt.Errorf("rig '%s' not found", f.Rig)
+	}
+
+	if err := focus.Save(townRoot, f); err != nil {
+		return err
+	}
+	fmt.Printf("%s Focused on %s\n", style.Success.Render("✓"), f)
+	fmt.Printf("  %s\n", style.Dim.Render("Notifications from other rigs will arrive as a digest. Exit with: gt focus clear"))
+	return nil
+}
+
+func showFocus(townRoot string) error {
+	f, err := focus.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	if f == nil {
+		fmt.Println("No focus set")
+		return nil
+	}
+	fmt.Printf("Focused on %s %s\n", style.Bold.Render(f.String()),
+		style.Dim.Render(fmt.Sprintf("(since %s ago)", formatDuration(time.Since(f.Since)))))
+	return nil
+}
+
+func runFocusClear(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	f, err := focus.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	if f == nil {
+		fmt.Println("No focus set")
+		return nil
+	}
+
+	// Deliver what was held before dropping the focus, so nothing is lost
+	// if the router cannot be loaded.
+	router, err := notify.NewRouter(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading notification router: %w", err)
+	}
+	deliveries, err := router.FlushBatched(true)
+	if err != nil {
+		return fmt.Errorf("delivering held notifications: %w", err)
+	}
+
+	if err := focus.Clear(townRoot); err != nil {
+		return err
+	}
+	fmt.Printf("%s Cleared focus on %s\n", style.Success.Render("✓"), f)
+	if len(deliveries) > 0 {
+		var channels []string
+		for _, d := range deliveries {
+			channels = append(channels, d.Channel)
+		}
+		fmt.Printf("  Delivered held notifications to: %s\n", strings.Join(channels, ", "))
+	}
+	return nil
+}
+
+// isKnownRig reports whether name is a rig in the town.
+func isKnownRig(townRoot, name string) bool {
+	for _, r := range discoverRigs(townRoot) {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
@@ -84,6 +84,9 @@ const HQGitignore = `# Gas Town HQ .gitignore
 # =============================================================================
 **/.runtime/
 
+# Artifact store (mail attachments, content-addressed blobs)
+.artifacts/
+
 # =============================================================================
 # Rig .beads symlinks (point to ignored mayor/rig/.beads, recreated on setup)
 # =============================================================================
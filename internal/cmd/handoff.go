@@ -6,10 +6,13 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/errcode"
 	"github.com/ctiospl/gastown/internal/events"
+	"github.com/ctiospl/gastown/internal/handoff"
 	"github.com/ctiospl/gastown/internal/session"
 	"github.com/ctiospl/gastown/internal/style"
 	"github.com/ctiospl/gastown/internal/tmux"
@@ -37,13 +40,26 @@ Examples:
   gt handoff gt-abc -s "Fix it"       # Hook with context, then restart
   gt handoff -s "Context" -m "Notes"  # Hand off with custom message
   gt handoff -c                       # Collect state into handoff message
+  gt handoff --summarize              # Start the next session from a summary
   gt handoff crew                     # Hand off crew session
   gt handoff mayor                    # Hand off mayor session
+  gt handoff crew --dry-run           # Show what the handoff would do
 
 The --collect (-c) flag gathers current state (hooked work, inbox, ready beads,
 in-progress items) and includes it in the handoff mail. This provides context
 for the next session without manual summarization.
 
+The --summarize flag condenses the session for its successor: recent
+commits, uncommitted changes, hooked and in-progress beads, and the notes,
+marks, and nudges the town log recorded for the agent since the session
+began. The next session's gt prime shows the summary once, so the fresh
+agent starts with it in context instead of cold.
+
+The --dry-run (-n) flag prints what the handoff would do and does none of
+it: the session and its worktree, the pane that would be respawned, the
+environment and command it would be respawned with, and the startup
+prompt. No mail is sent, nothing is hooked, and no handoff is logged.
+
 Any molecule on the hook will be auto-continued by the new session.
 The SessionStart hook runs 'gt prime' to restore context.`,
 	RunE: runHandoff,
@@ -55,6 +71,8 @@ var (
 	handoffSubject string
 	handoffMessage string
 	handoffCollect bool
+	handoffSummary bool
+	handoffUsage   usageFlags
 )
 
 func init() {
@@ -63,6 +81,8 @@ func init() {
 	handoffCmd.Flags().StringVarP(&handoffSubject, "subject", "s", "", "Subject for handoff mail (optional)")
 	handoffCmd.Flags().StringVarP(&handoffMessage, "message", "m", "", "Message body for handoff mail (optional)")
 	handoffCmd.Flags().BoolVarP(&handoffCollect, "collect", "c", false, "Auto-collect state (status, inbox, beads) into handoff message")
+	handoffCmd.Flags().BoolVar(&handoffSummary, "summarize", false, "Hand the next session a summary of this one (commits, open tasks, decisions)")
+	addUsageFlags(handoffCmd, &handoffUsage)
 	rootCmd.AddCommand(handoffCmd)
 }
 
@@ -74,6 +94,10 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 			style.Bold.Render("🐾"), polecatName)
 		// Polecats don't respawn themselves - Witness handles lifecycle
 		// Call gt done with DEFERRED exit type to preserve work state
+		if handoffDryRun {
+			fmt.Println("Would run: gt done --exit DEFERRED")
+			return nil
+		}
 		doneCmd := exec.Command("gt", "done", "--exit", "DEFERRED")
 		doneCmd.Stdout = os.Stdout
 		doneCmd.Stderr = os.Stderr
@@ -141,11 +165,26 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Summarize before the handoff is logged: it marks the end of the session
+	if handoffSummary {
+		summarizeForHandoff(targetSession)
+	}
+
 	// If handing off a different session, we need to find its pane and respawn there
 	if targetSession != currentSession {
 		return handoffRemoteSession(t, targetSession, restartCmd)
 	}
 
+	// Dry run mode - show what would happen (BEFORE any side effects)
+	if handoffDryRun {
+		plan := handoffPlan(currentSession, pane, restartCmd)
+		if handoffSubject != "" || handoffMessage != "" {
+			plan.Details = append(plan.Details, [2]string{"Mail", fmt.Sprintf("%q to self (auto-hooked)", handoffSubject)})
+		}
+		fmt.Print(plan)
+		return nil
+	}
+
 	// Handing off ourselves - print feedback then respawn
 	fmt.Printf("%s Handing off %s...\n", style.Bold.Render("🤝"), currentSession)
 
@@ -155,21 +194,13 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		if agent == "" {
 			agent = currentSession
 		}
-		_ = LogHandoff(townRoot, agent, handoffSubject)
+		cwd, _ := os.Getwd()
+		archived := archiveTranscript(townRoot, agent, "handoff")
+		_ = LogHandoff(townRoot, agent, handoffSubject, handoffUsage.usage(cwd), archived)
 		// Also log to activity feed
 		_ = events.LogFeed(events.TypeHandoff, agent, events.HandoffPayload(handoffSubject, true))
 	}
 
-	// Dry run mode - show what would happen (BEFORE any side effects)
-	if handoffDryRun {
-		if handoffSubject != "" || handoffMessage != "" {
-			fmt.Printf("Would send handoff mail: subject=%q (auto-hooked)\n", handoffSubject)
-		}
-		fmt.Printf("Would execute: tmux clear-history -t %s\n", pane)
-		fmt.Printf("Would execute: tmux respawn-pane -k -t %s %s\n", pane, restartCmd)
-		return nil
-	}
-
 	// If subject/message provided, send handoff mail to self first
 	// The mail is auto-hooked so the next session picks it up
 	if handoffSubject != "" || handoffMessage != "" {
@@ -206,6 +237,52 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 	return t.RespawnPane(pane, restartCmd)
 }
 
+// handoffPlan returns what handing off a session would do (--dry-run):
+// respawning its pane with restartCmd.
+func handoffPlan(sessionName, pane, restartCmd string) dryRunPlan {
+	plan := dryRunPlan{
+		Action:  "Would hand off " + sessionName,
+		Session: sessionName,
+		Details: [][2]string{{"Pane", pane + " (history cleared, then respawned)"}},
+		Command: restartCmd,
+		Prompt:  "gt prime",
+	}
+	if townRoot := detectTownRootFromCwd(); townRoot != "" {
+		plan.Worktree, _ = sessionWorkDir(sessionName, townRoot)
+	}
+	if role := sessionToGTRole(sessionName); role != "" {
+		plan.Env = map[string]string{"GT_ROLE": role, "BD_ACTOR": role, "GIT_AUTHOR_NAME": role}
+	}
+	return plan
+}
+
+// summarizeForHandoff saves a summary of the session for the agent's next
+// session to find at gt prime. Failing to is not fatal to the handoff.
+func summarizeForHandoff(sessionName string) {
+	townRoot := detectTownRootFromCwd()
+	id, err := session.ParseSessionName(sessionName)
+	if err != nil || townRoot == "" {
+		style.PrintWarning("cannot summarize %s: not a Gas Town agent session", sessionName)
+		return
+	}
+	workDir, err := sessionWorkDir(sessionName, townRoot)
+	if err != nil {
+		workDir, _ = os.Getwd()
+	}
+	summary := handoff.Build(townRoot, workDir, id.Address(), handoffMessage, time.Now())
+
+	if handoffDryRun {
+		fmt.Printf("Would hand the next session this summary:\n\n%s\n", summary.Markdown(time.Now()))
+		return
+	}
+	if err := handoff.Save(townRoot, summary); err != nil {
+		style.PrintWarning("could not save handoff summary: %v", err)
+		return
+	}
+	fmt.Printf("%s Summarized for the next session: %d commit(s), %d changed file(s), %d task(s), %d note(s)\n",
+		style.Bold.Render("🧭"), len(summary.Commits), len(summary.Uncommitted), len(summary.Tasks), len(summary.Decisions))
+}
+
 // getCurrentTmuxSession returns the current tmux session name.
 func getCurrentTmuxSession() (string, error) {
 	out, err := exec.Command("tmux", "display-message", "-p", "#{session_name}").Output()
@@ -447,7 +524,7 @@ func handoffRemoteSession(t *tmux.Tmux, targetSession, restartCmd string) error
 		return fmt.Errorf("checking session: %w", err)
 	}
 	if !exists {
-		return fmt.Errorf("session '%s' not found - is the agent running?", targetSession)
+		return errcode.New(errcode.SessionDead, "session '%s' not found - is the agent running?", targetSession)
 	}
 
 	// Get the pane ID for the target session
@@ -456,18 +533,18 @@ func handoffRemoteSession(t *tmux.Tmux, targetSession, restartCmd string) error
 		return fmt.Errorf("getting target pane: %w", err)
 	}
 
-	fmt.Printf("%s Handing off %s...\n", style.Bold.Render("🤝"), targetSession)
-
 	// Dry run mode
 	if handoffDryRun {
-		fmt.Printf("Would execute: tmux clear-history -t %s\n", targetPane)
-		fmt.Printf("Would execute: tmux respawn-pane -k -t %s %s\n", targetPane, restartCmd)
+		plan := handoffPlan(targetSession, targetPane, restartCmd)
 		if handoffWatch {
-			fmt.Printf("Would execute: tmux switch-client -t %s\n", targetSession)
+			plan.Details = append(plan.Details, [2]string{"Watch", "tmux switch-client -t " + targetSession})
 		}
+		fmt.Print(plan)
 		return nil
 	}
 
+	fmt.Printf("%s Handing off %s...\n", style.Bold.Render("🤝"), targetSession)
+
 	// Clear scrollback history before respawn (resets copy-mode from [0/N] to [0/0])
 	if err := t.ClearHistory(targetPane); err != nil {
 		// Non-fatal - continue with respawn even if clear fails
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/health"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	healthStale     string
+	healthUnhealthy bool
+	healthJSON      bool
+)
+
+var healthCmd = &cobra.Command{
+	Use:     "health",
+	GroupID: GroupDiag,
+	Short:   "Probe running agents for liveness",
+	Long: `Probe every agent's tmux session and report whether the agent is alive.
+
+Each agent is one of:
+  healthy   running, and printed output recently
+  stale     running, but silent for longer than --stale
+  exited    the agent process exited, leaving its shell in the pane
+  dead      the pane itself is dead
+  paused    frozen by 'gt pause' (not counted as unhealthy)
+
+The daemon runs the same probe every heartbeat. Whichever probes first
+logs each change in the town log as a "health" event: a warning when an
+agent goes unhealthy, and info when it recovers. An agent stays
+unhealthy, and is not logged again, until it recovers. Find them with:
+
+  gt log --type health
+
+The stale threshold defaults to the daemon's "health_stale_after" in
+mayor/config.json, or 30m. Agents waiting at a prompt print nothing
+either, so idle crew may show as stale; "0" turns staleness off.
+
+Exits with status 1 if any agent is unhealthy.
+
+Examples:
+  gt health
+  gt health --unhealthy           # Only agents needing attention
+  gt health --stale 2h --json`,
+	Args: cobra.NoArgs,
+	RunE: runHealth,
+}
+
+func init() {
+	healthCmd.Flags().StringVar(&healthStale, "stale", "", "Report agents silent this long as stale (default: daemon setting or 30m)")
+	healthCmd.Flags().BoolVar(&healthUnhealthy, "unhealthy", false, "Only show unhealthy agents")
+	healthCmd.Flags().BoolVar(&healthJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(healthCmd)
+}
+
+func runHealth(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	staleAfter := health.DefaultStaleAfter
+	setting := healthStale
+	if setting == "" {
+		if mc, err := config.LoadMayorConfig(constants.MayorConfigPath(townRoot)); err == nil && mc.Daemon != nil {
+			setting = mc.Daemon.HealthStaleAfter
+		}
+	}
+	if setting != "" {
+		if staleAfter, err = time.ParseDuration(setting); err != nil {
+			return fmt.Errorf("invalid stale threshold %q: %w", setting, err)
+		}
+	}
+	cmd.SilenceUsage = true
+
+	now := time.Now()
+	paused, _ := session.LoadPauses(townRoot)
+	probes, err := health.Check(tmux.NewTmux(), now, staleAfter, paused)
+	if err != nil {
+		return err
+	}
+	if _, err := health.Record(townRoot, probes, now); err != nil {
+		style.PrintWarning("could not record agent health: %v", err)
+	}
+
+	unhealthy := 0
+	shown := make([]health.Probe, 0, len(probes))
+	for _, p := range probes {
+		if !p.Status.Healthy() {
+			unhealthy++
+		} else if healthUnhealthy {
+			continue
+		}
+		shown = append(shown, p)
+	}
+
+	if healthJSON {
+		if err := outputJSON(shown); err != nil {
+			return err
+		}
+	} else {
+		printHealth(shown, now)
+		if len(probes) == 0 {
+			fmt.Println(style.Dim.Render("No agent sessions running"))
+		} else {
+			fmt.Printf("\n%d agent(s), %d unhealthy\n", len(probes), unhealthy)
+		}
+	}
+	if unhealthy > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// printHealth prints one line per probed agent.
+func printHealth(probes []health.Probe, now time.Time) {
+	for _, p := range probes {
+		mark, status := style.Success.Render("●"), fmt.Sprintf("%-8s", p.Status)
+		switch p.Status {
+		case health.StatusStale:
+			mark, status = style.Warning.Render("●"), style.Warning.Render(status)
+		case health.StatusPaused:
+			mark, status = style.Dim.Render("●"), style.Dim.Render(status)
+		case health.StatusExited, health.StatusDead:
+			mark, status = style.Error.Render("●"), style.Error.Render(status)
+		}
+		output := "no output yet"
+		if !p.LastOutput.IsZero() {
+			output = "output " + formatDuration(p.Age(now)) + " ago"
+		}
+		line := fmt.Sprintf("%s %-28s %s %s", mark, p.Agent, status, style.Dim.Render(output))
+		if p.Reason != "" {
+			line += style.Dim.Render(" — " + p.Reason)
+		}
+		fmt.Println(line)
+	}
+}
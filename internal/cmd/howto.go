@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/howto"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/suggest"
+)
+
+var howtoCommands bool
+
+var howtoCmd = &cobra.Command{
+	Use:     "howto [recipe | search words...]",
+	GroupID: GroupDiag,
+	Short:   "Operational recipes with example commands",
+	Long: `Show the cookbook of operational recipes: step-by-step fixes for common
+situations (a crashed crew member, switching agent presets, moving a rig),
+with the commands to run.
+
+The recipes are built into gt, so they always match the installed version.
+Without arguments, all recipes are listed. Give a recipe name to read it,
+or a few words to search titles, tags, and text.
+
+Examples:
+  gt howto                          # List recipes
+  gt howto resurrect-crew           # Read one
+  gt howto crashed crew             # Search
+  gt howto migrate-rig-remote --commands   # Just the commands, to copy`,
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: completeHowto,
+	RunE:              runHowto,
+}
+
+func init() {
+	howtoCmd.Flags().BoolVar(&howtoCommands, "commands", false, "Print only the recipe's commands, one per line")
+
+	rootCmd.AddCommand(howtoCmd)
+}
+
+func runHowto(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		printHowtoList(howto.All())
+		fmt.Printf("\n%s\n", style.Dim.Render("Read one with: gt howto <recipe>"))
+		return nil
+	}
+
+	r, ok := howto.Get(strings.ToLower(args[0]))
+	if !ok || len(args) > 1 {
+		matches := howto.Search(strings.Join(args, " "))
+		switch len(matches) {
+		case 0:
+			msg := fmt.Sprintf("no recipe matches %q", strings.Join(args, " "))
+			if similar := suggest.FindSimilar(args[0], howto.Slugs(), 3); len(similar) > 0 {
+				msg += fmt.Sprintf(" (did you mean %s?)", strings.Join(similar, ", "))
+			}
+			return fmt.Errorf("%s; run 'gt howto' to list recipes", msg)
+		case 1:
+			r = matches[0]
+		default:
+			fmt.Printf("%d recipes match:\n\n", len(matches))
+			printHowtoList(matches)
+			return nil
+		}
+	}
+
+	if howtoCommands {
+		for _, c := range r.Commands() {
+			fmt.Println(c)
+		}
+		return nil
+	}
+	renderRecipe(os.Stdout, r)
+	return nil
+}
+
+func printHowtoList(recipes []howto.Recipe) {
+	width := 0
+	for _, r := range recipes {
+		width = max(width, len(r.Slug))
+	}
+	for _, r := range recipes {
+		fmt.Printf("  %s  %s\n", style.Bold.Render(fmt.Sprintf("%-*s", width, r.Slug)), r.Summary)
+	}
+}
+
+// howtoInlineCode matches `code` spans in recipe text.
+var howtoInlineCode = regexp.MustCompile("`([^`]+)`")
+
+// renderRecipe prints a recipe's Markdown for the terminal: headings in
+// bold, commands indented and highlighted, comments dimmed.
+func renderRecipe(w io.Writer, r howto.Recipe) {
+	inCode, inBash := false, false
+	for _, line := range strings.Split(strings.TrimRight(r.Body, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			inCode = !inCode
+			lang := strings.TrimPrefix(trimmed, "```")
+			inBash = inCode && (lang == "bash" || lang == "sh")
+		case inCode && inBash && strings.HasPrefix(trimmed, "#"):
+			fmt.Fprintf(w, "    %s\n", style.Dim.Render(trimmed))
+		case inCode && inBash && trimmed != "":
+			fmt.Fprintf(w, "    %s %s\n", style.Dim.Render("$"), style.Info.Render(trimmed))
+		case inCode:
+			fmt.Fprintf(w, "    %s\n", line)
+		case strings.HasPrefix(line, "# "):
+			fmt.Fprintf(w, "%s\n", style.Bold.Render(strings.TrimPrefix(line, "# ")))
+		case strings.HasPrefix(line, "## "):
+			fmt.Fprintf(w, "%s\n", style.Bold.Render(strings.TrimPrefix(line, "## ")))
+		default:
+			fmt.Fprintln(w, howtoInlineCode.ReplaceAllStringFunc(line, func(m string) string {
+				return style.Bold.Render(strings.Trim(m, "`"))
+			}))
+		}
+	}
+}
+
+// completeHowto completes recipe names.
+func completeHowto(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, r := range howto.All() {
+		if strings.HasPrefix(r.Slug, toComplete) {
+			names = append(names, r.Slug+"\t"+r.Summary)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ctiospl/gastown/internal/howto"
+)
+
+// Recipes ship in the binary, so every gt command they show must exist in
+// this build, flags included.
+func TestHowtoCommandsExist(t *testing.T) {
+	for _, r := range howto.All() {
+		for _, line := range r.Commands() {
+			fields := strings.Fields(line)
+			if fields[0] != "gt" {
+				continue
+			}
+			c, rest, err := rootCmd.Find(fields[1:])
+			if err != nil || c == rootCmd {
+				t.Errorf("%s: %q: no such command", r.Slug, line)
+				continue
+			}
+			for _, arg := range rest {
+				name, ok := strings.CutPrefix(arg, "--")
+				if !ok {
+					continue
+				}
+				name, _, _ = strings.Cut(name, "=")
+				if c.Flags().Lookup(name) == nil && c.InheritedFlags().Lookup(name) == nil {
+					t.Errorf("%s: %q: %s has no --%s flag", r.Slug, line, c.CommandPath(), name)
+				}
+			}
+		}
+	}
+}
+
+func TestRenderRecipe(t *testing.T) {
+	r, _ := howto.Get("resurrect-crew")
+	var buf bytes.Buffer
+	renderRecipe(&buf, r)
+	out := buf.String()
+	if strings.Contains(out, "```") || strings.HasPrefix(out, "#") {
+		t.Errorf("Markdown syntax left in output:\n%s", out)
+	}
+	if !strings.Contains(out, "    $ gt crew restart gastown/max") {
+		t.Errorf("commands should be indented with a prompt:\n%s", out)
+	}
+}
@@ -161,6 +161,13 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Printf("   ✓ Created mayor/town.json\n")
 
+	// Register the town so cross-town views (gt log --all-towns) find it
+	if err := config.RegisterTown(townName, absPath); err != nil {
+		fmt.Printf("   %s Could not register town: %v\n", style.Dim.Render("⚠"), err)
+	} else {
+		fmt.Printf("   ✓ Registered town (see 'gt town list')\n")
+	}
+
 	// Create rigs.json in mayor/
 	rigsConfig := &config.RigsConfig{
 		Version: config.CurrentRigsVersion,
@@ -11,7 +11,7 @@ import (
 var issueCmd = &cobra.Command{
 	Use:     "issue",
 	GroupID: GroupConfig,
-	Short:   "Manage current issue for status line display",
+	Short:   "Manage current issue and show issue history",
 }
 
 var issueSetCmd = &cobra.Command{
@@ -0,0 +1,481 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/beads"
+	"github.com/ctiospl/gastown/internal/events"
+	"github.com/ctiospl/gastown/internal/mrqueue"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var issueTimelineJSON bool
+
+var issueTimelineCmd = &cobra.Command{
+	Use:   "timeline <issue-id>",
+	Short: "Show the lifecycle of one issue across all sources",
+	Long: `Show everything that happened to one issue, in chronological order.
+
+Assembles the issue's history from:
+  - Beads (created, closed)
+  - Activity events (sling, hook, done)
+  - The town log, including archives (spawns, and handoffs, crashes, and
+    nudges of the agents working the issue)
+  - Each rig's merge queue log (review, test runs, merge)
+
+Entries are grouped into phases, and the time spent in each is shown:
+  queued    created, waiting to be slung
+  working   an agent has it (again after a failed merge)
+  review    work submitted, waiting for the refinery
+  merging   refinery is testing and merging it
+
+Examples:
+  gt issue timeline gt-42
+  gt issue timeline gt-42 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueTimeline,
+}
+
+func init() {
+	issueTimelineCmd.Flags().BoolVar(&issueTimelineJSON, "json", false, "Output as JSON")
+	issueCmd.AddCommand(issueTimelineCmd)
+}
+
+// Issue lifecycle phases.
+const (
+	phaseQueued  = "queued"
+	phaseWorking = "working"
+	phaseReview  = "review"
+	phaseMerging = "merging"
+	phaseDone    = "done"
+)
+
+// timelineEntry is one thing that happened to an issue.
+type timelineEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Phase     string    `json:"phase"`  // phase the issue was in after this entry
+	Source    string    `json:"source"` // "beads", "events", "townlog", "mq"
+	Type      string    `json:"type"`
+	Actor     string    `json:"actor,omitempty"`
+	Summary   string    `json:"summary"`
+
+	enters string // phase this entry starts, or "" if it does not change phase
+}
+
+// phaseSpan is a contiguous stretch of time in one phase.
+type phaseSpan struct {
+	Phase    string        `json:"phase"`
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Duration time.Duration `json:"duration_ns"`
+	Open     bool          `json:"open,omitempty"` // still in this phase
+}
+
+// issueTimeline is the assembled lifecycle of one issue.
+type issueTimeline struct {
+	Issue     string          `json:"issue"`
+	Title     string          `json:"title,omitempty"`
+	Status    string          `json:"status,omitempty"`
+	Entries   []timelineEntry `json:"entries"`
+	Phases    []phaseSpan     `json:"phases"`
+	CycleTime time.Duration   `json:"cycle_time_ns"`
+}
+
+func runIssueTimeline(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	issueID := args[0]
+
+	tl := &issueTimeline{Issue: issueID}
+	var entries []timelineEntry
+
+	// 1. The bead itself
+	if issue, err := beads.New(townRoot).Show(issueID); err == nil {
+		tl.Title = issue.Title
+		tl.Status = issue.Status
+		entries = append(entries, beadTimelineEntries(issue)...)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s could not read bead %s: %v\n", style.WarningPrefix, issueID, err)
+	}
+
+	// 2. Activity events
+	feedEntries, err := collectIssueFeedEvents(townRoot, issueID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s could not read activity events: %v\n", style.WarningPrefix, err)
+	}
+	entries = append(entries, feedEntries...)
+
+	// 3. Merge queue events
+	entries = append(entries, collectIssueMQEvents(townRoot, issueID)...)
+
+	// 4. Town log, for spawns and the working agents' session events
+	logEvents, err := townlog.ReadArchivedEvents(townRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s could not read town log: %v\n", style.WarningPrefix, err)
+	}
+	entries = append(entries, issueTownlogEntries(logEvents, issueID, entries)...)
+
+	if len(entries) == 0 {
+		return fmt.Errorf("no history found for %s", issueID)
+	}
+
+	tl.Entries, tl.Phases = buildIssueTimeline(entries, time.Now())
+	if len(tl.Phases) > 0 {
+		tl.CycleTime = tl.Phases[len(tl.Phases)-1].End.Sub(tl.Phases[0].Start)
+	}
+
+	if issueTimelineJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tl)
+	}
+	printIssueTimeline(tl)
+	return nil
+}
+
+// beadTimelineEntries returns the creation and close of an issue.
+func beadTimelineEntries(issue *beads.Issue) []timelineEntry {
+	var entries []timelineEntry
+	if ts, err := time.Parse(time.RFC3339, issue.CreatedAt); err == nil {
+		summary := "Created"
+		if issue.CreatedBy != "" {
+			summary += " by " + issue.CreatedBy
+		}
+		entries = append(entries, timelineEntry{
+			Timestamp: ts, Source: "beads", Type: "created", Actor: issue.CreatedBy,
+			Summary: summary, enters: phaseQueued,
+		})
+	}
+	if ts, err := time.Parse(time.RFC3339, issue.ClosedAt); err == nil {
+		entries = append(entries, timelineEntry{
+			Timestamp: ts, Source: "beads", Type: "closed", Actor: issue.Assignee,
+			Summary: "Closed", enters: phaseDone,
+		})
+	}
+	return entries
+}
+
+// collectIssueFeedEvents returns activity events whose bead is issueID.
+func collectIssueFeedEvents(townRoot, issueID string) ([]timelineEntry, error) {
+	file, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []timelineEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		bead, _ := e.Payload["bead"].(string)
+		if !strings.EqualFold(bead, issueID) {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			continue
+		}
+		entry := timelineEntry{Timestamp: ts, Source: "events", Type: e.Type, Actor: e.Actor, Summary: formatFeedSummary(e)}
+		switch e.Type {
+		case events.TypeSling:
+			if target, ok := e.Payload["target"].(string); ok {
+				entry.Actor = target
+				entry.Summary = "Slung to " + target
+			}
+			entry.enters = phaseWorking
+		case events.TypeHook:
+			entry.Summary = "Hooked"
+			entry.enters = phaseWorking
+		case events.TypeUnhook:
+			entry.Summary = "Unhooked"
+		case events.TypeDone:
+			entry.Summary = "Work submitted"
+			if branch, ok := e.Payload["branch"].(string); ok && branch != "" {
+				entry.Summary += " (" + branch + ")"
+			}
+			entry.enters = phaseReview
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// collectIssueMQEvents returns merge queue events for issueID from every rig.
+func collectIssueMQEvents(townRoot, issueID string) []timelineEntry {
+	var entries []timelineEntry
+	for _, rigName := range discoverRigs(townRoot) {
+		logPath := mrqueue.NewEventLoggerFromRig(filepath.Join(townRoot, rigName)).LogPath()
+		file, err := os.Open(logPath) //nolint:gosec // G304: path is constructed from trusted townRoot
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var e mrqueue.Event
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+				continue
+			}
+			if !strings.EqualFold(e.SourceIssue, issueID) {
+				continue
+			}
+			entry := timelineEntry{Timestamp: e.Timestamp, Source: "mq", Type: string(e.Type), Actor: e.Worker}
+			switch e.Type {
+			case mrqueue.EventMergeStarted:
+				entry.Summary = fmt.Sprintf("Refinery started %s (%s → %s)", e.MRID, e.Branch, e.Target)
+				entry.enters = phaseMerging
+			case mrqueue.EventMerged:
+				entry.Summary = "Merged " + e.Branch
+				if e.MergeCommit != "" {
+					entry.Summary += " as " + shortCommit(e.MergeCommit)
+				}
+				entry.enters = phaseDone
+			case mrqueue.EventMergeFailed:
+				entry.Summary = "Merge failed: " + e.Reason
+				entry.enters = phaseWorking
+			case mrqueue.EventMergeSkipped:
+				entry.Summary = "Merge skipped: " + e.Reason
+			case mrqueue.EventClaimed:
+				entry.Actor = e.ClaimedBy
+				entry.Summary = "Claimed by " + e.ClaimedBy
+			case mrqueue.EventLeaseExpired:
+				entry.Actor = e.ClaimedBy
+				entry.Summary = "Lease held by " + e.ClaimedBy + " expired"
+			case mrqueue.EventReleased:
+				entry.Actor = e.ClaimedBy
+				entry.Summary = "Released by " + e.ClaimedBy
+			default:
+				continue // lease renewals are noise here
+			}
+			entries = append(entries, entry)
+		}
+		file.Close()
+	}
+	return entries
+}
+
+// issueTownlogEntries returns town log events for issueID: spawns and
+// completions that name it, plus handoffs, crashes, kills, and nudges of
+// the agents working it while they were on it.
+func issueTownlogEntries(logEvents []townlog.Event, issueID string, known []timelineEntry) []timelineEntry {
+	var entries []timelineEntry
+	agents := make(map[string]bool)
+	for _, e := range known {
+		if e.Type == events.TypeSling && e.Actor != "" {
+			agents[e.Actor] = true
+		}
+	}
+
+	var start, end time.Time
+	for _, e := range logEvents {
+		if !mentionsIssue(e.Context, issueID) {
+			continue
+		}
+		entry := timelineEntry{Timestamp: e.Timestamp, Source: "townlog", Type: string(e.Type), Actor: e.Agent, Summary: formatTownlogSummary(e)}
+		switch e.Type {
+		case townlog.EventSpawn:
+			entry.enters = phaseWorking
+			agents[e.Agent] = true
+			if start.IsZero() || e.Timestamp.Before(start) {
+				start = e.Timestamp
+			}
+		case townlog.EventDone:
+			entry.enters = phaseReview
+			if e.Timestamp.After(end) {
+				end = e.Timestamp
+			}
+		}
+		entries = append(entries, entry)
+	}
+	for _, e := range known {
+		switch e.enters {
+		case phaseWorking:
+			if start.IsZero() || e.Timestamp.Before(start) {
+				start = e.Timestamp
+			}
+		case phaseReview:
+			if e.Timestamp.After(end) {
+				end = e.Timestamp
+			}
+		}
+	}
+	if start.IsZero() {
+		return entries
+	}
+	if end.Before(start) {
+		end = time.Now()
+	}
+
+	for _, e := range logEvents {
+		switch e.Type {
+		case townlog.EventHandoff, townlog.EventCrash, townlog.EventKill, townlog.EventNudge, townlog.EventWake:
+		default:
+			continue
+		}
+		if mentionsIssue(e.Context, issueID) || !agentMatchesAny(e.Agent, agents) {
+			continue
+		}
+		if e.Timestamp.Before(start) || e.Timestamp.After(end) {
+			continue
+		}
+		entries = append(entries, timelineEntry{
+			Timestamp: e.Timestamp, Source: "townlog", Type: string(e.Type), Actor: e.Agent, Summary: formatTownlogSummary(e),
+		})
+	}
+	return entries
+}
+
+// agentMatchesAny reports whether a town log agent is one of agents. Sling
+// targets and town log agents use different address forms
+// ("gastown/Toast" vs "gastown/polecats/Toast"), so both are normalized.
+func agentMatchesAny(agent string, agents map[string]bool) bool {
+	norm := func(a string) string {
+		a = strings.TrimSuffix(a, "/")
+		a = strings.Replace(a, "/polecats/", "/", 1)
+		return strings.ToLower(a)
+	}
+	want := norm(agent)
+	for a := range agents {
+		if norm(a) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsIssue reports whether s contains issueID as a whole token, so
+// "gt-4" does not match "gt-42" or "gt-4.1".
+func mentionsIssue(s, issueID string) bool {
+	if issueID == "" {
+		return false
+	}
+	s, issueID = strings.ToLower(s), strings.ToLower(issueID)
+	isIDChar := func(c byte) bool {
+		return c == '-' || c == '.' || c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z')
+	}
+	for i := 0; ; {
+		j := strings.Index(s[i:], issueID)
+		if j < 0 {
+			return false
+		}
+		j += i
+		end := j + len(issueID)
+		before := j == 0 || !isIDChar(s[j-1])
+		after := end == len(s) || !isIDChar(s[end]) || (s[end] == '.' && (end+1 == len(s) || s[end+1] < '0' || s[end+1] > '9'))
+		if before && after {
+			return true
+		}
+		i = j + 1
+	}
+}
+
+// buildIssueTimeline sorts entries, drops duplicates reported by more than
+// one source, assigns each entry its phase, and splits the history into
+// phase spans. The last span ends at now unless the issue is done.
+func buildIssueTimeline(entries []timelineEntry, now time.Time) ([]timelineEntry, []phaseSpan) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	var deduped []timelineEntry
+	for _, e := range entries {
+		if n := len(deduped); n > 0 {
+			prev := deduped[n-1]
+			if prev.Source != e.Source && prev.enters == e.enters && e.enters != "" &&
+				e.Timestamp.Sub(prev.Timestamp) < 2*time.Second {
+				continue
+			}
+		}
+		deduped = append(deduped, e)
+	}
+
+	var spans []phaseSpan
+	phase := ""
+	for i := range deduped {
+		e := &deduped[i]
+		if e.enters != "" && e.enters != phase {
+			if n := len(spans); n > 0 {
+				spans[n-1].End = e.Timestamp
+				spans[n-1].Duration = e.Timestamp.Sub(spans[n-1].Start)
+			}
+			phase = e.enters
+			if phase != phaseDone {
+				spans = append(spans, phaseSpan{Phase: phase, Start: e.Timestamp})
+			}
+		}
+		e.Phase = phase
+	}
+	if n := len(spans); n > 0 && phase != phaseDone {
+		spans[n-1].End = now
+		spans[n-1].Duration = now.Sub(spans[n-1].Start)
+		spans[n-1].Open = true
+	}
+	return deduped, spans
+}
+
+func printIssueTimeline(tl *issueTimeline) {
+	header := tl.Issue
+	if tl.Title != "" {
+		header += ": " + tl.Title
+	}
+	fmt.Printf("\n%s %s", style.Bold.Render("📜"), style.Bold.Render(header))
+	if tl.Status != "" {
+		fmt.Printf(" %s", style.Dim.Render("["+tl.Status+"]"))
+	}
+	fmt.Print("\n\n")
+
+	var prev time.Time
+	for _, e := range tl.Entries {
+		gap := ""
+		if !prev.IsZero() {
+			gap = style.Dim.Render("+" + formatDuration(e.Timestamp.Sub(prev)))
+		}
+		prev = e.Timestamp
+		fmt.Printf("  %s  %-8s %-8s %s %s\n",
+			e.Timestamp.Local().Format("2006-01-02 15:04"), e.Phase, style.Dim.Render(e.Source), e.Summary, gap)
+	}
+
+	if len(tl.Phases) == 0 {
+		return
+	}
+	totals := make(map[string]time.Duration)
+	spans := make(map[string]int)
+	var order []string
+	for _, s := range tl.Phases {
+		if _, seen := totals[s.Phase]; !seen {
+			order = append(order, s.Phase)
+		}
+		totals[s.Phase] += s.Duration
+		spans[s.Phase]++
+	}
+	fmt.Printf("\n%s\n", style.Bold.Render("Phases"))
+	for _, p := range order {
+		line := fmt.Sprintf("  %-8s %s", p, formatDuration(totals[p]))
+		if spans[p] > 1 {
+			line += style.Dim.Render(fmt.Sprintf(" (%d spans)", spans[p]))
+		}
+		fmt.Println(line)
+	}
+	cycle := "Cycle time"
+	if tl.Phases[len(tl.Phases)-1].Open {
+		cycle += " so far"
+	}
+	fmt.Printf("  %s: %s\n", cycle, style.Bold.Render(formatDuration(tl.CycleTime)))
+}
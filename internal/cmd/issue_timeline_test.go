@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMentionsIssue(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"gt-42", true},
+		{"GT-42", true},
+		{"completed gt-42 (branch)", true},
+		{"gt-421", false},
+		{"gt-42.1", false},
+		{"gt-42.", true},
+		{"xgt-42", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := mentionsIssue(tt.s, "gt-42"); got != tt.want {
+			t.Errorf("mentionsIssue(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestBuildIssueTimeline(t *testing.T) {
+	t0 := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return t0.Add(time.Duration(m) * time.Minute) }
+	entries := []timelineEntry{
+		{Timestamp: at(90), Source: "mq", Type: "merged", enters: phaseDone},
+		{Timestamp: at(0), Source: "beads", Type: "created", enters: phaseQueued},
+		{Timestamp: at(10), Source: "events", Type: "sling", enters: phaseWorking},
+		{Timestamp: at(10), Source: "townlog", Type: "spawn", enters: phaseWorking}, // duplicate
+		{Timestamp: at(30), Source: "townlog", Type: "handoff"},
+		{Timestamp: at(60), Source: "events", Type: "done", enters: phaseReview},
+		{Timestamp: at(70), Source: "mq", Type: "merge_started", enters: phaseMerging},
+		{Timestamp: at(75), Source: "mq", Type: "merge_failed", enters: phaseWorking},
+		{Timestamp: at(80), Source: "mq", Type: "merge_started", enters: phaseMerging},
+	}
+
+	got, spans := buildIssueTimeline(entries, at(1000))
+	if len(got) != len(entries)-1 {
+		t.Errorf("got %d entries, want duplicate spawn dropped", len(got))
+	}
+	if got[2].Type != "handoff" || got[2].Phase != phaseWorking {
+		t.Errorf("handoff entry = %+v, want phase working", got[2])
+	}
+
+	want := []struct {
+		phase string
+		mins  int
+	}{
+		{phaseQueued, 10}, {phaseWorking, 50}, {phaseReview, 10},
+		{phaseMerging, 5}, {phaseWorking, 5}, {phaseMerging, 10},
+	}
+	if len(spans) != len(want) {
+		t.Fatalf("got %d spans, want %d: %+v", len(spans), len(want), spans)
+	}
+	for i, w := range want {
+		if spans[i].Phase != w.phase || spans[i].Duration != time.Duration(w.mins)*time.Minute {
+			t.Errorf("span %d = %s %v, want %s %dm", i, spans[i].Phase, spans[i].Duration, w.phase, w.mins)
+		}
+	}
+	if spans[len(spans)-1].Open {
+		t.Error("merged issue should not have an open phase")
+	}
+}
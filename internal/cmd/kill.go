@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/agentrt"
+	"github.com/ctiospl/gastown/internal/events"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+// killPollInterval is how often a graceful kill checks for the agent's
+// done signal.
+const killPollInterval = 2 * time.Second
+
+var (
+	killGraceful bool
+	killTimeout  time.Duration
+	killSelector string
+	killDryRun   bool
+	killReason   string
+)
+
+var killCmd = &cobra.Command{
+	Use:     "kill [<agent>...]",
+	GroupID: GroupAgents,
+	Short:   "Stop agents, optionally letting them wrap up first",
+	Long: `Stop agents' sessions.
+
+By default the session is killed at once, and whatever the agent had not
+committed is left in its working tree as it was.
+
+With --graceful the agent is first nudged (urgently, past DND) to wrap up:
+to commit its work and signal that it is done, with gt done for polecats
+and gt handoff for other agents, so the next session picks up from its
+handoff mail. gt kill waits for the signal in the town log, or for the
+session to exit, and then stops the session. Only if --timeout expires
+first is the session force-killed. A paused agent is resumed to wrap up.
+
+Each kill is logged as a "kill" event saying which path was taken: killed
+at once, stopped after the done signal, exited on its own, or force-killed
+at the timeout. Kills are logged with warn severity, except those of
+agents that wrapped up, which are logged as info.
+
+--selector kills every running agent whose labels (gt spawn --label)
+match a selector such as "team=frontend", along with any agents named.
+
+--reason says why the agents are being stopped. It is added to the kill
+events and, with --graceful, to the wrap-up nudge.
+
+--dry-run prints what would be stopped, changing nothing: each agent's
+session and worktree, and with --graceful the wrap-up nudge it would get.
+
+Examples:
+  gt kill gastown/polecats/Toast
+  gt kill gastown/crew/max --graceful
+  gt kill gastown/polecats/Toast gastown/polecats/Nux --graceful --timeout 5m
+  gt kill --selector team=frontend --graceful
+  gt kill gastown/crew/max --reason "reassigning to infra"
+  gt kill --selector team=frontend --dry-run`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if killSelector != "" {
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	RunE: runKill,
+}
+
+func init() {
+	killCmd.Flags().BoolVarP(&killGraceful, "graceful", "g", false, "Nudge the agent to wrap up and commit, and wait for it before killing")
+	killCmd.Flags().DurationVar(&killTimeout, "timeout", 2*time.Minute, "With --graceful, how long to wait for the done signal before force-killing")
+	killCmd.Flags().StringVar(&killSelector, "selector", "", "Also kill every running agent whose labels match this selector (e.g. team=frontend)")
+	killCmd.Flags().BoolVarP(&killDryRun, "dry-run", "n", false, "Show the sessions that would be stopped, and how, without stopping them")
+	killCmd.Flags().StringVar(&killReason, "reason", "", "Why the agents are being stopped, for the kill events and the wrap-up nudge")
+	rootCmd.AddCommand(killCmd)
+}
+
+// killTarget is an agent being killed.
+type killTarget struct {
+	id      *session.AgentIdentity
+	session string
+	done    bool
+}
+
+func runKill(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if killTimeout <= 0 {
+		return fmt.Errorf("--timeout must be positive")
+	}
+	if cmd.Flags().Changed("timeout") && !killGraceful {
+		return fmt.Errorf("--timeout applies to --graceful kills")
+	}
+	cmd.SilenceUsage = true
+
+	t := tmux.NewTmux()
+	if killSelector != "" {
+		selected, err := selectAgents(townRoot, killSelector)
+		if err != nil {
+			return err
+		}
+		for _, agent := range selected {
+			id, err := session.ParseAddress(agent)
+			if err != nil || slices.Contains(args, id.Address()) {
+				continue
+			}
+			if running, _ := t.HasSession(id.SessionName()); running {
+				args = append(args, id.Address())
+			}
+		}
+		if len(args) == 0 {
+			fmt.Printf("%s No running agents match %q\n", style.WarningPrefix, killSelector)
+			return nil
+		}
+	}
+	pauses, _ := session.LoadPauses(townRoot)
+	var targets []*killTarget
+	var failed int
+	for _, arg := range args {
+		id, err := session.ParseAddress(arg)
+		if err != nil {
+			style.PrintWarning("%v", err)
+			failed++
+			continue
+		}
+		target := &killTarget{id: id, session: id.SessionName()}
+		if running, _ := t.HasSession(target.session); !running {
+			style.PrintWarning("%s is not running", id.Address())
+			failed++
+			continue
+		}
+		if _, paused := pauses[target.session]; paused && !killDryRun {
+			if _, err := session.ResumeSession(t, townRoot, target.session); err != nil {
+				style.PrintWarning("%v", err)
+			}
+		}
+		targets = append(targets, target)
+	}
+
+	if killDryRun {
+		for i, target := range targets {
+			if i > 0 {
+				fmt.Println()
+			}
+			_, paused := pauses[target.session]
+			fmt.Print(killPlan(townRoot, target, paused))
+		}
+	} else if killGraceful {
+		failed += drainAgents(townRoot, t, targets)
+	} else {
+		for _, target := range targets {
+			if killAgent(townRoot, t, target, killContext("gt kill"), "") {
+				fmt.Printf("%s Killed %s\n", style.SuccessPrefix, target.id.Address())
+			} else {
+				failed++
+			}
+		}
+	}
+
+	if failed > 0 {
+		return partialFailure("%d of %d agent(s) not killed", failed, len(args))
+	}
+	return nil
+}
+
+// drainAgents nudges each target to wrap up, then stops each one as it
+// signals done, exits, or runs out of time. It returns how many could not
+// be killed.
+func drainAgents(townRoot string, t *tmux.Tmux, targets []*killTarget) int {
+	start := time.Now().Truncate(time.Second) // the town log keeps whole seconds
+	failed := 0
+	for _, target := range targets {
+		msg := wrapUpMessage(target.id, killTimeout, killReason)
+		if err := agentrt.ForAgent(townRoot, target.id.Address()).Nudge(t, target.session, msg); err != nil {
+			style.PrintWarning("could not nudge %s to wrap up: %v", target.id.Address(), err)
+			continue
+		}
+		_ = LogNudge(townRoot, target.id.Address(), msg)
+		fmt.Printf("%s Asked %s to wrap up\n", style.Dim.Render("○"), target.id.Address())
+	}
+	fmt.Printf("%s Waiting up to %s for done signals...\n", style.Dim.Render("○"), formatDuration(killTimeout))
+
+	deadline := start.Add(killTimeout)
+	for {
+		evts, _ := townlog.ReadEvents(townRoot)
+		pending := 0
+		for _, target := range targets {
+			if target.done {
+				continue
+			}
+			waited := formatDuration(time.Since(start).Round(time.Second))
+			if e, ok := drainSignal(evts, target.id.Address(), start); ok {
+				target.done = true
+				if killAgent(townRoot, t, target, killContext(fmt.Sprintf("gt kill --graceful: stopped after %s signal (%s)", e.Type, waited)), townlog.SeverityInfo) {
+					fmt.Printf("%s %s signaled %s after %s; stopped\n", style.SuccessPrefix, target.id.Address(), e.Type, waited)
+				} else {
+					failed++
+				}
+				continue
+			}
+			if agentrt.ForAgent(townRoot, target.id.Address()).Exited(t, target.session) {
+				target.done = true
+				if killAgent(townRoot, t, target, killContext(fmt.Sprintf("gt kill --graceful: runtime exited on its own (%s)", waited)), townlog.SeverityInfo) {
+					fmt.Printf("%s %s exited on its own after %s; stopped\n", style.SuccessPrefix, target.id.Address(), waited)
+				} else {
+					failed++
+				}
+				continue
+			}
+			if running, _ := t.HasSession(target.session); !running {
+				target.done = true
+				_ = townlog.NewLogger(townRoot).LogEvent(townlog.Event{
+					Timestamp: time.Now(),
+					Type:      townlog.EventKill,
+					Agent:     target.id.Address(),
+					Context:   killContext(fmt.Sprintf("gt kill --graceful: exited on its own (%s)", waited)),
+					Severity:  townlog.SeverityInfo,
+				})
+				fmt.Printf("%s %s exited on its own after %s\n", style.SuccessPrefix, target.id.Address(), waited)
+				continue
+			}
+			pending++
+		}
+		if pending == 0 {
+			return failed
+		}
+		if !time.Now().Before(deadline) {
+			break
+		}
+		wait := killPollInterval
+		if left := time.Until(deadline); left < wait {
+			wait = left
+		}
+		time.Sleep(wait)
+	}
+
+	for _, target := range targets {
+		if target.done {
+			continue
+		}
+		reason := killContext(fmt.Sprintf("gt kill --graceful: no done signal within %s; force-killed", formatDuration(killTimeout)))
+		if killAgent(townRoot, t, target, reason, "") {
+			fmt.Printf("%s %s did not signal done within %s; force-killed\n", style.WarningPrefix, target.id.Address(), formatDuration(killTimeout))
+		} else {
+			failed++
+		}
+	}
+	return failed
+}
+
+// killPlan returns what killing a target would do (--dry-run).
+func killPlan(townRoot string, target *killTarget, paused bool) dryRunPlan {
+	plan := dryRunPlan{Action: "Would kill " + target.id.Address(), Session: target.session}
+	if dir, err := sessionWorkDir(target.session, townRoot); err == nil {
+		plan.Worktree = dir
+		if !killGraceful {
+			plan.Worktree += " (uncommitted work is left as it is)"
+		}
+	}
+	if killReason != "" {
+		plan.Details = append(plan.Details, [2]string{"Reason", killReason})
+	}
+	if paused && killGraceful {
+		plan.Details = append(plan.Details, [2]string{"Paused", "would be resumed to wrap up"})
+	}
+	if killGraceful {
+		plan.Action = "Would stop " + target.id.Address() + " gracefully"
+		plan.Details = append(plan.Details,
+			[2]string{"Nudge", wrapUpMessage(target.id, killTimeout, killReason)},
+			[2]string{"Timeout", "force-killed after " + formatDuration(killTimeout) + " without a done signal"})
+	}
+	return plan
+}
+
+// wrapUpMessage is the nudge asking an agent to finish before it is
+// killed, saying why if reason is given.
+func wrapUpMessage(id *session.AgentIdentity, timeout time.Duration, reason string) string {
+	signal := "gt handoff -m \"<where you left off>\""
+	if id.Role == session.RolePolecat {
+		signal = "gt done (with --status DEFERRED if the work is not finished)"
+	}
+	if reason != "" {
+		reason = " (" + reason + ")"
+	}
+	return fmt.Sprintf("[gt kill] This session is being stopped%s. Wrap up now: commit your work, then run %s. "+
+		"The session will be killed in %s whether or not you have.", reason, signal, formatDuration(timeout))
+}
+
+// killContext returns the logged context of a kill, with --reason added.
+func killContext(context string) string {
+	if killReason == "" {
+		return context
+	}
+	return context + "; reason: " + killReason
+}
+
+// drainSignal returns the done or handoff event the agent logged since a
+// graceful kill began, if any.
+func drainSignal(evts []townlog.Event, agent string, since time.Time) (townlog.Event, bool) {
+	key := townlog.AgentKey(agent)
+	for _, e := range evts {
+		if e.Timestamp.Before(since) || townlog.AgentKey(e.Agent) != key {
+			continue
+		}
+		if e.Type == townlog.EventDone || e.Type == townlog.EventHandoff {
+			return e, true
+		}
+	}
+	return townlog.Event{}, false
+}
+
+// killAgent kills the target's session and logs the kill with reason and
+// severity (empty for the kill event's default), reporting whether it
+// succeeded.
+func killAgent(townRoot string, t *tmux.Tmux, target *killTarget, reason string, severity townlog.Severity) bool {
+	if err := t.KillSession(target.session); err != nil {
+		if running, _ := t.HasSession(target.session); running {
+			fmt.Printf("%s %s: %v\n", style.ErrorPrefix, target.id.Address(), err)
+			return false
+		}
+	}
+	_ = townlog.NewLogger(townRoot).LogEvent(townlog.Event{
+		Timestamp: time.Now(),
+		Type:      townlog.EventKill,
+		Agent:     target.id.Address(),
+		Context:   reason,
+		Severity:  severity,
+	})
+	_ = events.LogFeed(events.TypeKill, "gt", events.KillPayload(target.id.Rig, target.id.Address(), reason))
+	return true
+}
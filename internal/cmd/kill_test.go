@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+func TestDrainSignal(t *testing.T) {
+	start := time.Date(2026, 10, 14, 10, 0, 0, 0, time.Local)
+	agent := "gastown/polecats/Toast"
+	evts := []townlog.Event{
+		{Timestamp: start.Add(-time.Hour), Type: townlog.EventDone, Agent: agent}, // an earlier run
+		{Timestamp: start.Add(10 * time.Second), Type: townlog.EventNudge, Agent: agent},
+		{Timestamp: start.Add(20 * time.Second), Type: townlog.EventDone, Agent: "gastown/polecats/Nux"},
+	}
+	if _, ok := drainSignal(evts, agent, start); ok {
+		t.Fatal("drainSignal found a signal before the agent sent one")
+	}
+
+	evts = append(evts, townlog.Event{Timestamp: start.Add(45 * time.Second), Type: townlog.EventDone, Agent: agent + "/"})
+	e, ok := drainSignal(evts, agent, start)
+	if !ok || !e.Timestamp.Equal(start.Add(45*time.Second)) {
+		t.Errorf("drainSignal = %v, %v; want the done at +45s", e, ok)
+	}
+
+	handoff := []townlog.Event{{Timestamp: start, Type: townlog.EventHandoff, Agent: "gastown/crew/max"}}
+	if e, ok := drainSignal(handoff, "gastown/crew/max", start); !ok || e.Type != townlog.EventHandoff {
+		t.Errorf("drainSignal(handoff) = %v, %v", e, ok)
+	}
+}
+
+func TestWrapUpMessage(t *testing.T) {
+	polecat := wrapUpMessage(&session.AgentIdentity{Role: session.RolePolecat, Rig: "gastown", Name: "Toast"}, 2*time.Minute, "")
+	if !strings.Contains(polecat, "gt done") || !strings.Contains(polecat, "2m") {
+		t.Errorf("polecat message = %q", polecat)
+	}
+	crew := wrapUpMessage(&session.AgentIdentity{Role: session.RoleCrew, Rig: "gastown", Name: "max"}, 5*time.Minute, "max runtime 4h reached")
+	if !strings.Contains(crew, "gt handoff") || strings.Contains(crew, "gt done") || !strings.Contains(crew, "stopped (max runtime 4h reached).") {
+		t.Errorf("crew message = %q", crew)
+	}
+}
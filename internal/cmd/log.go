@@ -1,13 +1,20 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"os/signal"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/i18n"
+	"github.com/ctiospl/gastown/internal/lifecycle"
+	"github.com/ctiospl/gastown/internal/notify"
 	"github.com/ctiospl/gastown/internal/style"
 	"github.com/ctiospl/gastown/internal/townlog"
 	"github.com/ctiospl/gastown/internal/workspace"
@@ -16,10 +23,21 @@ import (
 // Log command flags
 var (
 	logTail   int
-	logType   string
+	logTypes  []string
 	logAgent  string
 	logSince  string
+	logUntil  string
 	logFollow bool
+	logIDs    bool
+	logAll    bool
+	logJSON   bool
+
+	logAgentRe     string
+	logSelector    string
+	logAllTowns    bool
+	logMinSeverity string
+	logSession     string
+	logAgentFile   string
 
 	// log crash flags
 	crashAgent    string
@@ -41,14 +59,46 @@ Events logged include:
   done    - agent finished work
   crash   - agent exited unexpectedly
   kill    - agent killed intentionally
+  mark    - named checkpoint (see 'gt mark')
+
+Scripts and hooks can record their own event types with 'gt log emit'.
 
 Examples:
   gt log                     # Show last 20 events
   gt log -n 50               # Show last 50 events
   gt log --type spawn        # Show only spawn events
+  gt log --type crash,kill,done  # Only terminal events
   gt log --agent greenplace/    # Show events for gastown rig
+  gt log --agent 'gastown/crew/*'         # Agent glob
+  gt log --agent-re 'crew/(max|joe)'      # Agent regular expression
+  gt log --agent-file gastown/crew/max    # One agent's history from its own log file
   gt log --since 1h          # Show events from last hour
-  gt log -f                  # Follow log (like tail -f)`,
+  gt log --since sprint-1 --until sprint-2  # Between two marks (see 'gt mark')
+  gt log --since '2026-10-13 22:00' --until 2026-10-14T06:00  # A specific window
+  gt log --since 2026-10-13 --until 2026-10-13   # One whole day
+  gt log -f                  # Follow new events as they happen
+  gt log -f --type crash     # Follow only crashes
+  gt log watch --type crash --exec './notify.sh {agent}'  # Act on new events
+  ci-events | gt log import   # Bring CI and deploy events into the town log
+  gt log --min-severity warn # Only warnings and errors
+  gt log --ids               # Show event and session IDs (see 'gt explain')
+  gt log --session 3f9a2c01  # One agent lifecycle: spawn, nudges, handoffs, done
+  gt log --all               # Include events hidden by rig ignore rules
+  gt log note "restarted tmux server here"  # Annotate the timeline
+  gt log --json | jq .type   # One JSON object per event, for tooling
+  gt log grep gt-abc         # Search event context (see 'gt log grep --help')
+  gt log stats --since 24h   # Counts per type and agent, crash rate, busiest hour
+  gt log export --since 7d > events.csv  # CSV for spreadsheets
+  gt log report --format md  # Activity report grouped by agent, for PRs and sharing
+  gt log migrate             # Move to the indexed SQLite event store
+  gt log emit deploy gastown/crew/max v1.4.2  # Record a custom event
+  gt log --all-towns -f      # Follow every registered town (see 'gt town list')
+  gt log serve --addr :7777  # Stream events over HTTP (Server-Sent Events)
+
+Rigs can hide noisy events from this view (they are still recorded) with
+"ignore" rules in <rig>/settings/config.json:
+
+  "ignore": [{"events": ["nudge", "polecat_checked"], "agents": ["janitor"]}]`,
 	RunE: runLog,
 }
 
@@ -71,10 +121,20 @@ Examples:
 
 func init() {
 	logCmd.Flags().IntVarP(&logTail, "tail", "n", 20, "Number of events to show")
-	logCmd.Flags().StringVarP(&logType, "type", "t", "", "Filter by event type (spawn,wake,nudge,handoff,done,crash,kill)")
-	logCmd.Flags().StringVarP(&logAgent, "agent", "a", "", "Filter by agent prefix (e.g., gastown/, greenplace/crew/max)")
-	logCmd.Flags().StringVar(&logSince, "since", "", "Show events since duration (e.g., 1h, 30m, 24h)")
-	logCmd.Flags().BoolVarP(&logFollow, "follow", "f", false, "Follow log output (like tail -f)")
+	logCmd.Flags().StringSliceVarP(&logTypes, "type", "t", nil, "Filter by event types, comma-separated or repeated (spawn,wake,nudge,handoff,done,crash,kill,mark,note)")
+	logCmd.Flags().StringVarP(&logAgent, "agent", "a", "", "Filter by agent prefix or glob (e.g., gastown/, greenplace/crew/max, '*/crew/*')")
+	logCmd.Flags().StringVar(&logAgentRe, "agent-re", "", "Filter by agent regular expression (e.g., 'crew/(max|joe)')")
+	logCmd.Flags().StringVar(&logSelector, "selector", "", "Filter by agent label selector (e.g., team=frontend,lang!=go; see gt spawn --label)")
+	logCmd.Flags().StringVar(&logSince, "since", "", "Show events since duration (e.g., 1h, 30m, 24h), time (RFC3339, 2006-01-02 15:04), or mark")
+	logCmd.Flags().StringVar(&logUntil, "until", "", "Show events up to duration ago, time, or mark")
+	logCmd.Flags().StringVar(&logMinSeverity, "min-severity", "", "Show only events at least this severe: info, warn (kills, nudged polecats), or error (crashes, escalations)")
+	logCmd.Flags().StringVar(&logSession, "session", "", "Show the events of one agent session, spawn to done, by session ID or prefix (see --ids)")
+	logCmd.Flags().BoolVarP(&logFollow, "follow", "f", false, "Follow new events; --type, --agent and --since still apply")
+	logCmd.Flags().BoolVar(&logIDs, "ids", false, "Prefix each event with its ID (for 'gt explain')")
+	logCmd.Flags().BoolVar(&logAll, "all", false, "Include events hidden by rig ignore rules")
+	logCmd.Flags().BoolVar(&logJSON, "json", false, "Output one JSON object per event (JSON Lines)")
+	logCmd.Flags().StringVar(&logAgentFile, "agent-file", "", "Show one agent's events from its own log (logs/agents/<agent>.log), without scanning the town log")
+	logCmd.Flags().BoolVar(&logAllTowns, "all-towns", false, "Merge the logs of all registered towns, prefixed with the town name")
 
 	// crash subcommand flags
 	logCrashCmd.Flags().StringVar(&crashAgent, "agent", "", "Agent ID (e.g., greenplace/Toast)")
@@ -89,93 +149,236 @@ func init() {
 func runLog(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
-		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		if !logAllTowns {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		townRoot = "" // --all-towns works from anywhere
+	}
+
+	// Build filter
+	filter := townlog.Filter{}
+
+	filter.Types = townlog.ParseTypes(logTypes...)
+
+	if err := applyAgentFilter(&filter, logAgent, logAgentRe); err != nil {
+		return err
 	}
+	if err := applySelectorFilter(&filter, townRoot, logSelector); err != nil {
+		return err
+	}
+	if err := applySeverityFilter(&filter, logMinSeverity); err != nil {
+		return err
+	}
+	filter.Session = strings.TrimSpace(logSession)
 
-	logPath := fmt.Sprintf("%s/logs/town.log", townRoot)
+	if logSince != "" {
+		if filter.Since, err = resolveTimeBoundary(townRoot, "since", logSince); err != nil {
+			return err
+		}
+	}
+	if logUntil != "" {
+		if filter.Until, err = resolveTimeBoundary(townRoot, "until", logUntil); err != nil {
+			return err
+		}
+	}
+	if err := checkTimeRange(filter.Since, filter.Until); err != nil {
+		return err
+	}
+
+	if logAgentFile != "" {
+		if logAgent != "" || logAgentRe != "" || logSelector != "" || logAllTowns || logFollow {
+			return fmt.Errorf("--agent-file cannot be combined with --agent, --agent-re, --selector, --all-towns, or --follow")
+		}
+		return runLogAgentFile(cmd, townRoot, filter)
+	}
+
+	if logAllTowns {
+		if logSelector != "" {
+			return fmt.Errorf("--selector cannot be combined with --all-towns: agent labels are kept per town")
+		}
+		return runLogAllTowns(cmd.Context(), townRoot, filter)
+	}
 
-	// If following, use tail -f
 	if logFollow {
-		return followLog(logPath)
+		return followLog(cmd.Context(), townRoot, filter)
 	}
 
-	// Check if log file exists
-	if _, err := os.Stat(logPath); os.IsNotExist(err) {
-		fmt.Printf("%s No log file yet (no events recorded)\n", style.Dim.Render("○"))
+	// Check if the town has logged anything
+	if !townlog.HasLog(townRoot) {
+		if !logJSON {
+			fmt.Printf("%s %s\n", style.Dim.Render("○"), i18n.T("log.no_file"))
+		}
 		return nil
 	}
 
-	// Read events
-	events, err := townlog.ReadEvents(townRoot)
+	// Read matching events
+	events, err := townlog.QueryEvents(townRoot, filter)
 	if err != nil {
 		return fmt.Errorf("reading events: %w", err)
 	}
 
-	if len(events) == 0 {
-		fmt.Printf("%s No events in log\n", style.Dim.Render("○"))
+	if len(events) == 0 && filter.Empty() {
+		if !logJSON {
+			fmt.Printf("%s %s\n", style.Dim.Render("○"), i18n.T("log.empty"))
+		}
 		return nil
 	}
 
-	// Build filter
-	filter := townlog.Filter{}
+	return printLogEvents(cmd, townRoot, filter, events)
+}
 
-	if logType != "" {
-		filter.Type = townlog.EventType(logType)
+// runLogAgentFile shows the events of --agent-file, read from its agent log.
+func runLogAgentFile(cmd *cobra.Command, townRoot string, filter townlog.Filter) error {
+	events, err := townlog.ReadAgentEvents(townRoot, logAgentFile)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
 	}
+	return printLogEvents(cmd, townRoot, filter, townlog.FilterEvents(events, filter))
+}
 
-	if logAgent != "" {
-		filter.Agent = logAgent
+// printLogEvents hides ignored events, applies --tail, and prints events.
+func printLogEvents(cmd *cobra.Command, townRoot string, filter townlog.Filter, events []townlog.Event) error {
+	// Hide rig-ignored noise unless asked for everything
+	hidden := 0
+	if !logAll {
+		events, hidden = filterIgnoredEvents(townRoot, events)
 	}
 
-	if logSince != "" {
-		duration, err := time.ParseDuration(logSince)
-		if err != nil {
-			return fmt.Errorf("invalid --since duration: %w", err)
-		}
-		filter.Since = time.Now().Add(-duration)
+	// Apply tail limit; a session thread is shown whole unless -n is given
+	if logTail > 0 && len(events) > logTail && (filter.Session == "" || cmd.Flags().Changed("tail")) {
+		events = events[len(events)-logTail:]
 	}
 
-	// Apply filter
-	events = townlog.FilterEvents(events, filter)
-
-	// Apply tail limit
-	if logTail > 0 && len(events) > logTail {
-		events = events[len(events)-logTail:]
+	if logJSON {
+		return writeEventsJSON(os.Stdout, events)
 	}
 
 	if len(events) == 0 {
-		fmt.Printf("%s No events match filter\n", style.Dim.Render("○"))
+		fmt.Printf("%s %s\n", style.Dim.Render("○"), i18n.T("log.no_match"))
+		printHiddenEvents(hidden)
 		return nil
 	}
 
-	// Print events
-	for _, e := range events {
+	// Print events, with the annotations attached to them
+	notes, _ := townlog.ReadAnnotations(townRoot)
+	attached := townlog.AttachAnnotations(events, notes)
+	for i, e := range events {
+		if logIDs {
+			printEventIDs(e)
+		}
 		printEvent(e)
+		printAnnotations(attached[i])
 	}
+	if filter.Session != "" {
+		printSessionSummary(events)
+	}
+	printHiddenEvents(hidden)
 
 	return nil
 }
 
-// followLog uses tail -f to follow the log file.
-func followLog(logPath string) error {
-	// Check if log file exists, create empty if not
-	if _, err := os.Stat(logPath); os.IsNotExist(err) {
-		// Create logs directory and empty file
-		if err := os.MkdirAll(fmt.Sprintf("%s", logPath[:len(logPath)-len("town.log")-1]), 0755); err != nil {
-			return fmt.Errorf("creating logs directory: %w", err)
+// filterIgnoredEvents drops events hidden by rig ignore rules and returns
+// how many were dropped.
+func filterIgnoredEvents(townRoot string, events []townlog.Event) ([]townlog.Event, int) {
+	rules := config.LoadIgnoreRules(townRoot)
+	if len(rules) == 0 {
+		return events, 0
+	}
+	kept := events[:0:0]
+	for _, e := range events {
+		if !rules.Ignored(string(e.Type), e.Agent) {
+			kept = append(kept, e)
 		}
-		if _, err := os.Create(logPath); err != nil {
-			return fmt.Errorf("creating log file: %w", err)
+	}
+	return kept, len(events) - len(kept)
+}
+
+func printHiddenEvents(hidden int) {
+	if hidden > 0 {
+		fmt.Printf("%s\n", style.Dim.Render(i18n.T("log.hidden", hidden)))
+	}
+}
+
+// followLog prints new events as they are appended to the town log,
+// after the last --tail matching events. The same filters as a normal
+// listing apply, including rig ignore rules unless --all is set.
+func followLog(ctx context.Context, townRoot string, filter townlog.Filter) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	var rules config.IgnoreRules
+	if !logAll {
+		rules = config.LoadIgnoreRules(townRoot)
+	}
+
+	if !logJSON {
+		fmt.Printf("%s %s\n\n", style.Dim.Render("○"), i18n.T("log.following", townlog.LogLocation(townRoot)))
+	}
+
+	opts := townlog.FollowOptions{Filter: filter, Backlog: logTail}
+	return townlog.Follow(ctx, townRoot, opts, func(e townlog.Event) error {
+		if rules.Ignored(string(e.Type), e.Agent) {
+			return nil
+		}
+		if logJSON {
+			return writeEventsJSON(os.Stdout, []townlog.Event{e})
 		}
+		if logIDs {
+			printEventIDs(e)
+		}
+		printEvent(e)
+		return nil
+	})
+}
+
+// printEventIDs prints the --ids prefix of an event line: its event ID,
+// then its session ID or blanks.
+func printEventIDs(e townlog.Event) {
+	fmt.Printf("%s %s ", style.Dim.Render(e.ID()), style.Dim.Render(fmt.Sprintf("%-8s", e.SessionID)))
+}
+
+// printSessionSummary prints the footer of a --session thread: its agent,
+// length, and whether it has ended.
+func printSessionSummary(events []townlog.Event) {
+	if len(events) == 0 {
+		return
 	}
+	first, last := events[0], events[len(events)-1]
+	state := i18n.T("log.session_open")
+	switch last.Type {
+	case townlog.EventDone, townlog.EventKill, townlog.EventCrash:
+		state = i18n.T("log.session_ended", string(last.Type))
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render(i18n.T("log.session_summary", first.SessionID, first.Agent,
+		len(events), last.Timestamp.Sub(first.Timestamp).Round(time.Second), state)))
+}
 
-	fmt.Printf("%s Following %s (Ctrl+C to stop)\n\n", style.Dim.Render("○"), logPath)
+// logEventJSON is the --json form of a town log event.
+type logEventJSON struct {
+	ID   string `json:"id"`
+	Town string `json:"town,omitempty"` // set by --all-towns
+	townlog.Event
+}
 
-	tailCmd := exec.Command("tail", "-f", logPath)
-	tailCmd.Stdout = os.Stdout
-	tailCmd.Stderr = os.Stderr
+// newLogEventJSON returns the --json form of e, with its severity spelled
+// out even when it is the type's default.
+func newLogEventJSON(town string, e townlog.Event) logEventJSON {
+	e.Severity = e.Level()
+	return logEventJSON{ID: e.ID(), Town: town, Event: e}
+}
 
-	return tailCmd.Run()
+// writeEventsJSON writes events as JSON Lines, one object per event.
+func writeEventsJSON(w io.Writer, events []townlog.Event) error {
+	enc := json.NewEncoder(w)
+	for _, e := range events {
+		if err := enc.Encode(newLogEventJSON("", e)); err != nil {
+			return fmt.Errorf("writing event: %w", err)
+		}
+	}
+	return nil
 }
 
 // printEvent prints a single event with styling.
@@ -201,6 +404,10 @@ func printEvent(e townlog.Event) {
 		typeStr = style.Warning.Render("[kill]")
 	case townlog.EventCallback:
 		typeStr = style.Bold.Render("[callback]")
+	case townlog.EventMark:
+		typeStr = style.Bold.Render("[mark]")
+	case townlog.EventNote:
+		typeStr = style.Warning.Render("[note]")
 	case townlog.EventPatrolStarted:
 		typeStr = style.Bold.Render("[patrol_started]")
 	case townlog.EventPolecatChecked:
@@ -212,86 +419,74 @@ func printEvent(e townlog.Event) {
 	case townlog.EventPatrolComplete:
 		typeStr = style.Success.Render("[patrol_complete]")
 	default:
-		typeStr = fmt.Sprintf("[%s]", e.Type)
+		// Custom types recorded with 'gt log emit', colored by severity
+		switch e.Level() {
+		case townlog.SeverityError:
+			typeStr = style.Error.Render(fmt.Sprintf("[%s]", e.Type))
+		case townlog.SeverityWarn:
+			typeStr = style.Warning.Render(fmt.Sprintf("[%s]", e.Type))
+		default:
+			typeStr = style.Info.Render(fmt.Sprintf("[%s]", e.Type))
+		}
+	}
+
+	if style.Accessible() {
+		// Without color, say how severe the event is
+		if sev := e.Level(); sev != townlog.SeverityInfo {
+			typeStr += " " + string(sev) + ":"
+		}
 	}
 
 	detail := formatEventDetail(e)
+	if !e.Usage.IsZero() {
+		detail += style.Dim.Render(" · " + formatUsage(e.Usage))
+	}
+	if e.Transcript != "" {
+		detail += style.Dim.Render(" · transcript " + e.Transcript)
+	}
 	fmt.Printf("%s %s %s %s\n", style.Dim.Render(ts), typeStr, e.Agent, detail)
 }
 
 // formatEventDetail returns a human-readable detail string for an event.
+// Known event types are localized via the "event.<type>" catalog keys.
 func formatEventDetail(e townlog.Event) string {
+	detail := plainEventDetail(e)
+	switch {
+	case e.Type == townlog.EventNote:
+		// Operator annotations stand out from agent chatter
+		return style.Bold.Render(detail)
+	case !e.Type.IsBuiltin() && e.Context == "":
+		return style.Dim.Render(detail)
+	}
+	return detail
+}
+
+// plainEventDetail is formatEventDetail without styling.
+func plainEventDetail(e townlog.Event) string {
+	key := "event." + string(e.Type)
 	switch e.Type {
-	case townlog.EventSpawn:
+	case townlog.EventSpawn, townlog.EventWake, townlog.EventHandoff, townlog.EventDone,
+		townlog.EventCrash, townlog.EventKill, townlog.EventCallback, townlog.EventMark,
+		townlog.EventPatrolStarted, townlog.EventPolecatChecked, townlog.EventPolecatNudged,
+		townlog.EventEscalationSent, townlog.EventPatrolComplete:
 		if e.Context != "" {
-			return fmt.Sprintf("spawned for %s", e.Context)
+			return i18n.T(key+"_ctx", e.Context)
 		}
-		return "spawned"
-	case townlog.EventWake:
-		if e.Context != "" {
-			return fmt.Sprintf("resumed (%s)", e.Context)
-		}
-		return "resumed"
+		return i18n.T(key)
 	case townlog.EventNudge:
 		if e.Context != "" {
-			return fmt.Sprintf("nudged with %q", truncateStr(e.Context, 40))
-		}
-		return "nudged"
-	case townlog.EventHandoff:
-		if e.Context != "" {
-			return fmt.Sprintf("handed off (%s)", e.Context)
-		}
-		return "handed off"
-	case townlog.EventDone:
-		if e.Context != "" {
-			return fmt.Sprintf("completed %s", e.Context)
-		}
-		return "completed work"
-	case townlog.EventCrash:
-		if e.Context != "" {
-			return fmt.Sprintf("exited unexpectedly (%s)", e.Context)
-		}
-		return "exited unexpectedly"
-	case townlog.EventKill:
-		if e.Context != "" {
-			return fmt.Sprintf("killed (%s)", e.Context)
-		}
-		return "killed"
-	case townlog.EventCallback:
-		if e.Context != "" {
-			return fmt.Sprintf("callback: %s", e.Context)
-		}
-		return "callback processed"
-	case townlog.EventPatrolStarted:
-		if e.Context != "" {
-			return fmt.Sprintf("started patrol (%s)", e.Context)
-		}
-		return "started patrol"
-	case townlog.EventPolecatChecked:
-		if e.Context != "" {
-			return fmt.Sprintf("checked %s", e.Context)
-		}
-		return "checked polecat"
-	case townlog.EventPolecatNudged:
-		if e.Context != "" {
-			return fmt.Sprintf("nudged (%s)", e.Context)
-		}
-		return "nudged polecat"
-	case townlog.EventEscalationSent:
-		if e.Context != "" {
-			return fmt.Sprintf("escalated (%s)", e.Context)
-		}
-		return "escalated"
-	case townlog.EventPatrolComplete:
-		if e.Context != "" {
-			return fmt.Sprintf("patrol complete (%s)", e.Context)
+			return i18n.T(key+"_ctx", truncateStr(e.Context, 40))
 		}
-		return "patrol complete"
+		return i18n.T(key)
+	case townlog.EventNote:
+		return i18n.T(key+"_ctx", e.Context)
 	default:
+		// Custom types have no phrasing of their own; the bracketed type
+		// already names the event, so show the context as given.
 		if e.Context != "" {
-			return fmt.Sprintf("%s (%s)", e.Type, e.Context)
+			return e.Context
 		}
-		return string(e.Type)
+		return "(no context)"
 	}
 }
 
@@ -343,14 +538,66 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 	}
 
 	// Log the event
-	logger := townlog.NewLogger(townRoot)
-	if err := logger.Log(eventType, crashAgent, context); err != nil {
+	if err := LogEventWithRoot(townRoot, eventType, crashAgent, context); err != nil {
 		return fmt.Errorf("logging event: %w", err)
 	}
 
+	if eventType == townlog.EventCrash {
+		_, _ = notify.Route(townRoot, &notify.Notification{
+			Event:    string(townlog.EventCrash),
+			Rig:      rigFromAgentID(crashAgent),
+			Severity: notify.SeverityHigh,
+			Source:   crashAgent,
+			Subject:  fmt.Sprintf("%s crashed", crashAgent),
+			Body:     context,
+		})
+		checkCrashLoop(townRoot, crashAgent)
+		if crashSession != "" {
+			// The town's retry policy decides for agents with work on
+			// their hook; the rig's restart policy for the others.
+			if retrying, err := startRetry(townRoot, crashSession); err != nil {
+				style.PrintWarning("could not retry the work of %s: %v", crashAgent, err)
+			} else if !retrying {
+				if err := startSupervise(townRoot, crashSession); err != nil {
+					style.PrintWarning("could not schedule restart of %s: %v", crashAgent, err)
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
+// checkCrashLoop opens a crash loop incident when an agent has crashed
+// repeatedly within the detection window. The daemon clears it once the
+// agent stays up for a full window.
+func checkCrashLoop(townRoot, agent string) {
+	events, err := townlog.ReadEvents(townRoot)
+	if err != nil {
+		return
+	}
+	crashes := 0
+	for _, e := range townlog.FilterEvents(events, townlog.Filter{
+		Type:  townlog.EventCrash,
+		Since: time.Now().Add(-notify.CrashLoopWindow),
+	}) {
+		if e.Agent == agent {
+			crashes++
+		}
+	}
+	if crashes < notify.CrashLoopThreshold {
+		return
+	}
+	_, _ = notify.Raise(townRoot, notify.CrashLoopCondition(agent), &notify.Notification{
+		Event:    notify.EventCrashLoop,
+		Rig:      rigFromAgentID(agent),
+		Severity: notify.SeverityCritical,
+		Source:   agent,
+		Subject:  fmt.Sprintf("%s is crash looping", agent),
+		Body:     fmt.Sprintf("%d crashes in the last %s", crashes, notify.CrashLoopWindow),
+	})
+}
+
 // LogEvent is a helper that logs an event from anywhere in the codebase.
 // It finds the town root and logs the event.
 func LogEvent(eventType townlog.EventType, agent, context string) error {
@@ -362,14 +609,40 @@ func LogEvent(eventType townlog.EventType, agent, context string) error {
 		return nil
 	}
 
-	logger := townlog.NewLogger(townRoot)
-	return logger.Log(eventType, agent, context)
+	return LogEventWithRoot(townRoot, eventType, agent, context)
 }
 
 // LogEventWithRoot logs an event when the town root is already known.
 func LogEventWithRoot(townRoot string, eventType townlog.EventType, agent, context string) error {
-	logger := townlog.NewLogger(townRoot)
-	return logger.Log(eventType, agent, context)
+	return logAndStartHooks(townRoot, townlog.Event{
+		Timestamp: time.Now(),
+		Type:      eventType,
+		Agent:     agent,
+		Context:   context,
+	})
+}
+
+// logAndStartHooks logs e and, for done and crash events, starts the
+// town's and rig's post_done and on_crash hooks without waiting for them.
+// Hook failures are warnings: the event is logged either way.
+func logAndStartHooks(townRoot string, e townlog.Event) error {
+	if err := townlog.NewLogger(townRoot).LogEvent(e); err != nil {
+		return err
+	}
+	var ev lifecycle.Event
+	switch e.Type {
+	case townlog.EventDone:
+		ev = lifecycle.PostDone
+	case townlog.EventCrash:
+		ev = lifecycle.OnCrash
+	default:
+		return nil
+	}
+	m := lifecycle.Meta{Event: ev, Agent: e.Agent, Context: e.Context, Time: e.Timestamp}
+	if err := lifecycle.Start(townRoot, m); err != nil {
+		style.PrintWarning("%s hooks for %s: %v", ev, e.Agent, err)
+	}
+	return nil
 }
 
 // Convenience functions for common events
@@ -389,14 +662,29 @@ func LogNudge(townRoot, agent, message string) error {
 	return LogEventWithRoot(townRoot, townlog.EventNudge, agent, strings.TrimSpace(message))
 }
 
-// LogHandoff logs a handoff event.
-func LogHandoff(townRoot, agent, context string) error {
-	return LogEventWithRoot(townRoot, townlog.EventHandoff, agent, context)
+// LogHandoff logs a handoff event, with the usage of the session handed
+// off if it is known (nil otherwise) and the path of its archived
+// transcript (empty if none).
+func LogHandoff(townRoot, agent, context string, usage *townlog.Usage, transcript string) error {
+	return logWithUsage(townRoot, townlog.EventHandoff, agent, context, usage, transcript)
+}
+
+// LogDone logs a done event, with the usage of the work if it is known
+// (nil otherwise) and the path of the session's archived transcript
+// (empty if none).
+func LogDone(townRoot, agent, issueID string, usage *townlog.Usage, transcript string) error {
+	return logWithUsage(townRoot, townlog.EventDone, agent, issueID, usage, transcript)
 }
 
-// LogDone logs a done event.
-func LogDone(townRoot, agent, issueID string) error {
-	return LogEventWithRoot(townRoot, townlog.EventDone, agent, issueID)
+func logWithUsage(townRoot string, eventType townlog.EventType, agent, context string, usage *townlog.Usage, transcript string) error {
+	return logAndStartHooks(townRoot, townlog.Event{
+		Timestamp:  time.Now(),
+		Type:       eventType,
+		Agent:      agent,
+		Context:    context,
+		Usage:      usage,
+		Transcript: transcript,
+	})
 }
 
 // LogCrash logs a crash event.
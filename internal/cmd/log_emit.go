@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	logEmitQuiet    bool
+	logEmitSeverity string
+)
+
+var logEmitCmd = &cobra.Command{
+	Use:   "emit <type> <agent> [context...]",
+	Short: "Record a custom event in the town log",
+	Long: `Record an event of your own type in the town log.
+
+Scripts and hooks use this to put their milestones (deploys, benchmark
+runs, test results) on the same timeline as agent lifecycle events, where
+'gt log --type', 'gt log -f', and 'gt log grep' can find them.
+
+The type is a lowercase name of letters, digits, '_', '-', and '.',
+starting with a letter. Built-in types (spawn, done, crash, ...) are
+accepted too, but are best left to gt. The agent is an address with no
+spaces; use "-" for the current agent. Remaining arguments form the
+context, joined onto one line.
+
+An event has its type's severity (error for crash and escalation_sent,
+warn for kill and polecat_nudged, info for the rest, custom types
+included) unless --severity says otherwise, so failures can be picked
+out with 'gt log --min-severity'.
+
+Examples:
+  gt log emit deploy gastown/crew/max "v1.4.2 to staging"
+  gt log emit bench.done - p95=212ms
+  gt log emit deploy - --severity error "v1.4.2 rollout failed"
+  gt log --type deploy,bench.done`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runLogEmit,
+}
+
+func init() {
+	logEmitCmd.Flags().StringVar(&logEmitSeverity, "severity", "", "Event severity: info, warn, or error (default: the type's)")
+	logEmitCmd.Flags().BoolVarP(&logEmitQuiet, "quiet", "q", false, "Print nothing on success")
+
+	logCmd.AddCommand(logEmitCmd)
+}
+
+func runLogEmit(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	eventType := townlog.EventType(args[0])
+	agent := args[1]
+	if agent == "-" {
+		agent = detectActor()
+	}
+	context := strings.Join(args[2:], " ")
+
+	event, err := townlog.PrepareEvent(townlog.Event{Type: eventType, Agent: agent, Context: context, Severity: townlog.Severity(logEmitSeverity)})
+	if err == nil {
+		err = logAndStartHooks(townRoot, event)
+	}
+	if err != nil {
+		return fmt.Errorf("recording event: %w", err)
+	}
+	if !logEmitQuiet {
+		fmt.Printf("%s Recorded [%s] %s\n", style.Success.Render("✓"), eventType, agent)
+	}
+	return nil
+}
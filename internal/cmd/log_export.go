@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	logExportFormat   string
+	logExportTypes    []string
+	logExportAgent    string
+	logExportAgentRe  string
+	logExportSelector string
+	logExportSince    string
+	logExportUntil    string
+	logExportArchives bool
+	logExportAll      bool
+)
+
+// logExportColumns is the CSV header. Columns are only ever added at the
+// end, so spreadsheets and scripts that read them by position keep working.
+var logExportColumns = []string{
+	"id", "timestamp", "type", "agent", "severity", "session", "context",
+	"tokens_in", "tokens_out", "cost_usd", "duration_seconds",
+}
+
+var logExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export town log events for spreadsheets and other tools",
+	Long: `Write town log events to stdout, oldest first, for loading into a
+spreadsheet or another system.
+
+Formats:
+  csv    a header row, then one row per event (default)
+  json   JSON Lines, as 'gt log --json' writes them
+
+CSV columns are, in order: id, timestamp (RFC 3339), type, agent,
+severity (always spelled out), session, context, tokens_in, tokens_out,
+cost_usd, and duration_seconds. The last four hold the usage reported with
+done and handoff events and are empty otherwise. New columns are only ever
+added at the end.
+
+The same filters as 'gt log' select the events. Only the current log is
+read unless --archives is given.
+
+Examples:
+  gt log export --format csv --since 7d > events.csv
+  gt log export --type done,crash --archives > outcomes.csv
+  gt log export --format json --agent gastown/ | jq .context`,
+	Args: cobra.NoArgs,
+	RunE: runLogExport,
+}
+
+func init() {
+	logExportCmd.Flags().StringVar(&logExportFormat, "format", "csv", "Output format: csv or json")
+	logExportCmd.Flags().StringSliceVarP(&logExportTypes, "type", "t", nil, "Only these event types (comma-separated)")
+	logExportCmd.Flags().StringVarP(&logExportAgent, "agent", "a", "", "Only agents with this prefix or glob (e.g. 'gastown/crew/*')")
+	logExportCmd.Flags().StringVar(&logExportAgentRe, "agent-re", "", "Only agents matching this regular expression")
+	logExportCmd.Flags().StringVar(&logExportSelector, "selector", "", "Only agents whose labels match this selector (e.g. team=frontend)")
+	logExportCmd.Flags().StringVar(&logExportSince, "since", "", "Only events since duration, time, or mark")
+	logExportCmd.Flags().StringVar(&logExportUntil, "until", "", "Only events up to duration ago, time, or mark")
+	logExportCmd.Flags().BoolVar(&logExportArchives, "archives", false, "Include archived logs")
+	logExportCmd.Flags().BoolVar(&logExportAll, "all", false, "Include events hidden by rig ignore rules")
+
+	logCmd.AddCommand(logExportCmd)
+}
+
+func runLogExport(cmd *cobra.Command, args []string) error {
+	var write func(io.Writer, []townlog.Event) error
+	switch logExportFormat {
+	case "csv":
+		write = writeEventsCSV
+	case "json", "jsonl":
+		write = writeEventsJSON
+	default:
+		return fmt.Errorf("invalid format %q: use csv or json", logExportFormat)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	filter := townlog.Filter{
+		Types: townlog.ParseTypes(logExportTypes...),
+	}
+	if err := applyAgentFilter(&filter, logExportAgent, logExportAgentRe); err != nil {
+		return err
+	}
+	if err := applySelectorFilter(&filter, townRoot, logExportSelector); err != nil {
+		return err
+	}
+	if logExportSince != "" {
+		if filter.Since, err = resolveTimeBoundary(townRoot, "since", logExportSince); err != nil {
+			return err
+		}
+	}
+	if logExportUntil != "" {
+		if filter.Until, err = resolveTimeBoundary(townRoot, "until", logExportUntil); err != nil {
+			return err
+		}
+	}
+	if err := checkTimeRange(filter.Since, filter.Until); err != nil {
+		return err
+	}
+
+	var events []townlog.Event
+	if logExportArchives {
+		events, err = townlog.ReadArchivedEvents(townRoot)
+		events = townlog.FilterEvents(events, filter)
+	} else {
+		events, err = townlog.QueryEvents(townRoot, filter)
+	}
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+	if !logExportAll {
+		events, _ = filterIgnoredEvents(townRoot, events)
+	}
+	return write(os.Stdout, events)
+}
+
+// writeEventsCSV writes events as CSV with a header row (see
+// logExportColumns).
+func writeEventsCSV(w io.Writer, events []townlog.Event) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write(logExportColumns)
+	for _, e := range events {
+		var tokensIn, tokensOut, cost, seconds string
+		if u := e.Usage; !u.IsZero() {
+			tokensIn = strconv.FormatInt(u.TokensIn, 10)
+			tokensOut = strconv.FormatInt(u.TokensOut, 10)
+			cost = strconv.FormatFloat(u.Cost, 'f', -1, 64)
+			seconds = strconv.FormatFloat(u.Duration.Seconds(), 'f', -1, 64)
+		}
+		_ = cw.Write([]string{
+			e.ID(), e.Timestamp.Format(time.RFC3339), string(e.Type), e.Agent,
+			string(e.Level()), e.SessionID, e.Context,
+			tokensIn, tokensOut, cost, seconds,
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("writing events: %w", err)
+	}
+	return nil
+}
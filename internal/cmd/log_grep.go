@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/i18n"
+	"github.com/ctiospl/gastown/internal/labels"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	logGrepIgnoreCase bool
+	logGrepFixed      bool
+	logGrepTypes      []string
+	logGrepAgent      string
+	logGrepAgentRe    string
+	logGrepSelector   string
+	logGrepSince      string
+	logGrepUntil      string
+	logGrepSeverity   string
+	logGrepCurrent    bool
+	logGrepMax        int
+	logGrepCount      bool
+	logGrepIDs        bool
+	logGrepJSON       bool
+)
+
+var logGrepCmd = &cobra.Command{
+	Use:   "grep <pattern>",
+	Short: "Search event context with a regular expression",
+	Long: `Search the context of town log events (issue IDs, branch names, messages).
+
+The pattern is a Go regular expression (RE2 syntax) matched against each
+event's context. Archived logs are searched too, oldest first, so results
+cover the town's whole history. Rig ignore rules do not apply: a search
+shows every match.
+
+Examples:
+  gt log grep gt-abc                     # Every event mentioning gt-abc
+  gt log grep 'polecat/nux-\d+'          # Branch names
+  gt log grep -i 'timeout|deadline' --type crash,kill
+  gt log grep gt-abc --since sprint-1 --agent gastown/
+  gt log grep gt-abc --min-severity warn  # Only kills, crashes, escalations
+  gt log grep -F 'a.b(c)' -c             # Literal text, count only
+  gt log grep gt-abc --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogGrep,
+}
+
+func init() {
+	logGrepCmd.Flags().BoolVarP(&logGrepIgnoreCase, "ignore-case", "i", false, "Case-insensitive match")
+	logGrepCmd.Flags().BoolVarP(&logGrepFixed, "fixed-strings", "F", false, "Treat the pattern as literal text")
+	logGrepCmd.Flags().StringSliceVarP(&logGrepTypes, "type", "t", nil, "Only these event types (comma-separated)")
+	logGrepCmd.Flags().StringVarP(&logGrepAgent, "agent", "a", "", "Only agents with this prefix or glob (e.g. 'gastown/crew/*')")
+	logGrepCmd.Flags().StringVar(&logGrepAgentRe, "agent-re", "", "Only agents matching this regular expression")
+	logGrepCmd.Flags().StringVar(&logGrepSelector, "selector", "", "Only agents whose labels match this selector (e.g. team=frontend)")
+	logGrepCmd.Flags().StringVar(&logGrepSince, "since", "", "Only events since duration, time, or mark")
+	logGrepCmd.Flags().StringVar(&logGrepUntil, "until", "", "Only events up to duration ago, time, or mark")
+	logGrepCmd.Flags().StringVar(&logGrepSeverity, "min-severity", "", "Only events at least this severe (info, warn, error)")
+	logGrepCmd.Flags().BoolVar(&logGrepCurrent, "current", false, "Search only the current log, not archives")
+	logGrepCmd.Flags().IntVarP(&logGrepMax, "max", "n", 0, "Show at most the last N matches (0 for all)")
+	logGrepCmd.Flags().BoolVarP(&logGrepCount, "count", "c", false, "Print only the number of matches")
+	logGrepCmd.Flags().BoolVar(&logGrepIDs, "ids", false, "Prefix each event with its ID (for 'gt explain')")
+	logGrepCmd.Flags().BoolVar(&logGrepJSON, "json", false, "Output one JSON object per event (JSON Lines)")
+
+	logCmd.AddCommand(logGrepCmd)
+}
+
+func runLogGrep(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	pattern, err := compileLogPattern(args[0], logGrepFixed, logGrepIgnoreCase)
+	if err != nil {
+		return err
+	}
+	filter := townlog.Filter{
+		Types:   townlog.ParseTypes(logGrepTypes...),
+		Pattern: pattern,
+	}
+	if err := applyAgentFilter(&filter, logGrepAgent, logGrepAgentRe); err != nil {
+		return err
+	}
+	if err := applySelectorFilter(&filter, townRoot, logGrepSelector); err != nil {
+		return err
+	}
+	if err := applySeverityFilter(&filter, logGrepSeverity); err != nil {
+		return err
+	}
+	if logGrepSince != "" {
+		if filter.Since, err = resolveTimeBoundary(townRoot, "since", logGrepSince); err != nil {
+			return err
+		}
+	}
+	if logGrepUntil != "" {
+		if filter.Until, err = resolveTimeBoundary(townRoot, "until", logGrepUntil); err != nil {
+			return err
+		}
+	}
+	if err := checkTimeRange(filter.Since, filter.Until); err != nil {
+		return err
+	}
+
+	var matches []townlog.Event
+	if logGrepCurrent {
+		matches, err = townlog.QueryEvents(townRoot, filter)
+	} else {
+		var events []townlog.Event
+		events, err = townlog.ReadArchivedEvents(townRoot)
+		matches = townlog.FilterEvents(events, filter)
+	}
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+
+	if logGrepCount {
+		fmt.Println(len(matches))
+		return nil
+	}
+	if logGrepMax > 0 && len(matches) > logGrepMax {
+		matches = matches[len(matches)-logGrepMax:]
+	}
+
+	if logGrepJSON {
+		return writeEventsJSON(os.Stdout, matches)
+	}
+	if len(matches) == 0 {
+		fmt.Printf("%s %s\n", style.Dim.Render("○"), i18n.T("log.no_match"))
+		return nil
+	}
+	for _, e := range matches {
+		if logGrepIDs {
+			fmt.Printf("%s ", style.Dim.Render(e.ID()))
+		}
+		printEvent(e)
+	}
+	return nil
+}
+
+// compileLogPattern builds the regexp for a log search.
+func compileLogPattern(pattern string, fixed, ignoreCase bool) (*regexp.Regexp, error) {
+	if fixed {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return re, nil
+}
+
+// applyAgentFilter sets a filter's agent criteria: agent is a prefix or a
+// glob (see townlog.MatchAgent), agentRe a regular expression searched in
+// the agent ID.
+func applyAgentFilter(f *townlog.Filter, agent, agentRe string) error {
+	if agent != "" {
+		if err := townlog.ValidateAgentGlob(agent); err != nil {
+			return err
+		}
+		f.Agent = agent
+	}
+	if agentRe != "" {
+		re, err := regexp.Compile(agentRe)
+		if err != nil {
+			return fmt.Errorf("invalid agent pattern: %w", err)
+		}
+		f.AgentPattern = re
+	}
+	return nil
+}
+
+// applySelectorFilter narrows a filter to the agents whose labels match
+// selector (see gt spawn --label); an empty selector leaves it alone.
+func applySelectorFilter(f *townlog.Filter, townRoot, selector string) error {
+	if selector == "" {
+		return nil
+	}
+	agents, err := selectAgents(townRoot, selector)
+	if err != nil {
+		return err
+	}
+	f.AgentKeys = make(map[string]bool, len(agents))
+	for _, a := range agents {
+		f.AgentKeys[townlog.AgentKey(a)] = true
+	}
+	return nil
+}
+
+// selectAgents returns the addresses of the agents whose labels match
+// selector.
+func selectAgents(townRoot, selector string) ([]string, error) {
+	sel, err := labels.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	store, err := labels.Load(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("loading agent labels: %w", err)
+	}
+	return store.Select(sel), nil
+}
+
+// applySeverityFilter sets a filter's minimum severity (empty for all).
+func applySeverityFilter(f *townlog.Filter, minSeverity string) error {
+	if minSeverity == "" {
+		return nil
+	}
+	sev, err := townlog.ParseSeverity(minSeverity)
+	if err != nil {
+		return err
+	}
+	f.MinSeverity = sev
+	return nil
+}
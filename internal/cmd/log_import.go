@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	logImportAgent       string
+	logImportSkipInvalid bool
+	logImportDryRun      bool
+	logImportQuiet       bool
+)
+
+var logImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import events from other systems into the town log",
+	Long: `Import events from CI runs, deploy bots, and other systems into the town
+log, so it is the one timeline of everything that happened.
+
+Events are read as JSON Lines from the file, or from stdin if there is
+none or it is "-". Each line is an object:
+
+  {"timestamp": "2026-03-01T14:05:00Z", "type": "ci.failed",
+   "agent": "ci/github", "context": "main #812", "severity": "error"}
+
+type and agent are required and follow the rules of 'gt log emit'.
+timestamp (RFC 3339) defaults to the time of the import, and severity to
+the type's. Other fields are ignored, so the output of 'gt log --json'
+imports as is. Blank lines and lines starting with '#' are skipped.
+
+Every line is checked before anything is recorded: with an invalid line,
+nothing is imported unless --skip-invalid is given. Events already in the
+log, and repeats within the input, are skipped (an event is the same if it
+has the same time to the second, type, agent, and context), so importing a
+file twice is harmless. Imported events keep their timestamps and are
+recorded oldest first.
+
+Examples:
+  ci-events | gt log import
+  gt log import deploys.jsonl --dry-run
+  gt log import --agent ci/jenkins builds.jsonl
+  other-town$ gt log --json --type deploy > deploys.jsonl`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLogImport,
+}
+
+func init() {
+	logImportCmd.Flags().StringVar(&logImportAgent, "agent", "", "Agent for events that do not name one (e.g. ci/github)")
+	logImportCmd.Flags().BoolVar(&logImportSkipInvalid, "skip-invalid", false, "Import the valid lines even if some are invalid")
+	logImportCmd.Flags().BoolVarP(&logImportDryRun, "dry-run", "n", false, "Check the input and count new events without recording them")
+	logImportCmd.Flags().BoolVarP(&logImportQuiet, "quiet", "q", false, "Print nothing on success")
+
+	logCmd.AddCommand(logImportCmd)
+}
+
+// logImportRecord is one line of import input.
+type logImportRecord struct {
+	Timestamp *time.Time     `json:"timestamp"`
+	Type      string         `json:"type"`
+	Agent     string         `json:"agent"`
+	Context   string         `json:"context"`
+	Severity  string         `json:"severity"`
+	Usage     *townlog.Usage `json:"usage"`
+}
+
+// logImportError is an invalid line of import input.
+type logImportError struct {
+	Line int
+	Err  error
+}
+
+func runLogImport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	in, name := io.Reader(os.Stdin), "stdin"
+	if len(args) == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("opening input: %w", err)
+		}
+		defer f.Close()
+		in, name = f, args[0]
+	}
+	events, invalid, err := parseLogImport(in, logImportAgent)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+	cmd.SilenceUsage = true
+
+	for _, bad := range invalid {
+		fmt.Fprintf(os.Stderr, "%s %s:%d: %v\n", style.Error.Render("✗"), name, bad.Line, bad.Err)
+	}
+	if len(invalid) > 0 && !logImportSkipInvalid {
+		return fmt.Errorf("%d invalid line(s) in %s; nothing imported (use --skip-invalid to import the rest)", len(invalid), name)
+	}
+
+	if logImportDryRun {
+		fresh, err := townlog.Unlogged(townRoot, events)
+		if err != nil {
+			return fmt.Errorf("reading events: %w", err)
+		}
+		fmt.Printf("%s %d event(s) would be imported, %d already logged\n",
+			style.Dim.Render("○"), len(fresh), len(events)-len(fresh))
+		return nil
+	}
+
+	added, err := townlog.NewLogger(townRoot).Import(events)
+	if err != nil {
+		return fmt.Errorf("importing events: %w", err)
+	}
+	if !logImportQuiet {
+		fmt.Printf("%s Imported %d event(s) from %s", style.Success.Render("✓"), added, name)
+		if skipped := len(events) - added; skipped > 0 {
+			fmt.Printf(", %d already logged", skipped)
+		}
+		fmt.Println()
+	}
+	if len(invalid) > 0 {
+		return partialFailure("%d invalid line(s) in %s were not imported", len(invalid), name)
+	}
+	return nil
+}
+
+// parseLogImport reads import input, returning the valid events and the
+// invalid lines. defaultAgent is used for records without an agent.
+func parseLogImport(r io.Reader, defaultAgent string) ([]townlog.Event, []logImportError, error) {
+	var events []townlog.Event
+	var invalid []logImportError
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var rec logImportRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			invalid = append(invalid, logImportError{n, fmt.Errorf("not a JSON object: %w", err)})
+			continue
+		}
+		e := townlog.Event{
+			Type:     townlog.EventType(rec.Type),
+			Agent:    rec.Agent,
+			Context:  rec.Context,
+			Severity: townlog.Severity(rec.Severity),
+			Usage:    rec.Usage,
+		}
+		if e.Agent == "" {
+			e.Agent = defaultAgent
+		}
+		if rec.Timestamp != nil {
+			e.Timestamp = *rec.Timestamp
+		}
+		e, err := townlog.PrepareEvent(e)
+		if err != nil {
+			invalid = append(invalid, logImportError{n, err})
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, invalid, sc.Err()
+}
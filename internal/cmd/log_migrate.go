@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	logMigrateArchives bool
+	logMigrateTo       string
+)
+
+var logMigrateCmd = &cobra.Command{
+	Use:   "migrate [file...]",
+	Short: "Move the town log into a SQLite event store or log segments",
+	Long: `Create the town's SQLite event store (logs/town.db) and import town.log.
+
+The flat-file town.log gets slow to read once it holds tens of thousands of
+events. The SQLite store indexes events by time, agent, and type, so
+filtered 'gt log' queries stay fast. Once logs/town.db exists, events are
+written to it instead of town.log, and every reader (gt log, gt log -f,
+gt log grep, gt explain, ...) uses it. town.log is kept, unchanged, as a
+record of what was imported.
+
+Requires the sqlite3 command wherever gt runs, including the daemon's
+service (its PATH is recorded by gt daemon install). Once logs/town.db
+exists, gt without sqlite3 fails to log or read events rather than
+writing them to town.log.
+
+Towns with hundreds of agents can use binary log segments instead
+(--to segments): compact length-prefixed records in logs/segments/, with
+older segments sealed and gzipped. Appends are a single write and reads
+skip segments outside the requested time range. They need no external
+command. A town on the SQLite store that moves to segments has its stored
+events carried over. 'gt log' output is the same with every backend.
+
+Migrating is idempotent: events already in the store are skipped, so it is
+safe to run again or to import further files later.
+
+Examples:
+  gt log migrate                  # Import town.log
+  gt log migrate --archives       # Also import rotated and archived logs
+  gt log migrate old/town.log.gz  # Import specific files
+  gt log migrate --to segments    # Use binary log segments instead`,
+	RunE: runLogMigrate,
+}
+
+func init() {
+	logMigrateCmd.Flags().BoolVar(&logMigrateArchives, "archives", false, "Also import rotated and archived logs")
+	logMigrateCmd.Flags().StringVar(&logMigrateTo, "to", "sqlite", "Store to migrate to: sqlite or segments")
+
+	logCmd.AddCommand(logMigrateCmd)
+}
+
+func runLogMigrate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	paths := args
+	if len(paths) == 0 {
+		if logMigrateArchives {
+			paths = append(paths, townlog.ArchivePaths(townRoot)...)
+		}
+		current := filepath.Join(townRoot, "logs", "town.log")
+		if _, err := os.Stat(current); err == nil {
+			paths = append(paths, current)
+		}
+	}
+
+	switch logMigrateTo {
+	case "sqlite":
+		if townlog.UsesSegments(townRoot) {
+			return fmt.Errorf("town already logs to %s, which takes precedence over SQLite", townlog.LogLocation(townRoot))
+		}
+	case "segments":
+		return migrateToSegments(townRoot, paths)
+	default:
+		return fmt.Errorf("unknown store %q (want sqlite or segments)", logMigrateTo)
+	}
+
+	if err := townlog.CreateStore(townRoot); err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		n, err := townlog.ImportLog(townRoot, path)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Imported %d events from %s\n", style.Success.Render("✓"), n, path)
+	}
+
+	total, err := townlog.CountStoredEvents(townRoot)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s %s holds %d events; the town now logs to it\n",
+		style.Bold.Render("●"), townlog.DBPath(townRoot), total)
+	return nil
+}
+
+// migrateToSegments creates the town's log segments and imports paths into
+// them, along with the SQLite store's events when the town has one.
+func migrateToSegments(townRoot string, paths []string) error {
+	var stored []townlog.Event
+	if townlog.UsesSQLite(townRoot) && !townlog.UsesSegments(townRoot) {
+		var err error
+		if stored, err = townlog.ReadEvents(townRoot); err != nil {
+			return err
+		}
+	}
+
+	if err := townlog.CreateSegments(townRoot); err != nil {
+		return err
+	}
+
+	if len(stored) > 0 {
+		if err := townlog.AppendSegments(townRoot, stored); err != nil {
+			return err
+		}
+		fmt.Printf("%s Imported %d events from %s\n", style.Success.Render("✓"), len(stored), townlog.DBPath(townRoot))
+	}
+	for _, path := range paths {
+		n, err := townlog.ImportSegments(townRoot, path)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Imported %d events from %s\n", style.Success.Render("✓"), n, path)
+	}
+
+	total, err := townlog.CountSegmentEvents(townRoot)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s %s holds %d events; the town now logs to it\n",
+		style.Bold.Render("●"), townlog.LogLocation(townRoot), total)
+	return nil
+}
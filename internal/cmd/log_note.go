@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	logNoteSince    string
+	logNoteUntil    string
+	logNoteTypes    []string
+	logNoteAgent    string
+	logNoteAgentRe  string
+	logNoteSelector string
+)
+
+var logNoteCmd = &cobra.Command{
+	Use:   "note [<event-id>[,<event-id>...]] <text...>",
+	Short: "Annotate the town log",
+	Long: `Write a human annotation into the town log, or attach one to past events.
+
+During an incident, note what you did ("restarted tmux server here") so
+the timeline later shows why agents behaved the way they did. Notes are
+rendered distinctly in 'gt log' and can be listed with 'gt log --type note'.
+Arguments are joined into one note.
+
+Afterwards, attach what you learned to the events themselves:
+
+  - an event ID (see 'gt log --ids'), or several joined by commas,
+    annotates those events;
+  - --since (and --until, default now) annotates a time range;
+  - --since with --type, --agent, or --agent-re annotates every matching
+    event in the range.
+
+Annotations are stored beside the log (logs/annotations.jsonl) and shown
+under their events in 'gt log', 'gt log report', and 'gt explain'.
+
+Examples:
+  gt log note "restarted tmux server here"
+  gt log note rolled back the config change
+  gt log --type note --since 24h
+  gt log note 3f9a2c01 "this crash was the OOM bug"
+  gt log note --since 14:00 --until 15:30 "deploy window: expect restarts"
+  gt log note --since 24h --type crash --agent 'gastown/*' "OOM bug, fixed in gt-4f2"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runLogNote,
+}
+
+func init() {
+	logNoteCmd.Flags().StringVar(&logNoteSince, "since", "", "Annotate events from this duration ago, time, or mark")
+	logNoteCmd.Flags().StringVar(&logNoteUntil, "until", "", "End of the annotated range: duration ago, time, or mark (default: now)")
+	logNoteCmd.Flags().StringSliceVarP(&logNoteTypes, "type", "t", nil, "Annotate only events of these types in the range")
+	logNoteCmd.Flags().StringVarP(&logNoteAgent, "agent", "a", "", "Annotate only events of agents with this prefix or glob in the range")
+	logNoteCmd.Flags().StringVar(&logNoteAgentRe, "agent-re", "", "Annotate only events of agents matching this regular expression in the range")
+	logNoteCmd.Flags().StringVar(&logNoteSelector, "selector", "", "Annotate only events of agents whose labels match this selector")
+	logCmd.AddCommand(logNoteCmd)
+}
+
+// eventIDsArg matches an argument made of event IDs (or ID prefixes).
+var eventIDsArg = regexp.MustCompile(`^[0-9a-f]{4,8}(,[0-9a-f]{4,8})*$`)
+
+func runLogNote(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	actor := detectActor()
+	if actor == "" || strings.ContainsAny(actor, " \t") {
+		actor = "overseer"
+	}
+
+	filtered := len(logNoteTypes) > 0 || logNoteAgent != "" || logNoteAgentRe != "" || logNoteSelector != ""
+	if logNoteSince == "" && (logNoteUntil != "" || filtered) {
+		return fmt.Errorf("--until, --type, and --agent select events in a range: give --since too")
+	}
+	if logNoteSince != "" {
+		return annotateRange(townRoot, actor, noteText(args), filtered)
+	}
+
+	if len(args) > 1 && eventIDsArg.MatchString(args[0]) {
+		ids, err := findNoteEvents(townRoot, args[0])
+		if err == nil {
+			return annotate(townRoot, townlog.Annotation{By: actor, Text: noteText(args[1:]), Events: ids})
+		}
+		// A word such as "added" or "bead" looks like an ID too; only one
+		// with a digit in it is taken to be a mistyped one.
+		if strings.ContainsAny(args[0], "0123456789") {
+			return err
+		}
+	}
+
+	text := noteText(args)
+	if text == "" {
+		return fmt.Errorf("note text cannot be empty")
+	}
+	if err := townlog.NewLogger(townRoot).Log(townlog.EventNote, actor, text); err != nil {
+		return fmt.Errorf("writing note: %w", err)
+	}
+
+	fmt.Printf("%s Noted in the town log\n", style.SuccessPrefix)
+	return nil
+}
+
+// findNoteEvents resolves a comma-separated list of event IDs or ID
+// prefixes to full event IDs.
+func findNoteEvents(townRoot, arg string) ([]string, error) {
+	events, err := townlog.ReadArchivedEvents(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading events: %w", err)
+	}
+	var ids []string
+	for _, prefix := range strings.Split(arg, ",") {
+		i, err := townlog.FindEvent(events, prefix)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, events[i].ID())
+	}
+	return ids, nil
+}
+
+// annotateRange annotates the --since..--until range, or with filtered,
+// every event in it that matches the filter flags.
+func annotateRange(townRoot, actor, text string, filtered bool) error {
+	from, err := resolveTimeBoundary(townRoot, "since", logNoteSince)
+	if err != nil {
+		return err
+	}
+	to := time.Now()
+	if logNoteUntil != "" {
+		if to, err = resolveTimeBoundary(townRoot, "until", logNoteUntil); err != nil {
+			return err
+		}
+	}
+	if err := checkTimeRange(from, to); err != nil {
+		return err
+	}
+	if !filtered {
+		return annotate(townRoot, townlog.Annotation{By: actor, Text: text, From: from, To: to})
+	}
+
+	filter := townlog.Filter{Types: townlog.ParseTypes(logNoteTypes...), Since: from, Until: to}
+	if err := applyAgentFilter(&filter, logNoteAgent, logNoteAgentRe); err != nil {
+		return err
+	}
+	if err := applySelectorFilter(&filter, townRoot, logNoteSelector); err != nil {
+		return err
+	}
+	events, err := townlog.ReadArchivedEvents(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+	var ids []string
+	for _, e := range townlog.FilterEvents(events, filter) {
+		ids = append(ids, e.ID())
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("no events match in that range; nothing annotated")
+	}
+	return annotate(townRoot, townlog.Annotation{By: actor, Text: text, Events: ids})
+}
+
+// annotate stores a and reports what it covers.
+func annotate(townRoot string, a townlog.Annotation) error {
+	if a.Text == "" {
+		return fmt.Errorf("note text cannot be empty")
+	}
+	if err := townlog.Annotate(townRoot, a); err != nil {
+		return err
+	}
+	switch len(a.Events) {
+	case 0:
+		fmt.Printf("%s Annotated %s → %s\n", style.SuccessPrefix,
+			a.From.Local().Format("2006-01-02 15:04"), a.To.Local().Format("2006-01-02 15:04"))
+	case 1:
+		fmt.Printf("%s Annotated event %s\n", style.SuccessPrefix, a.Events[0])
+	default:
+		fmt.Printf("%s Annotated %d events\n", style.SuccessPrefix, len(a.Events))
+	}
+	return nil
+}
+
+// noteText joins note arguments into one line.
+func noteText(args []string) string {
+	return strings.Join(strings.Fields(strings.Join(args, " ")), " ")
+}
+
+// printAnnotations prints the annotations attached to an event, indented
+// under it.
+func printAnnotations(notes []townlog.Annotation) {
+	for _, a := range notes {
+		line := a.By + ": " + a.Text
+		if a.IsRange() {
+			line += fmt.Sprintf(" (%s → %s)", a.From.Local().Format("01-02 15:04"), a.To.Local().Format("01-02 15:04"))
+		}
+		fmt.Printf("    %s %s\n", style.Warning.Render("✎"), line)
+	}
+}
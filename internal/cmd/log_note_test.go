@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+// noteTown returns a town to run gt log note in, as gastown/crew/max.
+func noteTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town","version":1,"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+	t.Setenv("GT_ROLE", "gastown/crew/max")
+	t.Cleanup(func() {
+		logNoteSince, logNoteUntil, logNoteTypes, logNoteAgent, logNoteAgentRe, logNoteSelector = "", "", nil, "", "", ""
+	})
+	return townRoot
+}
+
+func TestLogNote(t *testing.T) {
+	townRoot := noteTown(t)
+
+	if err := runLogNote(nil, []string{"restarted", " tmux  server", "here"}); err != nil {
+		t.Fatal(err)
+	}
+	events, err := townlog.ReadEvents(townRoot)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("events = %v, %v", events, err)
+	}
+	if e := events[0]; e.Type != townlog.EventNote || e.Agent != "gastown/crew/max" || e.Context != "restarted tmux server here" {
+		t.Errorf("stored note = %+v", e)
+	}
+}
+
+func TestLogNoteEmpty(t *testing.T) {
+	townRoot := noteTown(t)
+
+	if err := runLogNote(nil, []string{"  ", "\t"}); err == nil || !strings.Contains(err.Error(), "cannot be empty") {
+		t.Errorf("empty note: %v", err)
+	}
+	logNoteSince = "1h"
+	if err := runLogNote(nil, []string{" "}); err == nil || !strings.Contains(err.Error(), "cannot be empty") {
+		t.Errorf("empty range note: %v", err)
+	}
+	if townlog.HasLog(townRoot) {
+		t.Error("empty note logged")
+	}
+	if notes, _ := townlog.ReadAnnotations(townRoot); len(notes) != 0 {
+		t.Errorf("empty note annotated: %v", notes)
+	}
+}
+
+func TestLogNoteAnnotatesAgentsEvents(t *testing.T) {
+	townRoot := noteTown(t)
+	logger := townlog.NewLogger(townRoot)
+	_ = logger.Log(townlog.EventCrash, "gastown/crew/ada", "oom")
+	_ = logger.Log(townlog.EventCrash, "beads/crew/joe", "oom")
+	events, err := townlog.ReadEvents(townRoot)
+	if err != nil || len(events) != 2 {
+		t.Fatalf("events = %v, %v", events, err)
+	}
+
+	logNoteAgent = "gastown/*"
+	if err := runLogNote(nil, []string{"OOM bug"}); err == nil {
+		t.Error("--agent without --since succeeded")
+	}
+	logNoteSince = "1h"
+	if err := runLogNote(nil, []string{"OOM", "bug"}); err != nil {
+		t.Fatal(err)
+	}
+	notes, err := townlog.ReadAnnotations(townRoot)
+	if err != nil || len(notes) != 1 {
+		t.Fatalf("annotations = %v, %v", notes, err)
+	}
+	a := notes[0]
+	if a.By != "gastown/crew/max" || a.Text != "OOM bug" || a.IsRange() {
+		t.Errorf("annotation = %+v", a)
+	}
+	if len(a.Events) != 1 || a.Events[0] != events[0].ID() {
+		t.Errorf("annotated %v, want only %s's crash %s", a.Events, events[0].Agent, events[0].ID())
+	}
+}
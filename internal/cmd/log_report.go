@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/beads"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/web"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	logReportFormat   string
+	logReportOutput   string
+	logReportTitle    string
+	logReportIssueURL string
+	logReportTypes    []string
+	logReportAgent    string
+	logReportAgentRe  string
+	logReportSelector string
+	logReportSince    string
+	logReportUntil    string
+	logReportSeverity string
+	logReportArchives bool
+	logReportAll      bool
+)
+
+var logReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Render town log activity as a Markdown or HTML report",
+	Long: `Render town log events as an activity report grouped by agent, for
+pasting into a PR description or sharing with the team.
+
+The report opens with a summary table (events, spawns, dones and crashes
+per agent) linking to one section per agent, with that agent's events in
+order. With --issue-url, issue IDs in event details (gt-abc, hq-xyz: any
+prefix routed in the town's beads) link to your tracker; {id} in the URL
+is replaced by the issue ID.
+
+The same filters as 'gt log' select the events; the default window is the
+last 24 hours.
+
+Examples:
+  gt log report                                # Markdown, last 24h
+  gt log report --format html -o report.html
+  gt log report --since sprint-1 --agent gastown/ | pbcopy
+  gt log report --issue-url 'https://github.com/org/repo/issues?q={id}'
+  gt log report --min-severity warn --since 7d --archives`,
+	Args: cobra.NoArgs,
+	RunE: runLogReport,
+}
+
+func init() {
+	logReportCmd.Flags().StringVar(&logReportFormat, "format", "md", "Report format: md or html")
+	logReportCmd.Flags().StringVarP(&logReportOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	logReportCmd.Flags().StringVar(&logReportTitle, "title", "", "Report title (default: Gas Town activity: <town>)")
+	logReportCmd.Flags().StringVar(&logReportIssueURL, "issue-url", "", "Link issue IDs to this URL; {id} is replaced by the ID")
+	logReportCmd.Flags().StringSliceVarP(&logReportTypes, "type", "t", nil, "Only these event types (comma-separated)")
+	logReportCmd.Flags().StringVarP(&logReportAgent, "agent", "a", "", "Only agents with this prefix or glob (e.g. 'gastown/crew/*')")
+	logReportCmd.Flags().StringVar(&logReportAgentRe, "agent-re", "", "Only agents matching this regular expression")
+	logReportCmd.Flags().StringVar(&logReportSelector, "selector", "", "Only agents whose labels match this selector (e.g. team=frontend)")
+	logReportCmd.Flags().StringVar(&logReportSince, "since", "24h", "Only events since duration, time, or mark")
+	logReportCmd.Flags().StringVar(&logReportUntil, "until", "", "Only events up to duration ago, time, or mark")
+	logReportCmd.Flags().StringVar(&logReportSeverity, "min-severity", "", "Only events at least this severe (info, warn, error)")
+	logReportCmd.Flags().BoolVar(&logReportArchives, "archives", false, "Include archived logs")
+	logReportCmd.Flags().BoolVar(&logReportAll, "all", false, "Include events hidden by rig ignore rules")
+
+	logCmd.AddCommand(logReportCmd)
+}
+
+func runLogReport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if logReportFormat != "md" && logReportFormat != "html" {
+		return fmt.Errorf("invalid --format %q: use md or html", logReportFormat)
+	}
+	if logReportIssueURL != "" && !strings.Contains(logReportIssueURL, "{id}") {
+		return fmt.Errorf("--issue-url must contain {id}")
+	}
+
+	filter := townlog.Filter{Types: townlog.ParseTypes(logReportTypes...)}
+	if err := applyAgentFilter(&filter, logReportAgent, logReportAgentRe); err != nil {
+		return err
+	}
+	if err := applySelectorFilter(&filter, townRoot, logReportSelector); err != nil {
+		return err
+	}
+	if err := applySeverityFilter(&filter, logReportSeverity); err != nil {
+		return err
+	}
+	if logReportSince != "" {
+		if filter.Since, err = resolveTimeBoundary(townRoot, "since", logReportSince); err != nil {
+			return err
+		}
+	}
+	if logReportUntil != "" {
+		if filter.Until, err = resolveTimeBoundary(townRoot, "until", logReportUntil); err != nil {
+			return err
+		}
+	}
+	if err := checkTimeRange(filter.Since, filter.Until); err != nil {
+		return err
+	}
+
+	var events []townlog.Event
+	if logReportArchives {
+		events, err = townlog.ReadArchivedEvents(townRoot)
+		events = townlog.FilterEvents(events, filter)
+	} else {
+		events, err = townlog.QueryEvents(townRoot, filter)
+	}
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+	if !logReportAll {
+		events, _ = filterIgnoredEvents(townRoot, events)
+	}
+
+	opts := logReportOptions{
+		Title:    logReportTitle,
+		Since:    filter.Since,
+		Until:    filter.Until,
+		IssueURL: logReportIssueURL,
+	}
+	if opts.Title == "" {
+		name, err := workspace.GetTownName(townRoot)
+		if err != nil || name == "" {
+			name = filepath.Base(townRoot)
+		}
+		opts.Title = "Gas Town activity: " + name
+	}
+	if opts.Until.IsZero() {
+		opts.Until = time.Now()
+	}
+	if opts.IssueURL != "" {
+		opts.Issues = reportIssuePattern(townRoot)
+	}
+	opts.Notes, _ = townlog.ReadAnnotations(townRoot)
+	report := buildLogReport(events, opts)
+
+	out := io.Writer(os.Stdout)
+	if logReportOutput != "" {
+		f, err := os.Create(logReportOutput)
+		if err != nil {
+			return fmt.Errorf("creating report: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if logReportFormat == "html" {
+		tmpl, err := web.LoadTemplates()
+		if err != nil {
+			return fmt.Errorf("loading template: %w", err)
+		}
+		err = tmpl.ExecuteTemplate(out, "report.html", report)
+	} else {
+		err = writeMarkdownReport(out, report)
+	}
+	if err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	if logReportOutput != "" {
+		fmt.Printf("%s Wrote %d events to %s\n", style.Success.Render("✓"), report.Events, logReportOutput)
+	}
+	return nil
+}
+
+// logReportOptions control how buildLogReport renders events.
+type logReportOptions struct {
+	Title    string
+	Since    time.Time      // zero for the first event
+	Until    time.Time      // end of the window
+	Issues   *regexp.Regexp // issue IDs to link (nil for none)
+	IssueURL string         // with {id} for the issue ID
+	Notes    []townlog.Annotation
+}
+
+// buildLogReport groups events, oldest first, by agent.
+func buildLogReport(events []townlog.Event, opts logReportOptions) web.ReportData {
+	stats := computeLogStats(events)
+	since := opts.Since
+	if since.IsZero() {
+		since = stats.First
+	}
+	report := web.ReportData{
+		Title:   opts.Title,
+		Window:  since.Local().Format("2006-01-02 15:04") + " → " + opts.Until.Local().Format("2006-01-02 15:04"),
+		Events:  stats.Events,
+		Crashes: stats.Crashes,
+	}
+
+	// Times within one day are shown without the date.
+	timeFormat := "15:04"
+	if stats.First.Local().Format("2006-01-02") != stats.Last.Local().Format("2006-01-02") {
+		timeFormat = "01-02 15:04"
+	}
+
+	index := make(map[string]int, len(stats.ByAgent))
+	anchors := make(map[string]int)
+	for i, a := range stats.ByAgent {
+		index[a.Agent] = i
+		report.Agents = append(report.Agents, web.ReportAgent{
+			Agent:   a.Agent,
+			Anchor:  reportAnchor(a.Agent, anchors),
+			Spawns:  a.Spawns,
+			Done:    a.Done,
+			Crashes: a.Crashes,
+		})
+	}
+	attached := townlog.AttachAnnotations(events, opts.Notes)
+	for i, e := range events {
+		section := &report.Agents[index[e.Agent]]
+		entry := web.ReportEntry{
+			Time:     e.Timestamp.Local().Format(timeFormat),
+			Type:     string(e.Type),
+			Severity: string(e.Level()),
+			Detail:   reportSpans(strings.Join(strings.Fields(plainEventDetail(e)), " "), opts.Issues, opts.IssueURL),
+		}
+		for _, a := range attached[i] {
+			entry.Notes = append(entry.Notes, a.By+": "+a.Text)
+		}
+		section.Entries = append(section.Entries, entry)
+	}
+	return report
+}
+
+// reportAnchor returns a GitHub-style heading anchor for agent, unique
+// among those issued so far (seen counts them).
+func reportAnchor(agent string, seen map[string]int) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(agent) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	anchor := b.String()
+	if n := seen[anchor]; n > 0 {
+		seen[anchor] = n + 1
+		return fmt.Sprintf("%s-%d", anchor, n)
+	}
+	seen[anchor] = 1
+	return anchor
+}
+
+// reportIssuePattern matches issue IDs with the prefixes routed in the
+// town's beads, or returns nil if there are none.
+func reportIssuePattern(townRoot string) *regexp.Regexp {
+	routes, _ := beads.LoadRoutes(filepath.Join(townRoot, ".beads"))
+	var prefixes []string
+	for _, r := range routes {
+		if p := strings.TrimSuffix(r.Prefix, "-"); p != "" {
+			prefixes = append(prefixes, regexp.QuoteMeta(p))
+		}
+	}
+	if len(prefixes) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`\b(?:` + strings.Join(prefixes, "|") + `)-[a-z0-9]+(?:\.[0-9]+)*\b`)
+}
+
+// reportSpans splits text into spans, linking the issue IDs it contains.
+func reportSpans(text string, issues *regexp.Regexp, issueURL string) []web.ReportSpan {
+	if issues == nil || issueURL == "" {
+		return []web.ReportSpan{{Text: text}}
+	}
+	var spans []web.ReportSpan
+	last := 0
+	for _, m := range issues.FindAllStringIndex(text, -1) {
+		if m[0] > last {
+			spans = append(spans, web.ReportSpan{Text: text[last:m[0]]})
+		}
+		id := text[m[0]:m[1]]
+		spans = append(spans, web.ReportSpan{Text: id, URL: strings.ReplaceAll(issueURL, "{id}", id)})
+		last = m[1]
+	}
+	if last < len(text) {
+		spans = append(spans, web.ReportSpan{Text: text[last:]})
+	}
+	return spans
+}
+
+// markdownEscaper escapes text that Markdown would otherwise format.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "|", `\|`, "#", `\#`,
+)
+
+// markdownURL keeps a URL from ending a Markdown link early.
+var markdownURL = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29")
+
+// writeMarkdownReport renders a report as GitHub-flavored Markdown.
+func writeMarkdownReport(w io.Writer, r web.ReportData) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", markdownEscaper.Replace(r.Title))
+	fmt.Fprintf(&b, "%s · %d events · %d agents · %d crashes\n\n", r.Window, r.Events, len(r.Agents), r.Crashes)
+	if len(r.Agents) == 0 {
+		b.WriteString("_No matching events._\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	b.WriteString("| Agent | Events | Spawns | Done | Crashes |\n")
+	b.WriteString("| --- | ---: | ---: | ---: | ---: |\n")
+	for _, a := range r.Agents {
+		fmt.Fprintf(&b, "| [%s](#%s) | %d | %d | %d | %d |\n",
+			markdownEscaper.Replace(a.Agent), a.Anchor, len(a.Entries), a.Spawns, a.Done, a.Crashes)
+	}
+
+	for _, a := range r.Agents {
+		fmt.Fprintf(&b, "\n## %s\n\n", markdownEscaper.Replace(a.Agent))
+		for _, e := range a.Entries {
+			fmt.Fprintf(&b, "- `%s` **%s** ", e.Time, markdownEscaper.Replace(e.Type))
+			for _, s := range e.Detail {
+				if s.URL != "" {
+					fmt.Fprintf(&b, "[%s](%s)", markdownEscaper.Replace(s.Text), markdownURL.Replace(s.URL))
+				} else {
+					b.WriteString(markdownEscaper.Replace(s.Text))
+				}
+			}
+			b.WriteString("\n")
+			for _, n := range e.Notes {
+				fmt.Fprintf(&b, "  - _✎ %s_\n", markdownEscaper.Replace(n))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+// logStreamHeartbeat is how often an idle stream sends a comment, so
+// proxies and clients do not drop the connection.
+const logStreamHeartbeat = 15 * time.Second
+
+var (
+	logServeAddr        string
+	logServeAllowOrigin string
+)
+
+var logServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Stream town events over HTTP as Server-Sent Events",
+	Long: `Serve the town log as a Server-Sent Events stream at /events, so
+dashboards and bots can subscribe to lifecycle events as they happen.
+
+Each event is sent with its ID (see 'gt explain'), its type as the SSE
+event name, and the same JSON object as 'gt log --json' as data. Clients
+that reconnect with a Last-Event-ID header get the events they missed.
+
+Query parameters filter the stream:
+  type=crash,kill   Only these event types (repeatable)
+  agent=gastown/    Only agents with this prefix or glob
+  agent_re=crew/.*  Only agents matching a regular expression
+  since=1h          Replay matching events since a duration ago, a time, or a mark
+  backlog=20        Replay the last N matching events first
+  grep=gt-abc       Only events whose context matches a regular expression
+  min_severity=warn Only events at least this severe (info, warn, error)
+  session=3f9a2c01  Only the events of one agent session
+  all=1             Include events hidden by rig ignore rules
+
+Links created with 'gt share' are served under /share/, giving read-only
+live views of single agents' sessions.
+
+Examples:
+  gt log serve                           # Listen on :7777
+  gt log serve --addr 127.0.0.1:9000
+  curl -N 'localhost:7777/events?type=crash,kill&agent=gastown/'
+  gt log serve --allow-origin '*'        # Let browser dashboards connect`,
+	Args: cobra.NoArgs,
+	RunE: runLogServe,
+}
+
+func init() {
+	logServeCmd.Flags().StringVar(&logServeAddr, "addr", ":7777", "Address to listen on")
+	logServeCmd.Flags().StringVar(&logServeAllowOrigin, "allow-origin", "", "Access-Control-Allow-Origin for browser clients (e.g. '*')")
+
+	logCmd.AddCommand(logServeCmd)
+}
+
+func runLogServe(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	listener, err := net.Listen("tcp", logServeAddr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", logServeAddr, err)
+	}
+
+	mux := http.NewServeMux()
+	stream := &logStreamHandler{townRoot: townRoot, allowOrigin: logServeAllowOrigin}
+	mux.Handle("/events", stream)
+	mux.Handle("/share/", newShareHandler(townRoot, &logStreamHandler{townRoot: townRoot}))
+
+	// No write timeout: streams stay open for as long as clients listen.
+	// Handlers run under ctx, so Ctrl+C ends them and shutdown is prompt.
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("%s Streaming town events at http://%s/events\n", style.Success.Render("✓"), listener.Addr())
+	fmt.Printf("   Press Ctrl+C to stop\n")
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// logStreamHandler serves the town log as an SSE stream.
+type logStreamHandler struct {
+	townRoot    string
+	allowOrigin string
+	interval    time.Duration // poll interval; zero uses the townlog default
+}
+
+// logStreamRequest is a parsed stream subscription.
+type logStreamRequest struct {
+	filter  townlog.Filter
+	backlog int
+	all     bool
+}
+
+// parseLogStreamRequest reads stream filters from query parameters.
+func parseLogStreamRequest(townRoot string, q url.Values) (logStreamRequest, error) {
+	var req logStreamRequest
+	req.filter.Types = townlog.ParseTypes(q["type"]...)
+	if err := applyAgentFilter(&req.filter, q.Get("agent"), q.Get("agent_re")); err != nil {
+		return req, err
+	}
+
+	if v := q.Get("since"); v != "" {
+		since, err := resolveTimeBoundary(townRoot, "since", v)
+		if err != nil {
+			return req, err
+		}
+		req.filter.Since = since
+		req.backlog = -1 // everything since, unless backlog says otherwise
+	}
+	if v := q.Get("backlog"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return req, fmt.Errorf("invalid backlog %q: want a number of events", v)
+		}
+		req.backlog = n
+	}
+	if v := q.Get("grep"); v != "" {
+		pattern, err := compileLogPattern(v, false, false)
+		if err != nil {
+			return req, err
+		}
+		req.filter.Pattern = pattern
+	}
+	if err := applySeverityFilter(&req.filter, q.Get("min_severity")); err != nil {
+		return req, err
+	}
+	req.filter.Session = q.Get("session")
+	if v := q.Get("all"); v != "" {
+		all, err := strconv.ParseBool(v)
+		if err != nil {
+			return req, fmt.Errorf("invalid all %q: want true or false", v)
+		}
+		req.all = all
+	}
+	return req, nil
+}
+
+func (h *logStreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.allowOrigin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", h.allowOrigin)
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	req, err := parseLogStreamRequest(h.townRoot, r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+		req.backlog = h.missedSince(req.filter, lastID)
+	}
+	if req.backlog < 0 {
+		req.backlog = math.MaxInt
+	}
+
+	var rules config.IgnoreRules
+	if !req.all {
+		rules = config.LoadIgnoreRules(h.townRoot)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // keep nginx from buffering the stream
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, ": gt town events\n\n")
+	flusher.Flush()
+
+	// Follow runs in its own goroutine and hands events over, so only this
+	// one writes to the response: heartbeats and events never interleave.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	events := make(chan townlog.Event)
+	done := make(chan error, 1)
+	go func() {
+		opts := townlog.FollowOptions{Filter: req.filter, Backlog: req.backlog, Interval: h.interval}
+		done <- townlog.Follow(ctx, h.townRoot, opts, func(e townlog.Event) error {
+			select {
+			case events <- e:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	heartbeat := time.NewTicker(logStreamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case e := <-events:
+			if rules.Ignored(string(e.Type), e.Agent) {
+				continue
+			}
+			if err := writeSSEEvent(w, e); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// missedSince returns how many events matching filter were logged after
+// the one with ID lastID, for a client resuming its stream. An unknown ID
+// replays nothing rather than the whole log.
+func (h *logStreamHandler) missedSince(filter townlog.Filter, lastID string) int {
+	events, err := townlog.QueryEvents(h.townRoot, filter)
+	if err != nil {
+		return 0
+	}
+	i, err := townlog.FindEvent(events, lastID)
+	if err != nil {
+		return 0
+	}
+	return len(events) - i - 1
+}
+
+// writeSSEEvent writes one event in Server-Sent Events framing. Event types
+// and IDs never contain newlines, and the JSON data is a single line.
+func writeSSEEvent(w io.Writer, e townlog.Event) error {
+	data, err := json.Marshal(newLogEventJSON("", e))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID(), e.Type, data)
+	return err
+}
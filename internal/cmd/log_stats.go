@@ -0,0 +1,371 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	logStatsTypes    []string
+	logStatsAgent    string
+	logStatsAgentRe  string
+	logStatsSelector string
+	logStatsSince    string
+	logStatsUntil    string
+	logStatsArchives bool
+	logStatsAll      bool
+	logStatsTop      int
+	logStatsJSON     bool
+)
+
+var logStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize town log events",
+	Long: `Aggregate town log events into a summary:
+
+  Events by type   how often each event type was recorded
+  Agents           events, spawns, dones and crashes per agent
+  Crash rate       crashes per agent session started (spawn or wake)
+  Spawn → done     time from an agent's spawn to its next done
+  Busiest hour     hour of the day (local time) with the most events
+  Cost             tokens, cost, and time reported with done and handoff
+                   events (see 'gt done --help'), per completed issue
+
+The same filters as 'gt log' select the events. Only the current log is
+read unless --archives is given.
+
+Examples:
+  gt log stats                      # Whole current log
+  gt log stats --since 24h          # Last day
+  gt log stats --agent gastown/ --archives
+  gt log stats --since sprint-1 --until sprint-2
+  gt log stats --json | jq .crash_rate`,
+	Args: cobra.NoArgs,
+	RunE: runLogStats,
+}
+
+func init() {
+	logStatsCmd.Flags().StringSliceVarP(&logStatsTypes, "type", "t", nil, "Only these event types (comma-separated)")
+	logStatsCmd.Flags().StringVarP(&logStatsAgent, "agent", "a", "", "Only agents with this prefix or glob (e.g. 'gastown/crew/*')")
+	logStatsCmd.Flags().StringVar(&logStatsAgentRe, "agent-re", "", "Only agents matching this regular expression")
+	logStatsCmd.Flags().StringVar(&logStatsSelector, "selector", "", "Only agents whose labels match this selector (e.g. team=frontend)")
+	logStatsCmd.Flags().StringVar(&logStatsSince, "since", "", "Only events since duration, time, or mark")
+	logStatsCmd.Flags().StringVar(&logStatsUntil, "until", "", "Only events up to duration ago, time, or mark")
+	logStatsCmd.Flags().BoolVar(&logStatsArchives, "archives", false, "Include archived logs")
+	logStatsCmd.Flags().BoolVar(&logStatsAll, "all", false, "Include events hidden by rig ignore rules")
+	logStatsCmd.Flags().IntVar(&logStatsTop, "top", 10, "Number of agents to show (0 for all)")
+	logStatsCmd.Flags().BoolVar(&logStatsJSON, "json", false, "Output as JSON")
+
+	logCmd.AddCommand(logStatsCmd)
+}
+
+// logStatCount is the number of events for one type.
+type logStatCount struct {
+	Type  townlog.EventType `json:"type"`
+	Count int               `json:"count"`
+}
+
+// agentLogStats holds one agent's event counts.
+type agentLogStats struct {
+	Agent   string `json:"agent"`
+	Events  int    `json:"events"`
+	Spawns  int    `json:"spawns"`
+	Done    int    `json:"done"`
+	Crashes int    `json:"crashes"`
+}
+
+// logStats is the summary printed by 'gt log stats'.
+type logStats struct {
+	Events    int             `json:"events"`
+	First     time.Time       `json:"first,omitzero"`
+	Last      time.Time       `json:"last,omitzero"`
+	ByType    []logStatCount  `json:"by_type"`  // most frequent first
+	ByAgent   []agentLogStats `json:"by_agent"` // most events first
+	Sessions  int             `json:"sessions"` // spawns and wakes
+	Crashes   int             `json:"crashes"`
+	CrashRate float64         `json:"crash_rate"` // crashes / sessions
+
+	// Spawn-to-done durations, pairing each done with the agent's most
+	// recent spawn that has not been matched yet.
+	Completions       int     `json:"completions"`
+	AvgSpawnToDone    float64 `json:"avg_spawn_to_done_seconds"`
+	MedianSpawnToDone float64 `json:"median_spawn_to_done_seconds"`
+
+	ByHour      [24]int `json:"by_hour"`      // events per local hour of day
+	BusiestHour int     `json:"busiest_hour"` // -1 without events
+
+	// Usage reported with done and handoff events: the total, and per
+	// completed issue (its done plus the handoffs of the same session),
+	// most expensive first.
+	Usage  *townlog.Usage `json:"usage,omitempty"`
+	Issues []issueUsage   `json:"issues,omitempty"`
+}
+
+// issueUsage is the reported usage of one completed issue.
+type issueUsage struct {
+	Issue string        `json:"issue"`
+	Agent string        `json:"agent"`
+	Usage townlog.Usage `json:"usage"`
+}
+
+func runLogStats(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	filter := townlog.Filter{
+		Types: townlog.ParseTypes(logStatsTypes...),
+	}
+	if err := applyAgentFilter(&filter, logStatsAgent, logStatsAgentRe); err != nil {
+		return err
+	}
+	if err := applySelectorFilter(&filter, townRoot, logStatsSelector); err != nil {
+		return err
+	}
+	if logStatsSince != "" {
+		if filter.Since, err = resolveTimeBoundary(townRoot, "since", logStatsSince); err != nil {
+			return err
+		}
+	}
+	if logStatsUntil != "" {
+		if filter.Until, err = resolveTimeBoundary(townRoot, "until", logStatsUntil); err != nil {
+			return err
+		}
+	}
+	if err := checkTimeRange(filter.Since, filter.Until); err != nil {
+		return err
+	}
+
+	var events []townlog.Event
+	if logStatsArchives {
+		events, err = townlog.ReadArchivedEvents(townRoot)
+		events = townlog.FilterEvents(events, filter)
+	} else {
+		events, err = townlog.QueryEvents(townRoot, filter)
+	}
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+	hidden := 0
+	if !logStatsAll {
+		events, hidden = filterIgnoredEvents(townRoot, events)
+	}
+
+	stats := computeLogStats(events)
+	if logStatsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+	if stats.Events == 0 {
+		fmt.Printf("%s No matching events\n", style.Dim.Render("○"))
+		printHiddenEvents(hidden)
+		return nil
+	}
+	printLogStats(stats)
+	printHiddenEvents(hidden)
+	return nil
+}
+
+// computeLogStats aggregates events, which must be oldest first.
+func computeLogStats(events []townlog.Event) logStats {
+	stats := logStats{Events: len(events), BusiestHour: -1}
+	byType := make(map[townlog.EventType]int)
+	byAgent := make(map[string]*agentLogStats)
+	spawnedAt := make(map[string]time.Time) // agent -> unmatched spawn
+	var total townlog.Usage
+	handedOff := make(map[string]*townlog.Usage) // session -> usage of its handoffs
+	var durations []time.Duration
+
+	for _, e := range events {
+		if stats.First.IsZero() || e.Timestamp.Before(stats.First) {
+			stats.First = e.Timestamp
+		}
+		if e.Timestamp.After(stats.Last) {
+			stats.Last = e.Timestamp
+		}
+		byType[e.Type]++
+		stats.ByHour[e.Timestamp.Local().Hour()]++
+
+		a := byAgent[e.Agent]
+		if a == nil {
+			a = &agentLogStats{Agent: e.Agent}
+			byAgent[e.Agent] = a
+		}
+		a.Events++
+
+		total.Add(e.Usage)
+		session := e.SessionID
+		if session == "" {
+			session = townlog.AgentKey(e.Agent)
+		}
+
+		switch e.Type {
+		case townlog.EventSpawn:
+			a.Spawns++
+			stats.Sessions++
+			spawnedAt[e.Agent] = e.Timestamp
+		case townlog.EventWake:
+			stats.Sessions++
+		case townlog.EventHandoff:
+			if !e.Usage.IsZero() {
+				if handedOff[session] == nil {
+					handedOff[session] = &townlog.Usage{}
+				}
+				handedOff[session].Add(e.Usage)
+			}
+		case townlog.EventDone:
+			a.Done++
+			var u townlog.Usage
+			u.Add(handedOff[session])
+			u.Add(e.Usage)
+			delete(handedOff, session)
+			if !u.IsZero() {
+				stats.Issues = append(stats.Issues, issueUsage{Issue: e.Context, Agent: e.Agent, Usage: u})
+			}
+			if t, ok := spawnedAt[e.Agent]; ok {
+				durations = append(durations, e.Timestamp.Sub(t))
+				delete(spawnedAt, e.Agent)
+			}
+		case townlog.EventCrash:
+			a.Crashes++
+			stats.Crashes++
+		}
+	}
+
+	for t, n := range byType {
+		stats.ByType = append(stats.ByType, logStatCount{Type: t, Count: n})
+	}
+	sort.Slice(stats.ByType, func(i, j int) bool {
+		if stats.ByType[i].Count != stats.ByType[j].Count {
+			return stats.ByType[i].Count > stats.ByType[j].Count
+		}
+		return stats.ByType[i].Type < stats.ByType[j].Type
+	})
+	for _, a := range byAgent {
+		stats.ByAgent = append(stats.ByAgent, *a)
+	}
+	sort.Slice(stats.ByAgent, func(i, j int) bool {
+		if stats.ByAgent[i].Events != stats.ByAgent[j].Events {
+			return stats.ByAgent[i].Events > stats.ByAgent[j].Events
+		}
+		return stats.ByAgent[i].Agent < stats.ByAgent[j].Agent
+	})
+
+	if stats.Sessions > 0 {
+		stats.CrashRate = float64(stats.Crashes) / float64(stats.Sessions)
+	}
+	if len(durations) > 0 {
+		var total time.Duration
+		for _, d := range durations {
+			total += d
+		}
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		median := durations[len(durations)/2]
+		if len(durations)%2 == 0 {
+			median = (durations[len(durations)/2-1] + median) / 2
+		}
+		stats.Completions = len(durations)
+		stats.AvgSpawnToDone = (total / time.Duration(len(durations))).Seconds()
+		stats.MedianSpawnToDone = median.Seconds()
+	}
+	if !total.IsZero() {
+		stats.Usage = &total
+	}
+	sort.SliceStable(stats.Issues, func(i, j int) bool {
+		a, b := stats.Issues[i].Usage, stats.Issues[j].Usage
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		return a.TokensIn+a.TokensOut > b.TokensIn+b.TokensOut
+	})
+	for h, n := range stats.ByHour {
+		if n > 0 && (stats.BusiestHour < 0 || n > stats.ByHour[stats.BusiestHour]) {
+			stats.BusiestHour = h
+		}
+	}
+	return stats
+}
+
+func printLogStats(s logStats) {
+	fmt.Printf("\n%s Town log stats\n", style.Bold.Render("📊"))
+	fmt.Printf("%s\n\n", style.Dim.Render(fmt.Sprintf("%d events, %s → %s",
+		s.Events, s.First.Local().Format("2006-01-02 15:04"), s.Last.Local().Format("2006-01-02 15:04"))))
+
+	fmt.Println(style.Bold.Render("Events by type"))
+	for _, c := range s.ByType {
+		fmt.Printf("  %-18s %6d\n", c.Type, c.Count)
+	}
+
+	agents := s.ByAgent
+	heading := "Agents"
+	if logStatsTop > 0 && len(agents) > logStatsTop {
+		agents = agents[:logStatsTop]
+		heading = fmt.Sprintf("Agents (top %d of %d)", logStatsTop, len(s.ByAgent))
+	}
+	fmt.Printf("\n%s\n", style.Bold.Render(heading))
+	fmt.Printf("  %-32s %6s %6s %6s %6s\n", "AGENT", "EVENTS", "SPAWN", "DONE", "CRASH")
+	for _, a := range agents {
+		fmt.Printf("  %-32s %6d %6d %6d %6d\n", truncateStr(a.Agent, 32), a.Events, a.Spawns, a.Done, a.Crashes)
+	}
+
+	fmt.Println()
+	if s.Sessions > 0 {
+		fmt.Printf("Crash rate:    %.1f%% (%d crashes / %d sessions)\n", s.CrashRate*100, s.Crashes, s.Sessions)
+	} else {
+		fmt.Printf("Crash rate:    %s\n", style.Dim.Render("- (no sessions started)"))
+	}
+	if s.Completions > 0 {
+		fmt.Printf("Spawn → done:  avg %s, median %s (%d tasks)\n",
+			formatGap(secondsDuration(s.AvgSpawnToDone)), formatGap(secondsDuration(s.MedianSpawnToDone)), s.Completions)
+	} else {
+		fmt.Printf("Spawn → done:  %s\n", style.Dim.Render("- (no spawn followed by done)"))
+	}
+	if s.BusiestHour >= 0 {
+		fmt.Printf("Busiest hour:  %02d:00-%02d:00 (%d events)\n", s.BusiestHour, (s.BusiestHour+1)%24, s.ByHour[s.BusiestHour])
+	}
+	if s.Usage != nil {
+		fmt.Printf("Reported cost: %s\n", formatUsage(s.Usage))
+	}
+	printIssueUsage(s.Issues)
+}
+
+// printIssueUsage prints the cost of each completed issue that reported
+// usage, most expensive first.
+func printIssueUsage(issues []issueUsage) {
+	if len(issues) == 0 {
+		return
+	}
+	heading := "Cost per issue"
+	if logStatsTop > 0 && len(issues) > logStatsTop {
+		heading = fmt.Sprintf("Cost per issue (top %d of %d)", logStatsTop, len(issues))
+		issues = issues[:logStatsTop]
+	}
+	fmt.Printf("\n%s\n", style.Bold.Render(heading))
+	fmt.Printf("  %-16s %-24s %10s %10s %8s %9s\n", "ISSUE", "AGENT", "TOKENS IN", "OUT", "COST", "TIME")
+	for _, iu := range issues {
+		u := iu.Usage
+		cost, took := "-", "-"
+		if u.Cost != 0 {
+			cost = fmt.Sprintf("$%.2f", u.Cost)
+		}
+		if u.Duration != 0 {
+			took = formatGap(u.Duration)
+		}
+		fmt.Printf("  %-16s %-24s %10d %10d %8s %9s\n", truncateStr(iu.Issue, 16), truncateStr(iu.Agent, 24),
+			u.TokensIn, u.TokensOut, cost, took)
+	}
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,482 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+func TestWriteEventsJSON(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	evts := []townlog.Event{
+		{Timestamp: ts, Type: townlog.EventSpawn, Agent: "gastown/polecats/nux", Context: "gt-1"},
+		{Timestamp: ts.Add(time.Minute), Type: townlog.EventCrash, Agent: "gastown/polecats/nux"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeEventsJSON(&buf, evts); err != nil {
+		t.Fatalf("writeEventsJSON: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want one per event:\n%s", len(lines), buf.String())
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("line is not JSON: %v", err)
+	}
+	want := map[string]interface{}{
+		"id":        evts[0].ID(),
+		"timestamp": "2026-03-01T09:30:00Z",
+		"type":      "spawn",
+		"agent":     "gastown/polecats/nux",
+		"context":   "gt-1",
+		"severity":  "info",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+	if strings.Contains(lines[1], `"context"`) {
+		t.Errorf("empty context should be omitted: %s", lines[1])
+	}
+	if !strings.Contains(lines[1], `"severity":"error"`) {
+		t.Errorf("crash should carry its default severity: %s", lines[1])
+	}
+}
+
+func TestWriteEventsCSV(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	evts := []townlog.Event{
+		{Timestamp: ts, Type: townlog.EventSpawn, Agent: "gastown/polecats/nux", Context: "gt-1", SessionID: "s1"},
+		{Timestamp: ts.Add(time.Minute), Type: townlog.EventDone, Agent: "gastown/polecats/nux", Context: `fixed "a, b"`,
+			Usage: &townlog.Usage{TokensIn: 1200, TokensOut: 340, Cost: 0.42, Duration: 90 * time.Second}},
+	}
+
+	var buf bytes.Buffer
+	if err := writeEventsCSV(&buf, evts); err != nil {
+		t.Fatalf("writeEventsCSV: %v", err)
+	}
+	want := "id,timestamp,type,agent,severity,session,context,tokens_in,tokens_out,cost_usd,duration_seconds\n" +
+		evts[0].ID() + ",2026-03-01T09:30:00Z,spawn,gastown/polecats/nux,info,s1,gt-1,,,,\n" +
+		evts[1].ID() + `,2026-03-01T09:31:00Z,done,gastown/polecats/nux,info,,"fixed ""a, b""",1200,340,0.42,90` + "\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestCompileLogPattern(t *testing.T) {
+	tests := []struct {
+		pattern           string
+		fixed, ignoreCase bool
+		input             string
+		want              bool
+	}{
+		{`gt-\w+`, false, false, "hooked gt-abc", true},
+		{`GT-ABC`, false, true, "hooked gt-abc", true},
+		{`a.b(c)`, true, false, "axb(c)", false},
+		{`a.b(c)`, true, false, "see a.b(c)", true},
+	}
+	for _, tt := range tests {
+		re, err := compileLogPattern(tt.pattern, tt.fixed, tt.ignoreCase)
+		if err != nil {
+			t.Fatalf("compileLogPattern(%q) error: %v", tt.pattern, err)
+		}
+		if got := re.MatchString(tt.input); got != tt.want {
+			t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.input, got, tt.want)
+		}
+	}
+
+	if _, err := compileLogPattern("(", false, false); err == nil {
+		t.Error("invalid pattern should fail")
+	}
+}
+
+func TestSortTownEvents(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	events := []townEvent{
+		{Town: "alpha", Event: townlog.Event{Timestamp: ts.Add(2 * time.Minute), Type: townlog.EventDone}},
+		{Town: "alpha", Event: townlog.Event{Timestamp: ts.Add(2 * time.Minute), Type: townlog.EventKill}},
+		{Town: "beta", Event: townlog.Event{Timestamp: ts.Add(time.Minute), Type: townlog.EventSpawn}},
+		{Town: "beta", Event: townlog.Event{Timestamp: ts.Add(3 * time.Minute), Type: townlog.EventCrash}},
+	}
+	sortTownEvents(events)
+
+	var got []string
+	for _, e := range events {
+		got = append(got, e.Town+":"+string(e.Type))
+	}
+	want := "beta:spawn alpha:done alpha:kill beta:crash"
+	if strings.Join(got, " ") != want {
+		t.Errorf("order = %v, want %s", got, want)
+	}
+}
+
+func TestLogStreamHandler(t *testing.T) {
+	townRoot := t.TempDir()
+	logger := townlog.NewLogger(townRoot)
+	if err := logger.Log(townlog.EventSpawn, "gastown/polecats/nux", "gt-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Log(townlog.EventCrash, "gastown/polecats/nux", "exit code 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(&logStreamHandler{townRoot: townRoot, interval: 10 * time.Millisecond})
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?type=crash,done&backlog=5", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	// Backlog first, then events logged after the stream opened.
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() (name, data string) {
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("reading stream: %v", err)
+			}
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimSpace(strings.TrimPrefix(line, "event: "))
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimSpace(strings.TrimPrefix(line, "data: "))
+			case line == "\n" && name != "":
+				return name, data
+			}
+		}
+	}
+	if name, data := readEvent(); name != "crash" || !strings.Contains(data, `"context":"exit code 1"`) {
+		t.Errorf("backlog event = %s %s", name, data)
+	}
+
+	if err := logger.Log(townlog.EventNudge, "gastown/polecats/nux", "filtered out"); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Log(townlog.EventDone, "gastown/polecats/nux", "gt-1"); err != nil {
+		t.Fatal(err)
+	}
+	if name, _ := readEvent(); name != "done" {
+		t.Errorf("live event = %s, want done", name)
+	}
+}
+
+func TestParseLogStreamRequest(t *testing.T) {
+	req, err := parseLogStreamRequest("", url.Values{
+		"type":         {"crash,kill", "done"},
+		"agent":        {"gastown/"},
+		"since":        {"1h"},
+		"agent_re":     {"crew/(max|joe)"},
+		"min_severity": {"warn"},
+	})
+	if err != nil {
+		t.Fatalf("parseLogStreamRequest: %v", err)
+	}
+	if len(req.filter.Types) != 3 || req.filter.Agent != "gastown/" || req.filter.Since.IsZero() || req.filter.AgentPattern == nil ||
+		req.filter.MinSeverity != townlog.SeverityWarn {
+		t.Errorf("filter = %+v", req.filter)
+	}
+	if req.backlog != -1 {
+		t.Errorf("since without backlog should replay everything, backlog = %d", req.backlog)
+	}
+
+	for _, bad := range []url.Values{
+		{"backlog": {"many"}},
+		{"grep": {"("}},
+		{"all": {"maybe"}},
+		{"agent": {"gastown/["}},
+		{"agent_re": {"crew/("}},
+		{"min_severity": {"fatal"}},
+	} {
+		if _, err := parseLogStreamRequest("", bad); err == nil {
+			t.Errorf("%v should be rejected", bad)
+		}
+	}
+}
+
+func TestComputeLogStats(t *testing.T) {
+	start := time.Date(2026, 3, 1, 14, 0, 0, 0, time.Local)
+	ev := func(min int, typ townlog.EventType, agent string) townlog.Event {
+		return townlog.Event{Timestamp: start.Add(time.Duration(min) * time.Minute), Type: typ, Agent: agent}
+	}
+	events := []townlog.Event{
+		ev(0, townlog.EventSpawn, "gastown/polecats/nux"),
+		ev(5, townlog.EventSpawn, "gastown/polecats/toast"),
+		ev(10, townlog.EventNudge, "gastown/polecats/nux"),
+		ev(20, townlog.EventDone, "gastown/polecats/nux"),   // 20m
+		ev(45, townlog.EventDone, "gastown/polecats/toast"), // 40m
+		ev(50, townlog.EventDone, "gastown/polecats/toast"), // no spawn left to pair
+		ev(70, townlog.EventWake, "gastown/polecats/nux"),
+		ev(80, townlog.EventCrash, "gastown/polecats/nux"),
+	}
+
+	s := computeLogStats(events)
+	if s.Events != 8 || !s.First.Equal(events[0].Timestamp) || !s.Last.Equal(events[7].Timestamp) {
+		t.Errorf("events=%d first=%v last=%v", s.Events, s.First, s.Last)
+	}
+	if s.ByType[0].Type != townlog.EventDone || s.ByType[0].Count != 3 {
+		t.Errorf("most frequent type = %+v, want done x3", s.ByType[0])
+	}
+	if a := s.ByAgent[0]; a.Agent != "gastown/polecats/nux" || a.Events != 5 || a.Spawns != 1 || a.Done != 1 || a.Crashes != 1 {
+		t.Errorf("top agent = %+v", a)
+	}
+	if s.Sessions != 3 || s.Crashes != 1 || s.CrashRate != 1.0/3 {
+		t.Errorf("sessions=%d crashes=%d rate=%v", s.Sessions, s.Crashes, s.CrashRate)
+	}
+	if s.Completions != 2 || s.AvgSpawnToDone != 30*60 || s.MedianSpawnToDone != 30*60 {
+		t.Errorf("completions=%d avg=%v median=%v", s.Completions, s.AvgSpawnToDone, s.MedianSpawnToDone)
+	}
+	if s.BusiestHour != 14 || s.ByHour[14] != 6 || s.ByHour[15] != 2 {
+		t.Errorf("busiest hour = %d, by hour = %v", s.BusiestHour, s.ByHour)
+	}
+
+	if empty := computeLogStats(nil); empty.Events != 0 || empty.BusiestHour != -1 {
+		t.Errorf("empty stats = %+v", empty)
+	}
+}
+
+func TestBuildTimeline(t *testing.T) {
+	start := time.Date(2026, 3, 1, 14, 0, 0, 0, time.Local)
+	at := func(min int) time.Time { return start.Add(time.Duration(min) * time.Minute) }
+	ev := func(min int, typ townlog.EventType, agent string) townlog.Event {
+		return townlog.Event{Timestamp: at(min), Type: typ, Agent: agent}
+	}
+	events := []townlog.Event{
+		ev(10, townlog.EventDone, "gastown/crew/max"), // running before the window
+		ev(15, townlog.EventSpawn, "gastown/polecats/nux"),
+		ev(20, townlog.EventSpawn, "gastown/polecats/toast"),
+		ev(30, townlog.EventNudge, "gastown/nux"), // same agent, short form
+		ev(40, townlog.EventCrash, "gastown/polecats/nux"),
+		ev(70, townlog.EventWake, "gastown/polecats/nux"),
+		ev(90, townlog.EventDone, "gastown/polecats/toast"),
+	}
+
+	tl := buildTimeline(events, at(0), at(120))
+	if len(tl.Agents) != 3 {
+		t.Fatalf("got %d agents, want 3: %+v", len(tl.Agents), tl.Agents)
+	}
+	crew, nux, toast := tl.Agents[0], tl.Agents[1], tl.Agents[2]
+	if crew.Agent != "gastown/crew/max" || !crew.Spans[0].Start.Equal(at(0)) || crew.Spans[0].EndedBy != townlog.EventDone {
+		t.Errorf("crew = %+v, want a span from the window start to its done", crew)
+	}
+	if nux.Agent != "gastown/nux" || len(nux.Spans) != 2 {
+		t.Fatalf("nux = %+v, want two spans", nux)
+	}
+	if nux.Spans[0].EndedBy != townlog.EventCrash || !nux.Spans[1].End.Equal(at(120)) || nux.Spans[1].EndedBy != "" {
+		t.Errorf("nux spans = %+v", nux.Spans)
+	}
+	if nux.Active != (25+50)*60 || nux.LongestIdle != 30*60 {
+		t.Errorf("nux active=%v idle=%v", nux.Active, nux.LongestIdle)
+	}
+	if toast.Active != 70*60 {
+		t.Errorf("toast active = %v", toast.Active)
+	}
+	if tl.Peak != 2 || !tl.PeakAt.Equal(at(20)) {
+		t.Errorf("peak = %d at %v, want 2 at 14:20", tl.Peak, tl.PeakAt)
+	}
+
+	chart := renderTimeline(tl, 80)
+	lines := strings.Split(strings.TrimSuffix(chart, "\n"), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[2], "gastown/nux") || !strings.Contains(lines[2], "✗") {
+		t.Errorf("chart:\n%s", chart)
+	}
+}
+
+func TestResolveTimeBoundaryTimestamps(t *testing.T) {
+	tests := []struct {
+		flag, value string
+		want        time.Time
+	}{
+		{"since", "2026-10-13T22:00:00Z", time.Date(2026, 10, 13, 22, 0, 0, 0, time.UTC)},
+		{"since", "2026-10-13T22:00:00+02:00", time.Date(2026, 10, 13, 20, 0, 0, 0, time.UTC)},
+		{"since", "2026-10-13 22:00", time.Date(2026, 10, 13, 22, 0, 0, 0, time.Local)},
+		{"since", "2026-10-13T22:00:30", time.Date(2026, 10, 13, 22, 0, 30, 0, time.Local)},
+		{"since", "2026-10-13", time.Date(2026, 10, 13, 0, 0, 0, 0, time.Local)},
+		{"until", "2026-10-13", time.Date(2026, 10, 13, 23, 59, 59, 999999999, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := resolveTimeBoundary("", tt.flag, tt.value)
+		if err != nil {
+			t.Errorf("resolveTimeBoundary(%s, %q): %v", tt.flag, tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("resolveTimeBoundary(%s, %q) = %v, want %v", tt.flag, tt.value, got, tt.want)
+		}
+	}
+
+	if got, err := resolveTimeBoundary("", "since", "2h"); err != nil || time.Since(got) < 2*time.Hour-time.Minute {
+		t.Errorf("durations still work: %v, %v", got, err)
+	}
+	if _, err := resolveTimeBoundary("", "since", "last-tuesday"); err == nil {
+		t.Error("outside a town, a value that is not a duration or time should fail")
+	}
+
+	early, late := time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	if checkTimeRange(early, late) != nil || checkTimeRange(early, time.Time{}) != nil {
+		t.Error("checkTimeRange rejected a valid range")
+	}
+	if checkTimeRange(late, early) == nil {
+		t.Error("checkTimeRange accepted --since after --until")
+	}
+}
+
+func TestBuildLogReport(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 9, 30, 0, 0, time.Local)
+	events := []townlog.Event{
+		{Timestamp: ts, Type: townlog.EventSpawn, Agent: "gastown/polecats/nux", Context: "gt-abc"},
+		{Timestamp: ts.Add(time.Minute), Type: townlog.EventSpawn, Agent: "gastown/crew/max_2", Context: "hq-x1"},
+		{Timestamp: ts.Add(2 * time.Minute), Type: townlog.EventCrash, Agent: "gastown/polecats/nux", Context: "exit *1*"},
+	}
+	report := buildLogReport(events, logReportOptions{
+		Title:    "Activity",
+		Until:    ts.Add(time.Hour),
+		Issues:   regexp.MustCompile(`\b(?:gt|hq)-[a-z0-9]+\b`),
+		IssueURL: "https://issues.example.com/{id}",
+		Notes:    []townlog.Annotation{{By: "overseer", Text: "OOM bug", Events: []string{events[2].ID()}}},
+	})
+
+	if report.Events != 3 || report.Crashes != 1 || len(report.Agents) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	nux := report.Agents[0]
+	if nux.Agent != "gastown/polecats/nux" || nux.Anchor != "gastownpolecatsnux" || len(nux.Entries) != 2 {
+		t.Errorf("busiest agent section = %+v", nux)
+	}
+	if nux.Entries[0].Time != "09:30" || nux.Entries[1].Severity != "error" {
+		t.Errorf("entries = %+v", nux.Entries)
+	}
+	if got := nux.Entries[1].Notes; len(got) != 1 || got[0] != "overseer: OOM bug" {
+		t.Errorf("crash notes = %v", got)
+	}
+	if got := nux.Entries[0].Detail; len(got) != 2 || got[1].URL != "https://issues.example.com/gt-abc" {
+		t.Errorf("issue not linked: %+v", got)
+	}
+
+	var buf bytes.Buffer
+	if err := writeMarkdownReport(&buf, report); err != nil {
+		t.Fatalf("writeMarkdownReport: %v", err)
+	}
+	md := buf.String()
+	for _, want := range []string{
+		"# Activity\n",
+		"| [gastown/polecats/nux](#gastownpolecatsnux) | 2 | 1 | 0 | 1 |",
+		"## gastown/crew/max\\_2",
+		"- `09:30` **spawn** spawned for [gt-abc](https://issues.example.com/gt-abc)",
+		"exit \\*1\\*",
+		"  - _✎ overseer: OOM bug_",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestReportAnchorUnique(t *testing.T) {
+	seen := make(map[string]int)
+	if a, b := reportAnchor("gastown/Crew/max", seen), reportAnchor("gastown/crew/max", seen); a != "gastowncrewmax" || b != "gastowncrewmax-1" {
+		t.Errorf("anchors = %q, %q", a, b)
+	}
+}
+
+func TestComputeLogStatsUsage(t *testing.T) {
+	start := time.Date(2026, 3, 1, 14, 0, 0, 0, time.Local)
+	events := []townlog.Event{
+		{Timestamp: start, Type: townlog.EventSpawn, Agent: "gastown/Toast", SessionID: "s1"},
+		{Timestamp: start.Add(time.Minute), Type: townlog.EventHandoff, Agent: "gastown/Toast", SessionID: "s1",
+			Usage: &townlog.Usage{TokensIn: 100, Cost: 0.5}},
+		{Timestamp: start.Add(2 * time.Minute), Type: townlog.EventDone, Agent: "gastown/Toast", SessionID: "s1", Context: "gt-1",
+			Usage: &townlog.Usage{TokensIn: 50, Cost: 0.25}},
+		{Timestamp: start.Add(3 * time.Minute), Type: townlog.EventDone, Agent: "gastown/Nux", Context: "gt-2",
+			Usage: &townlog.Usage{Cost: 1}},
+		{Timestamp: start.Add(4 * time.Minute), Type: townlog.EventDone, Agent: "gastown/Max", Context: "gt-3"},
+	}
+	s := computeLogStats(events)
+	if s.Usage == nil || s.Usage.Cost != 1.75 || s.Usage.TokensIn != 150 {
+		t.Errorf("total usage = %+v, want $1.75 and 150 tokens in", s.Usage)
+	}
+	if len(s.Issues) != 2 {
+		t.Fatalf("issues = %+v, want 2", s.Issues)
+	}
+	if s.Issues[0].Issue != "gt-2" || s.Issues[1].Issue != "gt-1" || s.Issues[1].Usage.Cost != 0.75 {
+		t.Errorf("issues = %+v, want gt-2 then gt-1 with its handoff included", s.Issues)
+	}
+}
+
+func TestParseLogImport(t *testing.T) {
+	input := `{"timestamp": "2026-03-01T14:05:00Z", "type": "ci.failed", "agent": "ci/github", "context": "main  #812", "severity": "error"}
+
+# comment
+{"type": "deploy", "context": "v1.2"}
+{"type": "Bad Type", "agent": "ci/github"}
+not json
+{"type": "deploy", "agent": "has space"}
+`
+	events, invalid, err := parseLogImport(strings.NewReader(input), "ci/default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || len(invalid) != 3 {
+		t.Fatalf("got %d events and %d invalid lines, want 2 and 3: %+v", len(events), len(invalid), invalid)
+	}
+	if e := events[0]; e.Context != "main #812" || e.Severity != townlog.SeverityError || !e.Timestamp.Equal(time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)) {
+		t.Errorf("first event = %+v", e)
+	}
+	if e := events[1]; e.Agent != "ci/default" || e.Timestamp.IsZero() {
+		t.Errorf("defaults not applied: %+v", e)
+	}
+	if invalid[0].Line != 5 || invalid[1].Line != 6 || invalid[2].Line != 7 {
+		t.Errorf("invalid lines = %+v, want 5, 6, 7", invalid)
+	}
+}
+
+func TestLogDoneStartsPostDoneHooks(t *testing.T) {
+	town := t.TempDir()
+	marker := filepath.Join(town, "done")
+	settings := `{"hooks": {"post_done": ["echo \"$GT_HOOK_AGENT $GT_HOOK_CONTEXT\" > ` + marker + `"]}}`
+	if err := os.MkdirAll(filepath.Dir(config.TownSettingsPath(town)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config.TownSettingsPath(town), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LogDone(town, "gastown/crew/max", "gt-abc", nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := os.ReadFile(marker)
+		if err == nil && strings.TrimSpace(string(data)) == "gastown/crew/max gt-abc" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("post_done hook did not run: %q, %v", data, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+var (
+	logTimelineAgent    string
+	logTimelineAgentRe  string
+	logTimelineSelector string
+	logTimelineSince    string
+	logTimelineUntil    string
+	logTimelineArchives bool
+	logTimelineAll      bool
+	logTimelineWidth    int
+	logTimelineJSON     bool
+)
+
+var logTimelineCmd = &cobra.Command{
+	Use:   "timeline",
+	Short: "Chart when each agent was active",
+	Long: `Draw a per-agent timeline of the town log, one row per agent.
+
+An agent is active from its spawn (or wake) until its done, crash, or
+kill. Agents already running when the window starts are drawn from its
+start, and agents still running at its end are drawn to the end. Idle
+stretches show as dots, so gaps between tasks and overlapping work are
+visible at a glance:
+
+  █ active   ✓ done   ✗ crash   ✕ killed   · idle
+
+The bottom row counts the agents active in each column. Each agent's row
+ends with its total active time and its longest idle gap.
+
+The window defaults to the last 24 hours; the same filters as 'gt log'
+narrow it. With GT_ACCESSIBLE=1 the spans are listed as text instead.
+
+Examples:
+  gt log timeline                       # Last 24 hours
+  gt log timeline --since 2h            # Last two hours
+  gt log timeline --agent 'gastown/*' --since sprint-1 --until sprint-2
+  gt log timeline --json                # Spans as JSON`,
+	Args: cobra.NoArgs,
+	RunE: runLogTimeline,
+}
+
+func init() {
+	logTimelineCmd.Flags().StringVarP(&logTimelineAgent, "agent", "a", "", "Only agents with this prefix or glob (e.g. 'gastown/crew/*')")
+	logTimelineCmd.Flags().StringVar(&logTimelineAgentRe, "agent-re", "", "Only agents matching this regular expression")
+	logTimelineCmd.Flags().StringVar(&logTimelineSelector, "selector", "", "Only agents whose labels match this selector (e.g. team=frontend)")
+	logTimelineCmd.Flags().StringVar(&logTimelineSince, "since", "24h", "Start of the window: duration, time, or mark")
+	logTimelineCmd.Flags().StringVar(&logTimelineUntil, "until", "", "End of the window: duration ago, time, or mark (default now)")
+	logTimelineCmd.Flags().BoolVar(&logTimelineArchives, "archives", false, "Include archived logs")
+	logTimelineCmd.Flags().BoolVar(&logTimelineAll, "all", false, "Include events hidden by rig ignore rules")
+	logTimelineCmd.Flags().IntVar(&logTimelineWidth, "width", 0, "Chart width in columns (default: terminal width)")
+	logTimelineCmd.Flags().BoolVar(&logTimelineJSON, "json", false, "Output as JSON")
+
+	logCmd.AddCommand(logTimelineCmd)
+}
+
+// timelineSpan is one stretch of time an agent was active.
+type timelineSpan struct {
+	Start   time.Time         `json:"start"`
+	End     time.Time         `json:"end"`
+	EndedBy townlog.EventType `json:"ended_by,omitempty"` // done, crash, or kill; empty if still running or respawned
+}
+
+// timelineRow is one agent's spans, oldest first.
+type timelineRow struct {
+	Agent       string         `json:"agent"`
+	Spans       []timelineSpan `json:"spans"`
+	Active      float64        `json:"active_seconds"`
+	LongestIdle float64        `json:"longest_idle_seconds"` // between spans
+}
+
+// timeline is what 'gt log timeline' draws.
+type timeline struct {
+	From   time.Time     `json:"from"`
+	To     time.Time     `json:"to"`
+	Agents []timelineRow `json:"agents"` // by first activity
+	Peak   int           `json:"peak_concurrency"`
+	PeakAt time.Time     `json:"peak_at,omitzero"`
+}
+
+func runLogTimeline(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var filter townlog.Filter
+	if err := applyAgentFilter(&filter, logTimelineAgent, logTimelineAgentRe); err != nil {
+		return err
+	}
+	if err := applySelectorFilter(&filter, townRoot, logTimelineSelector); err != nil {
+		return err
+	}
+	if logTimelineSince != "" {
+		if filter.Since, err = resolveTimeBoundary(townRoot, "since", logTimelineSince); err != nil {
+			return err
+		}
+	}
+	if logTimelineUntil != "" {
+		if filter.Until, err = resolveTimeBoundary(townRoot, "until", logTimelineUntil); err != nil {
+			return err
+		}
+	}
+	if err := checkTimeRange(filter.Since, filter.Until); err != nil {
+		return err
+	}
+
+	var events []townlog.Event
+	if logTimelineArchives {
+		events, err = townlog.ReadArchivedEvents(townRoot)
+		events = townlog.FilterEvents(events, filter)
+	} else {
+		events, err = townlog.QueryEvents(townRoot, filter)
+	}
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+	hidden := 0
+	if !logTimelineAll {
+		events, hidden = filterIgnoredEvents(townRoot, events)
+	}
+
+	from, to := filter.Since, filter.Until
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() && len(events) > 0 {
+		from = events[0].Timestamp
+	}
+	tl := buildTimeline(events, from, to)
+
+	if logTimelineJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tl)
+	}
+	if len(tl.Agents) == 0 {
+		fmt.Printf("%s No agent activity in the window\n", style.Dim.Render("○"))
+		printHiddenEvents(hidden)
+		return nil
+	}
+	if style.Accessible() {
+		printTimelineText(tl)
+	} else {
+		width := logTimelineWidth
+		if width <= 0 {
+			width = 100
+			if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+				width = w
+			}
+		}
+		fmt.Print(renderTimeline(tl, width))
+	}
+	printHiddenEvents(hidden)
+	return nil
+}
+
+// buildTimeline turns events, oldest first, into per-agent activity spans
+// clipped to [from, to]. A spawn or wake opens a span, and done, crash, or
+// kill closes it. An agent whose first event in the window is anything
+// else was already running, so its first span starts at from.
+func buildTimeline(events []townlog.Event, from, to time.Time) timeline {
+	tl := timeline{From: from, To: to}
+	rows := map[string]*timelineRow{}
+	open := map[string]time.Time{}
+	var order []string
+
+	for _, e := range events {
+		if e.Agent == "" || e.Timestamp.Before(from) || e.Timestamp.After(to) {
+			continue
+		}
+		agent := townlog.AgentKey(e.Agent)
+		row := rows[agent]
+		if row == nil {
+			row = &timelineRow{Agent: agent}
+			rows[agent] = row
+			order = append(order, agent)
+		}
+		start, running := open[agent]
+
+		switch e.Type {
+		case townlog.EventSpawn, townlog.EventWake:
+			if running {
+				row.Spans = append(row.Spans, timelineSpan{Start: start, End: e.Timestamp})
+			}
+			open[agent] = e.Timestamp
+		case townlog.EventDone, townlog.EventCrash, townlog.EventKill:
+			if !running {
+				if len(row.Spans) > 0 {
+					continue // already ended; a second end adds nothing
+				}
+				start = from
+			}
+			row.Spans = append(row.Spans, timelineSpan{Start: start, End: e.Timestamp, EndedBy: e.Type})
+			delete(open, agent)
+		default:
+			if !running && len(row.Spans) == 0 {
+				open[agent] = from
+			}
+		}
+	}
+
+	for _, agent := range order {
+		row := rows[agent]
+		if start, running := open[agent]; running {
+			row.Spans = append(row.Spans, timelineSpan{Start: start, End: to})
+		}
+		if len(row.Spans) == 0 {
+			continue
+		}
+		for i, s := range row.Spans {
+			row.Active += s.End.Sub(s.Start).Seconds()
+			if i > 0 {
+				row.LongestIdle = max(row.LongestIdle, s.Start.Sub(row.Spans[i-1].End).Seconds())
+			}
+		}
+		tl.Agents = append(tl.Agents, *row)
+	}
+	sort.SliceStable(tl.Agents, func(i, j int) bool {
+		return tl.Agents[i].Spans[0].Start.Before(tl.Agents[j].Spans[0].Start)
+	})
+
+	// Peak concurrency: sweep span starts and ends in time order.
+	type edge struct {
+		at    time.Time
+		delta int
+	}
+	var edges []edge
+	for _, row := range tl.Agents {
+		for _, s := range row.Spans {
+			edges = append(edges, edge{s.Start, 1}, edge{s.End, -1})
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if !edges[i].at.Equal(edges[j].at) {
+			return edges[i].at.Before(edges[j].at)
+		}
+		return edges[i].delta < edges[j].delta // ends before starts at the same instant
+	})
+	active := 0
+	for _, e := range edges {
+		active += e.delta
+		if active > tl.Peak {
+			tl.Peak, tl.PeakAt = active, e.at
+		}
+	}
+	return tl
+}
+
+// renderTimeline draws tl as a chart width columns wide.
+func renderTimeline(tl timeline, width int) string {
+	nameWidth := len("active")
+	for _, row := range tl.Agents {
+		nameWidth = max(nameWidth, len(row.Agent))
+	}
+	nameWidth = min(nameWidth, 28)
+	const summaryWidth = 22
+	cols := max(width-nameWidth-summaryWidth-3, 10)
+
+	span := tl.To.Sub(tl.From)
+	if span <= 0 {
+		span = time.Minute
+	}
+	step := span / time.Duration(cols)
+	if step <= 0 {
+		step = time.Nanosecond
+	}
+	column := func(t time.Time) int {
+		return min(max(int(t.Sub(tl.From)/step), 0), cols-1)
+	}
+
+	var b strings.Builder
+	layout := "15:04"
+	if tl.From.Local().YearDay() != tl.To.Local().YearDay() || tl.From.Year() != tl.To.Year() {
+		layout = "01-02 15:04"
+	}
+	left, right := tl.From.Local().Format(layout), tl.To.Local().Format(layout)
+	fmt.Fprintf(&b, "%-*s  %s%*s\n", nameWidth, "", left, cols-len(left), right)
+
+	counts := make([]int, cols)
+	for _, row := range tl.Agents {
+		cells := make([]string, cols)
+		for i := range cells {
+			cells[i] = style.Dim.Render("·")
+		}
+		for _, s := range row.Spans {
+			first, last := column(s.Start), column(s.End)
+			for i := first; i <= last; i++ {
+				cells[i] = "█"
+				counts[i]++
+			}
+			switch s.EndedBy {
+			case townlog.EventDone:
+				cells[last] = style.Success.Render("✓")
+			case townlog.EventCrash:
+				cells[last] = style.Error.Render("✗")
+			case townlog.EventKill:
+				cells[last] = style.Warning.Render("✕")
+			}
+		}
+		summary := formatGap(secondsDuration(row.Active)) + " active"
+		if row.LongestIdle > 0 {
+			summary += ", gap " + formatGap(secondsDuration(row.LongestIdle))
+		}
+		fmt.Fprintf(&b, "%-*s  %s  %s\n", nameWidth, truncateStr(row.Agent, nameWidth), strings.Join(cells, ""), style.Dim.Render(summary))
+	}
+
+	var conc strings.Builder
+	for _, n := range counts {
+		switch {
+		case n == 0:
+			conc.WriteByte(' ')
+		case n > 9:
+			conc.WriteByte('+')
+		default:
+			conc.WriteByte(byte('0' + n))
+		}
+	}
+	fmt.Fprintf(&b, "%-*s  %s", nameWidth, "active", style.Dim.Render(conc.String()))
+	if tl.Peak > 0 {
+		fmt.Fprintf(&b, "  %s", style.Dim.Render(fmt.Sprintf("peak %d at %s", tl.Peak, tl.PeakAt.Local().Format("15:04"))))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// printTimelineText lists tl's spans as text, for accessible mode.
+func printTimelineText(tl timeline) {
+	fmt.Printf("Timeline %s to %s, %d agents, at most %d active at once\n",
+		tl.From.Local().Format("2006-01-02 15:04"), tl.To.Local().Format("2006-01-02 15:04"), len(tl.Agents), tl.Peak)
+	for _, row := range tl.Agents {
+		var spans []string
+		for _, s := range row.Spans {
+			text := s.Start.Local().Format("15:04") + " to " + s.End.Local().Format("15:04")
+			if s.EndedBy != "" {
+				text += " (" + string(s.EndedBy) + ")"
+			} else if !s.End.Before(tl.To) {
+				text += " (still running)"
+			}
+			spans = append(spans, text)
+		}
+		fmt.Printf("%s: active %s in total: %s\n", row.Agent, formatGap(secondsDuration(row.Active)), strings.Join(spans, ", "))
+	}
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/i18n"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+// logTown is one town read by 'gt log --all-towns'.
+type logTown struct {
+	Name string
+	Root string
+}
+
+// townEvent is an event tagged with the town it came from.
+type townEvent struct {
+	Town string
+	townlog.Event
+}
+
+// registeredLogTowns returns the registered towns that still exist, with
+// the current town (if any) added when it is not registered.
+func registeredLogTowns(currentRoot string) ([]logTown, error) {
+	registry, err := config.LoadTownRegistry()
+	if err != nil {
+		return nil, err
+	}
+	var towns []logTown
+	for _, t := range registry.Towns {
+		if !townExists(t.Path) {
+			fmt.Fprintf(os.Stderr, "%s skipping registered town %s: %s is gone (see 'gt town unregister')\n", style.WarningPrefix, t.Name, t.Path)
+			continue
+		}
+		towns = append(towns, logTown{Name: t.Name, Root: t.Path})
+	}
+	if currentRoot != "" && registry.Find(currentRoot) == nil {
+		name, err := workspace.GetTownName(currentRoot)
+		if err != nil || name == "" {
+			name = filepath.Base(currentRoot)
+		}
+		towns = append(towns, logTown{Name: name, Root: currentRoot})
+	}
+	if len(towns) == 0 {
+		return nil, fmt.Errorf("no registered towns (see 'gt town register')")
+	}
+	return towns, nil
+}
+
+// runLogAllTowns is 'gt log --all-towns': the merged, chronological log of
+// every registered town, each event prefixed with its town's name.
+func runLogAllTowns(ctx context.Context, currentRoot string, filter townlog.Filter) error {
+	towns, err := registeredLogTowns(currentRoot)
+	if err != nil {
+		return err
+	}
+
+	var merged []townEvent
+	hidden := 0
+	for _, t := range towns {
+		events, err := townlog.QueryEvents(t.Root, filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s skipping town %s: %v\n", style.WarningPrefix, t.Name, err)
+			continue
+		}
+		if !logAll {
+			var n int
+			events, n = filterIgnoredEvents(t.Root, events)
+			hidden += n
+		}
+		for _, e := range events {
+			merged = append(merged, townEvent{Town: t.Name, Event: e})
+		}
+	}
+	sortTownEvents(merged)
+
+	if logTail > 0 && len(merged) > logTail {
+		merged = merged[len(merged)-logTail:]
+	}
+
+	if logFollow {
+		return followAllTowns(ctx, towns, filter, merged)
+	}
+
+	if logJSON {
+		return writeTownEventsJSON(merged)
+	}
+	if len(merged) == 0 {
+		key := "log.no_match"
+		if filter.Empty() {
+			key = "log.empty"
+		}
+		fmt.Printf("%s %s\n", style.Dim.Render("○"), i18n.T(key))
+		printHiddenEvents(hidden)
+		return nil
+	}
+
+	width := townNameWidth(towns)
+	for _, e := range merged {
+		printTownEvent(width, e)
+	}
+	printHiddenEvents(hidden)
+	return nil
+}
+
+// followAllTowns prints the backlog, then follows every town's log at once.
+func followAllTowns(ctx context.Context, towns []logTown, filter townlog.Filter, backlog []townEvent) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	width := townNameWidth(towns)
+	var mu sync.Mutex
+	emit := func(e townEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if logJSON {
+			return writeTownEventsJSON([]townEvent{e})
+		}
+		printTownEvent(width, e)
+		return nil
+	}
+
+	if !logJSON {
+		names := make([]string, len(towns))
+		for i, t := range towns {
+			names[i] = t.Name
+		}
+		fmt.Printf("%s %s\n\n", style.Dim.Render("○"), i18n.T("log.following", strings.Join(names, ", ")))
+	}
+	for _, e := range backlog {
+		if err := emit(e); err != nil {
+			return err
+		}
+	}
+
+	errs := make(chan error, len(towns))
+	for _, t := range towns {
+		var rules config.IgnoreRules
+		if !logAll {
+			rules = config.LoadIgnoreRules(t.Root)
+		}
+		go func(t logTown) {
+			opts := townlog.FollowOptions{Filter: filter}
+			errs <- townlog.Follow(ctx, t.Root, opts, func(e townlog.Event) error {
+				if rules.Ignored(string(e.Type), e.Agent) {
+					return nil
+				}
+				return emit(townEvent{Town: t.Name, Event: e})
+			})
+		}(t)
+	}
+
+	var first error
+	for range towns {
+		if err := <-errs; err != nil && first == nil {
+			first = err
+			cancel()
+		}
+	}
+	return first
+}
+
+// sortTownEvents orders events from several towns by time. Events within a
+// town keep their log order.
+func sortTownEvents(events []townEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+}
+
+// townNameWidth is the width of the town column.
+func townNameWidth(towns []logTown) int {
+	width := 0
+	for _, t := range towns {
+		if len(t.Name) > width {
+			width = len(t.Name)
+		}
+	}
+	return width
+}
+
+// printTownEvent prints an event prefixed with its town.
+func printTownEvent(width int, e townEvent) {
+	fmt.Printf("%s ", style.Bold.Render(fmt.Sprintf("%-*s", width, e.Town)))
+	if logIDs {
+		fmt.Printf("%s ", style.Dim.Render(e.ID()))
+	}
+	printEvent(e.Event)
+}
+
+// writeTownEventsJSON writes events as JSON Lines with their town.
+func writeTownEventsJSON(events []townEvent) error {
+	enc := json.NewEncoder(os.Stdout)
+	for _, e := range events {
+		if err := enc.Encode(newLogEventJSON(e.Town, e.Event)); err != nil {
+			return fmt.Errorf("writing event: %w", err)
+		}
+	}
+	return nil
+}
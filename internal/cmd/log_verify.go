@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	logVerifyArchives bool
+	logVerifyHead     string
+	logVerifyJSON     bool
+)
+
+// logVerifyShown is how many breaks gt log verify lists.
+const logVerifyShown = 10
+
+var logVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the town log's hash chain for tampering",
+	Long: `Check that the town log has not been edited, cut short, or added to
+behind gt's back.
+
+With "log_chain": true in settings/config.json, every event the town logs
+records the hash of the event before it, and the hash of the newest event
+(the chain head) is kept in .runtime/log-chain.json. Modifying or removing
+an event breaks the link of the next one, inserting one breaks its own
+link, and removing events from the end leaves the recorded head missing
+from the log. Events logged before chaining was turned on are reported as
+unchained; from the first chained event on, every event must be chained.
+
+verify prints the chain head. Record it somewhere outside the town (a
+ticket, a commit, a notary service) and later pass it to --head to prove
+that the log still contains everything up to that point, even against
+someone able to rewrite log-chain.json too.
+
+Rotated logs start partway through the chain; use --archives to check the
+chain from its start. Chaining works with town.log, the SQLite store, and
+log segments, and survives 'gt log migrate'. town.log records times in
+local time, so verify it in the time zone it was written in.
+
+Exits with status 1 (error GT1007) if the chain is broken.
+
+Examples:
+  gt log verify                  # Check the current log
+  gt log verify --archives       # Check from the start of the chain
+  gt log verify --head 3f9a...   # Also check against a recorded head
+  gt log verify --json           # Machine-readable report`,
+	Args: cobra.NoArgs,
+	RunE: runLogVerify,
+}
+
+func init() {
+	logVerifyCmd.Flags().BoolVar(&logVerifyArchives, "archives", false, "Also verify rotated and archived logs")
+	logVerifyCmd.Flags().StringVar(&logVerifyHead, "head", "", "A chain head recorded earlier that must still be in the log")
+	logVerifyCmd.Flags().BoolVar(&logVerifyJSON, "json", false, "Output the report as JSON")
+
+	logCmd.AddCommand(logVerifyCmd)
+}
+
+func runLogVerify(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cmd.SilenceUsage = true // failures below are about the log, not the command line
+	events, err := townlog.ReadChainedEvents(townRoot, logVerifyArchives)
+	if err != nil {
+		return err
+	}
+	recorded, err := townlog.ChainHead(townRoot)
+	if err != nil {
+		return err
+	}
+	r := townlog.VerifyChain(events, logVerifyArchives, recorded, logVerifyHead)
+
+	if logVerifyJSON {
+		if err := outputJSON(r); err != nil {
+			return err
+		}
+	} else {
+		printChainReport(r)
+	}
+	switch {
+	case !r.OK():
+		return fmt.Errorf("%w: %d problem(s) found", townlog.ErrChainBroken, len(r.Breaks))
+	case r.Chained == 0:
+		return fmt.Errorf("town log is not hash-chained; set \"log_chain\": true in settings/config.json")
+	}
+	return nil
+}
+
+func printChainReport(r *townlog.ChainReport) {
+	if r.Chained == 0 && r.OK() {
+		return
+	}
+	if r.OK() {
+		fmt.Printf("%s Town log chain intact: %d chained event(s)", style.Success.Render("✓"), r.Chained)
+		if r.Unchained > 0 {
+			fmt.Printf(", after %d unchained", r.Unchained)
+		}
+		fmt.Println()
+	} else {
+		fmt.Printf("%s Town log chain broken: %d problem(s) in %d event(s)\n\n", style.Error.Render("✗"), len(r.Breaks), r.Events)
+		for i, b := range r.Breaks {
+			if i == logVerifyShown {
+				fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("... and %d more (see --json)", len(r.Breaks)-i)))
+				break
+			}
+			where := "end of log"
+			if b.Event != nil {
+				where = fmt.Sprintf("event %d (%s, %s %s)", b.Index+1, b.Event.ID(),
+					b.Event.Timestamp.Format("2006-01-02 15:04:05"), b.Event.Agent)
+			}
+			fmt.Printf("  %s: %s\n", style.Bold.Render(where), b.Reason)
+		}
+		fmt.Println()
+	}
+	if r.Continued {
+		fmt.Printf("  %s\n", style.Dim.Render("The chain starts in a rotated log; use --archives to check it from the start."))
+	}
+	if r.Head != "" {
+		fmt.Printf("  Head: %s\n", r.Head)
+	}
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/util"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	logWatchTypes    []string
+	logWatchAgent    string
+	logWatchAgentRe  string
+	logWatchSelector string
+	logWatchSeverity string
+	logWatchExec     string
+	logWatchNudge    string
+	logWatchTimeout  time.Duration
+	logWatchAll      bool
+)
+
+var logWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Run a command or nudge an agent when matching events are logged",
+	Long: `Watch the town log and act on each new event that matches the filters:
+run a shell command (--exec), nudge a supervisor agent (--nudge), or both.
+
+The command runs in the town root, one event at a time. These placeholders
+are replaced with the event's fields, shell-quoted:
+
+  {type} {agent} {context} {session} {severity} {time} {id}
+
+and the same fields are in the environment as GT_EVENT_TYPE, GT_EVENT_AGENT,
+GT_EVENT_CONTEXT, GT_EVENT_SESSION, GT_EVENT_SEVERITY, GT_EVENT_TIME, and
+GT_EVENT_ID. A command that fails or runs past --timeout is reported, and
+watching goes on. Only events logged after the watch starts are acted on;
+rig ignore rules apply unless --all is given.
+
+Examples:
+  gt log watch --type crash --exec './notify.sh {agent}'
+  gt log watch --type crash,kill --agent 'gastown/*' --nudge gastown/witness
+  gt log watch --min-severity error --nudge mayor
+  gt log watch --type deploy --exec 'curl -s -d {context} https://ci.example/hook'`,
+	Args: cobra.NoArgs,
+	RunE: runLogWatch,
+}
+
+func init() {
+	logWatchCmd.Flags().StringSliceVarP(&logWatchTypes, "type", "t", nil, "Only these event types (comma-separated)")
+	logWatchCmd.Flags().StringVarP(&logWatchAgent, "agent", "a", "", "Only agents with this prefix or glob (e.g. 'gastown/*')")
+	logWatchCmd.Flags().StringVar(&logWatchAgentRe, "agent-re", "", "Only agents matching this regular expression")
+	logWatchCmd.Flags().StringVar(&logWatchSelector, "selector", "", "Only agents whose labels match this selector (e.g. team=frontend)")
+	logWatchCmd.Flags().StringVar(&logWatchSeverity, "min-severity", "", "Only events at least this severe (info, warn, error)")
+	logWatchCmd.Flags().StringVar(&logWatchExec, "exec", "", "Shell command to run per event; {agent}, {type}, ... are replaced")
+	logWatchCmd.Flags().StringVar(&logWatchNudge, "nudge", "", "Agent to nudge about each event (e.g. mayor, gastown/witness)")
+	logWatchCmd.Flags().DurationVar(&logWatchTimeout, "timeout", time.Minute, "Stop a command that runs longer than this")
+	logWatchCmd.Flags().BoolVar(&logWatchAll, "all", false, "Include events hidden by rig ignore rules")
+
+	logCmd.AddCommand(logWatchCmd)
+}
+
+func runLogWatch(cmd *cobra.Command, args []string) error {
+	if logWatchExec == "" && logWatchNudge == "" {
+		return fmt.Errorf("nothing to do: give --exec or --nudge (use 'gt log -f' to only print events)")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	filter, err := logWatchFilter(townRoot)
+	if err != nil {
+		return err
+	}
+	w := &logWatcher{townRoot: townRoot, exec: logWatchExec, nudge: logWatchNudge, timeout: logWatchTimeout}
+	if !logWatchAll {
+		w.rules = config.LoadIgnoreRules(townRoot)
+	}
+	if w.gtPath, err = os.Executable(); err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	cmd.SilenceUsage = true
+
+	fmt.Printf("%s Watching %s\n\n", style.Dim.Render("○"), townlog.LogLocation(townRoot))
+	err = w.watch(ctx, filter, 0)
+	if ctx.Err() != nil {
+		return nil // interrupted
+	}
+	return err
+}
+
+// logWatchFilter returns the filter of gt log watch's flags.
+func logWatchFilter(townRoot string) (townlog.Filter, error) {
+	filter := townlog.Filter{Types: townlog.ParseTypes(logWatchTypes...)}
+	if err := applyAgentFilter(&filter, logWatchAgent, logWatchAgentRe); err != nil {
+		return filter, err
+	}
+	if err := applySelectorFilter(&filter, townRoot, logWatchSelector); err != nil {
+		return filter, err
+	}
+	if err := applySeverityFilter(&filter, logWatchSeverity); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// logWatcher acts on the events gt log watch sees.
+type logWatcher struct {
+	townRoot string
+	gtPath   string             // gt, to nudge with
+	rules    config.IgnoreRules // rig ignore rules, unless --all
+	exec     string             // --exec command, placeholders unexpanded
+	nudge    string             // --nudge agent
+	timeout  time.Duration      // for each --exec command
+}
+
+// watch acts on each new event in the town log that filter matches, until
+// ctx is canceled. A zero interval polls at townlog's default.
+func (w *logWatcher) watch(ctx context.Context, filter townlog.Filter, interval time.Duration) error {
+	return townlog.Follow(ctx, w.townRoot, townlog.FollowOptions{Filter: filter, Interval: interval}, func(e townlog.Event) error {
+		w.handle(ctx, e)
+		return nil
+	})
+}
+
+// handle prints e and acts on it. Failed actions are reported, never
+// returned, so the watch goes on.
+func (w *logWatcher) handle(ctx context.Context, e townlog.Event) {
+	if w.rules.Ignored(string(e.Type), e.Agent) {
+		return
+	}
+	// Our own nudges are logged too; acting on them would loop.
+	if e.Type == townlog.EventNudge && w.nudge != "" && townlog.AgentKey(e.Agent) == townlog.AgentKey(w.nudge) {
+		return
+	}
+	printEvent(e)
+	if w.exec != "" {
+		reportWatchAction("ran command", "command", w.run(ctx, expandWatchCommand(w.exec, e), e))
+	}
+	if w.nudge != "" {
+		nudge := exec.CommandContext(ctx, w.gtPath, "nudge", w.nudge, "-m", watchNudgeMessage(e)) //nolint:gosec // G204: target is the user's own flag
+		nudge.Dir = w.townRoot
+		out, err := nudge.CombinedOutput()
+		if err != nil {
+			err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+		}
+		reportWatchAction("nudged "+w.nudge, "nudge to "+w.nudge, err)
+	}
+}
+
+// run runs one --exec command for e, with its output passed through.
+func (w *logWatcher) run(ctx context.Context, command string, e townlog.Event) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	c := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // G204: the command is the user's own flag
+	c.Dir = w.townRoot
+	c.Stdout, c.Stderr = os.Stdout, os.Stderr
+	c.Env = append(os.Environ(), watchEventEnv(e)...)
+	err := c.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", w.timeout)
+	}
+	return err
+}
+
+// reportWatchAction prints the outcome of one action taken for an event.
+func reportWatchAction(done, action string, err error) {
+	if err != nil {
+		fmt.Printf("    %s %s failed: %v\n", style.Error.Render("✗"), action, err)
+		return
+	}
+	fmt.Printf("    %s\n", style.Dim.Render("→ "+done))
+}
+
+// watchEventFields returns the placeholders of an event and their values.
+func watchEventFields(e townlog.Event) [][2]string {
+	return [][2]string{
+		{"type", string(e.Type)},
+		{"agent", e.Agent},
+		{"context", e.Context},
+		{"session", e.SessionID},
+		{"severity", string(e.Level())},
+		{"time", e.Timestamp.Format(time.RFC3339)},
+		{"id", e.ID()},
+	}
+}
+
+// expandWatchCommand replaces the {field} placeholders in command with
+// e's fields, each quoted for sh.
+func expandWatchCommand(command string, e townlog.Event) string {
+	var pairs []string
+	for _, f := range watchEventFields(e) {
+		pairs = append(pairs, "{"+f[0]+"}", util.ShellQuote(f[1]))
+	}
+	return strings.NewReplacer(pairs...).Replace(command)
+}
+
+func watchEventEnv(e townlog.Event) []string {
+	var env []string
+	for _, f := range watchEventFields(e) {
+		env = append(env, "GT_EVENT_"+strings.ToUpper(f[0])+"="+f[1])
+	}
+	return env
+}
+
+// watchNudgeMessage is the nudge sent to the --nudge agent about e.
+func watchNudgeMessage(e townlog.Event) string {
+	msg := fmt.Sprintf("[log watch] %s %s", e.Type, e.Agent)
+	if e.Context != "" {
+		msg += ": " + e.Context
+	}
+	return msg + fmt.Sprintf(" (gt explain %s)", e.ID())
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+func TestExpandWatchCommand(t *testing.T) {
+	e := townlog.Event{
+		Timestamp: time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC),
+		Type:      townlog.EventCrash,
+		Agent:     "gastown/Toast",
+		Context:   `it's $(rm -rf ~)`,
+	}
+	got := expandWatchCommand("./notify.sh {agent} {type} {context} {unknown}", e)
+	want := `./notify.sh 'gastown/Toast' 'crash' 'it'\''s $(rm -rf ~)' {unknown}`
+	if got != want {
+		t.Errorf("expandWatchCommand() = %s, want %s", got, want)
+	}
+}
+
+func TestLogWatchFilter(t *testing.T) {
+	defer func() { logWatchTypes, logWatchAgent, logWatchSeverity = nil, "", "" }()
+	logWatchTypes, logWatchAgent, logWatchSeverity = []string{"crash,kill"}, "gastown/*", "error"
+
+	filter, err := logWatchFilter(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		e    townlog.Event
+		want bool
+	}{
+		{townlog.Event{Type: townlog.EventCrash, Agent: "gastown/Toast", Severity: townlog.SeverityError}, true},
+		{townlog.Event{Type: townlog.EventKill, Agent: "gastown/crew/max", Severity: townlog.SeverityError}, true},
+		{townlog.Event{Type: townlog.EventSpawn, Agent: "gastown/Toast", Severity: townlog.SeverityError}, false},
+		{townlog.Event{Type: townlog.EventCrash, Agent: "beads/Nux", Severity: townlog.SeverityError}, false},
+		{townlog.Event{Type: townlog.EventCrash, Agent: "gastown/Toast", Severity: townlog.SeverityWarn}, false},
+	} {
+		if got := filter.Match(tc.e); got != tc.want {
+			t.Errorf("filter.Match(%s %s %s) = %v, want %v", tc.e.Type, tc.e.Agent, tc.e.Severity, got, tc.want)
+		}
+	}
+
+	logWatchSeverity = "loud"
+	if _, err := logWatchFilter(t.TempDir()); err == nil {
+		t.Error("logWatchFilter accepted an unknown severity")
+	}
+}
+
+func TestLogWatcherRunsCommands(t *testing.T) {
+	townRoot := t.TempDir()
+	seen := filepath.Join(townRoot, "seen")
+	w := &logWatcher{
+		townRoot: townRoot,
+		// Fails, without recording it, for gt-2
+		exec:    "[ {context} != 'gt-2' ] && echo {type} {context} $GT_EVENT_AGENT >> seen",
+		timeout: 10 * time.Second,
+	}
+	filter := townlog.Filter{Types: []townlog.EventType{townlog.EventCrash, townlog.EventSpawn}}
+	logger := townlog.NewLogger(townRoot)
+	read := func() string {
+		data, _ := os.ReadFile(seen)
+		return string(data)
+	}
+	waitFor := func(ctx context.Context, want string) bool {
+		for ctx.Err() == nil && !strings.Contains(read(), want) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return ctx.Err() == nil
+	}
+
+	var watchErr error
+	out := captureStdout(t, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+		go func() {
+			defer cancel()
+			// Only events logged once the watch is following are acted on
+			for ctx.Err() == nil && !strings.Contains(read(), "warmup") {
+				_ = logger.Log(townlog.EventSpawn, "gastown/Toast", "warmup")
+				time.Sleep(50 * time.Millisecond)
+			}
+			_ = logger.Log(townlog.EventCrash, "gastown/Toast", "gt-1")
+			_ = logger.Log(townlog.EventNudge, "gastown/Toast", "gt-nudge")
+			_ = logger.Log(townlog.EventCrash, "gastown/Toast", "gt-2")
+			_ = logger.Log(townlog.EventCrash, "gastown/Nux", "gt-3")
+			waitFor(ctx, "gt-3")
+		}()
+		watchErr = w.watch(ctx, filter, 10*time.Millisecond)
+	})
+	if watchErr != nil && watchErr != context.Canceled {
+		t.Fatalf("watch() = %v", watchErr)
+	}
+
+	got := read()
+	for _, want := range []string{"crash gt-1 gastown/Toast", "crash gt-3 gastown/Nux"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("commands ran for:\n%s\nmissing %q", got, want)
+		}
+	}
+	for _, unwanted := range []string{"gt-2", "gt-nudge"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("commands ran for:\n%s\nincluding %q", got, unwanted)
+		}
+	}
+	if !strings.Contains(out, "command failed: exit status 1") {
+		t.Errorf("failed command not reported:\n%s", out)
+	}
+	if strings.Count(out, "→ ran command") < 3 {
+		t.Errorf("successful commands not reported:\n%s", out)
+	}
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/gtlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+// Internal logging flags, shared by every command.
+var (
+	logVerbosity int
+	logLevelFlag string
+)
+
+// Internal log state for the current invocation.
+var (
+	internalLogCloser io.Closer = io.NopCloser(nil)
+	internalLogLevel  slog.Level
+	internalLogSet    bool // user asked for verbosity explicitly
+	commandStart      time.Time
+)
+
+func init() {
+	rootCmd.PersistentFlags().CountVarP(&logVerbosity, "verbose", "v",
+		"Increase internal log verbosity (-v debug, -vv trace); logs also go to stderr")
+	rootCmd.PersistentFlags().StringVar(&logLevelFlag, "log-level", "",
+		"Internal log level: trace, debug, info, warn, error (also GT_DEBUG=1|<level>)")
+}
+
+// initInternalLog configures the gt-internal log for this invocation.
+// Precedence: --log-level, then -v/-vv, then GT_DEBUG, then the default.
+// Commands with their own -v flag shadow the global one; --log-level and
+// GT_DEBUG work everywhere.
+func initInternalLog(cmd *cobra.Command) {
+	commandStart = time.Now()
+	internalLogLevel = gtlog.DefaultLevel
+	internalLogSet = false
+
+	if envLevel, ok, err := gtlog.EnvLevel(); err != nil {
+		fmt.Fprintf(os.Stderr, "gt: ignoring GT_DEBUG: %v\n", err)
+	} else if ok {
+		internalLogLevel, internalLogSet = envLevel, true
+	}
+	if logVerbosity > 0 {
+		internalLogLevel, internalLogSet = gtlog.VerbosityLevel(logVerbosity), true
+	}
+	if logLevelFlag != "" {
+		level, err := gtlog.ParseLevel(logLevelFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gt: ignoring --log-level: %v\n", err)
+		} else {
+			internalLogLevel, internalLogSet = level, true
+		}
+	}
+
+	process := "cli"
+	if cmd == daemonRunCmd {
+		process = "daemon"
+	}
+	townRoot, _ := workspace.FindFromCwd()
+	closer, err := gtlog.Init(gtlog.Options{
+		TownRoot: townRoot,
+		Level:    internalLogLevel,
+		Stderr:   internalLogSet,
+		Process:  process,
+	})
+	internalLogCloser = closer
+	if err != nil && internalLogSet {
+		fmt.Fprintf(os.Stderr, "gt: internal log unavailable: %v\n", err)
+	}
+
+	slog.Info("command start", "cmd", buildCommandPath(cmd), "args", os.Args[1:], "town", townRoot)
+}
+
+// finishInternalLog records how the invocation ended and closes the log.
+func finishInternalLog(err error) {
+	if commandStart.IsZero() {
+		return // flag parsing failed before any command ran
+	}
+	attrs := []any{"duration", time.Since(commandStart).Round(time.Millisecond)}
+	if code, ok := IsSilentExit(err); ok {
+		slog.Info("command exit", append(attrs, "code", code)...)
+	} else if err != nil {
+		slog.Warn("command failed", append(attrs, "err", err)...)
+	} else {
+		slog.Info("command done", attrs...)
+	}
+	_ = internalLogCloser.Close()
+}
+
+// internalLogArgs returns flags that propagate an explicitly requested log
+// level to a gt child process (e.g. the daemon spawned by 'gt daemon start').
+func internalLogArgs() []string {
+	if !internalLogSet {
+		return nil
+	}
+	return []string{"--log-level", internalLogLevel.String()}
+}
+
+// persistentPreRun runs before every command.
+func persistentPreRun(cmd *cobra.Command, args []string) error {
+	initAccessibility()
+	initInternalLog(cmd)
+	warnIfDaemonDown(cmd, args)
+	return acquireOpLock(cmd, args)
+}
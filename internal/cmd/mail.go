@@ -35,6 +35,8 @@ var (
 	mailNotify        bool
 	mailSendSelf      bool
 	mailCC            []string // CC recipients
+	mailAttach        []string // files to attach
+	mailTTL           string
 	mailInboxJSON     bool
 	mailReadJSON      bool
 	mailInboxUnread   bool
@@ -45,6 +47,7 @@ var (
 	mailThreadJSON    bool
 	mailReplySubject  string
 	mailReplyMessage  string
+	mailReplyAttach   []string
 
 	// Search flags
 	mailSearchFrom    string
@@ -133,6 +136,15 @@ Priority levels:
 
 Use --urgent as shortcut for --priority 0.
 
+Priority also decides how the recipient hears about the message: urgent
+mail is injected into the recipient's session at once (waking the agent if
+it is asleep), normal and high mail show an arrival banner, and low and
+backlog mail show nothing until the recipient's next mail check.
+
+Use --ttl for coordination mail that is useless once stale; unread mail past
+its TTL is closed and shows as expired in 'gt mail status'. Towns can set a
+default with "default_ttl" in config/messaging.json.
+
 Examples:
   gt mail send greenplace/Toast -s "Status check" -m "How's that bug fix going?"
   gt mail send mayor/ -s "Work complete" -m "Finished gt-abc"
@@ -142,7 +154,10 @@ Examples:
   gt mail send mayor/ -s "Re: Status" -m "Done" --reply-to msg-abc123
   gt mail send --self -s "Handoff" -m "Context for next session"
   gt mail send greenplace/Toast -s "Update" -m "Progress report" --cc overseer
-  gt mail send list:oncall -s "Alert" -m "System down"`,
+  gt mail send list:oncall -s "Alert" -m "System down"
+  gt mail send mayor/ -s "Patch" -m "For review" --attach fix.diff
+  gt mail send gastown/witness -s "Hold merges" -m "Release at 5pm" --ttl 4h
+  git diff | gt mail send greenplace/Toast -s "My diff" --attach -`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runMailSend,
 }
@@ -231,14 +246,16 @@ Examples:
 }
 
 var mailThreadCmd = &cobra.Command{
-	Use:   "thread <thread-id>",
+	Use:   "thread <thread-id|message-id>",
 	Short: "View a message thread",
 	Long: `View all messages in a conversation thread.
 
-Shows messages in chronological order (oldest first).
+Shows the thread as a reply tree: each message is followed by its replies,
+oldest first. Pass a thread ID or the ID of any message in the thread.
 
 Examples:
-  gt mail thread thread-abc123`,
+  gt mail thread thread-abc123
+  gt mail thread msg-abc123`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMailThread,
 }
@@ -255,7 +272,8 @@ This is a convenience command that automatically:
 
 Examples:
   gt mail reply msg-abc123 -m "Thanks, working on it now"
-  gt mail reply msg-abc123 -s "Custom subject" -m "Reply body"`,
+  gt mail reply msg-abc123 -s "Custom subject" -m "Reply body"
+  gt mail reply msg-abc123 -m "Log attached" --attach build.log`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMailReply,
 }
@@ -410,6 +428,8 @@ func init() {
 	mailSendCmd.Flags().BoolVar(&mailPermanent, "permanent", false, "Send as permanent (not ephemeral, synced to remote)")
 	mailSendCmd.Flags().BoolVar(&mailSendSelf, "self", false, "Send to self (auto-detect from cwd)")
 	mailSendCmd.Flags().StringArrayVar(&mailCC, "cc", nil, "CC recipients (can be used multiple times)")
+	mailSendCmd.Flags().StringArrayVarP(&mailAttach, "attach", "A", nil, "Attach a file, or - for stdin (can be used multiple times)")
+	mailSendCmd.Flags().StringVar(&mailTTL, "ttl", "", "Expire the message if still unread after this long (e.g., 30m, 4h, 2d)")
 	_ = mailSendCmd.MarkFlagRequired("subject") // cobra flags: error only at runtime if missing
 
 	// Inbox flags
@@ -433,6 +453,7 @@ func init() {
 	// Reply flags
 	mailReplyCmd.Flags().StringVarP(&mailReplySubject, "subject", "s", "", "Override reply subject (default: Re: <original>)")
 	mailReplyCmd.Flags().StringVarP(&mailReplyMessage, "message", "m", "", "Reply message body (required)")
+	mailReplyCmd.Flags().StringArrayVarP(&mailReplyAttach, "attach", "A", nil, "Attach a file, or - for stdin (can be used multiple times)")
 	_ = mailReplyCmd.MarkFlagRequired("message")
 
 	// Search flags
@@ -457,6 +478,7 @@ func init() {
 	mailCmd.AddCommand(mailArchiveCmd)
 	mailCmd.AddCommand(mailCheckCmd)
 	mailCmd.AddCommand(mailThreadCmd)
+	mailCmd.AddCommand(mailAttachmentsCmd)
 	mailCmd.AddCommand(mailReplyCmd)
 	mailCmd.AddCommand(mailClaimCmd)
 	mailCmd.AddCommand(mailReleaseCmd)
@@ -540,6 +562,19 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Set CC recipients
 	msg.CC = mailCC
 
+	if mailTTL != "" {
+		ttl, err := parseDuration(mailTTL)
+		if err != nil || ttl <= 0 {
+			return fmt.Errorf("invalid --ttl %q: use a duration like 30m, 4h, or 2d", mailTTL)
+		}
+		msg.ExpiresAt = time.Now().Add(ttl)
+	}
+
+	// Store attachments in the artifact store; only references are mailed
+	if msg.Attachments, err = storeAttachments(workDir, mailAttach); err != nil {
+		return err
+	}
+
 	// Handle reply-to: auto-set type to reply and look up thread
 	if mailReplyTo != "" {
 		msg.ReplyTo = mailReplyTo
@@ -584,6 +619,12 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
 	fmt.Printf("  Subject: %s\n", mailSubject)
+	if msg.ID != "" {
+		fmt.Printf("  ID: %s %s\n", msg.ID, style.Dim.Render("(track with 'gt mail status')"))
+	}
+	if !msg.ExpiresAt.IsZero() {
+		fmt.Printf("  Expires: %s\n", msg.ExpiresAt.Format("2006-01-02 15:04"))
+	}
 
 	// Show fan-out recipients for list addresses
 	if len(listRecipients) > 0 {
@@ -593,6 +634,7 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	if len(msg.CC) > 0 {
 		fmt.Printf("  CC: %s\n", strings.Join(msg.CC, ", "))
 	}
+	printAttachmentNames(msg.Attachments)
 	if msg.Type != mail.TypeNotification {
 		fmt.Printf("  Type: %s\n", msg.Type)
 	}
@@ -652,6 +694,9 @@ func runMailInbox(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Urgent mail first, low-priority mail last
+	mail.SortByPriority(messages)
+
 	for _, msg := range messages {
 		readMarker := "●"
 		if msg.Read {
@@ -662,8 +707,13 @@ func runMailInbox(cmd *cobra.Command, args []string) error {
 			typeMarker = fmt.Sprintf(" [%s]", msg.Type)
 		}
 		priorityMarker := ""
-		if msg.Priority == mail.PriorityHigh || msg.Priority == mail.PriorityUrgent {
+		switch msg.Priority {
+		case mail.PriorityUrgent:
+			priorityMarker = " " + style.Error.Render("!!")
+		case mail.PriorityHigh:
 			priorityMarker = " " + style.Bold.Render("!")
+		case mail.PriorityLow:
+			priorityMarker = " " + style.Dim.Render("(low)")
 		}
 		wispMarker := ""
 		if msg.Wisp {
@@ -708,6 +758,10 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 	// Note: We intentionally do NOT mark as read/ack on read.
 	// User must explicitly delete/ack the message.
 	// This preserves handoff messages for reference.
+	// The sender still gets a read receipt (unless reading their own mail).
+	if strings.TrimSuffix(address, "/") != strings.TrimSuffix(msg.From, "/") {
+		mail.RecordRead(workDir, msg.ID)
+	}
 
 	// JSON output
 	if mailReadJSON {
@@ -746,6 +800,11 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 		fmt.Printf("\n%s\n", msg.Body)
 	}
 
+	if len(msg.Attachments) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Attachments:"))
+		printAttachments(workDir, msg.Attachments)
+	}
+
 	return nil
 }
 
@@ -830,6 +889,7 @@ func runMailDelete(cmd *cobra.Command, args []string) error {
 	if err := mailbox.Delete(msgID); err != nil {
 		return fmt.Errorf("deleting message: %w", err)
 	}
+	mail.RecordRead(workDir, msgID)
 
 	fmt.Printf("%s Message deleted\n", style.Bold.Render("✓"))
 	return nil
@@ -859,6 +919,7 @@ func runMailArchive(cmd *cobra.Command, args []string) error {
 		if err := mailbox.Delete(msgID); err != nil {
 			errors = append(errors, fmt.Sprintf("%s: %v", msgID, err))
 		} else {
+			mail.RecordRead(workDir, msgID)
 			archived++
 		}
 	}
@@ -870,7 +931,7 @@ func runMailArchive(cmd *cobra.Command, args []string) error {
 		for _, e := range errors {
 			fmt.Printf("  Error: %s\n", e)
 		}
-		return fmt.Errorf("failed to archive %d messages", len(errors))
+		return partialFailure("failed to archive %d messages", len(errors))
 	}
 
 	if len(args) == 1 {
@@ -932,7 +993,7 @@ func runMailClear(cmd *cobra.Command, args []string) error {
 		for _, e := range errors {
 			fmt.Printf("  Error: %s\n", e)
 		}
-		return fmt.Errorf("failed to clear %d messages", len(errors))
+		return partialFailure("failed to clear %d messages", len(errors))
 	}
 
 	fmt.Printf("%s Cleared %d messages from %s\n",
@@ -1172,16 +1233,34 @@ func runMailCheck(cmd *cobra.Command, args []string) error {
 		if unread > 0 {
 			// Get subjects for context
 			messages, _ := mailbox.ListUnread()
-			var subjects []string
+			mail.SortByPriority(messages)
+
+			// In a DND window only urgent mail gets through
+			window := config.ActiveDNDWindow(workDir, address)
+			var subjects, ids []string
+			held := 0
 			for _, msg := range messages {
-				subjects = append(subjects, fmt.Sprintf("- %s from %s: %s", msg.ID, msg.From, msg.Subject))
+				if window != nil && !msg.Priority.Interrupts() {
+					held++
+					continue
+				}
+				subjects = append(subjects, fmt.Sprintf("- %s from %s: %s%s", msg.ID, msg.From, msg.Subject, injectPriorityTag(msg.Priority)))
+				ids = append(ids, msg.ID)
+			}
+			if len(ids) == 0 {
+				return nil
 			}
+			mail.RecordInjected(workDir, ids...)
 
 			fmt.Println("<system-reminder>")
 			fmt.Printf("You have %d unread message(s) in your inbox.\n\n", unread)
 			for _, s := range subjects {
 				fmt.Println(s)
 			}
+			if held > 0 {
+				fmt.Printf("\n%d non-urgent message(s) held until your DND window ends at %s.\n",
+					held, window.Until(time.Now()).Format("15:04"))
+			}
 			fmt.Println()
 			fmt.Println("Run 'gt mail inbox' to see your messages, or 'gt mail read <id>' for a specific message.")
 			fmt.Println("</system-reminder>")
@@ -1198,6 +1277,17 @@ func runMailCheck(cmd *cobra.Command, args []string) error {
 	return NewSilentExit(1)
 }
 
+// injectPriorityTag marks urgent and low-priority mail in hook output.
+func injectPriorityTag(p mail.Priority) string {
+	switch p {
+	case mail.PriorityUrgent:
+		return " [URGENT - read now]"
+	case mail.PriorityLow:
+		return " (low priority)"
+	}
+	return ""
+}
+
 func runMailThread(cmd *cobra.Command, args []string) error {
 	threadID := args[0]
 
@@ -1217,6 +1307,13 @@ func runMailThread(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting mailbox: %w", err)
 	}
 
+	// Accept any message in the thread in place of the thread ID
+	if !strings.HasPrefix(threadID, "thread-") {
+		if msg, err := mailbox.Get(threadID); err == nil && msg.ThreadID != "" {
+			threadID = msg.ThreadID
+		}
+	}
+
 	messages, err := mailbox.ListByThread(threadID)
 	if err != nil {
 		return fmt.Errorf("getting thread: %w", err)
@@ -1238,7 +1335,9 @@ func runMailThread(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	for i, msg := range messages {
+	for i, entry := range mail.ThreadTree(messages) {
+		msg := entry.Message
+		indent := "  " + strings.Repeat("    ", entry.Depth)
 		typeMarker := ""
 		if msg.Type != "" && msg.Type != mail.TypeNotification {
 			typeMarker = fmt.Sprintf(" [%s]", msg.Type)
@@ -1249,17 +1348,24 @@ func runMailThread(cmd *cobra.Command, args []string) error {
 		}
 
 		if i > 0 {
-			fmt.Printf("  %s\n", style.Dim.Render("│"))
+			fmt.Printf("%s%s\n", indent, style.Dim.Render("│"))
+		}
+		bullet := "●"
+		if entry.Depth > 0 {
+			bullet = "↳"
 		}
-		fmt.Printf("  %s %s%s%s\n", style.Bold.Render("●"), msg.Subject, typeMarker, priorityMarker)
-		fmt.Printf("    %s from %s to %s\n",
+		fmt.Printf("%s%s %s%s%s\n", indent, style.Bold.Render(bullet), msg.Subject, typeMarker, priorityMarker)
+		fmt.Printf("%s  %s from %s to %s\n", indent,
 			style.Dim.Render(msg.ID),
 			msg.From, msg.To)
-		fmt.Printf("    %s\n",
+		fmt.Printf("%s  %s\n", indent,
 			style.Dim.Render(msg.Timestamp.Format("2006-01-02 15:04")))
 
 		if msg.Body != "" {
-			fmt.Printf("    %s\n", msg.Body)
+			fmt.Printf("%s  %s\n", indent, msg.Body)
+		}
+		for _, a := range msg.Attachments {
+			fmt.Printf("%s  %s %s %s\n", indent, style.Dim.Render("📎"), a.Name, style.Dim.Render(shortArtifactID(a.ID)))
 		}
 	}
 
@@ -1317,6 +1423,10 @@ func runMailReply(cmd *cobra.Command, args []string) error {
 		reply.ThreadID = generateThreadID()
 	}
 
+	if reply.Attachments, err = storeAttachments(workDir, mailReplyAttach); err != nil {
+		return err
+	}
+
 	// Send the reply
 	if err := router.Send(reply); err != nil {
 		return fmt.Errorf("sending reply: %w", err)
@@ -1327,6 +1437,7 @@ func runMailReply(cmd *cobra.Command, args []string) error {
 	if original.ThreadID != "" {
 		fmt.Printf("  Thread: %s\n", style.Dim.Render(original.ThreadID))
 	}
+	printAttachmentNames(reply.Attachments)
 
 	return nil
 }
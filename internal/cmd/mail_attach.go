@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/artifact"
+	"github.com/ctiospl/gastown/internal/mail"
+	"github.com/ctiospl/gastown/internal/style"
+)
+
+var (
+	mailAttachmentsOutput string
+	mailAttachmentsJSON   bool
+)
+
+var mailAttachmentsCmd = &cobra.Command{
+	Use:   "attachments <message-id> [name...]",
+	Short: "List or save a message's attachments",
+	Long: `List the files attached to a message, or save them.
+
+Attachments live in the town's artifact store (<town>/.artifacts/), keyed
+by content hash; messages only carry references. The listing shows each
+file's path in the store, which can be read directly.
+
+Examples:
+  gt mail attachments msg-abc123                 # List with store paths
+  gt mail attachments msg-abc123 -o .            # Save all to current dir
+  gt mail attachments msg-abc123 fix.diff -o /tmp`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMailAttachments,
+}
+
+func init() {
+	mailAttachmentsCmd.Flags().StringVarP(&mailAttachmentsOutput, "output", "o", "", "Save attachments into this directory")
+	mailAttachmentsCmd.Flags().BoolVar(&mailAttachmentsJSON, "json", false, "Output as JSON")
+}
+
+// attachmentInfo is an attachment resolved against the artifact store.
+type attachmentInfo struct {
+	mail.Attachment
+	Size int64  `json:"size"`
+	Path string `json:"path,omitempty"` // empty if missing from the store
+}
+
+func runMailAttachments(cmd *cobra.Command, args []string) error {
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	router := mail.NewRouter(workDir)
+	mailbox, err := router.GetMailbox(detectSender())
+	if err != nil {
+		return fmt.Errorf("getting mailbox: %w", err)
+	}
+	msg, err := mailbox.Get(args[0])
+	if err != nil {
+		return fmt.Errorf("getting message: %w", err)
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range args[1:] {
+		wanted[name] = true
+	}
+	var selected []mail.Attachment
+	for _, a := range msg.Attachments {
+		if len(wanted) == 0 || wanted[a.Name] {
+			selected = append(selected, a)
+		}
+	}
+	if len(selected) == 0 {
+		if len(args) > 1 {
+			return fmt.Errorf("message %s has no attachment named %v", msg.ID, args[1:])
+		}
+		fmt.Printf("%s Message %s has no attachments\n", style.Dim.Render("○"), msg.ID)
+		return nil
+	}
+
+	if mailAttachmentsOutput != "" {
+		store := artifact.NewStore(workDir)
+		for _, a := range selected {
+			dst := filepath.Join(mailAttachmentsOutput, a.Name)
+			if err := saveArtifact(store, a.ID, dst); err != nil {
+				return fmt.Errorf("saving %s: %w", a.Name, err)
+			}
+			fmt.Printf("%s Saved %s\n", style.Success.Render("✓"), dst)
+		}
+		return nil
+	}
+
+	if mailAttachmentsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(resolveAttachments(workDir, selected))
+	}
+	printAttachments(workDir, selected)
+	return nil
+}
+
+// storeAttachments adds files to the artifact store and returns references
+// for a message. "-" reads standard input.
+func storeAttachments(townRoot string, paths []string) ([]mail.Attachment, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	store := artifact.NewStore(townRoot)
+	var attachments []mail.Attachment
+	for _, p := range paths {
+		var id string
+		var err error
+		name := p
+		if p == "-" {
+			name = "stdin.txt"
+			id, _, err = store.Put(os.Stdin)
+		} else {
+			id, _, err = store.PutFile(p)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("attaching %s: %w", p, err)
+		}
+		attachments = append(attachments, mail.NewAttachment(id, name))
+	}
+	return attachments, nil
+}
+
+// resolveAttachments looks up each attachment's size and store path.
+func resolveAttachments(townRoot string, attachments []mail.Attachment) []attachmentInfo {
+	store := artifact.NewStore(townRoot)
+	infos := make([]attachmentInfo, 0, len(attachments))
+	for _, a := range attachments {
+		info := attachmentInfo{Attachment: a}
+		if path, err := store.Path(a.ID); err == nil {
+			info.Path = path
+			if st, err := os.Stat(path); err == nil {
+				info.Size = st.Size()
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// printAttachments lists attachments with their sizes and store paths.
+func printAttachments(townRoot string, attachments []mail.Attachment) {
+	for _, info := range resolveAttachments(townRoot, attachments) {
+		if info.Path == "" {
+			fmt.Printf("  📎 %s %s\n", info.Name, style.Warning.Render("(missing from artifact store)"))
+			continue
+		}
+		fmt.Printf("  📎 %s %s\n", info.Name, style.Dim.Render(fmt.Sprintf("(%s, %s)", formatBytes(info.Size), shortArtifactID(info.ID))))
+		fmt.Printf("     %s\n", style.Dim.Render(info.Path))
+	}
+}
+
+// printAttachmentNames prints the attachment line of a send confirmation.
+func printAttachmentNames(attachments []mail.Attachment) {
+	for _, a := range attachments {
+		fmt.Printf("  Attached: %s %s\n", a.Name, style.Dim.Render(shortArtifactID(a.ID)))
+	}
+}
+
+// shortArtifactID abbreviates a content hash for display.
+func shortArtifactID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// saveArtifact copies an artifact out of the store to dst.
+func saveArtifact(store *artifact.Store, id, dst string) error {
+	in, err := store.Open(id)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst) //nolint:gosec // G304: destination chosen by the user
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
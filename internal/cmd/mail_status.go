@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/mail"
+	"github.com/ctiospl/gastown/internal/style"
+)
+
+var (
+	mailStatusAll     bool
+	mailStatusFrom    string
+	mailStatusSince   string
+	mailStatusPending bool
+	mailStatusJSON    bool
+)
+
+var mailStatusCmd = &cobra.Command{
+	Use:   "status [message-id...]",
+	Short: "Show delivery state of sent mail",
+	Long: `Show whether mail you sent actually reached its recipients.
+
+Each message moves through these states:
+
+  sent       in the recipient's mailbox
+  notified   a banner was shown in the recipient's session
+  injected   listed in the recipient's context by the mail hook
+  read       opened, archived, or deleted by the recipient
+  expired    passed its TTL unread and was closed
+
+By default, shows the last day of mail sent from your address.
+
+Examples:
+  gt mail status                    # My recent mail
+  gt mail status hq-abc123          # One message
+  gt mail status --pending          # Sent but not yet seen
+  gt mail status --all --since 1h   # Everyone's mail, last hour
+  gt mail status --from mayor/ --json`,
+	RunE: runMailStatus,
+}
+
+func init() {
+	mailStatusCmd.Flags().BoolVar(&mailStatusAll, "all", false, "Show mail from every sender")
+	mailStatusCmd.Flags().StringVar(&mailStatusFrom, "from", "", "Show mail from this sender (default: you)")
+	mailStatusCmd.Flags().StringVar(&mailStatusSince, "since", "24h", "Only mail sent since duration, time, or mark")
+	mailStatusCmd.Flags().BoolVar(&mailStatusPending, "pending", false, "Only mail the recipient has not seen yet")
+	mailStatusCmd.Flags().BoolVar(&mailStatusJSON, "json", false, "Output as JSON")
+
+	mailCmd.AddCommand(mailStatusCmd)
+}
+
+func runMailStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	receipts, err := mail.ReadReceipts(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading receipts: %w", err)
+	}
+
+	var since time.Time
+	if len(args) == 0 && mailStatusSince != "" {
+		if since, err = resolveTimeBoundary(townRoot, "since", mailStatusSince); err != nil {
+			return err
+		}
+	}
+	from := mailStatusFrom
+	if from == "" && !mailStatusAll && len(args) == 0 {
+		from = detectSender()
+	}
+	wanted := make(map[string]bool)
+	for _, id := range args {
+		wanted[id] = true
+	}
+
+	var statuses []mail.DeliveryStatus
+	for _, d := range mail.Deliveries(receipts, time.Now()) {
+		switch {
+		case len(wanted) > 0 && !wanted[d.MessageID]:
+			continue
+		case from != "" && strings.TrimSuffix(d.From, "/") != strings.TrimSuffix(from, "/"):
+			continue
+		case !since.IsZero() && d.SentAt.Before(since):
+			continue
+		case mailStatusPending && (d.Delivered() || d.State == mail.ReceiptExpired):
+			continue
+		}
+		statuses = append(statuses, d)
+	}
+
+	if mailStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+
+	if len(statuses) == 0 {
+		fmt.Printf("%s No tracked mail matches\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, d := range statuses {
+		fmt.Printf("%s %-9s %s → %s  %s\n",
+			deliveryIcon(d.State), d.State, d.From, d.To, style.Bold.Render(d.Subject))
+		fmt.Printf("    %s sent %s%s\n",
+			style.Dim.Render(d.MessageID),
+			d.SentAt.Format("2006-01-02 15:04"),
+			style.Dim.Render(deliveryTimeline(d)))
+	}
+	return nil
+}
+
+// deliveryIcon renders a delivery state as a status icon.
+func deliveryIcon(state mail.ReceiptState) string {
+	switch state {
+	case mail.ReceiptRead:
+		return style.Success.Render("✓")
+	case mail.ReceiptInjected:
+		return style.Success.Render("●")
+	case mail.ReceiptExpired:
+		return style.Error.Render("✗")
+	case mail.ReceiptNotified:
+		return style.Warning.Render("◐")
+	default:
+		return style.Dim.Render("○")
+	}
+}
+
+// deliveryTimeline describes when later states were reached, relative to
+// sending: ", notified +2s, read +14m".
+func deliveryTimeline(d mail.DeliveryStatus) string {
+	var parts []string
+	for _, state := range []mail.ReceiptState{mail.ReceiptNotified, mail.ReceiptInjected, mail.ReceiptRead, mail.ReceiptExpired} {
+		if t, ok := d.Times[state]; ok {
+			parts = append(parts, fmt.Sprintf("%s +%s", state, formatDuration(t.Sub(d.SentAt))))
+		}
+	}
+	if _, ok := d.Times[mail.ReceiptExpired]; !ok && d.State == mail.ReceiptExpired {
+		parts = append(parts, "expired "+d.ExpiresAt.Format("15:04"))
+	} else if !d.ExpiresAt.IsZero() && d.State != mail.ReceiptRead && d.State != mail.ReceiptExpired {
+		parts = append(parts, "expires "+d.ExpiresAt.Format("2006-01-02 15:04"))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(parts, ", ")
+}
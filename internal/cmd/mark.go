@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/events"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var markCmd = &cobra.Command{
+	Use:     "mark [name]",
+	GroupID: GroupDiag,
+	Short:   "Set a named checkpoint in the town log",
+	Long: `Set a named mark in the town's event stream.
+
+Marks are lightweight checkpoints of the whole town. Use a mark's name in
+place of a duration wherever a command takes --since or --until, to look
+at exactly what happened between two points in time.
+
+Run with no arguments to list marks. If a name is reused, the most recent
+mark with that name wins.
+
+Examples:
+  gt mark "before the big refactor"
+  gt mark                                      # List marks
+  gt log --since "before the big refactor"     # Everything after the mark
+  gt log --since sprint-1 --until sprint-2     # Between two marks
+  gt audit --since "before the big refactor"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMark,
+}
+
+func init() {
+	rootCmd.AddCommand(markCmd)
+}
+
+func runMark(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	existing, err := townlog.ReadArchivedEvents(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+	if len(args) == 0 {
+		return listMarks(existing)
+	}
+
+	name := strings.TrimSpace(args[0])
+	if name == "" {
+		return fmt.Errorf("mark name cannot be empty")
+	}
+	if _, err := parseDuration(name); err == nil {
+		return fmt.Errorf("mark name %q looks like a duration; pick a name that can't be mistaken for one", name)
+	}
+
+	actor := detectActor()
+	if actor == "" || strings.ContainsAny(actor, " \t") {
+		actor = "overseer"
+	}
+	if err := townlog.NewLogger(townRoot).Log(townlog.EventMark, actor, name); err != nil {
+		return fmt.Errorf("writing mark: %w", err)
+	}
+	_ = events.LogFeed(events.TypeMark, actor, events.MarkPayload(name))
+
+	fmt.Printf("%s Marked %s\n", style.Success.Render("✓"), style.Bold.Render(name))
+	if prev, err := townlog.FindMark(existing, name); err == nil && prev.Context == name {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("Replaces the mark of the same name from %s as a boundary",
+			prev.Timestamp.Format("2006-01-02 15:04"))))
+	}
+	return nil
+}
+
+func listMarks(all []townlog.Event) error {
+	var marks []townlog.Event
+	for _, e := range all {
+		if e.Type == townlog.EventMark {
+			marks = append(marks, e)
+		}
+	}
+	if len(marks) == 0 {
+		fmt.Printf("%s No marks set. Create one with: gt mark <name>\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	for _, m := range marks {
+		fmt.Printf("%s  %s  %s %s\n",
+			style.Dim.Render(m.ID()),
+			m.Timestamp.Format("2006-01-02 15:04"),
+			style.Bold.Render(m.Context),
+			style.Dim.Render(fmt.Sprintf("(%s, %s ago)", m.Agent, formatDuration(time.Since(m.Timestamp)))))
+	}
+	return nil
+}
+
+// timestampLayouts are the absolute times --since and --until accept, tried
+// in order. Layouts without a zone are in local time.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseTimestamp parses an absolute time in one of timestampLayouts.
+// dateOnly reports a bare date such as 2026-10-13.
+func parseTimestamp(value string) (t time.Time, dateOnly bool, err error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, layout == "2006-01-02", nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// resolveTimeBoundary turns a --since/--until value into a time. The value
+// is a duration back from now (30m, 24h, 7d), an absolute time (RFC3339,
+// "2026-10-13 22:00" in local time, or a date), or the name or ID of a mark
+// set with 'gt mark'. A date as --until includes the whole day.
+func resolveTimeBoundary(townRoot, flag, value string) (time.Time, error) {
+	if d, err := parseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, dateOnly, err := parseTimestamp(value); err == nil {
+		if dateOnly && flag == "until" {
+			return t.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+		}
+		return t, nil
+	}
+	if townRoot == "" {
+		return time.Time{}, fmt.Errorf("invalid --%s %q: not a duration or timestamp (marks can only be used inside a town)", flag, value)
+	}
+	all, err := townlog.ReadArchivedEvents(townRoot)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reading marks: %w", err)
+	}
+	mark, err := townlog.FindMark(all, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s %q: not a duration, a timestamp (e.g. 2026-10-13T22:00), or the name of a mark (see 'gt mark')", flag, value)
+	}
+	return mark.Timestamp, nil
+}
+
+// checkTimeRange rejects a --since that is after --until.
+func checkTimeRange(since, until time.Time) error {
+	if !since.IsZero() && !until.IsZero() && since.After(until) {
+		return fmt.Errorf("--since (%s) is after --until (%s)",
+			since.Local().Format("2006-01-02 15:04:05"), until.Local().Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
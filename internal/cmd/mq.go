@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/focus"
 	"github.com/ctiospl/gastown/internal/git"
 	"github.com/ctiospl/gastown/internal/refinery"
 	"github.com/ctiospl/gastown/internal/rig"
@@ -305,7 +306,8 @@ func init() {
 }
 
 // findCurrentRig determines the current rig from the working directory.
-// Returns the rig name and rig object, or an error if not in a rig.
+// Outside any rig it falls back to the focused rig (see gt focus).
+// Returns the rig name and rig object, or an error if neither applies.
 func findCurrentRig(townRoot string) (string, *rig.Rig, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -320,12 +322,15 @@ func findCurrentRig(townRoot string) (string, *rig.Rig, error) {
 
 	// The first component of the relative path should be the rig name
 	parts := strings.Split(relPath, string(filepath.Separator))
-	if len(parts) == 0 || parts[0] == "" || parts[0] == "." {
+	var rigName string
+	if len(parts) > 0 && parts[0] != "" && parts[0] != "." {
+		rigName = parts[0]
+	} else if f := focus.Current(townRoot); f != nil && f.Rig != "" {
+		rigName = f.Rig // outside any rig, default to the operator's focus
+	} else {
 		return "", nil, fmt.Errorf("not inside a rig directory")
 	}
 
-	rigName := parts[0]
-
 	// Load rig manager and get the rig
 	rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
 	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
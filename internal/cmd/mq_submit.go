@@ -156,6 +156,10 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 	if worker != "" {
 		description += fmt.Sprintf("\nworker: %s", worker)
 	}
+	stacked, isStacked := stackedOn(filepath.Join(townRoot, rigName), branch)
+	if isStacked {
+		description += fmt.Sprintf("\nstacked_on: %s", stacked.Parent)
+	}
 
 	// Create MR bead (ephemeral wisp - will be cleaned up after merge)
 	mrIssue, err := bd.Create(beads.CreateOptions{
@@ -178,6 +182,9 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Worker: %s\n", worker)
 	}
 	fmt.Printf("  Priority: P%d\n", priority)
+	if isStacked {
+		holdStackedMR(bd, stacked, mrIssue.ID)
+	}
 
 	// Auto-cleanup for polecats: if this is a polecat branch and cleanup not disabled,
 	// send lifecycle request and wait for termination
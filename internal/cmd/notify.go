@@ -29,7 +29,10 @@ Examples:
   gt notify normal    # Default notification level
   gt notify muted     # Enable DND mode
 
-Related: gt dnd - quick toggle for DND mode`,
+Related:
+  gt dnd            quick toggle for DND mode
+  gt notify rules   route town events to channels (quiet hours, escalation)
+  gt notify pending notifications awaiting acknowledgment`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runNotify,
 }
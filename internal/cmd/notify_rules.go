@@ -0,0 +1,492 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/notify"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	notifyRuleEvents     []string
+	notifyRuleRigs       []string
+	notifyRuleLabels     []string
+	notifyRuleSeverity   string
+	notifyRuleChannels   []string
+	notifyRuleQuiet      string
+	notifyRuleQuietAllow string
+	notifyRuleDedup      string
+	notifyRuleEscalate   []string
+
+	notifyTestEvent    string
+	notifyTestRig      string
+	notifyTestLabels   []string
+	notifyTestSeverity string
+
+	notifyChannelURL string
+)
+
+var notifyRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Manage notification routing rules",
+	Long: `Manage routing rules that send town notifications to channels.
+
+Rules live in config/notify.json. Each rule matches notifications by
+event type, rig, label, and minimum severity, and delivers to one or more
+named channels. Rules can also:
+
+  --quiet HH:MM-HH:MM   suppress delivery during quiet hours
+                        (critical still gets through unless --quiet-allow)
+  --dedup <window>      drop repeats of the same notification
+  --escalate <d>:<ch>   notify another channel if unacked after <d>
+                        (repeat for multi-step chains)
+
+Channels are defined with 'gt notify rules channel':
+  mail       target is a mail address (overseer, list:oncall, mayor/)
+  nudge      target is a tmux session (gt-mayor)
+  command    target is a shell command; GT_NOTIFY_* env vars describe the event
+  pagerduty  target is an Events API v2 routing key (or env:VAR)
+  opsgenie   target is an Alert API key (or env:VAR); --url for EU accounts
+
+On-call channels open one incident per condition (an agent's crash loop,
+daemon down, a rig or agent over its gt budget) and resolve it
+automatically when the condition clears. See 'gt notify incidents'.
+
+Without a subcommand, lists channels and rules.
+
+Examples:
+  gt notify rules channel ops mail overseer
+  gt notify rules channel pager command 'page-oncall "$GT_NOTIFY_SUBJECT"'
+  gt notify rules channel pd pagerduty env:PAGERDUTY_ROUTING_KEY
+  gt notify rules add oncall --min-severity critical --channel pd
+  gt notify rules add crashes --event crash --min-severity high --channel ops \
+      --dedup 10m --quiet 22:00-07:00 --escalate 15m:pager
+  gt notify rules test --event crash --rig gastown --severity high
+  gt notify rules remove crashes`,
+	Args: cobra.NoArgs,
+	RunE: runNotifyRulesList,
+}
+
+var notifyRulesAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add or replace a routing rule",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNotifyRulesAdd,
+}
+
+var notifyRulesRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a routing rule",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNotifyRulesRemove,
+}
+
+var notifyRulesChannelCmd = &cobra.Command{
+	Use:   "channel <name> <mail|nudge|command|pagerduty|opsgenie> <target>",
+	Short: "Define or replace a notification channel",
+	Args:  cobra.ExactArgs(3),
+	RunE:  runNotifyRulesChannel,
+}
+
+var notifyRulesTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Show which rules and channels a notification would hit",
+	Long: `Dry-run a notification through the routing rules.
+
+Nothing is delivered; quiet hours and dedup state are evaluated as of now.`,
+	Args: cobra.NoArgs,
+	RunE: runNotifyRulesTest,
+}
+
+var notifyAckCmd = &cobra.Command{
+	Use:   "ack <id>",
+	Short: "Acknowledge a notification and stop its escalation",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNotifyAck,
+}
+
+var notifyIncidentsCmd = &cobra.Command{
+	Use:   "incidents",
+	Short: "List open incidents (conditions raised and not yet resolved)",
+	Args:  cobra.NoArgs,
+	RunE:  runNotifyIncidents,
+}
+
+var notifyResolveCmd = &cobra.Command{
+	Use:   "resolve <condition>",
+	Short: "Manually resolve an open incident",
+	Long: `Resolve an open incident on every channel it was raised on.
+
+Incidents normally resolve on their own when the condition clears; use
+this when a condition was fixed out of band.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNotifyResolve,
+}
+
+var notifyPendingCmd = &cobra.Command{
+	Use:   "pending",
+	Short: "List notifications awaiting acknowledgment",
+	Args:  cobra.NoArgs,
+	RunE:  runNotifyPending,
+}
+
+func init() {
+	notifyRulesAddCmd.Flags().StringSliceVar(&notifyRuleEvents, "event", nil, "Event types to match (repeatable)")
+	notifyRulesAddCmd.Flags().StringSliceVar(&notifyRuleRigs, "rig", nil, "Rigs to match (repeatable)")
+	notifyRulesAddCmd.Flags().StringSliceVar(&notifyRuleLabels, "label", nil, "Labels to match (any, repeatable)")
+	notifyRulesAddCmd.Flags().StringVar(&notifyRuleSeverity, "min-severity", "", "Minimum severity: low, medium, high, critical")
+	notifyRulesAddCmd.Flags().StringSliceVar(&notifyRuleChannels, "channel", nil, "Channels to notify (required, repeatable)")
+	notifyRulesAddCmd.Flags().StringVar(&notifyRuleQuiet, "quiet", "", "Quiet hours window, e.g. 22:00-07:00")
+	notifyRulesAddCmd.Flags().StringVar(&notifyRuleQuietAllow, "quiet-allow", "", "Minimum severity delivered during quiet hours (default: critical)")
+	notifyRulesAddCmd.Flags().StringVar(&notifyRuleDedup, "dedup", "", "Dedup window, e.g. 10m")
+	notifyRulesAddCmd.Flags().StringArrayVar(&notifyRuleEscalate, "escalate", nil, "Escalation step <delay>:<channel>[,<channel>] (repeatable)")
+	_ = notifyRulesAddCmd.MarkFlagRequired("channel")
+
+	notifyRulesTestCmd.Flags().StringVar(&notifyTestEvent, "event", "", "Event type (required)")
+	notifyRulesTestCmd.Flags().StringVar(&notifyTestRig, "rig", "", "Rig name")
+	notifyRulesTestCmd.Flags().StringSliceVar(&notifyTestLabels, "label", nil, "Labels")
+	notifyRulesTestCmd.Flags().StringVar(&notifyTestSeverity, "severity", "medium", "Severity")
+	_ = notifyRulesTestCmd.MarkFlagRequired("event")
+
+	notifyRulesChannelCmd.Flags().StringVar(&notifyChannelURL, "url", "", "API endpoint override for on-call channels")
+
+	notifyRulesCmd.AddCommand(notifyRulesAddCmd)
+	notifyRulesCmd.AddCommand(notifyRulesRemoveCmd)
+	notifyRulesCmd.AddCommand(notifyRulesChannelCmd)
+	notifyRulesCmd.AddCommand(notifyRulesTestCmd)
+
+	notifyCmd.AddCommand(notifyRulesCmd)
+	notifyCmd.AddCommand(notifyAckCmd)
+	notifyCmd.AddCommand(notifyPendingCmd)
+	notifyCmd.AddCommand(notifyIncidentsCmd)
+	notifyCmd.AddCommand(notifyResolveCmd)
+}
+
+func runNotifyRulesList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rules, err := notify.LoadRules(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if len(rules.Channels) == 0 && len(rules.Rules) == 0 {
+		fmt.Printf("%s No notification rules configured\n", style.Dim.Render("○"))
+		fmt.Printf("  Start with: %s\n", style.Bold.Render("gt notify rules channel <name> mail overseer"))
+		return nil
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render("Channels:"))
+	names := make([]string, 0, len(rules.Channels))
+	for name := range rules.Channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ch := rules.Channels[name]
+		fmt.Printf("  %-12s %-8s %s\n", name, ch.Type, style.Dim.Render(ch.Target))
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Rules:"))
+	if len(rules.Rules) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(none)"))
+	}
+	for _, rule := range rules.Rules {
+		fmt.Printf("  %s → %s\n", style.Bold.Render(rule.Name), strings.Join(rule.Channels, ", "))
+		fmt.Printf("    match: %s\n", describeMatch(rule.Match))
+		if q := rule.QuietHours; q != nil {
+			allow := q.AllowSeverity
+			if allow == "" {
+				allow = "critical"
+			}
+			fmt.Printf("    quiet: %s-%s (allows %s)\n", q.Start, q.End, allow)
+		}
+		if rule.Dedup != "" {
+			fmt.Printf("    dedup: %s\n", rule.Dedup)
+		}
+		for i, step := range rule.Escalation {
+			fmt.Printf("    escalate %d: after %s → %s\n", i+1, step.After, strings.Join(step.Channels, ", "))
+		}
+	}
+	return nil
+}
+
+func describeMatch(m notify.Match) string {
+	var parts []string
+	if len(m.Events) > 0 {
+		parts = append(parts, "event="+strings.Join(m.Events, ","))
+	}
+	if len(m.Rigs) > 0 {
+		parts = append(parts, "rig="+strings.Join(m.Rigs, ","))
+	}
+	if len(m.Labels) > 0 {
+		parts = append(parts, "label="+strings.Join(m.Labels, ","))
+	}
+	if m.MinSeverity != "" {
+		parts = append(parts, "severity>="+m.MinSeverity)
+	}
+	if len(parts) == 0 {
+		return "everything"
+	}
+	return strings.Join(parts, " ")
+}
+
+func runNotifyRulesAdd(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rules, err := notify.LoadRules(townRoot)
+	if err != nil {
+		return err
+	}
+
+	rule := notify.Rule{
+		Name: args[0],
+		Match: notify.Match{
+			Events:      notifyRuleEvents,
+			Rigs:        notifyRuleRigs,
+			Labels:      notifyRuleLabels,
+			MinSeverity: strings.ToLower(notifyRuleSeverity),
+		},
+		Channels: notifyRuleChannels,
+		Dedup:    notifyRuleDedup,
+	}
+	if notifyRuleQuiet != "" {
+		q, err := notify.ParseQuietHours(notifyRuleQuiet)
+		if err != nil {
+			return err
+		}
+		q.AllowSeverity = strings.ToLower(notifyRuleQuietAllow)
+		rule.QuietHours = q
+	}
+	for _, spec := range notifyRuleEscalate {
+		step, err := parseEscalationStep(spec)
+		if err != nil {
+			return err
+		}
+		rule.Escalation = append(rule.Escalation, step)
+	}
+
+	existed := rules.GetRule(rule.Name) != nil
+	rules.SetRule(rule)
+	if err := notify.SaveRules(townRoot, rules); err != nil {
+		return fmt.Errorf("saving rules: %w", err)
+	}
+
+	verb := "Added"
+	if existed {
+		verb = "Updated"
+	}
+	fmt.Printf("%s %s rule %s\n", style.SuccessPrefix, verb, style.Bold.Render(rule.Name))
+	return nil
+}
+
+// parseEscalationStep parses "<delay>:<channel>[,<channel>...]".
+func parseEscalationStep(spec string) (notify.EscalationStep, error) {
+	after, channels, ok := strings.Cut(spec, ":")
+	if !ok || channels == "" {
+		return notify.EscalationStep{}, fmt.Errorf("invalid --escalate %q: expected <delay>:<channel>", spec)
+	}
+	if _, err := time.ParseDuration(after); err != nil {
+		return notify.EscalationStep{}, fmt.Errorf("invalid --escalate delay %q: %w", after, err)
+	}
+	return notify.EscalationStep{After: after, Channels: strings.Split(channels, ",")}, nil
+}
+
+func runNotifyRulesRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rules, err := notify.LoadRules(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := rules.RemoveRule(args[0]); err != nil {
+		return err
+	}
+	if err := notify.SaveRules(townRoot, rules); err != nil {
+		return fmt.Errorf("saving rules: %w", err)
+	}
+	fmt.Printf("%s Removed rule %s\n", style.SuccessPrefix, args[0])
+	return nil
+}
+
+func runNotifyRulesChannel(cmd *cobra.Command, args []string) error {
+	name, chType, target := args[0], strings.ToLower(args[1]), args[2]
+	if !notify.IsValidChannelType(chType) {
+		return fmt.Errorf("invalid channel type %q: must be mail, nudge, command, pagerduty, or opsgenie", args[1])
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rules, err := notify.LoadRules(townRoot)
+	if err != nil {
+		return err
+	}
+	rules.Channels[name] = notify.Channel{Type: chType, Target: target, URL: notifyChannelURL}
+	if err := notify.SaveRules(townRoot, rules); err != nil {
+		return fmt.Errorf("saving rules: %w", err)
+	}
+	fmt.Printf("%s Channel %s → %s %s\n", style.SuccessPrefix, style.Bold.Render(name), chType, target)
+	return nil
+}
+
+func runNotifyRulesTest(cmd *cobra.Command, args []string) error {
+	severity, err := notify.ParseSeverity(notifyTestSeverity)
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	router, err := notify.NewRouter(townRoot)
+	if err != nil {
+		return err
+	}
+
+	decisions, err := router.Evaluate(&notify.Notification{
+		Event:    notifyTestEvent,
+		Rig:      notifyTestRig,
+		Labels:   notifyTestLabels,
+		Severity: severity,
+	})
+	if err != nil {
+		return err
+	}
+	if len(decisions) == 0 {
+		fmt.Printf("%s No rules match\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, d := range decisions {
+		if d.Suppressed != "" {
+			fmt.Printf("  %s %s: suppressed (%s)\n", style.Dim.Render("○"), d.Rule, d.Suppressed)
+			continue
+		}
+		fmt.Printf("  %s %s → %s\n", style.Bold.Render("✓"), d.Rule, strings.Join(d.Channels, ", "))
+	}
+	return nil
+}
+
+func runNotifyAck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	router, err := notify.NewRouter(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := router.Ack(args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("%s Acknowledged %s\n", style.SuccessPrefix, args[0])
+	return nil
+}
+
+func runNotifyPending(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	router, err := notify.NewRouter(townRoot)
+	if err != nil {
+		return err
+	}
+	pending, err := router.ListPending()
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Printf("%s No notifications awaiting acknowledgment\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	table := style.NewTable(
+		style.Column{Name: "ID", Width: 12},
+		style.Column{Name: "RULE", Width: 14},
+		style.Column{Name: "SEVERITY", Width: 8},
+		style.Column{Name: "NEXT", Width: 10},
+		style.Column{Name: "SUBJECT", Width: 40},
+	)
+	for _, p := range pending {
+		next := time.Until(p.NextAt).Round(time.Second)
+		nextStr := "due"
+		if next > 0 {
+			nextStr = next.String()
+		}
+		table.AddRow(p.ID, p.Rule, p.Notification.Severity.String(), nextStr, p.Notification.Subject)
+	}
+	fmt.Print(table.Render())
+	return nil
+}
+
+func runNotifyIncidents(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	router, err := notify.NewRouter(townRoot)
+	if err != nil {
+		return err
+	}
+	open, err := router.ListOpen()
+	if err != nil {
+		return err
+	}
+	if len(open) == 0 {
+		fmt.Printf("%s No open incidents\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	table := style.NewTable(
+		style.Column{Name: "CONDITION", Width: 32},
+		style.Column{Name: "OPENED", Width: 14},
+		style.Column{Name: "CHANNELS", Width: 20},
+		style.Column{Name: "SUBJECT", Width: 40},
+	)
+	for _, inc := range open {
+		table.AddRow(inc.Condition, formatAge(inc.OpenedAt),
+			strings.Join(inc.Channels, ","), inc.Notification.Subject)
+	}
+	fmt.Print(table.Render())
+	return nil
+}
+
+func runNotifyResolve(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if !notify.IsOpen(townRoot, args[0]) {
+		return fmt.Errorf("no open incident for condition %q", args[0])
+	}
+	deliveries, err := notify.Clear(townRoot, args[0], "resolved manually")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Resolved %s on %d channel(s)\n", style.SuccessPrefix, args[0], len(deliveries))
+	return nil
+}
+
+// routeNotification sends n through the town's routing rules.
+// Routing is best-effort: failures are reported as warnings, never errors,
+// so notification problems never block the operation that raised them.
+func routeNotification(townRoot string, n *notify.Notification) []notify.Delivery {
+	deliveries, err := notify.Route(townRoot, n)
+	if err != nil {
+		style.PrintWarning("notification routing: %v", err)
+	}
+	return deliveries
+}
@@ -6,22 +6,41 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/agentrt"
 	"github.com/ctiospl/gastown/internal/beads"
 	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/daemon"
+	"github.com/ctiospl/gastown/internal/errcode"
 	"github.com/ctiospl/gastown/internal/events"
+	"github.com/ctiospl/gastown/internal/idle"
+	"github.com/ctiospl/gastown/internal/mail"
 	"github.com/ctiospl/gastown/internal/session"
 	"github.com/ctiospl/gastown/internal/style"
 	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
 	"github.com/ctiospl/gastown/internal/workspace"
 )
 
 var nudgeMessageFlag string
 var nudgeForceFlag bool
+var nudgePriorityFlag string
+var nudgeAllFlag bool
+var nudgeAgentFlag string
+var nudgeSelectorFlag string
+
+// nudgeWakeTimeout bounds how long an urgent nudge waits for a woken agent.
+const nudgeWakeTimeout = 60 * time.Second
 
 func init() {
 	rootCmd.AddCommand(nudgeCmd)
 	nudgeCmd.Flags().StringVarP(&nudgeMessageFlag, "message", "m", "", "Message to send")
 	nudgeCmd.Flags().BoolVarP(&nudgeForceFlag, "force", "f", false, "Send even if target has DND enabled")
+	nudgeCmd.Flags().StringVar(&nudgePriorityFlag, "priority", "normal", "Delivery priority: low, normal, or urgent")
+	nudgeCmd.Flags().BoolVar(&nudgeAllFlag, "all", false, "Nudge every running agent (no target)")
+	nudgeCmd.Flags().StringVar(&nudgeAgentFlag, "agent", "", "Nudge every running agent matching a pattern, e.g. 'gastown/crew/*' (no target)")
+	nudgeCmd.Flags().StringVar(&nudgeSelectorFlag, "selector", "", "Nudge every running agent whose labels match a selector, e.g. team=frontend (no target)")
+	nudgeCmd.MarkFlagsMutuallyExclusive("all", "agent")
+	nudgeCmd.MarkFlagsMutuallyExclusive("all", "selector")
 }
 
 var nudgeCmd = &cobra.Command{
@@ -51,7 +70,29 @@ Channel syntax:
 
 DND (Do Not Disturb):
   If the target has DND enabled (gt dnd on), the nudge is skipped.
-  Use --force to override DND and send anyway.
+  If the target is in a scheduled DND window (gt agent dnd), the nudge is
+  deferred to its mailbox as a low-priority nudge.
+  Use --force or --priority urgent to override DND and send anyway.
+
+Priority (--priority):
+  low      Not injected; delivered as low-priority mail that the agent
+           sees at its next natural wake (mail check hook or gt prime)
+  normal   Injected into the running session (default)
+  urgent   Bypasses DND, wakes the agent if it is asleep, then injects
+
+Broadcast (--all, --agent, --selector):
+  Instead of a target, --all nudges every running agent and --agent every
+  running agent whose address matches a pattern, as gt log --agent
+  matches them: "gastown/crew/*", "*/witness", "gastown/*" for a whole
+  rig, or a plain prefix such as "gastown/". --selector nudges the
+  running agents whose labels (gt spawn --label) match a selector such as
+  "team=frontend,lang!=go", and narrows --agent. The sender is left out. Each
+  recipient is logged as its own nudge event, and agents in a DND window
+  get the nudge in their mailbox.
+
+Runtimes without the nudge capability (see 'gt agent info'):
+  The message is delivered as mail instead, since text injected into
+  their session would be ignored.
 
 Examples:
   gt nudge greenplace/furiosa "Check your mail and start working"
@@ -59,12 +100,25 @@ Examples:
   gt nudge mayor "Status update requested"
   gt nudge witness "Check polecat health"
   gt nudge deacon session-started
-  gt nudge channel:workers "New priority work available"`,
-	Args: cobra.RangeArgs(1, 2),
+  gt nudge channel:workers "New priority work available"
+  gt nudge greenplace/furiosa --priority urgent "Stop: main is broken"
+  gt nudge mayor --priority low "FYI: nightly digest is ready"
+  gt nudge --agent 'gastown/crew/*' "please rebase on main"
+  gt nudge --selector team=frontend "The API schema changed; regenerate clients"
+  gt nudge --all "Merge freeze until 17:00"`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if nudgeAllFlag || nudgeAgentFlag != "" || nudgeSelectorFlag != "" {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.RangeArgs(1, 2)(cmd, args)
+	},
 	RunE: runNudge,
 }
 
 func runNudge(cmd *cobra.Command, args []string) error {
+	if nudgeAllFlag || nudgeAgentFlag != "" || nudgeSelectorFlag != "" {
+		return runNudgeBroadcast(args)
+	}
 	target := args[0]
 
 	// Get message from -m flag or positional arg
@@ -77,39 +131,26 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("message required: use -m flag or provide as second argument")
 	}
 
+	priority, err := parseNudgePriority(nudgePriorityFlag)
+	if err != nil {
+		return err
+	}
+
 	// Handle channel syntax: channel:<name>
 	if strings.HasPrefix(target, "channel:") {
 		channelName := strings.TrimPrefix(target, "channel:")
-		return runNudgeChannel(channelName, message)
+		return runNudgeChannel(channelName, message, priority)
 	}
 
 	// Identify sender for message prefix
-	sender := "unknown"
-	if roleInfo, err := GetRole(); err == nil {
-		switch roleInfo.Role {
-		case RoleMayor:
-			sender = "mayor"
-		case RoleCrew:
-			sender = fmt.Sprintf("%s/crew/%s", roleInfo.Rig, roleInfo.Polecat)
-		case RolePolecat:
-			sender = fmt.Sprintf("%s/%s", roleInfo.Rig, roleInfo.Polecat)
-		case RoleWitness:
-			sender = fmt.Sprintf("%s/witness", roleInfo.Rig)
-		case RoleRefinery:
-			sender = fmt.Sprintf("%s/refinery", roleInfo.Rig)
-		case RoleDeacon:
-			sender = "deacon"
-		default:
-			sender = string(roleInfo.Role)
-		}
-	}
+	sender := nudgeSender()
 
 	// Prefix message with sender
-	message = fmt.Sprintf("[from %s] %s", sender, message)
+	message = nudgePrefix(sender, priority) + message
 
-	// Check DND status for target (unless force flag or channel target)
+	// Check DND status for target (unless force flag, urgent, or channel target)
 	townRoot, _ := workspace.FindFromCwd()
-	if townRoot != "" && !nudgeForceFlag && !strings.HasPrefix(target, "channel:") {
+	if townRoot != "" && !nudgeForceFlag && !priority.Interrupts() && !strings.HasPrefix(target, "channel:") {
 		shouldSend, level, _ := shouldNudgeTarget(townRoot, target, nudgeForceFlag)
 		if !shouldSend {
 			fmt.Printf("%s Target has DND enabled (%s) - nudge skipped\n", style.Dim.Render("○"), level)
@@ -118,6 +159,49 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Scheduled DND windows defer non-urgent nudges to the mailbox
+	if townRoot != "" && !nudgeForceFlag && !priority.Interrupts() {
+		if agent := nudgeTargetAgent(target); agent != "" {
+			if w := config.ActiveDNDWindow(townRoot, agent); w != nil {
+				fmt.Printf("%s %s is in DND window %s until %s - nudge deferred\n",
+					style.Dim.Render("○"), agent, w.ID, w.Until(time.Now()).Format("15:04"))
+				priority = mail.PriorityLow
+			}
+		}
+	}
+
+	// Chaos mode: delay delivery to exercise timeout handling
+	if townRoot != "" {
+		if delay := daemon.ChaosNudgeDelay(townRoot); delay > 0 {
+			time.Sleep(delay)
+		}
+	}
+
+	// Low-priority nudges wait in the mailbox for the agent's next wake
+	if priority.Batched() {
+		if townRoot == "" {
+			return fmt.Errorf("low-priority nudges need a Gas Town workspace")
+		}
+		agent := nudgeTargetAgent(target)
+		if agent == "" {
+			id, err := session.ParseSessionName(target)
+			if err != nil {
+				return fmt.Errorf("cannot queue low-priority nudge for %q: %w", target, err)
+			}
+			agent = id.Address()
+		}
+		return nudgeViaMail(townRoot, agent, sender, message, priority)
+	}
+
+	// Runtimes that can't act on injected text get the nudge as mail
+	if townRoot != "" {
+		if agent := nudgeTargetAgent(target); agent != "" {
+			if !config.ResolveCapabilities(townRoot, agent).Has(config.CapNudge) {
+				return nudgeViaMail(townRoot, agent, sender, message, mail.PriorityHigh)
+			}
+		}
+	}
+
 	t := tmux.NewTmux()
 
 	// Expand role shortcuts to session names
@@ -149,13 +233,13 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("checking deacon session: %w", err)
 		}
-		if !exists {
+		if !exists && !priority.Interrupts() {
 			// Deacon not running - this is not an error, just log and return
 			fmt.Printf("%s Deacon not running, nudge skipped\n", style.Dim.Render("○"))
 			return nil
 		}
 
-		if err := t.NudgeSession(deaconSession, message); err != nil {
+		if err := deliverNudge(t, townRoot, deaconSession, message, priority); err != nil {
 			return fmt.Errorf("nudging deacon: %w", err)
 		}
 
@@ -194,7 +278,7 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		}
 
 		// Send nudge using the reliable NudgeSession
-		if err := t.NudgeSession(sessionName, message); err != nil {
+		if err := deliverNudge(t, townRoot, sessionName, message, priority); err != nil {
 			return fmt.Errorf("nudging session: %w", err)
 		}
 
@@ -211,11 +295,11 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("checking session: %w", err)
 		}
-		if !exists {
-			return fmt.Errorf("session %q not found", target)
+		if !exists && !priority.Interrupts() {
+			return errcode.New(errcode.SessionDead, "session %q not found", target)
 		}
 
-		if err := t.NudgeSession(target, message); err != nil {
+		if err := deliverNudge(t, townRoot, target, message, priority); err != nil {
 			return fmt.Errorf("nudging session: %w", err)
 		}
 
@@ -231,8 +315,109 @@ func runNudge(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// nudgeTargetAgent returns the agent address for a nudge target, or "" for
+// raw session names whose agent can't be determined.
+func nudgeTargetAgent(target string) string {
+	if target == "mayor" || target == "deacon" || strings.Contains(target, "/") {
+		return target
+	}
+	return ""
+}
+
+// parseNudgePriority parses a --priority value. Nudges have three levels;
+// high is accepted as normal for symmetry with mail.
+func parseNudgePriority(s string) (mail.Priority, error) {
+	switch p := mail.Priority(strings.ToLower(s)); p {
+	case mail.PriorityLow, mail.PriorityUrgent:
+		return p, nil
+	case "", mail.PriorityNormal, mail.PriorityHigh:
+		return mail.PriorityNormal, nil
+	}
+	return "", fmt.Errorf("invalid priority %q: must be low, normal, or urgent", s)
+}
+
+// nudgePrefix returns the sender prefix for a nudge, flagging urgent ones.
+func nudgePrefix(sender string, priority mail.Priority) string {
+	if priority.Interrupts() {
+		return fmt.Sprintf("[URGENT from %s] ", sender)
+	}
+	return fmt.Sprintf("[from %s] ", sender)
+}
+
+// deliverNudge injects a nudge into a session, the way the agent's runtime
+// takes input. An agent put to sleep for being idle is woken first, and
+// urgent nudges also wake an agent that is not running and wait for its
+// runtime to come up.
+func deliverNudge(t *tmux.Tmux, townRoot, sessionName, message string, priority mail.Priority) error {
+	rt := nudgeRuntime(townRoot, sessionName)
+	wakeIdle(t, townRoot, sessionName, "nudged")
+	if priority.Interrupts() {
+		woke, err := session.Wake(t, sessionName)
+		if err != nil {
+			return fmt.Errorf("waking %s: %w", sessionName, err)
+		}
+		if woke {
+			fmt.Printf("%s Woke %s for urgent nudge\n", style.Dim.Render("○"), sessionName)
+			_ = agentrt.WaitReady(t, rt, sessionName, nudgeWakeTimeout)
+		}
+	}
+	return rt.Nudge(t, sessionName, message)
+}
+
+// wakeIdle wakes the agent of a session if it was put to sleep for being
+// idle, now that work has arrived for it.
+func wakeIdle(t *tmux.Tmux, townRoot, sessionName, why string) {
+	if townRoot == "" {
+		return
+	}
+	woke, err := idle.Wake(t, townRoot, sessionName, why)
+	if err != nil {
+		style.PrintWarning("waking %s: %v", sessionName, err)
+	} else if woke {
+		fmt.Printf("%s Woke %s from idle sleep\n", style.Dim.Render("○"), sessionName)
+	}
+}
+
+// nudgeRuntime returns the runtime adapter for a session's agent, or
+// Claude's if the session is not a Gas Town agent's.
+func nudgeRuntime(townRoot, sessionName string) agentrt.Adapter {
+	if townRoot != "" {
+		if id, err := session.ParseSessionName(sessionName); err == nil {
+			return agentrt.ForAgent(townRoot, id.Address())
+		}
+	}
+	return agentrt.For(townRoot, string(config.AgentClaude))
+}
+
+// nudgeViaMail delivers a nudge as mail: to agents whose runtime lacks the
+// nudge capability, and for low-priority nudges that wait for the next wake.
+func nudgeViaMail(townRoot, agent, sender, message string, priority mail.Priority) error {
+	to := agent
+	if !strings.Contains(to, "/") {
+		to += "/"
+	}
+	msg := &mail.Message{
+		From:     sender,
+		To:       to,
+		Subject:  "Nudge",
+		Body:     message,
+		Priority: priority,
+	}
+	if err := mail.NewRouter(townRoot).Send(msg); err != nil {
+		return fmt.Errorf("mailing nudge: %w", err)
+	}
+
+	if priority.Batched() {
+		fmt.Printf("%s Queued low-priority nudge for %s (delivered at its next wake)\n", style.Dim.Render("○"), agent)
+	} else {
+		fmt.Printf("%s %s's runtime does not support nudges; sent as mail instead\n", style.WarningPrefix, agent)
+	}
+	_ = LogNudge(townRoot, agent, message)
+	return nil
+}
+
 // runNudgeChannel nudges all members of a named channel.
-func runNudgeChannel(channelName, message string) error {
+func runNudgeChannel(channelName, message string, priority mail.Priority) error {
 	// Find town root
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -257,28 +442,10 @@ func runNudgeChannel(channelName, message string) error {
 	}
 
 	// Identify sender for message prefix
-	sender := "unknown"
-	if roleInfo, err := GetRole(); err == nil {
-		switch roleInfo.Role {
-		case RoleMayor:
-			sender = "mayor"
-		case RoleCrew:
-			sender = fmt.Sprintf("%s/crew/%s", roleInfo.Rig, roleInfo.Polecat)
-		case RolePolecat:
-			sender = fmt.Sprintf("%s/%s", roleInfo.Rig, roleInfo.Polecat)
-		case RoleWitness:
-			sender = fmt.Sprintf("%s/witness", roleInfo.Rig)
-		case RoleRefinery:
-			sender = fmt.Sprintf("%s/refinery", roleInfo.Rig)
-		case RoleDeacon:
-			sender = "deacon"
-		default:
-			sender = string(roleInfo.Role)
-		}
-	}
+	sender := nudgeSender()
 
 	// Prefix message with sender
-	prefixedMessage := fmt.Sprintf("[from %s] %s", sender, message)
+	prefixedMessage := nudgePrefix(sender, priority) + message
 
 	// Get all running sessions for pattern matching
 	agents, err := getAgentSessions(true)
@@ -305,21 +472,134 @@ func runNudgeChannel(channelName, message string) error {
 		return nil
 	}
 
-	// Send nudges
+	fmt.Printf("Nudging channel %q (%d target(s))...\n\n", channelName, len(targets))
+	err = fanOutNudge(townRoot, targets, sender, prefixedMessage, priority)
+
+	// Log nudge event
+	_ = events.LogFeed(events.TypeNudge, sender, events.NudgePayload("", "channel:"+channelName, message))
+	return err
+}
+
+// runNudgeBroadcast nudges every running agent (--all) or every running
+// agent matching --agent and --selector.
+func runNudgeBroadcast(args []string) error {
+	var message string
+	if nudgeMessageFlag != "" {
+		message = nudgeMessageFlag
+	} else if len(args) == 1 {
+		message = args[0]
+	} else {
+		return fmt.Errorf("message required: use -m flag or provide as argument")
+	}
+	priority, err := parseNudgePriority(nudgePriorityFlag)
+	if err != nil {
+		return err
+	}
+	if nudgeAgentFlag != "" {
+		if err := townlog.ValidateAgentGlob(nudgeAgentFlag); err != nil {
+			return err
+		}
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	agents, err := getAgentSessions(true)
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+	var sessions []string
+	for _, a := range agents {
+		sessions = append(sessions, a.Name)
+	}
+	sender := nudgeSender()
+	pattern, label := nudgeAgentFlag, nudgeAgentFlag
+	if nudgeAllFlag {
+		pattern, label = "", "all agents"
+	}
+	targets := matchNudgeSessions(sessions, pattern, sender)
+	if nudgeSelectorFlag != "" {
+		selected, err := selectAgents(townRoot, nudgeSelectorFlag)
+		if err != nil {
+			return err
+		}
+		targets = selectSessions(targets, selected)
+		label = strings.TrimSpace(nudgeAgentFlag + " " + nudgeSelectorFlag)
+	}
+	if len(targets) == 0 {
+		fmt.Printf("%s No running agents match %q\n", style.WarningPrefix, label)
+		return nil
+	}
+
+	fmt.Printf("Nudging %s (%d target(s))...\n\n", label, len(targets))
+	err = fanOutNudge(townRoot, targets, sender, nudgePrefix(sender, priority)+message, priority)
+	_ = events.LogFeed(events.TypeNudge, sender, events.NudgePayload("", label, message))
+	return err
+}
+
+// matchNudgeSessions returns the agent sessions whose agent matches
+// pattern (see townlog.MatchAgent; "" matches every agent), leaving out
+// the sender's own.
+func matchNudgeSessions(sessions []string, pattern, sender string) []string {
+	var targets []string
+	for _, name := range sessions {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		agent := id.Address()
+		if townlog.AgentKey(agent) == townlog.AgentKey(sender) {
+			continue
+		}
+		if pattern == "" || townlog.MatchAgent(pattern, agent) || townlog.MatchAgent(pattern, townlog.AgentKey(agent)) {
+			targets = append(targets, name)
+		}
+	}
+	return targets
+}
+
+// selectSessions returns the agent sessions of the selected agents.
+func selectSessions(sessions, agents []string) []string {
+	keys := make(map[string]bool, len(agents))
+	for _, a := range agents {
+		keys[townlog.AgentKey(a)] = true
+	}
+	var out []string
+	for _, name := range sessions {
+		if id, err := session.ParseSessionName(name); err == nil && keys[townlog.AgentKey(id.Address())] {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// fanOutNudge nudges each session in turn, logging a nudge event for each
+// recipient. Agents in a scheduled DND window, and every agent for a
+// low-priority nudge, get it in their mailbox instead.
+func fanOutNudge(townRoot string, targets []string, sender, message string, priority mail.Priority) error {
 	t := tmux.NewTmux()
 	var succeeded, failed int
 	var failures []string
 
-	fmt.Printf("Nudging channel %q (%d target(s))...\n\n", channelName, len(targets))
-
 	for i, sessionName := range targets {
-		if err := t.NudgeSession(sessionName, prefixedMessage); err != nil {
+		var err error
+		if priority.Batched() || (!priority.Interrupts() && sessionInDND(townRoot, sessionName)) {
+			err = queueChannelNudge(townRoot, sessionName, sender, message)
+		} else {
+			wakeIdle(t, townRoot, sessionName, "nudged")
+			err = nudgeRuntime(townRoot, sessionName).Nudge(t, sessionName, message)
+		}
+		if err != nil {
 			failed++
 			failures = append(failures, fmt.Sprintf("%s: %v", sessionName, err))
 			fmt.Printf("  %s %s\n", style.ErrorPrefix, sessionName)
 		} else {
 			succeeded++
 			fmt.Printf("  %s %s\n", style.SuccessPrefix, sessionName)
+			if id, err := session.ParseSessionName(sessionName); err == nil {
+				_ = LogNudge(townRoot, id.Address(), message)
+			}
 		}
 
 		// Small delay between nudges
@@ -330,28 +610,81 @@ func runNudgeChannel(channelName, message string) error {
 
 	fmt.Println()
 
-	// Log nudge event
-	_ = events.LogFeed(events.TypeNudge, sender, events.NudgePayload("", "channel:"+channelName, message))
-
 	if failed > 0 {
-		fmt.Printf("%s Channel nudge complete: %d succeeded, %d failed\n",
+		fmt.Printf("%s Nudge complete: %d succeeded, %d failed\n",
 			style.WarningPrefix, succeeded, failed)
 		for _, f := range failures {
 			fmt.Printf("  %s\n", style.Dim.Render(f))
 		}
-		return fmt.Errorf("%d nudge(s) failed", failed)
+		return partialFailure("%d nudge(s) failed", failed)
 	}
 
-	fmt.Printf("%s Channel nudge complete: %d target(s) nudged\n", style.SuccessPrefix, succeeded)
+	fmt.Printf("%s Nudge complete: %d target(s) nudged\n", style.SuccessPrefix, succeeded)
 	return nil
 }
 
+// nudgeSender returns the address nudges from this session are signed
+// with.
+func nudgeSender() string {
+	roleInfo, err := GetRole()
+	if err != nil {
+		return "unknown"
+	}
+	switch roleInfo.Role {
+	case RoleMayor:
+		return "mayor"
+	case RoleCrew:
+		return fmt.Sprintf("%s/crew/%s", roleInfo.Rig, roleInfo.Polecat)
+	case RolePolecat:
+		return fmt.Sprintf("%s/%s", roleInfo.Rig, roleInfo.Polecat)
+	case RoleWitness:
+		return fmt.Sprintf("%s/witness", roleInfo.Rig)
+	case RoleRefinery:
+		return fmt.Sprintf("%s/refinery", roleInfo.Rig)
+	case RoleDeacon:
+		return "deacon"
+	default:
+		return string(roleInfo.Role)
+	}
+}
+
+// sessionInDND reports whether the agent behind a session is in a scheduled
+// DND window.
+func sessionInDND(townRoot, sessionName string) bool {
+	id, err := session.ParseSessionName(sessionName)
+	if err != nil {
+		return false
+	}
+	return config.ActiveDNDWindow(townRoot, id.Address()) != nil
+}
+
+// queueChannelNudge sends a low-priority channel nudge to one member's
+// mailbox.
+func queueChannelNudge(townRoot, sessionName, sender, message string) error {
+	id, err := session.ParseSessionName(sessionName)
+	if err != nil {
+		return err
+	}
+	to := id.Address()
+	if !strings.Contains(to, "/") {
+		to += "/"
+	}
+	return mail.NewRouter(townRoot).Send(&mail.Message{
+		From:     sender,
+		To:       to,
+		Subject:  "Nudge",
+		Body:     message,
+		Priority: mail.PriorityLow,
+	})
+}
+
 // resolveNudgePattern resolves a nudge channel pattern to session names.
 // Patterns can be:
 //   - Literal: "gastown/witness" → gt-gastown-witness
 //   - Wildcard: "gastown/polecats/*" → all polecat sessions in gastown
 //   - Role: "*/witness" → all witness sessions
 //   - Special: "mayor", "deacon" → gt-{town}-mayor, gt-{town}-deacon
+//
 // townName is used to generate the correct session names for mayor/deacon.
 func resolveNudgePattern(pattern string, agents []*AgentSession) []string {
 	var results []string
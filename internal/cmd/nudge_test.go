@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"reflect"
 	"testing"
+
+	"github.com/ctiospl/gastown/internal/mail"
 )
 
 func TestResolveNudgePattern(t *testing.T) {
@@ -110,3 +113,68 @@ func TestResolveNudgePattern(t *testing.T) {
 		})
 	}
 }
+
+func TestParseNudgePriority(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    mail.Priority
+		wantErr bool
+	}{
+		{"low", mail.PriorityLow, false},
+		{"", mail.PriorityNormal, false},
+		{"high", mail.PriorityNormal, false},
+		{"URGENT", mail.PriorityUrgent, false},
+		{"backlog", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseNudgePriority(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseNudgePriority(%q) = %q, %v; want %q, err=%v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMatchNudgeSessions(t *testing.T) {
+	sessions := []string{
+		"gt-mayor",
+		"gt-gastown-witness",
+		"gt-gastown-crew-max",
+		"gt-gastown-crew-joe",
+		"gt-gastown-Toast",
+		"gt-beads-crew-max",
+		"not-gastown",
+	}
+	tests := []struct {
+		pattern string
+		sender  string
+		want    []string
+	}{
+		{"", "mayor", []string{"gt-gastown-witness", "gt-gastown-crew-max", "gt-gastown-crew-joe", "gt-gastown-Toast", "gt-beads-crew-max"}},
+		{"gastown/crew/*", "unknown", []string{"gt-gastown-crew-max", "gt-gastown-crew-joe"}},
+		{"gastown/crew/*", "gastown/crew/joe", []string{"gt-gastown-crew-max"}},
+		{"*/crew/max", "mayor", []string{"gt-gastown-crew-max", "gt-beads-crew-max"}},
+		{"gastown/*", "gastown/Toast", []string{"gt-gastown-witness", "gt-gastown-crew-max", "gt-gastown-crew-joe"}},
+		{"gastown/Toast", "mayor", []string{"gt-gastown-Toast"}},
+		{"*/witness", "mayor", []string{"gt-gastown-witness"}},
+		{"nothing/*", "mayor", nil},
+	}
+	for _, tt := range tests {
+		got := matchNudgeSessions(sessions, tt.pattern, tt.sender)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("matchNudgeSessions(%q, sender %q) = %v, want %v", tt.pattern, tt.sender, got, tt.want)
+		}
+	}
+}
+
+func TestSelectSessions(t *testing.T) {
+	sessions := []string{"gt-mayor", "gt-gastown-crew-max", "gt-gastown-crew-joe", "gt-gastown-Toast"}
+	got := selectSessions(sessions, []string{"gastown/crew/max", "gastown/polecats/Toast", "beads/crew/max"})
+	want := []string{"gt-gastown-crew-max", "gt-gastown-Toast"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("selectSessions = %v, want %v", got, want)
+	}
+	if got := selectSessions(sessions, nil); got != nil {
+		t.Errorf("selectSessions(no agents) = %v, want none", got)
+	}
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/oplock"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+// lockedCommands are the mutating commands that take the town's operation
+// lock, so concurrent invocations from operators, cron, and hooks do not
+// start, stop, or reshape the town at the same time. Subcommands of a
+// listed command are not locked unless listed too.
+var lockedCommands = []string{
+	"up", "down", "start", "stop", "shutdown", "install", "sling", "migrate-agents",
+	"rig add", "rig boot", "rig reboot", "rig remove", "rig reset", "rig restart",
+	"rig shutdown", "rig start", "rig stop",
+	"crew add", "crew remove", "crew rename", "crew restart", "crew start", "crew stop",
+	"polecat add", "polecat gc", "polecat nuke", "polecat remove",
+	"worktree remove", "cache clean", "prune agents",
+}
+
+// opLockAnnotation marks a command as taking the operation lock.
+const opLockAnnotation = "gt-oplock"
+
+// Operation lock flags, added to each locked command.
+var (
+	opLockWait   waitValue
+	opLockNoWait bool
+)
+
+// opLock is the slot held by this invocation, if any.
+var opLock *oplock.Lock
+
+// waitValue is the --wait flag: a duration, or no value to wait forever.
+type waitValue struct {
+	set bool
+	d   time.Duration // negative: forever
+}
+
+func (w *waitValue) String() string {
+	if !w.set {
+		return ""
+	}
+	if w.d < 0 {
+		return "forever"
+	}
+	return w.d.String()
+}
+
+func (w *waitValue) Set(s string) error {
+	w.set = true
+	if s == "forever" {
+		w.d = -1
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	w.d = d
+	return nil
+}
+
+func (w *waitValue) Type() string { return "duration" }
+
+// registerOpLocks marks the locked commands and gives them --wait and
+// --no-wait. It runs from Execute, once every command is registered.
+func registerOpLocks(root *cobra.Command) {
+	for _, path := range lockedCommands {
+		c, _, err := root.Find(strings.Fields(path))
+		if err != nil || c == root || c.Annotations[opLockAnnotation] != "" {
+			continue
+		}
+		if c.Annotations == nil {
+			c.Annotations = map[string]string{}
+		}
+		c.Annotations[opLockAnnotation] = "true"
+		// gt shutdown has its own --wait (seconds to wait for agents).
+		if c.Flags().Lookup("wait") == nil {
+			f := c.Flags().VarPF(&opLockWait, "wait", "", "Wait this long for the town operation lock (no value: until it is free)")
+			f.NoOptDefVal = "forever"
+		}
+		c.Flags().BoolVar(&opLockNoWait, "no-wait", false, "Fail at once if another gt operation holds the town lock")
+	}
+}
+
+// acquireOpLock takes a town operation slot if cmd is a locked command
+// run inside a town. It waits as --wait, --no-wait, or the town's
+// operations.wait_timeout setting says.
+func acquireOpLock(cmd *cobra.Command, args []string) error {
+	if cmd.Annotations[opLockAnnotation] == "" {
+		return nil
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil // the command reports the missing town itself
+	}
+
+	slots, wait := opLockSettings(townRoot)
+	switch {
+	case opLockNoWait:
+		wait = 0
+	case opLockWait.set:
+		wait = opLockWait.d
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	opLock, err = oplock.Acquire(ctx, townRoot, oplock.Options{
+		Slots:   slots,
+		Wait:    wait,
+		Command: strings.Join(append([]string{cmd.CommandPath()}, args...), " "),
+		OnWait: func(holders []oplock.Holder) {
+			var running []string
+			for _, h := range holders {
+				running = append(running, oplock.Describe(h))
+			}
+			fmt.Fprintf(os.Stderr, "%s %s\n", style.Dim.Render("⏳"),
+				style.Dim.Render("Waiting for the town operation lock: "+strings.Join(running, "; ")))
+		},
+	})
+	if err != nil {
+		cmd.SilenceUsage = true // the command line was fine
+	}
+	return err
+}
+
+// releaseOpLock frees this invocation's slot.
+func releaseOpLock() {
+	opLock.Release()
+	opLock = nil
+}
+
+// opLockSettings returns the town's slot count and default wait.
+func opLockSettings(townRoot string) (int, time.Duration) {
+	slots := config.DefaultMaxParallelOps
+	wait, _ := time.ParseDuration(config.DefaultOpWaitTimeout)
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Operations == nil {
+		return slots, wait
+	}
+	if settings.Operations.MaxParallel > 0 {
+		slots = settings.Operations.MaxParallel
+	}
+	if d, err := time.ParseDuration(settings.Operations.WaitTimeout); err == nil {
+		wait = d
+	}
+	return slots, wait
+}
+
+var locksJSON bool
+
+var locksCmd = &cobra.Command{
+	Use:     "locks",
+	GroupID: GroupDiag,
+	Short:   "Show gt operations holding the town lock",
+	Long: `Show which gt commands hold the town's operation lock.
+
+Mutating commands (gt up, gt down, gt sling, gt rig add, gt crew start,
+gt polecat nuke, ...) each take one of the town's operation slots while
+they run, so operators, cron jobs, and hooks running gt at the same time
+do not trip over each other. By default a town has one slot, so these
+commands run one at a time; settings/config.json can allow more:
+
+  "operations": {"max_parallel": 2, "wait_timeout": "10m"}
+
+A command that finds every slot taken waits for one, up to wait_timeout
+(default 5m). --wait=30s changes how long, --wait alone waits until a
+slot is free, and --no-wait fails at once (exit status 6, error GT1006).
+gt commands run by a command holding a slot share it. Slots are released
+when their holder exits, even if it crashes.
+
+Examples:
+  gt locks          # Who holds the lock
+  gt locks --json   # Machine-readable`,
+	Args: cobra.NoArgs,
+	RunE: runLocks,
+}
+
+func init() {
+	locksCmd.Flags().BoolVar(&locksJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(locksCmd)
+}
+
+func runLocks(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	slots, _ := opLockSettings(townRoot)
+	holders, err := oplock.Holders(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if locksJSON {
+		if holders == nil {
+			holders = []oplock.Holder{}
+		}
+		return outputJSON(struct {
+			MaxParallel int             `json:"max_parallel"`
+			Holders     []oplock.Holder `json:"holders"`
+		}{slots, holders})
+	}
+
+	if len(holders) == 0 {
+		fmt.Printf("%s No gt operations running (%d slot(s))\n", style.Dim.Render("○"), slots)
+		return nil
+	}
+	fmt.Printf("%s %d of %d operation slot(s) in use\n\n", style.Bold.Render("●"), len(holders), slots)
+	for _, h := range holders {
+		who := h.User
+		if h.Host != "" {
+			who += "@" + h.Host
+		}
+		fmt.Printf("  slot %d  %s  %s\n", h.Slot, style.Bold.Render(h.Command),
+			style.Dim.Render(fmt.Sprintf("pid %d  %s  for %s", h.PID, who, time.Since(h.Since).Round(time.Second))))
+	}
+	return nil
+}
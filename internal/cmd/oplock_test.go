@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLockedCommandsExist(t *testing.T) {
+	for _, path := range lockedCommands {
+		c, _, err := rootCmd.Find(strings.Fields(path))
+		if err != nil || c.CommandPath() != "gt "+path {
+			t.Errorf("locked command %q does not exist", path)
+		}
+	}
+}
+
+func TestWaitValue(t *testing.T) {
+	var w waitValue
+	if err := w.Set("forever"); err != nil || w.d >= 0 || w.String() != "forever" {
+		t.Errorf("Set(forever) = %v, %+v", err, w)
+	}
+	if err := w.Set("30s"); err != nil || w.String() != "30s" {
+		t.Errorf("Set(30s) = %v, %+v", err, w)
+	}
+	if err := w.Set("soon"); err == nil {
+		t.Error("Set(soon) should fail")
+	}
+}
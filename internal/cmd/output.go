@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/daemon"
+	"github.com/ctiospl/gastown/internal/outputlog"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	outputFollow bool
+	outputLines  int
+)
+
+var outputCmd = &cobra.Command{
+	Use:     "output <agent>",
+	GroupID: GroupDiag,
+	Short:   "Show an agent's captured terminal output",
+	Long: `Show the raw output an agent's session printed, as captured in
+logs/output/<agent>.log (e.g. logs/output/gastown/crew/max.log).
+
+Everything an agent's tmux pane prints is captured as the session starts,
+or by the daemon within 30 seconds for sessions started otherwise, so a
+crash leaves its last output behind even once the pane has closed. The
+output is raw: it includes the runtime's terminal escape sequences, so it
+is best viewed in a terminal or with less -R.
+
+Logs are rotated at 10 MB, keeping the three before as <agent>.log.1
+(the newest) to <agent>.log.3.
+
+By default the last 50 lines are printed; -n 0 prints the whole log. With
+-f, gt output keeps printing new output as the agent prints it, until
+interrupted.
+
+Examples:
+  gt output gastown/crew/max
+  gt output gastown/Toast -f
+  gt output mayor -n 200 | less -R`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOutput,
+}
+
+var outputCaptureCmd = &cobra.Command{
+	Use:    "output-capture <agent>",
+	Short:  "Append stdin to an agent's output log (internal use)",
+	Hidden: true, // Internal command piped into by tmux pipe-pane
+	Args:   cobra.ExactArgs(1),
+	RunE:   runOutputCapture,
+}
+
+func init() {
+	outputCmd.Flags().BoolVarP(&outputFollow, "follow", "f", false, "Keep printing new output as the agent prints it")
+	outputCmd.Flags().IntVarP(&outputLines, "lines", "n", 50, "Print the last this many lines (0 for all)")
+	rootCmd.AddCommand(outputCmd)
+	rootCmd.AddCommand(outputCaptureCmd)
+}
+
+func runOutput(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if outputLines < 0 {
+		return fmt.Errorf("--lines must not be negative")
+	}
+	cmd.SilenceUsage = true
+	id, err := resolveOutputAgent(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+
+	path := outputlog.Path(townRoot, id.Address())
+	data, err := outputlog.Tail(path, outputLines)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading output log: %w", err)
+	}
+	if os.IsNotExist(err) && !outputFollow {
+		return fmt.Errorf("no output captured for %s (%s)%s", id.Address(), path, outputCaptureHint(townRoot, id))
+	}
+	if _, err := os.Stdout.Write(data); err != nil {
+		return err
+	}
+	if !outputFollow {
+		return nil
+	}
+
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return outputlog.Follow(ctx, path, offset, os.Stdout)
+}
+
+// resolveOutputAgent returns the agent target names: an address, or an
+// agent's name alone when the output of one agent of that name has been
+// captured.
+func resolveOutputAgent(townRoot, target string) (*session.AgentIdentity, error) {
+	target = strings.TrimSuffix(target, "/")
+	id, err := session.ParseAddress(target)
+	if err == nil || strings.Contains(target, "/") {
+		return id, err
+	}
+	matches, _ := filepath.Glob(filepath.Join(outputlog.Dir(townRoot), "*", "*", target+".log"))
+	short, _ := filepath.Glob(filepath.Join(outputlog.Dir(townRoot), "*", target+".log"))
+	matches = append(matches, short...)
+	var agents []string
+	for _, m := range matches {
+		rel, _ := filepath.Rel(outputlog.Dir(townRoot), strings.TrimSuffix(m, ".log"))
+		agents = append(agents, filepath.ToSlash(rel))
+	}
+	switch len(agents) {
+	case 0:
+		return nil, fmt.Errorf("no output captured for an agent named %q (give its address)", target)
+	case 1:
+		return session.ParseAddress(agents[0])
+	}
+	return nil, fmt.Errorf("%q matches %d agents: %s; give its address", target, len(agents), strings.Join(agents, ", "))
+}
+
+// outputCaptureHint says why an agent may have no output log yet.
+func outputCaptureHint(townRoot string, id *session.AgentIdentity) string {
+	t := tmux.NewTmux()
+	if running, _ := t.HasSession(id.SessionName()); !running {
+		return "; it has not run since output capture began"
+	}
+	if running, _, _ := daemon.IsRunning(townRoot); !running {
+		return "; its session is not captured, and the daemon, which captures it, is not running (gt daemon start)"
+	}
+	return ""
+}
+
+func runOutputCapture(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	w, err := outputlog.Open(outputlog.Path(townRoot, args[0]))
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	if _, err := io.Copy(w, os.Stdin); err != nil {
+		style.PrintWarning("capturing output of %s: %v", args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ctiospl/gastown/internal/outputlog"
+)
+
+func TestResolveOutputAgent(t *testing.T) {
+	town := t.TempDir()
+	for _, agent := range []string{"gastown/crew/max", "gastown/polecats/Toast", "infra/crew/max", "infra/crew/ada"} {
+		path := outputlog.Path(town, agent)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("output\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		target, want string
+	}{
+		{"gastown/crew/max", "gastown/crew/max"},
+		{"ada", "infra/crew/ada"},
+		{"Toast", "gastown/polecats/Toast"},
+		{"gastown/crew/gone/", "gastown/crew/gone"},
+		{"max", ""}, // ambiguous
+		{"nobody", ""},
+	}
+	for _, tt := range tests {
+		id, err := resolveOutputAgent(town, tt.target)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("resolveOutputAgent(%q) = %s, want an error", tt.target, id.Address())
+			}
+			continue
+		}
+		if err != nil || id.Address() != tt.want {
+			t.Errorf("resolveOutputAgent(%q) = %v, %v; want %s", tt.target, id, err, tt.want)
+		}
+	}
+}
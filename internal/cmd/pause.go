@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+// Town log events recorded by gt pause and gt resume.
+const (
+	EventPause  townlog.EventType = "pause"
+	EventResume townlog.EventType = "resume"
+)
+
+var (
+	pauseReason string
+	pauseJSON   bool
+)
+
+var pauseCmd = &cobra.Command{
+	Use:     "pause [agent]",
+	GroupID: GroupAgents,
+	Short:   "Freeze an agent's session without killing it",
+	Long: `Freeze an agent so it stops working, and spending tokens, until resumed.
+
+The processes in the agent's tmux session are stopped with SIGSTOP. Unlike
+gt stop, nothing exits: the agent keeps its whole conversation in memory
+and carries on from where it was with 'gt resume'. The session can still
+be attached to and read while paused, but ignores input.
+
+A request the agent was waiting on when paused may time out; the agent's
+runtime retries it on resume.
+
+Pausing and resuming are logged as "pause" and "resume" events. Paused
+agents show as paused in gt health rather than stale. Without an agent,
+lists the paused agents, including those the daemon put to sleep for being
+idle (daemon.sleep_idle_after in mayor/config.json), which are listed as
+paused by idle and wake by themselves when work arrives for them.
+
+Examples:
+  gt pause gastown/crew/max --reason "out of budget until Monday"
+  gt pause gastown/polecats/Toast
+  gt pause                     # List paused agents`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPause,
+}
+
+func init() {
+	pauseCmd.Flags().StringVar(&pauseReason, "reason", "", "Why the agent is paused (shown by gt pause and logged)")
+	pauseCmd.Flags().BoolVar(&pauseJSON, "json", false, "List paused agents as JSON")
+
+	rootCmd.AddCommand(pauseCmd)
+}
+
+func runPause(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if len(args) == 0 {
+		return listPauses(townRoot)
+	}
+
+	agent, sessionName, err := pauseTarget(args[0])
+	if err != nil {
+		return err
+	}
+	t := tmux.NewTmux()
+	if exists, err := t.HasSession(sessionName); err != nil {
+		return err
+	} else if !exists {
+		return fmt.Errorf("%s is not running (no session %s)", agent, sessionName)
+	}
+	cmd.SilenceUsage = true
+
+	p := session.Pause{
+		Agent:    agent,
+		Session:  sessionName,
+		PausedAt: time.Now(),
+		By:       detectSender(),
+		Reason:   pauseReason,
+	}
+	if err := session.PauseSession(t, townRoot, p); err != nil {
+		return err
+	}
+	_ = t.DisplayMessageDefault(sessionName, "Paused by "+p.By+" (gt resume "+agent+")")
+	_ = townlog.NewLogger(townRoot).LogEvent(townlog.Event{
+		Timestamp: p.PausedAt,
+		Type:      EventPause,
+		Agent:     agent,
+		Context:   p.Reason,
+	})
+	fmt.Printf("%s Paused %s\n", style.Success.Render("✓"), agent)
+	fmt.Printf("  Resume with: %s\n", style.Dim.Render("gt resume "+agent))
+	return nil
+}
+
+// runResumePaused resumes agents frozen by gt pause: the one named, or
+// with --all every one (see gt resume).
+func runResumePaused(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cmd.SilenceUsage = true
+
+	var sessions []string
+	if resumeAllPaused {
+		pauses, err := session.LoadPauses(townRoot)
+		if err != nil {
+			return err
+		}
+		for _, p := range session.SortedPauses(pauses) {
+			sessions = append(sessions, p.Session)
+		}
+		if len(sessions) == 0 {
+			fmt.Println("No agents are paused")
+			return nil
+		}
+	} else {
+		_, sessionName, err := pauseTarget(args[0])
+		if err != nil {
+			return err
+		}
+		sessions = []string{sessionName}
+	}
+
+	t := tmux.NewTmux()
+	logger := townlog.NewLogger(townRoot)
+	failed := 0
+	for _, sessionName := range sessions {
+		p, err := session.ResumeSession(t, townRoot, sessionName)
+		if err != nil {
+			fmt.Printf("%s %v\n", style.Error.Render("✗"), err)
+			failed++
+			continue
+		}
+		paused := time.Since(p.PausedAt).Round(time.Second)
+		_ = logger.Log(EventResume, p.Agent, "paused for "+formatDuration(paused))
+		fmt.Printf("%s Resumed %s %s\n", style.Success.Render("✓"), p.Agent, style.Dim.Render("(paused for "+formatDuration(paused)+")"))
+	}
+	if failed > 0 {
+		if len(sessions) == 1 {
+			return NewSilentExit(1)
+		}
+		return partialFailure("%d of %d agents could not be resumed", failed, len(sessions))
+	}
+	return nil
+}
+
+// listPauses prints the paused agents.
+func listPauses(townRoot string) error {
+	pauses, err := session.LoadPauses(townRoot)
+	if err != nil {
+		return err
+	}
+	list := session.SortedPauses(pauses)
+	if pauseJSON {
+		return outputJSON(list)
+	}
+	if len(list) == 0 {
+		fmt.Println("No agents are paused")
+		return nil
+	}
+	for _, p := range list {
+		line := fmt.Sprintf("%s %-28s paused %s ago by %s", style.Dim.Render("⏸"), p.Agent,
+			formatDuration(time.Since(p.PausedAt).Round(time.Second)), p.By)
+		if p.Reason != "" {
+			line += style.Dim.Render(" — " + p.Reason)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// pauseTarget resolves an agent address, or a session name, to the agent
+// and its tmux session.
+func pauseTarget(arg string) (agent, sessionName string, err error) {
+	if strings.HasPrefix(arg, session.Prefix) {
+		id, err := session.ParseSessionName(arg)
+		if err != nil {
+			return "", "", err
+		}
+		return id.Address(), arg, nil
+	}
+	agent = strings.Trim(arg, "/")
+	if parts := strings.Split(agent, "/"); len(parts) == 2 && parts[1] != "witness" && parts[1] != "refinery" {
+		agent = parts[0] + "/polecats/" + parts[1] // rig/name is a polecat
+	}
+	if sessionName = shareSessionName(agent); sessionName == "" {
+		return "", "", fmt.Errorf("invalid agent %q: use e.g. gastown/crew/max, gastown/polecats/Toast, gastown/witness, or mayor", arg)
+	}
+	return agent, sessionName, nil
+}
@@ -7,12 +7,14 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/ctiospl/gastown/internal/beads"
 	"github.com/ctiospl/gastown/internal/git"
+	"github.com/ctiospl/gastown/internal/metrics"
 	"github.com/ctiospl/gastown/internal/polecat"
 	"github.com/ctiospl/gastown/internal/rig"
 	"github.com/ctiospl/gastown/internal/session"
@@ -524,7 +526,7 @@ func runPolecatRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(removeErrors) > 0 {
-		return fmt.Errorf("%d removal(s) failed", len(removeErrors))
+		return partialFailure("%d removal(s) failed", len(removeErrors))
 	}
 
 	return nil
@@ -604,7 +606,7 @@ func runPolecatSync(cmd *cobra.Command, args []string) error {
 		for _, e := range syncErrors {
 			fmt.Printf("  - %s\n", e)
 		}
-		return fmt.Errorf("%d sync(s) failed", len(syncErrors))
+		return partialFailure("%d sync(s) failed", len(syncErrors))
 	}
 
 	return nil
@@ -624,6 +626,8 @@ type PolecatStatus struct {
 	Windows        int           `json:"windows,omitempty"`
 	CreatedAt      string        `json:"created_at,omitempty"`
 	LastActivity   string        `json:"last_activity,omitempty"`
+
+	Metrics *metrics.Metrics `json:"metrics,omitempty"` // self-reported via the worktree metrics file
 }
 
 func runPolecatStatus(cmd *cobra.Command, args []string) error {
@@ -655,9 +659,13 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Self-reported metrics (optional; malformed files are shown as a warning)
+	agentMetrics, metricsErr := metrics.Read(p.ClonePath)
+
 	// JSON output
 	if polecatStatusJSON {
 		status := PolecatStatus{
+			Metrics:        agentMetrics,
 			Rig:            rigName,
 			Name:           polecatName,
 			State:          p.State,
@@ -708,6 +716,15 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Clone:         %s\n", style.Dim.Render(p.ClonePath))
 	fmt.Printf("  Branch:        %s\n", style.Dim.Render(p.Branch))
 
+	// Progress from the metrics file
+	if metricsErr != nil {
+		fmt.Println()
+		fmt.Printf("%s %v\n", style.WarningPrefix, metricsErr)
+	} else if agentMetrics != nil {
+		fmt.Println()
+		printAgentMetrics(agentMetrics)
+	}
+
 	// Session info
 	fmt.Println()
 	fmt.Printf("%s\n", style.Bold.Render("Session"))
@@ -744,6 +761,41 @@ func runPolecatStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printAgentMetrics renders a metrics file as a status section.
+func printAgentMetrics(m *metrics.Metrics) {
+	fmt.Printf("%s\n", style.Bold.Render("Progress"))
+	if m.State != "" {
+		fmt.Printf("  State:         %s\n", m.State)
+	}
+	if m.Progress != nil {
+		fmt.Printf("  Progress:      %s\n", m.Progress.String())
+	}
+	if m.Message != "" {
+		fmt.Printf("  Message:       %s\n", m.Message)
+	}
+	if m.Tokens != nil {
+		fmt.Printf("  Tokens:        %d in / %d out\n", m.Tokens.Input, m.Tokens.Output)
+	}
+	if m.CostUSD != nil {
+		fmt.Printf("  Cost:          $%.2f\n", *m.CostUSD)
+	}
+	names := make([]string, 0, len(m.Counters))
+	for name := range m.Counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("  %-14s %g\n", name+":", m.Counters[name])
+	}
+	updated := formatActivityTime(m.Updated)
+	if m.Stale(time.Now()) {
+		updated = style.Warning.Render(updated + " (stale)")
+	} else {
+		updated = style.Dim.Render(updated)
+	}
+	fmt.Printf("  Updated:       %s\n", updated)
+}
+
 // formatActivityTime returns a human-readable relative time string.
 func formatActivityTime(t time.Time) string {
 	d := time.Since(t)
@@ -1301,10 +1353,11 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Nuke each polecat
+	// Nuke each polecat. Nukes can't be undone, so the safety checks above
+	// vet every target before any is touched; here each outcome is recorded
+	// for the summary and one failure doesn't stop the rest.
 	t := tmux.NewTmux()
-	var nukeErrors []string
-	nuked := 0
+	b := newBatch("Nuke")
 
 	for _, p := range toNuke {
 		if polecatNukeDryRun {
@@ -1402,7 +1455,7 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 			if errors.Is(err, polecat.ErrPolecatNotFound) {
 				fmt.Printf("  %s worktree already gone\n", style.Dim.Render("○"))
 			} else {
-				nukeErrors = append(nukeErrors, fmt.Sprintf("%s/%s: worktree removal failed: %v", p.rigName, p.polecatName, err))
+				b.fail(p.rigName+"/"+p.polecatName, fmt.Errorf("worktree removal failed: %w", err))
 				continue
 			}
 		} else {
@@ -1435,7 +1488,7 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  %s closed agent bead %s\n", style.Success.Render("✓"), agentBeadID)
 		}
 
-		nuked++
+		b.succeed(p.rigName+"/"+p.polecatName, "nuked", nil)
 	}
 
 	// Report results
@@ -1444,20 +1497,11 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if len(nukeErrors) > 0 {
-		fmt.Printf("\n%s Some nukes failed:\n", style.Warning.Render("Warning:"))
-		for _, e := range nukeErrors {
-			fmt.Printf("  - %s\n", e)
-		}
-	}
-
-	if nuked > 0 {
-		fmt.Printf("\n%s Nuked %d polecat(s).\n", style.SuccessPrefix, nuked)
-	}
-
-	if len(nukeErrors) > 0 {
-		return fmt.Errorf("%d nuke(s) failed", len(nukeErrors))
+	if len(toNuke) > 1 || b.count(batchFailed) > 0 {
+		b.report()
+	} else {
+		fmt.Printf("\n%s Nuked %d polecat(s).\n", style.SuccessPrefix, b.count(batchOK))
 	}
 
-	return nil
+	return b.err()
 }
@@ -39,6 +39,7 @@ type SlingSpawnOptions struct {
 	Account  string // Claude Code account handle to use
 	Create   bool   // Create polecat if it doesn't exist (currently always true for sling)
 	HookBead string // Bead ID to set as hook_bead at spawn time (atomic assignment)
+	StackOn  string // Polecat or branch to stack the new branch on (see 'gt stack')
 }
 
 // SpawnPolecatForSling creates a fresh polecat and optionally starts its session.
@@ -76,25 +77,31 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	}
 	fmt.Printf("Allocated polecat: %s\n", polecatName)
 
+	// Fail fast, with every problem at once, before creating anything
+	if err := runSpawnPreflight(townRoot, r, polecatMgr, polecatName, opts).Err(); err != nil {
+		polecatMgr.ReleaseName(polecatName)
+		return nil, err
+	}
+
 	// Check if polecat already exists (shouldn't happen - indicates stale state needing repair)
-	existingPolecat, err := polecatMgr.Get(polecatName)
+	_, err = polecatMgr.Get(polecatName)
 
 	// Build add options with hook_bead set atomically at spawn time
 	addOpts := polecat.AddOptions{
 		HookBead: opts.HookBead,
 	}
+	if opts.StackOn != "" {
+		addOpts.StackOn, addOpts.StackOnIssue, err = polecatMgr.StackParent(opts.StackOn)
+		if err != nil {
+			polecatMgr.ReleaseName(polecatName)
+			return nil, fmt.Errorf("resolving --stack-on: %w", err)
+		}
+		fmt.Printf("Stacking on %s\n", addOpts.StackOn)
+	}
 
 	if err == nil {
 		// Stale state: polecat exists despite fresh name allocation - repair it
-		// Check for uncommitted work first
-		if !opts.Force {
-			pGit := git.NewGit(existingPolecat.ClonePath)
-			workStatus, checkErr := pGit.CheckUncommittedWork()
-			if checkErr == nil && !workStatus.Clean() {
-				return nil, fmt.Errorf("polecat '%s' has uncommitted work: %s\nUse --force to proceed anyway",
-					polecatName, workStatus.String())
-			}
-		}
+		// (preflight already refused if that would discard uncommitted work)
 		fmt.Printf("Repairing stale polecat %s with fresh worktree...\n", polecatName)
 		if _, err = polecatMgr.RepairWorktreeWithOptions(polecatName, opts.Force, addOpts); err != nil {
 			return nil, fmt.Errorf("repairing stale polecat: %w", err)
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,8 +18,11 @@ import (
 	"github.com/ctiospl/gastown/internal/beads"
 	"github.com/ctiospl/gastown/internal/checkpoint"
 	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/drift"
 	"github.com/ctiospl/gastown/internal/events"
+	"github.com/ctiospl/gastown/internal/handoff"
 	"github.com/ctiospl/gastown/internal/lock"
+	"github.com/ctiospl/gastown/internal/retrieval"
 	"github.com/ctiospl/gastown/internal/session"
 	"github.com/ctiospl/gastown/internal/style"
 	"github.com/ctiospl/gastown/internal/templates"
@@ -165,6 +169,9 @@ func runPrime(cmd *cobra.Command, args []string) error {
 	// Output handoff content if present
 	outputHandoffContent(ctx)
 
+	// Output the summary left by gt handoff --summarize, once
+	outputHandoffSummary(ctx)
+
 	// Output attachment status (for autonomous work detection)
 	outputAttachmentStatus(ctx)
 
@@ -178,6 +185,9 @@ func runPrime(cmd *cobra.Command, args []string) error {
 	// Output previous session checkpoint for crash recovery
 	outputCheckpointContext(ctx)
 
+	// Warn about edits made to the worktree while no session was running
+	outputDriftContext(ctx)
+
 	// Run bd prime to output beads workflow context
 	runBdPrime(cwd)
 
@@ -503,6 +513,29 @@ func outputHandoffContent(ctx RoleContext) {
 	fmt.Println(style.Dim.Render("(Clear with: gt rig reset --handoff)"))
 }
 
+// outputHandoffSummary displays, and consumes, the context summary the
+// previous session left with gt handoff --summarize, or gt clone left from
+// the agent cloned.
+func outputHandoffSummary(ctx RoleContext) {
+	agent := getAgentIdentity(ctx)
+	if agent == "" {
+		return
+	}
+	summary, err := handoff.Take(ctx.TownRoot, agent)
+	if err != nil || summary == nil {
+		return
+	}
+	title := "## 🧭 Context Summary from Previous Session"
+	if summary.From != "" {
+		title = "## 🧭 Context Summary from " + summary.From + " (gt clone)"
+	}
+	fmt.Println()
+	fmt.Printf("%s\n\n", style.Bold.Render(title))
+	fmt.Print(summary.Markdown(time.Now()))
+	fmt.Println()
+	fmt.Println("Resume from this summary rather than rediscovering it; check git log and bd show for detail.")
+}
+
 // runBdPrime runs `bd prime` and outputs the result.
 // This provides beads workflow context to the agent.
 func runBdPrime(workDir string) {
@@ -1056,9 +1089,43 @@ func checkSlungWork(ctx RoleContext) bool {
 	}
 	fmt.Println()
 
+	outputRelatedContext(ctx, hookedBead)
+
 	return true
 }
 
+// primeContextTimeout bounds retrieval so a slow embeddings provider
+// cannot stall session startup.
+const primeContextTimeout = 15 * time.Second
+
+// outputRelatedContext shows the snippets of the rig's context index that
+// best match the hooked work. Rigs without an index show nothing.
+func outputRelatedContext(ctx RoleContext, issue *beads.Issue) {
+	if ctx.Rig == "" {
+		return
+	}
+	_, r, err := getRig(ctx.Rig)
+	if err != nil {
+		return
+	}
+	searchCtx, cancel := context.WithTimeout(context.Background(), primeContextTimeout)
+	defer cancel()
+	hits, err := retrieveContext(searchCtx, r, issue.Title+"\n"+issue.Description, 0)
+	if err != nil {
+		if !errors.Is(err, retrieval.ErrNoIndex) {
+			fmt.Fprintf(os.Stderr, "  context retrieval: %v\n", err)
+		}
+		return
+	}
+	if len(hits) == 0 {
+		return
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render("## Related Context"))
+	fmt.Println("From this rig's code, agent notes, and past handoffs (see 'gt context'):")
+	fmt.Println()
+	printContextHits(hits)
+}
+
 // buildRoleAnnouncement creates the role announcement string for autonomous mode.
 func buildRoleAnnouncement(ctx RoleContext) string {
 	switch ctx.Role {
@@ -1539,6 +1606,48 @@ func outputCheckpointContext(ctx RoleContext) {
 	fmt.Println()
 }
 
+// outputDriftContext tells a worker what changed in its worktree since its
+// last session ended (human edits, other tools), so it does not overwrite
+// them unknowingly.
+func outputDriftContext(ctx RoleContext) {
+	if ctx.Role != RolePolecat && ctx.Role != RoleCrew {
+		return
+	}
+	r, err := drift.Check(ctx.WorkDir)
+	if err != nil || r == nil {
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("%s\n\n", style.Bold.Render("## ✋ Changed Outside Your Session"))
+	fmt.Printf("Since your last session ended %s ago, someone else changed this worktree.\n",
+		time.Since(r.Since).Round(time.Minute))
+	fmt.Println("Treat these changes as intentional: do not revert or overwrite them without asking.")
+	fmt.Println()
+	if len(r.Commits) > 0 {
+		fmt.Printf("  **New commits:** %d\n", len(r.Commits))
+		for _, c := range r.Commits {
+			fmt.Printf("    - %s\n", c)
+		}
+	}
+	if len(r.Changes) > 0 {
+		fmt.Printf("  **Uncommitted changes:** %d\n", len(r.Changes))
+		maxShow := 10
+		if len(r.Changes) < maxShow {
+			maxShow = len(r.Changes)
+		}
+		for _, c := range r.Changes[:maxShow] {
+			fmt.Printf("    - %s (%s)\n", c.Path, c.Kind)
+		}
+		if len(r.Changes) > maxShow {
+			fmt.Printf("    ... and %d more\n", len(r.Changes)-maxShow)
+		}
+	}
+	fmt.Println()
+	fmt.Println("Review them with `gt diff --outside`.")
+	fmt.Println()
+}
+
 // emitSessionEvent emits a session_start event for seance discovery.
 // The event is written to ~/gt/.events.jsonl and can be queried via gt seance.
 // Session ID resolution order: GT_SESSION_ID, CLAUDE_SESSION_ID, persisted file, fallback.
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/prune"
+	"github.com/ctiospl/gastown/internal/style"
+)
+
+var (
+	pruneAgentsInactive string
+	pruneAgentsRig      string
+	pruneAgentsDryRun   bool
+	pruneAgentsForce    bool
+	pruneAgentsJSON     bool
+)
+
+var pruneCmd = &cobra.Command{
+	Use:     "prune",
+	GroupID: GroupWorkspace,
+	Short:   "Retire stale parts of the town",
+	RunE:    requireSubcommand,
+}
+
+var pruneAgentsCmd = &cobra.Command{
+	Use:   "agents",
+	Short: "Retire crew workers that have been inactive for weeks",
+	Long: `Retire crew identities with no activity for a long time, so the roster of
+a long-lived town shows the agents that still work in it.
+
+A crew worker's last activity is its newest event in the town log
+(archived logs included), or, if it has none, the time its state or
+workspace last changed. Workers inactive for longer than --inactive
+(default 8w) are retired:
+
+  1. Their state file, mailbox (legacy inbox and open mail, which is then
+     closed), notes (checkpoints, parked work), runtime state, and
+     capability handshake are archived to archive/agents/<rig>-crew-<name>-<time>/
+  2. The workspace is removed, so the worker drops out of gt status,
+     gt crew list, and completion
+  3. The agent bead is closed, and a "retire" event is logged
+
+Workers with a running session, uncommitted changes, stashes, or unpushed
+commits are skipped unless --force is given. Polecats are not pruned;
+gt polecat gc recycles them. Town agents (mayor, deacon, witnesses,
+refineries) are never pruned.
+
+The daemon can do this daily: set "prune_agents_after": "12w" in the
+daemon section of mayor/config.json. It skips workers --force would be
+needed for.
+
+Examples:
+  gt prune agents --dry-run          # What would be retired
+  gt prune agents                    # Retire crew idle for 8 weeks
+  gt prune agents --inactive 30d     # ... for 30 days
+  gt prune agents --rig gastown      # Only in one rig`,
+	Args: cobra.NoArgs,
+	RunE: runPruneAgents,
+}
+
+func init() {
+	pruneAgentsCmd.Flags().StringVar(&pruneAgentsInactive, "inactive", "8w", "Retire workers inactive this long (e.g. 8w, 30d, 720h)")
+	pruneAgentsCmd.Flags().StringVar(&pruneAgentsRig, "rig", "", "Only prune crew of this rig")
+	pruneAgentsCmd.Flags().BoolVarP(&pruneAgentsDryRun, "dry-run", "n", false, "Show what would be retired without changing anything")
+	pruneAgentsCmd.Flags().BoolVarP(&pruneAgentsForce, "force", "f", false, "Retire workers with running sessions or unpushed work too")
+	pruneAgentsCmd.Flags().BoolVar(&pruneAgentsJSON, "json", false, "Output as JSON")
+
+	pruneCmd.AddCommand(pruneAgentsCmd)
+	rootCmd.AddCommand(pruneCmd)
+}
+
+// pruneResult is one worker considered by gt prune agents.
+type pruneResult struct {
+	prune.Identity
+	Status  string         `json:"status"`           // "retired", "would retire", "skipped", or "error"
+	Reason  string         `json:"reason,omitempty"` // why it was skipped or failed
+	Retired *prune.Retired `json:"retired,omitempty"`
+}
+
+func runPruneAgents(cmd *cobra.Command, args []string) error {
+	age, err := prune.ParseAge(pruneAgentsInactive)
+	if err != nil {
+		return err
+	}
+	rigs, townRoot, err := getAllRigs()
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, r := range rigs {
+		if pruneAgentsRig == "" || r.Name == pruneAgentsRig {
+			names = append(names, r.Name)
+		}
+	}
+	if pruneAgentsRig != "" && len(names) == 0 {
+		return fmt.Errorf("rig %q not found", pruneAgentsRig)
+	}
+
+	roster, err := prune.Roster(townRoot, names)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	results := []pruneResult{}
+	failed := 0
+	for _, id := range prune.Inactive(roster, now.Add(-age)) {
+		res := pruneResult{Identity: id}
+		if reason := prune.Check(id); reason != "" && !pruneAgentsForce {
+			res.Status, res.Reason = "skipped", reason+" (use --force)"
+		} else if pruneAgentsDryRun {
+			res.Status = "would retire"
+		} else if retired, err := prune.Retire(townRoot, id, now); err != nil {
+			res.Status, res.Reason = "error", err.Error()
+			failed++
+		} else {
+			res.Status, res.Retired = "retired", retired
+		}
+		results = append(results, res)
+	}
+
+	if pruneAgentsJSON {
+		if err := outputJSON(results); err != nil {
+			return err
+		}
+	} else {
+		printPruneResults(results, len(roster), age)
+	}
+	if failed > 0 {
+		return partialFailure("%d of %d worker(s) could not be retired", failed, len(results))
+	}
+	return nil
+}
+
+func printPruneResults(results []pruneResult, total int, age time.Duration) {
+	if len(results) == 0 {
+		fmt.Printf("%s No crew inactive for %s (%d worker(s) checked)\n", style.Dim.Render("○"), inactivityText(age), total)
+		return
+	}
+	for _, r := range results {
+		since := fmt.Sprintf("inactive since %s (%s)", r.LastActive.Format("2006-01-02"), r.Seen)
+		switch r.Status {
+		case "retired":
+			fmt.Printf("%s Retired %s, %s\n", style.Success.Render("✓"), style.Bold.Render(r.Agent), since)
+			fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("archived to %s (%d message(s))", r.Retired.Archive, r.Retired.Messages)))
+			for _, w := range r.Retired.Warnings {
+				style.PrintWarning("%s: %s", r.Agent, w)
+			}
+		case "would retire":
+			fmt.Printf("  Would retire %s, %s\n", style.Bold.Render(r.Agent), since)
+		case "skipped":
+			fmt.Printf("%s Skipped %s, %s: %s\n", style.Dim.Render("⊘"), r.Agent, since, r.Reason)
+		default:
+			fmt.Printf("%s %s: %s\n", style.Error.Render("✗"), r.Agent, r.Reason)
+		}
+	}
+}
+
+// inactivityText renders an inactivity period in days or weeks when it is a
+// whole number of them.
+func inactivityText(d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d >= 7*day && d%(7*day) == 0:
+		return fmt.Sprintf("%d weeks", d/(7*day))
+	case d >= day && d%day == 0:
+		return fmt.Sprintf("%d days", d/day)
+	}
+	return d.String()
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/daemon"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/workqueue"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var queueJSON bool
+
+var queueCmd = &cobra.Command{
+	Use:     "queue [agent]",
+	GroupID: GroupWork,
+	Short:   "Queue instructions for an agent's next tasks",
+	Long: `List, add, and remove the instructions queued for agents.
+
+Each agent has a work queue: instructions handed to it one at a time,
+oldest first, as nudges, whenever it is ready for more work. An agent is
+ready when it logs a done event for its current task (gt done, gt log
+done), or when it goes idle: its runtime is waiting at its prompt and has
+printed nothing for 2 minutes since its last instruction.
+
+Agents asleep for being idle are woken for their next instruction. Agents
+paused by hand or preempted, in DND, or whose session is not running keep
+their queue until they are back.
+
+Queues are kept in .runtime/agent-queues.json and delivered by the daemon,
+which checks every 30 seconds; the daemon must be running (gt daemon start)
+for instructions to be delivered. Each delivery is logged as a "queue"
+event (gt log --type queue).
+
+Examples:
+  gt queue add gastown/crew/max "fix the flaky test next"
+  gt queue add gastown/crew/max "then update the changelog"
+  gt queue                        # List every agent's queue
+  gt queue gastown/crew/max       # List one agent's queue
+  gt queue remove q-2
+  gt queue clear gastown/crew/max`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runQueueList,
+}
+
+var queueAddCmd = &cobra.Command{
+	Use:   "add <agent> <message>",
+	Short: "Queue an instruction for an agent",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runQueueAdd,
+}
+
+var queueRemoveCmd = &cobra.Command{
+	Use:     "remove <id>...",
+	Aliases: []string{"rm"},
+	Short:   "Remove queued instructions",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    runQueueRemove,
+}
+
+var queueClearCmd = &cobra.Command{
+	Use:   "clear <agent>",
+	Short: "Remove every instruction queued for an agent",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runQueueClear,
+}
+
+func init() {
+	queueCmd.Flags().BoolVar(&queueJSON, "json", false, "Output as JSON")
+
+	queueCmd.AddCommand(queueAddCmd)
+	queueCmd.AddCommand(queueRemoveCmd)
+	queueCmd.AddCommand(queueClearCmd)
+	rootCmd.AddCommand(queueCmd)
+}
+
+func runQueueList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	list, err := workqueue.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		list = workqueue.For(list, strings.TrimSuffix(args[0], "/"))
+	}
+	if queueJSON {
+		if list == nil {
+			list = []workqueue.Item{}
+		}
+		return outputJSON(list)
+	}
+	if len(list) == 0 {
+		fmt.Printf("%s No queued instructions\n", style.Dim.Render("○"))
+		return nil
+	}
+	fmt.Print(formatQueues(list, time.Now()))
+	if running, _, _ := daemon.IsRunning(townRoot); !running {
+		fmt.Println()
+		style.PrintWarning("the daemon is not running, so instructions will not be delivered (gt daemon start)")
+	}
+	return nil
+}
+
+// formatQueues renders queued instructions by agent, next first.
+func formatQueues(list []workqueue.Item, now time.Time) string {
+	var b strings.Builder
+	for i, head := range workqueue.Heads(list) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		queue := workqueue.For(list, head.Agent)
+		fmt.Fprintf(&b, "%s %s\n", style.Bold.Render(head.Agent), style.Dim.Render(fmt.Sprintf("(%d queued)", len(queue))))
+		for _, it := range queue {
+			note := "queued " + formatDuration(now.Sub(it.CreatedAt).Round(time.Minute)) + " ago"
+			if it.CreatedBy != "" {
+				note += " by " + it.CreatedBy
+			}
+			fmt.Fprintf(&b, "  %-6s %s %s\n", it.ID, it.Message, style.Dim.Render("("+note+")"))
+		}
+	}
+	return b.String()
+}
+
+func runQueueAdd(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	it, err := workqueue.Add(townRoot, workqueue.Item{
+		Agent:     args[0],
+		Message:   args[1],
+		CreatedAt: time.Now(),
+		CreatedBy: detectSender(),
+	})
+	if err != nil {
+		return err
+	}
+	list, _ := workqueue.Load(townRoot)
+	n := len(workqueue.For(list, it.Agent))
+
+	fmt.Printf("%s Queued %s for %s (%d in its queue)\n", style.Success.Render("✓"), style.Bold.Render(it.ID), it.Agent, n)
+	fmt.Printf("  Delivered when %s finishes its task or goes idle\n", it.Agent)
+	if running, _, _ := daemon.IsRunning(townRoot); !running {
+		style.PrintWarning("the daemon is not running, so instructions will not be delivered (gt daemon start)")
+	}
+	return nil
+}
+
+func runQueueRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	for _, id := range args {
+		it, err := workqueue.Remove(townRoot, id)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Removed %s from %s's queue: %s\n", style.Success.Render("✓"), it.ID, it.Agent, it.Message)
+	}
+	return nil
+}
+
+func runQueueClear(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	agent := strings.TrimSuffix(args[0], "/")
+	cleared, err := workqueue.Clear(townRoot, agent)
+	if err != nil {
+		return err
+	}
+	if len(cleared) == 0 {
+		fmt.Printf("%s No instructions queued for %s\n", style.Dim.Render("○"), agent)
+		return nil
+	}
+	fmt.Printf("%s Cleared %d queued instruction(s) for %s\n", style.Success.Render("✓"), len(cleared), agent)
+	return nil
+}
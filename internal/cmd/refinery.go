@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/ctiospl/gastown/internal/mrqueue"
@@ -123,19 +124,40 @@ var refineryClaimCmd = &cobra.Command{
 	Long: `Claim a merge request for processing by this refinery worker.
 
 When running multiple refinery workers in parallel, each worker must claim
-an MR before processing to prevent double-processing. Claims expire after
-10 minutes if not processed (for crash recovery).
+an MR before processing to prevent double-processing. A claim is a lease:
+it lapses after --lease (default 10m) unless renewed with 'gt refinery
+renew', so a worker that crashes mid-merge releases the MR for another
+worker. Claiming an MR you already hold renews it.
 
 The worker ID is automatically determined from the GT_REFINERY_WORKER
 environment variable, or defaults to "refinery-1".
 
 Examples:
   gt refinery claim gt-abc123
+  gt refinery claim gt-abc123 --lease 30m
   GT_REFINERY_WORKER=refinery-2 gt refinery claim gt-abc123`,
 	Args: cobra.ExactArgs(1),
 	RunE: runRefineryClaim,
 }
 
+var refineryRenewCmd = &cobra.Command{
+	Use:   "renew <mr-id>",
+	Short: "Renew this worker's lease on a claimed MR",
+	Long: `Extend this worker's lease on a claimed merge request.
+
+Run periodically during long merges (tests, large rebases) so the lease
+does not lapse. Fails if the lease already lapsed and another worker took
+the MR; stop processing it in that case.
+
+Examples:
+  gt refinery renew gt-abc123
+  gt refinery renew gt-abc123 --lease 30m`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRefineryRenew,
+}
+
+var refineryLease time.Duration
+
 var refineryReleaseCmd = &cobra.Command{
 	Use:   "release <mr-id>",
 	Short: "Release a claimed MR back to the queue",
@@ -155,9 +177,9 @@ var refineryUnclaimedCmd = &cobra.Command{
 	Short: "List unclaimed MRs available for processing",
 	Long: `List merge requests that are available for claiming.
 
-Shows MRs that are not currently claimed by any worker, or have stale
-claims (worker may have crashed). Useful for parallel refinery workers
-to find work.
+Shows MRs that are not currently claimed by any worker, or whose lease
+has lapsed (worker may have crashed). Lapsed leases are released and
+logged. Useful for parallel refinery workers to find work.
 
 Examples:
   gt refinery unclaimed
@@ -174,7 +196,7 @@ var refineryReadyCmd = &cobra.Command{
 	Long: `List merge requests ready for processing.
 
 Shows MRs that are:
-- Not currently claimed by any worker (or claim is stale)
+- Not currently claimed by any worker (or the lease has lapsed)
 - Not blocked by an open task (e.g., conflict resolution in progress)
 
 This is the preferred command for finding work to process.
@@ -215,6 +237,10 @@ func init() {
 	// Queue flags
 	refineryQueueCmd.Flags().BoolVar(&refineryQueueJSON, "json", false, "Output as JSON")
 
+	// Claim flags
+	refineryClaimCmd.Flags().DurationVar(&refineryLease, "lease", mrqueue.DefaultLease, "Lease duration before the claim lapses")
+	refineryRenewCmd.Flags().DurationVar(&refineryLease, "lease", mrqueue.DefaultLease, "New lease duration from now")
+
 	// Unclaimed flags
 	refineryUnclaimedCmd.Flags().BoolVar(&refineryUnclaimedJSON, "json", false, "Output as JSON")
 
@@ -232,6 +258,7 @@ func init() {
 	refineryCmd.AddCommand(refineryQueueCmd)
 	refineryCmd.AddCommand(refineryAttachCmd)
 	refineryCmd.AddCommand(refineryClaimCmd)
+	refineryCmd.AddCommand(refineryRenewCmd)
 	refineryCmd.AddCommand(refineryReleaseCmd)
 	refineryCmd.AddCommand(refineryUnclaimedCmd)
 	refineryCmd.AddCommand(refineryReadyCmd)
@@ -546,7 +573,7 @@ func runRefineryClaim(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding merge queue: %w", err)
 	}
 
-	if err := q.Claim(mrID, workerID); err != nil {
+	if err := q.ClaimWithLease(mrID, workerID, refineryLease); err != nil {
 		if err == mrqueue.ErrNotFound {
 			return fmt.Errorf("MR %s not found in queue", mrID)
 		}
@@ -556,7 +583,30 @@ func runRefineryClaim(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("claiming MR: %w", err)
 	}
 
-	fmt.Printf("%s Claimed %s for %s\n", style.Bold.Render("✓"), mrID, workerID)
+	fmt.Printf("%s Claimed %s for %s (lease %s)\n", style.Bold.Render("✓"), mrID, workerID, refineryLease)
+	return nil
+}
+
+func runRefineryRenew(cmd *cobra.Command, args []string) error {
+	mrID := args[0]
+	workerID := getWorkerID()
+
+	q, err := mrqueue.NewFromWorkdir(".")
+	if err != nil {
+		return fmt.Errorf("finding merge queue: %w", err)
+	}
+
+	if err := q.Renew(mrID, workerID, refineryLease); err != nil {
+		if err == mrqueue.ErrNotFound {
+			return fmt.Errorf("MR %s not found in queue", mrID)
+		}
+		if err == mrqueue.ErrLeaseLost {
+			return fmt.Errorf("%s no longer holds MR %s; stop processing it", workerID, mrID)
+		}
+		return fmt.Errorf("renewing lease: %w", err)
+	}
+
+	fmt.Printf("%s Renewed %s for %s (lease %s)\n", style.Bold.Render("✓"), mrID, workerID, refineryLease)
 	return nil
 }
 
@@ -588,6 +638,9 @@ func runRefineryUnclaimed(cmd *cobra.Command, args []string) error {
 	}
 
 	q := mrqueue.New(r.Path)
+	if _, err := q.ExpireLeases(); err != nil {
+		return fmt.Errorf("expiring leases: %w", err)
+	}
 	unclaimed, err := q.ListUnclaimed()
 	if err != nil {
 		return fmt.Errorf("listing unclaimed MRs: %w", err)
@@ -628,6 +681,11 @@ func runRefineryReady(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Free MRs held by crashed workers so they are re-dispatched
+	if _, err := mrqueue.New(r.Path).ExpireLeases(); err != nil {
+		return fmt.Errorf("expiring leases: %w", err)
+	}
+
 	// Create engineer for the rig (it has beads access for status checking)
 	eng := refinery.NewEngineer(r)
 
@@ -71,7 +71,7 @@ func runRelease(cmd *cobra.Command, args []string) error {
 	}
 
 	if failed > 0 {
-		return fmt.Errorf("%d issue(s) failed to release", failed)
+		return partialFailure("%d issue(s) failed to release", failed)
 	}
 
 	return nil
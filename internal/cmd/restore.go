@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/beads"
+	"github.com/ctiospl/gastown/internal/checkpoint"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	restoreFrom  string
+	restoreForce bool
+)
+
+var restoreCmd = &cobra.Command{
+	Use:     "restore <agent>",
+	GroupID: GroupAgents,
+	Short:   "Put an agent back to a checkpoint after a bad run",
+	Long: `Restore an agent to a snapshot taken with gt checkpoint <agent>.
+
+The agent's working tree is put back as it was: its branch is reset to the
+checkpointed commit, and the uncommitted and untracked changes of the time
+come back as uncommitted changes. The beads it had hooked or in progress
+are assigned back to it with their status of the time.
+
+Everything since is first checkpointed itself, so a restore can be undone
+by restoring that checkpoint, which gt restore names.
+
+The agent's next session is told of the restore by gt prime, with the
+transcript position of the checkpoint. The agent must not be running,
+since its session would work on under it; --force stops it, restores, and
+starts it again.
+
+Examples:
+  gt checkpoint list gastown/crew/max
+  gt restore gastown/crew/max --from ckpt-2
+  gt restore gastown/polecats/Toast --from ckpt-1 --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRestore,
+}
+
+func init() {
+	restoreCmd.Flags().StringVar(&restoreFrom, "from", "", "Checkpoint to restore, e.g. ckpt-2 (required)")
+	restoreCmd.Flags().BoolVarP(&restoreForce, "force", "f", false, "Stop the agent's session if it is running, and restart it after")
+	_ = restoreCmd.MarkFlagRequired("from")
+	rootCmd.AddCommand(restoreCmd)
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	id, err := session.ParseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	agent := id.Address()
+	snap, err := checkpoint.LoadSnapshot(townRoot, agent, restoreFrom)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	t := tmux.NewTmux()
+	sessionName := id.SessionName()
+	running, _ := t.HasSession(sessionName)
+	if running && !restoreForce {
+		return fmt.Errorf("%s is running; stop it first (gt session stop %s) or pass --force", agent, agent)
+	}
+
+	// Keep what is being rolled back, so the restore can be undone.
+	before, err := snapshotAgent(townRoot, agent, "before restoring "+snap.ID)
+	if err != nil {
+		return fmt.Errorf("checkpointing current state: %w", err)
+	}
+	fmt.Printf("%s Checkpointed the current state as %s\n", style.Success.Render("✓"), style.Bold.Render(before.ID))
+
+	if running {
+		if err := t.KillSession(sessionName); err != nil {
+			return fmt.Errorf("stopping %s: %w", sessionName, err)
+		}
+		fmt.Printf("%s Stopped %s\n", style.Success.Render("✓"), sessionName)
+	}
+
+	if err := snap.RestoreWorkTree(); err != nil {
+		return fmt.Errorf("restoring working tree: %w", err)
+	}
+	fmt.Printf("%s Restored working tree: %s at %s, %d uncommitted file(s)\n",
+		style.Success.Render("✓"), snap.Branch, snap.Head[:min(12, len(snap.Head))], len(snap.Modified))
+
+	restoreTasks(snap, before)
+	leaveRestoreCheckpoint(snap, before)
+
+	_ = townlog.NewLogger(townRoot).LogEvent(townlog.Event{
+		Timestamp: time.Now(),
+		Type:      checkpoint.EventRestore,
+		Agent:     agent,
+		Context:   fmt.Sprintf("restored %s (current state kept as %s)", snap.ID, before.ID),
+	})
+
+	if snap.Transcript != "" {
+		fmt.Printf("  Transcript as of the checkpoint: %s:%d\n", snap.Transcript, snap.TranscriptLine)
+	}
+	if running {
+		if _, err := session.Wake(t, sessionName); err != nil {
+			return fmt.Errorf("restarting %s: %w", agent, err)
+		}
+		fmt.Printf("%s Restarted %s\n", style.Success.Render("✓"), agent)
+	}
+	fmt.Printf("\n  Undo with: gt restore %s --from %s\n", agent, before.ID)
+	return nil
+}
+
+// restoreTasks assigns the snapshot's beads back to the agent with their
+// status of the time, and points out beads the agent took on since.
+func restoreTasks(snap, current *checkpoint.Snapshot) {
+	b := beads.New(snap.WorkDir)
+	restored := map[string]bool{}
+	for _, task := range snap.Tasks {
+		restored[task.ID] = true
+		issue, err := b.Show(task.ID)
+		if err != nil {
+			style.PrintWarning("could not check task %s: %v", task.ID, err)
+			continue
+		}
+		if issue.Status == task.Status && issue.Assignee == snap.Agent {
+			continue
+		}
+		status, assignee := task.Status, snap.Agent
+		if err := b.Update(task.ID, beads.UpdateOptions{Status: &status, Assignee: &assignee}); err != nil {
+			style.PrintWarning("could not restore task %s: %v", task.ID, err)
+			continue
+		}
+		fmt.Printf("%s Restored task %s: %s → %s\n", style.Success.Render("✓"), task.ID, issue.Status, task.Status)
+	}
+	for _, task := range current.Tasks {
+		if !restored[task.ID] {
+			style.PrintWarning("%s took on %s [%s] after the checkpoint; it is still assigned", snap.Agent, task.ID, task.Status)
+		}
+	}
+}
+
+// leaveRestoreCheckpoint writes a crash-recovery checkpoint describing the
+// restore, which the agent's next gt prime shows.
+func leaveRestoreCheckpoint(snap, before *checkpoint.Snapshot) {
+	notes := fmt.Sprintf("Restored from %s, taken %s; the work since was rolled back (kept as %s).",
+		snap.ID, snap.CreatedAt.Local().Format("2006-01-02 15:04"), before.ID)
+	if snap.Transcript != "" {
+		notes += fmt.Sprintf(" Transcript as of the checkpoint: %s:%d.", snap.Transcript, snap.TranscriptLine)
+	}
+	if snap.Notes != "" {
+		notes += " Checkpoint notes: " + snap.Notes
+	}
+	cp := &checkpoint.Checkpoint{
+		Branch:        snap.Branch,
+		LastCommit:    snap.Head,
+		ModifiedFiles: snap.Modified,
+		SessionID:     snap.SessionID,
+		Notes:         notes,
+	}
+	for _, task := range snap.Tasks {
+		if task.Status == beads.StatusHooked {
+			cp.HookedBead = task.ID
+			break
+		}
+	}
+	if err := checkpoint.Write(snap.WorkDir, cp); err != nil {
+		style.PrintWarning("could not leave a checkpoint for the next session: %v", err)
+	}
+}
@@ -14,10 +14,12 @@ import (
 // Resume command checks for cleared gates and resumes parked work.
 
 var resumeCmd = &cobra.Command{
-	Use:     "resume",
+	Use:     "resume [agent]",
 	GroupID: GroupWork,
-	Short:   "Resume from parked work or check for handoff messages",
+	Short:   "Resume from parked work, or resume a paused agent",
 	Long: `Resume work that was parked on a gate, or check for handoff messages.
+With an agent (or --all), continue agents frozen by 'gt pause' instead,
+with their full context.
 
 By default, this command checks for parked work (from 'gt park') and whether
 its gate has cleared. If the gate is closed, it restores your work context.
@@ -34,7 +36,10 @@ The resume command:
 Examples:
   gt resume              # Check for and resume parked work
   gt resume --status     # Just show parked work status without resuming
-  gt resume --handoff    # Check inbox for handoff messages`,
+  gt resume --handoff    # Check inbox for handoff messages
+  gt resume gastown/crew/max   # Continue an agent frozen by gt pause
+  gt resume --all              # Continue every paused agent`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runResume,
 }
 
@@ -42,12 +47,14 @@ var (
 	resumeStatusOnly bool
 	resumeJSON       bool
 	resumeHandoff    bool
+	resumeAllPaused  bool
 )
 
 func init() {
 	resumeCmd.Flags().BoolVar(&resumeStatusOnly, "status", false, "Just show parked work status")
 	resumeCmd.Flags().BoolVar(&resumeJSON, "json", false, "Output as JSON")
 	resumeCmd.Flags().BoolVar(&resumeHandoff, "handoff", false, "Check for handoff messages instead of parked work")
+	resumeCmd.Flags().BoolVar(&resumeAllPaused, "all", false, "Resume every agent paused by gt pause")
 	rootCmd.AddCommand(resumeCmd)
 }
 
@@ -61,6 +68,13 @@ type ResumeStatus struct {
 }
 
 func runResume(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 || resumeAllPaused {
+		if len(args) == 1 && resumeAllPaused {
+			return fmt.Errorf("name an agent to resume, or use --all, not both")
+		}
+		return runResumePaused(cmd, args)
+	}
+
 	// If --handoff flag, check for handoff messages instead
 	if resumeHandoff {
 		return checkHandoffMessages()
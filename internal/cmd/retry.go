@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/beads"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/retry"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var retryCmd = &cobra.Command{
+	Use:     "retry <session>",
+	GroupID: GroupAgents,
+	Short:   "Retry the work of a crashed agent under the town's retry policy",
+	Long: `Decide what happens to the task on the hook of a crashed agent's session,
+under the town's retry policy for its rig, and do it.
+
+gt log crash runs this in the background for each crash it records in a
+rig the town has a retry policy for, so there is rarely a reason to run it
+by hand. Policies are set in the town's settings/config.json, with a
+default and overrides per rig:
+
+  "retry": {
+    "default": {"action": "respawn", "max_attempts": 3},
+    "rigs": {
+      "gastown": {"action": "reassign"},
+      "infra":   {"action": "escalate"}
+    }
+  }
+
+Actions:
+  respawn    Restart the same agent on the task, in its worktree
+  reassign   Take the task off the agent's hook and sling it to a fresh
+             polecat of the rig (gt sling <task> <rig>)
+  escalate   Leave the task for a human: gt escalate -s HIGH
+
+A task respawned or reassigned max_attempts times (default 3) is escalated
+instead; once it is escalated, the next crash on it starts a new count.
+Crashed agents with nothing on their hook are left to their rig's restart
+policy (see 'gt supervise'), as are the agents of rigs without a retry
+policy.
+
+Each decision is logged as a "retry" event, naming the task:
+
+  gt log --type retry
+
+Examples:
+  gt retry gt-gastown-Toast`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRetry,
+}
+
+func init() {
+	rootCmd.AddCommand(retryCmd)
+}
+
+func runRetry(cmd *cobra.Command, args []string) error {
+	sessionName := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if _, err := session.ParseSessionName(sessionName); err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+	id, policy, ok, err := retryPolicy(townRoot, sessionName)
+	if err != nil {
+		return err
+	}
+	agent := id.Address()
+	if !ok {
+		fmt.Printf("%s has no retry policy; not retrying\n", agent)
+		return nil
+	}
+
+	task, err := hookedTask(townRoot, id)
+	if err != nil {
+		return fmt.Errorf("finding the work on %s's hook: %w", agent, err)
+	}
+	if task == "" {
+		fmt.Printf("%s had nothing on its hook; leaving it to its restart policy\n", agent)
+		return startSupervise(townRoot, sessionName)
+	}
+
+	events, err := townlog.ReadEvents(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+	decision := retry.Decide(events, task, policy, time.Now())
+	t := tmux.NewTmux()
+	if decision.Action == retry.Respawn {
+		if running, err := agentRunning(t, sessionName); err != nil {
+			return err
+		} else if running {
+			fmt.Printf("%s is running again; not respawning\n", agent)
+			return nil
+		}
+	}
+
+	note := decision.Context(task, agent, policy)
+	e := townlog.Event{Timestamp: time.Now(), Type: retry.EventRetry, Agent: agent, Context: note}
+	var retryErr error
+	switch decision.Action {
+	case retry.Respawn:
+		_ = t.KillSession(sessionName) // a dead pane left by remain-on-exit
+		_, retryErr = session.Wake(t, sessionName)
+	case retry.Reassign:
+		// Clearing the crashed agent's hook is best effort: the sling
+		// hooks the task to the fresh polecat either way.
+		_ = runGT(townRoot, "unsling", task, agent, "--force")
+		retryErr = runGT(townRoot, "sling", task, id.Rig)
+	case retry.Escalate:
+		e.Severity = townlog.SeverityError
+		retryErr = runGT(townRoot, "escalate", "-s", SeverityHigh,
+			fmt.Sprintf("%s crashed working on %s", agent, task),
+			"-m", fmt.Sprintf("Retry policy for %s: %s.\n%s", id.Rig, policy.Action, note))
+	}
+	if retryErr != nil {
+		e.Context = fmt.Sprintf("%s failed: %v", note, retryErr)
+		e.Severity = townlog.SeverityError
+	}
+	if err := townlog.NewLogger(townRoot).LogEvent(e); err != nil {
+		style.PrintWarning("could not log retry: %v", err)
+	}
+	if retryErr != nil {
+		return retryErr
+	}
+	fmt.Printf("%s %s\n", style.Success.Render("✓"), note)
+	return nil
+}
+
+// retryPolicy returns the agent of a session and the town's retry policy
+// for its rig, if it has one.
+func retryPolicy(townRoot, sessionName string) (*session.AgentIdentity, retry.Policy, bool, error) {
+	id, err := session.ParseSessionName(sessionName)
+	if err != nil {
+		return nil, retry.Policy{}, false, err
+	}
+	if id.Role != session.RoleCrew && id.Role != session.RolePolecat {
+		return id, retry.Policy{}, false, nil // rig and town agents do no tasks to retry
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return id, retry.Policy{}, false, fmt.Errorf("loading town settings: %w", err)
+	}
+	policy, ok, err := retry.PolicyFor(settings.Retry, id.Rig)
+	return id, policy, ok, err
+}
+
+// hookedTask returns the ID of the bead hooked to an agent, or "" if none.
+func hookedTask(townRoot string, id *session.AgentIdentity) (string, error) {
+	hooked, err := beads.New(filepath.Join(townRoot, id.Rig)).List(beads.ListOptions{
+		Status:   beads.StatusHooked,
+		Priority: -1,
+	})
+	if err != nil {
+		return "", err
+	}
+	// Polecats are assigned both as rig/polecats/name and as rig/name
+	key := townlog.AgentKey(id.Address())
+	for _, issue := range hooked {
+		if townlog.AgentKey(issue.Assignee) == key {
+			return issue.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// runGT runs a gt command from townRoot, failing with the last line of its
+// output.
+func runGT(townRoot string, args ...string) error {
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+	c := exec.Command(gtPath, args...) //nolint:gosec // G204: args are constructed internally
+	c.Dir = townRoot
+	if out, err := c.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if i := strings.LastIndex(msg, "\n"); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return fmt.Errorf("gt %s: %v: %s", args[0], err, msg)
+	}
+	return nil
+}
+
+// startRetry starts 'gt retry' for a crashed session in the background,
+// and reports whether it did: only if the town has a retry policy for the
+// agent's rig.
+func startRetry(townRoot, sessionName string) (bool, error) {
+	if _, _, ok, err := retryPolicy(townRoot, sessionName); err != nil || !ok {
+		return false, err
+	}
+	gtPath, err := os.Executable()
+	if err != nil {
+		return false, fmt.Errorf("finding executable: %w", err)
+	}
+	c := exec.Command(gtPath, "retry", sessionName) //nolint:gosec // G204: session comes from the pane-died hook
+	c.Dir = townRoot
+	// A session of its own, so a reassignment outlives the tmux hook.
+	c.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := c.Start(); err != nil {
+		return false, fmt.Errorf("starting retry: %w", err)
+	}
+	return true, c.Process.Release()
+}
@@ -60,9 +60,21 @@ The command also:
   - Creates ~/gt/plugins/ (town-level) if it doesn't exist
   - Creates <rig>/plugins/ (rig-level)
 
+Several rigs can share one monorepo, each scoped to its directories with
+--paths. Their worktrees check out only those paths (plus files at the
+repository root), and 'gt rig route' maps changed files to the owning rig.
+Use --local-repo to share git objects between the rigs.
+
+For huge repositories, --filter makes partial clones that fetch file
+contents on demand and --depth makes shallow clones; polecat worktrees
+share the trimmed repository, so spawns stay fast. Fetch more history
+later with 'gt rig deepen'.
+
 Example:
   gt rig add gastown https://github.com/ctiospl/gastown
-  gt rig add my-project git@github.com:user/repo.git --prefix mp`,
+  gt rig add my-project git@github.com:user/repo.git --prefix mp
+  gt rig add api git@github.com:org/mono.git --paths services/api,libs/go --local-repo ~/src/mono
+  gt rig add big git@github.com:org/huge.git --filter blob:none --depth 50`,
 	Args: cobra.ExactArgs(2),
 	RunE: runRigAdd,
 }
@@ -253,6 +265,9 @@ var (
 	rigAddPrefix       string
 	rigAddLocalRepo    string
 	rigAddBranch       string
+	rigAddPaths        []string
+	rigAddFilter       string
+	rigAddDepth        int
 	rigResetHandoff    bool
 	rigResetMail       bool
 	rigResetStale      bool
@@ -283,6 +298,9 @@ func init() {
 	rigAddCmd.Flags().StringVar(&rigAddPrefix, "prefix", "", "Beads issue prefix (default: derived from name)")
 	rigAddCmd.Flags().StringVar(&rigAddLocalRepo, "local-repo", "", "Local repo path to share git objects (optional)")
 	rigAddCmd.Flags().StringVar(&rigAddBranch, "branch", "", "Default branch name (default: auto-detected from remote)")
+	rigAddCmd.Flags().StringSliceVar(&rigAddPaths, "paths", nil, "Monorepo directories the rig is scoped to, comma-separated (default: whole repo)")
+	rigAddCmd.Flags().StringVar(&rigAddFilter, "filter", "", "Partial clone filter, e.g. blob:none (default: full clone)")
+	rigAddCmd.Flags().IntVar(&rigAddDepth, "depth", 0, "Shallow clone with this many commits of history (default: full history)")
 
 	rigResetCmd.Flags().BoolVar(&rigResetHandoff, "handoff", false, "Clear handoff content")
 	rigResetCmd.Flags().BoolVar(&rigResetMail, "mail", false, "Clear stale mail messages")
@@ -328,6 +346,14 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	var cloneCfg *rig.CloneConfig
+	if rigAddDepth < 0 {
+		return fmt.Errorf("--depth must be positive")
+	}
+	if rigAddFilter != "" || rigAddDepth > 0 {
+		cloneCfg = &rig.CloneConfig{Filter: rigAddFilter, Depth: rigAddDepth}
+	}
+
 	// Create rig manager
 	g := git.NewGit(townRoot)
 	mgr := rig.NewManager(townRoot, rigsConfig, g)
@@ -337,6 +363,15 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	if rigAddLocalRepo != "" {
 		fmt.Printf("  Local repo: %s\n", rigAddLocalRepo)
 	}
+	if len(rigAddPaths) > 0 {
+		fmt.Printf("  Paths: %s\n", strings.Join(rigAddPaths, ", "))
+	}
+	if rigAddFilter != "" {
+		fmt.Printf("  Filter: %s\n", rigAddFilter)
+	}
+	if rigAddDepth > 0 {
+		fmt.Printf("  Depth: %d\n", rigAddDepth)
+	}
 
 	startTime := time.Now()
 
@@ -347,6 +382,8 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		BeadsPrefix:   rigAddPrefix,
 		LocalRepo:     rigAddLocalRepo,
 		DefaultBranch: rigAddBranch,
+		Paths:         rigAddPaths,
+		Clone:         cloneCfg,
 	})
 	if err != nil {
 		return fmt.Errorf("adding rig: %w", err)
@@ -864,7 +901,7 @@ func runRigStart(cmd *cobra.Command, args []string) error {
 	}
 	if len(failedRigs) > 0 {
 		fmt.Printf("%s Failed rigs: %s\n", style.Warning.Render("⚠"), strings.Join(failedRigs, ", "))
-		return fmt.Errorf("some rigs failed to start")
+		return partialFailure("some rigs failed to start")
 	}
 
 	return nil
@@ -1234,7 +1271,7 @@ func runRigStop(cmd *cobra.Command, args []string) error {
 		if len(failed) > 0 {
 			fmt.Printf("%s Failed: %s\n", style.Warning.Render("⚠"), strings.Join(failed, ", "))
 			fmt.Printf("\nUse %s to force shutdown (DANGER: will lose work!)\n", style.Bold.Render("--nuclear"))
-			return fmt.Errorf("some rigs failed to stop")
+			return partialFailure("some rigs failed to stop")
 		}
 	} else if len(failed) > 0 {
 		fmt.Printf("\nUse %s to force shutdown (DANGER: will lose work!)\n", style.Bold.Render("--nuclear"))
@@ -1422,7 +1459,7 @@ func runRigRestart(cmd *cobra.Command, args []string) error {
 		if len(failed) > 0 {
 			fmt.Printf("%s Failed: %s\n", style.Warning.Render("⚠"), strings.Join(failed, ", "))
 			fmt.Printf("\nUse %s to force shutdown (DANGER: will lose work!)\n", style.Bold.Render("--nuclear"))
-			return fmt.Errorf("some rigs failed to restart")
+			return partialFailure("some rigs failed to restart")
 		}
 	} else if len(failed) > 0 {
 		fmt.Printf("\nUse %s to force shutdown (DANGER: will lose work!)\n", style.Bold.Render("--nuclear"))
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/git"
+	"github.com/ctiospl/gastown/internal/rig"
+	"github.com/ctiospl/gastown/internal/style"
+)
+
+var (
+	rigDeepenBy         int
+	rigDeepenUnshallow  bool
+	rigDeepenBackground bool
+)
+
+var rigDeepenCmd = &cobra.Command{
+	Use:   "deepen [rig]",
+	Short: "Fetch more history into a rig's shallow clones",
+	Long: `Fetch more history of the default branch into a rig created with
+'gt rig add --depth'.
+
+The shared repository that polecat worktrees use is deepened, along with
+the mayor and crew clones. Clones with full history are skipped. Without
+--by, all history is fetched (--unshallow).
+
+With --background the fetch runs detached and logs to
+<rig>/.runtime/deepen.log, so agents can keep working meanwhile.
+
+Examples:
+  gt rig deepen gastown --by 500     # 500 more commits
+  gt rig deepen --unshallow          # All history (rig of current directory)
+  gt rig deepen gastown --background`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRigDeepen,
+}
+
+func init() {
+	rigDeepenCmd.Flags().IntVar(&rigDeepenBy, "by", 0, "Fetch this many more commits (default: all history)")
+	rigDeepenCmd.Flags().BoolVar(&rigDeepenUnshallow, "unshallow", false, "Fetch all history")
+	rigDeepenCmd.Flags().BoolVar(&rigDeepenBackground, "background", false, "Run detached, logging to <rig>/.runtime/deepen.log")
+
+	rigCmd.AddCommand(rigDeepenCmd)
+}
+
+func runRigDeepen(cmd *cobra.Command, args []string) error {
+	if rigDeepenUnshallow && rigDeepenBy > 0 {
+		return fmt.Errorf("--by and --unshallow are mutually exclusive")
+	}
+	if rigDeepenBy < 0 {
+		return fmt.Errorf("--by must be positive")
+	}
+
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	_, r, err := getRigOrCwd(rigName)
+	if err != nil {
+		return err
+	}
+
+	if rigDeepenBackground {
+		return startBackgroundDeepen(r)
+	}
+
+	branch := r.DefaultBranch()
+	deepened := 0
+	for _, c := range shallowClones(r) {
+		how := "all history"
+		if rigDeepenBy > 0 {
+			how = fmt.Sprintf("%d more commits", rigDeepenBy)
+		}
+		fmt.Printf("Deepening %s (%s)...\n", c.name, how)
+		if err := c.git.Deepen("origin", branch, rigDeepenBy); err != nil {
+			return fmt.Errorf("deepening %s: %w", c.name, err)
+		}
+		deepened++
+	}
+	if deepened == 0 {
+		fmt.Printf("%s %s has no shallow clones\n", style.Dim.Render("○"), r.Name)
+		return nil
+	}
+	fmt.Printf("%s Deepened %d clone(s) of %s\n", style.Success.Render("✓"), deepened, r.Name)
+	return nil
+}
+
+// rigClone is one of a rig's git repositories.
+type rigClone struct {
+	name string
+	git  *git.Git
+}
+
+// shallowClones returns the rig's shallow repositories: the shared bare
+// repo, the mayor clone, and crew clones.
+func shallowClones(r *rig.Rig) []rigClone {
+	var clones []rigClone
+	add := func(name string, g *git.Git) {
+		if shallow, err := g.IsShallow(); err == nil && shallow {
+			clones = append(clones, rigClone{name: name, git: g})
+		}
+	}
+
+	// Missing clones fail IsShallow and are skipped.
+	add("shared repo", git.NewGitWithDir(filepath.Join(r.Path, ".repo.git"), ""))
+	add("mayor/rig", git.NewGit(filepath.Join(r.Path, "mayor", "rig")))
+	for _, name := range r.Crew {
+		add("crew/"+name, git.NewGit(filepath.Join(r.Path, "crew", name)))
+	}
+	return clones
+}
+
+// startBackgroundDeepen re-runs 'gt rig deepen' detached from the
+// terminal, with output going to the rig's deepen log.
+func startBackgroundDeepen(r *rig.Rig) error {
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+
+	runtimeDir := filepath.Join(r.Path, ".runtime")
+	if err := os.MkdirAll(runtimeDir, 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	logPath := filepath.Join(runtimeDir, "deepen.log")
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("opening deepen log: %w", err)
+	}
+	defer logFile.Close()
+
+	args := []string{"rig", "deepen", r.Name}
+	if rigDeepenBy > 0 {
+		args = append(args, "--by", strconv.Itoa(rigDeepenBy))
+	}
+	deepenCmd := exec.Command(gtPath, args...)
+	deepenCmd.Dir = r.Path
+	deepenCmd.Stdin = nil
+	deepenCmd.Stdout = logFile
+	deepenCmd.Stderr = logFile
+	if err := deepenCmd.Start(); err != nil {
+		return fmt.Errorf("starting deepen: %w", err)
+	}
+
+	fmt.Printf("%s Deepening %s in the background (PID %d)\n", style.Success.Render("✓"), r.Name, deepenCmd.Process.Pid)
+	fmt.Printf("  Log: %s\n", style.Dim.Render(logPath))
+	return nil
+}
@@ -34,3 +34,18 @@ func getRig(rigName string) (string, *rig.Rig, error) {
 
 	return townRoot, r, nil
 }
+
+// getRigOrCwd is getRig for commands whose rig argument is optional: an
+// empty name means the rig of the current directory.
+func getRigOrCwd(rigName string) (string, *rig.Rig, error) {
+	if rigName == "" {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		if rigName, err = inferRigFromCwd(townRoot); err != nil {
+			return "", nil, fmt.Errorf("could not determine rig: %w", err)
+		}
+	}
+	return getRig(rigName)
+}
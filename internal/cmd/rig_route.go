@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/git"
+	"github.com/ctiospl/gastown/internal/rig"
+	"github.com/ctiospl/gastown/internal/style"
+)
+
+var (
+	rigRouteRig  string
+	rigRouteBase string
+	rigRouteJSON bool
+)
+
+var rigRouteCmd = &cobra.Command{
+	Use:   "route [file...]",
+	Short: "Show which rigs of a monorepo own a set of changed files",
+	Long: `Map changed files to the rigs that own them, for rigs scoped to
+directories of one monorepo (see 'gt rig add --paths').
+
+A file goes to the rig with the most specific matching path. A rig over
+the same repository without paths takes the files no scoped rig owns.
+Files are relative to the repository root; without arguments, the files
+the current branch changed since it forked from the rig's default branch
+are routed.
+
+'gt done' uses the same rules to warn when a branch of a scoped rig
+changes files owned by another rig.
+
+Examples:
+  gt rig route                                  # Files changed on this branch
+  gt rig route services/api/main.go libs/go/x.go
+  gt rig route --rig api --base origin/release-2
+  gt rig route --json`,
+	RunE: runRigRoute,
+}
+
+func init() {
+	rigRouteCmd.Flags().StringVar(&rigRouteRig, "rig", "", "Rig whose repository to route within (default: rig of the current directory)")
+	rigRouteCmd.Flags().StringVar(&rigRouteBase, "base", "", "Compare the current branch against this ref (default: origin/<default branch>)")
+	rigRouteCmd.Flags().BoolVar(&rigRouteJSON, "json", false, "Output as JSON")
+
+	rigCmd.AddCommand(rigRouteCmd)
+}
+
+// rigRouteJSONOutput is the --json form of 'gt rig route'.
+type rigRouteJSONOutput struct {
+	Routes   map[string][]string `json:"routes"`
+	Unrouted []string            `json:"unrouted"`
+}
+
+func runRigRoute(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := getRigOrCwd(rigRouteRig)
+	if err != nil {
+		return err
+	}
+
+	files := args
+	if len(files) == 0 {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting current directory: %w", err)
+		}
+		base := rigRouteBase
+		if base == "" {
+			base = "origin/" + r.DefaultBranch()
+		}
+		if files, err = git.NewGit(cwd).ChangedFiles(base, "HEAD"); err != nil {
+			return fmt.Errorf("listing files changed since %s: %w", base, err)
+		}
+	}
+
+	routed, unrouted := rig.RouteFiles(monorepoScopes(townRoot, r.GitURL), files)
+	if rigRouteJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rigRouteJSONOutput{Routes: routed, Unrouted: unrouted})
+	}
+	if len(files) == 0 {
+		fmt.Printf("%s No changed files\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	for _, name := range sortedKeys(routed) {
+		scope := "whole repository"
+		if paths := rigScopeOf(townRoot, name); len(paths) > 0 {
+			scope = strings.Join(paths, ", ")
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render(name), style.Dim.Render("("+scope+")"))
+		for _, f := range routed[name] {
+			fmt.Printf("  %s\n", f)
+		}
+	}
+	if len(unrouted) > 0 {
+		fmt.Printf("%s\n", style.Warning.Render("No rig"))
+		for _, f := range unrouted {
+			fmt.Printf("  %s\n", f)
+		}
+	}
+	return nil
+}
+
+// monorepoScopes returns the paths of every rig over the repository at
+// gitURL, keyed by rig name. Rigs covering the whole repository have none.
+func monorepoScopes(townRoot, gitURL string) map[string][]string {
+	scopes := make(map[string][]string)
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return scopes
+	}
+	for name, entry := range rigsConfig.Rigs {
+		if entry.GitURL == gitURL {
+			scopes[name] = rigScopeOf(townRoot, name)
+		}
+	}
+	return scopes
+}
+
+// rigScopeOf returns a rig's monorepo paths, or nil for a whole-repo rig.
+func rigScopeOf(townRoot, rigName string) []string {
+	cfg, err := rig.LoadRigConfig(filepath.Join(townRoot, rigName))
+	if err != nil {
+		return nil
+	}
+	return cfg.Paths
+}
+
+// warnOutOfScope warns when a branch of a path-scoped rig changes files
+// outside the rig's paths, naming the rigs that own them.
+func warnOutOfScope(townRoot, rigName string, files []string) {
+	cfg, err := rig.LoadRigConfig(filepath.Join(townRoot, rigName))
+	if err != nil || len(cfg.Paths) == 0 {
+		return
+	}
+	var outside []string
+	for _, f := range files {
+		if !rig.OwnsPath(cfg.Paths, f) {
+			outside = append(outside, f)
+		}
+	}
+	if len(outside) == 0 {
+		return
+	}
+
+	scopes := monorepoScopes(townRoot, cfg.GitURL)
+	delete(scopes, rigName)
+	routed, unrouted := rig.RouteFiles(scopes, outside)
+	style.PrintWarning("%d changed file(s) are outside %s's paths (%s)", len(outside), rigName, strings.Join(cfg.Paths, ", "))
+	for _, name := range sortedKeys(routed) {
+		fmt.Printf("  owned by %s: %s\n", name, summarizeFiles(routed[name]))
+	}
+	if len(unrouted) > 0 {
+		fmt.Printf("  owned by no rig: %s\n", summarizeFiles(unrouted))
+	}
+}
+
+// summarizeFiles lists the first few files and how many more there are.
+func summarizeFiles(files []string) string {
+	const shown = 3
+	if len(files) <= shown {
+		return strings.Join(files, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(files[:shown], ", "), len(files)-shown)
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/claude"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/templates"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var rolesJSON bool
+
+var rolesCmd = &cobra.Command{
+	Use:     "roles",
+	GroupID: GroupAgents,
+	Short:   "List and inspect agent roles",
+	Long: `List and inspect the roles agents run in.
+
+Agent roles are what crew agents are for, given with gt spawn --role. A
+role sets the prompt the agent starts with (a template rendered with
+{{.Rig}}, {{.Name}}, and {{.Address}}), the tools it may use, and Claude
+Code hooks for its sessions. reviewer and tester are built in; the town's
+settings/roles/<name>.json defines more, or overrides them:
+
+  {
+    "type": "agent-role",
+    "version": 1,
+    "description": "Audits changes for security issues",
+    "prompt": "You are {{.Address}}, a security auditor in {{.Rig}}.",
+    "allowed_tools": ["Read", "Grep", "Bash(git log:*)"],
+    "disallowed_tools": ["Edit", "Write"],
+    "hooks": {"Stop": ["gt costs record"]}
+  }
+
+  allowed_tools     tools the agent may use without asking; with any, Claude
+                    Code asks before the others instead of skipping its
+                    permission checks (default: all tools)
+  disallowed_tools  tools the agent may never use
+  hooks             commands by Claude Code hook event (SessionStart,
+                    UserPromptSubmit, PreToolUse, PostToolUse, Notification,
+                    PreCompact, Stop, SubagentStop, SessionEnd), run in
+                    addition to those of the workspace's settings
+
+Gas Town roles (mayor, deacon, witness, refinery, polecat, crew) are the
+ones gt starts agents in itself: their prompt is the role context gt prime
+prints, and their hooks are those of the Claude Code settings gt installs.`,
+	RunE: requireSubcommand,
+}
+
+var rolesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List agent roles and Gas Town roles",
+	Args:  cobra.NoArgs,
+	RunE:  runRolesList,
+}
+
+var rolesShowCmd = &cobra.Command{
+	Use:   "show <role>",
+	Short: "Show a role's prompt, tools, and hooks",
+	Long: `Show what a role sets up: its prompt template, the tools its agents may
+use, and the hooks their sessions run.
+
+Examples:
+  gt roles show reviewer
+  gt roles show polecat
+  gt roles show tester --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRolesShow,
+}
+
+func init() {
+	rolesListCmd.Flags().BoolVar(&rolesJSON, "json", false, "Output as JSON")
+	rolesShowCmd.Flags().BoolVar(&rolesJSON, "json", false, "Output as JSON")
+	rolesCmd.AddCommand(rolesListCmd)
+	rolesCmd.AddCommand(rolesShowCmd)
+	rootCmd.AddCommand(rolesCmd)
+}
+
+// gasTownRole describes a role gt starts agents in itself.
+type gasTownRole struct {
+	Description string
+	StartedBy   string
+}
+
+// gasTownRoles describes config.GasTownRoles.
+var gasTownRoles = map[string]gasTownRole{
+	"mayor":    {"Coordinates the town's work across its rigs", "gt mayor start"},
+	"deacon":   {"Watches over the town's agents and runs its patrols", "gt deacon start"},
+	"witness":  {"Watches a rig's polecats, nudging or recycling stuck ones", "gt witness start <rig>"},
+	"refinery": {"Merges a rig's finished work through its merge queue", "gt refinery start <rig>"},
+	"polecat":  {"Works one task in a worktree of its own, then is recycled", "gt sling <bead> <rig>"},
+	"crew":     {"A persistent, named agent working with a human", "gt spawn <rig>/crew/<name>"},
+}
+
+// roleView is a role as gt roles prints it.
+type roleView struct {
+	Name            string              `json:"name"`
+	Kind            string              `json:"kind"` // "agent", "built-in agent", or "gas town"
+	Description     string              `json:"description,omitempty"`
+	Prompt          string              `json:"prompt,omitempty"`
+	PromptSource    string              `json:"prompt_source"`
+	AllowedTools    []string            `json:"allowed_tools,omitempty"`
+	DisallowedTools []string            `json:"disallowed_tools,omitempty"`
+	Hooks           map[string][]string `json:"hooks,omitempty"`
+	StartedBy       string              `json:"started_by"`
+}
+
+func runRolesList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	names, err := config.ListAgentRoles(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing roles: %w", err)
+	}
+	var views []roleView
+	var broken []string
+	for _, name := range names {
+		v, err := agentRoleView(townRoot, name)
+		if err != nil {
+			broken = append(broken, fmt.Sprintf("%-12s %s", name, style.Error.Render(err.Error())))
+			continue
+		}
+		views = append(views, v)
+	}
+	for _, name := range config.GasTownRoles {
+		v, err := gasTownRoleView(name)
+		if err != nil {
+			return err
+		}
+		views = append(views, v)
+	}
+	if rolesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(views)
+	}
+
+	fmt.Println(style.Bold.Render("Agent roles") + style.Dim.Render(" (gt spawn --role <role>)"))
+	for _, v := range views {
+		if v.Kind == "gas town" {
+			continue
+		}
+		note := ""
+		if v.Kind == "built-in agent" {
+			note = style.Dim.Render(" (built-in)")
+		}
+		fmt.Printf("  %-12s %s%s\n", v.Name, v.Description, note)
+	}
+	for _, line := range broken {
+		fmt.Printf("  %s\n", line)
+	}
+	fmt.Println()
+	fmt.Println(style.Bold.Render("Gas Town roles") + style.Dim.Render(" (started by gt)"))
+	for _, v := range views {
+		if v.Kind == "gas town" {
+			fmt.Printf("  %-12s %s %s\n", v.Name, v.Description, style.Dim.Render("("+v.StartedBy+")"))
+		}
+	}
+	return nil
+}
+
+func runRolesShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cmd.SilenceUsage = true
+	var v roleView
+	if _, ok := gasTownRoles[args[0]]; ok {
+		v, err = gasTownRoleView(args[0])
+	} else {
+		v, err = agentRoleView(townRoot, args[0])
+	}
+	if err != nil {
+		return err
+	}
+	if rolesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	fmt.Print(v.String())
+	return nil
+}
+
+// agentRoleView returns the view of the named agent role.
+func agentRoleView(townRoot, name string) (roleView, error) {
+	r, err := config.LoadAgentRole(townRoot, name)
+	if err != nil {
+		return roleView{}, err
+	}
+	v := roleView{
+		Name:            r.Name,
+		Kind:            "agent",
+		Description:     r.Description,
+		Prompt:          r.Prompt,
+		PromptSource:    filepath.Join(config.AgentRolesDir(townRoot), r.Name+".json"),
+		AllowedTools:    r.AllowedTools,
+		DisallowedTools: r.DisallowedTools,
+		Hooks:           r.Hooks,
+		StartedBy:       "gt spawn --role " + r.Name + " <rig>/crew/<name>",
+	}
+	if r.Builtin {
+		v.Kind = "built-in agent"
+		v.PromptSource = "built in"
+	}
+	return v, nil
+}
+
+// gasTownRoleView returns the view of the named Gas Town role.
+func gasTownRoleView(name string) (roleView, error) {
+	g := gasTownRoles[name]
+	prompts, err := templates.GetAllRoleTemplates()
+	if err != nil {
+		return roleView{}, fmt.Errorf("reading role templates: %w", err)
+	}
+	hooks, err := claude.Hooks(claude.RoleTypeFor(name))
+	if err != nil {
+		return roleView{}, err
+	}
+	return roleView{
+		Name:         name,
+		Kind:         "gas town",
+		Description:  g.Description,
+		Prompt:       string(prompts[name+".md.tmpl"]),
+		PromptSource: "templates/roles/" + name + ".md.tmpl, printed by gt prime",
+		Hooks:        hooks,
+		StartedBy:    g.StartedBy,
+	}, nil
+}
+
+// String renders the view as gt roles show prints it.
+func (v roleView) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", style.Bold.Render(v.Name), style.Dim.Render("("+v.Kind+" role)"))
+	if v.Description != "" {
+		fmt.Fprintf(&b, "  %s\n", v.Description)
+	}
+	fmt.Fprintf(&b, "\nStarted by: %s\n", v.StartedBy)
+
+	tools := "all"
+	if len(v.AllowedTools) > 0 {
+		tools = strings.Join(v.AllowedTools, ", ") + "; others after asking"
+	}
+	fmt.Fprintf(&b, "Tools:      %s\n", tools)
+	if len(v.DisallowedTools) > 0 {
+		fmt.Fprintf(&b, "Never:      %s\n", strings.Join(v.DisallowedTools, ", "))
+	}
+
+	fmt.Fprintf(&b, "\nHooks:\n")
+	if len(v.Hooks) == 0 {
+		fmt.Fprintf(&b, "  %s\n", style.Dim.Render("(none beyond the workspace's)"))
+	}
+	events := make([]string, 0, len(v.Hooks))
+	for event := range v.Hooks {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	for _, event := range events {
+		for _, c := range v.Hooks[event] {
+			fmt.Fprintf(&b, "  %-17s %s\n", event+":", c)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nPrompt %s:\n", style.Dim.Render("("+v.PromptSource+")"))
+	if v.Prompt == "" {
+		fmt.Fprintf(&b, "  %s\n", style.Dim.Render("(none)"))
+		return b.String()
+	}
+	for _, line := range strings.Split(strings.TrimRight(v.Prompt, "\n"), "\n") {
+		if line != "" {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRoleViews(t *testing.T) {
+	v, err := agentRoleView(t.TempDir(), "reviewer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Kind != "built-in agent" || v.StartedBy != "gt spawn --role reviewer <rig>/crew/<name>" {
+		t.Errorf("reviewer view = %+v", v)
+	}
+	out := v.String()
+	for _, want := range []string{"Read, Grep", "; others after asking", "Never:", "(none beyond the workspace's)", "You are {{.Address}}"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("reviewer output missing %q:\n%s", want, out)
+		}
+	}
+
+	for name := range gasTownRoles {
+		v, err := gasTownRoleView(name)
+		if err != nil {
+			t.Fatalf("gasTownRoleView(%s): %v", name, err)
+		}
+		if v.Prompt == "" || len(v.Hooks["SessionStart"]) == 0 {
+			t.Errorf("%s view has no prompt or SessionStart hooks: %+v", name, v)
+		}
+	}
+}
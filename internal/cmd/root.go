@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/townlog"
 )
 
 var rootCmd = &cobra.Command{
@@ -15,21 +16,37 @@ var rootCmd = &cobra.Command{
 	Long: `Gas Town (gt) manages multi-agent workspaces called rigs.
 
 It coordinates agent spawning, work distribution, and communication
-across distributed teams of AI agents working on shared codebases.`,
+across distributed teams of AI agents working on shared codebases.
+
+Diagnostics are written to logs/gt-internal.log (JSON lines, separate from
+the town activity log). Use -v (debug) or -vv (trace: every tmux, git, and
+bd call) to raise the level and mirror it to stderr, or --log-level / GT_DEBUG
+for commands that define their own -v. Attach this log to bug reports.
+
+Messages in log and status output follow GT_LANG (or LC_ALL/LC_MESSAGES/LANG).
+Available locales: en, es, de.`,
+	PersistentPreRunE: persistentPreRun,
 }
 
 // Execute runs the root command and returns an exit code.
 // The caller (main) should call os.Exit with this code.
 func Execute() int {
-	if err := rootCmd.Execute(); err != nil {
-		// Check for silent exit (scripting commands that signal status via exit code)
-		if code, ok := IsSilentExit(err); ok {
-			return code
+	markUsageErrors(rootCmd)
+	registerOpLocks(rootCmd)
+	cmd, err := rootCmd.ExecuteC()
+	releaseOpLock()
+	finishInternalLog(err)
+	// Let webhook posts for events this command logged finish.
+	townlog.WaitWebhooks(townlog.WebhookTimeout)
+	if err != nil {
+		// Silent exits (scripting commands that signal status via exit code)
+		// print nothing
+		if _, ok := IsSilentExit(err); !ok {
+			reportCommandError(cmd, err)
 		}
-		// Other errors already printed by cobra
-		return 1
 	}
-	return 0
+	// The exit status classifies the error (see 'gt errors')
+	return exitStatus(err)
 }
 
 // Command group IDs - used by subcommands to organize help output
@@ -47,6 +64,10 @@ func init() {
 	// Enable prefix matching for subcommands (e.g., "gt ref at" -> "gt refinery attach")
 	cobra.EnablePrefixMatching = true
 
+	// Execute prints errors itself, with their codes (see reportCommandError)
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+
 	// Define command groups (order determines help output order)
 	rootCmd.AddGroup(
 		&cobra.Group{ID: GroupWork, Title: "Work Management:"},
@@ -62,8 +83,7 @@ func init() {
 	rootCmd.SetHelpCommandGroupID(GroupDiag)
 	rootCmd.SetCompletionCommandGroupID(GroupConfig)
 
-	// Global flags can be added here
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file")
+	// Global flags (-v, --log-level) are registered in logging.go
 }
 
 // buildCommandPath walks the command hierarchy to build the full command path.
@@ -0,0 +1,402 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// SandboxMarker is the metadata file written at the root of every sandbox town.
+// Its presence is what makes "gt sandbox destroy" willing to delete a directory.
+const SandboxMarker = ".sandbox.json"
+
+// SandboxInfo describes a disposable experiment town.
+type SandboxInfo struct {
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	RigName   string    `json:"rig_name,omitempty"`
+	RigURL    string    `json:"rig_url,omitempty"`
+	Shallow   bool      `json:"shallow,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	dir string // the directory the marker was read from
+}
+
+var (
+	sandboxRig     string
+	sandboxRigName string
+	sandboxShallow bool
+	sandboxBeads   bool
+	sandboxAll     bool
+)
+
+var sandboxCmd = &cobra.Command{
+	Use:     "sandbox",
+	GroupID: GroupWorkspace,
+	Short:   "Manage disposable experiment towns",
+	RunE:    requireSubcommand,
+	Long: `Manage throwaway towns for trying prompts and policies safely.
+
+A sandbox is a complete Gas Town HQ created in a temporary directory
+(under $TMPDIR/gt-sandboxes/). It never touches your production town:
+it has its own mayor/, settings/, logs/ and rigs.
+
+Tmux session names are machine-global, so avoid starting agents in a
+sandbox whose rig name collides with a production rig.
+
+Examples:
+  gt sandbox create                                   # Empty sandbox town
+  gt sandbox create try-prompts --rig https://github.com/user/repo
+  gt sandbox create quick --rig https://github.com/user/repo --shallow
+  gt sandbox list
+  gt sandbox destroy try-prompts
+  gt sandbox destroy --all`,
+}
+
+var sandboxCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create a disposable sandbox town",
+	Long: `Create a throwaway town in a temp directory.
+
+With --rig, the sandbox is pre-wired to a rig cloned from the given URL.
+With --shallow, the repository is first cloned with --depth 1 and used as
+the local reference, which keeps large repos cheap to sandbox.
+
+Beads are skipped by default to keep sandboxes fast and self-contained;
+pass --beads to initialize them.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSandboxCreate,
+}
+
+var sandboxListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List sandbox towns",
+	RunE:  runSandboxList,
+}
+
+var sandboxDestroyCmd = &cobra.Command{
+	Use:   "destroy [name...]",
+	Short: "Destroy sandbox towns and their sessions",
+	Long: `Destroy one or more sandbox towns.
+
+Kills any tmux sessions whose working directory is inside the sandbox,
+then removes the sandbox directory. Only directories containing a
+sandbox marker that names them are ever removed.`,
+	RunE: runSandboxDestroy,
+}
+
+var sandboxPathCmd = &cobra.Command{
+	Use:   "path <name>",
+	Short: "Print the path to a sandbox town",
+	Long: `Print the path to a sandbox town, for use with cd:
+
+  cd $(gt sandbox path try-prompts)`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSandboxPath,
+}
+
+func init() {
+	sandboxCreateCmd.Flags().StringVar(&sandboxRig, "rig", "", "Git URL of a rig to add to the sandbox")
+	sandboxCreateCmd.Flags().StringVar(&sandboxRigName, "rig-name", "", "Rig name (default: derived from URL)")
+	sandboxCreateCmd.Flags().BoolVar(&sandboxShallow, "shallow", false, "Use a shallow clone as the rig's local reference")
+	sandboxCreateCmd.Flags().BoolVar(&sandboxBeads, "beads", false, "Initialize town beads (requires bd)")
+
+	sandboxDestroyCmd.Flags().BoolVar(&sandboxAll, "all", false, "Destroy all sandboxes")
+
+	sandboxCmd.AddCommand(sandboxCreateCmd)
+	sandboxCmd.AddCommand(sandboxListCmd)
+	sandboxCmd.AddCommand(sandboxDestroyCmd)
+	sandboxCmd.AddCommand(sandboxPathCmd)
+	rootCmd.AddCommand(sandboxCmd)
+}
+
+// sandboxRoot returns the directory that holds all sandbox towns.
+func sandboxRoot() string {
+	return filepath.Join(os.TempDir(), "gt-sandboxes")
+}
+
+// sandboxPath returns the town root for a named sandbox.
+func sandboxPath(name string) string {
+	return filepath.Join(sandboxRoot(), name)
+}
+
+// validateSandboxName rejects names that would escape the sandbox root.
+func validateSandboxName(name string) error {
+	if name == "" {
+		return fmt.Errorf("sandbox name cannot be empty")
+	}
+	if strings.ContainsAny(name, `/\ `) || name == "." || name == ".." {
+		return fmt.Errorf("invalid sandbox name %q: must not contain slashes or spaces", name)
+	}
+	return nil
+}
+
+// rigNameFromURL derives a rig name from a git URL ("…/my-repo.git" → "my_repo").
+func rigNameFromURL(gitURL string) string {
+	base := strings.TrimSuffix(strings.TrimRight(gitURL, "/"), ".git")
+	if idx := strings.LastIndexAny(base, "/:"); idx >= 0 {
+		base = base[idx+1:]
+	}
+	base = strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(base)
+	return strings.ToLower(base)
+}
+
+// loadSandbox reads the sandbox marker for a sandbox town.
+func loadSandbox(path string) (*SandboxInfo, error) {
+	data, err := os.ReadFile(filepath.Join(path, SandboxMarker)) //nolint:gosec // G304: path is under the sandbox root
+	if err != nil {
+		return nil, err
+	}
+	var info SandboxInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parsing sandbox marker: %w", err)
+	}
+	info.dir = path
+	return &info, nil
+}
+
+// destroyDir returns the directory destroying sb removes: the one holding
+// its marker. A marker naming another path (stale or edited) is refused,
+// so destroy never removes anything outside the sandbox root.
+func (sb *SandboxInfo) destroyDir() (string, error) {
+	if filepath.Clean(sb.Path) != filepath.Clean(sb.dir) {
+		return "", fmt.Errorf("sandbox %s: marker names %s, not %s; remove it by hand", sb.Name, sb.Path, sb.dir)
+	}
+	return sb.dir, nil
+}
+
+// listSandboxes returns all sandboxes under the sandbox root, oldest first.
+func listSandboxes() ([]*SandboxInfo, error) {
+	entries, err := os.ReadDir(sandboxRoot())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var result []*SandboxInfo
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		info, err := loadSandbox(filepath.Join(sandboxRoot(), entry.Name()))
+		if err != nil {
+			continue // Not a sandbox (or marker unreadable) - never touch it
+		}
+		result = append(result, info)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+func runSandboxCreate(cmd *cobra.Command, args []string) error {
+	name := fmt.Sprintf("sb-%s", time.Now().Format("20060102-150405"))
+	if len(args) > 0 {
+		name = args[0]
+	}
+	if err := validateSandboxName(name); err != nil {
+		return err
+	}
+
+	path := sandboxPath(name)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("sandbox %q already exists at %s", name, path)
+	}
+
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+
+	fmt.Printf("%s Creating sandbox %s\n", style.Bold.Render("🧪"), style.Bold.Render(name))
+
+	success := false
+	defer func() {
+		if !success {
+			_ = os.RemoveAll(path) // best-effort cleanup of partial sandbox
+		}
+	}()
+
+	installArgs := []string{"install", path, "--name", "sandbox-" + name}
+	if !sandboxBeads {
+		installArgs = append(installArgs, "--no-beads")
+	}
+	if err := runSandboxStep(gtPath, "", installArgs...); err != nil {
+		return fmt.Errorf("creating sandbox town: %w", err)
+	}
+
+	info := &SandboxInfo{
+		Name:      name,
+		Path:      path,
+		CreatedAt: time.Now(),
+	}
+
+	if sandboxRig != "" {
+		rigName := sandboxRigName
+		if rigName == "" {
+			rigName = rigNameFromURL(sandboxRig)
+		}
+		info.RigName = rigName
+		info.RigURL = sandboxRig
+		info.Shallow = sandboxShallow
+
+		rigArgs := []string{"rig", "add", rigName, sandboxRig}
+		if sandboxShallow {
+			seedPath := filepath.Join(path, ".sandbox-seed")
+			fmt.Printf("  Shallow-cloning %s...\n", sandboxRig)
+			if err := runSandboxStep("git", "", "clone", "--depth", "1", sandboxRig, seedPath); err != nil {
+				return fmt.Errorf("shallow clone: %w", err)
+			}
+			rigArgs = append(rigArgs, "--local-repo", seedPath)
+		}
+		if err := runSandboxStep(gtPath, path, rigArgs...); err != nil {
+			return fmt.Errorf("adding rig %s: %w", rigName, err)
+		}
+	}
+
+	if err := util.AtomicWriteJSON(filepath.Join(path, SandboxMarker), info); err != nil {
+		return fmt.Errorf("writing sandbox marker: %w", err)
+	}
+	success = true
+
+	fmt.Printf("\n%s Sandbox ready: %s\n", style.SuccessPrefix, path)
+	fmt.Printf("  cd %s\n", path)
+	fmt.Printf("  Destroy with: %s\n", style.Dim.Render("gt sandbox destroy "+name))
+	return nil
+}
+
+// runSandboxStep runs a setup command, streaming its output indented.
+func runSandboxStep(bin, dir string, args ...string) error {
+	c := exec.Command(bin, args...) //nolint:gosec // G204: args are constructed internally
+	if dir != "" {
+		c.Dir = dir
+	}
+	out, err := c.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %s", filepath.Base(bin), args[0], strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func runSandboxList(cmd *cobra.Command, args []string) error {
+	sandboxes, err := listSandboxes()
+	if err != nil {
+		return fmt.Errorf("listing sandboxes: %w", err)
+	}
+	if len(sandboxes) == 0 {
+		fmt.Printf("%s No sandboxes\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	table := style.NewTable(
+		style.Column{Name: "NAME", Width: 24},
+		style.Column{Name: "RIG", Width: 16},
+		style.Column{Name: "AGE", Width: 10},
+		style.Column{Name: "PATH", Width: 50},
+	)
+	for _, sb := range sandboxes {
+		rigName := sb.RigName
+		if rigName == "" {
+			rigName = "-"
+		} else if sb.Shallow {
+			rigName += " (shallow)"
+		}
+		table.AddRow(sb.Name, rigName, formatSandboxAge(time.Since(sb.CreatedAt)), sb.Path)
+	}
+	fmt.Print(table.Render())
+	return nil
+}
+
+// formatSandboxAge renders a coarse age (e.g., "5m", "3h", "2d").
+func formatSandboxAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func runSandboxDestroy(cmd *cobra.Command, args []string) error {
+	var targets []*SandboxInfo
+	if sandboxAll {
+		all, err := listSandboxes()
+		if err != nil {
+			return fmt.Errorf("listing sandboxes: %w", err)
+		}
+		targets = all
+	} else {
+		if len(args) == 0 {
+			return fmt.Errorf("specify sandbox name(s) or --all")
+		}
+		for _, name := range args {
+			if err := validateSandboxName(name); err != nil {
+				return err
+			}
+			info, err := loadSandbox(sandboxPath(name))
+			if err != nil {
+				return fmt.Errorf("sandbox %q not found", name)
+			}
+			targets = append(targets, info)
+		}
+	}
+
+	if len(targets) == 0 {
+		fmt.Printf("%s No sandboxes to destroy\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	t := tmux.NewTmux()
+	refused := 0
+	for _, sb := range targets {
+		dir, err := sb.destroyDir()
+		if err != nil {
+			fmt.Printf("%s %v\n", style.ErrorPrefix, err)
+			refused++
+			continue
+		}
+		// Kill sessions running inside the sandbox so nothing lingers.
+		if sessions, err := t.FindSessionByWorkDir(dir, false); err == nil {
+			for _, s := range sessions {
+				_ = t.KillSession(s)
+				fmt.Printf("  Killed session %s\n", s)
+			}
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("removing sandbox %s: %w", sb.Name, err)
+		}
+		fmt.Printf("%s Destroyed sandbox %s\n", style.SuccessPrefix, sb.Name)
+	}
+	if refused > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runSandboxPath(cmd *cobra.Command, args []string) error {
+	if err := validateSandboxName(args[0]); err != nil {
+		return err
+	}
+	info, err := loadSandbox(sandboxPath(args[0]))
+	if err != nil {
+		return fmt.Errorf("sandbox %q not found", args[0])
+	}
+	fmt.Println(info.Path)
+	return nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+func TestRigNameFromURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://github.com/ctiospl/gastown", "gastown"},
+		{"https://github.com/user/my-repo.git", "my_repo"},
+		{"git@github.com:user/Some.Repo.git", "some_repo"},
+		{"https://example.com/repo/", "repo"},
+	}
+	for _, tt := range tests {
+		if got := rigNameFromURL(tt.url); got != tt.want {
+			t.Errorf("rigNameFromURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestValidateSandboxName(t *testing.T) {
+	for _, name := range []string{"", ".", "..", "a/b", "has space"} {
+		if err := validateSandboxName(name); err == nil {
+			t.Errorf("validateSandboxName(%q) should fail", name)
+		}
+	}
+	if err := validateSandboxName("try-prompts"); err != nil {
+		t.Errorf("validateSandboxName(try-prompts) = %v", err)
+	}
+}
+
+func TestListSandboxesSkipsUnmarkedDirs(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	marked := sandboxPath("marked")
+	if err := os.MkdirAll(marked, 0755); err != nil {
+		t.Fatal(err)
+	}
+	info := &SandboxInfo{Name: "marked", Path: marked, CreatedAt: time.Now()}
+	if err := util.AtomicWriteJSON(filepath.Join(marked, SandboxMarker), info); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sandboxPath("unmarked"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := listSandboxes()
+	if err != nil {
+		t.Fatalf("listSandboxes: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "marked" {
+		t.Errorf("listSandboxes() = %+v, want only the marked sandbox", got)
+	}
+}
+
+func TestSandboxDestroyRefusesForeignMarkerPath(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	outside := t.TempDir()
+
+	writeMarker := func(name, path string) {
+		t.Helper()
+		if err := os.MkdirAll(sandboxPath(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		info := &SandboxInfo{Name: name, Path: path, CreatedAt: time.Now()}
+		if err := util.AtomicWriteJSON(filepath.Join(sandboxPath(name), SandboxMarker), info); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeMarker("edited", outside)
+	writeMarker("good", sandboxPath("good"))
+
+	if err := runSandboxDestroy(nil, []string{"edited"}); err == nil {
+		t.Error("destroying a sandbox whose marker names another path succeeded")
+	}
+	for _, dir := range []string{outside, sandboxPath("edited")} {
+		if _, err := os.Stat(dir); err != nil {
+			t.Errorf("%s removed: %v", dir, err)
+		}
+	}
+
+	if err := runSandboxDestroy(nil, []string{"good"}); err != nil {
+		t.Fatalf("destroying a sound sandbox: %v", err)
+	}
+	if _, err := os.Stat(sandboxPath("good")); !os.IsNotExist(err) {
+		t.Errorf("sandbox not removed: %v", err)
+	}
+}
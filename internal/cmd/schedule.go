@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/daemon"
+	"github.com/ctiospl/gastown/internal/schedule"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var scheduleJSON bool
+
+var scheduleCmd = &cobra.Command{
+	Use:     "schedule",
+	GroupID: GroupServices,
+	Short:   "Schedule nudges and wakes on a cron schedule",
+	Long: `List, add, and remove the town's scheduled jobs: nudges and wakes run on
+cron schedules, like a town cron.
+
+Jobs are kept in settings/schedule.json and run by the daemon, which
+checks for due jobs every minute; the daemon must be running (gt daemon
+start) for them to run. Times are the machine's local time.
+
+Schedules are five-field cron expressions (minute hour day month weekday)
+or @hourly, @daily, @weekly, @monthly:
+
+  "0 9 * * 1-5"    09:00 on weekdays
+  "*/30 * * * *"   every half hour
+  "0 18 * * fri"   18:00 on Fridays
+
+Actions:
+  nudge <agent> <message>   nudge the agent, as gt nudge
+  wake <agent> [message]    start the agent's session if it is not
+                            running, then nudge it with the message
+
+Each run is logged as a "schedule" event (gt log --type schedule), with
+error severity when it failed. A run the daemon missed by more than 10
+minutes, as while it was down, is logged as skipped rather than run late.
+
+Examples:
+  gt schedule add "0 9 * * *" nudge gastown/crew/max "post standup"
+  gt schedule add "0 8 * * 1-5" wake gastown/witness
+  gt schedule                  # List jobs and their next runs
+  gt schedule run sched-1      # Run a job now
+  gt schedule remove sched-1`,
+	Args: cobra.NoArgs,
+	RunE: runScheduleList,
+}
+
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add <cron> <nudge|wake> <agent> [message]",
+	Short: "Add a scheduled job",
+	Args:  cobra.RangeArgs(3, 4),
+	RunE:  runScheduleAdd,
+}
+
+var scheduleRemoveCmd = &cobra.Command{
+	Use:     "remove <id>...",
+	Aliases: []string{"rm"},
+	Short:   "Remove scheduled jobs",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    runScheduleRemove,
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run <id>",
+	Short: "Run a scheduled job now",
+	Long: `Run a scheduled job now, outside its schedule. The run is logged like a
+scheduled one, and counts as the job's last run.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScheduleRun,
+}
+
+func init() {
+	scheduleCmd.Flags().BoolVar(&scheduleJSON, "json", false, "Output as JSON")
+
+	scheduleCmd.AddCommand(scheduleAddCmd)
+	scheduleCmd.AddCommand(scheduleRemoveCmd)
+	scheduleCmd.AddCommand(scheduleRunCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+// scheduledJob is a job as listed by gt schedule --json.
+type scheduledJob struct {
+	schedule.Job
+	LastRun *time.Time `json:"last_run,omitempty"`
+	NextRun *time.Time `json:"next_run,omitempty"`
+}
+
+func runScheduleList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	jobs, err := schedule.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	runs, err := schedule.LoadRuns(townRoot)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	list := make([]scheduledJob, 0, len(jobs))
+	for _, j := range jobs {
+		sj := scheduledJob{Job: j}
+		if last, ok := runs[j.ID]; ok {
+			sj.LastRun = &last
+		}
+		if next := j.Next(now); !next.IsZero() {
+			sj.NextRun = &next
+		}
+		list = append(list, sj)
+	}
+	if scheduleJSON {
+		return outputJSON(list)
+	}
+	if len(list) == 0 {
+		fmt.Printf("%s No scheduled jobs\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, sj := range list {
+		next := "never"
+		if sj.NextRun != nil {
+			next = sj.NextRun.Format("Mon 2006-01-02 15:04")
+		}
+		fmt.Printf("%s  %-14s %s\n", style.Bold.Render(sj.ID), sj.Cron, sj.Job)
+		line := "next " + next
+		if sj.LastRun != nil {
+			line += ", last ran " + formatDuration(now.Sub(*sj.LastRun).Round(time.Minute)) + " ago"
+		}
+		fmt.Printf("    %s\n", style.Dim.Render(line))
+	}
+	if running, _, _ := daemon.IsRunning(townRoot); !running {
+		fmt.Println()
+		style.PrintWarning("the daemon is not running, so jobs will not run (gt daemon start)")
+	}
+	return nil
+}
+
+func runScheduleAdd(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	j := schedule.Job{
+		Cron:      args[0],
+		Action:    strings.ToLower(args[1]),
+		Agent:     strings.TrimSuffix(args[2], "/"),
+		CreatedAt: time.Now(),
+		CreatedBy: detectSender(),
+	}
+	if len(args) == 4 {
+		j.Message = args[3]
+	}
+	j, err = schedule.Add(townRoot, j)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Scheduled %s: %s\n", style.Success.Render("✓"), style.Bold.Render(j.ID), j)
+	if next := j.Next(time.Now()); !next.IsZero() {
+		fmt.Printf("  Next run: %s\n", next.Format("Mon 2006-01-02 15:04"))
+	} else {
+		style.PrintWarning("%q never matches a date, so the job will never run", j.Cron)
+	}
+	if running, _, _ := daemon.IsRunning(townRoot); !running {
+		style.PrintWarning("the daemon is not running, so jobs will not run (gt daemon start)")
+	}
+	return nil
+}
+
+func runScheduleRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	for _, id := range args {
+		j, err := schedule.Remove(townRoot, id)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Removed %s: %s\n", style.Success.Render("✓"), j.ID, j)
+	}
+	return nil
+}
+
+func runScheduleRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	jobs, err := schedule.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	j, ok := schedule.Find(jobs, args[0])
+	if !ok {
+		return fmt.Errorf("no scheduled job %s", args[0])
+	}
+	cmd.SilenceUsage = true
+
+	now := time.Now()
+	runErr := schedule.Run(townRoot, tmux.NewTmux(), j)
+	schedule.Log(townRoot, j, runErr, now)
+	if err := schedule.MarkRun(townRoot, j, now); err != nil {
+		style.PrintWarning("could not record run: %v", err)
+	}
+	if runErr != nil {
+		return runErr
+	}
+	fmt.Printf("%s Ran %s: %s\n", style.Success.Render("✓"), j.ID, j)
+	return nil
+}
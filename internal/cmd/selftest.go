@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/gtlog"
+	"github.com/ctiospl/gastown/internal/mrqueue"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var selftestKeep bool
+
+var selftestCmd = &cobra.Command{
+	Use:     "selftest",
+	GroupID: GroupDiag,
+	Short:   "Run an end-to-end self-test in a temporary town",
+	Long: `Exercise the full gt stack against a fake runtime in a temp town.
+
+The self-test creates a throwaway town, then walks through the agent
+lifecycle using a fake runtime ("cat" in a tmux pane) instead of a real
+LLM session:
+
+  install   create a town with gt install
+  spawn     start a fake agent session and log the spawn
+  nudge     deliver a nudge and verify it reached the pane
+  crash     record a crash via the pane-died hook command
+  handoff   respawn the pane and log the handoff
+  queue     submit, order and claim merge requests
+  log       query the town log with filters
+
+Steps that need tmux are skipped (not failed) when tmux is unavailable.
+Exits non-zero if any step fails, so it can gate release qualification.
+
+Examples:
+  gt selftest            # Run all steps, clean up afterwards
+  gt selftest --keep     # Keep the temp town for inspection
+  gt selftest -v         # Show each step's details (and debug logs)`,
+	RunE: runSelftest,
+}
+
+func init() {
+	selftestCmd.Flags().BoolVar(&selftestKeep, "keep", false, "Keep the temporary town after the run")
+	rootCmd.AddCommand(selftestCmd)
+}
+
+// errSelftestSkip marks a step that could not run in this environment.
+var errSelftestSkip = errors.New("skipped")
+
+// selftestEnv is the shared state threaded through self-test steps.
+type selftestEnv struct {
+	townRoot string
+	gtPath   string
+	tmux     *tmux.Tmux
+	session  string
+	agent    string
+	hasTmux  bool
+}
+
+// selftestStep is one stage of the self-test.
+type selftestStep struct {
+	name string
+	run  func(env *selftestEnv) (string, error)
+}
+
+// selftestSteps returns the self-test stages in execution order.
+func selftestSteps() []selftestStep {
+	return []selftestStep{
+		{"install", selftestInstall},
+		{"spawn", selftestSpawn},
+		{"nudge", selftestNudge},
+		{"crash", selftestCrash},
+		{"handoff", selftestHandoff},
+		{"queue", selftestQueue},
+		{"log", selftestLogQueries},
+	}
+}
+
+func runSelftest(cmd *cobra.Command, args []string) error {
+	tmpDir, err := os.MkdirTemp("", "gt-selftest-")
+	if err != nil {
+		return fmt.Errorf("creating temp dir: %w", err)
+	}
+
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+
+	t := tmux.NewTmux()
+	env := &selftestEnv{
+		townRoot: filepath.Join(tmpDir, "town"),
+		gtPath:   gtPath,
+		tmux:     t,
+		session:  fmt.Sprintf("gt-selftest-%d", os.Getpid()),
+		agent:    "selftest/polecats/fake",
+		hasTmux:  t.IsAvailable(),
+	}
+
+	defer func() {
+		if env.hasTmux {
+			_ = t.KillSession(env.session) // best-effort: session may not exist
+		}
+		if selftestKeep {
+			fmt.Printf("\nTemp town kept at %s\n", env.townRoot)
+			return
+		}
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	fmt.Printf("%s Running gt selftest in %s\n\n", style.Bold.Render("🔬"), style.Dim.Render(env.townRoot))
+
+	var passed, failed, skipped int
+	for _, step := range selftestSteps() {
+		start := time.Now()
+		detail, err := step.run(env)
+		elapsed := time.Since(start).Round(time.Millisecond)
+
+		switch {
+		case errors.Is(err, errSelftestSkip):
+			skipped++
+			fmt.Printf("  %s %-8s %s\n", style.Dim.Render("○"), step.name, style.Dim.Render(err.Error()))
+		case err != nil:
+			failed++
+			fmt.Printf("  %s %-8s %v\n", style.ErrorPrefix, step.name, err)
+		default:
+			passed++
+			fmt.Printf("  %s %-8s %s\n", style.SuccessPrefix, step.name, style.Dim.Render(elapsed.String()))
+			if gtlog.Verbose() && detail != "" {
+				fmt.Printf("             %s\n", style.Dim.Render(detail))
+			}
+		}
+
+		// Nothing downstream can work without a town.
+		if step.name == "install" && err != nil {
+			break
+		}
+	}
+
+	fmt.Printf("\n%d passed, %d failed, %d skipped\n", passed, failed, skipped)
+	if failed > 0 {
+		return fmt.Errorf("%d selftest step(s) failed", failed)
+	}
+	return nil
+}
+
+func selftestInstall(env *selftestEnv) (string, error) {
+	if err := runSandboxStep(env.gtPath, "", "install", env.townRoot, "--name", "selftest", "--no-beads"); err != nil {
+		return "", err
+	}
+	if ok, _ := workspace.IsWorkspace(env.townRoot); !ok {
+		return "", fmt.Errorf("%s is not a workspace after install", env.townRoot)
+	}
+	return env.townRoot, nil
+}
+
+func selftestSpawn(env *selftestEnv) (string, error) {
+	if !env.hasTmux {
+		return "", fmt.Errorf("%w: tmux not available", errSelftestSkip)
+	}
+	if err := env.tmux.NewSession(env.session, env.townRoot); err != nil {
+		return "", fmt.Errorf("creating session: %w", err)
+	}
+	// Fake runtime: cat echoes whatever is injected into the pane.
+	if err := env.tmux.SendKeys(env.session, "cat"); err != nil {
+		return "", fmt.Errorf("starting fake runtime: %w", err)
+	}
+	if err := LogSpawn(env.townRoot, env.agent, "selftest-1"); err != nil {
+		return "", fmt.Errorf("logging spawn: %w", err)
+	}
+	return "session " + env.session, nil
+}
+
+func selftestNudge(env *selftestEnv) (string, error) {
+	if !env.hasTmux {
+		return "", fmt.Errorf("%w: tmux not available", errSelftestSkip)
+	}
+	const marker = "selftest-nudge-ping"
+	if err := env.tmux.NudgeSession(env.session, marker); err != nil {
+		return "", fmt.Errorf("nudging: %w", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		out, err := env.tmux.CapturePane(env.session, 50)
+		if err == nil && strings.Contains(out, marker) {
+			if err := LogNudge(env.townRoot, env.agent, marker); err != nil {
+				return "", fmt.Errorf("logging nudge: %w", err)
+			}
+			return "nudge visible in pane", nil
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return "", fmt.Errorf("nudge never appeared in pane")
+}
+
+func selftestCrash(env *selftestEnv) (string, error) {
+	if env.hasTmux {
+		// Interrupt the fake runtime, as a real crash would end it.
+		_ = env.tmux.SendKeysRaw(env.session, "C-c")
+	}
+	// Invoke the same command the pane-died hook runs.
+	if err := runSandboxStep(env.gtPath, env.townRoot, "log", "crash",
+		"--agent", env.agent, "--session", env.session, "--exit-code", "1"); err != nil {
+		return "", err
+	}
+
+	events, err := townlog.ReadEvents(env.townRoot)
+	if err != nil {
+		return "", fmt.Errorf("reading events: %w", err)
+	}
+	crashes := townlog.FilterEvents(events, townlog.Filter{Type: townlog.EventCrash, Agent: env.agent})
+	if len(crashes) != 1 {
+		return "", fmt.Errorf("expected 1 crash event, found %d", len(crashes))
+	}
+	return "crash recorded via gt log crash", nil
+}
+
+func selftestHandoff(env *selftestEnv) (string, error) {
+	if !env.hasTmux {
+		return "", fmt.Errorf("%w: tmux not available", errSelftestSkip)
+	}
+	pane, err := env.tmux.GetPaneID(env.session)
+	if err != nil {
+		return "", fmt.Errorf("getting pane: %w", err)
+	}
+	// Handoff respawns the pane with a fresh runtime.
+	if err := env.tmux.RespawnPane(pane, "cat"); err != nil {
+		return "", fmt.Errorf("respawning pane: %w", err)
+	}
+	if err := LogHandoff(env.townRoot, env.agent, "selftest handoff", nil, ""); err != nil {
+		return "", fmt.Errorf("logging handoff: %w", err)
+	}
+	return "pane " + pane + " respawned", nil
+}
+
+func selftestQueue(env *selftestEnv) (string, error) {
+	q := mrqueue.New(filepath.Join(env.townRoot, "selftest"))
+
+	low := &mrqueue.MR{Branch: "polecat/low", Target: "main", Worker: "low", Rig: "selftest", Priority: 3}
+	high := &mrqueue.MR{Branch: "polecat/high", Target: "main", Worker: "high", Rig: "selftest", Priority: 0}
+	for _, mr := range []*mrqueue.MR{low, high} {
+		if err := q.Submit(mr); err != nil {
+			return "", fmt.Errorf("submitting MR: %w", err)
+		}
+	}
+
+	ordered, err := q.ListByScore()
+	if err != nil {
+		return "", fmt.Errorf("listing MRs: %w", err)
+	}
+	if len(ordered) != 2 || ordered[0].ID != high.ID {
+		return "", fmt.Errorf("queue did not order high-priority MR first")
+	}
+
+	if err := q.Claim(high.ID, "selftest-worker"); err != nil {
+		return "", fmt.Errorf("claiming MR: %w", err)
+	}
+	unclaimed, err := q.ListUnclaimed()
+	if err != nil {
+		return "", fmt.Errorf("listing unclaimed: %w", err)
+	}
+	if len(unclaimed) != 1 || unclaimed[0].ID != low.ID {
+		return "", fmt.Errorf("claimed MR still dispatchable")
+	}
+	return "2 MRs ordered and claimed", nil
+}
+
+func selftestLogQueries(env *selftestEnv) (string, error) {
+	// Work without tmux too: make sure there is something to query.
+	if !env.hasTmux {
+		if err := LogSpawn(env.townRoot, env.agent, "selftest-1"); err != nil {
+			return "", fmt.Errorf("logging spawn: %w", err)
+		}
+	}
+	if err := LogDone(env.townRoot, env.agent, "selftest-1", nil, ""); err != nil {
+		return "", fmt.Errorf("logging done: %w", err)
+	}
+
+	events, err := townlog.ReadEvents(env.townRoot)
+	if err != nil {
+		return "", fmt.Errorf("reading events: %w", err)
+	}
+	if n := len(townlog.FilterEvents(events, townlog.Filter{Type: townlog.EventSpawn})); n != 1 {
+		return "", fmt.Errorf("type filter: expected 1 spawn, got %d", n)
+	}
+	if n := len(townlog.FilterEvents(events, townlog.Filter{Agent: "selftest/"})); n != len(events) {
+		return "", fmt.Errorf("agent filter: expected %d events, got %d", len(events), n)
+	}
+	if n := len(townlog.FilterEvents(events, townlog.Filter{Since: time.Now().Add(time.Hour)})); n != 0 {
+		return "", fmt.Errorf("since filter: expected 0 future events, got %d", n)
+	}
+	tail, err := townlog.TailEvents(env.townRoot, 1)
+	if err != nil || len(tail) != 1 || tail[0].Type != townlog.EventDone {
+		return "", fmt.Errorf("tail: expected last event to be done")
+	}
+	return fmt.Sprintf("%d events queried", len(events)), nil
+}
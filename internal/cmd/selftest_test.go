@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelftestQueueAndLogSteps(t *testing.T) {
+	env := &selftestEnv{
+		townRoot: t.TempDir(),
+		agent:    "selftest/polecats/fake",
+	}
+
+	if _, err := selftestQueue(env); err != nil {
+		t.Fatalf("queue step: %v", err)
+	}
+	if _, err := selftestLogQueries(env); err != nil {
+		t.Fatalf("log step: %v", err)
+	}
+}
+
+func TestSelftestTmuxStepsSkipWithoutTmux(t *testing.T) {
+	env := &selftestEnv{townRoot: t.TempDir()}
+
+	for _, step := range []func(*selftestEnv) (string, error){selftestSpawn, selftestNudge, selftestHandoff} {
+		_, err := step(env)
+		if !errors.Is(err, errSelftestSkip) {
+			t.Errorf("expected skip without tmux, got %v", err)
+		}
+	}
+}
+
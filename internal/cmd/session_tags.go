@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/events"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	sessionTagAgent string
+	sessionTagIssue string
+	sessionTagNote  string
+
+	sessionTagsBy    string
+	sessionTagsSince string
+	sessionTagsList  bool
+	sessionTagsJSON  bool
+)
+
+var sessionTagCmd = &cobra.Command{
+	Use:   "tag <tag>",
+	Short: "Attach an outcome tag to an agent session",
+	Long: `Attach an outcome tag to the session that did a piece of work.
+
+The test gate and review steps call this so reports can measure the
+downstream quality of agent work, not just completion. Well-known tags:
+
+  tests-green       tests passed at the merge gate
+  tests-red         tests failed at the merge gate
+  review-approved   review passed
+  review-rejected   review requested changes
+  reverted-later    the merged work was later reverted
+
+Any lowercase, dash-separated tag is accepted. With --issue alone, the tag
+goes to the agent that completed the issue, on the session it was working
+in at the time.
+
+Examples:
+  gt session tag tests-green --issue gt-abc
+  gt session tag review-approved --agent gastown/polecats/nux --issue gt-abc
+  gt session tag reverted-later --issue gt-abc --note "broke nightly build"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionTag,
+}
+
+var sessionTagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Report outcome tags across sessions",
+	Long: `Report sessions, completed work and outcome tags per agent.
+
+Each tag column shows how many sessions carry the tag and, in parentheses,
+its share of completed work.
+
+Examples:
+  gt session tags                  # Per agent
+  gt session tags --by rig         # Per rig (or --by role)
+  gt session tags --since 7d
+  gt session tags --list           # Individual tags
+  gt session tags --json`,
+	Args: cobra.NoArgs,
+	RunE: runSessionTags,
+}
+
+func init() {
+	sessionTagCmd.Flags().StringVar(&sessionTagAgent, "agent", "", "Agent whose session to tag (e.g., gastown/polecats/nux)")
+	sessionTagCmd.Flags().StringVar(&sessionTagIssue, "issue", "", "Issue the outcome is about")
+	sessionTagCmd.Flags().StringVar(&sessionTagNote, "note", "", "Optional note stored with the tag")
+
+	sessionTagsCmd.Flags().StringVar(&sessionTagsBy, "by", "agent", "Group by agent, rig, or role")
+	sessionTagsCmd.Flags().StringVar(&sessionTagsSince, "since", "", "Only count activity since duration (e.g., 24h, 7d), time, or mark")
+	sessionTagsCmd.Flags().BoolVar(&sessionTagsList, "list", false, "List individual tags instead of the summary")
+	sessionTagsCmd.Flags().BoolVar(&sessionTagsJSON, "json", false, "Output as JSON")
+
+	sessionCmd.AddCommand(sessionTagCmd)
+	sessionCmd.AddCommand(sessionTagsCmd)
+}
+
+func runSessionTag(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if sessionTagAgent == "" && sessionTagIssue == "" {
+		return fmt.Errorf("specify --issue, --agent, or both")
+	}
+
+	t, err := session.TagSession(townRoot, detectActor(), sessionTagAgent, sessionTagIssue, args[0], sessionTagNote)
+	if err != nil {
+		return err
+	}
+
+	target := t.Agent
+	if t.SessionID != "" {
+		target += " session " + t.SessionID
+	}
+	fmt.Printf("%s Tagged %s %s\n", style.Success.Render("✓"), target, style.Bold.Render(t.Tag))
+	if t.SessionID == "" {
+		fmt.Printf("  %s\n", style.Dim.Render("No session_start event found for this agent; tagged by agent only"))
+	}
+	return nil
+}
+
+func runSessionTags(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	group, err := sessionTagGrouping(sessionTagsBy)
+	if err != nil {
+		return err
+	}
+	var since time.Time
+	if sessionTagsSince != "" {
+		if since, err = resolveTimeBoundary(townRoot, "since", sessionTagsSince); err != nil {
+			return err
+		}
+	}
+
+	all, err := events.Read(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if sessionTagsList {
+		var tags []session.Tag
+		for _, t := range session.Tags(all) {
+			if since.IsZero() || !t.Time.Before(since) {
+				tags = append(tags, t)
+			}
+		}
+		if sessionTagsJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(tags)
+		}
+		if len(tags) == 0 {
+			fmt.Printf("%s No session tags recorded\n", style.Dim.Render("○"))
+			return nil
+		}
+		for _, t := range tags {
+			line := fmt.Sprintf("%s  %-16s %s", t.Time.Local().Format("2006-01-02 15:04"), t.Tag, t.Agent)
+			if t.Issue != "" {
+				line += " " + t.Issue
+			}
+			if t.Note != "" {
+				line += " " + style.Dim.Render("— "+t.Note)
+			}
+			fmt.Println(line)
+		}
+		return nil
+	}
+
+	summaries := session.Summarize(all, since, group)
+	if sessionTagsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summaries)
+	}
+	if len(summaries) == 0 {
+		fmt.Printf("%s No session activity recorded\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	header := fmt.Sprintf("%-28s %8s %6s", strings.ToUpper(sessionTagsBy), "SESSIONS", "DONE")
+	for _, tag := range session.OutcomeTags {
+		header += fmt.Sprintf(" %16s", strings.ToUpper(tag))
+	}
+	fmt.Println(style.Bold.Render(header))
+	for _, s := range summaries {
+		line := fmt.Sprintf("%-28s %8d %6d", s.Group, s.Sessions, s.Done)
+		for _, tag := range session.OutcomeTags {
+			line += fmt.Sprintf(" %16s", formatTagCell(s, tag))
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// formatTagCell renders a tag count with its share of completed work.
+func formatTagCell(s session.TagSummary, tag string) string {
+	n := s.Tags[tag]
+	if rate := s.Rate(tag); rate >= 0 && n > 0 {
+		return fmt.Sprintf("%d (%.0f%%)", n, rate*100)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// sessionTagGrouping maps an agent address to its report group.
+func sessionTagGrouping(by string) (func(string) string, error) {
+	switch by {
+	case "agent":
+		return func(agent string) string { return agent }, nil
+	case "rig":
+		return func(agent string) string {
+			if rig, _, ok := strings.Cut(agent, "/"); ok {
+				return rig
+			}
+			return "(town)"
+		}, nil
+	case "role":
+		return agentRoleName, nil
+	default:
+		return nil, fmt.Errorf("invalid --by %q: use agent, rig, or role", by)
+	}
+}
+
+// agentRoleName returns the role part of an agent address:
+// "gastown/polecats/nux" → "polecat", "gastown/witness" → "witness".
+func agentRoleName(agent string) string {
+	parts := strings.Split(agent, "/")
+	switch {
+	case len(parts) >= 3 && parts[1] == "polecats":
+		return "polecat"
+	case len(parts) >= 3:
+		return parts[1]
+	case len(parts) == 2:
+		return parts[1]
+	default:
+		return agent
+	}
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/share"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/web"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+// Shared views show this much of the session and this many recent events.
+const (
+	sharePaneLines  = 200
+	shareEventCount = 20
+)
+
+var (
+	shareTTL     time.Duration
+	shareBaseURL string
+	shareList    bool
+	shareRevoke  string
+)
+
+var shareCmd = &cobra.Command{
+	Use:     "share <agent>",
+	GroupID: GroupAgents,
+	Short:   "Create a read-only link to watch an agent's session",
+	Long: `Create a time-limited, read-only link to a live view of an agent's
+session: its terminal output, whether it is running, and its recent town
+log events. A teammate can open the link in a browser to watch the agent
+work, without SSH access to this machine.
+
+Links are served by 'gt log serve', which must be running and reachable
+by the teammate (e.g. 'gt log serve --addr 0.0.0.0:7777'). Anyone with the
+link can watch until it expires or is revoked, so share it privately.
+
+Examples:
+  gt share gastown/nux                   # Link valid for 1 hour
+  gt share gastown/crew/max --ttl 30m
+  gt share gastown/nux --base-url https://gt.example.com
+  gt share --list                        # Active links
+  gt share --revoke gastown/nux          # Revoke all links to an agent
+  gt share --revoke 3f9a2c               # Revoke one link by token prefix`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runShare,
+}
+
+func init() {
+	shareCmd.Flags().DurationVar(&shareTTL, "ttl", time.Hour, "How long the link stays valid")
+	shareCmd.Flags().StringVar(&shareBaseURL, "base-url", "", "URL where 'gt log serve' is reachable (default: http://<hostname>:7777)")
+	shareCmd.Flags().BoolVar(&shareList, "list", false, "List active links")
+	shareCmd.Flags().StringVar(&shareRevoke, "revoke", "", "Revoke links by token prefix or agent")
+
+	rootCmd.AddCommand(shareCmd)
+}
+
+func runShare(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	switch {
+	case shareList:
+		return listShares(townRoot)
+	case shareRevoke != "":
+		n, err := share.Revoke(townRoot, shareAgentAddress(shareRevoke))
+		if err != nil {
+			return fmt.Errorf("revoking: %w", err)
+		}
+		if n == 0 {
+			return fmt.Errorf("no active link matches %q", shareRevoke)
+		}
+		fmt.Printf("%s Revoked %d link(s)\n", style.Success.Render("✓"), n)
+		return nil
+	case len(args) == 0:
+		return fmt.Errorf("agent required (or use --list / --revoke)")
+	}
+
+	agent := shareAgentAddress(args[0])
+	sessionName := shareSessionName(agent)
+	if sessionName == "" {
+		return fmt.Errorf("cannot share %q: not an agent address (e.g. gastown/nux, gastown/crew/max, mayor)", args[0])
+	}
+	sh, err := share.Create(townRoot, agent, detectActor(), shareTTL)
+	if err != nil {
+		return fmt.Errorf("creating link: %w", err)
+	}
+
+	base, err := shareBase(shareBaseURL)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Read-only link to %s (expires %s):\n\n", style.Success.Render("✓"), style.Bold.Render(agent),
+		sh.ExpiresAt.Local().Format("2006-01-02 15:04"))
+	fmt.Printf("  %s/share/%s\n\n", base, sh.Token)
+	if running, _ := tmux.NewTmux().HasSession(sessionName); !running {
+		style.PrintWarning("%s has no running session; the view shows it as stopped until it starts", agent)
+	}
+	fmt.Printf("%s\n", style.Dim.Render("Served by 'gt log serve'. Revoke with: gt share --revoke "+sh.Token[:8]))
+	return nil
+}
+
+func listShares(townRoot string) error {
+	shares, err := share.List(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(shares) == 0 {
+		fmt.Printf("%s No active links\n", style.Dim.Render("○"))
+		return nil
+	}
+	fmt.Printf("  %-10s %-32s %-16s %s\n", "TOKEN", "AGENT", "EXPIRES", "BY")
+	for _, sh := range shares {
+		fmt.Printf("  %-10s %-32s %-16s %s\n", sh.Token[:8], truncateStr(sh.Agent, 32),
+			sh.ExpiresAt.Local().Format("2006-01-02 15:04"), sh.CreatedBy)
+	}
+	return nil
+}
+
+// shareBase returns the URL links are built on, without a trailing slash.
+func shareBase(flag string) (string, error) {
+	if flag == "" {
+		host, err := os.Hostname()
+		if err != nil || host == "" {
+			host = "localhost"
+		}
+		return "http://" + host + ":7777", nil
+	}
+	u, err := url.Parse(flag)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid --base-url %q: want http(s)://host[:port]", flag)
+	}
+	return strings.TrimSuffix(flag, "/"), nil
+}
+
+// shareAgentAddress canonicalizes an agent address, spelling out polecats
+// ("gastown/nux" becomes "gastown/polecats/nux"). Non-addresses, such as
+// token prefixes, are returned unchanged.
+func shareAgentAddress(agent string) string {
+	parts := strings.Split(strings.Trim(agent, "/"), "/")
+	if len(parts) == 2 && parts[1] != "witness" && parts[1] != "refinery" {
+		return parts[0] + "/polecats/" + parts[1]
+	}
+	return strings.Join(parts, "/")
+}
+
+// shareSessionName returns the tmux session of a canonical agent address,
+// or "" if it is not one.
+func shareSessionName(agent string) string {
+	id, err := session.ParseAddress(agent)
+	if err != nil {
+		return ""
+	}
+	return id.SessionName()
+}
+
+// shareAgentPattern matches a shared agent's town log events. Polecats
+// are logged both as rig/polecats/name and as rig/name.
+func shareAgentPattern(agent string) *regexp.Regexp {
+	parts := strings.Split(agent, "/")
+	if len(parts) == 3 && parts[1] == "polecats" {
+		return regexp.MustCompile("^" + regexp.QuoteMeta(parts[0]) + "/(polecats/)?" + regexp.QuoteMeta(parts[2]) + "$")
+	}
+	return regexp.MustCompile("^" + regexp.QuoteMeta(agent) + "$")
+}
+
+// shareStatus is the JSON polled by a shared view.
+type shareStatus struct {
+	Agent     string         `json:"agent"`
+	Running   bool           `json:"running"`
+	Pane      string         `json:"pane,omitempty"`
+	Events    []logEventJSON `json:"events"`
+	ExpiresAt time.Time      `json:"expires_at"`
+}
+
+// shareHandler serves shared views under /share/<token>:
+//
+//	/share/<token>          HTML page that polls the status
+//	/share/<token>/status   JSON status (shareStatus)
+//	/share/<token>/events   SSE stream of the agent's events
+//
+// Every request re-checks the token, so a revoked link stops working on
+// the page's next poll; event streams end when the link expires. Nothing
+// can be sent to the agent.
+type shareHandler struct {
+	townRoot string
+	stream   *logStreamHandler
+
+	// capture reports whether a session is running and its recent output.
+	capture func(sessionName string) (running bool, pane string)
+}
+
+func newShareHandler(townRoot string, stream *logStreamHandler) *shareHandler {
+	t := tmux.NewTmux()
+	return &shareHandler{
+		townRoot: townRoot,
+		stream:   stream,
+		capture: func(sessionName string) (bool, string) {
+			if running, _ := t.HasSession(sessionName); !running {
+				return false, ""
+			}
+			pane, _ := t.CapturePane(sessionName, sharePaneLines)
+			return true, pane
+		},
+	}
+}
+
+func (h *shareHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token, view, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/share/"), "/")
+	sh, err := share.Lookup(h.townRoot, token)
+	switch {
+	case errors.Is(err, share.ErrExpired):
+		http.Error(w, "this link has expired", http.StatusGone)
+		return
+	case err != nil:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("X-Robots-Tag", "noindex")
+
+	switch view {
+	case "":
+		h.servePage(w, sh)
+	case "status":
+		h.serveStatus(w, sh)
+	case "events":
+		// Stream only this agent's events, and only until the link expires.
+		ctx, cancel := context.WithDeadline(r.Context(), sh.ExpiresAt)
+		defer cancel()
+		q := url.Values{"agent_re": {shareAgentPattern(sh.Agent).String()}, "backlog": {fmt.Sprint(shareEventCount)}}
+		streamReq := r.WithContext(ctx)
+		streamReq.URL = &url.URL{Path: r.URL.Path, RawQuery: q.Encode()}
+		h.stream.ServeHTTP(w, streamReq)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *shareHandler) servePage(w http.ResponseWriter, sh *share.Share) {
+	tmpl, err := web.LoadTemplates()
+	if err != nil {
+		http.Error(w, "loading template", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = tmpl.ExecuteTemplate(w, "share.html", web.ShareData{
+		Agent:     sh.Agent,
+		ExpiresAt: sh.ExpiresAt,
+		StatusURL: "/share/" + url.PathEscape(sh.Token) + "/status",
+	})
+}
+
+func (h *shareHandler) serveStatus(w http.ResponseWriter, sh *share.Share) {
+	status := shareStatus{Agent: sh.Agent, ExpiresAt: sh.ExpiresAt, Events: []logEventJSON{}}
+	status.Running, status.Pane = h.capture(shareSessionName(sh.Agent))
+
+	events, err := townlog.QueryEvents(h.townRoot, townlog.Filter{AgentPattern: shareAgentPattern(sh.Agent)})
+	if err == nil {
+		if len(events) > shareEventCount {
+			events = events[len(events)-shareEventCount:]
+		}
+		for _, e := range events {
+			status.Events = append(status.Events, newLogEventJSON("", e))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
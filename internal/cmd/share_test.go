@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/share"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+func TestShareAddresses(t *testing.T) {
+	tests := []struct {
+		in, agent, session string
+	}{
+		{"gastown/nux", "gastown/polecats/nux", "gt-gastown-nux"},
+		{"gastown/polecats/nux", "gastown/polecats/nux", "gt-gastown-nux"},
+		{"gastown/crew/max", "gastown/crew/max", "gt-gastown-crew-max"},
+		{"gastown/witness", "gastown/witness", "gt-gastown-witness"},
+		{"mayor", "mayor", "gt-mayor"},
+		{"3f9a2c", "3f9a2c", ""},
+	}
+	for _, tt := range tests {
+		agent := shareAgentAddress(tt.in)
+		if agent != tt.agent || shareSessionName(agent) != tt.session {
+			t.Errorf("%q -> %q (session %q), want %q (session %q)", tt.in, agent, shareSessionName(agent), tt.agent, tt.session)
+		}
+	}
+
+	re := shareAgentPattern("gastown/polecats/nux")
+	for agent, want := range map[string]bool{
+		"gastown/polecats/nux": true, "gastown/nux": true, "gastown/polecats/nuxx": false, "other/nux": false,
+	} {
+		if re.MatchString(agent) != want {
+			t.Errorf("pattern matches %q = %v, want %v", agent, !want, want)
+		}
+	}
+}
+
+func TestShareHandler(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := townlog.NewLogger(townRoot).Log(townlog.EventSpawn, "gastown/polecats/nux", "gt-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := townlog.NewLogger(townRoot).Log(townlog.EventSpawn, "gastown/polecats/toast", "gt-2"); err != nil {
+		t.Fatal(err)
+	}
+	sh, err := share.Create(townRoot, "gastown/polecats/nux", "overseer", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &shareHandler{
+		townRoot: townRoot,
+		stream:   &logStreamHandler{townRoot: townRoot},
+		capture: func(sessionName string) (bool, string) {
+			return sessionName == "gt-gastown-nux", "working on gt-1"
+		},
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	if rec := get("/share/" + sh.Token); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "gastown/polecats/nux") {
+		t.Errorf("page = %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec := get("/share/" + sh.Token + "/status")
+	var status shareStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("status is not JSON: %v\n%s", err, rec.Body.String())
+	}
+	if !status.Running || status.Pane != "working on gt-1" || len(status.Events) != 1 || status.Events[0].Context != "gt-1" {
+		t.Errorf("status = %+v", status)
+	}
+
+	if rec := get("/share/not-a-token/status"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown token = %d, want 404", rec.Code)
+	}
+	post := httptest.NewRecorder()
+	h.ServeHTTP(post, httptest.NewRequest(http.MethodPost, "/share/"+sh.Token+"/status", nil))
+	if post.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST = %d, want 405", post.Code)
+	}
+
+	if _, err := share.Revoke(townRoot, sh.Agent); err != nil {
+		t.Fatal(err)
+	}
+	if rec := get("/share/" + sh.Token + "/status"); rec.Code != http.StatusNotFound {
+		t.Errorf("revoked token = %d, want 404", rec.Code)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/base32"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -15,6 +16,7 @@ import (
 	"github.com/ctiospl/gastown/internal/config"
 	"github.com/ctiospl/gastown/internal/dog"
 	"github.com/ctiospl/gastown/internal/events"
+	"github.com/ctiospl/gastown/internal/polecat"
 	"github.com/ctiospl/gastown/internal/session"
 	"github.com/ctiospl/gastown/internal/style"
 	"github.com/ctiospl/gastown/internal/tmux"
@@ -43,6 +45,13 @@ Auto-Convoy:
   gt sling gt-abc gastown              # Creates "Work: <issue-title>" convoy
   gt sling gt-abc gastown --no-convoy  # Skip auto-convoy creation
 
+Spawn Preflight:
+  Before spawning a polecat, sling checks that the runtime is installed and
+  has credentials, policy allows the spawn (no used-up gt budget that
+  pauses or kills the polecat, max_polecats), the disk has space, a branch
+  can be created, and a reused worktree has no uncommitted work. All
+  failures are reported together and nothing is created.
+
 Target Resolution:
   gt sling gt-abc                       # Self (current agent)
   gt sling gt-abc crew                  # Crew worker in current rig
@@ -58,6 +67,7 @@ Spawning Options (when target is a rig):
   gt sling gp-abc greenplace --naked                # No-tmux (manual start)
   gt sling gp-abc greenplace --force                # Ignore unread mail
   gt sling gp-abc greenplace --account work         # Use specific Claude account
+  gt sling gp-abc greenplace --stack-on Toast       # Build on Toast's unmerged branch
 
 Natural Language Args:
   gt sling gt-abc --args "patch release"
@@ -90,7 +100,11 @@ Batch Slinging:
   gt sling gt-abc gt-def gt-ghi gastown   # Sling multiple beads to a rig
 
   When multiple beads are provided with a rig target, each bead gets its own
-  polecat. This parallelizes work dispatch without running gt sling N times.`,
+  polecat. This parallelizes work dispatch without running gt sling N times.
+
+  The batch is all-or-nothing: if any bead fails, the polecats spawned for
+  the others are removed and their beads unhooked, and a per-bead summary
+  is printed. Use --keep-partial to keep the beads that succeeded.`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runSling,
 }
@@ -111,6 +125,10 @@ var (
 	slingAccount  string // --account: Claude Code account handle to use
 	slingQuality  string // --quality: shorthand for polecat workflow (basic|shiny|chrome)
 	slingNoConvoy bool   // --no-convoy: skip auto-convoy creation
+	slingStackOn  string // --stack-on: start the new polecat's branch on another unmerged branch
+
+	// Batch sling
+	slingKeepPartial bool // --keep-partial: don't roll back a partially failed batch
 )
 
 func init() {
@@ -126,9 +144,11 @@ func init() {
 	slingCmd.Flags().BoolVar(&slingCreate, "create", false, "Create polecat if it doesn't exist")
 	slingCmd.Flags().StringVar(&slingMolecule, "molecule", "", "Molecule workflow to instantiate on the bead")
 	slingCmd.Flags().BoolVar(&slingForce, "force", false, "Force spawn even if polecat has unread mail")
+	slingCmd.Flags().BoolVar(&slingKeepPartial, "keep-partial", false, "Batch sling: keep successful slings if another bead fails")
 	slingCmd.Flags().StringVar(&slingAccount, "account", "", "Claude Code account handle to use")
 	slingCmd.Flags().StringVarP(&slingQuality, "quality", "q", "", "Polecat workflow quality level (basic|shiny|chrome)")
 	slingCmd.Flags().BoolVar(&slingNoConvoy, "no-convoy", false, "Skip auto-convoy creation for single-issue sling")
+	slingCmd.Flags().StringVar(&slingStackOn, "stack-on", "", "Start the new polecat's branch on a polecat's (or branch's) unmerged work (see 'gt stack')")
 
 	rootCmd.AddCommand(slingCmd)
 }
@@ -267,6 +287,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 					Account:  slingAccount,
 					Create:   slingCreate,
 					HookBead: beadID, // Set atomically at spawn time
+					StackOn:  slingStackOn,
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -464,6 +485,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Slung work wakes an agent put to sleep for being idle
+	if targetPane != "" {
+		wakeSlingTarget(townRoot, targetAgent, beadID)
+	}
+
 	// Try to inject the "start now" prompt (graceful if no tmux)
 	if targetPane == "" {
 		fmt.Printf("%s No pane to nudge (agent will discover work via gt prime)\n", style.Dim.Render("○"))
@@ -520,6 +546,16 @@ func storeArgsInBead(beadID, args string) error {
 	return nil
 }
 
+// wakeSlingTarget wakes the target agent if it was put to sleep for being
+// idle, before it is told about the work slung to it.
+func wakeSlingTarget(townRoot, agent, work string) {
+	id, err := session.ParseAddress(agent)
+	if err != nil {
+		return
+	}
+	wakeIdle(tmux.NewTmux(), townRoot, id.SessionName(), "work slung: "+work)
+}
+
 // injectStartPrompt sends a prompt to the target pane to start working.
 // Uses the reliable nudge pattern: literal mode + 500ms debounce + separate Enter.
 func injectStartPrompt(pane, beadID, subject, args string) error {
@@ -876,6 +912,7 @@ func runSlingFormula(args []string) error {
 		fmt.Printf("%s No pane to nudge (agent will discover work via gt prime)\n", style.Dim.Render("○"))
 		return nil
 	}
+	wakeSlingTarget(townRoot, targetAgent, formulaName)
 
 	var prompt string
 	if slingArgs != "" {
@@ -955,9 +992,15 @@ func wakeRigAgents(rigName string) {
 	witnessSession := fmt.Sprintf("gt-%s-witness", rigName)
 	refinerySession := fmt.Sprintf("gt-%s-refinery", rigName)
 
-	// Silent nudges - sessions might not exist yet
-	_ = t.NudgeSession(witnessSession, "Polecat dispatched - check for work")
-	_ = t.NudgeSession(refinerySession, "Polecat dispatched - check for merge requests")
+	// Silent nudges - sessions might not exist yet. Agents in a scheduled
+	// DND window are left alone; they pick the work up when it ends.
+	townRoot, _ := workspace.FindFromCwd()
+	if !sessionInDND(townRoot, witnessSession) {
+		_ = t.NudgeSession(witnessSession, "Polecat dispatched - check for work")
+	}
+	if !sessionInDND(townRoot, refinerySession) {
+		_ = t.NudgeSession(refinerySession, "Polecat dispatched - check for merge requests")
+	}
 }
 
 // detectActor returns the current agent's actor string for event logging.
@@ -1254,7 +1297,9 @@ func createAutoConvoy(beadID, beadTitle string) (string, error) {
 }
 
 // runBatchSling handles slinging multiple beads to a rig.
-// Each bead gets its own freshly spawned polecat.
+// Each bead gets its own freshly spawned polecat. The batch is
+// all-or-nothing: if any bead fails, the polecats spawned for the others
+// are removed and their beads unhooked, unless --keep-partial is set.
 func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error {
 	// Validate all beads exist before spawning any polecats
 	for _, beadID := range beadIDs {
@@ -1276,14 +1321,9 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 	fmt.Printf("%s Batch slinging %d beads to rig '%s'...\n", style.Bold.Render("🎯"), len(beadIDs), rigName)
 
-	// Track results for summary
-	type slingResult struct {
-		beadID   string
-		polecat  string
-		success  bool
-		errMsg   string
-	}
-	results := make([]slingResult, 0, len(beadIDs))
+	// Each spawned polecat is recorded with an undo, so a failure part-way
+	// through can tear the batch down instead of leaving it half-slung.
+	b := newBatch("Batch sling")
 
 	// Spawn a polecat for each bead and sling it
 	for i, beadID := range beadIDs {
@@ -1292,13 +1332,13 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		// Check bead status
 		info, err := getBeadInfo(beadID)
 		if err != nil {
-			results = append(results, slingResult{beadID: beadID, success: false, errMsg: err.Error()})
+			b.fail(beadID, fmt.Errorf("could not get bead info: %w", err))
 			fmt.Printf("  %s Could not get bead info: %v\n", style.Dim.Render("✗"), err)
 			continue
 		}
 
 		if info.Status == "pinned" && !slingForce {
-			results = append(results, slingResult{beadID: beadID, success: false, errMsg: "already pinned"})
+			b.skip(beadID, "already pinned (use --force to re-sling)")
 			fmt.Printf("  %s Already pinned (use --force to re-sling)\n", style.Dim.Render("✗"))
 			continue
 		}
@@ -1310,10 +1350,11 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			Account:  slingAccount,
 			Create:   slingCreate,
 			HookBead: beadID, // Set atomically at spawn time
+			StackOn:  slingStackOn,
 		}
 		spawnInfo, err := SpawnPolecatForSling(rigName, spawnOpts)
 		if err != nil {
-			results = append(results, slingResult{beadID: beadID, success: false, errMsg: err.Error()})
+			b.fail(beadID, fmt.Errorf("spawning polecat: %w", err))
 			fmt.Printf("  %s Failed to spawn polecat: %v\n", style.Dim.Render("✗"), err)
 			continue
 		}
@@ -1344,7 +1385,11 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		}
 		hookCmd.Stderr = os.Stderr
 		if err := hookCmd.Run(); err != nil {
-			results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: false, errMsg: "hook failed"})
+			// Don't leave a polecat running with nothing on its hook
+			if cleanupErr := discardSpawnedPolecat(spawnInfo); cleanupErr != nil {
+				fmt.Printf("  %s Could not remove %s: %v\n", style.Dim.Render("Warning:"), spawnInfo.PolecatName, cleanupErr)
+			}
+			b.fail(beadID, fmt.Errorf("hooking bead to %s: %w", spawnInfo.PolecatName, err))
 			fmt.Printf("  %s Failed to hook bead: %v\n", style.Dim.Render("✗"), err)
 			continue
 		}
@@ -1374,28 +1419,51 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			}
 		}
 
-		results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: true})
+		spawned := spawnInfo
+		b.succeed(beadID, "→ "+spawned.PolecatName, func() error {
+			return unslingBatchBead(beadID, spawned, townBeadsDir)
+		})
+	}
+
+	if !slingKeepPartial {
+		b.rollback()
 	}
 
 	// Wake witness and refinery once at the end
-	wakeRigAgents(rigName)
+	if b.count(batchOK) > 0 {
+		wakeRigAgents(rigName)
+	}
 
-	// Print summary
-	successCount := 0
-	for _, r := range results {
-		if r.success {
-			successCount++
-		}
+	b.report()
+	return b.err()
+}
+
+// unslingBatchBead reverses one successful batch sling: the bead goes back
+// to open and the polecat spawned for it is removed.
+func unslingBatchBead(beadID string, info *SpawnedPolecatInfo, townBeadsDir string) error {
+	unhookCmd := exec.Command("bd", "update", beadID, "--status=open", "--assignee=")
+	unhookCmd.Env = append(os.Environ(), "BEADS_DIR="+townBeadsDir)
+	if err := unhookCmd.Run(); err != nil {
+		return fmt.Errorf("unhooking %s: %w", beadID, err)
 	}
+	return discardSpawnedPolecat(info)
+}
 
-	fmt.Printf("\n%s Batch sling complete: %d/%d succeeded\n", style.Bold.Render("📊"), successCount, len(beadIDs))
-	if successCount < len(beadIDs) {
-		for _, r := range results {
-			if !r.success {
-				fmt.Printf("  %s %s: %s\n", style.Dim.Render("✗"), r.beadID, r.errMsg)
-			}
+// discardSpawnedPolecat kills a just-spawned polecat's session and removes
+// its worktree. The polecat has done no work yet, so nothing is lost.
+func discardSpawnedPolecat(info *SpawnedPolecatInfo) error {
+	mgr, r, err := getPolecatManager(info.RigName)
+	if err != nil {
+		return err
+	}
+	sessMgr := session.NewManager(tmux.NewTmux(), r)
+	if running, _ := sessMgr.IsRunning(info.PolecatName); running {
+		if err := sessMgr.Stop(info.PolecatName, true); err != nil {
+			return fmt.Errorf("stopping session: %w", err)
 		}
 	}
-
+	if err := mgr.RemoveWithOptions(info.PolecatName, true, true); err != nil && !errors.Is(err, polecat.ErrPolecatNotFound) {
+		return fmt.Errorf("removing worktree: %w", err)
+	}
 	return nil
 }
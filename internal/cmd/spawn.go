@@ -0,0 +1,932 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/crew"
+	"github.com/ctiospl/gastown/internal/daemon"
+	"github.com/ctiospl/gastown/internal/labels"
+	"github.com/ctiospl/gastown/internal/lifecycle"
+	"github.com/ctiospl/gastown/internal/pending"
+	"github.com/ctiospl/gastown/internal/preempt"
+	"github.com/ctiospl/gastown/internal/remote"
+	"github.com/ctiospl/gastown/internal/runlimit"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	spawnTemplate    string
+	spawnRole        string
+	spawnAccount     string
+	spawnList        bool
+	spawnBatch       string
+	spawnCount       int
+	spawnParallel    int
+	spawnKeepPartial bool
+	spawnAfter       []string
+	spawnPending     bool
+	spawnCancel      string
+	spawnPriority    string
+	spawnLabels      []string
+	spawnRuntime     string
+	spawnSandbox     string
+	spawnHost        string
+	spawnDryRun      bool
+	spawnMaxRuntime  time.Duration
+	spawnHard        bool
+)
+
+var spawnCmd = &cobra.Command{
+	Use:     "spawn <rig>/crew/<name>",
+	GroupID: GroupAgents,
+	Short:   "Create and start crew agents, optionally from a template",
+	Long: `Create a crew agent and start its session in one command.
+
+The agent can be configured from a template (--template: the town's
+settings/templates/<name>.json, JSON like its other settings rather than
+YAML), given a role (--role), runtime (--runtime), sandbox (--sandbox), or
+host (--host), all of which stay bound to it until it is spawned again
+without them. Without any, spawn is the same as 'gt crew start <rig>
+<name>'. --count and --batch (a JSON file, not YAML) spawn several agents
+at once, --after defers a spawn until other agents are done, and --dry-run
+shows what a spawn would do.
+
+See docs/reference.md for template and batch files, priorities and
+queueing, labels, hooks, and runtime limits.
+
+Examples:
+  gt spawn --template reviewer gastown/crew/rex
+  gt spawn --role reviewer gastown/crew/rex
+  gt spawn gastown/crew/max
+  gt spawn gastown/crew/ada --runtime aider
+  gt spawn gastown/crew/box --sandbox docker
+  gt spawn gastown/crew/far --host build-box-2
+  gt spawn --count 3 --template reviewer gastown/crew/rev
+  gt spawn --batch crew.json
+  gt spawn gastown/crew/tester --after gastown/crew/impl
+  gt spawn --pending
+  gt spawn gastown/crew/hotfix --priority high
+  gt spawn gastown/crew/ui --label team=frontend --label lang=ts
+  gt spawn gastown/crew/night --max-runtime 4h
+  gt spawn --batch crew.json --dry-run
+  gt spawn --list`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if spawnList || spawnBatch != "" || spawnPending || spawnCancel != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runSpawn,
+}
+
+func init() {
+	spawnCmd.Flags().StringVarP(&spawnTemplate, "template", "t", "", "Agent template to configure the agent with, from settings/templates/<name>.json")
+	spawnCmd.Flags().StringVar(&spawnRole, "role", "", "Agent role to give the agent: reviewer, tester, or one from settings/roles/ (see gt roles list)")
+	spawnCmd.Flags().StringVar(&spawnAccount, "account", "", "Claude Code account handle to use (overrides the template's)")
+	spawnCmd.Flags().StringVar(&spawnRuntime, "runtime", "", "Agent runtime: claude, codex, gemini, aider, or a custom agent (overrides the template's and rig's)")
+	spawnCmd.Flags().StringVar(&spawnSandbox, "sandbox", "", "Run the agent's runtime in a container: docker (configured by the town's \"sandbox\" settings)")
+	spawnCmd.Flags().StringVar(&spawnHost, "host", "", "Run the agent on this machine over ssh (configured by the town's \"hosts\" settings)")
+	spawnCmd.Flags().BoolVar(&spawnList, "list", false, "List the town's agent templates")
+	spawnCmd.Flags().StringVar(&spawnBatch, "batch", "", "Spawn the agents listed in this file (JSON only; YAML is not accepted)")
+	spawnCmd.Flags().IntVar(&spawnCount, "count", 0, "Spawn this many agents, numbering the address's name")
+	spawnCmd.Flags().IntVar(&spawnParallel, "parallel", 4, "Workspaces to create at a time in a batch")
+	spawnCmd.Flags().BoolVar(&spawnKeepPartial, "keep-partial", false, "Leave started sessions running if another agent in the batch fails")
+	spawnCmd.Flags().StringSliceVar(&spawnAfter, "after", nil, "Spawn only once this agent is done (repeatable: once all are)")
+	spawnCmd.Flags().BoolVar(&spawnPending, "pending", false, "List spawns waiting on other agents")
+	spawnCmd.Flags().StringVar(&spawnCancel, "cancel", "", "Cancel the pending spawn with this ID")
+	spawnCmd.Flags().StringSliceVar(&spawnLabels, "label", nil, "Label the agents key=value (repeatable; replaces their labels)")
+	spawnCmd.Flags().StringVar(&spawnPriority, "priority", string(preempt.Normal), "Agent priority: low, normal, or high (high preempts lower agents at the concurrency limit)")
+	spawnCmd.Flags().DurationVar(&spawnMaxRuntime, "max-runtime", 0, "Stop the agent once its session has run this long (e.g. 4h): it is asked to hand off first")
+	spawnCmd.Flags().BoolVar(&spawnHard, "hard", false, "With --max-runtime, kill the agent at the limit without asking it to hand off")
+	spawnCmd.Flags().BoolVarP(&spawnDryRun, "dry-run", "n", false, "Show the session, worktree, environment, command, and prompt each agent would get, without spawning")
+
+	rootCmd.AddCommand(spawnCmd)
+}
+
+// spawnTarget is one agent to spawn.
+type spawnTarget struct {
+	Rig      string
+	Name     string
+	Template *config.AgentTemplate
+	Role     *config.AgentRole
+	Account  string
+	Runtime  string // "" for the template's or rig's
+	Sandbox  string // "" to run on the host
+	Host     string // "" to run locally
+	Priority preempt.Priority
+	Labels   labels.Labels // nil to keep the agent's labels
+
+	MaxRuntime time.Duration // 0 for no limit
+	Hard       bool          // kill at MaxRuntime rather than hand off
+}
+
+// Address returns the target's agent address.
+func (t spawnTarget) Address() string {
+	return t.Rig + "/crew/" + t.Name
+}
+
+// binding returns what the target's crew member is bound to.
+func (t spawnTarget) binding() config.CrewBinding {
+	b := config.CrewBinding{Runtime: t.Runtime, Sandbox: t.Sandbox, Host: t.Host}
+	if t.Template != nil {
+		b.Template = t.Template.Name
+	}
+	if t.Role != nil {
+		b.Role = t.Role.Name
+	}
+	return b
+}
+
+// spawnBatchFile is the --batch file.
+type spawnBatchFile struct {
+	Template string `json:"template"`
+	Role     string `json:"role"`
+	Runtime  string `json:"runtime"`
+	Sandbox  string `json:"sandbox"`
+	Host     string `json:"host"`
+	Agents   []struct {
+		Address  string `json:"address"`
+		Template string `json:"template"`
+		Role     string `json:"role"`
+		Account  string `json:"account"`
+		Runtime  string `json:"runtime"`
+		Sandbox  string `json:"sandbox"`
+		Host     string `json:"host"`
+	} `json:"agents"`
+}
+
+func runSpawn(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if spawnList {
+		return listAgentTemplates(townRoot)
+	}
+	if spawnPending {
+		return listPendingSpawns(townRoot)
+	}
+	if spawnCancel != "" {
+		s, err := pending.Remove(townRoot, spawnCancel)
+		if err != nil {
+			return err
+		}
+		if s.Queued {
+			pending.LogQueue(townRoot, s, "cancelled by "+detectSender(), nil)
+			fmt.Printf("%s Cancelled %s: %s, queued for a slot\n", style.Success.Render("✓"), s.ID, s.Agent)
+			return nil
+		}
+		fmt.Printf("%s Cancelled %s: %s after %s\n", style.Success.Render("✓"), s.ID, s.Agent, strings.Join(s.Waiting(), ", "))
+		return nil
+	}
+	if spawnBatch != "" && spawnCount != 0 {
+		return fmt.Errorf("--batch and --count cannot be combined")
+	}
+	if spawnCount < 0 || spawnParallel < 1 {
+		return fmt.Errorf("--count must not be negative and --parallel must be at least 1")
+	}
+	priority, err := preempt.Parse(spawnPriority)
+	if err != nil {
+		return fmt.Errorf("--priority: %w", err)
+	}
+	agentLabels, err := labels.Parse(spawnLabels)
+	if err != nil {
+		return fmt.Errorf("--label: %w", err)
+	}
+	if spawnMaxRuntime < 0 {
+		return fmt.Errorf("--max-runtime must not be negative")
+	}
+	if spawnHard && spawnMaxRuntime == 0 {
+		return fmt.Errorf("--hard applies to --max-runtime")
+	}
+
+	// Check every address and template before creating anything
+	var targets []spawnTarget
+	if spawnBatch != "" {
+		targets, err = readSpawnBatch(townRoot, spawnBatch)
+	} else {
+		targets, err = spawnCountTargets(townRoot, args[0], spawnCount)
+	}
+	if err != nil {
+		return err
+	}
+	for i := range targets {
+		targets[i].Priority = priority
+		targets[i].MaxRuntime, targets[i].Hard = spawnMaxRuntime, spawnHard
+		if len(spawnLabels) > 0 {
+			targets[i].Labels = agentLabels
+		}
+	}
+	cmd.SilenceUsage = true
+
+	if spawnDryRun {
+		return planSpawns(townRoot, targets, spawnAfter)
+	}
+	if len(spawnAfter) > 0 {
+		return deferSpawns(townRoot, targets, spawnAfter)
+	}
+	if spawnBatch == "" && spawnCount == 0 {
+		t := targets[0]
+		if err := createSpawnWorkspace(t); err != nil {
+			return err
+		}
+		if t.Template != nil {
+			fmt.Printf("%s Configured from template %s\n", style.SuccessPrefix, t.Template.Name)
+		}
+		if t.Role != nil {
+			fmt.Printf("%s Role: %s\n", style.SuccessPrefix, t.Role.Name)
+		}
+		if t.Runtime != "" {
+			fmt.Printf("%s Runtime: %s\n", style.SuccessPrefix, t.Runtime)
+		}
+		if t.Sandbox != "" {
+			fmt.Printf("%s Sandbox: %s\n", style.SuccessPrefix, t.Sandbox)
+		}
+		if t.Host != "" {
+			fmt.Printf("%s Host: %s\n", style.SuccessPrefix, t.Host)
+		}
+		if t.MaxRuntime > 0 {
+			fmt.Printf("%s Max runtime: %s\n", style.SuccessPrefix, maxRuntimeDetail(t))
+		}
+		_, over, why, err := makeRoom(townRoot, targets)
+		if err != nil {
+			return err
+		}
+		if len(over) > 0 {
+			_, err := queueSpawns(townRoot, over, why)
+			return err
+		}
+		if err := startSpawnSession(cmd, t); err != nil {
+			return err
+		}
+		return recordSpawn(townRoot, t)
+	}
+	return spawnAll(cmd, townRoot, targets)
+}
+
+// spawnAll spawns a batch: workspaces in parallel, then sessions in turn.
+func spawnAll(cmd *cobra.Command, townRoot string, targets []spawnTarget) error {
+	fmt.Printf("Creating %d crew workspace(s), %d at a time...\n", len(targets), spawnParallel)
+	created := make([]error, len(targets))
+	sem := make(chan struct{}, spawnParallel)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			created[i] = createSpawnWorkspace(t)
+		}()
+	}
+	wg.Wait()
+
+	var ready []spawnTarget
+	for i, t := range targets {
+		if created[i] == nil {
+			ready = append(ready, t)
+		}
+	}
+	_, over, why, err := makeRoom(townRoot, ready)
+	if err != nil {
+		return err
+	}
+	queuedIDs := make(map[string]string) // by address
+	queued, err := queueSpawns(townRoot, over, why)
+	if err != nil {
+		return err
+	}
+	for _, s := range queued {
+		queuedIDs[s.Agent] = s.ID
+	}
+
+	tm := tmux.NewTmux()
+	b := newBatch("spawn")
+	for i, t := range targets {
+		addr := t.Address()
+		if err := created[i]; err != nil {
+			b.fail(addr, err)
+			continue
+		}
+		if id, ok := queuedIDs[addr]; ok {
+			b.succeed(addr, "queued at the concurrency limit ("+id+")", func() error {
+				_, err := pending.Remove(townRoot, id)
+				return err
+			})
+			continue
+		}
+		fmt.Printf("\n%s\n", style.Bold.Render(addr))
+		sessionID := crewSessionName(t.Rig, t.Name)
+		wasRunning, _ := tm.HasSession(sessionID)
+		if err := startSpawnSession(cmd, t); err != nil {
+			b.fail(addr, err)
+			continue
+		}
+		if err := recordSpawn(townRoot, t); err != nil {
+			style.PrintWarning("%s: %v", addr, err)
+		}
+		detail := "started"
+		if t.Template != nil {
+			detail += " from template " + t.Template.Name
+		}
+		if t.Role != nil {
+			detail += " as " + t.Role.Name
+		}
+		if t.Runtime != "" {
+			detail += " on " + t.Runtime
+		}
+		if t.Sandbox != "" {
+			detail += " in " + t.Sandbox
+		}
+		if t.Host != "" {
+			detail += " on host " + t.Host
+		}
+		if t.MaxRuntime > 0 {
+			detail += " for at most " + runlimit.FormatDuration(t.MaxRuntime)
+		}
+		if wasRunning {
+			b.skip(addr, "already running")
+			continue
+		}
+		b.succeed(addr, detail, func() error {
+			return tm.KillSession(sessionID)
+		})
+	}
+
+	if !spawnKeepPartial {
+		b.rollback()
+	}
+	b.report()
+	return b.err()
+}
+
+// deferSpawns records pending spawns of the targets, to be run by the
+// daemon once every agent in after is done.
+func deferSpawns(townRoot string, targets []spawnTarget, after []string) error {
+	var deps []string
+	for _, a := range after {
+		id, err := session.ParseAddress(a)
+		if err != nil {
+			return fmt.Errorf("--after %s: %w", a, err)
+		}
+		if !slices.Contains(deps, id.Address()) {
+			deps = append(deps, id.Address())
+		}
+	}
+	verb := "is"
+	if len(deps) > 1 {
+		verb = "are"
+	}
+	for _, t := range targets {
+		s := pendingSpawn(t)
+		s.After = deps
+		s, err := pending.Add(townRoot, s)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s will spawn once %s %s done (%s)\n", style.Success.Render("✓"),
+			style.Bold.Render(s.Agent), strings.Join(deps, " and "), verb, s.ID)
+	}
+	if running, _, _ := daemon.IsRunning(townRoot); !running {
+		style.PrintWarning("the daemon is not running, so pending spawns will not run (gt daemon start)")
+	}
+	return nil
+}
+
+// queueSpawns queues spawns of the targets, to be started by the daemon
+// as slots free up under max_concurrent_agents, and logs each.
+func queueSpawns(townRoot string, targets []spawnTarget, why string) ([]pending.Spawn, error) {
+	var queued []pending.Spawn
+	for _, t := range targets {
+		s := pendingSpawn(t)
+		s.Queued = true
+		s, err := pending.Add(townRoot, s)
+		if err != nil {
+			return queued, err
+		}
+		pending.LogQueue(townRoot, s, "queued: "+why, nil)
+		fmt.Printf("%s %s queued (%s): %s; it starts when a slot frees up\n", style.Bold.Render("⏳"),
+			style.Bold.Render(s.Agent), s.ID, why)
+		queued = append(queued, s)
+	}
+	if running, _, _ := daemon.IsRunning(townRoot); !running {
+		style.PrintWarning("the daemon is not running, so queued spawns will not start (gt daemon start)")
+	}
+	return queued, nil
+}
+
+// pendingSpawn returns the pending spawn of a target, waiting on nothing
+// yet.
+func pendingSpawn(t spawnTarget) pending.Spawn {
+	s := pending.Spawn{
+		Agent:     t.Address(),
+		Account:   t.Account,
+		Runtime:   t.Runtime,
+		Sandbox:   t.Sandbox,
+		Host:      t.Host,
+		CreatedAt: time.Now(),
+		CreatedBy: detectSender(),
+	}
+	if t.Template != nil {
+		s.Template = t.Template.Name
+	}
+	if t.Role != nil {
+		s.Role = t.Role.Name
+	}
+	if t.Priority != preempt.Normal {
+		s.Priority = string(t.Priority)
+	}
+	if t.Labels != nil {
+		s.Labels = t.Labels.Specs()
+	}
+	if t.MaxRuntime > 0 {
+		s.MaxRuntime = runlimit.FormatDuration(t.MaxRuntime)
+		s.Hard = t.Hard
+	}
+	return s
+}
+
+// recordSpawn records the priority, maximum runtime, and labels of a
+// spawned target. Its runtime is counted from now; without --max-runtime
+// any limit it had is dropped.
+func recordSpawn(townRoot string, t spawnTarget) error {
+	if err := preempt.Set(townRoot, t.Address(), t.Priority); err != nil {
+		return fmt.Errorf("recording priority: %w", err)
+	}
+	if t.MaxRuntime > 0 {
+		l := runlimit.Limit{Agent: t.Address(), Session: crewSessionName(t.Rig, t.Name), StartedAt: time.Now(), MaxRuntime: t.MaxRuntime, Hard: t.Hard}
+		if err := runlimit.Set(townRoot, l); err != nil {
+			return fmt.Errorf("recording max runtime: %w", err)
+		}
+		if running, _, _ := daemon.IsRunning(townRoot); !running {
+			style.PrintWarning("the daemon is not running, so the max runtime will not be enforced (gt daemon start)")
+		}
+	} else if _, err := runlimit.Clear(townRoot, t.Address()); err != nil {
+		return fmt.Errorf("clearing max runtime: %w", err)
+	}
+	if t.Labels == nil {
+		return nil
+	}
+	if err := labels.Set(townRoot, t.Address(), t.Labels); err != nil {
+		return fmt.Errorf("recording labels: %w", err)
+	}
+	fmt.Printf("%s Labelled %s\n", style.SuccessPrefix, t.Labels)
+	return nil
+}
+
+// makeRoom preempts running agents of lower priority than the targets
+// when starting their sessions would exceed the town's
+// max_concurrent_agents, and splits the targets into those that may start
+// and those that do not fit, which are to be queued. Targets whose
+// sessions already run always fit. why says why any were left over. With
+// --dry-run it only says whom it would preempt.
+func makeRoom(townRoot string, targets []spawnTarget) (fit, over []spawnTarget, why string, err error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("loading town settings: %w", err)
+	}
+	limit := settings.MaxConcurrentAgents
+	if limit <= 0 || len(targets) == 0 {
+		return targets, nil, "", nil
+	}
+	t := tmux.NewTmux()
+	running, err := preempt.Running(t, townRoot)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("counting running agents: %w", err)
+	}
+	priority := targets[0].Priority
+	var starting []spawnTarget
+	for _, target := range targets {
+		if exists, _ := t.HasSession(crewSessionName(target.Rig, target.Name)); exists {
+			fit = append(fit, target)
+		} else {
+			starting = append(starting, target)
+		}
+	}
+	free := max(limit-len(running), 0)
+	if len(starting) <= free {
+		return targets, nil, "", nil
+	}
+
+	victims := preempt.Victims(running, priority, len(starting)-free)
+	for _, v := range victims {
+		if spawnDryRun {
+			fmt.Printf("Would preempt %s (%s priority) to make room\n", v.Agent, v.Priority)
+			continue
+		}
+		if err := preempt.Preempt(t, townRoot, v, starting[0].Address(), priority); err != nil {
+			return nil, nil, "", fmt.Errorf("preempting %s: %w", v.Agent, err)
+		}
+		fmt.Printf("%s Preempted %s (%s priority) to make room; the daemon resumes it when a slot frees up\n",
+			style.Bold.Render("⏸"), v.Agent, v.Priority)
+	}
+	if len(victims) > 0 && !spawnDryRun {
+		if running, _, _ := daemon.IsRunning(townRoot); !running {
+			style.PrintWarning("the daemon is not running, so preempted agents will not be resumed (gt daemon start, or gt resume)")
+		}
+	}
+	room := free + len(victims)
+	why = fmt.Sprintf("max_concurrent_agents (%d) reached with %d running", limit, len(running))
+	if len(victims) < len(starting)-free {
+		why += fmt.Sprintf(", %d of them below %s priority", len(victims), priority)
+	}
+	return append(fit, starting[:room]...), starting[room:], why, nil
+}
+
+// planSpawns prints what spawning the targets would do (--dry-run).
+func planSpawns(townRoot string, targets []spawnTarget, after []string) error {
+	queued := make(map[string]string) // why, by address
+	if len(after) > 0 {
+		for _, a := range after {
+			if _, err := session.ParseAddress(a); err != nil {
+				return fmt.Errorf("--after %s: %w", a, err)
+			}
+		}
+		fmt.Printf("Would wait for %s to be done, then spawn:\n", strings.Join(after, " and "))
+	} else {
+		_, over, why, err := makeRoom(townRoot, targets)
+		if err != nil {
+			return err
+		}
+		for _, t := range over {
+			queued[t.Address()] = why
+		}
+	}
+	for _, t := range targets {
+		plan, err := spawnPlan(townRoot, t)
+		if err != nil {
+			return fmt.Errorf("%s: %w", t.Address(), err)
+		}
+		if why, ok := queued[t.Address()]; ok {
+			plan.Action = "Would queue " + t.Address() + " (" + why + "), then spawn it"
+		}
+		fmt.Printf("\n%s", plan)
+	}
+	return nil
+}
+
+// spawnPlan returns what spawning a target would do.
+func spawnPlan(townRoot string, t spawnTarget) (dryRunPlan, error) {
+	crewMgr, r, err := getCrewManager(t.Rig)
+	if err != nil {
+		return dryRunPlan{}, err
+	}
+	worktree := filepath.Join(r.Path, "crew", t.Name)
+	if _, err := crewMgr.Get(t.Name); err == crew.ErrCrewNotFound {
+		worktree += " (would be created)"
+	} else if err != nil {
+		return dryRunPlan{}, fmt.Errorf("getting crew worker: %w", err)
+	}
+	sessionName := crewSessionName(t.Rig, t.Name)
+	if exists, _ := tmux.NewTmux().HasSession(sessionName); exists {
+		sessionName += " (exists: its runtime is restarted if it has exited)"
+	}
+
+	binding := t.binding()
+	startup := config.PlanCrewStartup(t.Rig, t.Name, r.Path, "", binding)
+	configDir, account, err := config.ResolveAccountConfigDir(constants.MayorAccountsPath(townRoot), t.Account)
+	if err != nil {
+		return dryRunPlan{}, fmt.Errorf("resolving account: %w", err)
+	}
+	if configDir != "" {
+		startup.Env["CLAUDE_CONFIG_DIR"] = configDir
+	}
+
+	plan := dryRunPlan{
+		Action:   "Would spawn " + t.Address(),
+		Session:  sessionName,
+		Worktree: worktree,
+		Env:      startup.Env,
+		Command:  startup.Command,
+		Prompt:   startup.Prompt,
+	}
+	if binding.Template != "" {
+		plan.Details = append(plan.Details, [2]string{"Template", binding.Template})
+	}
+	if binding.Role != "" {
+		plan.Details = append(plan.Details, [2]string{"Role", binding.Role})
+	}
+	plan.Details = append(plan.Details, [2]string{"Runtime", startup.Runtime})
+	if t.Sandbox != "" {
+		plan.Details = append(plan.Details, [2]string{"Sandbox", t.Sandbox})
+	}
+	if t.Host != "" {
+		plan.Details = append(plan.Details, [2]string{"Host", t.Host})
+		if h, err := config.LoadHost(townRoot, t.Host); err == nil {
+			plan.Details = append(plan.Details, [2]string{"Remote", h.SSH + ":" + h.WorkDir(t.Rig, t.Name)})
+		}
+	}
+	if account != "" {
+		plan.Details = append(plan.Details, [2]string{"Account", account})
+	}
+	plan.Details = append(plan.Details, [2]string{"Priority", string(t.Priority)})
+	if t.Labels != nil {
+		plan.Details = append(plan.Details, [2]string{"Labels", t.Labels.String()})
+	}
+	if t.MaxRuntime > 0 {
+		plan.Details = append(plan.Details, [2]string{"Limit", "max runtime " + maxRuntimeDetail(t)})
+	}
+	hooks, _, err := lifecycle.ForEvent(townRoot, lifecycle.Meta{Event: lifecycle.PreSpawn, Agent: t.Address()})
+	if err != nil {
+		return dryRunPlan{}, err
+	}
+	for _, h := range hooks {
+		plan.Details = append(plan.Details, [2]string{"Hook", h.Source + " pre_spawn: " + h.Command})
+	}
+	plan.Details = append(plan.Details, [2]string{"Nudge", "gt prime, once the runtime is up"})
+	return plan, nil
+}
+
+// listPendingSpawns prints the spawns waiting on other agents.
+func listPendingSpawns(townRoot string) error {
+	list, err := pending.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		fmt.Printf("%s No pending spawns\n", style.Dim.Render("○"))
+		return nil
+	}
+	position := make(map[string]int)
+	for i, s := range pending.Queue(list) {
+		position[s.ID] = i + 1
+	}
+	for _, s := range list {
+		var line string
+		if s.Queued {
+			fmt.Printf("%s  %s queued for a slot\n", style.Bold.Render(s.ID), s.Agent)
+			line = fmt.Sprintf("#%d in the queue", position[s.ID])
+			if s.Priority != "" {
+				line += ", " + s.Priority + " priority"
+			}
+		} else {
+			fmt.Printf("%s  %s after %s\n", style.Bold.Render(s.ID), s.Agent, strings.Join(s.After, ", "))
+			line = "waiting on " + strings.Join(s.Waiting(), ", ")
+		}
+		if s.Template != "" {
+			line += ", template " + s.Template
+		}
+		if s.Role != "" {
+			line += ", role " + s.Role
+		}
+		if s.MaxRuntime != "" {
+			line += ", max runtime " + s.MaxRuntime
+		}
+		line += ", declared " + s.CreatedAt.Local().Format("2006-01-02 15:04")
+		fmt.Printf("    %s\n", style.Dim.Render(line))
+	}
+	if running, _, _ := daemon.IsRunning(townRoot); !running {
+		fmt.Println()
+		style.PrintWarning("the daemon is not running, so pending spawns will not run (gt daemon start)")
+	}
+	return nil
+}
+
+// createSpawnWorkspace creates the target's crew workspace if it does not
+// exist and binds it to the target's template, role, runtime, sandbox, and
+// host, unbinding it from those it is not given. The binding is written
+// in one atomic replace, so a session started meanwhile sees the old
+// binding or the new one, never a mix.
+func createSpawnWorkspace(t spawnTarget) error {
+	crewMgr, r, err := getCrewManager(t.Rig)
+	if err != nil {
+		return err
+	}
+	if _, err := crewMgr.Get(t.Name); err == crew.ErrCrewNotFound {
+		if _, err := crewMgr.Add(t.Name, false); err != nil {
+			return fmt.Errorf("creating crew workspace: %w", err)
+		}
+		fmt.Printf("%s Created crew workspace: %s/%s\n", style.SuccessPrefix, t.Rig, t.Name)
+	} else if err != nil {
+		return fmt.Errorf("getting crew worker: %w", err)
+	}
+
+	if err := config.SetCrewBinding(r.Path, t.Name, t.binding()); err != nil {
+		return fmt.Errorf("binding crew member: %w", err)
+	}
+	if t.Host != "" {
+		townRoot := filepath.Dir(r.Path)
+		h, err := config.LoadHost(townRoot, t.Host)
+		if err != nil {
+			return err
+		}
+		if err := remote.Provision(h, r.GitURL, t.Rig, t.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startSpawnSession starts the target's session, first running the
+// town's and rig's pre_spawn hooks if it has none yet.
+func startSpawnSession(cmd *cobra.Command, t spawnTarget) error {
+	sessionID := crewSessionName(t.Rig, t.Name)
+	if exists, _ := tmux.NewTmux().HasSession(sessionID); !exists {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		m := lifecycle.Meta{Event: lifecycle.PreSpawn, Agent: t.Address(), Session: sessionID, Time: time.Now()}
+		if err := lifecycle.Run(townRoot, m, os.Stdout); err != nil {
+			return fmt.Errorf("not spawning %s: %w", t.Address(), err)
+		}
+	}
+	startCrewRig = t.Rig
+	startCrewAccount = t.Account
+	return runStartCrew(cmd, []string{t.Rig + "/" + t.Name})
+}
+
+// maxRuntimeDetail describes the target's maximum runtime and what
+// happens when it is reached.
+func maxRuntimeDetail(t spawnTarget) string {
+	if t.Hard {
+		return runlimit.FormatDuration(t.MaxRuntime) + ", then killed"
+	}
+	return runlimit.FormatDuration(t.MaxRuntime) + ", then asked to hand off and stopped"
+}
+
+// newSpawnTarget checks an address, template, role, runtime, sandbox, and
+// host and returns the target. The account defaults to --account, then the
+// template's, and the role, runtime, sandbox, and host to --role,
+// --runtime, --sandbox, and --host.
+func newSpawnTarget(townRoot, addr, template, role, account, runtime, sandbox, host string) (spawnTarget, error) {
+	rigName, name, err := parseSpawnAddress(addr)
+	if err != nil {
+		return spawnTarget{}, err
+	}
+	t := spawnTarget{Rig: rigName, Name: name, Account: account, Runtime: runtime, Sandbox: sandbox, Host: host}
+	if template != "" {
+		if t.Template, err = config.LoadAgentTemplate(townRoot, template); err != nil {
+			return spawnTarget{}, err
+		}
+	}
+	if role == "" {
+		role = spawnRole
+	}
+	if role != "" {
+		if t.Role, err = config.LoadAgentRole(townRoot, role); err != nil {
+			return spawnTarget{}, err
+		}
+	}
+	if t.Account == "" {
+		t.Account = spawnAccount
+	}
+	if t.Account == "" && t.Template != nil {
+		t.Account = t.Template.Account
+	}
+	if t.Runtime == "" {
+		t.Runtime = spawnRuntime
+	}
+	if t.Runtime != "" && !config.KnownRuntime(townRoot, t.Runtime) {
+		known := config.ListAgentPresets()
+		sort.Strings(known)
+		return spawnTarget{}, fmt.Errorf("unknown runtime %q (presets: %s; or define it under \"agents\" in settings/config.json)",
+			t.Runtime, strings.Join(known, ", "))
+	}
+	if t.Sandbox == "" {
+		t.Sandbox = spawnSandbox
+	}
+	if t.Sandbox != "" {
+		if err := config.ValidateSandbox(townRoot, t.Sandbox); err != nil {
+			return spawnTarget{}, err
+		}
+	}
+	if t.Host == "" {
+		t.Host = spawnHost
+	}
+	if t.Host != "" {
+		if t.Sandbox != "" {
+			return spawnTarget{}, fmt.Errorf("%s: a sandbox and a host cannot be combined", t.Address())
+		}
+		if err := config.ValidateHost(townRoot, t.Host); err != nil {
+			return spawnTarget{}, err
+		}
+	}
+	return t, nil
+}
+
+// spawnCountTargets returns the targets for an address and --count: the
+// address itself for a count of 0, or count agents numbered from 1 after
+// its name.
+func spawnCountTargets(townRoot, addr string, count int) ([]spawnTarget, error) {
+	if count == 0 {
+		t, err := newSpawnTarget(townRoot, addr, spawnTemplate, "", "", "", "", "")
+		return []spawnTarget{t}, err
+	}
+	var targets []spawnTarget
+	for i := 1; i <= count; i++ {
+		t, err := newSpawnTarget(townRoot, fmt.Sprintf("%s%d", strings.TrimRight(addr, "/"), i), spawnTemplate, "", "", "", "", "")
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// readSpawnBatch reads and checks a --batch file.
+func readSpawnBatch(townRoot, path string) ([]spawnTarget, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the user's own flag
+	if err != nil {
+		return nil, fmt.Errorf("reading batch file: %w", err)
+	}
+	var f spawnBatchFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing batch file %s as JSON: %w", path, err)
+	}
+	if len(f.Agents) == 0 {
+		return nil, fmt.Errorf("batch file %s lists no agents", path)
+	}
+	seen := make(map[string]bool)
+	var targets []spawnTarget
+	for i, a := range f.Agents {
+		template := a.Template
+		if template == "" {
+			template = f.Template
+		}
+		if template == "" {
+			template = spawnTemplate
+		}
+		role := a.Role
+		if role == "" {
+			role = f.Role
+		}
+		runtime := a.Runtime
+		if runtime == "" {
+			runtime = f.Runtime
+		}
+		sandbox := a.Sandbox
+		if sandbox == "" {
+			sandbox = f.Sandbox
+		}
+		host := a.Host
+		if host == "" {
+			host = f.Host
+		}
+		t, err := newSpawnTarget(townRoot, a.Address, template, role, a.Account, runtime, sandbox, host)
+		if err != nil {
+			return nil, fmt.Errorf("batch agent %d: %w", i+1, err)
+		}
+		if seen[t.Address()] {
+			return nil, fmt.Errorf("batch agent %d: %s is listed twice", i+1, t.Address())
+		}
+		seen[t.Address()] = true
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// parseSpawnAddress splits a crew address, <rig>/crew/<name> or
+// <rig>/<name>, into its rig and name.
+func parseSpawnAddress(addr string) (rigName, name string, err error) {
+	parts := strings.Split(strings.Trim(addr, "/"), "/")
+	switch {
+	case len(parts) == 3 && parts[1] == "crew":
+		rigName, name = parts[0], parts[2]
+	case len(parts) == 2 && parts[1] != "crew" && parts[1] != "polecats":
+		rigName, name = parts[0], parts[1]
+	case len(parts) == 3 && parts[1] == "polecats":
+		return "", "", fmt.Errorf("%s: polecats are spawned for work by 'gt sling', not by name", addr)
+	default:
+		return "", "", fmt.Errorf("invalid address %q: want <rig>/crew/<name>", addr)
+	}
+	if rigName == "" || name == "" {
+		return "", "", fmt.Errorf("invalid address %q: want <rig>/crew/<name>", addr)
+	}
+	return rigName, name, nil
+}
+
+// listAgentTemplates prints the town's agent templates.
+func listAgentTemplates(townRoot string) error {
+	names, err := config.ListAgentTemplates(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing templates: %w", err)
+	}
+	if len(names) == 0 {
+		fmt.Printf("%s No agent templates in %s\n", style.Dim.Render("○"), config.AgentTemplatesDir(townRoot))
+		return nil
+	}
+	for _, name := range names {
+		t, err := config.LoadAgentTemplate(townRoot, name)
+		if err != nil {
+			fmt.Printf("  %-16s %s\n", name, style.Error.Render(err.Error()))
+			continue
+		}
+		fmt.Printf("  %-16s %s\n", name, style.Dim.Render(t.Description))
+	}
+	return nil
+}
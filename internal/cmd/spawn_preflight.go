@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/budget"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/git"
+	"github.com/ctiospl/gastown/internal/polecat"
+	"github.com/ctiospl/gastown/internal/rig"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// spawnMinFreeDisk is the free space a rig's filesystem needs before a
+// polecat is spawned. Worktrees, build output, and transcripts fill it.
+const spawnMinFreeDisk = 512 << 20
+
+// preflightCheck is the outcome of one spawn preflight check.
+type preflightCheck struct {
+	Name string
+	Err  error
+	Fix  string // suggested remedy, shown when Err is set
+}
+
+// spawnPreflight collects every preflight check for one spawn so failures
+// are reported together instead of one at a time.
+type spawnPreflight struct {
+	Rig    string
+	Checks []preflightCheck
+}
+
+func (p *spawnPreflight) add(name string, err error, fix string) {
+	p.Checks = append(p.Checks, preflightCheck{Name: name, Err: err, Fix: fix})
+}
+
+// Err returns a consolidated error listing every failed check, or nil.
+func (p *spawnPreflight) Err() error {
+	var failed []preflightCheck
+	for _, c := range p.Checks {
+		if c.Err != nil {
+			failed = append(failed, c)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "spawn preflight failed for %s (%d of %d checks):", p.Rig, len(failed), len(p.Checks))
+	for _, c := range failed {
+		fmt.Fprintf(&b, "\n  ✗ %s: %v", c.Name, c.Err)
+		if c.Fix != "" {
+			fmt.Fprintf(&b, "\n      %s", c.Fix)
+		}
+	}
+	return fmt.Errorf("%s", b.String())
+}
+
+// runSpawnPreflight verifies that a polecat can be spawned into r before
+// anything is created: the runtime is installed and authenticated, policy
+// and the town's fairness rules allow the spawn, disk space is available, a branch can be created, and
+// the allocated polecat's worktree holds no uncommitted work.
+func runSpawnPreflight(townRoot string, r *rig.Rig, mgr *polecat.Manager, polecatName string, opts SlingSpawnOptions) *spawnPreflight {
+	p := &spawnPreflight{Rig: r.Name}
+	settings, _ := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+
+	// Runtime binary and credentials (naked spawns start the agent manually)
+	if !opts.Naked {
+		rc := config.ResolveAgentConfig(townRoot, r.Path)
+		_, err := exec.LookPath(rc.Command)
+		if err != nil {
+			err = fmt.Errorf("%s not found in PATH", rc.Command)
+		}
+		p.add("runtime", err, "install the runtime, or set \"agent\" in the rig's settings/config.json")
+
+		preset := config.GetAgentPresetByName(config.ResolveAgentName(townRoot, r.Path))
+		if err == nil && preset != nil {
+			p.add("auth", checkRuntimeAuth(townRoot, preset, opts.Account),
+				fmt.Sprintf("log in to %s, or export one of: %s", preset.Name, strings.Join(preset.AuthEnv, ", ")))
+		}
+	}
+
+	// Policy: the polecat's spend budgets and the rig's polecat cap
+	policyErr := spawnBudgetErr(townRoot, r.Name+"/polecats/"+polecatName)
+	if policyErr == nil && settings != nil && settings.MaxPolecats > 0 {
+		if existing, err := mgr.List(); err == nil && len(existing) >= settings.MaxPolecats {
+			policyErr = fmt.Errorf("rig has %d polecats (max_polecats: %d)", len(existing), settings.MaxPolecats)
+		}
+	}
+	p.add("policy", policyErr, "wait for capacity or the budget's next period, or raise the limit (rig settings, gt budget set)")
+
+	// Fairness: the rig's share of town-wide capacity and its quota
+	if state, err := loadFairnessState(townRoot); err != nil {
+		p.add("fairness", err, "fix \"fairness\" in settings/config.json")
+	} else if state.Configured {
+		p.add("fairness", state.Policy.Admit(r.Name, state.Rigs, state.Running, state.Used),
+			"wait for the rig's share to free up, or adjust \"fairness\" in settings/config.json (see 'gt fairness')")
+	}
+
+	// Disk space
+	free, err := util.FreeDiskSpace(r.Path)
+	if errors.Is(err, errors.ErrUnsupported) {
+		err = nil // not checked on this platform
+	} else if err == nil && free < spawnMinFreeDisk {
+		err = fmt.Errorf("only %d MB free on the rig's filesystem (need %d MB)", free>>20, spawnMinFreeDisk>>20)
+	}
+	p.add("disk", err, "free up space, e.g. 'gt polecat gc' to remove stale worktrees")
+
+	// Branch creation in the repo base
+	p.add("branch", mgr.CheckCanAdd(), "check the rig's repo (.repo.git or mayor/rig) for locks or missing commits")
+
+	// A stale worktree for the allocated name is repaired in place; refuse
+	// if that would discard uncommitted work.
+	var worktreeErr error
+	if existing, err := mgr.Get(polecatName); err == nil && !opts.Force {
+		workStatus, checkErr := git.NewGit(existing.ClonePath).CheckUncommittedWork()
+		if checkErr == nil && !workStatus.Clean() {
+			worktreeErr = fmt.Errorf("polecat %s has uncommitted work: %s", polecatName, workStatus.String())
+		}
+	}
+	p.add("worktree", worktreeErr, "commit or discard the work, or use --force")
+
+	return p
+}
+
+// spawnBudgetErr returns why agent may not be spawned for its spend: a
+// budget that covers it is used up and would pause or kill it (see gt
+// budget). Budgets that only warn do not stop a spawn.
+func spawnBudgetErr(townRoot, agent string) error {
+	budgets, err := budget.Load(townRoot)
+	if err != nil || len(budgets) == 0 {
+		return err
+	}
+	ledger, err := budget.LoadLedger(townRoot)
+	if err != nil {
+		return err
+	}
+	if s, stop := budget.Stopping(ledger.Statuses(budgets, time.Now()), agent); stop {
+		return fmt.Errorf("%s", s.Reason())
+	}
+	return nil
+}
+
+// checkRuntimeAuth reports whether credentials for the runtime are present:
+// one of the preset's AuthEnv variables, one of its AuthFiles, or a gt
+// account config dir. Presets that declare neither are not checked.
+func checkRuntimeAuth(townRoot string, preset *config.AgentPresetInfo, account string) error {
+	if len(preset.AuthEnv) == 0 && len(preset.AuthFiles) == 0 {
+		return nil
+	}
+	for _, env := range preset.AuthEnv {
+		if os.Getenv(env) != "" {
+			return nil
+		}
+	}
+	if preset.Name == config.AgentClaude {
+		configDir, _, err := config.ResolveAccountConfigDir(constants.MayorAccountsPath(townRoot), account)
+		if err != nil {
+			return err
+		}
+		if configDir != "" {
+			if _, err := os.Stat(configDir); err == nil {
+				return nil
+			}
+			return fmt.Errorf("account config dir %s does not exist", configDir)
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("cannot locate home directory: %w", err)
+	}
+	for _, f := range preset.AuthFiles {
+		if _, err := os.Stat(filepath.Join(home, f)); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("no %s credentials found", preset.Name)
+}
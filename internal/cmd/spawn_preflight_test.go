@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/budget"
+	"github.com/ctiospl/gastown/internal/git"
+	"github.com/ctiospl/gastown/internal/polecat"
+	"github.com/ctiospl/gastown/internal/rig"
+)
+
+func TestSpawnPreflightErrConsolidates(t *testing.T) {
+	p := &spawnPreflight{Rig: "gastown"}
+	p.add("runtime", nil, "install it")
+	if err := p.Err(); err != nil {
+		t.Fatalf("Err() = %v with no failures", err)
+	}
+
+	p.add("disk", errors.New("only 10 MB free"), "free up space")
+	p.add("branch", errors.New("cannot write refs"), "")
+	err := p.Err()
+	if err == nil {
+		t.Fatal("Err() = nil with failures")
+	}
+	msg := err.Error()
+	for _, want := range []string{"gastown (2 of 3 checks)", "disk: only 10 MB free", "free up space", "branch: cannot write refs"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error %q missing %q", msg, want)
+		}
+	}
+	if strings.Contains(msg, "runtime") {
+		t.Errorf("error %q lists a passing check", msg)
+	}
+}
+
+func TestSpawnPreflightBlocksOverBudget(t *testing.T) {
+	townRoot := t.TempDir()
+	today := time.Now().Format("2006-01-02")
+	ledger := &budget.Ledger{Spend: map[string]map[string]budget.Spend{
+		"gastown/polecats/Toast": {today: {Cost: 6}},
+	}}
+	if err := budget.SaveLedger(townRoot, ledger); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []budget.Budget{
+		{Scope: "gastown/", Amount: 5, Unit: budget.USD, Period: budget.Day},
+		{Scope: "beads/", Amount: 5, Unit: budget.USD, Period: budget.Day},
+	} {
+		if _, err := budget.Set(townRoot, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	policy := func(rigName string) error {
+		t.Helper()
+		r := &rig.Rig{Name: rigName, Path: filepath.Join(townRoot, rigName)}
+		mgr := polecat.NewManager(r, git.NewGit(r.Path))
+		p := runSpawnPreflight(townRoot, r, mgr, "Nux", SlingSpawnOptions{Naked: true})
+		for _, c := range p.Checks {
+			if c.Name == "policy" {
+				if c.Err != nil && !strings.Contains(p.Err().Error(), "policy: "+c.Err.Error()) {
+					t.Errorf("preflight error %q lacks the policy failure", p.Err())
+				}
+				return c.Err
+			}
+		}
+		t.Fatal("no policy check")
+		return nil
+	}
+
+	err := policy("gastown")
+	if err == nil || !strings.Contains(err.Error(), "budget 5usd/day for gastown/ exceeded ($6.00)") {
+		t.Errorf("spawn into the over-budget rig: policy = %v", err)
+	}
+	if err := policy("beads"); err != nil {
+		t.Errorf("spawn into a rig under budget: policy = %v", err)
+	}
+
+	// A budget that only warns lets spawns through
+	if _, err := budget.Set(townRoot, budget.Budget{Scope: "gastown/", Amount: 5, Unit: budget.USD, Period: budget.Day, Action: budget.Warn}); err != nil {
+		t.Fatal(err)
+	}
+	if err := policy("gastown"); err != nil {
+		t.Errorf("spawn under a warn-only budget: policy = %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/labels"
+)
+
+func TestParseSpawnAddress(t *testing.T) {
+	tests := []struct {
+		addr, rig, name string
+		wantErr         bool
+	}{
+		{addr: "gastown/crew/rex", rig: "gastown", name: "rex"},
+		{addr: "gastown/rex", rig: "gastown", name: "rex"},
+		{addr: "/gastown/crew/rex/", rig: "gastown", name: "rex"},
+		{addr: "gastown/polecats/Toast", wantErr: true},
+		{addr: "gastown/witness/x", wantErr: true},
+		{addr: "gastown/crew", wantErr: true},
+		{addr: "rex", wantErr: true},
+	}
+	for _, tt := range tests {
+		rig, name, err := parseSpawnAddress(tt.addr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSpawnAddress(%q) error = %v, wantErr %v", tt.addr, err, tt.wantErr)
+			continue
+		}
+		if rig != tt.rig || name != tt.name {
+			t.Errorf("parseSpawnAddress(%q) = %q, %q; want %q, %q", tt.addr, rig, name, tt.rig, tt.name)
+		}
+	}
+}
+
+func TestReadSpawnBatch(t *testing.T) {
+	town := t.TempDir()
+	dir := config.AgentTemplatesDir(town)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"reviewer": `{"account": "work"}`,
+		"builder":  `{}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(content string) string {
+		path := filepath.Join(t.TempDir(), "crew.json")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	targets, err := readSpawnBatch(town, write(`{"template": "reviewer", "agents": [
+		{"address": "gastown/crew/rex"},
+		{"address": "gastown/max", "template": "builder", "account": "home"}]}`))
+	if err != nil {
+		t.Fatalf("readSpawnBatch: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("got %d targets, want 2", len(targets))
+	}
+	if got := targets[0]; got.Address() != "gastown/crew/rex" || got.Template.Name != "reviewer" || got.Account != "work" {
+		t.Errorf("targets[0] = %+v, want rex from reviewer with the template's account", got)
+	}
+	if got := targets[1]; got.Address() != "gastown/crew/max" || got.Template.Name != "builder" || got.Account != "home" {
+		t.Errorf("targets[1] = %+v, want max from builder with its own account", got)
+	}
+
+	for _, bad := range []string{
+		`{"agents": []}`,
+		`{"agents": [{"address": "gastown/crew/rex", "template": "missing"}]}`,
+		`{"agents": [{"address": "gastown/polecats/Toast"}]}`,
+		`{"agents": [{"address": "gastown/crew/rex"}, {"address": "gastown/rex"}]}`,
+	} {
+		if _, err := readSpawnBatch(town, write(bad)); err == nil {
+			t.Errorf("readSpawnBatch(%s) succeeded, want an error", bad)
+		}
+	}
+
+	targets, err = spawnCountTargets(town, "gastown/crew/rev", 3)
+	if err != nil {
+		t.Fatalf("spawnCountTargets: %v", err)
+	}
+	if len(targets) != 3 || targets[0].Address() != "gastown/crew/rev1" || targets[2].Address() != "gastown/crew/rev3" {
+		t.Errorf("spawnCountTargets = %+v, want rev1..rev3", targets)
+	}
+}
+
+func TestPendingSpawnLabels(t *testing.T) {
+	old := spawnLabels
+	spawnLabels = []string{"team=other"}
+	defer func() { spawnLabels = old }()
+
+	s := pendingSpawn(spawnTarget{Rig: "gastown", Name: "ui", Labels: labels.Labels{"team": "frontend", "lang": "ts"}})
+	if !slices.Equal(s.Labels, []string{"lang=ts", "team=frontend"}) {
+		t.Errorf("Labels = %v, want the target's", s.Labels)
+	}
+	if s := pendingSpawn(spawnTarget{Rig: "gastown", Name: "ui"}); s.Labels != nil {
+		t.Errorf("Labels = %v, want none for a target keeping its labels", s.Labels)
+	}
+}
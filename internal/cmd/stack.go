@@ -0,0 +1,426 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/beads"
+	"github.com/ctiospl/gastown/internal/git"
+	"github.com/ctiospl/gastown/internal/polecat"
+	"github.com/ctiospl/gastown/internal/rig"
+	"github.com/ctiospl/gastown/internal/stack"
+	"github.com/ctiospl/gastown/internal/style"
+)
+
+var (
+	stackListJSON     bool
+	stackRestackDry   bool
+	stackTrackOn      string
+	stackTrackRigName string
+)
+
+var stackCmd = &cobra.Command{
+	Use:     "stack",
+	GroupID: GroupWork,
+	Short:   "Track and restack branches built on unmerged work",
+	Long: `Manage stacked branches: work started on another agent's unmerged
+branch instead of the default branch, so it does not wait for the merge.
+
+Stack new work with 'gt sling <bead> <rig> --stack-on <polecat|branch>'.
+gt records the stack, and when the stacked work is submitted its merge
+request depends on the parent's issue, so the Refinery merges the stack
+bottom-up: the child's MR stays blocked in 'gt mq list' until the parent
+has merged.
+
+When the parent changes (amended after review, rebased onto a newer default
+branch) or merges, run 'gt stack restack' to move the branches above it.
+
+Examples:
+  gt sling gt-def gastown --stack-on Toast   # Build on Toast's branch
+  gt stack list                              # Stacks in the current rig
+  gt stack restack gastown                   # Rebase stacks onto their parents
+  gt stack track crew/max-auth --on crew/max-api   # Track a hand-made stack`,
+	RunE: requireSubcommand,
+}
+
+var stackListCmd = &cobra.Command{
+	Use:   "list [rig]",
+	Short: "Show a rig's stacked branches in merge order",
+	Long: `Show each stack bottom-up, the order its branches merge in, with the
+state of every stacked branch:
+
+  waiting          parent is unmerged and the branch is on its latest commit
+  behind parent    parent has new commits; restack to pick them up
+  parent merged    rebase onto the default branch with 'gt stack restack'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runStackList,
+}
+
+var stackRestackCmd = &cobra.Command{
+	Use:   "restack [rig] [branch]",
+	Short: "Rebase stacked branches onto their updated or merged parents",
+	Long: `Rebase stacked branches in merge order, so that whole stacks move:
+
+  - A branch whose parent has merged is rebased onto the default branch
+    and leaves the stack.
+  - A branch whose parent has new commits is rebased onto the parent's tip.
+
+Only the branch's own commits are replayed, even when the parent was
+rewritten. Branches are rebased in the worktree that has them checked out,
+which must be clean, and force-pushed when they are on origin. A conflict
+aborts that branch's rebase and leaves it as it was.
+
+Examples:
+  gt stack restack                         # Every stack in the current rig
+  gt stack restack gastown polecat/Nux-m3x # One branch
+  gt stack restack --dry-run`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runStackRestack,
+}
+
+var stackTrackCmd = &cobra.Command{
+	Use:   "track <branch> --on <polecat|branch>",
+	Short: "Record that a branch is stacked on another",
+	Long: `Record a stack created by hand, for example by a crew worker who
+branched from a polecat's work. The branch must be checked out in one of
+the rig's worktrees to be restacked.
+
+Examples:
+  gt stack track crew/max-auth --on Toast
+  gt stack track feature-b --on feature-a --rig gastown`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStackTrack,
+}
+
+func init() {
+	stackListCmd.Flags().BoolVar(&stackListJSON, "json", false, "Output as JSON")
+
+	stackRestackCmd.Flags().BoolVarP(&stackRestackDry, "dry-run", "n", false, "Show what would be rebased")
+
+	stackTrackCmd.Flags().StringVar(&stackTrackOn, "on", "", "Polecat or branch the branch is built on (required)")
+	stackTrackCmd.Flags().StringVar(&stackTrackRigName, "rig", "", "Rig (default: rig of the current directory)")
+	_ = stackTrackCmd.MarkFlagRequired("on")
+
+	stackCmd.AddCommand(stackListCmd)
+	stackCmd.AddCommand(stackRestackCmd)
+	stackCmd.AddCommand(stackTrackCmd)
+	rootCmd.AddCommand(stackCmd)
+}
+
+// Stack entry states, as shown by 'gt stack list'.
+const (
+	stackWaiting      = "waiting"
+	stackBehind       = "behind parent"
+	stackParentMerged = "parent merged"
+)
+
+// stackEntryJSON is the --json form of a stack entry.
+type stackEntryJSON struct {
+	stack.Entry
+	Depth int    `json:"depth"`
+	State string `json:"state"`
+}
+
+// stackState reports where a stacked branch stands against its parent.
+func stackState(repo *git.Git, bd *beads.Beads, e stack.Entry) string {
+	if stackParentHasMerged(repo, bd, e) {
+		return stackParentMerged
+	}
+	if tip, err := repo.Rev(e.Parent); err == nil && tip != e.Base {
+		return stackBehind
+	}
+	return stackWaiting
+}
+
+// stackParentHasMerged reports whether the work a branch is stacked on has
+// landed. The Refinery closes an issue once its branch merges; without an
+// issue to go by, a parent branch that is gone is taken as merged.
+func stackParentHasMerged(repo *git.Git, bd *beads.Beads, e stack.Entry) bool {
+	if e.ParentIssue != "" {
+		issue, err := bd.Show(e.ParentIssue)
+		return err == nil && issue.Status == "closed"
+	}
+	exists, err := repo.BranchExists(e.Parent)
+	return err == nil && !exists
+}
+
+// stackRepo returns the rig's shared repository and beads.
+func stackRepo(r *rig.Rig) (*git.Git, *beads.Beads, error) {
+	repo, err := polecat.NewManager(r, git.NewGit(r.Path)).RepoBase()
+	if err != nil {
+		return nil, nil, fmt.Errorf("finding repo base: %w", err)
+	}
+	return repo, beads.New(r.BeadsPath()), nil
+}
+
+func runStackList(cmd *cobra.Command, args []string) error {
+	var rigName string
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	_, r, err := getRigOrCwd(rigName)
+	if err != nil {
+		return err
+	}
+	s, err := stack.Load(r.Path)
+	if err != nil {
+		return err
+	}
+	repo, bd, err := stackRepo(r)
+	if err != nil {
+		return err
+	}
+
+	ordered := s.Ordered()
+	if stackListJSON {
+		out := make([]stackEntryJSON, 0, len(ordered))
+		for _, e := range ordered {
+			out = append(out, stackEntryJSON{Entry: e, Depth: s.Depth(e.Branch), State: stackState(repo, bd, e)})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	if len(ordered) == 0 {
+		fmt.Printf("%s No stacked branches in %s\n", style.Dim.Render("○"), r.Name)
+		return nil
+	}
+
+	// Print each stack from its unstacked root, children indented below
+	// their parent.
+	var printed = make(map[string]bool)
+	var printTree func(branch string, depth int)
+	printTree = func(branch string, depth int) {
+		for _, e := range s.Children(branch) {
+			if printed[e.Branch] {
+				continue
+			}
+			printed[e.Branch] = true
+			fmt.Printf("%s└─ %s%s  %s\n", strings.Repeat("   ", depth), e.Branch,
+				stackIssueLabel(e.Issue), stackStateLabel(stackState(repo, bd, e)))
+			printTree(e.Branch, depth+1)
+		}
+	}
+	for _, e := range ordered {
+		if _, stacked := s.Get(e.Parent); stacked || printed[e.Parent] {
+			continue
+		}
+		printed[e.Parent] = true
+		fmt.Printf("%s%s\n", style.Bold.Render(e.Parent), stackIssueLabel(e.ParentIssue))
+		printTree(e.Parent, 0)
+	}
+	return nil
+}
+
+func stackIssueLabel(issue string) string {
+	if issue == "" {
+		return ""
+	}
+	return " " + style.Dim.Render("("+issue+")")
+}
+
+func stackStateLabel(state string) string {
+	switch state {
+	case stackParentMerged:
+		return style.Warning.Render(state + " - restack")
+	case stackBehind:
+		return style.Warning.Render(state)
+	default:
+		return style.Dim.Render(state)
+	}
+}
+
+func runStackRestack(cmd *cobra.Command, args []string) error {
+	var rigName, only string
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	if len(args) > 1 {
+		only = args[1]
+	}
+	_, r, err := getRigOrCwd(rigName)
+	if err != nil {
+		return err
+	}
+	s, err := stack.Load(r.Path)
+	if err != nil {
+		return err
+	}
+	if only != "" {
+		if _, ok := s.Get(only); !ok {
+			return fmt.Errorf("%s is not a stacked branch in %s", only, r.Name)
+		}
+	}
+	repo, bd, err := stackRepo(r)
+	if err != nil {
+		return err
+	}
+	if err := repo.Fetch("origin"); err != nil {
+		style.PrintWarning("could not fetch origin: %v", err)
+	}
+	worktrees, err := repo.WorktreeList()
+	if err != nil {
+		return fmt.Errorf("listing worktrees: %w", err)
+	}
+
+	changed := make(map[string]*stack.Entry) // branch -> new entry, nil to remove
+	failed := 0
+	for _, e := range s.Ordered() {
+		if only != "" && e.Branch != only {
+			continue
+		}
+		updated, err := restackEntry(repo, bd, r, worktrees, e)
+		if err != nil {
+			failed++
+			fmt.Printf("%s %s: %v\n", style.Error.Render("✗"), e.Branch, err)
+			continue
+		}
+		if updated != nil && updated.Base == e.Base && updated.Parent == e.Parent {
+			fmt.Printf("%s %s is up to date with %s\n", style.Dim.Render("○"), e.Branch, e.Parent)
+			continue
+		}
+		changed[e.Branch] = updated
+	}
+
+	if !stackRestackDry && len(changed) > 0 {
+		err := stack.Update(r.Path, func(s *stack.Stacks) error {
+			for branch, e := range changed {
+				if e == nil {
+					s.Remove(branch)
+				} else if err := s.Add(*e); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return partialFailure("%d branch(es) could not be restacked", failed)
+	}
+	return nil
+}
+
+// restackEntry rebases one stacked branch onto its parent, or onto the
+// default branch once the parent has merged. It returns the entry to
+// keep, or nil when the branch has left the stack.
+func restackEntry(repo *git.Git, bd *beads.Beads, r *rig.Rig, worktrees []git.Worktree, e stack.Entry) (*stack.Entry, error) {
+	merged := stackParentHasMerged(repo, bd, e)
+	onto := e.Parent
+	if merged {
+		onto = "origin/" + r.DefaultBranch()
+	}
+	ontoSHA, err := repo.Rev(onto)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", onto, err)
+	}
+	if !merged && ontoSHA == e.Base {
+		return &e, nil
+	}
+
+	var worktree string
+	for _, wt := range worktrees {
+		if wt.Branch == e.Branch {
+			worktree = wt.Path
+			break
+		}
+	}
+	if worktree == "" {
+		return nil, fmt.Errorf("not checked out in any worktree of %s", r.Name)
+	}
+	wg := git.NewGit(worktree)
+	if dirty, err := wg.HasUncommittedChanges(); err != nil || dirty {
+		return nil, fmt.Errorf("worktree %s has uncommitted changes", worktree)
+	}
+
+	if stackRestackDry {
+		fmt.Printf("Would rebase %s onto %s\n", e.Branch, onto)
+		if merged {
+			return nil, nil
+		}
+		e.Base = ontoSHA
+		return &e, nil
+	}
+
+	if err := wg.RebaseOnto(onto, e.Base); err != nil {
+		_ = wg.AbortRebase()
+		if errors.Is(err, git.ErrMergeConflict) || errors.Is(err, git.ErrRebaseConflict) {
+			return nil, fmt.Errorf("conflicts rebasing onto %s; resolve by hand in %s", onto, worktree)
+		}
+		return nil, fmt.Errorf("rebasing onto %s: %w", onto, err)
+	}
+	if onOrigin, err := wg.RemoteBranchExists("origin", e.Branch); err == nil && onOrigin {
+		if err := wg.Push("origin", e.Branch, true); err != nil {
+			style.PrintWarning("rebased %s but could not push it: %v", e.Branch, err)
+		}
+	}
+
+	if merged {
+		fmt.Printf("%s %s rebased onto %s (%s merged)\n", style.Success.Render("✓"), e.Branch, onto, e.Parent)
+		return nil, nil
+	}
+	fmt.Printf("%s %s rebased onto %s\n", style.Success.Render("✓"), e.Branch, e.Parent)
+	e.Base = ontoSHA
+	e.RestackedAt = time.Now()
+	return &e, nil
+}
+
+func runStackTrack(cmd *cobra.Command, args []string) error {
+	branch := args[0]
+	_, r, err := getRigOrCwd(stackTrackRigName)
+	if err != nil {
+		return err
+	}
+	mgr := polecat.NewManager(r, git.NewGit(r.Path))
+	parent, parentIssue, err := mgr.StackParent(stackTrackOn)
+	if err != nil {
+		return err
+	}
+	repo, err := mgr.RepoBase()
+	if err != nil {
+		return fmt.Errorf("finding repo base: %w", err)
+	}
+	base, err := repo.MergeBase(branch, parent)
+	if err != nil {
+		return fmt.Errorf("%s and %s share no history: %w", branch, parent, err)
+	}
+	err = stack.Update(r.Path, func(s *stack.Stacks) error {
+		return s.Add(stack.Entry{Branch: branch, Parent: parent, Base: base, ParentIssue: parentIssue})
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s is stacked on %s\n", style.Success.Render("✓"), branch, parent)
+	return nil
+}
+
+// stackedOn returns the stack entry of a branch built on unmerged work.
+func stackedOn(rigPath, branch string) (*stack.Entry, bool) {
+	s, err := stack.Load(rigPath)
+	if err != nil {
+		return nil, false
+	}
+	return s.Get(branch)
+}
+
+// holdStackedMR makes a stacked branch's merge request wait for its
+// parent: the MR depends on the parent's issue, which the Refinery closes
+// when the parent merges, so the queue skips it until then.
+func holdStackedMR(bd *beads.Beads, e *stack.Entry, mrID string) {
+	if e.ParentIssue == "" {
+		style.PrintWarning("stacked on %s, which has no tracked issue; merge it first", e.Parent)
+		return
+	}
+	if err := bd.AddDependency(mrID, e.ParentIssue); err != nil {
+		style.PrintWarning("could not make %s wait for %s: %v", mrID, e.ParentIssue, err)
+		return
+	}
+	fmt.Printf("  Stacked on: %s (merges after %s)\n", e.Parent, e.ParentIssue)
+}
@@ -9,11 +9,13 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/agentrt"
 	"github.com/ctiospl/gastown/internal/claude"
 	"github.com/ctiospl/gastown/internal/config"
 	"github.com/ctiospl/gastown/internal/constants"
 	"github.com/ctiospl/gastown/internal/crew"
 	"github.com/ctiospl/gastown/internal/git"
+	"github.com/ctiospl/gastown/internal/outputlog"
 	"github.com/ctiospl/gastown/internal/polecat"
 	"github.com/ctiospl/gastown/internal/rig"
 	"github.com/ctiospl/gastown/internal/session"
@@ -345,7 +347,7 @@ func ensureRefinerySession(rigName string, r *rig.Rig) (bool, error) {
 
 	// Launch Claude directly (no respawn loop - daemon handles restart)
 	// Export GT_ROLE and BD_ACTOR in the command since tmux SetEnvironment only affects new panes
-	if err := t.SendKeys(sessionName, config.BuildAgentStartupCommand("refinery", bdActor, "", "")); err != nil {
+	if err := t.SendKeys(sessionName, config.BuildRigAgentStartupCommand("refinery", bdActor, r.Path, "")); err != nil {
 		return false, fmt.Errorf("sending command: %w", err)
 	}
 
@@ -771,22 +773,23 @@ func runStartCrew(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("checking session: %w", err)
 	}
 
+	// The runtime adapter (see the agentrt package) launches and nudges the
+	// crew member's runtime: its --runtime, its template's, or the rig's
+	address := fmt.Sprintf("%s/crew/%s", rigName, name)
+	rt := agentrt.ForAgent(townRoot, address)
+	typedPrompt := config.CrewTypedPrompt(rigName, name, r.Path, "")
+
 	if hasSession {
-		// Session exists - check if Claude is still running
-		if !t.IsClaudeRunning(sessionID) {
-			// Claude has exited, restart it
-			fmt.Printf("Session exists, restarting Claude...\n")
+		// Session exists - check if the runtime is still running
+		if !rt.Running(t, sessionID) {
+			// The runtime has exited, restart it, then prime
+			fmt.Printf("Session exists, restarting %s...\n", rt.Name())
 			claudeCmd := config.BuildCrewStartupCommand(rigName, name, r.Path, "")
-			if err := t.SendKeys(sessionID, claudeCmd); err != nil {
-				return fmt.Errorf("restarting claude: %w", err)
-			}
-			// Wait for Claude to start, then prime
-			shells := constants.SupportedShells
-			if err := t.WaitForCommand(sessionID, shells, constants.ClaudeStartTimeout); err != nil {
-				style.PrintWarning("Timeout waiting for Claude to start: %v", err)
+			if err := rt.Launch(t, sessionID, claudeCmd, typedPrompt); err != nil {
+				style.PrintWarning("%v", err)
 			}
 			time.Sleep(constants.ShutdownNotifyDelay)
-			if err := t.NudgeSession(sessionID, "gt prime"); err != nil {
+			if err := rt.Nudge(t, sessionID, "gt prime"); err != nil {
 				style.PrintWarning("Could not send prime command: %v", err)
 			}
 		} else {
@@ -812,28 +815,24 @@ func runStartCrew(cmd *cobra.Command, args []string) error {
 		theme := getThemeForRig(rigName)
 		_ = t.ConfigureGasTownSession(sessionID, theme, rigName, name, "crew")
 
+		// Capture its output to logs/output/ (non-fatal)
+		_ = outputlog.Start(t, townRoot, sessionID, address)
+
 		// Wait for shell to be ready after session creation
 		if err := t.WaitForShellReady(sessionID, constants.ShellReadyTimeout); err != nil {
 			return fmt.Errorf("waiting for shell: %w", err)
 		}
 
-		// Start claude with skip permissions and proper env vars for seance
+		// Start the runtime with skip permissions and proper env vars for seance
 		claudeCmd := config.BuildCrewStartupCommand(rigName, name, r.Path, "")
-		if err := t.SendKeys(sessionID, claudeCmd); err != nil {
-			return fmt.Errorf("starting claude: %w", err)
+		if err := rt.Launch(t, sessionID, claudeCmd, typedPrompt); err != nil {
+			style.PrintWarning("%v", err)
 		}
 
-		// Wait for Claude to start
-		shells := constants.SupportedShells
-		if err := t.WaitForCommand(sessionID, shells, constants.ClaudeStartTimeout); err != nil {
-			style.PrintWarning("Timeout waiting for Claude to start: %v", err)
-		}
-
-		// Give Claude time to initialize after process starts
+		// Give the runtime time to initialize after process starts
 		time.Sleep(constants.ShutdownNotifyDelay)
 
 		// Inject startup nudge for predecessor discovery via /resume
-		address := fmt.Sprintf("%s/crew/%s", rigName, name)
 		_ = session.StartupNudge(t, sessionID, session.StartupNudgeConfig{
 			Recipient: address,
 			Sender:    "human",
@@ -841,7 +840,7 @@ func runStartCrew(cmd *cobra.Command, args []string) error {
 		}) // Non-fatal: session works without nudge
 
 		// Send gt prime to initialize context
-		if err := t.NudgeSession(sessionID, "gt prime"); err != nil {
+		if err := rt.Nudge(t, sessionID, "gt prime"); err != nil {
 			style.PrintWarning("Could not send prime command: %v", err)
 		}
 
@@ -952,6 +951,9 @@ func startCrewMember(rigName, crewName, townRoot string) error {
 	theme := getThemeForRig(rigName)
 	_ = t.ConfigureGasTownSession(sessionID, theme, rigName, crewName, "crew")
 
+	// Capture its output to logs/output/ (non-fatal)
+	_ = outputlog.Start(t, townRoot, sessionID, rigName+"/crew/"+crewName)
+
 	// Set up C-b n/p keybindings for crew session cycling (non-fatal)
 	_ = t.SetCrewCycleBindings(sessionID)
 
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/ctiospl/gastown/internal/beads"
@@ -14,7 +15,9 @@ import (
 	"github.com/ctiospl/gastown/internal/constants"
 	"github.com/ctiospl/gastown/internal/crew"
 	"github.com/ctiospl/gastown/internal/git"
+	"github.com/ctiospl/gastown/internal/i18n"
 	"github.com/ctiospl/gastown/internal/mail"
+	"github.com/ctiospl/gastown/internal/metrics"
 	"github.com/ctiospl/gastown/internal/rig"
 	"github.com/ctiospl/gastown/internal/style"
 	"github.com/ctiospl/gastown/internal/tmux"
@@ -23,6 +26,7 @@ import (
 
 var statusJSON bool
 var statusFast bool
+var statusAll bool
 
 var statusCmd = &cobra.Command{
 	Use:     "status",
@@ -33,13 +37,18 @@ var statusCmd = &cobra.Command{
 
 Shows town name, registered rigs, active polecats, and witness status.
 
-Use --fast to skip mail lookups for faster execution.`,
+Use --fast to skip mail lookups for faster execution.
+
+Agents hidden entirely by a rig's ignore rules (an "ignore" rule with agents
+but no events, in <rig>/settings/config.json) are left out; use --all to
+include them.`,
 	RunE: runStatus,
 }
 
 func init() {
 	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Output as JSON")
 	statusCmd.Flags().BoolVar(&statusFast, "fast", false, "Skip mail lookups for faster execution")
+	statusCmd.Flags().BoolVar(&statusAll, "all", false, "Include agents hidden by rig ignore rules")
 	rootCmd.AddCommand(statusCmd)
 }
 
@@ -75,6 +84,9 @@ type AgentRuntime struct {
 	State        string `json:"state,omitempty"`         // Agent state from agent bead
 	UnreadMail   int    `json:"unread_mail"`             // Number of unread messages
 	FirstSubject string `json:"first_subject,omitempty"` // Subject of first unread message
+
+	// Metrics is what the agent reports in its worktree metrics file, if any
+	Metrics *metrics.Metrics `json:"metrics,omitempty"`
 }
 
 // RigStatus represents status of a single rig.
@@ -270,6 +282,9 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	go func() {
 		defer wg.Done()
 		status.Agents = discoverGlobalAgents(allSessions, allAgentBeads, allHookBeads, mailRouter, statusFast)
+		for i := range status.Agents {
+			status.Agents[i].Metrics = readAgentMetrics(townRoot, status.Agents[i].Address)
+		}
 	}()
 
 	// Process all rigs in parallel
@@ -344,7 +359,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	// Show bd daemon warning at the end if there were issues
 	if bdWarning != "" {
 		fmt.Printf("%s %s\n", style.Warning.Render("⚠"), bdWarning)
-		fmt.Printf("  Run 'bd daemon killall && bd daemon --start' to restart daemons\n")
+		fmt.Printf("  %s\n", i18n.T("status.bd_restart"))
 	}
 
 	return nil
@@ -358,7 +373,7 @@ func outputStatusJSON(status TownStatus) error {
 
 func outputStatusText(status TownStatus) error {
 	// Header
-	fmt.Printf("%s %s\n", style.Bold.Render("Town:"), status.Name)
+	fmt.Printf("%s %s\n", style.Bold.Render(i18n.T("status.town")), status.Name)
 	fmt.Printf("%s\n\n", style.Dim.Render(status.Location))
 
 	// Overseer info
@@ -369,9 +384,9 @@ func outputStatusText(status TownStatus) error {
 		} else if status.Overseer.Username != "" && status.Overseer.Username != status.Overseer.Name {
 			overseerDisplay = fmt.Sprintf("%s (@%s)", status.Overseer.Name, status.Overseer.Username)
 		}
-		fmt.Printf("👤 %s %s\n", style.Bold.Render("Overseer:"), overseerDisplay)
+		statusf("👤 %s %s\n", style.Bold.Render(i18n.T("status.overseer")), overseerDisplay)
 		if status.Overseer.UnreadMail > 0 {
-			fmt.Printf("   📬 %d unread\n", status.Overseer.UnreadMail)
+			statusf("   📬 %s\n", i18n.T("status.unread", status.Overseer.UnreadMail))
 		}
 		fmt.Println()
 	}
@@ -395,24 +410,38 @@ func outputStatusText(status TownStatus) error {
 		if icon == "" {
 			icon = roleIcons[agent.Name]
 		}
-		fmt.Printf("%s %s\n", icon, style.Bold.Render(capitalizeFirst(agent.Name)))
+		statusf("%s %s\n", icon, style.Bold.Render(capitalizeFirst(agent.Name)))
 		renderAgentDetails(agent, "   ", nil, status.Location)
 		fmt.Println()
 	}
 
 	if len(status.Rigs) == 0 {
-		fmt.Printf("%s\n", style.Dim.Render("No rigs registered. Use 'gt rig add' to add one."))
+		fmt.Printf("%s\n", style.Dim.Render(i18n.T("status.no_rigs")))
 		return nil
 	}
 
+	var ignores config.IgnoreRules
+	if !statusAll {
+		ignores = config.LoadIgnoreRules(status.Location)
+	}
+	hidden := 0
+
 	// Rigs
 	for _, r := range status.Rigs {
 		// Rig header with separator
-		fmt.Printf("─── %s ───────────────────────────────────────────\n\n", style.Bold.Render(r.Name+"/"))
+		if style.Accessible() {
+			fmt.Printf("%s\n\n", i18n.T("status.rig", r.Name+"/"))
+		} else {
+			fmt.Printf("─── %s ───────────────────────────────────────────\n\n", style.Bold.Render(r.Name+"/"))
+		}
 
 		// Group agents by role
 		var witnesses, refineries, crews, polecats []AgentRuntime
 		for _, agent := range r.Agents {
+			if ignores.HidesAgent(agent.Address) {
+				hidden++
+				continue
+			}
 			switch agent.Role {
 			case "witness":
 				witnesses = append(witnesses, agent)
@@ -427,7 +456,7 @@ func outputStatusText(status TownStatus) error {
 
 		// Witness
 		if len(witnesses) > 0 {
-			fmt.Printf("%s %s\n", roleIcons["witness"], style.Bold.Render("Witness"))
+			statusf("%s %s\n", roleIcons["witness"], style.Bold.Render(i18n.T("status.witness")))
 			for _, agent := range witnesses {
 				renderAgentDetails(agent, "   ", r.Hooks, status.Location)
 			}
@@ -436,7 +465,7 @@ func outputStatusText(status TownStatus) error {
 
 		// Refinery
 		if len(refineries) > 0 {
-			fmt.Printf("%s %s\n", roleIcons["refinery"], style.Bold.Render("Refinery"))
+			statusf("%s %s\n", roleIcons["refinery"], style.Bold.Render(i18n.T("status.refinery")))
 			for _, agent := range refineries {
 				renderAgentDetails(agent, "   ", r.Hooks, status.Location)
 			}
@@ -444,13 +473,13 @@ func outputStatusText(status TownStatus) error {
 			if r.MQ != nil {
 				mqParts := []string{}
 				if r.MQ.Pending > 0 {
-					mqParts = append(mqParts, fmt.Sprintf("%d pending", r.MQ.Pending))
+					mqParts = append(mqParts, i18n.T("status.mq_pending", r.MQ.Pending))
 				}
 				if r.MQ.InFlight > 0 {
-					mqParts = append(mqParts, style.Warning.Render(fmt.Sprintf("%d in-flight", r.MQ.InFlight)))
+					mqParts = append(mqParts, style.Warning.Render(i18n.T("status.mq_in_flight", r.MQ.InFlight)))
 				}
 				if r.MQ.Blocked > 0 {
-					mqParts = append(mqParts, style.Dim.Render(fmt.Sprintf("%d blocked", r.MQ.Blocked)))
+					mqParts = append(mqParts, style.Dim.Render(i18n.T("status.mq_blocked", r.MQ.Blocked)))
 				}
 				if len(mqParts) > 0 {
 					// Add state indicator
@@ -461,10 +490,20 @@ func outputStatusText(status TownStatus) error {
 					case "blocked":
 						stateIcon = style.Error.Render("○")
 					}
+					if style.Accessible() {
+						// The icon's color is the state; say it instead
+						stateIcon = i18n.T("status.mq_idle") + ":"
+						switch r.MQ.State {
+						case "processing":
+							stateIcon = i18n.T("status.mq_processing") + ":"
+						case "blocked":
+							stateIcon = i18n.T("status.mq_blocked_state") + ":"
+						}
+					}
 					// Add health warning if stale
 					healthSuffix := ""
 					if r.MQ.Health == "stale" {
-						healthSuffix = style.Error.Render(" [stale]")
+						healthSuffix = style.Error.Render(" " + i18n.T("status.stale"))
 					}
 					fmt.Printf("   MQ: %s %s%s\n", stateIcon, strings.Join(mqParts, ", "), healthSuffix)
 				}
@@ -474,7 +513,7 @@ func outputStatusText(status TownStatus) error {
 
 		// Crew
 		if len(crews) > 0 {
-			fmt.Printf("%s %s (%d)\n", roleIcons["crew"], style.Bold.Render("Crew"), len(crews))
+			statusf("%s %s (%d)\n", roleIcons["crew"], style.Bold.Render(i18n.T("status.crew")), len(crews))
 			for _, agent := range crews {
 				renderAgentDetails(agent, "   ", r.Hooks, status.Location)
 			}
@@ -483,7 +522,7 @@ func outputStatusText(status TownStatus) error {
 
 		// Polecats
 		if len(polecats) > 0 {
-			fmt.Printf("%s %s (%d)\n", roleIcons["polecat"], style.Bold.Render("Polecats"), len(polecats))
+			statusf("%s %s (%d)\n", roleIcons["polecat"], style.Bold.Render(i18n.T("status.polecats")), len(polecats))
 			for _, agent := range polecats {
 				renderAgentDetails(agent, "   ", r.Hooks, status.Location)
 			}
@@ -492,10 +531,14 @@ func outputStatusText(status TownStatus) error {
 
 		// No agents
 		if len(witnesses) == 0 && len(refineries) == 0 && len(crews) == 0 && len(polecats) == 0 {
-			fmt.Printf("   %s\n\n", style.Dim.Render("(no agents)"))
+			fmt.Printf("   %s\n\n", style.Dim.Render(i18n.T("status.no_agents")))
 		}
 	}
 
+	if hidden > 0 {
+		fmt.Printf("%s\n", style.Dim.Render(i18n.T("status.hidden_agents", hidden)))
+	}
+
 	return nil
 }
 
@@ -517,19 +560,19 @@ func renderAgentDetails(agent AgentRuntime, indent string, hooks []AgentHookInfo
 	switch {
 	case beadSaysRunning && sessionExists:
 		// Normal running state - session exists and bead agrees
-		statusStr = style.Success.Render("running")
+		statusStr = style.Success.Render(i18n.T("status.running"))
 	case beadSaysRunning && !sessionExists:
 		// Bead thinks running but session is gone - stale bead state
-		statusStr = style.Error.Render("running")
-		stateInfo = style.Warning.Render(" [dead]")
+		statusStr = style.Error.Render(i18n.T("status.running"))
+		stateInfo = style.Warning.Render(" " + i18n.T("status.dead"))
 	case !beadSaysRunning && sessionExists:
 		// Session exists but bead says stopped/dead - mismatch!
 		// This is the key case: tmux says alive, bead says dead/stopped
-		statusStr = style.Success.Render("running")
+		statusStr = style.Success.Render(i18n.T("status.running"))
 		stateInfo = style.Warning.Render(" [bead: " + beadState + "]")
 	default:
 		// Both agree: stopped
-		statusStr = style.Error.Render("stopped")
+		statusStr = style.Error.Render(i18n.T("status.stopped"))
 	}
 
 	// Add agent state info if not already shown and state is interesting
@@ -566,7 +609,7 @@ func renderAgentDetails(agent AgentRuntime, indent string, hooks []AgentHookInfo
 	fmt.Printf("%s%s %s%s\n", indent, style.Dim.Render(agentBeadID), statusStr, stateInfo)
 
 	// Line 2: Hook bead (pinned work)
-	hookStr := style.Dim.Render("(none)")
+	hookStr := style.Dim.Render(i18n.T("status.none"))
 	hookBead := agent.HookBead
 	hookTitle := agent.WorkTitle
 
@@ -592,18 +635,35 @@ func renderAgentDetails(agent AgentRuntime, indent string, hooks []AgentHookInfo
 		hookStr = truncateWithEllipsis(hookTitle, 50)
 	}
 
-	fmt.Printf("%s  hook: %s\n", indent, hookStr)
+	statusf("%s  %s %s\n", indent, i18n.T("status.hook"), hookStr)
 
-	// Line 3: Mail (if any unread)
+	// Line 3: Self-reported progress from the agent's metrics file
+	if m := agent.Metrics; m != nil {
+		if summary := m.Summary(); summary != "" {
+			progressStr := truncateWithEllipsis(summary, 60)
+			if m.Stale(time.Now()) {
+				progressStr = style.Dim.Render(progressStr + " " + i18n.T("status.stale"))
+			}
+			fmt.Printf("%s  %s %s\n", indent, i18n.T("status.progress"), progressStr)
+		}
+	}
+
+	// Line 4: Mail (if any unread)
 	if agent.UnreadMail > 0 {
-		mailStr := fmt.Sprintf("📬 %d unread", agent.UnreadMail)
+		mailStr := "📬 " + i18n.T("status.unread", agent.UnreadMail)
 		if agent.FirstSubject != "" {
-			mailStr = fmt.Sprintf("📬 %d unread → %s", agent.UnreadMail, truncateWithEllipsis(agent.FirstSubject, 35))
+			mailStr = fmt.Sprintf("📬 %s → %s", i18n.T("status.unread", agent.UnreadMail), truncateWithEllipsis(agent.FirstSubject, 35))
 		}
-		fmt.Printf("%s  mail: %s\n", indent, mailStr)
+		statusf("%s  %s %s\n", indent, i18n.T("status.mail"), mailStr)
 	}
 }
 
+// statusf prints a status line that has symbols or emoji of its own, in
+// plain text when output is accessible (see style.Plain).
+func statusf(format string, args ...interface{}) {
+	fmt.Print(style.Plain(fmt.Sprintf(format, args...)))
+}
+
 // formatHookInfo formats the hook bead and title for display
 func formatHookInfo(hookBead, title string, maxLen int) string {
 	if hookBead == "" {
@@ -884,6 +944,8 @@ func discoverRigAgents(allSessions map[string]bool, r *rig.Rig, crews []string,
 				populateMailInfo(&agent, mailRouter)
 			}
 
+			agent.Metrics = readAgentMetrics(townRoot, d.address)
+
 			agents[idx] = agent
 		}(i, def)
 	}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/health"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/supervise"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var superviseCmd = &cobra.Command{
+	Use:     "supervise <session>",
+	GroupID: GroupAgents,
+	Short:   "Restart a crashed agent under its rig's restart policy",
+	Long: `Restart the agent of a crashed tmux session, after the backoff its rig's
+restart policy calls for.
+
+gt log crash runs this in the background for each crash it records (the
+pane-died hook of polecat sessions calls gt log crash), so there is rarely
+a reason to run it by hand. Policies are set per rig, under "restart" in
+settings/config.json, with overrides for some of its agents:
+
+  "restart": {
+    "enabled": true,
+    "backoff": "10s",
+    "max_backoff": "5m",
+    "max_restarts": 5,
+    "reset_after": "1h",
+    "agents": {
+      "polecats/*": {"enabled": true, "max_restarts": 3},
+      "crew/max":   {"enabled": false}
+    }
+  }
+
+Each crash in a row doubles the wait before the restart, from backoff up
+to max_backoff. A restarted agent that stays up for reset_after ends the
+run, so its next crash waits backoff again. After max_restarts restarts in
+a row the agent is left down, and a "restart" event with error severity
+says so; the next crash after it is started by hand begins a new run.
+
+Each restart is logged as a "restart" event:
+
+  gt log --type restart
+
+An agent that is running again by the time the backoff ends is left alone.
+
+Examples:
+  gt supervise gt-gastown-Toast`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSupervise,
+}
+
+func init() {
+	rootCmd.AddCommand(superviseCmd)
+}
+
+func runSupervise(cmd *cobra.Command, args []string) error {
+	sessionName := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	id, policy, ok, err := supervisedPolicy(townRoot, sessionName)
+	if err != nil {
+		return err
+	}
+	agent := id.Address()
+	if !ok {
+		fmt.Printf("%s has no restart policy; not restarting\n", agent)
+		return nil
+	}
+	cmd.SilenceUsage = true
+
+	events, err := townlog.ReadEvents(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+	decision := supervise.Decide(events, agent, policy, time.Now())
+	logger := townlog.NewLogger(townRoot)
+	if decision.GiveUp {
+		_ = logger.LogEvent(townlog.Event{
+			Timestamp: time.Now(),
+			Type:      supervise.EventRestart,
+			Agent:     agent,
+			Context:   supervise.GiveUpContext(policy),
+			Severity:  townlog.SeverityError,
+		})
+		fmt.Printf("%s %s: %s\n", style.Error.Render("✗"), agent, supervise.GiveUpContext(policy))
+		return NewSilentExit(1)
+	}
+
+	fmt.Printf("Restarting %s in %s (attempt %d/%d)\n", agent, decision.Delay, decision.Attempt, policy.MaxRestarts)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	select {
+	case <-time.After(decision.Delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	t := tmux.NewTmux()
+	if running, err := agentRunning(t, sessionName); err != nil {
+		return err
+	} else if running {
+		fmt.Printf("%s is running again; not restarting\n", agent)
+		return nil
+	}
+	_ = t.KillSession(sessionName) // a dead pane left by remain-on-exit
+
+	note := supervise.RestartContext(decision.Attempt, policy, decision.Delay)
+	e := townlog.Event{Timestamp: time.Now(), Type: supervise.EventRestart, Agent: agent, Context: note}
+	_, wakeErr := session.Wake(t, sessionName)
+	if wakeErr != nil {
+		e.Context = fmt.Sprintf("%s failed: %v", note, wakeErr)
+		e.Severity = townlog.SeverityError
+	}
+	if err := logger.LogEvent(e); err != nil {
+		style.PrintWarning("could not log restart: %v", err)
+	}
+	if wakeErr != nil {
+		return wakeErr
+	}
+	fmt.Printf("%s Restarted %s\n", style.Success.Render("✓"), agent)
+	return nil
+}
+
+// supervisedPolicy returns the agent of a session and its rig's restart
+// policy for it, if it has one.
+func supervisedPolicy(townRoot, sessionName string) (*session.AgentIdentity, supervise.Policy, bool, error) {
+	id, err := session.ParseSessionName(sessionName)
+	if err != nil {
+		return nil, supervise.Policy{}, false, err
+	}
+	if id.Rig == "" {
+		return id, supervise.Policy{}, false, nil // town-level agents are the daemon's to restart
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, id.Rig)))
+	if err != nil {
+		return id, supervise.Policy{}, false, nil
+	}
+	name := strings.TrimPrefix(id.Address(), id.Rig+"/")
+	policy, ok := supervise.PolicyFor(settings.Restart, name)
+	return id, policy, ok, nil
+}
+
+// agentRunning reports whether an agent process runs in the session: the
+// session exists, its pane is alive, and it is not back at a shell.
+func agentRunning(t *tmux.Tmux, sessionName string) (bool, error) {
+	exists, err := t.HasSession(sessionName)
+	if err != nil || !exists {
+		return false, err
+	}
+	state, err := t.GetPaneState(sessionName)
+	if err != nil {
+		return false, nil
+	}
+	status, _ := health.Classify(state, time.Now(), 0)
+	return status.Healthy(), nil
+}
+
+// startSupervise starts 'gt supervise' for a crashed session in the
+// background, if its rig restarts the agent.
+func startSupervise(townRoot, sessionName string) error {
+	if _, _, ok, err := supervisedPolicy(townRoot, sessionName); err != nil || !ok {
+		return err
+	}
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+	c := exec.Command(gtPath, "supervise", sessionName) //nolint:gosec // G204: session comes from the pane-died hook
+	c.Dir = townRoot
+	// A session of its own, so the backoff outlives the tmux hook.
+	c.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("starting supervise: %w", err)
+	}
+	return c.Process.Release()
+}
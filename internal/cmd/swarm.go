@@ -137,14 +137,26 @@ var swarmDispatchCmd = &cobra.Command{
 Finds the first unassigned task in the epic's ready front and slings it
 to an idle polecat in the rig.
 
+Idle polecats are ranked by affinity: a polecat that finished one of the
+task's dependencies (or an issue it mentions), recently changed files the
+task names, or recently worked on an issue with the same label gets the
+task, so it starts with warm context from its branches and checkpoint.
+History older than a day is ignored. Ties go to the first idle polecat.
+
 Examples:
   gt swarm dispatch gt-abc         # Dispatch next task from epic gt-abc
-  gt swarm dispatch gt-abc --rig greenplace  # Dispatch in specific rig`,
+  gt swarm dispatch gt-abc --rig greenplace  # Dispatch in specific rig
+  gt swarm dispatch gt-abc --explain         # Show every candidate's affinity
+  gt swarm dispatch gt-abc --no-affinity     # First idle polecat wins`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSwarmDispatch,
 }
 
-var swarmDispatchRig string
+var (
+	swarmDispatchRig        string
+	swarmDispatchExplain    bool
+	swarmDispatchNoAffinity bool
+)
 
 func init() {
 	// Create flags
@@ -163,6 +175,8 @@ func init() {
 
 	// Dispatch flags
 	swarmDispatchCmd.Flags().StringVar(&swarmDispatchRig, "rig", "", "Rig to dispatch in (auto-detected from epic if not specified)")
+	swarmDispatchCmd.Flags().BoolVar(&swarmDispatchExplain, "explain", false, "Show the affinity of every idle polecat")
+	swarmDispatchCmd.Flags().BoolVar(&swarmDispatchNoAffinity, "no-affinity", false, "Ignore affinity and take the first idle polecat")
 
 	// Add subcommands
 	swarmCmd.AddCommand(swarmCreateCmd)
@@ -492,9 +506,17 @@ func runSwarmDispatch(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Dispatch first unassigned task to first idle polecat
+	// Dispatch first unassigned task, preferring the polecat with the most
+	// relevant recent work
 	task := unassigned[0]
 	worker := idlePolecats[0]
+	if !swarmDispatchNoAffinity {
+		affinityTask := loadAffinityTask(foundRig, task.ID, task.Title)
+		histories := polecatMgr.RecentWork(idlePolecats, time.Now().Add(-polecat.AffinityWindow))
+		ranked := polecat.RankByAffinity(affinityTask, idlePolecats, histories)
+		worker = ranked[0].Polecat
+		printAffinityReport(task.ID, ranked, swarmDispatchExplain)
+	}
 	target := fmt.Sprintf("%s/%s", foundRig.Name, worker)
 
 	fmt.Printf("Dispatching %s to %s...\n", task.ID, target)
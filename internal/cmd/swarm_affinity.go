@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ctiospl/gastown/internal/beads"
+	"github.com/ctiospl/gastown/internal/polecat"
+	"github.com/ctiospl/gastown/internal/rig"
+	"github.com/ctiospl/gastown/internal/style"
+)
+
+// loadAffinityTask describes a task for affinity ranking. If the issue
+// cannot be read, only its ID and title take part.
+func loadAffinityTask(r *rig.Rig, id, title string) polecat.AffinityTask {
+	task := polecat.AffinityTask{ID: id, Title: title}
+	issue, err := beads.New(r.BeadsPath()).Show(id)
+	if err != nil {
+		return task
+	}
+	task.Description = issue.Description
+	task.Labels = issue.Labels
+	task.DependsOn = append(task.DependsOn, issue.DependsOn...)
+	for _, dep := range issue.Dependencies {
+		task.DependsOn = append(task.DependsOn, dep.ID)
+	}
+	return task
+}
+
+// printAffinityReport explains why a polecat was picked for a task. With
+// all set, every candidate is listed with its score.
+func printAffinityReport(taskID string, ranked []polecat.Affinity, all bool) {
+	best := ranked[0]
+	if best.Score == 0 {
+		fmt.Printf("%s No idle polecat has recent work related to %s; taking %s\n",
+			style.Dim.Render("○"), taskID, best.Polecat)
+	} else {
+		fmt.Printf("%s Affinity: %s (score %d) — %s\n",
+			style.Success.Render("●"), best.Polecat, best.Score, strings.Join(best.Reasons, "; "))
+	}
+	if !all {
+		return
+	}
+	fmt.Printf("\nCandidates for %s:\n", taskID)
+	for _, a := range ranked {
+		reasons := "no related recent work"
+		if len(a.Reasons) > 0 {
+			reasons = strings.Join(a.Reasons, "; ")
+		}
+		fmt.Printf("  %-12s %3d  %s\n", a.Polecat, a.Score, style.Dim.Render(reasons))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	townRegisterName string
+	townListJSON     bool
+)
+
+var townRegisterCmd = &cobra.Command{
+	Use:   "register [path]",
+	Short: "Add a town to this machine's town registry",
+	Long: `Add a town to the registry of towns on this machine.
+
+Registered towns are what 'gt log --all-towns' reads. 'gt install' registers
+new towns itself; use this for towns created before the registry existed.
+The town is named after its town.json unless --name is given. Registering
+a town again renames it.
+
+The registry is ~/.config/gt/towns.json ($XDG_CONFIG_HOME/gt/towns.json).
+
+Examples:
+  gt town register                  # The town containing the current directory
+  gt town register ~/gt-work --name work`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTownRegister,
+}
+
+var townUnregisterCmd = &cobra.Command{
+	Use:   "unregister <name|path>",
+	Short: "Remove a town from the town registry",
+	Long: `Remove a town from the registry of towns on this machine.
+
+The town itself is untouched; it is only left out of 'gt log --all-towns'.
+
+Examples:
+  gt town unregister work
+  gt town unregister ~/old-town`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTownUnregister,
+}
+
+var townListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered towns",
+	Long: `List the towns in this machine's town registry.
+
+Towns whose directory no longer holds a town are flagged as missing.
+
+Examples:
+  gt town list
+  gt town list --json`,
+	Args: cobra.NoArgs,
+	RunE: runTownList,
+}
+
+func init() {
+	townRegisterCmd.Flags().StringVar(&townRegisterName, "name", "", "Name to register the town under (default: its town.json name)")
+	townListCmd.Flags().BoolVar(&townListJSON, "json", false, "Output as JSON")
+
+	townCmd.AddCommand(townRegisterCmd)
+	townCmd.AddCommand(townUnregisterCmd)
+	townCmd.AddCommand(townListCmd)
+}
+
+func runTownRegister(cmd *cobra.Command, args []string) error {
+	var townRoot string
+	var err error
+	if len(args) > 0 {
+		townRoot, err = workspace.Find(args[0])
+		if err == nil && townRoot == "" {
+			err = fmt.Errorf("%s is not inside a Gas Town workspace", args[0])
+		}
+	} else {
+		townRoot, err = workspace.FindFromCwdOrError()
+	}
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	name := townRegisterName
+	if name == "" {
+		if name, err = workspace.GetTownName(townRoot); err != nil || name == "" {
+			name = filepath.Base(townRoot)
+		}
+	}
+	if err := config.RegisterTown(name, townRoot); err != nil {
+		return fmt.Errorf("registering town: %w", err)
+	}
+	fmt.Printf("%s Registered town %s (%s)\n", style.Success.Render("✓"), style.Bold.Render(name), townRoot)
+	return nil
+}
+
+func runTownUnregister(cmd *cobra.Command, args []string) error {
+	registry, err := config.LoadTownRegistry()
+	if err != nil {
+		return err
+	}
+	if !registry.Unregister(args[0]) {
+		return fmt.Errorf("no registered town %q (see 'gt town list')", args[0])
+	}
+	if err := config.SaveTownRegistry(registry); err != nil {
+		return err
+	}
+	fmt.Printf("%s Unregistered %s\n", style.Success.Render("✓"), args[0])
+	return nil
+}
+
+// registeredTownStatus is a registry entry with whether the town still exists.
+type registeredTownStatus struct {
+	config.RegisteredTown
+	Missing bool `json:"missing,omitempty"`
+}
+
+func runTownList(cmd *cobra.Command, args []string) error {
+	registry, err := config.LoadTownRegistry()
+	if err != nil {
+		return err
+	}
+	towns := make([]registeredTownStatus, 0, len(registry.Towns))
+	for _, t := range registry.Towns {
+		towns = append(towns, registeredTownStatus{RegisteredTown: t, Missing: !townExists(t.Path)})
+	}
+
+	if townListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(towns)
+	}
+
+	if len(towns) == 0 {
+		fmt.Printf("%s No registered towns (see 'gt town register')\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, t := range towns {
+		icon := style.Success.Render("●")
+		note := ""
+		if t.Missing {
+			icon = style.Error.Render("✗")
+			note = " " + style.Warning.Render("(missing)")
+		}
+		fmt.Printf("%s %-16s %s%s\n", icon, t.Name, style.Dim.Render(t.Path), note)
+	}
+	return nil
+}
+
+// townExists reports whether path is still the root of a town.
+func townExists(path string) bool {
+	_, err := os.Stat(filepath.Join(path, workspace.PrimaryMarker))
+	return err == nil
+}
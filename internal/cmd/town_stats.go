@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+var (
+	townStatsMonths int
+	townStatsCSV    bool
+	townStatsJSON   bool
+)
+
+var townStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show month-over-month town trends",
+	Long: `Show long-horizon trends computed from the town log and its archives.
+
+For each calendar month:
+  Done        tasks completed (done events)
+  Crash rate  crashes per agent session started (spawn or wake)
+  $/task      recorded session cost divided by tasks completed
+  Handoffs    average handoffs per completed task
+
+Month-over-month changes are shown for throughput and cost per task.
+Archived logs (logs/town.log.*, logs/archive/town*.log[.gz]) are read
+along with the live log. Costs come from session events recorded by
+'gt costs record'; months without cost data show "-".
+
+Examples:
+  gt town stats                 # Last 12 months
+  gt town stats --months 24     # Two years
+  gt town stats --csv > roi.csv # Export for a spreadsheet`,
+	RunE: runTownStats,
+}
+
+func init() {
+	townStatsCmd.Flags().IntVar(&townStatsMonths, "months", 12, "Number of months to include (0 for all)")
+	townStatsCmd.Flags().BoolVar(&townStatsCSV, "csv", false, "Output as CSV")
+	townStatsCmd.Flags().BoolVar(&townStatsJSON, "json", false, "Output as JSON")
+	townCmd.AddCommand(townStatsCmd)
+}
+
+// monthTrend holds one month's town metrics.
+type monthTrend struct {
+	Month           string  `json:"month"` // YYYY-MM
+	Sessions        int     `json:"sessions"`
+	Completed       int     `json:"completed"`
+	Crashes         int     `json:"crashes"`
+	Handoffs        int     `json:"handoffs"`
+	CostUSD         float64 `json:"cost_usd"`
+	CostRecorded    bool    `json:"cost_recorded"`
+	CrashRate       float64 `json:"crash_rate"`        // crashes / sessions
+	CostPerTask     float64 `json:"cost_per_task"`     // cost / completed
+	HandoffsPerTask float64 `json:"handoffs_per_task"` // handoffs / completed
+
+	// Month-over-month change as a fraction (0.12 = +12%); nil if the
+	// previous month has no baseline.
+	ThroughputChange  *float64 `json:"throughput_change,omitempty"`
+	CostPerTaskChange *float64 `json:"cost_per_task_change,omitempty"`
+}
+
+func runTownStats(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	events, err := townlog.ReadArchivedEvents(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading town logs: %w", err)
+	}
+	costs, err := querySessionEvents()
+	if err != nil {
+		// Trends without costs are still useful
+		fmt.Fprintf(os.Stderr, "%s could not read session costs: %v\n", style.WarningPrefix, err)
+	}
+
+	trends := computeMonthTrends(events, costs)
+	if townStatsMonths > 0 && len(trends) > townStatsMonths {
+		trends = trends[len(trends)-townStatsMonths:]
+	}
+
+	switch {
+	case townStatsJSON:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(trends)
+	case townStatsCSV:
+		return writeTrendsCSV(trends)
+	}
+
+	if len(trends) == 0 {
+		fmt.Println(style.Dim.Render("No events in the town log yet"))
+		return nil
+	}
+
+	fmt.Printf("\n%s Town trends\n\n", style.Bold.Render("📈"))
+	fmt.Printf("%-8s %6s %7s %8s %8s %7s %9s\n", "Month", "Done", "MoM", "Crash", "$/task", "MoM", "Handoffs")
+	fmt.Println(style.Dim.Render("─────────────────────────────────────────────────────────"))
+	for _, m := range trends {
+		costPerTask := "-"
+		if m.CostRecorded && m.Completed > 0 {
+			costPerTask = fmt.Sprintf("$%.2f", m.CostPerTask)
+		}
+		handoffs := "-"
+		if m.Completed > 0 {
+			handoffs = fmt.Sprintf("%.1f", m.HandoffsPerTask)
+		}
+		fmt.Printf("%-8s %6d %7s %7.1f%% %8s %7s %9s\n",
+			m.Month, m.Completed, formatChange(m.ThroughputChange), m.CrashRate*100,
+			costPerTask, formatChange(m.CostPerTaskChange), handoffs)
+	}
+	return nil
+}
+
+// computeMonthTrends buckets events and costs by local calendar month,
+// oldest first. Months between the first and last event are included even
+// if empty, so gaps are visible.
+func computeMonthTrends(events []townlog.Event, costs []CostEntry) []monthTrend {
+	byMonth := make(map[string]*monthTrend)
+	var first, last time.Time
+	bucket := func(t time.Time) *monthTrend {
+		t = t.Local()
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+		if t.After(last) {
+			last = t
+		}
+		key := t.Format("2006-01")
+		m := byMonth[key]
+		if m == nil {
+			m = &monthTrend{Month: key}
+			byMonth[key] = m
+		}
+		return m
+	}
+
+	for _, e := range events {
+		switch e.Type {
+		case townlog.EventSpawn, townlog.EventWake:
+			bucket(e.Timestamp).Sessions++
+		case townlog.EventDone:
+			bucket(e.Timestamp).Completed++
+		case townlog.EventCrash:
+			bucket(e.Timestamp).Crashes++
+		case townlog.EventHandoff:
+			bucket(e.Timestamp).Handoffs++
+		}
+	}
+	for _, c := range costs {
+		if c.EndedAt.IsZero() {
+			continue
+		}
+		m := bucket(c.EndedAt)
+		m.CostUSD += c.CostUSD
+		m.CostRecorded = true
+	}
+
+	if first.IsZero() {
+		return nil
+	}
+
+	var trends []monthTrend
+	var prev *monthTrend
+	for t := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.Local); !t.After(last); t = t.AddDate(0, 1, 0) {
+		m := byMonth[t.Format("2006-01")]
+		if m == nil {
+			m = &monthTrend{Month: t.Format("2006-01")}
+		}
+		if m.Sessions > 0 {
+			m.CrashRate = float64(m.Crashes) / float64(m.Sessions)
+		}
+		if m.Completed > 0 {
+			m.CostPerTask = m.CostUSD / float64(m.Completed)
+			m.HandoffsPerTask = float64(m.Handoffs) / float64(m.Completed)
+		}
+		if prev != nil {
+			if prev.Completed > 0 {
+				m.ThroughputChange = ratioChange(float64(m.Completed), float64(prev.Completed))
+			}
+			if prev.CostRecorded && prev.CostPerTask > 0 && m.CostRecorded && m.Completed > 0 {
+				m.CostPerTaskChange = ratioChange(m.CostPerTask, prev.CostPerTask)
+			}
+		}
+		trends = append(trends, *m)
+		prev = m
+	}
+	return trends
+}
+
+func ratioChange(cur, prev float64) *float64 {
+	change := cur/prev - 1
+	return &change
+}
+
+// formatChange renders a month-over-month change as "+12%", or "-".
+func formatChange(change *float64) string {
+	if change == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%+.0f%%", *change*100)
+}
+
+// writeTrendsCSV writes trends as CSV with a header row. Ratios are raw
+// fractions so spreadsheets can format them.
+func writeTrendsCSV(trends []monthTrend) error {
+	w := csv.NewWriter(os.Stdout)
+	_ = w.Write([]string{"month", "sessions", "completed", "crashes", "handoffs", "cost_usd",
+		"crash_rate", "cost_per_task", "handoffs_per_task", "throughput_change", "cost_per_task_change"})
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+	opt := func(v *float64) string {
+		if v == nil {
+			return ""
+		}
+		return f(*v)
+	}
+	for _, m := range trends {
+		cost, costPerTask := "", ""
+		if m.CostRecorded {
+			cost = f(m.CostUSD)
+			if m.Completed > 0 {
+				costPerTask = f(m.CostPerTask)
+			}
+		}
+		_ = w.Write([]string{
+			m.Month,
+			strconv.Itoa(m.Sessions),
+			strconv.Itoa(m.Completed),
+			strconv.Itoa(m.Crashes),
+			strconv.Itoa(m.Handoffs),
+			cost,
+			f(m.CrashRate),
+			costPerTask,
+			f(m.HandoffsPerTask),
+			opt(m.ThroughputChange),
+			opt(m.CostPerTaskChange),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+func TestComputeMonthTrends(t *testing.T) {
+	jan := time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local)
+	mar := time.Date(2026, 3, 5, 12, 0, 0, 0, time.Local)
+	ev := func(ts time.Time, typ townlog.EventType) townlog.Event {
+		return townlog.Event{Timestamp: ts, Type: typ, Agent: "gastown/Toast"}
+	}
+	events := []townlog.Event{
+		ev(jan, townlog.EventSpawn), ev(jan, townlog.EventSpawn),
+		ev(jan, townlog.EventCrash), ev(jan, townlog.EventHandoff),
+		ev(jan, townlog.EventDone), ev(jan, townlog.EventDone),
+		ev(mar, townlog.EventWake), ev(mar, townlog.EventDone),
+		ev(mar, townlog.EventDone), ev(mar, townlog.EventDone),
+	}
+	costs := []CostEntry{
+		{CostUSD: 4, EndedAt: jan},
+		{CostUSD: 3, EndedAt: mar},
+	}
+
+	trends := computeMonthTrends(events, costs)
+	if len(trends) != 3 {
+		t.Fatalf("got %d months, want 3 (gap included): %+v", len(trends), trends)
+	}
+	j, f, m := trends[0], trends[1], trends[2]
+	if j.Month != "2026-01" || f.Month != "2026-02" || m.Month != "2026-03" {
+		t.Errorf("months = %s %s %s", j.Month, f.Month, m.Month)
+	}
+	if j.CrashRate != 0.5 || j.CostPerTask != 2 || j.HandoffsPerTask != 0.5 {
+		t.Errorf("january = %+v", j)
+	}
+	if f.Completed != 0 || f.ThroughputChange == nil || *f.ThroughputChange != -1 {
+		t.Errorf("february = %+v", f)
+	}
+	// No baseline in an empty month
+	if m.ThroughputChange != nil || m.CostPerTaskChange != nil || m.CostPerTask != 1 {
+		t.Errorf("march = %+v", m)
+	}
+
+	if got := computeMonthTrends(nil, nil); got != nil {
+		t.Errorf("empty input = %+v, want nil", got)
+	}
+}
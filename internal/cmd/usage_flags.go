@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/metrics"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+// usageFlags are the --tokens-in, --tokens-out, --cost, and --duration
+// flags of commands that log a done or handoff event.
+type usageFlags struct {
+	cmd       *cobra.Command
+	tokensIn  int64
+	tokensOut int64
+	cost      float64
+	duration  time.Duration
+}
+
+// addUsageFlags registers the usage flags on cmd.
+func addUsageFlags(cmd *cobra.Command, f *usageFlags) {
+	f.cmd = cmd
+	cmd.Flags().Int64Var(&f.tokensIn, "tokens-in", 0, "Input tokens used, for the town log (default: from the metrics file)")
+	cmd.Flags().Int64Var(&f.tokensOut, "tokens-out", 0, "Output tokens used, for the town log (default: from the metrics file)")
+	cmd.Flags().Float64Var(&f.cost, "cost", 0, "Cost in USD, for the town log (default: from the metrics file)")
+	cmd.Flags().DurationVar(&f.duration, "duration", 0, "Time the work took, for the town log")
+}
+
+// usage returns the usage to log with the event: the tokens and cost the
+// agent's metrics file in workDir reports, overridden by any flags given.
+// It returns nil if nothing is known.
+func (f *usageFlags) usage(workDir string) *townlog.Usage {
+	var u townlog.Usage
+	if m, err := metrics.Read(workDir); err == nil && m != nil {
+		if m.Tokens != nil {
+			u.TokensIn, u.TokensOut = m.Tokens.Input, m.Tokens.Output
+		}
+		if m.CostUSD != nil {
+			u.Cost = *m.CostUSD
+		}
+	}
+	flags := f.cmd.Flags()
+	if flags.Changed("tokens-in") {
+		u.TokensIn = f.tokensIn
+	}
+	if flags.Changed("tokens-out") {
+		u.TokensOut = f.tokensOut
+	}
+	if flags.Changed("cost") {
+		u.Cost = f.cost
+	}
+	if flags.Changed("duration") {
+		u.Duration = f.duration
+	}
+	if u.IsZero() {
+		return nil
+	}
+	return &u
+}
+
+// formatUsage renders usage for display, e.g.
+// "12000 in / 3400 out tokens, $0.42, 12m0s".
+func formatUsage(u *townlog.Usage) string {
+	var parts []string
+	if u.TokensIn != 0 || u.TokensOut != 0 {
+		parts = append(parts, fmt.Sprintf("%d in / %d out tokens", u.TokensIn, u.TokensOut))
+	}
+	if u.Cost != 0 {
+		parts = append(parts, fmt.Sprintf("$%.2f", u.Cost))
+	}
+	if u.Duration != 0 {
+		parts = append(parts, u.Duration.Round(time.Second).String())
+	}
+	return strings.Join(parts, ", ")
+}
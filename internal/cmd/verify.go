@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ctiospl/gastown/internal/beads"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/git"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/style"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/verify"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+// EventVerify is the town log event recording a verify suite's result.
+const EventVerify townlog.EventType = "verify"
+
+// labelVerifyFailed marks issues reopened by a failed verify suite.
+const labelVerifyFailed = "verify-failed"
+
+var (
+	verifyWorktree string
+	verifyAgent    string
+	verifyNoReopen bool
+	verifyJSON     bool
+)
+
+var verifyCmd = &cobra.Command{
+	Use:     "verify",
+	GroupID: GroupWork,
+	Short:   "Validate finished work",
+	RunE:    requireSubcommand,
+}
+
+var verifyDoneCmd = &cobra.Command{
+	Use:   "done [issue]",
+	Short: "Run the rig's post-done validation suite",
+	Long: `Run the rig's verify checks in an agent's worktree and record the result.
+
+The checks are configured per rig, under "verify" in settings/config.json:
+typically the build, the tests, lint, and licence and secret scans. Each
+runs with sh -c in the worktree, even if an earlier one failed, with
+GT_VERIFY_ISSUE, GT_AGENT, GT_RIG_PATH, and GT_WORKTREE set.
+
+gt done starts the suite in the background once work is reported done
+(unless the rig sets "manual": true), so the agent never waits on it; its
+output goes to logs/verify/<issue>.log. It complements the merge queue's
+test gate: it runs before the merge rather than during it, and can run
+slower and stricter checks.
+
+The result is recorded on the agent's session: a "verify" event in the
+town log (error severity on failure) and a verify-green or verify-red
+session tag (see 'gt session tags'). With "reopen": true, a failed
+required check reopens the issue and labels it verify-failed, so the work
+is picked up again.
+
+The issue defaults to the one named by the worktree's branch, and the
+agent to the one running the command.
+
+Exits with status 1 if a required check fails.
+
+Examples:
+  gt verify done                                  # In a polecat worktree
+  gt verify done gt-abc --worktree gastown/polecats/Toast
+  gt verify done --no-reopen --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runVerifyDone,
+}
+
+func init() {
+	verifyDoneCmd.Flags().StringVar(&verifyWorktree, "worktree", "", "Worktree to verify (default: current directory)")
+	verifyDoneCmd.Flags().StringVar(&verifyAgent, "agent", "", "Agent whose work is verified (default: detected)")
+	verifyDoneCmd.Flags().BoolVar(&verifyNoReopen, "no-reopen", false, "Do not reopen the issue on failure, whatever the rig's settings")
+	verifyDoneCmd.Flags().BoolVar(&verifyJSON, "json", false, "Output the result as JSON (check output goes to stderr)")
+
+	verifyCmd.AddCommand(verifyDoneCmd)
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// verifyDoneResult is the --json form of a verify run.
+type verifyDoneResult struct {
+	Issue    string `json:"issue"`
+	Agent    string `json:"agent"`
+	Worktree string `json:"worktree"`
+	Passed   bool   `json:"passed"`
+	Summary  string `json:"summary"`
+	Reopened bool   `json:"reopened,omitempty"`
+	verify.Result
+}
+
+func runVerifyDone(cmd *cobra.Command, args []string) error {
+	worktree := verifyWorktree
+	if worktree == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting current directory: %w", err)
+		}
+		worktree = cwd
+	}
+	worktree, err := filepath.Abs(worktree)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(worktree); err != nil || !info.IsDir() {
+		return fmt.Errorf("worktree %s not found (was it already removed?)", worktree)
+	}
+	townRoot, err := workspace.Find(worktree)
+	if err != nil || townRoot == "" {
+		return fmt.Errorf("not in a Gas Town workspace: %s", worktree)
+	}
+	rigName, err := rigOfPath(townRoot, worktree)
+	if err != nil {
+		return err
+	}
+	rigPath := filepath.Join(townRoot, rigName)
+
+	issue := ""
+	if len(args) == 1 {
+		issue = args[0]
+	} else if branch, err := git.NewGit(worktree).CurrentBranch(); err == nil {
+		issue = parseBranchName(branch).Issue
+	}
+	if issue == "" {
+		return fmt.Errorf("cannot determine the issue from the worktree's branch; name it: gt verify done <issue>")
+	}
+	agent := verifyAgent
+	if agent == "" {
+		agent = detectSender()
+	}
+
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil || settings.Verify == nil || len(settings.Verify.Checks) == 0 {
+		return fmt.Errorf("rig %s has no verify checks: add \"verify\": {\"checks\": [...]} to %s", rigName, config.RigSettingsPath(rigPath))
+	}
+	cmd.SilenceUsage = true
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var out io.Writer = os.Stdout
+	if verifyJSON {
+		out = os.Stderr
+	}
+	env := []string{
+		"GT_VERIFY_ISSUE=" + issue,
+		"GT_AGENT=" + agent,
+		"GT_RIG_PATH=" + rigPath,
+		"GT_WORKTREE=" + worktree,
+	}
+	res := verify.Run(ctx, worktree, settings.Verify.Checks, env, out)
+
+	r := verifyDoneResult{Issue: issue, Agent: agent, Worktree: worktree, Passed: res.Passed(), Summary: res.Summary(), Result: res}
+	recordVerify(townRoot, r)
+	if !r.Passed && settings.Verify.Reopen && !verifyNoReopen {
+		if err := reopenVerifiedIssue(worktree, issue); err != nil {
+			style.PrintWarning("could not reopen %s: %v", issue, err)
+		} else {
+			r.Reopened = true
+		}
+	}
+
+	if verifyJSON {
+		if err := outputJSON(r); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println()
+		if r.Passed {
+			fmt.Printf("%s %s: %s\n", style.Success.Render("✓"), issue, r.Summary)
+		} else {
+			fmt.Printf("%s %s: %s\n", style.Error.Render("✗"), issue, r.Summary)
+		}
+		if r.Reopened {
+			fmt.Printf("  Reopened %s (labelled %s)\n", issue, labelVerifyFailed)
+		}
+	}
+	if !r.Passed {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// recordVerify logs the result on the agent's session and tags the
+// session with it. Failures to record are warnings: the checks ran.
+func recordVerify(townRoot string, r verifyDoneResult) {
+	e := townlog.Event{
+		Timestamp: time.Now(),
+		Type:      EventVerify,
+		Agent:     r.Agent,
+		Context:   r.Issue + ": " + r.Summary,
+	}
+	if !r.Passed {
+		e.Severity = townlog.SeverityError
+	}
+	if all, err := townlog.ReadEvents(townRoot); err == nil {
+		e.SessionID = doneSession(all, r.Agent, r.Issue)
+	}
+	if err := townlog.NewLogger(townRoot).LogEvent(e); err != nil {
+		style.PrintWarning("could not log verify result: %v", err)
+	}
+
+	tag := session.TagVerifyGreen
+	if !r.Passed {
+		tag = session.TagVerifyRed
+	}
+	if _, err := session.TagSession(townRoot, "gt verify", r.Agent, r.Issue, tag, r.Summary); err != nil {
+		style.PrintWarning("could not tag session with %s: %v", tag, err)
+	}
+}
+
+// doneSession returns the session in which agent last reported issue done,
+// or "" if it did not.
+func doneSession(events []townlog.Event, agent, issue string) string {
+	key := townlog.AgentKey(agent)
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		if e.Type == townlog.EventDone && e.Context == issue && townlog.AgentKey(e.Agent) == key {
+			return e.SessionID
+		}
+	}
+	return ""
+}
+
+// reopenVerifiedIssue reopens an issue whose work failed verification.
+func reopenVerifiedIssue(worktree, issue string) error {
+	status := "open"
+	return beads.New(worktree).Update(issue, beads.UpdateOptions{
+		Status:    &status,
+		AddLabels: []string{labelVerifyFailed},
+	})
+}
+
+// verifyLogPath is where a background verify run of issue writes its output.
+func verifyLogPath(townRoot, issue string) string {
+	return filepath.Join(townRoot, "logs", "verify", issue+".log")
+}
+
+// startVerify starts 'gt verify done' for issue in the background, if the
+// rig has verify checks that gt done should run. It returns the log file
+// the run writes to, or "" if none was started.
+func startVerify(townRoot, rigName, worktree, agent, issue string) (string, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rigName)))
+	if err != nil || settings.Verify == nil || len(settings.Verify.Checks) == 0 || settings.Verify.Manual {
+		return "", nil
+	}
+	gtPath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("finding executable: %w", err)
+	}
+	logPath := verifyLogPath(townRoot, issue)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return "", err
+	}
+	logFile, err := os.Create(logPath) //nolint:gosec // G304: path is under the town's log dir
+	if err != nil {
+		return "", err
+	}
+	defer logFile.Close()
+
+	c := exec.Command(gtPath, "verify", "done", issue, "--worktree", worktree, "--agent", agent) //nolint:gosec // G204: arguments come from gt done
+	c.Dir = worktree
+	c.Stdout = logFile
+	c.Stderr = logFile
+	// A session of its own, so the run outlives the agent's tmux session
+	// when gt done --exit ends it.
+	c.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := c.Start(); err != nil {
+		return "", fmt.Errorf("starting verify: %w", err)
+	}
+	_ = c.Process.Release()
+	return logPath, nil
+}
+
+// rigOfPath returns the name of the rig containing path.
+func rigOfPath(townRoot, path string) (string, error) {
+	rel, err := filepath.Rel(townRoot, path)
+	if err != nil {
+		return "", err
+	}
+	name := strings.Split(rel, string(filepath.Separator))[0]
+	if rel == "." || name == ".." {
+		return "", fmt.Errorf("%s is not inside a rig", path)
+	}
+	return name, nil
+}
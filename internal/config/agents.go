@@ -21,6 +21,8 @@ const (
 	AgentGemini AgentPreset = "gemini"
 	// AgentCodex is OpenAI Codex.
 	AgentCodex AgentPreset = "codex"
+	// AgentAider is Aider.
+	AgentAider AgentPreset = "aider"
 )
 
 // AgentPresetInfo contains the configuration details for an agent preset.
@@ -52,12 +54,31 @@ type AgentPresetInfo struct {
 	// SupportsHooks indicates if the agent supports hooks system.
 	SupportsHooks bool `json:"supports_hooks,omitempty"`
 
+	// NoPromptArg means the runtime takes no startup prompt on its command
+	// line (aider reads its arguments as files to edit); gt types the
+	// prompt into the session once the runtime is up instead.
+	NoPromptArg bool `json:"no_prompt_arg,omitempty"`
+
 	// SupportsForkSession indicates if --fork-session is available.
 	// Claude-only feature for seance command.
 	SupportsForkSession bool `json:"supports_fork_session,omitempty"`
 
 	// NonInteractive contains settings for non-interactive mode.
 	NonInteractive *NonInteractiveConfig `json:"non_interactive,omitempty"`
+
+	// AuthEnv lists environment variables, any of which authenticates the
+	// runtime (e.g. an API key). Checked by spawn preflight.
+	AuthEnv []string `json:"auth_env,omitempty"`
+
+	// AuthFiles lists credential files relative to the home directory, any
+	// of which authenticates the runtime. If neither AuthEnv nor AuthFiles
+	// is set, preflight does not check authentication.
+	AuthFiles []string `json:"auth_files,omitempty"`
+
+	// Capabilities declares which gt integrations the runtime supports.
+	// A runtime can refine this at startup with 'gt agents handshake'.
+	// Nil means undeclared; gt then assumes only CapNudge.
+	Capabilities []Capability `json:"capabilities,omitempty"`
 }
 
 // NonInteractiveConfig contains settings for running agents non-interactively.
@@ -97,6 +118,9 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		SupportsHooks:       true,
 		SupportsForkSession: true,
 		NonInteractive:      nil, // Claude is native non-interactive
+		Capabilities:        []Capability{CapNudge, CapTokenUsage, CapCheckpoint, CapContextSignal},
+		AuthEnv:             []string{"ANTHROPIC_API_KEY", "CLAUDE_CODE_OAUTH_TOKEN"},
+		AuthFiles:           []string{".claude/.credentials.json", ".claude.json"},
 	},
 	AgentGemini: {
 		Name:                AgentGemini,
@@ -111,6 +135,9 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 			PromptFlag: "-p",
 			OutputFlag: "--output-format json",
 		},
+		Capabilities: []Capability{CapNudge, CapCheckpoint},
+		AuthEnv:      []string{"GEMINI_API_KEY", "GOOGLE_API_KEY"},
+		AuthFiles:    []string{".gemini/oauth_creds.json"},
 	},
 	AgentCodex: {
 		Name:                AgentCodex,
@@ -125,6 +152,24 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 			Subcommand: "exec",
 			OutputFlag: "--json",
 		},
+		Capabilities: []Capability{CapNudge, CapTokenUsage, CapCheckpoint},
+		AuthEnv:      []string{"OPENAI_API_KEY"},
+		AuthFiles:    []string{".codex/auth.json"},
+	},
+	AgentAider: {
+		Name:                AgentAider,
+		Command:             "aider",
+		Args:                []string{"--yes-always"},
+		SessionIDEnv:        "", // Aider keeps chat history per repo, not sessions
+		SupportsHooks:       false,
+		SupportsForkSession: false,
+		NoPromptArg:         true,
+		NonInteractive: &NonInteractiveConfig{
+			PromptFlag: "--message",
+		},
+		Capabilities: []Capability{CapNudge},
+		AuthEnv:      []string{"ANTHROPIC_API_KEY", "OPENAI_API_KEY", "GEMINI_API_KEY", "DEEPSEEK_API_KEY", "OPENROUTER_API_KEY"},
+		AuthFiles:    []string{".aider.conf.yml"},
 	},
 }
 
@@ -254,8 +299,9 @@ func RuntimeConfigFromPreset(preset AgentPreset) *RuntimeConfig {
 	}
 
 	return &RuntimeConfig{
-		Command: info.Command,
-		Args:    append([]string(nil), info.Args...), // Copy to avoid mutation
+		Command:     info.Command,
+		Args:        append([]string(nil), info.Args...), // Copy to avoid mutation
+		NoPromptArg: info.NoPromptArg,
 	}
 }
 
@@ -322,6 +368,7 @@ func (rc *RuntimeConfig) MergeWithPreset(preset AgentPreset) *RuntimeConfig {
 		Command:       rc.Command,
 		Args:          append([]string(nil), rc.Args...),
 		InitialPrompt: rc.InitialPrompt,
+		NoPromptArg:   rc.NoPromptArg || info.NoPromptArg,
 	}
 
 	// Apply preset defaults only if not overridden
@@ -10,7 +10,7 @@ import (
 
 func TestBuiltinPresets(t *testing.T) {
 	// Ensure all built-in presets are accessible (E2E tested agents only)
-	presets := []AgentPreset{AgentClaude, AgentGemini, AgentCodex}
+	presets := []AgentPreset{AgentClaude, AgentGemini, AgentCodex, AgentAider}
 
 	for _, preset := range presets {
 		info := GetAgentPreset(preset)
@@ -34,7 +34,7 @@ func TestGetAgentPresetByName(t *testing.T) {
 		{"claude", AgentClaude, false},
 		{"gemini", AgentGemini, false},
 		{"codex", AgentCodex, false},
-		{"aider", "", true},    // Not built-in, can be added via config
+		{"aider", AgentAider, false},
 		{"opencode", "", true}, // Not built-in, can be added via config
 		{"unknown", "", true},
 	}
@@ -63,6 +63,7 @@ func TestRuntimeConfigFromPreset(t *testing.T) {
 		{AgentClaude, "claude"},
 		{AgentGemini, "gemini"},
 		{AgentCodex, "codex"},
+		{AgentAider, "aider"},
 	}
 
 	for _, tt := range tests {
@@ -84,7 +85,7 @@ func TestIsKnownPreset(t *testing.T) {
 		{"claude", true},
 		{"gemini", true},
 		{"codex", true},
-		{"aider", false},    // Not built-in, can be added via config
+		{"aider", true},
 		{"opencode", false}, // Not built-in, can be added via config
 		{"unknown", false},
 		{"chatgpt", false},
@@ -161,6 +162,7 @@ func TestAgentPresetYOLOFlags(t *testing.T) {
 		{AgentClaude, "--dangerously-skip-permissions"},
 		{AgentGemini, "yolo"}, // Part of "--approval-mode yolo"
 		{AgentCodex, "--yolo"},
+		{AgentAider, "--yes-always"},
 	}
 
 	for _, tt := range tests {
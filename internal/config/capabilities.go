@@ -0,0 +1,195 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Capability is an integration a runtime may support. gt features that
+// depend on a capability degrade gracefully for runtimes that lack it.
+type Capability string
+
+const (
+	// CapNudge means the runtime acts on text injected into its session
+	// (gt nudge). Without it, nudges are delivered as mail.
+	CapNudge Capability = "nudge"
+
+	// CapTokenUsage means the runtime reports token usage and cost, so
+	// gt costs can attribute spend. Without it, costs show as n/a unless
+	// the agent reports them in its metrics file.
+	CapTokenUsage Capability = "token_usage"
+
+	// CapCheckpoint means the runtime can resume a saved session, so
+	// handoffs and restarts keep conversation state.
+	CapCheckpoint Capability = "checkpoint"
+
+	// CapContextSignal means the runtime signals context-window pressure,
+	// so gt can hand off before the context fills.
+	CapContextSignal Capability = "context_signal"
+)
+
+// AllCapabilities lists every known capability in display order.
+var AllCapabilities = []Capability{CapNudge, CapTokenUsage, CapCheckpoint, CapContextSignal}
+
+// CapabilityDegradation describes what gt does when a capability is missing.
+var CapabilityDegradation = map[Capability]string{
+	CapNudge:         "nudges are delivered as mail instead",
+	CapTokenUsage:    "costs are n/a unless its metrics file reports cost_usd",
+	CapCheckpoint:    "restarts begin a fresh session",
+	CapContextSignal: "no automatic handoff on context pressure",
+}
+
+// ParseCapability validates a capability name.
+func ParseCapability(s string) (Capability, error) {
+	c := Capability(strings.TrimSpace(s))
+	for _, known := range AllCapabilities {
+		if c == known {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("unknown capability %q (known: %s)", s, joinCapabilities(AllCapabilities))
+}
+
+func joinCapabilities(caps []Capability) string {
+	names := make([]string, len(caps))
+	for i, c := range caps {
+		names[i] = string(c)
+	}
+	return strings.Join(names, ", ")
+}
+
+// CapabilityHandshake is what a runtime reports about itself at startup.
+type CapabilityHandshake struct {
+	Agent        string       `json:"agent"`
+	Runtime      string       `json:"runtime"`
+	Version      string       `json:"version,omitempty"`
+	Capabilities []Capability `json:"capabilities"`
+	Time         time.Time    `json:"time"`
+}
+
+// HandshakePath returns where an agent's handshake is stored.
+func HandshakePath(townRoot, agent string) string {
+	name := strings.ReplaceAll(NormalizeAgentAddress(agent), "/", "_")
+	return filepath.Join(townRoot, ".runtime", "capabilities", name+".json")
+}
+
+// NormalizeAgentAddress returns the canonical form of an agent address so
+// that "gastown/Toast" and "gastown/polecats/Toast" name the same agent.
+func NormalizeAgentAddress(agent string) string {
+	agent = strings.Trim(agent, "/")
+	rig, rel, ok := strings.Cut(agent, "/")
+	if !ok {
+		return agent
+	}
+	return rig + "/" + rigAgentPath(rel)
+}
+
+// SaveHandshake records a runtime's handshake, replacing any earlier one.
+func SaveHandshake(townRoot string, h *CapabilityHandshake) error {
+	h.Agent = NormalizeAgentAddress(h.Agent)
+	path := HandshakePath(townRoot, h.Agent)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating capabilities dir: %w", err)
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644) //nolint:gosec // G306: not secret
+}
+
+// LoadHandshake returns an agent's most recent handshake, or nil.
+func LoadHandshake(townRoot, agent string) *CapabilityHandshake {
+	data, err := os.ReadFile(HandshakePath(townRoot, agent)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil
+	}
+	var h CapabilityHandshake
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil
+	}
+	return &h
+}
+
+// Capability sources, from most to least authoritative.
+const (
+	CapSourceHandshake = "handshake" // reported by the running runtime
+	CapSourcePreset    = "preset"    // declared in the agent preset
+	CapSourceDefault   = "default"   // undeclared; only nudge assumed
+)
+
+// AgentCapabilities is the negotiated capability set for one agent.
+type AgentCapabilities struct {
+	Agent   string
+	Runtime string
+	Version string
+	Source  string
+	Set     map[Capability]bool
+}
+
+// Has reports whether the agent supports c.
+func (a *AgentCapabilities) Has(c Capability) bool {
+	return a.Set[c]
+}
+
+// Missing returns the known capabilities the agent lacks.
+func (a *AgentCapabilities) Missing() []Capability {
+	var missing []Capability
+	for _, c := range AllCapabilities {
+		if !a.Set[c] {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// ResolveCapabilities negotiates an agent's capabilities: a handshake from
+// the runtime wins, then the runtime preset's declaration, then a
+// conservative default. agent is an address like "gastown/polecats/Toast"
+// or "mayor".
+func ResolveCapabilities(townRoot, agent string) *AgentCapabilities {
+	agent = NormalizeAgentAddress(agent)
+	runtime := ResolveAgentRuntime(townRoot, agent)
+	result := &AgentCapabilities{Agent: agent, Runtime: runtime, Set: make(map[Capability]bool)}
+
+	if h := LoadHandshake(townRoot, agent); h != nil {
+		result.Source = CapSourceHandshake
+		if h.Runtime != "" {
+			// The handshake names the runtime actually running, which may
+			// differ from configuration if settings changed since startup.
+			result.Runtime = h.Runtime
+		}
+		result.Version = h.Version
+		for _, c := range h.Capabilities {
+			result.Set[c] = true
+		}
+		return result
+	}
+
+	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
+	if preset := GetAgentPresetByName(runtime); preset != nil && preset.Capabilities != nil {
+		result.Source = CapSourcePreset
+		for _, c := range preset.Capabilities {
+			result.Set[c] = true
+		}
+		return result
+	}
+
+	result.Source = CapSourceDefault
+	result.Set[CapNudge] = true
+	return result
+}
+
+// agentRigPath returns the rig directory for a rig agent, or "" for
+// town-level agents.
+func agentRigPath(townRoot, agent string) string {
+	rig, _, ok := strings.Cut(strings.Trim(agent, "/"), "/")
+	if !ok {
+		return ""
+	}
+	return filepath.Join(townRoot, rig)
+}
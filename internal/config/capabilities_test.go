@@ -0,0 +1,71 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveCapabilities(t *testing.T) {
+	ResetRegistryForTesting()
+	t.Cleanup(ResetRegistryForTesting)
+	townRoot := t.TempDir()
+
+	geminiRig := NewRigSettings()
+	geminiRig.Agent = "gemini"
+	if err := SaveRigSettings(RigSettingsPath(filepath.Join(townRoot, "alpha")), geminiRig); err != nil {
+		t.Fatal(err)
+	}
+	customRig := NewRigSettings()
+	customRig.Runtime = &RuntimeConfig{Command: "/usr/local/bin/opencode"}
+	if err := SaveRigSettings(RigSettingsPath(filepath.Join(townRoot, "beta")), customRig); err != nil {
+		t.Fatal(err)
+	}
+
+	mayor := ResolveCapabilities(townRoot, "mayor")
+	if mayor.Runtime != "claude" || mayor.Source != CapSourcePreset || len(mayor.Missing()) != 0 {
+		t.Errorf("mayor = %+v, want claude preset with every capability", mayor)
+	}
+
+	gemini := ResolveCapabilities(townRoot, "alpha/Toast")
+	if gemini.Runtime != "gemini" || gemini.Source != CapSourcePreset {
+		t.Errorf("gemini rig = %+v, want gemini preset", gemini)
+	}
+	if !gemini.Has(CapNudge) || gemini.Has(CapTokenUsage) {
+		t.Errorf("gemini capabilities = %v", gemini.Set)
+	}
+
+	custom := ResolveCapabilities(townRoot, "beta/crew/max")
+	if custom.Runtime != "opencode" || custom.Source != CapSourceDefault {
+		t.Errorf("custom runtime = %+v, want opencode with default capabilities", custom)
+	}
+	if !custom.Has(CapNudge) || len(custom.Missing()) != 3 {
+		t.Errorf("default capabilities = %v, want nudge only", custom.Set)
+	}
+
+	// A handshake from the running runtime overrides the preset, and short
+	// polecat addresses resolve to the same agent.
+	if err := SaveHandshake(townRoot, &CapabilityHandshake{
+		Agent:        "alpha/polecats/Toast",
+		Runtime:      "gemini",
+		Version:      "0.9.0",
+		Capabilities: []Capability{CapTokenUsage},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	shook := ResolveCapabilities(townRoot, "alpha/Toast")
+	if shook.Source != CapSourceHandshake || shook.Version != "0.9.0" {
+		t.Errorf("after handshake = %+v, want handshake source", shook)
+	}
+	if shook.Has(CapNudge) || !shook.Has(CapTokenUsage) {
+		t.Errorf("handshake capabilities = %v, want token_usage only", shook.Set)
+	}
+}
+
+func TestParseCapability(t *testing.T) {
+	if c, err := ParseCapability(" checkpoint "); err != nil || c != CapCheckpoint {
+		t.Errorf("ParseCapability(checkpoint) = %q, %v", c, err)
+	}
+	if _, err := ParseCapability("telepathy"); err == nil {
+		t.Error("ParseCapability accepted an unknown capability")
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DepCache is a package manager or build cache shared by every agent
+// worktree in the town. Each cache is pointed at a directory under
+// <town>/.cache/deps/<name> through the tool's own environment variables.
+//
+// Only tools whose caches are safe for concurrent use are listed: Go and
+// pip lock their caches, and the pnpm store, npm cacache, yarn and ccache
+// write entries atomically. Agents can therefore share them without any
+// coordination from Gas Town.
+type DepCache struct {
+	Name        string
+	Description string
+	Env         map[string]string // variable → subdirectory of the cache dir ("" for the dir itself)
+}
+
+// DepCaches lists the caches a rig can enable with worktree.caches.
+var DepCaches = []DepCache{
+	{Name: "go", Description: "Go module and build cache", Env: map[string]string{"GOMODCACHE": "mod", "GOCACHE": "build"}},
+	{Name: "pnpm", Description: "pnpm content-addressed store (node_modules are hard-linked from it)", Env: map[string]string{"npm_config_store_dir": "store"}},
+	{Name: "npm", Description: "npm download cache", Env: map[string]string{"npm_config_cache": ""}},
+	{Name: "yarn", Description: "Yarn package cache", Env: map[string]string{"YARN_CACHE_FOLDER": ""}},
+	{Name: "pip", Description: "pip wheel and download cache", Env: map[string]string{"PIP_CACHE_DIR": ""}},
+	{Name: "ccache", Description: "C/C++ compiler cache", Env: map[string]string{"CCACHE_DIR": ""}},
+}
+
+// LookupDepCache returns the cache with the given name.
+func LookupDepCache(name string) (DepCache, bool) {
+	for _, c := range DepCaches {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return DepCache{}, false
+}
+
+// DepCacheRoot returns the directory holding the town's shared caches.
+func DepCacheRoot(townRoot string) string {
+	return filepath.Join(townRoot, ".cache", "deps")
+}
+
+// Dir returns the cache's directory in the town.
+func (c DepCache) Dir(townRoot string) string {
+	return filepath.Join(DepCacheRoot(townRoot), c.Name)
+}
+
+// DepCacheEnv returns the environment that points the named caches at the
+// town's shared directories. Unknown names are skipped; they are rejected
+// when rig settings are loaded.
+func DepCacheEnv(townRoot string, names []string) map[string]string {
+	env := make(map[string]string)
+	for _, name := range names {
+		c, ok := LookupDepCache(name)
+		if !ok {
+			continue
+		}
+		for k, sub := range c.Env {
+			env[k] = filepath.Join(c.Dir(townRoot), sub)
+		}
+	}
+	return env
+}
+
+// EnsureDepCaches creates the directories for the named caches.
+func EnsureDepCaches(townRoot string, names []string) error {
+	for _, dir := range DepCacheEnv(townRoot, names) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("creating cache directory: %w", err)
+		}
+	}
+	return nil
+}
+
+// RigDepCacheEnv returns the shared cache environment for agents of the
+// rig at rigPath, or nil if the rig enables no caches.
+func RigDepCacheEnv(rigPath string) map[string]string {
+	if rigPath == "" {
+		return nil
+	}
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil || settings.Worktree == nil || len(settings.Worktree.Caches) == 0 {
+		return nil
+	}
+	return DepCacheEnv(filepath.Dir(rigPath), settings.Worktree.Caches)
+}
+
+// DepCacheNames returns the names of all known caches, sorted.
+func DepCacheNames() []string {
+	names := make([]string, 0, len(DepCaches))
+	for _, c := range DepCaches {
+		names = append(names, c.Name)
+	}
+	sort.Strings(names)
+	return names
+}
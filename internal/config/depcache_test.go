@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDepCacheEnv(t *testing.T) {
+	townRoot := t.TempDir()
+	env := DepCacheEnv(townRoot, []string{"go", "pip", "nope"})
+
+	want := map[string]string{
+		"GOMODCACHE":    filepath.Join(townRoot, ".cache", "deps", "go", "mod"),
+		"GOCACHE":       filepath.Join(townRoot, ".cache", "deps", "go", "build"),
+		"PIP_CACHE_DIR": filepath.Join(townRoot, ".cache", "deps", "pip"),
+	}
+	if len(env) != len(want) {
+		t.Fatalf("DepCacheEnv() = %v, want %v", env, want)
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("%s = %q, want %q", k, env[k], v)
+		}
+	}
+
+	if err := EnsureDepCaches(townRoot, []string{"go"}); err != nil {
+		t.Fatalf("EnsureDepCaches() error: %v", err)
+	}
+	if _, err := os.Stat(want["GOMODCACHE"]); err != nil {
+		t.Errorf("GOMODCACHE dir not created: %v", err)
+	}
+}
+
+func TestValidateWorktreeCaches(t *testing.T) {
+	if err := validateWorktreeConfig(&WorktreeConfig{Caches: []string{"go", "pnpm"}}); err != nil {
+		t.Errorf("known caches rejected: %v", err)
+	}
+	if err := validateWorktreeConfig(&WorktreeConfig{Caches: []string{"maven"}}); err == nil {
+		t.Error("unknown cache accepted")
+	}
+}
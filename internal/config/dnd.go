@@ -0,0 +1,171 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// DNDWindow is a scheduled Do Not Disturb period for the agents matching
+// Agent. A window is either daily ("22:00-07:00", local time, may wrap past
+// midnight) or a one-off span from Start to End.
+type DNDWindow struct {
+	ID     string    `json:"id"`
+	Agent  string    `json:"agent"`           // address, glob ("gastown/polecats/*"), or role ("witness")
+	Daily  string    `json:"daily,omitempty"` // "HH:MM-HH:MM"
+	Start  time.Time `json:"start,omitzero"`
+	End    time.Time `json:"end,omitzero"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// dndRoles are role names a window can target in every rig.
+var dndRoles = map[string]string{
+	"witness":  "/witness",
+	"refinery": "/refinery",
+	"crew":     "/crew/",
+	"polecat":  "/polecats/",
+	"polecats": "/polecats/",
+}
+
+// Matches reports whether the window applies to agent (a full address such
+// as "gastown/polecats/Toast" or "mayor/").
+func (w DNDWindow) Matches(agent string) bool {
+	agent = NormalizeAgentAddress(agent)
+	pattern := strings.Trim(w.Agent, "/")
+	if pattern == "*" || pattern == agent || NormalizeAgentAddress(pattern) == agent {
+		return true
+	}
+	if marker, ok := dndRoles[pattern]; ok {
+		if strings.HasSuffix(marker, "/") {
+			return strings.Contains(agent, marker)
+		}
+		return strings.HasSuffix(agent, marker)
+	}
+	if ok, _ := path.Match(pattern, agent); ok {
+		return true
+	}
+	// "gastown/*" covers everything in the rig, including crew and polecats.
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasSuffix(prefix, "/") {
+		return strings.HasPrefix(agent, prefix)
+	}
+	return false
+}
+
+// Active reports whether now falls inside the window.
+func (w DNDWindow) Active(now time.Time) bool {
+	if w.Daily == "" {
+		return !now.Before(w.Start) && now.Before(w.End)
+	}
+	start, end, err := parseDailyWindow(w.Daily)
+	if err != nil || start == end {
+		return false
+	}
+	mins := now.Hour()*60 + now.Minute()
+	if start < end {
+		return mins >= start && mins < end
+	}
+	return mins >= start || mins < end
+}
+
+// Until returns when the window's current span ends. It is only meaningful
+// while the window is active.
+func (w DNDWindow) Until(now time.Time) time.Time {
+	if w.Daily == "" {
+		return w.End
+	}
+	_, end, _ := parseDailyWindow(w.Daily)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	until := midnight.Add(time.Duration(end) * time.Minute)
+	if !until.After(now) {
+		until = until.AddDate(0, 0, 1)
+	}
+	return until
+}
+
+// Expired reports whether a one-off window is over. Daily windows never expire.
+func (w DNDWindow) Expired(now time.Time) bool {
+	return w.Daily == "" && !now.Before(w.End)
+}
+
+// Describe renders the window's schedule for display.
+func (w DNDWindow) Describe() string {
+	if w.Daily != "" {
+		return "daily " + w.Daily
+	}
+	return w.Start.Format("2006-01-02 15:04") + " → " + w.End.Format("2006-01-02 15:04")
+}
+
+func (w DNDWindow) validate() error {
+	if w.Agent == "" {
+		return fmt.Errorf("%w: dnd window %q agent", ErrMissingField, w.ID)
+	}
+	if w.Daily != "" {
+		if _, _, err := parseDailyWindow(w.Daily); err != nil {
+			return fmt.Errorf("dnd window %q: %w", w.ID, err)
+		}
+		return nil
+	}
+	if w.Start.IsZero() || !w.End.After(w.Start) {
+		return fmt.Errorf("dnd window %q: needs a daily schedule or an end after its start", w.ID)
+	}
+	return nil
+}
+
+// ValidateDailyWindow checks a "HH:MM-HH:MM" daily window.
+func ValidateDailyWindow(s string) error {
+	_, _, err := parseDailyWindow(s)
+	return err
+}
+
+// parseDailyWindow returns a daily window as minutes since midnight.
+func parseDailyWindow(s string) (int, int, error) {
+	startStr, endStr, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("daily window must be HH:MM-HH:MM, got %q", s)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(startStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf("daily window start: expected HH:MM, got %q", startStr)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(endStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf("daily window end: expected HH:MM, got %q", endStr)
+	}
+	return start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute(), nil
+}
+
+// ActiveDND returns the first window active for agent at now, or nil.
+func (c *MessagingConfig) ActiveDND(agent string, now time.Time) *DNDWindow {
+	for i := range c.DND {
+		if c.DND[i].Active(now) && c.DND[i].Matches(agent) {
+			return &c.DND[i]
+		}
+	}
+	return nil
+}
+
+// NextDNDID returns an unused window ID.
+func (c *MessagingConfig) NextDNDID() string {
+	max := 0
+	for _, w := range c.DND {
+		var n int
+		if _, err := fmt.Sscanf(w.ID, "dnd-%d", &n); err == nil && n > max {
+			max = n
+		}
+	}
+	return fmt.Sprintf("dnd-%d", max+1)
+}
+
+// ActiveDNDWindow returns the town's DND window currently active for agent,
+// or nil if there is none (or no messaging config).
+func ActiveDNDWindow(townRoot, agent string) *DNDWindow {
+	if townRoot == "" || agent == "" {
+		return nil
+	}
+	cfg, err := LoadMessagingConfig(MessagingConfigPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	return cfg.ActiveDND(agent, time.Now())
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDNDWindowMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		agent   string
+		want    bool
+	}{
+		{"mayor", "mayor/", true},
+		{"gastown/Toast", "gastown/polecats/Toast", true},
+		{"gastown/polecats/*", "gastown/polecats/Toast", true},
+		{"gastown/polecats/*", "gastown/crew/max", false},
+		{"gastown/*", "gastown/crew/max", true},
+		{"*/witness", "beads/witness", true},
+		{"witness", "beads/witness", true},
+		{"witness", "mayor", false},
+		{"crew", "gastown/crew/max", true},
+		{"*", "deacon", true},
+	}
+	for _, tt := range tests {
+		w := DNDWindow{Agent: tt.pattern}
+		if got := w.Matches(tt.agent); got != tt.want {
+			t.Errorf("DNDWindow{Agent: %q}.Matches(%q) = %v, want %v", tt.pattern, tt.agent, got, tt.want)
+		}
+	}
+}
+
+func TestDNDWindowActive(t *testing.T) {
+	at := func(hh, mm int) time.Time { return time.Date(2026, 3, 1, hh, mm, 0, 0, time.Local) }
+
+	night := DNDWindow{Agent: "*", Daily: "22:00-07:00"}
+	if !night.Active(at(23, 30)) || !night.Active(at(6, 59)) || night.Active(at(7, 0)) {
+		t.Error("wrapping daily window boundaries wrong")
+	}
+	if got := night.Until(at(23, 30)); !got.Equal(at(7, 0).AddDate(0, 0, 1)) {
+		t.Errorf("Until() = %v, want next day 07:00", got)
+	}
+
+	bench := DNDWindow{Agent: "*", Start: at(10, 0), End: at(12, 0)}
+	if !bench.Active(at(10, 0)) || bench.Active(at(12, 0)) {
+		t.Error("one-off window boundaries wrong")
+	}
+	if !bench.Expired(at(12, 0)) || night.Expired(at(12, 0)) {
+		t.Error("only ended one-off windows should expire")
+	}
+}
+
+func TestMessagingConfigDND(t *testing.T) {
+	cfg := NewMessagingConfig()
+	cfg.DND = []DNDWindow{
+		{ID: "dnd-1", Agent: "gastown/polecats/*", Daily: "00:00-23:59"},
+		{ID: "dnd-7", Agent: "mayor", Daily: "01:00-01:01"},
+	}
+	if err := validateMessagingConfig(cfg); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	noon := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	if w := cfg.ActiveDND("gastown/Toast", noon); w == nil || w.ID != "dnd-1" {
+		t.Errorf("ActiveDND(polecat) = %v, want dnd-1", w)
+	}
+	if w := cfg.ActiveDND("mayor/", noon); w != nil {
+		t.Errorf("ActiveDND(mayor) = %v, want nil", w)
+	}
+	if got := cfg.NextDNDID(); got != "dnd-8" {
+		t.Errorf("NextDNDID() = %q, want dnd-8", got)
+	}
+
+	cfg.DND = append(cfg.DND, DNDWindow{ID: "dnd-9", Agent: "mayor", Daily: "25:00-01:00"})
+	if err := validateMessagingConfig(cfg); err == nil {
+		t.Error("expected invalid daily window to fail validation")
+	}
+}
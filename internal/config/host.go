@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// Remote agents ('gt spawn --host build-box-2') run their runtime on
+// another machine, over an ssh connection started in their local tmux
+// session, so nudges and kills reach them as they reach local agents.
+// Their worktrees are kept on the host under its dir, laid out as a town of
+// its own, and the environment gt sets for the agent is exported there.
+
+// DefaultHostDir is where agents' worktrees are kept on a host that sets
+// no dir, relative to the remote home directory.
+const DefaultHostDir = "gt-remote"
+
+// hostName matches a host name: letters, digits, '.', '_', '@', and '-'.
+var hostName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._@-]*$`)
+
+// Host is a machine agents run on, with its settings' defaults applied.
+type Host struct {
+	Name string
+	HostConfig
+}
+
+// LoadHost returns the named host, as configured by the town's "hosts"
+// settings, if at all.
+func LoadHost(townRoot, name string) (Host, error) {
+	if !hostName.MatchString(name) {
+		return Host{}, fmt.Errorf("invalid host name %q", name)
+	}
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		return Host{}, fmt.Errorf("loading town settings: %w", err)
+	}
+	h := Host{Name: name}
+	if c := settings.Hosts[name]; c != nil {
+		h.HostConfig = *c
+	}
+	if h.SSH == "" {
+		h.SSH = name
+	}
+	if h.Dir == "" {
+		h.Dir = DefaultHostDir
+	}
+	return h, nil
+}
+
+// ValidateHost checks that the town can run agents on the named host: its
+// name is valid and ssh is installed. Whether the host is reachable is
+// found out when the agent is spawned.
+func ValidateHost(townRoot, name string) error {
+	if _, err := LoadHost(townRoot, name); err != nil {
+		return err
+	}
+	if _, err := exec.LookPath("ssh"); err != nil {
+		return fmt.Errorf("remote hosts need ssh: %w", err)
+	}
+	return nil
+}
+
+// SSHCommand returns the ssh command line running remoteCommand on the
+// host, with tty allocation if tty is set.
+func (h Host) SSHCommand(tty bool, remoteCommand string) []string {
+	args := []string{"ssh"}
+	if tty {
+		args = append(args, "-t")
+	}
+	args = append(args, "-o", "ServerAliveInterval=30")
+	args = append(args, h.Args...)
+	return append(args, h.SSH, remoteCommand)
+}
+
+// WorkDir returns a crew member's worktree on the host.
+func (h Host) WorkDir(rigName, crewName string) string {
+	return strings.TrimSuffix(h.Dir, "/") + "/" + rigName + "/crew/" + crewName
+}
+
+// RemotePath quotes a path on the host for its shell, leaving a leading
+// "~/" to expand to the remote home directory.
+func RemotePath(p string) string {
+	if p == "~" {
+		return `"$HOME"`
+	}
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		return `"$HOME"/` + shellWord(rest)
+	}
+	return shellWord(p)
+}
+
+// AgentHost returns the host an agent runs on, for an address like
+// "gastown/crew/max", or "" if it runs locally.
+func AgentHost(townRoot, agent string) string {
+	if parts := strings.Split(NormalizeAgentAddress(agent), "/"); len(parts) == 3 && parts[1] == "crew" {
+		return ReadCrewBinding(filepath.Join(townRoot, parts[0]), parts[2]).Host
+	}
+	return ""
+}
+
+// buildRemoteCommand builds a startup command running rc on h, in the
+// crew member's worktree there, with envVars exported.
+func buildRemoteCommand(envVars map[string]string, rc *RuntimeConfig, prompt string, h Host, rigName, crewName string) string {
+	runtimeCmd := rc.BuildCommand()
+	if prompt != "" {
+		runtimeCmd = rc.BuildCommandWithPrompt(prompt)
+	}
+	remote := "cd " + RemotePath(h.WorkDir(rigName, crewName))
+	if prefix := exportPrefix(envVars); prefix != "" {
+		remote += " && " + prefix
+	}
+	remote += " && " + runtimeCmd
+
+	args := h.SSHCommand(true, remote)
+	for i, a := range args[:len(args)-1] {
+		args[i] = shellWord(a)
+	}
+	args[len(args)-1] = util.ShellQuote(remote)
+	return strings.Join(args, " ")
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeHostSettings(t *testing.T, townRoot string, hosts map[string]*HostConfig) {
+	t.Helper()
+	settings := NewTownSettings()
+	settings.Hosts = hosts
+	data, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(TownSettingsPath(townRoot), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadHost(t *testing.T) {
+	townRoot := t.TempDir()
+	if _, err := LoadHost(townRoot, "-oProxyCommand=x"); err == nil {
+		t.Error("LoadHost accepted a name that ssh would take for an option")
+	}
+	h, err := LoadHost(townRoot, "build-box-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.SSH != "build-box-2" || h.Dir != DefaultHostDir {
+		t.Errorf("unconfigured host = %+v", h)
+	}
+
+	writeHostSettings(t, townRoot, map[string]*HostConfig{
+		"build-box-2": {SSH: "ci@10.0.0.7", Dir: "/srv/agents", Args: []string{"-p", "2222"}},
+	})
+	h, err = LoadHost(townRoot, "build-box-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := h.WorkDir("gastown", "max"); got != "/srv/agents/gastown/crew/max" {
+		t.Errorf("WorkDir = %q", got)
+	}
+	if got := strings.Join(h.SSHCommand(false, "true"), " "); got != "ssh -o ServerAliveInterval=30 -p 2222 ci@10.0.0.7 true" {
+		t.Errorf("SSHCommand = %q", got)
+	}
+}
+
+func TestRemotePath(t *testing.T) {
+	for p, want := range map[string]string{
+		"gt-remote/gastown": "gt-remote/gastown",
+		"~/gt remote":       `"$HOME"/"gt remote"`,
+		"~":                 `"$HOME"`,
+		"/srv/agents":       "/srv/agents",
+	} {
+		if got := RemotePath(p); got != want {
+			t.Errorf("RemotePath(%q) = %q, want %q", p, got, want)
+		}
+	}
+}
+
+func TestBuildCrewStartupCommandRemote(t *testing.T) {
+	ResetRegistryForTesting()
+	t.Cleanup(ResetRegistryForTesting)
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+
+	if err := SetCrewBinding(rigPath, "max", CrewBinding{Host: "build-box-2"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := AgentHost(townRoot, "gastown/crew/max"); got != "build-box-2" {
+		t.Fatalf("AgentHost = %q", got)
+	}
+
+	cmd := BuildCrewStartupCommand("gastown", "max", rigPath, "gt prime")
+	for _, want := range []string{
+		"ssh -t -o ServerAliveInterval=30 build-box-2 'cd ",
+		`'cd gt-remote/gastown/crew/max && export `,
+		"GT_CREW=max", "GT_HOST=build-box-2", "GT_RIG=gastown",
+		` && claude --dangerously-skip-permissions "gt prime"'`,
+	} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("remote command lacks %q:\n%s", want, cmd)
+		}
+	}
+	if strings.Contains(cmd, "systemd-run") {
+		t.Errorf("remote command should not limit the ssh client:\n%s", cmd)
+	}
+
+	if err := SetCrewBinding(rigPath, "max", CrewBinding{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := AgentHost(townRoot, "gastown/crew/max"); got != "" {
+		t.Errorf("AgentHost after unbinding = %q", got)
+	}
+	if cmd := BuildCrewStartupCommand("gastown", "max", rigPath, "gt prime"); strings.Contains(cmd, "ssh") {
+		t.Errorf("unbound command = %s", cmd)
+	}
+}
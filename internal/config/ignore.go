@@ -0,0 +1,109 @@
+package config
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ctiospl/gastown/internal/constants"
+)
+
+// Matches reports whether the rule hides an event of eventType from agent,
+// where agent is relative to the rig (e.g. "polecats/Toast", "witness").
+func (r IgnoreRule) Matches(eventType, agent string) bool {
+	if len(r.Events) > 0 && !containsString(r.Events, eventType) {
+		return false
+	}
+	if len(r.Agents) == 0 {
+		return true
+	}
+	base := path.Base(agent)
+	for _, pattern := range r.Agents {
+		if ok, _ := path.Match(pattern, agent); ok {
+			return true
+		}
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, base); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HidesAgent reports whether the rule hides every event from agent, so the
+// agent itself can be left out of default views.
+func (r IgnoreRule) HidesAgent(agent string) bool {
+	return len(r.Events) == 0 && len(r.Agents) > 0 && r.Matches("", agent)
+}
+
+// rigAgentPath normalizes a rig-relative agent: polecats are addressed both
+// as "<rig>/<name>" and "<rig>/polecats/<name>", so bare names other than
+// the singleton roles become "polecats/<name>".
+func rigAgentPath(rel string) string {
+	if strings.Contains(rel, "/") || rel == "witness" || rel == "refinery" {
+		return rel
+	}
+	return "polecats/" + rel
+}
+
+// IgnoreRules holds each rig's ignore rules, keyed by rig name.
+type IgnoreRules map[string][]IgnoreRule
+
+// LoadIgnoreRules reads the ignore rules of every registered rig.
+// Rigs without settings or without rules are omitted.
+func LoadIgnoreRules(townRoot string) IgnoreRules {
+	rules := make(IgnoreRules)
+	rigsConfig, err := LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return rules
+	}
+	for name := range rigsConfig.Rigs {
+		settings, err := LoadRigSettings(RigSettingsPath(filepath.Join(townRoot, name)))
+		if err != nil || len(settings.Ignore) == 0 {
+			continue
+		}
+		rules[name] = settings.Ignore
+	}
+	return rules
+}
+
+// Ignored reports whether an event of eventType from agent (a full address
+// such as "gastown/polecats/Toast") is hidden by its rig's rules.
+func (rules IgnoreRules) Ignored(eventType, agent string) bool {
+	rig, rel, ok := strings.Cut(strings.TrimSuffix(agent, "/"), "/")
+	if !ok {
+		return false // town-level agents (mayor, deacon) have no rig rules
+	}
+	rel = rigAgentPath(rel)
+	for _, r := range rules[rig] {
+		if r.Matches(eventType, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// HidesAgent reports whether agent is hidden entirely by its rig's rules.
+func (rules IgnoreRules) HidesAgent(agent string) bool {
+	rig, rel, ok := strings.Cut(strings.TrimSuffix(agent, "/"), "/")
+	if !ok {
+		return false
+	}
+	rel = rigAgentPath(rel)
+	for _, r := range rules[rig] {
+		if r.HidesAgent(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
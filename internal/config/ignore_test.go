@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	rules := IgnoreRules{
+		"gastown": {
+			{Events: []string{"nudge", "polecat_checked"}, Agents: []string{"janitor"}},
+			{Agents: []string{"crew/bot-*"}},
+			{Events: []string{"patrol_started"}},
+		},
+	}
+
+	tests := []struct {
+		event, agent string
+		want         bool
+	}{
+		{"nudge", "gastown/polecats/janitor", true},
+		{"nudge", "gastown/janitor", true}, // short polecat address
+		{"crash", "gastown/polecats/janitor", false},
+		{"nudge", "gastown/crew/max", false},
+		{"spawn", "gastown/crew/bot-1", true},
+		{"patrol_started", "gastown/witness", true},
+		{"nudge", "other/polecats/janitor", false},
+		{"nudge", "mayor", false},
+	}
+	for _, tt := range tests {
+		if got := rules.Ignored(tt.event, tt.agent); got != tt.want {
+			t.Errorf("Ignored(%q, %q) = %v, want %v", tt.event, tt.agent, got, tt.want)
+		}
+	}
+
+	if !rules.HidesAgent("gastown/crew/bot-1") {
+		t.Error("agent-only rule should hide the agent")
+	}
+	if rules.HidesAgent("gastown/polecats/janitor") {
+		t.Error("event-scoped rule must not hide the agent")
+	}
+}
+
+func TestLoadIgnoreRules(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigs := []byte(`{"version": 1, "rigs": {"gastown": {"git_url": "x"}, "bare": {"git_url": "y"}}}`)
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), rigs, 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings := NewRigSettings()
+	settings.Ignore = []IgnoreRule{{Events: []string{"nudge"}}}
+	if err := SaveRigSettings(RigSettingsPath(filepath.Join(townRoot, "gastown")), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := LoadIgnoreRules(townRoot)
+	if len(rules) != 1 || len(rules["gastown"]) != 1 {
+		t.Fatalf("LoadIgnoreRules = %+v", rules)
+	}
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Resource limits are enforced by starting the agent in a transient
+// systemd scope (systemd-run --user --scope), whose cgroup caps the agent
+// and every process it starts. They therefore apply on Linux with a
+// systemd user manager only; elsewhere agents run unlimited, and gt doctor
+// says so. Gas Town runs agents in tmux, so Windows is not supported.
+
+// memorySize matches the memory sizes systemd accepts: bytes with an
+// optional K, M, G, or T suffix, or a percentage of physical memory.
+var memorySize = regexp.MustCompile(`^([0-9]+(\.[0-9]+)?[KMGT]?|[0-9]+(\.[0-9]+)?%)$`)
+
+// IsZero reports whether l sets no limit.
+func (l *ResourceLimits) IsZero() bool {
+	return l == nil || (l.CPU == 0 && l.Memory == "" && l.Tasks == 0)
+}
+
+// Validate checks that each limit set is one systemd can enforce.
+func (l *ResourceLimits) Validate() error {
+	if l == nil {
+		return nil
+	}
+	if l.CPU < 0 {
+		return fmt.Errorf("cpu must not be negative, got %v", l.CPU)
+	}
+	if l.Memory != "" && !memorySize.MatchString(l.Memory) {
+		return fmt.Errorf("invalid memory %q: use a size like \"4G\" or \"512M\", or a percentage like \"25%%\"", l.Memory)
+	}
+	if l.Tasks < 0 {
+		return fmt.Errorf("tasks must not be negative, got %d", l.Tasks)
+	}
+	return nil
+}
+
+// ValidateResourceLimits checks the town's default and per-rig limits.
+func ValidateResourceLimits(c *ResourceLimitsConfig) error {
+	if c == nil {
+		return nil
+	}
+	if err := c.Default.Validate(); err != nil {
+		return fmt.Errorf("limits.default: %w", err)
+	}
+	for rig, l := range c.Rigs {
+		if err := l.Validate(); err != nil {
+			return fmt.Errorf("limits.rigs.%s: %w", rig, err)
+		}
+	}
+	return nil
+}
+
+// For returns the limits for the agents of a rig: the rig's own limits,
+// with the unset ones taken from the default.
+func (c *ResourceLimitsConfig) For(rigName string) *ResourceLimits {
+	if c == nil {
+		return nil
+	}
+	var l ResourceLimits
+	if c.Default != nil {
+		l = *c.Default
+	}
+	if r := c.Rigs[rigName]; r != nil {
+		if r.CPU != 0 {
+			l.CPU = r.CPU
+		}
+		if r.Memory != "" {
+			l.Memory = r.Memory
+		}
+		if r.Tasks != 0 {
+			l.Tasks = r.Tasks
+		}
+	}
+	if l.IsZero() {
+		return nil
+	}
+	return &l
+}
+
+// RigResourceLimits returns the limits the town sets for the agents of the
+// rig at rigPath, or nil if there are none.
+func RigResourceLimits(rigPath string) *ResourceLimits {
+	if rigPath == "" {
+		return nil
+	}
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(filepath.Dir(rigPath)))
+	if err != nil {
+		return nil
+	}
+	return settings.Limits.For(filepath.Base(rigPath))
+}
+
+// properties returns the systemd unit properties enforcing l.
+func (l *ResourceLimits) properties() []string {
+	var props []string
+	if l.CPU > 0 {
+		props = append(props, fmt.Sprintf("CPUQuota=%d%%", int(math.Round(l.CPU*100))))
+	}
+	if l.Memory != "" && memorySize.MatchString(l.Memory) {
+		// No swap either, or the agent would page rather than stop at the limit.
+		props = append(props, "MemoryMax="+l.Memory, "MemorySwapMax=0")
+	}
+	if l.Tasks > 0 {
+		props = append(props, fmt.Sprintf("TasksMax=%d", l.Tasks))
+	}
+	return props
+}
+
+// wrapCommand returns cmd run in a systemd scope enforcing l.
+func (l *ResourceLimits) wrapCommand(cmd string) string {
+	props := l.properties()
+	if len(props) == 0 {
+		return cmd
+	}
+	args := []string{"systemd-run", "--user", "--scope", "--quiet", "--collect"}
+	for _, p := range props {
+		args = append(args, "-p", p)
+	}
+	return strings.Join(args, " ") + " -- " + cmd
+}
+
+var (
+	limitsOnce sync.Once
+	limitsErr  error
+)
+
+// ResourceLimitsSupported returns why resource limits cannot be enforced
+// on this machine, or nil if they can. The answer is probed once, by
+// starting a trivial scope.
+func ResourceLimitsSupported() error {
+	limitsOnce.Do(func() {
+		if runtime.GOOS != "linux" {
+			limitsErr = fmt.Errorf("resource limits need Linux cgroups (running on %s)", runtime.GOOS)
+			return
+		}
+		if _, err := exec.LookPath("systemd-run"); err != nil {
+			limitsErr = fmt.Errorf("resource limits need systemd-run: %w", err)
+			return
+		}
+		out, err := exec.Command("systemd-run", "--user", "--scope", "--quiet", "--collect", "true").CombinedOutput()
+		if err != nil {
+			msg := strings.TrimSpace(string(out))
+			if msg == "" {
+				msg = err.Error()
+			}
+			limitsErr = fmt.Errorf("resource limits need a systemd user manager: %s", msg)
+		}
+	})
+	return limitsErr
+}
+
+// limitCommand returns cmd run under l where limits can be enforced, and
+// cmd unchanged otherwise.
+func limitCommand(l *ResourceLimits, cmd string) string {
+	if l.IsZero() || ResourceLimitsSupported() != nil {
+		return cmd
+	}
+	return l.wrapCommand(cmd)
+}
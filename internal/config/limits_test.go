@@ -0,0 +1,101 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResourceLimitsFor(t *testing.T) {
+	c := &ResourceLimitsConfig{
+		Default: &ResourceLimits{CPU: 2, Memory: "4G"},
+		Rigs: map[string]*ResourceLimits{
+			"gastown": {Memory: "8G", Tasks: 512},
+		},
+	}
+	if got := c.For("gastown"); *got != (ResourceLimits{CPU: 2, Memory: "8G", Tasks: 512}) {
+		t.Errorf("For(gastown) = %+v", *got)
+	}
+	if got := c.For("beads"); *got != (ResourceLimits{CPU: 2, Memory: "4G"}) {
+		t.Errorf("For(beads) = %+v", *got)
+	}
+	if got := (&ResourceLimitsConfig{}).For("beads"); got != nil {
+		t.Errorf("For with no limits = %+v, want nil", *got)
+	}
+	var none *ResourceLimitsConfig
+	if got := none.For("beads"); got != nil {
+		t.Errorf("nil config For = %+v, want nil", *got)
+	}
+}
+
+func TestResourceLimitsValidate(t *testing.T) {
+	valid := []ResourceLimits{
+		{},
+		{CPU: 0.5, Memory: "512M", Tasks: 100},
+		{Memory: "1.5G"},
+		{Memory: "25%"},
+		{Memory: "1073741824"},
+	}
+	for _, l := range valid {
+		if err := l.Validate(); err != nil {
+			t.Errorf("Validate(%+v) = %v, want nil", l, err)
+		}
+	}
+	invalid := []ResourceLimits{
+		{CPU: -1},
+		{Memory: "4GB"},
+		{Memory: "lots"},
+		{Tasks: -5},
+	}
+	for _, l := range invalid {
+		if err := l.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", l)
+		}
+	}
+	c := &ResourceLimitsConfig{Rigs: map[string]*ResourceLimits{"gastown": {Memory: "4 G"}}}
+	if err := ValidateResourceLimits(c); err == nil {
+		t.Error("ValidateResourceLimits accepted an invalid rig limit")
+	}
+}
+
+func TestResourceLimitsWrapCommand(t *testing.T) {
+	l := &ResourceLimits{CPU: 1.5, Memory: "4G", Tasks: 256}
+	want := "systemd-run --user --scope --quiet --collect -p CPUQuota=150% -p MemoryMax=4G -p MemorySwapMax=0 -p TasksMax=256 -- claude --dangerously-skip-permissions"
+	if got := l.wrapCommand("claude --dangerously-skip-permissions"); got != want {
+		t.Errorf("wrapCommand =\n  %s\nwant\n  %s", got, want)
+	}
+	if got := (&ResourceLimits{}).wrapCommand("claude"); got != "claude" {
+		t.Errorf("wrapCommand with no limits = %q", got)
+	}
+	if got := limitCommand(nil, "claude"); got != "claude" {
+		t.Errorf("limitCommand(nil) = %q", got)
+	}
+}
+
+func TestRigResourceLimits(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	if got := RigResourceLimits(rigPath); got != nil {
+		t.Errorf("RigResourceLimits without settings = %+v", *got)
+	}
+
+	settings := NewTownSettings()
+	settings.Limits = &ResourceLimitsConfig{Rigs: map[string]*ResourceLimits{"gastown": {Memory: "2G"}}}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(TownSettingsPath(townRoot), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := RigResourceLimits(rigPath); got == nil || got.Memory != "2G" {
+		t.Errorf("RigResourceLimits = %+v, want memory 2G", got)
+	}
+	if got := RigResourceLimits(filepath.Join(townRoot, "beads")); got != nil {
+		t.Errorf("RigResourceLimits(beads) = %+v, want nil", *got)
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/ctiospl/gastown/internal/util"
 )
 
 var (
@@ -209,6 +212,169 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.Worktree != nil {
+		if err := validateWorktreeConfig(c.Worktree); err != nil {
+			return err
+		}
+	}
+	if c.Verify != nil {
+		if err := validateVerifyConfig(c.Verify); err != nil {
+			return err
+		}
+	}
+	if c.Context != nil {
+		if err := validateContextConfig(c.Context); err != nil {
+			return err
+		}
+	}
+	if c.Restart != nil {
+		if err := validateRestartConfig(c.Restart); err != nil {
+			return err
+		}
+	}
+	if c.Hooks != nil {
+		if err := ValidateLifecycleHooks(c.Hooks); err != nil {
+			return err
+		}
+	}
+	for k := range c.Env {
+		if !envNamePattern.MatchString(k) {
+			return fmt.Errorf("invalid env variable name %q", k)
+		}
+		if reservedTemplateEnv[k] {
+			return fmt.Errorf("env variable %s is set by gt and cannot be overridden", k)
+		}
+	}
+	return nil
+}
+
+// ValidateLifecycleHooks validates town or rig lifecycle hooks.
+func ValidateLifecycleHooks(h *LifecycleHooks) error {
+	for _, c := range append(append(append([]string{}, h.PreSpawn...), h.PostDone...), h.OnCrash...) {
+		if strings.TrimSpace(c) == "" {
+			return fmt.Errorf("hooks: empty command")
+		}
+	}
+	if h.Timeout != "" {
+		if d, err := time.ParseDuration(h.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("hooks: invalid timeout %q: want a duration like 2m", h.Timeout)
+		}
+	}
+	return nil
+}
+
+// validateRestartConfig validates the rig's supervision policy.
+func validateRestartConfig(c *RestartConfig) error {
+	if err := validateRestartPolicy(&c.RestartPolicy); err != nil {
+		return fmt.Errorf("restart: %w", err)
+	}
+	for name, p := range c.Agents {
+		if _, err := path.Match(name, ""); err != nil {
+			return fmt.Errorf("restart.agents: invalid pattern %q: %w", name, err)
+		}
+		if err := validateRestartPolicy(&p); err != nil {
+			return fmt.Errorf("restart.agents[%s]: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validateRestartPolicy validates one restart policy.
+func validateRestartPolicy(p *RestartPolicy) error {
+	for _, f := range []struct{ name, value string }{
+		{"backoff", p.Backoff}, {"max_backoff", p.MaxBackoff}, {"reset_after", p.ResetAfter},
+	} {
+		if f.value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(f.value); err != nil || d <= 0 {
+			return fmt.Errorf("invalid %s %q: want a positive duration such as \"30s\"", f.name, f.value)
+		}
+	}
+	if p.MaxRestarts < 0 {
+		return fmt.Errorf("max_restarts must not be negative")
+	}
+	return nil
+}
+
+// validateVerifyConfig validates the post-done verify suite.
+func validateVerifyConfig(c *VerifyConfig) error {
+	names := make(map[string]bool)
+	for i, check := range c.Checks {
+		if strings.TrimSpace(check.Run) == "" {
+			return fmt.Errorf("verify check %d: run is required", i+1)
+		}
+		if check.Timeout != "" {
+			if _, err := time.ParseDuration(check.Timeout); err != nil {
+				return fmt.Errorf("verify check %d: invalid timeout: %w", i+1, err)
+			}
+		}
+		if check.Name != "" {
+			if names[check.Name] {
+				return fmt.Errorf("verify check %d: duplicate name %q", i+1, check.Name)
+			}
+			names[check.Name] = true
+		}
+	}
+	return nil
+}
+
+// validateContextConfig validates context index settings.
+func validateContextConfig(c *ContextConfig) error {
+	if c.BudgetTokens < 0 {
+		return fmt.Errorf("context budget_tokens must not be negative, got %d", c.BudgetTokens)
+	}
+	e := c.Embeddings
+	if e == nil {
+		return nil
+	}
+	switch e.Provider {
+	case "", EmbeddingsLocal:
+	case EmbeddingsOpenAI:
+		if e.Model == "" {
+			return fmt.Errorf("%w: context embeddings provider %q needs a model", ErrMissingField, e.Provider)
+		}
+	case EmbeddingsCommand:
+		if len(e.Command) == 0 {
+			return fmt.Errorf("%w: context embeddings provider %q needs a command", ErrMissingField, e.Provider)
+		}
+	default:
+		return fmt.Errorf("unknown context embeddings provider %q: use %s, %s, or %s",
+			e.Provider, EmbeddingsLocal, EmbeddingsOpenAI, EmbeddingsCommand)
+	}
+	return nil
+}
+
+// validateWorktreeConfig validates worktree setup hooks.
+func validateWorktreeConfig(c *WorktreeConfig) error {
+	for _, name := range c.Caches {
+		if _, ok := LookupDepCache(name); !ok {
+			return fmt.Errorf("unknown worktree cache %q: known caches are %s", name, strings.Join(DepCacheNames(), ", "))
+		}
+	}
+	for _, p := range append(append(append([]string{}, c.Sparse...), c.Copy...), c.Link...) {
+		if p == "" || filepath.IsAbs(p) || strings.HasPrefix(filepath.Clean(p), "..") {
+			return fmt.Errorf("invalid worktree path %q: must be relative to the worktree", p)
+		}
+	}
+	for i, h := range c.Setup {
+		for _, p := range append(append([]string{}, h.CacheKey...), h.Outputs...) {
+			if p == "" || filepath.IsAbs(p) || strings.HasPrefix(filepath.Clean(p), "..") {
+				return fmt.Errorf("worktree setup hook %d: invalid path %q: must be relative to the worktree", i+1, p)
+			}
+		}
+		if strings.TrimSpace(h.Run) == "" {
+			return fmt.Errorf("worktree setup hook %d: run is required", i+1)
+		}
+		if h.Timeout != "" {
+			if _, err := time.ParseDuration(h.Timeout); err != nil {
+				return fmt.Errorf("worktree setup hook %d: invalid timeout: %w", i+1, err)
+			}
+		}
+		if (len(h.CacheKey) > 0) != (len(h.Outputs) > 0) {
+			return fmt.Errorf("worktree setup hook %d: cache_key and outputs must be set together", i+1)
+		}
+	}
 	return nil
 }
 
@@ -582,6 +748,12 @@ func validateMessagingConfig(c *MessagingConfig) error {
 		c.NudgeChannels = make(map[string][]string)
 	}
 
+	if c.DefaultTTL != "" {
+		if _, err := time.ParseDuration(c.DefaultTTL); err != nil {
+			return fmt.Errorf("invalid default_ttl %q: %w", c.DefaultTTL, err)
+		}
+	}
+
 	// Validate lists have at least one recipient
 	for name, recipients := range c.Lists {
 		if len(recipients) == 0 {
@@ -619,6 +791,12 @@ func validateMessagingConfig(c *MessagingConfig) error {
 		}
 	}
 
+	for _, w := range c.DND {
+		if err := w.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -726,18 +904,84 @@ func ResolveAgentConfig(townRoot, rigPath string) *RuntimeConfig {
 	// Load custom agent registry if it exists
 	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
 
-	// Determine which agent name to use
-	agentName := ""
-	if rigSettings != nil && rigSettings.Agent != "" {
-		agentName = rigSettings.Agent
-	} else if townSettings.DefaultAgent != "" {
-		agentName = townSettings.DefaultAgent
-	} else {
-		agentName = "claude" // ultimate fallback
+	// Look up the agent configuration
+	return lookupAgentConfig(selectAgentName(rigSettings, townSettings), townSettings)
+}
+
+// ResolveAgentName returns the name of the agent runtime a rig uses, following
+// the same resolution order as ResolveAgentConfig. An empty rigPath resolves
+// the town default (used by town-level agents like the mayor). Rigs that set
+// Runtime directly report the runtime command's base name.
+func ResolveAgentName(townRoot, rigPath string) string {
+	var rigSettings *RigSettings
+	if rigPath != "" {
+		rigSettings, _ = LoadRigSettings(RigSettingsPath(rigPath))
 	}
+	if rigSettings != nil && rigSettings.Runtime != nil {
+		return filepath.Base(fillRuntimeDefaults(rigSettings.Runtime).Command)
+	}
+	townSettings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		townSettings = NewTownSettings()
+	}
+	return selectAgentName(rigSettings, townSettings)
+}
 
-	// Look up the agent configuration
-	return lookupAgentConfig(agentName, townSettings)
+// ResolveCrewRuntime returns the name of the runtime a crew member runs:
+// its runtime binding, then its template's agent, then the rig's.
+func ResolveCrewRuntime(townRoot, rigPath, crewName string) string {
+	b := ReadCrewBinding(rigPath, crewName)
+	if b.Runtime != "" {
+		return b.Runtime
+	}
+	if b.Template != "" {
+		if t, err := LoadAgentTemplate(townRoot, b.Template); err == nil && t.Agent != "" {
+			return t.Agent
+		}
+	}
+	return ResolveAgentName(townRoot, rigPath)
+}
+
+// ResolveAgentRuntime returns the name of the runtime an agent runs, for
+// an address like "gastown/crew/max", "gastown/Toast", or "mayor".
+func ResolveAgentRuntime(townRoot, agent string) string {
+	agent = NormalizeAgentAddress(agent)
+	if parts := strings.Split(agent, "/"); len(parts) == 3 && parts[1] == "crew" {
+		return ResolveCrewRuntime(townRoot, filepath.Join(townRoot, parts[0]), parts[2])
+	}
+	return ResolveAgentName(townRoot, agentRigPath(townRoot, agent))
+}
+
+// KnownRuntime reports whether name is a runtime the town can run: one of
+// its custom agents, or a preset.
+func KnownRuntime(townRoot, name string) bool {
+	if townSettings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot)); err == nil && townSettings.Agents[name] != nil {
+		return true
+	}
+	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
+	return IsKnownPreset(name)
+}
+
+// namedRuntimeConfig returns the configuration of the named runtime: a
+// town custom agent or a preset.
+func namedRuntimeConfig(townRoot, name string) *RuntimeConfig {
+	townSettings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		townSettings = NewTownSettings()
+	}
+	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
+	return lookupAgentConfig(name, townSettings)
+}
+
+// selectAgentName picks the rig's agent, then the town default, then claude.
+func selectAgentName(rigSettings *RigSettings, townSettings *TownSettings) string {
+	if rigSettings != nil && rigSettings.Agent != "" {
+		return rigSettings.Agent
+	}
+	if townSettings != nil && townSettings.DefaultAgent != "" {
+		return townSettings.DefaultAgent
+	}
+	return "claude" // ultimate fallback
 }
 
 // lookupAgentConfig looks up an agent by name.
@@ -769,6 +1013,7 @@ func fillRuntimeDefaults(rc *RuntimeConfig) *RuntimeConfig {
 		Command:       rc.Command,
 		Args:          rc.Args,
 		InitialPrompt: rc.InitialPrompt,
+		NoPromptArg:   rc.NoPromptArg,
 	}
 	if result.Command == "" {
 		result.Command = "claude"
@@ -799,10 +1044,24 @@ func GetRuntimeCommandWithPrompt(rigPath, prompt string) string {
 	return ResolveAgentConfig(townRoot, rigPath).BuildCommandWithPrompt(prompt)
 }
 
+// RigEnv returns the environment the settings of the rig at rigPath export
+// into its agents, or nil if they set none.
+func RigEnv(rigPath string) map[string]string {
+	if rigPath == "" {
+		return nil
+	}
+	settings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil {
+		return nil
+	}
+	return settings.Env
+}
+
 // BuildStartupCommand builds a full startup command with environment exports.
 // envVars is a map of environment variable names to values.
 // rigPath is optional - if empty, uses defaults.
 // prompt is optional - if provided, appended as the initial prompt.
+// The agent runs under the town's resource limits for the rig, if any.
 func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) string {
 	var rc *RuntimeConfig
 	if rigPath != "" {
@@ -812,45 +1071,80 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 	} else {
 		rc = DefaultRuntimeConfig()
 	}
+	return buildStartupCommand(envVars, rc, prompt, RigResourceLimits(rigPath))
+}
 
-	// Build environment export prefix
-	var exports []string
-	for k, v := range envVars {
-		exports = append(exports, fmt.Sprintf("%s=%s", k, v))
-	}
-
-	// Sort for deterministic output
-	sort.Strings(exports)
-
+// buildStartupCommand builds a startup command running rc under limits.
+func buildStartupCommand(envVars map[string]string, rc *RuntimeConfig, prompt string, limits *ResourceLimits) string {
 	var cmd string
-	if len(exports) > 0 {
-		cmd = "export " + strings.Join(exports, " ") + " && "
+	if prefix := exportPrefix(envVars); prefix != "" {
+		cmd = prefix + " && "
 	}
 
 	// Add runtime command
 	if prompt != "" {
-		cmd += rc.BuildCommandWithPrompt(prompt)
+		cmd += limitCommand(limits, rc.BuildCommandWithPrompt(prompt))
 	} else {
-		cmd += rc.BuildCommand()
+		cmd += limitCommand(limits, rc.BuildCommand())
 	}
 
 	return cmd
 }
 
+// exportPrefix returns the shell command exporting envVars, or "".
+func exportPrefix(envVars map[string]string) string {
+	var exports []string
+	for k, v := range envVars {
+		if strings.IndexFunc(v, unsafeInShell) >= 0 {
+			v = quoteForShell(v)
+		}
+		exports = append(exports, fmt.Sprintf("%s=%s", k, v))
+	}
+	if len(exports) == 0 {
+		return ""
+	}
+
+	// Sort for deterministic output
+	sort.Strings(exports)
+	return "export " + strings.Join(exports, " ")
+}
+
 // BuildAgentStartupCommand is a convenience function for starting agent sessions.
-// It sets standard environment variables (GT_ROLE, BD_ACTOR, GIT_AUTHOR_NAME)
-// and builds the full startup command.
+// It sets standard environment variables (GT_ROLE, BD_ACTOR, GIT_AUTHOR_NAME),
+// plus the environment of the settings of the rig at rigPath, if any, and
+// builds the full startup command.
 func BuildAgentStartupCommand(role, bdActor, rigPath, prompt string) string {
 	envVars := map[string]string{
 		"GT_ROLE":         role,
 		"BD_ACTOR":        bdActor,
 		"GIT_AUTHOR_NAME": bdActor,
 	}
+	for k, v := range RigEnv(rigPath) {
+		envVars[k] = v
+	}
 	return BuildStartupCommand(envVars, rigPath, prompt)
 }
 
+// BuildRigAgentStartupCommand builds the startup command for a rig's
+// witness or refinery: that of BuildAgentStartupCommand with the town's
+// default runtime, plus the environment of the settings of the rig at
+// rigPath.
+func BuildRigAgentStartupCommand(role, bdActor, rigPath, prompt string) string {
+	envVars := map[string]string{
+		"GT_ROLE":         role,
+		"BD_ACTOR":        bdActor,
+		"GIT_AUTHOR_NAME": bdActor,
+	}
+	for k, v := range RigEnv(rigPath) {
+		envVars[k] = v
+	}
+	return BuildStartupCommand(envVars, "", prompt)
+}
+
 // BuildPolecatStartupCommand builds the startup command for a polecat.
-// Sets GT_ROLE, GT_RIG, GT_POLECAT, BD_ACTOR, and GIT_AUTHOR_NAME.
+// Sets GT_ROLE, GT_RIG, GT_POLECAT, BD_ACTOR, and GIT_AUTHOR_NAME, plus the
+// rig's shared dependency cache variables and the environment of its
+// settings.
 func BuildPolecatStartupCommand(rigName, polecatName, rigPath, prompt string) string {
 	bdActor := fmt.Sprintf("%s/polecats/%s", rigName, polecatName)
 	envVars := map[string]string{
@@ -860,12 +1154,148 @@ func BuildPolecatStartupCommand(rigName, polecatName, rigPath, prompt string) st
 		"BD_ACTOR":        bdActor,
 		"GIT_AUTHOR_NAME": polecatName,
 	}
+	for k, v := range RigDepCacheEnv(rigPath) {
+		envVars[k] = v
+	}
+	for k, v := range RigEnv(rigPath) {
+		envVars[k] = v
+	}
 	return BuildStartupCommand(envVars, rigPath, prompt)
 }
 
 // BuildCrewStartupCommand builds the startup command for a crew member.
-// Sets GT_ROLE, GT_RIG, GT_CREW, BD_ACTOR, and GIT_AUTHOR_NAME.
+// Sets GT_ROLE, GT_RIG, GT_CREW, BD_ACTOR, and GIT_AUTHOR_NAME, plus the
+// rig's shared dependency cache variables and the environment of its
+// settings. A crew member bound to an agent template (see SetCrewBinding)
+// also gets the template's runtime, flags, prompt, and environment, and
+// GT_TEMPLATE; one bound to a runtime runs that runtime. One bound to a
+// sandbox runs it in a container of the town's sandbox image, without the
+// dependency caches, which live outside its worktree; one bound to a host
+// runs it there over ssh, also without them.
 func BuildCrewStartupCommand(rigName, crewName, rigPath, prompt string) string {
+	return PlanCrewStartup(rigName, crewName, rigPath, prompt, ReadCrewBinding(rigPath, crewName)).Command
+}
+
+// CrewTypedPrompt returns the startup prompt to type into a crew member's
+// session once its runtime is up, for runtimes that take none on their
+// command line (RuntimeConfig.NoPromptArg), or "".
+func CrewTypedPrompt(rigName, crewName, rigPath, prompt string) string {
+	_, rc, prompt := crewStartup(rigName, crewName, rigPath, prompt, ReadCrewBinding(rigPath, crewName))
+	if !rc.NoPromptArg {
+		return ""
+	}
+	return prompt
+}
+
+// CrewBinding is what a crew member is bound to: the names of the agent
+// template, role, runtime, sandbox, and host it starts with, "" for none.
+type CrewBinding struct {
+	Template string `json:"template,omitempty"`
+	Role     string `json:"role,omitempty"`
+	Runtime  string `json:"runtime,omitempty"`
+	Sandbox  string `json:"sandbox,omitempty"`
+	Host     string `json:"host,omitempty"`
+}
+
+// CrewBindingPath returns where a crew member's binding is kept.
+func CrewBindingPath(rigPath, crewName string) string {
+	return filepath.Join(rigPath, "crew", crewName, ".runtime", "binding.json")
+}
+
+// SetCrewBinding binds a crew member to b, replacing its whole binding at
+// once, so every later start of its session applies it and none sees half
+// of a change. An empty binding removes the file.
+func SetCrewBinding(rigPath, crewName string, b CrewBinding) error {
+	path := CrewBindingPath(rigPath, crewName)
+	if b == (CrewBinding{}) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, b)
+}
+
+// ReadCrewBinding returns what a crew member is bound to. A missing or
+// unreadable binding file means it is bound to nothing.
+func ReadCrewBinding(rigPath, crewName string) CrewBinding {
+	var b CrewBinding
+	if rigPath == "" {
+		return b
+	}
+	data, err := os.ReadFile(CrewBindingPath(rigPath, crewName)) //nolint:gosec // G304: path is within the rig
+	if err == nil && json.Unmarshal(data, &b) != nil {
+		b = CrewBinding{}
+	}
+	return b
+}
+
+// CrewStartup is how a crew member's runtime is started.
+type CrewStartup struct {
+	Env     map[string]string // the environment set for the runtime
+	Runtime string            // the runtime's command, e.g. "claude"
+	Command string            // the startup command typed into its shell
+	Prompt  string            // the startup prompt, on the command line or typed in
+}
+
+// PlanCrewStartup returns how a crew member bound to b is started (see
+// BuildCrewStartupCommand), whatever it is bound to now.
+func PlanCrewStartup(rigName, crewName, rigPath, prompt string, b CrewBinding) CrewStartup {
+	envVars, rc, prompt := crewStartup(rigName, crewName, rigPath, prompt, b)
+	plan := CrewStartup{Env: envVars, Runtime: rc.Command, Prompt: prompt}
+	if rigPath != "" && b.Sandbox == SandboxDocker {
+		townSettings, err := LoadOrCreateTownSettings(TownSettingsPath(filepath.Dir(rigPath)))
+		if err != nil || townSettings.Sandbox == nil || townSettings.Sandbox.Image == "" {
+			// Never fall back to running on the host
+			plan.Command = "echo " + quoteForShell("gt: no sandbox image in the town's settings/config.json; not starting "+crewName) + " >&2"
+			return plan
+		}
+		for k := range RigDepCacheEnv(rigPath) {
+			delete(envVars, k)
+		}
+		workDir := filepath.Join(rigPath, "crew", crewName)
+		plan.Command = buildSandboxedCommand(envVars, rc, prompt, townSettings.Sandbox, RigResourceLimits(rigPath), workDir, fmt.Sprintf("%s/crew/%s", rigName, crewName))
+		return plan
+	}
+	if rigPath != "" && b.Host != "" {
+		h, err := LoadHost(filepath.Dir(rigPath), b.Host)
+		if err != nil {
+			// Never fall back to running locally
+			plan.Command = "echo " + quoteForShell(fmt.Sprintf("gt: %v; not starting %s", err, crewName)) + " >&2"
+			return plan
+		}
+		for k := range RigDepCacheEnv(rigPath) {
+			delete(envVars, k)
+		}
+		envVars["GT_HOST"] = h.Name
+		plan.Command = buildRemoteCommand(envVars, rc, prompt, h, rigName, crewName)
+		return plan
+	}
+	plan.Command = buildStartupCommand(envVars, rc, prompt, RigResourceLimits(rigPath))
+	return plan
+}
+
+// crewStartup returns the environment, runtime, and startup prompt of a
+// crew member bound to b.
+func crewStartup(rigName, crewName, rigPath, prompt string, b CrewBinding) (map[string]string, *RuntimeConfig, string) {
+	envVars, rc, prompt := crewTemplateStartup(rigName, crewName, rigPath, prompt, b)
+	if rigPath == "" || b.Role == "" {
+		return envVars, rc, prompt
+	}
+	r, err := LoadAgentRole(filepath.Dir(rigPath), b.Role)
+	if err != nil {
+		return envVars, rc, prompt
+	}
+	envVars["GT_AGENT_ROLE"] = b.Role
+	return envVars, r.applyTo(rc), r.startupPrompt(rigName, crewName, prompt)
+}
+
+// crewTemplateStartup returns the environment, runtime, and startup
+// prompt of a crew member bound to b, before its role is applied.
+func crewTemplateStartup(rigName, crewName, rigPath, prompt string, b CrewBinding) (map[string]string, *RuntimeConfig, string) {
 	bdActor := fmt.Sprintf("%s/crew/%s", rigName, crewName)
 	envVars := map[string]string{
 		"GT_ROLE":         "crew",
@@ -874,5 +1304,36 @@ func BuildCrewStartupCommand(rigName, crewName, rigPath, prompt string) string {
 		"BD_ACTOR":        bdActor,
 		"GIT_AUTHOR_NAME": crewName,
 	}
-	return BuildStartupCommand(envVars, rigPath, prompt)
+	for k, v := range RigDepCacheEnv(rigPath) {
+		envVars[k] = v
+	}
+	for k, v := range RigEnv(rigPath) {
+		envVars[k] = v
+	}
+	if rigPath == "" {
+		return envVars, DefaultRuntimeConfig(), prompt
+	}
+	townRoot := filepath.Dir(rigPath)
+	if b.Template != "" {
+		if t, err := LoadAgentTemplate(townRoot, b.Template); err == nil {
+			for k, v := range t.Env {
+				envVars[k] = v
+			}
+			envVars["GT_TEMPLATE"] = b.Template
+			return envVars, t.runtimeConfig(townRoot, rigPath, b.Runtime), t.startupPrompt(prompt)
+		}
+	}
+	if b.Runtime != "" {
+		return envVars, namedRuntimeConfig(townRoot, b.Runtime), prompt
+	}
+	return envVars, ResolveAgentConfig(townRoot, rigPath), prompt
+}
+
+// unsafeInShell reports whether r needs quoting in a shell word.
+func unsafeInShell(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	}
+	return !strings.ContainsRune("_-./:@%+,=", r)
 }
@@ -310,6 +310,90 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid verify checks",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Verify: &VerifyConfig{Checks: []VerifyCheck{
+					{Name: "build", Run: "go build ./..."},
+					{Name: "lint", Run: "golangci-lint run", Timeout: "5m", Optional: true},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "verify check without run",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Verify:  &VerifyConfig{Checks: []VerifyCheck{{Name: "build"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate verify check",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Verify: &VerifyConfig{Checks: []VerifyCheck{
+					{Name: "test", Run: "go test ./..."},
+					{Name: "test", Run: "make test"},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid restart policy",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Restart: &RestartConfig{
+					RestartPolicy: RestartPolicy{Enabled: true, Backoff: "30s", MaxRestarts: 3},
+					Agents:        map[string]RestartPolicy{"polecats/*": {Enabled: false}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "restart policy with bad backoff",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Restart: &RestartConfig{Agents: map[string]RestartPolicy{"crew/max": {Enabled: true, Backoff: "soon"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid context embeddings",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Context: &ContextConfig{
+					Embeddings:   &EmbeddingsConfig{Provider: EmbeddingsOpenAI, Model: "nomic-embed-text", URL: "http://localhost:11434/v1"},
+					BudgetTokens: 1500,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "context embeddings without model",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Context: &ContextConfig{Embeddings: &EmbeddingsConfig{Provider: EmbeddingsOpenAI}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown context embeddings provider",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Context: &ContextConfig{Embeddings: &EmbeddingsConfig{Provider: "magic"}},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -931,6 +1015,34 @@ func TestBuildCrewStartupCommand(t *testing.T) {
 	}
 }
 
+func TestRigEnv(t *testing.T) {
+	rigPath := filepath.Join(t.TempDir(), "gastown")
+	settings := NewRigSettings()
+	settings.Env = map[string]string{"API_URL": "https://api.test", "GOFLAGS": "-mod=mod -tags=e2e"}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, cmd := range map[string]string{
+		"polecat": BuildPolecatStartupCommand("gastown", "toast", rigPath, ""),
+		"crew":    BuildCrewStartupCommand("gastown", "max", rigPath, ""),
+		"witness": BuildRigAgentStartupCommand("witness", "gastown/witness", rigPath, ""),
+	} {
+		for _, want := range []string{"API_URL=https://api.test", `GOFLAGS="-mod=mod -tags=e2e"`} {
+			if !strings.Contains(cmd, want) {
+				t.Errorf("%s command missing %s:\n%s", name, want, cmd)
+			}
+		}
+	}
+
+	for _, env := range []map[string]string{{"GT_ROLE": "mayor"}, {"BAD-NAME": "x"}} {
+		settings.Env = env
+		if err := SaveRigSettings(RigSettingsPath(rigPath), settings); err == nil {
+			t.Errorf("rig env %v accepted", env)
+		}
+	}
+}
+
 func TestLoadRuntimeConfigFromSettings(t *testing.T) {
 	// Create temp rig with custom runtime config
 	dir := t.TempDir()
@@ -970,3 +1082,26 @@ func TestLoadRuntimeConfigFallsBackToDefaults(t *testing.T) {
 		t.Errorf("Command = %q, want %q (default)", rc.Command, "claude")
 	}
 }
+
+func TestCrewBindingRoundTrip(t *testing.T) {
+	rigPath := filepath.Join(t.TempDir(), "gastown")
+	full := CrewBinding{Template: "reviewer", Role: "tester", Runtime: "aider", Sandbox: SandboxDocker, Host: "build-box-2"}
+	if err := SetCrewBinding(rigPath, "max", full); err != nil {
+		t.Fatal(err)
+	}
+	if got := ReadCrewBinding(rigPath, "max"); got != full {
+		t.Errorf("ReadCrewBinding = %+v, want %+v", got, full)
+	}
+
+	// Rebinding replaces the whole binding, leaving nothing of the old one
+	if err := SetCrewBinding(rigPath, "max", CrewBinding{Runtime: "codex"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ReadCrewBinding(rigPath, "max"); got != (CrewBinding{Runtime: "codex"}) {
+		t.Errorf("ReadCrewBinding after rebinding = %+v", got)
+	}
+	entries, err := os.ReadDir(filepath.Dir(CrewBindingPath(rigPath, "max")))
+	if err != nil || len(entries) != 1 || entries[0].Name() != "binding.json" {
+		t.Errorf(".runtime holds %v, %v; want binding.json alone", entries, err)
+	}
+}
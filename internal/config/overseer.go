@@ -18,6 +18,10 @@ type OverseerConfig struct {
 	Email    string `json:"email,omitempty"`    // email address
 	Username string `json:"username,omitempty"` // username/handle
 	Source   string `json:"source"`             // how identity was detected
+
+	// Accessible selects screen-reader friendly plain output for this
+	// operator (see GT_ACCESSIBLE).
+	Accessible bool `json:"accessible,omitempty"`
 }
 
 // CurrentOverseerVersion is the current schema version for OverseerConfig.
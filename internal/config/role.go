@@ -0,0 +1,240 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// AgentRole is what a crew agent is for: the prompt it starts with, the
+// tools it may use, and the Claude Code hooks its sessions run. Roles are
+// applied with 'gt spawn --role'. The built-in roles (reviewer, tester)
+// apply unless the town defines a role of the same name in its
+// settings/roles/<name>.json.
+type AgentRole struct {
+	Type    string `json:"type"`    // "agent-role"
+	Version int    `json:"version"` // schema version
+
+	// Name is the role's file name without .json; set on load.
+	Name string `json:"-"`
+
+	// Builtin is set for a built-in role the town does not override.
+	Builtin bool `json:"-"`
+
+	// Description says what agents in the role do.
+	Description string `json:"description,omitempty"`
+
+	// Prompt is a text/template sent to the agent at startup, ahead of
+	// its template's prompt and gt's own. It is rendered with .Rig, .Name,
+	// and .Address (e.g. "gastown/crew/rex").
+	Prompt string `json:"prompt,omitempty"`
+
+	// AllowedTools are the tools the agent may use without asking, in
+	// Claude Code's syntax (e.g. "Read", "Bash(go test:*)"). With any,
+	// the runtime asks before using other tools, rather than skipping
+	// permission checks. Empty allows every tool.
+	AllowedTools []string `json:"allowed_tools,omitempty"`
+
+	// DisallowedTools are tools the agent may never use.
+	DisallowedTools []string `json:"disallowed_tools,omitempty"`
+
+	// Hooks are commands Claude Code runs at points of the agent's
+	// session, by event (e.g. "Stop": ["gt costs record"]), in addition
+	// to those of its workspace's settings.
+	Hooks map[string][]string `json:"hooks,omitempty"`
+}
+
+// CurrentAgentRoleVersion is the current schema version of agent roles.
+const CurrentAgentRoleVersion = 1
+
+// RoleHookEvents are the Claude Code events a role can hook.
+var RoleHookEvents = []string{
+	"SessionStart", "UserPromptSubmit", "PreToolUse", "PostToolUse",
+	"Notification", "PreCompact", "Stop", "SubagentStop", "SessionEnd",
+}
+
+// GasTownRoles are the roles gt itself runs agents in; an agent role
+// cannot take their names.
+var GasTownRoles = []string{"mayor", "deacon", "witness", "refinery", "polecat", "crew"}
+
+// builtinAgentRoles are the roles every town has.
+var builtinAgentRoles = map[string]AgentRole{
+	"reviewer": {
+		Description: "Reviews changes without making them",
+		Prompt: "You are {{.Address}}, a reviewer. Review the changes you are asked to: read the diff and the " +
+			"code around it, run the tests, and report what is wrong or missing. Do not edit files, commit, or push.",
+		AllowedTools:    []string{"Read", "Grep", "Glob", "Bash(git diff:*)", "Bash(git log:*)", "Bash(git show:*)", "Bash(go test:*)", "Bash(gt:*)", "Bash(bd:*)"},
+		DisallowedTools: []string{"Edit", "Write", "NotebookEdit", "Bash(git commit:*)", "Bash(git push:*)"},
+	},
+	"tester": {
+		Description: "Writes and runs tests",
+		Prompt: "You are {{.Address}}, a tester. Write tests for the work you are asked to cover, run them, and " +
+			"report failures with the command that reproduces them. Change tests, not the code under test.",
+		DisallowedTools: []string{"Bash(git push:*)"},
+	},
+}
+
+// AgentRolesDir returns the directory holding a town's agent roles.
+func AgentRolesDir(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "roles")
+}
+
+// LoadAgentRole loads and validates the named agent role: the town's, or
+// the built-in one.
+func LoadAgentRole(townRoot, name string) (*AgentRole, error) {
+	if !templateNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid role name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	if slices.Contains(GasTownRoles, name) {
+		return nil, fmt.Errorf("%s is a Gas Town role, which gt starts agents in itself", name)
+	}
+	path := filepath.Join(AgentRolesDir(townRoot), name+".json")
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town's settings
+	if os.IsNotExist(err) {
+		if r, ok := builtinAgentRoles[name]; ok {
+			r.Name, r.Builtin = name, true
+			return &r, nil
+		}
+		return nil, fmt.Errorf("%w: role %q (%s)", ErrNotFound, name, path)
+	} else if err != nil {
+		return nil, fmt.Errorf("reading role: %w", err)
+	}
+	var r AgentRole
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing role %s: %w", path, err)
+	}
+	r.Name = name
+	if err := validateAgentRole(&r); err != nil {
+		return nil, fmt.Errorf("role %s: %w", name, err)
+	}
+	return &r, nil
+}
+
+// ListAgentRoles returns the names of the built-in roles and the town's,
+// sorted.
+func ListAgentRoles(townRoot string) ([]string, error) {
+	var names []string
+	for name := range builtinAgentRoles {
+		names = append(names, name)
+	}
+	entries, err := os.ReadDir(AgentRolesDir(townRoot))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if ok && !e.IsDir() && templateNamePattern.MatchString(name) && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// validateAgentRole validates an agent role.
+func validateAgentRole(r *AgentRole) error {
+	if r.Type != "agent-role" && r.Type != "" {
+		return fmt.Errorf("%w: expected type 'agent-role', got '%s'", ErrInvalidType, r.Type)
+	}
+	if r.Version > CurrentAgentRoleVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, r.Version, CurrentAgentRoleVersion)
+	}
+	if _, err := r.RenderPrompt("rig", "name"); err != nil {
+		return err
+	}
+	for event := range r.Hooks {
+		if !slices.Contains(RoleHookEvents, event) {
+			return fmt.Errorf("unknown hook event %q (events: %s)", event, strings.Join(RoleHookEvents, ", "))
+		}
+	}
+	return nil
+}
+
+// RenderPrompt renders the role's prompt for the crew member name of rig.
+func (r *AgentRole) RenderPrompt(rig, name string) (string, error) {
+	if r.Prompt == "" {
+		return "", nil
+	}
+	tmpl, err := template.New(r.Name).Option("missingkey=error").Parse(r.Prompt)
+	if err != nil {
+		return "", fmt.Errorf("parsing prompt: %w", err)
+	}
+	var b strings.Builder
+	data := struct{ Rig, Name, Address string }{rig, name, rig + "/crew/" + name}
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("rendering prompt: %w", err)
+	}
+	return b.String(), nil
+}
+
+// applyTo returns rc with the role's tools and hooks added to its flags.
+// Only Claude Code takes them; other runtimes are returned as they are.
+func (r *AgentRole) applyTo(rc *RuntimeConfig) *RuntimeConfig {
+	if rc.Command != "" && filepath.Base(rc.Command) != "claude" {
+		return rc
+	}
+	out := *rc
+	if out.Args == nil {
+		out.Args = DefaultRuntimeConfig().Args
+	}
+	var args []string
+	for _, a := range out.Args {
+		// Skipping permission checks would let the agent use any tool
+		if a != "--dangerously-skip-permissions" || len(r.AllowedTools) == 0 {
+			args = append(args, a)
+		}
+	}
+	if len(r.AllowedTools) > 0 {
+		args = append(args, "--allowedTools", shellWord(strings.Join(r.AllowedTools, ",")))
+	}
+	if len(r.DisallowedTools) > 0 {
+		args = append(args, "--disallowedTools", shellWord(strings.Join(r.DisallowedTools, ",")))
+	}
+	if len(r.Hooks) > 0 {
+		args = append(args, "--settings", util.ShellQuote(r.settingsJSON()))
+	}
+	out.Args = args
+	return &out
+}
+
+// settingsJSON returns Claude Code settings holding the role's hooks.
+func (r *AgentRole) settingsJSON() string {
+	type hook struct {
+		Type    string `json:"type"`
+		Command string `json:"command"`
+	}
+	type matcher struct {
+		Matcher string `json:"matcher"`
+		Hooks   []hook `json:"hooks"`
+	}
+	hooks := make(map[string][]matcher)
+	for event, commands := range r.Hooks {
+		m := matcher{}
+		for _, c := range commands {
+			m.Hooks = append(m.Hooks, hook{Type: "command", Command: c})
+		}
+		hooks[event] = []matcher{m}
+	}
+	data, _ := json.Marshal(map[string]any{"hooks": hooks})
+	return string(data)
+}
+
+// startupPrompt combines the role's prompt for a crew member with the
+// prompt it would start with otherwise.
+func (r *AgentRole) startupPrompt(rig, name, prompt string) string {
+	rolePrompt, err := r.RenderPrompt(rig, name)
+	switch {
+	case err != nil || rolePrompt == "":
+		return prompt
+	case prompt == "":
+		return rolePrompt
+	}
+	return rolePrompt + "\n\n" + prompt
+}
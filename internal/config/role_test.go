@@ -0,0 +1,110 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRole(t *testing.T, townRoot, name, content string) {
+	t.Helper()
+	dir := AgentRolesDir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadAgentRole(t *testing.T) {
+	town := t.TempDir()
+	writeRole(t, town, "tester", `{"type": "agent-role", "version": 1, "description": "Our testers",
+		"prompt": "You test {{.Rig}}.", "hooks": {"Stop": ["make test-report"]}}`)
+	writeRole(t, town, "bad-hook", `{"hooks": {"OnCrash": ["true"]}}`)
+	writeRole(t, town, "bad-prompt", `{"prompt": "You are {{.Nmae}}."}`)
+
+	r, err := LoadAgentRole(town, "reviewer")
+	if err != nil {
+		t.Fatalf("LoadAgentRole(reviewer): %v", err)
+	}
+	if !r.Builtin || r.Name != "reviewer" || len(r.AllowedTools) == 0 {
+		t.Errorf("built-in reviewer = %+v", r)
+	}
+	r, err = LoadAgentRole(town, "tester")
+	if err != nil {
+		t.Fatalf("LoadAgentRole(tester): %v", err)
+	}
+	if r.Builtin || r.Description != "Our testers" {
+		t.Errorf("the town's tester = %+v, want it to override the built-in", r)
+	}
+
+	if _, err := LoadAgentRole(town, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing role: err = %v, want ErrNotFound", err)
+	}
+	for _, name := range []string{"bad-hook", "bad-prompt", "polecat", "../tester"} {
+		if _, err := LoadAgentRole(town, name); err == nil {
+			t.Errorf("LoadAgentRole(%q) succeeded, want an error", name)
+		}
+	}
+
+	names, err := ListAgentRoles(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(names, ","); got != "bad-hook,bad-prompt,reviewer,tester" {
+		t.Errorf("ListAgentRoles = %s", got)
+	}
+}
+
+func TestBuildCrewStartupCommandWithRole(t *testing.T) {
+	town := t.TempDir()
+	rigPath := filepath.Join(town, "gastown")
+	writeRole(t, town, "auditor", `{"prompt": "You are {{.Address}}, an auditor.",
+		"allowed_tools": ["Read", "Bash(git log:*)"], "disallowed_tools": ["Write"],
+		"hooks": {"Stop": ["echo $GT_CREW done"]}}`)
+
+	if err := SetCrewBinding(rigPath, "rex", CrewBinding{Role: "auditor"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ReadCrewBinding(rigPath, "rex").Role; got != "auditor" {
+		t.Fatalf("bound role = %q", got)
+	}
+	plan := PlanCrewStartup("gastown", "rex", rigPath, "gt prime", ReadCrewBinding(rigPath, "rex"))
+	if plan.Env["GT_AGENT_ROLE"] != "auditor" {
+		t.Errorf("GT_AGENT_ROLE = %q", plan.Env["GT_AGENT_ROLE"])
+	}
+	if plan.Prompt != "You are gastown/crew/rex, an auditor.\n\ngt prime" {
+		t.Errorf("Prompt = %q", plan.Prompt)
+	}
+	for _, want := range []string{
+		`--allowedTools "Read,Bash(git log:*)"`,
+		"--disallowedTools Write",
+		`--settings '{"hooks":{"Stop":[{"matcher":"","hooks":[{"type":"command","command":"echo $GT_CREW done"}]}]}}'`,
+	} {
+		if !strings.Contains(plan.Command, want) {
+			t.Errorf("command missing %s:\n%s", want, plan.Command)
+		}
+	}
+	if strings.Contains(plan.Command, "--dangerously-skip-permissions") {
+		t.Errorf("command skips permission checks despite allowed_tools:\n%s", plan.Command)
+	}
+
+	// Runtimes other than Claude Code get the prompt only
+	if err := SetCrewBinding(rigPath, "rex", CrewBinding{Role: "auditor", Runtime: "aider"}); err != nil {
+		t.Fatal(err)
+	}
+	plan = PlanCrewStartup("gastown", "rex", rigPath, "gt prime", ReadCrewBinding(rigPath, "rex"))
+	if strings.Contains(plan.Command, "--allowedTools") || !strings.HasPrefix(plan.Prompt, "You are gastown/crew/rex") {
+		t.Errorf("aider plan = %+v", plan)
+	}
+
+	if err := SetCrewBinding(rigPath, "rex", CrewBinding{Runtime: "aider"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ReadCrewBinding(rigPath, "rex").Role; got != "" {
+		t.Errorf("bound role after unbinding = %q", got)
+	}
+}
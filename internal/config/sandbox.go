@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Sandboxed agents ('gt spawn --sandbox docker') run their runtime in a
+// docker container started in their tmux session. The container sees only
+// the agent's worktree, mounted at the same path, and the town's
+// sandbox.mounts; its home directory is an empty tmpfs. The environment gt
+// sets for the agent is passed in, and the town's resource limits for the
+// rig become the container's.
+
+// SandboxDocker is the docker sandbox.
+const SandboxDocker = "docker"
+
+// Sandboxes lists the supported sandboxes.
+var Sandboxes = []string{SandboxDocker}
+
+// ValidateSandbox checks that the town can run agents in the named sandbox:
+// it is supported, the town sets an image, and docker is installed.
+func ValidateSandbox(townRoot, name string) error {
+	if name != SandboxDocker {
+		return fmt.Errorf("unknown sandbox %q (supported: %s)", name, strings.Join(Sandboxes, ", "))
+	}
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.Sandbox == nil || settings.Sandbox.Image == "" {
+		return fmt.Errorf("no sandbox image: set sandbox.image in %s to an image with the agent runtime installed", TownSettingsPath(townRoot))
+	}
+	if _, err := exec.LookPath("docker"); err != nil {
+		return fmt.Errorf("the docker sandbox needs docker: %w", err)
+	}
+	return nil
+}
+
+// SandboxLabel is the docker label naming the agent a container runs,
+// e.g. gastown.agent=gastown/crew/max.
+const SandboxLabel = "gastown.agent"
+
+// buildSandboxedCommand builds a startup command running rc in a container
+// of c, with workDir mounted, envVars passed in, and limits enforced.
+func buildSandboxedCommand(envVars map[string]string, rc *RuntimeConfig, prompt string, c *SandboxConfig, limits *ResourceLimits, workDir, agent string) string {
+	runtimeCmd := rc.BuildCommand()
+	if prompt != "" {
+		runtimeCmd = rc.BuildCommandWithPrompt(prompt)
+	}
+
+	args := []string{"docker", "run", "--rm", "-it", "--init",
+		"--label", shellWord(SandboxLabel + "=" + agent),
+		"--user", `"$(id -u):$(id -g)"`,
+		"--tmpfs", `"$HOME:mode=1777"`, "-e", `HOME="$HOME"`,
+		"-v", shellWord(workDir + ":" + workDir), "-w", shellWord(workDir),
+	}
+	for _, m := range c.Mounts {
+		path, mode, _ := strings.Cut(m, ":")
+		if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			path = `"$HOME"/` + shellWord(rest)
+		} else {
+			path = shellWord(path)
+		}
+		mount := path + ":" + path
+		if mode != "" {
+			mount += ":" + shellWord(mode)
+		}
+		args = append(args, "-v", mount)
+	}
+	names := make([]string, 0, len(envVars))
+	for k := range envVars {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		args = append(args, "-e", k)
+	}
+	args = append(args, limits.dockerFlags()...)
+	if c.Network != "" {
+		args = append(args, "--network", shellWord(c.Network))
+	}
+	for _, a := range c.Args {
+		args = append(args, shellWord(a))
+	}
+	args = append(args, shellWord(c.Image), runtimeCmd)
+
+	cmd := strings.Join(args, " ")
+	if prefix := exportPrefix(envVars); prefix != "" {
+		cmd = prefix + " && " + cmd
+	}
+	return cmd
+}
+
+// dockerFlags returns the docker run flags enforcing l. Memory given as a
+// share of the machine's memory cannot be expressed and is left out.
+func (l *ResourceLimits) dockerFlags() []string {
+	if l.IsZero() {
+		return nil
+	}
+	var flags []string
+	if l.CPU > 0 {
+		flags = append(flags, "--cpus", fmt.Sprintf("%g", l.CPU))
+	}
+	if l.Memory != "" && memorySize.MatchString(l.Memory) && !strings.HasSuffix(l.Memory, "%") {
+		// No swap either, as for systemd
+		flags = append(flags, "--memory", strings.ToLower(l.Memory), "--memory-swap", strings.ToLower(l.Memory))
+	}
+	if l.Tasks > 0 {
+		flags = append(flags, "--pids-limit", fmt.Sprintf("%d", l.Tasks))
+	}
+	return flags
+}
+
+// shellWord quotes s for the shell if it needs it.
+func shellWord(s string) string {
+	if strings.IndexFunc(s, unsafeInShell) >= 0 {
+		return quoteForShell(s)
+	}
+	return s
+}
+
+// AgentSandbox returns the sandbox an agent runs in, for an address like
+// "gastown/crew/max", or "" if it runs on the host.
+func AgentSandbox(townRoot, agent string) string {
+	if parts := strings.Split(NormalizeAgentAddress(agent), "/"); len(parts) == 3 && parts[1] == "crew" {
+		return ReadCrewBinding(filepath.Join(townRoot, parts[0]), parts[2]).Sandbox
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSandboxSettings(t *testing.T, townRoot string, sandbox *SandboxConfig, limits *ResourceLimitsConfig) {
+	t.Helper()
+	settings := NewTownSettings()
+	settings.Sandbox = sandbox
+	settings.Limits = limits
+	data, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(TownSettingsPath(townRoot), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateSandbox(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := ValidateSandbox(townRoot, "podman"); err == nil || !strings.Contains(err.Error(), "unknown sandbox") {
+		t.Errorf("ValidateSandbox(podman) = %v", err)
+	}
+	if err := ValidateSandbox(townRoot, SandboxDocker); err == nil || !strings.Contains(err.Error(), "sandbox.image") {
+		t.Errorf("ValidateSandbox without an image = %v", err)
+	}
+}
+
+func TestBuildCrewStartupCommandSandboxed(t *testing.T) {
+	ResetRegistryForTesting()
+	t.Cleanup(ResetRegistryForTesting)
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+
+	if err := SetCrewBinding(rigPath, "box", CrewBinding{Sandbox: SandboxDocker}); err != nil {
+		t.Fatal(err)
+	}
+	if got := AgentSandbox(townRoot, "gastown/crew/box"); got != SandboxDocker {
+		t.Fatalf("AgentSandbox = %q", got)
+	}
+
+	// Without an image the agent is not started on the host instead
+	cmd := BuildCrewStartupCommand("gastown", "box", rigPath, "gt prime")
+	if !strings.HasPrefix(cmd, "echo ") || strings.Contains(cmd, "claude") {
+		t.Errorf("command without a sandbox image = %s", cmd)
+	}
+
+	writeSandboxSettings(t, townRoot, &SandboxConfig{
+		Image:   "agent:latest",
+		Network: "none",
+		Mounts:  []string{"~/.claude", "/opt/tools:ro"},
+		Args:    []string{"--cap-drop", "ALL"},
+	}, &ResourceLimitsConfig{Rigs: map[string]*ResourceLimits{"gastown": {CPU: 2, Memory: "4G", Tasks: 512}}})
+	cmd = BuildCrewStartupCommand("gastown", "box", rigPath, "gt prime")
+	workDir := filepath.Join(rigPath, "crew", "box")
+	for _, want := range []string{
+		"export ", " && docker run --rm -it --init ",
+		"--label gastown.agent=gastown/crew/box",
+		"-v " + workDir + ":" + workDir + " -w " + workDir,
+		`-v "$HOME"/.claude:"$HOME"/.claude `,
+		"-v /opt/tools:/opt/tools:ro ",
+		"-e GT_CREW -e GT_RIG -e GT_ROLE ",
+		"--cpus 2 --memory 4g --memory-swap 4g --pids-limit 512",
+		"--network none --cap-drop ALL agent:latest claude ",
+		"gt prime",
+	} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("sandboxed command lacks %q:\n%s", want, cmd)
+		}
+	}
+	if strings.Contains(cmd, "systemd-run") {
+		t.Errorf("sandboxed command should limit the container, not the client:\n%s", cmd)
+	}
+
+	if err := SetCrewBinding(rigPath, "box", CrewBinding{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := AgentSandbox(townRoot, "gastown/crew/box"); got != "" {
+		t.Errorf("AgentSandbox after unbinding = %q", got)
+	}
+	if cmd := BuildCrewStartupCommand("gastown", "box", rigPath, "gt prime"); strings.Contains(cmd, "docker") {
+		t.Errorf("unbound command = %s", cmd)
+	}
+}
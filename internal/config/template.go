@@ -0,0 +1,168 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AgentTemplate is a reusable definition of an agent: its role, runtime,
+// startup prompt, and environment. Templates live in the town's
+// settings/templates/<name>.json and are applied with 'gt spawn --template'.
+type AgentTemplate struct {
+	Type    string `json:"type"`    // "agent-template"
+	Version int    `json:"version"` // schema version
+
+	// Name is the template's file name without .json; set on load.
+	Name string `json:"-"`
+
+	// Description says what agents spawned from the template are for.
+	Description string `json:"description,omitempty"`
+
+	// Role is the Gas Town role the template is for. Only "crew" (the
+	// default) can be spawned from a template; polecats are spawned by
+	// gt sling.
+	Role string `json:"role,omitempty"`
+
+	// Agent selects the runtime preset ("claude", "gemini", "codex", or a
+	// custom agent), overriding the rig's.
+	Agent string `json:"agent,omitempty"`
+
+	// Args are extra runtime flags, appended to the runtime's own.
+	Args []string `json:"args,omitempty"`
+
+	// Prompt is sent to the agent at startup, ahead of any prompt gt adds
+	// (e.g. "You review pull requests; never push to main.").
+	Prompt string `json:"prompt,omitempty"`
+
+	// Env sets environment variables in the agent's session. The
+	// variables gt sets for the role (GT_ROLE, GT_RIG, ...) cannot be
+	// overridden.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Account is the Claude Code account handle to use, unless one is
+	// given on the command line.
+	Account string `json:"account,omitempty"`
+}
+
+// CurrentAgentTemplateVersion is the current schema version of agent templates.
+const CurrentAgentTemplateVersion = 1
+
+var (
+	templateNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	envNamePattern      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// reservedTemplateEnv are the variables identifying an agent, which a
+// template cannot set.
+var reservedTemplateEnv = map[string]bool{
+	"GT_ROLE": true, "GT_RIG": true, "GT_CREW": true, "GT_POLECAT": true,
+	"BD_ACTOR": true, "GT_TEMPLATE": true, "GT_AGENT_ROLE": true,
+}
+
+// AgentTemplatesDir returns the directory holding a town's agent templates.
+func AgentTemplatesDir(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "templates")
+}
+
+// LoadAgentTemplate loads and validates the named agent template.
+func LoadAgentTemplate(townRoot, name string) (*AgentTemplate, error) {
+	if !templateNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid template name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	path := filepath.Join(AgentTemplatesDir(townRoot), name+".json")
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town's settings
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: template %q (%s)", ErrNotFound, name, path)
+		}
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+	var t AgentTemplate
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("parsing template %s: %w", path, err)
+	}
+	t.Name = name
+	if err := validateAgentTemplate(&t); err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	return &t, nil
+}
+
+// ListAgentTemplates returns the names of a town's agent templates, sorted.
+func ListAgentTemplates(townRoot string) ([]string, error) {
+	entries, err := os.ReadDir(AgentTemplatesDir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if ok && !e.IsDir() && templateNamePattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// validateAgentTemplate validates an agent template.
+func validateAgentTemplate(t *AgentTemplate) error {
+	if t.Type != "agent-template" && t.Type != "" {
+		return fmt.Errorf("%w: expected type 'agent-template', got '%s'", ErrInvalidType, t.Type)
+	}
+	if t.Version > CurrentAgentTemplateVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, t.Version, CurrentAgentTemplateVersion)
+	}
+	if t.Role != "" && t.Role != "crew" {
+		return fmt.Errorf("unsupported role %q: templates can only define crew agents", t.Role)
+	}
+	for k := range t.Env {
+		if !envNamePattern.MatchString(k) {
+			return fmt.Errorf("invalid environment variable name %q", k)
+		}
+		if reservedTemplateEnv[k] {
+			return fmt.Errorf("environment variable %s is set by gt and cannot be overridden", k)
+		}
+	}
+	return nil
+}
+
+// runtimeConfig returns the runtime an agent spawned from t runs: agent
+// if set, else the template's agent (or the rig's, without one), with the
+// template's extra flags.
+func (t *AgentTemplate) runtimeConfig(townRoot, rigPath, agent string) *RuntimeConfig {
+	if agent == "" {
+		agent = t.Agent
+	}
+	var rc *RuntimeConfig
+	if agent != "" {
+		rc = namedRuntimeConfig(townRoot, agent)
+	} else {
+		rc = ResolveAgentConfig(townRoot, rigPath)
+	}
+	out := *rc
+	if out.Args == nil {
+		out.Args = DefaultRuntimeConfig().Args
+	}
+	out.Args = append(append([]string{}, out.Args...), t.Args...)
+	return &out
+}
+
+// startupPrompt combines the template's prompt with the one gt sends.
+func (t *AgentTemplate) startupPrompt(prompt string) string {
+	switch {
+	case t.Prompt == "":
+		return prompt
+	case prompt == "":
+		return t.Prompt
+	}
+	return t.Prompt + "\n\n" + prompt
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, townRoot, name, content string) {
+	t.Helper()
+	dir := AgentTemplatesDir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadAgentTemplate(t *testing.T) {
+	town := t.TempDir()
+	writeTemplate(t, town, "reviewer", `{"type": "agent-template", "version": 1,
+		"description": "Reviews PRs", "agent": "claude", "args": ["--model", "opus"],
+		"prompt": "You review pull requests.", "env": {"REVIEW_MODE": "strict"}}`)
+	writeTemplate(t, town, "bad-env", `{"env": {"GT_ROLE": "mayor"}}`)
+	writeTemplate(t, town, "witness", `{"role": "witness"}`)
+
+	tmpl, err := LoadAgentTemplate(town, "reviewer")
+	if err != nil {
+		t.Fatalf("LoadAgentTemplate: %v", err)
+	}
+	if tmpl.Name != "reviewer" || tmpl.Env["REVIEW_MODE"] != "strict" || len(tmpl.Args) != 2 {
+		t.Errorf("loaded %+v", tmpl)
+	}
+
+	if _, err := LoadAgentTemplate(town, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing template: err = %v, want ErrNotFound", err)
+	}
+	for _, name := range []string{"bad-env", "witness", "../reviewer"} {
+		if _, err := LoadAgentTemplate(town, name); err == nil {
+			t.Errorf("LoadAgentTemplate(%q) succeeded, want an error", name)
+		}
+	}
+
+	names, err := ListAgentTemplates(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(names, ","); got != "bad-env,reviewer,witness" {
+		t.Errorf("ListAgentTemplates = %s", got)
+	}
+}
+
+func TestBuildCrewStartupCommandWithTemplate(t *testing.T) {
+	town := t.TempDir()
+	rigPath := filepath.Join(town, "gastown")
+	writeTemplate(t, town, "reviewer", `{"args": ["--model", "opus"],
+		"prompt": "You review pull requests.", "env": {"REVIEW_SCOPE": "api docs"}}`)
+
+	plain := BuildCrewStartupCommand("gastown", "rex", rigPath, "gt prime")
+	if strings.Contains(plain, "GT_TEMPLATE") {
+		t.Errorf("unbound crew got a template: %s", plain)
+	}
+
+	if err := SetCrewBinding(rigPath, "rex", CrewBinding{Template: "reviewer"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ReadCrewBinding(rigPath, "rex").Template; got != "reviewer" {
+		t.Fatalf("bound template = %q", got)
+	}
+	cmd := BuildCrewStartupCommand("gastown", "rex", rigPath, "gt prime")
+	for _, want := range []string{
+		"GT_ROLE=crew", "GT_TEMPLATE=reviewer", `REVIEW_SCOPE="api docs"`,
+		"--dangerously-skip-permissions --model opus", "You review pull requests.\n\ngt prime",
+	} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("command missing %q:\n%s", want, cmd)
+		}
+	}
+
+	// Planning a binding gives the command binding it would
+	plan := PlanCrewStartup("gastown", "rex", rigPath, "gt prime", CrewBinding{Template: "reviewer"})
+	if plan.Command != cmd || plan.Env["REVIEW_SCOPE"] != "api docs" || plan.Prompt != "You review pull requests.\n\ngt prime" {
+		t.Errorf("PlanCrewStartup = %+v, want the command %s", plan, cmd)
+	}
+
+	if err := SetCrewBinding(rigPath, "rex", CrewBinding{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ReadCrewBinding(rigPath, "rex").Template; got != "" {
+		t.Errorf("bound template after unbinding = %q", got)
+	}
+	if _, err := os.Stat(CrewBindingPath(rigPath, "rex")); !os.IsNotExist(err) {
+		t.Errorf("empty binding left its file: %v", err)
+	}
+}
+
+func TestBuildCrewStartupCommandWithRuntime(t *testing.T) {
+	ResetRegistryForTesting()
+	t.Cleanup(ResetRegistryForTesting)
+	town := t.TempDir()
+	rigPath := filepath.Join(town, "gastown")
+	writeTemplate(t, town, "reviewer", `{"agent": "gemini", "args": ["--model", "opus"], "prompt": "You review pull requests."}`)
+
+	if err := SetCrewBinding(rigPath, "ada", CrewBinding{Runtime: "aider"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ReadCrewBinding(rigPath, "ada").Runtime; got != "aider" {
+		t.Fatalf("bound runtime = %q", got)
+	}
+	cmd := BuildCrewStartupCommand("gastown", "ada", rigPath, "gt prime")
+	if !strings.HasSuffix(cmd, "aider --yes-always") {
+		t.Errorf("aider command should end with its flags and no prompt:\n%s", cmd)
+	}
+	if got := CrewTypedPrompt("gastown", "ada", rigPath, "gt prime"); got != "gt prime" {
+		t.Errorf("CrewTypedPrompt = %q, want the prompt typed in", got)
+	}
+	if got := ResolveAgentRuntime(town, "gastown/crew/ada"); got != "aider" {
+		t.Errorf("ResolveAgentRuntime = %q", got)
+	}
+
+	// The runtime binding overrides the template's agent, keeping its flags
+	if err := SetCrewBinding(rigPath, "ada", CrewBinding{Template: "reviewer", Runtime: "codex"}); err != nil {
+		t.Fatal(err)
+	}
+	cmd = BuildCrewStartupCommand("gastown", "ada", rigPath, "gt prime")
+	if !strings.Contains(cmd, "codex --yolo --model opus") || !strings.Contains(cmd, "You review pull requests.") {
+		t.Errorf("codex command = %s", cmd)
+	}
+	if got := CrewTypedPrompt("gastown", "ada", rigPath, "gt prime"); got != "" {
+		t.Errorf("CrewTypedPrompt for codex = %q, want none", got)
+	}
+
+	// Without a runtime binding, the template's agent applies
+	if err := SetCrewBinding(rigPath, "ada", CrewBinding{Template: "reviewer"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ResolveAgentRuntime(town, "gastown/crew/ada"); got != "gemini" {
+		t.Errorf("ResolveAgentRuntime after unbinding = %q, want the template's gemini", got)
+	}
+	if got := ResolveAgentRuntime(town, "gastown/Toast"); got != "claude" {
+		t.Errorf("ResolveAgentRuntime(polecat) = %q", got)
+	}
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CurrentTownRegistryVersion is the current schema version for TownRegistry.
+const CurrentTownRegistryVersion = 1
+
+// TownRegistry lists the towns on this machine, so that commands such as
+// 'gt log --all-towns' can reach all of them. Unlike the rest of the
+// configuration it lives outside any town, in the user's config directory.
+type TownRegistry struct {
+	Version int              `json:"version"`
+	Towns   []RegisteredTown `json:"towns"`
+}
+
+// RegisteredTown is one entry in the town registry.
+type RegisteredTown struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"` // absolute town root
+	AddedAt time.Time `json:"added_at"`
+}
+
+// TownRegistryPath returns the path of the town registry:
+// $XDG_CONFIG_HOME/gt/towns.json, or ~/.config/gt/towns.json.
+func TownRegistryPath() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gt", "towns.json"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "gt", "towns.json"), nil
+}
+
+// LoadTownRegistry loads the town registry. A missing registry is empty.
+func LoadTownRegistry() (*TownRegistry, error) {
+	path, err := TownRegistryPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the user's config location
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &TownRegistry{Version: CurrentTownRegistryVersion}, nil
+		}
+		return nil, fmt.Errorf("reading town registry: %w", err)
+	}
+
+	var registry TownRegistry
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("parsing town registry: %w", err)
+	}
+	if err := validateTownRegistry(&registry); err != nil {
+		return nil, err
+	}
+	return &registry, nil
+}
+
+// SaveTownRegistry writes the town registry.
+func SaveTownRegistry(registry *TownRegistry) error {
+	if err := validateTownRegistry(registry); err != nil {
+		return err
+	}
+	path, err := TownRegistryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	data, err := json.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding town registry: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing town registry: %w", err)
+	}
+	return nil
+}
+
+// RegisterTown adds a town to the registry, or renames it if its path is
+// already registered.
+func RegisterTown(name, path string) error {
+	registry, err := LoadTownRegistry()
+	if err != nil {
+		return err
+	}
+	if err := registry.Register(name, path); err != nil {
+		return err
+	}
+	return SaveTownRegistry(registry)
+}
+
+// Register adds a town, or renames the entry already registered at path.
+// Names must be unique.
+func (r *TownRegistry) Register(name, path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", path, err)
+	}
+	for _, t := range r.Towns {
+		if t.Name == name && t.Path != abs {
+			return fmt.Errorf("town name %q is already registered for %s", name, t.Path)
+		}
+	}
+	if existing := r.Find(abs); existing != nil {
+		existing.Name = name
+		return nil
+	}
+	r.Towns = append(r.Towns, RegisteredTown{Name: name, Path: abs, AddedAt: time.Now()})
+	return nil
+}
+
+// Unregister removes the town with the given name or path and reports
+// whether one was registered.
+func (r *TownRegistry) Unregister(nameOrPath string) bool {
+	abs, _ := filepath.Abs(nameOrPath)
+	for i, t := range r.Towns {
+		if t.Name == nameOrPath || t.Path == abs {
+			r.Towns = append(r.Towns[:i], r.Towns[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the registered town with the given path, or nil.
+func (r *TownRegistry) Find(path string) *RegisteredTown {
+	abs, _ := filepath.Abs(path)
+	for i := range r.Towns {
+		if r.Towns[i].Path == abs {
+			return &r.Towns[i]
+		}
+	}
+	return nil
+}
+
+// validateTownRegistry validates a TownRegistry.
+func validateTownRegistry(r *TownRegistry) error {
+	if r.Version > CurrentTownRegistryVersion {
+		return fmt.Errorf("%w: town registry version %d", ErrInvalidVersion, r.Version)
+	}
+	names := make(map[string]bool)
+	for _, t := range r.Towns {
+		if t.Name == "" || t.Path == "" {
+			return fmt.Errorf("%w: town registry entries need a name and path", ErrMissingField)
+		}
+		if names[t.Name] {
+			return fmt.Errorf("town registry lists %q twice", t.Name)
+		}
+		names[t.Name] = true
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTownRegistry(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	registry, err := LoadTownRegistry()
+	if err != nil {
+		t.Fatalf("LoadTownRegistry() on missing file: %v", err)
+	}
+	if len(registry.Towns) != 0 {
+		t.Fatalf("missing registry has %d towns, want 0", len(registry.Towns))
+	}
+
+	townA := t.TempDir()
+	townB := t.TempDir()
+	if err := RegisterTown("alpha", townA); err != nil {
+		t.Fatalf("RegisterTown(alpha): %v", err)
+	}
+	if err := RegisterTown("beta", townB); err != nil {
+		t.Fatalf("RegisterTown(beta): %v", err)
+	}
+	if err := RegisterTown("alpha", townB); err == nil {
+		t.Error("registering a taken name for another path should fail")
+	}
+	// Re-registering a path renames it.
+	if err := RegisterTown("bravo", townB); err != nil {
+		t.Fatalf("RegisterTown(bravo): %v", err)
+	}
+
+	registry, err = LoadTownRegistry()
+	if err != nil {
+		t.Fatalf("LoadTownRegistry(): %v", err)
+	}
+	if len(registry.Towns) != 2 {
+		t.Fatalf("got %d towns, want 2: %+v", len(registry.Towns), registry.Towns)
+	}
+	if got := registry.Find(townB); got == nil || got.Name != "bravo" {
+		t.Errorf("Find(townB) = %+v, want bravo", got)
+	}
+	if !filepath.IsAbs(registry.Towns[0].Path) {
+		t.Errorf("registered path %q is not absolute", registry.Towns[0].Path)
+	}
+
+	if !registry.Unregister("alpha") {
+		t.Error("Unregister(alpha) = false, want true")
+	}
+	if !registry.Unregister(townB) {
+		t.Error("Unregister(townB path) = false, want true")
+	}
+	if registry.Unregister("alpha") {
+		t.Error("Unregister of a missing town = true, want false")
+	}
+}
@@ -48,6 +48,211 @@ type TownSettings struct {
 	// Values override or extend the built-in presets.
 	// Example: {"gemini": {"command": "/custom/path/to/gemini"}}
 	Agents map[string]*RuntimeConfig `json:"agents,omitempty"`
+
+	// Webhooks posts selected town log events (crash, done, handoff by
+	// default) to external URLs such as a Slack incoming webhook.
+	Webhooks []WebhookConfig `json:"webhooks,omitempty"`
+
+	// LogChain makes the town log tamper-evident: each event records the
+	// hash of the one before it, and 'gt log verify' checks the chain.
+	LogChain bool `json:"log_chain,omitempty"`
+
+	// Operations limits how many mutating gt commands (gt up, gt sling,
+	// gt rig add, ...) run at once across the town's operators, cron jobs,
+	// and hooks.
+	Operations *OperationsConfig `json:"operations,omitempty"`
+
+	// Fairness shares polecat capacity between rigs with weights and
+	// quotas, so one busy rig cannot take all of it.
+	Fairness *FairnessConfig `json:"fairness,omitempty"`
+
+	// Limits caps the CPU, memory, and processes of each agent session,
+	// so one runaway agent cannot starve the machine.
+	Limits *ResourceLimitsConfig `json:"limits,omitempty"`
+
+	// MaxConcurrentAgents limits the worker agents (crew and polecats)
+	// running at once across the town; paused agents do not count. When
+	// the limit is reached, gt spawn preempts agents of lower priority
+	// than the one it spawns and queues the spawns that still do not fit.
+	// 0 means no limit.
+	MaxConcurrentAgents int `json:"max_concurrent_agents,omitempty"`
+
+	// Sandbox configures the containers that agents spawned with
+	// 'gt spawn --sandbox docker' run in.
+	Sandbox *SandboxConfig `json:"sandbox,omitempty"`
+
+	// Hosts configures the machines that agents spawned with
+	// 'gt spawn --host <name>' run on over SSH, by name. A host without an
+	// entry is reached as ssh <name>, with the defaults of HostConfig.
+	Hosts map[string]*HostConfig `json:"hosts,omitempty"`
+
+	// Retry decides what happens to the work of agents that crash with
+	// work on their hook (see 'gt retry').
+	Retry *RetryConfig `json:"retry,omitempty"`
+
+	// Hooks are shell commands run at points of every agent's life, ahead
+	// of those of its rig's settings.
+	Hooks *LifecycleHooks `json:"hooks,omitempty"`
+}
+
+// LifecycleHooks are shell commands run with sh -c at points of an
+// agent's life, with the event's metadata in GT_HOOK_* environment
+// variables (see the lifecycle package).
+type LifecycleHooks struct {
+	// PreSpawn runs before an agent's session starts, in its worktree. A
+	// failing command stops the spawn.
+	PreSpawn []string `json:"pre_spawn,omitempty"`
+
+	// PostDone runs in the background once an agent logs done.
+	PostDone []string `json:"post_done,omitempty"`
+
+	// OnCrash runs in the background once an agent crashes.
+	OnCrash []string `json:"on_crash,omitempty"`
+
+	// Timeout bounds each pre_spawn command (e.g. "2m"). Default: 5m.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// RetryConfig is the town's retry policy for work whose agent crashed:
+// a default, and overrides for some rigs.
+type RetryConfig struct {
+	// Default applies to rigs without an entry in Rigs.
+	Default *RetryPolicy `json:"default,omitempty"`
+
+	// Rigs overrides the policy per rig.
+	Rigs map[string]*RetryPolicy `json:"rigs,omitempty"`
+}
+
+// RetryPolicy says what to do with a task whose agent crashed.
+type RetryPolicy struct {
+	// Action is "respawn" (restart the same agent on the task),
+	// "reassign" (hand the task to a fresh polecat), or "escalate" (leave
+	// it for a human).
+	Action string `json:"action"`
+
+	// MaxAttempts is how many times a task is respawned or reassigned
+	// before it is escalated instead. Default: 3.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+}
+
+// SandboxConfig configures agent containers. Only the agent's worktree
+// and the listed mounts are visible inside one.
+type SandboxConfig struct {
+	// Image is the container image agents run in. It must have the
+	// agents' runtime installed (e.g. Claude Code); gt does not install it.
+	Image string `json:"image,omitempty"`
+
+	// Network is the container's network (docker run --network), e.g.
+	// "none" to cut agents off. Default: docker's default network.
+	Network string `json:"network,omitempty"`
+
+	// Mounts are extra host paths mounted at the same path, such as the
+	// runtime's credentials ("~/.claude"). "~/" is the home directory;
+	// a ":ro" suffix mounts the path read-only.
+	Mounts []string `json:"mounts,omitempty"`
+
+	// Args are extra docker run flags.
+	Args []string `json:"args,omitempty"`
+}
+
+// HostConfig is a machine agents run on over SSH (see 'gt spawn --host').
+type HostConfig struct {
+	// SSH is the ssh destination, e.g. "dev@build-box-2". Default: the
+	// host's name, so hosts in ~/.ssh/config work as they are.
+	SSH string `json:"ssh,omitempty"`
+
+	// Dir is where agents' worktrees are kept on the host, laid out as a
+	// town of its own; "~/" is the remote home directory. Default:
+	// "gt-remote", in the remote home directory.
+	Dir string `json:"dir,omitempty"`
+
+	// Args are extra ssh flags, e.g. ["-p", "2222"].
+	Args []string `json:"args,omitempty"`
+}
+
+// ResourceLimitsConfig sets per-agent resource limits for the town, with
+// overrides by rig.
+type ResourceLimitsConfig struct {
+	// Default applies to the agents of every rig.
+	Default *ResourceLimits `json:"default,omitempty"`
+
+	// Rigs overrides Default field by field, by rig name.
+	Rigs map[string]*ResourceLimits `json:"rigs,omitempty"`
+}
+
+// ResourceLimits caps one agent session. Zero fields are unlimited.
+type ResourceLimits struct {
+	// CPU is how many cores the agent may use, e.g. 1.5.
+	CPU float64 `json:"cpu,omitempty"`
+
+	// Memory is the most memory the agent may use, as a size ("4G",
+	// "512M") or a share of the machine's memory ("25%"). An agent that
+	// goes over it is killed.
+	Memory string `json:"memory,omitempty"`
+
+	// Tasks limits the processes and threads the agent may run.
+	Tasks int `json:"tasks,omitempty"`
+}
+
+// FairnessConfig configures how polecat capacity is shared between rigs.
+type FairnessConfig struct {
+	// MaxPolecats limits running polecats across the town. Each rig is
+	// guaranteed its weighted share of the slots and may borrow slots
+	// other rigs leave idle. 0 means no town-wide limit.
+	MaxPolecats int `json:"max_polecats,omitempty"`
+
+	// Window is the period quotas are measured over. Default: "24h".
+	Window string `json:"window,omitempty"`
+
+	// Rigs sets weights and quotas by rig name. Unlisted rigs have
+	// weight 1 and no quota.
+	Rigs map[string]*RigFairness `json:"rigs,omitempty"`
+}
+
+// RigFairness is one rig's fairness settings.
+type RigFairness struct {
+	// Weight is the rig's relative share of the town's slots. Default: 1.
+	Weight float64 `json:"weight,omitempty"`
+
+	// Quota is the polecat time the rig may use per window, e.g. "40h".
+	Quota string `json:"quota,omitempty"`
+}
+
+// Operation lock defaults.
+const (
+	DefaultMaxParallelOps = 1
+	DefaultOpWaitTimeout  = "5m"
+)
+
+// OperationsConfig configures the town's operation lock.
+type OperationsConfig struct {
+	// MaxParallel is how many mutating commands may run at once.
+	// Default: 1, so they run one at a time.
+	MaxParallel int `json:"max_parallel,omitempty"`
+
+	// WaitTimeout is how long a command waits for a free slot before
+	// failing, unless --wait or --no-wait says otherwise. Default: "5m".
+	WaitTimeout string `json:"wait_timeout,omitempty"`
+}
+
+// Webhook payload formats.
+const (
+	WebhookFormatJSON  = "json"  // the event as a JSON object
+	WebhookFormatSlack = "slack" // {"text": ...}, as Slack incoming webhooks expect
+)
+
+// WebhookConfig is one webhook in the town settings.
+type WebhookConfig struct {
+	// URL receives a POST per event. "env:VAR" reads it from the
+	// environment, so webhook secrets can stay out of the config.
+	URL string `json:"url"`
+
+	// Events lists the event types to send; "*" sends every event.
+	// Default: crash, done, handoff.
+	Events []string `json:"events,omitempty"`
+
+	// Format is the payload format: "json" (default) or "slack".
+	Format string `json:"format,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -62,8 +267,23 @@ func NewTownSettings() *TownSettings {
 
 // DaemonConfig represents daemon process settings.
 type DaemonConfig struct {
-	HeartbeatInterval string `json:"heartbeat_interval,omitempty"` // e.g., "30s"
-	PollInterval      string `json:"poll_interval,omitempty"`      // e.g., "10s"
+	HeartbeatInterval string       `json:"heartbeat_interval,omitempty"` // e.g., "30s"
+	PollInterval      string       `json:"poll_interval,omitempty"`      // e.g., "10s"
+	Chaos             *ChaosConfig `json:"chaos,omitempty"`              // fault injection (staging towns only)
+	PruneAgentsAfter  string       `json:"prune_agents_after,omitempty"` // retire crew idle this long, e.g. "12w" (see gt prune agents)
+	HealthStaleAfter  string       `json:"health_stale_after,omitempty"` // report agents silent this long as stale, e.g. "1h" (default 30m, "0" = never)
+	SleepIdleAfter    string       `json:"sleep_idle_after,omitempty"`   // put worker agents with no output or file changes this long to sleep, e.g. "30m"
+}
+
+// ChaosConfig controls the daemon's opt-in fault injector.
+// It is meant for staging towns: it deliberately breaks things so that
+// restart policies, checkpoints, and rescue branches get exercised.
+type ChaosConfig struct {
+	Enabled            bool    `json:"enabled"`                       // must be true for any injection
+	KillProbability    float64 `json:"kill_probability,omitempty"`    // chance per heartbeat to kill a random agent session
+	CorruptProbability float64 `json:"corrupt_probability,omitempty"` // chance per heartbeat to corrupt a polecat worktree file
+	NudgeDelay         string  `json:"nudge_delay,omitempty"`         // max random delay added to nudges, e.g. "30s"
+	Seed               int64   `json:"seed,omitempty"`                // RNG seed for reproducible runs (0 = time-based)
 }
 
 // DeaconConfig represents deacon process settings.
@@ -138,6 +358,206 @@ type RigSettings struct {
 	// If empty, uses the town's default_agent setting.
 	// Takes precedence over Runtime if both are set.
 	Agent string `json:"agent,omitempty"`
+
+	// MaxPolecats caps how many polecats the rig may run at once.
+	// Spawns beyond the cap fail preflight. Zero means no cap.
+	MaxPolecats int `json:"max_polecats,omitempty"`
+
+	// Ignore hides matching events from default log, status, and
+	// notification views. Ignored events are still recorded; --all shows them.
+	Ignore []IgnoreRule `json:"ignore,omitempty"`
+
+	// Worktree prepares each new polecat worktree before the agent starts.
+	Worktree *WorktreeConfig `json:"worktree,omitempty"`
+
+	// Verify checks finished work in the agent's worktree after gt done
+	// (see 'gt verify done').
+	Verify *VerifyConfig `json:"verify,omitempty"`
+
+	// Context configures retrieval of related snippets for new tasks
+	// (see 'gt context index').
+	Context *ContextConfig `json:"context,omitempty"`
+
+	// Restart respawns the rig's agents after they crash (see 'gt supervise').
+	Restart *RestartConfig `json:"restart,omitempty"`
+
+	// Hooks are shell commands run at points of the rig's agents' lives,
+	// after those of the town's settings.
+	Hooks *LifecycleHooks `json:"hooks,omitempty"`
+
+	// Env is exported into every agent started for the rig: its crew,
+	// polecats, witness, and refinery (e.g. API endpoints, GOFLAGS).
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// DefaultContextBudgetTokens bounds retrieved context when the rig sets no budget.
+const DefaultContextBudgetTokens = 2000
+
+// ContextConfig configures the rig's context index: which embeddings
+// model it uses and how much retrieved context a new task receives.
+type ContextConfig struct {
+	// Embeddings selects the model that vectorizes code, notes, and
+	// handoffs. Without it the built-in local embedder is used.
+	Embeddings *EmbeddingsConfig `json:"embeddings,omitempty"`
+
+	// BudgetTokens caps the retrieved snippets shown for a task.
+	// Zero means DefaultContextBudgetTokens.
+	BudgetTokens int `json:"budget_tokens,omitempty"`
+}
+
+// Embeddings providers.
+const (
+	// EmbeddingsLocal is the built-in hashing embedder: no model, no
+	// network, keyword-level similarity.
+	EmbeddingsLocal = "local"
+
+	// EmbeddingsOpenAI is any OpenAI-compatible /embeddings endpoint,
+	// including local servers such as ollama or llama.cpp.
+	EmbeddingsOpenAI = "openai"
+
+	// EmbeddingsCommand runs a program that reads a JSON array of texts on
+	// stdin and writes a JSON array of vectors on stdout.
+	EmbeddingsCommand = "command"
+)
+
+// EmbeddingsConfig describes an embeddings model.
+type EmbeddingsConfig struct {
+	Provider  string   `json:"provider"`              // local, openai, or command
+	Model     string   `json:"model,omitempty"`       // model name (openai)
+	URL       string   `json:"url,omitempty"`         // API base URL (openai; default https://api.openai.com/v1)
+	APIKeyEnv string   `json:"api_key_env,omitempty"` // env var holding the API key (openai)
+	Command   []string `json:"command,omitempty"`     // program and arguments (command)
+}
+
+// WorktreeConfig describes post-create setup for polecat worktrees.
+// Steps run in order: Copy, then Link, then Setup hooks.
+type WorktreeConfig struct {
+	// Caches lists shared dependency caches (see DepCaches) used by setup
+	// hooks and agent sessions, e.g. ["go", "pnpm"]. They live once per
+	// town under .cache/deps/, so spawns reuse downloads and build outputs.
+	Caches []string `json:"caches,omitempty"`
+
+	// Sparse lists directories, relative to the repository root, that new
+	// worktrees check out instead of the whole tree (sparse checkout). In
+	// a rig scoped with --paths they are checked out alongside the rig's
+	// paths, e.g. shared libraries the rig builds against.
+	Sparse []string `json:"sparse,omitempty"`
+
+	// Copy lists files or directories copied from <rig>/settings/worktree/
+	// into every new worktree, e.g. ".env" or "config/local.yaml".
+	Copy []string `json:"copy,omitempty"`
+
+	// Link lists paths symlinked into every worktree from one shared copy
+	// under <rig>/.shared/, e.g. a build output dir all agents can reuse.
+	Link []string `json:"link,omitempty"`
+
+	// Setup lists commands run in the new worktree (e.g. "npm ci").
+	Setup []WorktreeHook `json:"setup,omitempty"`
+}
+
+// WorktreeHook is one setup command. When both CacheKey and Outputs are
+// set, the outputs are cached under <rig>/.cache/worktree/ and restored
+// for later worktrees with the same key instead of running the command.
+type WorktreeHook struct {
+	// Name identifies the hook in output and cache paths. Defaults to its
+	// position ("setup-1").
+	Name string `json:"name,omitempty"`
+
+	// Run is the shell command, run with sh -c in the worktree.
+	Run string `json:"run"`
+
+	// CacheKey lists files, relative to the worktree, whose contents key
+	// the cache (e.g. "package-lock.json").
+	CacheKey []string `json:"cache_key,omitempty"`
+
+	// Outputs lists paths the command produces, relative to the worktree
+	// (e.g. "node_modules").
+	Outputs []string `json:"outputs,omitempty"`
+
+	// Timeout bounds the command (e.g. "10m"). Default: 10m.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// VerifyConfig is a rig's post-done validation suite: checks such as the
+// build, tests, lint, and licence or secret scans, run in the agent's
+// worktree once it reports its work done. Unlike the merge queue's test
+// gate they run in the background, so gt done never waits on them.
+type VerifyConfig struct {
+	// Checks run in order; each runs even if an earlier one failed.
+	Checks []VerifyCheck `json:"checks"`
+
+	// Manual stops gt done from starting the suite, for rigs that run
+	// 'gt verify done' themselves (e.g. from CI).
+	Manual bool `json:"manual,omitempty"`
+
+	// Reopen reopens the issue when a required check fails, so the work
+	// is picked up again.
+	Reopen bool `json:"reopen,omitempty"`
+}
+
+// RestartConfig is a rig's supervision policy: which agents are respawned
+// after a crash, and how quickly. The rig-wide policy applies to all of the
+// rig's agents except those with an entry in Agents.
+type RestartConfig struct {
+	RestartPolicy
+
+	// Agents overrides the policy for some agents, keyed by name within
+	// the rig: "witness", "refinery", "crew/max", or a glob such as
+	// "polecats/*". An exact name wins over globs; globs are tried in
+	// sorted order.
+	Agents map[string]RestartPolicy `json:"agents,omitempty"`
+}
+
+// RestartPolicy says whether and how a crashed agent is respawned. The nth
+// restart in a row waits Backoff * 2^(n-1), capped at MaxBackoff.
+type RestartPolicy struct {
+	// Enabled turns restarts on.
+	Enabled bool `json:"enabled"`
+
+	// Backoff is the wait before the first restart (e.g. "10s"). Default: 10s.
+	Backoff string `json:"backoff,omitempty"`
+
+	// MaxBackoff caps the wait (e.g. "5m"). Default: 5m.
+	MaxBackoff string `json:"max_backoff,omitempty"`
+
+	// MaxRestarts is how many restarts in a row are tried before giving
+	// up. Default: 5.
+	MaxRestarts int `json:"max_restarts,omitempty"`
+
+	// ResetAfter is how long a restarted agent must stay up for its next
+	// crash to count as a first one again (e.g. "1h"). Default: 1h.
+	ResetAfter string `json:"reset_after,omitempty"`
+}
+
+// VerifyCheck is one command of the verify suite.
+type VerifyCheck struct {
+	// Name identifies the check in output and the town log. Defaults to
+	// its position ("check-1").
+	Name string `json:"name,omitempty"`
+
+	// Run is the shell command, run with sh -c in the worktree. A non-zero
+	// exit fails the check.
+	Run string `json:"run"`
+
+	// Timeout bounds the command (e.g. "10m"). Default: 10m.
+	Timeout string `json:"timeout,omitempty"`
+
+	// Optional checks are reported but never fail the suite, e.g. a new
+	// linter that is not yet enforced.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// IgnoreRule suppresses noisy events for some of a rig's agents.
+// A rule matches when the event type is in Events (empty: any type) and the
+// agent matches one of Agents (empty: any agent in the rig).
+type IgnoreRule struct {
+	// Events lists event types, e.g. "nudge", "polecat_checked".
+	Events []string `json:"events,omitempty"`
+
+	// Agents lists agent patterns relative to the rig, matched with
+	// path.Match: "witness", "polecats/*", "crew/janitor". A bare name such
+	// as "janitor" also matches the agent's last path element.
+	Agents []string `json:"agents,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -170,6 +590,10 @@ type RuntimeConfig struct {
 	// For claude, this is passed as the prompt argument.
 	// Empty by default (hooks handle context).
 	InitialPrompt string `json:"initial_prompt,omitempty"`
+
+	// NoPromptArg means the runtime takes no startup prompt on its command
+	// line; the prompt is typed into its session once it is up instead.
+	NoPromptArg bool `json:"no_prompt_arg,omitempty"`
 }
 
 // DefaultRuntimeConfig returns a RuntimeConfig with sensible defaults.
@@ -217,7 +641,7 @@ func (rc *RuntimeConfig) BuildCommandWithPrompt(prompt string) string {
 		p = rc.InitialPrompt
 	}
 
-	if p == "" {
+	if p == "" || (rc != nil && rc.NoPromptArg) {
 		return base
 	}
 
@@ -397,6 +821,15 @@ type MessagingConfig struct {
 	// Like mailing lists but for tmux send-keys instead of durable mail.
 	// Example: {"workers": ["gastown/polecats/*", "gastown/crew/*"], "witnesses": ["*/witness"]}
 	NudgeChannels map[string][]string `json:"nudge_channels,omitempty"`
+
+	// DefaultTTL expires mail left unread this long (e.g. "24h"), unless the
+	// sender sets its own TTL. Empty means mail never expires.
+	DefaultTTL string `json:"default_ttl,omitempty"`
+
+	// DND schedules Do Not Disturb windows for agents. While a window is
+	// active, non-urgent nudges, mail banners, and auto-wakes for matching
+	// agents are deferred. Managed with 'gt agent dnd'.
+	DND []DNDWindow `json:"dnd,omitempty"`
 }
 
 // QueueConfig represents a work queue configuration.
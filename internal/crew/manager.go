@@ -71,20 +71,29 @@ func (m *Manager) Add(name string, createBranch bool) (*CrewWorker, error) {
 	}
 
 	// Clone the rig repo
+	cloneOpts := m.rig.CloneOptions()
 	if m.rig.LocalRepo != "" {
-		if err := m.git.CloneWithReference(m.rig.GitURL, crewPath, m.rig.LocalRepo); err != nil {
+		withRef := cloneOpts
+		withRef.Reference = m.rig.LocalRepo
+		if err := m.git.CloneWithOptions(m.rig.GitURL, crewPath, withRef); err != nil {
 			fmt.Printf("Warning: could not clone with local repo reference: %v\n", err)
-			if err := m.git.Clone(m.rig.GitURL, crewPath); err != nil {
+			if err := m.git.CloneWithOptions(m.rig.GitURL, crewPath, cloneOpts); err != nil {
 				return nil, fmt.Errorf("cloning rig: %w", err)
 			}
 		}
 	} else {
-		if err := m.git.Clone(m.rig.GitURL, crewPath); err != nil {
+		if err := m.git.CloneWithOptions(m.rig.GitURL, crewPath, cloneOpts); err != nil {
 			return nil, fmt.Errorf("cloning rig: %w", err)
 		}
 	}
 
 	crewGit := git.NewGit(crewPath)
+	if paths := m.rig.ScopePaths(); len(paths) > 0 {
+		if err := crewGit.SparseCheckoutSet(paths); err != nil {
+			_ = os.RemoveAll(crewPath) // best-effort cleanup
+			return nil, fmt.Errorf("scoping checkout to rig paths: %w", err)
+		}
+	}
 	branchName := m.rig.DefaultBranch()
 
 	// Optionally create a working branch
@@ -0,0 +1,101 @@
+package daemon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/budget"
+	"github.com/ctiospl/gastown/internal/notify"
+)
+
+// budgetInterval is how often the daemon records agents' spend and
+// enforces the town's budgets.
+const budgetInterval = time.Minute
+
+// runBudgets checks the town's cost budgets (gt budget), when it has any
+// or has agents paused for one.
+func (d *Daemon) runBudgets() {
+	ticker := time.NewTicker(budgetInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			if budget.NeedsCheck(d.config.TownRoot) {
+				if err := budget.RunCheck(d.config.TownRoot); err != nil {
+					d.logger.Printf("Warning: checking budgets: %v", err)
+				}
+			}
+			d.checkBudgetLimits(time.Now())
+		}
+	}
+}
+
+// checkBudgetLimits raises a budget limit incident for each scope with a
+// used-up budget, and resolves those of scopes back under their budgets:
+// in a new period, or with the budget raised or removed.
+func (d *Daemon) checkBudgetLimits(now time.Time) {
+	townRoot := d.config.TownRoot
+	budgets, err := budget.Load(townRoot)
+	if err != nil {
+		d.logger.Printf("Warning: loading budgets: %v", err)
+		return
+	}
+	ledger, err := budget.LoadLedger(townRoot)
+	if err != nil {
+		d.logger.Printf("Warning: loading budget spend: %v", err)
+		return
+	}
+	exceeded := make(map[string][]string)
+	for _, s := range ledger.Statuses(budgets, now) {
+		if s.Exceeded() {
+			exceeded[s.Budget.Scope] = append(exceeded[s.Budget.Scope], s.Reason())
+		}
+	}
+
+	scopes := make([]string, 0, len(exceeded))
+	for scope := range exceeded {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	for _, scope := range scopes {
+		rig := "" // none for a town agent, e.g. mayor
+		if i := strings.Index(scope, "/"); i > 0 {
+			rig = scope[:i]
+		}
+		_, err := notify.Raise(townRoot, notify.BudgetLimitCondition(scope), &notify.Notification{
+			Event:    notify.EventBudgetLimit,
+			Rig:      rig,
+			Severity: notify.SeverityHigh,
+			Source:   scope,
+			Subject:  fmt.Sprintf("%s is over budget", scope),
+			Body:     strings.Join(exceeded[scope], "\n"),
+		})
+		if err != nil {
+			d.logger.Printf("Warning: raising budget limit for %s: %v", scope, err)
+		}
+	}
+
+	router, err := notify.NewRouter(townRoot)
+	if err != nil {
+		return
+	}
+	open, err := router.ListOpen()
+	if err != nil {
+		return
+	}
+	for _, inc := range open {
+		scope := notify.BudgetLimitScope(inc.Condition)
+		if scope == "" || exceeded[scope] != nil {
+			continue
+		}
+		if _, err := notify.Clear(townRoot, inc.Condition, "spend is back under budget"); err != nil {
+			d.logger.Printf("Warning: resolving %s: %v", inc.Condition, err)
+			continue
+		}
+		d.logger.Printf("Resolved budget limit incident for %s", scope)
+	}
+}
@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/budget"
+	"github.com/ctiospl/gastown/internal/notify"
+)
+
+func TestCheckBudgetLimits(t *testing.T) {
+	townRoot := t.TempDir()
+	rules := notify.NewRules()
+	rules.Channels["pager"] = notify.Channel{Type: notify.ChannelCommand, Target: "true"}
+	rules.Rules = []notify.Rule{{Name: "budgets", Match: notify.Match{Events: []string{notify.EventBudgetLimit}}, Channels: []string{"pager"}}}
+	if err := notify.SaveRules(townRoot, rules); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)
+	ledger := &budget.Ledger{Spend: map[string]map[string]budget.Spend{
+		"gastown/crew/max": {"2026-10-14": {Cost: 6}},
+		"beads/crew/ada":   {"2026-10-14": {Cost: 1}},
+	}}
+	if err := budget.SaveLedger(townRoot, ledger); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []budget.Budget{
+		{Scope: "gastown/", Amount: 5, Unit: budget.USD, Period: budget.Day},
+		{Scope: "beads/", Amount: 5, Unit: budget.USD, Period: budget.Day},
+	} {
+		if _, err := budget.Set(townRoot, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d := &Daemon{config: &Config{TownRoot: townRoot}, logger: log.New(io.Discard, "", 0)}
+	gastown, beads := notify.BudgetLimitCondition("gastown/"), notify.BudgetLimitCondition("beads/")
+
+	d.checkBudgetLimits(now)
+	if !notify.IsOpen(townRoot, gastown) || notify.IsOpen(townRoot, beads) {
+		t.Fatalf("after exceeding gastown/: gastown open %v, beads open %v", notify.IsOpen(townRoot, gastown), notify.IsOpen(townRoot, beads))
+	}
+
+	// Raising the budget puts the rig back under it
+	if _, err := budget.Set(townRoot, budget.Budget{Scope: "gastown/", Amount: 10, Unit: budget.USD, Period: budget.Day}); err != nil {
+		t.Fatal(err)
+	}
+	d.checkBudgetLimits(now)
+	if notify.IsOpen(townRoot, gastown) {
+		t.Error("budget limit still open after the budget was raised")
+	}
+
+	// As does the next period
+	if _, err := budget.Set(townRoot, budget.Budget{Scope: "gastown/", Amount: 5, Unit: budget.USD, Period: budget.Day}); err != nil {
+		t.Fatal(err)
+	}
+	d.checkBudgetLimits(now)
+	if !notify.IsOpen(townRoot, gastown) {
+		t.Fatal("budget limit not reopened")
+	}
+	d.checkBudgetLimits(now.AddDate(0, 0, 1))
+	if notify.IsOpen(townRoot, gastown) {
+		t.Error("budget limit still open in the next period")
+	}
+}
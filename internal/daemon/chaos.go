@@ -0,0 +1,180 @@
+package daemon
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+// chaosCorruptMarker is appended to files corrupted by the chaos injector.
+// It looks like a conflict marker so builds and tests fail loudly.
+const chaosCorruptMarker = "\n<<<<<<< gt-chaos: injected corruption\n"
+
+// LoadChaosConfig returns the town's chaos configuration, or nil if chaos
+// mode is not enabled. Errors reading the config are treated as disabled.
+func LoadChaosConfig(townRoot string) *config.ChaosConfig {
+	mc, err := config.LoadMayorConfig(constants.MayorConfigPath(townRoot))
+	if err != nil || mc.Daemon == nil || mc.Daemon.Chaos == nil || !mc.Daemon.Chaos.Enabled {
+		return nil
+	}
+	return mc.Daemon.Chaos
+}
+
+// ChaosNudgeDelay returns a random delay to apply before delivering a nudge,
+// or zero if chaos mode is off or no nudge delay is configured.
+func ChaosNudgeDelay(townRoot string) time.Duration {
+	cfg := LoadChaosConfig(townRoot)
+	if cfg == nil || cfg.NudgeDelay == "" {
+		return 0
+	}
+	max, err := time.ParseDuration(cfg.NudgeDelay)
+	if err != nil || max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max))) //nolint:gosec // G404: chaos timing, not security
+}
+
+// chaosInjector randomly breaks things during daemon heartbeats.
+// Each heartbeat rolls independently for each fault type; recovery is
+// left to the normal heartbeat on the next cycle.
+type chaosInjector struct {
+	cfg      *config.ChaosConfig
+	townRoot string
+	tmux     *tmux.Tmux
+	logger   *log.Logger
+	rng      *rand.Rand
+}
+
+// newChaosInjector creates an injector, or returns nil if chaos is disabled.
+func newChaosInjector(townRoot string, t *tmux.Tmux, logger *log.Logger) *chaosInjector {
+	cfg := LoadChaosConfig(townRoot)
+	if cfg == nil {
+		return nil
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &chaosInjector{
+		cfg:      cfg,
+		townRoot: townRoot,
+		tmux:     t,
+		logger:   logger,
+		rng:      rand.New(rand.NewSource(seed)), //nolint:gosec // G404: chaos selection, not security
+	}
+}
+
+// inject runs one round of fault injection.
+func (c *chaosInjector) inject() {
+	if c.roll(c.cfg.KillProbability) {
+		c.killRandomSession()
+	}
+	if c.roll(c.cfg.CorruptProbability) {
+		c.corruptRandomWorktreeFile()
+	}
+}
+
+// roll returns true with probability p.
+func (c *chaosInjector) roll(p float64) bool {
+	return p > 0 && c.rng.Float64() < p
+}
+
+// killRandomSession kills one running agent session.
+// The Mayor and crew are never targeted: they are human-facing workspaces.
+func (c *chaosInjector) killRandomSession() {
+	sessions, err := c.tmux.ListSessions()
+	if err != nil {
+		c.logger.Printf("Chaos: listing sessions: %v", err)
+		return
+	}
+
+	var victims []string
+	for _, s := range sessions {
+		if !strings.HasPrefix(s, session.Prefix) || s == session.MayorSessionName() || strings.Contains(s, "-crew-") {
+			continue
+		}
+		victims = append(victims, s)
+	}
+	if len(victims) == 0 {
+		return
+	}
+
+	victim := victims[c.rng.Intn(len(victims))]
+	if err := c.tmux.KillSession(victim); err != nil {
+		c.logger.Printf("Chaos: killing %s: %v", victim, err)
+		return
+	}
+	c.logger.Printf("Chaos: killed session %s", victim)
+	_ = townlog.NewLogger(c.townRoot).Log(townlog.EventKill, victim, "chaos injection")
+}
+
+// corruptRandomWorktreeFile appends a conflict marker to a random file in a
+// random polecat worktree, so rescue and verification paths get exercised.
+func (c *chaosInjector) corruptRandomWorktreeFile() {
+	worktrees, _ := filepath.Glob(filepath.Join(c.townRoot, "*", constants.DirPolecats, "*"))
+	if len(worktrees) == 0 {
+		return
+	}
+	worktree := worktrees[c.rng.Intn(len(worktrees))]
+
+	target := c.pickFile(worktree)
+	if target == "" {
+		return
+	}
+
+	f, err := os.OpenFile(target, os.O_APPEND|os.O_WRONLY, 0) //nolint:gosec // G304: path from worktree walk
+	if err != nil {
+		c.logger.Printf("Chaos: opening %s: %v", target, err)
+		return
+	}
+	_, err = f.WriteString(chaosCorruptMarker)
+	_ = f.Close()
+	if err != nil {
+		c.logger.Printf("Chaos: corrupting %s: %v", target, err)
+		return
+	}
+	c.logger.Printf("Chaos: corrupted %s", target)
+}
+
+// pickFile returns a random regular file under dir, skipping git metadata
+// and runtime state. Returns "" if none is found.
+func (c *chaosInjector) pickFile(dir string) string {
+	var files []string
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", ".beads", constants.DirRuntime, "node_modules":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() && d.Name() != ".git" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if len(files) == 0 {
+		return ""
+	}
+	return files[c.rng.Intn(len(files))]
+}
+
+// String describes the enabled fault types, for the startup log line.
+func (c *chaosInjector) String() string {
+	return fmt.Sprintf("kill=%.2f corrupt=%.2f nudge_delay=%s",
+		c.cfg.KillProbability, c.cfg.CorruptProbability, c.cfg.NudgeDelay)
+}
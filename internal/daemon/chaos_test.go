@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/constants"
+)
+
+func writeChaosConfig(t *testing.T, townRoot string, chaos *config.ChaosConfig) {
+	t.Helper()
+	mc := config.NewMayorConfig()
+	mc.Daemon = &config.DaemonConfig{Chaos: chaos}
+	if err := config.SaveMayorConfig(constants.MayorConfigPath(townRoot), mc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadChaosConfig_Disabled(t *testing.T) {
+	townRoot := t.TempDir()
+	if LoadChaosConfig(townRoot) != nil {
+		t.Error("expected nil without mayor config")
+	}
+
+	writeChaosConfig(t, townRoot, &config.ChaosConfig{KillProbability: 1})
+	if LoadChaosConfig(townRoot) != nil {
+		t.Error("expected nil when enabled is false")
+	}
+	if ChaosNudgeDelay(townRoot) != 0 {
+		t.Error("expected no nudge delay when disabled")
+	}
+}
+
+func TestChaosNudgeDelay(t *testing.T) {
+	townRoot := t.TempDir()
+	writeChaosConfig(t, townRoot, &config.ChaosConfig{Enabled: true, NudgeDelay: "50ms"})
+
+	for i := 0; i < 20; i++ {
+		if d := ChaosNudgeDelay(townRoot); d < 0 || d >= 50_000_000 {
+			t.Fatalf("delay %v out of range", d)
+		}
+	}
+}
+
+func TestChaosCorruptWorktreeFile(t *testing.T) {
+	townRoot := t.TempDir()
+	writeChaosConfig(t, townRoot, &config.ChaosConfig{Enabled: true, CorruptProbability: 1, Seed: 1})
+
+	worktree := filepath.Join(townRoot, "gastown", constants.DirPolecats, "nux")
+	if err := os.MkdirAll(filepath.Join(worktree, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(worktree, "main.go")
+	if err := os.WriteFile(target, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitFile := filepath.Join(worktree, ".git", "HEAD")
+	if err := os.WriteFile(gitFile, []byte("ref: refs/heads/main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := newChaosInjector(townRoot, nil, log.New(io.Discard, "", 0))
+	if c == nil {
+		t.Fatal("expected injector when chaos is enabled")
+	}
+	c.corruptRandomWorktreeFile()
+
+	data, _ := os.ReadFile(target)
+	if !strings.Contains(string(data), "gt-chaos") {
+		t.Errorf("expected %s to be corrupted, got %q", target, data)
+	}
+	head, _ := os.ReadFile(gitFile)
+	if strings.Contains(string(head), "gt-chaos") {
+		t.Error("git metadata must never be corrupted")
+	}
+}
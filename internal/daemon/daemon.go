@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"strconv"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -19,9 +21,12 @@ import (
 	"github.com/ctiospl/gastown/internal/constants"
 	"github.com/ctiospl/gastown/internal/deacon"
 	"github.com/ctiospl/gastown/internal/feed"
+	"github.com/ctiospl/gastown/internal/idle"
+	"github.com/ctiospl/gastown/internal/notify"
 	"github.com/ctiospl/gastown/internal/polecat"
 	"github.com/ctiospl/gastown/internal/session"
 	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
 )
 
 // Daemon is the town-level background service.
@@ -35,6 +40,13 @@ type Daemon struct {
 	ctx     context.Context
 	cancel  context.CancelFunc
 	curator *feed.Curator
+	chaos   *chaosInjector // nil unless chaos mode is enabled
+
+	lastPrune time.Time // when pruneInactiveAgents last ran
+
+	idleSeen *idle.Tracker // worktree changes seen by sleepIdleAgents; nil until first used
+
+	progress atomic.Int64 // when the last heartbeat pass finished (Unix ns); see pulse
 }
 
 // New creates a new daemon instance.
@@ -99,6 +111,34 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
 
+	// Liveness heartbeat (checked by every gt command) and systemd watchdog
+	d.progress.Store(time.Now().UnixNano())
+	go d.runWatchdog()
+
+	// Scheduled nudges and wakes (gt schedule)
+	go d.runScheduler()
+
+	// Spawns waiting on other agents' done events (gt spawn --after)
+	go d.runPendingSpawns()
+
+	// Preempted agents and queued spawns, as slots free up
+	go d.runSlots()
+
+	// Instructions queued for agents, as they become ready (gt queue)
+	go d.runWorkQueues()
+
+	// Agents past their maximum runtime (gt spawn --max-runtime)
+	go d.runRuntimeLimits()
+
+	// Agent output, for sessions not captured as they started (gt output)
+	go d.runOutputCapture()
+
+	// Events of agents running on remote hosts (gt spawn --host)
+	go d.runRemoteSync()
+
+	// Agents' spend against the town's budgets (gt budget)
+	go d.runBudgets()
+
 	// Handle signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1)
@@ -118,6 +158,17 @@ func (d *Daemon) Run() error {
 		d.logger.Println("Feed curator started")
 	}
 
+	// Chaos mode (opt-in, staging towns only)
+	d.chaos = newChaosInjector(d.config.TownRoot, d.tmux, d.logger)
+	if d.chaos != nil {
+		d.logger.Printf("WARNING: chaos mode enabled (%s)", d.chaos)
+	}
+
+	// A running daemon resolves any open daemon-down incident
+	if _, err := notify.Clear(d.config.TownRoot, notify.ConditionDaemonDown, "daemon started"); err != nil {
+		d.logger.Printf("Warning: resolving daemon-down incident: %v", err)
+	}
+
 	// Initial heartbeat
 	d.heartbeat(state)
 
@@ -161,51 +212,149 @@ const recoveryHeartbeatInterval = 3 * time.Minute
 // - Orphaned work (assigned to dead agents)
 func (d *Daemon) heartbeat(state *State) {
 	d.logger.Println("Heartbeat starting (recovery-focused)")
+	start := time.Now()
 
 	// 1. Poke Boot (the Deacon's watchdog) instead of Deacon directly
 	// Boot handles the "when to wake Deacon" decision via triage logic
-	d.ensureBootRunning()
+	d.step("boot", d.ensureBootRunning)
 
 	// 1b. Direct Deacon heartbeat check (belt-and-suspenders)
 	// Boot may not detect all stuck states; this provides a fallback
-	d.checkDeaconHeartbeat()
+	d.step("deacon-heartbeat", d.checkDeaconHeartbeat)
 
 	// 2. Ensure Witnesses are running for all rigs (restart if dead)
-	d.ensureWitnessesRunning()
+	d.step("witnesses", d.ensureWitnessesRunning)
 
 	// 2b. Ensure Refineries are running for all rigs (restart if dead)
-	d.ensureRefineriesRunning()
+	d.step("refineries", d.ensureRefineriesRunning)
 
 	// 3. Trigger pending polecat spawns (bootstrap mode - ZFC violation acceptable)
 	// This ensures polecats get nudged even when Deacon isn't in a patrol cycle.
 	// Uses regex-based WaitForClaudeReady, which is acceptable for daemon bootstrap.
-	d.triggerPendingSpawns()
+	d.step("pending-spawns", d.triggerPendingSpawns)
 
 	// 4. Process lifecycle requests
-	d.processLifecycleRequests()
+	d.step("lifecycle", d.processLifecycleRequests)
 
 	// 5. Check for stale agents (timeout fallback)
 	// Agents that report "running" but haven't updated in too long are marked dead
-	d.checkStaleAgents()
+	d.step("stale-agents", d.checkStaleAgents)
 
 	// 6. Check for GUPP violations (agents with work-on-hook not progressing)
-	d.checkGUPPViolations()
+	d.step("gupp", d.checkGUPPViolations)
 
 	// 7. Check for orphaned work (assigned to dead agents)
-	d.checkOrphanedWork()
+	d.step("orphaned-work", d.checkOrphanedWork)
 
 	// 8. Check polecat session health (proactive crash detection)
 	// This validates tmux sessions are still alive for polecats with work-on-hook
-	d.checkPolecatSessionHealth()
+	d.step("polecat-health", d.checkPolecatSessionHealth)
+
+	// 8b. Probe every agent session's liveness and log unhealthy agents
+	d.step("agent-health", d.probeAgentHealth)
+
+	// 9. Advance notification escalation chains (unacked notifications)
+	d.step("notify-escalations", d.processNotificationEscalations)
+
+	// 10. Raise blockers of high-priority work to that priority
+	d.step("priority-inheritance", d.inheritPriorities)
+
+	// 11. Retire long-inactive crew, if the town asks for it (daily)
+	d.step("prune-agents", d.pruneInactiveAgents)
+
+	// 11b. Put idle agents to sleep, freeing their slots
+	d.step("sleep-idle", d.sleepIdleAgents)
+
+	// 12. Inject faults last, so the next heartbeat has to recover from them
+	if d.chaos != nil {
+		d.step("chaos", d.chaos.inject)
+	}
 
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
+	d.progress.Store(state.LastHeartbeat.UnixNano())
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
 
 	d.logger.Printf("Heartbeat complete (#%d)", state.HeartbeatCount)
+	slog.Info("daemon heartbeat", "n", state.HeartbeatCount, "duration", time.Since(start).Round(time.Millisecond))
+}
+
+// step runs one heartbeat step, recording its duration in the internal log.
+func (d *Daemon) step(name string, fn func()) {
+	start := time.Now()
+	fn()
+	slog.Debug("heartbeat step", "step", name, "duration", time.Since(start).Round(time.Millisecond))
+}
+
+// processNotificationEscalations delivers escalation steps for notifications
+// that have gone unacknowledged past their deadline, and the digest of
+// notifications batched while the operator is focused on one rig.
+func (d *Daemon) processNotificationEscalations() {
+	router, err := notify.NewRouter(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Warning: loading notification rules: %v", err)
+		return
+	}
+	deliveries, err := router.Escalate()
+	for _, del := range deliveries {
+		d.logger.Printf("Escalated notification %s (%s) to %s", del.Pending, del.Rule, del.Channel)
+	}
+	if err != nil {
+		d.logger.Printf("Warning: notification escalation: %v", err)
+	}
+
+	// Deliver the digest of notifications held while the operator is focused
+	if deliveries, err := router.FlushBatched(false); err != nil {
+		d.logger.Printf("Warning: notification digest: %v", err)
+	} else if len(deliveries) > 0 {
+		d.logger.Printf("Delivered focus digest to %d channel(s)", len(deliveries))
+	}
+
+	d.resolveClearedCrashLoops(router)
+}
+
+// resolveClearedCrashLoops resolves crash loop incidents for agents that
+// have not crashed for a full detection window.
+func (d *Daemon) resolveClearedCrashLoops(router *notify.Router) {
+	open, err := router.ListOpen()
+	if err != nil || len(open) == 0 {
+		return
+	}
+
+	events, err := townlog.ReadEvents(d.config.TownRoot)
+	if err != nil {
+		return
+	}
+	recent := townlog.FilterEvents(events, townlog.Filter{
+		Type:  townlog.EventCrash,
+		Since: time.Now().Add(-notify.CrashLoopWindow),
+	})
+
+	for _, inc := range open {
+		agent := notify.CrashLoopAgent(inc.Condition)
+		if agent == "" || time.Since(inc.OpenedAt) < notify.CrashLoopWindow {
+			continue
+		}
+		crashing := false
+		for _, e := range recent {
+			if e.Agent == agent {
+				crashing = true
+				break
+			}
+		}
+		if crashing {
+			continue
+		}
+		body := fmt.Sprintf("no crashes in the last %s", notify.CrashLoopWindow)
+		if _, err := notify.Clear(d.config.TownRoot, inc.Condition, body); err != nil {
+			d.logger.Printf("Warning: resolving %s: %v", inc.Condition, err)
+			continue
+		}
+		d.logger.Printf("Resolved crash loop incident for %s", agent)
+	}
 }
 
 // DeaconRole is the role name for the Deacon's handoff bead.
@@ -690,6 +839,9 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Printf("Warning: failed to save final state: %v", err)
 	}
 
+	// Clean stop: remove the liveness heartbeat so CLI checks don't alarm
+	d.clearBeat()
+
 	d.logger.Println("Daemon stopped")
 	return nil
 }
@@ -766,9 +918,10 @@ func StopDaemon(townRoot string) error {
 		_ = process.Signal(syscall.SIGKILL)
 	}
 
-	// Clean up PID file
+	// Clean up PID file and liveness heartbeat (an intentional stop is not an outage)
 	pidFile := filepath.Join(townRoot, "daemon", "daemon.pid")
 	_ = os.Remove(pidFile)
+	_ = os.Remove(HeartbeatFile(townRoot))
 
 	return nil
 }
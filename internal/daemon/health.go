@@ -0,0 +1,44 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/health"
+	"github.com/ctiospl/gastown/internal/session"
+)
+
+// probeAgentHealth probes every agent session for liveness, as 'gt health'
+// does, and logs agents that went unhealthy (or recovered) since the last
+// heartbeat. It only reports: restarting is left to the other steps.
+func (d *Daemon) probeAgentHealth() {
+	staleAfter := health.DefaultStaleAfter
+	if mc, err := config.LoadMayorConfig(constants.MayorConfigPath(d.config.TownRoot)); err == nil && mc.Daemon != nil && mc.Daemon.HealthStaleAfter != "" {
+		dur, err := time.ParseDuration(mc.Daemon.HealthStaleAfter)
+		if err != nil {
+			d.logger.Printf("Warning: daemon.health_stale_after: %v", err)
+		} else {
+			staleAfter = dur
+		}
+	}
+
+	now := time.Now()
+	paused, _ := session.LoadPauses(d.config.TownRoot)
+	probes, err := health.Check(d.tmux, now, staleAfter, paused)
+	if err != nil {
+		d.logger.Printf("Warning: probing agent health: %v", err)
+		return
+	}
+	changed, err := health.Record(d.config.TownRoot, probes, now)
+	if err != nil {
+		d.logger.Printf("Warning: recording agent health: %v", err)
+	}
+	for _, p := range changed {
+		if p.Status.Healthy() {
+			d.logger.Printf("Agent %s recovered", p.Agent)
+		} else {
+			d.logger.Printf("Agent %s is %s: %s", p.Agent, p.Status, p.Reason)
+		}
+	}
+}
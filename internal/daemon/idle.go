@@ -0,0 +1,41 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/idle"
+)
+
+// sleepIdleAgents puts worker agents that have printed no output and made
+// no file changes for the town's daemon.sleep_idle_after to sleep, freeing
+// their slots. They are woken when work arrives for them (see idle.Wake).
+func (d *Daemon) sleepIdleAgents() {
+	mc, err := config.LoadMayorConfig(constants.MayorConfigPath(d.config.TownRoot))
+	if err != nil || mc.Daemon == nil || mc.Daemon.SleepIdleAfter == "" {
+		return
+	}
+	after, err := time.ParseDuration(mc.Daemon.SleepIdleAfter)
+	if err != nil || after <= 0 {
+		d.logger.Printf("Warning: daemon.sleep_idle_after: %v", err)
+		return
+	}
+
+	if d.idleSeen == nil {
+		d.idleSeen = idle.NewTracker()
+	}
+	now := time.Now()
+	agents, err := d.idleSeen.Idle(d.tmux, d.config.TownRoot, after, now)
+	if err != nil {
+		d.logger.Printf("Warning: checking for idle agents: %v", err)
+		return
+	}
+	for _, a := range agents {
+		if err := idle.Sleep(d.tmux, d.config.TownRoot, a, now); err != nil {
+			d.logger.Printf("Warning: putting idle %s to sleep: %v", a.Agent, err)
+			continue
+		}
+		d.logger.Printf("Put %s to sleep (idle since %s)", a.Agent, a.LastActive().Format("15:04"))
+	}
+}
@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/ctiospl/gastown/internal/outputlog"
+	"github.com/ctiospl/gastown/internal/session"
+)
+
+// outputInterval is how often the daemon looks for agent sessions whose
+// output is not being captured.
+const outputInterval = 30 * time.Second
+
+// runOutputCapture captures the output of agent sessions started without
+// it (see the outputlog package).
+func (d *Daemon) runOutputCapture() {
+	d.captureOutput()
+	ticker := time.NewTicker(outputInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			d.captureOutput()
+		}
+	}
+}
+
+// captureOutput starts capturing each agent session not yet captured.
+func (d *Daemon) captureOutput() {
+	sessions, err := d.tmux.ListSessions()
+	if err != nil {
+		return
+	}
+	agents := make(map[string]string)
+	for _, name := range sessions {
+		if id, err := session.ParseSessionName(name); err == nil {
+			agents[name] = id.Address()
+		}
+	}
+	started, err := outputlog.StartAll(d.tmux, d.config.TownRoot, agents)
+	if err != nil {
+		d.logger.Printf("Warning: capturing agent output: %v", err)
+	}
+	for _, agent := range started {
+		d.logger.Printf("Capturing output of %s", agent)
+	}
+}
@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"math"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/pending"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+// runPendingSpawns follows the town log for done events and spawns the
+// agents waiting on them (gt spawn --after). Done events logged while the
+// daemon was down, since the oldest pending spawn was declared, are
+// caught up on first.
+func (d *Daemon) runPendingSpawns() {
+	for {
+		since := pending.Oldest(d.config.TownRoot)
+		if since.IsZero() {
+			since = time.Now()
+		}
+		opts := townlog.FollowOptions{
+			Filter:  townlog.Filter{Type: townlog.EventDone, Since: since.Truncate(time.Second)},
+			Backlog: math.MaxInt,
+		}
+		err := townlog.Follow(d.ctx, d.config.TownRoot, opts, func(e townlog.Event) error {
+			d.spawnReady(e)
+			return nil
+		})
+		if err != nil {
+			d.logger.Printf("Warning: following the town log for pending spawns: %v", err)
+		}
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-time.After(time.Minute):
+		}
+	}
+}
+
+// spawnReady spawns the pending agents whose last dependency e satisfied.
+func (d *Daemon) spawnReady(e townlog.Event) {
+	ready, err := pending.Observe(d.config.TownRoot, e)
+	if err != nil {
+		d.logger.Printf("Warning: checking pending spawns: %v", err)
+		return
+	}
+	for _, s := range ready {
+		err := pending.Run(d.config.TownRoot, s)
+		pending.Log(d.config.TownRoot, s, err)
+		if err != nil {
+			d.logger.Printf("Warning: pending spawn %s of %s failed: %v", s.ID, s.Agent, err)
+			continue
+		}
+		d.logger.Printf("Spawned %s after %v (%s)", s.Agent, s.After, s.ID)
+	}
+}
@@ -0,0 +1,46 @@
+package daemon
+
+import (
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/preempt"
+	"github.com/ctiospl/gastown/internal/session"
+)
+
+// resumePreempted resumes agents paused to make room for higher-priority
+// work (gt spawn --priority) while the town is under its
+// max_concurrent_agents limit, highest priority first. Without a limit,
+// every preempted agent is resumed.
+func (d *Daemon) resumePreempted() {
+	pauses, err := session.LoadPauses(d.config.TownRoot)
+	if err != nil || len(preempt.Resumable(pauses, nil, 1)) == 0 {
+		return
+	}
+	// Forget preempted agents whose sessions have gone, which hold no slot
+	for name, p := range pauses {
+		if exists, _ := d.tmux.HasSession(name); !exists && p.By == preempt.PausedBy {
+			_, _ = session.ResumeSession(d.tmux, d.config.TownRoot, name)
+			delete(pauses, name)
+		}
+	}
+
+	free := len(pauses)
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot)); err == nil && settings.MaxConcurrentAgents > 0 {
+		running, err := preempt.Running(d.tmux, d.config.TownRoot)
+		if err != nil {
+			d.logger.Printf("Warning: counting running agents: %v", err)
+			return
+		}
+		free = settings.MaxConcurrentAgents - len(running)
+	}
+	if free <= 0 {
+		return
+	}
+	ps, _ := preempt.Load(d.config.TownRoot)
+	for _, p := range preempt.Resumable(pauses, ps, free) {
+		if err := preempt.Resume(d.tmux, d.config.TownRoot, p); err != nil {
+			d.logger.Printf("Warning: resuming preempted %s: %v", p.Agent, err)
+			continue
+		}
+		d.logger.Printf("Resumed preempted %s", p.Agent)
+	}
+}
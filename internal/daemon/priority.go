@@ -0,0 +1,97 @@
+package daemon
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/beads"
+)
+
+// inheritPriorities raises the priority of open issues that block
+// higher-priority work, so critical paths are not starved behind
+// low-priority tasks, and reverts the boost once it is no longer needed.
+// Active boosts are recorded in the town's inheritance ledger and marked
+// with the inherited-priority label.
+func (d *Daemon) inheritPriorities() {
+	b := beads.New(d.config.TownRoot)
+	blocked, err := b.Blocked()
+	if err != nil {
+		return // bd unavailable; try next heartbeat
+	}
+	ledger, err := beads.LoadInheritanceLedger(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Warning: %v", err)
+		return
+	}
+	if len(blocked) == 0 && len(ledger.Boosts) == 0 {
+		return
+	}
+
+	all, err := b.List(beads.ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		return
+	}
+	issues := make(map[string]*beads.Issue, len(all))
+	for _, issue := range all {
+		if issue.Status != "closed" {
+			issues[issue.ID] = issue
+		}
+	}
+	for _, issue := range blocked {
+		if open, ok := issues[issue.ID]; ok {
+			open.BlockedBy = issue.BlockedBy
+		}
+	}
+
+	apply, revert := beads.ReconcileInheritance(issues, ledger, time.Now())
+	for _, boost := range apply {
+		priority := boost.Inherited
+		if err := b.Update(boost.ID, beads.UpdateOptions{
+			Priority:  &priority,
+			AddLabels: []string{beads.InheritedPriorityLabel},
+		}); err != nil {
+			d.logger.Printf("Warning: failed to boost %s: %v", boost.ID, err)
+			ledger.Boosts[boost.ID].Inherited = -1 // keep Original; retry next heartbeat
+			continue
+		}
+		d.logger.Printf("Priority inheritance: %s P%d → P%d (blocks %s)", boost.ID, boost.Original, boost.Inherited, boost.From)
+		if boost.Assignee != "" {
+			d.notifyPriorityInherited(boost)
+		}
+	}
+	for _, boost := range revert {
+		update := beads.UpdateOptions{RemoveLabels: []string{beads.InheritedPriorityLabel}}
+		// Leave the priority alone if someone changed it by hand since.
+		if issue := issues[boost.ID]; issue != nil && issue.Priority == boost.Inherited {
+			priority := boost.Original
+			update.Priority = &priority
+		}
+		if err := b.Update(boost.ID, update); err != nil {
+			d.logger.Printf("Warning: failed to revert inherited priority on %s: %v", boost.ID, err)
+			continue
+		}
+		d.logger.Printf("Priority inheritance ended: %s back to P%d", boost.ID, boost.Original)
+	}
+
+	if err := ledger.Save(d.config.TownRoot); err != nil {
+		d.logger.Printf("Warning: failed to save inheritance ledger: %v", err)
+	}
+}
+
+// notifyPriorityInherited tells the assigned agent its work now gates
+// higher-priority work.
+func (d *Daemon) notifyPriorityInherited(boost beads.PriorityInheritance) {
+	subject := fmt.Sprintf("PRIORITY_INHERITED: %s is now P%d", boost.ID, boost.Inherited)
+	body := fmt.Sprintf(`%s blocks %s, so it now runs at P%d (was P%d).
+
+Finish it before lower-priority work. The original priority is restored
+once %s is no longer blocked on it.`,
+		boost.ID, boost.From, boost.Inherited, boost.Original, boost.From)
+
+	cmd := exec.Command("gt", "mail", "send", boost.Assignee, "-s", subject, "-m", body)
+	cmd.Dir = d.config.TownRoot
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: failed to notify %s of inherited priority: %v", boost.Assignee, err)
+	}
+}
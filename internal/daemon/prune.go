@@ -0,0 +1,57 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/oplock"
+	"github.com/ctiospl/gastown/internal/prune"
+)
+
+// pruneInterval is how often the daemon looks for inactive crew.
+const pruneInterval = 24 * time.Hour
+
+// pruneInactiveAgents retires crew workers idle for longer than the town's
+// daemon.prune_agents_after, as 'gt prune agents' would. Workers with a
+// running session or unpushed work are left alone. It runs at most once a
+// day, and skips a heartbeat when other gt operations hold the town lock.
+func (d *Daemon) pruneInactiveAgents() {
+	if time.Since(d.lastPrune) < pruneInterval {
+		return
+	}
+	mc, err := config.LoadMayorConfig(constants.MayorConfigPath(d.config.TownRoot))
+	if err != nil || mc.Daemon == nil || mc.Daemon.PruneAgentsAfter == "" {
+		return
+	}
+	age, err := prune.ParseAge(mc.Daemon.PruneAgentsAfter)
+	if err != nil || age <= 0 {
+		d.logger.Printf("Warning: daemon.prune_agents_after: %v", err)
+		return
+	}
+
+	lock, err := oplock.Acquire(d.ctx, d.config.TownRoot, oplock.Options{Command: "gt daemon (prune agents)"})
+	if err != nil {
+		return // try again next heartbeat
+	}
+	defer lock.Release()
+	d.lastPrune = time.Now()
+
+	roster, err := prune.Roster(d.config.TownRoot, d.getKnownRigs())
+	if err != nil {
+		d.logger.Printf("Warning: pruning agents: %v", err)
+		return
+	}
+	for _, id := range prune.Inactive(roster, time.Now().Add(-age)) {
+		if reason := prune.Check(id); reason != "" {
+			d.logger.Printf("Not retiring %s (inactive since %s): %s", id.Agent, id.LastActive.Format("2006-01-02"), reason)
+			continue
+		}
+		r, err := prune.Retire(d.config.TownRoot, id, time.Now())
+		if err != nil {
+			d.logger.Printf("Warning: retiring %s: %v", id.Agent, err)
+			continue
+		}
+		d.logger.Printf("Retired %s (inactive since %s), archived to %s", id.Agent, id.LastActive.Format("2006-01-02"), r.Archive)
+	}
+}
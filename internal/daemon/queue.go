@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/pending"
+	"github.com/ctiospl/gastown/internal/preempt"
+)
+
+// slotInterval is how often the daemon fills free slots under the town's
+// max_concurrent_agents.
+const slotInterval = 30 * time.Second
+
+// runSlots fills the slots that free up under the town's
+// max_concurrent_agents as agents finish. It checks every 30 seconds,
+// rather than every heartbeat, so a queued agent starts soon after a slot
+// frees up. Preempted agents are resumed first: they have work in flight.
+func (d *Daemon) runSlots() {
+	ticker := time.NewTicker(slotInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			d.resumePreempted()
+			d.startQueuedSpawns()
+		}
+	}
+}
+
+// startQueuedSpawns starts spawns queued at max_concurrent_agents (gt
+// spawn) into the free slots, highest priority first and oldest first
+// among equals. Without a limit, every queued spawn is started.
+func (d *Daemon) startQueuedSpawns() {
+	list, err := pending.Load(d.config.TownRoot)
+	if err != nil || len(pending.Queue(list)) == 0 {
+		return
+	}
+	free := len(list)
+	limit := 0
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot)); err == nil && settings.MaxConcurrentAgents > 0 {
+		limit = settings.MaxConcurrentAgents
+		running, err := preempt.Running(d.tmux, d.config.TownRoot)
+		if err != nil {
+			d.logger.Printf("Warning: counting running agents: %v", err)
+			return
+		}
+		free = limit - len(running)
+	}
+	if free <= 0 {
+		return
+	}
+	next, err := pending.Dequeue(d.config.TownRoot, free)
+	if err != nil {
+		d.logger.Printf("Warning: dequeuing spawns: %v", err)
+		return
+	}
+	for _, s := range next {
+		decision := fmt.Sprintf("started after %s queued: a slot is free", time.Since(s.CreatedAt).Round(time.Second))
+		if limit == 0 {
+			decision = fmt.Sprintf("started after %s queued: max_concurrent_agents was removed", time.Since(s.CreatedAt).Round(time.Second))
+		}
+		err := pending.Run(d.config.TownRoot, s)
+		if err != nil {
+			pending.LogQueue(d.config.TownRoot, s, "failed to start", err)
+			d.logger.Printf("Warning: queued spawn %s of %s failed: %v", s.ID, s.Agent, err)
+			continue
+		}
+		pending.LogQueue(d.config.TownRoot, s, decision, nil)
+		d.logger.Printf("Started queued %s (%s)", s.Agent, s.ID)
+	}
+}
@@ -0,0 +1,59 @@
+package daemon
+
+import (
+	"slices"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/remote"
+	"github.com/ctiospl/gastown/internal/session"
+)
+
+// remoteSyncInterval is how often the daemon pulls the events of agents
+// running on remote hosts into the town log.
+const remoteSyncInterval = time.Minute
+
+// runRemoteSync pulls the events logged on the hosts that crew members run
+// on (gt spawn --host) into the town log.
+func (d *Daemon) runRemoteSync() {
+	ticker := time.NewTicker(remoteSyncInterval)
+	defer ticker.Stop()
+	var last []string
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			last = d.syncRemoteEvents(last)
+		}
+	}
+}
+
+// syncRemoteEvents syncs the events of each host an agent runs on, and
+// once more of each in last, the hosts synced before, so the events of
+// agents since stopped are not missed. It returns the hosts agents run on.
+func (d *Daemon) syncRemoteEvents(last []string) []string {
+	active := remote.ActiveHosts(d.config.TownRoot, func(rigName, crewName string) bool {
+		running, err := d.tmux.HasSession(session.CrewSessionName(rigName, crewName))
+		return err == nil && running
+	})
+	hosts := append([]string{}, active...)
+	for _, name := range last {
+		if !slices.Contains(active, name) {
+			hosts = append(hosts, name)
+		}
+	}
+	for _, name := range hosts {
+		h, err := config.LoadHost(d.config.TownRoot, name)
+		if err == nil {
+			var n int
+			if n, err = remote.SyncEvents(d.config.TownRoot, h); n > 0 {
+				d.logger.Printf("Synced %d event(s) from host %s", n, name)
+			}
+		}
+		if err != nil {
+			d.logger.Printf("Warning: syncing events from host %s: %v", name, err)
+		}
+	}
+	return active
+}
@@ -0,0 +1,46 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/ctiospl/gastown/internal/runlimit"
+)
+
+// runLimitInterval is how often the daemon looks for agents past their
+// maximum runtime.
+const runLimitInterval = time.Minute
+
+// runRuntimeLimits stops agents that have run past the maximum runtime
+// they were spawned with (gt spawn --max-runtime).
+func (d *Daemon) runRuntimeLimits() {
+	ticker := time.NewTicker(runLimitInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case now := <-ticker.C:
+			d.enforceRuntimeLimits(now)
+		}
+	}
+}
+
+// enforceRuntimeLimits stops each agent past its limit. A graceful stop
+// waits for the agent to hand off, so each runs in its own goroutine; the
+// limit is dropped first, so it is enforced only once.
+func (d *Daemon) enforceRuntimeLimits(now time.Time) {
+	due, err := runlimit.Due(d.tmux, d.config.TownRoot, now)
+	if err != nil {
+		d.logger.Printf("Warning: checking runtime limits: %v", err)
+	}
+	for _, l := range due {
+		d.logger.Printf("%s reached its max runtime of %s; stopping it", l.Agent, runlimit.FormatDuration(l.MaxRuntime))
+		go func() {
+			err := runlimit.Run(d.config.TownRoot, l)
+			runlimit.Log(d.config.TownRoot, l, err)
+			if err != nil {
+				d.logger.Printf("Warning: stopping %s at its max runtime: %v", l.Agent, err)
+			}
+		}()
+	}
+}
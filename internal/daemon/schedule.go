@@ -0,0 +1,36 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/ctiospl/gastown/internal/schedule"
+)
+
+// runScheduler runs the town's scheduled jobs (gt schedule) as they come
+// due. It checks every minute, rather than every heartbeat, so a job runs
+// within a minute of its time.
+func (d *Daemon) runScheduler() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case now := <-ticker.C:
+			d.runScheduledJobs(now)
+		}
+	}
+}
+
+// runScheduledJobs runs the jobs due by now.
+func (d *Daemon) runScheduledJobs(now time.Time) {
+	ran, err := schedule.RunDue(d.config.TownRoot, now, func(j schedule.Job) error {
+		return schedule.Run(d.config.TownRoot, d.tmux, j)
+	})
+	if err != nil {
+		d.logger.Printf("Warning: running scheduled jobs: %v", err)
+	}
+	for _, j := range ran {
+		d.logger.Printf("Ran scheduled job %s: %s", j.ID, j)
+	}
+}
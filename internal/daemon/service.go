@@ -0,0 +1,178 @@
+package daemon
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// ServiceManager identifies an OS service manager that can supervise the daemon.
+type ServiceManager string
+
+const (
+	// ServiceSystemd is a systemd user unit (Linux).
+	ServiceSystemd ServiceManager = "systemd"
+
+	// ServiceLaunchd is a launchd user agent (macOS).
+	ServiceLaunchd ServiceManager = "launchd"
+)
+
+// DetectServiceManager returns the service manager for this OS.
+func DetectServiceManager() (ServiceManager, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return ServiceSystemd, nil
+	case "darwin":
+		return ServiceLaunchd, nil
+	default:
+		return "", fmt.Errorf("no supported service manager on %s", runtime.GOOS)
+	}
+}
+
+// ParseServiceManager validates a service manager name.
+func ParseServiceManager(s string) (ServiceManager, error) {
+	switch m := ServiceManager(s); m {
+	case ServiceSystemd, ServiceLaunchd:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown service manager %q (want systemd or launchd)", s)
+	}
+}
+
+// ServiceSpec describes how a service manager should run the daemon.
+type ServiceSpec struct {
+	TownName string // used in the unit name and description
+	TownRoot string // working directory
+	GTPath   string // absolute path to the gt binary
+	Path     string // PATH for the daemon (tmux, bd, and agent runtimes must resolve)
+	Home     string // HOME for the daemon (agent runtimes read their config from it)
+	User     string // user the daemon runs as; informational, units are per-user
+}
+
+var unsafeUnitChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// serviceSlug returns a unit-safe lowercase form of the town name.
+func serviceSlug(townName string) string {
+	name := strings.Trim(unsafeUnitChars.ReplaceAllString(townName, "-"), "-")
+	if name == "" {
+		name = "town"
+	}
+	return strings.ToLower(name)
+}
+
+// ServiceName returns the service name for a town, e.g. "gastown-daemon-mytown".
+func ServiceName(townName string) string {
+	return "gastown-daemon-" + serviceSlug(townName)
+}
+
+// LaunchdLabel returns the launchd label for a town, e.g. "com.gastown.daemon.mytown".
+func LaunchdLabel(townName string) string {
+	return "com.gastown.daemon." + serviceSlug(townName)
+}
+
+// ServiceFileName returns the unit file name for a town under manager m.
+func ServiceFileName(m ServiceManager, townName string) string {
+	if m == ServiceLaunchd {
+		return LaunchdLabel(townName) + ".plist"
+	}
+	return ServiceName(townName) + ".service"
+}
+
+// ServiceUnit renders the unit file for manager m.
+func ServiceUnit(m ServiceManager, spec ServiceSpec) string {
+	if m == ServiceLaunchd {
+		return LaunchdPlist(spec)
+	}
+	return SystemdUnit(spec)
+}
+
+// SystemdUnit renders a systemd user unit for the daemon.
+// The unit uses Type=notify with a watchdog: the daemon pings systemd every
+// WatchdogInterval, and systemd restarts it if the pings stop.
+func SystemdUnit(spec ServiceSpec) string {
+	watchdogSec := int(WatchdogStaleAfter.Seconds())
+	var env strings.Builder
+	fmt.Fprintf(&env, "Environment=PATH=%s\n", spec.Path)
+	if spec.Home != "" {
+		fmt.Fprintf(&env, "Environment=HOME=%s\n", spec.Home)
+	}
+	return fmt.Sprintf(`[Unit]
+Description=Gas Town daemon (%s)
+Documentation=https://github.com/ctiospl/gastown
+
+[Service]
+Type=notify
+NotifyAccess=main
+ExecStart=%s daemon run
+WorkingDirectory=%s
+%sRestart=always
+RestartSec=5
+WatchdogSec=%d
+
+[Install]
+WantedBy=default.target
+`, describeTown(spec), systemdQuote(spec.GTPath), spec.TownRoot, env.String(), watchdogSec)
+}
+
+// LaunchdPlist renders a launchd user agent for the daemon. launchd has no
+// watchdog protocol, so KeepAlive restarts the daemon only when it exits;
+// hangs are surfaced by the liveness heartbeat instead.
+func LaunchdPlist(spec ServiceSpec) string {
+	x := html.EscapeString
+	var env strings.Builder
+	fmt.Fprintf(&env, "\t\t<key>PATH</key>\n\t\t<string>%s</string>\n", x(spec.Path))
+	if spec.Home != "" {
+		fmt.Fprintf(&env, "\t\t<key>HOME</key>\n\t\t<string>%s</string>\n", x(spec.Home))
+	}
+	logPath := spec.TownRoot + "/daemon/launchd.log"
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<!-- Gas Town daemon (%s) -->
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+		<string>daemon</string>
+		<string>run</string>
+	</array>
+	<key>WorkingDirectory</key>
+	<string>%s</string>
+	<key>EnvironmentVariables</key>
+	<dict>
+%s	</dict>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+	<key>ThrottleInterval</key>
+	<integer>5</integer>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, x(describeTown(spec)), x(LaunchdLabel(spec.TownName)), x(spec.GTPath), x(spec.TownRoot),
+		env.String(), x(logPath), x(logPath))
+}
+
+// describeTown returns the human-readable service description suffix.
+func describeTown(spec ServiceSpec) string {
+	if spec.User != "" {
+		return spec.TownName + ", user " + spec.User
+	}
+	return spec.TownName
+}
+
+// systemdQuote quotes an ExecStart argument if it contains spaces.
+func systemdQuote(s string) string {
+	if strings.ContainsAny(s, " \t") {
+		return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+	}
+	return s
+}
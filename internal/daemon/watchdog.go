@@ -0,0 +1,167 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// WatchdogInterval is how often the daemon writes its liveness heartbeat.
+// This is independent of the (much slower) recovery heartbeat so that a
+// long-running recovery pass never looks like a dead daemon, but it stops
+// once a pass has run past heartbeatStallAfter.
+const WatchdogInterval = 30 * time.Second
+
+// heartbeatStallAfter is how long after the last recovery heartbeat pass
+// finished the daemon counts as stuck in the next one: an interval for the
+// next pass to start, and as long again for it to finish.
+const heartbeatStallAfter = 2 * recoveryHeartbeatInterval
+
+// WatchdogStaleAfter is how old the liveness heartbeat may get before the
+// daemon is considered hung.
+const WatchdogStaleAfter = 3 * WatchdogInterval
+
+// Beat is the content of the liveness heartbeat file.
+type Beat struct {
+	PID  int       `json:"pid"`
+	Time time.Time `json:"time"`
+}
+
+// HeartbeatFile returns the path to the daemon's liveness heartbeat.
+// The file exists while the daemon runs and is removed on clean shutdown,
+// so a present-but-stale file means the daemon died without stopping.
+func HeartbeatFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "heartbeat.json")
+}
+
+// HealthStatus classifies daemon liveness.
+type HealthStatus string
+
+const (
+	// HealthOK means the daemon is running and beating.
+	HealthOK HealthStatus = "ok"
+
+	// HealthStopped means the daemon is not running and was stopped cleanly
+	// (or never started). Not an alarm.
+	HealthStopped HealthStatus = "stopped"
+
+	// HealthDead means the daemon exited without cleaning up its heartbeat.
+	HealthDead HealthStatus = "dead"
+
+	// HealthHung means the daemon process exists but has stopped beating.
+	HealthHung HealthStatus = "hung"
+)
+
+// Health is the result of a liveness check.
+type Health struct {
+	Status   HealthStatus
+	PID      int
+	LastBeat time.Time
+}
+
+// Unhealthy reports whether supervision is silently disabled.
+func (h Health) Unhealthy() bool {
+	return h.Status == HealthDead || h.Status == HealthHung
+}
+
+// CheckHealth inspects the liveness heartbeat. It is cheap enough to run
+// on every gt invocation.
+func CheckHealth(townRoot string) Health {
+	data, err := os.ReadFile(HeartbeatFile(townRoot))
+	if err != nil {
+		return Health{Status: HealthStopped}
+	}
+	var beat Beat
+	if err := json.Unmarshal(data, &beat); err != nil {
+		return Health{Status: HealthDead}
+	}
+
+	h := Health{PID: beat.PID, LastBeat: beat.Time}
+	if !processAlive(beat.PID) {
+		h.Status = HealthDead
+	} else if time.Since(beat.Time) > WatchdogStaleAfter {
+		h.Status = HealthHung
+	} else {
+		h.Status = HealthOK
+	}
+	return h
+}
+
+// processAlive reports whether pid is a live process.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// runWatchdog writes the liveness heartbeat and pings systemd's watchdog
+// until the daemon context is canceled.
+func (d *Daemon) runWatchdog() {
+	ticker := time.NewTicker(WatchdogInterval)
+	defer ticker.Stop()
+
+	d.pulse(time.Now())
+	sdNotify("READY=1")
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			d.pulse(time.Now())
+		}
+	}
+}
+
+// pulse beats unless the recovery heartbeat has stalled, reporting whether
+// it did. A daemon stuck in a heartbeat pass stops beating, so gt reports
+// it hung and systemd's watchdog restarts it.
+func (d *Daemon) pulse(now time.Time) bool {
+	last := time.Unix(0, d.progress.Load())
+	if stalled := now.Sub(last); stalled > heartbeatStallAfter {
+		d.logger.Printf("Warning: no heartbeat pass finished in %s; withholding liveness heartbeat", stalled.Round(time.Second))
+		return false
+	}
+	d.beat()
+	return true
+}
+
+// beat records one liveness heartbeat.
+func (d *Daemon) beat() {
+	beat := Beat{PID: os.Getpid(), Time: time.Now()}
+	if err := util.AtomicWriteJSON(HeartbeatFile(d.config.TownRoot), beat); err != nil {
+		d.logger.Printf("Warning: writing liveness heartbeat: %v", err)
+	}
+	sdNotify("WATCHDOG=1")
+}
+
+// clearBeat removes the liveness heartbeat on clean shutdown.
+func (d *Daemon) clearBeat() {
+	_ = os.Remove(HeartbeatFile(d.config.TownRoot))
+	sdNotify("STOPPING=1")
+}
+
+// sdNotify sends a state update to systemd when running under a
+// Type=notify unit. It is a no-op otherwise.
+func sdNotify(state string) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return
+	}
+	addr := &net.UnixAddr{Name: socket, Net: "unixgram"}
+	conn, err := net.DialUnix("unixgram", nil, addr)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	_, _ = conn.Write([]byte(state))
+}
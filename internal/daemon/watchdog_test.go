@@ -0,0 +1,125 @@
+package daemon
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+func writeBeat(t *testing.T, townRoot string, beat Beat) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(townRoot, "daemon"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := util.AtomicWriteJSON(HeartbeatFile(townRoot), beat); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if h := CheckHealth(townRoot); h.Status != HealthStopped || h.Unhealthy() {
+		t.Errorf("no heartbeat file: got %s, want stopped", h.Status)
+	}
+
+	writeBeat(t, townRoot, Beat{PID: os.Getpid(), Time: time.Now()})
+	if h := CheckHealth(townRoot); h.Status != HealthOK {
+		t.Errorf("fresh beat from live pid: got %s, want ok", h.Status)
+	}
+
+	writeBeat(t, townRoot, Beat{PID: os.Getpid(), Time: time.Now().Add(-2 * WatchdogStaleAfter)})
+	if h := CheckHealth(townRoot); h.Status != HealthHung || !h.Unhealthy() {
+		t.Errorf("stale beat from live pid: got %s, want hung", h.Status)
+	}
+
+	writeBeat(t, townRoot, Beat{PID: 0, Time: time.Now()})
+	if h := CheckHealth(townRoot); h.Status != HealthDead || !h.Unhealthy() {
+		t.Errorf("beat from dead pid: got %s, want dead", h.Status)
+	}
+}
+
+func TestPulseStopsWhenHeartbeatStalls(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "daemon"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	d := &Daemon{config: &Config{TownRoot: townRoot}, logger: log.New(io.Discard, "", 0)}
+	now := time.Now()
+
+	d.progress.Store(now.Add(-time.Minute).UnixNano())
+	if !d.pulse(now) {
+		t.Fatal("no beat while heartbeat passes finish")
+	}
+	if h := CheckHealth(townRoot); h.Status != HealthOK {
+		t.Errorf("after a beat: got %s, want ok", h.Status)
+	}
+
+	// Stuck in a pass: the beat goes stale and the daemon reads as hung
+	d.progress.Store(now.Add(-heartbeatStallAfter - time.Second).UnixNano())
+	writeBeat(t, townRoot, Beat{PID: os.Getpid(), Time: now.Add(-2 * WatchdogStaleAfter)})
+	if d.pulse(now) {
+		t.Error("beat while a heartbeat pass is stuck")
+	}
+	if h := CheckHealth(townRoot); h.Status != HealthHung {
+		t.Errorf("stuck daemon: got %s, want hung", h.Status)
+	}
+}
+
+func TestSystemdUnit(t *testing.T) {
+	unit := SystemdUnit(ServiceSpec{
+		TownName: "my town",
+		TownRoot: "/home/me/gt",
+		GTPath:   "/usr/local/bin/gt",
+		Path:     "/usr/bin:/bin",
+	})
+	for _, want := range []string{
+		"Type=notify",
+		"ExecStart=/usr/local/bin/gt daemon run",
+		"WorkingDirectory=/home/me/gt",
+		"Restart=always",
+		"WatchdogSec=90",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit missing %q:\n%s", want, unit)
+		}
+	}
+
+	if got := ServiceName("My Town!"); got != "gastown-daemon-my-town" {
+		t.Errorf("ServiceName = %q", got)
+	}
+}
+
+func TestLaunchdPlist(t *testing.T) {
+	plist := ServiceUnit(ServiceLaunchd, ServiceSpec{
+		TownName: "Dev & Test",
+		TownRoot: "/Users/me/gt",
+		GTPath:   "/opt/homebrew/bin/gt",
+		Path:     "/opt/homebrew/bin:/usr/bin",
+		Home:     "/Users/me",
+	})
+	for _, want := range []string{
+		"<string>com.gastown.daemon.dev-test</string>",
+		"<string>/opt/homebrew/bin/gt</string>",
+		"<key>KeepAlive</key>",
+		"<key>HOME</key>",
+		"Dev &amp; Test",
+		"/Users/me/gt/daemon/launchd.log",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("plist missing %q:\n%s", want, plist)
+		}
+	}
+	if got := ServiceFileName(ServiceLaunchd, "Dev & Test"); got != "com.gastown.daemon.dev-test.plist" {
+		t.Errorf("ServiceFileName = %q", got)
+	}
+	if _, err := ParseServiceManager("upstart"); err == nil {
+		t.Error("expected error for unknown manager")
+	}
+}
@@ -0,0 +1,39 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/ctiospl/gastown/internal/workqueue"
+)
+
+// queueInterval is how often the daemon looks for agents ready for the
+// next instruction of their work queue.
+const queueInterval = 30 * time.Second
+
+// runWorkQueues hands agents the instructions queued for them (gt queue)
+// as they finish their tasks or go idle. It checks every 30 seconds,
+// rather than every heartbeat, so an agent gets its next instruction soon
+// after it becomes ready for it.
+func (d *Daemon) runWorkQueues() {
+	ticker := time.NewTicker(queueInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case now := <-ticker.C:
+			d.deliverQueued(now)
+		}
+	}
+}
+
+// deliverQueued delivers the next instruction of each ready agent.
+func (d *Daemon) deliverQueued(now time.Time) {
+	delivered, err := workqueue.DeliverReady(d.tmux, d.config.TownRoot, now)
+	if err != nil {
+		d.logger.Printf("Warning: delivering queued instructions: %v", err)
+	}
+	for _, it := range delivered {
+		d.logger.Printf("Delivered queued instruction %s to %s", it.ID, it.Agent)
+	}
+}
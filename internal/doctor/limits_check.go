@@ -0,0 +1,64 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/ctiospl/gastown/internal/config"
+)
+
+// ResourceLimitsCheck verifies that the resource limits set in town
+// settings are valid and can be enforced on this machine.
+type ResourceLimitsCheck struct {
+	BaseCheck
+}
+
+// NewResourceLimitsCheck creates a new resource limits check.
+func NewResourceLimitsCheck() *ResourceLimitsCheck {
+	return &ResourceLimitsCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "resource-limits",
+			CheckDescription: "Check agent resource limits can be enforced",
+		},
+	}
+}
+
+// Run checks the town's resource limits.
+func (c *ResourceLimitsCheck) Run(ctx *CheckContext) *CheckResult {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot))
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Could not load town settings: %v", err),
+		}
+	}
+	if settings.Limits == nil || (settings.Limits.Default.IsZero() && len(settings.Limits.Rigs) == 0) {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No resource limits configured",
+		}
+	}
+	if err := config.ValidateResourceLimits(settings.Limits); err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: err.Error(),
+			FixHint: "Fix \"limits\" in settings/config.json",
+		}
+	}
+	if err := config.ResourceLimitsSupported(); err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Resource limits are configured but not enforced",
+			Details: []string{err.Error()},
+			FixHint: "Agents run unlimited; enable a systemd user session (loginctl enable-linger) to enforce limits",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: "Resource limits are enforced with systemd scopes",
+	}
+}
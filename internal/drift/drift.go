@@ -0,0 +1,317 @@
+// Package drift detects changes made to an agent's worktree outside the
+// agent's sessions: a human editing files, another tool, a stray commit.
+// When a session ends the worktree is snapshotted; when the next session
+// primes, the worktree is compared against the snapshot and the agent is
+// told what changed under it, so it does not clobber the work unknowingly.
+package drift
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Filename is the drift state file within the worktree's .runtime/.
+const Filename = "drift.json"
+
+// maxCommits caps the commits listed in a report.
+const maxCommits = 20
+
+// FileState is one uncommitted file in a snapshot.
+type FileState struct {
+	// Status is the two-letter git status code (e.g. " M", "??").
+	Status string `json:"status"`
+
+	// Hash is the SHA-256 of the file's contents, empty if deleted.
+	Hash string `json:"hash,omitempty"`
+}
+
+// Snapshot is the state of a worktree at one point in time: its commit and
+// every uncommitted file.
+type Snapshot struct {
+	Head    string               `json:"head"`
+	Files   map[string]FileState `json:"files,omitempty"`
+	TakenAt time.Time            `json:"taken_at"`
+}
+
+// ChangeKind says how a file changed between two snapshots.
+type ChangeKind string
+
+const (
+	// ChangeAdded is a file that was clean and now has uncommitted changes.
+	ChangeAdded ChangeKind = "added"
+
+	// ChangeModified is an uncommitted file whose contents changed.
+	ChangeModified ChangeKind = "modified"
+
+	// ChangeReverted is an uncommitted file that is now clean again, its
+	// changes discarded or committed.
+	ChangeReverted ChangeKind = "reverted"
+)
+
+// Change is one file changed outside the agent's session.
+type Change struct {
+	Path string     `json:"path"`
+	Kind ChangeKind `json:"kind"`
+}
+
+// Report lists what changed in a worktree between the end of one session
+// and the start of the next.
+type Report struct {
+	Since      time.Time `json:"since"`
+	DetectedAt time.Time `json:"detected_at"`
+	OldHead    string    `json:"old_head"`
+	NewHead    string    `json:"new_head"`
+	Commits    []string  `json:"commits,omitempty"` // "<sha> <subject>", newest first
+	Changes    []Change  `json:"changes,omitempty"`
+}
+
+// Empty reports whether nothing changed.
+func (r *Report) Empty() bool {
+	return r == nil || (r.OldHead == r.NewHead && len(r.Changes) == 0)
+}
+
+// HeadMoved reports whether the worktree's commit changed.
+func (r *Report) HeadMoved() bool {
+	return r != nil && r.OldHead != r.NewHead
+}
+
+// Paths returns the changed files.
+func (r *Report) Paths() []string {
+	if r == nil {
+		return nil
+	}
+	paths := make([]string, len(r.Changes))
+	for i, c := range r.Changes {
+		paths[i] = c.Path
+	}
+	return paths
+}
+
+// State is what is kept between sessions in the drift file.
+type State struct {
+	// Baseline is the snapshot taken when the last session ended. It is
+	// consumed by the next Check.
+	Baseline *Snapshot `json:"baseline,omitempty"`
+
+	// Pending is the last non-empty report, kept for 'gt diff' until the
+	// current session ends.
+	Pending *Report `json:"pending,omitempty"`
+}
+
+// Path returns the drift state file path for a worktree.
+func Path(worktree string) string {
+	return filepath.Join(worktree, ".runtime", Filename)
+}
+
+// Take snapshots a worktree.
+func Take(worktree string) (*Snapshot, error) {
+	head, err := gitOutput(worktree, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("reading HEAD: %w", err)
+	}
+	status, err := gitOutput(worktree, "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, fmt.Errorf("reading status: %w", err)
+	}
+
+	snap := &Snapshot{
+		Head:    strings.TrimSpace(head),
+		Files:   make(map[string]FileState),
+		TakenAt: time.Now(),
+	}
+	entries := strings.Split(status, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		code, path := entry[:2], entry[3:]
+		if code[0] == 'R' || code[0] == 'C' {
+			i++ // the next entry is the rename source
+		}
+		if path == ".runtime" || strings.HasPrefix(path, ".runtime/") {
+			continue
+		}
+		snap.Files[path] = FileState{Status: code, Hash: hashFile(filepath.Join(worktree, path))}
+	}
+	return snap, nil
+}
+
+// Compare reports how cur differs from old. Commits are not filled in.
+func Compare(old, cur *Snapshot) *Report {
+	r := &Report{
+		Since:      old.TakenAt,
+		DetectedAt: cur.TakenAt,
+		OldHead:    old.Head,
+		NewHead:    cur.Head,
+	}
+	for path, now := range cur.Files {
+		was, ok := old.Files[path]
+		switch {
+		case !ok:
+			r.Changes = append(r.Changes, Change{Path: path, Kind: ChangeAdded})
+		case was != now:
+			r.Changes = append(r.Changes, Change{Path: path, Kind: ChangeModified})
+		}
+	}
+	for path := range old.Files {
+		if _, ok := cur.Files[path]; !ok {
+			r.Changes = append(r.Changes, Change{Path: path, Kind: ChangeReverted})
+		}
+	}
+	sort.Slice(r.Changes, func(i, j int) bool { return r.Changes[i].Path < r.Changes[j].Path })
+	return r
+}
+
+// Record snapshots the worktree as the baseline for the next session and
+// drops the pending report of the session that is ending.
+func Record(worktree string) error {
+	snap, err := Take(worktree)
+	if err != nil {
+		return err
+	}
+	return Save(worktree, &State{Baseline: snap})
+}
+
+// Check compares the worktree against the baseline recorded when the last
+// session ended, consuming the baseline. A non-empty report is kept as
+// pending for 'gt diff'. Returns nil if there is no baseline or nothing
+// changed.
+func Check(worktree string) (*Report, error) {
+	state, err := Load(worktree)
+	if err != nil || state.Baseline == nil {
+		return nil, err
+	}
+	r, err := compareWorktree(worktree, state.Baseline)
+	if err != nil {
+		return nil, err
+	}
+	state.Baseline = nil
+	if !r.Empty() {
+		state.Pending = r
+	}
+	if err := Save(worktree, state); err != nil {
+		return nil, err
+	}
+	if r.Empty() {
+		return nil, nil
+	}
+	return r, nil
+}
+
+// Peek returns what changed outside the agent's sessions without
+// consuming anything: changes since the baseline if the agent is between
+// sessions, otherwise the pending report from its last Check. Returns nil
+// if nothing changed.
+func Peek(worktree string) (*Report, error) {
+	state, err := Load(worktree)
+	if err != nil {
+		return nil, err
+	}
+	if state.Baseline != nil {
+		r, err := compareWorktree(worktree, state.Baseline)
+		if err != nil || r.Empty() {
+			return nil, err
+		}
+		return r, nil
+	}
+	return state.Pending, nil
+}
+
+// Load reads a worktree's drift state. A missing file is an empty state.
+func Load(worktree string) (*State, error) {
+	data, err := os.ReadFile(Path(worktree)) //nolint:gosec // G304: path is constructed from the worktree
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{}, nil
+		}
+		return nil, fmt.Errorf("reading drift state: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing drift state: %w", err)
+	}
+	return &state, nil
+}
+
+// Save writes a worktree's drift state.
+func Save(worktree string, state *State) error {
+	path := Path(worktree)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding drift state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing drift state: %w", err)
+	}
+	return nil
+}
+
+// compareWorktree compares the worktree as it is now against baseline,
+// listing commits made since.
+func compareWorktree(worktree string, baseline *Snapshot) (*Report, error) {
+	cur, err := Take(worktree)
+	if err != nil {
+		return nil, err
+	}
+	r := Compare(baseline, cur)
+	if r.HeadMoved() {
+		out, err := gitOutput(worktree, "log", "--format=%h %s", fmt.Sprintf("-%d", maxCommits), r.OldHead+".."+r.NewHead)
+		if err == nil {
+			for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+				if line != "" {
+					r.Commits = append(r.Commits, line)
+				}
+			}
+		}
+	}
+	return r, nil
+}
+
+// hashFile returns the SHA-256 of a file's contents (a symlink's target),
+// or "" if it does not exist.
+func hashFile(path string) string {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return ""
+	}
+	var data []byte
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return ""
+		}
+		data = []byte(target)
+	} else if data, err = os.ReadFile(path); err != nil { //nolint:gosec // G304: path is within the worktree
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,110 @@
+package drift
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func initRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test"},
+	} {
+		git(t, dir, args...)
+	}
+	writeFile(t, dir, "a.go", "package a\n")
+	writeFile(t, dir, "b.go", "package b\n")
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-qm", "initial")
+	return dir
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckReportsChangesSinceRecord(t *testing.T) {
+	dir := initRepo(t)
+	writeFile(t, dir, "a.go", "package a // agent edit\n")
+	if err := Record(dir); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	// Between sessions: a human edits the agent's file, adds one, and
+	// commits another change.
+	writeFile(t, dir, "a.go", "package a // human edit\n")
+	writeFile(t, dir, "notes.txt", "todo\n")
+	writeFile(t, dir, "b.go", "package b // fixed\n")
+	git(t, dir, "commit", "-qm", "hotfix", "b.go")
+
+	peek, err := Peek(dir)
+	if err != nil || peek.Empty() {
+		t.Fatalf("Peek between sessions = %+v, %v", peek, err)
+	}
+
+	r, err := Check(dir)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	want := map[string]ChangeKind{"a.go": ChangeModified, "notes.txt": ChangeAdded}
+	if len(r.Changes) != len(want) {
+		t.Fatalf("changes = %+v, want %v", r.Changes, want)
+	}
+	for _, c := range r.Changes {
+		if want[c.Path] != c.Kind {
+			t.Errorf("%s: %s, want %s", c.Path, c.Kind, want[c.Path])
+		}
+	}
+	if !r.HeadMoved() || len(r.Commits) != 1 {
+		t.Errorf("head moved = %v, commits = %v", r.HeadMoved(), r.Commits)
+	}
+
+	// The baseline is consumed; the report stays pending for gt diff.
+	if again, err := Check(dir); err != nil || again != nil {
+		t.Errorf("second Check = %+v, %v", again, err)
+	}
+	if pending, err := Peek(dir); err != nil || len(pending.Changes) != 2 {
+		t.Errorf("Peek after Check = %+v, %v", pending, err)
+	}
+}
+
+func TestCheckRevertedAndUnchanged(t *testing.T) {
+	dir := initRepo(t)
+	if r, err := Check(dir); err != nil || r != nil {
+		t.Fatalf("Check without baseline = %+v, %v", r, err)
+	}
+
+	writeFile(t, dir, "a.go", "package a // agent edit\n")
+	if err := Record(dir); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := Check(dir); err != nil || r != nil {
+		t.Errorf("Check with nothing changed = %+v, %v", r, err)
+	}
+
+	if err := Record(dir); err != nil {
+		t.Fatal(err)
+	}
+	git(t, dir, "checkout", "--", "a.go")
+	r, err := Check(dir)
+	if err != nil || r == nil || len(r.Changes) != 1 || r.Changes[0].Kind != ChangeReverted {
+		t.Errorf("Check after discard = %+v, %v", r, err)
+	}
+}
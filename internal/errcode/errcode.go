@@ -0,0 +1,403 @@
+// Package errcode defines the stable error codes gt reports with its
+// errors, so scripts can branch on a failure without matching message
+// text. Each code has a name, a one-line remediation hint printed with
+// the error, and a longer explanation shown by 'gt errors <code>'.
+//
+// Codes are grouped by area: 1xxx workspace and configuration, 2xxx agents
+// and sessions, 3xxx git, 4xxx beads and mail, 5xxx services, 6xxx how the
+// command was run. A code, once published, keeps its meaning; retired codes
+// are not reused.
+//
+// Each code also belongs to an exit class, the process exit status gt
+// reports it with (see ExitStatus), so wrappers can tell what went wrong
+// from the status alone.
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Code is a stable error code such as "GT1001".
+type Code string
+
+// Error codes.
+const (
+	WorkspaceNotFound Code = "GT1001"
+	ConfigNotFound    Code = "GT1002"
+	ConfigInvalid     Code = "GT1003"
+	RigNotFound       Code = "GT1004"
+	RigExists         Code = "GT1005"
+	TownBusy          Code = "GT1006"
+	LogChainBroken    Code = "GT1007"
+
+	TmuxNotRunning      Code = "GT2001"
+	SessionExists       Code = "GT2002"
+	SessionDead         Code = "GT2003"
+	PolecatNotFound     Code = "GT2004"
+	CrewNotFound        Code = "GT2005"
+	UncommittedChanges  Code = "GT2006"
+	WorkerLocked        Code = "GT2007"
+	DaemonNotRunning    Code = "GT2008"
+	AgentAlreadyRunning Code = "GT2009"
+
+	NotAGitRepo   Code = "GT3001"
+	MergeConflict Code = "GT3002"
+	GitAuthFailed Code = "GT3003"
+
+	BdNotInstalled   Code = "GT4001"
+	BeadsNotFound    Code = "GT4002"
+	IssueNotFound    Code = "GT4003"
+	MessageNotFound  Code = "GT4004"
+	UnknownMailGroup Code = "GT4005"
+
+	RefineryNotRunning Code = "GT5001"
+	WitnessNotRunning  Code = "GT5002"
+
+	BadUsage       Code = "GT6001"
+	PartialFailure Code = "GT6002"
+)
+
+// Exit statuses. Every gt command exits with one of these; the statuses
+// from ExitCommandStatus up are defined by commands that report a result
+// through their status, such as 'gt deacon health-check'.
+const (
+	ExitOK            = 0  // success
+	ExitFailure       = 1  // an error without a more specific class
+	ExitUsage         = 2  // bad command line: unknown command or flag, wrong arguments
+	ExitNoTown        = 3  // not inside a Gas Town workspace
+	ExitNotFound      = 4  // the rig, agent, session, issue, or message does not exist
+	ExitPartial       = 5  // some of several targets failed; the output says which
+	ExitConflict      = 6  // the current state forbids it: already running, locked, uncommitted work, conflicts
+	ExitUnavailable   = 7  // a tool or service gt needs is not installed or not running
+	ExitCommandStatus = 10 // first status reserved for command-specific results
+)
+
+// exitClasses maps codes to their exit status; codes not listed exit with
+// ExitFailure.
+var exitClasses = map[Code]int{
+	WorkspaceNotFound:   ExitNoTown,
+	RigNotFound:         ExitNotFound,
+	PolecatNotFound:     ExitNotFound,
+	CrewNotFound:        ExitNotFound,
+	SessionDead:         ExitNotFound,
+	IssueNotFound:       ExitNotFound,
+	MessageNotFound:     ExitNotFound,
+	UnknownMailGroup:    ExitNotFound,
+	RigExists:           ExitConflict,
+	TownBusy:            ExitConflict,
+	SessionExists:       ExitConflict,
+	UncommittedChanges:  ExitConflict,
+	WorkerLocked:        ExitConflict,
+	AgentAlreadyRunning: ExitConflict,
+	MergeConflict:       ExitConflict,
+	TmuxNotRunning:      ExitUnavailable,
+	DaemonNotRunning:    ExitUnavailable,
+	BdNotInstalled:      ExitUnavailable,
+	RefineryNotRunning:  ExitUnavailable,
+	WitnessNotRunning:   ExitUnavailable,
+	BadUsage:            ExitUsage,
+	PartialFailure:      ExitPartial,
+}
+
+// ExitStatus returns the exit status for an error with code c: ExitFailure
+// for codes without a class, and for "".
+func ExitStatus(c Code) int {
+	if status, ok := exitClasses[c]; ok {
+		return status
+	}
+	return ExitFailure
+}
+
+// Info documents one code.
+type Info struct {
+	Code    Code   `json:"code"`
+	Name    string `json:"name"`    // kebab-case, e.g. "workspace-not-found"
+	Summary string `json:"summary"` // what went wrong, one line
+	Hint    string `json:"hint"`    // what to do about it, one line
+	Details string `json:"details"` // longer explanation for 'gt errors <code>'
+	Exit    int    `json:"exit"`    // exit status, see ExitStatus
+}
+
+var catalog = map[Code]Info{
+	WorkspaceNotFound: {
+		Name:    "workspace-not-found",
+		Summary: "The command must run inside a Gas Town workspace",
+		Hint:    "cd into your town (e.g. ~/gt), or create one with 'gt install'",
+		Details: `gt looks for the town root (the directory with mayor/town.json) in the
+current directory and its parents. Commands that act on agents, rigs, or
+the town log need one. Run them from anywhere inside the town, or create a
+town first with 'gt install <path>'.`,
+	},
+	ConfigNotFound: {
+		Name:    "config-not-found",
+		Summary: "A configuration file is missing",
+		Hint:    "check the path in the message; 'gt doctor' reports missing files",
+		Details: `A town, rig, or agent configuration file the command needs does not exist.
+The message names the file. 'gt doctor' checks the town's layout and can
+recreate many files with --fix.`,
+	},
+	ConfigInvalid: {
+		Name:    "config-invalid",
+		Summary: "A configuration file cannot be used",
+		Hint:    "fix the file named in the message; 'gt doctor' reports config problems",
+		Details: `A configuration file has the wrong type, an unsupported schema version,
+or lacks a required field. Files written by a newer gt need that version;
+otherwise correct the field the message names.`,
+	},
+	RigNotFound: {
+		Name:    "rig-not-found",
+		Summary: "No rig has that name",
+		Hint:    "list rigs with 'gt rig list'",
+		Details: `The rig named on the command line is not registered in this town. Rig
+names are the directories registered with 'gt rig add'; 'gt rig list'
+shows them.`,
+	},
+	RigExists: {
+		Name:    "rig-exists",
+		Summary: "A rig with that name already exists",
+		Hint:    "pick another name, or see the existing rig with 'gt rig list'",
+		Details: `'gt rig add' refuses to replace an existing rig. Choose a different name,
+or remove the old rig first.`,
+	},
+	TownBusy: {
+		Name:    "town-busy",
+		Summary: "Other gt operations hold the town's operation lock",
+		Hint:    "see what is running with 'gt locks', or retry with --wait",
+		Details: `Mutating commands such as gt up, gt sling, and gt rig add take one of the
+town's operation slots while they run. Every slot stayed taken for as
+long as the command waited (operations.wait_timeout, 5m by default, or
+--wait), or --no-wait was given. 'gt locks' shows the holders; slots
+free up when they exit.`,
+	},
+	LogChainBroken: {
+		Name:    "log-chain-broken",
+		Summary: "The town log's hash chain does not verify",
+		Hint:    "the events listed by 'gt log verify' were edited, removed, or inserted outside gt",
+		Details: `In a town with log_chain set, every event records the hash of the event
+before it. 'gt log verify' found a link that does not match: an event was
+modified or removed, an event was inserted, or events were cut off the end
+of the log. Compare the log with a backup or with a chain head recorded
+earlier (--head). A town.log checked in a different time zone from the one
+it was written in also fails, since its times are local.`,
+	},
+	TmuxNotRunning: {
+		Name:    "tmux-not-running",
+		Summary: "No tmux server is running",
+		Hint:    "bring the town up with 'gt up', which starts tmux too",
+		Details: `Agent sessions live in tmux, and no tmux server is running, so there are
+no sessions to act on. Starting any agent starts the server.`,
+	},
+	SessionExists: {
+		Name:    "session-exists",
+		Summary: "The agent's session is already running",
+		Hint:    "attach to it, or stop it first (see 'gt status')",
+		Details: `The command would start a session that is already running. Attach to the
+running session instead, or stop the agent and start it again.`,
+	},
+	SessionDead: {
+		Name:    "session-dead",
+		Summary: "The agent's tmux session is not running",
+		Hint:    "check 'gt status', then start the agent (e.g. 'gt crew start <name>')",
+		Details: `The command needs the agent's live session (to nudge it, hand off, or
+capture its output), but the session does not exist: the agent was never
+started, or it exited or crashed. 'gt log --agent <agent>' shows how its
+last session ended.`,
+	},
+	PolecatNotFound: {
+		Name:    "polecat-not-found",
+		Summary: "No polecat has that name",
+		Hint:    "list polecats with 'gt polecat list'",
+		Details: `The polecat named on the command line does not exist in the rig. Polecat
+addresses are <rig>/<name>.`,
+	},
+	CrewNotFound: {
+		Name:    "crew-not-found",
+		Summary: "No crew member has that name",
+		Hint:    "list crew members with 'gt crew list'",
+		Details: `The crew member named on the command line does not exist in the rig. Crew
+addresses are <rig>/crew/<name>.`,
+	},
+	UncommittedChanges: {
+		Name:    "uncommitted-changes",
+		Summary: "The worker has uncommitted changes",
+		Hint:    "commit or stash the changes, or pass --force to discard them",
+		Details: `The command would remove or reset a worktree that has uncommitted work.
+gt refuses so the work is not lost. Commit or stash it in the worktree,
+or pass --force if the changes can go.`,
+	},
+	WorkerLocked: {
+		Name:    "worker-locked",
+		Summary: "Another agent holds the worker's lock",
+		Hint:    "wait for the other agent to finish, or check who holds it with 'gt status'",
+		Details: `A worker is locked while an agent uses it, so two agents do not work in
+the same worktree. The lock is released when the holder exits.`,
+	},
+	DaemonNotRunning: {
+		Name:    "daemon-not-running",
+		Summary: "The gt daemon is not running",
+		Hint:    "start it with 'gt daemon start'",
+		Details: `The command talks to the background daemon, which is not running. Start it
+with 'gt daemon start'; 'gt daemon status' shows its state.`,
+	},
+	AgentAlreadyRunning: {
+		Name:    "agent-already-running",
+		Summary: "The service is already running",
+		Hint:    "nothing to do; check it with 'gt status'",
+		Details: `The command would start a rig service (witness or refinery) that is
+already running.`,
+	},
+	NotAGitRepo: {
+		Name:    "not-a-git-repo",
+		Summary: "The directory is not a git repository",
+		Hint:    "run from inside the rig's clone or worktree",
+		Details: `The command needs a git repository and the directory it ran in is not in
+one. Rig clones and agent worktrees are git repositories.`,
+	},
+	MergeConflict: {
+		Name:    "merge-conflict",
+		Summary: "A merge or rebase hit conflicts",
+		Hint:    "resolve the conflicts in the worktree, then retry",
+		Details: `Git could not combine the branches automatically. Resolve the conflicted
+files in the worktree (git status lists them), commit, and retry.`,
+	},
+	GitAuthFailed: {
+		Name:    "git-auth-failed",
+		Summary: "The git remote rejected the credentials",
+		Hint:    "check your SSH key or token for the remote",
+		Details: `Fetching from or pushing to the remote failed authentication. Check that
+the SSH agent has the key, or that the token in your credential helper is
+valid for the repository.`,
+	},
+	BdNotInstalled: {
+		Name:    "bd-not-installed",
+		Summary: "The bd (beads) command is not installed",
+		Hint:    "install it with 'pip install beads-cli'",
+		Details: `Issues, mail, and molecules are stored with beads, driven through the bd
+command, which is not on PATH.`,
+	},
+	BeadsNotFound: {
+		Name:    "beads-not-found",
+		Summary: "No beads database was found",
+		Hint:    "run from inside the town or a rig, or initialize beads with 'bd init'",
+		Details: `The command needs a .beads directory in the current directory or its
+parents and found none.`,
+	},
+	IssueNotFound: {
+		Name:    "issue-not-found",
+		Summary: "No issue has that ID",
+		Hint:    "check the ID and its prefix; 'bd list' shows issues",
+		Details: `Issue IDs start with their rig's prefix (e.g. gt-abc12). An ID with an
+unknown prefix, or one that was deleted, is not found.`,
+	},
+	MessageNotFound: {
+		Name:    "message-not-found",
+		Summary: "No mail message has that ID",
+		Hint:    "list messages with 'gt mail inbox'",
+		Details: `The message ID does not exist in the mailbox, or it was already deleted.`,
+	},
+	UnknownMailGroup: {
+		Name:    "unknown-mail-group",
+		Summary: "The mailing list, queue, or announce channel does not exist",
+		Hint:    "check the address against the town's messaging config",
+		Details: `Mail addressed to a list, queue, or announce channel needs that group to
+be defined in the town's messaging configuration.`,
+	},
+	RefineryNotRunning: {
+		Name:    "refinery-not-running",
+		Summary: "The rig's refinery is not running",
+		Hint:    "start it with 'gt refinery start <rig>'",
+		Details: `The refinery processes the rig's merge queue. Commands that talk to it
+need it running.`,
+	},
+	WitnessNotRunning: {
+		Name:    "witness-not-running",
+		Summary: "The rig's witness is not running",
+		Hint:    "start it with 'gt witness start <rig>'",
+		Details: `The witness supervises the rig's polecats. Commands that talk to it need
+it running.`,
+	},
+	BadUsage: {
+		Name:    "bad-usage",
+		Summary: "The command line is not valid",
+		Hint:    "check the command's usage with --help",
+		Details: `The command, a flag, or the number of arguments is wrong: an unknown
+command or flag, a missing required flag, or too many or too few
+arguments. Nothing was done.`,
+	},
+	PartialFailure: {
+		Name:    "partial-failure",
+		Summary: "Some of the command's targets failed",
+		Hint:    "the output lists which targets failed and why; retry those",
+		Details: `A command acting on several targets (agents, rigs, messages, issues)
+ran against all of them, but one or more failed. The rest succeeded. The
+output reports each target's result; fix the failures and run the
+command again for those targets.`,
+	},
+}
+
+// Lookup returns the documentation of a code, given as "GT1001", "1001",
+// or a name such as "workspace-not-found".
+func Lookup(s string) (Info, bool) {
+	s = strings.TrimSpace(s)
+	code := Code(strings.ToUpper(s))
+	if !strings.HasPrefix(string(code), "GT") {
+		code = "GT" + code
+	}
+	if info, ok := catalog[code]; ok {
+		info.Code, info.Exit = code, ExitStatus(code)
+		return info, true
+	}
+	for c, info := range catalog {
+		if info.Name == strings.ToLower(s) {
+			info.Code, info.Exit = c, ExitStatus(c)
+			return info, true
+		}
+	}
+	return Info{}, false
+}
+
+// All returns every code's documentation, ordered by code.
+func All() []Info {
+	infos := make([]Info, 0, len(catalog))
+	for c, info := range catalog {
+		info.Code, info.Exit = c, ExitStatus(c)
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Code < infos[j].Code })
+	return infos
+}
+
+// Error is an error with a code.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+func (e *Error) Unwrap() error { return e.Err }
+
+// New returns an error with a code and a fmt.Errorf message.
+func New(code Code, format string, args ...interface{}) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// Wrap gives err a code. It returns nil for a nil err.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Of returns the code carried by err or any error it wraps, or "" if it
+// has none.
+func Of(err error) Code {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	codeRe := regexp.MustCompile(`^GT[1-6]\d{3}$`)
+	names := map[string]Code{}
+	for _, info := range All() {
+		if !codeRe.MatchString(string(info.Code)) {
+			t.Errorf("%s: code should be GT followed by 1xxx-6xxx", info.Code)
+		}
+		if info.Name == "" || info.Summary == "" || info.Hint == "" || info.Details == "" {
+			t.Errorf("%s: name, summary, hint, and details are all required: %+v", info.Code, info)
+		}
+		if other, dup := names[info.Name]; dup {
+			t.Errorf("%s and %s share the name %q", other, info.Code, info.Name)
+		}
+		names[info.Name] = info.Code
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, q := range []string{"GT2003", "gt2003", "2003", "session-dead", " Session-Dead "} {
+		info, ok := Lookup(q)
+		if !ok || info.Code != SessionDead || info.Name != "session-dead" {
+			t.Errorf("Lookup(%q) = %+v, %v", q, info, ok)
+		}
+	}
+	if _, ok := Lookup("GT9999"); ok {
+		t.Error("Lookup(GT9999) should fail")
+	}
+}
+
+func TestOf(t *testing.T) {
+	err := fmt.Errorf("nudging: %w", New(SessionDead, "session %q not found", "gt-max"))
+	if got := Of(err); got != SessionDead {
+		t.Errorf("Of(wrapped) = %q", got)
+	}
+	if err.Error() != `nudging: session "gt-max" not found` {
+		t.Errorf("message = %q", err.Error())
+	}
+	base := errors.New("boom")
+	if !errors.Is(Wrap(ConfigInvalid, base), base) {
+		t.Error("Wrap should keep the wrapped error")
+	}
+	if Of(base) != "" || Wrap(ConfigInvalid, nil) != nil {
+		t.Error("uncoded errors have no code, and Wrap(nil) is nil")
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	tests := []struct {
+		code Code
+		want int
+	}{
+		{"", ExitFailure},
+		{ConfigInvalid, ExitFailure},
+		{WorkspaceNotFound, ExitNoTown},
+		{PolecatNotFound, ExitNotFound},
+		{SessionDead, ExitNotFound},
+		{WorkerLocked, ExitConflict},
+		{TmuxNotRunning, ExitUnavailable},
+		{BadUsage, ExitUsage},
+		{PartialFailure, ExitPartial},
+	}
+	for _, tt := range tests {
+		if got := ExitStatus(tt.code); got != tt.want {
+			t.Errorf("ExitStatus(%q) = %d, want %d", tt.code, got, tt.want)
+		}
+	}
+	for c := range exitClasses {
+		if _, ok := catalog[c]; !ok {
+			t.Errorf("exit class for undocumented code %s", c)
+		}
+	}
+	if info, _ := Lookup("bad-usage"); info.Exit != ExitUsage {
+		t.Errorf("Lookup(bad-usage).Exit = %d", info.Exit)
+	}
+}
@@ -5,6 +5,7 @@
 package events
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -45,10 +46,12 @@ const (
 	TypeNudge   = "nudge"
 	TypeBoot    = "boot"
 	TypeHalt    = "halt"
+	TypeMark    = "mark"
 
 	// Session events (for seance discovery)
 	TypeSessionStart = "session_start"
 	TypeSessionEnd   = "session_end"
+	TypeSessionTag   = "session_tag"
 
 	// Witness patrol events
 	TypePatrolStarted   = "patrol_started"
@@ -130,6 +133,37 @@ func write(event Event) error {
 	return nil
 }
 
+// Read returns every event in a town's events log, oldest first.
+// Malformed lines are skipped; a missing log yields no events.
+func Read(townRoot string) ([]Event, error) {
+	f, err := os.Open(filepath.Join(townRoot, EventsFile)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening events file: %w", err)
+	}
+	defer f.Close()
+
+	var all []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		all = append(all, e)
+	}
+	return all, scanner.Err()
+}
+
+// Time parses the event's timestamp, returning the zero time if invalid.
+func (e Event) Time() time.Time {
+	t, _ := time.Parse(time.RFC3339, e.Timestamp)
+	return t
+}
+
 // Payload helpers for common event structures.
 
 // SlingPayload creates a payload for sling events.
@@ -274,6 +308,32 @@ func HaltPayload(services []string) map[string]interface{} {
 	}
 }
 
+// MarkPayload creates a payload for mark events.
+func MarkPayload(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": name,
+	}
+}
+
+// SessionTagPayload creates a payload for session tag events.
+// sessionID may be empty when the tagged agent's session is unknown.
+func SessionTagPayload(sessionID, agent, issue, tag, note string) map[string]interface{} {
+	p := map[string]interface{}{
+		"tag":   tag,
+		"agent": agent,
+	}
+	if sessionID != "" {
+		p["session_id"] = sessionID
+	}
+	if issue != "" {
+		p["issue"] = issue
+	}
+	if note != "" {
+		p["note"] = note
+	}
+	return p
+}
+
 // SessionPayload creates a payload for session start/end events.
 // sessionID: Claude Code session UUID
 // role: Gas Town role (e.g., "gastown/crew/joe", "deacon")
@@ -0,0 +1,273 @@
+// Package fairness shares the town's polecat capacity between rigs, so one
+// busy rig cannot take every slot or the whole activity budget.
+//
+// Each rig has a weight (default 1). With a town-wide polecat limit, a
+// rig is guaranteed its weighted share of the slots. Slots guaranteed to
+// rigs that have been idle for the whole window may be borrowed; slots
+// owed to a rig that is working are not. A rig may also have a quota: the polecat time it may
+// use per window (a rolling 24 hours by default), measured from the town
+// log's spawn, wake, done, crash, and kill events.
+package fairness
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+// DefaultWindow is the period quotas are measured over.
+const DefaultWindow = 24 * time.Hour
+
+// Policy is a town's fairness configuration, parsed.
+type Policy struct {
+	MaxPolecats int                      // town-wide running polecat limit; 0 for none
+	Window      time.Duration            // period quotas are measured over
+	Weights     map[string]float64       // per-rig weight; missing rigs weigh 1
+	Quotas      map[string]time.Duration // per-rig polecat time per window
+}
+
+// FromConfig parses cfg. It returns nil, nil if cfg is nil.
+func FromConfig(cfg *config.FairnessConfig) (*Policy, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	p := &Policy{
+		MaxPolecats: cfg.MaxPolecats,
+		Window:      DefaultWindow,
+		Weights:     make(map[string]float64),
+		Quotas:      make(map[string]time.Duration),
+	}
+	if cfg.Window != "" {
+		d, err := time.ParseDuration(cfg.Window)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("fairness: invalid window %q", cfg.Window)
+		}
+		p.Window = d
+	}
+	for name, rc := range cfg.Rigs {
+		if rc == nil {
+			continue
+		}
+		if rc.Weight < 0 {
+			return nil, fmt.Errorf("fairness: rig %s has a negative weight", name)
+		}
+		if rc.Weight > 0 {
+			p.Weights[name] = rc.Weight
+		}
+		if rc.Quota != "" {
+			d, err := time.ParseDuration(rc.Quota)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("fairness: rig %s has an invalid quota %q", name, rc.Quota)
+			}
+			p.Quotas[name] = d
+		}
+	}
+	return p, nil
+}
+
+// Weight returns the rig's weight.
+func (p *Policy) Weight(rig string) float64 {
+	if w, ok := p.Weights[rig]; ok {
+		return w
+	}
+	return 1
+}
+
+// Targets returns each rig's weighted fraction of the town's capacity.
+func (p *Policy) Targets(rigs []string) map[string]float64 {
+	var total float64
+	for _, r := range rigs {
+		total += p.Weight(r)
+	}
+	targets := make(map[string]float64, len(rigs))
+	for _, r := range rigs {
+		if total > 0 {
+			targets[r] = p.Weight(r) / total
+		}
+	}
+	return targets
+}
+
+// Guaranteed returns the polecat slots each rig is guaranteed: its
+// weighted share of MaxPolecats, rounded down.
+func (p *Policy) Guaranteed(rigs []string) map[string]int {
+	guaranteed := make(map[string]int, len(rigs))
+	if p.MaxPolecats <= 0 {
+		return guaranteed
+	}
+	for r, share := range p.Targets(rigs) {
+		guaranteed[r] = int(math.Floor(share*float64(p.MaxPolecats) + 1e-9))
+	}
+	return guaranteed
+}
+
+// Admit reports why another polecat may not be spawned into rig, or nil if
+// it may. rigs lists every rig in the town, running holds the polecats
+// each is running, and used the polecat time each has used in the
+// current window.
+func (p *Policy) Admit(rig string, rigs []string, running map[string]int, used map[string]time.Duration) error {
+	if quota, ok := p.Quotas[rig]; ok && used[rig] >= quota {
+		return fmt.Errorf("rig used %s of polecat time in the last %s (quota %s)",
+			FormatHours(used[rig]), FormatHours(p.Window), FormatHours(quota))
+	}
+	if p.MaxPolecats <= 0 {
+		return nil
+	}
+	if !slices.Contains(rigs, rig) {
+		rigs = append(rigs, rig)
+	}
+
+	total := 0
+	for _, r := range rigs {
+		total += running[r]
+	}
+	if total >= p.MaxPolecats {
+		return fmt.Errorf("town has %d polecats running (fairness max_polecats: %d)", total, p.MaxPolecats)
+	}
+	guaranteed := p.Guaranteed(rigs)
+	if running[rig] < guaranteed[rig] {
+		return nil
+	}
+
+	// Past its guarantee, the rig borrows: only slots no one else is owed.
+	// Rigs that ran no polecats in the window are idle and are owed none.
+	owed := 0
+	for _, r := range rigs {
+		if r != rig && running[r] < guaranteed[r] && (running[r] > 0 || used[r] > 0) {
+			owed += guaranteed[r] - running[r]
+		}
+	}
+	if free := p.MaxPolecats - total; free <= owed {
+		return fmt.Errorf("rig is at its share (%d of %d slots); the %d free slot(s) are reserved for other rigs",
+			running[rig], p.MaxPolecats, free)
+	}
+	return nil
+}
+
+// PolecatRig returns the rig of a polecat's town log agent, e.g. "gastown"
+// for "gastown/Toast" or "gastown/polecats/Toast". Other agents return
+// false.
+func PolecatRig(agent string) (string, bool) {
+	parts := strings.Split(townlog.AgentKey(agent), "/")
+	if len(parts) != 2 {
+		return "", false // town agents, crew
+	}
+	switch parts[1] {
+	case "witness", "refinery":
+		return "", false
+	}
+	return parts[0], true
+}
+
+// Usage returns the polecat time each rig used between from and to. A
+// polecat runs from a spawn or wake until its done, crash, or kill; one
+// still running at to counts until to.
+func Usage(events []townlog.Event, from, to time.Time) map[string]time.Duration {
+	used := make(map[string]time.Duration)
+	for _, s := range spans(events, to) {
+		start, end := s.start, s.end
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			used[s.rig] += end.Sub(start)
+		}
+	}
+	return used
+}
+
+// Period is one window of a fairness report.
+type Period struct {
+	Start time.Time                `json:"start"`
+	End   time.Time                `json:"end"`
+	Used  map[string]time.Duration `json:"-"`     // polecat time per rig
+	Hours map[string]float64       `json:"hours"` // Used, in hours
+	Share map[string]float64       `json:"share"` // fraction of the period's polecat time
+}
+
+// Report splits from..to into periods of length step and returns each
+// rig's polecat time and share of the total in every period.
+func Report(events []townlog.Event, from, to time.Time, step time.Duration) []Period {
+	var periods []Period
+	for start := from; start.Before(to); start = start.Add(step) {
+		end := start.Add(step)
+		if end.After(to) {
+			end = to
+		}
+		periods = append(periods, Period{Start: start, End: end, Used: Usage(events, start, end)})
+	}
+	for i := range periods {
+		periods[i].Share = Shares(periods[i].Used)
+		periods[i].Hours = make(map[string]float64, len(periods[i].Used))
+		for r, d := range periods[i].Used {
+			periods[i].Hours[r] = d.Hours()
+		}
+	}
+	return periods
+}
+
+// Shares returns each rig's fraction of the total time in used.
+func Shares(used map[string]time.Duration) map[string]float64 {
+	var total time.Duration
+	for _, d := range used {
+		total += d
+	}
+	shares := make(map[string]float64, len(used))
+	for r, d := range used {
+		if total > 0 {
+			shares[r] = float64(d) / float64(total)
+		}
+	}
+	return shares
+}
+
+// FormatHours renders a duration as hours to a tenth, e.g. "3.5h" or "24h".
+func FormatHours(d time.Duration) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", d.Hours()), ".0") + "h"
+}
+
+type span struct {
+	rig        string
+	start, end time.Time
+}
+
+// spans returns every polecat run in events, which are in time order.
+func spans(events []townlog.Event, to time.Time) []span {
+	var out []span
+	open := make(map[string]span)
+	for _, e := range events {
+		rig, ok := PolecatRig(e.Agent)
+		if !ok {
+			continue
+		}
+		agent := townlog.AgentKey(e.Agent)
+		s, running := open[agent]
+		switch e.Type {
+		case townlog.EventSpawn, townlog.EventWake:
+			if running {
+				out = append(out, span{rig: s.rig, start: s.start, end: e.Timestamp})
+			}
+			open[agent] = span{rig: rig, start: e.Timestamp}
+		case townlog.EventDone, townlog.EventCrash, townlog.EventKill:
+			if running {
+				out = append(out, span{rig: s.rig, start: s.start, end: e.Timestamp})
+				delete(open, agent)
+			}
+		}
+	}
+	for _, s := range open {
+		s.end = to
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].start.Before(out[j].start) })
+	return out
+}
@@ -0,0 +1,80 @@
+package fairness
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+func TestAdmit(t *testing.T) {
+	p, err := FromConfig(&config.FairnessConfig{
+		MaxPolecats: 6,
+		Rigs: map[string]*config.RigFairness{
+			"gastown": {Weight: 2},
+			"beads":   {Quota: "10h"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rigs := []string{"beads", "gastown"}
+	if g := p.Guaranteed(rigs); g["gastown"] != 4 || g["beads"] != 2 {
+		t.Fatalf("Guaranteed() = %v, want gastown 4, beads 2", g)
+	}
+
+	tests := []struct {
+		name    string
+		rig     string
+		running map[string]int
+		used    map[string]time.Duration
+		reason  string // "" if admitted
+	}{
+		{"within share", "gastown", map[string]int{"gastown": 3}, nil, ""},
+		{"town full", "beads", map[string]int{"gastown": 4, "beads": 2}, nil, "town has 6"},
+		{"borrows an idle rig's slots", "gastown", map[string]int{"gastown": 4}, nil, ""},
+		{"slots owed to a working rig", "gastown", map[string]int{"gastown": 4}, map[string]time.Duration{"beads": time.Hour}, "reserved for other rigs"},
+		{"borrowing past what is owed", "beads", map[string]int{"gastown": 1, "beads": 2}, nil, "reserved for other rigs"},
+		{"quota spent", "beads", nil, map[string]time.Duration{"beads": 10 * time.Hour}, "quota 10h"},
+	}
+	for _, tt := range tests {
+		err := p.Admit(tt.rig, rigs, tt.running, tt.used)
+		switch {
+		case tt.reason == "" && err != nil:
+			t.Errorf("%s: Admit() = %v, want admitted", tt.name, err)
+		case tt.reason != "" && (err == nil || !strings.Contains(err.Error(), tt.reason)):
+			t.Errorf("%s: Admit() = %v, want %q", tt.name, err, tt.reason)
+		}
+	}
+}
+
+func TestUsage(t *testing.T) {
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return base.Add(time.Duration(h) * time.Hour) }
+	events := []townlog.Event{
+		{Timestamp: at(0), Type: townlog.EventSpawn, Agent: "gastown/Toast"},
+		{Timestamp: at(1), Type: townlog.EventSpawn, Agent: "beads/polecats/Nux"},
+		{Timestamp: at(1), Type: townlog.EventSpawn, Agent: "gastown/crew/max"}, // crew is not counted
+		{Timestamp: at(3), Type: townlog.EventDone, Agent: "gastown/Toast"},
+		{Timestamp: at(4), Type: townlog.EventCrash, Agent: "beads/Nux"},
+		{Timestamp: at(5), Type: townlog.EventSpawn, Agent: "gastown/Toast"}, // still running
+	}
+
+	used := Usage(events, at(2), at(6))
+	if used["gastown"] != 2*time.Hour || used["beads"] != 2*time.Hour || len(used) != 2 {
+		t.Fatalf("Usage() = %v, want gastown 2h, beads 2h", used)
+	}
+
+	periods := Report(events, at(0), at(6), 3*time.Hour)
+	if len(periods) != 2 {
+		t.Fatalf("Report() returned %d periods, want 2", len(periods))
+	}
+	if s := periods[0].Share["gastown"]; s < 0.59 || s > 0.61 {
+		t.Errorf("gastown share of the first period = %v, want 0.6", s)
+	}
+	if s := periods[1].Share["gastown"]; s != 0.5 {
+		t.Errorf("gastown share of the second period = %v, want 0.5", s)
+	}
+}
@@ -0,0 +1,98 @@
+// Package focus stores the operator's focus: a rig or convoy that commands
+// default to while the operator works on it. Focus is town-wide state in
+// .runtime/focus.json, set with 'gt focus' and cleared with 'gt focus clear'.
+package focus
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/constants"
+)
+
+// BatchInterval is how long notifications from outside the focus are held
+// before being delivered as one digest.
+const BatchInterval = 30 * time.Minute
+
+// Focus is the pinned scope. Either or both of Rig and Convoy may be set.
+type Focus struct {
+	Rig    string    `json:"rig,omitempty"`
+	Convoy string    `json:"convoy,omitempty"`
+	Since  time.Time `json:"since"`
+}
+
+// Path returns the focus file for a town.
+func Path(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "focus.json")
+}
+
+// Load returns the town's focus, or nil if none is set.
+func Load(townRoot string) (*Focus, error) {
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading focus: %w", err)
+	}
+	var f Focus
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing focus: %w", err)
+	}
+	if f.Rig == "" && f.Convoy == "" {
+		return nil, nil
+	}
+	return &f, nil
+}
+
+// Current returns the town's focus, treating an unreadable file as none.
+// Commands use it to pick defaults, where a broken focus file should not
+// stop them working.
+func Current(townRoot string) *Focus {
+	f, _ := Load(townRoot)
+	return f
+}
+
+// Save sets the town's focus.
+func Save(townRoot string, f *Focus) error {
+	if f.Since.IsZero() {
+		f.Since = time.Now()
+	}
+	if err := os.MkdirAll(constants.TownRuntimePath(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling focus: %w", err)
+	}
+	return os.WriteFile(Path(townRoot), data, 0644)
+}
+
+// Clear removes the town's focus. Clearing when none is set is not an error.
+func Clear(townRoot string) error {
+	if err := os.Remove(Path(townRoot)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("clearing focus: %w", err)
+	}
+	return nil
+}
+
+// Excludes reports whether something in rig falls outside a rig focus.
+// Town-level items (empty rig) and a focus without a rig exclude nothing.
+func (f *Focus) Excludes(rig string) bool {
+	return f != nil && f.Rig != "" && rig != "" && rig != f.Rig
+}
+
+// String renders the focus as "rig gastown, convoy hq-cv-abc".
+func (f *Focus) String() string {
+	switch {
+	case f.Rig != "" && f.Convoy != "":
+		return fmt.Sprintf("rig %s, convoy %s", f.Rig, f.Convoy)
+	case f.Rig != "":
+		return "rig " + f.Rig
+	default:
+		return "convoy " + f.Convoy
+	}
+}
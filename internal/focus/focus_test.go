@@ -0,0 +1,53 @@
+package focus
+
+import "testing"
+
+func TestSaveLoadClear(t *testing.T) {
+	town := t.TempDir()
+	if f, err := Load(town); f != nil || err != nil {
+		t.Fatalf("Load(empty) = %v, %v", f, err)
+	}
+
+	if err := Save(town, &Focus{Rig: "gastown"}); err != nil {
+		t.Fatal(err)
+	}
+	f := Current(town)
+	if f == nil || f.Rig != "gastown" || f.Since.IsZero() {
+		t.Fatalf("Current = %+v", f)
+	}
+	if f.String() != "rig gastown" {
+		t.Errorf("String() = %q", f.String())
+	}
+
+	if err := Clear(town); err != nil {
+		t.Fatal(err)
+	}
+	if err := Clear(town); err != nil {
+		t.Errorf("second Clear = %v", err)
+	}
+	if Current(town) != nil {
+		t.Error("focus still set after Clear")
+	}
+}
+
+func TestExcludes(t *testing.T) {
+	var none *Focus
+	rig := &Focus{Rig: "gastown"}
+	convoy := &Focus{Convoy: "hq-cv-1"}
+	tests := []struct {
+		f    *Focus
+		rig  string
+		want bool
+	}{
+		{none, "beads", false},
+		{rig, "gastown", false},
+		{rig, "beads", true},
+		{rig, "", false},
+		{convoy, "beads", false},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Excludes(tt.rig); got != tt.want {
+			t.Errorf("%v.Excludes(%q) = %v, want %v", tt.f, tt.rig, got, tt.want)
+		}
+	}
+}
@@ -5,8 +5,13 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/gtlog"
 )
 
 // Common errors
@@ -62,7 +67,9 @@ func (g *Git) run(args ...string) (string, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	start := time.Now()
 	err := cmd.Run()
+	gtlog.Exec("git", args, g.workDir, start, err)
 	if err != nil {
 		return "", g.wrapError(err, stderr.String(), args)
 	}
@@ -140,6 +147,69 @@ func (g *Git) CloneBareWithReference(url, dest, reference string) error {
 	return nil
 }
 
+// CloneOptions trims a clone of a huge repository.
+type CloneOptions struct {
+	Filter    string // partial clone filter such as "blob:none": objects are fetched when first needed
+	Depth     int    // shallow clone with this many commits of history; 0 for full history
+	Reference string // local repository to borrow objects from, if it can
+}
+
+// cloneArgs returns the git clone arguments for opts.
+func (o CloneOptions) cloneArgs() []string {
+	var args []string
+	if o.Filter != "" {
+		args = append(args, "--filter="+o.Filter)
+	}
+	if o.Depth > 0 {
+		// --depth implies --single-branch; agents need every branch.
+		args = append(args, "--depth", strconv.Itoa(o.Depth), "--no-single-branch")
+	}
+	if o.Reference != "" {
+		args = append(args, "--reference-if-able", o.Reference)
+	}
+	return args
+}
+
+// CloneWithOptions clones a repository, trimmed as opts asks.
+func (g *Git) CloneWithOptions(url, dest string, opts CloneOptions) error {
+	return g.clone(append(append([]string{"clone"}, opts.cloneArgs()...), url, dest))
+}
+
+// CloneBareWithOptions clones a bare repository, trimmed as opts asks.
+func (g *Git) CloneBareWithOptions(url, dest string, opts CloneOptions) error {
+	return g.clone(append(append([]string{"clone", "--bare"}, opts.cloneArgs()...), url, dest))
+}
+
+func (g *Git) clone(args []string) error {
+	cmd := exec.Command("git", args...) //nolint:gosec // G204: arguments are built from rig config
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return g.wrapError(err, stderr.String(), args[:len(args)-1])
+	}
+	return nil
+}
+
+// IsShallow reports whether the repository has truncated history.
+func (g *Git) IsShallow() (bool, error) {
+	out, err := g.run("rev-parse", "--is-shallow-repository")
+	if err != nil {
+		return false, err
+	}
+	return out == "true", nil
+}
+
+// Deepen fetches more history of branch from remote into a shallow
+// repository: by more commits, or all of it if by is zero or less.
+func (g *Git) Deepen(remote, branch string, by int) error {
+	if by <= 0 {
+		_, err := g.run("fetch", "--unshallow", remote, branch)
+		return err
+	}
+	_, err := g.run("fetch", "--deepen="+strconv.Itoa(by), remote, branch)
+	return err
+}
+
 // Checkout checks out the given ref.
 func (g *Git) Checkout(ref string) error {
 	_, err := g.run("checkout", ref)
@@ -335,6 +405,13 @@ func (g *Git) Rebase(onto string) error {
 	return err
 }
 
+// RebaseOnto replays the commits of the current branch after upstream onto
+// newBase (git rebase --onto newBase upstream).
+func (g *Git) RebaseOnto(newBase, upstream string) error {
+	_, err := g.run("rebase", "--onto", newBase, upstream)
+	return err
+}
+
 // AbortMerge aborts a merge in progress.
 func (g *Git) AbortMerge() error {
 	_, err := g.run("merge", "--abort")
@@ -448,6 +525,22 @@ func (g *Git) CreateBranchFrom(name, ref string) error {
 	return err
 }
 
+// CanCreateBranch verifies that a branch could be created from HEAD:
+// HEAD must resolve and the ref store must be writable. It writes and
+// removes a scratch ref rather than a real branch.
+func (g *Git) CanCreateBranch() error {
+	head, err := g.Rev("HEAD")
+	if err != nil {
+		return fmt.Errorf("HEAD does not resolve to a commit: %w", err)
+	}
+	ref := fmt.Sprintf("refs/gt-preflight/%d", os.Getpid())
+	if _, err := g.run("update-ref", ref, head); err != nil {
+		return fmt.Errorf("cannot write refs: %w", err)
+	}
+	_, _ = g.run("update-ref", "-d", ref)
+	return nil
+}
+
 // BranchExists checks if a branch exists locally.
 func (g *Git) BranchExists(name string) (bool, error) {
 	_, err := g.run("show-ref", "--verify", "--quiet", "refs/heads/"+name)
@@ -515,6 +608,11 @@ func (g *Git) Rev(ref string) (string, error) {
 	return g.run("rev-parse", ref)
 }
 
+// MergeBase returns the best common ancestor of two refs.
+func (g *Git) MergeBase(a, b string) (string, error) {
+	return g.run("merge-base", a, b)
+}
+
 // IsAncestor checks if ancestor is an ancestor of descendant.
 func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	_, err := g.run("merge-base", "--is-ancestor", ancestor, descendant)
@@ -561,6 +659,34 @@ func (g *Git) WorktreeAddExistingForce(path, branch string) error {
 	return err
 }
 
+// WorktreeAddSparse creates a new worktree at the given path with a new
+// branch starting from startPoint (HEAD if empty), checking out only the
+// given directories and the files at the repository root. Other worktrees
+// of the repository keep their full checkout.
+func (g *Git) WorktreeAddSparse(path, branch, startPoint string, dirs []string) error {
+	args := []string{"worktree", "add", "--no-checkout", "-b", branch, path}
+	if startPoint != "" {
+		args = append(args, startPoint)
+	}
+	if _, err := g.run(args...); err != nil {
+		return err
+	}
+	wt := NewGit(path)
+	if err := wt.SparseCheckoutSet(dirs); err != nil {
+		return err
+	}
+	_, err := wt.run("checkout")
+	return err
+}
+
+// SparseCheckoutSet limits the working tree to the given directories (cone
+// mode) and the files at the repository root.
+func (g *Git) SparseCheckoutSet(dirs []string) error {
+	args := append([]string{"sparse-checkout", "set", "--cone"}, dirs...)
+	_, err := g.run(args...)
+	return err
+}
+
 // WorktreeRemove removes a worktree.
 func (g *Git) WorktreeRemove(path string, force bool) error {
 	args := []string{"worktree", "remove", path}
@@ -676,6 +802,18 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// ChangedFiles returns the files a branch changed since it forked from base.
+func (g *Git) ChangedFiles(base, branch string) ([]string, error) {
+	out, err := g.run("diff", "--name-only", base+"..."+branch)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
 // StashCount returns the number of stashes in the repository.
 func (g *Git) StashCount() (int, error) {
 	out, err := g.run("stash", "list")
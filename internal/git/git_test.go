@@ -234,6 +234,30 @@ func TestRev(t *testing.T) {
 	}
 }
 
+func TestCanCreateBranch(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	if err := g.CanCreateBranch(); err != nil {
+		t.Fatalf("CanCreateBranch: %v", err)
+	}
+	// The scratch ref must not be left behind
+	out, err := g.run("for-each-ref", "refs/gt-preflight/")
+	if err != nil || out != "" {
+		t.Errorf("scratch ref left behind: %q, %v", out, err)
+	}
+
+	empty := t.TempDir()
+	cmd := exec.Command("git", "init")
+	cmd.Dir = empty
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+	if err := NewGit(empty).CanCreateBranch(); err == nil {
+		t.Error("CanCreateBranch succeeded on a repo without commits")
+	}
+}
+
 func TestFetchBranch(t *testing.T) {
 	// Create a "remote" repo
 	remoteDir := t.TempDir()
@@ -318,6 +342,187 @@ func TestCheckConflicts_NoConflict(t *testing.T) {
 	}
 }
 
+func TestChangedFiles(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	mainBranch, _ := g.CurrentBranch()
+
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout feature: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "pkg"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pkg", "a.go"), []byte("package pkg\n"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := g.Add("pkg/a.go"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("add pkg"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	files, err := g.ChangedFiles(mainBranch, "feature")
+	if err != nil {
+		t.Fatalf("ChangedFiles: %v", err)
+	}
+	if len(files) != 1 || files[0] != "pkg/a.go" {
+		t.Errorf("ChangedFiles = %v, want [pkg/a.go]", files)
+	}
+
+	files, err = g.ChangedFiles(mainBranch, mainBranch)
+	if err != nil {
+		t.Fatalf("ChangedFiles: %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("ChangedFiles on same branch = %v, want none", files)
+	}
+}
+
+func TestRebaseOnto(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	commit := func(name string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		if err := g.Add(name); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		if err := g.Commit("add " + name); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		sha, _ := g.Rev("HEAD")
+		return sha
+	}
+	mainBranch, _ := g.CurrentBranch()
+
+	// child is stacked on parent; parent is then rewritten.
+	if err := g.CreateBranchFrom("parent", mainBranch); err != nil {
+		t.Fatalf("CreateBranchFrom: %v", err)
+	}
+	_ = g.Checkout("parent")
+	oldParent := commit("a.txt")
+	if err := g.CreateBranchFrom("child", "parent"); err != nil {
+		t.Fatalf("CreateBranchFrom: %v", err)
+	}
+	_ = g.Checkout("child")
+	commit("b.txt")
+	if mb, err := g.MergeBase("child", "parent"); err != nil || mb != oldParent {
+		t.Errorf("MergeBase = %q, %v; want the parent commit", mb, err)
+	}
+	_ = g.Checkout("parent")
+	if _, err := g.run("reset", "--hard", mainBranch); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	newParent := commit("a2.txt")
+
+	_ = g.Checkout("child")
+	if err := g.RebaseOnto("parent", oldParent); err != nil {
+		t.Fatalf("RebaseOnto: %v", err)
+	}
+	if ok, _ := g.IsAncestor(newParent, "child"); !ok {
+		t.Error("child should be on top of the rewritten parent")
+	}
+	if ok, _ := g.IsAncestor(oldParent, "child"); ok {
+		t.Error("child should no longer contain the old parent commit")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.txt")); err != nil {
+		t.Error("child's own commit should be kept")
+	}
+}
+
+func TestWorktreeAddSparse(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	for _, f := range []string{"services/api/main.go", "services/web/index.js", "libs/common/util.go"} {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(f+"\n"), 0644); err != nil {
+			t.Fatalf("write %s: %v", f, err)
+		}
+	}
+	if err := g.Add("."); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("monorepo layout"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	wt := filepath.Join(t.TempDir(), "api")
+	if err := g.WorktreeAddSparse(wt, "polecat/api", "", []string{"services/api", "libs/common"}); err != nil {
+		t.Fatalf("WorktreeAddSparse: %v", err)
+	}
+	for _, f := range []string{"README.md", "services/api/main.go", "libs/common/util.go"} {
+		if _, err := os.Stat(filepath.Join(wt, f)); err != nil {
+			t.Errorf("%s should be checked out: %v", f, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(wt, "services", "web")); !os.IsNotExist(err) {
+		t.Errorf("services/web should not be checked out (err=%v)", err)
+	}
+	wg := NewGit(wt)
+	if branch, _ := wg.CurrentBranch(); branch != "polecat/api" {
+		t.Errorf("branch = %q, want polecat/api", branch)
+	}
+	if dirty, err := wg.HasUncommittedChanges(); err != nil || dirty {
+		t.Errorf("sparse worktree should be clean: dirty=%v err=%v", dirty, err)
+	}
+
+	// The main checkout is unaffected
+	if _, err := os.Stat(filepath.Join(dir, "services", "web", "index.js")); err != nil {
+		t.Errorf("main checkout lost services/web: %v", err)
+	}
+}
+
+func TestShallowCloneAndDeepen(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		_ = g.Add(name)
+		if err := g.Commit("add " + name); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+	branch, _ := g.CurrentBranch()
+
+	// Local paths ignore --depth; file:// URLs honor it.
+	bare := filepath.Join(t.TempDir(), "repo.git")
+	if err := g.CloneBareWithOptions("file://"+dir, bare, CloneOptions{Depth: 1}); err != nil {
+		t.Fatalf("CloneBareWithOptions: %v", err)
+	}
+	bg := NewGitWithDir(bare, "")
+	if shallow, err := bg.IsShallow(); err != nil || !shallow {
+		t.Fatalf("depth-1 clone should be shallow: shallow=%v err=%v", shallow, err)
+	}
+	if n, _ := bg.run("rev-list", "--count", branch); n != "1" {
+		t.Errorf("depth-1 clone has %s commits", n)
+	}
+
+	if err := bg.Deepen("origin", branch, 1); err != nil {
+		t.Fatalf("Deepen: %v", err)
+	}
+	if n, _ := bg.run("rev-list", "--count", branch); n != "2" {
+		t.Errorf("after deepening by 1, %s commits", n)
+	}
+	if err := bg.Deepen("origin", branch, 0); err != nil {
+		t.Fatalf("Deepen (unshallow): %v", err)
+	}
+	if shallow, _ := bg.IsShallow(); shallow {
+		t.Error("unshallowed clone is still shallow")
+	}
+}
+
 func TestCheckConflicts_WithConflict(t *testing.T) {
 	dir := initTestRepo(t)
 	g := NewGit(dir)
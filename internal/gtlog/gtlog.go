@@ -0,0 +1,234 @@
+// Package gtlog provides gt's structured internal log.
+//
+// The internal log records what gt itself did — commands run, tmux/git/bd
+// invocations, daemon heartbeat steps — for debugging and bug reports. It is
+// separate from the town activity log (townlog), which records agent
+// lifecycle events for users.
+//
+// Init installs a slog handler as the process default, so other packages log
+// with the standard slog functions (slog.Debug, slog.Info, ...) and need not
+// import this package.
+package gtlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileName is the internal log's file name.
+const FileName = "gt-internal.log"
+
+// MaxSize is the size at which the internal log is rotated to FileName.1.
+const MaxSize = 10 * 1024 * 1024
+
+// DefaultLevel is the file log level when no verbosity is requested.
+const DefaultLevel = slog.LevelInfo
+
+// Options configures Init.
+type Options struct {
+	// TownRoot selects <town>/logs/gt-internal.log. Empty uses the user
+	// cache directory, so commands run outside a town still log.
+	TownRoot string
+
+	// Level is the minimum level written to the log file.
+	Level slog.Level
+
+	// Stderr mirrors records at Level to stderr in text form. Set when the
+	// user asked for verbosity (-v, --log-level, GT_DEBUG).
+	Stderr bool
+
+	// Process tags every record, e.g. "cli" or "daemon".
+	Process string
+}
+
+// ParseLevel parses a level name (trace, debug, info, warn, error), an
+// offset form such as "debug-4", or a slog level number.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if strings.EqualFold(s, "trace") {
+		return LevelTrace, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return slog.Level(n), nil
+	}
+	if err := level.UnmarshalText([]byte(strings.ToLower(s))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q (want trace, debug, info, warn, or error)", s)
+	}
+	return level, nil
+}
+
+// VerbosityLevel maps a -v count to a level: 0 info, 1 debug, 2+ trace
+// (slog.LevelDebug-4, which includes per-call detail such as tmux commands).
+func VerbosityLevel(count int) slog.Level {
+	switch {
+	case count <= 0:
+		return DefaultLevel
+	case count == 1:
+		return slog.LevelDebug
+	default:
+		return LevelTrace
+	}
+}
+
+// LevelTrace is below debug and records every external command gt runs.
+const LevelTrace = slog.LevelDebug - 4
+
+// EnvLevel reads GT_DEBUG. "1" or "true" means debug; a level name is used
+// as-is. ok is false when GT_DEBUG is unset or "0"/"false".
+func EnvLevel() (level slog.Level, ok bool, err error) {
+	v := strings.TrimSpace(os.Getenv("GT_DEBUG"))
+	switch strings.ToLower(v) {
+	case "", "0", "false":
+		return 0, false, nil
+	case "1", "true":
+		return slog.LevelDebug, true, nil
+	}
+	level, err = ParseLevel(v)
+	return level, err == nil, err
+}
+
+// Path returns the internal log path for a town (or the user cache
+// directory when townRoot is empty).
+func Path(townRoot string) (string, error) {
+	if townRoot != "" {
+		return filepath.Join(townRoot, "logs", FileName), nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gastown", FileName), nil
+}
+
+// Init opens the internal log and installs it as the slog default.
+// The returned closer flushes and closes the file. If the file cannot be
+// opened, only the stderr mirror (if any) is installed and the error is
+// returned so the caller can decide whether to surface it.
+func Init(opts Options) (io.Closer, error) {
+	var handlers []slog.Handler
+	var closer io.Closer = nopCloser{}
+
+	stderrOpts := &slog.HandlerOptions{Level: opts.Level, ReplaceAttr: traceLevelName}
+	if opts.Stderr {
+		handlers = append(handlers, slog.NewTextHandler(os.Stderr, stderrOpts))
+	}
+
+	path, err := Path(opts.TownRoot)
+	if err == nil {
+		var f *os.File
+		if f, err = openLog(path); err == nil {
+			closer = f
+			handlers = append(handlers, slog.NewJSONHandler(f, &slog.HandlerOptions{
+				Level:       opts.Level,
+				ReplaceAttr: traceLevelName,
+			}))
+		}
+	}
+
+	var h slog.Handler = fanout(handlers)
+	logger := slog.New(h)
+	if opts.Process != "" {
+		logger = logger.With("proc", opts.Process, "pid", os.Getpid())
+	}
+	slog.SetDefault(logger)
+	return closer, err
+}
+
+// Verbose reports whether the process logs at debug level or below, as
+// -v, --log-level, or GT_DEBUG ask, for commands that also print more
+// detail then.
+func Verbose() bool {
+	return slog.Default().Enabled(context.Background(), slog.LevelDebug)
+}
+
+// openLog opens path for appending, rotating it first if it is too large.
+func openLog(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > MaxSize {
+		_ = os.Rename(path, path+".1")
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // G302: log is not secret
+}
+
+// traceLevelName renders LevelTrace as "TRACE" instead of "DEBUG-4".
+func traceLevelName(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey && len(groups) == 0 {
+		if level, ok := a.Value.Any().(slog.Level); ok && level <= LevelTrace {
+			a.Value = slog.StringValue("TRACE")
+		}
+	}
+	return a
+}
+
+// fanout sends each record to every handler that accepts its level.
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// Exec records an external command gt ran. Successful calls are logged at
+// trace level; failures at debug level with the error.
+func Exec(name string, args []string, dir string, start time.Time, err error) {
+	ctx := context.Background()
+	level := LevelTrace
+	if err != nil {
+		level = slog.LevelDebug
+	}
+	if !slog.Default().Enabled(ctx, level) {
+		return
+	}
+	attrs := []any{"cmd", name, "args", args, "duration", time.Since(start).Round(time.Millisecond)}
+	if dir != "" {
+		attrs = append(attrs, "dir", dir)
+	}
+	if err != nil {
+		attrs = append(attrs, "err", err)
+	}
+	slog.Log(ctx, level, "exec", attrs...)
+}
@@ -0,0 +1,93 @@
+package gtlog
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]slog.Level{
+		"debug":   slog.LevelDebug,
+		"INFO":    slog.LevelInfo,
+		"warn":    slog.LevelWarn,
+		"trace":   LevelTrace,
+		"debug-4": LevelTrace,
+		"8":       slog.LevelError,
+	}
+	for in, want := range tests {
+		got, err := ParseLevel(in)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
+
+func TestVerbosityLevel(t *testing.T) {
+	if VerbosityLevel(0) != DefaultLevel || VerbosityLevel(1) != slog.LevelDebug || VerbosityLevel(3) != LevelTrace {
+		t.Error("unexpected verbosity mapping")
+	}
+}
+
+func TestEnvLevel(t *testing.T) {
+	t.Setenv("GT_DEBUG", "")
+	if _, ok, _ := EnvLevel(); ok {
+		t.Error("empty GT_DEBUG should not enable logging")
+	}
+	t.Setenv("GT_DEBUG", "1")
+	if level, ok, _ := EnvLevel(); !ok || level != slog.LevelDebug {
+		t.Errorf("GT_DEBUG=1: got %v, %v", level, ok)
+	}
+	t.Setenv("GT_DEBUG", "trace")
+	if level, ok, _ := EnvLevel(); !ok || level != LevelTrace {
+		t.Errorf("GT_DEBUG=trace: got %v, %v", level, ok)
+	}
+}
+
+func TestInitWritesJSONToTownLog(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	townRoot := t.TempDir()
+	closer, err := Init(Options{TownRoot: townRoot, Level: slog.LevelDebug, Process: "cli"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Verbose() {
+		t.Error("Verbose() = false at debug level")
+	}
+	slog.Debug("hello", "k", "v")
+	Exec("git", []string{"status"}, "", time.Now(), nil) // trace: filtered out
+	_ = closer.Close()
+
+	data, err := os.ReadFile(filepath.Join(townRoot, "logs", FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 record, got %d:\n%s", len(lines), data)
+	}
+	var rec map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec["msg"] != "hello" || rec["proc"] != "cli" || rec["k"] != "v" {
+		t.Errorf("unexpected record: %v", rec)
+	}
+
+	records, err := ReadRecords(townRoot, time.Now().Add(-time.Minute), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Msg != "hello" || records[0].Proc != "cli" || records[0].String("k") != "v" {
+		t.Errorf("ReadRecords = %+v", records)
+	}
+}
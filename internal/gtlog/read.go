@@ -0,0 +1,92 @@
+package gtlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+)
+
+// Record is one parsed internal log entry.
+type Record struct {
+	Time  time.Time
+	Level string
+	Msg   string
+	Proc  string
+	PID   int
+	Attrs map[string]interface{} // remaining fields
+}
+
+// String returns attr key as a string, or "" if absent.
+func (r Record) String(key string) string {
+	if v, ok := r.Attrs[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// Duration returns attr key as a duration, or 0 if absent.
+// slog's JSON handler encodes durations as integer nanoseconds.
+func (r Record) Duration(key string) time.Duration {
+	if v, ok := r.Attrs[key].(float64); ok {
+		return time.Duration(v)
+	}
+	return 0
+}
+
+// ReadRecords returns the internal log records for a town with timestamps
+// in [from, to], oldest first. The rotated log is included so a window
+// spanning a rotation is complete. Unparseable lines are skipped.
+func ReadRecords(townRoot string, from, to time.Time) ([]Record, error) {
+	path, err := Path(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	for _, p := range []string{path + ".1", path} {
+		recs, err := readRecordsFile(p, from, to)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		records = append(records, recs...)
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, nil
+}
+
+func readRecordsFile(path string, from, to time.Time) ([]Record, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is derived from the town root
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &fields); err != nil {
+			continue
+		}
+		r := Record{Attrs: fields}
+		if ts, ok := fields["time"].(string); ok {
+			r.Time, _ = time.Parse(time.RFC3339Nano, ts)
+		}
+		if r.Time.Before(from) || r.Time.After(to) {
+			continue
+		}
+		r.Level, _ = fields["level"].(string)
+		r.Msg, _ = fields["msg"].(string)
+		r.Proc, _ = fields["proc"].(string)
+		if pid, ok := fields["pid"].(float64); ok {
+			r.PID = int(pid)
+		}
+		for _, k := range []string{"time", "level", "msg", "proc", "pid"} {
+			delete(fields, k)
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
@@ -0,0 +1,244 @@
+// Package handoff builds the context summary an agent passes to its next
+// session with 'gt handoff --summarize', so the fresh session starts from
+// a condensed account of the last one (recent commits, uncommitted work,
+// open tasks, decisions and instructions from the town log) instead of
+// cold.
+//
+// The summary is saved under the town's .runtime directory and shown, then
+// removed, by the next 'gt prime' for the agent, which the fresh session
+// runs on startup. 'gt clone' hands a new agent the summary of the one it
+// was cloned from in the same way.
+package handoff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/beads"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// Limits keep the summary condensed: it is read by the next session, not
+// archived.
+const (
+	MaxCommits     = 10
+	MaxUncommitted = 15
+	MaxTasks       = 10
+	MaxDecisions   = 10
+)
+
+// DefaultLookback bounds how far back a summary reaches when the agent's
+// session start is not in the town log.
+const DefaultLookback = 24 * time.Hour
+
+// Summary is the condensed context of one agent session.
+type Summary struct {
+	Agent       string    `json:"agent"`
+	From        string    `json:"from,omitempty"` // the agent summarized, if cloned from another
+	WrittenAt   time.Time `json:"written_at"`
+	Since       time.Time `json:"since"` // start of the summarized session
+	Branch      string    `json:"branch,omitempty"`
+	Commits     []string  `json:"commits,omitempty"`     // "abc1234 subject", newest first
+	Uncommitted []string  `json:"uncommitted,omitempty"` // git status --short lines
+	Tasks       []string  `json:"tasks,omitempty"`       // hooked and in-progress beads
+	Decisions   []string  `json:"decisions,omitempty"`   // notes, marks, and nudges, oldest first
+	Message     string    `json:"message,omitempty"`     // the handoff message, if any
+	More        []string  `json:"more,omitempty"`        // sections cut short, e.g. "3 more commits"
+}
+
+// Build summarizes the session of agent working in workDir, as of now.
+// Each source is best effort: a workDir that is not a git clone, or beads
+// that cannot be queried, leave their section empty.
+func Build(townRoot, workDir, agent, message string, now time.Time) *Summary {
+	s := &Summary{Agent: agent, WrittenAt: now, Message: strings.TrimSpace(message)}
+	events, _ := townlog.ReadEvents(townRoot)
+	s.Since = sessionStart(events, agent, now)
+
+	if workDir != "" {
+		s.Branch = git(workDir, "rev-parse", "--abbrev-ref", "HEAD")
+		commits := lines(git(workDir, "log", "--since="+s.Since.Format(time.RFC3339), "--format=%h %s", "-n", fmt.Sprint(MaxCommits+1)))
+		if len(commits) == 0 {
+			commits = lines(git(workDir, "log", "--format=%h %s", "-n", "3")) // nothing new: where the branch stands
+		}
+		s.Commits = s.clip(commits, MaxCommits, "commits")
+		s.Uncommitted = s.clip(lines(git(workDir, "status", "--short")), MaxUncommitted, "changed files")
+		s.Tasks = s.clip(openTasks(workDir, agent), MaxTasks, "tasks")
+	}
+	s.Decisions = decisions(events, agent, s.Since)
+	if n := len(s.Decisions) - MaxDecisions; n > 0 { // keep the latest
+		s.More = append(s.More, fmt.Sprintf("%d earlier notes", n))
+		s.Decisions = s.Decisions[n:]
+	}
+	return s
+}
+
+// clip keeps the first max items, noting how many were cut.
+func (s *Summary) clip(items []string, max int, what string) []string {
+	if len(items) <= max {
+		return items
+	}
+	s.More = append(s.More, fmt.Sprintf("%d more %s", len(items)-max, what))
+	return items[:max]
+}
+
+// sessionStart returns when the agent's current session began: its last
+// spawn, wake, or handoff in the town log, within DefaultLookback.
+func sessionStart(events []townlog.Event, agent string, now time.Time) time.Time {
+	start := now.Add(-DefaultLookback)
+	key := townlog.AgentKey(agent)
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		if e.Timestamp.Before(start) {
+			break
+		}
+		if townlog.AgentKey(e.Agent) != key {
+			continue
+		}
+		switch e.Type {
+		case townlog.EventSpawn, townlog.EventWake, townlog.EventHandoff:
+			return e.Timestamp
+		}
+	}
+	return start
+}
+
+// decisions returns what the town log recorded about the agent's session:
+// its notes and marks, and the nudges it was sent.
+func decisions(events []townlog.Event, agent string, since time.Time) []string {
+	key := townlog.AgentKey(agent)
+	var out []string
+	for _, e := range events {
+		if e.Timestamp.Before(since) || townlog.AgentKey(e.Agent) != key || e.Context == "" {
+			continue
+		}
+		switch e.Type {
+		case townlog.EventNote, townlog.EventMark, townlog.EventNudge:
+			out = append(out, fmt.Sprintf("%s %s: %s", e.Timestamp.Local().Format("15:04"), e.Type, oneLine(e.Context)))
+		}
+	}
+	return out
+}
+
+// openTasks lists the agent's hooked and in-progress beads.
+func openTasks(workDir, agent string) []string {
+	b := beads.New(workDir)
+	var out []string
+	for _, status := range []string{beads.StatusHooked, "in_progress"} {
+		issues, err := b.List(beads.ListOptions{Status: status, Assignee: agent, Priority: -1})
+		if err != nil {
+			continue
+		}
+		for _, issue := range issues {
+			out = append(out, fmt.Sprintf("%s [%s] %s", issue.ID, status, issue.Title))
+		}
+	}
+	return out
+}
+
+func git(dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(out), "\n")
+}
+
+func lines(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func oneLine(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > 200 {
+		s = s[:197] + "..."
+	}
+	return s
+}
+
+// Markdown renders the summary for the next session.
+func (s *Summary) Markdown(now time.Time) string {
+	var b strings.Builder
+	when := "just now"
+	if age := now.Sub(s.WrittenAt).Round(time.Minute); age > 0 {
+		when = age.String() + " ago"
+	}
+	if s.From != "" {
+		fmt.Fprintf(&b, "Cloned %s from %s, whose session started %s. You start where it stands; take your own approach from here.\n",
+			when, s.From, s.Since.Local().Format("Jan 2 15:04"))
+	} else {
+		fmt.Fprintf(&b, "Handed off %s by the previous %s session (which started %s).\n",
+			when, s.Agent, s.Since.Local().Format("Jan 2 15:04"))
+	}
+	if s.Branch != "" {
+		fmt.Fprintf(&b, "Branch: %s\n", s.Branch)
+	}
+	section := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n### %s\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+	}
+	section("Recent commits", s.Commits)
+	section("Uncommitted changes", s.Uncommitted)
+	section("Open tasks", s.Tasks)
+	section("Decisions, notes, and instructions", s.Decisions)
+	if s.Message != "" {
+		fmt.Fprintf(&b, "\n### Handoff message\n%s\n", s.Message)
+	}
+	if len(s.More) > 0 {
+		fmt.Fprintf(&b, "\n(Not shown: %s.)\n", strings.Join(s.More, ", "))
+	}
+	return b.String()
+}
+
+// Path returns where the summary handed to an agent's next session is
+// kept.
+func Path(townRoot, agent string) string {
+	name := strings.ReplaceAll(townlog.AgentKey(agent), "/", "--")
+	return filepath.Join(townRoot, ".runtime", "handoff", name+".json")
+}
+
+// Save stores the summary for the agent's next session, replacing any
+// left unread.
+func Save(townRoot string, s *Summary) error {
+	path := Path(townRoot, s.Agent)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, s)
+}
+
+// Take returns the summary handed to the agent and removes it, so it is
+// shown to one session only. It returns nil if there is none.
+func Take(townRoot, agent string) (*Summary, error) {
+	path := Path(townRoot, agent)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var s Summary
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := os.Remove(path); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
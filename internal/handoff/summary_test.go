@@ -0,0 +1,138 @@
+package handoff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+func gitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "Add the parser")
+	if err := os.WriteFile(filepath.Join(dir, "b.go"), []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestBuild(t *testing.T) {
+	townRoot := t.TempDir()
+	workDir := gitRepo(t)
+	now := time.Now()
+	agent := "gastown/crew/max"
+
+	logger := townlog.NewLogger(townRoot)
+	for _, e := range []townlog.Event{
+		{Timestamp: now.Add(-3 * time.Hour), Type: townlog.EventNote, Agent: agent, Context: "from the previous session"},
+		{Timestamp: now.Add(-2 * time.Hour), Type: townlog.EventSpawn, Agent: agent},
+		{Timestamp: now.Add(-time.Hour), Type: townlog.EventNote, Agent: agent, Context: "chose the\nstreaming parser"},
+		{Timestamp: now.Add(-30 * time.Minute), Type: townlog.EventNudge, Agent: agent, Context: "freeze main at 5"},
+		{Timestamp: now.Add(-20 * time.Minute), Type: townlog.EventNote, Agent: "gastown/crew/joe", Context: "someone else's"},
+	} {
+		if err := logger.LogEvent(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := Build(townRoot, workDir, agent, "finish the tests", now)
+	if s.Since.Sub(now.Add(-2*time.Hour)).Abs() > time.Second {
+		t.Errorf("Since = %v, want the spawn at %v", s.Since, now.Add(-2*time.Hour))
+	}
+	if s.Branch != "main" {
+		t.Errorf("Branch = %q, want main", s.Branch)
+	}
+	if len(s.Commits) != 1 || !strings.HasSuffix(s.Commits[0], "Add the parser") {
+		t.Errorf("Commits = %v", s.Commits)
+	}
+	if len(s.Uncommitted) != 1 || !strings.HasSuffix(s.Uncommitted[0], "b.go") {
+		t.Errorf("Uncommitted = %v", s.Uncommitted)
+	}
+	if len(s.Decisions) != 2 || !strings.HasSuffix(s.Decisions[0], "note: chose the streaming parser") ||
+		!strings.HasSuffix(s.Decisions[1], "nudge: freeze main at 5") {
+		t.Errorf("Decisions = %v", s.Decisions)
+	}
+
+	md := s.Markdown(now.Add(5 * time.Minute))
+	for _, want := range []string{"Branch: main", "### Recent commits", "### Uncommitted changes", "### Decisions", "### Handoff message\nfinish the tests"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "### Open tasks") {
+		t.Errorf("Markdown has an empty Open tasks section:\n%s", md)
+	}
+
+	s.Agent, s.From = "gastown/crew/max2", "gastown/crew/max"
+	if md := s.Markdown(now); !strings.HasPrefix(md, "Cloned just now from gastown/crew/max") {
+		t.Errorf("Markdown of a clone's summary:\n%s", md)
+	}
+}
+
+func TestBuildWithoutSessionStart(t *testing.T) {
+	now := time.Now()
+	s := Build(t.TempDir(), "", "mayor", "", now)
+	if !s.Since.Equal(now.Add(-DefaultLookback)) {
+		t.Errorf("Since = %v, want %v", s.Since, now.Add(-DefaultLookback))
+	}
+	if s.Branch != "" || len(s.Commits) != 0 {
+		t.Errorf("summary without a workDir has git context: %+v", s)
+	}
+}
+
+func TestClip(t *testing.T) {
+	s := &Summary{}
+	items := make([]string, MaxCommits+3)
+	if got := s.clip(items, MaxCommits, "commits"); len(got) != MaxCommits {
+		t.Errorf("clip kept %d, want %d", len(got), MaxCommits)
+	}
+	if got := s.clip(items[:2], MaxCommits, "tasks"); len(got) != 2 {
+		t.Errorf("clip kept %d, want 2", len(got))
+	}
+	if len(s.More) != 1 || s.More[0] != "3 more commits" {
+		t.Errorf("More = %v", s.More)
+	}
+}
+
+func TestSaveTake(t *testing.T) {
+	townRoot := t.TempDir()
+	s := &Summary{Agent: "gastown/crew/max", WrittenAt: time.Now(), Commits: []string{"abc1234 Add the parser"}}
+	if err := Save(townRoot, s); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Take(townRoot, "gastown/polecats/max"); err != nil || got != nil {
+		t.Errorf("Take for another agent = %v, %v", got, err)
+	}
+	got, err := Take(townRoot, "gastown/crew/max")
+	if err != nil || got == nil {
+		t.Fatalf("Take = %v, %v", got, err)
+	}
+	if len(got.Commits) != 1 || got.Commits[0] != s.Commits[0] {
+		t.Errorf("Take = %+v", got)
+	}
+	if got, err := Take(townRoot, "gastown/crew/max"); err != nil || got != nil {
+		t.Errorf("second Take = %v, %v, want nil", got, err)
+	}
+}
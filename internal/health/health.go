@@ -0,0 +1,188 @@
+// Package health probes running agents for liveness, so that an agent
+// whose process died or that stopped producing output is noticed without
+// someone looking at its pane.
+//
+// Each Gas Town tmux session is probed for three things: whether its pane
+// is dead, whether the agent process still runs in it (rather than the
+// shell it was started from), and how long ago the pane last printed
+// output. The daemon probes every heartbeat and 'gt health' on demand;
+// both record transitions in the town log, so an agent going unhealthy is
+// logged once, as a warning, rather than on every probe.
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+// EventHealth is the town log event recorded when an agent's health
+// changes: warn severity when it goes unhealthy, info when it recovers.
+const EventHealth townlog.EventType = "health"
+
+// DefaultStaleAfter is how long an agent may go without printing output
+// before it is reported stale.
+const DefaultStaleAfter = 30 * time.Minute
+
+// Status is an agent's health as of a probe.
+type Status string
+
+const (
+	// StatusHealthy: the agent is running and printed output recently.
+	StatusHealthy Status = "healthy"
+	// StatusStale: the agent is running but has printed nothing for
+	// longer than the stale threshold.
+	StatusStale Status = "stale"
+	// StatusExited: the agent process exited, leaving its shell.
+	StatusExited Status = "exited"
+	// StatusDead: the pane's process exited and the pane is dead.
+	StatusDead Status = "dead"
+	// StatusPaused: the agent was frozen by 'gt pause'.
+	StatusPaused Status = "paused"
+)
+
+// Healthy reports whether s needs no attention.
+func (s Status) Healthy() bool {
+	return s == StatusHealthy || s == StatusPaused
+}
+
+// Probe is the result of probing one agent session.
+type Probe struct {
+	Agent      string    `json:"agent"` // e.g. "gastown/crew/max"
+	Session    string    `json:"session"`
+	Status     Status    `json:"status"`
+	Command    string    `json:"command,omitempty"` // what runs in the pane
+	LastOutput time.Time `json:"last_output,omitempty"`
+	Reason     string    `json:"reason,omitempty"` // why the agent is unhealthy
+}
+
+// Age returns how long before now the agent last printed output, or 0 if
+// that is unknown.
+func (p Probe) Age(now time.Time) time.Duration {
+	if p.LastOutput.IsZero() {
+		return 0
+	}
+	return now.Sub(p.LastOutput)
+}
+
+// Sessions is what probing needs of tmux.
+type Sessions interface {
+	ListSessions() ([]string, error)
+	GetPaneState(session string) (*tmux.PaneState, error)
+}
+
+// Check probes every Gas Town agent session, sorted by agent. Sessions
+// that vanish while being probed are left out, and paused sessions (by
+// session name) are reported paused unless their pane is dead: a paused
+// agent is silent, and its shell holds the terminal.
+func Check(t Sessions, now time.Time, staleAfter time.Duration, paused map[string]session.Pause) ([]Probe, error) {
+	names, err := t.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	var probes []Probe
+	for _, name := range names {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue // not a Gas Town session
+		}
+		state, err := t.GetPaneState(name)
+		if err != nil {
+			continue
+		}
+		p := Probe{Agent: id.Address(), Session: name, Command: state.Command, LastOutput: state.Activity}
+		p.Status, p.Reason = Classify(state, now, staleAfter)
+		if _, ok := paused[name]; ok && p.Status != StatusDead {
+			p.Status, p.Reason = StatusPaused, ""
+		}
+		probes = append(probes, p)
+	}
+	sort.Slice(probes, func(i, j int) bool { return probes[i].Agent < probes[j].Agent })
+	return probes, nil
+}
+
+// Classify returns the status of a pane, and why it is unhealthy. A zero
+// staleAfter never reports the pane stale.
+func Classify(state *tmux.PaneState, now time.Time, staleAfter time.Duration) (Status, string) {
+	if state.Dead {
+		return StatusDead, "pane is dead"
+	}
+	for _, sh := range constants.SupportedShells {
+		if state.Command == sh {
+			return StatusExited, "agent exited to " + sh
+		}
+	}
+	if staleAfter > 0 && !state.Activity.IsZero() {
+		if age := now.Sub(state.Activity); age > staleAfter {
+			return StatusStale, "no output for " + age.Truncate(time.Minute).String()
+		}
+	}
+	return StatusHealthy, ""
+}
+
+// stateFile records the last status seen of each agent, so transitions
+// are logged once.
+type stateFile struct {
+	Agents map[string]Status `json:"agents"`
+}
+
+// StatePath returns where the last probed statuses are kept.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "health.json")
+}
+
+// Record logs the probes whose status changed since the last recorded
+// probe, and saves the new statuses. Agents seen for the first time are
+// logged only if unhealthy; agents whose sessions are gone are forgotten,
+// their exit being the crash or done event's to report. It returns the
+// probes that were logged.
+func Record(townRoot string, probes []Probe, now time.Time) ([]Probe, error) {
+	path := StatePath(townRoot)
+	var prev stateFile
+	if data, err := os.ReadFile(path); err == nil { //nolint:gosec // G304: path is within the town
+		_ = json.Unmarshal(data, &prev)
+	}
+
+	next := stateFile{Agents: make(map[string]Status, len(probes))}
+	var changed []Probe
+	logger := townlog.NewLogger(townRoot)
+	for _, p := range probes {
+		next.Agents[p.Agent] = p.Status
+		was, seen := prev.Agents[p.Agent]
+		if was == p.Status || (p.Status.Healthy() && (!seen || was.Healthy())) {
+			continue
+		}
+		e := townlog.Event{
+			Timestamp: now,
+			Type:      EventHealth,
+			Agent:     p.Agent,
+			Context:   fmt.Sprintf("%s: %s", p.Status, p.Reason),
+			Severity:  townlog.SeverityWarn,
+		}
+		if p.Status.Healthy() {
+			e.Context = fmt.Sprintf("recovered (was %s)", was)
+			e.Severity = townlog.SeverityInfo
+		}
+		if err := logger.LogEvent(e); err != nil {
+			return changed, fmt.Errorf("logging health of %s: %w", p.Agent, err)
+		}
+		changed = append(changed, p)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return changed, err
+	}
+	data, err := json.MarshalIndent(next, "", "  ")
+	if err != nil {
+		return changed, err
+	}
+	return changed, os.WriteFile(path, data, 0644) //nolint:gosec // G306: not sensitive
+}
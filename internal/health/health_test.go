@@ -0,0 +1,114 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+type fakeSessions map[string]*tmux.PaneState
+
+func (f fakeSessions) ListSessions() ([]string, error) {
+	var names []string
+	for name := range f {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (f fakeSessions) GetPaneState(session string) (*tmux.PaneState, error) {
+	return f[session], nil
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	sessions := fakeSessions{
+		"gt-gastown-crew-max": {Command: "node", Activity: now.Add(-time.Minute)},
+		"gt-gastown-Toast":    {Command: "node", Activity: now.Add(-2 * time.Hour)},
+		"gt-gastown-witness":  {Command: "bash", Activity: now},
+		"gt-mayor":            {Dead: true},
+		"gt-gastown-crew-joe": {Command: "node", Activity: now.Add(-3 * time.Hour)},
+		"scratch":             {Command: "vim"},
+	}
+	paused := map[string]session.Pause{"gt-gastown-crew-joe": {Agent: "gastown/crew/joe"}}
+
+	probes, err := Check(sessions, now, DefaultStaleAfter, paused)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Status{
+		"gastown/crew/joe":       StatusPaused,
+		"gastown/crew/max":       StatusHealthy,
+		"gastown/polecats/Toast": StatusStale,
+		"gastown/witness":        StatusExited,
+		"mayor":                  StatusDead,
+	}
+	if len(probes) != len(want) {
+		t.Fatalf("got %d probes, want %d: %+v", len(probes), len(want), probes)
+	}
+	for _, p := range probes {
+		if p.Status != want[p.Agent] {
+			t.Errorf("%s: status %s, want %s", p.Agent, p.Status, want[p.Agent])
+		}
+		if !p.Status.Healthy() && p.Reason == "" {
+			t.Errorf("%s: unhealthy without a reason", p.Agent)
+		}
+	}
+	if probes[0].Agent != "gastown/crew/joe" {
+		t.Errorf("probes not sorted: first is %s", probes[0].Agent)
+	}
+
+	// A zero threshold turns staleness off.
+	probes, _ = Check(sessions, now, 0, nil)
+	for _, p := range probes {
+		if p.Agent == "gastown/polecats/Toast" && p.Status != StatusHealthy {
+			t.Errorf("staleness off: Toast is %s", p.Status)
+		}
+	}
+}
+
+func TestRecordLogsTransitionsOnce(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	healthy := Probe{Agent: "gastown/crew/max", Status: StatusHealthy}
+	dead := Probe{Agent: "gastown/crew/max", Status: StatusDead, Reason: "pane is dead"}
+	paused := Probe{Agent: "gastown/crew/max", Status: StatusPaused}
+
+	steps := []struct {
+		probes []Probe
+		logged int
+	}{
+		{[]Probe{healthy}, 0}, // first sight, healthy: nothing to report
+		{[]Probe{dead}, 1},    // went unhealthy
+		{[]Probe{dead}, 0},    // still unhealthy: already reported
+		{[]Probe{healthy}, 1}, // recovered
+		{[]Probe{paused}, 0},  // paused on purpose: not a change of health
+		{[]Probe{healthy}, 0}, // resumed
+	}
+	for i, step := range steps {
+		changed, err := Record(townRoot, step.probes, now)
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+		if len(changed) != step.logged {
+			t.Errorf("step %d: logged %d, want %d", i, len(changed), step.logged)
+		}
+	}
+
+	events, err := townlog.ReadEvents(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if events[0].Type != EventHealth || events[0].Level() != townlog.SeverityWarn {
+		t.Errorf("unhealthy event = %s/%s, want health/warn", events[0].Type, events[0].Level())
+	}
+	if events[1].Level() != townlog.SeverityInfo {
+		t.Errorf("recovery event severity = %s, want info", events[1].Level())
+	}
+}
@@ -0,0 +1,183 @@
+// Package howto is the cookbook behind 'gt howto': short operational
+// recipes embedded in the binary, so they always describe the installed
+// version of gt.
+//
+// Each recipe is a Markdown file in recipes/ with front matter:
+//
+//	---
+//	title: Resurrect a crashed crew member
+//	summary: One line shown in listings
+//	tags: crew, crash, recovery
+//	---
+//
+// Commands in fenced code blocks are what the recipe tells you to run.
+package howto
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed recipes/*.md
+var recipesFS embed.FS
+
+// Recipe is one cookbook entry.
+type Recipe struct {
+	Slug    string   // file name without .md, e.g. "resurrect-crew"
+	Title   string   // e.g. "Resurrect a crashed crew member"
+	Summary string   // one line
+	Tags    []string // search keywords
+	Body    string   // Markdown after the front matter
+}
+
+var (
+	loadOnce sync.Once
+	recipes  []Recipe
+)
+
+func load() {
+	loadOnce.Do(func() {
+		entries, err := recipesFS.ReadDir("recipes")
+		if err != nil {
+			panic(fmt.Sprintf("howto: reading embedded recipes: %v", err))
+		}
+		for _, e := range entries {
+			data, err := recipesFS.ReadFile(path.Join("recipes", e.Name()))
+			if err != nil {
+				panic(fmt.Sprintf("howto: reading %s: %v", e.Name(), err))
+			}
+			r, err := parse(strings.TrimSuffix(e.Name(), ".md"), string(data))
+			if err != nil {
+				panic(fmt.Sprintf("howto: %v", err))
+			}
+			recipes = append(recipes, r)
+		}
+		sort.Slice(recipes, func(i, j int) bool { return recipes[i].Slug < recipes[j].Slug })
+	})
+}
+
+// parse reads a recipe file: front matter, then the Markdown body.
+func parse(slug, content string) (Recipe, error) {
+	r := Recipe{Slug: slug}
+	rest, ok := strings.CutPrefix(content, "---\n")
+	if !ok {
+		return r, fmt.Errorf("%s: missing front matter", slug)
+	}
+	header, body, ok := strings.Cut(rest, "\n---\n")
+	if !ok {
+		return r, fmt.Errorf("%s: unterminated front matter", slug)
+	}
+	for _, line := range strings.Split(header, "\n") {
+		key, value, _ := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "title":
+			r.Title = value
+		case "summary":
+			r.Summary = value
+		case "tags":
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					r.Tags = append(r.Tags, tag)
+				}
+			}
+		}
+	}
+	if r.Title == "" {
+		return r, fmt.Errorf("%s: recipe has no title", slug)
+	}
+	r.Body = strings.TrimLeft(body, "\n")
+	return r, nil
+}
+
+// All returns every recipe, ordered by slug.
+func All() []Recipe {
+	load()
+	return recipes
+}
+
+// Get returns the recipe with the given slug.
+func Get(slug string) (Recipe, bool) {
+	for _, r := range All() {
+		if r.Slug == slug {
+			return r, true
+		}
+	}
+	return Recipe{}, false
+}
+
+// Slugs returns every recipe's slug.
+func Slugs() []string {
+	var slugs []string
+	for _, r := range All() {
+		slugs = append(slugs, r.Slug)
+	}
+	return slugs
+}
+
+// Search returns the recipes containing every word of query, best match
+// first: a word counts most in the title or slug, then in the tags and
+// summary, then anywhere in the body.
+func Search(query string) []Recipe {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return nil
+	}
+	type scored struct {
+		r     Recipe
+		score int
+	}
+	var hits []scored
+	for _, r := range All() {
+		title := strings.ToLower(r.Title + " " + r.Slug)
+		meta := strings.ToLower(strings.Join(r.Tags, " ") + " " + r.Summary)
+		body := strings.ToLower(r.Body)
+		score := 0
+		for _, w := range words {
+			switch {
+			case strings.Contains(title, w):
+				score += 3
+			case strings.Contains(meta, w):
+				score += 2
+			case strings.Contains(body, w):
+				score++
+			default:
+				score = -1
+			}
+			if score < 0 {
+				break
+			}
+		}
+		if score > 0 {
+			hits = append(hits, scored{r, score})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+	result := make([]Recipe, len(hits))
+	for i, h := range hits {
+		result[i] = h.r
+	}
+	return result
+}
+
+// Commands returns the shell commands in the recipe's bash code blocks, in
+// order, without comments.
+func (r Recipe) Commands() []string {
+	var cmds []string
+	inBash := false
+	for _, line := range strings.Split(r.Body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			lang := strings.TrimPrefix(trimmed, "```")
+			inBash = !inBash && (lang == "bash" || lang == "sh")
+		case inBash && trimmed != "" && !strings.HasPrefix(trimmed, "#"):
+			cmds = append(cmds, trimmed)
+		}
+	}
+	return cmds
+}
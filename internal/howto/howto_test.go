@@ -0,0 +1,64 @@
+package howto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecipesParse(t *testing.T) {
+	all := All()
+	if len(all) == 0 {
+		t.Fatal("no embedded recipes")
+	}
+	for _, r := range all {
+		if r.Summary == "" || len(r.Tags) == 0 {
+			t.Errorf("%s: recipe needs a summary and tags", r.Slug)
+		}
+		if !strings.HasPrefix(r.Body, "# ") {
+			t.Errorf("%s: body should start with a heading", r.Slug)
+		}
+		if len(r.Commands()) == 0 {
+			t.Errorf("%s: recipe has no example commands", r.Slug)
+		}
+	}
+	if _, ok := Get("resurrect-crew"); !ok {
+		t.Error("Get(resurrect-crew) not found")
+	}
+}
+
+func TestParse(t *testing.T) {
+	r, err := parse("demo", "---\ntitle: Demo\nsummary: A demo\ntags: a, b,\n---\n\n# Demo\n\n```bash\n# comment\ngt status\n\ngt log -f\n```\n\n```json\n{\"x\": 1}\n```\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Title != "Demo" || r.Summary != "A demo" || len(r.Tags) != 2 {
+		t.Errorf("parsed %+v", r)
+	}
+	if got := r.Commands(); len(got) != 2 || got[0] != "gt status" || got[1] != "gt log -f" {
+		t.Errorf("Commands() = %q", got)
+	}
+	if _, err := parse("bad", "# no front matter"); err == nil {
+		t.Error("missing front matter should fail")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	got := Search("crashed crew")
+	if len(got) == 0 || got[0].Slug != "resurrect-crew" {
+		t.Errorf("Search(crashed crew) = %v", slugs(got))
+	}
+	if got := Search("preset"); len(got) == 0 || got[0].Slug != "switch-agent-preset" {
+		t.Errorf("Search(preset) = %v", slugs(got))
+	}
+	if got := Search("crew nonexistentword"); len(got) != 0 {
+		t.Errorf("every word must match, got %v", slugs(got))
+	}
+}
+
+func slugs(rs []Recipe) []string {
+	var s []string
+	for _, r := range rs {
+		s = append(s, r.Slug)
+	}
+	return s
+}
@@ -0,0 +1,143 @@
+// Package i18n provides the message catalog for user-facing CLI strings.
+//
+// Messages are looked up by key with T, which formats the localized
+// template with fmt.Sprintf. Catalogs live in locales/<lang>.json and are
+// embedded in the binary. English (en) is the source catalog: every key must
+// exist there, and other locales fall back to it for missing keys.
+//
+// The locale is chosen from GT_LANG, then the POSIX variables LC_ALL,
+// LC_MESSAGES, and LANG. Only the language part is used ("de_DE.UTF-8" → "de").
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the source locale that all others fall back to.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var (
+	mu       sync.RWMutex
+	catalogs map[string]map[string]string
+	current  string
+	loadOnce sync.Once
+)
+
+func load() {
+	loadOnce.Do(func() {
+		catalogs = make(map[string]map[string]string)
+		entries, err := localeFS.ReadDir("locales")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: reading embedded locales: %v", err))
+		}
+		for _, e := range entries {
+			data, err := localeFS.ReadFile(path.Join("locales", e.Name()))
+			if err != nil {
+				panic(fmt.Sprintf("i18n: reading %s: %v", e.Name(), err))
+			}
+			var msgs map[string]string
+			if err := json.Unmarshal(data, &msgs); err != nil {
+				panic(fmt.Sprintf("i18n: parsing %s: %v", e.Name(), err))
+			}
+			catalogs[strings.TrimSuffix(e.Name(), ".json")] = msgs
+		}
+		current = detect()
+	})
+}
+
+// Detect returns the locale selected by the environment, or DefaultLocale
+// if none is set or the requested language has no catalog.
+func Detect() string {
+	load()
+	return detect()
+}
+
+func detect() string {
+	for _, env := range []string{"GT_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		lang := normalize(v)
+		if _, ok := catalogs[lang]; ok {
+			return lang
+		}
+		// The first variable that is set wins, as in POSIX, even if we
+		// have no catalog for it.
+		return DefaultLocale
+	}
+	return DefaultLocale
+}
+
+// normalize reduces a POSIX locale name to its language: "pt_BR.UTF-8" → "pt".
+func normalize(locale string) string {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "_.@-"); i >= 0 {
+		lang = lang[:i]
+	}
+	if lang == "c" || lang == "posix" {
+		return DefaultLocale
+	}
+	return lang
+}
+
+// Locale returns the active locale.
+func Locale() string {
+	load()
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// SetLocale switches the active locale. It returns an error (and leaves the
+// locale unchanged) if there is no catalog for it.
+func SetLocale(locale string) error {
+	load()
+	lang := normalize(locale)
+	if _, ok := catalogs[lang]; !ok {
+		return fmt.Errorf("unsupported locale %q (available: %s)", locale, strings.Join(Available(), ", "))
+	}
+	mu.Lock()
+	current = lang
+	mu.Unlock()
+	return nil
+}
+
+// Available returns the supported locales, sorted.
+func Available() []string {
+	load()
+	out := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		out = append(out, lang)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// T returns the message for key in the active locale, formatted with args.
+// Unknown keys return the key itself so missing entries are visible.
+func T(key string, args ...interface{}) string {
+	load()
+	mu.RLock()
+	msg, ok := catalogs[current][key]
+	mu.RUnlock()
+	if !ok {
+		if msg, ok = catalogs[DefaultLocale][key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+var verbRe = regexp.MustCompile(`%[-+# 0-9.\[\]]*[a-zA-Z]`)
+
+// Every locale must be a subset of en with the same format verbs, so
+// translations can never break fmt.Sprintf.
+func TestCatalogsMatchSource(t *testing.T) {
+	load()
+	source := catalogs[DefaultLocale]
+	if len(source) == 0 {
+		t.Fatal("source catalog is empty")
+	}
+	for lang, msgs := range catalogs {
+		for key, msg := range msgs {
+			want, ok := source[key]
+			if !ok {
+				t.Errorf("%s: key %q not in %s", lang, key, DefaultLocale)
+				continue
+			}
+			if got, exp := verbRe.FindAllString(msg, -1), verbRe.FindAllString(want, -1); len(got) != len(exp) {
+				t.Errorf("%s: %q has verbs %v, source has %v", lang, key, got, exp)
+			}
+		}
+	}
+}
+
+func TestSetLocaleAndFallback(t *testing.T) {
+	defer func() { _ = SetLocale(DefaultLocale) }()
+
+	if err := SetLocale("es_ES.UTF-8"); err != nil {
+		t.Fatal(err)
+	}
+	if got := T("status.unread", 3); got != "3 sin leer" {
+		t.Errorf("es status.unread = %q", got)
+	}
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Errorf("unknown key = %q", got)
+	}
+	if err := SetLocale("xx"); err == nil {
+		t.Error("expected error for unsupported locale")
+	}
+	if Locale() != "es" {
+		t.Errorf("failed SetLocale should keep locale, got %q", Locale())
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("GT_LANG", "")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	if got := Detect(); got != "de" {
+		t.Errorf("LANG=de_DE.UTF-8: got %q", got)
+	}
+	t.Setenv("GT_LANG", "es")
+	if got := Detect(); got != "es" {
+		t.Errorf("GT_LANG=es: got %q", got)
+	}
+	t.Setenv("GT_LANG", "")
+	t.Setenv("LC_ALL", "C")
+	if got := Detect(); got != DefaultLocale {
+		t.Errorf("LC_ALL=C: got %q", got)
+	}
+}
@@ -0,0 +1,199 @@
+// Package idle puts worker agents that have gone quiet to sleep and wakes
+// them when work arrives.
+//
+// A worker agent (crew or polecat) is idle when its pane has printed no
+// output and its worktree has not changed, at HEAD or in its uncommitted
+// files, for the town's daemon.sleep_idle_after. The daemon checks every
+// heartbeat and puts idle agents to sleep: their session is paused as by
+// gt pause, keeping the conversation in memory, and no longer takes a slot
+// toward max_concurrent_agents. A sleeping agent is resumed when work
+// arrives for it: a nudge, mail, work slung onto its hook, or a scheduled
+// wake. Falling asleep and waking are logged as "sleep" events.
+package idle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/drift"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+// EventSleep is the town log event recorded when an idle agent is put to
+// sleep, and when it is woken.
+const EventSleep townlog.EventType = "sleep"
+
+// PausedBy is the By of the pause records of sleeping agents, which tells
+// them apart from agents paused by hand or preempted.
+const PausedBy = "idle"
+
+// Agent is a running worker agent as of an idle check.
+type Agent struct {
+	Agent    string // address, e.g. "gastown/crew/max"
+	Session  string
+	Worktree string
+	Output   time.Time // when its pane last printed output; zero if unknown
+	Changed  time.Time // when its worktree was last seen to change
+}
+
+// LastActive returns when the agent last printed output or changed its
+// worktree.
+func (a Agent) LastActive() time.Time {
+	if a.Output.After(a.Changed) {
+		return a.Output
+	}
+	return a.Changed
+}
+
+// Tracker remembers the state of agents' worktrees between checks, to tell
+// when they last changed. A worktree counts as changed when first seen, so
+// agents are never put to sleep sooner than the idle period after the
+// tracker starts.
+type Tracker struct {
+	worktrees map[string]worktreeState // by session
+}
+
+type worktreeState struct {
+	fingerprint string
+	changed     time.Time
+}
+
+// NewTracker returns a tracker that has seen no worktrees.
+func NewTracker() *Tracker {
+	return &Tracker{worktrees: make(map[string]worktreeState)}
+}
+
+// LastChanged returns when the session's worktree was last seen to change:
+// now, if it changed since the session's last check or was not seen before.
+func (tr *Tracker) LastChanged(sessionName, worktree string, now time.Time) time.Time {
+	fp := fingerprint(worktree)
+	if prev, ok := tr.worktrees[sessionName]; ok && prev.fingerprint == fp {
+		return prev.changed
+	}
+	tr.worktrees[sessionName] = worktreeState{fingerprint: fp, changed: now}
+	return now
+}
+
+// forget drops the sessions not in keep.
+func (tr *Tracker) forget(keep map[string]bool) {
+	for s := range tr.worktrees {
+		if !keep[s] {
+			delete(tr.worktrees, s)
+		}
+	}
+}
+
+// fingerprint summarizes a worktree's commit and uncommitted files, or
+// returns "" if it cannot be read.
+func fingerprint(worktree string) string {
+	snap, err := drift.Take(worktree)
+	if err != nil {
+		return ""
+	}
+	data, _ := json.Marshal(struct {
+		Head  string
+		Files map[string]drift.FileState
+	}{snap.Head, snap.Files})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Idle returns the running worker agents of the town that have been idle
+// for at least after. Paused agents, and sessions whose runtime has exited
+// to the shell, are not running.
+func (tr *Tracker) Idle(t *tmux.Tmux, townRoot string, after time.Duration, now time.Time) ([]Agent, error) {
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	pauses, err := session.LoadPauses(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var idle []Agent
+	for _, name := range sessions {
+		id, err := session.ParseSessionName(name)
+		if err != nil || (id.Role != session.RoleCrew && id.Role != session.RolePolecat) {
+			continue
+		}
+		if _, paused := pauses[name]; paused {
+			continue
+		}
+		state, err := t.GetPaneState(name)
+		if err != nil || state.Dead || slices.Contains(constants.SupportedShells, state.Command) {
+			continue
+		}
+		seen[name] = true
+		dir := "crew"
+		if id.Role == session.RolePolecat {
+			dir = "polecats"
+		}
+		a := Agent{
+			Agent:    id.Address(),
+			Session:  name,
+			Worktree: filepath.Join(townRoot, id.Rig, dir, id.Name),
+			Output:   state.Activity,
+		}
+		a.Changed = tr.LastChanged(name, a.Worktree, now)
+		if now.Sub(a.LastActive()) >= after {
+			idle = append(idle, a)
+		}
+	}
+	tr.forget(seen)
+	return idle, nil
+}
+
+// Sleep puts an idle agent to sleep and logs it.
+func Sleep(t *tmux.Tmux, townRoot string, a Agent, now time.Time) error {
+	reason := fmt.Sprintf("idle: no output or file changes for %s", now.Sub(a.LastActive()).Round(time.Minute))
+	err := session.PauseSession(t, townRoot, session.Pause{
+		Agent:    a.Agent,
+		Session:  a.Session,
+		PausedAt: now,
+		By:       PausedBy,
+		Reason:   reason,
+	})
+	if err != nil {
+		return err
+	}
+	_ = t.DisplayMessageDefault(a.Session, "Asleep ("+reason+"); wakes when work arrives or on gt resume")
+	logSleep(townRoot, a.Agent, "asleep: "+reason)
+	return nil
+}
+
+// Wake resumes the agent of a session if it is asleep, and reports
+// whether it was. why says what work arrived, e.g. "nudge from mayor".
+// Agents paused by hand or preempted are left paused.
+func Wake(t *tmux.Tmux, townRoot, sessionName, why string) (bool, error) {
+	pauses, err := session.LoadPauses(townRoot)
+	if err != nil {
+		return false, err
+	}
+	if p, ok := pauses[sessionName]; !ok || p.By != PausedBy {
+		return false, nil
+	}
+	p, err := session.ResumeSession(t, townRoot, sessionName)
+	if err != nil {
+		return false, err
+	}
+	logSleep(townRoot, p.Agent, fmt.Sprintf("woken after %s: %s", time.Since(p.PausedAt).Round(time.Second), why))
+	return true, nil
+}
+
+func logSleep(townRoot, agent, context string) {
+	_ = townlog.NewLogger(townRoot).LogEvent(townlog.Event{
+		Timestamp: time.Now(),
+		Type:      EventSleep,
+		Agent:     agent,
+		Context:   context,
+	})
+}
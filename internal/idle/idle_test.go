@@ -0,0 +1,123 @@
+package idle
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/tmux"
+)
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestLastActive(t *testing.T) {
+	output := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	changed := output.Add(time.Hour)
+	if got := (Agent{Output: output, Changed: changed}).LastActive(); !got.Equal(changed) {
+		t.Errorf("LastActive = %v, want the file change", got)
+	}
+	if got := (Agent{Output: changed, Changed: output}).LastActive(); !got.Equal(changed) {
+		t.Errorf("LastActive = %v, want the output", got)
+	}
+}
+
+func TestTrackerLastChanged(t *testing.T) {
+	dir := t.TempDir()
+	git(t, dir, "init", "-q")
+	git(t, dir, "config", "user.email", "test@test.com")
+	git(t, dir, "config", "user.name", "Test")
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-qm", "initial")
+
+	tr := NewTracker()
+	t0 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	if got := tr.LastChanged("gt-gastown-crew-max", dir, t0); !got.Equal(t0) {
+		t.Errorf("first LastChanged = %v, want now", got)
+	}
+	if got := tr.LastChanged("gt-gastown-crew-max", dir, t0.Add(time.Hour)); !got.Equal(t0) {
+		t.Errorf("LastChanged of unchanged worktree = %v, want %v", got, t0)
+	}
+
+	// Editing a file counts, and so does editing it again
+	t1 := t0.Add(2 * time.Hour)
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a // edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := tr.LastChanged("gt-gastown-crew-max", dir, t1); !got.Equal(t1) {
+		t.Errorf("LastChanged after an edit = %v, want %v", got, t1)
+	}
+	t2 := t1.Add(time.Hour)
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a // edited twice\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := tr.LastChanged("gt-gastown-crew-max", dir, t2); !got.Equal(t2) {
+		t.Errorf("LastChanged after a second edit = %v, want %v", got, t2)
+	}
+
+	// Files under .runtime/ are gt's, not the agent's work
+	if err := os.MkdirAll(filepath.Join(dir, ".runtime"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".runtime", "state.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := tr.LastChanged("gt-gastown-crew-max", dir, t2.Add(time.Hour)); !got.Equal(t2) {
+		t.Errorf("LastChanged after a .runtime write = %v, want %v", got, t2)
+	}
+
+	tr.forget(map[string]bool{})
+	if len(tr.worktrees) != 0 {
+		t.Errorf("forget kept %d worktrees", len(tr.worktrees))
+	}
+}
+
+func TestWakeOnlySleepingAgents(t *testing.T) {
+	townRoot := t.TempDir()
+	pauses := map[string]session.Pause{
+		"gt-gastown-crew-zzz-sleeper": {Agent: "gastown/crew/zzz-sleeper", Session: "gt-gastown-crew-zzz-sleeper", PausedAt: time.Now(), By: PausedBy},
+		"gt-gastown-crew-zzz-held":    {Agent: "gastown/crew/zzz-held", Session: "gt-gastown-crew-zzz-held", PausedAt: time.Now(), By: "mayor"},
+	}
+	data, err := json.Marshal(pauses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(session.PausesPath(townRoot)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(session.PausesPath(townRoot), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tm := tmux.NewTmux()
+	if woke, err := Wake(tm, townRoot, "gt-gastown-crew-zzz-held", "nudged"); err != nil || woke {
+		t.Errorf("Wake of an agent paused by hand = %v, %v; want it left paused", woke, err)
+	}
+	if woke, err := Wake(tm, townRoot, "gt-gastown-crew-zzz-sleeper", "nudged"); err != nil || !woke {
+		t.Errorf("Wake of a sleeping agent = %v, %v", woke, err)
+	}
+	if woke, err := Wake(tm, townRoot, "gt-gastown-crew-zzz-awake", "nudged"); err != nil || woke {
+		t.Errorf("Wake of an agent that is not asleep = %v, %v", woke, err)
+	}
+
+	left, err := session.LoadPauses(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := left["gt-gastown-crew-zzz-sleeper"]; ok || len(left) != 1 {
+		t.Errorf("pauses after waking = %v, want only the held agent", left)
+	}
+}
@@ -0,0 +1,325 @@
+// Package labels keeps key=value labels on agents and selects agents by
+// them, Kubernetes-style.
+//
+// Labels are set with 'gt spawn --label team=frontend' and kept in the
+// town's .runtime/agent-labels.json. A selector such as
+// "team=frontend,lang!=go" picks the agents whose labels satisfy every one
+// of its requirements; commands that take --agent also take --selector.
+package labels
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// maxLen bounds label keys and values, as in Kubernetes.
+const maxLen = 63
+
+// Path returns where a town's agent labels are kept.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "agent-labels.json")
+}
+
+// Labels are an agent's labels.
+type Labels map[string]string
+
+// String returns the labels as "k=v,k2=v2", sorted by key.
+func (l Labels) String() string {
+	return strings.Join(l.Specs(), ",")
+}
+
+// Specs returns the labels as "key=value" strings, sorted by key, as
+// Parse takes them.
+func (l Labels) Specs() []string {
+	keys := make([]string, 0, len(l))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	specs := make([]string, len(keys))
+	for i, k := range keys {
+		specs[i] = k + "=" + l[k]
+	}
+	return specs
+}
+
+// Parse parses labels given as "key=value" strings.
+func Parse(specs []string) (Labels, error) {
+	l := Labels{}
+	for _, spec := range specs {
+		k, v, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("label %q: want key=value", spec)
+		}
+		if err := validKey(k); err != nil {
+			return nil, err
+		}
+		if err := validValue(v); err != nil {
+			return nil, err
+		}
+		l[k] = v
+	}
+	return l, nil
+}
+
+// validKey checks a label key: up to 63 letters, digits, '-', '_', '.',
+// and '/', beginning and ending with a letter or digit.
+func validKey(k string) error {
+	if k == "" || !validToken(k, "-_./") {
+		return fmt.Errorf("invalid label key %q (letters, digits, '-', '_', '.', '/'; at most %d)", k, maxLen)
+	}
+	return nil
+}
+
+// validValue checks a label value: as a key, without '/', or empty.
+func validValue(v string) error {
+	if v != "" && !validToken(v, "-_.") {
+		return fmt.Errorf("invalid label value %q (letters, digits, '-', '_', '.'; at most %d)", v, maxLen)
+	}
+	return nil
+}
+
+func validToken(s, punct string) bool {
+	if len(s) > maxLen {
+		return false
+	}
+	alnum := func(r byte) bool {
+		return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+	}
+	for i := 0; i < len(s); i++ {
+		if !alnum(s[i]) && !strings.ContainsRune(punct, rune(s[i])) {
+			return false
+		}
+	}
+	return alnum(s[0]) && alnum(s[len(s)-1])
+}
+
+// Store maps agent addresses to their labels.
+type Store map[string]Labels
+
+// Of returns the agent's labels.
+func (s Store) Of(agent string) Labels {
+	key := townlog.AgentKey(agent)
+	for a, l := range s {
+		if townlog.AgentKey(a) == key {
+			return l
+		}
+	}
+	return nil
+}
+
+// Select returns the addresses of the agents sel matches, sorted.
+func (s Store) Select(sel Selector) []string {
+	var out []string
+	for a, l := range s {
+		if sel.Matches(l) {
+			out = append(out, a)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Load returns the town's agent labels.
+func Load(townRoot string) (Store, error) {
+	s := Store{}
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", Path(townRoot), err)
+	}
+	return s, nil
+}
+
+// Set replaces the agent's labels; empty labels remove its entry.
+func Set(townRoot, agent string, l Labels) error {
+	s, err := Load(townRoot)
+	if err != nil {
+		return err
+	}
+	key := townlog.AgentKey(agent)
+	for a := range s {
+		if townlog.AgentKey(a) == key {
+			delete(s, a)
+		}
+	}
+	if len(l) > 0 {
+		s[agent] = l
+	}
+	if err := os.MkdirAll(filepath.Dir(Path(townRoot)), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(Path(townRoot), s)
+}
+
+// operator is how a requirement tests a label.
+type operator string
+
+const (
+	opEquals    operator = "="
+	opNotEquals operator = "!="
+	opIn        operator = "in"
+	opNotIn     operator = "notin"
+	opExists    operator = "exists"
+	opNotExists operator = "!"
+)
+
+// requirement is one comma-separated term of a selector.
+type requirement struct {
+	key    string
+	op     operator
+	values []string
+}
+
+func (r requirement) matches(l Labels) bool {
+	v, ok := l[r.key]
+	switch r.op {
+	case opEquals:
+		return ok && v == r.values[0]
+	case opNotEquals:
+		return !ok || v != r.values[0]
+	case opIn:
+		return ok && slices.Contains(r.values, v)
+	case opNotIn:
+		return !ok || !slices.Contains(r.values, v)
+	case opExists:
+		return ok
+	case opNotExists:
+		return !ok
+	}
+	return false
+}
+
+// Selector picks agents by their labels. It matches labels that satisfy
+// every requirement; the empty selector matches everything.
+type Selector struct {
+	reqs []requirement
+	text string
+}
+
+// String returns the selector as given.
+func (s Selector) String() string {
+	return s.text
+}
+
+// Matches reports whether labels satisfy the selector.
+func (s Selector) Matches(l Labels) bool {
+	for _, r := range s.reqs {
+		if !r.matches(l) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseSelector parses a Kubernetes-style selector: comma-separated
+// requirements, each one of
+//
+//	key=value, key==value   the label is set to value
+//	key!=value              the label is not set to value (or not set)
+//	key in (a,b)            the label is set to a or b
+//	key notin (a,b)         the label is not set to a or b (or not set)
+//	key                     the label is set
+//	!key                    the label is not set
+func ParseSelector(text string) (Selector, error) {
+	sel := Selector{text: text}
+	for _, term := range splitTerms(text) {
+		r, err := parseRequirement(strings.TrimSpace(term))
+		if err != nil {
+			return Selector{}, fmt.Errorf("selector %q: %w", text, err)
+		}
+		sel.reqs = append(sel.reqs, r)
+	}
+	if len(sel.reqs) == 0 {
+		return Selector{}, fmt.Errorf("selector %q is empty", text)
+	}
+	return sel, nil
+}
+
+// splitTerms splits a selector at the commas outside parentheses.
+func splitTerms(text string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range text {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, text[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if strings.TrimSpace(text[start:]) != "" || len(terms) > 0 {
+		terms = append(terms, text[start:])
+	}
+	return terms
+}
+
+func parseRequirement(term string) (requirement, error) {
+	if term == "" {
+		return requirement{}, fmt.Errorf("empty requirement")
+	}
+	if key, ok := strings.CutPrefix(term, "!"); ok && !strings.Contains(key, "=") {
+		key = strings.TrimSpace(key)
+		return requirement{key: key, op: opNotExists}, validKey(key)
+	}
+	for _, op := range []string{"!=", "==", "="} {
+		if key, value, ok := strings.Cut(term, op); ok {
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			if err := validKey(key); err != nil {
+				return requirement{}, err
+			}
+			if err := validValue(value); err != nil {
+				return requirement{}, err
+			}
+			r := requirement{key: key, op: opEquals, values: []string{value}}
+			if op == "!=" {
+				r.op = opNotEquals
+			}
+			return r, nil
+		}
+	}
+	if fields := strings.Fields(term); len(fields) >= 2 && (fields[1] == "in" || fields[1] == "notin") {
+		key := fields[0]
+		if err := validKey(key); err != nil {
+			return requirement{}, err
+		}
+		set := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(term[len(key):]), fields[1]))
+		if !strings.HasPrefix(set, "(") || !strings.HasSuffix(set, ")") {
+			return requirement{}, fmt.Errorf("want %s %s (a,b)", key, fields[1])
+		}
+		r := requirement{key: key, op: opIn}
+		if fields[1] == "notin" {
+			r.op = opNotIn
+		}
+		for _, v := range strings.Split(set[1:len(set)-1], ",") {
+			v = strings.TrimSpace(v)
+			if err := validValue(v); err != nil {
+				return requirement{}, err
+			}
+			r.values = append(r.values, v)
+		}
+		return r, nil
+	}
+	if err := validKey(term); err != nil {
+		return requirement{}, err
+	}
+	return requirement{key: term, op: opExists}, nil
+}
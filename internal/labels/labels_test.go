@@ -0,0 +1,96 @@
+package labels
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	l, err := Parse([]string{"team=frontend", "lang=go", "gastown.io/tier=", "team=ui"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.String() != "gastown.io/tier=,lang=go,team=ui" {
+		t.Errorf("labels = %s", l)
+	}
+	for _, bad := range []string{"team", "=x", "-team=x", "team=a/b", "team=a b"} {
+		if _, err := Parse([]string{bad}); err == nil {
+			t.Errorf("Parse accepted %q", bad)
+		}
+	}
+}
+
+func TestSelector(t *testing.T) {
+	frontend := Labels{"team": "frontend", "lang": "ts"}
+	backend := Labels{"team": "backend", "lang": "go", "oncall": ""}
+	tests := []struct {
+		selector string
+		front    bool
+		back     bool
+	}{
+		{"team=frontend", true, false},
+		{"team==backend", false, true},
+		{"lang!=go", true, false},
+		{"missing!=x", true, true},
+		{"team=frontend,lang=go", false, false},
+		{"lang in (go, rust)", false, true},
+		{"team notin (backend),lang", true, false},
+		{"oncall", false, true},
+		{"!oncall", true, false},
+		{" team = backend , oncall ", false, true},
+	}
+	for _, tt := range tests {
+		sel, err := ParseSelector(tt.selector)
+		if err != nil {
+			t.Errorf("ParseSelector(%q): %v", tt.selector, err)
+			continue
+		}
+		if got := sel.Matches(frontend); got != tt.front {
+			t.Errorf("%q matches frontend = %v, want %v", tt.selector, got, tt.front)
+		}
+		if got := sel.Matches(backend); got != tt.back {
+			t.Errorf("%q matches backend = %v, want %v", tt.selector, got, tt.back)
+		}
+	}
+	for _, bad := range []string{"", "team=frontend,", "lang in go", "bad key=x", "!"} {
+		if _, err := ParseSelector(bad); err == nil {
+			t.Errorf("ParseSelector accepted %q", bad)
+		}
+	}
+}
+
+func TestSetLoadSelect(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := Set(townRoot, "gastown/crew/ui", Labels{"team": "frontend"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(townRoot, "gastown/polecats/Toast", Labels{"team": "frontend", "lang": "go"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(townRoot, "gastown/crew/api", Labels{"team": "backend"}); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Of("gastown/Toast").String(); got != "lang=go,team=frontend" {
+		t.Errorf("Of(Toast) = %s", got)
+	}
+	sel, _ := ParseSelector("team=frontend")
+	if got := s.Select(sel); len(got) != 2 || got[0] != "gastown/crew/ui" || got[1] != "gastown/polecats/Toast" {
+		t.Errorf("Select = %v", got)
+	}
+
+	// Labels are replaced, and cleared with none
+	if err := Set(townRoot, "gastown/crew/ui", Labels{"team": "backend"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(townRoot, "gastown/crew/api", nil); err != nil {
+		t.Fatal(err)
+	}
+	s, _ = Load(townRoot)
+	if got := s.Select(sel); len(got) != 1 || got[0] != "gastown/polecats/Toast" {
+		t.Errorf("Select after relabelling = %v", got)
+	}
+	if s.Of("gastown/crew/api") != nil {
+		t.Errorf("api still labelled: %v", s.Of("gastown/crew/api"))
+	}
+}
@@ -0,0 +1,224 @@
+// Package lifecycle runs the shell hooks that town and rig settings define
+// for points of an agent's life: pre_spawn before its session starts,
+// post_done once it logs done, and on_crash once it crashes. Hooks run with
+// sh -c, the town's before the rig's, and get the event's metadata in
+// GT_HOOK_* environment variables, so operators can add provisioning and
+// notifications without changing gt.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+)
+
+// Event is a point of an agent's life that hooks run at.
+type Event string
+
+const (
+	PreSpawn Event = "pre_spawn"
+	PostDone Event = "post_done"
+	OnCrash  Event = "on_crash"
+)
+
+// DefaultTimeout bounds a pre_spawn command when the settings set no
+// timeout.
+const DefaultTimeout = 5 * time.Minute
+
+// Meta describes what hooks run for.
+type Meta struct {
+	Event   Event
+	Agent   string // e.g. "gastown/crew/max"
+	Session string // the agent's tmux session, if known
+	Context string // the logged event's context, if any
+	Time    time.Time
+}
+
+// Hook is one command to run.
+type Hook struct {
+	Source  string // "town" or the rig's name
+	Command string
+}
+
+// Rig returns the rig of the agent, or "" for town agents.
+func (m Meta) Rig() string {
+	parts := strings.Split(strings.Trim(m.Agent, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0]
+}
+
+// WorkDir returns the worktree of the agent under townRoot, or "" if it
+// has none: only crew and polecats do.
+func (m Meta) WorkDir(townRoot string) string {
+	parts := strings.Split(strings.Trim(m.Agent, "/"), "/")
+	switch {
+	case len(parts) == 3 && (parts[1] == "crew" || parts[1] == "polecats"):
+		return filepath.Join(townRoot, parts[0], parts[1], parts[2])
+	case len(parts) == 2 && parts[1] != "witness" && parts[1] != "refinery":
+		return filepath.Join(townRoot, parts[0], "polecats", parts[1])
+	}
+	return ""
+}
+
+// Env returns the GT_HOOK_* variables describing m to its hooks, with the
+// town root.
+func (m Meta) Env(townRoot string) []string {
+	at := m.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return []string{
+		"GT_TOWN_ROOT=" + townRoot,
+		"GT_HOOK_EVENT=" + string(m.Event),
+		"GT_HOOK_AGENT=" + m.Agent,
+		"GT_HOOK_RIG=" + m.Rig(),
+		"GT_HOOK_SESSION=" + m.Session,
+		"GT_HOOK_WORKDIR=" + m.WorkDir(townRoot),
+		"GT_HOOK_CONTEXT=" + m.Context,
+		"GT_HOOK_TIME=" + at.UTC().Format(time.RFC3339),
+	}
+}
+
+// ForEvent returns the hooks of the town and of the agent's rig for m's
+// event, in the order they run, and the timeout of each pre_spawn command:
+// the rig's, else the town's.
+func ForEvent(townRoot string, m Meta) ([]Hook, time.Duration, error) {
+	town, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, 0, fmt.Errorf("loading town settings: %w", err)
+	}
+	var hooks []Hook
+	timeout := DefaultTimeout
+	add := func(source string, h *config.LifecycleHooks) error {
+		if h == nil {
+			return nil
+		}
+		if err := config.ValidateLifecycleHooks(h); err != nil {
+			return fmt.Errorf("%s settings: %w", source, err)
+		}
+		if h.Timeout != "" {
+			timeout, _ = time.ParseDuration(h.Timeout)
+		}
+		for _, c := range commands(h, m.Event) {
+			hooks = append(hooks, Hook{Source: source, Command: c})
+		}
+		return nil
+	}
+	if err := add("town", town.Hooks); err != nil {
+		return nil, 0, err
+	}
+	if rig := m.Rig(); rig != "" {
+		path := config.RigSettingsPath(filepath.Join(townRoot, rig))
+		settings, err := config.LoadRigSettings(path)
+		if err != nil && !errors.Is(err, config.ErrNotFound) {
+			return nil, 0, fmt.Errorf("loading %s settings: %w", rig, err)
+		}
+		if settings != nil {
+			if err := add(rig, settings.Hooks); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return hooks, timeout, nil
+}
+
+// commands returns the commands h runs at ev.
+func commands(h *config.LifecycleHooks, ev Event) []string {
+	switch ev {
+	case PreSpawn:
+		return h.PreSpawn
+	case PostDone:
+		return h.PostDone
+	case OnCrash:
+		return h.OnCrash
+	}
+	return nil
+}
+
+// Run runs the hooks for m in turn and waits for them, in the agent's
+// worktree if it exists and the town root otherwise, writing their output
+// to out. It stops at the first command that fails or times out.
+func Run(townRoot string, m Meta, out io.Writer) error {
+	hooks, timeout, err := ForEvent(townRoot, m)
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+	for _, h := range hooks {
+		_, _ = fmt.Fprintf(out, "  Running %s %s hook: %s\n", h.Source, m.Event, h.Command)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		cmd := command(ctx, townRoot, m, h)
+		cmd.Stdout = out
+		cmd.Stderr = out
+		err := cmd.Run()
+		cancel()
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("%s %s hook %q timed out after %s", h.Source, m.Event, h.Command, timeout)
+			}
+			return fmt.Errorf("%s %s hook %q: %w", h.Source, m.Event, h.Command, err)
+		}
+	}
+	return nil
+}
+
+// Start starts the hooks for m and returns without waiting for them. They
+// run in turn in a process of their own, which outlives gt, with their
+// output appended to the town's logs/hooks.log.
+func Start(townRoot string, m Meta) error {
+	hooks, _, err := ForEvent(townRoot, m)
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+	logPath := LogPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return err
+	}
+	log, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: not sensitive
+	if err != nil {
+		return fmt.Errorf("opening hook log: %w", err)
+	}
+	defer log.Close()
+	var script strings.Builder
+	for _, h := range hooks {
+		_, _ = fmt.Fprintf(log, "%s %s %s hook for %s: %s\n", time.Now().UTC().Format(time.RFC3339), h.Source, m.Event, m.Agent, h.Command)
+		// A subshell each, so one hook's exit or cd does not end the rest
+		script.WriteString("(\n" + h.Command + "\n)\n")
+	}
+	cmd := command(context.Background(), townRoot, m, Hook{Command: script.String()})
+	cmd.Stdout = log
+	cmd.Stderr = log
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting %s hooks: %w", m.Event, err)
+	}
+	return cmd.Process.Release()
+}
+
+// LogPath returns where the output of background hooks is appended.
+func LogPath(townRoot string) string {
+	return filepath.Join(townRoot, "logs", "hooks.log")
+}
+
+// command returns the command running h for m.
+func command(ctx context.Context, townRoot string, m Meta, h Hook) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.Command) //nolint:gosec // G204: command comes from the town's own settings
+	cmd.Dir = townRoot
+	if dir := m.WorkDir(townRoot); dir != "" {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			cmd.Dir = dir
+		}
+	}
+	cmd.Env = append(os.Environ(), m.Env(townRoot)...)
+	return cmd
+}
@@ -0,0 +1,121 @@
+package lifecycle
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+)
+
+func writeHooks(t *testing.T, path string, settings any) {
+	t.Helper()
+	data, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMeta(t *testing.T) {
+	tests := []struct {
+		agent, rig, workDir string
+	}{
+		{"gastown/crew/max", "gastown", "gastown/crew/max"},
+		{"gastown/polecats/Toast", "gastown", "gastown/polecats/Toast"},
+		{"gastown/Toast", "gastown", "gastown/polecats/Toast"},
+		{"gastown/witness", "gastown", ""},
+		{"mayor", "", ""},
+	}
+	for _, tt := range tests {
+		m := Meta{Agent: tt.agent}
+		want := tt.workDir
+		if want != "" {
+			want = filepath.Join("/town", want)
+		}
+		if m.Rig() != tt.rig || m.WorkDir("/town") != want {
+			t.Errorf("%s: Rig() = %q, WorkDir() = %q; want %q, %q", tt.agent, m.Rig(), m.WorkDir("/town"), tt.rig, want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	town := t.TempDir()
+	writeHooks(t, config.TownSettingsPath(town), map[string]any{
+		"hooks": config.LifecycleHooks{PreSpawn: []string{`echo "town $GT_HOOK_EVENT $GT_HOOK_AGENT $GT_HOOK_SESSION"`}},
+	})
+	rigPath := filepath.Join(town, "gastown")
+	writeHooks(t, config.RigSettingsPath(rigPath), map[string]any{
+		"hooks": config.LifecycleHooks{PreSpawn: []string{"echo rig in $(basename $PWD)"}, OnCrash: []string{"true"}},
+	})
+	if err := os.MkdirAll(filepath.Join(rigPath, "crew", "max"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	m := Meta{Event: PreSpawn, Agent: "gastown/crew/max", Session: "gt-gastown-crew-max", Time: time.Now()}
+	hooks, timeout, err := ForEvent(town, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hooks) != 2 || hooks[0].Source != "town" || hooks[1].Source != "gastown" || timeout != DefaultTimeout {
+		t.Fatalf("ForEvent = %+v, %s", hooks, timeout)
+	}
+	var out bytes.Buffer
+	if err := Run(town, m, &out); err != nil {
+		t.Fatalf("Run: %v\n%s", err, out.String())
+	}
+	for _, want := range []string{"town pre_spawn gastown/crew/max gt-gastown-crew-max", "rig in max"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	// Another rig's agents get the town's hooks only
+	if hooks, _, _ := ForEvent(town, Meta{Event: PreSpawn, Agent: "infra/crew/ada"}); len(hooks) != 1 {
+		t.Errorf("infra hooks = %+v, want the town's", hooks)
+	}
+
+	// A failing hook stops the rest
+	writeHooks(t, config.RigSettingsPath(rigPath), map[string]any{
+		"hooks": config.LifecycleHooks{PreSpawn: []string{"exit 3", "echo unreached"}, Timeout: "1m"},
+	})
+	out.Reset()
+	if err := Run(town, m, &out); err == nil || strings.Contains(out.String(), "unreached\n") {
+		t.Errorf("Run with a failing hook = %v:\n%s", err, out.String())
+	}
+}
+
+func TestStart(t *testing.T) {
+	town := t.TempDir()
+	marker := filepath.Join(town, "crashed")
+	writeHooks(t, config.TownSettingsPath(town), map[string]any{
+		"hooks": config.LifecycleHooks{OnCrash: []string{"exit 1", `echo "$GT_HOOK_CONTEXT" > ` + marker}},
+	})
+	if err := Start(town, Meta{Event: OnCrash, Agent: "gastown/crew/max", Context: "exit 137"}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := os.ReadFile(marker)
+		if err == nil && strings.TrimSpace(string(data)) == "exit 137" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("on_crash hook did not run after a failing one: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	log, err := os.ReadFile(LogPath(town))
+	if err != nil || !strings.Contains(string(log), "town on_crash hook for gastown/crew/max: exit 1") {
+		t.Errorf("hook log = %q, %v", log, err)
+	}
+}
@@ -100,6 +100,7 @@ func (m *Mailbox) listBeads() ([]*Message, error) {
 	if err != nil {
 		return nil, err
 	}
+	messages = m.expireStale(messages)
 
 	// Sort by timestamp (newest first)
 	sort.Slice(messages, func(i, j int) bool {
@@ -150,6 +151,25 @@ func (m *Mailbox) listFromDir(beadsDir string) ([]*Message, error) { //nolint:un
 	return messages, nil
 }
 
+// expireStale closes unread messages past their TTL and returns the rest.
+// Closing is best-effort: a message that cannot be closed is still hidden.
+func (m *Mailbox) expireStale(messages []*Message) []*Message {
+	now := timeNow()
+	kept := messages[:0]
+	var expired []string
+	for _, msg := range messages {
+		if !msg.Expired(now) {
+			kept = append(kept, msg)
+			continue
+		}
+		if m.closeInDir(msg.ID, m.beadsDir) == nil {
+			expired = append(expired, msg.ID)
+		}
+	}
+	recordReceipts(detectTownRoot(m.workDir), ReceiptExpired, expired...)
+	return kept
+}
+
 // identityVariants returns all identity formats to query.
 // For town-level agents (mayor/, deacon/), also includes the variant without
 // trailing slash for backwards compatibility with legacy messages.
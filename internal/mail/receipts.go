@@ -0,0 +1,186 @@
+package mail
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ReceiptState is how far a message got toward its recipient.
+type ReceiptState string
+
+// Receipt states, in delivery order. A later state implies the earlier ones.
+const (
+	// ReceiptSent means the message is in the recipient's mailbox.
+	ReceiptSent ReceiptState = "sent"
+
+	// ReceiptNotified means a banner was shown in the recipient's session.
+	ReceiptNotified ReceiptState = "notified"
+
+	// ReceiptInjected means the message was listed in the recipient's
+	// context by the mail check hook.
+	ReceiptInjected ReceiptState = "injected"
+
+	// ReceiptRead means the recipient opened, archived, or deleted it.
+	ReceiptRead ReceiptState = "read"
+
+	// ReceiptExpired means the message passed its TTL unread and was closed.
+	ReceiptExpired ReceiptState = "expired"
+)
+
+// receiptRank orders states so the furthest one wins.
+var receiptRank = map[ReceiptState]int{
+	ReceiptSent:     1,
+	ReceiptNotified: 2,
+	ReceiptInjected: 3,
+	ReceiptRead:     4,
+	ReceiptExpired:  4,
+}
+
+// Receipt records one delivery step of a message.
+type Receipt struct {
+	MessageID string       `json:"message_id"`
+	State     ReceiptState `json:"state"`
+	Time      time.Time    `json:"time"`
+
+	// Set on the sent receipt only.
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to,omitempty"`
+	Subject   string    `json:"subject,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// ReceiptsPath returns the town's receipt log.
+func ReceiptsPath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "mail-receipts.jsonl")
+}
+
+// RecordReceipt appends a receipt to the town's receipt log. Each receipt
+// is a single small append, so concurrent writers do not interleave.
+func RecordReceipt(townRoot string, r Receipt) error {
+	if townRoot == "" || r.MessageID == "" {
+		return nil
+	}
+	if r.Time.IsZero() {
+		r.Time = timeNow()
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	path := ReceiptsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating receipts directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: receipts are not secret
+	if err != nil {
+		return fmt.Errorf("opening receipts: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// recordReceipts records the same state for several messages, best-effort.
+func recordReceipts(townRoot string, state ReceiptState, ids ...string) {
+	for _, id := range ids {
+		_ = RecordReceipt(townRoot, Receipt{MessageID: id, State: state})
+	}
+}
+
+// RecordInjected marks messages as shown to their recipient by a hook.
+func RecordInjected(townRoot string, ids ...string) {
+	recordReceipts(townRoot, ReceiptInjected, ids...)
+}
+
+// RecordRead marks messages as read by their recipient.
+func RecordRead(townRoot string, ids ...string) {
+	recordReceipts(townRoot, ReceiptRead, ids...)
+}
+
+// ReadReceipts returns every receipt in the town's log, oldest first.
+func ReadReceipts(townRoot string) ([]Receipt, error) {
+	f, err := os.Open(ReceiptsPath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var receipts []Receipt
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Receipt
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue // Skip malformed lines
+		}
+		receipts = append(receipts, r)
+	}
+	return receipts, scanner.Err()
+}
+
+// DeliveryStatus is the folded receipt history of one message.
+type DeliveryStatus struct {
+	MessageID string                     `json:"message_id"`
+	From      string                     `json:"from"`
+	To        string                     `json:"to"`
+	Subject   string                     `json:"subject"`
+	SentAt    time.Time                  `json:"sent_at"`
+	ExpiresAt time.Time                  `json:"expires_at,omitzero"`
+	State     ReceiptState               `json:"state"`
+	Times     map[ReceiptState]time.Time `json:"times"` // first time each state was reached
+}
+
+// Delivered reports whether the recipient saw the message.
+func (d DeliveryStatus) Delivered() bool {
+	return d.State == ReceiptInjected || d.State == ReceiptRead
+}
+
+// Deliveries folds receipts into one status per sent message, newest first.
+// Unread messages past their expiry show as expired even before the
+// recipient's mailbox has closed them. Receipts for messages with no sent
+// record (mail sent before receipts existed) are ignored.
+func Deliveries(receipts []Receipt, now time.Time) []DeliveryStatus {
+	byID := make(map[string]*DeliveryStatus)
+	var order []string
+	for _, r := range receipts {
+		if r.State == ReceiptSent {
+			if _, ok := byID[r.MessageID]; !ok {
+				byID[r.MessageID] = &DeliveryStatus{
+					MessageID: r.MessageID,
+					Times:     make(map[ReceiptState]time.Time),
+				}
+				order = append(order, r.MessageID)
+			}
+			d := byID[r.MessageID]
+			d.From, d.To, d.Subject, d.SentAt, d.ExpiresAt = r.From, r.To, r.Subject, r.Time, r.ExpiresAt
+		}
+		d, ok := byID[r.MessageID]
+		if !ok {
+			continue
+		}
+		if _, seen := d.Times[r.State]; !seen {
+			d.Times[r.State] = r.Time
+		}
+		if receiptRank[r.State] > receiptRank[d.State] {
+			d.State = r.State
+		}
+	}
+
+	statuses := make([]DeliveryStatus, 0, len(order))
+	for _, id := range order {
+		d := byID[id]
+		if d.State != ReceiptRead && !d.ExpiresAt.IsZero() && now.After(d.ExpiresAt) {
+			d.State = ReceiptExpired
+		}
+		statuses = append(statuses, *d)
+	}
+	sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].SentAt.After(statuses[j].SentAt) })
+	return statuses
+}
@@ -0,0 +1,70 @@
+package mail
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeliveries(t *testing.T) {
+	townRoot := t.TempDir()
+	sent := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	record := func(r Receipt) {
+		t.Helper()
+		if err := RecordReceipt(townRoot, r); err != nil {
+			t.Fatalf("RecordReceipt() error: %v", err)
+		}
+	}
+	record(Receipt{MessageID: "hq-1", State: ReceiptSent, Time: sent, From: "mayor/", To: "gastown/Toast", Subject: "Go"})
+	record(Receipt{MessageID: "hq-1", State: ReceiptNotified, Time: sent.Add(time.Second)})
+	record(Receipt{MessageID: "hq-1", State: ReceiptRead, Time: sent.Add(time.Minute)})
+	record(Receipt{MessageID: "hq-1", State: ReceiptInjected, Time: sent.Add(2 * time.Minute)}) // later hook run
+	record(Receipt{MessageID: "hq-2", State: ReceiptSent, Time: sent.Add(time.Hour), ExpiresAt: sent.Add(2 * time.Hour)})
+	record(Receipt{MessageID: "hq-old", State: ReceiptRead, Time: sent}) // no sent record
+
+	receipts, err := ReadReceipts(townRoot)
+	if err != nil {
+		t.Fatalf("ReadReceipts() error: %v", err)
+	}
+
+	got := Deliveries(receipts, sent.Add(90*time.Minute))
+	if len(got) != 2 {
+		t.Fatalf("Deliveries() returned %d statuses, want 2", len(got))
+	}
+	if got[0].MessageID != "hq-2" || got[0].State != ReceiptSent {
+		t.Errorf("newest = %s %s, want hq-2 sent", got[0].MessageID, got[0].State)
+	}
+	if got[1].State != ReceiptRead || !got[1].Delivered() {
+		t.Errorf("hq-1 state = %s, want read", got[1].State)
+	}
+	if got[1].Times[ReceiptRead] != sent.Add(time.Minute) {
+		t.Errorf("hq-1 read at %v", got[1].Times[ReceiptRead])
+	}
+
+	// Past its expiry, unread mail shows as expired.
+	got = Deliveries(receipts, sent.Add(3*time.Hour))
+	if got[0].State != ReceiptExpired {
+		t.Errorf("hq-2 state after expiry = %s, want expired", got[0].State)
+	}
+	if got[1].State != ReceiptRead {
+		t.Errorf("read mail must not expire, got %s", got[1].State)
+	}
+}
+
+func TestMessageExpiryLabel(t *testing.T) {
+	expires := time.Unix(1767348000, 0)
+	msg := &Message{ExpiresAt: expires}
+
+	bm := BeadsMessage{ID: "hq-ttl", Status: "open", Labels: []string{"from:mayor/", msg.expiryLabel()}}
+	got := bm.ToMessage()
+	if !got.ExpiresAt.Equal(expires) {
+		t.Fatalf("ExpiresAt = %v, want %v", got.ExpiresAt, expires)
+	}
+	if got.Expired(expires.Add(-time.Second)) || !got.Expired(expires.Add(time.Second)) {
+		t.Error("Expired() should flip at ExpiresAt")
+	}
+	got.Read = true
+	if got.Expired(expires.Add(time.Hour)) {
+		t.Error("read mail must not expire")
+	}
+}
@@ -9,8 +9,10 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/idle"
 	"github.com/ctiospl/gastown/internal/session"
 	"github.com/ctiospl/gastown/internal/tmux"
 )
@@ -518,6 +520,15 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 // - Queues (queue:name) - stores single message for worker claiming
 // - Announces (announce:name) - bulletin board, no claiming, retention-limited
 func (r *Router) Send(msg *Message) error {
+	// Apply the town's default TTL to mail without an explicit expiry.
+	// Pinned mail and handoffs to self are context, not coordination, and
+	// never expire by default.
+	if msg.ExpiresAt.IsZero() && !msg.Pinned && !isSelfMail(msg.From, msg.To) {
+		if ttl := r.defaultTTL(); ttl > 0 {
+			msg.ExpiresAt = timeNow().Add(ttl)
+		}
+	}
+
 	// Check for mailing list address
 	if isListAddress(msg.To) {
 		return r.sendToList(msg)
@@ -582,7 +593,7 @@ func (r *Router) sendToSingle(msg *Message) error {
 	// Convert addresses to beads identities
 	toIdentity := addressToIdentity(msg.To)
 
-	// Build labels for from/thread/reply-to/cc
+	// Build labels for from/thread/reply-to/cc/attachments
 	var labels []string
 	labels = append(labels, "from:"+msg.From)
 	if msg.ThreadID != "" {
@@ -596,6 +607,12 @@ func (r *Router) sendToSingle(msg *Message) error {
 		ccIdentity := addressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	for _, a := range msg.Attachments {
+		labels = append(labels, a.label())
+	}
+	if !msg.ExpiresAt.IsZero() {
+		labels = append(labels, msg.expiryLabel())
+	}
 
 	// Build command: bd create <subject> --type=message --assignee=<recipient> -d <body>
 	args := []string{"create", msg.Subject,
@@ -621,6 +638,9 @@ func (r *Router) sendToSingle(msg *Message) error {
 		args = append(args, "--ephemeral")
 	}
 
+	// JSON output gives us the message ID for delivery receipts
+	args = append(args, "--json")
+
 	beadsDir := r.resolveBeadsDir(msg.To)
 	cmd := exec.Command("bd", args...) //nolint:gosec // G204: bd is a trusted internal tool
 	cmd.Env = append(cmd.Environ(),
@@ -628,7 +648,8 @@ func (r *Router) sendToSingle(msg *Message) error {
 	)
 	cmd.Dir = filepath.Dir(beadsDir) // Run in parent of .beads
 
-	var stderr bytes.Buffer
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
@@ -639,10 +660,27 @@ func (r *Router) sendToSingle(msg *Message) error {
 		return fmt.Errorf("sending message: %w", err)
 	}
 
+	var created struct {
+		ID string `json:"id"`
+	}
+	if json.Unmarshal(stdout.Bytes(), &created) == nil && created.ID != "" {
+		msg.ID = created.ID
+	}
+	_ = RecordReceipt(r.townRoot, Receipt{
+		MessageID: msg.ID,
+		State:     ReceiptSent,
+		From:      msg.From,
+		To:        msg.To,
+		Subject:   msg.Subject,
+		ExpiresAt: msg.ExpiresAt,
+	})
+
 	// Notify recipient if they have an active session (best-effort notification)
 	// Skip notification for self-mail (handoffs to future-self don't need present-self notified)
 	if !isSelfMail(msg.From, msg.To) {
-		_ = r.notifyRecipient(msg)
+		if state, err := r.notifyRecipient(msg); err == nil && state != "" {
+			_ = RecordReceipt(r.townRoot, Receipt{MessageID: msg.ID, State: state})
+		}
 	}
 
 	return nil
@@ -704,7 +742,7 @@ func (r *Router) sendToQueue(msg *Message) error {
 		return err
 	}
 
-	// Build labels for from/thread/reply-to/cc plus queue metadata
+	// Build labels for from/thread/reply-to/cc/attachments plus queue metadata
 	var labels []string
 	labels = append(labels, "from:"+msg.From)
 	labels = append(labels, "queue:"+queueName)
@@ -718,6 +756,12 @@ func (r *Router) sendToQueue(msg *Message) error {
 		ccIdentity := addressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	for _, a := range msg.Attachments {
+		labels = append(labels, a.label())
+	}
+	if !msg.ExpiresAt.IsZero() {
+		labels = append(labels, msg.expiryLabel())
+	}
 
 	// Build command: bd create <subject> --type=message --assignee=queue:<name> -d <body>
 	// Use queue:<name> as assignee so inbox queries can filter by queue
@@ -787,7 +831,7 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 		}
 	}
 
-	// Build labels for from/thread/reply-to/cc plus announce metadata
+	// Build labels for from/thread/reply-to/cc/attachments plus announce metadata
 	var labels []string
 	labels = append(labels, "from:"+msg.From)
 	labels = append(labels, "announce:"+announceName)
@@ -801,6 +845,12 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 		ccIdentity := addressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	for _, a := range msg.Attachments {
+		labels = append(labels, a.label())
+	}
+	if !msg.ExpiresAt.IsZero() {
+		labels = append(labels, msg.expiryLabel())
+	}
 
 	// Build command: bd create <subject> --type=message --assignee=announce:<name> -d <body>
 	// Use announce:<name> as assignee so queries can filter by channel
@@ -931,23 +981,76 @@ func (r *Router) GetMailbox(address string) (*Mailbox, error) {
 	return NewMailboxFromAddress(address, workDir), nil
 }
 
-// notifyRecipient sends a notification to a recipient's tmux session.
-// Uses send-keys to echo a visible banner to ensure notification is seen.
-// Supports mayor/, rig/polecat, and rig/refinery addresses.
-func (r *Router) notifyRecipient(msg *Message) error {
+// notifyRecipient sends a notification to a recipient's tmux session and
+// returns the delivery state it reached ("" if nothing was shown).
+//
+// Priority decides how loud the notification is: low-priority mail shows no
+// banner and waits for the recipient's next mail check, normal mail shows a
+// banner, and urgent mail wakes the recipient if it is asleep and is also
+// injected into the session as a nudge. Only urgent mail is announced while
+// the recipient is in a scheduled DND window.
+func (r *Router) notifyRecipient(msg *Message) (ReceiptState, error) {
+	if msg.Priority.Batched() {
+		return "", nil
+	}
+	if !msg.Priority.Interrupts() && config.ActiveDNDWindow(r.townRoot, msg.To) != nil {
+		return "", nil // Recipient is in a DND window; mail check picks it up later
+	}
 	sessionID := addressToSessionID(msg.To)
 	if sessionID == "" {
-		return nil // Unable to determine session ID
+		return "", nil // Unable to determine session ID
 	}
 
 	// Check if session exists
 	hasSession, err := r.tmux.HasSession(sessionID)
-	if err != nil || !hasSession {
-		return nil // No active session, skip notification
+	if err != nil {
+		return "", nil
+	}
+	if !hasSession {
+		if !msg.Priority.Interrupts() {
+			return "", nil // No active session, skip notification
+		}
+		// The woken agent reads its mail on startup
+		if _, err := session.Wake(r.tmux, sessionID); err != nil {
+			return "", err
+		}
+		return "", nil
+	}
+
+	// Mail is work: wake the recipient if it was put to sleep for being idle
+	if r.townRoot != "" {
+		_, _ = idle.Wake(r.tmux, r.townRoot, sessionID, "mail from "+msg.From)
 	}
 
 	// Send visible notification banner to the terminal
-	return r.tmux.SendNotificationBanner(sessionID, msg.From, msg.Subject)
+	if err := r.tmux.SendNotificationBanner(sessionID, msg.From, msg.Subject); err != nil {
+		return "", err
+	}
+	if !msg.Priority.Interrupts() {
+		return ReceiptNotified, nil
+	}
+	readCmd := "gt mail inbox"
+	if msg.ID != "" {
+		readCmd = "gt mail read " + msg.ID
+	}
+	nudge := fmt.Sprintf("[URGENT mail from %s] %s - run: %s", msg.From, msg.Subject, readCmd)
+	if err := r.tmux.NudgeSession(sessionID, nudge); err != nil {
+		return ReceiptNotified, nil
+	}
+	return ReceiptInjected, nil
+}
+
+// defaultTTL returns the town's default mail TTL, or zero for none.
+func (r *Router) defaultTTL() time.Duration {
+	if r.townRoot == "" {
+		return 0
+	}
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(r.townRoot))
+	if err != nil || cfg.DefaultTTL == "" {
+		return 0
+	}
+	ttl, _ := time.ParseDuration(cfg.DefaultTTL)
+	return ttl
 }
 
 // addressToSessionID converts a mail address to a tmux session ID.
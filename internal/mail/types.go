@@ -4,6 +4,9 @@ package mail
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -106,6 +109,62 @@ type Message struct {
 	// CC contains addresses that should receive a copy of this message.
 	// CC'd recipients see the message in their inbox but are not the primary recipient.
 	CC []string `json:"cc,omitempty"`
+
+	// Attachments reference files in the town's artifact store.
+	Attachments []Attachment `json:"attachments,omitempty"`
+
+	// ExpiresAt is when the message expires if still unread (zero: never).
+	// Expired mail is closed when the recipient's mailbox is next listed.
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// Expired reports whether the message is unread past its expiry.
+func (m *Message) Expired(now time.Time) bool {
+	return !m.Read && !m.ExpiresAt.IsZero() && now.After(m.ExpiresAt)
+}
+
+// expiryLabel encodes the expiry as a beads label ("expires:<unix seconds>").
+func (m *Message) expiryLabel() string {
+	return "expires:" + strconv.FormatInt(m.ExpiresAt.Unix(), 10)
+}
+
+// Attachment is a file sent with a message. The content lives in the
+// artifact store under ID; only the reference travels with the message.
+type Attachment struct {
+	// ID is the artifact ID (content hash).
+	ID string `json:"id"`
+
+	// Name is the file's base name, sanitized for use in labels.
+	Name string `json:"name"`
+}
+
+// NewAttachment builds an attachment reference, reducing name to a base
+// name made of characters that are safe in a beads label.
+func NewAttachment(id, name string) Attachment {
+	name = filepath.Base(name)
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	return Attachment{ID: id, Name: safe}
+}
+
+// label encodes the attachment as a beads label ("attachment:<id>:<name>").
+func (a Attachment) label() string {
+	return "attachment:" + a.ID + ":" + a.Name
+}
+
+// parseAttachmentLabel decodes the value of an attachment label.
+func parseAttachmentLabel(value string) (Attachment, bool) {
+	id, name, ok := strings.Cut(value, ":")
+	if !ok || id == "" {
+		return Attachment{}, false
+	}
+	return Attachment{ID: id, Name: name}, true
 }
 
 // NewMessage creates a new message with a generated ID and thread ID.
@@ -141,6 +200,55 @@ func NewReplyMessage(from, to, subject, body string, original *Message) *Message
 	}
 }
 
+// ThreadEntry is a message in a thread with its reply depth.
+type ThreadEntry struct {
+	Message *Message
+	Depth   int // 0 for messages that start the thread or whose parent is missing
+}
+
+// ThreadTree orders a thread's messages as a reply tree: each message is
+// followed by its replies, oldest first, with Depth giving the nesting.
+func ThreadTree(messages []*Message) []ThreadEntry {
+	byID := make(map[string]bool, len(messages))
+	for _, m := range messages {
+		byID[m.ID] = true
+	}
+	sorted := append([]*Message(nil), messages...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	children := make(map[string][]*Message)
+	var roots []*Message
+	for _, m := range sorted {
+		if m.ReplyTo != "" && m.ReplyTo != m.ID && byID[m.ReplyTo] {
+			children[m.ReplyTo] = append(children[m.ReplyTo], m)
+		} else {
+			roots = append(roots, m)
+		}
+	}
+
+	entries := make([]ThreadEntry, 0, len(messages))
+	visited := make(map[string]bool, len(messages))
+	var walk func(m *Message, depth int)
+	walk = func(m *Message, depth int) {
+		if visited[m.ID] {
+			return
+		}
+		visited[m.ID] = true
+		entries = append(entries, ThreadEntry{Message: m, Depth: depth})
+		for _, c := range children[m.ID] {
+			walk(c, depth+1)
+		}
+	}
+	for _, m := range roots {
+		walk(m, 0)
+	}
+	// Reply cycles have no root; list them flat rather than dropping them.
+	for _, m := range sorted {
+		walk(m, 0)
+	}
+	return entries
+}
+
 // generateID creates a random message ID.
 func generateID() string {
 	b := make([]byte, 8)
@@ -170,11 +278,13 @@ type BeadsMessage struct {
 	Wisp        bool      `json:"wisp,omitempty"` // Ephemeral message (filtered from JSONL export)
 
 	// Cached parsed values (populated by ParseLabels)
-	sender   string
-	threadID string
-	replyTo  string
-	msgType  string
-	cc       []string // CC recipients
+	sender      string
+	threadID    string
+	replyTo     string
+	msgType     string
+	cc          []string // CC recipients
+	attachments []Attachment
+	expiresAt   time.Time
 }
 
 // ParseLabels extracts metadata from the labels array.
@@ -190,6 +300,14 @@ func (bm *BeadsMessage) ParseLabels() {
 			bm.msgType = strings.TrimPrefix(label, "msg-type:")
 		} else if strings.HasPrefix(label, "cc:") {
 			bm.cc = append(bm.cc, strings.TrimPrefix(label, "cc:"))
+		} else if strings.HasPrefix(label, "attachment:") {
+			if a, ok := parseAttachmentLabel(strings.TrimPrefix(label, "attachment:")); ok {
+				bm.attachments = append(bm.attachments, a)
+			}
+		} else if strings.HasPrefix(label, "expires:") {
+			if secs, err := strconv.ParseInt(strings.TrimPrefix(label, "expires:"), 10, 64); err == nil {
+				bm.expiresAt = time.Unix(secs, 0)
+			}
 		}
 	}
 }
@@ -241,19 +359,21 @@ func (bm *BeadsMessage) ToMessage() *Message {
 	}
 
 	return &Message{
-		ID:        bm.ID,
-		From:      identityToAddress(bm.sender),
-		To:        identityToAddress(bm.Assignee),
-		Subject:   bm.Title,
-		Body:      bm.Description,
-		Timestamp: bm.CreatedAt,
-		Read:      bm.Status == "closed",
-		Priority:  priority,
-		Type:      msgType,
-		ThreadID:  bm.threadID,
-		ReplyTo:   bm.replyTo,
-		Wisp:      bm.Wisp,
-		CC:        ccAddrs,
+		ID:          bm.ID,
+		From:        identityToAddress(bm.sender),
+		To:          identityToAddress(bm.Assignee),
+		Subject:     bm.Title,
+		Body:        bm.Description,
+		Timestamp:   bm.CreatedAt,
+		Read:        bm.Status == "closed",
+		Priority:    priority,
+		Type:        msgType,
+		ThreadID:    bm.threadID,
+		ReplyTo:     bm.replyTo,
+		Wisp:        bm.Wisp,
+		CC:          ccAddrs,
+		Attachments: bm.attachments,
+		ExpiresAt:   bm.expiresAt,
 	}
 }
 
@@ -272,6 +392,27 @@ func PriorityToBeads(p Priority) int {
 	}
 }
 
+// Interrupts reports whether mail at this priority interrupts the
+// recipient: it is injected into the session at once, waking the agent if
+// it is asleep.
+func (p Priority) Interrupts() bool {
+	return p == PriorityUrgent
+}
+
+// Batched reports whether mail at this priority skips the arrival banner
+// and waits for the recipient's next natural wake (the mail check hook).
+func (p Priority) Batched() bool {
+	return p == PriorityLow
+}
+
+// SortByPriority orders messages most urgent first, keeping the existing
+// order within each priority.
+func SortByPriority(messages []*Message) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return PriorityToBeads(messages[i].Priority) < PriorityToBeads(messages[j].Priority)
+	})
+}
+
 // ParsePriority parses a priority string, returning PriorityNormal for invalid values.
 func ParsePriority(s string) Priority {
 	switch Priority(s) {
@@ -1,6 +1,8 @@
 package mail
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -137,6 +139,33 @@ func TestParsePriority(t *testing.T) {
 	}
 }
 
+func TestPriorityDelivery(t *testing.T) {
+	if !PriorityUrgent.Interrupts() || PriorityHigh.Interrupts() {
+		t.Error("only urgent mail should interrupt")
+	}
+	if !PriorityLow.Batched() || PriorityNormal.Batched() {
+		t.Error("only low mail should be batched")
+	}
+}
+
+func TestSortByPriority(t *testing.T) {
+	messages := []*Message{
+		{ID: "a", Priority: PriorityLow},
+		{ID: "b", Priority: PriorityNormal},
+		{ID: "c", Priority: PriorityUrgent},
+		{ID: "d", Priority: PriorityNormal},
+	}
+	SortByPriority(messages)
+
+	var got []string
+	for _, m := range messages {
+		got = append(got, m.ID)
+	}
+	if strings.Join(got, "") != "cbda" {
+		t.Errorf("SortByPriority order = %v, want [c b d a]", got)
+	}
+}
+
 func TestParseMessageType(t *testing.T) {
 	tests := []struct {
 		s        string
@@ -349,3 +378,42 @@ func TestBeadsMessageToMessageEmptyLabels(t *testing.T) {
 		t.Errorf("ThreadID should be empty, got %q", msg.ThreadID)
 	}
 }
+
+func TestBeadsMessageToMessageAttachments(t *testing.T) {
+	a := NewAttachment("4f2a9c", "/tmp/out/fix, final.diff")
+	if a.Name != "fix__final.diff" {
+		t.Fatalf("NewAttachment name = %q, want 'fix__final.diff'", a.Name)
+	}
+
+	bm := BeadsMessage{
+		ID:     "hq-att",
+		Labels: []string{"from:mayor/", a.label(), "attachment:bad"},
+	}
+	msg := bm.ToMessage()
+	if len(msg.Attachments) != 1 {
+		t.Fatalf("Attachments = %v, want 1", msg.Attachments)
+	}
+	if msg.Attachments[0] != a {
+		t.Errorf("Attachment = %+v, want %+v", msg.Attachments[0], a)
+	}
+}
+
+func TestThreadTree(t *testing.T) {
+	base := time.Now()
+	msgs := []*Message{
+		{ID: "m3", ReplyTo: "m1", Timestamp: base.Add(3 * time.Minute)},
+		{ID: "m1", Timestamp: base},
+		{ID: "m2", ReplyTo: "m1", Timestamp: base.Add(time.Minute)},
+		{ID: "m4", ReplyTo: "m2", Timestamp: base.Add(4 * time.Minute)},
+		{ID: "m5", ReplyTo: "gone", Timestamp: base.Add(5 * time.Minute)},
+	}
+
+	var got []string
+	for _, e := range ThreadTree(msgs) {
+		got = append(got, fmt.Sprintf("%s@%d", e.Message.ID, e.Depth))
+	}
+	want := "m1@0 m2@1 m4@2 m3@1 m5@0"
+	if strings.Join(got, " ") != want {
+		t.Errorf("ThreadTree() = %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,182 @@
+// Package metrics reads the per-agent metrics file: a small JSON document an
+// agent (or a shim around its runtime) writes into its worktree to report
+// progress, state, and usage. Any runtime that can write a file can report
+// structured state this way; gt ingests it into status, progress, and cost
+// views. See docs/metrics-contract.md for the contract.
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// Filename is the metrics file name within the worktree's .runtime directory.
+const Filename = "metrics.json"
+
+// Version is the contract version this package understands.
+const Version = 1
+
+// StaleAfter is how long a metrics file stays current without an update.
+// Older files are still shown, but marked stale.
+const StaleAfter = 15 * time.Minute
+
+// Metrics is the content of an agent's metrics file. Every field is
+// optional; writers report what they know.
+type Metrics struct {
+	// Version is the contract version the writer targets.
+	Version int `json:"version"`
+
+	// Updated is when the writer last refreshed the file. If omitted, the
+	// file's modification time is used.
+	Updated time.Time `json:"updated,omitempty"`
+
+	// State is a short free-form state, e.g. "working", "testing", "blocked".
+	State string `json:"state,omitempty"`
+
+	// Message is a one-line description of what the agent is doing.
+	Message string `json:"message,omitempty"`
+
+	// Progress reports how far through its work the agent is.
+	Progress *Progress `json:"progress,omitempty"`
+
+	// Tokens reports cumulative token usage for the session.
+	Tokens *Tokens `json:"tokens,omitempty"`
+
+	// CostUSD is the cumulative session cost. Nil means unknown, which is
+	// distinct from zero.
+	CostUSD *float64 `json:"cost_usd,omitempty"`
+
+	// Counters holds arbitrary named numbers, e.g. "tests_passed".
+	Counters map[string]float64 `json:"counters,omitempty"`
+}
+
+// Progress is either a count (Done of Total) or a Percent.
+type Progress struct {
+	Done    int     `json:"done,omitempty"`
+	Total   int     `json:"total,omitempty"`
+	Percent float64 `json:"percent,omitempty"`
+	Label   string  `json:"label,omitempty"` // what is being counted, e.g. "steps"
+}
+
+// Tokens is cumulative token usage.
+type Tokens struct {
+	Input      int64 `json:"input,omitempty"`
+	Output     int64 `json:"output,omitempty"`
+	CacheRead  int64 `json:"cache_read,omitempty"`
+	CacheWrite int64 `json:"cache_write,omitempty"`
+}
+
+// Total returns the sum of all token counts.
+func (t *Tokens) Total() int64 {
+	return t.Input + t.Output + t.CacheRead + t.CacheWrite
+}
+
+// Fraction returns progress in [0, 1], or -1 if unknown.
+func (p *Progress) Fraction() float64 {
+	switch {
+	case p.Total > 0:
+		return min(float64(p.Done)/float64(p.Total), 1)
+	case p.Percent > 0:
+		return min(p.Percent/100, 1)
+	default:
+		return -1
+	}
+}
+
+// String renders progress as "3/7 steps (43%)" or "43%".
+func (p *Progress) String() string {
+	f := p.Fraction()
+	if f < 0 {
+		return p.Label
+	}
+	pct := fmt.Sprintf("%.0f%%", f*100)
+	if p.Total > 0 {
+		s := fmt.Sprintf("%d/%d", p.Done, p.Total)
+		if p.Label != "" {
+			s += " " + p.Label
+		}
+		return s + " (" + pct + ")"
+	}
+	if p.Label != "" {
+		return pct + " " + p.Label
+	}
+	return pct
+}
+
+// Stale reports whether the file has not been updated within StaleAfter.
+func (m *Metrics) Stale(now time.Time) bool {
+	return now.Sub(m.Updated) > StaleAfter
+}
+
+// Summary renders state, message, and progress on one line.
+func (m *Metrics) Summary() string {
+	s := m.State
+	if m.Progress != nil {
+		if p := m.Progress.String(); p != "" {
+			if s != "" {
+				s += " "
+			}
+			s += p
+		}
+	}
+	if m.Message != "" {
+		if s != "" {
+			s += " — "
+		}
+		s += m.Message
+	}
+	return s
+}
+
+// Path returns the metrics file path for a worktree.
+func Path(workDir string) string {
+	return filepath.Join(workDir, constants.DirRuntime, Filename)
+}
+
+// Read loads an agent's metrics from its worktree.
+// Returns nil, nil if the agent has not written a metrics file.
+func Read(workDir string) (*Metrics, error) {
+	path := Path(workDir)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted workDir
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading metrics: %w", err)
+	}
+
+	var m Metrics
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing metrics %s: %w", path, err)
+	}
+	if m.Version > Version {
+		return nil, fmt.Errorf("metrics %s: unsupported version %d (want <= %d)", path, m.Version, Version)
+	}
+	if m.Updated.IsZero() {
+		if info, err := os.Stat(path); err == nil {
+			m.Updated = info.ModTime()
+		}
+	}
+	return &m, nil
+}
+
+// Write saves metrics into a worktree atomically. Shims written in Go can
+// use it; others write the JSON directly.
+func Write(workDir string, m *Metrics) error {
+	if m.Version == 0 {
+		m.Version = Version
+	}
+	if m.Updated.IsZero() {
+		m.Updated = time.Now()
+	}
+	if err := os.MkdirAll(filepath.Dir(Path(workDir)), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	return util.AtomicWriteJSON(Path(workDir), m)
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadMissing(t *testing.T) {
+	m, err := Read(t.TempDir())
+	if m != nil || err != nil {
+		t.Errorf("Read(empty) = %v, %v; want nil, nil", m, err)
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	dir := t.TempDir()
+	cost := 1.25
+	if err := Write(dir, &Metrics{
+		State:    "testing",
+		Progress: &Progress{Done: 3, Total: 7, Label: "steps"},
+		Tokens:   &Tokens{Input: 100, Output: 20},
+		CostUSD:  &cost,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Read(dir)
+	if err != nil || m == nil {
+		t.Fatalf("Read = %v, %v", m, err)
+	}
+	if m.Version != Version || m.Updated.IsZero() {
+		t.Errorf("Write did not fill version/updated: %+v", m)
+	}
+	if got, want := m.Summary(), "testing 3/7 steps (43%)"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	if m.Tokens.Total() != 120 || *m.CostUSD != 1.25 {
+		t.Errorf("usage = %+v, cost %v", m.Tokens, *m.CostUSD)
+	}
+	if m.Stale(time.Now()) {
+		t.Error("freshly written metrics reported stale")
+	}
+}
+
+func TestReadHandWritten(t *testing.T) {
+	dir := t.TempDir()
+	path := Path(dir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A minimal file from a shell shim: no version, no timestamp.
+	if err := os.WriteFile(path, []byte(`{"progress":{"percent":40},"message":"running tests"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Updated.IsZero() {
+		t.Error("Updated should fall back to the file's mtime")
+	}
+	if got, want := m.Summary(), "40% — running tests"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version":99}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(dir); err == nil {
+		t.Error("Read accepted a future contract version")
+	}
+}
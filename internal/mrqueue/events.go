@@ -22,6 +22,15 @@ const (
 	EventMergeFailed EventType = "merge_failed"
 	// EventMergeSkipped indicates an MR was skipped (already merged, etc.).
 	EventMergeSkipped EventType = "merge_skipped"
+
+	// EventClaimed indicates a worker took a lease on an MR.
+	EventClaimed EventType = "claimed"
+	// EventLeaseRenewed indicates a worker extended its lease.
+	EventLeaseRenewed EventType = "lease_renewed"
+	// EventLeaseExpired indicates a lease lapsed and the MR was freed.
+	EventLeaseExpired EventType = "lease_expired"
+	// EventReleased indicates a worker gave up its lease.
+	EventReleased EventType = "released"
 )
 
 // Event represents a single MQ lifecycle event.
@@ -36,6 +45,10 @@ type Event struct {
 	Rig         string    `json:"rig,omitempty"`
 	MergeCommit string    `json:"merge_commit,omitempty"` // For merged events
 	Reason      string    `json:"reason,omitempty"`       // For failed/skipped events
+
+	// Lease events
+	ClaimedBy      string     `json:"claimed_by,omitempty"`
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
 }
 
 // EventLogger handles writing MQ events to the event log.
@@ -146,6 +159,22 @@ func (l *EventLogger) LogMergeSkipped(mr *MR, reason string) error {
 	})
 }
 
+// LogLease logs a lease event (claimed, lease_renewed, lease_expired,
+// released) for holder.
+func (l *EventLogger) LogLease(eventType EventType, mr *MR, holder string) error {
+	return l.LogEvent(Event{
+		Type:           eventType,
+		MRID:           mr.ID,
+		Branch:         mr.Branch,
+		Target:         mr.Target,
+		Worker:         mr.Worker,
+		SourceIssue:    mr.SourceIssue,
+		Rig:            mr.Rig,
+		ClaimedBy:      holder,
+		LeaseExpiresAt: mr.LeaseExpiresAt,
+	})
+}
+
 // LogPath returns the path to the event log file.
 func (l *EventLogger) LogPath() string {
 	return l.logPath
@@ -0,0 +1,201 @@
+package mrqueue
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// DefaultLease is how long a claim lasts without renewal. A worker that
+// crashes mid-merge stops renewing, and the MR becomes available for
+// re-dispatch once the lease lapses.
+const DefaultLease = 10 * time.Minute
+
+// leaseExpiry returns when mr's claim lapses. Claims written before leases
+// existed expire DefaultLease after ClaimedAt; a claim with neither time
+// is treated as already lapsed, so it can never block the MR.
+func (mr *MR) leaseExpiry() time.Time {
+	switch {
+	case mr.LeaseExpiresAt != nil:
+		return *mr.LeaseExpiresAt
+	case mr.ClaimedAt != nil:
+		return mr.ClaimedAt.Add(DefaultLease)
+	default:
+		return time.Time{}
+	}
+}
+
+// LeaseActive reports whether a worker holds a live lease on mr at now.
+func (mr *MR) LeaseActive(now time.Time) bool {
+	return mr.ClaimedBy != "" && now.Before(mr.leaseExpiry())
+}
+
+// locked runs fn under an exclusive lock on the queue directory so claims
+// from concurrent workers are read-modify-write safe.
+func (q *Queue) locked(fn func() error) error {
+	if err := q.EnsureDir(); err != nil {
+		return fmt.Errorf("creating mq directory: %w", err)
+	}
+	return util.WithFileLock(filepath.Join(q.dir, ".lock"), fn)
+}
+
+// save writes mr atomically via a temp file and rename.
+func (q *Queue) save(mr *MR) error {
+	data, err := json.MarshalIndent(mr, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling MR: %w", err)
+	}
+	path := filepath.Join(q.dir, mr.ID+".json")
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath) // cleanup
+		return fmt.Errorf("renaming temp file: %w", err)
+	}
+	return nil
+}
+
+// events returns the logger for this queue's rig.
+func (q *Queue) events() *EventLogger {
+	return NewEventLogger(filepath.Dir(q.dir))
+}
+
+// Claim takes a DefaultLease on an MR for workerID.
+// Returns nil if successful, ErrAlreadyClaimed if another worker holds a
+// live lease, or ErrNotFound if the MR doesn't exist.
+func (q *Queue) Claim(id, workerID string) error {
+	return q.ClaimWithLease(id, workerID, DefaultLease)
+}
+
+// ClaimWithLease takes a lease of the given duration on an MR. Claiming an
+// MR the worker already holds renews it; claiming one whose lease lapsed
+// takes it over and logs the expiry.
+func (q *Queue) ClaimWithLease(id, workerID string, lease time.Duration) error {
+	return q.locked(func() error {
+		mr, err := q.load(filepath.Join(q.dir, id+".json"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("loading MR: %w", err)
+		}
+
+		now := time.Now()
+		prev := mr.ClaimedBy
+		if prev != "" && prev != workerID {
+			if mr.LeaseActive(now) {
+				return ErrAlreadyClaimed
+			}
+			_ = q.events().LogLease(EventLeaseExpired, mr, prev)
+		}
+
+		expires := now.Add(lease)
+		if prev != workerID || mr.ClaimedAt == nil {
+			mr.ClaimedAt = &now
+		}
+		mr.ClaimedBy = workerID
+		mr.LeaseExpiresAt = &expires
+		if err := q.save(mr); err != nil {
+			return err
+		}
+
+		eventType := EventClaimed
+		if prev == workerID {
+			eventType = EventLeaseRenewed
+		}
+		_ = q.events().LogLease(eventType, mr, workerID)
+		return nil
+	})
+}
+
+// Renew extends workerID's lease on an MR. Returns ErrLeaseLost if the
+// worker does not hold a live lease, e.g. because it lapsed and another
+// worker claimed the MR; the worker should stop processing it.
+func (q *Queue) Renew(id, workerID string, lease time.Duration) error {
+	return q.locked(func() error {
+		mr, err := q.load(filepath.Join(q.dir, id+".json"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("loading MR: %w", err)
+		}
+
+		now := time.Now()
+		if mr.ClaimedBy != workerID || !mr.LeaseActive(now) {
+			return ErrLeaseLost
+		}
+		expires := now.Add(lease)
+		mr.LeaseExpiresAt = &expires
+		if err := q.save(mr); err != nil {
+			return err
+		}
+		_ = q.events().LogLease(EventLeaseRenewed, mr, workerID)
+		return nil
+	})
+}
+
+// Release releases a claimed MR back to the queue.
+// Called when processing fails and the MR should be retried.
+func (q *Queue) Release(id string) error {
+	return q.locked(func() error {
+		mr, err := q.load(filepath.Join(q.dir, id+".json"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil // Already removed
+			}
+			return fmt.Errorf("loading MR: %w", err)
+		}
+		if mr.ClaimedBy == "" {
+			return nil
+		}
+
+		holder := mr.ClaimedBy
+		mr.ClaimedBy = ""
+		mr.ClaimedAt = nil
+		mr.LeaseExpiresAt = nil
+		if err := q.save(mr); err != nil {
+			return err
+		}
+		_ = q.events().LogLease(EventReleased, mr, holder)
+		return nil
+	})
+}
+
+// ExpireLeases clears every claim whose lease has lapsed, logging a
+// lease_expired event for each, and returns the freed MRs.
+func (q *Queue) ExpireLeases() ([]*MR, error) {
+	all, err := q.List()
+	if err != nil || len(all) == 0 {
+		return nil, err
+	}
+
+	var freed []*MR
+	err = q.locked(func() error {
+		now := time.Now()
+		for _, listed := range all {
+			// Re-read under the lock; a worker may have renewed meanwhile.
+			mr, err := q.load(filepath.Join(q.dir, listed.ID+".json"))
+			if err != nil || mr.ClaimedBy == "" || mr.LeaseActive(now) {
+				continue
+			}
+			lapsed := *mr
+			mr.ClaimedBy = ""
+			mr.ClaimedAt = nil
+			mr.LeaseExpiresAt = nil
+			if err := q.save(mr); err != nil {
+				return err
+			}
+			_ = q.events().LogLease(EventLeaseExpired, &lapsed, lapsed.ClaimedBy)
+			freed = append(freed, mr)
+		}
+		return nil
+	})
+	return freed, err
+}
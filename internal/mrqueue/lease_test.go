@@ -0,0 +1,130 @@
+package mrqueue
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestQueue(t *testing.T) *Queue {
+	t.Helper()
+	q := New(t.TempDir())
+	if err := q.Submit(&MR{ID: "mr-1", Branch: "polecat/nux", Target: "main", SourceIssue: "gt-1"}); err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func leaseEvents(t *testing.T, q *Queue) []EventType {
+	t.Helper()
+	f, err := os.Open(q.events().LogPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var types []EventType
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+func TestLeaseLifecycle(t *testing.T) {
+	q := newTestQueue(t)
+
+	if err := q.ClaimWithLease("mr-1", "w1", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Claim("mr-1", "w2"); err != ErrAlreadyClaimed {
+		t.Errorf("second worker claim = %v, want ErrAlreadyClaimed", err)
+	}
+	if err := q.Renew("mr-1", "w2", time.Hour); err != ErrLeaseLost {
+		t.Errorf("renew by non-holder = %v, want ErrLeaseLost", err)
+	}
+	if err := q.Renew("mr-1", "w1", time.Hour); err != nil {
+		t.Errorf("renew by holder = %v", err)
+	}
+	if ready, _ := q.ListReady(nil); len(ready) != 0 {
+		t.Errorf("leased MR listed as ready")
+	}
+
+	// Let the lease lapse: w1 "crashed"
+	if err := q.Renew("mr-1", "w1", -time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if unclaimed, _ := q.ListUnclaimed(); len(unclaimed) != 1 {
+		t.Errorf("lapsed MR not listed as unclaimed")
+	}
+	freed, err := q.ExpireLeases()
+	if err != nil || len(freed) != 1 {
+		t.Fatalf("ExpireLeases = %v, %v", freed, err)
+	}
+	if mr, _ := q.Get("mr-1"); mr.ClaimedBy != "" || mr.LeaseExpiresAt != nil {
+		t.Errorf("expired claim not cleared: %+v", mr)
+	}
+	if err := q.Renew("mr-1", "w1", time.Hour); err != ErrLeaseLost {
+		t.Errorf("renew after expiry = %v, want ErrLeaseLost", err)
+	}
+
+	if err := q.Claim("mr-1", "w2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Release("mr-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []EventType{EventClaimed, EventLeaseRenewed, EventLeaseRenewed, EventLeaseExpired, EventClaimed, EventReleased}
+	got := leaseEvents(t, q)
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLegacyClaimWithoutTimestampDoesNotBlock(t *testing.T) {
+	q := newTestQueue(t)
+	mr, _ := q.Get("mr-1")
+	mr.ClaimedBy = "ghost"
+	data, _ := json.Marshal(mr)
+	if err := os.WriteFile(filepath.Join(q.Dir(), "mr-1.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Claim("mr-1", "w1"); err != nil {
+		t.Errorf("claim over a claim with no lease = %v", err)
+	}
+}
+
+func TestConcurrentClaims(t *testing.T) {
+	q := newTestQueue(t)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	winners := 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(worker string) {
+			defer wg.Done()
+			if err := q.Claim("mr-1", worker); err == nil {
+				mu.Lock()
+				winners++
+				mu.Unlock()
+			}
+		}(string(rune('a' + i)))
+	}
+	wg.Wait()
+	if winners != 1 {
+		t.Errorf("%d workers claimed the MR, want 1", winners)
+	}
+}
@@ -37,6 +37,10 @@ type MR struct {
 	ClaimedBy string     `json:"claimed_by,omitempty"` // Worker ID that claimed this MR
 	ClaimedAt *time.Time `json:"claimed_at,omitempty"` // When the MR was claimed
 
+	// LeaseExpiresAt is when the claim lapses unless renewed. Claims
+	// written before leases existed have none; see leaseExpiry.
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
+
 	// Blocking fields for non-blocking delegation
 	BlockedBy string `json:"blocked_by,omitempty"` // Task ID that blocks this MR (e.g., conflict resolution task)
 }
@@ -233,86 +237,7 @@ func (q *Queue) Dir() string {
 	return q.dir
 }
 
-// ClaimStaleTimeout is how long before a claimed MR is considered stale.
-// If a worker claims an MR but doesn't process it within this time,
-// another worker can reclaim it.
-const ClaimStaleTimeout = 10 * time.Minute
-
-// Claim attempts to claim an MR for processing by a specific worker.
-// Returns nil if successful, ErrAlreadyClaimed if another worker has it,
-// or ErrNotFound if the MR doesn't exist.
-// Uses atomic file operations to prevent race conditions.
-func (q *Queue) Claim(id, workerID string) error {
-	path := filepath.Join(q.dir, id+".json")
-
-	// Read current state
-	mr, err := q.load(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return ErrNotFound
-		}
-		return fmt.Errorf("loading MR: %w", err)
-	}
-
-	// Check if already claimed by another worker
-	if mr.ClaimedBy != "" && mr.ClaimedBy != workerID {
-		// Check if claim is stale (worker may have crashed)
-		if mr.ClaimedAt != nil && time.Since(*mr.ClaimedAt) < ClaimStaleTimeout {
-			return ErrAlreadyClaimed
-		}
-		// Stale claim - allow reclaim
-	}
-
-	// Claim the MR
-	now := time.Now()
-	mr.ClaimedBy = workerID
-	mr.ClaimedAt = &now
-
-	// Write atomically
-	data, err := json.MarshalIndent(mr, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshaling MR: %w", err)
-	}
-
-	// Write to temp file first, then rename (atomic on most filesystems)
-	tmpPath := path + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
-		return fmt.Errorf("writing temp file: %w", err)
-	}
-	if err := os.Rename(tmpPath, path); err != nil {
-		_ = os.Remove(tmpPath) // cleanup
-		return fmt.Errorf("renaming temp file: %w", err)
-	}
-
-	return nil
-}
-
-// Release releases a claimed MR back to the queue.
-// Called when processing fails and the MR should be retried.
-func (q *Queue) Release(id string) error {
-	path := filepath.Join(q.dir, id+".json")
-
-	mr, err := q.load(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil // Already removed
-		}
-		return fmt.Errorf("loading MR: %w", err)
-	}
-
-	// Clear claim
-	mr.ClaimedBy = ""
-	mr.ClaimedAt = nil
-
-	data, err := json.MarshalIndent(mr, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshaling MR: %w", err)
-	}
-
-	return os.WriteFile(path, data, 0644)
-}
-
-// ListUnclaimed returns MRs that are not claimed or have stale claims.
+// ListUnclaimed returns MRs that are not claimed or whose lease has lapsed.
 // Sorted by priority then creation time.
 func (q *Queue) ListUnclaimed() ([]*MR, error) {
 	all, err := q.List()
@@ -320,14 +245,10 @@ func (q *Queue) ListUnclaimed() ([]*MR, error) {
 		return nil, err
 	}
 
+	now := time.Now()
 	var unclaimed []*MR
 	for _, mr := range all {
-		if mr.ClaimedBy == "" {
-			unclaimed = append(unclaimed, mr)
-			continue
-		}
-		// Check if claim is stale
-		if mr.ClaimedAt != nil && time.Since(*mr.ClaimedAt) >= ClaimStaleTimeout {
+		if !mr.LeaseActive(now) {
 			unclaimed = append(unclaimed, mr)
 		}
 	}
@@ -356,29 +277,24 @@ func (q *Queue) ListClaimedBy(workerID string) ([]*MR, error) {
 var (
 	ErrNotFound       = fmt.Errorf("merge request not found")
 	ErrAlreadyClaimed = fmt.Errorf("merge request already claimed by another worker")
+	ErrLeaseLost      = fmt.Errorf("merge request lease is not held by this worker")
 )
 
 // SetBlockedBy marks an MR as blocked by a task (e.g., conflict resolution).
 // When the blocking task closes, the MR becomes ready for processing again.
 func (q *Queue) SetBlockedBy(mrID, taskID string) error {
-	path := filepath.Join(q.dir, mrID+".json")
-
-	mr, err := q.load(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return ErrNotFound
+	return q.locked(func() error {
+		mr, err := q.load(filepath.Join(q.dir, mrID+".json"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return ErrNotFound
+			}
+			return fmt.Errorf("loading MR: %w", err)
 		}
-		return fmt.Errorf("loading MR: %w", err)
-	}
 
-	mr.BlockedBy = taskID
-
-	data, err := json.MarshalIndent(mr, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshaling MR: %w", err)
-	}
-
-	return os.WriteFile(path, data, 0644)
+		mr.BlockedBy = taskID
+		return q.save(mr)
+	})
 }
 
 // ClearBlockedBy removes the blocking task from an MR.
@@ -408,7 +324,7 @@ func (mr *MR) IsBlocked(checkStatus func(beadID string) (isOpen bool, err error)
 type BeadStatusChecker func(beadID string) (isOpen bool, err error)
 
 // ListReady returns MRs that are ready for processing:
-// - Not claimed by another worker (or claim is stale)
+// - Not claimed by another worker (or the lease has lapsed)
 // - Not blocked by an open task
 // Sorted by priority score (highest first).
 // The checkStatus function is used to check if blocking tasks are still open.
@@ -418,14 +334,12 @@ func (q *Queue) ListReady(checkStatus BeadStatusChecker) ([]*MR, error) {
 		return nil, err
 	}
 
+	now := time.Now()
 	var ready []*MR
 	for _, mr := range all {
-		// Skip if claimed by another worker (and not stale)
-		if mr.ClaimedBy != "" {
-			if mr.ClaimedAt != nil && time.Since(*mr.ClaimedAt) < ClaimStaleTimeout {
-				continue
-			}
-			// Stale claim - include in ready list
+		// Skip if another worker holds a live lease
+		if mr.LeaseActive(now) {
+			continue
 		}
 
 		// Skip if blocked by an open task
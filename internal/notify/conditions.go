@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"strings"
+	"time"
+)
+
+// Well-known conditions that open on-call incidents. Each is raised with
+// Raise when detected and resolved with Clear once it no longer holds.
+const (
+	// ConditionDaemonDown is raised by 'gt daemon check' when the daemon is
+	// not running, and cleared when the daemon starts or the check passes.
+	ConditionDaemonDown = "daemon-down"
+
+	// crashLoopPrefix prefixes per-agent crash loop conditions.
+	crashLoopPrefix = "crash-loop:"
+
+	// budgetLimitPrefix prefixes per-scope budget limit conditions, raised
+	// by the daemon while a rig's or agent's budget is used up (gt budget).
+	budgetLimitPrefix = "budget-limit:"
+)
+
+// Event types for condition notifications, matchable in rules.
+const (
+	EventDaemonDown  = "daemon_down"
+	EventCrashLoop   = "crash_loop"
+	EventBudgetLimit = "budget_limit"
+)
+
+// Crash loop detection: CrashLoopThreshold crashes of one agent within
+// CrashLoopWindow opens an incident; a full window without crashes clears it.
+const (
+	CrashLoopThreshold = 3
+	CrashLoopWindow    = 10 * time.Minute
+)
+
+// CrashLoopCondition returns the condition key for an agent's crash loop.
+func CrashLoopCondition(agent string) string {
+	return crashLoopPrefix + agent
+}
+
+// CrashLoopAgent returns the agent for a crash loop condition, or "" if
+// condition is not a crash loop.
+func CrashLoopAgent(condition string) string {
+	agent, ok := strings.CutPrefix(condition, crashLoopPrefix)
+	if !ok {
+		return ""
+	}
+	return agent
+}
+
+// BudgetLimitCondition returns the condition key for a budget scope (a rig,
+// "gastown/", or an agent) having used up one of its budgets.
+func BudgetLimitCondition(scope string) string {
+	return budgetLimitPrefix + scope
+}
+
+// BudgetLimitScope returns the budget scope for a budget limit condition,
+// or "" if condition is not a budget limit.
+func BudgetLimitScope(condition string) string {
+	scope, ok := strings.CutPrefix(condition, budgetLimitPrefix)
+	if !ok {
+		return ""
+	}
+	return scope
+}
@@ -0,0 +1,127 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/mail"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/tmux"
+)
+
+// wakeTimeout bounds how long a critical nudge waits for a woken agent.
+const wakeTimeout = 60 * time.Second
+
+// Deliver sends n to ch using the built-in channel implementations.
+// pendingID, when set, is included so the recipient knows how to ack.
+func Deliver(townRoot string, ch Channel, n *Notification, pendingID string) error {
+	switch ch.Type {
+	case ChannelMail:
+		return deliverMail(townRoot, ch.Target, n, pendingID)
+	case ChannelNudge:
+		return deliverNudge(ch.Target, n, pendingID)
+	case ChannelCommand:
+		return deliverCommand(townRoot, ch.Target, n, pendingID)
+	case ChannelPagerDuty:
+		return deliverPagerDuty(ch, n)
+	case ChannelOpsgenie:
+		return deliverOpsgenie(ch, n)
+	}
+	return fmt.Errorf("unknown channel type %q", ch.Type)
+}
+
+func deliverMail(townRoot, to string, n *Notification, pendingID string) error {
+	body := n.Body
+	if pendingID != "" {
+		body = strings.TrimSpace(body + "\n\nAcknowledge with: gt notify ack " + pendingID)
+	}
+	from := n.Source
+	if from == "" {
+		from = "daemon"
+	}
+	msg := &mail.Message{
+		From:     from,
+		To:       to,
+		Subject:  fmt.Sprintf("[%s] %s", subjectTag(n), n.Subject),
+		Body:     body,
+		Priority: mailPriority(n.Severity),
+	}
+	return mail.NewRouter(townRoot).Send(msg)
+}
+
+// deliverNudge nudges a session. Nudges carry the same priority levels as
+// mail: critical notifications are urgent and wake an agent that is asleep.
+func deliverNudge(target string, n *Notification, pendingID string) error {
+	t := tmux.NewTmux()
+	if mailPriority(n.Severity).Interrupts() {
+		woke, err := session.Wake(t, target)
+		if err != nil {
+			return err
+		}
+		if woke {
+			_ = t.WaitForClaudeReady(target, wakeTimeout)
+		}
+	}
+	return t.NudgeSession(target, formatLine(n, pendingID))
+}
+
+func deliverCommand(townRoot, command string, n *Notification, pendingID string) error {
+	cmd := exec.Command("sh", "-c", command) //nolint:gosec // G204: command comes from town config
+	cmd.Dir = townRoot
+	cmd.Env = append(os.Environ(),
+		"GT_NOTIFY_EVENT="+n.Event,
+		"GT_NOTIFY_RIG="+n.Rig,
+		"GT_NOTIFY_LABELS="+strings.Join(n.Labels, ","),
+		"GT_NOTIFY_SEVERITY="+n.Severity.String(),
+		"GT_NOTIFY_SOURCE="+n.Source,
+		"GT_NOTIFY_SUBJECT="+n.Subject,
+		"GT_NOTIFY_BODY="+n.Body,
+		"GT_NOTIFY_ID="+pendingID,
+		"GT_NOTIFY_CONDITION="+n.Condition,
+		"GT_NOTIFY_ACTION="+action(n),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// formatLine renders n as a single line for nudges.
+func formatLine(n *Notification, pendingID string) string {
+	line := fmt.Sprintf("[notify %s] %s", strings.ToLower(subjectTag(n)), n.Subject)
+	if pendingID != "" {
+		line += " (ack: gt notify ack " + pendingID + ")"
+	}
+	return line
+}
+
+func mailPriority(s Severity) mail.Priority {
+	switch s {
+	case SeverityCritical:
+		return mail.PriorityUrgent
+	case SeverityHigh:
+		return mail.PriorityHigh
+	case SeverityLow:
+		return mail.PriorityLow
+	}
+	return mail.PriorityNormal
+}
+
+// subjectTag is the bracketed prefix for human-readable channels.
+func subjectTag(n *Notification) string {
+	if n.Resolve {
+		return "RESOLVED"
+	}
+	return strings.ToUpper(n.Severity.String())
+}
+
+// action is "resolve" for all-clear notifications, otherwise "trigger".
+func action(n *Notification) string {
+	if n.Resolve {
+		return "resolve"
+	}
+	return "trigger"
+}
@@ -0,0 +1,178 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Default on-call API endpoints.
+const (
+	pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+	opsgenieAlertsURL  = "https://api.opsgenie.com/v2/alerts"
+)
+
+// oncallClient is shared by the on-call backends.
+var oncallClient = &http.Client{Timeout: 10 * time.Second}
+
+// resolveKey returns the API key for a channel target. Targets of the form
+// "env:VAR" read the key from the environment so secrets stay out of config.
+func resolveKey(target string) (string, error) {
+	if name, ok := strings.CutPrefix(target, "env:"); ok {
+		key := os.Getenv(name)
+		if key == "" {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return key, nil
+	}
+	return target, nil
+}
+
+// incidentKey is the backend dedup key for n. Conditions map one-to-one to
+// incidents; one-off notifications get a key from their subject.
+func incidentKey(n *Notification) string {
+	if n.Condition != "" {
+		return "gt-" + n.Condition
+	}
+	return "gt-" + n.Event + "-" + n.Subject
+}
+
+// deliverPagerDuty sends a trigger or resolve event to PagerDuty Events API v2.
+func deliverPagerDuty(ch Channel, n *Notification) error {
+	key, err := resolveKey(ch.Target)
+	if err != nil {
+		return err
+	}
+
+	event := map[string]interface{}{
+		"routing_key":  key,
+		"event_action": action(n),
+		"dedup_key":    incidentKey(n),
+	}
+	if !n.Resolve {
+		source := n.Source
+		if source == "" {
+			source = "gastown"
+		}
+		event["payload"] = map[string]interface{}{
+			"summary":  n.Subject,
+			"source":   source,
+			"severity": pagerDutySeverity(n.Severity),
+			"group":    n.Rig,
+			"class":    n.Event,
+			"custom_details": map[string]interface{}{
+				"body":   n.Body,
+				"labels": n.Labels,
+			},
+		}
+	}
+
+	endpoint := ch.URL
+	if endpoint == "" {
+		endpoint = pagerDutyEventsURL
+	}
+	return postJSON(endpoint, nil, event)
+}
+
+// pagerDutySeverity maps to PagerDuty's severity vocabulary.
+func pagerDutySeverity(s Severity) string {
+	switch s {
+	case SeverityCritical:
+		return "critical"
+	case SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	}
+	return "info"
+}
+
+// deliverOpsgenie creates or closes an Opsgenie alert keyed by alias.
+func deliverOpsgenie(ch Channel, n *Notification) error {
+	key, err := resolveKey(ch.Target)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{"Authorization": "GenieKey " + key}
+
+	base := strings.TrimSuffix(ch.URL, "/")
+	if base == "" {
+		base = opsgenieAlertsURL
+	}
+	alias := incidentKey(n)
+
+	if n.Resolve {
+		endpoint := fmt.Sprintf("%s/%s/close?identifierType=alias", base, url.PathEscape(alias))
+		return postJSON(endpoint, headers, map[string]string{"source": "gastown"})
+	}
+
+	alert := map[string]interface{}{
+		"message":     truncate(n.Subject, 130), // Opsgenie limit
+		"alias":       alias,
+		"description": n.Body,
+		"priority":    opsgeniePriority(n.Severity),
+		"source":      "gastown",
+		"entity":      n.Source,
+		"tags":        append([]string{n.Event}, n.Labels...),
+	}
+	if n.Rig != "" {
+		alert["details"] = map[string]string{"rig": n.Rig}
+	}
+	return postJSON(base, headers, alert)
+}
+
+// opsgeniePriority maps to Opsgenie's P1-P5 priorities.
+func opsgeniePriority(s Severity) string {
+	switch s {
+	case SeverityCritical:
+		return "P1"
+	case SeverityHigh:
+		return "P2"
+	case SeverityMedium:
+		return "P3"
+	}
+	return "P4"
+}
+
+// postJSON posts body as JSON and treats any non-2xx response as an error.
+func postJSON(endpoint string, headers map[string]string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := oncallClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting to %s: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s: %s", req.URL.Host, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// truncate shortens s to n runes, ending it with "..." if cut.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-3]) + "..."
+}
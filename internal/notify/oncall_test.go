@@ -0,0 +1,140 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+type capturedRequest struct {
+	path string
+	auth string
+	body map[string]interface{}
+}
+
+func newCaptureServer(t *testing.T) (*httptest.Server, *[]capturedRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var reqs []capturedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		reqs = append(reqs, capturedRequest{r.URL.RequestURI(), r.Header.Get("Authorization"), body})
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &reqs
+}
+
+func TestPagerDutyTriggerAndResolve(t *testing.T) {
+	srv, reqs := newCaptureServer(t)
+	t.Setenv("GT_TEST_PD_KEY", "routing-123")
+	ch := Channel{Type: ChannelPagerDuty, Target: "env:GT_TEST_PD_KEY", URL: srv.URL}
+
+	n := &Notification{Event: "daemon_down", Condition: "daemon-down", Severity: SeverityCritical, Subject: "daemon down"}
+	if err := Deliver("", ch, n, ""); err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	resolved := *n
+	resolved.Resolve = true
+	if err := Deliver("", ch, &resolved, ""); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+
+	if len(*reqs) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(*reqs))
+	}
+	trigger, resolve := (*reqs)[0].body, (*reqs)[1].body
+	if trigger["routing_key"] != "routing-123" || trigger["event_action"] != "trigger" {
+		t.Errorf("bad trigger: %v", trigger)
+	}
+	if resolve["event_action"] != "resolve" || resolve["dedup_key"] != trigger["dedup_key"] {
+		t.Errorf("resolve must reuse the trigger dedup key: %v", resolve)
+	}
+	if payload, _ := trigger["payload"].(map[string]interface{}); payload["severity"] != "critical" {
+		t.Errorf("bad payload severity: %v", payload)
+	}
+}
+
+func TestOpsgenieCreateAndClose(t *testing.T) {
+	srv, reqs := newCaptureServer(t)
+	ch := Channel{Type: ChannelOpsgenie, Target: "genie-key", URL: srv.URL + "/v2/alerts"}
+
+	n := &Notification{Event: "crash_loop", Condition: "crash-loop:gastown/nux", Severity: SeverityCritical, Subject: "crash loop"}
+	if err := Deliver("", ch, n, ""); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	n.Resolve = true
+	if err := Deliver("", ch, n, ""); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	create, closeReq := (*reqs)[0], (*reqs)[1]
+	if create.auth != "GenieKey genie-key" || create.body["priority"] != "P1" {
+		t.Errorf("bad create request: %+v", create)
+	}
+	want := "/v2/alerts/gt-crash-loop:gastown%2Fnux/close?identifierType=alias"
+	if closeReq.path != want {
+		t.Errorf("close path = %q, want %q", closeReq.path, want)
+	}
+}
+
+func TestRaiseAndClearCondition(t *testing.T) {
+	srv, reqs := newCaptureServer(t)
+	townRoot := t.TempDir()
+
+	rules := NewRules()
+	rules.Channels["pd"] = Channel{Type: ChannelPagerDuty, Target: "key", URL: srv.URL}
+	rules.Rules = []Rule{{Name: "oncall", Match: Match{MinSeverity: "critical"}, Channels: []string{"pd"}}}
+	if err := SaveRules(townRoot, rules); err != nil {
+		t.Fatal(err)
+	}
+
+	n := func() *Notification {
+		return &Notification{Event: "daemon_down", Severity: SeverityCritical, Subject: "daemon down"}
+	}
+	if _, err := Raise(townRoot, "daemon-down", n()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Raise(townRoot, "daemon-down", n()); err != nil {
+		t.Fatal(err)
+	}
+	if len(*reqs) != 1 {
+		t.Fatalf("repeat raise of open condition should be dropped, got %d requests", len(*reqs))
+	}
+	if !IsOpen(townRoot, "daemon-down") {
+		t.Fatal("condition should be open")
+	}
+
+	if _, err := Clear(townRoot, "daemon-down", "daemon running"); err != nil {
+		t.Fatal(err)
+	}
+	if len(*reqs) != 2 || (*reqs)[1].body["event_action"] != "resolve" {
+		t.Fatalf("expected resolve event, got %+v", *reqs)
+	}
+	if IsOpen(townRoot, "daemon-down") {
+		t.Error("condition should be closed after Clear")
+	}
+
+	// Clearing again is a no-op.
+	if _, err := Clear(townRoot, "daemon-down", ""); err != nil || len(*reqs) != 2 {
+		t.Errorf("second clear should do nothing, err=%v requests=%d", err, len(*reqs))
+	}
+}
+
+func TestTruncateKeepsRunesWhole(t *testing.T) {
+	s := strings.Repeat("é", 200)
+	got := truncate(s, 130)
+	if !utf8.ValidString(got) || utf8.RuneCountInString(got) != 130 || !strings.HasSuffix(got, "...") {
+		t.Errorf("truncate = %q (%d runes), want 127 runes and \"...\"", got, utf8.RuneCountInString(got))
+	}
+	if got := truncate("short", 130); got != "short" {
+		t.Errorf("truncate(short) = %q", got)
+	}
+}
@@ -0,0 +1,645 @@
+package notify
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/focus"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// Severity orders notifications by urgency.
+type Severity int
+
+const (
+	SeverityLow Severity = iota
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+// String returns the lowercase severity name.
+func (s Severity) String() string {
+	switch s {
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	case SeverityHigh:
+		return "high"
+	case SeverityCritical:
+		return "critical"
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// ParseSeverity parses a severity name (case-insensitive).
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(s) {
+	case "low":
+		return SeverityLow, nil
+	case "medium", "":
+		return SeverityMedium, nil
+	case "high":
+		return SeverityHigh, nil
+	case "critical":
+		return SeverityCritical, nil
+	}
+	return SeverityLow, fmt.Errorf("invalid severity %q: must be low, medium, high, or critical", s)
+}
+
+// MarshalText encodes the severity by name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity name.
+func (s *Severity) UnmarshalText(b []byte) error {
+	parsed, err := ParseSeverity(string(b))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// Notification is something that may need to reach a human or agent.
+type Notification struct {
+	Event    string   `json:"event"`            // event type (e.g., "crash", "escalation")
+	Rig      string   `json:"rig,omitempty"`    // rig the event concerns
+	Labels   []string `json:"labels,omitempty"` // free-form labels
+	Severity Severity `json:"severity"`
+	Source   string   `json:"source,omitempty"` // who raised it
+	Subject  string   `json:"subject"`
+	Body     string   `json:"body,omitempty"`
+
+	// Condition is a stable key for an ongoing problem (e.g., "daemon-down").
+	// While a condition is open, repeat notifications for it are dropped;
+	// on-call backends use it as the incident dedup key.
+	Condition string `json:"condition,omitempty"`
+
+	// Resolve marks this as the all-clear for Condition. It is delivered to
+	// every channel the condition was raised on, then the condition closes.
+	Resolve bool `json:"resolve,omitempty"`
+}
+
+// dedupKey identifies repeats of the same notification for a rule.
+func (n *Notification) dedupKey(rule string) string {
+	return strings.Join([]string{rule, n.Event, n.Rig, n.Source, n.Subject}, "\x00")
+}
+
+// Pending is a notification awaiting acknowledgment, with escalation steps left.
+type Pending struct {
+	ID           string       `json:"id"`
+	Rule         string       `json:"rule"`
+	Notification Notification `json:"notification"`
+	SentAt       time.Time    `json:"sent_at"`
+	NextStep     int          `json:"next_step"` // index into the rule's escalation steps
+	NextAt       time.Time    `json:"next_at"`
+}
+
+// Incident is an open condition and the channels it was raised on.
+type Incident struct {
+	Condition    string       `json:"condition"`
+	Notification Notification `json:"notification"`
+	OpenedAt     time.Time    `json:"opened_at"`
+	Channels     []string     `json:"channels"`
+}
+
+// addChannel records that the incident was delivered to name.
+func (i *Incident) addChannel(name string) {
+	for _, c := range i.Channels {
+		if c == name {
+			return
+		}
+	}
+	i.Channels = append(i.Channels, name)
+}
+
+// Batched is a notification held back while the operator is focused on
+// another rig, to be delivered in a digest.
+type Batched struct {
+	Notification Notification `json:"notification"`
+	At           time.Time    `json:"at"`
+}
+
+// state is the router's persisted state (.runtime/notify-state.json).
+type state struct {
+	LastSent map[string]time.Time `json:"last_sent,omitempty"` // dedup key -> last delivery
+	Pending  []*Pending           `json:"pending,omitempty"`
+	Open     map[string]*Incident `json:"open,omitempty"` // condition -> incident
+	Batched  []*Batched           `json:"batched,omitempty"`
+}
+
+// Delivery is one notification sent to one channel.
+type Delivery struct {
+	Rule    string
+	Channel string
+	Pending string // pending ID when the notification needs acknowledgment
+}
+
+// Decision explains what routing did for one matching rule.
+type Decision struct {
+	Rule       string
+	Channels   []string
+	Suppressed string // non-empty reason when the rule matched but did not deliver
+}
+
+// Router routes notifications through the town's rules.
+type Router struct {
+	townRoot string
+	rules    *Rules
+	ignores  config.IgnoreRules // per-rig ignore rules (see RigSettings.Ignore)
+	focus    *focus.Focus       // operator focus; other rigs' notifications are batched
+	deliver  DeliverFunc
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+// DeliverFunc sends a notification to a channel.
+type DeliverFunc func(townRoot string, ch Channel, n *Notification, pendingID string) error
+
+// NewRouter loads the town's rules and returns a router that delivers via
+// the default channel implementations.
+func NewRouter(townRoot string) (*Router, error) {
+	rules, err := LoadRules(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return &Router{
+		townRoot: townRoot,
+		rules:    rules,
+		ignores:  config.LoadIgnoreRules(townRoot),
+		focus:    focus.Current(townRoot),
+		deliver:  Deliver,
+		now:      time.Now,
+	}, nil
+}
+
+// statePath returns the path to the router's state file.
+func statePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "notify-state.json")
+}
+
+func (r *Router) loadState() (*state, error) {
+	s := &state{LastSent: make(map[string]time.Time), Open: make(map[string]*Incident)}
+	data, err := os.ReadFile(statePath(r.townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("reading notify state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing notify state: %w", err)
+	}
+	if s.LastSent == nil {
+		s.LastSent = make(map[string]time.Time)
+	}
+	if s.Open == nil {
+		s.Open = make(map[string]*Incident)
+	}
+	return s, nil
+}
+
+// locked runs fn holding the router's mutex and the state file's lock,
+// so that routers in the daemon and in gt commands do not overwrite each
+// other's state. Every read-modify-write of the state runs under it.
+func (r *Router) locked(fn func() error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return util.WithFileLock(statePath(r.townRoot)+".lock", fn)
+}
+
+func (r *Router) saveState(s *state) error {
+	if err := os.MkdirAll(filepath.Dir(statePath(r.townRoot)), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	return util.AtomicWriteJSON(statePath(r.townRoot), s)
+}
+
+// Evaluate reports what each matching rule would do with n, without
+// delivering anything or touching state beyond reading it.
+func (r *Router) Evaluate(n *Notification) ([]Decision, error) {
+	s, err := r.loadState()
+	if err != nil {
+		return nil, err
+	}
+	return r.evaluate(n, s), nil
+}
+
+func (r *Router) evaluate(n *Notification, s *state) []Decision {
+	now := r.now()
+	var decisions []Decision
+	for i := range r.rules.Rules {
+		rule := &r.rules.Rules[i]
+		if !rule.Matches(n) {
+			continue
+		}
+		d := Decision{Rule: rule.Name, Channels: rule.Channels}
+		if n.Condition != "" && s.Open[n.Condition] != nil {
+			d.Suppressed = fmt.Sprintf("condition %s already open", n.Condition)
+		} else if n.Condition == "" && r.ignores.Ignored(n.Event, n.Source) {
+			// Incidents are never ignored; rig rules only quiet routine noise.
+			d.Suppressed = fmt.Sprintf("ignored by %s rig rules", rigFromSource(n.Source))
+		} else if r.batches(n) {
+			d.Suppressed = fmt.Sprintf("batched while focused on %s", r.focus)
+		} else if q := rule.QuietHours; q != nil && q.Active(now) && !q.Allows(n.Severity) {
+			d.Suppressed = fmt.Sprintf("quiet hours %s-%s", q.Start, q.End)
+		} else if rule.Dedup != "" {
+			window, _ := time.ParseDuration(rule.Dedup)
+			if last, ok := s.LastSent[n.dedupKey(rule.Name)]; ok && now.Sub(last) < window {
+				d.Suppressed = fmt.Sprintf("duplicate within %s", rule.Dedup)
+			}
+		}
+		decisions = append(decisions, d)
+	}
+	return decisions
+}
+
+// Route delivers n to every channel of every matching, unsuppressed rule.
+// Rules with escalation steps record a pending entry that must be acked.
+// Delivery errors are collected; routing continues past them.
+func (r *Router) Route(n *Notification) ([]Delivery, error) {
+	var deliveries []Delivery
+	err := r.locked(func() (err error) {
+		deliveries, err = r.route(n)
+		return err
+	})
+	return deliveries, err
+}
+
+func (r *Router) route(n *Notification) ([]Delivery, error) {
+	s, err := r.loadState()
+	if err != nil {
+		return nil, err
+	}
+	if n.Resolve {
+		return r.resolve(n, s)
+	}
+
+	now := r.now()
+	var deliveries []Delivery
+	var errs []string
+	decisions := r.evaluate(n, s)
+	if len(decisions) > 0 && r.batches(n) {
+		s.Batched = append(s.Batched, &Batched{Notification: *n, At: now})
+	}
+	for _, d := range decisions {
+		if d.Suppressed != "" {
+			continue
+		}
+		rule := r.rules.GetRule(d.Rule)
+
+		var pendingID string
+		if len(rule.Escalation) > 0 {
+			first, _ := time.ParseDuration(rule.Escalation[0].After)
+			p := &Pending{
+				ID:           newPendingID(),
+				Rule:         rule.Name,
+				Notification: *n,
+				SentAt:       now,
+				NextAt:       now.Add(first),
+			}
+			s.Pending = append(s.Pending, p)
+			pendingID = p.ID
+		}
+
+		for _, name := range rule.Channels {
+			if err := r.deliver(r.townRoot, r.rules.Channels[name], n, pendingID); err != nil {
+				errs = append(errs, fmt.Sprintf("%s/%s: %v", rule.Name, name, err))
+				continue
+			}
+			deliveries = append(deliveries, Delivery{Rule: rule.Name, Channel: name, Pending: pendingID})
+			if n.Condition != "" {
+				r.openIncident(s, n, now).addChannel(name)
+			}
+		}
+		s.LastSent[n.dedupKey(rule.Name)] = now
+	}
+
+	if err := r.saveState(s); err != nil {
+		return deliveries, err
+	}
+	if len(errs) > 0 {
+		return deliveries, fmt.Errorf("delivery failed: %s", strings.Join(errs, "; "))
+	}
+	return deliveries, nil
+}
+
+// Escalate advances every pending notification whose next step is due,
+// delivering to that step's channels. Returns the deliveries made.
+func (r *Router) Escalate() ([]Delivery, error) {
+	var deliveries []Delivery
+	err := r.locked(func() (err error) {
+		deliveries, err = r.escalate()
+		return err
+	})
+	return deliveries, err
+}
+
+func (r *Router) escalate() ([]Delivery, error) {
+	s, err := r.loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	now := r.now()
+	var deliveries []Delivery
+	var errs []string
+	remaining := s.Pending[:0]
+	for _, p := range s.Pending {
+		rule := r.rules.GetRule(p.Rule)
+		if rule == nil || p.NextStep >= len(rule.Escalation) {
+			continue // rule removed or chain exhausted
+		}
+		if now.Before(p.NextAt) {
+			remaining = append(remaining, p)
+			continue
+		}
+
+		step := rule.Escalation[p.NextStep]
+		for _, name := range step.Channels {
+			ch, ok := r.rules.Channels[name]
+			if !ok {
+				continue
+			}
+			if err := r.deliver(r.townRoot, ch, &p.Notification, p.ID); err != nil {
+				errs = append(errs, fmt.Sprintf("%s/%s: %v", rule.Name, name, err))
+				continue
+			}
+			deliveries = append(deliveries, Delivery{Rule: rule.Name, Channel: name, Pending: p.ID})
+			if inc := s.Open[p.Notification.Condition]; inc != nil {
+				inc.addChannel(name)
+			}
+		}
+
+		p.NextStep++
+		if p.NextStep < len(rule.Escalation) {
+			delay, _ := time.ParseDuration(rule.Escalation[p.NextStep].After)
+			p.NextAt = now.Add(delay)
+			remaining = append(remaining, p)
+		}
+	}
+	s.Pending = remaining
+
+	if err := r.saveState(s); err != nil {
+		return deliveries, err
+	}
+	if len(errs) > 0 {
+		return deliveries, fmt.Errorf("escalation failed: %s", strings.Join(errs, "; "))
+	}
+	return deliveries, nil
+}
+
+// openIncident returns the open incident for n's condition, creating it.
+func (r *Router) openIncident(s *state, n *Notification, now time.Time) *Incident {
+	inc := s.Open[n.Condition]
+	if inc == nil {
+		inc = &Incident{Condition: n.Condition, Notification: *n, OpenedAt: now}
+		s.Open[n.Condition] = inc
+	}
+	return inc
+}
+
+// resolve delivers the all-clear for n.Condition to every channel the
+// condition reached, then closes it and cancels pending escalations.
+// Resolving a condition that is not open is a no-op.
+func (r *Router) resolve(n *Notification, s *state) ([]Delivery, error) {
+	inc := s.Open[n.Condition]
+	if inc == nil {
+		return nil, nil
+	}
+
+	// Carry the original context so backends can match the incident.
+	resolved := inc.Notification
+	resolved.Resolve = true
+	if n.Body != "" {
+		resolved.Body = n.Body
+	}
+
+	var deliveries []Delivery
+	var errs []string
+	for _, name := range inc.Channels {
+		ch, ok := r.rules.Channels[name]
+		if !ok {
+			continue
+		}
+		if err := r.deliver(r.townRoot, ch, &resolved, ""); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		deliveries = append(deliveries, Delivery{Channel: name})
+	}
+
+	delete(s.Open, n.Condition)
+	remaining := s.Pending[:0]
+	for _, p := range s.Pending {
+		if p.Notification.Condition != n.Condition {
+			remaining = append(remaining, p)
+		}
+	}
+	s.Pending = remaining
+
+	if err := r.saveState(s); err != nil {
+		return deliveries, err
+	}
+	if len(errs) > 0 {
+		return deliveries, fmt.Errorf("resolve failed: %s", strings.Join(errs, "; "))
+	}
+	return deliveries, nil
+}
+
+// ListOpen returns open incidents, oldest first.
+func (r *Router) ListOpen() ([]*Incident, error) {
+	s, err := r.loadState()
+	if err != nil {
+		return nil, err
+	}
+	open := make([]*Incident, 0, len(s.Open))
+	for _, inc := range s.Open {
+		open = append(open, inc)
+	}
+	sort.Slice(open, func(i, j int) bool {
+		return open[i].OpenedAt.Before(open[j].OpenedAt)
+	})
+	return open, nil
+}
+
+// Ack acknowledges a pending notification, stopping its escalation chain.
+func (r *Router) Ack(id string) error {
+	return r.locked(func() error { return r.ack(id) })
+}
+
+func (r *Router) ack(id string) error {
+	s, err := r.loadState()
+	if err != nil {
+		return err
+	}
+	for i, p := range s.Pending {
+		if p.ID == id {
+			s.Pending = append(s.Pending[:i], s.Pending[i+1:]...)
+			return r.saveState(s)
+		}
+	}
+	return fmt.Errorf("no pending notification %q", id)
+}
+
+// ListPending returns unacknowledged notifications, oldest first.
+func (r *Router) ListPending() ([]*Pending, error) {
+	s, err := r.loadState()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(s.Pending, func(i, j int) bool {
+		return s.Pending[i].SentAt.Before(s.Pending[j].SentAt)
+	})
+	return s.Pending, nil
+}
+
+// Route is a convenience wrapper that loads the town's rules and routes n.
+// Towns without rules are a no-op.
+func Route(townRoot string, n *Notification) ([]Delivery, error) {
+	r, err := NewRouter(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.rules.Rules) == 0 && !n.Resolve {
+		return nil, nil
+	}
+	return r.Route(n)
+}
+
+// Raise routes n as an open condition. Repeats while the condition is open
+// are dropped, so callers can raise on every check without flooding.
+func Raise(townRoot, condition string, n *Notification) ([]Delivery, error) {
+	n.Condition = condition
+	return Route(townRoot, n)
+}
+
+// Clear resolves a condition raised with Raise. body optionally explains
+// what cleared it. Clearing a condition that is not open is a no-op.
+func Clear(townRoot, condition, body string) ([]Delivery, error) {
+	return Route(townRoot, &Notification{Condition: condition, Resolve: true, Body: body})
+}
+
+// IsOpen reports whether a condition is currently raised.
+func IsOpen(townRoot, condition string) bool {
+	r := &Router{townRoot: townRoot}
+	s, err := r.loadState()
+	return err == nil && s.Open[condition] != nil
+}
+
+func newPendingID() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return "ntf-" + hex.EncodeToString(b)
+}
+
+// batches reports whether n is held for a digest because the operator is
+// focused elsewhere. Incidents and high-severity notifications always
+// interrupt.
+func (r *Router) batches(n *Notification) bool {
+	if n.Condition != "" || n.Resolve || n.Severity >= SeverityHigh {
+		return false
+	}
+	rig := n.Rig
+	if rig == "" && strings.Contains(n.Source, "/") {
+		rig = rigFromSource(n.Source)
+	}
+	if rig == "mayor" || rig == "deacon" {
+		rig = "" // town-level agents are never outside the focus
+	}
+	return r.focus.Excludes(rig)
+}
+
+// FlushBatched delivers notifications held while focused as one digest per
+// rule. Unless force is set, the batch is held until its oldest entry is
+// focus.BatchInterval old or the focus is cleared.
+func (r *Router) FlushBatched(force bool) ([]Delivery, error) {
+	var deliveries []Delivery
+	err := r.locked(func() (err error) {
+		deliveries, err = r.flushBatched(force)
+		return err
+	})
+	return deliveries, err
+}
+
+func (r *Router) flushBatched(force bool) ([]Delivery, error) {
+	s, err := r.loadState()
+	if err != nil || len(s.Batched) == 0 {
+		return nil, err
+	}
+	now := r.now()
+	if !force && r.focus != nil && now.Sub(s.Batched[0].At) < focus.BatchInterval {
+		return nil, nil
+	}
+
+	var deliveries []Delivery
+	var errs []string
+	for i := range r.rules.Rules {
+		rule := &r.rules.Rules[i]
+		var held []*Batched
+		for _, b := range s.Batched {
+			if rule.Matches(&b.Notification) {
+				held = append(held, b)
+			}
+		}
+		if len(held) == 0 {
+			continue
+		}
+		digest := digestOf(held)
+		for _, name := range rule.Channels {
+			if err := r.deliver(r.townRoot, r.rules.Channels[name], digest, ""); err != nil {
+				errs = append(errs, fmt.Sprintf("%s/%s: %v", rule.Name, name, err))
+				continue
+			}
+			deliveries = append(deliveries, Delivery{Rule: rule.Name, Channel: name})
+		}
+	}
+	s.Batched = nil
+
+	if err := r.saveState(s); err != nil {
+		return deliveries, err
+	}
+	if len(errs) > 0 {
+		return deliveries, fmt.Errorf("digest delivery failed: %s", strings.Join(errs, "; "))
+	}
+	return deliveries, nil
+}
+
+// digestOf summarizes held notifications as one, at the highest severity.
+func digestOf(held []*Batched) *Notification {
+	d := &Notification{Event: "digest", Severity: SeverityLow}
+	var b strings.Builder
+	for _, h := range held {
+		n := h.Notification
+		if n.Severity > d.Severity {
+			d.Severity = n.Severity
+		}
+		where := n.Rig
+		if where == "" {
+			where = n.Source
+		}
+		fmt.Fprintf(&b, "%s [%s] %s: %s\n", h.At.Format("15:04"), where, n.Event, n.Subject)
+	}
+	d.Subject = fmt.Sprintf("%d notification(s) held while focused", len(held))
+	d.Body = b.String()
+	return d
+}
+
+// rigFromSource returns the rig of an agent address, e.g. "gastown" for
+// "gastown/polecats/Toast".
+func rigFromSource(source string) string {
+	rig, _, _ := strings.Cut(source, "/")
+	return rig
+}
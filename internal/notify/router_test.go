@@ -0,0 +1,276 @@
+package notify
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/focus"
+)
+
+type recorded struct {
+	channel Channel
+	pending string
+}
+
+func newTestRouter(t *testing.T, rules *Rules, now *time.Time) (*Router, *[]recorded) {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := SaveRules(townRoot, rules); err != nil {
+		t.Fatalf("SaveRules: %v", err)
+	}
+	r, err := NewRouter(townRoot)
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	var sent []recorded
+	r.deliver = func(_ string, ch Channel, _ *Notification, pendingID string) error {
+		sent = append(sent, recorded{ch, pendingID})
+		return nil
+	}
+	r.now = func() time.Time { return *now }
+	return r, &sent
+}
+
+func testRules() *Rules {
+	rules := NewRules()
+	rules.Channels["ops"] = Channel{Type: ChannelMail, Target: "overseer"}
+	rules.Channels["pager"] = Channel{Type: ChannelCommand, Target: "true"}
+	return rules
+}
+
+func TestRuleMatches(t *testing.T) {
+	rule := &Rule{Match: Match{
+		Events:      []string{"crash"},
+		Rigs:        []string{"gastown"},
+		Labels:      []string{"prod"},
+		MinSeverity: "high",
+	}}
+
+	tests := []struct {
+		name string
+		n    Notification
+		want bool
+	}{
+		{"all match", Notification{Event: "crash", Rig: "gastown", Labels: []string{"prod"}, Severity: SeverityHigh}, true},
+		{"wrong event", Notification{Event: "done", Rig: "gastown", Labels: []string{"prod"}, Severity: SeverityHigh}, false},
+		{"wrong rig", Notification{Event: "crash", Rig: "beads", Labels: []string{"prod"}, Severity: SeverityHigh}, false},
+		{"no label", Notification{Event: "crash", Rig: "gastown", Severity: SeverityHigh}, false},
+		{"too low", Notification{Event: "crash", Rig: "gastown", Labels: []string{"prod"}, Severity: SeverityMedium}, false},
+	}
+	for _, tt := range tests {
+		if got := rule.Matches(&tt.n); got != tt.want {
+			t.Errorf("%s: Matches() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestQuietHoursWrapsMidnight(t *testing.T) {
+	q, err := ParseQuietHours("22:00-07:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+	for hour, want := range map[int]bool{23: true, 3: true, 7: false, 12: false, 21: false} {
+		if got := q.Active(day.Add(time.Duration(hour) * time.Hour)); got != want {
+			t.Errorf("Active(%02d:00) = %v, want %v", hour, got, want)
+		}
+	}
+	if q.Allows(SeverityHigh) || !q.Allows(SeverityCritical) {
+		t.Error("quiet hours should only allow critical by default")
+	}
+}
+
+func TestRouteDedupAndQuietHours(t *testing.T) {
+	rules := testRules()
+	rules.Rules = []Rule{{
+		Name:       "crashes",
+		Match:      Match{Events: []string{"crash"}},
+		Channels:   []string{"ops"},
+		Dedup:      "10m",
+		QuietHours: &QuietHours{Start: "22:00", End: "07:00"},
+	}}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)
+	r, sent := newTestRouter(t, rules, &now)
+
+	n := &Notification{Event: "crash", Subject: "nux died", Severity: SeverityHigh}
+	if _, err := r.Route(n); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Route(n); err != nil {
+		t.Fatal(err)
+	}
+	if len(*sent) != 1 {
+		t.Fatalf("expected dedup to suppress repeat, got %d deliveries", len(*sent))
+	}
+
+	now = now.Add(11 * time.Minute)
+	_, _ = r.Route(n)
+	if len(*sent) != 2 {
+		t.Fatalf("expected delivery after dedup window, got %d", len(*sent))
+	}
+
+	now = time.Date(2026, 1, 1, 23, 0, 0, 0, time.Local)
+	_, _ = r.Route(&Notification{Event: "crash", Subject: "other", Severity: SeverityHigh})
+	if len(*sent) != 2 {
+		t.Errorf("expected quiet hours to suppress high severity")
+	}
+	_, _ = r.Route(&Notification{Event: "crash", Subject: "fire", Severity: SeverityCritical})
+	if len(*sent) != 3 {
+		t.Errorf("expected critical to break through quiet hours")
+	}
+}
+
+// TestRoutersShareState routes through two routers at once, as the daemon
+// and a gt command do, and checks that neither loses the other's pending
+// notifications.
+func TestRoutersShareState(t *testing.T) {
+	rules := testRules()
+	rules.Rules = []Rule{{
+		Name:       "escalations",
+		Match:      Match{Events: []string{"escalation"}},
+		Channels:   []string{"ops"},
+		Escalation: []EscalationStep{{After: "15m", Channels: []string{"pager"}}},
+	}}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)
+	daemon, _ := newTestRouter(t, rules, &now)
+	cli, err := NewRouter(daemon.townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli.deliver = func(string, Channel, *Notification, string) error { return nil }
+	cli.now = daemon.now
+
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		r := daemon
+		if i%2 == 1 {
+			r = cli
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := r.Route(&Notification{Event: "escalation", Subject: fmt.Sprintf("help %d", i)}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	pending, err := daemon.ListPending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 40 {
+		t.Errorf("%d pending notifications, want 40", len(pending))
+	}
+}
+
+func TestEscalationChainAndAck(t *testing.T) {
+	rules := testRules()
+	rules.Rules = []Rule{{
+		Name:       "escalations",
+		Match:      Match{Events: []string{"escalation"}},
+		Channels:   []string{"ops"},
+		Escalation: []EscalationStep{{After: "15m", Channels: []string{"pager"}}},
+	}}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)
+	r, sent := newTestRouter(t, rules, &now)
+
+	deliveries, err := r.Route(&Notification{Event: "escalation", Subject: "help"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := deliveries[0].Pending
+	if id == "" {
+		t.Fatal("expected pending ID for rule with escalation")
+	}
+
+	now = now.Add(5 * time.Minute)
+	if d, _ := r.Escalate(); len(d) != 0 {
+		t.Errorf("escalated too early")
+	}
+
+	now = now.Add(11 * time.Minute)
+	d, err := r.Escalate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d) != 1 || (*sent)[1].channel.Type != ChannelCommand {
+		t.Fatalf("expected escalation to pager, got %+v", d)
+	}
+	if pending, _ := r.ListPending(); len(pending) != 0 {
+		t.Errorf("exhausted chain should be dropped, got %d pending", len(pending))
+	}
+
+	// A second notification acked before escalation never pages.
+	deliveries, _ = r.Route(&Notification{Event: "escalation", Subject: "again"})
+	if err := r.Ack(deliveries[0].Pending); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	now = now.Add(time.Hour)
+	if d, _ := r.Escalate(); len(d) != 0 {
+		t.Errorf("acked notification escalated")
+	}
+}
+
+func TestValidateRejectsUnknownChannel(t *testing.T) {
+	rules := testRules()
+	rules.Rules = []Rule{{Name: "bad", Channels: []string{"nope"}}}
+	if err := rules.Validate(); err == nil {
+		t.Error("expected error for unknown channel")
+	}
+}
+
+func TestRouteSkipsRigIgnoredEvents(t *testing.T) {
+	rules := testRules()
+	rules.Rules = []Rule{{Name: "all", Channels: []string{"ops"}}}
+	now := time.Now()
+	r, sent := newTestRouter(t, rules, &now)
+	r.ignores = config.IgnoreRules{"gastown": {{Events: []string{"nudge"}, Agents: []string{"janitor"}}}}
+
+	_, _ = r.Route(&Notification{Event: "nudge", Source: "gastown/polecats/janitor", Subject: "poke"})
+	if len(*sent) != 0 {
+		t.Fatalf("ignored event was delivered")
+	}
+	_, _ = r.Route(&Notification{Event: "crash", Source: "gastown/polecats/janitor", Subject: "died"})
+	_, _ = r.Route(&Notification{Event: "nudge", Source: "gastown/crew/max", Subject: "poke"})
+	if len(*sent) != 2 {
+		t.Errorf("expected non-ignored events delivered, got %d", len(*sent))
+	}
+}
+
+func TestRouteBatchesOutsideFocus(t *testing.T) {
+	rules := testRules()
+	rules.Rules = []Rule{{Name: "all", Channels: []string{"ops"}}}
+	now := time.Now()
+	r, sent := newTestRouter(t, rules, &now)
+	r.focus = &focus.Focus{Rig: "gastown"}
+
+	_, _ = r.Route(&Notification{Event: "done", Rig: "beads", Subject: "finished"})
+	_, _ = r.Route(&Notification{Event: "nudge", Source: "beads/crew/joe", Subject: "poke"})
+	if len(*sent) != 0 {
+		t.Fatalf("notifications outside the focus were delivered immediately")
+	}
+	_, _ = r.Route(&Notification{Event: "done", Rig: "gastown", Subject: "finished"})
+	_, _ = r.Route(&Notification{Event: "crash", Rig: "beads", Severity: SeverityHigh, Subject: "died"})
+	_, _ = r.Route(&Notification{Event: "escalation", Source: "mayor/", Subject: "help"})
+	if len(*sent) != 3 {
+		t.Fatalf("focused, urgent, and town-level notifications should interrupt; got %d", len(*sent))
+	}
+
+	// Not due yet
+	if d, _ := r.FlushBatched(false); len(d) != 0 {
+		t.Errorf("batch flushed before BatchInterval")
+	}
+	now = now.Add(focus.BatchInterval)
+	d, err := r.FlushBatched(false)
+	if err != nil || len(d) != 1 || len(*sent) != 4 {
+		t.Fatalf("FlushBatched = %v, %v; sent %d", d, err, len(*sent))
+	}
+	if d, _ := r.FlushBatched(true); len(d) != 0 {
+		t.Errorf("batch delivered twice")
+	}
+}
@@ -0,0 +1,346 @@
+// Package notify routes town notifications to delivery channels.
+//
+// Routing rules live in config/notify.json and map notifications (by event
+// type, rig, label, and severity) to named channels. Rules can suppress
+// delivery during quiet hours, dedup repeats inside a window, and escalate
+// to further channels when a notification is not acknowledged in time.
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// CurrentRulesVersion is the current schema version for the rules config.
+const CurrentRulesVersion = 1
+
+// Channel types.
+const (
+	ChannelMail    = "mail"    // Target is a mail address (e.g., "overseer", "list:oncall")
+	ChannelNudge   = "nudge"   // Target is a tmux session name (e.g., "gt-mayor")
+	ChannelCommand = "command" // Target is a shell command; notification is passed via GT_NOTIFY_* env
+
+	// On-call backends open an incident per condition and resolve it when
+	// the condition clears. Target is the integration key, or "env:VAR".
+	ChannelPagerDuty = "pagerduty" // Events API v2 routing key
+	ChannelOpsgenie  = "opsgenie"  // Alert API key
+)
+
+// Errors returned by the rules config.
+var (
+	ErrRuleNotFound    = errors.New("rule not found")
+	ErrChannelNotFound = errors.New("channel not found")
+)
+
+// Channel is a named delivery destination.
+type Channel struct {
+	Type   string `json:"type"`          // mail, nudge, command, pagerduty, or opsgenie
+	Target string `json:"target"`        // address, session, command line, or API key
+	URL    string `json:"url,omitempty"` // on-call API endpoint override (e.g., Opsgenie EU)
+}
+
+// Match selects which notifications a rule applies to.
+// Empty fields match everything.
+type Match struct {
+	Events      []string `json:"events,omitempty"`       // event types (e.g., "crash", "escalation")
+	Rigs        []string `json:"rigs,omitempty"`         // rig names
+	Labels      []string `json:"labels,omitempty"`       // any label matches
+	MinSeverity string   `json:"min_severity,omitempty"` // low, medium, high, critical
+}
+
+// QuietHours suppresses a rule's notifications during a daily window.
+// Notifications at or above AllowSeverity are still delivered.
+type QuietHours struct {
+	Start         string `json:"start"`                    // "22:00" (local time)
+	End           string `json:"end"`                      // "07:00" (may wrap past midnight)
+	AllowSeverity string `json:"allow_severity,omitempty"` // default: critical
+}
+
+// EscalationStep notifies further channels if the notification is still
+// unacknowledged After the previous step.
+type EscalationStep struct {
+	After    string   `json:"after"`    // e.g., "15m"
+	Channels []string `json:"channels"` // channel names
+}
+
+// Rule maps matching notifications to channels.
+type Rule struct {
+	Name       string           `json:"name"`
+	Match      Match            `json:"match"`
+	Channels   []string         `json:"channels"`
+	QuietHours *QuietHours      `json:"quiet_hours,omitempty"`
+	Dedup      string           `json:"dedup,omitempty"` // window, e.g., "10m"
+	Escalation []EscalationStep `json:"escalation,omitempty"`
+}
+
+// Rules is the routing configuration (config/notify.json).
+type Rules struct {
+	Type     string             `json:"type"`    // "notify-rules"
+	Version  int                `json:"version"` // schema version
+	Channels map[string]Channel `json:"channels,omitempty"`
+	Rules    []Rule             `json:"rules,omitempty"`
+}
+
+// RulesPath returns the path to the rules config in a town.
+func RulesPath(townRoot string) string {
+	return filepath.Join(townRoot, "config", "notify.json")
+}
+
+// NewRules returns an empty rules config.
+func NewRules() *Rules {
+	return &Rules{
+		Type:     "notify-rules",
+		Version:  CurrentRulesVersion,
+		Channels: make(map[string]Channel),
+	}
+}
+
+// LoadRules loads the town's routing rules. A missing file yields empty rules.
+func LoadRules(townRoot string) (*Rules, error) {
+	data, err := os.ReadFile(RulesPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewRules(), nil
+		}
+		return nil, fmt.Errorf("reading notify rules: %w", err)
+	}
+
+	var r Rules
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing notify rules: %w", err)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// SaveRules validates and writes the routing rules.
+func SaveRules(townRoot string, r *Rules) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(RulesPath(townRoot)), 0755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	return util.AtomicWriteJSON(RulesPath(townRoot), r)
+}
+
+// Validate checks the rules for consistency.
+func (r *Rules) Validate() error {
+	if r.Type != "notify-rules" && r.Type != "" {
+		return fmt.Errorf("invalid type: expected 'notify-rules', got '%s'", r.Type)
+	}
+	if r.Version > CurrentRulesVersion {
+		return fmt.Errorf("invalid version: got %d, max supported %d", r.Version, CurrentRulesVersion)
+	}
+	if r.Channels == nil {
+		r.Channels = make(map[string]Channel)
+	}
+
+	for name, ch := range r.Channels {
+		if !IsValidChannelType(ch.Type) {
+			return fmt.Errorf("channel '%s': unknown type '%s'", name, ch.Type)
+		}
+		if ch.Target == "" {
+			return fmt.Errorf("channel '%s': target is required", name)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, rule := range r.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("rule name is required")
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("duplicate rule '%s'", rule.Name)
+		}
+		seen[rule.Name] = true
+
+		if len(rule.Channels) == 0 {
+			return fmt.Errorf("rule '%s': at least one channel is required", rule.Name)
+		}
+		if err := r.checkChannels(rule.Name, rule.Channels); err != nil {
+			return err
+		}
+		if rule.Match.MinSeverity != "" {
+			if _, err := ParseSeverity(rule.Match.MinSeverity); err != nil {
+				return fmt.Errorf("rule '%s': %w", rule.Name, err)
+			}
+		}
+		if rule.Dedup != "" {
+			if _, err := time.ParseDuration(rule.Dedup); err != nil {
+				return fmt.Errorf("rule '%s': invalid dedup window: %w", rule.Name, err)
+			}
+		}
+		if q := rule.QuietHours; q != nil {
+			if _, _, err := q.bounds(); err != nil {
+				return fmt.Errorf("rule '%s': %w", rule.Name, err)
+			}
+		}
+		for i, step := range rule.Escalation {
+			if _, err := time.ParseDuration(step.After); err != nil {
+				return fmt.Errorf("rule '%s': escalation step %d: invalid delay: %w", rule.Name, i+1, err)
+			}
+			if err := r.checkChannels(rule.Name, step.Channels); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Rules) checkChannels(rule string, channels []string) error {
+	for _, name := range channels {
+		if _, ok := r.Channels[name]; !ok {
+			return fmt.Errorf("rule '%s': %w: %s", rule, ErrChannelNotFound, name)
+		}
+	}
+	return nil
+}
+
+// IsValidChannelType reports whether t is a known channel type.
+func IsValidChannelType(t string) bool {
+	switch t {
+	case ChannelMail, ChannelNudge, ChannelCommand, ChannelPagerDuty, ChannelOpsgenie:
+		return true
+	}
+	return false
+}
+
+// SetRule adds a rule, replacing any existing rule with the same name.
+func (r *Rules) SetRule(rule Rule) {
+	for i := range r.Rules {
+		if r.Rules[i].Name == rule.Name {
+			r.Rules[i] = rule
+			return
+		}
+	}
+	r.Rules = append(r.Rules, rule)
+}
+
+// RemoveRule deletes a rule by name.
+func (r *Rules) RemoveRule(name string) error {
+	for i := range r.Rules {
+		if r.Rules[i].Name == name {
+			r.Rules = append(r.Rules[:i], r.Rules[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrRuleNotFound, name)
+}
+
+// GetRule returns a rule by name.
+func (r *Rules) GetRule(name string) *Rule {
+	for i := range r.Rules {
+		if r.Rules[i].Name == name {
+			return &r.Rules[i]
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the rule applies to n.
+func (rule *Rule) Matches(n *Notification) bool {
+	m := rule.Match
+	if len(m.Events) > 0 && !containsFold(m.Events, n.Event) {
+		return false
+	}
+	if len(m.Rigs) > 0 && !containsFold(m.Rigs, n.Rig) {
+		return false
+	}
+	if len(m.Labels) > 0 {
+		found := false
+		for _, l := range n.Labels {
+			if containsFold(m.Labels, l) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if m.MinSeverity != "" {
+		min, _ := ParseSeverity(m.MinSeverity)
+		if n.Severity < min {
+			return false
+		}
+	}
+	return true
+}
+
+// Active reports whether t falls inside the quiet window.
+func (q *QuietHours) Active(t time.Time) bool {
+	start, end, err := q.bounds()
+	if err != nil || start == end {
+		return false
+	}
+	mins := t.Hour()*60 + t.Minute()
+	if start < end {
+		return mins >= start && mins < end
+	}
+	// Window wraps past midnight (e.g., 22:00-07:00).
+	return mins >= start || mins < end
+}
+
+// Allows reports whether a notification of severity s breaks through quiet hours.
+func (q *QuietHours) Allows(s Severity) bool {
+	allow := SeverityCritical
+	if q.AllowSeverity != "" {
+		if parsed, err := ParseSeverity(q.AllowSeverity); err == nil {
+			allow = parsed
+		}
+	}
+	return s >= allow
+}
+
+// bounds returns the window as minutes since midnight.
+func (q *QuietHours) bounds() (int, int, error) {
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid quiet hours start: %w", err)
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid quiet hours end: %w", err)
+	}
+	return start, end, nil
+}
+
+// ParseQuietHours parses a "HH:MM-HH:MM" window.
+func ParseQuietHours(s string) (*QuietHours, error) {
+	start, end, ok := strings.Cut(s, "-")
+	if !ok {
+		return nil, fmt.Errorf("quiet hours must be HH:MM-HH:MM, got %q", s)
+	}
+	q := &QuietHours{Start: strings.TrimSpace(start), End: strings.TrimSpace(end)}
+	if _, _, err := q.bounds(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if item == "*" || strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+// Package oplock coordinates mutating gt commands across processes, so
+// operators, cron jobs, and hooks running gt at the same time do not trip
+// over each other.
+//
+// A town has a fixed number of operation slots, one file each under
+// <town>/.runtime/oplock/, held with flock(2). A command runs while it
+// holds a slot; with one slot (the default) mutating commands run one at a
+// time. The kernel releases a slot when its holder exits, however it
+// exits, so there are no stale locks. The slot file records who holds it,
+// for gt locks.
+package oplock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrBusy means every slot stayed held for as long as the command waited.
+var ErrBusy = errors.New("town is busy")
+
+// EnvHeld is set, to "<pid>:<slot>", in the environment of a process that
+// holds a slot. gt commands it runs, such as hooks or the rig starts of
+// gt up, share their parent's slot instead of waiting on it.
+const EnvHeld = "GT_OPLOCK_HELD"
+
+// pollInterval is how often a waiting command retries the slots.
+const pollInterval = 100 * time.Millisecond
+
+// Holder describes the command holding a slot.
+type Holder struct {
+	Slot    int       `json:"slot"`
+	PID     int       `json:"pid"`
+	Command string    `json:"command"`
+	User    string    `json:"user,omitempty"`
+	Host    string    `json:"host,omitempty"`
+	Since   time.Time `json:"since"`
+}
+
+// Options configures Acquire.
+type Options struct {
+	// Slots is how many commands may hold the lock at once (minimum 1).
+	Slots int
+
+	// Wait is how long to wait for a free slot: 0 fails at once, and a
+	// negative duration waits until ctx is done.
+	Wait time.Duration
+
+	// Command describes the caller, e.g. "gt rig add gastown".
+	Command string
+
+	// OnWait, if set, is called once with the current holders when the
+	// caller has to wait.
+	OnWait func([]Holder)
+}
+
+// Lock is a held slot.
+type Lock struct {
+	f    *os.File
+	slot int
+}
+
+// Dir returns the directory of a town's slot files.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "oplock")
+}
+
+func slotPath(townRoot string, slot int) string {
+	return filepath.Join(Dir(townRoot), fmt.Sprintf("slot-%d.lock", slot))
+}
+
+// Acquire takes a free slot, waiting for one as opts.Wait allows. It
+// returns an error wrapping ErrBusy, and naming the holders, if none came
+// free in time. A process whose parent holds a slot (see EnvHeld) gets a
+// Lock that shares it.
+func Acquire(ctx context.Context, townRoot string, opts Options) (*Lock, error) {
+	if inheritedSlot(townRoot) {
+		return &Lock{}, nil
+	}
+	slots := max(opts.Slots, 1)
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return nil, fmt.Errorf("creating lock directory: %w", err)
+	}
+
+	var deadline <-chan time.Time
+	if opts.Wait > 0 {
+		timer := time.NewTimer(opts.Wait)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	waited := false
+	for {
+		for slot := 1; slot <= slots; slot++ {
+			l, err := tryAcquire(townRoot, slot, opts.Command)
+			if err != nil {
+				return nil, err
+			}
+			if l != nil {
+				return l, nil
+			}
+		}
+
+		if opts.Wait == 0 {
+			return nil, busyError(townRoot)
+		}
+		if !waited && opts.OnWait != nil {
+			holders, _ := Holders(townRoot)
+			opts.OnWait(holders)
+		}
+		waited = true
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline:
+			return nil, busyError(townRoot)
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// tryAcquire takes slot if it is free, and returns nil if it is not.
+func tryAcquire(townRoot string, slot int, command string) (*Lock, error) {
+	f, err := os.OpenFile(slotPath(townRoot, slot), os.O_CREATE|os.O_RDWR, 0644) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		return nil, fmt.Errorf("opening lock slot: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("locking slot %d: %w", slot, err)
+	}
+
+	h := Holder{Slot: slot, PID: os.Getpid(), Command: command, Since: time.Now()}
+	if u, err := user.Current(); err == nil {
+		h.User = u.Username
+	}
+	h.Host, _ = os.Hostname()
+	data, _ := json.Marshal(h)
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt(append(data, '\n'), 0)
+	}
+
+	_ = os.Setenv(EnvHeld, fmt.Sprintf("%d:%d", h.PID, slot))
+	return &Lock{f: f, slot: slot}, nil
+}
+
+// inheritedSlot reports whether EnvHeld names a slot that its process
+// still holds. A stale value, left by a parent that has exited, does not
+// count.
+func inheritedSlot(townRoot string) bool {
+	pidStr, slotStr, ok := strings.Cut(os.Getenv(EnvHeld), ":")
+	if !ok {
+		return false
+	}
+	pid, err1 := strconv.Atoi(pidStr)
+	slot, err2 := strconv.Atoi(slotStr)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	h, held := readSlot(townRoot, slot)
+	return held && h.PID == pid
+}
+
+// Release frees the slot. It is safe to call on a nil or shared Lock.
+func (l *Lock) Release() {
+	if l == nil || l.f == nil {
+		return
+	}
+	_ = l.f.Truncate(0)
+	_ = syscall.Flock(int(l.f.Fd()), syscall.LOCK_UN)
+	l.f.Close()
+	l.f = nil
+	_ = os.Unsetenv(EnvHeld)
+}
+
+// Holders returns the commands holding slots, by slot.
+func Holders(townRoot string) ([]Holder, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading lock directory: %w", err)
+	}
+	var holders []Holder
+	for _, e := range entries {
+		var slot int
+		if _, err := fmt.Sscanf(e.Name(), "slot-%d.lock", &slot); err != nil {
+			continue
+		}
+		if h, held := readSlot(townRoot, slot); held {
+			holders = append(holders, h)
+		}
+	}
+	sort.Slice(holders, func(i, j int) bool { return holders[i].Slot < holders[j].Slot })
+	return holders, nil
+}
+
+// readSlot returns a slot's holder, and whether it is held at all. A held
+// slot whose holder has not finished recording itself has only Slot set.
+func readSlot(townRoot string, slot int) (Holder, bool) {
+	h := Holder{Slot: slot}
+	f, err := os.Open(slotPath(townRoot, slot)) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		return h, false
+	}
+	defer f.Close()
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_SH|syscall.LOCK_NB); err == nil {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		return h, false
+	}
+	data := make([]byte, 4096)
+	n, _ := f.Read(data)
+	_ = json.Unmarshal(data[:n], &h)
+	h.Slot = slot
+	return h, true
+}
+
+// busyError describes why Acquire gave up.
+func busyError(townRoot string) error {
+	holders, _ := Holders(townRoot)
+	if len(holders) == 0 {
+		return ErrBusy
+	}
+	var running []string
+	for _, h := range holders {
+		running = append(running, Describe(h))
+	}
+	return fmt.Errorf("%w: running %s", ErrBusy, strings.Join(running, "; "))
+}
+
+// Describe summarizes a holder in one line.
+func Describe(h Holder) string {
+	s := h.Command
+	if s == "" {
+		s = "gt"
+	}
+	s += fmt.Sprintf(" (pid %d", h.PID)
+	if h.User != "" {
+		s += ", " + h.User
+	}
+	if !h.Since.IsZero() {
+		s += fmt.Sprintf(", for %s", time.Since(h.Since).Round(time.Second))
+	}
+	return s + ")"
+}
@@ -0,0 +1,122 @@
+package oplock
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAcquireSlots(t *testing.T) {
+	t.Setenv(EnvHeld, "")
+	townRoot := t.TempDir()
+	ctx := context.Background()
+
+	a, err := Acquire(ctx, townRoot, Options{Slots: 2, Command: "gt up"})
+	if err != nil {
+		t.Fatalf("Acquire(a): %v", err)
+	}
+	os.Unsetenv(EnvHeld) // act as a separate process
+	b, err := Acquire(ctx, townRoot, Options{Slots: 2, Command: "gt sling"})
+	if err != nil {
+		t.Fatalf("Acquire(b): %v", err)
+	}
+	os.Unsetenv(EnvHeld)
+
+	_, err = Acquire(ctx, townRoot, Options{Slots: 2, Command: "gt rig add"})
+	if !errors.Is(err, ErrBusy) {
+		t.Fatalf("third Acquire with 2 slots: err = %v, want ErrBusy", err)
+	}
+	if !strings.Contains(err.Error(), "gt up (pid") || !strings.Contains(err.Error(), "gt sling") {
+		t.Errorf("busy error should name the holders: %v", err)
+	}
+
+	holders, err := Holders(townRoot)
+	if err != nil || len(holders) != 2 || holders[0].Command != "gt up" || holders[1].Slot != 2 {
+		t.Fatalf("Holders() = %+v, %v", holders, err)
+	}
+	if holders[0].PID != os.Getpid() || holders[0].Since.IsZero() {
+		t.Errorf("holder not recorded: %+v", holders[0])
+	}
+
+	a.Release()
+	a.Release() // harmless twice
+	c, err := Acquire(ctx, townRoot, Options{Slots: 2, Command: "gt rig add"})
+	if err != nil {
+		t.Fatalf("Acquire after Release: %v", err)
+	}
+	b.Release()
+	c.Release()
+	if holders, _ := Holders(townRoot); len(holders) != 0 {
+		t.Errorf("Holders() after releasing all = %+v", holders)
+	}
+}
+
+func TestAcquireWaits(t *testing.T) {
+	t.Setenv(EnvHeld, "")
+	townRoot := t.TempDir()
+	ctx := context.Background()
+
+	a, err := Acquire(ctx, townRoot, Options{Command: "gt down"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Unsetenv(EnvHeld)
+
+	start := time.Now()
+	if _, err := Acquire(ctx, townRoot, Options{Wait: 300 * time.Millisecond}); !errors.Is(err, ErrBusy) {
+		t.Fatalf("Acquire(wait 300ms) = %v, want ErrBusy", err)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("gave up after %v, before the wait ran out", elapsed)
+	}
+
+	var waitedOn []Holder
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		a.Release()
+	}()
+	b, err := Acquire(ctx, townRoot, Options{Wait: -1, OnWait: func(h []Holder) { waitedOn = h }})
+	if err != nil {
+		t.Fatalf("Acquire(wait forever): %v", err)
+	}
+	defer b.Release()
+	if len(waitedOn) != 1 || waitedOn[0].Command != "gt down" {
+		t.Errorf("OnWait got %+v, want the gt down holder", waitedOn)
+	}
+}
+
+func TestAcquireInherited(t *testing.T) {
+	t.Setenv(EnvHeld, "")
+	townRoot := t.TempDir()
+	ctx := context.Background()
+
+	parent, err := Acquire(ctx, townRoot, Options{Command: "gt up"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A child gt sees EnvHeld and shares the slot rather than deadlocking.
+	child, err := Acquire(ctx, townRoot, Options{})
+	if err != nil {
+		t.Fatalf("child Acquire: %v", err)
+	}
+	child.Release()
+	if holders, _ := Holders(townRoot); len(holders) != 1 {
+		t.Errorf("releasing the shared lock freed the parent's slot")
+	}
+
+	env := os.Getenv(EnvHeld)
+	parent.Release()
+	// Once the parent is gone, a leftover EnvHeld is ignored.
+	t.Setenv(EnvHeld, env)
+	other, err := Acquire(ctx, townRoot, Options{Command: "gt sling"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Release()
+	if holders, _ := Holders(townRoot); len(holders) != 1 || holders[0].Command != "gt sling" {
+		t.Errorf("stale EnvHeld was honored: holders = %+v", holders)
+	}
+}
@@ -0,0 +1,246 @@
+// Package outputlog captures the raw output of agents: everything an
+// agent's tmux pane prints is piped (tmux pipe-pane) into 'gt
+// output-capture', which appends it to logs/output/<agent>.log, so a
+// crash leaves its last output behind even once the pane has closed.
+//
+// Capture is started as agent sessions are created, and the daemon starts
+// it for any agent session that lacks it. Logs are rotated at MaxSize,
+// keeping Keep older logs as <agent>.log.1 (the newest) up to
+// <agent>.log.<Keep>.
+package outputlog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// MaxSize is the size at which an agent's output log is rotated.
+const MaxSize = 10 * 1024 * 1024
+
+// Keep is how many rotated output logs are kept for each agent.
+const Keep = 3
+
+// Dir returns the directory of the town's output logs.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, "logs", "output")
+}
+
+// Path returns the output log of agent: logs/output/<agent>.log, e.g.
+// logs/output/gastown/crew/max.log. Polecats are filed under their short
+// address (see townlog.AgentKey), and path elements that could leave the
+// directory are replaced.
+func Path(townRoot, agent string) string {
+	parts := strings.Split(townlog.AgentKey(agent), "/")
+	for i, p := range parts {
+		if p == "" || p == "." || p == ".." || strings.ContainsRune(p, filepath.Separator) {
+			parts[i] = "_"
+		}
+	}
+	parts[len(parts)-1] += ".log"
+	return filepath.Join(append([]string{Dir(townRoot)}, parts...)...)
+}
+
+// Writer appends to an output log, rotating it once it would grow past
+// MaxSize.
+type Writer struct {
+	path    string
+	file    *os.File
+	size    int64
+	maxSize int64
+}
+
+// Open opens the output log at path for appending, creating it and its
+// directory if need be.
+func Open(path string) (*Writer, error) {
+	w := &Writer{path: path, maxSize: MaxSize}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600) //nolint:gosec // G304: path is under the town's log dir
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	w.file, w.size = f, info.Size()
+	return nil
+}
+
+// Write appends p, first rotating the log if p would take it past MaxSize.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the rotated logs up by one, dropping the oldest, and
+// starts a new log.
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	for i := Keep - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", w.path, i), fmt.Sprintf("%s.%d", w.path, i+1))
+	}
+	if err := os.Rename(w.path, w.path+".1"); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+// Close closes the log.
+func (w *Writer) Close() error {
+	return w.file.Close()
+}
+
+// Command returns the shell command tmux pipes agent's pane into.
+func Command(townRoot, agent string) string {
+	return fmt.Sprintf("cd %s && exec gt output-capture %s", util.ShellQuote(townRoot), util.ShellQuote(agent))
+}
+
+// Start starts capturing the output of agent's session, unless it is
+// already being captured.
+func Start(t *tmux.Tmux, townRoot, sessionName, agent string) error {
+	if err := t.PipePane(sessionName, Command(townRoot, agent)); err != nil {
+		return fmt.Errorf("capturing output of %s: %w", agent, err)
+	}
+	return nil
+}
+
+// StartAll starts capturing the output of each of the sessions not yet
+// captured, given with their agents, and returns the agents it started
+// capturing.
+func StartAll(t *tmux.Tmux, townRoot string, agents map[string]string) ([]string, error) {
+	names := make([]string, 0, len(agents))
+	for name := range agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var started, errs []string
+	for _, name := range names {
+		if piped, err := t.IsPanePiped(name); err != nil || piped {
+			continue
+		}
+		if err := Start(t, townRoot, name, agents[name]); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		started = append(started, agents[name])
+	}
+	if len(errs) > 0 {
+		return started, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return started, nil
+}
+
+// Tail returns the last n lines of the output log at path, or all of it
+// for n <= 0.
+func Tail(path string, n int) ([]byte, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the town's log dir
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return data, nil
+	}
+	end := len(data)
+	if end > 0 && data[end-1] == '\n' {
+		end--
+	}
+	for i := 0; i < n; i++ {
+		nl := bytes.LastIndexByte(data[:end], '\n')
+		if nl < 0 {
+			return data, nil
+		}
+		end = nl
+	}
+	return data[end+1:], nil
+}
+
+// FollowInterval is how often Follow polls the log for new output.
+const FollowInterval = 250 * time.Millisecond
+
+// Follow copies output appended to the log at path to w from offset on
+// until ctx is done, picking up the new log when it is rotated. A log that
+// does not exist yet is waited for.
+func Follow(ctx context.Context, path string, offset int64, w io.Writer) error {
+	var f *os.File
+	var info os.FileInfo
+	defer func() {
+		if f != nil {
+			_ = f.Close()
+		}
+	}()
+	open := func() error {
+		var err error
+		if f, err = os.Open(path); err != nil { //nolint:gosec // G304: path is under the town's log dir
+			f = nil
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info, err = f.Stat(); err != nil {
+			return err
+		}
+		_, err = f.Seek(offset, io.SeekStart)
+		return err
+	}
+	if err := open(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(FollowInterval)
+	defer ticker.Stop()
+	for {
+		if f != nil {
+			if _, err := io.Copy(w, f); err != nil {
+				return err
+			}
+			if current, err := os.Stat(path); err == nil && !os.SameFile(current, info) {
+				// Rotated: finish the old log, then read the new one from the start
+				if _, err := io.Copy(w, f); err != nil {
+					return err
+				}
+				_ = f.Close()
+				f, offset = nil, 0
+			}
+		}
+		if f == nil {
+			if err := open(); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package outputlog
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPath(t *testing.T) {
+	if got, want := Path("/town", "gastown/polecats/Toast"), "/town/logs/output/gastown/Toast.log"; got != want {
+		t.Errorf("Path = %q, want %q", got, want)
+	}
+	if got := Path("/town", "../../etc"); !strings.HasPrefix(got, "/town/logs/output/") {
+		t.Errorf("Path escaped the output dir: %q", got)
+	}
+}
+
+func TestWriterRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "output", "gastown", "crew", "max.log")
+	w, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.maxSize = 10
+	for _, chunk := range []string{"first\n", "second\n", "third\n", "fourth\n", "fifth\n"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for suffix, want := range map[string]string{"": "fifth\n", ".1": "fourth\n", ".2": "third\n", ".3": "second\n"} {
+		data, err := os.ReadFile(path + suffix)
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", filepath.Base(path+suffix), data, err, want)
+		}
+	}
+	if _, err := os.Stat(path + ".4"); !os.IsNotExist(err) {
+		t.Errorf("kept more than %d rotated logs", Keep)
+	}
+}
+
+func TestTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "max.log")
+	if err := os.WriteFile(path, []byte("one\ntwo\nthree\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for n, want := range map[int]string{0: "one\ntwo\nthree\n", 2: "two\nthree\n", 5: "one\ntwo\nthree\n"} {
+		if got, err := Tail(path, n); err != nil || string(got) != want {
+			t.Errorf("Tail(%d) = %q, %v; want %q", n, got, err, want)
+		}
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe to read while Follow writes to it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "max.log")
+	w, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.maxSize = 20
+	_, _ = w.Write([]byte("already there\n"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var out syncBuffer
+	done := make(chan error)
+	go func() { done <- Follow(ctx, path, 14, &out) }()
+	time.Sleep(50 * time.Millisecond) // let Follow open the log before it rotates
+
+	_, _ = w.Write([]byte("new\n"))
+	_, _ = w.Write([]byte("after rotation\n"))
+	deadline := time.Now().Add(5 * time.Second)
+	for out.String() != "new\nafter rotation\n" {
+		if time.Now().After(deadline) {
+			t.Fatalf("followed %q", out.String())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,380 @@
+// Package pending keeps spawns that wait on other agents: 'gt spawn
+// <agent> --after <other>' records a pending spawn, and the daemon, which
+// follows the town log, spawns the agent once every agent it waits on has
+// logged a done event.
+//
+// Spawns that would exceed the town's max_concurrent_agents are queued
+// the same way, waiting for a slot rather than on agents: the daemon
+// starts them as others finish, highest priority first and, among equals,
+// oldest first.
+//
+// Pending spawns are kept in the town's .runtime/pending-spawns.json,
+// with the dependencies already satisfied, so they survive daemon
+// restarts; done events logged while the daemon was down are caught up on
+// when it starts.
+package pending
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/preempt"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// EventSpawnAfter is the town log event recorded when a pending spawn is
+// triggered, with error severity when the spawn failed.
+const EventSpawnAfter townlog.EventType = "spawn_after"
+
+// EventSpawnQueue is the town log event recorded when a spawn is queued at
+// the concurrency limit, and when a queued spawn is started or cancelled.
+const EventSpawnQueue townlog.EventType = "spawn_queue"
+
+// Spawn is an agent to spawn once the agents it waits on are done.
+type Spawn struct {
+	ID        string    `json:"id"`               // e.g. "pend-3"
+	Agent     string    `json:"agent"`            // e.g. "gastown/crew/tester"
+	After     []string  `json:"after"`            // agents whose done events it waits on
+	Queued    bool      `json:"queued,omitempty"` // waits for a slot under max_concurrent_agents instead
+	Done      []string  `json:"done,omitempty"`
+	Template  string    `json:"template,omitempty"`
+	Role      string    `json:"role,omitempty"`
+	Account   string    `json:"account,omitempty"`
+	Runtime   string    `json:"runtime,omitempty"`
+	Sandbox   string    `json:"sandbox,omitempty"`
+	Host      string    `json:"host,omitempty"`
+	Priority  string    `json:"priority,omitempty"`
+	Labels    []string  `json:"labels,omitempty"` // key=value
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+
+	MaxRuntime string `json:"max_runtime,omitempty"` // e.g. "4h"
+	Hard       bool   `json:"hard,omitempty"`        // kill at MaxRuntime rather than hand off
+}
+
+// Waiting returns the agents the spawn still waits on.
+func (s Spawn) Waiting() []string {
+	var out []string
+	for _, a := range s.After {
+		if !slices.Contains(s.Done, a) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// observe records e if it is a done event of an agent s waits on, logged
+// since s was declared. It reports whether s is now ready to spawn.
+func (s *Spawn) observe(e townlog.Event) bool {
+	if e.Type != townlog.EventDone || e.Timestamp.Before(s.CreatedAt.Truncate(time.Second)) {
+		return false
+	}
+	key := townlog.AgentKey(e.Agent)
+	for _, a := range s.Waiting() {
+		if townlog.AgentKey(a) == key {
+			s.Done = append(s.Done, a)
+			return len(s.Waiting()) == 0
+		}
+	}
+	return false
+}
+
+// Args returns the gt arguments that spawn the agent.
+func (s Spawn) Args() []string {
+	args := []string{"spawn", s.Agent}
+	if s.Template != "" {
+		args = append(args, "--template", s.Template)
+	}
+	if s.Role != "" {
+		args = append(args, "--role", s.Role)
+	}
+	if s.Account != "" {
+		args = append(args, "--account", s.Account)
+	}
+	if s.Runtime != "" {
+		args = append(args, "--runtime", s.Runtime)
+	}
+	if s.Sandbox != "" {
+		args = append(args, "--sandbox", s.Sandbox)
+	}
+	if s.Host != "" {
+		args = append(args, "--host", s.Host)
+	}
+	if s.Priority != "" {
+		args = append(args, "--priority", s.Priority)
+	}
+	for _, l := range s.Labels {
+		args = append(args, "--label", l)
+	}
+	if s.MaxRuntime != "" {
+		args = append(args, "--max-runtime", s.MaxRuntime)
+		if s.Hard {
+			args = append(args, "--hard")
+		}
+	}
+	return args
+}
+
+// Path returns where a town's pending spawns are kept.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "pending-spawns.json")
+}
+
+// Load returns the town's pending spawns, ordered by ID.
+func Load(townRoot string) ([]Spawn, error) {
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var list []Spawn
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", Path(townRoot), err)
+	}
+	sort.SliceStable(list, func(i, k int) bool { return idNumber(list[i].ID) < idNumber(list[k].ID) })
+	return list, nil
+}
+
+func save(townRoot string, list []Spawn) error {
+	if err := os.MkdirAll(filepath.Dir(Path(townRoot)), 0755); err != nil {
+		return err
+	}
+	if list == nil {
+		list = []Spawn{}
+	}
+	return util.AtomicWriteJSON(Path(townRoot), list)
+}
+
+// locked runs fn holding the town's pending-spawns lock. Every
+// load-modify-save runs under it, so a CLI Add cannot be lost to the
+// daemon's Observe or Dequeue saving the list it loaded before.
+func locked(townRoot string, fn func() error) error {
+	return util.WithFileLock(Path(townRoot)+".lock", fn)
+}
+
+// Add gives s the next free ID and saves it.
+func Add(townRoot string, s Spawn) (Spawn, error) {
+	if len(s.After) == 0 && !s.Queued {
+		return Spawn{}, fmt.Errorf("a pending spawn must wait on at least one agent or for a slot")
+	}
+	for _, a := range s.After {
+		if townlog.AgentKey(a) == townlog.AgentKey(s.Agent) {
+			return Spawn{}, fmt.Errorf("%s cannot wait on itself", s.Agent)
+		}
+	}
+	err := locked(townRoot, func() error {
+		list, err := Load(townRoot)
+		if err != nil {
+			return err
+		}
+		max := 0
+		for _, other := range list {
+			if n := idNumber(other.ID); n > max {
+				max = n
+			}
+		}
+		s.ID = fmt.Sprintf("pend-%d", max+1)
+		return save(townRoot, append(list, s))
+	})
+	if err != nil {
+		return Spawn{}, err
+	}
+	return s, nil
+}
+
+// idNumber returns the number of a pending spawn ID, or 0 if it has none.
+func idNumber(id string) int {
+	var n int
+	_, _ = fmt.Sscanf(id, "pend-%d", &n)
+	return n
+}
+
+// Remove cancels the pending spawn with the given ID and returns it.
+func Remove(townRoot, id string) (Spawn, error) {
+	var removed Spawn
+	err := locked(townRoot, func() error {
+		list, err := Load(townRoot)
+		if err != nil {
+			return err
+		}
+		for i, s := range list {
+			if s.ID == id {
+				removed = s
+				return save(townRoot, append(list[:i:i], list[i+1:]...))
+			}
+		}
+		return fmt.Errorf("no pending spawn %s", id)
+	})
+	if err != nil {
+		return Spawn{}, err
+	}
+	return removed, nil
+}
+
+// Observe records events against the town's pending spawns and returns
+// those whose last dependency they satisfied, removing them: the caller
+// spawns them.
+func Observe(townRoot string, events ...townlog.Event) ([]Spawn, error) {
+	var ready []Spawn
+	err := locked(townRoot, func() error {
+		list, err := Load(townRoot)
+		if err != nil || len(list) == 0 {
+			return err
+		}
+		var waiting []Spawn
+		changed := false
+		for _, s := range list {
+			before := len(s.Done)
+			isReady := false
+			for _, e := range events {
+				if s.observe(e) {
+					isReady = true
+					break
+				}
+			}
+			changed = changed || len(s.Done) != before
+			if isReady {
+				ready = append(ready, s)
+			} else {
+				waiting = append(waiting, s)
+			}
+		}
+		if !changed {
+			return nil
+		}
+		return save(townRoot, waiting)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ready, nil
+}
+
+// Oldest returns when the oldest spawn waiting on agents was declared, or
+// the zero time if there are none.
+func Oldest(townRoot string) time.Time {
+	list, _ := Load(townRoot)
+	var oldest time.Time
+	for _, s := range list {
+		if len(s.After) > 0 && (oldest.IsZero() || s.CreatedAt.Before(oldest)) {
+			oldest = s.CreatedAt
+		}
+	}
+	return oldest
+}
+
+// Queue returns the queued spawns in the order they start: highest
+// priority first and, among equals, oldest first.
+func Queue(list []Spawn) []Spawn {
+	var queue []Spawn
+	for _, s := range list {
+		if s.Queued {
+			queue = append(queue, s)
+		}
+	}
+	sort.SliceStable(queue, func(i, k int) bool {
+		pi, pk := priority(queue[i]), priority(queue[k])
+		if pi != pk {
+			return pi.Outranks(pk)
+		}
+		return idNumber(queue[i].ID) < idNumber(queue[k].ID)
+	})
+	return queue
+}
+
+func priority(s Spawn) preempt.Priority {
+	if s.Priority == "" {
+		return preempt.Normal
+	}
+	return preempt.Priority(s.Priority)
+}
+
+// Dequeue removes the first n queued spawns (see Queue) and returns them:
+// the caller starts them.
+func Dequeue(townRoot string, n int) ([]Spawn, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	var next []Spawn
+	err := locked(townRoot, func() error {
+		list, err := Load(townRoot)
+		if err != nil {
+			return err
+		}
+		next = Queue(list)
+		if len(next) > n {
+			next = next[:n]
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		var rest []Spawn
+		for _, s := range list {
+			if !slices.ContainsFunc(next, func(q Spawn) bool { return q.ID == s.ID }) {
+				rest = append(rest, s)
+			}
+		}
+		return save(townRoot, rest)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// Run spawns the agent with 'gt spawn', from townRoot.
+func Run(townRoot string, s Spawn) error {
+	cmd := exec.Command("gt", s.Args()...) //nolint:gosec // G204: args come from the town's pending spawns
+	cmd.Dir = townRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if i := strings.LastIndex(msg, "\n"); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return fmt.Errorf("gt spawn: %v: %s", err, msg)
+	}
+	return nil
+}
+
+// Log records a triggered spawn in the town log; err is its failure, if
+// any.
+func Log(townRoot string, s Spawn, err error) {
+	e := townlog.Event{
+		Timestamp: time.Now(),
+		Type:      EventSpawnAfter,
+		Agent:     s.Agent,
+		Context:   fmt.Sprintf("%s: spawned after %s", s.ID, strings.Join(s.After, ", ")),
+	}
+	if err != nil {
+		e.Context = fmt.Sprintf("%s: spawn after %s failed: %v", s.ID, strings.Join(s.After, ", "), err)
+		e.Severity = townlog.SeverityError
+	}
+	_ = townlog.NewLogger(townRoot).LogEvent(e)
+}
+
+// LogQueue records a queueing decision about s in the town log, e.g.
+// "queued: max_concurrent_agents (4) reached"; err is the failure of a
+// queued spawn that was started, if any.
+func LogQueue(townRoot string, s Spawn, decision string, err error) {
+	e := townlog.Event{
+		Timestamp: time.Now(),
+		Type:      EventSpawnQueue,
+		Agent:     s.Agent,
+		Context:   fmt.Sprintf("%s: %s", s.ID, decision),
+	}
+	if err != nil {
+		e.Context += fmt.Sprintf(": %v", err)
+		e.Severity = townlog.SeverityError
+	}
+	_ = townlog.NewLogger(townRoot).LogEvent(e)
+}
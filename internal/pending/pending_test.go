@@ -0,0 +1,284 @@
+package pending
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+func done(agent string, at time.Time) townlog.Event {
+	return townlog.Event{Timestamp: at, Type: townlog.EventDone, Agent: agent}
+}
+
+func TestAddRemove(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	a, err := Add(townRoot, Spawn{Agent: "gastown/crew/tester", After: []string{"gastown/crew/impl"}, CreatedAt: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Add(townRoot, Spawn{Agent: "gastown/crew/docs", After: []string{"gastown/crew/impl"}, CreatedAt: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID != "pend-1" || b.ID != "pend-2" {
+		t.Errorf("IDs = %s, %s", a.ID, b.ID)
+	}
+	if _, err := Add(townRoot, Spawn{Agent: "gastown/crew/x"}); err == nil {
+		t.Error("Add accepted a spawn waiting on nothing")
+	}
+	if _, err := Add(townRoot, Spawn{Agent: "gastown/crew/x", After: []string{"gastown/crew/x/"}}); err == nil {
+		t.Error("Add accepted a spawn waiting on itself")
+	}
+
+	if _, err := Remove(townRoot, "pend-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Remove(townRoot, "pend-1"); err == nil {
+		t.Error("removed pend-1 twice")
+	}
+	list, _ := Load(townRoot)
+	if len(list) != 1 || list[0].ID != "pend-2" {
+		t.Errorf("Load after remove = %v", list)
+	}
+	if got := Oldest(townRoot); !got.Equal(now) {
+		t.Errorf("Oldest = %v, want %v", got, now)
+	}
+}
+
+func TestObserve(t *testing.T) {
+	townRoot := t.TempDir()
+	declared := time.Date(2026, 10, 14, 10, 0, 0, 0, time.Local)
+	tester, _ := Add(townRoot, Spawn{
+		Agent:     "gastown/crew/tester",
+		After:     []string{"gastown/crew/impl", "gastown/polecats/Toast"},
+		Template:  "tester",
+		CreatedAt: declared,
+	})
+	_, _ = Add(townRoot, Spawn{Agent: "gastown/crew/docs", After: []string{"gastown/crew/other"}, CreatedAt: declared})
+
+	// A done event from before the spawn was declared does not count, nor
+	// does another type of event.
+	ready, err := Observe(townRoot,
+		done("gastown/crew/impl", declared.Add(-time.Minute)),
+		townlog.Event{Timestamp: declared.Add(time.Minute), Type: townlog.EventKill, Agent: "gastown/crew/impl"})
+	if err != nil || len(ready) != 0 {
+		t.Fatalf("Observe = %v, %v; want nothing ready", ready, err)
+	}
+
+	if ready, _ := Observe(townRoot, done("gastown/crew/impl", declared.Add(time.Minute))); len(ready) != 0 {
+		t.Fatalf("ready after one of two dependencies: %v", ready)
+	}
+	list, _ := Load(townRoot)
+	if w := list[0].Waiting(); len(w) != 1 || w[0] != "gastown/polecats/Toast" {
+		t.Errorf("still waiting on %v, want Toast", w)
+	}
+
+	// Replayed events, as when the daemon restarts, are harmless.
+	ready, _ = Observe(townRoot,
+		done("gastown/crew/impl", declared.Add(time.Minute)),
+		done("gastown/polecats/Toast/", declared.Add(2*time.Minute)))
+	if len(ready) != 1 || ready[0].ID != tester.ID {
+		t.Fatalf("ready = %v, want %s", ready, tester.ID)
+	}
+	if args := ready[0].Args(); len(args) != 4 || args[1] != "gastown/crew/tester" || args[3] != "tester" {
+		t.Errorf("Args = %v", args)
+	}
+	list, _ = Load(townRoot)
+	if len(list) != 1 || list[0].Agent != "gastown/crew/docs" {
+		t.Errorf("pending after trigger = %v, want docs only", list)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	for _, s := range []Spawn{
+		{Agent: "gastown/crew/low", Priority: "low", Queued: true},
+		{Agent: "gastown/crew/tester", After: []string{"gastown/crew/impl"}},
+		{Agent: "gastown/crew/first", Queued: true},
+		{Agent: "gastown/crew/hotfix", Priority: "high", Queued: true},
+		{Agent: "gastown/crew/second", Priority: "normal", Queued: true},
+	} {
+		s.CreatedAt = now
+		if _, err := Add(townRoot, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := Oldest(townRoot); !got.Equal(now) {
+		t.Errorf("Oldest = %v, want the waiting spawn's %v", got, now)
+	}
+
+	list, _ := Load(townRoot)
+	var order []string
+	for _, s := range Queue(list) {
+		order = append(order, s.Agent)
+	}
+	want := []string{"gastown/crew/hotfix", "gastown/crew/first", "gastown/crew/second", "gastown/crew/low"}
+	if !slices.Equal(order, want) {
+		t.Errorf("Queue = %v, want %v", order, want)
+	}
+
+	next, err := Dequeue(townRoot, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next) != 2 || next[0].Agent != "gastown/crew/hotfix" || next[1].Agent != "gastown/crew/first" {
+		t.Errorf("Dequeue(2) = %v", next)
+	}
+	list, _ = Load(townRoot)
+	if len(list) != 3 || len(Queue(list)) != 2 {
+		t.Errorf("after Dequeue, %d spawns left with %d queued; want 3 with 2", len(list), len(Queue(list)))
+	}
+}
+
+// TestConcurrentUpdates runs Add, Observe and Dequeue at once, as the CLI
+// and the daemon do, and checks that every spawn comes out exactly once.
+func TestConcurrentUpdates(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	impl := done("gastown/crew/impl", now.Add(time.Hour))
+
+	var mu sync.Mutex
+	added := map[string]int{}
+	taken := map[string]int{}
+	take := func(list []Spawn) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, s := range list {
+			taken[s.Agent]++
+		}
+	}
+
+	var adders, daemon sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 20; i++ {
+		adders.Add(1)
+		go func() {
+			defer adders.Done()
+			spec := Spawn{Agent: fmt.Sprintf("gastown/crew/w%d", i), After: []string{"gastown/crew/impl"}, CreatedAt: now}
+			if i%2 == 0 {
+				spec = Spawn{Agent: spec.Agent, Queued: true, CreatedAt: now}
+			}
+			s, err := Add(townRoot, spec)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			added[s.Agent]++
+			mu.Unlock()
+		}()
+	}
+	daemon.Add(2)
+	go func() {
+		defer daemon.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			ready, err := Observe(townRoot, impl)
+			if err != nil {
+				t.Error(err)
+			}
+			take(ready)
+		}
+	}()
+	go func() {
+		defer daemon.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			next, err := Dequeue(townRoot, 1)
+			if err != nil {
+				t.Error(err)
+			}
+			take(next)
+		}
+	}()
+	adders.Wait()
+	close(stop)
+	daemon.Wait()
+
+	ready, _ := Observe(townRoot, impl)
+	take(ready)
+	next, _ := Dequeue(townRoot, 100)
+	take(next)
+
+	if len(added) != 20 {
+		t.Errorf("added %d spawns, want 20", len(added))
+	}
+	for agent := range added {
+		if taken[agent] != 1 {
+			t.Errorf("%s came out %d times, want once", agent, taken[agent])
+		}
+	}
+	if list, _ := Load(townRoot); len(list) != 0 {
+		t.Errorf("left pending: %v", list)
+	}
+}
+
+// TestAddDuringObserve is a regression test for 'gt spawn --after' losing
+// its pending spawn when the daemon's Observe saved the list it had loaded
+// before the Add.
+func TestAddDuringObserve(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	impl := done("gastown/crew/impl", now.Add(time.Hour))
+
+	var daemon sync.WaitGroup
+	stop := make(chan struct{})
+	daemon.Add(1)
+	go func() {
+		defer daemon.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			// Something for every Observe to trigger, so each one saves.
+			if _, err := Add(townRoot, Spawn{Agent: "gastown/crew/tester", After: []string{"gastown/crew/impl"}, CreatedAt: now}); err != nil {
+				t.Error(err)
+			}
+			if _, err := Observe(townRoot, impl); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+
+	var cli sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		cli.Add(1)
+		go func() {
+			defer cli.Done()
+			agent := fmt.Sprintf("gastown/crew/w%d", i)
+			if _, err := Add(townRoot, Spawn{Agent: agent, After: []string{"gastown/crew/other"}, CreatedAt: now}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	cli.Wait()
+	close(stop)
+	daemon.Wait()
+
+	list, err := Load(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		agent := fmt.Sprintf("gastown/crew/w%d", i)
+		if !slices.ContainsFunc(list, func(s Spawn) bool { return s.Agent == agent }) {
+			t.Errorf("pending spawn of %s was lost", agent)
+		}
+	}
+}
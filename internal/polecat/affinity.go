@@ -0,0 +1,225 @@
+package polecat
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/checkpoint"
+	"github.com/ctiospl/gastown/internal/events"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+// AffinityWindow is how far back a polecat's finished work counts toward
+// its affinity for new tasks.
+const AffinityWindow = 24 * time.Hour
+
+// Affinity weights. A task that builds on a polecat's own issue is the
+// strongest signal; sharing files beats sharing a label.
+const (
+	affinityIssue = 3 // finished an issue the task depends on or mentions
+	affinityFile  = 2 // recently changed a file the task references
+	affinityLabel = 1 // recently finished an issue with the same label
+)
+
+// fileRefPattern matches path-like words in a task: anything with a slash
+// or a file extension.
+var fileRefPattern = regexp.MustCompile(`[\w.-]*[\w-](?:/[\w.-]+)+/?|[\w-]+\.[A-Za-z]\w{0,5}\b`)
+
+// AffinityTask is the work being dispatched, as far as affinity cares.
+type AffinityTask struct {
+	ID          string
+	Title       string
+	Description string
+	DependsOn   []string
+	Labels      []string
+}
+
+// WorkHistory is what a polecat worked on recently.
+type WorkHistory struct {
+	Issues []string // finished or hooked issues, most recent first
+	Files  []string // files its branches and checkpoint touched
+	Labels []string // labels of the finished issues
+}
+
+// Affinity is how well a polecat fits a task, and why.
+type Affinity struct {
+	Polecat string   `json:"polecat"`
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// ScoreAffinity rates a polecat's recent work against a task.
+func ScoreAffinity(task AffinityTask, name string, h WorkHistory) Affinity {
+	a := Affinity{Polecat: name}
+
+	related := make(map[string]bool)
+	for _, id := range task.DependsOn {
+		related[id] = true
+	}
+	text := task.Title + "\n" + task.Description
+	for _, id := range h.Issues {
+		switch {
+		case related[id]:
+			a.Score += affinityIssue
+			a.Reasons = append(a.Reasons, fmt.Sprintf("finished %s, which this depends on", id))
+		case strings.Contains(text, id):
+			a.Score += affinityIssue
+			a.Reasons = append(a.Reasons, fmt.Sprintf("finished %s, which this mentions", id))
+		}
+	}
+
+	for _, ref := range fileRefs(text) {
+		for _, f := range h.Files {
+			if fileMatches(f, ref) {
+				a.Score += affinityFile
+				a.Reasons = append(a.Reasons, "recently changed "+f)
+				break
+			}
+		}
+	}
+
+	recent := make(map[string]bool)
+	for _, l := range h.Labels {
+		recent[l] = true
+	}
+	for _, l := range task.Labels {
+		if recent[l] {
+			a.Score += affinityLabel
+			a.Reasons = append(a.Reasons, "recently worked on label "+l)
+		}
+	}
+	return a
+}
+
+// RankByAffinity scores each candidate polecat for a task, best first.
+// Candidates with equal scores keep their given order.
+func RankByAffinity(task AffinityTask, candidates []string, histories map[string]WorkHistory) []Affinity {
+	ranked := make([]Affinity, 0, len(candidates))
+	for _, name := range candidates {
+		ranked = append(ranked, ScoreAffinity(task, name, histories[name]))
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score > ranked[j].Score })
+	return ranked
+}
+
+// RecentWork gathers the work history of the named polecats since the
+// given time, from their done events, the branches those events name, and
+// their checkpoints. Missing sources are skipped; history is best-effort.
+func (m *Manager) RecentWork(names []string, since time.Time) map[string]WorkHistory {
+	histories := make(map[string]WorkHistory, len(names))
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	branches := make(map[string][]string)
+	if townRoot, err := workspace.Find(m.rig.Path); err == nil && townRoot != "" {
+		all, _ := events.Read(townRoot)
+		for i := len(all) - 1; i >= 0; i-- {
+			e := all[i]
+			if e.Type != events.TypeDone || e.Time().Before(since) {
+				continue
+			}
+			name, ok := m.polecatFromActor(e.Actor)
+			if !ok || !wanted[name] {
+				continue
+			}
+			h := histories[name]
+			if bead, _ := e.Payload["bead"].(string); bead != "" {
+				h.Issues = appendUnique(h.Issues, bead)
+			}
+			if branch, _ := e.Payload["branch"].(string); branch != "" {
+				branches[name] = append(branches[name], branch)
+			}
+			histories[name] = h
+		}
+	}
+
+	repo, repoErr := m.repoBase()
+	for _, name := range names {
+		h := histories[name]
+		if repoErr == nil {
+			for _, branch := range branches[name] {
+				files, _ := repo.ChangedFiles(m.rig.DefaultBranch(), branch)
+				for _, f := range files {
+					h.Files = appendUnique(h.Files, f)
+				}
+			}
+		}
+		if cp, err := checkpoint.Read(m.polecatDir(name)); err == nil && cp != nil && !cp.Timestamp.Before(since) {
+			if cp.HookedBead != "" {
+				h.Issues = appendUnique(h.Issues, cp.HookedBead)
+			}
+			for _, f := range cp.ModifiedFiles {
+				h.Files = appendUnique(h.Files, f)
+			}
+		}
+		if len(h.Issues) > 0 {
+			if issues, err := m.beads.ShowMultiple(h.Issues); err == nil {
+				for _, id := range h.Issues {
+					if issue := issues[id]; issue != nil {
+						for _, l := range issue.Labels {
+							h.Labels = appendUnique(h.Labels, l)
+						}
+					}
+				}
+			}
+		}
+		histories[name] = h
+	}
+	return histories
+}
+
+// polecatFromActor returns the polecat name of an event actor in this rig.
+// Polecats sign events as "rig/name" or "rig/polecats/name".
+func (m *Manager) polecatFromActor(actor string) (string, bool) {
+	parts := strings.Split(strings.TrimSuffix(actor, "/"), "/")
+	if len(parts) == 3 && parts[1] == "polecats" {
+		parts = []string{parts[0], parts[2]}
+	}
+	if len(parts) != 2 || parts[0] != m.rig.Name {
+		return "", false
+	}
+	switch parts[1] {
+	case "witness", "refinery", "crew", "mayor":
+		return "", false
+	}
+	return parts[1], true
+}
+
+// fileRefs returns the distinct path-like words in text.
+func fileRefs(text string) []string {
+	var refs []string
+	for _, word := range strings.Fields(text) {
+		if strings.Contains(word, "://") {
+			continue // URLs are not files
+		}
+		for _, ref := range fileRefPattern.FindAllString(word, -1) {
+			ref = strings.TrimRight(strings.TrimPrefix(ref, "./"), ".")
+			refs = appendUnique(refs, ref)
+		}
+	}
+	return refs
+}
+
+// fileMatches reports whether a changed file is the file or directory a
+// task refers to. Bare names match by base name.
+func fileMatches(file, ref string) bool {
+	if dir := strings.TrimSuffix(ref, "/"); dir != ref || !strings.Contains(filepath.Base(ref), ".") {
+		return strings.HasPrefix(file, dir+"/") || strings.Contains(file, "/"+dir+"/")
+	}
+	return file == ref || strings.HasSuffix(file, "/"+ref)
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
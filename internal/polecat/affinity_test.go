@@ -0,0 +1,102 @@
+package polecat
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ctiospl/gastown/internal/rig"
+)
+
+func TestScoreAffinity(t *testing.T) {
+	task := AffinityTask{
+		ID:          "gt-20",
+		Title:       "Handle empty input in internal/mail/router.go",
+		Description: "Follow-up to gt-11. See also docs/mail/.",
+		DependsOn:   []string{"gt-10"},
+		Labels:      []string{"mail", "ux"},
+	}
+	h := WorkHistory{
+		Issues: []string{"gt-10", "gt-11", "gt-12"},
+		Files:  []string{"internal/mail/router.go", "docs/mail/routing.md", "README.md"},
+		Labels: []string{"mail"},
+	}
+
+	a := ScoreAffinity(task, "Toast", h)
+	want := 2*affinityIssue + 2*affinityFile + affinityLabel
+	if a.Score != want {
+		t.Errorf("Score = %d, want %d (reasons %v)", a.Score, want, a.Reasons)
+	}
+	if len(a.Reasons) != 5 {
+		t.Errorf("Reasons = %v, want 5 entries", a.Reasons)
+	}
+
+	if a := ScoreAffinity(task, "Nux", WorkHistory{}); a.Score != 0 || len(a.Reasons) != 0 {
+		t.Errorf("empty history scored %d %v, want 0", a.Score, a.Reasons)
+	}
+}
+
+func TestRankByAffinity(t *testing.T) {
+	task := AffinityTask{ID: "gt-2", DependsOn: []string{"gt-1"}}
+	histories := map[string]WorkHistory{
+		"Toast": {Issues: []string{"gt-1"}},
+	}
+
+	ranked := RankByAffinity(task, []string{"Nux", "Slit", "Toast"}, histories)
+	var order []string
+	for _, a := range ranked {
+		order = append(order, a.Polecat)
+	}
+	if want := []string{"Toast", "Nux", "Slit"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
+
+func TestFileRefs(t *testing.T) {
+	got := fileRefs("Fix ./internal/cmd/log.go and main.go; see https://example.com/x and internal/mail/")
+	want := []string{"internal/cmd/log.go", "main.go", "internal/mail/"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fileRefs = %v, want %v", got, want)
+	}
+}
+
+func TestFileMatches(t *testing.T) {
+	tests := []struct {
+		file, ref string
+		want      bool
+	}{
+		{"internal/cmd/log.go", "internal/cmd/log.go", true},
+		{"internal/cmd/log.go", "log.go", true},
+		{"internal/cmd/log.go", "internal/cmd/", true},
+		{"internal/cmd/log.go", "internal/cmd", true},
+		{"internal/cmd/log.go", "cmd", true},
+		{"internal/cmd/log.go", "catalog.go", false},
+		{"internal/cmd/log.go", "internal/mail", false},
+	}
+	for _, tt := range tests {
+		if got := fileMatches(tt.file, tt.ref); got != tt.want {
+			t.Errorf("fileMatches(%q, %q) = %v, want %v", tt.file, tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestPolecatFromActor(t *testing.T) {
+	m := &Manager{rig: &rig.Rig{Name: "gastown"}}
+	tests := []struct {
+		actor string
+		want  string
+		ok    bool
+	}{
+		{"gastown/Toast", "Toast", true},
+		{"gastown/polecats/Toast", "Toast", true},
+		{"gastown/witness", "", false},
+		{"gastown/crew/max", "", false},
+		{"beads/Toast", "", false},
+		{"mayor/", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := m.polecatFromActor(tt.actor)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("polecatFromActor(%q) = %q, %v, want %q, %v", tt.actor, got, ok, tt.want, tt.ok)
+		}
+	}
+}
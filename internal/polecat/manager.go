@@ -43,6 +43,7 @@ type Manager struct {
 	git      *git.Git
 	beads    *beads.Beads
 	namePool *NamePool
+	worktree *config.WorktreeConfig // post-create setup, nil if none
 }
 
 // NewManager creates a new polecat manager.
@@ -74,11 +75,17 @@ func NewManager(r *rig.Rig, g *git.Git) *Manager {
 	}
 	_ = pool.Load() // non-fatal: state file may not exist for new rigs
 
+	var worktree *config.WorktreeConfig
+	if settings != nil {
+		worktree = settings.Worktree
+	}
+
 	return &Manager{
 		rig:      r,
 		git:      g,
 		beads:    beads.New(beadsPath),
 		namePool: pool,
+		worktree: worktree,
 	}
 }
 
@@ -168,6 +175,42 @@ func (m *Manager) repoBase() (*git.Git, error) {
 	return git.NewGit(mayorPath), nil
 }
 
+// CheckCanAdd verifies that a polecat worktree could be created: the repo
+// base exists and a branch can be created in it. It changes nothing.
+func (m *Manager) CheckCanAdd() error {
+	repoGit, err := m.repoBase()
+	if err != nil {
+		return err
+	}
+	return repoGit.CanCreateBranch()
+}
+
+// addWorktree creates a polecat worktree on a new branch from startPoint
+// (the repo base's HEAD if empty). In a rig scoped to part of a monorepo,
+// or with worktree.sparse configured, only those paths are checked out.
+func (m *Manager) addWorktree(repoGit *git.Git, path, branch, startPoint string) error {
+	if paths := m.sparsePaths(); len(paths) > 0 {
+		return repoGit.WorktreeAddSparse(path, branch, startPoint, paths)
+	}
+	if startPoint == "" {
+		return repoGit.WorktreeAdd(path, branch)
+	}
+	return repoGit.WorktreeAddFromRef(path, branch, startPoint)
+}
+
+// sparsePaths returns the directories new worktrees check out, or nil to
+// check out the whole tree.
+func (m *Manager) sparsePaths() []string {
+	paths := m.rig.ScopePaths()
+	if m.worktree != nil && len(m.worktree.Sparse) > 0 {
+		merged, err := rig.NormalizePaths(append(append([]string{}, paths...), m.worktree.Sparse...))
+		if err == nil {
+			paths = merged
+		}
+	}
+	return paths
+}
+
 // polecatDir returns the directory for a polecat.
 func (m *Manager) polecatDir(name string) string {
 	return filepath.Join(m.rig.Path, "polecats", name)
@@ -182,6 +225,11 @@ func (m *Manager) exists(name string) bool {
 // AddOptions configures polecat creation.
 type AddOptions struct {
 	HookBead string // Bead ID to set as hook_bead at spawn time (atomic assignment)
+
+	// StackOn starts the polecat's branch from another unmerged branch
+	// instead of the default branch, and records the stack (see StackParent).
+	StackOn      string
+	StackOnIssue string // Work on the StackOn branch; it must merge first
 }
 
 // Add creates a new polecat as a git worktree from the repo base.
@@ -223,9 +271,17 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 
 	// Always create fresh branch - unique name guarantees no collision
 	// git worktree add -b polecat/<name>-<timestamp> <path>
-	if err := repoGit.WorktreeAdd(polecatPath, branchName); err != nil {
+	if opts.StackOn != "" {
+		if err := m.addWorktree(repoGit, polecatPath, branchName, opts.StackOn); err != nil {
+			return nil, fmt.Errorf("creating worktree on %s: %w", opts.StackOn, err)
+		}
+	} else if err := m.addWorktree(repoGit, polecatPath, branchName, ""); err != nil {
 		return nil, fmt.Errorf("creating worktree: %w", err)
 	}
+	if err := m.recordStack(repoGit, branchName, opts); err != nil {
+		_ = repoGit.WorktreeRemove(polecatPath, true)
+		return nil, err
+	}
 
 	// NOTE: We intentionally do NOT write to CLAUDE.md here.
 	// Gas Town context is injected ephemerally via SessionStart hook (gt prime).
@@ -240,6 +296,13 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 		fmt.Printf("Warning: could not set up shared beads: %v\n", err)
 	}
 
+	// Run the rig's worktree setup (template files, shared links, hooks)
+	// now, so the agent starts in a ready workspace.
+	if err := SetupWorktree(m.rig.Path, polecatPath, m.worktree, os.Stdout); err != nil {
+		_ = repoGit.WorktreeRemove(polecatPath, true)
+		return nil, fmt.Errorf("preparing worktree: %w", err)
+	}
+
 	// NOTE: Slash commands (.claude/commands/) are provisioned at town level by gt install.
 	// All agents inherit them via Claude's directory traversal - no per-workspace copies needed.
 
@@ -453,15 +516,22 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 		defaultBranch = rigCfg.DefaultBranch
 	}
 	startPoint := fmt.Sprintf("origin/%s", defaultBranch)
+	if opts.StackOn != "" {
+		startPoint = opts.StackOn
+	}
 
 	// Create fresh worktree with unique branch name, starting from origin's default branch
 	// Old branches are left behind - they're ephemeral (never pushed to origin)
 	// and will be cleaned up by garbage collection
 	// Use base36 encoding for shorter branch names (8 chars vs 13 digits)
 	branchName := fmt.Sprintf("polecat/%s-%s", name, strconv.FormatInt(time.Now().UnixMilli(), 36))
-	if err := repoGit.WorktreeAddFromRef(polecatPath, branchName, startPoint); err != nil {
+	if err := m.addWorktree(repoGit, polecatPath, branchName, startPoint); err != nil {
 		return nil, fmt.Errorf("creating fresh worktree from %s: %w", startPoint, err)
 	}
+	if err := m.recordStack(repoGit, branchName, opts); err != nil {
+		_ = repoGit.WorktreeRemove(polecatPath, true)
+		return nil, err
+	}
 
 	// NOTE: We intentionally do NOT write to CLAUDE.md here.
 	// Gas Town context is injected ephemerally via SessionStart hook (gt prime).
@@ -471,6 +541,12 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 		fmt.Printf("Warning: could not set up shared beads: %v\n", err)
 	}
 
+	// Run the rig's worktree setup, as for a new polecat
+	if err := SetupWorktree(m.rig.Path, polecatPath, m.worktree, os.Stdout); err != nil {
+		_ = repoGit.WorktreeRemove(polecatPath, true)
+		return nil, fmt.Errorf("preparing worktree: %w", err)
+	}
+
 	// NOTE: Slash commands inherited from town level - no per-workspace copies needed.
 
 	// Create fresh agent bead for ZFC compliance
@@ -0,0 +1,265 @@
+package polecat
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+)
+
+// DefaultSetupTimeout bounds a worktree setup hook without its own timeout.
+const DefaultSetupTimeout = 10 * time.Minute
+
+// setupCompleteMarker marks a cache entry as fully written.
+const setupCompleteMarker = ".complete"
+
+// WorktreeTemplateDir returns the directory whose files are copied into
+// new worktrees (see config.WorktreeConfig.Copy).
+func WorktreeTemplateDir(rigPath string) string {
+	return filepath.Join(rigPath, "settings", "worktree")
+}
+
+// WorktreeSharedDir returns the directory holding paths shared by all
+// worktrees through symlinks (see config.WorktreeConfig.Link).
+func WorktreeSharedDir(rigPath string) string {
+	return filepath.Join(rigPath, ".shared")
+}
+
+// WorktreeCacheDir returns the cache of setup hook outputs. It is safe to
+// delete; hooks rerun on the next spawn.
+func WorktreeCacheDir(rigPath string) string {
+	return filepath.Join(rigPath, ".cache", "worktree")
+}
+
+// SetupWorktree prepares a freshly created worktree according to the rig's
+// worktree config: creates the shared dependency caches, copies template
+// files, links shared paths, and runs setup hooks, restoring cached outputs
+// where the cache key matches. Progress is written to out.
+func SetupWorktree(rigPath, worktreePath string, cfg *config.WorktreeConfig, out io.Writer) error {
+	if cfg == nil {
+		return nil
+	}
+
+	townRoot := filepath.Dir(rigPath)
+	if err := config.EnsureDepCaches(townRoot, cfg.Caches); err != nil {
+		return err
+	}
+	cacheEnv := config.DepCacheEnv(townRoot, cfg.Caches)
+
+	for _, rel := range cfg.Copy {
+		src := filepath.Join(WorktreeTemplateDir(rigPath), rel)
+		if err := copyPath(src, filepath.Join(worktreePath, rel)); err != nil {
+			return fmt.Errorf("copying %s into worktree: %w", rel, err)
+		}
+	}
+
+	for _, rel := range cfg.Link {
+		if err := linkShared(rigPath, worktreePath, rel); err != nil {
+			return fmt.Errorf("linking shared %s: %w", rel, err)
+		}
+	}
+
+	for i, hook := range cfg.Setup {
+		if hook.Name == "" {
+			hook.Name = fmt.Sprintf("setup-%d", i+1)
+		}
+		if err := runSetupHook(rigPath, worktreePath, hook, cacheEnv, out); err != nil {
+			return fmt.Errorf("setup hook %s: %w", hook.Name, err)
+		}
+	}
+	return nil
+}
+
+// runSetupHook runs one hook, or restores its outputs from the cache. env
+// points package managers at the shared dependency caches.
+func runSetupHook(rigPath, worktreePath string, hook config.WorktreeHook, env map[string]string, out io.Writer) error {
+	cacheEntry := ""
+	if len(hook.CacheKey) > 0 && len(hook.Outputs) > 0 {
+		key, err := setupCacheKey(worktreePath, hook)
+		if err != nil {
+			return err
+		}
+		cacheEntry = filepath.Join(WorktreeCacheDir(rigPath), hook.Name+"-"+key)
+		if _, err := os.Stat(filepath.Join(cacheEntry, setupCompleteMarker)); err == nil {
+			_, _ = fmt.Fprintf(out, "  Restoring %s from cache\n", hook.Name)
+			for _, rel := range hook.Outputs {
+				if err := copyPath(filepath.Join(cacheEntry, rel), filepath.Join(worktreePath, rel)); err != nil {
+					return fmt.Errorf("restoring cached %s: %w", rel, err)
+				}
+			}
+			return nil
+		}
+	}
+
+	timeout := DefaultSetupTimeout
+	if hook.Timeout != "" {
+		if d, err := time.ParseDuration(hook.Timeout); err == nil {
+			timeout = d
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	_, _ = fmt.Fprintf(out, "  Running %s: %s\n", hook.Name, hook.Run)
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook.Run) //nolint:gosec // G204: command comes from the rig's own settings
+	cmd.Dir = worktreePath
+	cmd.Env = append(os.Environ(),
+		"GT_RIG_PATH="+rigPath,
+		"GT_WORKTREE="+worktreePath,
+		"GT_POLECAT="+filepath.Base(worktreePath),
+	)
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out after %s", timeout)
+		}
+		return fmt.Errorf("%w: %s", err, lastLines(output.String(), 10))
+	}
+
+	if cacheEntry != "" {
+		if err := storeSetupCache(cacheEntry, worktreePath, hook.Outputs); err != nil {
+			// Caching is an optimization; the worktree itself is ready.
+			_, _ = fmt.Fprintf(out, "  Warning: could not cache %s: %v\n", hook.Name, err)
+		}
+	}
+	return nil
+}
+
+// setupCacheKey hashes the hook's command and the contents of its key
+// files, so the cache is invalidated when either changes.
+func setupCacheKey(worktreePath string, hook config.WorktreeHook) (string, error) {
+	h := sha256.New()
+	_, _ = io.WriteString(h, hook.Run+"\x00")
+	for _, rel := range hook.CacheKey {
+		data, err := os.ReadFile(filepath.Join(worktreePath, rel)) //nolint:gosec // G304: path is relative to the worktree, validated in config
+		if err != nil {
+			return "", fmt.Errorf("reading cache key %s: %w", rel, err)
+		}
+		_, _ = io.WriteString(h, rel+"\x00")
+		_, _ = h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
+
+// storeSetupCache copies outputs into a new cache entry. The entry is
+// written to a temporary directory and renamed into place, so concurrent
+// spawns never restore a partial entry.
+func storeSetupCache(entry, worktreePath string, outputs []string) error {
+	if err := os.MkdirAll(filepath.Dir(entry), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(entry), filepath.Base(entry)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	for _, rel := range outputs {
+		if err := copyPath(filepath.Join(worktreePath, rel), filepath.Join(tmp, rel)); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmp, setupCompleteMarker), nil, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, entry); err != nil {
+		if _, statErr := os.Stat(filepath.Join(entry, setupCompleteMarker)); statErr == nil {
+			return nil // another spawn cached it first
+		}
+		return err
+	}
+	return nil
+}
+
+// linkShared symlinks rel in the worktree to the rig's shared copy,
+// creating an empty shared directory on first use.
+func linkShared(rigPath, worktreePath, rel string) error {
+	shared := filepath.Join(WorktreeSharedDir(rigPath), rel)
+	if _, err := os.Stat(shared); os.IsNotExist(err) {
+		if err := os.MkdirAll(shared, 0755); err != nil {
+			return err
+		}
+	}
+	dst := filepath.Join(worktreePath, rel)
+	if _, err := os.Lstat(dst); err == nil {
+		return fmt.Errorf("%s already exists in the worktree", rel)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.Symlink(shared, dst)
+}
+
+// copyPath copies a file, symlink, or directory tree from src to dst,
+// preserving file modes.
+func copyPath(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		_ = os.Remove(dst)
+		return os.Symlink(target, dst)
+	case info.IsDir():
+		if err := os.MkdirAll(dst, info.Mode().Perm()); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := copyPath(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		in, err := os.Open(src) //nolint:gosec // G304: paths come from rig settings
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm()) //nolint:gosec // G304: paths come from rig settings
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			_ = out.Close()
+			return err
+		}
+		return out.Close()
+	}
+}
+
+// lastLines returns the last n lines of s, for error messages.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,85 @@
+package polecat
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ctiospl/gastown/internal/config"
+)
+
+func TestSetupWorktree(t *testing.T) {
+	rigPath := t.TempDir()
+	counter := filepath.Join(rigPath, "runs")
+
+	if err := os.MkdirAll(WorktreeTemplateDir(rigPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(WorktreeTemplateDir(rigPath), ".env"), []byte("TOKEN=x\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.WorktreeConfig{
+		Copy: []string{".env"},
+		Link: []string{"target"},
+		Setup: []config.WorktreeHook{{
+			Name:     "deps",
+			Run:      "echo run >> " + counter + " && mkdir -p vendor && cp lock vendor/installed",
+			CacheKey: []string{"lock"},
+			Outputs:  []string{"vendor"},
+		}},
+	}
+
+	spawn := func(name, lock string) string {
+		t.Helper()
+		wt := filepath.Join(rigPath, "polecats", name)
+		if err := os.MkdirAll(wt, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(wt, "lock"), []byte(lock), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := SetupWorktree(rigPath, wt, cfg, io.Discard); err != nil {
+			t.Fatalf("SetupWorktree(%s): %v", name, err)
+		}
+		return wt
+	}
+	runs := func() int {
+		data, _ := os.ReadFile(counter)
+		return strings.Count(string(data), "run")
+	}
+
+	first := spawn("nux", "v1")
+	if data, err := os.ReadFile(filepath.Join(first, ".env")); err != nil || string(data) != "TOKEN=x\n" {
+		t.Errorf(".env not copied: %q, %v", data, err)
+	}
+	if target, err := os.Readlink(filepath.Join(first, "target")); err != nil || target != filepath.Join(WorktreeSharedDir(rigPath), "target") {
+		t.Errorf("target link = %q, %v", target, err)
+	}
+
+	second := spawn("slit", "v1")
+	if runs() != 1 {
+		t.Errorf("hook ran %d times, want 1 (second spawn restores from cache)", runs())
+	}
+	if data, err := os.ReadFile(filepath.Join(second, "vendor", "installed")); err != nil || string(data) != "v1" {
+		t.Errorf("cached output not restored: %q, %v", data, err)
+	}
+
+	spawn("furiosa", "v2")
+	if runs() != 2 {
+		t.Errorf("hook ran %d times, want 2 (changed cache key reruns)", runs())
+	}
+}
+
+func TestSetupWorktreeHookFailure(t *testing.T) {
+	rigPath := t.TempDir()
+	wt := t.TempDir()
+	cfg := &config.WorktreeConfig{Setup: []config.WorktreeHook{{Run: "echo broken >&2; exit 3"}}}
+
+	err := SetupWorktree(rigPath, wt, cfg, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "setup-1") || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("SetupWorktree error = %v, want hook name and output", err)
+	}
+}
@@ -0,0 +1,64 @@
+package polecat
+
+import (
+	"fmt"
+
+	"github.com/ctiospl/gastown/internal/git"
+	"github.com/ctiospl/gastown/internal/stack"
+)
+
+// RepoBase returns git for the rig's shared repository, where polecat
+// branches live: the bare repo, or mayor/rig in older rigs.
+func (m *Manager) RepoBase() (*git.Git, error) {
+	return m.repoBase()
+}
+
+// StackParent resolves what new work is stacked on: the current branch and
+// hooked issue of a polecat of this rig, or else a branch of the rig's
+// repo by name.
+func (m *Manager) StackParent(ref string) (branch, issue string, err error) {
+	if p, err := m.Get(ref); err == nil {
+		return p.Branch, p.Issue, nil
+	}
+	repoGit, err := m.repoBase()
+	if err != nil {
+		return "", "", fmt.Errorf("finding repo base: %w", err)
+	}
+	exists, err := repoGit.BranchExists(ref)
+	if err != nil {
+		return "", "", fmt.Errorf("checking branch %s: %w", ref, err)
+	}
+	if !exists {
+		return "", "", fmt.Errorf("%q is neither a polecat of %s nor a branch", ref, m.rig.Name)
+	}
+	if s, err := stack.Load(m.rig.Path); err == nil {
+		if e, ok := s.Get(ref); ok {
+			return ref, e.Issue, nil
+		}
+	}
+	return ref, "", nil
+}
+
+// recordStack remembers that a new branch was started on opts.StackOn.
+func (m *Manager) recordStack(repoGit *git.Git, branch string, opts AddOptions) error {
+	if opts.StackOn == "" {
+		return nil
+	}
+	base, err := repoGit.Rev(opts.StackOn)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", opts.StackOn, err)
+	}
+	err = stack.Update(m.rig.Path, func(s *stack.Stacks) error {
+		return s.Add(stack.Entry{
+			Branch:      branch,
+			Parent:      opts.StackOn,
+			Base:        base,
+			Issue:       opts.HookBead,
+			ParentIssue: opts.StackOnIssue,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("recording stack: %w", err)
+	}
+	return nil
+}
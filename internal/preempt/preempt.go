@@ -0,0 +1,245 @@
+// Package preempt keeps agent priorities and makes room for urgent work.
+//
+// Agents have a priority: low, normal (the default), or high, set with
+// 'gt spawn --priority' and kept in the town's
+// .runtime/agent-priorities.json. When the town limits how many worker
+// agents (crew and polecats) run at once and the limit is reached, a spawn
+// may preempt running agents of lower priority: they are paused (see gt
+// pause), keeping their conversation in memory, and no longer take a slot.
+// The daemon resumes preempted agents, highest priority first, as slots
+// free up.
+package preempt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// EventPreempt is the town log event recorded when an agent is paused to
+// make room for higher-priority work, and when it is resumed.
+const EventPreempt townlog.EventType = "preempt"
+
+// PausedBy is the By of the pause records of preempted agents, which tells
+// them apart from agents paused by hand.
+const PausedBy = "preempt"
+
+// Priority is how urgent an agent's work is.
+type Priority string
+
+// Agent priorities, lowest first.
+const (
+	Low    Priority = "low"
+	Normal Priority = "normal"
+	High   Priority = "high"
+)
+
+// rank orders priorities; unknown priorities rank as normal.
+func (p Priority) rank() int {
+	switch p {
+	case Low:
+		return 0
+	case High:
+		return 2
+	}
+	return 1
+}
+
+// Outranks reports whether p is more urgent than q.
+func (p Priority) Outranks(q Priority) bool {
+	return p.rank() > q.rank()
+}
+
+// Parse returns the priority named s.
+func Parse(s string) (Priority, error) {
+	switch p := Priority(s); p {
+	case Low, Normal, High:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown priority %q (want low, normal, or high)", s)
+}
+
+// Path returns where a town's agent priorities are kept.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "agent-priorities.json")
+}
+
+// Priorities maps agent keys (see townlog.AgentKey) to the priorities of
+// agents that are not normal.
+type Priorities map[string]Priority
+
+// Of returns the agent's priority.
+func (ps Priorities) Of(agent string) Priority {
+	if p, ok := ps[townlog.AgentKey(agent)]; ok {
+		return p
+	}
+	return Normal
+}
+
+// Load returns the town's agent priorities.
+func Load(townRoot string) (Priorities, error) {
+	ps := Priorities{}
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ps, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &ps); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", Path(townRoot), err)
+	}
+	return ps, nil
+}
+
+// Set records the agent's priority; normal removes its entry.
+func Set(townRoot, agent string, p Priority) error {
+	ps, err := Load(townRoot)
+	if err != nil {
+		return err
+	}
+	key := townlog.AgentKey(agent)
+	if ps[key] == p || (p == Normal && ps[key] == "") {
+		return nil
+	}
+	if p == Normal {
+		delete(ps, key)
+	} else {
+		ps[key] = p
+	}
+	if err := os.MkdirAll(filepath.Dir(Path(townRoot)), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(Path(townRoot), ps)
+}
+
+// Worker is a running crew member or polecat.
+type Worker struct {
+	Agent    string // address, e.g. "gastown/crew/max"
+	Session  string
+	Priority Priority
+	Started  time.Time // zero if unknown
+}
+
+// Running returns the town's running workers. Paused agents are not
+// running: they hold no slot.
+func Running(t *tmux.Tmux, townRoot string) ([]Worker, error) {
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	pauses, err := session.LoadPauses(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	ps, err := Load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var workers []Worker
+	for _, name := range sessions {
+		if _, paused := pauses[name]; paused {
+			continue
+		}
+		id, err := session.ParseSessionName(name)
+		if err != nil || (id.Role != session.RoleCrew && id.Role != session.RolePolecat) {
+			continue
+		}
+		w := Worker{Agent: id.Address(), Session: name, Priority: ps.Of(id.Address())}
+		if info, err := t.GetSessionInfo(name); err == nil {
+			w.Started, _ = time.ParseInLocation(time.ANSIC, info.Created, time.Local)
+		}
+		workers = append(workers, w)
+	}
+	return workers, nil
+}
+
+// Victims chooses up to n of the running workers to preempt for work of
+// priority p: only workers of lower priority, lowest first and, among
+// equals, the most recently started, which have the least work in flight.
+// Fewer than n are returned if there are not enough.
+func Victims(running []Worker, p Priority, n int) []Worker {
+	var out []Worker
+	for _, w := range running {
+		if w.Priority.rank() < p.rank() {
+			out = append(out, w)
+		}
+	}
+	sort.SliceStable(out, func(i, k int) bool {
+		if ri, rk := out[i].Priority.rank(), out[k].Priority.rank(); ri != rk {
+			return ri < rk
+		}
+		return out[i].Started.After(out[k].Started)
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// Preempt pauses w to make room for agent, of priority p, and logs it.
+func Preempt(t *tmux.Tmux, townRoot string, w Worker, agent string, p Priority) error {
+	reason := fmt.Sprintf("preempted by %s (%s priority)", agent, p)
+	err := session.PauseSession(t, townRoot, session.Pause{
+		Agent:    w.Agent,
+		Session:  w.Session,
+		PausedAt: time.Now(),
+		By:       PausedBy,
+		Reason:   reason,
+	})
+	if err != nil {
+		return err
+	}
+	logPreempt(townRoot, w.Agent, fmt.Sprintf("paused (%s priority): %s", w.Priority, reason))
+	return nil
+}
+
+// Resumable chooses up to n preempted agents to resume: highest priority
+// first and, among equals, the longest paused.
+func Resumable(pauses map[string]session.Pause, ps Priorities, n int) []session.Pause {
+	var out []session.Pause
+	for _, p := range pauses {
+		if p.By == PausedBy {
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, k int) bool {
+		if ri, rk := ps.Of(out[i].Agent).rank(), ps.Of(out[k].Agent).rank(); ri != rk {
+			return ri > rk
+		}
+		if !out[i].PausedAt.Equal(out[k].PausedAt) {
+			return out[i].PausedAt.Before(out[k].PausedAt)
+		}
+		return out[i].Agent < out[k].Agent
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// Resume continues a preempted agent and logs it.
+func Resume(t *tmux.Tmux, townRoot string, p session.Pause) error {
+	if _, err := session.ResumeSession(t, townRoot, p.Session); err != nil {
+		return err
+	}
+	logPreempt(townRoot, p.Agent, fmt.Sprintf("resumed after %s: a slot is free", time.Since(p.PausedAt).Round(time.Second)))
+	return nil
+}
+
+func logPreempt(townRoot, agent, context string) {
+	_ = townlog.NewLogger(townRoot).LogEvent(townlog.Event{
+		Timestamp: time.Now(),
+		Type:      EventPreempt,
+		Agent:     agent,
+		Context:   context,
+	})
+}
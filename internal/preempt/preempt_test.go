@@ -0,0 +1,97 @@
+package preempt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/session"
+)
+
+func TestParse(t *testing.T) {
+	for _, s := range []string{"low", "normal", "high"} {
+		if p, err := Parse(s); err != nil || string(p) != s {
+			t.Errorf("Parse(%q) = %q, %v", s, p, err)
+		}
+	}
+	if _, err := Parse("urgent"); err == nil {
+		t.Error("Parse accepted an unknown priority")
+	}
+}
+
+func TestSetLoad(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := Set(townRoot, "gastown/crew/max", High); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(townRoot, "gastown/polecats/Toast/", Low); err != nil {
+		t.Fatal(err)
+	}
+	ps, err := Load(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps.Of("gastown/crew/max/") != High || ps.Of("gastown/polecats/Toast") != Low || ps.Of("gastown/crew/rex") != Normal {
+		t.Errorf("priorities = %v", ps)
+	}
+
+	if err := Set(townRoot, "gastown/crew/max", Normal); err != nil {
+		t.Fatal(err)
+	}
+	ps, _ = Load(townRoot)
+	if len(ps) != 1 || ps.Of("gastown/crew/max") != Normal {
+		t.Errorf("after resetting max to normal: %v", ps)
+	}
+}
+
+func TestVictims(t *testing.T) {
+	start := time.Date(2026, 10, 14, 10, 0, 0, 0, time.Local)
+	running := []Worker{
+		{Agent: "gastown/crew/old", Priority: Low, Started: start},
+		{Agent: "gastown/crew/urgent", Priority: High, Started: start},
+		{Agent: "gastown/crew/new", Priority: Low, Started: start.Add(time.Hour)},
+		{Agent: "gastown/polecats/Toast", Priority: Normal, Started: start.Add(2 * time.Hour)},
+	}
+
+	got := Victims(running, High, 3)
+	want := []string{"gastown/crew/new", "gastown/crew/old", "gastown/polecats/Toast"}
+	if len(got) != len(want) {
+		t.Fatalf("Victims(high, 3) = %v", got)
+	}
+	for i, w := range got {
+		if w.Agent != want[i] {
+			t.Errorf("victim %d = %s, want %s", i, w.Agent, want[i])
+		}
+	}
+
+	if got := Victims(running, Normal, 5); len(got) != 2 {
+		t.Errorf("Victims(normal) = %v, want the two low agents", got)
+	}
+	if got := Victims(running, Low, 1); len(got) != 0 {
+		t.Errorf("Victims(low) = %v, want none", got)
+	}
+}
+
+func TestResumable(t *testing.T) {
+	at := time.Date(2026, 10, 14, 10, 0, 0, 0, time.Local)
+	pauses := map[string]session.Pause{
+		"gt-gastown-crew-a": {Agent: "gastown/crew/a", Session: "gt-gastown-crew-a", PausedAt: at, By: PausedBy},
+		"gt-gastown-crew-b": {Agent: "gastown/crew/b", Session: "gt-gastown-crew-b", PausedAt: at.Add(time.Minute), By: PausedBy},
+		"gt-gastown-crew-c": {Agent: "gastown/crew/c", Session: "gt-gastown-crew-c", PausedAt: at.Add(-time.Hour), By: PausedBy},
+		"gt-gastown-crew-d": {Agent: "gastown/crew/d", Session: "gt-gastown-crew-d", PausedAt: at.Add(-2 * time.Hour), By: "mayor"},
+	}
+	ps := Priorities{"gastown/crew/b": High, "gastown/crew/c": Low}
+
+	got := Resumable(pauses, ps, 10)
+	want := []string{"gastown/crew/b", "gastown/crew/a", "gastown/crew/c"}
+	if len(got) != len(want) {
+		t.Fatalf("Resumable = %v", got)
+	}
+	for i, p := range got {
+		if p.Agent != want[i] {
+			t.Errorf("resumable %d = %s, want %s", i, p.Agent, want[i])
+		}
+	}
+	if got := Resumable(pauses, ps, 1); len(got) != 1 || got[0].Agent != "gastown/crew/b" {
+		t.Errorf("Resumable(1) = %v", got)
+	}
+}
@@ -0,0 +1,296 @@
+// Package prune retires agent identities that have gone quiet, so the
+// roster of a long-lived town lists the agents that still work in it.
+//
+// A crew worker is inactive when neither the town log nor its workspace
+// shows any activity for the given period. Retiring it archives what
+// identifies it (its state file, mailbox, and notes) under
+// <town>/archive/agents/, removes the workspace so it drops out of
+// gt status, gt crew list, and completion, and closes its agent bead.
+// Polecats are not pruned: they are recycled by gt polecat gc.
+package prune
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/beads"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/crew"
+	"github.com/ctiospl/gastown/internal/git"
+	"github.com/ctiospl/gastown/internal/mail"
+	"github.com/ctiospl/gastown/internal/rig"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+// EventRetire is the town log event recorded for each retired identity.
+const EventRetire townlog.EventType = "retire"
+
+// Identity is a crew worker and when it was last active.
+type Identity struct {
+	Agent      string    `json:"agent"` // e.g. "gastown/crew/max"
+	Rig        string    `json:"rig"`
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	LastActive time.Time `json:"last_active"`
+	Seen       string    `json:"seen"` // where LastActive comes from: "log", "state", or "workspace"
+}
+
+// Retired describes one retired identity.
+type Retired struct {
+	Identity
+	Archive  string   `json:"archive"`            // directory the identity was archived to
+	Messages int      `json:"messages"`           // open mail archived and closed
+	Warnings []string `json:"warnings,omitempty"` // steps that failed without stopping the retirement
+}
+
+// ParseAge parses an inactivity period: a Go duration, or a whole number
+// of days or weeks such as "30d" or "8w".
+func ParseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid period %q", s)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid period %q: use a duration such as 336h, 30d, or 8w", s)
+	}
+	return d, nil
+}
+
+// Roster returns the crew workers of the given rigs with their last
+// activity, least recently active first. Activity is the newest town log
+// event for the agent, archived logs included; without one, the worker's
+// state file or workspace modification time.
+func Roster(townRoot string, rigs []string) ([]Identity, error) {
+	events, err := townlog.ReadArchivedEvents(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading town log: %w", err)
+	}
+	lastEvent := make(map[string]time.Time)
+	for _, e := range events {
+		key := townlog.AgentKey(e.Agent)
+		if e.Timestamp.After(lastEvent[key]) {
+			lastEvent[key] = e.Timestamp
+		}
+	}
+
+	var roster []Identity
+	for _, rigName := range rigs {
+		workers, err := crewManager(townRoot, rigName).List()
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range workers {
+			id := Identity{
+				Agent: rigName + "/crew/" + w.Name,
+				Rig:   rigName,
+				Name:  w.Name,
+				Path:  w.ClonePath,
+			}
+			switch {
+			case !lastEvent[id.Agent].IsZero():
+				id.LastActive, id.Seen = lastEvent[id.Agent], "log"
+			case !w.UpdatedAt.IsZero():
+				id.LastActive, id.Seen = w.UpdatedAt, "state"
+			default:
+				if info, err := os.Stat(w.ClonePath); err == nil {
+					id.LastActive, id.Seen = info.ModTime(), "workspace"
+				}
+			}
+			roster = append(roster, id)
+		}
+	}
+	sort.SliceStable(roster, func(i, j int) bool { return roster[i].LastActive.Before(roster[j].LastActive) })
+	return roster, nil
+}
+
+// Inactive returns the identities of roster last active before cutoff.
+func Inactive(roster []Identity, cutoff time.Time) []Identity {
+	var stale []Identity
+	for _, id := range roster {
+		if id.LastActive.Before(cutoff) {
+			stale = append(stale, id)
+		}
+	}
+	return stale
+}
+
+// Check reports why an identity cannot be retired safely: a running
+// session, or work in its workspace that exists nowhere else. It returns
+// "" if there is nothing in the way.
+func Check(id Identity) string {
+	if running, _ := tmux.NewTmux().HasSession(session.CrewSessionName(id.Rig, id.Name)); running {
+		return "session is running"
+	}
+	status, err := git.NewGit(id.Path).CheckUncommittedWork()
+	if err != nil {
+		return ""
+	}
+	if !status.Clean() {
+		return status.String()
+	}
+	return ""
+}
+
+// Retire archives an identity and removes it from the roster, stopping its
+// session if one is running. Callers should Check it first; Retire does
+// not.
+func Retire(townRoot string, id Identity, now time.Time) (*Retired, error) {
+	r := &Retired{Identity: id}
+	r.Archive = filepath.Join(ArchiveDir(townRoot), fmt.Sprintf("%s-crew-%s-%s", id.Rig, id.Name, now.Format("20060102-150405")))
+	if err := os.MkdirAll(r.Archive, 0755); err != nil {
+		return nil, fmt.Errorf("creating archive: %w", err)
+	}
+	if err := writeJSON(filepath.Join(r.Archive, "identity.json"), id); err != nil {
+		return nil, err
+	}
+
+	// The registry entry, legacy mailbox, and notes live in the workspace.
+	for _, name := range []string{"state.json", "mail", ".polecat-checkpoint.json", ".runtime"} {
+		if err := copyTree(filepath.Join(id.Path, name), filepath.Join(r.Archive, name)); err != nil {
+			return nil, fmt.Errorf("archiving %s: %w", name, err)
+		}
+	}
+	parked, _ := filepath.Glob(filepath.Join(id.Path, ".beads", "parked-*.json"))
+	for _, path := range parked {
+		if err := copyTree(path, filepath.Join(r.Archive, "notes", filepath.Base(path))); err != nil {
+			return nil, fmt.Errorf("archiving parked work: %w", err)
+		}
+	}
+	handshake := config.HandshakePath(townRoot, id.Agent)
+	if err := copyTree(handshake, filepath.Join(r.Archive, "capabilities.json")); err != nil {
+		return nil, fmt.Errorf("archiving handshake: %w", err)
+	}
+
+	n, err := archiveMail(townRoot, id.Agent, filepath.Join(r.Archive, "mail.jsonl"))
+	r.Messages = n
+	if err != nil {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("archiving mail: %v", err))
+	}
+
+	t := tmux.NewTmux()
+	sessionName := session.CrewSessionName(id.Rig, id.Name)
+	if running, _ := t.HasSession(sessionName); running {
+		if err := t.KillSession(sessionName); err != nil {
+			return nil, fmt.Errorf("stopping session: %w", err)
+		}
+	}
+	if err := crewManager(townRoot, id.Rig).Remove(id.Name, true); err != nil {
+		return nil, fmt.Errorf("removing workspace: %w", err)
+	}
+	_ = os.Remove(handshake)
+
+	prefix := beads.GetPrefixForRig(townRoot, id.Rig)
+	bead := beads.CrewBeadIDWithPrefix(prefix, id.Rig, id.Name)
+	reason := fmt.Sprintf("Retired: inactive since %s", id.LastActive.Format("2006-01-02"))
+	if err := beads.New(filepath.Join(townRoot, id.Rig)).CloseWithReason(reason, bead); err != nil &&
+		!strings.Contains(err.Error(), "no issue found") && !strings.Contains(err.Error(), "already closed") {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("closing agent bead %s: %v", bead, err))
+	}
+
+	_ = townlog.NewLogger(townRoot).Emit(EventRetire, id.Agent,
+		fmt.Sprintf("inactive since %s, archived to %s", id.LastActive.Format("2006-01-02"), r.Archive))
+	return r, nil
+}
+
+// ArchiveDir returns where retired identities are archived.
+func ArchiveDir(townRoot string) string {
+	return filepath.Join(townRoot, "archive", "agents")
+}
+
+// archiveMail writes the agent's open mail to path, one JSON message per
+// line, and closes it. It returns how many messages it archived.
+func archiveMail(townRoot, agent, path string) (int, error) {
+	mailbox, err := mail.NewRouterWithTownRoot(townRoot, townRoot).GetMailbox(agent)
+	if err != nil {
+		return 0, err
+	}
+	messages, err := mailbox.List()
+	if err != nil || len(messages) == 0 {
+		return 0, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // G302: archive is non-sensitive operational data
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	n := 0
+	for _, msg := range messages {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return n, err
+		}
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			return n, err
+		}
+		if err := mailbox.Delete(msg.ID); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+func crewManager(townRoot, rigName string) *crew.Manager {
+	path := filepath.Join(townRoot, rigName)
+	return crew.NewManager(&rig.Rig{Name: rigName, Path: path}, git.NewGit(path))
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644) //nolint:gosec // G306: archive is non-sensitive operational data
+}
+
+// copyTree copies a file or directory tree to dst. A missing src copies
+// nothing.
+func copyTree(src, dst string) error {
+	info, err := os.Lstat(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dst, 0755); err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := copyTree(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !info.Mode().IsRegular() {
+		return nil // sockets, symlinks into the workspace, ...
+	}
+	data, err := os.ReadFile(src) //nolint:gosec // G304: src is within the workspace being archived
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, info.Mode().Perm())
+}
@@ -0,0 +1,111 @@
+package prune
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"8w", 8 * 7 * 24 * time.Hour},
+		{"30d", 30 * 24 * time.Hour},
+		{"36h", 36 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got, err := ParseAge(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseAge(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "w", "-2w", "8 weeks"} {
+		if _, err := ParseAge(bad); err == nil {
+			t.Errorf("ParseAge(%q) should fail", bad)
+		}
+	}
+}
+
+// newTown returns a town with one rig and crew workers max (last seen in
+// the log 90 days ago), joe (seen yesterday), and ann (never logged).
+func newTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	for _, name := range []string{"max", "joe", "ann"} {
+		dir := filepath.Join(townRoot, "gastown", "crew", name)
+		if err := os.MkdirAll(filepath.Join(dir, "mail"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "mail", "inbox.jsonl"), []byte(`{"id":"m1"}`+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "state.json"), []byte(`{"name":"`+name+`"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().AddDate(0, 0, -90)
+	_ = os.Chtimes(filepath.Join(townRoot, "gastown", "crew", "ann"), old, old)
+
+	l := townlog.NewLogger(townRoot)
+	_ = l.LogEvent(townlog.Event{Timestamp: old, Type: townlog.EventSpawn, Agent: "gastown/crew/max"})
+	_ = l.LogEvent(townlog.Event{Timestamp: time.Now().AddDate(0, 0, -1), Type: townlog.EventSpawn, Agent: "gastown/crew/joe"})
+	return townRoot
+}
+
+func TestRoster(t *testing.T) {
+	townRoot := newTown(t)
+	roster, err := Roster(townRoot, []string{"gastown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roster) != 3 || roster[2].Name != "joe" || roster[2].Seen != "log" {
+		t.Fatalf("Roster() = %+v, want joe, the most recently active, last", roster)
+	}
+
+	stale := Inactive(roster, time.Now().Add(-8*7*24*time.Hour))
+	if len(stale) != 2 {
+		t.Fatalf("Inactive() = %+v, want max and ann", stale)
+	}
+	for _, id := range stale {
+		if id.Name == "ann" && id.Seen != "workspace" {
+			t.Errorf("ann has no events, so its activity should come from the workspace, got %q", id.Seen)
+		}
+	}
+}
+
+func TestRetire(t *testing.T) {
+	townRoot := newTown(t)
+	roster, _ := Roster(townRoot, []string{"gastown"})
+	var retiree Identity
+	for _, id := range roster {
+		if id.Name == "max" {
+			retiree = id
+		}
+	}
+
+	r, err := Retire(townRoot, retiree, time.Now())
+	if err != nil {
+		t.Fatalf("Retire() error: %v", err)
+	}
+	for _, name := range []string{"identity.json", "state.json", filepath.Join("mail", "inbox.jsonl")} {
+		if _, err := os.Stat(filepath.Join(r.Archive, name)); err != nil {
+			t.Errorf("archive is missing %s: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(retiree.Path); !os.IsNotExist(err) {
+		t.Errorf("workspace should be removed, stat err = %v", err)
+	}
+	if after, _ := Roster(townRoot, []string{"gastown"}); len(after) != 2 {
+		t.Errorf("roster after retiring max = %+v", after)
+	}
+
+	events, _ := townlog.ReadEvents(townRoot)
+	last := events[len(events)-1]
+	if last.Type != EventRetire || last.Agent != "gastown/crew/max" {
+		t.Errorf("last event = %+v, want a retire event for max", last)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/ctiospl/gastown/internal/git"
 	"github.com/ctiospl/gastown/internal/mrqueue"
 	"github.com/ctiospl/gastown/internal/rig"
+	"github.com/ctiospl/gastown/internal/session"
 )
 
 // MergeQueueConfig holds configuration for the merge queue processor.
@@ -466,7 +467,32 @@ func (e *Engineer) ProcessMRFromQueue(ctx context.Context, mr *mrqueue.MR) Proce
 	}
 
 	// Use the shared merge logic
-	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	result := e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	e.tagTestOutcome(mr, result)
+	return result
+}
+
+// tagTestOutcome records the test gate result on the worker's session so
+// quality reports can see it (see 'gt session tags'). Merges that never
+// reached the tests are not tagged.
+func (e *Engineer) tagTestOutcome(mr *mrqueue.MR, result ProcessResult) {
+	if !e.config.RunTests || e.config.TestCommand == "" || mr.Worker == "" {
+		return
+	}
+	var tag string
+	switch {
+	case result.TestsFailed:
+		tag = session.TagTestsRed
+	case result.Success:
+		tag = session.TagTestsGreen
+	default:
+		return
+	}
+	agent := fmt.Sprintf("%s/polecats/%s", e.rig.Name, mr.Worker)
+	townRoot := filepath.Dir(e.rig.Path)
+	if _, err := session.TagSession(townRoot, e.rig.Name+"/refinery", agent, mr.SourceIssue, tag, ""); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to tag session with %s: %v\n", tag, err)
+	}
 }
 
 // handleSuccessFromQueue handles a successful merge from wisp queue.
@@ -0,0 +1,117 @@
+// Package remote runs crew agents on other machines: 'gt spawn <agent>
+// --host build-box-2' provisions the agent's worktree on the host over ssh,
+// and the agent's session runs its runtime there through ssh (see
+// config.CrewBinding), so nudges and kills, which act on the local tmux
+// pane, reach it as they reach local agents.
+//
+// A host keeps its agents' worktrees under its dir, laid out as a town of
+// its own: the gt run there by remote agents logs their events to the
+// dir's logs/town.log. The daemon pulls those events into the local town
+// log with SyncEvents, skipping those it already has, so remote agents'
+// work shows in gt log like local agents'.
+package remote
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// SyncLines is how many of the last lines of a host's town log SyncEvents
+// reads each time.
+const SyncLines = 500
+
+// townStub is the mayor/town.json that makes a host's dir a town, for the
+// gt run by its agents.
+const townStub = `{"type":"town","version":1,"name":"remote"}`
+
+// ProvisionScript returns the shell script that prepares a crew member's
+// worktree on h, cloning gitURL into it unless it is already there.
+func ProvisionScript(h config.Host, gitURL, rigName, crewName string) string {
+	dir := config.RemotePath(strings.TrimSuffix(h.Dir, "/"))
+	return strings.Join([]string{
+		"set -e",
+		"mkdir -p " + dir + "/mayor " + dir + "/logs",
+		"[ -f " + dir + "/mayor/town.json ] || printf '%s\\n' " + util.ShellQuote(townStub) + " > " + dir + "/mayor/town.json",
+		"wt=" + config.RemotePath(h.WorkDir(rigName, crewName)),
+		`[ -d "$wt/.git" ] || git clone -q ` + util.ShellQuote(gitURL) + ` "$wt"`,
+	}, "\n")
+}
+
+// Provision prepares a crew member's worktree on h for its rig's
+// repository at gitURL.
+func Provision(h config.Host, gitURL, rigName, crewName string) error {
+	if gitURL == "" {
+		return fmt.Errorf("rig %s has no git URL to clone on %s", rigName, h.Name)
+	}
+	_, err := run(h, ProvisionScript(h, gitURL, rigName, crewName))
+	if err != nil {
+		return fmt.Errorf("provisioning %s/crew/%s on %s: %w", rigName, crewName, h.Name, err)
+	}
+	return nil
+}
+
+// SyncEvents imports the events logged on h that the town's log lacks,
+// returning how many it imported.
+func SyncEvents(townRoot string, h config.Host) (int, error) {
+	log := config.RemotePath(strings.TrimSuffix(h.Dir, "/") + "/logs/town.log")
+	out, err := run(h, fmt.Sprintf("[ ! -f %s ] || tail -n %d %s", log, SyncLines, log))
+	if err != nil {
+		return 0, fmt.Errorf("reading the town log on %s: %w", h.Name, err)
+	}
+	events, err := townlog.ParseLogLines(out)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+	return townlog.NewLogger(townRoot).Import(events)
+}
+
+// ActiveHosts returns the names of the hosts that crew members of the town
+// bound to them are running on, sorted, as told by running, which reports
+// whether a crew member's session is running.
+func ActiveHosts(townRoot string, running func(rigName, crewName string) bool) []string {
+	paths, _ := filepath.Glob(filepath.Join(townRoot, "*", "crew", "*", ".runtime", "binding.json"))
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, p := range paths {
+		crewDir := filepath.Dir(filepath.Dir(p))
+		rigPath, crewName := filepath.Dir(filepath.Dir(crewDir)), filepath.Base(crewDir)
+		name := config.ReadCrewBinding(rigPath, crewName).Host
+		if name != "" && !seen[name] && running(filepath.Base(rigPath), crewName) {
+			seen[name] = true
+			hosts = append(hosts, name)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// run runs script on h without prompting, returning its output.
+func run(h config.Host, script string) (string, error) {
+	args := h.SSHCommand(false, script)
+	args = append([]string{args[0], "-o", "BatchMode=yes"}, args[1:]...)
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // G204: args come from the town's host settings
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			if msg := strings.TrimSpace(string(ee.Stderr)); msg != "" {
+				return "", fmt.Errorf("%v: %s", err, lastLine(msg))
+			}
+		}
+		return "", err
+	}
+	return string(out), nil
+}
+
+func lastLine(s string) string {
+	if i := strings.LastIndex(s, "\n"); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package remote
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+// fakeSSH puts an ssh on PATH that runs its last argument locally.
+func fakeSSH(t *testing.T) {
+	t.Helper()
+	bin := t.TempDir()
+	script := "#!/bin/sh\nfor last; do :; done\nexec sh -c \"$last\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "ssh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestProvision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	fakeSSH(t)
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+
+	h := config.Host{Name: "box", HostConfig: config.HostConfig{SSH: "box", Dir: filepath.Join(t.TempDir(), "gt remote")}}
+	if err := Provision(h, "", "gastown", "max"); err == nil {
+		t.Error("Provision without a git URL succeeded")
+	}
+	for i := 0; i < 2; i++ { // again once provisioned
+		if err := Provision(h, repo, "gastown", "max"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, p := range []string{"mayor/town.json", "logs", "gastown/crew/max/.git"} {
+		if _, err := os.Stat(filepath.Join(h.Dir, p)); err != nil {
+			t.Errorf("provisioned host lacks %s: %v", p, err)
+		}
+	}
+}
+
+func TestSyncEvents(t *testing.T) {
+	fakeSSH(t)
+	townRoot := t.TempDir()
+	h := config.Host{Name: "box", HostConfig: config.HostConfig{SSH: "box", Dir: t.TempDir()}}
+
+	if n, err := SyncEvents(townRoot, h); err != nil || n != 0 {
+		t.Fatalf("SyncEvents without a remote log = %d, %v", n, err)
+	}
+
+	remote := townlog.NewLogger(h.Dir)
+	when := time.Now().Add(-time.Minute).Truncate(time.Second)
+	if err := remote.LogEvent(townlog.Event{Timestamp: when, Type: townlog.EventDone, Agent: "gastown/crew/max", Context: "gt-abc"}); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int{1, 0} { // nothing new the second time
+		n, err := SyncEvents(townRoot, h)
+		if err != nil || n != want {
+			t.Fatalf("SyncEvents #%d = %d, %v; want %d", i+1, n, err, want)
+		}
+	}
+	events, err := townlog.ReadEvents(townRoot)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("local events = %v, %v", events, err)
+	}
+	if e := events[0]; e.Agent != "gastown/crew/max" || !e.Timestamp.Equal(when) {
+		t.Errorf("synced event = %+v", e)
+	}
+}
+
+func TestActiveHosts(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	for name, host := range map[string]string{"max": "box-b", "joe": "box-a", "sam": "box-b", "bob": "box-c"} {
+		if err := config.SetCrewBinding(rigPath, name, config.CrewBinding{Host: host}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	running := func(rigName, crewName string) bool { return rigName == "gastown" && crewName != "bob" }
+	if got := strings.Join(ActiveHosts(townRoot, running), ","); got != "box-a,box-b" {
+		t.Errorf("ActiveHosts = %q", got)
+	}
+}
@@ -0,0 +1,250 @@
+package retrieval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ctiospl/gastown/internal/config"
+)
+
+// embedTimeout bounds one call to an embeddings provider.
+const embedTimeout = 2 * time.Minute
+
+// localDims is the vector size of the built-in local embedder.
+const localDims = 512
+
+// Embedder turns texts into vectors. Vectors of one embedder are
+// comparable with each other, and with no other embedder's.
+type Embedder interface {
+	// Name identifies the embedder and model, so an index built with one
+	// is not queried with another.
+	Name() string
+
+	// Embed returns one vector per text.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// NewEmbedder returns the embedder a rig's context settings select. A nil
+// config selects the local embedder.
+func NewEmbedder(c *config.EmbeddingsConfig) (Embedder, error) {
+	if c == nil {
+		return localEmbedder{}, nil
+	}
+	switch c.Provider {
+	case "", config.EmbeddingsLocal:
+		return localEmbedder{}, nil
+	case config.EmbeddingsOpenAI:
+		url := c.URL
+		if url == "" {
+			url = "https://api.openai.com/v1"
+		}
+		var key string
+		if c.APIKeyEnv != "" {
+			if key = os.Getenv(c.APIKeyEnv); key == "" {
+				return nil, fmt.Errorf("embeddings API key: $%s is not set", c.APIKeyEnv)
+			}
+		}
+		return &openAIEmbedder{url: strings.TrimSuffix(url, "/"), model: c.Model, key: key}, nil
+	case config.EmbeddingsCommand:
+		if len(c.Command) == 0 {
+			return nil, fmt.Errorf("embeddings provider %q needs a command", c.Provider)
+		}
+		return &commandEmbedder{argv: c.Command}, nil
+	default:
+		return nil, fmt.Errorf("unknown embeddings provider %q", c.Provider)
+	}
+}
+
+// localEmbedder hashes words and word pairs into a fixed-size vector. It
+// needs no model and finds snippets that share identifiers and terms with
+// the query, which is most of what matters for code.
+type localEmbedder struct{}
+
+func (localEmbedder) Name() string { return config.EmbeddingsLocal }
+
+func (localEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		v := make([]float32, localDims)
+		words := tokenize(text)
+		for j, w := range words {
+			v[hashBucket(w)]++
+			if j > 0 {
+				v[hashBucket(words[j-1]+" "+w)] += 0.5
+			}
+		}
+		vectors[i] = normalize(v)
+	}
+	return vectors, nil
+}
+
+// tokenize lowercases text and splits it into words, also splitting
+// camelCase and snake_case identifiers into their parts.
+func tokenize(text string) []string {
+	var words []string
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		lower := strings.ToLower(field)
+		if len(lower) > 1 {
+			words = append(words, lower)
+		}
+		parts := identifierParts(field)
+		if len(parts) > 1 {
+			for _, p := range parts {
+				if len(p) > 1 {
+					words = append(words, strings.ToLower(p))
+				}
+			}
+		}
+	}
+	return words
+}
+
+// identifierParts splits "parseLogLine" or "parse_log_line" into words.
+func identifierParts(s string) []string {
+	var parts []string
+	start := 0
+	runes := []rune(s)
+	for i := 1; i <= len(runes); i++ {
+		if i == len(runes) || runes[i] == '_' ||
+			(unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i-1])) {
+			if part := strings.Trim(string(runes[start:i]), "_"); part != "" {
+				parts = append(parts, part)
+			}
+			start = i
+		}
+	}
+	return parts
+}
+
+func hashBucket(s string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return int(h.Sum32() % localDims)
+}
+
+// normalize scales v to unit length, so a dot product is cosine similarity.
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	n := float32(math.Sqrt(sum))
+	for i := range v {
+		v[i] /= n
+	}
+	return v
+}
+
+// openAIEmbedder calls an OpenAI-compatible /embeddings endpoint.
+type openAIEmbedder struct {
+	url   string
+	model string
+	key   string
+}
+
+func (e *openAIEmbedder) Name() string { return config.EmbeddingsOpenAI + ":" + e.model }
+
+func (e *openAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(map[string]interface{}{"model": e.model, "input": texts})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, embedTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.key != "" {
+		req.Header.Set("Authorization", "Bearer "+e.key)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embeddings request: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading embeddings response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embeddings request: %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var out struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parsing embeddings response: %w", err)
+	}
+	if len(out.Data) != len(texts) {
+		return nil, fmt.Errorf("embeddings response has %d vectors for %d texts", len(out.Data), len(texts))
+	}
+	vectors := make([][]float32, len(texts))
+	for _, d := range out.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("embeddings response has out-of-range index %d", d.Index)
+		}
+		vectors[d.Index] = normalize(d.Embedding)
+	}
+	return vectors, nil
+}
+
+// commandEmbedder runs a program that maps a JSON array of texts on stdin
+// to a JSON array of vectors on stdout.
+type commandEmbedder struct {
+	argv []string
+}
+
+func (e *commandEmbedder) Name() string {
+	return config.EmbeddingsCommand + ":" + strings.Join(e.argv, " ")
+}
+
+func (e *commandEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	input, err := json.Marshal(texts)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, embedTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, e.argv[0], e.argv[1:]...) //nolint:gosec // G204: command comes from rig settings
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("embeddings command: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var vectors [][]float32
+	if err := json.Unmarshal(stdout.Bytes(), &vectors); err != nil {
+		return nil, fmt.Errorf("parsing embeddings command output: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("embeddings command returned %d vectors for %d texts", len(vectors), len(texts))
+	}
+	for i := range vectors {
+		vectors[i] = normalize(vectors[i])
+	}
+	return vectors, nil
+}
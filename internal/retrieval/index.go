@@ -0,0 +1,239 @@
+// Package retrieval maintains a rig's context index: embeddings of its
+// code, agent notes, and past handoffs, searched to give a new task the
+// most relevant snippets within a token budget.
+package retrieval
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Document sources.
+const (
+	SourceCode    = "code"
+	SourceNote    = "note"
+	SourceHandoff = "handoff"
+)
+
+// CurrentIndexVersion is the current schema version of the index file.
+const CurrentIndexVersion = 1
+
+const (
+	chunkLines    = 40   // lines per chunk
+	maxChunkBytes = 4000 // longer chunks are cut
+	embedBatch    = 64   // texts per embeddings call
+
+	// relevanceFloor drops hits scoring below this fraction of the best
+	// one, so leftover budget is not filled with noise.
+	relevanceFloor = 0.4
+)
+
+// ErrNoIndex means the rig has no context index yet.
+var ErrNoIndex = errors.New("no context index (run 'gt context index')")
+
+// Document is one unit of indexable text.
+type Document struct {
+	Source string // SourceCode, SourceNote, or SourceHandoff
+	Path   string // file path relative to the repo, or where the text came from
+	Text   string
+}
+
+// Chunk is an indexed slice of a document.
+type Chunk struct {
+	Source string    `json:"source"`
+	Path   string    `json:"path"`
+	Line   int       `json:"line"` // first line, 1-based
+	Text   string    `json:"text"`
+	Vector []float32 `json:"vector"`
+}
+
+// Index is a rig's context index.
+type Index struct {
+	Version  int       `json:"version"`
+	Embedder string    `json:"embedder"` // Embedder.Name() of the model that built it
+	BuiltAt  time.Time `json:"built_at"`
+	Chunks   []Chunk   `json:"chunks"`
+}
+
+// Hit is a chunk retrieved for a query.
+type Hit struct {
+	Source string  `json:"source"`
+	Path   string  `json:"path"`
+	Line   int     `json:"line"`
+	Text   string  `json:"text"`
+	Score  float32 `json:"score"`
+	Tokens int     `json:"tokens"`
+}
+
+// IndexPath returns where a rig's context index is stored.
+func IndexPath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "context-index.json.gz")
+}
+
+// EstimateTokens approximates the token count of text (about four bytes
+// per token for English and code).
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// Build chunks documents and embeds every chunk.
+func Build(ctx context.Context, docs []Document, e Embedder) (*Index, error) {
+	var chunks []Chunk
+	for _, d := range docs {
+		chunks = append(chunks, chunkDocument(d)...)
+	}
+	for start := 0; start < len(chunks); start += embedBatch {
+		end := min(start+embedBatch, len(chunks))
+		texts := make([]string, 0, end-start)
+		for _, c := range chunks[start:end] {
+			texts = append(texts, c.Path+"\n"+c.Text)
+		}
+		vectors, err := e.Embed(ctx, texts)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range vectors {
+			chunks[start+i].Vector = v
+		}
+	}
+	return &Index{
+		Version:  CurrentIndexVersion,
+		Embedder: e.Name(),
+		BuiltAt:  time.Now(),
+		Chunks:   chunks,
+	}, nil
+}
+
+// chunkDocument splits a document into chunks of chunkLines lines.
+// Blank chunks are dropped.
+func chunkDocument(d Document) []Chunk {
+	lines := strings.Split(d.Text, "\n")
+	var chunks []Chunk
+	for start := 0; start < len(lines); start += chunkLines {
+		end := min(start+chunkLines, len(lines))
+		text := strings.Join(lines[start:end], "\n")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if len(text) > maxChunkBytes {
+			text = text[:maxChunkBytes]
+		}
+		chunks = append(chunks, Chunk{Source: d.Source, Path: d.Path, Line: start + 1, Text: text})
+	}
+	return chunks
+}
+
+// Load reads a rig's context index.
+func Load(rigPath string) (*Index, error) {
+	f, err := os.Open(IndexPath(rigPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoIndex
+		}
+		return nil, fmt.Errorf("opening context index: %w", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading context index: %w", err)
+	}
+	defer zr.Close()
+
+	var ix Index
+	if err := json.NewDecoder(zr).Decode(&ix); err != nil {
+		return nil, fmt.Errorf("parsing context index: %w", err)
+	}
+	if ix.Version > CurrentIndexVersion {
+		return nil, fmt.Errorf("context index version %d is newer than this gt supports", ix.Version)
+	}
+	return &ix, nil
+}
+
+// Save writes the index for a rig, replacing any previous one atomically.
+func (ix *Index) Save(rigPath string) error {
+	path := IndexPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".context-index-*")
+	if err != nil {
+		return fmt.Errorf("creating context index: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // gone after a successful rename
+
+	zw := gzip.NewWriter(tmp)
+	if err := json.NewEncoder(zw).Encode(ix); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing context index: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing context index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing context index: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Search returns the chunks most similar to query, best first, whose
+// estimated tokens together fit in budget. Chunks too large for what is
+// left of the budget are skipped in favor of smaller ones, and chunks far
+// less similar than the best are left out.
+func (ix *Index) Search(ctx context.Context, e Embedder, query string, budget int) ([]Hit, error) {
+	if e.Name() != ix.Embedder {
+		return nil, fmt.Errorf("context index was built with %s, not %s; rebuild it with 'gt context index'", ix.Embedder, e.Name())
+	}
+	vectors, err := e.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	q := vectors[0]
+
+	hits := make([]Hit, 0, len(ix.Chunks))
+	for _, c := range ix.Chunks {
+		score := dot(q, c.Vector)
+		if score <= 0 {
+			continue
+		}
+		hits = append(hits, Hit{
+			Source: c.Source,
+			Path:   c.Path,
+			Line:   c.Line,
+			Text:   c.Text,
+			Score:  score,
+			Tokens: EstimateTokens(c.Text),
+		})
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+
+	var selected []Hit
+	left := budget
+	for _, h := range hits {
+		if h.Score < hits[0].Score*relevanceFloor {
+			break
+		}
+		if h.Tokens <= left {
+			selected = append(selected, h)
+			left -= h.Tokens
+		}
+	}
+	return selected, nil
+}
+
+// dot is the dot product of two vectors, over their common length.
+func dot(a, b []float32) float32 {
+	var sum float32
+	for i := 0; i < len(a) && i < len(b); i++ {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
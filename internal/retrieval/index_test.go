@@ -0,0 +1,184 @@
+package retrieval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ctiospl/gastown/internal/config"
+)
+
+func testDocs() []Document {
+	return []Document{
+		{Source: SourceCode, Path: "internal/mail/router.go", Text: "func (r *Router) notifyRecipient(msg *Message) {\n\t// show a banner in the recipient session\n}"},
+		{Source: SourceCode, Path: "internal/townlog/logger.go", Text: "func parseLogLine(line string) (Event, error) {\n\t// parse a town log line\n}"},
+		{Source: SourceNote, Path: "polecats/Toast", Text: "Working on gt-12\nRouter banners flicker when the recipient session is busy."},
+		{Source: SourceHandoff, Path: "gastown/crew/max hq-1", Text: "🤝 HANDOFF: deploy\nStaging deploy script half done."},
+	}
+}
+
+func TestBuildAndSearch(t *testing.T) {
+	ctx := context.Background()
+	e := localEmbedder{}
+	ix, err := Build(ctx, testDocs(), e)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(ix.Chunks) != 4 {
+		t.Fatalf("got %d chunks, want 4", len(ix.Chunks))
+	}
+
+	rigPath := t.TempDir()
+	if _, err := Load(rigPath); !errors.Is(err, ErrNoIndex) {
+		t.Fatalf("Load before Save: err = %v, want ErrNoIndex", err)
+	}
+	if err := ix.Save(rigPath); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := Load(rigPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	hits, err := loaded.Search(ctx, e, "recipient session banner in the mail router", 1000)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(hits) < 2 {
+		t.Fatalf("got %d hits, want at least 2", len(hits))
+	}
+	top := map[string]bool{hits[0].Path: true, hits[1].Path: true}
+	if !top["internal/mail/router.go"] || !top["polecats/Toast"] {
+		t.Errorf("top hits = %s, %s; want the router code and Toast's note", hits[0].Path, hits[1].Path)
+	}
+	for i := 1; i < len(hits); i++ {
+		if hits[i].Score > hits[i-1].Score {
+			t.Errorf("hits not sorted by score: %v", hits)
+		}
+	}
+}
+
+func TestSearchBudget(t *testing.T) {
+	ctx := context.Background()
+	e := localEmbedder{}
+	ix, err := Build(ctx, testDocs(), e)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	budget := 30
+	hits, err := ix.Search(ctx, e, "router session log line deploy", budget)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	total := 0
+	for _, h := range hits {
+		total += h.Tokens
+	}
+	if total > budget {
+		t.Errorf("hits use %d tokens, budget %d", total, budget)
+	}
+
+	if hits, _ := ix.Search(ctx, e, "router", 0); len(hits) != 0 {
+		t.Errorf("zero budget returned %d hits", len(hits))
+	}
+}
+
+func TestSearchRejectsOtherEmbedder(t *testing.T) {
+	ctx := context.Background()
+	ix, err := Build(ctx, testDocs(), localEmbedder{})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	other := &commandEmbedder{argv: []string{"embed"}}
+	if _, err := ix.Search(ctx, other, "router", 100); err == nil {
+		t.Error("Search with a different embedder should fail")
+	}
+}
+
+func TestChunkDocument(t *testing.T) {
+	lines := make([]string, chunkLines+5)
+	for i := range lines {
+		lines[i] = "line"
+	}
+	chunks := chunkDocument(Document{Source: SourceCode, Path: "a.go", Text: strings.Join(lines, "\n")})
+	if len(chunks) != 2 {
+		t.Fatalf("got %d chunks, want 2", len(chunks))
+	}
+	if chunks[0].Line != 1 || chunks[1].Line != chunkLines+1 {
+		t.Errorf("chunk lines = %d, %d, want 1, %d", chunks[0].Line, chunks[1].Line, chunkLines+1)
+	}
+	if got := chunkDocument(Document{Text: "\n\n  \n"}); len(got) != 0 {
+		t.Errorf("blank document gave %d chunks", len(got))
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := tokenize("parseLogLine(town_log) x")
+	want := []string{"parselogline", "parse", "log", "line", "town_log", "town", "log"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokenize = %v, want %v", got, want)
+	}
+}
+
+func TestOpenAIEmbedder(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		var req struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.URL.Path != "/v1/embeddings" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		type item struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}
+		var data []item
+		for i := len(req.Input) - 1; i >= 0; i-- { // out of order on purpose
+			data = append(data, item{Index: i, Embedding: []float32{float32(i + 1), 0}})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer srv.Close()
+
+	t.Setenv("TEST_EMBED_KEY", "sk-test")
+	e, err := NewEmbedder(&config.EmbeddingsConfig{
+		Provider:  config.EmbeddingsOpenAI,
+		Model:     "test-embed",
+		URL:       srv.URL + "/v1/",
+		APIKeyEnv: "TEST_EMBED_KEY",
+	})
+	if err != nil {
+		t.Fatalf("NewEmbedder: %v", err)
+	}
+	if e.Name() != "openai:test-embed" {
+		t.Errorf("Name = %q", e.Name())
+	}
+	vectors, err := e.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if gotAuth != "Bearer sk-test" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	if len(vectors) != 2 || vectors[0][0] != 1 || vectors[1][0] != 1 {
+		t.Errorf("vectors = %v, want two unit vectors in index order", vectors)
+	}
+}
+
+func TestNewEmbedderMissingKey(t *testing.T) {
+	t.Setenv("TEST_EMBED_KEY", "")
+	_, err := NewEmbedder(&config.EmbeddingsConfig{Provider: config.EmbeddingsOpenAI, Model: "m", APIKeyEnv: "TEST_EMBED_KEY"})
+	if err == nil {
+		t.Error("NewEmbedder with an unset API key should fail")
+	}
+}
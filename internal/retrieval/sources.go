@@ -0,0 +1,130 @@
+package retrieval
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ctiospl/gastown/internal/beads"
+	"github.com/ctiospl/gastown/internal/checkpoint"
+)
+
+// maxFileBytes skips files too large to be useful context (generated
+// code, data, bundles).
+const maxFileBytes = 256 * 1024
+
+// skippedDirs are path segments whose files are never indexed.
+var skippedDirs = []string{"vendor/", "node_modules/", "third_party/", "dist/", ".beads/"}
+
+// CodeDocuments returns the tracked text files of the git checkout at
+// repoDir. Binary, oversized, and vendored files are skipped.
+func CodeDocuments(repoDir string) ([]Document, error) {
+	cmd := exec.Command("git", "ls-files", "-z")
+	cmd.Dir = repoDir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("listing files in %s: %s", repoDir, strings.TrimSpace(stderr.String()))
+	}
+
+	var docs []Document
+	for _, rel := range strings.Split(stdout.String(), "\x00") {
+		if rel == "" || skippedPath(rel) {
+			continue
+		}
+		path := filepath.Join(repoDir, rel)
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxFileBytes {
+			continue
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // G304: tracked file of the rig's repo
+		if err != nil || isBinary(data) {
+			continue
+		}
+		docs = append(docs, Document{Source: SourceCode, Path: rel, Text: string(data)})
+	}
+	return docs, nil
+}
+
+func skippedPath(rel string) bool {
+	for _, dir := range skippedDirs {
+		if strings.HasPrefix(rel, dir) || strings.Contains(rel, "/"+dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBinary reports whether data looks like a binary file: a NUL byte in
+// its first 8KB, as git decides.
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0
+}
+
+// NoteDocuments returns the notes in the checkpoints of a rig's polecats
+// and crew, with the step and files they were working on.
+func NoteDocuments(rigPath string) []Document {
+	var docs []Document
+	for _, group := range []string{"polecats", "crew"} {
+		entries, err := os.ReadDir(filepath.Join(rigPath, group))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			cp, err := checkpoint.Read(filepath.Join(rigPath, group, entry.Name()))
+			if err != nil || cp == nil || cp.Notes == "" {
+				continue
+			}
+			var b strings.Builder
+			if cp.HookedBead != "" {
+				fmt.Fprintf(&b, "Working on %s", cp.HookedBead)
+				if cp.StepTitle != "" {
+					fmt.Fprintf(&b, " (%s)", cp.StepTitle)
+				}
+				b.WriteString("\n")
+			}
+			b.WriteString(cp.Notes)
+			if len(cp.ModifiedFiles) > 0 {
+				fmt.Fprintf(&b, "\nModified: %s", strings.Join(cp.ModifiedFiles, ", "))
+			}
+			docs = append(docs, Document{
+				Source: SourceNote,
+				Path:   group + "/" + entry.Name(),
+				Text:   b.String(),
+			})
+		}
+	}
+	return docs
+}
+
+// HandoffDocuments returns the handoff mail that agents of a rig sent to
+// their next session ('gt handoff -s ... -m ...'), read from town beads.
+func HandoffDocuments(townRoot, rigName string) ([]Document, error) {
+	messages, err := beads.New(townRoot).List(beads.ListOptions{
+		Status:   "all",
+		Type:     "message",
+		Priority: -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing handoff mail: %w", err)
+	}
+	var docs []Document
+	for _, m := range messages {
+		if !strings.Contains(m.Title, "HANDOFF") || !strings.HasPrefix(m.Assignee, rigName+"/") {
+			continue
+		}
+		docs = append(docs, Document{
+			Source: SourceHandoff,
+			Path:   m.Assignee + " " + m.ID,
+			Text:   m.Title + "\n" + m.Description,
+		})
+	}
+	return docs, nil
+}
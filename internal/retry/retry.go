@@ -0,0 +1,112 @@
+// Package retry decides what happens to the work of an agent that crashed
+// with a task on its hook, under the town's retry policy for the rig: the
+// same agent is respawned on it, the task is reassigned to a fresh
+// polecat, or it is escalated to a human. A task that has been respawned
+// or reassigned MaxAttempts times is escalated instead.
+//
+// As for supervised restarts, the attempts are read back from the town
+// log, from the "retry" events naming the task, rather than kept in a
+// state file. Escalating a task ends its run of attempts: once a human has
+// started it again, its next crash is a first one.
+package retry
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+// EventRetry is the town log event recorded for each retry decision.
+const EventRetry townlog.EventType = "retry"
+
+// Retry actions.
+const (
+	Respawn  = "respawn"
+	Reassign = "reassign"
+	Escalate = "escalate"
+)
+
+// Actions lists the retry actions.
+var Actions = []string{Respawn, Reassign, Escalate}
+
+// DefaultMaxAttempts is how many times a task is retried before it is
+// escalated, unless the policy says otherwise.
+const DefaultMaxAttempts = 3
+
+// Policy is a resolved retry policy.
+type Policy struct {
+	Action      string
+	MaxAttempts int
+}
+
+// PolicyFor returns the retry policy for a rig, and whether the town has
+// one for it.
+func PolicyFor(c *config.RetryConfig, rig string) (Policy, bool, error) {
+	if c == nil {
+		return Policy{}, false, nil
+	}
+	p := c.Default
+	if r := c.Rigs[rig]; r != nil {
+		p = r
+	}
+	if p == nil || p.Action == "" {
+		return Policy{}, false, nil
+	}
+	if !slices.Contains(Actions, p.Action) {
+		return Policy{}, false, fmt.Errorf("unknown retry action %q for rig %s (want %s)", p.Action, rig, strings.Join(Actions, ", "))
+	}
+	attempts := p.MaxAttempts
+	if attempts <= 0 {
+		attempts = DefaultMaxAttempts
+	}
+	return Policy{Action: p.Action, MaxAttempts: attempts}, true, nil
+}
+
+// Decision is what to do about a task whose agent crashed.
+type Decision struct {
+	Action  string
+	Attempt int // the retry's number in the task's current run, from 1
+}
+
+// Decide returns what to do about task at now, given the town log.
+func Decide(events []townlog.Event, task string, p Policy, now time.Time) Decision {
+	attempts := 0
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		if e.Type != EventRetry || e.Timestamp.After(now) {
+			continue
+		}
+		rest, ok := strings.CutPrefix(e.Context, task+": ")
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(rest, "escalated") {
+			break
+		}
+		attempts++
+	}
+	d := Decision{Action: p.Action, Attempt: attempts + 1}
+	if attempts >= p.MaxAttempts {
+		d.Action = Escalate
+	}
+	return d
+}
+
+// Context is the context of the retry event recording d for task, whose
+// agent crashed.
+func (d Decision) Context(task, agent string, p Policy) string {
+	switch {
+	case d.Action == Respawn:
+		return fmt.Sprintf("%s: respawned %s (attempt %d/%d)", task, agent, d.Attempt, p.MaxAttempts)
+	case d.Action == Reassign:
+		return fmt.Sprintf("%s: reassigned from %s to a fresh polecat (attempt %d/%d)", task, agent, d.Attempt, p.MaxAttempts)
+	case p.Action != Escalate:
+		return fmt.Sprintf("%s: escalated after %d attempts; %s crashed", task, p.MaxAttempts, agent)
+	default:
+		return fmt.Sprintf("%s: escalated; %s crashed", task, agent)
+	}
+}
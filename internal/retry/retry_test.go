@@ -0,0 +1,93 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+func TestPolicyFor(t *testing.T) {
+	c := &config.RetryConfig{
+		Default: &config.RetryPolicy{Action: Respawn},
+		Rigs: map[string]*config.RetryPolicy{
+			"beads":  {Action: Reassign, MaxAttempts: 1},
+			"wyvern": {Action: "retry"},
+		},
+	}
+	tests := []struct {
+		rig     string
+		want    Policy
+		wantOK  bool
+		wantErr bool
+	}{
+		{"gastown", Policy{Action: Respawn, MaxAttempts: DefaultMaxAttempts}, true, false},
+		{"beads", Policy{Action: Reassign, MaxAttempts: 1}, true, false},
+		{"wyvern", Policy{}, false, true},
+	}
+	for _, tt := range tests {
+		p, ok, err := PolicyFor(c, tt.rig)
+		if p != tt.want || ok != tt.wantOK || (err != nil) != tt.wantErr {
+			t.Errorf("PolicyFor(%s) = %+v, %v, %v; want %+v, %v", tt.rig, p, ok, err, tt.want, tt.wantOK)
+		}
+	}
+	if _, ok, _ := PolicyFor(&config.RetryConfig{Rigs: c.Rigs}, "gastown"); ok {
+		t.Error("a rig without a policy and no default should not retry")
+	}
+	if _, ok, _ := PolicyFor(nil, "gastown"); ok {
+		t.Error("no policy should not retry")
+	}
+}
+
+func TestDecide(t *testing.T) {
+	p := Policy{Action: Reassign, MaxAttempts: 2}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	retried := func(ago time.Duration, d Decision, task string) townlog.Event {
+		return townlog.Event{Timestamp: now.Add(-ago), Type: EventRetry, Agent: "gastown/Toast", Context: d.Context(task, "gastown/Toast", p)}
+	}
+
+	tests := []struct {
+		name   string
+		events []townlog.Event
+		want   Decision
+	}{
+		{"first crash", nil, Decision{Action: Reassign, Attempt: 1}},
+		{
+			"second crash",
+			[]townlog.Event{retried(time.Hour, Decision{Action: Reassign, Attempt: 1}, "gt-abc")},
+			Decision{Action: Reassign, Attempt: 2},
+		},
+		{
+			"other tasks do not count",
+			[]townlog.Event{retried(time.Hour, Decision{Action: Reassign, Attempt: 1}, "gt-abcd")},
+			Decision{Action: Reassign, Attempt: 1},
+		},
+		{
+			"attempts used up",
+			[]townlog.Event{
+				retried(2*time.Hour, Decision{Action: Reassign, Attempt: 1}, "gt-abc"),
+				retried(time.Hour, Decision{Action: Reassign, Attempt: 2}, "gt-abc"),
+			},
+			Decision{Action: Escalate, Attempt: 3},
+		},
+		{
+			"started by hand after escalating",
+			[]townlog.Event{
+				retried(3*time.Hour, Decision{Action: Reassign, Attempt: 1}, "gt-abc"),
+				retried(2*time.Hour, Decision{Action: Reassign, Attempt: 2}, "gt-abc"),
+				retried(time.Hour, Decision{Action: Escalate, Attempt: 3}, "gt-abc"),
+			},
+			Decision{Action: Reassign, Attempt: 1},
+		},
+	}
+	for _, tt := range tests {
+		if got := Decide(tt.events, "gt-abc", p, now); got != tt.want {
+			t.Errorf("%s: Decide = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	if got := Decide(nil, "gt-abc", Policy{Action: Escalate, MaxAttempts: 3}, now); got.Action != Escalate {
+		t.Errorf("escalate policy: Decide = %+v", got)
+	}
+}
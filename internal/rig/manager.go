@@ -33,6 +33,26 @@ type RigConfig struct {
 	DefaultBranch string       `json:"default_branch,omitempty"` // main, master, etc.
 	CreatedAt     time.Time    `json:"created_at"`               // when rig was created
 	Beads         *BeadsConfig `json:"beads,omitempty"`
+
+	// Paths scopes the rig to directories of a monorepo: worktrees check
+	// out only these (see ScopePaths) and changes are routed by them (see
+	// RouteFiles). Empty means the whole repository.
+	Paths []string `json:"paths,omitempty"`
+
+	// Clone trims the rig's clones of a huge repository.
+	Clone *CloneConfig `json:"clone,omitempty"`
+}
+
+// CloneConfig trims a rig's clones: the shared repo, the mayor clone, and
+// crew clones. Polecat worktrees share the trimmed repo.
+type CloneConfig struct {
+	// Filter is a partial clone filter such as "blob:none" (file contents
+	// are fetched when a checkout first needs them) or "tree:0".
+	Filter string `json:"filter,omitempty"`
+
+	// Depth makes clones shallow, with this many commits of history. Use
+	// 'gt rig deepen' to fetch more. Zero means full history.
+	Depth int `json:"depth,omitempty"`
 }
 
 // BeadsConfig represents beads configuration for the rig.
@@ -156,11 +176,13 @@ func (m *Manager) loadRig(name string, entry config.RigEntry) (*Rig, error) {
 
 // AddRigOptions configures rig creation.
 type AddRigOptions struct {
-	Name          string // Rig name (directory name)
-	GitURL        string // Repository URL
-	BeadsPrefix   string // Beads issue prefix (defaults to derived from name)
-	LocalRepo     string // Optional local repo for reference clones
-	DefaultBranch string // Default branch (defaults to auto-detected from remote)
+	Name          string       // Rig name (directory name)
+	GitURL        string       // Repository URL
+	BeadsPrefix   string       // Beads issue prefix (defaults to derived from name)
+	LocalRepo     string       // Optional local repo for reference clones
+	DefaultBranch string       // Default branch (defaults to auto-detected from remote)
+	Paths         []string     // Monorepo directories the rig is scoped to (default: whole repo)
+	Clone         *CloneConfig // Partial or shallow clones (default: full clones)
 }
 
 func resolveLocalRepo(path, gitURL string) (string, string) {
@@ -218,6 +240,11 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		return nil, fmt.Errorf("rig name %q contains invalid characters; hyphens, dots, and spaces are reserved for agent ID parsing. Try %q instead (underscores are allowed)", opts.Name, sanitized)
 	}
 
+	paths, err := NormalizePaths(opts.Paths)
+	if err != nil {
+		return nil, err
+	}
+
 	rigPath := filepath.Join(m.townRoot, opts.Name)
 
 	// Check if directory already exists
@@ -260,6 +287,8 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		Beads: &BeadsConfig{
 			Prefix: opts.BeadsPrefix,
 		},
+		Paths: paths,
+		Clone: opts.Clone,
 	}
 	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
 		return nil, fmt.Errorf("saving rig config: %w", err)
@@ -270,16 +299,19 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	// Mayor remains a separate clone (doesn't need branch visibility).
 	fmt.Printf("  Cloning repository (this may take a moment)...\n")
 	bareRepoPath := filepath.Join(rigPath, ".repo.git")
+	cloneOpts := opts.Clone.options()
 	if localRepo != "" {
-		if err := m.git.CloneBareWithReference(opts.GitURL, bareRepoPath, localRepo); err != nil {
+		withRef := cloneOpts
+		withRef.Reference = localRepo
+		if err := m.git.CloneBareWithOptions(opts.GitURL, bareRepoPath, withRef); err != nil {
 			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
 			_ = os.RemoveAll(bareRepoPath)
-			if err := m.git.CloneBare(opts.GitURL, bareRepoPath); err != nil {
+			if err := m.git.CloneBareWithOptions(opts.GitURL, bareRepoPath, cloneOpts); err != nil {
 				return nil, fmt.Errorf("creating bare repo: %w", err)
 			}
 		}
 	} else {
-		if err := m.git.CloneBare(opts.GitURL, bareRepoPath); err != nil {
+		if err := m.git.CloneBareWithOptions(opts.GitURL, bareRepoPath, cloneOpts); err != nil {
 			return nil, fmt.Errorf("creating bare repo: %w", err)
 		}
 	}
@@ -312,15 +344,17 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		return nil, fmt.Errorf("creating mayor dir: %w", err)
 	}
 	if localRepo != "" {
-		if err := m.git.CloneWithReference(opts.GitURL, mayorRigPath, localRepo); err != nil {
+		withRef := cloneOpts
+		withRef.Reference = localRepo
+		if err := m.git.CloneWithOptions(opts.GitURL, mayorRigPath, withRef); err != nil {
 			fmt.Printf("  Warning: could not use local repo reference: %v\n", err)
 			_ = os.RemoveAll(mayorRigPath)
-			if err := m.git.Clone(opts.GitURL, mayorRigPath); err != nil {
+			if err := m.git.CloneWithOptions(opts.GitURL, mayorRigPath, cloneOpts); err != nil {
 				return nil, fmt.Errorf("cloning for mayor: %w", err)
 			}
 		}
 	} else {
-		if err := m.git.Clone(opts.GitURL, mayorRigPath); err != nil {
+		if err := m.git.CloneWithOptions(opts.GitURL, mayorRigPath, cloneOpts); err != nil {
 			return nil, fmt.Errorf("cloning for mayor: %w", err)
 		}
 	}
@@ -330,6 +364,11 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	if err := mayorGit.Checkout(defaultBranch); err != nil {
 		return nil, fmt.Errorf("checking out default branch for mayor: %w", err)
 	}
+	if len(paths) > 0 {
+		if err := mayorGit.SparseCheckoutSet(paths); err != nil {
+			return nil, fmt.Errorf("scoping mayor clone: %w", err)
+		}
+	}
 	fmt.Printf("   ✓ Created mayor clone\n")
 
 	// Check if source repo has .beads/ with its own prefix - if so, use that prefix.
@@ -376,6 +415,11 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	if err := bareGit.WorktreeAddExisting(refineryRigPath, defaultBranch); err != nil {
 		return nil, fmt.Errorf("creating refinery worktree: %w", err)
 	}
+	if len(paths) > 0 {
+		if err := git.NewGit(refineryRigPath).SparseCheckoutSet(paths); err != nil {
+			return nil, fmt.Errorf("scoping refinery worktree: %w", err)
+		}
+	}
 	fmt.Printf("   ✓ Created refinery worktree\n")
 	// Create refinery CLAUDE.md (overrides any from cloned repo)
 	if err := m.createRoleCLAUDEmd(refineryRigPath, "refinery", opts.Name, ""); err != nil {
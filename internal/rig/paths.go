@@ -0,0 +1,93 @@
+package rig
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// NormalizePaths cleans a rig's monorepo paths into slash-separated
+// directories relative to the repository root, without duplicates.
+func NormalizePaths(paths []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var out []string
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		clean := path.Clean(strings.ReplaceAll(p, "\\", "/"))
+		if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") || strings.HasPrefix(clean, "-") {
+			return nil, fmt.Errorf("invalid rig path %q: use a directory relative to the repository root", p)
+		}
+		if clean == "." {
+			return nil, fmt.Errorf("invalid rig path %q: omit paths to cover the whole repository", p)
+		}
+		if !seen[clean] {
+			seen[clean] = true
+			out = append(out, clean)
+		}
+	}
+	return out, nil
+}
+
+// OwnsPath reports whether file, relative to the repository root, is in one
+// of the scope directories. An empty scope owns every file.
+func OwnsPath(scope []string, file string) bool {
+	if len(scope) == 0 {
+		return true
+	}
+	return matchLen(scope, file) > 0
+}
+
+// matchLen returns the length of the longest scope directory containing
+// file, or 0 if none does.
+func matchLen(scope []string, file string) int {
+	best := 0
+	for _, dir := range scope {
+		if (file == dir || strings.HasPrefix(file, dir+"/")) && len(dir) > best {
+			best = len(dir)
+		}
+	}
+	return best
+}
+
+// RouteFiles assigns changed files to the rigs that own them. scopes maps
+// rig names to their paths, for rigs over the same repository. A file goes
+// to the rig with the most specific matching path; rigs without paths
+// only take files no scoped rig owns. Files owned by no rig are returned
+// as unrouted.
+func RouteFiles(scopes map[string][]string, files []string) (routed map[string][]string, unrouted []string) {
+	names := make([]string, 0, len(scopes))
+	for name := range scopes {
+		names = append(names, name)
+	}
+	sort.Strings(names) // ties go to the first rig by name
+
+	routed = make(map[string][]string)
+	for _, file := range files {
+		owner, best := "", 0
+		fallback := ""
+		for _, name := range names {
+			if len(scopes[name]) == 0 {
+				if fallback == "" {
+					fallback = name
+				}
+				continue
+			}
+			if n := matchLen(scopes[name], file); n > best {
+				owner, best = name, n
+			}
+		}
+		if owner == "" {
+			owner = fallback
+		}
+		if owner == "" {
+			unrouted = append(unrouted, file)
+			continue
+		}
+		routed[owner] = append(routed[owner], file)
+	}
+	return routed, unrouted
+}
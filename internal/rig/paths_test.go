@@ -0,0 +1,70 @@
+package rig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizePaths(t *testing.T) {
+	got, err := NormalizePaths([]string{"services/api/", "./libs//common", " ", "services/api"})
+	if err != nil {
+		t.Fatalf("NormalizePaths: %v", err)
+	}
+	if want := []string{"services/api", "libs/common"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizePaths = %v, want %v", got, want)
+	}
+	for _, bad := range []string{"/abs", "../outside", ".", "-x"} {
+		if _, err := NormalizePaths([]string{bad}); err == nil {
+			t.Errorf("NormalizePaths(%q) should fail", bad)
+		}
+	}
+}
+
+func TestOwnsPath(t *testing.T) {
+	scope := []string{"services/api", "libs/common"}
+	for file, want := range map[string]bool{
+		"services/api/main.go":  true,
+		"libs/common":           true,
+		"services/apiv2/x.go":   false,
+		"services/web/index.js": false,
+		"README.md":             false,
+	} {
+		if got := OwnsPath(scope, file); got != want {
+			t.Errorf("OwnsPath(%q) = %v, want %v", file, got, want)
+		}
+	}
+	if !OwnsPath(nil, "anything") {
+		t.Error("a rig without paths owns the whole repository")
+	}
+}
+
+func TestRouteFiles(t *testing.T) {
+	scopes := map[string][]string{
+		"api":    {"services/api"},
+		"apiv1":  {"services/api/v1"},
+		"web":    {"services/web"},
+		"shared": {"libs"},
+	}
+	files := []string{"services/api/main.go", "services/api/v1/old.go", "libs/x.go", "services/web/a.js", "tools/gen.sh"}
+
+	routed, unrouted := RouteFiles(scopes, files)
+	want := map[string][]string{
+		"api":    {"services/api/main.go"},
+		"apiv1":  {"services/api/v1/old.go"},
+		"shared": {"libs/x.go"},
+		"web":    {"services/web/a.js"},
+	}
+	if !reflect.DeepEqual(routed, want) {
+		t.Errorf("routed = %v, want %v", routed, want)
+	}
+	if !reflect.DeepEqual(unrouted, []string{"tools/gen.sh"}) {
+		t.Errorf("unrouted = %v", unrouted)
+	}
+
+	// A whole-repo rig takes what no scoped rig owns
+	scopes["mono"] = nil
+	routed, unrouted = RouteFiles(scopes, files)
+	if len(unrouted) != 0 || !reflect.DeepEqual(routed["mono"], []string{"tools/gen.sh"}) {
+		t.Errorf("with whole-repo rig: routed = %v, unrouted = %v", routed, unrouted)
+	}
+}
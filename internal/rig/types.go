@@ -3,6 +3,7 @@ package rig
 
 import (
 	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/git"
 )
 
 // Rig represents a managed repository in the workspace.
@@ -89,3 +90,32 @@ func (r *Rig) DefaultBranch() string {
 	}
 	return cfg.DefaultBranch
 }
+
+// ScopePaths returns the monorepo directories the rig is scoped to, or nil
+// if the rig covers the whole repository.
+func (r *Rig) ScopePaths() []string {
+	cfg, err := LoadRigConfig(r.Path)
+	if err != nil {
+		return nil
+	}
+	return cfg.Paths
+}
+
+// CloneOptions returns how the rig's clones are trimmed, for cloning the
+// rig's repository again (e.g. for a new crew worker).
+func (r *Rig) CloneOptions() git.CloneOptions {
+	cfg, err := LoadRigConfig(r.Path)
+	if err != nil {
+		return git.CloneOptions{}
+	}
+	return cfg.Clone.options()
+}
+
+// options converts clone settings to git clone options; nil means a full
+// clone.
+func (c *CloneConfig) options() git.CloneOptions {
+	if c == nil {
+		return git.CloneOptions{}
+	}
+	return git.CloneOptions{Filter: c.Filter, Depth: c.Depth}
+}
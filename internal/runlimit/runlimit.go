@@ -0,0 +1,232 @@
+// Package runlimit keeps the maximum runtimes of agents: 'gt spawn
+// <agent> --max-runtime 4h' records a limit, and once the agent's session
+// has run that long the daemon stops it, so an agent forgotten about does
+// not go on using tokens overnight.
+//
+// By default an agent past its limit is stopped gracefully with 'gt kill
+// --graceful': nudged to commit its work and hand off (gt done for
+// polecats) before its session is stopped. A hard limit kills the session
+// at once. Either way the kill is logged with the limit as its reason, and
+// a "max_runtime" event records the limit being reached.
+//
+// Limits are kept in the town's .runtime/runtime-limits.json. A limit is
+// dropped once it has been enforced, when its agent is spawned again
+// without one, or when its session is found not to be running.
+package runlimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// EventMaxRuntime is the town log event recorded when an agent reaches its
+// maximum runtime and is stopped, with error severity when stopping it
+// failed.
+const EventMaxRuntime townlog.EventType = "max_runtime"
+
+// Limit is the maximum runtime of one agent's session.
+type Limit struct {
+	Agent      string        `json:"agent"`   // e.g. "gastown/crew/max"
+	Session    string        `json:"session"` // e.g. "gt-gastown-crew-max"
+	StartedAt  time.Time     `json:"started_at"`
+	MaxRuntime time.Duration `json:"max_runtime"`
+	Hard       bool          `json:"hard,omitempty"` // kill at once instead of handing off
+}
+
+// Deadline returns when the agent's session reaches its limit.
+func (l Limit) Deadline() time.Time {
+	return l.StartedAt.Add(l.MaxRuntime)
+}
+
+// Reason returns why an agent stopped at its limit is stopped, e.g. "max
+// runtime 4h reached".
+func (l Limit) Reason() string {
+	return "max runtime " + FormatDuration(l.MaxRuntime) + " reached"
+}
+
+// Args returns the gt arguments that stop the agent at its limit.
+func (l Limit) Args() []string {
+	args := []string{"kill", l.Agent, "--reason", l.Reason()}
+	if !l.Hard {
+		args = append(args, "--graceful")
+	}
+	return args
+}
+
+// FormatDuration formats a runtime limit tersely: "4h", "1h30m", "45m".
+func FormatDuration(d time.Duration) string {
+	s := d.Round(time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// Path returns where a town's limits are kept.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "runtime-limits.json")
+}
+
+// Load returns the town's limits, soonest deadline first.
+func Load(townRoot string) ([]Limit, error) {
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var list []Limit
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", Path(townRoot), err)
+	}
+	sort.SliceStable(list, func(i, k int) bool { return list[i].Deadline().Before(list[k].Deadline()) })
+	return list, nil
+}
+
+func save(townRoot string, list []Limit) error {
+	if err := os.MkdirAll(filepath.Dir(Path(townRoot)), 0755); err != nil {
+		return err
+	}
+	if list == nil {
+		list = []Limit{}
+	}
+	return util.AtomicWriteJSON(Path(townRoot), list)
+}
+
+// Set records l, replacing any limit its agent already had.
+func Set(townRoot string, l Limit) error {
+	id, err := session.ParseAddress(strings.TrimSuffix(l.Agent, "/"))
+	if err != nil {
+		return err
+	}
+	if l.MaxRuntime <= 0 {
+		return fmt.Errorf("a maximum runtime must be positive")
+	}
+	l.Agent = id.Address()
+	if l.Session == "" {
+		l.Session = id.SessionName()
+	}
+	list, err := Load(townRoot)
+	if err != nil {
+		return err
+	}
+	return save(townRoot, append(without(list, l.Agent), l))
+}
+
+// Clear drops an agent's limit, reporting whether it had one.
+func Clear(townRoot, agent string) (bool, error) {
+	list, err := Load(townRoot)
+	if err != nil {
+		return false, err
+	}
+	rest := without(list, agent)
+	if len(rest) == len(list) {
+		return false, nil
+	}
+	return true, save(townRoot, rest)
+}
+
+// For returns an agent's limit, if it has one.
+func For(list []Limit, agent string) (Limit, bool) {
+	for _, l := range list {
+		if townlog.AgentKey(l.Agent) == townlog.AgentKey(agent) {
+			return l, true
+		}
+	}
+	return Limit{}, false
+}
+
+func without(list []Limit, agent string) []Limit {
+	var rest []Limit
+	for _, l := range list {
+		if townlog.AgentKey(l.Agent) != townlog.AgentKey(agent) {
+			rest = append(rest, l)
+		}
+	}
+	return rest
+}
+
+// Expired returns the limits in list reached by now.
+func Expired(list []Limit, now time.Time) []Limit {
+	var out []Limit
+	for _, l := range list {
+		if !now.Before(l.Deadline()) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// Due drops the limits of agents whose sessions are no longer running and
+// those reached by now, and returns the reached ones, to be enforced.
+func Due(t *tmux.Tmux, townRoot string, now time.Time) ([]Limit, error) {
+	list, err := Load(townRoot)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	var due, rest []Limit
+	for _, l := range list {
+		if running, err := t.HasSession(l.Session); err == nil && !running {
+			continue
+		}
+		if !now.Before(l.Deadline()) {
+			due = append(due, l)
+		} else {
+			rest = append(rest, l)
+		}
+	}
+	if len(rest) == len(list) {
+		return nil, nil
+	}
+	return due, save(townRoot, rest)
+}
+
+// Run stops the agent at its limit with 'gt kill', from townRoot.
+func Run(townRoot string, l Limit) error {
+	cmd := exec.Command("gt", l.Args()...) //nolint:gosec // G204: args come from the town's limits
+	cmd.Dir = townRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if i := strings.LastIndex(msg, "\n"); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return fmt.Errorf("gt kill: %v: %s", err, msg)
+	}
+	return nil
+}
+
+// Log records an agent reaching its limit in the town log; err is the
+// failure to stop it, if any.
+func Log(townRoot string, l Limit, err error) {
+	how := "stopped gracefully, after a chance to hand off"
+	if l.Hard {
+		how = "killed"
+	}
+	e := townlog.Event{
+		Timestamp: time.Now(),
+		Type:      EventMaxRuntime,
+		Agent:     l.Agent,
+		Context:   fmt.Sprintf("%s: %s", l.Reason(), how),
+		Severity:  townlog.SeverityWarn,
+	}
+	if err != nil {
+		e.Context = fmt.Sprintf("%s: stopping failed: %v", l.Reason(), err)
+		e.Severity = townlog.SeverityError
+	}
+	_ = townlog.NewLogger(townRoot).LogEvent(e)
+}
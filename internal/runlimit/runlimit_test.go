@@ -0,0 +1,67 @@
+package runlimit
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLimits(t *testing.T) {
+	town := t.TempDir()
+	start := time.Date(2026, 10, 14, 20, 0, 0, 0, time.Local)
+	if err := Set(town, Limit{Agent: "gastown/crew/max/", StartedAt: start, MaxRuntime: 4 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(town, Limit{Agent: "gastown/Toast", StartedAt: start, MaxRuntime: time.Hour, Hard: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(town, Limit{Agent: "gastown/crew/ada", StartedAt: start}); err == nil {
+		t.Error("Set accepted a limit of no runtime")
+	}
+
+	list, err := Load(town)
+	if err != nil || len(list) != 2 {
+		t.Fatalf("Load = %+v, %v", list, err)
+	}
+	if list[0].Agent != "gastown/polecats/Toast" || list[1].Session != "gt-gastown-crew-max" {
+		t.Errorf("Load = %+v; want Toast's sooner limit first", list)
+	}
+	if got := Expired(list, start.Add(2*time.Hour)); len(got) != 1 || !got[0].Hard {
+		t.Errorf("Expired at +2h = %+v", got)
+	}
+	if got := Expired(list, start.Add(4*time.Hour)); len(got) != 2 {
+		t.Errorf("Expired at +4h = %+v", got)
+	}
+
+	// Spawning again replaces the agent's limit
+	if err := Set(town, Limit{Agent: "gastown/crew/max", StartedAt: start.Add(time.Hour), MaxRuntime: 30 * time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+	list, _ = Load(town)
+	if l, ok := For(list, "gastown/crew/max"); !ok || l.MaxRuntime != 30*time.Minute || len(list) != 2 {
+		t.Errorf("after replacing: %+v", list)
+	}
+
+	if ok, err := Clear(town, "gastown/polecats/Toast"); !ok || err != nil {
+		t.Errorf("Clear = %v, %v", ok, err)
+	}
+	if ok, _ := Clear(town, "gastown/polecats/Toast"); ok {
+		t.Error("Clear found a limit already cleared")
+	}
+}
+
+func TestArgs(t *testing.T) {
+	soft := Limit{Agent: "gastown/crew/max", MaxRuntime: 4 * time.Hour}
+	if want := []string{"kill", "gastown/crew/max", "--reason", "max runtime 4h reached", "--graceful"}; !slices.Equal(soft.Args(), want) {
+		t.Errorf("Args = %q, want %q", soft.Args(), want)
+	}
+	hard := Limit{Agent: "gastown/crew/max", MaxRuntime: 90 * time.Minute, Hard: true}
+	if want := []string{"kill", "gastown/crew/max", "--reason", "max runtime 1h30m reached"}; !slices.Equal(hard.Args(), want) {
+		t.Errorf("hard Args = %q, want %q", hard.Args(), want)
+	}
+	for d, want := range map[time.Duration]string{45 * time.Minute: "45m", 90 * time.Second: "1m30s", 2 * time.Hour: "2h"} {
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+// Package schedule runs nudges and wakes on cron schedules: the town's
+// cron. Jobs are kept in the town's settings/schedule.json and run by the
+// daemon, which checks for due jobs every minute; each run is recorded in
+// the town log as a "schedule" event.
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/idle"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// EventSchedule is the town log event recorded for each scheduled run,
+// with error severity when the run failed.
+const EventSchedule townlog.EventType = "schedule"
+
+// MissedGrace is how late a run may start, as when the daemon was down at
+// its time, before it is skipped rather than run. A 09:00 standup nudge
+// should not arrive at lunch.
+const MissedGrace = 10 * time.Minute
+
+// Actions a job can run.
+const (
+	// ActionNudge nudges the agent with the job's message, as 'gt nudge'.
+	ActionNudge = "nudge"
+	// ActionWake starts the agent's session if it is not running, and
+	// nudges it with the message, if there is one, once it is up.
+	ActionWake = "wake"
+)
+
+// Job is one scheduled action.
+type Job struct {
+	ID        string    `json:"id"`     // e.g. "sched-3"
+	Cron      string    `json:"cron"`   // e.g. "0 9 * * 1-5"
+	Action    string    `json:"action"` // ActionNudge or ActionWake
+	Agent     string    `json:"agent"`  // e.g. "gastown/crew/max"
+	Message   string    `json:"message,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+}
+
+// Validate checks the job's schedule, action, and agent.
+func (j Job) Validate() error {
+	if _, err := ParseCron(j.Cron); err != nil {
+		return err
+	}
+	switch j.Action {
+	case ActionNudge:
+		if strings.TrimSpace(j.Message) == "" {
+			return fmt.Errorf("a scheduled nudge needs a message")
+		}
+	case ActionWake:
+	default:
+		return fmt.Errorf("unknown action %q: must be %s or %s", j.Action, ActionNudge, ActionWake)
+	}
+	if _, err := session.ParseAddress(j.Agent); err != nil {
+		return err
+	}
+	return nil
+}
+
+// String describes what the job does, e.g. `nudge gastown/crew/max "post standup"`.
+func (j Job) String() string {
+	s := j.Action + " " + j.Agent
+	if j.Message != "" {
+		s += fmt.Sprintf(" %q", j.Message)
+	}
+	return s
+}
+
+// Next returns when the job next runs after t, or the zero time if never.
+func (j Job) Next(t time.Time) time.Time {
+	c, err := ParseCron(j.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	return c.Next(t)
+}
+
+// Path returns where a town's scheduled jobs are kept.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "schedule.json")
+}
+
+// RunsPath returns where the last run of each job is recorded.
+func RunsPath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "schedule-runs.json")
+}
+
+type jobsFile struct {
+	Jobs []Job `json:"jobs"`
+}
+
+// Load returns the town's jobs, ordered by ID.
+func Load(townRoot string) ([]Job, error) {
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var f jobsFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", Path(townRoot), err)
+	}
+	sort.SliceStable(f.Jobs, func(i, k int) bool { return idNumber(f.Jobs[i].ID) < idNumber(f.Jobs[k].ID) })
+	return f.Jobs, nil
+}
+
+func save(townRoot string, jobs []Job) error {
+	if err := os.MkdirAll(filepath.Dir(Path(townRoot)), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(Path(townRoot), jobsFile{Jobs: jobs})
+}
+
+// Add validates j, gives it the next free ID, and saves it.
+func Add(townRoot string, j Job) (Job, error) {
+	if err := j.Validate(); err != nil {
+		return Job{}, err
+	}
+	jobs, err := Load(townRoot)
+	if err != nil {
+		return Job{}, err
+	}
+	max := 0
+	for _, other := range jobs {
+		if n := idNumber(other.ID); n > max {
+			max = n
+		}
+	}
+	j.ID = fmt.Sprintf("sched-%d", max+1)
+	if err := save(townRoot, append(jobs, j)); err != nil {
+		return Job{}, err
+	}
+	return j, nil
+}
+
+// idNumber returns the number of a job ID, or 0 if it has none.
+func idNumber(id string) int {
+	var n int
+	_, _ = fmt.Sscanf(id, "sched-%d", &n)
+	return n
+}
+
+// Remove deletes the job with the given ID and returns it.
+func Remove(townRoot string, id string) (Job, error) {
+	jobs, err := Load(townRoot)
+	if err != nil {
+		return Job{}, err
+	}
+	for i, j := range jobs {
+		if j.ID == id {
+			if err := save(townRoot, append(jobs[:i:i], jobs[i+1:]...)); err != nil {
+				return Job{}, err
+			}
+			runs, _ := LoadRuns(townRoot)
+			delete(runs, id)
+			_ = saveRuns(townRoot, runs)
+			return j, nil
+		}
+	}
+	return Job{}, fmt.Errorf("no scheduled job %s", id)
+}
+
+// Find returns the job with the given ID.
+func Find(jobs []Job, id string) (Job, bool) {
+	for _, j := range jobs {
+		if j.ID == id {
+			return j, true
+		}
+	}
+	return Job{}, false
+}
+
+// LoadRuns returns when each job last ran, by ID.
+func LoadRuns(townRoot string) (map[string]time.Time, error) {
+	runs := map[string]time.Time{}
+	data, err := os.ReadFile(RunsPath(townRoot)) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		if os.IsNotExist(err) {
+			return runs, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", RunsPath(townRoot), err)
+	}
+	return runs, nil
+}
+
+func saveRuns(townRoot string, runs map[string]time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(RunsPath(townRoot)), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(RunsPath(townRoot), runs)
+}
+
+// Due returns the latest run of j due by now since its last run at last
+// (or, if it never ran, since it was created), if there is one. Runs
+// missed in between are folded into it: a job runs at most once per check.
+func Due(j Job, last, now time.Time) (time.Time, bool) {
+	c, err := ParseCron(j.Cron)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if last.IsZero() {
+		last = j.CreatedAt
+	}
+	at := c.Next(last)
+	if at.IsZero() || at.After(now) {
+		return time.Time{}, false
+	}
+	for {
+		next := c.Next(at)
+		if next.IsZero() || next.After(now) {
+			return at, true
+		}
+		at = next
+	}
+}
+
+// Runner performs a job's action.
+type Runner func(j Job) error
+
+// RunDue runs every job due by now, records its run, and logs it to the
+// town log. A run more than MissedGrace late is logged as skipped instead.
+// It returns the jobs run.
+func RunDue(townRoot string, now time.Time, run Runner) ([]Job, error) {
+	jobs, err := Load(townRoot)
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	runs, err := LoadRuns(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var ran []Job
+	for _, j := range jobs {
+		at, due := Due(j, runs[j.ID], now)
+		if !due {
+			continue
+		}
+		runs[j.ID] = now
+		if now.Sub(at) > MissedGrace {
+			logEvent(townRoot, j, "skipped: missed its "+at.Format("2006-01-02 15:04")+" run", townlog.SeverityWarn, now)
+			continue
+		}
+		Log(townRoot, j, run(j), now)
+		ran = append(ran, j)
+	}
+	return ran, saveRuns(townRoot, runs)
+}
+
+// MarkRun records that j ran at t, as when run by hand.
+func MarkRun(townRoot string, j Job, t time.Time) error {
+	runs, err := LoadRuns(townRoot)
+	if err != nil {
+		return err
+	}
+	runs[j.ID] = t
+	return saveRuns(townRoot, runs)
+}
+
+// Log records a run of j in the town log; err is the run's failure, if
+// any.
+func Log(townRoot string, j Job, err error, now time.Time) {
+	if err != nil {
+		logEvent(townRoot, j, err.Error(), townlog.SeverityError, now)
+		return
+	}
+	logEvent(townRoot, j, "", "", now)
+}
+
+func logEvent(townRoot string, j Job, note string, severity townlog.Severity, now time.Time) {
+	e := townlog.Event{
+		Timestamp: now,
+		Type:      EventSchedule,
+		Agent:     j.Agent,
+		Context:   fmt.Sprintf("%s (%s): %s", j.ID, j.Cron, j),
+		Severity:  severity,
+	}
+	if note != "" {
+		e.Context += ": " + note
+	}
+	_ = townlog.NewLogger(townRoot).LogEvent(e)
+}
+
+// Run performs a job's action from townRoot. Nudges go through 'gt nudge',
+// so DND, priorities, and the runtime's capabilities apply as usual.
+func Run(townRoot string, t *tmux.Tmux, j Job) error {
+	id, err := session.ParseAddress(j.Agent)
+	if err != nil {
+		return err
+	}
+	if j.Action == ActionWake {
+		if _, err := session.Wake(t, id.SessionName()); err != nil {
+			return err
+		}
+		if _, err := idle.Wake(t, townRoot, id.SessionName(), "scheduled wake "+j.ID); err != nil {
+			return err
+		}
+		if j.Message == "" {
+			return nil
+		}
+		return gtNudge(townRoot, j.Agent, "--priority", "urgent", j.Message)
+	}
+	return gtNudge(townRoot, j.Agent, j.Message)
+}
+
+func gtNudge(townRoot, agent string, args ...string) error {
+	cmd := exec.Command("gt", append([]string{"nudge", agent}, args...)...) //nolint:gosec // G204: args come from the town's schedule
+	cmd.Dir = townRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if i := strings.LastIndex(msg, "\n"); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return fmt.Errorf("gt nudge: %v: %s", err, msg)
+	}
+	return nil
+}
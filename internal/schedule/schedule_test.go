@@ -0,0 +1,178 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+func at(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCronNext(t *testing.T) {
+	tests := []struct {
+		expr, from, want string
+	}{
+		{"0 9 * * *", "2026-10-14 08:59", "2026-10-14 09:00"},
+		{"0 9 * * *", "2026-10-14 09:00", "2026-10-15 09:00"},
+		{"*/15 * * * *", "2026-10-14 10:07", "2026-10-14 10:15"},
+		{"0 9 * * 1-5", "2026-10-16 12:00", "2026-10-19 09:00"}, // Friday → Monday
+		{"0 18 * * fri", "2026-10-14 12:00", "2026-10-16 18:00"},
+		{"30 2 1 * *", "2026-10-14 12:00", "2026-11-01 02:30"},
+		{"0 0 * * 7", "2026-10-14 12:00", "2026-10-18 00:00"}, // 7 is Sunday
+		{"0 0 13 * 5", "2026-10-14 12:00", "2026-10-16 00:00"}, // the 13th or a Friday
+		{"0 12 * jan,jul *", "2026-10-14 12:00", "2027-01-01 12:00"},
+		{"@hourly", "2026-10-14 10:07", "2026-10-14 11:00"},
+		{"@weekly", "2026-10-14 10:07", "2026-10-18 00:00"},
+		{"5/20 * * * *", "2026-10-14 10:30", "2026-10-14 10:45"},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := c.Next(at(tt.from)); !got.Equal(at(tt.want)) {
+			t.Errorf("%q.Next(%s) = %s, want %s", tt.expr, tt.from, got.Format("2006-01-02 15:04"), tt.want)
+		}
+	}
+
+	c, _ := ParseCron("0 0 30 2 *")
+	if got := c.Next(at("2026-10-14 12:00")); !got.IsZero() {
+		t.Errorf("Feb 30 Next = %s, want never", got)
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *", "@often"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) = nil error", expr)
+		}
+	}
+}
+
+func TestJobValidate(t *testing.T) {
+	valid := []Job{
+		{Cron: "0 9 * * *", Action: ActionNudge, Agent: "gastown/crew/max", Message: "post standup"},
+		{Cron: "@daily", Action: ActionWake, Agent: "gastown/witness"},
+		{Cron: "0 8 * * 1-5", Action: ActionWake, Agent: "mayor", Message: "morning"},
+	}
+	for _, j := range valid {
+		if err := j.Validate(); err != nil {
+			t.Errorf("Validate(%+v) = %v", j, err)
+		}
+	}
+	invalid := []Job{
+		{Cron: "0 9 * *", Action: ActionNudge, Agent: "mayor", Message: "x"},
+		{Cron: "0 9 * * *", Action: ActionNudge, Agent: "mayor"},
+		{Cron: "0 9 * * *", Action: "kill", Agent: "mayor"},
+		{Cron: "0 9 * * *", Action: ActionWake, Agent: "nobody"},
+	}
+	for _, j := range invalid {
+		if err := j.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", j)
+		}
+	}
+}
+
+func TestDue(t *testing.T) {
+	j := Job{Cron: "0 * * * *", CreatedAt: at("2026-10-14 08:30")}
+	if _, due := Due(j, time.Time{}, at("2026-10-14 08:59")); due {
+		t.Error("due before its first run")
+	}
+	if got, due := Due(j, time.Time{}, at("2026-10-14 09:00")); !due || !got.Equal(at("2026-10-14 09:00")) {
+		t.Errorf("Due at 09:00 = %s, %v", got, due)
+	}
+	if _, due := Due(j, at("2026-10-14 09:00"), at("2026-10-14 09:30")); due {
+		t.Error("due again within the hour")
+	}
+	// Down since 09:00: the runs missed fold into the latest.
+	if got, due := Due(j, at("2026-10-14 09:00"), at("2026-10-14 13:05")); !due || !got.Equal(at("2026-10-14 13:00")) {
+		t.Errorf("Due after downtime = %s, %v; want 13:00", got, due)
+	}
+}
+
+func TestAddRemoveRunDue(t *testing.T) {
+	townRoot := t.TempDir()
+	created := at("2026-10-14 08:00")
+	standup, err := Add(townRoot, Job{Cron: "0 9 * * *", Action: ActionNudge, Agent: "gastown/crew/max", Message: "post standup", CreatedAt: created})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wake, err := Add(townRoot, Job{Cron: "30 8 * * *", Action: ActionWake, Agent: "gastown/witness", CreatedAt: created})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if standup.ID != "sched-1" || wake.ID != "sched-2" {
+		t.Fatalf("IDs = %s, %s", standup.ID, wake.ID)
+	}
+	if _, err := Add(townRoot, Job{Cron: "bad", Action: ActionWake, Agent: "mayor"}); err == nil {
+		t.Error("Add accepted an invalid job")
+	}
+
+	var calls []string
+	run := func(j Job) error {
+		calls = append(calls, j.ID)
+		if j.Action == ActionWake {
+			return errors.New("no such rig")
+		}
+		return nil
+	}
+	// 09:00: the standup is due; the 08:30 wake was missed by too much.
+	ran, err := RunDue(townRoot, at("2026-10-14 09:00"), run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 1 || ran[0].ID != "sched-1" || len(calls) != 1 {
+		t.Fatalf("ran %v (calls %v), want sched-1 only", ran, calls)
+	}
+	if ran, _ := RunDue(townRoot, at("2026-10-14 09:01"), run); len(ran) != 0 {
+		t.Errorf("ran %v again a minute later", ran)
+	}
+	// The next morning the wake runs on time, and fails.
+	if ran, _ := RunDue(townRoot, at("2026-10-15 08:30"), run); len(ran) != 1 || ran[0].ID != "sched-2" {
+		t.Errorf("ran %v at 08:30, want sched-2", ran)
+	}
+
+	events, err := townlog.ReadEvents(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var severities []townlog.Severity
+	for _, e := range events {
+		if e.Type == EventSchedule {
+			severities = append(severities, e.Level())
+		}
+	}
+	want := []townlog.Severity{townlog.SeverityInfo, townlog.SeverityWarn, townlog.SeverityError}
+	if len(severities) != len(want) {
+		t.Fatalf("schedule event severities = %v, want %v", severities, want)
+	}
+	for i := range want {
+		if severities[i] != want[i] {
+			t.Errorf("schedule event severities = %v, want %v", severities, want)
+			break
+		}
+	}
+
+	if _, err := Remove(townRoot, "sched-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Remove(townRoot, "sched-1"); err == nil {
+		t.Error("removed sched-1 twice")
+	}
+	jobs, _ := Load(townRoot)
+	if len(jobs) != 1 || jobs[0].ID != "sched-2" {
+		t.Errorf("jobs after remove = %v", jobs)
+	}
+	if j, _ := Add(townRoot, Job{Cron: "@daily", Action: ActionWake, Agent: "mayor"}); j.ID != "sched-3" {
+		t.Errorf("next ID = %s, want sched-3", j.ID)
+	}
+}
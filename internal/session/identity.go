@@ -90,6 +90,32 @@ func ParseSessionName(session string) (*AgentIdentity, error) {
 	return &AgentIdentity{Role: RolePolecat, Rig: rig, Name: name}, nil
 }
 
+// ParseAddress parses a mail-style agent address (see Address) into an
+// AgentIdentity. The short polecat form "<rig>/<name>" is accepted too.
+func ParseAddress(address string) (*AgentIdentity, error) {
+	parts := strings.Split(address, "/")
+	if len(parts) == 2 && parts[1] == "" {
+		parts = parts[:1] // "mayor/"
+	}
+	switch {
+	case len(parts) == 1 && parts[0] == "mayor":
+		return &AgentIdentity{Role: RoleMayor}, nil
+	case len(parts) == 1 && parts[0] == "deacon":
+		return &AgentIdentity{Role: RoleDeacon}, nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] == "witness":
+		return &AgentIdentity{Role: RoleWitness, Rig: parts[0]}, nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] == "refinery":
+		return &AgentIdentity{Role: RoleRefinery, Rig: parts[0]}, nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "" && parts[1] != "crew" && parts[1] != "polecats":
+		return &AgentIdentity{Role: RolePolecat, Rig: parts[0], Name: parts[1]}, nil
+	case len(parts) == 3 && parts[0] != "" && parts[1] == "crew" && parts[2] != "":
+		return &AgentIdentity{Role: RoleCrew, Rig: parts[0], Name: parts[2]}, nil
+	case len(parts) == 3 && parts[0] != "" && parts[1] == "polecats" && parts[2] != "":
+		return &AgentIdentity{Role: RolePolecat, Rig: parts[0], Name: parts[2]}, nil
+	}
+	return nil, fmt.Errorf("invalid agent address %q: use e.g. gastown/crew/max, gastown/polecats/Toast, gastown/witness, or mayor", address)
+}
+
 // SessionName returns the tmux session name for this identity.
 func (a *AgentIdentity) SessionName() string {
 	switch a.Role {
@@ -137,6 +163,26 @@ func (a *AgentIdentity) Address() string {
 	}
 }
 
+// StartCommand returns the gt arguments that start this identity's session.
+func (a *AgentIdentity) StartCommand() []string {
+	switch a.Role {
+	case RoleMayor:
+		return []string{"mayor", "start"}
+	case RoleDeacon:
+		return []string{"deacon", "start"}
+	case RoleWitness:
+		return []string{"witness", "start", a.Rig}
+	case RoleRefinery:
+		return []string{"refinery", "start", a.Rig}
+	case RoleCrew:
+		return []string{"crew", "start", a.Rig, a.Name}
+	case RolePolecat:
+		return []string{"session", "start", a.Rig + "/" + a.Name}
+	default:
+		return nil
+	}
+}
+
 // GTRole returns the GT_ROLE environment variable format.
 // This is the same as Address() for most roles.
 func (a *AgentIdentity) GTRole() string {
@@ -1,6 +1,7 @@
 package session
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -227,6 +228,25 @@ func TestAgentIdentity_Address(t *testing.T) {
 	}
 }
 
+func TestAgentIdentity_StartCommand(t *testing.T) {
+	tests := []struct {
+		identity AgentIdentity
+		want     string
+	}{
+		{AgentIdentity{Role: RoleMayor}, "mayor start"},
+		{AgentIdentity{Role: RoleWitness, Rig: "gastown"}, "witness start gastown"},
+		{AgentIdentity{Role: RoleCrew, Rig: "gastown", Name: "max"}, "crew start gastown max"},
+		{AgentIdentity{Role: RolePolecat, Rig: "gastown", Name: "Toast"}, "session start gastown/Toast"},
+		{AgentIdentity{Role: "unknown"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(tt.identity.StartCommand(), " "); got != tt.want {
+			t.Errorf("StartCommand(%+v) = %q, want %q", tt.identity, got, tt.want)
+		}
+	}
+}
+
 func TestParseSessionName_RoundTrip(t *testing.T) {
 	// Test that parsing then reconstructing gives the same result
 	sessions := []string{
@@ -250,3 +270,37 @@ func TestParseSessionName_RoundTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		session string // "" for an invalid address
+	}{
+		{"mayor", "gt-mayor"},
+		{"mayor/", "gt-mayor"},
+		{"deacon", "gt-deacon"},
+		{"gastown/witness", "gt-gastown-witness"},
+		{"gastown/refinery", "gt-gastown-refinery"},
+		{"gastown/crew/max", "gt-gastown-crew-max"},
+		{"gastown/polecats/Toast", "gt-gastown-Toast"},
+		{"gastown/Toast", "gt-gastown-Toast"},
+		{"gastown", ""},
+		{"gastown/crew/", ""},
+		{"gastown/dogs/rex", ""},
+		{"gastown/crew", ""},
+	}
+	for _, tt := range tests {
+		id, err := ParseAddress(tt.address)
+		if tt.session == "" {
+			if err == nil {
+				t.Errorf("ParseAddress(%q) = %+v, want error", tt.address, id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseAddress(%q): %v", tt.address, err)
+		} else if got := id.SessionName(); got != tt.session {
+			t.Errorf("ParseAddress(%q).SessionName() = %q, want %q", tt.address, got, tt.session)
+		}
+	}
+}
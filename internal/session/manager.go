@@ -13,6 +13,9 @@ import (
 	"github.com/ctiospl/gastown/internal/claude"
 	"github.com/ctiospl/gastown/internal/config"
 	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/drift"
+	"github.com/ctiospl/gastown/internal/lifecycle"
+	"github.com/ctiospl/gastown/internal/outputlog"
 	"github.com/ctiospl/gastown/internal/rig"
 	"github.com/ctiospl/gastown/internal/tmux"
 )
@@ -133,6 +136,13 @@ func (m *Manager) Start(polecat string, opts StartOptions) error {
 		return fmt.Errorf("ensuring Claude settings: %w", err)
 	}
 
+	// The town's and rig's pre_spawn hooks; a failing one stops the spawn
+	townRoot := filepath.Dir(m.rig.Path) // Town root is parent of rig directory
+	preSpawn := lifecycle.Meta{Event: lifecycle.PreSpawn, Agent: fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat), Session: sessionID, Time: time.Now()}
+	if err := lifecycle.Run(townRoot, preSpawn, os.Stdout); err != nil {
+		return fmt.Errorf("not starting %s: %w", sessionID, err)
+	}
+
 	// Create session
 	if err := m.tmux.NewSession(sessionID, workDir); err != nil {
 		return fmt.Errorf("creating session: %w", err)
@@ -152,12 +162,21 @@ func (m *Manager) Start(polecat string, opts StartOptions) error {
 	// Town beads use hq- prefix and store hooks, mail, and cross-rig coordination.
 	// BEADS_NO_DAEMON=1 prevents daemon from committing to wrong branch.
 	// Using town-level beads ensures gt prime and bd commands can find hooked work.
-	townRoot := filepath.Dir(m.rig.Path) // Town root is parent of rig directory
 	beadsDir := filepath.Join(townRoot, ".beads")
 	_ = m.tmux.SetEnvironment(sessionID, "BEADS_DIR", beadsDir)
 	_ = m.tmux.SetEnvironment(sessionID, "BEADS_NO_DAEMON", "1")
 	_ = m.tmux.SetEnvironment(sessionID, "BEADS_AGENT_NAME", fmt.Sprintf("%s/%s", m.rig.Name, polecat))
 
+	// Point package managers at the town's shared dependency caches (non-fatal)
+	for k, v := range config.RigDepCacheEnv(m.rig.Path) {
+		_ = m.tmux.SetEnvironment(sessionID, k, v)
+	}
+
+	// Export the rig's own environment from its settings (non-fatal)
+	for k, v := range config.RigEnv(m.rig.Path) {
+		_ = m.tmux.SetEnvironment(sessionID, k, v)
+	}
+
 	// Hook the issue to the polecat if provided via --issue flag
 	if opts.Issue != "" {
 		agentID := fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat)
@@ -175,6 +194,9 @@ func (m *Manager) Start(polecat string, opts StartOptions) error {
 	agentID := fmt.Sprintf("%s/%s", m.rig.Name, polecat)
 	_ = m.tmux.SetPaneDiedHook(sessionID, agentID)
 
+	// Capture its output to logs/output/ (non-fatal)
+	_ = outputlog.Start(m.tmux, townRoot, sessionID, agentID)
+
 	// Send initial command with env vars exported inline
 	// NOTE: tmux SetEnvironment only affects NEW panes, not the current shell.
 	// We must export GT_ROLE, GT_RIG, GT_POLECAT inline for Claude to detect identity.
@@ -264,6 +286,10 @@ func (m *Manager) Stop(polecat string, force bool) error {
 		return fmt.Errorf("killing session: %w", err)
 	}
 
+	// Snapshot the worktree so the next session learns of changes made
+	// while no agent was running (non-fatal)
+	_ = drift.Record(m.polecatDir(polecat))
+
 	return nil
 }
 
@@ -0,0 +1,197 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/constants"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// Pause records an agent whose session is frozen by 'gt pause'.
+type Pause struct {
+	Agent    string    `json:"agent"`
+	Session  string    `json:"session"`
+	PausedAt time.Time `json:"paused_at"`
+	By       string    `json:"by"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// PausesPath returns where a town's paused sessions are recorded.
+func PausesPath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "paused.json")
+}
+
+// LoadPauses returns the town's paused sessions, by session name.
+func LoadPauses(townRoot string) (map[string]Pause, error) {
+	data, err := os.ReadFile(PausesPath(townRoot)) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]Pause{}, nil
+		}
+		return nil, err
+	}
+	pauses := map[string]Pause{}
+	if err := json.Unmarshal(data, &pauses); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", PausesPath(townRoot), err)
+	}
+	return pauses, nil
+}
+
+// savePauses writes the town's paused sessions.
+func savePauses(townRoot string, pauses map[string]Pause) error {
+	if err := os.MkdirAll(filepath.Dir(PausesPath(townRoot)), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(PausesPath(townRoot), pauses)
+}
+
+// SortedPauses returns pauses ordered by agent.
+func SortedPauses(pauses map[string]Pause) []Pause {
+	list := make([]Pause, 0, len(pauses))
+	for _, p := range pauses {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Agent < list[j].Agent })
+	return list
+}
+
+// PauseSession freezes the processes running in a session with SIGSTOP,
+// so the agent stops working (and spending tokens) while its process, and
+// with it the whole conversation, stays in memory. The agent runs as a job
+// of the pane's shell, which takes the terminal back as it would after
+// Ctrl-Z; the tmux session stays usable and can be attached to and read.
+func PauseSession(t *tmux.Tmux, townRoot string, p Pause) error {
+	pauses, err := LoadPauses(townRoot)
+	if err != nil {
+		return err
+	}
+	if prev, ok := pauses[p.Session]; ok {
+		return fmt.Errorf("%s is already paused (since %s)", p.Agent, prev.PausedAt.Format("2006-01-02 15:04"))
+	}
+	pids, err := agentProcesses(t, p.Session)
+	if err != nil {
+		return err
+	}
+	if len(pids) == 0 {
+		return fmt.Errorf("no agent process is running in %s", p.Session)
+	}
+	if err := signalAll(pids, syscall.SIGSTOP); err != nil {
+		_ = signalAll(pids, syscall.SIGCONT)
+		return fmt.Errorf("pausing %s: %w", p.Agent, err)
+	}
+	pauses[p.Session] = p
+	if err := savePauses(townRoot, pauses); err != nil {
+		_ = signalAll(pids, syscall.SIGCONT)
+		return err
+	}
+	return nil
+}
+
+// ResumeSession continues the processes of a session paused by
+// PauseSession and returns its pause record. A paused session that no
+// longer exists is forgotten.
+func ResumeSession(t *tmux.Tmux, townRoot, sessionName string) (*Pause, error) {
+	pauses, err := LoadPauses(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := pauses[sessionName]
+	if !ok {
+		return nil, fmt.Errorf("%s is not paused", sessionName)
+	}
+	if exists, _ := t.HasSession(sessionName); exists {
+		if err := continueAgent(t, sessionName); err != nil {
+			return nil, fmt.Errorf("resuming %s: %w", p.Agent, err)
+		}
+	}
+	delete(pauses, sessionName)
+	if err := savePauses(townRoot, pauses); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// agentProcesses returns the processes below a session's pane shell,
+// parents before children.
+func agentProcesses(t *tmux.Tmux, sessionName string) ([]int, error) {
+	shell, err := t.GetPanePID(sessionName)
+	if err != nil {
+		return nil, err
+	}
+	out, err := exec.Command("ps", "-A", "-o", "pid=,ppid=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing processes: %w", err)
+	}
+	return descendants(parseProcessTable(string(out)), shell), nil
+}
+
+// continueAgent continues a paused session's agent. Once the shell has
+// taken the terminal back from the stopped job, only fg returns it to the
+// agent: continued in the background, the agent would stop again on its
+// first read of the terminal.
+func continueAgent(t *tmux.Tmux, sessionName string) error {
+	if cmd, _ := t.GetPaneCommand(sessionName); slices.Contains(constants.SupportedShells, cmd) {
+		return t.SendKeys(sessionName, "fg")
+	}
+	pids, err := agentProcesses(t, sessionName)
+	if err != nil {
+		return err
+	}
+	return signalAll(pids, syscall.SIGCONT)
+}
+
+// parseProcessTable parses "pid ppid" lines into children by parent.
+func parseProcessTable(out string) map[int][]int {
+	children := make(map[int][]int)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		if err1 == nil && err2 == nil {
+			children[ppid] = append(children[ppid], pid)
+		}
+	}
+	return children
+}
+
+// descendants returns the processes below root, breadth first.
+func descendants(children map[int][]int, root int) []int {
+	var pids []int
+	queue := children[root]
+	seen := map[int]bool{root: true}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		if seen[pid] {
+			continue
+		}
+		seen[pid] = true
+		pids = append(pids, pid)
+		queue = append(queue, children[pid]...)
+	}
+	return pids
+}
+
+// signalAll sends sig to each process, ignoring those that have exited.
+func signalAll(pids []int, sig syscall.Signal) error {
+	for _, pid := range pids {
+		if err := syscall.Kill(pid, sig); err != nil && err != syscall.ESRCH {
+			return fmt.Errorf("signalling %d: %w", pid, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDescendants(t *testing.T) {
+	table := parseProcessTable(`
+    1     0
+  100     1
+  101   100
+  102   101
+  103   101
+  200     1
+  garbage
+`)
+	if got, want := descendants(table, 100), []int{101, 102, 103}; !reflect.DeepEqual(got, want) {
+		t.Errorf("descendants(100) = %v, want %v", got, want)
+	}
+	if got := descendants(table, 200); len(got) != 0 {
+		t.Errorf("descendants(200) = %v, want none", got)
+	}
+}
+
+func TestLoadPauses(t *testing.T) {
+	townRoot := t.TempDir()
+	pauses, err := LoadPauses(townRoot)
+	if err != nil || len(pauses) != 0 {
+		t.Fatalf("LoadPauses on a new town = %v, %v", pauses, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	want := map[string]Pause{
+		"gt-gastown-crew-max": {Agent: "gastown/crew/max", Session: "gt-gastown-crew-max", PausedAt: now, By: "mayor"},
+		"gt-gastown-Toast":    {Agent: "gastown/polecats/Toast", Session: "gt-gastown-Toast", PausedAt: now, By: "mayor"},
+	}
+	if err := savePauses(townRoot, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadPauses(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadPauses = %v, want %v", got, want)
+	}
+	if list := SortedPauses(got); list[0].Agent != "gastown/crew/max" {
+		t.Errorf("SortedPauses first = %s", list[0].Agent)
+	}
+}
@@ -0,0 +1,202 @@
+package session
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/events"
+)
+
+// Outcome tags attached to agent sessions by the test gate, the verify
+// suite, and review steps, so reports can measure the quality of work and not only whether
+// it was completed. Any other lowercase tag is accepted too.
+const (
+	TagTestsGreen     = "tests-green"
+	TagTestsRed       = "tests-red"
+	TagVerifyGreen    = "verify-green"
+	TagVerifyRed      = "verify-red"
+	TagReviewApproved = "review-approved"
+	TagReviewRejected = "review-rejected"
+	TagRevertedLater  = "reverted-later"
+)
+
+// OutcomeTags lists the well-known tags in report order.
+var OutcomeTags = []string{TagTestsGreen, TagTestsRed, TagVerifyGreen, TagVerifyRed, TagReviewApproved, TagReviewRejected, TagRevertedLater}
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Tag is one outcome attached to an agent session.
+type Tag struct {
+	Time      time.Time `json:"time"`
+	Tag       string    `json:"tag"`
+	SessionID string    `json:"session_id,omitempty"` // empty if the session could not be found
+	Agent     string    `json:"agent"`
+	Issue     string    `json:"issue,omitempty"`
+	By        string    `json:"by"`
+	Note      string    `json:"note,omitempty"`
+}
+
+// target identifies what a tag is about, for counting each session once.
+func (t Tag) target() string {
+	if t.SessionID != "" {
+		return t.SessionID
+	}
+	return t.Agent + "\x00" + t.Issue
+}
+
+// ValidateTag checks that a tag is a lowercase, dash-separated word.
+func ValidateTag(tag string) error {
+	if !tagPattern.MatchString(tag) {
+		return fmt.Errorf("invalid tag %q: use lowercase letters, digits and dashes (e.g. %s)", tag, TagTestsGreen)
+	}
+	return nil
+}
+
+// ResolveTarget works out which session a tag belongs to. With only an
+// issue, the agent is whoever last completed (or hooked) it; the session is
+// then that agent's last session started before the work was done. The
+// session ID is empty when no session_start event is found.
+func ResolveTarget(all []events.Event, agent, issue string) (sessionID, resolvedAgent string, err error) {
+	var cutoff time.Time
+	if agent == "" {
+		if issue == "" {
+			return "", "", fmt.Errorf("need an agent or an issue to tag")
+		}
+		for _, wanted := range []string{events.TypeDone, events.TypeHook} {
+			for i := len(all) - 1; i >= 0 && agent == ""; i-- {
+				e := all[i]
+				if e.Type == wanted && payloadString(e, "bead") == issue {
+					agent, cutoff = e.Actor, e.Time()
+				}
+			}
+		}
+		if agent == "" {
+			return "", "", fmt.Errorf("no agent found that worked on %s; pass --agent", issue)
+		}
+	}
+
+	for i := len(all) - 1; i >= 0; i-- {
+		e := all[i]
+		if e.Type != events.TypeSessionStart || e.Actor != agent {
+			continue
+		}
+		if !cutoff.IsZero() && e.Time().After(cutoff) {
+			continue
+		}
+		return payloadString(e, "session_id"), agent, nil
+	}
+	return "", agent, nil
+}
+
+// Tags returns the session tags recorded in the event stream, oldest first.
+func Tags(all []events.Event) []Tag {
+	var tags []Tag
+	for _, e := range all {
+		if e.Type != events.TypeSessionTag {
+			continue
+		}
+		tags = append(tags, Tag{
+			Time:      e.Time(),
+			Tag:       payloadString(e, "tag"),
+			SessionID: payloadString(e, "session_id"),
+			Agent:     payloadString(e, "agent"),
+			Issue:     payloadString(e, "issue"),
+			By:        e.Actor,
+			Note:      payloadString(e, "note"),
+		})
+	}
+	return tags
+}
+
+// TagSummary aggregates sessions, completions and outcome tags for one
+// group of agents.
+type TagSummary struct {
+	Group    string         `json:"group"`
+	Sessions int            `json:"sessions"`
+	Done     int            `json:"done"`
+	Tags     map[string]int `json:"tags"` // distinct sessions carrying each tag
+}
+
+// Rate returns the share of completed work carrying tag, or -1 when
+// nothing was completed.
+func (s TagSummary) Rate(tag string) float64 {
+	if s.Done == 0 {
+		return -1
+	}
+	return float64(s.Tags[tag]) / float64(s.Done)
+}
+
+// Summarize groups sessions, done events and tags by group(agent), sorted
+// by group. Events before since are ignored.
+func Summarize(all []events.Event, since time.Time, group func(agent string) string) []TagSummary {
+	byGroup := make(map[string]*TagSummary)
+	get := func(agent string) *TagSummary {
+		g := group(agent)
+		s, ok := byGroup[g]
+		if !ok {
+			s = &TagSummary{Group: g, Tags: make(map[string]int)}
+			byGroup[g] = s
+		}
+		return s
+	}
+
+	seen := make(map[string]bool)
+	for _, e := range all {
+		if !since.IsZero() && e.Time().Before(since) {
+			continue
+		}
+		switch e.Type {
+		case events.TypeSessionStart:
+			get(e.Actor).Sessions++
+		case events.TypeDone:
+			get(e.Actor).Done++
+		}
+	}
+	for _, t := range Tags(all) {
+		if !since.IsZero() && t.Time.Before(since) {
+			continue
+		}
+		key := t.Tag + "\x00" + t.target()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		get(t.Agent).Tags[t.Tag]++
+	}
+
+	summaries := make([]TagSummary, 0, len(byGroup))
+	for _, s := range byGroup {
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Group < summaries[j].Group })
+	return summaries
+}
+
+// TagSession attaches tag to the session that did the work and records it
+// in the town's event stream. agent or issue (or both) must be set.
+func TagSession(townRoot, by, agent, issue, tag, note string) (Tag, error) {
+	if err := ValidateTag(tag); err != nil {
+		return Tag{}, err
+	}
+	all, err := events.Read(townRoot)
+	if err != nil {
+		return Tag{}, err
+	}
+	sessionID, agent, err := ResolveTarget(all, agent, issue)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	t := Tag{Time: time.Now(), Tag: tag, SessionID: sessionID, Agent: agent, Issue: issue, By: by, Note: note}
+	if err := events.LogAudit(events.TypeSessionTag, by, events.SessionTagPayload(sessionID, agent, issue, tag, note)); err != nil {
+		return Tag{}, fmt.Errorf("recording tag: %w", err)
+	}
+	return t, nil
+}
+
+func payloadString(e events.Event, key string) string {
+	s, _ := e.Payload[key].(string)
+	return s
+}
@@ -0,0 +1,88 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/events"
+)
+
+func tagTestEvent(at time.Time, typ, actor string, payload map[string]interface{}) events.Event {
+	return events.Event{Timestamp: at.Format(time.RFC3339), Type: typ, Actor: actor, Payload: payload}
+}
+
+func TestResolveTargetFromIssue(t *testing.T) {
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	nux := "gastown/polecats/nux"
+	all := []events.Event{
+		tagTestEvent(base, events.TypeSessionStart, nux, map[string]interface{}{"session_id": "s-1"}),
+		tagTestEvent(base.Add(time.Hour), events.TypeDone, nux, events.DonePayload("gt-1", "polecat/nux")),
+		tagTestEvent(base.Add(2*time.Hour), events.TypeSessionStart, nux, map[string]interface{}{"session_id": "s-2"}),
+	}
+
+	sessionID, agent, err := ResolveTarget(all, "", "gt-1")
+	if err != nil {
+		t.Fatalf("ResolveTarget: %v", err)
+	}
+	if agent != nux || sessionID != "s-1" {
+		t.Errorf("ResolveTarget = %q, %q; want %q, s-1 (the session that did the work)", sessionID, agent, nux)
+	}
+
+	// With an explicit agent, the latest session wins.
+	if sessionID, _, _ := ResolveTarget(all, nux, ""); sessionID != "s-2" {
+		t.Errorf("ResolveTarget by agent = %q, want s-2", sessionID)
+	}
+
+	if _, _, err := ResolveTarget(all, "", "gt-404"); err == nil {
+		t.Error("ResolveTarget for an unworked issue should fail")
+	}
+}
+
+func TestSummarizeCountsTaggedSessionsOnce(t *testing.T) {
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	nux := "gastown/polecats/nux"
+	tag := func(at time.Time, session, tag string) events.Event {
+		return tagTestEvent(at, events.TypeSessionTag, "gastown/refinery", events.SessionTagPayload(session, nux, "", tag, ""))
+	}
+	all := []events.Event{
+		tagTestEvent(base, events.TypeSessionStart, nux, map[string]interface{}{"session_id": "s-1"}),
+		tagTestEvent(base, events.TypeDone, nux, events.DonePayload("gt-1", "")),
+		tagTestEvent(base, events.TypeSessionStart, nux, map[string]interface{}{"session_id": "s-2"}),
+		tagTestEvent(base, events.TypeDone, nux, events.DonePayload("gt-2", "")),
+		tag(base, "s-1", TagTestsRed),
+		tag(base, "s-1", TagTestsGreen),
+		tag(base, "s-1", TagTestsGreen), // retried gate: counted once
+		tag(base, "s-2", TagTestsGreen),
+		tag(base, "s-2", TagRevertedLater),
+	}
+
+	rig := func(agent string) string { return strings.SplitN(agent, "/", 2)[0] }
+	got := Summarize(all, time.Time{}, rig)
+	if len(got) != 1 || got[0].Group != "gastown" {
+		t.Fatalf("Summarize = %+v, want one gastown group", got)
+	}
+	s := got[0]
+	if s.Sessions != 2 || s.Done != 2 {
+		t.Errorf("sessions/done = %d/%d, want 2/2", s.Sessions, s.Done)
+	}
+	if s.Tags[TagTestsGreen] != 2 || s.Tags[TagTestsRed] != 1 {
+		t.Errorf("tags = %v, want 2 tests-green and 1 tests-red", s.Tags)
+	}
+	if r := s.Rate(TagRevertedLater); r != 0.5 {
+		t.Errorf("reverted-later rate = %v, want 0.5", r)
+	}
+}
+
+func TestValidateTag(t *testing.T) {
+	for _, ok := range []string{"tests-green", "review-approved", "p0"} {
+		if err := ValidateTag(ok); err != nil {
+			t.Errorf("ValidateTag(%q) = %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"", "Tests", "-x", "has space"} {
+		if ValidateTag(bad) == nil {
+			t.Errorf("ValidateTag(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package session
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/ctiospl/gastown/internal/tmux"
+)
+
+// Wake starts the agent behind a tmux session if it is not running, and
+// reports whether it had to be started. A woken agent picks up waiting mail
+// through its startup hooks (gt prime).
+func Wake(t *tmux.Tmux, sessionName string) (bool, error) {
+	running, err := t.HasSession(sessionName)
+	if err != nil {
+		return false, fmt.Errorf("checking session: %w", err)
+	}
+	if running {
+		return false, nil
+	}
+
+	id, err := ParseSessionName(sessionName)
+	if err != nil {
+		return false, err
+	}
+	cmd := exec.Command("gt", id.StartCommand()...) //nolint:gosec // G204: arguments come from a parsed session name
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return false, fmt.Errorf("starting %s: %s", id.Address(), strings.TrimSpace(string(out)))
+	}
+	return true, nil
+}
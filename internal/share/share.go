@@ -0,0 +1,194 @@
+// Package share manages session sharing links: time-limited, read-only
+// tokens that let a teammate watch an agent's live session through
+// 'gt log serve' without shell access to the town.
+//
+// A town's shares are stored in <town>/.runtime/shares.json, readable only
+// by the owner since the tokens grant access. Expired shares are dropped
+// whenever the file is next updated.
+package share
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// CurrentVersion is the current schema version of the shares file.
+const CurrentVersion = 1
+
+// tokenBytes is the amount of randomness in a token.
+const tokenBytes = 16
+
+var (
+	// ErrNotFound means no share has the token (or it was revoked).
+	ErrNotFound = errors.New("share not found")
+
+	// ErrExpired means the share's time limit has passed.
+	ErrExpired = errors.New("share expired")
+)
+
+// Share is one sharing link.
+type Share struct {
+	Token     string    `json:"token"`
+	Agent     string    `json:"agent"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Expired reports whether the share is no longer valid at now.
+func (s Share) Expired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// Shares is a town's set of sharing links.
+type Shares struct {
+	Version int     `json:"version"`
+	Shares  []Share `json:"shares"`
+}
+
+// Path returns where a town's shares are stored.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "shares.json")
+}
+
+// Load reads a town's shares, expired ones included.
+func Load(townRoot string) (*Shares, error) {
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Shares{Version: CurrentVersion}, nil
+		}
+		return nil, fmt.Errorf("reading shares: %w", err)
+	}
+	var s Shares
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing shares: %w", err)
+	}
+	if s.Version > CurrentVersion {
+		return nil, fmt.Errorf("shares version %d is newer than this gt supports", s.Version)
+	}
+	return &s, nil
+}
+
+// update loads the shares, drops expired ones, applies fn, and saves the
+// result under a lock. Nothing is saved if fn fails.
+func update(townRoot string, fn func(*Shares) error) error {
+	path := Path(townRoot)
+	return util.WithFileLock(path+".lock", func() error {
+		s, err := Load(townRoot)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		kept := s.Shares[:0]
+		for _, sh := range s.Shares {
+			if !sh.Expired(now) {
+				kept = append(kept, sh)
+			}
+		}
+		s.Shares = kept
+		if err := fn(s); err != nil {
+			return err
+		}
+		s.Version = CurrentVersion
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding shares: %w", err)
+		}
+		return util.AtomicWriteFile(path, data, 0600)
+	})
+}
+
+// Create adds a share of agent's session that is valid for ttl.
+func Create(townRoot, agent, createdBy string, ttl time.Duration) (*Share, error) {
+	if agent == "" {
+		return nil, fmt.Errorf("share needs an agent")
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("share lifetime must be positive")
+	}
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("generating token: %w", err)
+	}
+	now := time.Now()
+	sh := Share{
+		Token:     hex.EncodeToString(buf),
+		Agent:     agent,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := update(townRoot, func(s *Shares) error {
+		s.Shares = append(s.Shares, sh)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &sh, nil
+}
+
+// Lookup returns the share with token, or ErrNotFound or ErrExpired.
+func Lookup(townRoot, token string) (*Share, error) {
+	s, err := Load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, sh := range s.Shares {
+		if len(sh.Token) == len(token) && subtle.ConstantTimeCompare([]byte(sh.Token), []byte(token)) == 1 {
+			if sh.Expired(time.Now()) {
+				return nil, ErrExpired
+			}
+			return &sh, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// List returns the shares that have not expired, oldest first.
+func List(townRoot string) ([]Share, error) {
+	s, err := Load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var active []Share
+	for _, sh := range s.Shares {
+		if !sh.Expired(now) {
+			active = append(active, sh)
+		}
+	}
+	return active, nil
+}
+
+// Revoke removes the shares whose token starts with target (at least six
+// characters) or whose agent is target, returning how many were removed.
+func Revoke(townRoot, target string) (int, error) {
+	if target == "" {
+		return 0, fmt.Errorf("nothing to revoke")
+	}
+	removed := 0
+	err := update(townRoot, func(s *Shares) error {
+		kept := s.Shares[:0]
+		for _, sh := range s.Shares {
+			if sh.Agent == target || (len(target) >= 6 && strings.HasPrefix(sh.Token, target)) {
+				removed++
+				continue
+			}
+			kept = append(kept, sh)
+		}
+		s.Shares = kept
+		return nil
+	})
+	return removed, err
+}
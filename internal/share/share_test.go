@@ -0,0 +1,78 @@
+package share
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCreateLookupRevoke(t *testing.T) {
+	town := t.TempDir()
+
+	sh, err := Create(town, "gastown/polecats/nux", "overseer", time.Hour)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(sh.Token) != 2*tokenBytes {
+		t.Errorf("token %q has unexpected length", sh.Token)
+	}
+	if info, err := os.Stat(Path(town)); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("shares file mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+
+	got, err := Lookup(town, sh.Token)
+	if err != nil || got.Agent != "gastown/polecats/nux" {
+		t.Fatalf("Lookup = %+v, %v", got, err)
+	}
+	if _, err := Lookup(town, sh.Token[:10]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup of a token prefix = %v, want ErrNotFound", err)
+	}
+
+	other, err := Create(town, "gastown/crew/max", "", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := Revoke(town, sh.Token[:8]); err != nil || n != 1 {
+		t.Errorf("Revoke by token prefix = %d, %v", n, err)
+	}
+	if _, err := Lookup(town, sh.Token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("revoked share still found: %v", err)
+	}
+	if n, err := Revoke(town, "gastown/crew/max"); err != nil || n != 1 {
+		t.Errorf("Revoke by agent = %d, %v", n, err)
+	}
+	if _, err := Lookup(town, other.Token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("share revoked by agent still found: %v", err)
+	}
+}
+
+func TestExpiredShares(t *testing.T) {
+	town := t.TempDir()
+	past := time.Now().Add(-time.Minute)
+	if err := update(town, func(s *Shares) error {
+		s.Shares = append(s.Shares, Share{Token: "deadbeefdeadbeef", Agent: "mayor", CreatedAt: past.Add(-time.Hour), ExpiresAt: past})
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Lookup(town, "deadbeefdeadbeef"); !errors.Is(err, ErrExpired) {
+		t.Errorf("Lookup of expired share = %v, want ErrExpired", err)
+	}
+	if active, err := List(town); err != nil || len(active) != 0 {
+		t.Errorf("List = %v, %v; want no active shares", active, err)
+	}
+
+	// The next update drops it.
+	if _, err := Create(town, "mayor", "", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(town)
+	if err != nil || len(s.Shares) != 1 {
+		t.Errorf("after Create, stored shares = %+v, %v", s, err)
+	}
+	if _, err := Create(town, "mayor", "", 0); err == nil {
+		t.Error("zero lifetime should be rejected")
+	}
+}
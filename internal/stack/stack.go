@@ -0,0 +1,175 @@
+// Package stack tracks stacked branches: work branched from another
+// agent's unmerged branch instead of the rig's default branch, so that
+// dependent work can start before its parent lands.
+//
+// A rig's stacks are stored in <rig>/.runtime/stacks.json. An entry lives
+// from when the child branch is created until its parent is merged and the
+// child has been restacked onto the default branch.
+package stack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// CurrentVersion is the current schema version of the stacks file.
+const CurrentVersion = 1
+
+// Entry records that Branch is stacked on Parent.
+type Entry struct {
+	Branch      string    `json:"branch"`
+	Parent      string    `json:"parent"`
+	Base        string    `json:"base"`                   // parent commit the branch is built on
+	Issue       string    `json:"issue,omitempty"`        // work on Branch
+	ParentIssue string    `json:"parent_issue,omitempty"` // work on Parent; closed once it merges
+	CreatedAt   time.Time `json:"created_at"`
+	RestackedAt time.Time `json:"restacked_at,omitzero"`
+}
+
+// Stacks is a rig's set of stacked branches.
+type Stacks struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+// Path returns where a rig's stacks are stored.
+func Path(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "stacks.json")
+}
+
+// Load reads a rig's stacks. A rig without stacks has none.
+func Load(rigPath string) (*Stacks, error) {
+	data, err := os.ReadFile(Path(rigPath)) //nolint:gosec // G304: path is within the rig
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Stacks{Version: CurrentVersion}, nil
+		}
+		return nil, fmt.Errorf("reading stacks: %w", err)
+	}
+	var s Stacks
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing stacks: %w", err)
+	}
+	if s.Version > CurrentVersion {
+		return nil, fmt.Errorf("stacks version %d is newer than this gt supports", s.Version)
+	}
+	return &s, nil
+}
+
+// Update loads a rig's stacks, applies fn, and saves the result, holding a
+// lock so concurrent spawns and restacks do not lose each other's changes.
+// Nothing is saved if fn fails.
+func Update(rigPath string, fn func(*Stacks) error) error {
+	path := Path(rigPath)
+	return util.WithFileLock(path+".lock", func() error {
+		s, err := Load(rigPath)
+		if err != nil {
+			return err
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+		s.Version = CurrentVersion
+		return util.AtomicWriteJSON(path, s)
+	})
+}
+
+// Get returns the entry for branch, if it is stacked.
+func (s *Stacks) Get(branch string) (*Entry, bool) {
+	for i := range s.Entries {
+		if s.Entries[i].Branch == branch {
+			return &s.Entries[i], true
+		}
+	}
+	return nil, false
+}
+
+// Add records e, replacing any entry for the same branch. A branch cannot
+// be stacked on itself or on one of its own descendants.
+func (s *Stacks) Add(e Entry) error {
+	if e.Branch == "" || e.Parent == "" {
+		return fmt.Errorf("stack entry needs a branch and a parent")
+	}
+	for p := e.Parent; p != ""; {
+		if p == e.Branch {
+			return fmt.Errorf("stacking %s on %s would create a cycle", e.Branch, e.Parent)
+		}
+		parent, ok := s.Get(p)
+		if !ok {
+			break
+		}
+		p = parent.Parent
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	if existing, ok := s.Get(e.Branch); ok {
+		*existing = e
+		return nil
+	}
+	s.Entries = append(s.Entries, e)
+	return nil
+}
+
+// Remove drops the entry for branch. Branches stacked on it keep their
+// entries.
+func (s *Stacks) Remove(branch string) bool {
+	for i := range s.Entries {
+		if s.Entries[i].Branch == branch {
+			s.Entries = append(s.Entries[:i], s.Entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Children returns the entries stacked directly on branch.
+func (s *Stacks) Children(branch string) []Entry {
+	var children []Entry
+	for _, e := range s.Entries {
+		if e.Parent == branch {
+			children = append(children, e)
+		}
+	}
+	return children
+}
+
+// Chain returns branch and the branches below it, bottom first: the order
+// in which they have to merge.
+func (s *Stacks) Chain(branch string) []string {
+	chain := []string{branch}
+	for e, ok := s.Get(branch); ok; e, ok = s.Get(e.Parent) {
+		chain = append([]string{e.Parent}, chain...)
+		if len(chain) > len(s.Entries)+1 {
+			break // corrupt file with a cycle
+		}
+	}
+	return chain
+}
+
+// Depth returns how many stacked branches are below branch; a branch on
+// the default branch has depth 0.
+func (s *Stacks) Depth(branch string) int {
+	return len(s.Chain(branch)) - 1
+}
+
+// Ordered returns the entries with every parent before the branches
+// stacked on it, so restacking them in order moves whole stacks. Ties go
+// to the older entry.
+func (s *Stacks) Ordered() []Entry {
+	ordered := append([]Entry(nil), s.Entries...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		di, dj := s.Depth(ordered[i].Branch), s.Depth(ordered[j].Branch)
+		if di != dj {
+			return di < dj
+		}
+		return ordered[i].CreatedAt.Before(ordered[j].CreatedAt)
+	})
+	return ordered
+}
@@ -0,0 +1,87 @@
+package stack
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUpdateAndLoad(t *testing.T) {
+	rigPath := t.TempDir()
+
+	s, err := Load(rigPath)
+	if err != nil {
+		t.Fatalf("Load without file: %v", err)
+	}
+	if len(s.Entries) != 0 {
+		t.Fatalf("new rig has %d entries", len(s.Entries))
+	}
+
+	err = Update(rigPath, func(s *Stacks) error {
+		return s.Add(Entry{Branch: "polecat/b", Parent: "polecat/a", Base: "abc123", ParentIssue: "gt-1"})
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	s, err = Load(rigPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	e, ok := s.Get("polecat/b")
+	if !ok || e.Parent != "polecat/a" || e.Base != "abc123" || e.CreatedAt.IsZero() {
+		t.Errorf("entry = %+v, %v", e, ok)
+	}
+}
+
+func TestAddRejectsCycles(t *testing.T) {
+	s := &Stacks{}
+	if err := s.Add(Entry{Branch: "b", Parent: "a"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := s.Add(Entry{Branch: "c", Parent: "b"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := s.Add(Entry{Branch: "a", Parent: "c"}); err == nil {
+		t.Error("stacking a on its own descendant should fail")
+	}
+	if err := s.Add(Entry{Branch: "a", Parent: "a"}); err == nil {
+		t.Error("stacking a branch on itself should fail")
+	}
+	if err := s.Add(Entry{Branch: "b", Parent: "main2"}); err != nil || len(s.Entries) != 2 {
+		t.Errorf("re-adding b should replace its entry: err=%v, %d entries", err, len(s.Entries))
+	}
+}
+
+func TestChainAndOrdered(t *testing.T) {
+	now := time.Now()
+	s := &Stacks{}
+	// Added out of order: c on b on a, and d on a.
+	_ = s.Add(Entry{Branch: "c", Parent: "b", CreatedAt: now})
+	_ = s.Add(Entry{Branch: "d", Parent: "a", CreatedAt: now.Add(time.Second)})
+	_ = s.Add(Entry{Branch: "b", Parent: "a", CreatedAt: now.Add(2 * time.Second)})
+
+	if got, want := s.Chain("c"), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Chain(c) = %v, want %v", got, want)
+	}
+	if got := s.Chain("a"); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("Chain(a) = %v", got)
+	}
+
+	var order []string
+	for _, e := range s.Ordered() {
+		order = append(order, e.Branch)
+	}
+	if want := []string{"d", "b", "c"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Ordered = %v, want %v", order, want)
+	}
+
+	if children := s.Children("a"); len(children) != 2 {
+		t.Errorf("Children(a) = %v", children)
+	}
+	if !s.Remove("b") || s.Remove("b") {
+		t.Error("Remove should report whether the entry existed")
+	}
+	if _, ok := s.Get("c"); !ok {
+		t.Error("removing b should keep the entries stacked on it")
+	}
+}
@@ -0,0 +1,177 @@
+package style
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ctiospl/gastown/internal/i18n"
+)
+
+// Accessible mode is for screen readers: output is linear plain text with
+// no color, status symbols are replaced by words, and emoji and
+// box-drawing rules are dropped. It is enabled with GT_ACCESSIBLE=1 or
+// "accessible": true in the town's mayor/overseer.json, and commands with
+// full-screen views fall back to their plain output.
+
+var accessible bool
+
+// colored holds the default styles, restored when accessible mode is off.
+var colored = struct {
+	success, warning, error, info, dim, bold lipgloss.Style
+}{Success, Warning, Error, Info, Dim, Bold}
+
+// Accessible reports whether accessible mode is on.
+func Accessible() bool {
+	return accessible
+}
+
+// AccessibleFromEnv reports the accessible mode requested by GT_ACCESSIBLE,
+// and whether the variable is set at all.
+func AccessibleFromEnv() (on, set bool) {
+	v := os.Getenv("GT_ACCESSIBLE")
+	if v == "" {
+		return false, false
+	}
+	on, err := strconv.ParseBool(v)
+	if err != nil {
+		on = v == "on" || v == "yes"
+	}
+	return on, true
+}
+
+// SetAccessible turns accessible mode on or off. It replaces the shared
+// styles, so it should be called before any output is rendered.
+func SetAccessible(on bool) {
+	accessible = on
+	if on {
+		plain := lipgloss.NewStyle().Transform(PlainText)
+		Success, Warning, Error, Info, Dim, Bold = plain, plain, plain, plain, plain, plain
+	} else {
+		Success, Warning, Error, Info, Dim, Bold = colored.success, colored.warning, colored.error, colored.info, colored.dim, colored.bold
+	}
+	SuccessPrefix = Success.Render("✓")
+	WarningPrefix = Warning.Render("⚠")
+	ErrorPrefix = Error.Render("✗")
+	ArrowPrefix = Info.Render("→")
+}
+
+// Plain returns s as accessible mode prints it: s itself when the mode is
+// off, PlainText(s) when it is on. It is for text printed without a style.
+func Plain(s string) string {
+	if !accessible {
+		return s
+	}
+	return PlainText(s)
+}
+
+// glyphWords maps status symbols to what a screen reader should say. An
+// empty word drops the symbol (and the space after it) where the text
+// beside it already says what it means, as in "● running".
+var glyphWords = map[rune]string{
+	'✓': "a11y.ok",
+	'✔': "a11y.ok",
+	'✗': "a11y.failed",
+	'✘': "a11y.failed",
+	'⚠': "a11y.warning",
+	'ℹ': "a11y.info",
+	'→': "->",
+	'←': "<-",
+	'↩': "<-",
+	'▶': ">",
+	'…': "...",
+	'●': "",
+	'○': "",
+	'◐': "",
+	'◌': "",
+	'•': "",
+	'⏸': "",
+	'⏳': "",
+	'⏱': "",
+	'✎': "",
+}
+
+// PlainText rewrites s for a screen reader: see glyphWords. Runs of three
+// or more box-drawing characters (rules and borders) are dropped; shorter
+// ones become ASCII, and emoji are dropped.
+func PlainText(s string) string {
+	s = strings.ReplaceAll(s, "⚠ Warning:", "Warning:")
+	var b strings.Builder
+	dropSpace := false
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == ' ' && dropSpace:
+		case isBoxDrawing(r):
+			n := 0
+			j := i
+			for j < len(s) {
+				r2, size2 := utf8.DecodeRuneInString(s[j:])
+				if !isBoxDrawing(r2) {
+					break
+				}
+				n++
+				j += size2
+			}
+			if n < 3 {
+				for _, r2 := range s[i:j] {
+					b.WriteString(boxASCII(r2))
+				}
+			}
+			dropSpace = n >= 3
+			i = j
+			continue
+		default:
+			if word, ok := glyphWords[r]; ok {
+				if strings.HasPrefix(word, "a11y.") {
+					word = i18n.T(word)
+				}
+				b.WriteString(word)
+				dropSpace = word == ""
+				i += size
+				continue
+			}
+			if isEmoji(r) {
+				dropSpace = true
+				i += size
+				continue
+			}
+			b.WriteRune(r)
+		}
+		dropSpace = false
+		i += size
+	}
+	return b.String()
+}
+
+// isBoxDrawing reports whether r is a line or corner for drawing boxes.
+func isBoxDrawing(r rune) bool {
+	return r >= 0x2500 && r <= 0x257F
+}
+
+// boxASCII is the ASCII stand-in for a box-drawing character.
+func boxASCII(r rune) string {
+	switch r {
+	case '─', '━', '═', '┄', '┈':
+		return "-"
+	case '│', '┃', '║', '┆', '┊':
+		return "|"
+	}
+	return "+"
+}
+
+// isEmoji reports whether r is a pictograph, or a joiner or variation
+// selector that is part of one.
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF:
+		return true
+	case r >= 0x2600 && r <= 0x27BF, r >= 0x2B00 && r <= 0x2BFF:
+		return true
+	case r == 0xFE0F || r == 0x200D || r == 0x20E3:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package style
+
+import "testing"
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"✓ Noted in the town log", "OK Noted in the town log"},
+		{"⚠ Warning: disk full", "Warning: disk full"},
+		{"● running", "running"},
+		{"👤 Overseer: Ada", "Overseer: Ada"},
+		{"⚙️ Deacon", "Deacon"},
+		{"─── gastown/ ────────", "gastown/ "},
+		{"├── gt-1: fix", "gt-1: fix"},
+		{"a │ b", "a | b"},
+		{"gt-abc → Fix login", "gt-abc -> Fix login"},
+		{"plain text", "plain text"},
+	}
+	for _, tt := range tests {
+		if got := PlainText(tt.in); got != tt.want {
+			t.Errorf("PlainText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSetAccessible(t *testing.T) {
+	defer SetAccessible(false)
+
+	SetAccessible(true)
+	if !Accessible() {
+		t.Fatal("Accessible() = false after SetAccessible(true)")
+	}
+	if got := Success.Render("✓"); got != "OK" {
+		t.Errorf("Success.Render(✓) = %q, want OK", got)
+	}
+	if got := Error.Render("✗ build"); got != "Failed build" {
+		t.Errorf("Error.Render = %q", got)
+	}
+	if Plain("→") != "->" {
+		t.Errorf("Plain(→) = %q", Plain("→"))
+	}
+
+	SetAccessible(false)
+	if Plain("→") != "→" {
+		t.Error("Plain should not rewrite text when accessible mode is off")
+	}
+	if Success.Render("✓") == "OK" {
+		t.Error("styles not restored after SetAccessible(false)")
+	}
+}
+
+func TestAccessibleFromEnv(t *testing.T) {
+	t.Setenv("GT_ACCESSIBLE", "")
+	if _, set := AccessibleFromEnv(); set {
+		t.Error("empty GT_ACCESSIBLE should count as unset")
+	}
+	for v, want := range map[string]bool{"1": true, "true": true, "yes": true, "0": false, "off": false} {
+		t.Setenv("GT_ACCESSIBLE", v)
+		if on, set := AccessibleFromEnv(); !set || on != want {
+			t.Errorf("GT_ACCESSIBLE=%s: on = %v, set = %v", v, on, set)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package supervise restarts crashed agents under their rig's restart
+// policy. Each crash in a row waits twice as long as the last before the
+// agent is respawned, up to a cap, and after too many restarts in a row
+// the agent is left down for someone to look at.
+//
+// The count of restarts is read back from the town log rather than kept
+// in a state file: a run of restarts ends once a restarted agent stays up
+// for the policy's ResetAfter.
+package supervise
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+// EventRestart is the town log event recorded for each supervised restart,
+// and for giving up on one.
+const EventRestart townlog.EventType = "restart"
+
+// Policy defaults.
+const (
+	DefaultBackoff     = 10 * time.Second
+	DefaultMaxBackoff  = 5 * time.Minute
+	DefaultMaxRestarts = 5
+	DefaultResetAfter  = time.Hour
+)
+
+// Policy is a resolved restart policy.
+type Policy struct {
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	MaxRestarts int
+	ResetAfter  time.Duration
+}
+
+// PolicyFor returns the restart policy for an agent of a rig, named within
+// the rig ("witness", "crew/max", "polecats/Toast"), and whether its
+// crashes are restarted at all.
+func PolicyFor(c *config.RestartConfig, name string) (Policy, bool) {
+	if c == nil {
+		return Policy{}, false
+	}
+	p, ok := c.Agents[name]
+	if !ok {
+		p = c.RestartPolicy
+		patterns := make([]string, 0, len(c.Agents))
+		for pattern := range c.Agents {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, name); matched {
+				p = c.Agents[pattern]
+				break
+			}
+		}
+	}
+	if !p.Enabled {
+		return Policy{}, false
+	}
+	return Policy{
+		Backoff:     duration(p.Backoff, DefaultBackoff),
+		MaxBackoff:  duration(p.MaxBackoff, DefaultMaxBackoff),
+		MaxRestarts: orDefault(p.MaxRestarts, DefaultMaxRestarts),
+		ResetAfter:  duration(p.ResetAfter, DefaultResetAfter),
+	}, true
+}
+
+// Delay returns the wait before the nth restart in a row (from 1).
+func (p Policy) Delay(n int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < n && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, p.MaxBackoff)
+}
+
+// Decision is what to do about a crash.
+type Decision struct {
+	Attempt int           // the restart's number in the current run, from 1
+	Delay   time.Duration // how long to wait before restarting
+	GiveUp  bool          // the run has reached MaxRestarts
+}
+
+// Decide returns what to do about agent crashing at now, given the town
+// log. A run of restarts ends at one whose agent then stayed up for
+// ResetAfter, or at giving up.
+func Decide(events []townlog.Event, agent string, p Policy, now time.Time) Decision {
+	key := townlog.AgentKey(agent)
+	restarts := 0
+	crashedAt := now // the crash that followed the restart being looked at
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		if townlog.AgentKey(e.Agent) != key || e.Timestamp.After(now) {
+			continue
+		}
+		if e.Type == townlog.EventCrash {
+			crashedAt = e.Timestamp
+			continue
+		}
+		if e.Type != EventRestart {
+			continue
+		}
+		// After giving up, the agent was started by hand: a new run.
+		if strings.HasPrefix(e.Context, "gave up") || crashedAt.Sub(e.Timestamp) >= p.ResetAfter {
+			break
+		}
+		restarts++
+	}
+	d := Decision{Attempt: restarts + 1}
+	if restarts >= p.MaxRestarts {
+		d.GiveUp = true
+		return d
+	}
+	d.Delay = p.Delay(d.Attempt)
+	return d
+}
+
+// RestartContext is the context of the restart event for attempt.
+func RestartContext(attempt int, p Policy, delay time.Duration) string {
+	return fmt.Sprintf("attempt %d/%d after %s backoff", attempt, p.MaxRestarts, delay)
+}
+
+// GiveUpContext is the context of the event recorded when a run of
+// restarts reaches the policy's cap.
+func GiveUpContext(p Policy) string {
+	return fmt.Sprintf("gave up after %d restarts in a row", p.MaxRestarts)
+}
+
+func duration(s string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d
+	}
+	return def
+}
+
+func orDefault(n, def int) int {
+	if n > 0 {
+		return n
+	}
+	return def
+}
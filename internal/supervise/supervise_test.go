@@ -0,0 +1,100 @@
+package supervise
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+func TestPolicyFor(t *testing.T) {
+	c := &config.RestartConfig{
+		RestartPolicy: config.RestartPolicy{Enabled: true, Backoff: "30s"},
+		Agents: map[string]config.RestartPolicy{
+			"polecats/*":     {Enabled: false},
+			"polecats/Toast": {Enabled: true, MaxRestarts: 2},
+		},
+	}
+	tests := []struct {
+		name        string
+		wantOK      bool
+		wantBackoff time.Duration
+		wantMax     int
+	}{
+		{"witness", true, 30 * time.Second, DefaultMaxRestarts},
+		{"polecats/Nux", false, 0, 0},
+		{"polecats/Toast", true, DefaultBackoff, 2},
+	}
+	for _, tt := range tests {
+		p, ok := PolicyFor(c, tt.name)
+		if ok != tt.wantOK || p.Backoff != tt.wantBackoff || p.MaxRestarts != tt.wantMax {
+			t.Errorf("PolicyFor(%s) = %+v, %v; want backoff %s, max %d, %v", tt.name, p, ok, tt.wantBackoff, tt.wantMax, tt.wantOK)
+		}
+	}
+	if _, ok := PolicyFor(nil, "witness"); ok {
+		t.Error("no policy should not restart")
+	}
+}
+
+func TestDelay(t *testing.T) {
+	p := Policy{Backoff: 10 * time.Second, MaxBackoff: time.Minute}
+	want := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	for i, w := range want {
+		if got := p.Delay(i + 1); got != w {
+			t.Errorf("Delay(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+}
+
+func TestDecide(t *testing.T) {
+	p := Policy{Backoff: 10 * time.Second, MaxBackoff: time.Minute, MaxRestarts: 3, ResetAfter: time.Hour}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(ago time.Duration) time.Time { return now.Add(-ago) }
+	crash := func(ago time.Duration) townlog.Event {
+		return townlog.Event{Timestamp: at(ago), Type: townlog.EventCrash, Agent: "gastown/Toast"}
+	}
+	restart := func(ago time.Duration, context string) townlog.Event {
+		return townlog.Event{Timestamp: at(ago), Type: EventRestart, Agent: "gastown/polecats/Toast", Context: context}
+	}
+
+	tests := []struct {
+		name   string
+		events []townlog.Event
+		want   Decision
+	}{
+		{"first crash", []townlog.Event{crash(0)}, Decision{Attempt: 1, Delay: 10 * time.Second}},
+		{
+			"second crash in a row",
+			[]townlog.Event{crash(5 * time.Minute), restart(4*time.Minute, ""), crash(0)},
+			Decision{Attempt: 2, Delay: 20 * time.Second},
+		},
+		{
+			"stayed up long enough",
+			[]townlog.Event{crash(3 * time.Hour), restart(3*time.Hour, ""), crash(0)},
+			Decision{Attempt: 1, Delay: 10 * time.Second},
+		},
+		{
+			"cap reached",
+			[]townlog.Event{
+				restart(30*time.Minute, ""), crash(20 * time.Minute),
+				restart(19*time.Minute, ""), crash(10 * time.Minute),
+				restart(9*time.Minute, ""), crash(0),
+			},
+			Decision{Attempt: 4, GiveUp: true},
+		},
+		{
+			"started by hand after giving up",
+			[]townlog.Event{
+				restart(30*time.Minute, ""), crash(20 * time.Minute),
+				restart(19*time.Minute, GiveUpContext(p)), crash(0),
+			},
+			Decision{Attempt: 1, Delay: 10 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		if got := Decide(tt.events, "gastown/Toast", p, now); got != tt.want {
+			t.Errorf("%s: Decide = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
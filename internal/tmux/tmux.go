@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ctiospl/gastown/internal/gtlog"
 	"github.com/ctiospl/gastown/internal/constants"
 )
 
@@ -35,7 +37,9 @@ func (t *Tmux) run(args ...string) (string, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	start := time.Now()
 	err := cmd.Run()
+	gtlog.Exec("tmux", args, "", start, err)
 	if err != nil {
 		return "", t.wrapError(err, stderr.String(), args)
 	}
@@ -365,6 +369,47 @@ func (t *Tmux) GetPaneCommand(session string) (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
+// PaneState is what a liveness probe sees of a session's first pane.
+type PaneState struct {
+	Dead     bool      // the pane's process has exited (remain-on-exit)
+	Command  string    // pane_current_command, e.g. "node" or "bash"
+	Activity time.Time // when the window last printed output
+}
+
+// GetPaneState returns the state of a session's first pane.
+func (t *Tmux) GetPaneState(session string) (*PaneState, error) {
+	out, err := t.run("display-message", "-p", "-t", session, "#{pane_dead}|#{pane_current_command}|#{window_activity}")
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(strings.TrimSpace(out), "|")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("unexpected pane state format: %s", out)
+	}
+	state := &PaneState{Dead: parts[0] == "1", Command: parts[1]}
+	if secs, err := strconv.ParseInt(parts[2], 10, 64); err == nil && secs > 0 {
+		state.Activity = time.Unix(secs, 0)
+	}
+	return state, nil
+}
+
+// PipePane pipes everything a session's pane prints to command, run by the
+// shell, unless the pane is already piped.
+func (t *Tmux) PipePane(session, command string) error {
+	_, err := t.run("pipe-pane", "-o", "-t", session, command)
+	return err
+}
+
+// IsPanePiped reports whether a session's pane is being piped (see
+// PipePane).
+func (t *Tmux) IsPanePiped(session string) (bool, error) {
+	out, err := t.run("display-message", "-p", "-t", session, "#{pane_pipe}")
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) == "1", nil
+}
+
 // GetPaneID returns the pane identifier for a session's first pane.
 // Returns a pane ID like "%0" that can be used with RespawnPane.
 func (t *Tmux) GetPaneID(session string) (string, error) {
@@ -379,6 +424,20 @@ func (t *Tmux) GetPaneID(session string) (string, error) {
 	return lines[0], nil
 }
 
+// GetPanePID returns the PID of the process started in a session's first
+// pane, usually the shell the agent runs under.
+func (t *Tmux) GetPanePID(session string) (int, error) {
+	out, err := t.run("list-panes", "-t", session, "-F", "#{pane_pid}")
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.Split(out, "\n")[0])
+	if err != nil {
+		return 0, fmt.Errorf("no pane PID for session %s: %q", session, out)
+	}
+	return pid, nil
+}
+
 // GetPaneWorkDir returns the current working directory of a pane.
 func (t *Tmux) GetPaneWorkDir(session string) (string, error) {
 	out, err := t.run("list-panes", "-t", session, "-F", "#{pane_current_path}")
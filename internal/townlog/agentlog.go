@@ -0,0 +1,73 @@
+package townlog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// agentLogDir returns the directory of per-agent logs.
+func agentLogDir(townRoot string) string {
+	return filepath.Join(logDir(townRoot), "agents")
+}
+
+// AgentLogPath returns the per-agent log of agent, which holds the same
+// lines as the town log for that agent only: logs/agents/<agent>.log, e.g.
+// logs/agents/gastown/crew/max.log. Polecats are filed under their short
+// address (see AgentKey), and path elements that could leave the directory
+// are replaced.
+func AgentLogPath(townRoot, agent string) string {
+	parts := strings.Split(AgentKey(agent), "/")
+	for i, p := range parts {
+		if p == "" || p == "." || p == ".." || strings.ContainsRune(p, filepath.Separator) {
+			parts[i] = "_"
+		}
+	}
+	parts[len(parts)-1] += ".log"
+	return filepath.Join(append([]string{agentLogDir(townRoot)}, parts...)...)
+}
+
+// appendAgentLog adds e's log line to its agent's log.
+func appendAgentLog(townRoot string, e Event) error {
+	if e.Agent == "" {
+		return nil
+	}
+	path := AgentLogPath(townRoot, e.Agent)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) //nolint:gosec // G304: path is under the town's log dir
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(formatLogLine(e) + "\n")
+	return err
+}
+
+// ReadAgentEvents returns one agent's events, oldest first, from its
+// per-agent log. An agent without one (its events predate per-agent logs)
+// is looked up in the town log instead.
+func ReadAgentEvents(townRoot, agent string) ([]Event, error) {
+	content, err := os.ReadFile(AgentLogPath(townRoot, agent)) //nolint:gosec // G304: path is under the town's log dir
+	if err == nil {
+		return ParseLogLines(string(content))
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading agent log: %w", err)
+	}
+
+	all, err := ReadEvents(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	key := AgentKey(agent)
+	var events []Event
+	for _, e := range all {
+		if AgentKey(e.Agent) == key {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
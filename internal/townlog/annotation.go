@@ -0,0 +1,120 @@
+package townlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// An Annotation is an operator's note on past events: on particular events
+// by ID, or on everything logged in a time range. Annotations are kept
+// beside the log, in logs/annotations.jsonl, rather than in it, so the
+// record of what happened is never rewritten, and they survive migrations
+// between log backends.
+type Annotation struct {
+	Created time.Time `json:"created"`
+	By      string    `json:"by"`
+	Text    string    `json:"text"`
+
+	// Events holds the IDs of the events annotated (see Event.ID). Without
+	// any, the annotation covers From through To.
+	Events []string  `json:"events,omitempty"`
+	From   time.Time `json:"from,omitzero"`
+	To     time.Time `json:"to,omitzero"`
+}
+
+// AnnotationsPath returns where a town's annotations are stored.
+func AnnotationsPath(townRoot string) string {
+	return filepath.Join(logDir(townRoot), "annotations.jsonl")
+}
+
+// Annotate stores a.
+func Annotate(townRoot string, a Annotation) error {
+	if strings.TrimSpace(a.Text) == "" {
+		return fmt.Errorf("annotation text is empty")
+	}
+	if len(a.Events) == 0 && (a.From.IsZero() || a.To.Before(a.From)) {
+		return fmt.Errorf("annotation covers no events and no time range")
+	}
+	if a.Created.IsZero() {
+		a.Created = time.Now()
+	}
+	line, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	path := AnnotationsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) //nolint:gosec // G304: path is under the town's log dir
+	if err != nil {
+		return fmt.Errorf("opening annotations: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing annotation: %w", err)
+	}
+	return nil
+}
+
+// ReadAnnotations returns a town's annotations, oldest first. Lines that
+// cannot be parsed are skipped.
+func ReadAnnotations(townRoot string) ([]Annotation, error) {
+	content, err := os.ReadFile(AnnotationsPath(townRoot)) //nolint:gosec // G304: path is under the town's log dir
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading annotations: %w", err)
+	}
+	var notes []Annotation
+	for _, line := range splitLines(string(content)) {
+		var a Annotation
+		if json.Unmarshal([]byte(line), &a) == nil {
+			notes = append(notes, a)
+		}
+	}
+	return notes, nil
+}
+
+// Covers reports whether a annotates e.
+func (a Annotation) Covers(e Event) bool {
+	if len(a.Events) > 0 {
+		id := e.ID()
+		for _, want := range a.Events {
+			if want == id {
+				return true
+			}
+		}
+		return false
+	}
+	return !e.Timestamp.Before(a.From) && !e.Timestamp.After(a.To)
+}
+
+// IsRange reports whether a covers a time range rather than given events.
+func (a Annotation) IsRange() bool {
+	return len(a.Events) == 0
+}
+
+// AttachAnnotations places notes on events, which are oldest first: it
+// returns the annotations to show with each event, by index. An
+// annotation on events is shown with each of them; one on a time range is
+// shown once, with the first event in the range.
+func AttachAnnotations(events []Event, notes []Annotation) map[int][]Annotation {
+	attached := make(map[int][]Annotation)
+	for _, a := range notes {
+		for i, e := range events {
+			if a.Covers(e) {
+				attached[i] = append(attached[i], a)
+				if a.IsRange() {
+					break
+				}
+			}
+		}
+	}
+	return attached
+}
@@ -0,0 +1,43 @@
+package townlog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnnotations(t *testing.T) {
+	townRoot := t.TempDir()
+	base := time.Date(2026, 3, 1, 14, 0, 0, 0, time.Local)
+	events := []Event{
+		{Timestamp: base, Type: EventSpawn, Agent: "gastown/Toast"},
+		{Timestamp: base.Add(time.Minute), Type: EventCrash, Agent: "gastown/Toast", Context: "exit 137"},
+		{Timestamp: base.Add(2 * time.Minute), Type: EventCrash, Agent: "gastown/Nux"},
+		{Timestamp: base.Add(10 * time.Minute), Type: EventDone, Agent: "gastown/Nux"},
+	}
+
+	if err := Annotate(townRoot, Annotation{By: "overseer", Text: "OOM", Events: []string{events[1].ID(), events[2].ID()}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Annotate(townRoot, Annotation{By: "overseer", Text: "deploy", From: base.Add(30 * time.Second), To: base.Add(5 * time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Annotate(townRoot, Annotation{Text: "nothing"}); err == nil {
+		t.Error("Annotate() without events or range should fail")
+	}
+
+	notes, err := ReadAnnotations(townRoot)
+	if err != nil || len(notes) != 2 {
+		t.Fatalf("ReadAnnotations() = %v, %v; want 2", notes, err)
+	}
+	attached := AttachAnnotations(events, notes)
+	if len(attached[0]) != 0 || len(attached[3]) != 0 {
+		t.Errorf("annotations on unannotated events: %v", attached)
+	}
+	// The range note appears once, on the first event in the range.
+	if len(attached[1]) != 2 || attached[1][1].Text != "deploy" {
+		t.Errorf("event 1 annotations = %v, want OOM and deploy", attached[1])
+	}
+	if len(attached[2]) != 1 || attached[2][0].Text != "OOM" {
+		t.Errorf("event 2 annotations = %v, want OOM", attached[2])
+	}
+}
@@ -0,0 +1,87 @@
+package townlog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ArchivePaths returns archived town logs: rotated copies next to town.log
+// (town.log.1, town.log.2026-01.gz, ...) and anything under logs/archive/.
+// Gzipped archives are supported by ReadArchivedEvents.
+func ArchivePaths(townRoot string) []string {
+	dir := logDir(townRoot)
+	rotated, _ := filepath.Glob(filepath.Join(dir, "town.log.*"))
+	archived, _ := filepath.Glob(filepath.Join(dir, "archive", "town*.log*"))
+	paths := append(rotated, archived...)
+	sort.Strings(paths)
+	return paths
+}
+
+// ReadArchivedEvents reads every event from the archived logs and the
+// current log (or SQLite store), oldest first. It is meant for long-horizon
+// analysis; use ReadEvents for the live log only.
+func ReadArchivedEvents(townRoot string) ([]Event, error) {
+	var events []Event
+	for _, path := range ArchivePaths(townRoot) {
+		content, err := readLogFile(path)
+		if err != nil {
+			return nil, err
+		}
+		parsed, _ := ParseLogLines(content)
+		events = append(events, parsed...)
+	}
+
+	current, err := ReadEvents(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, current...)
+
+	// Archives may overlap or be named out of order; sort and drop duplicates.
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	deduped := events[:0]
+	for i, e := range events {
+		if i > 0 && sameEvent(e, events[i-1]) {
+			continue
+		}
+		deduped = append(deduped, e)
+	}
+	return deduped, nil
+}
+
+// sameEvent reports whether two events are the same entry. Timestamps are
+// compared as instants, since stored and parsed times differ in location.
+func sameEvent(a, b Event) bool {
+	return a.Timestamp.Equal(b.Timestamp) && a.Type == b.Type && a.Agent == b.Agent && a.Context == b.Context
+}
+
+// readLogFile returns a log file's content, decompressing .gz files.
+func readLogFile(path string) (string, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path comes from a glob under the town's log dir
+	if err != nil {
+		return "", fmt.Errorf("reading archived log: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return "", fmt.Errorf("reading archived log %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("reading archived log %s: %w", path, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,256 @@
+package townlog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// A chained town log is tamper-evident. With log_chain set in the town
+// settings, every event records in Prev the hash of the event logged
+// before it. The hash covers the event's fields and its own Prev, so it
+// commits to the whole log up to that event: editing, removing, or
+// inserting an event breaks the link of the event after it, and
+// VerifyChain reports the break. The newest hash, the chain head, is kept
+// in <town>/.runtime/log-chain.json so that cutting events off the end of
+// the log is caught too. A head copied somewhere outside the town (gt log
+// verify prints it) also holds against someone who rewrites that file.
+
+// GenesisHash is the Prev of the first event of a chain that starts in an
+// empty log.
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// ErrChainBroken means a chained log failed verification.
+var ErrChainBroken = errors.New("town log hash chain is broken")
+
+// chainHead is the state kept in log-chain.json.
+type chainHead struct {
+	Head    string    `json:"head"`    // hash of the newest chained event
+	Events  int       `json:"events"`  // chained events logged so far
+	Updated time.Time `json:"updated"` // when Head last moved
+}
+
+// chainPath returns where a town's chain head is stored.
+func chainPath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "log-chain.json")
+}
+
+// HashEvent returns the chain hash of an event: the hex SHA-256 of its
+// timestamp, type, severity, session, agent, context, Prev, and usage. A severity
+// equal to the type's default hashes as none, as the log stores it.
+func HashEvent(e Event) string {
+	sev := e.Severity
+	if sev == DefaultSeverity(e.Type) {
+		sev = ""
+	}
+	h := sha256.New()
+	for _, field := range []string{
+		strconv.FormatInt(e.Timestamp.UnixNano(), 10), string(e.Type), string(sev),
+		e.SessionID, e.Agent, e.Context, e.Prev,
+	} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	// Usage was added later; events without it hash as they always did.
+	if !e.Usage.IsZero() {
+		h.Write([]byte(e.Usage.String()))
+		h.Write([]byte{0})
+	}
+	// So was Transcript.
+	if e.Transcript != "" {
+		h.Write([]byte(transcriptField + e.Transcript))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// stored returns e as reading it back from town.log gives it: to the
+// second, in local time, with nudge messages truncated. Chained events in
+// town.log are hashed in this form, so verification can recompute them.
+func stored(e Event) Event {
+	back, err := parseLogLine(formatLogLine(e))
+	if err != nil {
+		return e
+	}
+	return back
+}
+
+// linkEvent sets e.Prev to the chain head, calls write, and moves the head
+// to e. It holds the chain lock throughout, so the order of events in the
+// log is the order of the chain. last returns the newest event already in
+// the log; it is consulted only when the chain starts, to link it to the
+// log's unchained history. hash hashes e as the log stores it.
+func linkEvent(path string, e *Event, last func() (Event, bool), hash func(Event) string, write func(Event) error) error {
+	return util.WithFileLock(path+".lock", func() error {
+		head, err := readChainHead(path)
+		if err != nil {
+			return err
+		}
+		if head.Head == "" {
+			head.Head = GenesisHash
+			if prev, ok := last(); ok {
+				head.Head = hash(prev)
+			}
+		}
+
+		e.Prev = head.Head
+		if err := write(*e); err != nil {
+			return err
+		}
+		head.Head = hash(*e)
+		head.Events++
+		head.Updated = time.Now()
+		data, err := json.MarshalIndent(head, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding chain head: %w", err)
+		}
+		return util.AtomicWriteFile(path, data, 0600)
+	})
+}
+
+// readChainHead reads the chain state; a missing file is an empty chain.
+func readChainHead(path string) (chainHead, error) {
+	var head chainHead
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town
+	if os.IsNotExist(err) {
+		return head, nil
+	}
+	if err != nil {
+		return head, fmt.Errorf("reading chain head: %w", err)
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return head, fmt.Errorf("decoding chain head %s: %w", path, err)
+	}
+	return head, nil
+}
+
+// ChainHead returns the chain head recorded for the town, or "" if it has
+// never logged a chained event.
+func ChainHead(townRoot string) (string, error) {
+	head, err := readChainHead(chainPath(townRoot))
+	return head.Head, err
+}
+
+// ChainBreak is one place where a log fails verification.
+type ChainBreak struct {
+	Index  int    `json:"index"`           // position in the verified events; len(events) for the end of the log
+	Event  *Event `json:"event,omitempty"` // the event at Index, if any
+	Reason string `json:"reason"`
+}
+
+// ChainReport is the result of VerifyChain.
+type ChainReport struct {
+	Events    int          `json:"events"`              // events examined
+	Unchained int          `json:"unchained"`           // events logged before the chain started
+	Chained   int          `json:"chained"`             // events from the first chained one on
+	Head      string       `json:"head,omitempty"`      // hash of the newest event, once the chain has started
+	Recorded  string       `json:"recorded,omitempty"`  // the head in log-chain.json
+	Continued bool         `json:"continued,omitempty"` // the chain starts in an earlier, unverified log
+	Breaks    []ChainBreak `json:"breaks,omitempty"`
+}
+
+// OK reports whether verification found nothing wrong.
+func (r *ChainReport) OK() bool { return len(r.Breaks) == 0 }
+
+// VerifyChain checks the links of a town's events, oldest first as the log
+// holds them. Events before the first chained one are counted as
+// unchained; from there on every event must link to the one before it.
+// When wholeLog is false, events may begin after the start of the chain
+// (the earlier part rotated into archives), and the first link is not
+// checked. recorded is the chain head the log should end with, and anchor
+// (optional) a head recorded earlier that must still be in the chain.
+func VerifyChain(events []Event, wholeLog bool, recorded, anchor string) *ChainReport {
+	r := &ChainReport{Events: len(events), Recorded: recorded}
+	broken := func(i int, reason string) {
+		b := ChainBreak{Index: i, Reason: reason}
+		if i < len(events) {
+			b.Event = &events[i]
+		}
+		r.Breaks = append(r.Breaks, b)
+	}
+
+	hashes := make(map[string]int, len(events))
+	started := false
+	for i, e := range events {
+		if e.Prev == "" && !started {
+			r.Unchained++
+			continue
+		}
+		r.Chained++
+		switch {
+		case e.Prev == "":
+			broken(i, "event is not chained; it was inserted, or logged with chaining turned off")
+		case !started && i > 0:
+			if e.Prev != HashEvent(events[i-1]) {
+				broken(i, "first chained event does not link to the event before it")
+			}
+		case !started:
+			if e.Prev != GenesisHash {
+				if wholeLog {
+					broken(i, "first chained event links to an event that is not in the log")
+				} else {
+					r.Continued = true
+				}
+			}
+		case e.Prev != HashEvent(events[i-1]):
+			broken(i, "link does not match the event before it: that event was modified or removed, or this one inserted")
+		}
+		started = true
+		r.Head = HashEvent(e)
+		hashes[r.Head] = i
+	}
+
+	if recorded != "" && recorded != r.Head {
+		if i, ok := hashes[recorded]; ok {
+			broken(i+1, fmt.Sprintf("events after the recorded head were added without moving it (%d of them)", len(events)-i-1))
+		} else {
+			broken(len(events), "recorded head is not in the log: events were removed from the end, or the newest one was modified")
+		}
+	}
+	if anchor != "" {
+		if _, ok := hashes[anchor]; !ok {
+			broken(len(events), fmt.Sprintf("anchored head %s is not in the log", anchor))
+		}
+	}
+	return r
+}
+
+// ReadChainedEvents returns the events of a town in the order they were
+// chained: the order of town.log or the segments, and the order of
+// insertion in a SQLite store (whose queries sort by timestamp). With
+// archives, the rotated logs come first.
+func ReadChainedEvents(townRoot string, archives bool) ([]Event, error) {
+	var events []Event
+	if archives {
+		for _, path := range ArchivePaths(townRoot) {
+			content, err := readLogFile(path)
+			if err != nil {
+				return nil, err
+			}
+			parsed, _ := ParseLogLines(content)
+			events = append(events, parsed...)
+		}
+	}
+	b := openBackend(townRoot)
+	s, ok := b.(*store)
+	if !ok {
+		current, err := ReadEvents(townRoot)
+		return append(events, current...), err
+	}
+	rows, err := s.rows("", "id", 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		events = append(events, row.event())
+	}
+	return events, nil
+}
@@ -0,0 +1,163 @@
+package townlog
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newChainedTown returns a town whose log holds two unchained events, then
+// three chained ones.
+func newChainedTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	l := NewLogger(townRoot)
+	_ = l.Log(EventSpawn, "gastown/polecats/Toast", "gt-1")
+	_ = l.Log(EventNudge, "mayor", "before the chain")
+
+	dir := filepath.Join(townRoot, "settings")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type": "town-settings", "version": 1, "log_chain": true}`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	l = NewLogger(townRoot)
+	_ = l.Log(EventNudge, "gastown/polecats/Toast", strings.Repeat("a long nudge message ", 5))
+	_ = l.Emit("deploy", "gastown/crew/max", "v1.2")
+	_ = l.Log(EventDone, "gastown/polecats/Toast", "gt-1")
+	return townRoot
+}
+
+// verifyTown verifies the town's current log against its recorded head.
+func verifyTown(t *testing.T, townRoot string) *ChainReport {
+	t.Helper()
+	events, err := ReadChainedEvents(townRoot, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := ChainHead(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return VerifyChain(events, true, head, "")
+}
+
+func TestChainVerifies(t *testing.T) {
+	townRoot := newChainedTown(t)
+	r := verifyTown(t, townRoot)
+	if !r.OK() || r.Unchained != 2 || r.Chained != 3 || r.Head == "" || r.Head != r.Recorded {
+		t.Fatalf("VerifyChain() = %+v, want an intact chain of 3 after 2 unchained", r)
+	}
+
+	events, _ := ReadEvents(townRoot)
+	if events[2].Prev != HashEvent(events[1]) {
+		t.Error("the first chained event should link to the last unchained one")
+	}
+	if fresh := VerifyChain(events[2:], true, "", ""); fresh.OK() {
+		t.Error("a chain starting mid-log should fail when the log is meant to be whole")
+	}
+	if rotated := VerifyChain(events[2:], false, "", ""); !rotated.OK() || !rotated.Continued {
+		t.Errorf("a chain continued from rotated logs should verify: %+v", rotated)
+	}
+	if anchored := VerifyChain(events, true, r.Head, HashEvent(events[3])); !anchored.OK() {
+		t.Errorf("an anchor in the chain should verify: %+v", anchored.Breaks)
+	}
+	if anchored := VerifyChain(events, true, r.Head, GenesisHash); anchored.OK() {
+		t.Error("an anchor that is not in the chain should fail")
+	}
+}
+
+// rewriteLog applies fn to the lines of the town's town.log.
+func rewriteLog(t *testing.T, townRoot string, fn func([]string) []string) {
+	t.Helper()
+	data, err := os.ReadFile(logPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := fn(strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"))
+	if err := os.WriteFile(logPath(townRoot), []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChainDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name  string
+		edit  func([]string) []string
+		index int
+	}{
+		{"modified", func(l []string) []string {
+			l[3] = strings.Replace(l[3], "v1.2", "v1.3", 1)
+			return l
+		}, 4},
+		{"removed", func(l []string) []string { return append(l[:3], l[4:]...) }, 3},
+		{"truncated", func(l []string) []string { return l[:4] }, 4},
+		{"unchained tail", func(l []string) []string {
+			return append(l, "2026-01-02 15:04:05 [note] mayor noted \"added later\"")
+		}, 5},
+		{"history modified", func(l []string) []string {
+			l[1] = strings.Replace(l[1], "before", "after", 1)
+			return l
+		}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			townRoot := newChainedTown(t)
+			rewriteLog(t, townRoot, tt.edit)
+			r := verifyTown(t, townRoot)
+			if r.OK() {
+				t.Fatalf("VerifyChain() passed a %s log", tt.name)
+			}
+			if r.Breaks[0].Index != tt.index {
+				t.Errorf("first break at %d (%s), want %d", r.Breaks[0].Index, r.Breaks[0].Reason, tt.index)
+			}
+		})
+	}
+}
+
+func TestChainSurvivesMigration(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	townRoot := newChainedTown(t)
+	if err := CreateStore(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportLog(townRoot, logPath(townRoot)); err != nil {
+		t.Fatal(err)
+	}
+	_ = NewLogger(townRoot).Log(EventSpawn, "gastown/polecats/Nux", "gt-2")
+	if r := verifyTown(t, townRoot); !r.OK() || r.Chained != 4 {
+		t.Fatalf("SQLite chain: %+v", r)
+	}
+
+	events, _ := ReadChainedEvents(townRoot, false)
+	if err := CreateSegments(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Remove(DBPath(townRoot))
+	if err := AppendSegments(townRoot, events); err != nil {
+		t.Fatal(err)
+	}
+	_ = NewLogger(townRoot).Log(EventDone, "gastown/polecats/Nux", "gt-2")
+	if r := verifyTown(t, townRoot); !r.OK() || r.Chained != 5 {
+		t.Fatalf("segment chain: %+v", r)
+	}
+}
+
+func TestChainLineFormat(t *testing.T) {
+	townRoot := newChainedTown(t)
+	events, _ := ReadEvents(townRoot)
+	e := events[3]
+	if e.Type != "deploy" || e.Agent != "gastown/crew/max" || e.Context != "v1.2" || len(e.Prev) != 64 {
+		t.Errorf("chained event read back as %+v", e)
+	}
+	got := formatLogLine(e)
+	if !strings.Contains(got, " [deploy#"+e.Prev+"] ") {
+		t.Errorf("formatLogLine() = %q, want the link at the end of the tag", got)
+	}
+}
@@ -0,0 +1,204 @@
+package townlog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// DefaultFollowInterval is how often Follow polls the log for new lines.
+const DefaultFollowInterval = 250 * time.Millisecond
+
+// FollowOptions configures Follow.
+type FollowOptions struct {
+	// Filter selects which events are delivered, backlog included.
+	Filter Filter
+
+	// Backlog is how many matching events already in the log to deliver
+	// before following, like tail -n. Zero delivers none.
+	Backlog int
+
+	// Interval between polls. Zero uses DefaultFollowInterval.
+	Interval time.Duration
+}
+
+// Follow delivers events appended to the town log to fn until ctx is done
+// or fn returns an error. It polls rather than relying on tail or
+// filesystem notifications, so it works on every platform, and it picks up
+// the new file when the log is rotated or truncated. A log that does not
+// exist yet is waited for. Towns on log segments or the SQLite store are
+// polled for new records.
+func Follow(ctx context.Context, townRoot string, opts FollowOptions, fn func(Event) error) error {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultFollowInterval
+	}
+	if s := openBackend(townRoot); s != nil {
+		return s.follow(ctx, opts, interval, fn)
+	}
+	t := &follower{path: logPath(townRoot), filter: opts.Filter, fn: fn}
+	defer t.close()
+
+	if err := t.start(opts.Backlog); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := t.poll(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// follower tracks the read position in the log across polls.
+type follower struct {
+	path    string
+	filter  Filter
+	fn      func(Event) error
+	file    *os.File
+	info    os.FileInfo
+	offset  int64
+	partial []byte // trailing line not yet terminated by a newline
+}
+
+// start opens the log and delivers the last backlog matching events.
+func (t *follower) start(backlog int) error {
+	if err := t.open(); err != nil || t.file == nil {
+		return err
+	}
+	data, err := io.ReadAll(t.file)
+	if err != nil {
+		return fmt.Errorf("reading log file: %w", err)
+	}
+	t.offset = int64(len(data))
+	if info, err := t.file.Stat(); err == nil {
+		t.info = info
+	}
+
+	var matched []Event
+	for _, line := range t.lines(data) {
+		if e, ok := t.parse(line); ok {
+			matched = append(matched, e)
+		}
+	}
+	if backlog < len(matched) {
+		matched = matched[len(matched)-backlog:]
+	}
+	for _, e := range matched {
+		if err := t.fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// poll delivers any lines appended since the last poll.
+func (t *follower) poll() error {
+	if t.file == nil {
+		// Log did not exist yet; everything in it is new.
+		if err := t.open(); err != nil || t.file == nil {
+			return err
+		}
+	}
+
+	current, err := os.Stat(t.path)
+	switch {
+	case err == nil && !os.SameFile(current, t.info):
+		// Rotated: finish the old file, then read the new one from the start.
+		if err := t.drain(); err != nil {
+			return err
+		}
+		t.close()
+		if err := t.open(); err != nil || t.file == nil {
+			return err
+		}
+	case err == nil && (current.Size() < t.offset ||
+		current.Size() == t.offset && current.ModTime().After(t.info.ModTime())):
+		// Truncated in place. A rewrite to the same size still changes the
+		// modification time; appends would have grown the file.
+		t.info, t.offset, t.partial = current, 0, nil
+		if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("rewinding log file: %w", err)
+		}
+	}
+	return t.drain()
+}
+
+// drain reads to the current end of the open file and delivers new events.
+func (t *follower) drain() error {
+	data, err := io.ReadAll(t.file)
+	if err != nil {
+		return fmt.Errorf("reading log file: %w", err)
+	}
+	t.offset += int64(len(data))
+	if len(data) > 0 {
+		if info, err := t.file.Stat(); err == nil {
+			t.info = info
+		}
+	}
+	for _, line := range t.lines(data) {
+		if e, ok := t.parse(line); ok {
+			if err := t.fn(e); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// lines splits data into complete lines, carrying an unterminated tail
+// over to the next read.
+func (t *follower) lines(data []byte) []string {
+	data = append(t.partial, data...)
+	end := bytes.LastIndexByte(data, '\n')
+	if end < 0 {
+		t.partial = data
+		return nil
+	}
+	t.partial = append([]byte(nil), data[end+1:]...)
+	return splitLines(string(data[:end]))
+}
+
+func (t *follower) parse(line string) (Event, bool) {
+	e, err := parseLogLine(line)
+	if err != nil || !t.filter.Match(e) {
+		return Event{}, false
+	}
+	return e, true
+}
+
+// open opens the log from the start. A missing log is not an error; the
+// file stays nil until it appears.
+func (t *follower) open() error {
+	f, err := os.Open(t.path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	t.file, t.info, t.offset, t.partial = f, info, 0, nil
+	return nil
+}
+
+func (t *follower) close() {
+	if t.file != nil {
+		_ = t.file.Close()
+		t.file = nil
+	}
+}
@@ -0,0 +1,121 @@
+package townlog
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFollowerPoll(t *testing.T) {
+	townRoot := t.TempDir()
+	logger := NewLogger(townRoot)
+
+	var got []string
+	f := &follower{
+		path:   logPath(townRoot),
+		filter: Filter{Type: EventSpawn},
+		fn:     func(e Event) error { got = append(got, e.Context); return nil },
+	}
+	defer f.close()
+
+	// The log does not exist yet.
+	if err := f.start(10); err != nil {
+		t.Fatalf("start() error: %v", err)
+	}
+	if err := f.poll(); err != nil {
+		t.Fatalf("poll() error: %v", err)
+	}
+
+	_ = logger.Log(EventSpawn, "gastown/polecats/Toast", "gt-1")
+	_ = logger.Log(EventNudge, "gastown/polecats/Toast", "ping")
+	if err := f.poll(); err != nil {
+		t.Fatalf("poll() error: %v", err)
+	}
+	if len(got) != 1 || got[0] != "gt-1" {
+		t.Fatalf("after first append got %v, want [gt-1]", got)
+	}
+
+	// A partial line is held back until it is complete.
+	file, err := os.OpenFile(logPath(townRoot), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := formatLogLine(Event{Timestamp: time.Now(), Type: EventSpawn, Agent: "gastown/polecats/Nux", Context: "gt-2"})
+	_, _ = file.WriteString(line[:10])
+	if err := f.poll(); err != nil {
+		t.Fatalf("poll() error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("partial line delivered: %v", got)
+	}
+	_, _ = file.WriteString(line[10:] + "\n")
+	_ = file.Close()
+	if err := f.poll(); err != nil {
+		t.Fatalf("poll() error: %v", err)
+	}
+	if len(got) != 2 || got[1] != "gt-2" {
+		t.Fatalf("after completing line got %v, want [gt-1 gt-2]", got)
+	}
+
+	// Rotation: the log is replaced by a new file.
+	if err := os.Rename(logPath(townRoot), logPath(townRoot)+".1"); err != nil {
+		t.Fatal(err)
+	}
+	_ = logger.Log(EventSpawn, "gastown/polecats/Toast", "gt-3")
+	if err := f.poll(); err != nil {
+		t.Fatalf("poll() error: %v", err)
+	}
+	if len(got) != 3 || got[2] != "gt-3" {
+		t.Fatalf("after rotation got %v, want [gt-1 gt-2 gt-3]", got)
+	}
+
+	// Truncation in place starts over from the beginning.
+	if err := os.Truncate(logPath(townRoot), 0); err != nil {
+		t.Fatal(err)
+	}
+	_ = logger.Log(EventSpawn, "gastown/polecats/Toast", "gt-4")
+	if err := f.poll(); err != nil {
+		t.Fatalf("poll() error: %v", err)
+	}
+	if len(got) != 4 || got[3] != "gt-4" {
+		t.Fatalf("after truncation got %v, want [gt-1 gt-2 gt-3 gt-4]", got)
+	}
+}
+
+func TestFollowBacklog(t *testing.T) {
+	townRoot := t.TempDir()
+	logger := NewLogger(townRoot)
+	for _, ctx := range []string{"gt-1", "gt-2", "gt-3"} {
+		_ = logger.Log(EventDone, "gastown/polecats/Toast", ctx)
+		_ = logger.Log(EventNudge, "mayor", "ping")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var got []string
+	opts := FollowOptions{Filter: Filter{Agent: "gastown/"}, Backlog: 2, Interval: 10 * time.Millisecond}
+	err := Follow(ctx, townRoot, opts, func(e Event) error {
+		got = append(got, e.Context)
+		if len(got) == 2 {
+			_ = logger.Log(EventDone, "gastown/polecats/Nux", "gt-4")
+		}
+		if len(got) == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Follow() error: %v", err)
+	}
+	want := []string{"gt-2", "gt-3", "gt-4"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -2,11 +2,21 @@
 package townlog
 
 import (
+	"context"
+	"crypto/sha1" //nolint:gosec // G505: used for short content IDs only
+	"encoding/hex"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
 )
 
 // EventType represents the type of agent lifecycle event.
@@ -29,6 +39,10 @@ const (
 	EventKill EventType = "kill"
 	// EventCallback indicates a callback was processed during patrol.
 	EventCallback EventType = "callback"
+	// EventMark is a named checkpoint set by the operator (see FindMark).
+	EventMark EventType = "mark"
+	// EventNote is a free-form annotation written by the operator.
+	EventNote EventType = "note"
 
 	// Witness patrol events
 	EventPatrolStarted  EventType = "patrol_started"
@@ -38,18 +52,129 @@ const (
 	EventPatrolComplete EventType = "patrol_complete"
 )
 
+// Severity is how much attention an event deserves.
+type Severity string
+
+const (
+	// SeverityInfo is routine activity.
+	SeverityInfo Severity = "info"
+	// SeverityWarn is worth a look: an agent was killed or needed a nudge.
+	SeverityWarn Severity = "warn"
+	// SeverityError needs attention: an agent crashed or escalated.
+	SeverityError Severity = "error"
+)
+
+// Rank orders severities from info (0) to error (2). Unknown values rank
+// as info.
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityWarn:
+		return 1
+	case SeverityError:
+		return 2
+	}
+	return 0
+}
+
+// ParseSeverity parses a severity name.
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(strings.ToLower(strings.TrimSpace(s))); sev {
+	case SeverityInfo, SeverityWarn, SeverityError:
+		return sev, nil
+	}
+	return "", fmt.Errorf("invalid severity %q: use info, warn, or error", s)
+}
+
+// DefaultSeverity returns the severity of an event of type t that was
+// logged without one.
+func DefaultSeverity(t EventType) Severity {
+	switch t {
+	case EventCrash, EventEscalationSent:
+		return SeverityError
+	case EventKill, EventPolecatNudged:
+		return SeverityWarn
+	}
+	return SeverityInfo
+}
+
+// maxEventTypeLen bounds custom event type names.
+const maxEventTypeLen = 64
+
+// IsBuiltin reports whether t is one of the event types gt itself records.
+// Any other valid type is a custom type recorded by a script or hook.
+func (t EventType) IsBuiltin() bool {
+	_, ok := detailTemplates[t]
+	return ok
+}
+
+// ValidateEventType checks that t can be recorded and read back: a
+// lowercase letter followed by lowercase letters, digits, '_', '-', or '.'.
+func ValidateEventType(t EventType) error {
+	if t == "" {
+		return fmt.Errorf("event type is empty")
+	}
+	if len(t) > maxEventTypeLen {
+		return fmt.Errorf("event type %q is longer than %d characters", t, maxEventTypeLen)
+	}
+	for i, c := range t {
+		switch {
+		case c >= 'a' && c <= 'z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.'):
+		default:
+			return fmt.Errorf("invalid event type %q: use lowercase letters, digits, '_', '-', and '.', starting with a letter", t)
+		}
+	}
+	return nil
+}
+
 // Event represents a single agent lifecycle event.
 type Event struct {
 	Timestamp time.Time `json:"timestamp"`
 	Type      EventType `json:"type"`
 	Agent     string    `json:"agent"`            // e.g., "gastown/crew/max" or "gastown/polecats/Toast"
 	Context   string    `json:"context,omitempty"` // Additional context (issue ID, error message, etc.)
+
+	// Severity overrides the type's default severity (empty for the
+	// default; see Level).
+	Severity Severity `json:"severity,omitempty"`
+
+	// SessionID links the events of one agent lifecycle, from spawn to
+	// done. The logger fills it in; see trackSession.
+	SessionID string `json:"session_id,omitempty"`
+
+	// Prev is the hash of the event logged before this one, in towns that
+	// chain their log (see HashEvent); empty otherwise.
+	Prev string `json:"prev,omitempty"`
+
+	// Usage is the token, cost, and time figures reported with a done or
+	// handoff event; nil if none were.
+	Usage *Usage `json:"usage,omitempty"`
+
+	// Transcript is where the agent's session transcript was archived
+	// when it finished or handed off, relative to the town root; empty
+	// if it was not.
+	Transcript string `json:"transcript,omitempty"`
+}
+
+// Level returns the event's severity, falling back to its type's default.
+func (e Event) Level() Severity {
+	if e.Severity != "" {
+		return e.Severity
+	}
+	return DefaultSeverity(e.Type)
 }
 
-// Logger handles writing events to the town log file.
+// Logger handles writing events to the town log file, or to the town's
+// log segments or SQLite store when it has one. Events that webhooks in the town settings
+// subscribe to are also posted to them, in the background.
 type Logger struct {
-	logPath string
-	mu      sync.Mutex
+	townRoot     string
+	logPath      string
+	sessionsPath string
+	chainPath    string // empty unless the town chains its log
+	backend      backend
+	webhooks     *webhooks
+	mu           sync.Mutex
 }
 
 // logDir returns the directory for town logs.
@@ -57,23 +182,138 @@ func logDir(townRoot string) string {
 	return filepath.Join(townRoot, "logs")
 }
 
+// backend is a store that replaces town.log: log segments or SQLite.
+type backend interface {
+	insert(events ...Event) error
+	query(f Filter) ([]Event, error)
+	tail(n int) ([]Event, error)
+	follow(ctx context.Context, opts FollowOptions, interval time.Duration, fn func(Event) error) error
+}
+
+// openBackend returns the town's log store, or nil if it logs to town.log.
+// Segments take precedence over SQLite. A store that cannot be opened is
+// returned as one whose reads and writes fail, never as town.log.
+func openBackend(townRoot string) backend {
+	if l := openSegments(townRoot); l != nil {
+		return l
+	}
+	s, err := openStore(townRoot)
+	if err != nil {
+		return unavailable{err}
+	}
+	if s != nil {
+		return s
+	}
+	return nil
+}
+
 // logPath returns the path to the town log file.
 func logPath(townRoot string) string {
 	return filepath.Join(logDir(townRoot), "town.log")
 }
 
+// LogLocation returns where the town's events are kept: the segment
+// directory, the SQLite database, or town.log.
+func LogLocation(townRoot string) string {
+	switch {
+	case UsesSegments(townRoot):
+		return segmentDir(townRoot)
+	case UsesSQLite(townRoot):
+		return DBPath(townRoot)
+	}
+	return logPath(townRoot)
+}
+
+// HasLog reports whether the town has logged anything yet.
+func HasLog(townRoot string) bool {
+	if openBackend(townRoot) != nil {
+		return true
+	}
+	_, err := os.Stat(logPath(townRoot))
+	return err == nil
+}
+
 // NewLogger creates a new Logger for the given town root.
 func NewLogger(townRoot string) *Logger {
-	return &Logger{
-		logPath: logPath(townRoot),
+	l := &Logger{
+		townRoot:     townRoot,
+		logPath:      logPath(townRoot),
+		sessionsPath: sessionsPath(townRoot),
+		backend:      openBackend(townRoot),
+	}
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		l.webhooks = loadWebhooks(townRoot, settings)
+		if settings.LogChain {
+			l.chainPath = chainPath(townRoot)
+		}
 	}
+	return l
 }
 
-// LogEvent logs a single event to the town log.
+// LogEvent logs a single event to the town log and starts its webhook
+// deliveries, without waiting for them. An event without a session ID
+// gets its agent's current session (see trackSession).
 func (l *Logger) LogEvent(event Event) error {
+	if err := trackSession(l.sessionsPath, &event); err != nil {
+		return fmt.Errorf("tracking session: %w", err)
+	}
+	if err := l.write(event); err != nil {
+		return err
+	}
+	l.webhooks.notify(event)
+	return nil
+}
+
+// write appends event to the log file or store, linking it into the hash
+// chain if the town keeps one.
+func (l *Logger) write(event Event) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	if l.chainPath == "" {
+		return l.append(event)
+	}
+	hash := HashEvent
+	if l.backend == nil {
+		hash = func(e Event) string { return HashEvent(stored(e)) }
+	}
+	return linkEvent(l.chainPath, &event, l.last, hash, l.append)
+}
+
+// last returns the newest event in the log, if there is one.
+func (l *Logger) last() (Event, bool) {
+	var events []Event
+	if l.backend != nil {
+		events, _ = l.backend.tail(1)
+	} else if content, err := os.ReadFile(l.logPath); err == nil {
+		events, _ = ParseLogLines(string(content))
+	}
+	if len(events) == 0 {
+		return Event{}, false
+	}
+	return events[len(events)-1], true
+}
+
+// append writes event to the log file or store, then to its agent's log.
+func (l *Logger) append(event Event) error {
+	if err := l.appendTown(event); err != nil {
+		return err
+	}
+	// The agent log is a per-agent copy for fast lookups; the town log is
+	// the record, so failing to update the copy does not fail the event.
+	_ = appendAgentLog(l.townRoot, event)
+	return nil
+}
+
+// appendTown writes event to the log file or store.
+func (l *Logger) appendTown(event Event) error {
+	if l.backend != nil {
+		if err := l.backend.insert(event); err != nil {
+			return fmt.Errorf("writing event: %w", err)
+		}
+		return nil
+	}
+
 	// Ensure log directory exists
 	if err := os.MkdirAll(filepath.Dir(l.logPath), 0755); err != nil {
 		return fmt.Errorf("creating log directory: %w", err)
@@ -105,11 +345,135 @@ func (l *Logger) Log(eventType EventType, agent, context string) error {
 	})
 }
 
+// Emit records an event of any type, built-in or custom. It is the entry
+// point for events that come from outside gt (scripts, hooks), so it checks
+// the type and agent and folds the context onto one line.
+func (l *Logger) Emit(eventType EventType, agent, context string) error {
+	return l.EmitEvent(Event{Type: eventType, Agent: agent, Context: context})
+}
+
+// EmitEvent is Emit for an event that may carry its own severity. A zero
+// timestamp means now.
+func (l *Logger) EmitEvent(e Event) error {
+	e, err := PrepareEvent(e)
+	if err != nil {
+		return err
+	}
+	return l.LogEvent(e)
+}
+
+// Unlogged returns the events that are not in the town log yet, each
+// once. Events are the same if they have the same ID (see Event.ID).
+func Unlogged(townRoot string, events []Event) ([]Event, error) {
+	existing, err := ReadEvents(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		seen[e.ID()] = true
+	}
+	var fresh []Event
+	for _, e := range events {
+		if id := e.ID(); !seen[id] {
+			seen[id] = true
+			fresh = append(fresh, e)
+		}
+	}
+	return fresh, nil
+}
+
+// PrepareEvent checks an event that comes from outside gt and normalizes
+// it for the log: a severity equal to its type's default is dropped, the
+// context is folded onto one line, and a zero timestamp means now. The
+// timestamp is converted to local time, as the log stores it.
+func PrepareEvent(e Event) (Event, error) {
+	if err := ValidateEventType(e.Type); err != nil {
+		return e, err
+	}
+	if e.Agent == "" || strings.ContainsAny(e.Agent, " \t\r\n") {
+		return e, fmt.Errorf("invalid agent %q: must be non-empty with no whitespace", e.Agent)
+	}
+	if e.Severity != "" {
+		sev, err := ParseSeverity(string(e.Severity))
+		if err != nil {
+			return e, err
+		}
+		e.Severity = sev
+		if sev == DefaultSeverity(e.Type) {
+			e.Severity = ""
+		}
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	// The log is written in local time.
+	e.Timestamp = e.Timestamp.Local()
+	e.Context = strings.Join(strings.Fields(e.Context), " ")
+	return e, nil
+}
+
+// Import records events from another system, oldest first, skipping any
+// already in the log or repeated in events (see Unlogged), and returns how
+// many it recorded. Events are prepared as PrepareEvent does and keep
+// their own timestamps, so an import can fill in history; they join no
+// agent session, and webhooks are not posted for them.
+func (l *Logger) Import(events []Event) (int, error) {
+	prepared := make([]Event, 0, len(events))
+	for _, e := range events {
+		e, err := PrepareEvent(e)
+		if err != nil {
+			return 0, err
+		}
+		e.SessionID, e.Prev = "", ""
+		prepared = append(prepared, e)
+	}
+	fresh, err := Unlogged(l.townRoot, prepared)
+	if err != nil {
+		return 0, err
+	}
+	sort.SliceStable(fresh, func(i, j int) bool { return fresh[i].Timestamp.Before(fresh[j].Timestamp) })
+	for i, e := range fresh {
+		if err := l.write(e); err != nil {
+			return i, err
+		}
+	}
+	return len(fresh), nil
+}
+
 // formatLogLine formats an event as a human-readable log line.
 // Format: 2025-12-26 15:30:45 [spawn] gastown/crew/max spawned for gt-xyz
+// A severity other than the type's default follows the type, as in
+// [deploy:error], and the session ID comes next, as in [spawn@3f9a2c01].
+// In a chained log the hash of the previous event ends the tag, after '#'.
+// Types cannot contain ':', '@', or '#'. Reported usage ends the line, after
+// " | ", as in "completed gt-xyz | tokens=12000/3400 cost=$0.42", followed
+// by the archived transcript, as in " | transcript=logs/transcripts/...".
 func formatLogLine(e Event) string {
 	ts := e.Timestamp.Format("2006-01-02 15:04:05")
+	tag := string(e.Type)
+	if e.Severity != "" && e.Severity != DefaultSeverity(e.Type) {
+		tag += ":" + string(e.Severity)
+	}
+	if e.SessionID != "" {
+		tag += "@" + e.SessionID
+	}
+	if e.Prev != "" {
+		tag += "#" + e.Prev
+	}
+	line := fmt.Sprintf("%s [%s] %s %s", ts, tag, e.Agent, describe(e))
+	if !e.Usage.IsZero() {
+		line += usageSep + e.Usage.String()
+	}
+	if e.Transcript != "" {
+		line += usageSep + transcriptField + e.Transcript
+	}
+	return line
+}
 
+// describe returns what happened in an event, as the log line says it
+// after the agent: "exited unexpectedly (exit 1)".
+func describe(e Event) string {
 	var detail string
 	switch e.Type {
 	case EventSpawn:
@@ -158,6 +522,10 @@ func formatLogLine(e Event) string {
 		} else {
 			detail = "callback processed"
 		}
+	case EventMark:
+		detail = fmt.Sprintf("marked %q", e.Context)
+	case EventNote:
+		detail = fmt.Sprintf("noted %q", e.Context)
 	case EventPatrolStarted:
 		if e.Context != "" {
 			detail = fmt.Sprintf("started patrol (%s)", e.Context)
@@ -194,8 +562,7 @@ func formatLogLine(e Event) string {
 			detail += fmt.Sprintf(" (%s)", e.Context)
 		}
 	}
-
-	return fmt.Sprintf("%s [%s] %s %s", ts, e.Type, e.Agent, detail)
+	return detail
 }
 
 // truncate shortens a string to max length with ellipsis.
@@ -206,9 +573,12 @@ func truncate(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
-// ReadEvents reads all events from the log file.
+// ReadEvents reads all events from the log file (or the town's store).
 // Useful for filtering and analysis.
 func ReadEvents(townRoot string) ([]Event, error) {
+	if s := openBackend(townRoot); s != nil {
+		return s.query(Filter{})
+	}
 	path := logPath(townRoot)
 
 	content, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted townRoot
@@ -251,7 +621,8 @@ func parseLogLine(line string) (Event, error) {
 	if len(line) < 19 {
 		return event, fmt.Errorf("line too short")
 	}
-	ts, err := time.Parse("2006-01-02 15:04:05", line[:19])
+	// Lines are written in local time without a zone.
+	ts, err := time.ParseInLocation("2006-01-02 15:04:05", line[:19], time.Local)
 	if err != nil {
 		return event, fmt.Errorf("parsing timestamp: %w", err)
 	}
@@ -274,7 +645,15 @@ func parseLogLine(line string) (Event, error) {
 		return event, fmt.Errorf("unclosed bracket")
 	}
 
-	event.Type = EventType(rest[1:closeBracket])
+	tag, prev, _ := strings.Cut(rest[1:closeBracket], "#")
+	event.Prev = prev
+	tag, session, _ := strings.Cut(tag, "@")
+	event.SessionID = session
+	tag, sev, hasSev := strings.Cut(tag, ":")
+	event.Type = EventType(tag)
+	if hasSev {
+		event.Severity = Severity(sev)
+	}
 
 	// Rest is " agent details"
 	rest = rest[closeBracket+1:]
@@ -295,12 +674,106 @@ func parseLogLine(line string) (Event, error) {
 		event.Agent = rest
 	} else {
 		event.Agent = rest[:spaceIdx]
-		// The rest is context info (not worth parsing further)
+		detail, transcript := splitTranscript(rest[spaceIdx+1:])
+		detail, usage := splitUsage(detail)
+		event.Context = contextFromDetail(event.Type, detail)
+		event.Usage = usage
+		event.Transcript = transcript
 	}
 
 	return event, nil
 }
 
+// detailTemplates describes how formatLogLine renders each event's context,
+// so parseLogLine can recover it: prefix + context + suffix, or bare when
+// the context is empty.
+var detailTemplates = map[EventType]struct{ prefix, suffix, bare string }{
+	EventSpawn:          {"spawned for ", "", "spawned"},
+	EventWake:           {"resumed (", ")", "resumed"},
+	EventNudge:          {"nudged with ", "", "nudged"},
+	EventHandoff:        {"handed off (", ")", "handed off"},
+	EventDone:           {"completed ", "", "completed work"},
+	EventCrash:          {"exited unexpectedly (", ")", "exited unexpectedly"},
+	EventKill:           {"killed (", ")", "killed"},
+	EventCallback:       {"callback: ", "", "callback processed"},
+	EventMark:           {"marked ", "", "marked"},
+	EventNote:           {"noted ", "", "noted"},
+	EventPatrolStarted:  {"started patrol (", ")", "started patrol"},
+	EventPolecatChecked: {"checked polecat ", "", "checked polecat"},
+	EventPolecatNudged:  {"nudged polecat (", ")", "nudged polecat"},
+	EventEscalationSent: {"escalated (", ")", "escalated"},
+	EventPatrolComplete: {"patrol complete (", ")", "patrol complete"},
+}
+
+// contextFromDetail inverts formatLogLine's detail text back to the event
+// context. Nudge messages are stored truncated, so their context may end
+// in "...". Nudge, mark, and note contexts are quoted.
+func contextFromDetail(eventType EventType, detail string) string {
+	tmpl, ok := detailTemplates[eventType]
+	if !ok {
+		tmpl.prefix, tmpl.suffix, tmpl.bare = string(eventType)+" (", ")", string(eventType)
+	}
+	if detail == tmpl.bare {
+		return ""
+	}
+	if !strings.HasPrefix(detail, tmpl.prefix) || !strings.HasSuffix(detail, tmpl.suffix) ||
+		len(detail) < len(tmpl.prefix)+len(tmpl.suffix) {
+		return detail
+	}
+	ctx := detail[len(tmpl.prefix) : len(detail)-len(tmpl.suffix)]
+	if eventType == EventNudge || eventType == EventMark || eventType == EventNote {
+		if unquoted, err := strconv.Unquote(ctx); err == nil {
+			return unquoted
+		}
+	}
+	return ctx
+}
+
+// ID returns a short stable identifier for an event, derived from its
+// content. Identical events logged in the same second share an ID.
+func (e Event) ID() string {
+	sum := sha1.Sum([]byte(e.Timestamp.Format("2006-01-02 15:04:05") + "\x00" +
+		string(e.Type) + "\x00" + e.Agent + "\x00" + e.Context)) //nolint:gosec // G401: not security sensitive
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+// FindEvent returns the index of the event whose ID starts with idPrefix.
+// When several events share the prefix, the most recent one wins.
+func FindEvent(events []Event, idPrefix string) (int, error) {
+	idPrefix = strings.ToLower(strings.TrimSpace(idPrefix))
+	if idPrefix == "" {
+		return -1, fmt.Errorf("empty event ID")
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		if strings.HasPrefix(events[i].ID(), idPrefix) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("no event with ID %q", idPrefix)
+}
+
+// FindMark returns the mark named name, or whose event ID starts with name.
+// Mark names may be reused; the most recent mark wins.
+func FindMark(events []Event, name string) (Event, error) {
+	var byID *Event
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		if e.Type != EventMark {
+			continue
+		}
+		if e.Context == name {
+			return e, nil
+		}
+		if byID == nil && len(name) >= 4 && strings.HasPrefix(e.ID(), strings.ToLower(name)) {
+			byID = &events[i]
+		}
+	}
+	if byID != nil {
+		return *byID, nil
+	}
+	return Event{}, fmt.Errorf("no mark named %q (see 'gt mark' for the list)", name)
+}
+
 func splitLines(s string) []string {
 	var lines []string
 	start := 0
@@ -318,6 +791,9 @@ func splitLines(s string) []string {
 
 // TailEvents returns the last n events from the log.
 func TailEvents(townRoot string, n int) ([]Event, error) {
+	if s := openBackend(townRoot); s != nil {
+		return s.tail(n)
+	}
 	events, err := ReadEvents(townRoot)
 	if err != nil {
 		return nil, err
@@ -330,25 +806,150 @@ func TailEvents(townRoot string, n int) ([]Event, error) {
 
 // FilterEvents returns events matching the filter criteria.
 type Filter struct {
-	Type  EventType // Filter by event type (empty for all)
-	Agent string    // Filter by agent prefix (empty for all)
-	Since time.Time // Filter by time (zero for all)
+	Type  EventType   // Filter by event type (empty for all)
+	Types []EventType // Keep events of any of these types (empty for all)
+	Agent string      // Filter by agent prefix or glob (empty for all); see MatchAgent
+	Since time.Time   // Filter by time (zero for all)
+	Until time.Time   // Exclude events after this time (zero for all)
+
+	Pattern      *regexp.Regexp  // Keep events whose context matches (nil for all)
+	AgentPattern *regexp.Regexp  // Keep events whose agent matches (nil for all)
+	AgentKeys    map[string]bool // Keep events of these agents, by AgentKey (nil for all)
+
+	MinSeverity Severity // Keep events at least this severe (empty for all)
+	Session     string   // Keep events whose session ID starts with this (empty for all)
+}
+
+// Match reports whether an event passes the filter.
+func (f Filter) Match(e Event) bool {
+	if f.Type != "" && e.Type != f.Type {
+		return false
+	}
+	if len(f.Types) > 0 && !containsType(f.Types, e.Type) {
+		return false
+	}
+	if f.Agent != "" && !MatchAgent(f.Agent, e.Agent) {
+		return false
+	}
+	if f.AgentPattern != nil && !f.AgentPattern.MatchString(e.Agent) {
+		return false
+	}
+	if f.AgentKeys != nil && !f.AgentKeys[AgentKey(e.Agent)] {
+		return false
+	}
+	if !f.Since.IsZero() && e.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Timestamp.After(f.Until) {
+		return false
+	}
+	if f.Pattern != nil && !f.Pattern.MatchString(e.Context) {
+		return false
+	}
+	if f.MinSeverity != "" && e.Level().Rank() < f.MinSeverity.Rank() {
+		return false
+	}
+	if f.Session != "" && !hasPrefix(e.SessionID, f.Session) {
+		return false
+	}
+	return true
+}
+
+// Empty reports whether the filter lets every event through.
+func (f Filter) Empty() bool {
+	return f.Type == "" && len(f.Types) == 0 && f.Agent == "" &&
+		f.Since.IsZero() && f.Until.IsZero() && f.Pattern == nil && f.AgentPattern == nil &&
+		f.AgentKeys == nil && f.MinSeverity == "" && f.Session == ""
+}
+
+// MatchAgent reports whether agent matches an agent filter. A filter
+// without glob characters is a prefix ("gastown/" matches the whole rig).
+// A glob such as "gastown/crew/*" or "*/witness" is matched with
+// path.Match against the agent or any of its leading path segments, so
+// "gastown/*" matches "gastown/crew/max" too. An invalid glob matches
+// nothing (see ValidateAgentGlob).
+func MatchAgent(filter, agent string) bool {
+	if !isAgentGlob(filter) {
+		return hasPrefix(agent, filter)
+	}
+	for i := 0; i <= len(agent); i++ {
+		if i < len(agent) && agent[i] != '/' {
+			continue
+		}
+		if ok, _ := path.Match(filter, agent[:i]); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAgentGlob reports a malformed glob in an agent filter.
+func ValidateAgentGlob(filter string) error {
+	if _, err := path.Match(filter, ""); err != nil {
+		return fmt.Errorf("invalid agent pattern %q: %w", filter, err)
+	}
+	return nil
+}
+
+// agentGlobPrefix returns the literal part of an agent filter before its
+// first glob character.
+func agentGlobPrefix(filter string) string {
+	if i := strings.IndexAny(filter, globChars); i >= 0 {
+		return filter[:i]
+	}
+	return filter
+}
+
+const globChars = "*?[\\"
+
+func isAgentGlob(filter string) bool {
+	return strings.ContainsAny(filter, globChars)
+}
+
+func containsType(types []EventType, t EventType) bool {
+	for _, want := range types {
+		if want == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTypes parses event types from a comma-separated list such as
+// "crash,kill,done". Blank entries are ignored.
+func ParseTypes(list ...string) []EventType {
+	var types []EventType
+	for _, item := range list {
+		for _, t := range strings.Split(item, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types = append(types, EventType(t))
+			}
+		}
+	}
+	return types
+}
+
+// QueryEvents returns the events in the log matching f. With the SQLite
+// store the filter runs against its indexes instead of a full read; with
+// segments, those outside the filter's time range are skipped.
+func QueryEvents(townRoot string, f Filter) ([]Event, error) {
+	if s := openBackend(townRoot); s != nil {
+		return s.query(f)
+	}
+	events, err := ReadEvents(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return FilterEvents(events, f), nil
 }
 
 // FilterEvents applies a filter to events.
 func FilterEvents(events []Event, f Filter) []Event {
 	var result []Event
 	for _, e := range events {
-		if f.Type != "" && e.Type != f.Type {
-			continue
-		}
-		if f.Agent != "" && !hasPrefix(e.Agent, f.Agent) {
-			continue
-		}
-		if !f.Since.IsZero() && e.Timestamp.Before(f.Since) {
-			continue
+		if f.Match(e) {
+			result = append(result, e)
 		}
-		result = append(result, e)
 	}
 	return result
 }
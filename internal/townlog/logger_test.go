@@ -1,8 +1,10 @@
 package townlog
 
 import (
+	"compress/gzip"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -156,14 +158,144 @@ func TestLoggerLogEvent(t *testing.T) {
 		t.Fatalf("reading log file: %v", err)
 	}
 
-	if !strings.Contains(string(content), "[spawn]") {
-		t.Errorf("log file should contain [spawn], got: %s", content)
+	// A spawn opens a session, so its ID follows the type.
+	if !strings.Contains(string(content), "[spawn@") {
+		t.Errorf("log file should contain [spawn@<session>], got: %s", content)
 	}
 	if !strings.Contains(string(content), "gastown/crew/max") {
 		t.Errorf("log file should contain agent name, got: %s", content)
 	}
 }
 
+func TestValidateEventType(t *testing.T) {
+	valid := []EventType{"deploy", "bench.done", "ci-run", "step_2", EventSpawn}
+	for _, et := range valid {
+		if err := ValidateEventType(et); err != nil {
+			t.Errorf("ValidateEventType(%q) = %v, want nil", et, err)
+		}
+	}
+	invalid := []EventType{"", "Deploy", "2fast", ".hidden", "has space", "a/b", EventType(strings.Repeat("x", maxEventTypeLen+1))}
+	for _, et := range invalid {
+		if err := ValidateEventType(et); err == nil {
+			t.Errorf("ValidateEventType(%q) = nil, want error", et)
+		}
+	}
+}
+
+func TestLoggerEmit(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := NewLogger(tmpDir)
+
+	if err := logger.Emit("deploy", "gastown/crew/max", "v1.4.2\n  to staging"); err != nil {
+		t.Fatalf("Emit() error: %v", err)
+	}
+	if err := logger.Emit("Bad Type", "gastown/crew/max", ""); err == nil {
+		t.Error("Emit() with invalid type should fail")
+	}
+	if err := logger.Emit("deploy", "two words", ""); err == nil {
+		t.Error("Emit() with whitespace in agent should fail")
+	}
+
+	events, err := ReadEvents(tmpDir)
+	if err != nil {
+		t.Fatalf("ReadEvents() error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	e := events[0]
+	if e.Type != "deploy" || e.Type.IsBuiltin() {
+		t.Errorf("Type = %q (builtin %v), want custom deploy", e.Type, e.Type.IsBuiltin())
+	}
+	if e.Agent != "gastown/crew/max" {
+		t.Errorf("Agent = %q, want gastown/crew/max", e.Agent)
+	}
+	if e.Context != "v1.4.2 to staging" {
+		t.Errorf("Context = %q, want folded %q", e.Context, "v1.4.2 to staging")
+	}
+}
+
+func TestAgentLog(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := NewLogger(tmpDir)
+	_ = logger.Log(EventSpawn, "gastown/crew/max", "gt-xyz")
+	_ = logger.Log(EventSpawn, "gastown/polecats/Toast", "gt-abc")
+	_ = logger.Log(EventDone, "gastown/Toast", "gt-abc")
+	_ = logger.Log(EventNudge, "gastown/crew/max", "check mail")
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "logs", "agents", "gastown", "crew", "max.log")); err != nil {
+		t.Fatalf("agent log not written: %v", err)
+	}
+	events, err := ReadAgentEvents(tmpDir, "gastown/crew/max")
+	if err != nil {
+		t.Fatalf("ReadAgentEvents() error: %v", err)
+	}
+	if len(events) != 2 || events[1].Type != EventNudge {
+		t.Errorf("crew events = %+v, want spawn and nudge", events)
+	}
+	// Both spellings of a polecat share one log.
+	events, _ = ReadAgentEvents(tmpDir, "gastown/polecats/Toast")
+	if len(events) != 2 {
+		t.Errorf("polecat events = %d, want 2", len(events))
+	}
+
+	// An agent without its own log is found in the town log.
+	if err := os.RemoveAll(filepath.Join(tmpDir, "logs", "agents")); err != nil {
+		t.Fatal(err)
+	}
+	events, _ = ReadAgentEvents(tmpDir, "gastown/Toast")
+	if len(events) != 2 || events[0].Type != EventSpawn {
+		t.Errorf("fallback events = %+v, want spawn and done", events)
+	}
+
+	if got := AgentLogPath(tmpDir, "../../etc"); !strings.HasPrefix(got, filepath.Join(tmpDir, "logs", "agents")) {
+		t.Errorf("AgentLogPath escaped the log dir: %s", got)
+	}
+}
+
+func TestEventSeverity(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := NewLogger(tmpDir)
+
+	_ = logger.Log(EventSpawn, "gastown/polecats/Toast", "gt-1")
+	_ = logger.Log(EventKill, "gastown/polecats/Toast", "stuck")
+	_ = logger.Log(EventCrash, "gastown/witness", "exit 1")
+	if err := logger.EmitEvent(Event{Type: "deploy", Agent: "ci", Context: "v2 failed", Severity: "ERROR"}); err != nil {
+		t.Fatalf("EmitEvent() error: %v", err)
+	}
+	if err := logger.EmitEvent(Event{Type: EventCrash, Agent: "ci", Severity: SeverityError}); err != nil {
+		t.Fatalf("EmitEvent() with default severity error: %v", err)
+	}
+	if err := logger.EmitEvent(Event{Type: "deploy", Agent: "ci", Severity: "fatal"}); err == nil {
+		t.Error("EmitEvent() with unknown severity should fail")
+	}
+
+	events, err := ReadEvents(tmpDir)
+	if err != nil || len(events) != 5 {
+		t.Fatalf("ReadEvents() = %d events, %v; want 5", len(events), err)
+	}
+	want := []Severity{SeverityInfo, SeverityWarn, SeverityError, SeverityError, SeverityError}
+	for i, e := range events {
+		if e.Level() != want[i] {
+			t.Errorf("event %d (%s) Level() = %q, want %q", i, e.Type, e.Level(), want[i])
+		}
+	}
+	if events[3].Type != "deploy" || events[3].Context != "v2 failed" {
+		t.Errorf("[deploy:error] parsed as %+v", events[3])
+	}
+	if events[4].Severity != "" {
+		t.Errorf("default severity stored explicitly: %q", events[4].Severity)
+	}
+
+	got, _ := QueryEvents(tmpDir, Filter{MinSeverity: SeverityWarn})
+	if len(got) != 4 || got[0].Type != EventKill {
+		t.Errorf("MinSeverity warn = %v, want kill, crash, deploy, crash", got)
+	}
+	if (Filter{MinSeverity: SeverityWarn}).Empty() {
+		t.Error("Filter with MinSeverity reported Empty")
+	}
+}
+
 func TestFilterEvents(t *testing.T) {
 	now := time.Now()
 	events := []Event{
@@ -203,6 +335,41 @@ func TestFilterEvents(t *testing.T) {
 			filter:    Filter{Type: EventSpawn, Agent: "gastown/"},
 			wantCount: 1,
 		},
+		{
+			name:      "filter by type set",
+			filter:    Filter{Types: ParseTypes("done, spawn")},
+			wantCount: 3,
+		},
+		{
+			name:      "filter by context pattern",
+			filter:    Filter{Pattern: regexp.MustCompile(`^gt-[23]$`)},
+			wantCount: 2,
+		},
+		{
+			name:      "type set with agent",
+			filter:    Filter{Types: ParseTypes("done", "nudge,"), Agent: "gastown/crew/"},
+			wantCount: 1,
+		},
+		{
+			name:      "filter by agent glob",
+			filter:    Filter{Agent: "*/crew/*"},
+			wantCount: 3,
+		},
+		{
+			name:      "filter by agent regexp",
+			filter:    Filter{AgentPattern: regexp.MustCompile(`crew/(max|bob)`)},
+			wantCount: 2,
+		},
+		{
+			name:      "filter by agent key set",
+			filter:    Filter{AgentKeys: map[string]bool{"gastown/Toast": true, "wyvern/crew/joe": true}},
+			wantCount: 2,
+		},
+		{
+			name:      "empty agent key set",
+			filter:    Filter{AgentKeys: map[string]bool{}},
+			wantCount: 0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -215,6 +382,31 @@ func TestFilterEvents(t *testing.T) {
 	}
 }
 
+func TestMatchAgent(t *testing.T) {
+	tests := []struct {
+		filter, agent string
+		want          bool
+	}{
+		{"gastown/", "gastown/crew/max", true},
+		{"gastown/crew", "gastown/crewless", true}, // plain filters stay prefixes
+		{"gastown/crew/*", "gastown/crew/max", true},
+		{"gastown/crew/*", "gastown/polecats/Toast", false},
+		{"gastown/*", "gastown/crew/max", true}, // leading segments match
+		{"*/witness", "wyvern/witness", true},
+		{"*/witness", "wyvern/witnesses", false},
+		{"gastown/polecats/T?ast", "gastown/polecats/Toast", true},
+		{"gastown/[", "gastown/crew/max", false}, // invalid glob
+	}
+	for _, tt := range tests {
+		if got := MatchAgent(tt.filter, tt.agent); got != tt.want {
+			t.Errorf("MatchAgent(%q, %q) = %v, want %v", tt.filter, tt.agent, got, tt.want)
+		}
+	}
+	if ValidateAgentGlob("gastown/[") == nil || ValidateAgentGlob("*/crew/*") != nil {
+		t.Error("ValidateAgentGlob should reject only malformed globs")
+	}
+}
+
 func TestTruncate(t *testing.T) {
 	tests := []struct {
 		input  string
@@ -235,3 +427,148 @@ func TestTruncate(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLogLineRecoversContext(t *testing.T) {
+	ts := time.Date(2025, 12, 26, 15, 30, 45, 0, time.Local)
+	events := []Event{
+		{Timestamp: ts, Type: EventSpawn, Agent: "gastown/crew/max", Context: "gt-xyz"},
+		{Timestamp: ts, Type: EventNudge, Agent: "gastown/crew/max", Context: `say "hi"`},
+		{Timestamp: ts, Type: EventKill, Agent: "gastown/polecats/Toast", Context: "gt stop (forced)"},
+		{Timestamp: ts, Type: EventDone, Agent: "gastown/polecats/Toast"},
+		{Timestamp: ts, Type: EventNote, Agent: "overseer", Context: `restarted tmux server (see "incident")`},
+		{Timestamp: ts, Type: EventType("custom"), Agent: "mayor", Context: "x"},
+	}
+	for _, want := range events {
+		got, err := parseLogLine(formatLogLine(want))
+		if err != nil {
+			t.Fatalf("parseLogLine: %v", err)
+		}
+		if got != want {
+			t.Errorf("round trip = %+v, want %+v", got, want)
+		}
+		if got.ID() != want.ID() || len(got.ID()) != 8 {
+			t.Errorf("ID not stable: %q vs %q", got.ID(), want.ID())
+		}
+	}
+
+	if i, err := FindEvent(events, events[2].ID()[:5]); err != nil || i != 2 {
+		t.Errorf("FindEvent = %d, %v", i, err)
+	}
+	if _, err := FindEvent(events, "zzzz"); err == nil {
+		t.Error("expected error for unknown ID")
+	}
+}
+
+func TestReadArchivedEvents(t *testing.T) {
+	townRoot := t.TempDir()
+	logs := filepath.Join(townRoot, "logs")
+	if err := os.MkdirAll(filepath.Join(logs, "archive"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Gzipped archive from an older month
+	gzFile, err := os.Create(filepath.Join(logs, "archive", "town-2025-11.log.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(gzFile)
+	_, _ = gz.Write([]byte("2025-11-03 09:00:00 [spawn] gastown/polecats/Toast spawned\n"))
+	_ = gz.Close()
+	_ = gzFile.Close()
+
+	// Rotated copy that overlaps the current log by one line
+	rotated := "2025-12-01 10:00:00 [done] gastown/polecats/Toast completed gt-1\n" +
+		"2025-12-02 10:00:00 [crash] gastown/polecats/Nux crashed\n"
+	if err := os.WriteFile(filepath.Join(logs, "town.log.1"), []byte(rotated), 0644); err != nil {
+		t.Fatal(err)
+	}
+	current := "2025-12-02 10:00:00 [crash] gastown/polecats/Nux crashed\n" +
+		"2026-01-05 08:00:00 [handoff] gastown/crew/max handed off\n"
+	if err := os.WriteFile(filepath.Join(logs, "town.log"), []byte(current), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := ReadArchivedEvents(townRoot)
+	if err != nil {
+		t.Fatalf("ReadArchivedEvents: %v", err)
+	}
+	var types []EventType
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	want := []EventType{EventSpawn, EventDone, EventCrash, EventHandoff}
+	if len(types) != len(want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("events = %v, want %v", types, want)
+			break
+		}
+	}
+}
+
+func TestFindMark(t *testing.T) {
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	var events []Event
+	for i, name := range []string{"before the big refactor", "release", "before the big refactor"} {
+		line := formatLogLine(Event{Timestamp: base.Add(time.Duration(i) * time.Hour), Type: EventMark, Agent: "mayor", Context: name})
+		e, err := parseLogLine(line)
+		if err != nil {
+			t.Fatalf("parseLogLine(%q): %v", line, err)
+		}
+		events = append(events, e, Event{Timestamp: e.Timestamp, Type: EventSpawn, Agent: "gastown/polecats/Toast"})
+	}
+
+	mark, err := FindMark(events, "before the big refactor")
+	if err != nil {
+		t.Fatalf("FindMark: %v", err)
+	}
+	if !mark.Timestamp.Equal(base.Add(2 * time.Hour)) {
+		t.Errorf("FindMark picked %v, want the most recent mark", mark.Timestamp)
+	}
+
+	byID, err := FindMark(events, events[2].ID()[:6])
+	if err != nil || byID.Context != "release" {
+		t.Errorf("FindMark by ID = %+v, %v; want the release mark", byID, err)
+	}
+
+	if _, err := FindMark(events, "nope"); err == nil {
+		t.Error("FindMark of unknown name should fail")
+	}
+
+	ranged := FilterEvents(events, Filter{Type: EventSpawn, Since: base, Until: base.Add(time.Hour)})
+	if len(ranged) != 2 {
+		t.Errorf("FilterEvents between marks = %d events, want 2", len(ranged))
+	}
+}
+
+func TestLoggerImport(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := NewLogger(tmpDir)
+	_ = logger.Log(EventSpawn, "gastown/Toast", "gt-1")
+
+	ts := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	batch := []Event{
+		{Timestamp: ts.Add(time.Minute), Type: "deploy", Agent: "ci/bot", Context: "v2"},
+		{Timestamp: ts, Type: "deploy", Agent: "ci/bot", Context: "v1"},
+		{Timestamp: ts, Type: "deploy", Agent: "ci/bot", Context: "v1"}, // repeated
+	}
+	if n, err := logger.Import(batch); err != nil || n != 2 {
+		t.Fatalf("Import() = %d, %v; want 2", n, err)
+	}
+	if n, err := logger.Import(batch); err != nil || n != 0 {
+		t.Errorf("second Import() = %d, %v; want 0", n, err)
+	}
+	if _, err := logger.Import([]Event{{Type: "Bad", Agent: "x"}}); err == nil {
+		t.Error("Import() of an invalid event should fail")
+	}
+
+	events, _ := ReadEvents(tmpDir)
+	if len(events) != 3 || events[1].Context != "v1" || events[2].Context != "v2" {
+		t.Fatalf("events = %+v, want spawn then v1, v2", events)
+	}
+	if !events[1].Timestamp.Equal(ts) || events[1].SessionID != "" {
+		t.Errorf("imported event = %+v, want its timestamp and no session", events[1])
+	}
+}
@@ -0,0 +1,657 @@
+package townlog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// The segment backend keeps events in logs/segments/ as length-prefixed
+// binary records. It is for high-volume towns that outgrow town.log and
+// cannot rely on sqlite3: appends are one write, and reads decode records
+// without parsing text. Like the SQLite store it is optional; a town
+// switches to it with CreateSegments (gt log migrate --to segments), and
+// every reader and writer in this package then uses it.
+//
+// Events are appended to the active segment, NNNNNN.seg. Once it reaches
+// segmentMaxBytes it is sealed: gzipped to NNNNNN-<min>-<max>.seg.gz,
+// where min and max are the Unix seconds of its oldest and newest events,
+// so reads bounded by Since or Until skip it without opening it.
+//
+// A segment is segmentMagic followed by records:
+//
+//	uvarint  payload length
+//	payload  varint timestamp (Unix ns), then uvarint-length-prefixed
+//	         type, agent, context, severity, session ID, and Prev
+//	uint32   CRC-32 (IEEE) of the payload, little-endian
+//
+// Readers ignore payload bytes after the fields they know, so fields can
+// be added without a new format.
+
+// segmentMagic starts every segment file.
+const segmentMagic = "GTSEG01\n"
+
+// segmentMaxBytes is the size at which the active segment is sealed.
+// Tests lower it.
+var segmentMaxBytes int64 = 8 << 20
+
+// errTornRecord means data ends partway through a record.
+var errTornRecord = errors.New("torn record")
+
+// segmentDir returns the directory of a town's log segments.
+func segmentDir(townRoot string) string {
+	return filepath.Join(logDir(townRoot), "segments")
+}
+
+// segmentLog is the segment store of one town.
+type segmentLog struct {
+	dir string
+}
+
+// openSegments returns the town's segment store, or nil if it has none.
+func openSegments(townRoot string) *segmentLog {
+	dir := segmentDir(townRoot)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil
+	}
+	return &segmentLog{dir: dir}
+}
+
+// UsesSegments reports whether the town's events are kept in log segments.
+func UsesSegments(townRoot string) bool {
+	return openSegments(townRoot) != nil
+}
+
+// CreateSegments creates the town's segment store. Once it exists, the
+// town logs to it, in preference to SQLite and town.log.
+func CreateSegments(townRoot string) error {
+	if err := os.MkdirAll(segmentDir(townRoot), 0700); err != nil {
+		return fmt.Errorf("creating segment directory: %w", err)
+	}
+	return nil
+}
+
+// ImportSegments appends every event in a flat-file log (gzipped or not)
+// to the town's segments and returns how many lines it parsed. Events
+// already in the segments are skipped, so importing twice is harmless.
+func ImportSegments(townRoot, path string) (int, error) {
+	content, err := readLogFile(path)
+	if err != nil {
+		return 0, err
+	}
+	events, _ := ParseLogLines(content)
+	return len(events), AppendSegments(townRoot, events)
+}
+
+// AppendSegments adds events to the town's segments, skipping those
+// already stored.
+func AppendSegments(townRoot string, events []Event) error {
+	l := openSegments(townRoot)
+	if l == nil {
+		return errors.New("town has no log segments")
+	}
+	stored, err := l.query(Filter{})
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(stored))
+	for _, e := range stored {
+		seen[segmentKey(e)] = true
+	}
+	var fresh []Event
+	for _, e := range events {
+		if k := segmentKey(e); !seen[k] {
+			seen[k] = true
+			fresh = append(fresh, e)
+		}
+	}
+	return l.insert(fresh...)
+}
+
+// segmentKey identifies an event for import de-duplication, as the
+// SQLite store's unique index does.
+func segmentKey(e Event) string {
+	return strconv.FormatInt(e.Timestamp.UnixNano(), 10) + "\x00" + string(e.Type) + "\x00" + e.Agent + "\x00" + e.Context
+}
+
+// CountSegmentEvents returns how many events the town's segments hold.
+func CountSegmentEvents(townRoot string) (int, error) {
+	l := openSegments(townRoot)
+	if l == nil {
+		return 0, errors.New("town has no log segments")
+	}
+	events, err := l.query(Filter{})
+	return len(events), err
+}
+
+// segmentFile is one segment on disk.
+type segmentFile struct {
+	seq      int
+	path     string
+	sealed   bool
+	min, max int64 // Unix seconds of the oldest and newest event; sealed only
+}
+
+// parseSegmentName parses NNNNNN.seg and NNNNNN-<min>-<max>.seg.gz.
+func parseSegmentName(name string) (segmentFile, bool) {
+	var f segmentFile
+	base, sealed := strings.CutSuffix(name, ".seg.gz")
+	if !sealed {
+		var ok bool
+		if base, ok = strings.CutSuffix(name, ".seg"); !ok {
+			return f, false
+		}
+	}
+	parts := strings.Split(base, "-")
+	var err error
+	if f.seq, err = strconv.Atoi(parts[0]); err != nil || f.seq <= 0 {
+		return f, false
+	}
+	f.sealed = sealed
+	switch {
+	case !sealed && len(parts) == 1:
+	case sealed && len(parts) == 3:
+		min, err1 := strconv.ParseInt(parts[1], 10, 64)
+		max, err2 := strconv.ParseInt(parts[2], 10, 64)
+		if err1 != nil || err2 != nil {
+			return f, false
+		}
+		f.min, f.max = min, max
+	default:
+		return f, false
+	}
+	return f, true
+}
+
+// activeName and sealedName name a segment's files.
+func activeName(seq int) string { return fmt.Sprintf("%06d.seg", seq) }
+func sealedName(seq int, min, max int64) string {
+	return fmt.Sprintf("%06d-%d-%d.seg.gz", seq, min, max)
+}
+
+// list returns the segments in order. An active segment whose sealed copy
+// also exists (sealing was interrupted) is left out.
+func (l *segmentLog) list() ([]segmentFile, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, fmt.Errorf("listing segments: %w", err)
+	}
+	bySeq := map[int]segmentFile{}
+	for _, e := range entries {
+		f, ok := parseSegmentName(e.Name())
+		if !ok {
+			continue
+		}
+		f.path = filepath.Join(l.dir, e.Name())
+		if prev, dup := bySeq[f.seq]; !dup || (f.sealed && !prev.sealed) {
+			bySeq[f.seq] = f
+		}
+	}
+	files := make([]segmentFile, 0, len(bySeq))
+	for _, f := range bySeq {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].seq < files[j].seq })
+	return files, nil
+}
+
+// appendRecord encodes e onto b.
+func appendRecord(b []byte, e Event) []byte {
+	var payload []byte
+	payload = binary.AppendVarint(payload, e.Timestamp.UnixNano())
+	for _, s := range []string{string(e.Type), e.Agent, e.Context, string(e.Severity), e.SessionID, e.Prev, e.Usage.String(), e.Transcript} {
+		payload = binary.AppendUvarint(payload, uint64(len(s)))
+		payload = append(payload, s...)
+	}
+	b = binary.AppendUvarint(b, uint64(len(payload)))
+	b = append(b, payload...)
+	return binary.LittleEndian.AppendUint32(b, crc32.ChecksumIEEE(payload))
+}
+
+// decodeRecords calls fn for each record in data, stopping early if fn
+// returns false, and returns how many bytes of whole records it read. It
+// returns errTornRecord if data ends inside a record, and another error
+// if a record is corrupt.
+func decodeRecords(data []byte, fn func(Event) bool) (int, error) {
+	off := 0
+	for off < len(data) {
+		n, w := binary.Uvarint(data[off:])
+		if w == 0 {
+			return off, errTornRecord
+		}
+		if w < 0 || n > uint64(len(data)) {
+			return off, fmt.Errorf("corrupt segment record at byte %d", off)
+		}
+		end := off + w + int(n) + 4
+		if end > len(data) {
+			return off, errTornRecord
+		}
+		payload := data[off+w : end-4]
+		if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(data[end-4:end]) {
+			return off, fmt.Errorf("corrupt segment record at byte %d: checksum mismatch", off)
+		}
+		e, err := decodeRecord(payload)
+		if err != nil {
+			return off, fmt.Errorf("corrupt segment record at byte %d: %w", off, err)
+		}
+		off = end
+		if !fn(e) {
+			break
+		}
+	}
+	return off, nil
+}
+
+func decodeRecord(p []byte) (Event, error) {
+	var e Event
+	ts, w := binary.Varint(p)
+	if w <= 0 {
+		return e, errors.New("bad timestamp")
+	}
+	e.Timestamp = time.Unix(0, ts)
+	p = p[w:]
+	var usage string
+	fields := []*string{(*string)(&e.Type), &e.Agent, &e.Context, (*string)(&e.Severity), &e.SessionID, &e.Prev, &usage, &e.Transcript}
+	for _, field := range fields {
+		if len(p) == 0 {
+			break // written before this field existed
+		}
+		n, w := binary.Uvarint(p)
+		if w <= 0 || n > uint64(len(p)-w) {
+			return e, errors.New("bad field length")
+		}
+		*field = string(p[w : w+int(n)])
+		p = p[w+int(n):]
+	}
+	u, err := ParseUsage(usage)
+	if err != nil {
+		return e, err
+	}
+	e.Usage = u
+	return e, nil
+}
+
+// insert appends events to the active segment in one write, and seals
+// the segment if it has grown past segmentMaxBytes.
+func (l *segmentLog) insert(events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return util.WithFileLock(l.lockPath(), func() error {
+		files, err := l.list()
+		if err != nil {
+			return err
+		}
+		seq := 1
+		if len(files) > 0 {
+			last := files[len(files)-1]
+			seq = last.seq
+			if last.sealed {
+				seq++
+			}
+		}
+		path := filepath.Join(l.dir, activeName(seq))
+
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0600) //nolint:gosec // G304: path is within the town
+		if err != nil {
+			return fmt.Errorf("opening segment: %w", err)
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("opening segment: %w", err)
+		}
+		size := info.Size()
+		if good, ok := readLockedSize(l.lockPath(), seq); ok && good < size {
+			// The last append did not finish; drop its partial record.
+			if err := f.Truncate(good); err != nil {
+				return fmt.Errorf("repairing segment: %w", err)
+			}
+			size = good
+		}
+
+		var buf []byte
+		if size == 0 {
+			buf = append(buf, segmentMagic...)
+		}
+		for _, e := range events {
+			buf = appendRecord(buf, e)
+		}
+		if _, err := f.WriteAt(buf, size); err != nil {
+			return fmt.Errorf("writing segment: %w", err)
+		}
+		size += int64(len(buf))
+		writeLockedSize(l.lockPath(), seq, size)
+
+		if size >= segmentMaxBytes {
+			return l.seal(segmentFile{seq: seq, path: path})
+		}
+		return nil
+	})
+}
+
+// lockPath is the store's write lock. The lock file also records the size
+// of the active segment after the last complete append, so that a torn
+// append (a writer killed mid-write) can be cut off by the next writer.
+func (l *segmentLog) lockPath() string {
+	return filepath.Join(l.dir, ".lock")
+}
+
+// readLockedSize returns the active segment size recorded in the lock file.
+func readLockedSize(path string, seq int) (int64, bool) {
+	data, _ := os.ReadFile(path) //nolint:gosec // G304: path is within the town
+	var gotSeq int
+	var size int64
+	if _, err := fmt.Sscanf(string(data), "%d %d", &gotSeq, &size); err != nil || gotSeq != seq {
+		return 0, false
+	}
+	return size, true
+}
+
+func writeLockedSize(path string, seq int, size int64) {
+	_ = os.WriteFile(path, []byte(fmt.Sprintf("%d %d\n", seq, size)), 0600)
+}
+
+// seal compresses an active segment into its sealed, time-named form.
+func (l *segmentLog) seal(f segmentFile) error {
+	data, err := l.read(f)
+	if err != nil {
+		return err
+	}
+	min, max := int64(0), int64(0)
+	n, err := decodeRecords(data[len(segmentMagic):], func(e Event) bool {
+		ts := e.Timestamp.Unix()
+		if min == 0 || ts < min {
+			min = ts
+		}
+		if ts > max {
+			max = ts
+		}
+		return true
+	})
+	if err != nil && !errors.Is(err, errTornRecord) {
+		return fmt.Errorf("sealing %s: %w", filepath.Base(f.path), err)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data[:len(segmentMagic)+n]); err != nil {
+		return fmt.Errorf("compressing segment: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("compressing segment: %w", err)
+	}
+	if err := util.AtomicWriteFile(filepath.Join(l.dir, sealedName(f.seq, min, max)), buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing sealed segment: %w", err)
+	}
+	return os.Remove(f.path)
+}
+
+// read returns a segment's records, decompressed, starting with the magic.
+func (l *segmentLog) read(f segmentFile) ([]byte, error) {
+	data, err := l.readFrom(f, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 && !bytes.HasPrefix(data, []byte(segmentMagic)) {
+		return nil, fmt.Errorf("%s is not a log segment", filepath.Base(f.path))
+	}
+	if len(data) == 0 {
+		data = []byte(segmentMagic) // created but not yet written
+	}
+	return data, nil
+}
+
+// readFrom returns a segment's decompressed bytes from off on.
+func (l *segmentLog) readFrom(f segmentFile, off int64) ([]byte, error) {
+	file, err := os.Open(f.path) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if !f.sealed {
+		if _, err := file.Seek(off, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("reading segment: %w", err)
+		}
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading segment: %w", err)
+		}
+		return data, nil
+	}
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading segment %s: %w", filepath.Base(f.path), err)
+	}
+	defer gz.Close()
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("reading segment %s: %w", filepath.Base(f.path), err)
+	}
+	if off > int64(len(data)) {
+		off = int64(len(data))
+	}
+	return data[off:], nil
+}
+
+// events decodes a segment's events. A torn record at the end, from an
+// append still in progress or one that died, ends the segment.
+func (l *segmentLog) events(f segmentFile, fn func(Event) bool) error {
+	data, err := l.read(f)
+	if err != nil {
+		return err
+	}
+	_, err = decodeRecords(data[len(segmentMagic):], fn)
+	if errors.Is(err, errTornRecord) {
+		return nil
+	}
+	return err
+}
+
+// scan calls fn for the events of each segment that may hold events in
+// f's time range, oldest segment first. A segment sealed while the scan
+// runs is picked up under its new name.
+func (l *segmentLog) scan(f Filter, reverse bool, fn func(Event) bool) error {
+	files, err := l.list()
+	if err != nil {
+		return err
+	}
+	if reverse {
+		for i, j := 0, len(files)-1; i < j; i, j = i+1, j-1 {
+			files[i], files[j] = files[j], files[i]
+		}
+	}
+	for _, file := range files {
+		if file.sealed && ((!f.Since.IsZero() && file.max < f.Since.Unix()) ||
+			(!f.Until.IsZero() && file.min > f.Until.Unix())) {
+			continue
+		}
+		more := true
+		err := l.events(file, func(e Event) bool {
+			more = fn(e)
+			return more
+		})
+		if os.IsNotExist(err) {
+			// Sealed since it was listed.
+			if file, err = l.find(file.seq); err == nil {
+				err = l.events(file, func(e Event) bool {
+					more = fn(e)
+					return more
+				})
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if !more {
+			break
+		}
+	}
+	return nil
+}
+
+// find returns the current file of segment seq.
+func (l *segmentLog) find(seq int) (segmentFile, error) {
+	files, err := l.list()
+	if err != nil {
+		return segmentFile{}, err
+	}
+	for _, f := range files {
+		if f.seq == seq {
+			return f, nil
+		}
+	}
+	return segmentFile{}, os.ErrNotExist
+}
+
+// query returns the events matching f, oldest first.
+func (l *segmentLog) query(f Filter) ([]Event, error) {
+	var events []Event
+	err := l.scan(f, false, func(e Event) bool {
+		if f.Match(e) {
+			events = append(events, e)
+		}
+		return true
+	})
+	return events, err
+}
+
+// tail returns the last n events, oldest first, reading only as many
+// segments from the newest back as it needs.
+func (l *segmentLog) tail(n int) ([]Event, error) {
+	files, err := l.list()
+	if err != nil {
+		return nil, err
+	}
+	var chunks [][]Event
+	count := 0
+	for i := len(files) - 1; i >= 0 && count < n; i-- {
+		var chunk []Event
+		if err := l.events(files[i], func(e Event) bool {
+			chunk = append(chunk, e)
+			return true
+		}); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+		count += len(chunk)
+	}
+	var events []Event
+	for i := len(chunks) - 1; i >= 0; i-- {
+		events = append(events, chunks[i]...)
+	}
+	if len(events) > n {
+		events = events[len(events)-n:]
+	}
+	return events, nil
+}
+
+// segmentPos is a read position: a segment and a byte offset into its
+// decompressed contents.
+type segmentPos struct {
+	seq int
+	off int64
+}
+
+// follow is Follow for segments: it polls for records appended after the
+// last one read, moving on to newer segments as they are started.
+func (l *segmentLog) follow(ctx context.Context, opts FollowOptions, interval time.Duration, fn func(Event) error) error {
+	pos, err := l.end()
+	if err != nil {
+		return err
+	}
+
+	if opts.Backlog > 0 {
+		backlog, err := l.query(opts.Filter)
+		if err != nil {
+			return err
+		}
+		if opts.Backlog < len(backlog) {
+			backlog = backlog[len(backlog)-opts.Backlog:]
+		}
+		for _, e := range backlog {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := l.poll(&pos, opts.Filter, fn); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// end returns the position after the last record.
+func (l *segmentLog) end() (segmentPos, error) {
+	files, err := l.list()
+	if err != nil || len(files) == 0 {
+		return segmentPos{}, err
+	}
+	last := files[len(files)-1]
+	data, err := l.read(last)
+	if err != nil {
+		return segmentPos{}, err
+	}
+	n, _ := decodeRecords(data[len(segmentMagic):], func(Event) bool { return true })
+	return segmentPos{seq: last.seq, off: int64(len(segmentMagic) + n)}, nil
+}
+
+// poll delivers the matching records after pos and advances it.
+func (l *segmentLog) poll(pos *segmentPos, filter Filter, fn func(Event) error) error {
+	files, err := l.list()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.seq < pos.seq {
+			continue
+		}
+		if f.seq > pos.seq {
+			*pos = segmentPos{seq: f.seq, off: int64(len(segmentMagic))}
+		}
+		data, err := l.readFrom(f, pos.off)
+		if os.IsNotExist(err) {
+			return nil // sealed since it was listed; read it next poll
+		}
+		if err != nil {
+			return err
+		}
+		var ferr error
+		n, err := decodeRecords(data, func(e Event) bool {
+			if filter.Match(e) {
+				ferr = fn(e)
+			}
+			return ferr == nil
+		})
+		pos.off += int64(n)
+		if ferr != nil {
+			return ferr
+		}
+		if err != nil && !errors.Is(err, errTornRecord) {
+			return err
+		}
+	}
+	return nil
+}
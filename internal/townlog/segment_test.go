@@ -0,0 +1,220 @@
+package townlog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newSegmentTown returns a town on log segments whose flat-file log
+// already held two events before migration.
+func newSegmentTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	flat := NewLogger(townRoot)
+	_ = flat.Log(EventSpawn, "gastown/polecats/Toast", "gt-1")
+	_ = flat.Log(EventNudge, "mayor", "it's time")
+
+	if err := CreateSegments(townRoot); err != nil {
+		t.Fatalf("CreateSegments() error: %v", err)
+	}
+	for i := 0; i < 2; i++ { // importing twice stores each event once
+		if n, err := ImportSegments(townRoot, logPath(townRoot)); err != nil || n != 2 {
+			t.Fatalf("ImportSegments() = %d, %v; want 2", n, err)
+		}
+	}
+	return townRoot
+}
+
+func TestSegments(t *testing.T) {
+	townRoot := newSegmentTown(t)
+	if !UsesSegments(townRoot) || LogLocation(townRoot) != segmentDir(townRoot) {
+		t.Fatal("town does not use segments after CreateSegments")
+	}
+
+	before, _ := os.Stat(logPath(townRoot))
+	logger := NewLogger(townRoot)
+	_ = logger.Log(EventDone, "gastown/polecats/Toast", "gt-1 finished\nwith a newline")
+	_ = logger.LogEvent(Event{Timestamp: time.Now(), Type: EventCrash, Agent: "gastown/witness", Context: "exit 1", Severity: SeverityInfo})
+
+	after, _ := os.Stat(logPath(townRoot))
+	if after.Size() != before.Size() {
+		t.Error("town.log was written after migration")
+	}
+	if n, err := CountSegmentEvents(townRoot); err != nil || n != 4 {
+		t.Fatalf("CountSegmentEvents() = %d, %v; want 4", n, err)
+	}
+
+	all, err := ReadEvents(townRoot)
+	if err != nil || len(all) != 4 {
+		t.Fatalf("ReadEvents() = %d events, %v; want 4", len(all), err)
+	}
+	if all[1].Context != "it's time" || all[2].Context != "gt-1 finished\nwith a newline" {
+		t.Errorf("contexts not preserved: %q, %q", all[1].Context, all[2].Context)
+	}
+	if all[2].SessionID == "" || all[2].SessionID != all[0].SessionID {
+		t.Errorf("session IDs not preserved: %q, %q", all[0].SessionID, all[2].SessionID)
+	}
+	if all[3].Severity != SeverityInfo {
+		t.Errorf("severity = %q, want info", all[3].Severity)
+	}
+
+	got, err := QueryEvents(townRoot, Filter{Agent: "gastown/polecats/", Types: []EventType{EventDone, EventSpawn}})
+	if err != nil || len(got) != 2 || got[0].Type != EventSpawn || got[1].Type != EventDone {
+		t.Errorf("QueryEvents(agent, types) = %v, %v", got, err)
+	}
+
+	tail, err := TailEvents(townRoot, 2)
+	if err != nil || len(tail) != 2 || tail[0].Type != EventDone || tail[1].Type != EventCrash {
+		t.Errorf("TailEvents(2) = %v, %v", tail, err)
+	}
+}
+
+func TestSegmentsMatchTownLog(t *testing.T) {
+	flat := t.TempDir()
+	segs := t.TempDir()
+	if err := CreateSegments(segs); err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2026, 3, 1, 9, 30, 0, 0, time.Local)
+	for i, e := range []Event{
+		{Type: EventSpawn, Agent: "gastown/polecats/Toast", Context: "gt-1"},
+		{Type: EventHandoff, Agent: "gastown/polecats/Toast", Context: "context full"},
+		{Type: EventDone, Agent: "gastown/polecats/Toast", Context: "gt-1"},
+	} {
+		e.Timestamp = ts.Add(time.Duration(i) * time.Second)
+		e.SessionID = "0badcafe"
+		_ = NewLogger(flat).LogEvent(e)
+		_ = NewLogger(segs).LogEvent(e)
+	}
+
+	want, _ := ReadEvents(flat)
+	got, _ := ReadEvents(segs)
+	if len(got) != len(want) {
+		t.Fatalf("segments hold %d events, town.log %d", len(got), len(want))
+	}
+	for i := range want {
+		if formatLogLine(got[i]) != formatLogLine(want[i]) {
+			t.Errorf("line %d:\n got %q\nwant %q", i, formatLogLine(got[i]), formatLogLine(want[i]))
+		}
+	}
+}
+
+func TestSegmentSealing(t *testing.T) {
+	defer func(n int64) { segmentMaxBytes = n }(segmentMaxBytes)
+	segmentMaxBytes = 256
+
+	townRoot := t.TempDir()
+	if err := CreateSegments(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	logger := NewLogger(townRoot)
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i := 0; i < 40; i++ {
+		_ = logger.LogEvent(Event{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Type:      EventNudge,
+			Agent:     "mayor",
+			Context:   strings.Repeat("x", i),
+		})
+	}
+
+	files, err := openSegments(townRoot).list()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed := 0
+	for _, f := range files {
+		if f.sealed {
+			sealed++
+		}
+	}
+	if sealed < 2 {
+		t.Fatalf("got %d sealed segments of %d, want several", sealed, len(files))
+	}
+
+	all, err := ReadEvents(townRoot)
+	if err != nil || len(all) != 40 {
+		t.Fatalf("ReadEvents() = %d events, %v; want 40", len(all), err)
+	}
+	for i, e := range all {
+		if len(e.Context) != i {
+			t.Fatalf("event %d out of order: context length %d", i, len(e.Context))
+		}
+	}
+
+	got, _ := QueryEvents(townRoot, Filter{Since: start.Add(35 * time.Minute)})
+	if len(got) != 5 {
+		t.Errorf("QueryEvents(since) = %d events, want 5", len(got))
+	}
+	tail, _ := TailEvents(townRoot, 3)
+	if len(tail) != 3 || len(tail[2].Context) != 39 {
+		t.Errorf("TailEvents(3) = %v", tail)
+	}
+}
+
+func TestSegmentTornAppend(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := CreateSegments(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	logger := NewLogger(townRoot)
+	_ = logger.Log(EventNudge, "mayor", "one")
+
+	// A writer died partway through its record.
+	path := filepath.Join(segmentDir(townRoot), activeName(1))
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.Write(appendRecord(nil, Event{Timestamp: time.Now(), Type: EventNudge, Agent: "mayor", Context: "torn"})[:5])
+	f.Close()
+
+	if all, err := ReadEvents(townRoot); err != nil || len(all) != 1 {
+		t.Fatalf("ReadEvents() with torn tail = %v, %v; want the one event", all, err)
+	}
+
+	_ = logger.Log(EventNudge, "mayor", "two")
+	all, err := ReadEvents(townRoot)
+	if err != nil || len(all) != 2 || all[1].Context != "two" {
+		t.Fatalf("ReadEvents() after repair = %v, %v", all, err)
+	}
+}
+
+func TestSegmentFollow(t *testing.T) {
+	defer func(n int64) { segmentMaxBytes = n }(segmentMaxBytes)
+	segmentMaxBytes = 128
+
+	townRoot := newSegmentTown(t)
+	logger := NewLogger(townRoot)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var got []string
+	opts := FollowOptions{Filter: Filter{Agent: "gastown/"}, Backlog: 1, Interval: 10 * time.Millisecond}
+	err := Follow(ctx, townRoot, opts, func(e Event) error {
+		got = append(got, e.Context)
+		if len(got) == 1 {
+			// Enough to seal a segment or two while following.
+			for _, c := range []string{"gt-2", "gt-3", "gt-4"} {
+				_ = logger.Log(EventNudge, "mayor", "ignored")
+				_ = logger.Log(EventDone, "gastown/polecats/Nux", c)
+			}
+		}
+		if len(got) == 4 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Follow() error: %v", err)
+	}
+	want := []string{"gt-1", "gt-2", "gt-3", "gt-4"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,114 @@
+package townlog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// Session IDs join the events of one agent lifecycle, such as
+// spawn → nudge → handoff → done, so the whole thread can be read back
+// with Filter.Session. The logger assigns them: a spawn opens a new
+// session for its agent, a wake opens one if the agent has none, every
+// event for an agent with an open session joins it, and done, kill, and
+// crash close it. Open sessions are kept in <town>/.runtime/log-sessions.json.
+
+// sessionIDBytes is the amount of randomness in a session ID.
+const sessionIDBytes = 4
+
+// openSession is an agent's current session.
+type openSession struct {
+	ID      string    `json:"id"`
+	Started time.Time `json:"started"`
+}
+
+// sessionsPath returns where a town's open sessions are stored.
+func sessionsPath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "log-sessions.json")
+}
+
+// AgentKey is the canonical name of an agent, under which its sessions
+// are tracked. Polecats are logged both as rig/polecats/name and as
+// rig/name; both give rig/name.
+func AgentKey(agent string) string {
+	parts := strings.Split(strings.Trim(agent, "/"), "/")
+	if len(parts) == 3 && parts[1] == "polecats" {
+		return parts[0] + "/" + parts[2]
+	}
+	return strings.Join(parts, "/")
+}
+
+// NewSessionID returns a fresh random session ID.
+func NewSessionID() string {
+	buf := make([]byte, sessionIDBytes)
+	if _, err := rand.Read(buf); err != nil {
+		// Fall back to the clock; IDs only need to be unique per town.
+		return fmt.Sprintf("%08x", uint32(time.Now().UnixNano())) //nolint:gosec // G115: truncation intended
+	}
+	return hex.EncodeToString(buf)
+}
+
+// trackSession sets e's session from the agent's open session, opening or
+// closing it as e's type requires. An event that already has a session
+// keeps it, and a spawn or wake with one makes it the agent's session.
+func trackSession(sessionsFile string, e *Event) error {
+	if e.Agent == "" {
+		return nil
+	}
+	return updateSessions(sessionsFile, func(open map[string]openSession) {
+		key := AgentKey(e.Agent)
+		cur, ok := open[key]
+		switch {
+		case e.SessionID != "":
+		case e.Type == EventSpawn, e.Type == EventWake && !ok:
+			e.SessionID = NewSessionID()
+		case ok:
+			e.SessionID = cur.ID
+		}
+
+		switch e.Type {
+		case EventSpawn, EventWake:
+			if !ok || cur.ID != e.SessionID {
+				open[key] = openSession{ID: e.SessionID, Started: e.Timestamp}
+			}
+		case EventDone, EventKill, EventCrash:
+			if ok && cur.ID == e.SessionID {
+				delete(open, key)
+			}
+		}
+	})
+}
+
+// updateSessions applies fn to the open sessions under a lock and saves
+// them if fn changed anything.
+func updateSessions(path string, fn func(map[string]openSession)) error {
+	return util.WithFileLock(path+".lock", func() error {
+		open := map[string]openSession{}
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town
+		switch {
+		case err == nil:
+			// A corrupt file only loses the open sessions; start over.
+			_ = json.Unmarshal(data, &open)
+		case !os.IsNotExist(err):
+			return fmt.Errorf("reading sessions: %w", err)
+		}
+
+		before, _ := json.Marshal(open)
+		fn(open)
+		after, err := json.MarshalIndent(open, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding sessions: %w", err)
+		}
+		if compact, _ := json.Marshal(open); string(compact) == string(before) {
+			return nil
+		}
+		return util.AtomicWriteFile(path, after, 0600)
+	})
+}
@@ -0,0 +1,88 @@
+package townlog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionTracking(t *testing.T) {
+	townRoot := t.TempDir()
+	logger := NewLogger(townRoot)
+	log := func(typ EventType, agent, ctx string) {
+		t.Helper()
+		if err := logger.Log(typ, agent, ctx); err != nil {
+			t.Fatalf("Log(%s): %v", typ, err)
+		}
+	}
+
+	log(EventNudge, "gastown/polecats/nux", "before any session")
+	log(EventSpawn, "gastown/polecats/nux", "gt-1")
+	log(EventNudge, "gastown/nux", "start") // same polecat, short form
+	log(EventSpawn, "gastown/crew/max", "gt-2")
+	log(EventHandoff, "gastown/polecats/nux", "context full")
+	log(EventDone, "gastown/polecats/nux", "gt-1")
+	log(EventNudge, "gastown/polecats/nux", "after done")
+	log(EventSpawn, "gastown/polecats/nux", "gt-3")
+
+	events, err := ReadEvents(townRoot)
+	if err != nil || len(events) != 8 {
+		t.Fatalf("ReadEvents() = %d events, %v", len(events), err)
+	}
+	if events[0].SessionID != "" || events[6].SessionID != "" {
+		t.Errorf("events outside a session got IDs %q, %q", events[0].SessionID, events[6].SessionID)
+	}
+	nux := events[1].SessionID
+	if len(nux) != 8 {
+		t.Fatalf("spawn session ID = %q, want 8 hex digits", nux)
+	}
+	for _, i := range []int{2, 4, 5} {
+		if events[i].SessionID != nux {
+			t.Errorf("event %d (%s) session = %q, want %q", i, events[i].Type, events[i].SessionID, nux)
+		}
+	}
+	if max := events[3].SessionID; max == "" || max == nux {
+		t.Errorf("crew session = %q, want its own ID", max)
+	}
+	if next := events[7].SessionID; next == "" || next == nux {
+		t.Errorf("respawn session = %q, want a new ID", next)
+	}
+
+	thread := FilterEvents(events, Filter{Session: nux[:4]})
+	if len(thread) != 4 || thread[0].Type != EventSpawn || thread[3].Type != EventDone {
+		t.Errorf("Filter{Session} = %v", thread)
+	}
+}
+
+func TestSessionWake(t *testing.T) {
+	townRoot := t.TempDir()
+	logger := NewLogger(townRoot)
+	_ = logger.Log(EventWake, "mayor", "")
+	_ = logger.Log(EventWake, "mayor", "again")
+	_ = logger.Log(EventKill, "mayor", "gt stop")
+
+	events, _ := ReadEvents(townRoot)
+	if len(events) != 3 || events[0].SessionID == "" ||
+		events[1].SessionID != events[0].SessionID || events[2].SessionID != events[0].SessionID {
+		t.Errorf("wake should open a session once and kill should join it: %+v", events)
+	}
+}
+
+func TestSessionLogLine(t *testing.T) {
+	e := Event{
+		Timestamp: time.Date(2026, 10, 14, 9, 0, 0, 0, time.Local),
+		Type:      "deploy",
+		Agent:     "gastown/crew/max",
+		Context:   "v1.4",
+		Severity:  SeverityError,
+		SessionID: "3f9a2c01",
+	}
+	line := formatLogLine(e)
+	if !strings.Contains(line, "[deploy:error@3f9a2c01]") {
+		t.Errorf("formatLogLine() = %q", line)
+	}
+	got, err := parseLogLine(line)
+	if err != nil || got != e {
+		t.Errorf("parseLogLine(%q) = %+v, %v", line, got, err)
+	}
+}
@@ -0,0 +1,449 @@
+package townlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The SQLite backend keeps events in logs/town.db instead of town.log. It is
+// optional: a town switches to it by creating the database with
+// CreateStore (gt log migrate), and every reader and writer in this package
+// then uses it. Like the rest of gt, it drives sqlite3 as a subprocess
+// rather than linking a driver; without sqlite3 in PATH, a town with a
+// store fails to log or read events rather than splitting them between
+// the store and town.log.
+
+// sqliteBusyTimeout is how long sqlite3 waits for a locked database (ms).
+const sqliteBusyTimeout = ".timeout 5000"
+
+// insertBatch is how many rows go into one INSERT statement.
+const insertBatch = 500
+
+// storeSchema creates the events table and its indexes. The unique index
+// makes imports idempotent: a line imported twice is stored once.
+const storeSchema = `PRAGMA journal_mode=WAL;
+CREATE TABLE IF NOT EXISTS events (
+	id      INTEGER PRIMARY KEY,
+	ts      INTEGER NOT NULL,
+	type    TEXT NOT NULL,
+	agent   TEXT NOT NULL,
+	context TEXT NOT NULL DEFAULT '',
+	severity TEXT NOT NULL DEFAULT '',
+	session TEXT NOT NULL DEFAULT '',
+	prev    TEXT NOT NULL DEFAULT '',
+	usage   TEXT NOT NULL DEFAULT '',
+	transcript TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS events_ts ON events(ts);
+CREATE INDEX IF NOT EXISTS events_agent ON events(agent, ts);
+CREATE INDEX IF NOT EXISTS events_type ON events(type, ts);
+CREATE UNIQUE INDEX IF NOT EXISTS events_dedup ON events(ts, type, agent, context);
+`
+
+// storeUpgrades add the columns that stores created by older versions
+// lack, one statement each, oldest first.
+var storeUpgrades = []string{
+	`ALTER TABLE events ADD COLUMN severity TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE events ADD COLUMN session TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE events ADD COLUMN prev TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE events ADD COLUMN usage TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE events ADD COLUMN transcript TEXT NOT NULL DEFAULT '';`,
+}
+
+// sessionIndex indexes events by session. It is created by upgrade rather
+// than storeSchema, which also runs against stores without the column.
+const sessionIndex = `CREATE INDEX IF NOT EXISTS events_session ON events(session, ts);`
+
+// DBPath returns the path to the town's SQLite event store.
+func DBPath(townRoot string) string {
+	return filepath.Join(logDir(townRoot), "town.db")
+}
+
+// store is the SQLite event store of one town.
+type store struct {
+	path string
+}
+
+// errNoSQLite is why a town with a SQLite store cannot use it.
+var errNoSQLite = errors.New("town.db present but sqlite3 not found in PATH")
+
+// openStore returns the town's SQLite store, or nil if the town uses the
+// flat-file log. It fails if the store exists but sqlite3 is not installed.
+func openStore(townRoot string) (*store, error) {
+	path := DBPath(townRoot)
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil
+	}
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		return nil, errNoSQLite
+	}
+	return &store{path: path}, nil
+}
+
+// UsesSQLite reports whether the town's events are kept in SQLite.
+func UsesSQLite(townRoot string) bool {
+	_, err := os.Stat(DBPath(townRoot))
+	return err == nil
+}
+
+// unavailable is the backend of a town whose store cannot be opened: every
+// read and write fails with why.
+type unavailable struct {
+	err error
+}
+
+// warnUnavailable reports the first event a process cannot log, since many
+// callers do not check.
+var warnUnavailable sync.Once
+
+func (u unavailable) insert(...Event) error {
+	warnUnavailable.Do(func() { fmt.Fprintf(os.Stderr, "gt: not logging events: %v\n", u.err) })
+	return u.err
+}
+
+func (u unavailable) query(Filter) ([]Event, error) { return nil, u.err }
+func (u unavailable) tail(int) ([]Event, error)     { return nil, u.err }
+func (u unavailable) follow(context.Context, FollowOptions, time.Duration, func(Event) error) error {
+	return u.err
+}
+
+// CreateStore creates the town's SQLite store, or brings the schema of an
+// existing one up to date. Once it exists, the town logs to it.
+func CreateStore(townRoot string) error {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		return errors.New("sqlite3 not installed")
+	}
+	if err := os.MkdirAll(logDir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+	s := &store{path: DBPath(townRoot)}
+	if err := s.exec(storeSchema); err != nil {
+		return fmt.Errorf("creating event store: %w", err)
+	}
+	if err := s.upgrade(); err != nil {
+		return fmt.Errorf("upgrading event store: %w", err)
+	}
+	return os.Chmod(s.path, 0600)
+}
+
+// ImportLog loads every event in a flat-file log (gzipped or not) into the
+// town's SQLite store and returns how many lines it parsed. Events already
+// in the store are skipped.
+func ImportLog(townRoot, path string) (int, error) {
+	s, err := openStore(townRoot)
+	if err != nil {
+		return 0, err
+	}
+	if s == nil {
+		return 0, errors.New("town has no SQLite event store")
+	}
+	content, err := readLogFile(path)
+	if err != nil {
+		return 0, err
+	}
+	events, _ := ParseLogLines(content)
+	if err := s.insert(events...); err != nil {
+		return 0, fmt.Errorf("importing %s: %w", path, err)
+	}
+	return len(events), nil
+}
+
+// CountStoredEvents returns how many events the town's SQLite store holds.
+func CountStoredEvents(townRoot string) (int, error) {
+	s, err := openStore(townRoot)
+	if err != nil {
+		return 0, err
+	}
+	if s == nil {
+		return 0, errors.New("town has no SQLite event store")
+	}
+	out, err := s.run(false, "SELECT count(*) FROM events;")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+// exec runs a SQL script, stopping at the first error.
+func (s *store) exec(script string) error {
+	cmd := exec.Command("sqlite3", "-bail", "-cmd", sqliteBusyTimeout, s.path) //nolint:gosec // G204: path is constructed internally
+	cmd.Stdin = strings.NewReader(script)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("sqlite3: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// run runs a query and returns its output, as JSON rows if asJSON is set.
+func (s *store) run(asJSON bool, query string) ([]byte, error) {
+	args := []string{"-cmd", sqliteBusyTimeout}
+	if asJSON {
+		args = append(args, "-json")
+	}
+	args = append(args, s.path, query)
+	cmd := exec.Command("sqlite3", args...) //nolint:gosec // G204: query is built from quoted literals
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("sqlite3: %s", strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// upgrade adds the columns that stores created by older versions lack.
+func (s *store) upgrade() error {
+	for _, stmt := range storeUpgrades {
+		if err := s.exec(stmt); err != nil && !strings.Contains(err.Error(), "duplicate column") {
+			return err
+		}
+	}
+	return s.exec(sessionIndex)
+}
+
+// withUpgrade runs fn, and if it failed because the store predates one of
+// the newer columns, upgrades the store and runs fn again.
+func (s *store) withUpgrade(fn func() error) error {
+	err := fn()
+	if err == nil || !isMissingColumn(err) {
+		return err
+	}
+	if uerr := s.upgrade(); uerr != nil {
+		return err
+	}
+	return fn()
+}
+
+// isMissingColumn reports whether err is sqlite3 rejecting a column the
+// table does not have.
+func isMissingColumn(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "no such column") || strings.Contains(msg, "has no column named")
+}
+
+// insert stores events in one transaction.
+func (s *store) insert(events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return s.withUpgrade(func() error { return s.insertRows(events) })
+}
+
+func (s *store) insertRows(events []Event) error {
+	var b strings.Builder
+	b.WriteString("BEGIN;\n")
+	for i, e := range events {
+		if i%insertBatch == 0 {
+			if i > 0 {
+				b.WriteString(";\n")
+			}
+			b.WriteString("INSERT OR IGNORE INTO events (ts, type, agent, context, severity, session, prev, usage, transcript) VALUES\n")
+		} else {
+			b.WriteString(",\n")
+		}
+		fmt.Fprintf(&b, "(%d, %s, %s, %s, %s, %s, %s, %s, %s)", e.Timestamp.UnixNano(),
+			sqlQuote(string(e.Type)), sqlQuote(e.Agent), sqlQuote(e.Context), sqlQuote(string(e.Severity)),
+			sqlQuote(e.SessionID), sqlQuote(e.Prev), sqlQuote(e.Usage.String()), sqlQuote(e.Transcript))
+	}
+	b.WriteString(";\nCOMMIT;\n")
+	return s.exec(b.String())
+}
+
+// storedEvent is an events row as sqlite3 -json prints it.
+type storedEvent struct {
+	ID         int64  `json:"id"`
+	TS         int64  `json:"ts"`
+	Type       string `json:"type"`
+	Agent      string `json:"agent"`
+	Context    string `json:"context"`
+	Sev        string `json:"severity"`
+	Session    string `json:"session"`
+	Prev       string `json:"prev"`
+	Usage      string `json:"usage"`
+	Transcript string `json:"transcript"`
+}
+
+func (r storedEvent) event() Event {
+	usage, _ := ParseUsage(r.Usage)
+	return Event{
+		Timestamp:  time.Unix(0, r.TS),
+		Type:       EventType(r.Type),
+		Agent:      r.Agent,
+		Context:    r.Context,
+		Severity:   Severity(r.Sev),
+		SessionID:  r.Session,
+		Prev:       r.Prev,
+		Usage:      usage,
+		Transcript: r.Transcript,
+	}
+}
+
+// rows selects events matching where (SQL, may be empty), in the given
+// order, at most limit of them (0 for all).
+func (s *store) rows(where, order string, limit int) ([]storedEvent, error) {
+	query := "SELECT id, ts, type, agent, context, severity, session, prev, usage, transcript FROM events"
+	if where != "" {
+		query += " WHERE " + where
+	}
+	query += " ORDER BY " + order
+	if limit > 0 {
+		query += " LIMIT " + strconv.Itoa(limit)
+	}
+	var out []byte
+	err := s.withUpgrade(func() (err error) {
+		out, err = s.run(true, query+";")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil // sqlite3 prints nothing for an empty result
+	}
+	var rows []storedEvent
+	if err := json.Unmarshal(out, &rows); err != nil {
+		return nil, fmt.Errorf("decoding events: %w", err)
+	}
+	return rows, nil
+}
+
+// query returns the events matching f, oldest first. The indexed criteria
+// run in SQLite; f.Match then applies the rest (such as Pattern).
+func (s *store) query(f Filter) ([]Event, error) {
+	rows, err := s.rows(sqlWhere(f), "ts, id", 0)
+	if err != nil {
+		return nil, err
+	}
+	var events []Event
+	for _, r := range rows {
+		if e := r.event(); f.Match(e) {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// tail returns the last n events, oldest first.
+func (s *store) tail(n int) ([]Event, error) {
+	rows, err := s.rows("", "ts DESC, id DESC", n)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]Event, len(rows))
+	for i, r := range rows {
+		events[len(rows)-1-i] = r.event()
+	}
+	return events, nil
+}
+
+// follow is Follow for the SQLite store: it polls for rows added after the
+// last one seen.
+func (s *store) follow(ctx context.Context, opts FollowOptions, interval time.Duration, fn func(Event) error) error {
+	var last int64
+	if rows, err := s.rows("", "id DESC", 1); err != nil {
+		return err
+	} else if len(rows) > 0 {
+		last = rows[0].ID
+	}
+
+	if opts.Backlog > 0 {
+		backlog, err := s.query(opts.Filter)
+		if err != nil {
+			return err
+		}
+		if opts.Backlog < len(backlog) {
+			backlog = backlog[len(backlog)-opts.Backlog:]
+		}
+		for _, e := range backlog {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			rows, err := s.rows(fmt.Sprintf("id > %d", last), "id", 0)
+			if err != nil {
+				return err
+			}
+			for _, r := range rows {
+				last = r.ID
+				if e := r.event(); opts.Filter.Match(e) {
+					if err := fn(e); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+}
+
+// sqlWhere translates the indexable parts of a filter into a WHERE clause.
+func sqlWhere(f Filter) string {
+	var conds []string
+	if f.Type != "" {
+		conds = append(conds, "type = "+sqlQuote(string(f.Type)))
+	}
+	if len(f.Types) > 0 {
+		quoted := make([]string, len(f.Types))
+		for i, t := range f.Types {
+			quoted[i] = sqlQuote(string(t))
+		}
+		conds = append(conds, "type IN ("+strings.Join(quoted, ", ")+")")
+	}
+	if prefix := agentGlobPrefix(f.Agent); prefix != "" {
+		// A range keeps the agent index usable, unlike LIKE or substr.
+		// Globs narrow to their literal prefix; f.Match does the rest.
+		conds = append(conds, "agent >= "+sqlQuote(prefix))
+		if upper, ok := prefixUpperBound(prefix); ok {
+			conds = append(conds, "agent < "+sqlQuote(upper))
+		}
+	}
+	if f.Session != "" {
+		conds = append(conds, "session >= "+sqlQuote(f.Session))
+		if upper, ok := prefixUpperBound(f.Session); ok {
+			conds = append(conds, "session < "+sqlQuote(upper))
+		}
+	}
+	if !f.Since.IsZero() {
+		conds = append(conds, fmt.Sprintf("ts >= %d", f.Since.UnixNano()))
+	}
+	if !f.Until.IsZero() {
+		conds = append(conds, fmt.Sprintf("ts <= %d", f.Until.UnixNano()))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// prefixUpperBound returns the smallest string greater than every string
+// with the given prefix.
+func prefixUpperBound(prefix string) (string, bool) {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 0xff {
+			b[i]++
+			return string(b[:i+1]), true
+		}
+	}
+	return "", false
+}
+
+// sqlQuote renders s as a SQL string literal.
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,201 @@
+package townlog
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newSQLiteTown returns a town on the SQLite store whose flat-file log
+// already held two events before migration.
+func newSQLiteTown(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	townRoot := t.TempDir()
+	flat := NewLogger(townRoot)
+	_ = flat.Log(EventSpawn, "gastown/polecats/Toast", "gt-1")
+	_ = flat.Log(EventNudge, "mayor", "it's time")
+
+	if err := CreateStore(townRoot); err != nil {
+		t.Fatalf("CreateStore() error: %v", err)
+	}
+	for i := 0; i < 2; i++ { // importing twice stores each event once
+		if n, err := ImportLog(townRoot, logPath(townRoot)); err != nil || n != 2 {
+			t.Fatalf("ImportLog() = %d, %v; want 2", n, err)
+		}
+	}
+	return townRoot
+}
+
+func TestSQLiteStore(t *testing.T) {
+	townRoot := newSQLiteTown(t)
+	if !UsesSQLite(townRoot) {
+		t.Fatal("UsesSQLite() = false after CreateStore")
+	}
+
+	before, _ := os.Stat(logPath(townRoot))
+	logger := NewLogger(townRoot)
+	_ = logger.Log(EventDone, "gastown/polecats/Toast", "gt-1 finished\nwith a newline")
+	_ = logger.Log(EventCrash, "gastown/witness", "exit 1")
+
+	// New events go to the store, not town.log.
+	after, _ := os.Stat(logPath(townRoot))
+	if after.Size() != before.Size() {
+		t.Error("town.log was written after migration")
+	}
+	if n, err := CountStoredEvents(townRoot); err != nil || n != 4 {
+		t.Fatalf("CountStoredEvents() = %d, %v; want 4", n, err)
+	}
+
+	all, err := ReadEvents(townRoot)
+	if err != nil || len(all) != 4 {
+		t.Fatalf("ReadEvents() = %d events, %v; want 4", len(all), err)
+	}
+	if all[1].Context != "it's time" || all[2].Context != "gt-1 finished\nwith a newline" {
+		t.Errorf("contexts not preserved: %q, %q", all[1].Context, all[2].Context)
+	}
+
+	got, err := QueryEvents(townRoot, Filter{Agent: "gastown/polecats/", Types: []EventType{EventDone, EventSpawn}})
+	if err != nil || len(got) != 2 || got[0].Type != EventSpawn || got[1].Type != EventDone {
+		t.Errorf("QueryEvents(agent, types) = %v, %v", got, err)
+	}
+	got, _ = QueryEvents(townRoot, Filter{Since: all[3].Timestamp})
+	if len(got) != 1 || got[0].Type != EventCrash {
+		t.Errorf("QueryEvents(since) = %v, want the crash", got)
+	}
+
+	tail, err := TailEvents(townRoot, 2)
+	if err != nil || len(tail) != 2 || tail[0].Type != EventDone || tail[1].Type != EventCrash {
+		t.Errorf("TailEvents(2) = %v, %v", tail, err)
+	}
+}
+
+func TestSQLiteSeverityUpgrade(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(logDir(townRoot), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A store created before events had a severity.
+	old := &store{path: DBPath(townRoot)}
+	if err := old.exec("CREATE TABLE events (id INTEGER PRIMARY KEY, ts INTEGER NOT NULL, type TEXT NOT NULL, agent TEXT NOT NULL, context TEXT NOT NULL DEFAULT '');" +
+		"INSERT INTO events (ts, type, agent, context) VALUES (1, 'crash', 'mayor', 'exit 1');"); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := NewLogger(townRoot)
+	if err := logger.EmitEvent(Event{Type: "deploy", Agent: "ci", Context: "failed", Severity: SeverityError}); err != nil {
+		t.Fatalf("EmitEvent() on old store: %v", err)
+	}
+	got, err := QueryEvents(townRoot, Filter{MinSeverity: SeverityError})
+	if err != nil || len(got) != 2 || got[0].Type != EventCrash || got[1].Severity != SeverityError {
+		t.Errorf("QueryEvents(min error) = %v, %v", got, err)
+	}
+	if err := CreateStore(townRoot); err != nil {
+		t.Errorf("CreateStore() on upgraded store: %v", err)
+	}
+}
+
+func TestSQLiteFollow(t *testing.T) {
+	townRoot := newSQLiteTown(t)
+	logger := NewLogger(townRoot)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var got []string
+	opts := FollowOptions{Filter: Filter{Agent: "gastown/"}, Backlog: 1, Interval: 10 * time.Millisecond}
+	err := Follow(ctx, townRoot, opts, func(e Event) error {
+		got = append(got, e.Context)
+		if len(got) == 1 {
+			_ = logger.Log(EventNudge, "mayor", "skipped")
+			_ = logger.Log(EventDone, "gastown/polecats/Nux", "gt-2")
+		}
+		if len(got) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Follow() error: %v", err)
+	}
+	if len(got) != 2 || got[0] != "gt-1" || got[1] != "gt-2" {
+		t.Errorf("got %v, want [gt-1 gt-2]", got)
+	}
+}
+
+func TestSQLWhere(t *testing.T) {
+	since := time.Unix(100, 0)
+	got := sqlWhere(Filter{Types: []EventType{"spawn", "o'dd"}, Agent: "gastown/", Since: since})
+	want := "type IN ('spawn', 'o''dd') AND agent >= 'gastown/' AND agent < 'gastown0' AND ts >= 100000000000"
+	if got != want {
+		t.Errorf("sqlWhere() =\n  %s\nwant\n  %s", got, want)
+	}
+	if got := sqlWhere(Filter{Agent: "gastown/crew/*"}); got != "agent >= 'gastown/crew/' AND agent < 'gastown/crew0'" {
+		t.Errorf("sqlWhere(glob) = %q, want its literal prefix as a range", got)
+	}
+	if got := sqlWhere(Filter{Agent: "*/witness"}); got != "" {
+		t.Errorf("sqlWhere(leading glob) = %q, want no agent condition", got)
+	}
+	if got := sqlWhere(Filter{}); got != "" {
+		t.Errorf("sqlWhere(empty) = %q", got)
+	}
+}
+
+func TestSQLiteSessions(t *testing.T) {
+	townRoot := newSQLiteTown(t)
+	logger := NewLogger(townRoot)
+	_ = logger.Log(EventSpawn, "gastown/polecats/nux", "gt-1")
+	_ = logger.Log(EventSpawn, "gastown/crew/max", "gt-2")
+	_ = logger.Log(EventDone, "gastown/polecats/nux", "gt-1")
+
+	spawns, err := QueryEvents(townRoot, Filter{Agent: "gastown/polecats/nux", Type: EventSpawn})
+	if err != nil || len(spawns) != 1 || spawns[0].SessionID == "" {
+		t.Fatalf("QueryEvents(spawn) = %v, %v", spawns, err)
+	}
+	got, err := QueryEvents(townRoot, Filter{Session: spawns[0].SessionID})
+	if err != nil || len(got) != 2 || got[1].Type != EventDone {
+		t.Errorf("QueryEvents(session) = %v, %v", got, err)
+	}
+}
+
+func TestSQLiteWithoutSqlite3(t *testing.T) {
+	townRoot := newSQLiteTown(t)
+	if err := os.Remove(logPath(townRoot)); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", t.TempDir())
+
+	err := NewLogger(townRoot).Log(EventDone, "gastown/polecats/Toast", "gt-1")
+	if err == nil || !strings.Contains(err.Error(), "sqlite3 not found") {
+		t.Errorf("Log() without sqlite3 = %v", err)
+	}
+	if _, err := os.Stat(logPath(townRoot)); !os.IsNotExist(err) {
+		t.Error("logged to town.log instead of the store")
+	}
+	if _, err := ReadEvents(townRoot); err == nil {
+		t.Error("ReadEvents() without sqlite3 succeeded")
+	}
+	if _, err := TailEvents(townRoot, 5); err == nil {
+		t.Error("TailEvents() without sqlite3 succeeded")
+	}
+	if got := LogLocation(townRoot); got != DBPath(townRoot) {
+		t.Errorf("LogLocation() = %s, want the store", got)
+	}
+
+	// A town without a store still logs to town.log
+	flat := t.TempDir()
+	if err := NewLogger(flat).Log(EventSpawn, "gastown/polecats/Toast", "gt-2"); err != nil {
+		t.Fatalf("Log() to town.log: %v", err)
+	}
+	if events, err := ReadEvents(flat); err != nil || len(events) != 1 {
+		t.Errorf("ReadEvents() of town.log = %v, %v", events, err)
+	}
+}
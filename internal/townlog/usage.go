@@ -0,0 +1,147 @@
+package townlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Usage is what an agent's work cost, as reported with its done and
+// handoff events: tokens in and out, spend in dollars, and time taken.
+// Zero fields were not reported.
+type Usage struct {
+	TokensIn  int64
+	TokensOut int64
+	Cost      float64 // USD
+	Duration  time.Duration
+}
+
+// usageJSON is Usage as JSON, with the duration in seconds.
+type usageJSON struct {
+	TokensIn  int64   `json:"tokens_in,omitempty"`
+	TokensOut int64   `json:"tokens_out,omitempty"`
+	Cost      float64 `json:"cost,omitempty"`
+	Seconds   float64 `json:"duration_seconds,omitempty"`
+}
+
+// MarshalJSON encodes u with its duration as duration_seconds.
+func (u Usage) MarshalJSON() ([]byte, error) {
+	return json.Marshal(usageJSON{u.TokensIn, u.TokensOut, u.Cost, u.Duration.Seconds()})
+}
+
+// UnmarshalJSON decodes the form MarshalJSON writes.
+func (u *Usage) UnmarshalJSON(data []byte) error {
+	var j usageJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*u = Usage{j.TokensIn, j.TokensOut, j.Cost, time.Duration(j.Seconds * float64(time.Second))}
+	return nil
+}
+
+// IsZero reports whether nothing was reported.
+func (u *Usage) IsZero() bool {
+	return u == nil || *u == Usage{}
+}
+
+// Add adds o's figures to u.
+func (u *Usage) Add(o *Usage) {
+	if o == nil {
+		return
+	}
+	u.TokensIn += o.TokensIn
+	u.TokensOut += o.TokensOut
+	u.Cost += o.Cost
+	u.Duration += o.Duration
+}
+
+// String encodes u as it is stored: space-separated tokens=<in>/<out>,
+// cost=$<dollars>, and duration=<duration>, each only when reported, e.g.
+// "tokens=12000/3400 cost=$0.42 duration=12m0s". A nil or zero Usage
+// encodes as "".
+func (u *Usage) String() string {
+	if u.IsZero() {
+		return ""
+	}
+	var parts []string
+	if u.TokensIn != 0 || u.TokensOut != 0 {
+		parts = append(parts, fmt.Sprintf("tokens=%d/%d", u.TokensIn, u.TokensOut))
+	}
+	if u.Cost != 0 {
+		parts = append(parts, "cost=$"+strconv.FormatFloat(u.Cost, 'f', -1, 64))
+	}
+	if u.Duration != 0 {
+		parts = append(parts, "duration="+u.Duration.Round(time.Second).String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseUsage decodes a Usage encoded by String. It returns nil for "".
+func ParseUsage(s string) (*Usage, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var u Usage
+	for _, field := range strings.Fields(s) {
+		key, val, _ := strings.Cut(field, "=")
+		var err error
+		switch key {
+		case "tokens":
+			in, out, ok := strings.Cut(val, "/")
+			if !ok {
+				return nil, fmt.Errorf("invalid usage tokens %q: want <in>/<out>", val)
+			}
+			if u.TokensIn, err = strconv.ParseInt(in, 10, 64); err == nil {
+				u.TokensOut, err = strconv.ParseInt(out, 10, 64)
+			}
+		case "cost":
+			u.Cost, err = strconv.ParseFloat(strings.TrimPrefix(val, "$"), 64)
+		case "duration":
+			u.Duration, err = time.ParseDuration(val)
+		default:
+			return nil, fmt.Errorf("unknown usage field %q", field)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid usage field %q", field)
+		}
+	}
+	return &u, nil
+}
+
+// usageSep separates an event's usage from the rest of its log line.
+const usageSep = " | "
+
+// transcriptField introduces an event's archived transcript at the end of
+// its log line.
+const transcriptField = "transcript="
+
+// splitTranscript cuts the archived transcript path at the end of a log
+// line's detail text off it.
+func splitTranscript(detail string) (string, string) {
+	i := strings.LastIndex(detail, usageSep+transcriptField)
+	if i < 0 {
+		return detail, ""
+	}
+	path := detail[i+len(usageSep)+len(transcriptField):]
+	if path == "" || strings.ContainsAny(path, " |") {
+		return detail, ""
+	}
+	return detail[:i], path
+}
+
+// splitUsage cuts the usage encoded at the end of a log line's detail
+// text off it. Detail text that does not end in valid usage is returned
+// whole.
+func splitUsage(detail string) (string, *Usage) {
+	i := strings.LastIndex(detail, usageSep)
+	if i < 0 {
+		return detail, nil
+	}
+	u, err := ParseUsage(detail[i+len(usageSep):])
+	if err != nil || u == nil {
+		return detail, nil
+	}
+	return detail[:i], u
+}
@@ -0,0 +1,95 @@
+package townlog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsageEncoding(t *testing.T) {
+	u := &Usage{TokensIn: 12000, TokensOut: 3400, Cost: 0.42, Duration: 12 * time.Minute}
+	if got, want := u.String(), "tokens=12000/3400 cost=$0.42 duration=12m0s"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	back, err := ParseUsage(u.String())
+	if err != nil || *back != *u {
+		t.Errorf("ParseUsage(String()) = %+v, %v; want %+v", back, err, u)
+	}
+	if back, err := ParseUsage("cost=$1.5"); err != nil || back.Cost != 1.5 || back.TokensIn != 0 {
+		t.Errorf("ParseUsage(cost only) = %+v, %v", back, err)
+	}
+	for _, bad := range []string{"tokens=12", "cost=lots", "color=red"} {
+		if _, err := ParseUsage(bad); err == nil {
+			t.Errorf("ParseUsage(%q) = nil error", bad)
+		}
+	}
+	if (*Usage)(nil).String() != "" || !(&Usage{}).IsZero() {
+		t.Error("nil and zero usage should encode as empty")
+	}
+}
+
+func TestUsageLogLine(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 14, 0, 0, 0, time.Local)
+	e := Event{Timestamp: ts, Type: EventDone, Agent: "gastown/Toast", Context: "gt-xyz",
+		Usage: &Usage{TokensIn: 900, TokensOut: 100, Cost: 0.05}}
+	line := formatLogLine(e)
+	if want := "2026-03-01 14:00:00 [done] gastown/Toast completed gt-xyz | tokens=900/100 cost=$0.05"; line != want {
+		t.Errorf("formatLogLine() = %q, want %q", line, want)
+	}
+	back, err := parseLogLine(line)
+	if err != nil || back.Context != "gt-xyz" || back.Usage == nil || *back.Usage != *e.Usage {
+		t.Errorf("parseLogLine() = %+v, %v", back, err)
+	}
+
+	// A context that merely contains the separator keeps it.
+	e = Event{Timestamp: ts, Type: EventHandoff, Agent: "mayor", Context: "a | b"}
+	if back, _ := parseLogLine(formatLogLine(e)); back.Context != "a | b" || back.Usage != nil {
+		t.Errorf("context with separator parsed as %+v", back)
+	}
+}
+
+func TestUsageSegments(t *testing.T) {
+	townRoot := newSegmentTown(t)
+	u := &Usage{TokensIn: 5, TokensOut: 7, Duration: time.Minute}
+	if err := NewLogger(townRoot).LogEvent(Event{Timestamp: time.Now(), Type: EventDone, Agent: "gastown/Toast", Context: "gt-1", Usage: u}); err != nil {
+		t.Fatal(err)
+	}
+	tail, err := TailEvents(townRoot, 1)
+	if err != nil || len(tail) != 1 || tail[0].Usage == nil || *tail[0].Usage != *u {
+		t.Errorf("TailEvents() = %+v, %v; want usage %+v", tail, err, u)
+	}
+}
+
+func TestTranscriptLogLine(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 14, 0, 0, 0, time.Local)
+	e := Event{Timestamp: ts, Type: EventDone, Agent: "gastown/Toast", Context: "gt-xyz",
+		Usage: &Usage{TokensIn: 900, TokensOut: 100}, Transcript: "logs/transcripts/gastown/Toast/2026-03-01T14-00-00.md"}
+	line := formatLogLine(e)
+	if want := "2026-03-01 14:00:00 [done] gastown/Toast completed gt-xyz | tokens=900/100 | transcript=logs/transcripts/gastown/Toast/2026-03-01T14-00-00.md"; line != want {
+		t.Errorf("formatLogLine() = %q, want %q", line, want)
+	}
+	back, err := parseLogLine(line)
+	if err != nil || back.Context != "gt-xyz" || back.Usage == nil || back.Transcript != e.Transcript {
+		t.Errorf("parseLogLine() = %+v, %v", back, err)
+	}
+
+	// Without usage
+	e = Event{Timestamp: ts, Type: EventHandoff, Agent: "gastown/crew/max", Context: "lunch", Transcript: "logs/transcripts/x.md"}
+	if back, _ := parseLogLine(formatLogLine(e)); back.Context != "lunch" || back.Usage != nil || back.Transcript != e.Transcript {
+		t.Errorf("handoff with transcript parsed as %+v", back)
+	}
+	if HashEvent(e) == HashEvent(Event{Timestamp: ts, Type: EventHandoff, Agent: "gastown/crew/max", Context: "lunch"}) {
+		t.Error("the transcript link is not covered by the event hash")
+	}
+}
+
+func TestTranscriptSegments(t *testing.T) {
+	townRoot := newSegmentTown(t)
+	e := Event{Timestamp: time.Now(), Type: EventDone, Agent: "gastown/Toast", Context: "gt-1", Transcript: "logs/transcripts/gastown/Toast/a.md"}
+	if err := NewLogger(townRoot).LogEvent(e); err != nil {
+		t.Fatal(err)
+	}
+	tail, err := TailEvents(townRoot, 1)
+	if err != nil || len(tail) != 1 || tail[0].Transcript != e.Transcript {
+		t.Errorf("TailEvents() = %+v, %v; want transcript %s", tail, err, e.Transcript)
+	}
+}
@@ -0,0 +1,219 @@
+package townlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/workspace"
+)
+
+// WebhookTimeout bounds each webhook delivery.
+const WebhookTimeout = 5 * time.Second
+
+// defaultWebhookEvents are sent by webhooks that do not list their events.
+var defaultWebhookEvents = []EventType{EventCrash, EventDone, EventHandoff}
+
+var (
+	webhookClient = &http.Client{Timeout: WebhookTimeout}
+
+	// webhooksPending counts deliveries still in flight; idle is closed
+	// when the count drops to zero. See WaitWebhooks.
+	webhooksPending struct {
+		sync.Mutex
+		n    int
+		idle chan struct{}
+	}
+)
+
+// WebhookPayload is the body posted by webhooks in the "json" format.
+type WebhookPayload struct {
+	ID         string    `json:"id"`
+	Town       string    `json:"town"`
+	Timestamp  time.Time `json:"timestamp"`
+	Type       EventType `json:"type"`
+	Agent      string    `json:"agent"`
+	Context    string    `json:"context,omitempty"`
+	Severity   Severity  `json:"severity"`
+	SessionID  string    `json:"session_id,omitempty"`
+	Usage      *Usage    `json:"usage,omitempty"`
+	Transcript string    `json:"transcript,omitempty"`
+	Text       string    `json:"text"` // one-line summary, e.g. "gastown/nux exited unexpectedly"
+}
+
+// webhooks is the configured set of webhooks for one town.
+type webhooks struct {
+	town  string
+	hooks []config.WebhookConfig
+}
+
+// loadWebhooks returns the webhooks in the town settings, or nil if there
+// are none.
+func loadWebhooks(townRoot string, settings *config.TownSettings) *webhooks {
+	if len(settings.Webhooks) == 0 {
+		return nil
+	}
+	town, err := workspace.GetTownName(townRoot)
+	if err != nil || town == "" {
+		town = filepath.Base(townRoot)
+	}
+	return &webhooks{town: town, hooks: settings.Webhooks}
+}
+
+// notify starts a delivery to every webhook subscribed to e. It does not
+// wait for them: deliveries run in the background, and failures are
+// reported on stderr.
+func (w *webhooks) notify(e Event) {
+	if w == nil {
+		return
+	}
+	for _, hook := range w.hooks {
+		if !webhookWants(hook, e.Type) {
+			continue
+		}
+		webhookStarted()
+		go func(hook config.WebhookConfig) {
+			defer webhookFinished()
+			if err := deliverWebhook(hook, w.town, e); err != nil {
+				fmt.Fprintf(os.Stderr, "gt: webhook for %s event: %v\n", e.Type, err)
+			}
+		}(hook)
+	}
+}
+
+// WaitWebhooks waits up to timeout for background webhook deliveries, so
+// that a short-lived command does not exit before its events are sent.
+// It reports whether all deliveries finished.
+func WaitWebhooks(timeout time.Duration) bool {
+	webhooksPending.Lock()
+	if webhooksPending.n == 0 {
+		webhooksPending.Unlock()
+		return true
+	}
+	idle := webhooksPending.idle
+	webhooksPending.Unlock()
+
+	select {
+	case <-idle:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func webhookStarted() {
+	webhooksPending.Lock()
+	defer webhooksPending.Unlock()
+	if webhooksPending.n == 0 {
+		webhooksPending.idle = make(chan struct{})
+	}
+	webhooksPending.n++
+}
+
+func webhookFinished() {
+	webhooksPending.Lock()
+	defer webhooksPending.Unlock()
+	if webhooksPending.n--; webhooksPending.n == 0 {
+		close(webhooksPending.idle)
+	}
+}
+
+// webhookWants reports whether hook is subscribed to events of type t.
+func webhookWants(hook config.WebhookConfig, t EventType) bool {
+	if len(hook.Events) == 0 {
+		return containsType(defaultWebhookEvents, t)
+	}
+	for _, want := range ParseTypes(hook.Events...) {
+		if want == "*" || want == t {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookBody builds the payload for e in the hook's format.
+func webhookBody(hook config.WebhookConfig, town string, e Event) (interface{}, error) {
+	text := e.Agent + " " + describe(e)
+	switch hook.Format {
+	case "", config.WebhookFormatJSON:
+		return WebhookPayload{
+			ID:         e.ID(),
+			Town:       town,
+			Timestamp:  e.Timestamp,
+			Type:       e.Type,
+			Agent:      e.Agent,
+			Context:    e.Context,
+			Severity:   e.Level(),
+			SessionID:  e.SessionID,
+			Usage:      e.Usage,
+			Transcript: e.Transcript,
+			Text:       text,
+		}, nil
+	case config.WebhookFormatSlack:
+		prefix := ""
+		switch e.Level() {
+		case SeverityError:
+			prefix = ":red_circle: "
+		case SeverityWarn:
+			prefix = ":warning: "
+		}
+		return map[string]string{
+			"text": fmt.Sprintf("%s*[%s] %s* %s", prefix, slackEscape(town), e.Type, slackEscape(text)),
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown webhook format %q: use json or slack", hook.Format)
+}
+
+// slackEscape escapes the characters Slack treats as markup.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// deliverWebhook posts e to hook and treats any non-2xx response as an
+// error. Errors name only the host, since webhook URLs embed secrets.
+func deliverWebhook(hook config.WebhookConfig, town string, e Event) error {
+	endpoint := hook.URL
+	if name, ok := strings.CutPrefix(endpoint, "env:"); ok {
+		if endpoint = os.Getenv(name); endpoint == "" {
+			return fmt.Errorf("environment variable %s is not set", name)
+		}
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL: want http(s)://host/path")
+	}
+
+	body, err := webhookBody(hook, town, e)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding payload: %w", err)
+	}
+	resp, err := webhookClient.Post(endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		// Drop the URL from the error; it may embed a secret token.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("posting to %s: %w", u.Host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s: %s", u.Host, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package townlog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+)
+
+// writeWebhookSettings writes town settings with the given webhooks JSON.
+func writeWebhookSettings(t *testing.T, townRoot, hooks string) {
+	t.Helper()
+	dir := filepath.Join(townRoot, "settings")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type": "town-settings", "version": 1, "webhooks": ` + hooks + `}`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	bodies := make(chan map[string]interface{}, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		body["path"] = r.URL.Path
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	townRoot := t.TempDir()
+	t.Setenv("GT_TEST_WEBHOOK", srv.URL+"/slack")
+	writeWebhookSettings(t, townRoot, `[
+		{"url": "`+srv.URL+`/json"},
+		{"url": "env:GT_TEST_WEBHOOK", "format": "slack", "events": ["crash", "kill"]}
+	]`)
+
+	l := NewLogger(townRoot)
+	for _, e := range []Event{
+		{Timestamp: time.Now(), Type: EventSpawn, Agent: "gastown/nux", Context: "gt-1"},
+		{Timestamp: time.Now(), Type: EventCrash, Agent: "gastown/nux", Context: "exit <1>"},
+	} {
+		if err := l.LogEvent(e); err != nil {
+			t.Fatalf("LogEvent: %v", err)
+		}
+	}
+	if !WaitWebhooks(5 * time.Second) {
+		t.Fatal("webhook deliveries did not finish")
+	}
+	close(bodies)
+
+	got := map[string]map[string]interface{}{}
+	for b := range bodies {
+		got[b["path"].(string)] = b
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d deliveries, want 2 (spawn is not subscribed): %v", len(got), got)
+	}
+	if j := got["/json"]; j["type"] != "crash" || j["agent"] != "gastown/nux" || j["severity"] != "error" || j["id"] == "" {
+		t.Errorf("json payload = %v", j)
+	}
+	slack := got["/slack"]
+	if len(slack) != 2 { // text, plus the path added above
+		t.Errorf("slack payload has extra fields: %v", slack)
+	}
+	if text, _ := slack["text"].(string); !strings.Contains(text, "crash*") || !strings.Contains(text, "exit &lt;1&gt;") {
+		t.Errorf("slack text = %q", text)
+	}
+}
+
+func TestWebhookDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	townRoot := t.TempDir()
+	writeWebhookSettings(t, townRoot, `[{"url": "`+srv.URL+`", "events": ["*"]}]`)
+
+	start := time.Now()
+	if err := NewLogger(townRoot).Log(EventNote, "mayor", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("LogEvent took %v waiting on a slow webhook", elapsed)
+	}
+	if WaitWebhooks(50 * time.Millisecond) {
+		t.Error("WaitWebhooks reported done while the server is still blocked")
+	}
+}
+
+func TestWebhookWants(t *testing.T) {
+	tests := []struct {
+		events []string
+		typ    EventType
+		want   bool
+	}{
+		{nil, EventCrash, true},
+		{nil, EventDone, true},
+		{nil, EventHandoff, true},
+		{nil, EventSpawn, false},
+		{[]string{"spawn,kill"}, EventKill, true},
+		{[]string{"spawn"}, EventCrash, false},
+		{[]string{"*"}, "deploy", true},
+	}
+	for _, tt := range tests {
+		if got := webhookWants(configHook(tt.events), tt.typ); got != tt.want {
+			t.Errorf("webhookWants(%v, %s) = %v, want %v", tt.events, tt.typ, got, tt.want)
+		}
+	}
+}
+
+func configHook(events []string) config.WebhookConfig {
+	return config.WebhookConfig{URL: "https://example.com/hook", Events: events}
+}
@@ -0,0 +1,226 @@
+// Package transcript archives agent session transcripts for review after
+// the session's pane is gone.
+//
+// When an agent runs gt done or gt handoff, the Claude Code transcript of
+// its session (a JSON Lines file under ~/.claude/projects/) is rendered
+// as Markdown into the town's logs/transcripts/<agent>/<timestamp>.md,
+// and the done or handoff event links to it.
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ctiospl/gastown/internal/townlog"
+)
+
+// Dir is where a town's archived transcripts are kept, relative to the
+// town root.
+const Dir = "logs/transcripts"
+
+// Limits on what is copied of long tool traffic; the source transcript
+// has it all.
+const (
+	maxToolInput   = 400 // bytes of a tool call's input
+	maxResultLines = 40  // lines of a tool result
+)
+
+// Archive renders the transcript at src as Markdown into the town's
+// transcript archive and returns the new file's path relative to the town
+// root. reason says why it was archived, e.g. "done gt-abc".
+func Archive(townRoot, agent, src, reason string, at time.Time) (string, error) {
+	entries, err := read(src)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(townRoot, Dir, filepath.FromSlash(townlog.AgentKey(agent)))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	name := at.Format("2006-01-02T15-04-05")
+	path := filepath.Join(dir, name+".md")
+	for n := 2; fileExists(path); n++ {
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d.md", name, n))
+	}
+	md := Markdown(agent, src, reason, at, entries)
+	if err := os.WriteFile(path, []byte(md), 0644); err != nil { //nolint:gosec // G306: transcripts are shared like the town log
+		return "", err
+	}
+	return filepath.ToSlash(filepath.Join(Dir, townlog.AgentKey(agent), filepath.Base(path))), nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Entry is one message of a transcript.
+type Entry struct {
+	Timestamp time.Time
+	Role      string // "user" or "assistant"
+	SessionID string
+	Parts     []Part
+}
+
+// Part is one block of a message: text, a tool call, or a tool result.
+type Part struct {
+	Kind string // "text", "tool_use", or "tool_result"
+	Text string // the text, tool input (JSON), or result
+	Tool string // tool name, for tool_use
+}
+
+// rawEntry is a line of a Claude Code transcript.
+type rawEntry struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	SessionID string    `json:"sessionId"`
+	IsMeta    bool      `json:"isMeta"`
+	Message   struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// rawBlock is a content block of a transcript message.
+type rawBlock struct {
+	Type    string          `json:"type"`
+	Text    string          `json:"text"`
+	Name    string          `json:"name"`
+	Input   json.RawMessage `json:"input"`
+	Content json.RawMessage `json:"content"`
+}
+
+// read parses a Claude Code transcript, keeping user and assistant
+// messages. Lines it cannot parse are skipped.
+func read(path string) ([]Entry, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: transcript path from the agent's project dir
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var raw rawEntry
+		if json.Unmarshal(scanner.Bytes(), &raw) != nil || raw.IsMeta || (raw.Type != "user" && raw.Type != "assistant") {
+			continue
+		}
+		e := Entry{Timestamp: raw.Timestamp, Role: raw.Type, SessionID: raw.SessionID, Parts: parts(raw.Message.Content)}
+		if len(e.Parts) > 0 {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// parts decodes message content: a string, or a list of blocks.
+func parts(content json.RawMessage) []Part {
+	var text string
+	if json.Unmarshal(content, &text) == nil {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		return []Part{{Kind: "text", Text: text}}
+	}
+	var blocks []rawBlock
+	if json.Unmarshal(content, &blocks) != nil {
+		return nil
+	}
+	var out []Part
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			if strings.TrimSpace(b.Text) != "" {
+				out = append(out, Part{Kind: "text", Text: b.Text})
+			}
+		case "tool_use":
+			out = append(out, Part{Kind: "tool_use", Tool: b.Name, Text: string(b.Input)})
+		case "tool_result":
+			out = append(out, Part{Kind: "tool_result", Text: resultText(b.Content)})
+		}
+	}
+	return out
+}
+
+// resultText returns a tool result's text: a string, or text blocks.
+func resultText(content json.RawMessage) string {
+	var text string
+	if json.Unmarshal(content, &text) == nil {
+		return text
+	}
+	var blocks []rawBlock
+	_ = json.Unmarshal(content, &blocks)
+	var texts []string
+	for _, b := range blocks {
+		if b.Type == "text" {
+			texts = append(texts, b.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// Markdown renders transcript entries for reading.
+func Markdown(agent, src, reason string, at time.Time, entries []Entry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Transcript: %s\n\n", agent)
+	fmt.Fprintf(&b, "- Archived: %s (%s)\n", at.Format("2006-01-02 15:04:05"), reason)
+	if len(entries) > 0 {
+		if id := entries[0].SessionID; id != "" {
+			fmt.Fprintf(&b, "- Session: %s\n", id)
+		}
+		fmt.Fprintf(&b, "- Started: %s\n", entries[0].Timestamp.Local().Format("2006-01-02 15:04:05"))
+	}
+	fmt.Fprintf(&b, "- Source: %s\n", src)
+
+	for _, e := range entries {
+		role := "User"
+		if e.Role == "assistant" {
+			role = "Assistant"
+		}
+		fmt.Fprintf(&b, "\n## %s · %s\n", role, e.Timestamp.Local().Format("15:04:05"))
+		for _, p := range e.Parts {
+			switch p.Kind {
+			case "text":
+				fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(p.Text))
+			case "tool_use":
+				fmt.Fprintf(&b, "\n**→ %s** `%s`\n", p.Tool, clip(oneLine(p.Text), maxToolInput))
+			case "tool_result":
+				fmt.Fprintf(&b, "\n```\n%s\n```\n", clipLines(strings.TrimRight(p.Text, "\n"), maxResultLines))
+			}
+		}
+	}
+	return b.String()
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "`", "'")), " ")
+}
+
+func clip(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "…"
+}
+
+func clipLines(s string, n int) string {
+	s = strings.ReplaceAll(s, "```", "'''")
+	lines := strings.Split(s, "\n")
+	if len(lines) <= n {
+		return s
+	}
+	return strings.Join(lines[:n], "\n") + fmt.Sprintf("\n… (%d more lines)", len(lines)-n)
+}
@@ -0,0 +1,71 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = `{"type":"summary","summary":"ignored"}
+{"type":"user","timestamp":"2026-10-14T10:00:00Z","sessionId":"abc","message":{"role":"user","content":"Fix the flaky test"}}
+{"type":"user","timestamp":"2026-10-14T10:00:00Z","isMeta":true,"message":{"role":"user","content":"<meta>"}}
+{"type":"assistant","timestamp":"2026-10-14T10:00:05Z","sessionId":"abc","message":{"role":"assistant","content":[{"type":"thinking","thinking":"hmm"},{"type":"text","text":"Running the tests."},{"type":"tool_use","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","timestamp":"2026-10-14T10:00:09Z","sessionId":"abc","message":{"role":"user","content":[{"type":"tool_result","content":[{"type":"text","text":"ok\tpkg\t0.1s"}]}]}}
+not json
+`
+
+func TestArchive(t *testing.T) {
+	townRoot := t.TempDir()
+	src := filepath.Join(t.TempDir(), "abc.jsonl")
+	if err := os.WriteFile(src, []byte(sample), 0644); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 10, 14, 10, 5, 0, 0, time.Local)
+
+	path, err := Archive(townRoot, "gastown/polecats/Toast", src, "done gt-1", at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "logs/transcripts/gastown/Toast/2026-10-14T10-05-00.md" {
+		t.Errorf("path = %s", path)
+	}
+	data, err := os.ReadFile(filepath.Join(townRoot, path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := string(data)
+	for _, want := range []string{
+		"# Transcript: gastown/polecats/Toast",
+		"(done gt-1)",
+		"- Session: abc",
+		"Fix the flaky test",
+		"Running the tests.",
+		"**→ Bash** `{\"command\":\"go test ./...\"}`",
+		"ok\tpkg\t0.1s",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("transcript lacks %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "hmm") || strings.Contains(md, "<meta>") || strings.Contains(md, "ignored") {
+		t.Errorf("transcript has thinking, meta, or summary entries:\n%s", md)
+	}
+
+	// A second archive in the same second gets its own file
+	again, err := Archive(townRoot, "gastown/Toast", src, "handoff", at)
+	if err != nil || again != "logs/transcripts/gastown/Toast/2026-10-14T10-05-00-2.md" {
+		t.Errorf("second archive = %s, %v", again, err)
+	}
+}
+
+func TestClip(t *testing.T) {
+	if got := clip("héllo", 2); got != "h…" {
+		t.Errorf("clip = %q", got)
+	}
+	long := strings.Repeat("line\n", maxResultLines+5)
+	if got := clipLines(strings.TrimRight(long, "\n"), maxResultLines); !strings.HasSuffix(got, "(5 more lines)") {
+		t.Errorf("clipLines tail = %q", got[len(got)-20:])
+	}
+}
@@ -286,6 +286,9 @@ func (m *Model) renderConvoys() string {
 		lines = append(lines, "  "+AgentIdleStyle.Render("No active convoys"))
 	} else {
 		for _, c := range m.convoyState.InProgress {
+			if m.focusConvoy != "" && c.ID != m.focusConvoy {
+				continue
+			}
 			lines = append(lines, renderConvoyLine(c, false))
 		}
 	}
@@ -298,6 +301,9 @@ func (m *Model) renderConvoys() string {
 		lines = append(lines, "  "+AgentIdleStyle.Render("No recent landings"))
 	} else {
 		for _, c := range m.convoyState.Landed {
+			if m.focusConvoy != "" && c.ID != m.focusConvoy {
+				continue
+			}
 			lines = append(lines, renderConvoyLine(c, true))
 		}
 	}
@@ -464,6 +464,9 @@ func buildEventMessage(eventType string, payload map[string]interface{}) string
 		}
 		return "mail sent"
 
+	case "mark":
+		return fmt.Sprintf("mark: %s", getPayloadString(payload, "name"))
+
 	case "merged":
 		worker := getPayloadString(payload, "worker")
 		if worker != "" {
@@ -23,6 +23,7 @@ type KeyMap struct {
 	Enter   key.Binding
 	Expand  key.Binding
 	Refresh key.Binding
+	Palette key.Binding
 
 	// Search/Filter
 	Search      key.Binding
@@ -93,6 +94,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("r"),
 			key.WithHelp("r", "refresh"),
 		),
+		Palette: key.NewBinding(
+			key.WithKeys(":", "ctrl+p"),
+			key.WithHelp(":", "commands"),
+		),
 		Search: key.NewBinding(
 			key.WithKeys("/"),
 			key.WithHelp("/", "search"),
@@ -103,7 +108,7 @@ func DefaultKeyMap() KeyMap {
 		),
 		ClearFilter: key.NewBinding(
 			key.WithKeys("esc"),
-			key.WithHelp("esc", "clear"),
+			key.WithHelp("esc", "clear filter"),
 		),
 		Help: key.NewBinding(
 			key.WithKeys("?"),
@@ -118,7 +123,7 @@ func DefaultKeyMap() KeyMap {
 
 // ShortHelp returns key bindings for the short help view.
 func (k KeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Up, k.Down, k.Tab, k.Search, k.Filter, k.Quit, k.Help}
+	return []key.Binding{k.Up, k.Down, k.Tab, k.Palette, k.Filter, k.Quit, k.Help}
 }
 
 // FullHelp returns key bindings for the full help view.
@@ -126,7 +131,7 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.PageUp, k.PageDown, k.Top, k.Bottom},
 		{k.Tab, k.FocusTree, k.FocusConvoy, k.FocusFeed, k.Enter, k.Expand},
-		{k.Search, k.Filter, k.ClearFilter, k.Refresh},
+		{k.Palette, k.Search, k.Filter, k.ClearFilter, k.Refresh},
 		{k.Help, k.Quit},
 	}
 }
@@ -1,6 +1,8 @@
 package feed
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -8,6 +10,7 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/ctiospl/gastown/internal/beads"
 )
 
@@ -75,6 +78,19 @@ type Model struct {
 	showHelp bool
 	filter   string
 
+	// Operator focus (see gt focus); empty means everything is shown
+	focusRig    string
+	focusConvoy string
+
+	// Control surface: the agent selected in the tree, the open command
+	// palette, a text filter set from it, and the last command's outcome
+	selected         string
+	selectedLine     int // tree line of the selected agent, for scrolling
+	palette          *palette
+	viewFilter       string
+	status           string
+	transcriptFinder func(agent string) string
+
 	// Event source
 	eventChan <-chan Event
 	done      chan struct{}
@@ -104,6 +120,29 @@ func (m *Model) SetTownRoot(townRoot string) {
 	m.townRoot = townRoot
 }
 
+// SetTranscriptFinder sets how the palette's "open transcript" command
+// finds an agent's transcript file; it returns "" if there is none.
+func (m *Model) SetTranscriptFinder(find func(agent string) string) {
+	m.transcriptFinder = find
+}
+
+// SetFocus limits the feed to one rig and/or convoy. Events from other
+// rigs are dropped and only the focused convoy is listed.
+func (m *Model) SetFocus(rig, convoy string) {
+	m.focusRig = rig
+	m.focusConvoy = convoy
+	var parts []string
+	if rig != "" {
+		parts = append(parts, rig)
+	}
+	if convoy != "" {
+		parts = append(parts, convoy)
+	}
+	if len(parts) > 0 {
+		m.filter = "focus " + strings.Join(parts, ", ")
+	}
+}
+
 // Init initializes the model
 func (m *Model) Init() tea.Cmd {
 	return tea.Batch(
@@ -201,6 +240,19 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tickMsg:
 		cmds = append(cmds, tick())
+
+	case actionDoneMsg:
+		m.status = msg.label + ": done"
+		if msg.output != "" {
+			m.status = msg.label + ": " + msg.output
+		}
+		if msg.err != nil {
+			m.status = fmt.Sprintf("%s failed: %v", msg.label, msg.err)
+			if msg.output != "" {
+				m.status += " (" + msg.output + ")"
+			}
+		}
+		return m, nil
 	}
 
 	// Update viewports
@@ -220,6 +272,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleKey processes key presses
 func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.palette != nil {
+		model, cmd := m.handlePaletteKey(msg)
+		m.updateViewportSizes()
+		return model, cmd
+	}
+
 	switch {
 	case key.Matches(msg, m.keys.Quit):
 		m.closeOnce.Do(func() { close(m.done) })
@@ -257,6 +315,33 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.Refresh):
 		m.updateViewContent()
 		return m, nil
+
+	case key.Matches(msg, m.keys.Palette):
+		m.palette = newPalette()
+		m.status = ""
+		m.updateViewportSizes()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Search), key.Matches(msg, m.keys.Filter):
+		m.palette = newPalette()
+		for _, c := range paletteCommands() {
+			if c.Name == "filter view" {
+				m.palette.ask(c)
+			}
+		}
+		m.palette.input = []rune(m.viewFilter)
+		m.updateViewportSizes()
+		return m, nil
+
+	case key.Matches(msg, m.keys.ClearFilter):
+		m.viewFilter = ""
+		m.status = ""
+		m.updateViewContent()
+		return m, nil
+
+	case m.focusedPanel == PanelTree && key.Matches(msg, m.keys.Up, m.keys.Down):
+		m.moveSelection(key.Matches(msg, m.keys.Down))
+		return m, nil
 	}
 
 	// Pass to focused viewport
@@ -281,6 +366,9 @@ func (m *Model) updateViewportSizes() {
 	if m.showHelp {
 		helpHeight = 3
 	}
+	if m.palette != nil {
+		helpHeight += lipgloss.Height(m.renderPalette())
+	}
 	borderHeight := 6 // top and bottom borders for 3 panels
 
 	availableHeight := m.height - headerHeight - statusHeight - helpHeight - borderHeight
@@ -326,8 +414,68 @@ func (m *Model) updateViewContent() {
 	m.feedViewport.SetContent(m.renderFeed())
 }
 
+// selectedAgent returns the agent selected in the tree, or nil.
+func (m *Model) selectedAgent() *Agent {
+	for _, a := range m.treeAgents() {
+		if a.ID == m.selected {
+			return a
+		}
+	}
+	return nil
+}
+
+// moveSelection selects the next (or previous) agent in the tree and
+// scrolls it into view.
+func (m *Model) moveSelection(down bool) {
+	agents := m.treeAgents()
+	if len(agents) == 0 {
+		return
+	}
+	i := -1
+	for j, a := range agents {
+		if a.ID == m.selected {
+			i = j
+		}
+	}
+	switch {
+	case i < 0:
+		i = 0
+	case down && i < len(agents)-1:
+		i++
+	case !down && i > 0:
+		i--
+	}
+	m.selected = agents[i].ID
+	m.updateViewContent()
+	if line := m.selectedLine; line < m.treeViewport.YOffset {
+		m.treeViewport.SetYOffset(line)
+	} else if line >= m.treeViewport.YOffset+m.treeViewport.Height {
+		m.treeViewport.SetYOffset(line - m.treeViewport.Height + 1)
+	}
+}
+
+// matchesViewFilter reports whether text contains the palette's filter,
+// ignoring case.
+func (m *Model) matchesViewFilter(text ...string) bool {
+	if m.viewFilter == "" {
+		return true
+	}
+	f := strings.ToLower(m.viewFilter)
+	for _, t := range text {
+		if strings.Contains(strings.ToLower(t), f) {
+			return true
+		}
+	}
+	return false
+}
+
 // addEvent adds an event and updates the agent tree
 func (m *Model) addEvent(e Event) {
+	// Drop events from rigs outside the operator's focus
+	if m.focusRig != "" && e.Rig != "" && e.Rig != m.focusRig {
+		return
+	}
+
 	// Update agent tree first (always do this for status tracking)
 	if e.Rig != "" {
 		rig, ok := m.rigs[e.Rig]
@@ -0,0 +1,343 @@
+package feed
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteCommand is one operation offered by the command palette.
+type paletteCommand struct {
+	Name       string
+	Help       string
+	Prompt     string // asks for an argument before running; "" runs at once
+	NeedsAgent bool   // acts on the agent selected in the tree
+	run        func(m *Model, a *Agent, arg string) tea.Cmd
+}
+
+// paletteCommands returns the palette's commands, in the order they are
+// listed before anything is typed.
+func paletteCommands() []paletteCommand {
+	return []paletteCommand{
+		{Name: "spawn", Help: "sling an issue to a new polecat", Prompt: "issue [rig]", run: runSpawn},
+		{Name: "nudge selected", Help: "send the selected agent a message", Prompt: "message", NeedsAgent: true, run: runNudge},
+		{Name: "kill selected", Help: "stop the selected agent's session", Prompt: "stop it? (y/N)", NeedsAgent: true, run: runKill},
+		{Name: "open transcript", Help: "page through the selected agent's transcript", NeedsAgent: true, run: runTranscript},
+		{Name: "filter view", Help: "show only agents and events matching text", Prompt: "text", run: runFilter},
+		{Name: "clear filter", Help: "show everything again", run: func(m *Model, _ *Agent, _ string) tea.Cmd {
+			m.viewFilter = ""
+			m.updateViewContent()
+			return nil
+		}},
+	}
+}
+
+// palette is the state of an open command palette.
+type palette struct {
+	prompt      string
+	placeholder string
+	input       []rune
+	matches     []paletteCommand // commands matching the input, best first
+	cursor      int
+	pending     *paletteCommand // command waiting for its argument
+}
+
+func newPalette() *palette {
+	p := &palette{prompt: ": ", placeholder: "type a command"}
+	p.refresh()
+	return p
+}
+
+// ask switches the palette to reading cmd's argument.
+func (p *palette) ask(cmd paletteCommand) {
+	p.pending = &cmd
+	p.input = nil
+	p.prompt = cmd.Name + ": "
+	p.placeholder = cmd.Prompt
+}
+
+// value returns the text typed so far.
+func (p *palette) value() string {
+	return string(p.input)
+}
+
+// edit applies a key press to the input, reporting whether it was one.
+func (p *palette) edit(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyRunes:
+		p.input = append(p.input, msg.Runes...)
+	case tea.KeySpace:
+		p.input = append(p.input, ' ')
+	case tea.KeyBackspace:
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+	case tea.KeyCtrlU:
+		p.input = nil
+	default:
+		return false
+	}
+	return true
+}
+
+// refresh re-ranks the commands against the input.
+func (p *palette) refresh() {
+	p.matches = rankCommands(paletteCommands(), p.value())
+	if p.cursor >= len(p.matches) {
+		p.cursor = max(0, len(p.matches)-1)
+	}
+}
+
+// rankCommands returns the commands whose names fuzzy-match query, best
+// match first.
+func rankCommands(cmds []paletteCommand, query string) []paletteCommand {
+	type scored struct {
+		cmd   paletteCommand
+		score int
+	}
+	var ranked []scored
+	for _, c := range cmds {
+		if score, ok := fuzzyScore(query, c.Name); ok {
+			ranked = append(ranked, scored{c, score})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+	out := make([]paletteCommand, len(ranked))
+	for i, r := range ranked {
+		out[i] = r.cmd
+	}
+	return out
+}
+
+// fuzzyScore reports whether the letters of query appear in s in order,
+// ignoring case and spaces, and scores the match: consecutive letters and
+// letters at the start of a word score higher.
+func fuzzyScore(query, s string) (int, bool) {
+	q := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	r := []rune(strings.ToLower(s))
+	score, qi, prev := 0, 0, -2
+	for i := 0; i < len(r) && qi < len(q); i++ {
+		if r[i] != q[qi] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 2
+		}
+		if i == 0 || !unicode.IsLetter(r[i-1]) {
+			score += 3
+		}
+		prev = i
+		qi++
+	}
+	return score, qi == len(q)
+}
+
+// handlePaletteKey handles a key press while the palette is open.
+func (m *Model) handlePaletteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.palette
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.palette = nil
+		return m, nil
+	case "up", "ctrl+p":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return m, nil
+	case "down", "ctrl+n":
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+		return m, nil
+	case "enter":
+		if p.pending != nil {
+			cmd, arg := *p.pending, strings.TrimSpace(p.value())
+			m.palette = nil
+			return m, m.runPaletteCommand(cmd, arg)
+		}
+		if len(p.matches) == 0 {
+			return m, nil
+		}
+		cmd := p.matches[p.cursor]
+		if cmd.NeedsAgent && m.selectedAgent() == nil {
+			m.palette = nil
+			m.status = cmd.Name + ": select an agent in the tree first"
+			return m, nil
+		}
+		if cmd.Prompt != "" {
+			p.ask(cmd)
+			return m, nil
+		}
+		m.palette = nil
+		return m, m.runPaletteCommand(cmd, "")
+	}
+
+	if p.edit(msg) && p.pending == nil {
+		p.refresh()
+	}
+	return m, nil
+}
+
+func (m *Model) runPaletteCommand(cmd paletteCommand, arg string) tea.Cmd {
+	a := m.selectedAgent()
+	if cmd.NeedsAgent && a == nil {
+		m.status = cmd.Name + ": select an agent in the tree first"
+		return nil
+	}
+	return cmd.run(m, a, arg)
+}
+
+// actionDoneMsg reports the outcome of a palette command run in the
+// background.
+type actionDoneMsg struct {
+	label  string
+	output string
+	err    error
+}
+
+// runGT runs a gt subcommand in the background and reports it as label.
+func (m *Model) runGT(label string, args ...string) tea.Cmd {
+	m.status = label + "..."
+	townRoot := m.townRoot
+	return func() tea.Msg {
+		gt, err := os.Executable()
+		if err != nil {
+			gt = "gt"
+		}
+		c := exec.Command(gt, args...) //nolint:gosec // G204: args are built from palette commands
+		c.Dir = townRoot
+		out, err := c.CombinedOutput()
+		return actionDoneMsg{label: label, output: lastLine(string(out)), err: err}
+	}
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+func runSpawn(m *Model, a *Agent, arg string) tea.Cmd {
+	fields := strings.Fields(arg)
+	if len(fields) == 0 || len(fields) > 2 {
+		m.status = "spawn: give an issue and optionally a rig, e.g. gt-abc gastown"
+		return nil
+	}
+	args := append([]string{"sling"}, fields...)
+	if len(fields) == 1 {
+		rig := m.focusRig
+		if a != nil {
+			rig = a.Rig
+		}
+		if rig == "" {
+			m.status = "spawn: no rig selected; give one after the issue"
+			return nil
+		}
+		args = append(args, rig)
+	}
+	return m.runGT("spawn "+strings.Join(args[1:], " "), args...)
+}
+
+func runNudge(m *Model, a *Agent, arg string) tea.Cmd {
+	if arg == "" {
+		m.status = "nudge: no message given"
+		return nil
+	}
+	addr := agentAddress(a)
+	return m.runGT("nudge "+addr, "nudge", addr, "-m", arg)
+}
+
+func runKill(m *Model, a *Agent, arg string) tea.Cmd {
+	if !strings.EqualFold(arg, "y") && !strings.EqualFold(arg, "yes") {
+		m.status = "kill: cancelled"
+		return nil
+	}
+	args := stopArgs(a)
+	if args == nil {
+		m.status = "kill: don't know how to stop " + a.ID
+		return nil
+	}
+	return m.runGT("stop "+agentAddress(a), args...)
+}
+
+func runTranscript(m *Model, a *Agent, _ string) tea.Cmd {
+	path := ""
+	if m.transcriptFinder != nil {
+		path = m.transcriptFinder(agentAddress(a))
+	}
+	if path == "" {
+		m.status = "no transcript found for " + agentAddress(a)
+		return nil
+	}
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+	c := exec.Command("sh", "-c", pager+` "$0"`, path) //nolint:gosec // G204: the user's own pager
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return actionDoneMsg{label: "transcript " + agentAddress(a), err: err}
+	})
+}
+
+func runFilter(m *Model, _ *Agent, arg string) tea.Cmd {
+	m.viewFilter = arg
+	m.updateViewContent()
+	return nil
+}
+
+// agentAddress returns the gt address of a tree agent, e.g.
+// "gastown/Toast" for a polecat or "gastown/crew/joe" for crew.
+func agentAddress(a *Agent) string {
+	parts := strings.Split(a.ID, "/")
+	if len(parts) == 3 && parts[1] == "polecats" {
+		return parts[0] + "/" + parts[2]
+	}
+	return a.ID
+}
+
+// stopArgs returns the gt command that stops an agent's session, or nil.
+func stopArgs(a *Agent) []string {
+	parts := strings.Split(agentAddress(a), "/")
+	switch {
+	case len(parts) == 1 && (parts[0] == "mayor" || parts[0] == "deacon"):
+		return []string{parts[0], "stop"}
+	case len(parts) == 2 && (parts[1] == "witness" || parts[1] == "refinery"):
+		return []string{parts[1], "stop", parts[0]}
+	case len(parts) == 2:
+		return []string{"session", "stop", agentAddress(a)}
+	case len(parts) == 3 && parts[1] == "crew":
+		return []string{"crew", "stop", parts[0] + "/" + parts[2]}
+	}
+	return nil
+}
+
+// renderPalette renders the open palette: its input line and, until a
+// command is chosen, the matching commands.
+func (m *Model) renderPalette() string {
+	p := m.palette
+	input := p.value() + "█"
+	if len(p.input) == 0 {
+		input = "█" + HelpDescStyle.Render(p.placeholder)
+	}
+	lines := []string{PaletteSelectedStyle.Render(p.prompt) + input}
+	if p.pending == nil {
+		for i, c := range p.matches {
+			line := fmt.Sprintf("  %-16s %s", c.Name, HelpDescStyle.Render(c.Help))
+			if i == p.cursor {
+				line = PaletteSelectedStyle.Render("▸ "+c.Name) + strings.Repeat(" ", max(1, 17-len(c.Name))) + HelpDescStyle.Render(c.Help)
+			}
+			lines = append(lines, line)
+		}
+		if len(p.matches) == 0 {
+			lines = append(lines, HelpDescStyle.Render("  no matching command"))
+		}
+	} else if a := m.selectedAgent(); a != nil && p.pending.NeedsAgent {
+		lines = append(lines, HelpDescStyle.Render("  "+agentAddress(a)))
+	}
+	return PalettePanelStyle.Width(m.width - 2).Render(strings.Join(lines, "\n"))
+}
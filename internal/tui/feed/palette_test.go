@@ -0,0 +1,87 @@
+package feed
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRankCommands(t *testing.T) {
+	cmds := paletteCommands()
+	tests := []struct {
+		query string
+		want  string // best match
+	}{
+		{"", "spawn"},
+		{"nu", "nudge selected"},
+		{"ks", "kill selected"},
+		{"tr", "open transcript"},
+		{"fil", "filter view"},
+		{"clear", "clear filter"},
+	}
+	for _, tt := range tests {
+		got := rankCommands(cmds, tt.query)
+		if len(got) == 0 || got[0].Name != tt.want {
+			t.Errorf("rankCommands(%q) best = %v, want %q", tt.query, got, tt.want)
+		}
+	}
+	if got := rankCommands(cmds, "xyz"); len(got) != 0 {
+		t.Errorf("rankCommands(\"xyz\") = %v, want no matches", got)
+	}
+}
+
+func TestStopArgs(t *testing.T) {
+	tests := []struct {
+		id   string
+		want []string
+	}{
+		{"gastown/polecats/Toast", []string{"session", "stop", "gastown/Toast"}},
+		{"gastown/Toast", []string{"session", "stop", "gastown/Toast"}},
+		{"gastown/crew/joe", []string{"crew", "stop", "gastown/joe"}},
+		{"gastown/witness", []string{"witness", "stop", "gastown"}},
+		{"mayor", []string{"mayor", "stop"}},
+		{"somewhere/else/entirely/deep", nil},
+	}
+	for _, tt := range tests {
+		if got := stopArgs(&Agent{ID: tt.id}); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("stopArgs(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestPaletteFilter(t *testing.T) {
+	m := NewModel()
+	m.width, m.height = 100, 40
+	m.addEvent(Event{Type: "spawn", Actor: "gastown/crew/joe", Rig: "gastown", Role: "crew", Message: "started"})
+	m.addEvent(Event{Type: "spawn", Actor: "beads/crew/ann", Rig: "beads", Role: "crew", Message: "started"})
+
+	// Select the first agent and open the palette
+	m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	if a := m.selectedAgent(); a == nil || a.ID != "beads/crew/ann" {
+		t.Fatalf("selected agent = %v, want beads/crew/ann", a)
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	if m.palette == nil {
+		t.Fatal("palette did not open")
+	}
+	for _, r := range "filt" {
+		m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	for _, r := range "joe" {
+		m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.palette != nil || m.viewFilter != "joe" {
+		t.Fatalf("after filtering, palette = %v, filter = %q", m.palette, m.viewFilter)
+	}
+	if agents := m.treeAgents(); len(agents) != 1 || agents[0].ID != "gastown/crew/joe" {
+		t.Errorf("tree agents = %v, want only joe", agents)
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if len(m.treeAgents()) != 2 {
+		t.Errorf("esc should clear the filter")
+	}
+}
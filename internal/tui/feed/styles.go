@@ -93,6 +93,20 @@ var (
 	HelpDescStyle = lipgloss.NewStyle().
 			Foreground(colorDim)
 
+	// Command palette
+	PalettePanelStyle = lipgloss.NewStyle().
+				Border(lipgloss.RoundedBorder()).
+				BorderForeground(colorAccent).
+				Padding(0, 1)
+
+	PaletteSelectedStyle = lipgloss.NewStyle().
+				Foreground(colorHighlight).
+				Bold(true)
+
+	// The agent selected in the tree, which palette commands act on
+	AgentSelectedStyle = lipgloss.NewStyle().
+				Reverse(true)
+
 	// Focus indicator
 	FocusedBorderStyle = lipgloss.NewStyle().
 				Border(lipgloss.RoundedBorder()).
@@ -155,5 +169,6 @@ var (
 		"nudge":   "⚡",
 		"boot":    "🔌",
 		"halt":    "⏹",
+		"mark":    "🔖",
 	}
 )
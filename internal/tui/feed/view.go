@@ -32,6 +32,11 @@ func (m *Model) render() string {
 	feedPanel := m.renderFeedPanel()
 	sections = append(sections, feedPanel)
 
+	// Command palette (if open)
+	if m.palette != nil {
+		sections = append(sections, m.renderPalette())
+	}
+
 	// Status bar
 	sections = append(sections, m.renderStatusBar())
 
@@ -47,12 +52,17 @@ func (m *Model) render() string {
 func (m *Model) renderHeader() string {
 	title := TitleStyle.Render("GT Feed")
 
-	filter := ""
+	var filters []string
 	if m.filter != "" {
-		filter = FilterStyle.Render(fmt.Sprintf("Filter: %s", m.filter))
-	} else {
-		filter = FilterStyle.Render("Filter: all")
+		filters = append(filters, m.filter)
+	}
+	if m.viewFilter != "" {
+		filters = append(filters, fmt.Sprintf("%q", m.viewFilter))
+	}
+	if len(filters) == 0 {
+		filters = append(filters, "all")
 	}
+	filter := FilterStyle.Render("Filter: " + strings.Join(filters, ", "))
 
 	// Right-align filter
 	gap := m.width - lipgloss.Width(title) - lipgloss.Width(filter) - 4
@@ -81,6 +91,9 @@ func (m *Model) renderFeedPanel() string {
 	return style.Width(m.width - 2).Render(m.feedViewport.View())
 }
 
+// treeRoleOrder is the order roles are listed in under each rig.
+var treeRoleOrder = []string{"mayor", "witness", "refinery", "deacon", "crew", "polecat"}
+
 // renderTree renders the agent tree content
 func (m *Model) renderTree() string {
 	if len(m.rigs) == 0 {
@@ -88,27 +101,20 @@ func (m *Model) renderTree() string {
 	}
 
 	var lines []string
+	m.selectedLine = 0
 
-	// Sort rigs by name
-	rigNames := make([]string, 0, len(m.rigs))
-	for name := range m.rigs {
-		rigNames = append(rigNames, name)
-	}
-	sort.Strings(rigNames)
-
-	for _, rigName := range rigNames {
-		rig := m.rigs[rigName]
+	for _, rigName := range m.rigNames() {
+		byRole := m.groupAgentsByRole(m.visibleAgents(m.rigs[rigName]))
+		if len(byRole) == 0 && m.viewFilter != "" {
+			continue
+		}
 
 		// Rig header
 		rigLine := RigStyle.Render(rigName + "/")
 		lines = append(lines, rigLine)
 
-		// Group agents by role
-		byRole := m.groupAgentsByRole(rig.Agents)
-
 		// Render each role group
-		roleOrder := []string{"mayor", "witness", "refinery", "deacon", "crew", "polecat"}
-		for _, role := range roleOrder {
+		for _, role := range treeRoleOrder {
 			agents, ok := byRole[role]
 			if !ok || len(agents) == 0 {
 				continue
@@ -121,19 +127,61 @@ func (m *Model) renderTree() string {
 
 			// For crew and polecats, show as expandable group
 			if role == "crew" || role == "polecat" {
-				lines = append(lines, m.renderAgentGroup(icon, role, agents))
+				lines = append(lines, m.renderAgentGroup(icon, role, agents, len(lines)))
 			} else {
 				// Single agents (mayor, witness, refinery)
 				for _, agent := range agents {
+					if agent.ID == m.selected {
+						m.selectedLine = len(lines)
+					}
 					lines = append(lines, m.renderAgent(icon, agent, 2))
 				}
 			}
 		}
 	}
+	if len(lines) == 0 {
+		return AgentIdleStyle.Render("No agents match " + m.viewFilter)
+	}
 
 	return strings.Join(lines, "\n")
 }
 
+// rigNames returns the tree's rigs, sorted by name.
+func (m *Model) rigNames() []string {
+	names := make([]string, 0, len(m.rigs))
+	for name := range m.rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// visibleAgents returns the agents of rig that match the view filter.
+func (m *Model) visibleAgents(rig *Rig) map[string]*Agent {
+	if m.viewFilter == "" {
+		return rig.Agents
+	}
+	visible := make(map[string]*Agent)
+	for id, a := range rig.Agents {
+		if m.matchesViewFilter(a.ID, a.Role) {
+			visible[id] = a
+		}
+	}
+	return visible
+}
+
+// treeAgents returns the agents shown in the tree, in display order.
+func (m *Model) treeAgents() []*Agent {
+	var out []*Agent
+	for _, rigName := range m.rigNames() {
+		byRole := m.groupAgentsByRole(m.visibleAgents(m.rigs[rigName]))
+		for _, role := range treeRoleOrder {
+			out = append(out, byRole[role]...)
+		}
+	}
+	return out
+}
+
 // groupAgentsByRole groups agents by their role
 func (m *Model) groupAgentsByRole(agents map[string]*Agent) map[string][]*Agent {
 	result := make(map[string][]*Agent)
@@ -155,8 +203,9 @@ func (m *Model) groupAgentsByRole(agents map[string]*Agent) map[string][]*Agent
 	return result
 }
 
-// renderAgentGroup renders a group of agents (crew or polecats)
-func (m *Model) renderAgentGroup(icon, role string, agents []*Agent) string {
+// renderAgentGroup renders a group of agents (crew or polecats) starting
+// at tree line first.
+func (m *Model) renderAgentGroup(icon, role string, agents []*Agent, first int) string {
 	var lines []string
 
 	// Group header
@@ -169,6 +218,9 @@ func (m *Model) renderAgentGroup(icon, role string, agents []*Agent) string {
 
 	// Individual agents
 	for _, agent := range agents {
+		if agent.ID == m.selected {
+			m.selectedLine = first + len(lines)
+		}
 		lines = append(lines, m.renderAgent("", agent, 5))
 	}
 
@@ -209,6 +261,9 @@ func (m *Model) renderAgent(icon string, agent *Agent, indent int) string {
 		activity = fmt.Sprintf(" [%s] %s", age, msg)
 	}
 
+	if agent.ID == m.selected {
+		nameStyle = nameStyle.Inherit(AgentSelectedStyle)
+	}
 	line := prefix + nameStyle.Render(name+statusIndicator) + TimestampStyle.Render(activity)
 	return line
 }
@@ -229,8 +284,14 @@ func (m *Model) renderFeed() string {
 
 	for i := len(m.events) - 1; i >= start; i-- {
 		event := m.events[i]
+		if !m.matchesViewFilter(event.Actor, event.Target, event.Message, event.Type) {
+			continue
+		}
 		lines = append(lines, m.renderEvent(event))
 	}
+	if len(lines) == 0 {
+		return AgentIdleStyle.Render("No events match " + m.viewFilter)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -313,8 +374,11 @@ func (m *Model) renderStatusBar() string {
 	}
 	panel := fmt.Sprintf("[%s]", panelName)
 
-	// Event count
+	// Event count, or the outcome of the last palette command
 	count := fmt.Sprintf("%d events", len(m.events))
+	if m.status != "" {
+		count = m.status
+	}
 
 	// Short help
 	help := m.renderShortHelp()
@@ -334,7 +398,8 @@ func (m *Model) renderShortHelp() string {
 	hints := []string{
 		HelpKeyStyle.Render("j/k") + HelpDescStyle.Render(":scroll"),
 		HelpKeyStyle.Render("tab") + HelpDescStyle.Render(":switch"),
-		HelpKeyStyle.Render("/") + HelpDescStyle.Render(":search"),
+		HelpKeyStyle.Render("ctrl+p") + HelpDescStyle.Render(":commands"),
+		HelpKeyStyle.Render("/") + HelpDescStyle.Render(":filter"),
 		HelpKeyStyle.Render("q") + HelpDescStyle.Render(":quit"),
 		HelpKeyStyle.Render("?") + HelpDescStyle.Render(":help"),
 	}
@@ -0,0 +1,15 @@
+//go:build unix
+
+package util
+
+import "syscall"
+
+// FreeDiskSpace returns the bytes available to unprivileged users on the
+// filesystem containing path.
+func FreeDiskSpace(path string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil //nolint:unconvert // field types differ across platforms
+}
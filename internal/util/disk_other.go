@@ -0,0 +1,10 @@
+//go:build !unix
+
+package util
+
+import "errors"
+
+// FreeDiskSpace is not supported off Unix; it returns errors.ErrUnsupported.
+func FreeDiskSpace(path string) (uint64, error) {
+	return 0, errors.ErrUnsupported
+}
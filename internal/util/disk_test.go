@@ -0,0 +1,17 @@
+package util
+
+import "testing"
+
+func TestFreeDiskSpace(t *testing.T) {
+	free, err := FreeDiskSpace(t.TempDir())
+	if err != nil {
+		t.Fatalf("FreeDiskSpace: %v", err)
+	}
+	if free == 0 {
+		t.Error("FreeDiskSpace = 0 for a writable temp dir")
+	}
+
+	if _, err := FreeDiskSpace("/nonexistent/gt-disk-test"); err == nil {
+		t.Error("FreeDiskSpace succeeded for a missing path")
+	}
+}
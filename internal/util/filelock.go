@@ -0,0 +1,30 @@
+//go:build unix
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// WithFileLock runs fn while holding an exclusive flock on the file at
+// path, creating it and its directory if needed. Use it around a
+// load-modify-save of a shared file so concurrent gt processes do not lose
+// each other's changes; by convention the lock file is the data file's
+// path plus ".lock".
+func WithFileLock(path string, fn func() error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600) //nolint:gosec // G304: callers pass paths within the town
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return err
+	}
+	defer func() { _ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN) }()
+	return fn()
+}
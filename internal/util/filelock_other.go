@@ -0,0 +1,25 @@
+//go:build !unix
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// fileLockMu stands in for flock off Unix. It only serializes callers
+// within this process.
+var fileLockMu sync.Mutex
+
+// WithFileLock runs fn while holding the lock for path. Off Unix there is
+// no flock, so the lock is process-wide rather than per file and does not
+// exclude other processes.
+func WithFileLock(path string, fn func() error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	fileLockMu.Lock()
+	defer fileLockMu.Unlock()
+	return fn()
+}
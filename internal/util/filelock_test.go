@@ -0,0 +1,45 @@
+package util
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestWithFileLockSerializes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "counter")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := WithFileLock(path+".lock", func() error {
+				data, _ := os.ReadFile(path)
+				n, _ := strconv.Atoi(string(data))
+				return os.WriteFile(path, []byte(strconv.Itoa(n+1)), 0644)
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "20" {
+		t.Errorf("counter = %s, want 20", data)
+	}
+}
+
+func TestWithFileLockReturnsFnError(t *testing.T) {
+	want := errors.New("boom")
+	if err := WithFileLock(filepath.Join(t.TempDir(), "x.lock"), func() error { return want }); err != want {
+		t.Errorf("err = %v, want %v", err, want)
+	}
+}
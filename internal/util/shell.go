@@ -0,0 +1,8 @@
+package util
+
+import "strings"
+
+// ShellQuote quotes s as a single POSIX sh word, expanding nothing in it.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
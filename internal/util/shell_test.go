@@ -0,0 +1,18 @@
+package util
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	for _, s := range []string{"", "plain", "it's", "$(rm -rf ~) `x` \"y\"", "a\nb", "'''"} {
+		out, err := exec.Command("sh", "-c", "printf %s "+ShellQuote(s)).Output()
+		if err != nil {
+			t.Fatalf("sh: %v", err)
+		}
+		if string(out) != s {
+			t.Errorf("ShellQuote(%q) read back as %q", s, out)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Package verify runs a rig's post-done validation suite: the checks
+// configured under "verify" in the rig settings, run in an agent's
+// worktree after it reports its work done.
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/config"
+)
+
+// DefaultTimeout bounds a check without its own timeout.
+const DefaultTimeout = 10 * time.Minute
+
+// outputLines is how much of a failed check's output a result keeps.
+const outputLines = 20
+
+// CheckResult is the outcome of one check.
+type CheckResult struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Optional bool          `json:"optional,omitempty"`
+	Duration time.Duration `json:"-"`
+
+	// Error says why a failed check failed, e.g. "exit status 1" or
+	// "timed out after 10m0s".
+	Error string `json:"error,omitempty"`
+
+	// Output holds the last lines a failed check printed.
+	Output string `json:"output,omitempty"`
+}
+
+// MarshalJSON encodes c with its duration as duration_seconds.
+func (c CheckResult) MarshalJSON() ([]byte, error) {
+	type plain CheckResult
+	return json.Marshal(struct {
+		plain
+		Seconds float64 `json:"duration_seconds"`
+	}{plain(c), c.Duration.Seconds()})
+}
+
+// Result is the outcome of a suite run.
+type Result struct {
+	Checks []CheckResult `json:"checks"`
+}
+
+// Passed reports whether every required check passed.
+func (r Result) Passed() bool {
+	return len(r.Failed()) == 0
+}
+
+// Failed returns the names of the required checks that failed.
+func (r Result) Failed() []string {
+	var names []string
+	for _, c := range r.Checks {
+		if !c.Passed && !c.Optional {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// Summary describes r in a line, e.g. "passed 4 check(s)" or
+// "failed lint, secrets (2/4 passed)". Failed optional checks are noted
+// without failing the summary.
+func (r Result) Summary() string {
+	passed := 0
+	var optional []string
+	for _, c := range r.Checks {
+		switch {
+		case c.Passed:
+			passed++
+		case c.Optional:
+			optional = append(optional, c.Name)
+		}
+	}
+	var s string
+	if failed := r.Failed(); len(failed) > 0 {
+		s = fmt.Sprintf("failed %s (%d/%d passed)", strings.Join(failed, ", "), passed, len(r.Checks))
+	} else {
+		s = fmt.Sprintf("passed %d check(s)", passed)
+	}
+	if len(optional) > 0 {
+		s += fmt.Sprintf("; optional %s failed", strings.Join(optional, ", "))
+	}
+	return s
+}
+
+// CheckName returns the name of the i'th check (from 0): its own, or its
+// position ("check-1").
+func CheckName(i int, check config.VerifyCheck) string {
+	if check.Name != "" {
+		return check.Name
+	}
+	return fmt.Sprintf("check-%d", i+1)
+}
+
+// Run runs checks in order in worktree, each even if an earlier one
+// failed, with env added to the environment. Progress and each check's
+// output are written to out. A cancelled ctx fails the remaining checks.
+func Run(ctx context.Context, worktree string, checks []config.VerifyCheck, env []string, out io.Writer) Result {
+	var r Result
+	for i, check := range checks {
+		name := CheckName(i, check)
+		_, _ = fmt.Fprintf(out, "==> %s: %s\n", name, check.Run)
+		res := runCheck(ctx, worktree, check, env, out)
+		res.Name, res.Optional = name, check.Optional
+		if res.Passed {
+			_, _ = fmt.Fprintf(out, "==> %s passed in %s\n", name, res.Duration.Round(time.Millisecond))
+		} else {
+			_, _ = fmt.Fprintf(out, "==> %s failed: %s\n", name, res.Error)
+		}
+		r.Checks = append(r.Checks, res)
+	}
+	return r
+}
+
+// runCheck runs one check, copying its output to out.
+func runCheck(ctx context.Context, worktree string, check config.VerifyCheck, env []string, out io.Writer) CheckResult {
+	timeout := DefaultTimeout
+	if check.Timeout != "" {
+		if d, err := time.ParseDuration(check.Timeout); err == nil {
+			timeout = d
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", check.Run) //nolint:gosec // G204: command comes from the rig's own settings
+	cmd.Dir = worktree
+	cmd.Env = append(os.Environ(), env...)
+	var output bytes.Buffer
+	cmd.Stdout = io.MultiWriter(&output, out)
+	cmd.Stderr = cmd.Stdout
+	// Children that outlive a killed shell would hold the output open.
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	err := cmd.Run()
+	res := CheckResult{Passed: err == nil, Duration: time.Since(start)}
+	if err != nil {
+		res.Error = err.Error()
+		if ctx.Err() == context.DeadlineExceeded {
+			res.Error = fmt.Sprintf("timed out after %s", timeout)
+		}
+		res.Output = lastLines(output.String(), outputLines)
+	}
+	return res
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
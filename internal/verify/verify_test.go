@@ -0,0 +1,61 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ctiospl/gastown/internal/config"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	checks := []config.VerifyCheck{
+		{Name: "build", Run: "test -f go.mod"},
+		{Name: "lint", Run: "echo 'bad style'; exit 1", Optional: true},
+		{Run: "echo $GT_VERIFY_ISSUE; echo leaked key >&2; exit 3"},
+		{Name: "slow", Run: "sleep 5", Timeout: "50ms"},
+	}
+	var out bytes.Buffer
+	r := Run(context.Background(), dir, checks, []string{"GT_VERIFY_ISSUE=gt-abc"}, &out)
+
+	if len(r.Checks) != 4 {
+		t.Fatalf("got %d results, want 4", len(r.Checks))
+	}
+	if !r.Checks[0].Passed {
+		t.Errorf("build failed: %+v", r.Checks[0])
+	}
+	if c := r.Checks[1]; c.Passed || !c.Optional || c.Output != "bad style" {
+		t.Errorf("lint = %+v, want an optional failure with its output", c)
+	}
+	if c := r.Checks[2]; c.Name != "check-3" || c.Passed || c.Output != "gt-abc\nleaked key" {
+		t.Errorf("check-3 = %+v, want a failure with env and stderr captured", c)
+	}
+	if c := r.Checks[3]; c.Passed || !strings.Contains(c.Error, "timed out") {
+		t.Errorf("slow = %+v, want a timeout", c)
+	}
+	if !strings.Contains(out.String(), "==> build passed") {
+		t.Errorf("progress output missing build:\n%s", out.String())
+	}
+
+	if r.Passed() {
+		t.Error("Passed() = true with failed required checks")
+	}
+	if got, want := r.Summary(), "failed check-3, slow (1/4 passed); optional lint failed"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	r = Result{Checks: []CheckResult{{Name: "build", Passed: true}, {Name: "lint", Optional: true}}}
+	if !r.Passed() {
+		t.Error("Passed() = false with only an optional failure")
+	}
+	if got, want := r.Summary(), "passed 1 check(s); optional lint failed"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
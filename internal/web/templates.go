@@ -5,6 +5,7 @@ import (
 	"embed"
 	"html/template"
 	"io/fs"
+	"time"
 
 	"github.com/ctiospl/gastown/internal/activity"
 )
@@ -60,6 +61,49 @@ type TrackedIssue struct {
 	Assignee string
 }
 
+// ShareData represents data passed to the share template: a read-only
+// live view of one agent's session.
+type ShareData struct {
+	Agent     string    // e.g., "gastown/polecats/nux"
+	ExpiresAt time.Time // when the link stops working
+	StatusURL string    // JSON status the page polls
+}
+
+// ReportData represents data passed to the report template: town log
+// activity grouped by agent (see 'gt log report').
+type ReportData struct {
+	Title   string
+	Window  string // e.g., "2026-10-13 09:00 → 2026-10-14 09:00"
+	Events  int
+	Crashes int
+	Agents  []ReportAgent // most events first
+}
+
+// ReportAgent is one agent's section of a report.
+type ReportAgent struct {
+	Agent   string // e.g., "gastown/polecats/nux"
+	Anchor  string // fragment ID of the section
+	Spawns  int
+	Done    int
+	Crashes int
+	Entries []ReportEntry // oldest first
+}
+
+// ReportEntry is one event in a report.
+type ReportEntry struct {
+	Time     string // local time, e.g., "09:30"
+	Type     string
+	Severity string // "info", "warn", or "error"
+	Detail   []ReportSpan
+	Notes    []string // operator annotations (see gt log note)
+}
+
+// ReportSpan is a run of detail text, linked when URL is set.
+type ReportSpan struct {
+	Text string
+	URL  string
+}
+
 // LoadTemplates loads and parses all HTML templates.
 func LoadTemplates() (*template.Template, error) {
 	// Define template functions
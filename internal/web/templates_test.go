@@ -236,3 +236,79 @@ func TestConvoyTemplate_EmptyState(t *testing.T) {
 		t.Error("Template should show empty state message when no convoys")
 	}
 }
+
+func TestShareTemplate_EscapesAgentAndURL(t *testing.T) {
+	tmpl, err := LoadTemplates()
+	if err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+
+	data := ShareData{
+		Agent:     "gastown/<b>nux</b>",
+		ExpiresAt: time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC),
+		StatusURL: "/share/abc123/status",
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "share.html", data); err != nil {
+		t.Fatalf("ExecuteTemplate() error = %v", err)
+	}
+
+	output := buf.String()
+	if strings.Contains(output, "<b>nux</b>") {
+		t.Error("agent name should be HTML-escaped")
+	}
+	if !strings.Contains(output, `const statusURL = "/share/abc123/status";`) {
+		t.Error("status URL should be embedded as a JavaScript string")
+	}
+	if !strings.Contains(output, "2026-03-01 15:00 UTC") {
+		t.Error("template should show when the link expires")
+	}
+}
+
+func TestReportTemplate_LinksAndEscapes(t *testing.T) {
+	tmpl, err := LoadTemplates()
+	if err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+
+	data := ReportData{
+		Title:   "Gas Town activity: home",
+		Window:  "2026-03-01 09:00 → 2026-03-02 09:00",
+		Events:  2,
+		Crashes: 1,
+		Agents: []ReportAgent{{
+			Agent:   "gastown/polecats/nux",
+			Anchor:  "gastownpolecatsnux",
+			Crashes: 1,
+			Entries: []ReportEntry{
+				{Time: "09:30", Type: "spawn", Severity: "info", Detail: []ReportSpan{
+					{Text: "spawned for "}, {Text: "gt-abc", URL: "https://issues.example.com/gt-abc"},
+				}},
+				{Time: "10:00", Type: "crash", Severity: "error", Detail: []ReportSpan{
+					{Text: "crashed (<script>)"}, {Text: "evil", URL: "javascript:alert(1)"},
+				}},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "report.html", data); err != nil {
+		t.Fatalf("ExecuteTemplate() error = %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, `<a href="#gastownpolecatsnux">gastown/polecats/nux</a>`) ||
+		!strings.Contains(output, `<h2 id="gastownpolecatsnux">`) {
+		t.Error("summary should link to the agent's section")
+	}
+	if !strings.Contains(output, `<a href="https://issues.example.com/gt-abc">gt-abc</a>`) {
+		t.Error("issue spans should be linked")
+	}
+	if strings.Contains(output, "<script>") || strings.Contains(output, "javascript:alert") {
+		t.Error("detail text and URLs should be escaped")
+	}
+	if !strings.Contains(output, `class="sev-error"`) {
+		t.Error("entries should carry their severity class")
+	}
+}
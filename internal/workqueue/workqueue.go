@@ -0,0 +1,401 @@
+// Package workqueue keeps a queue of instructions for each agent: 'gt
+// queue add <agent> <message>' appends to the agent's queue, and the
+// daemon hands the instructions to the agent one at a time, oldest first,
+// as nudges, whenever it is ready for more work.
+//
+// An agent is ready for its next instruction when it logs a done event
+// for its current task, or when it has gone idle: its runtime is waiting
+// at its prompt and its pane has printed nothing for IdleAfter, counted
+// from its last delivery. Agents asleep for being idle are woken by the
+// nudge; agents paused by hand or preempted, in DND, or whose session is
+// not running keep their queue until they are back.
+//
+// Queues are kept in the town's .runtime/agent-queues.json. Each delivery
+// is logged as a "queue" event.
+package workqueue
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ctiospl/gastown/internal/agentrt"
+	"github.com/ctiospl/gastown/internal/beads"
+	"github.com/ctiospl/gastown/internal/config"
+	"github.com/ctiospl/gastown/internal/idle"
+	"github.com/ctiospl/gastown/internal/session"
+	"github.com/ctiospl/gastown/internal/tmux"
+	"github.com/ctiospl/gastown/internal/townlog"
+	"github.com/ctiospl/gastown/internal/util"
+)
+
+// EventQueue is the town log event recorded when a queued instruction is
+// delivered to its agent.
+const EventQueue townlog.EventType = "queue"
+
+// IdleAfter is how long an agent waiting at its prompt must have printed
+// nothing before it counts as idle and gets its next instruction.
+const IdleAfter = 2 * time.Minute
+
+// Item is one queued instruction.
+type Item struct {
+	ID        string    `json:"id"`    // e.g. "q-3"
+	Agent     string    `json:"agent"` // e.g. "gastown/crew/max"
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+}
+
+// Nudge returns the nudge delivering the item to its agent.
+func (it Item) Nudge() string {
+	by := ""
+	if it.CreatedBy != "" {
+		by = " by " + it.CreatedBy
+	}
+	return fmt.Sprintf("Next from your work queue (%s, queued%s): %s", it.ID, by, it.Message)
+}
+
+// Path returns where a town's queues are kept.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "agent-queues.json")
+}
+
+// DeliveriesPath returns where the time of each agent's last delivery is
+// kept.
+func DeliveriesPath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "agent-queue-deliveries.json")
+}
+
+// Load returns every queued instruction of the town, oldest first.
+func Load(townRoot string) ([]Item, error) {
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var list []Item
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", Path(townRoot), err)
+	}
+	sort.SliceStable(list, func(i, k int) bool { return idNumber(list[i].ID) < idNumber(list[k].ID) })
+	return list, nil
+}
+
+// locked runs fn holding the lock on the town's queues, so that the
+// CLI's changes and the daemon's deliveries do not overwrite each other.
+func locked(townRoot string, fn func() error) error {
+	return util.WithFileLock(Path(townRoot)+".lock", fn)
+}
+
+func save(townRoot string, list []Item) error {
+	if err := os.MkdirAll(filepath.Dir(Path(townRoot)), 0755); err != nil {
+		return err
+	}
+	if list == nil {
+		list = []Item{}
+	}
+	return util.AtomicWriteJSON(Path(townRoot), list)
+}
+
+// Add appends it to its agent's queue, giving it the next free ID.
+func Add(townRoot string, it Item) (Item, error) {
+	id, err := session.ParseAddress(strings.TrimSuffix(it.Agent, "/"))
+	if err != nil {
+		return Item{}, err
+	}
+	if strings.TrimSpace(it.Message) == "" {
+		return Item{}, fmt.Errorf("a queued instruction needs a message")
+	}
+	it.Agent = id.Address()
+	err = locked(townRoot, func() error {
+		list, err := Load(townRoot)
+		if err != nil {
+			return err
+		}
+		max := 0
+		for _, other := range list {
+			if n := idNumber(other.ID); n > max {
+				max = n
+			}
+		}
+		it.ID = fmt.Sprintf("q-%d", max+1)
+		return save(townRoot, append(list, it))
+	})
+	if err != nil {
+		return Item{}, err
+	}
+	return it, nil
+}
+
+// idNumber returns the number of a queue item ID, or 0 if it has none.
+func idNumber(id string) int {
+	var n int
+	_, _ = fmt.Sscanf(id, "q-%d", &n)
+	return n
+}
+
+// Remove drops the queued instruction with the given ID and returns it.
+func Remove(townRoot, id string) (Item, error) {
+	var removed Item
+	err := locked(townRoot, func() error {
+		list, err := Load(townRoot)
+		if err != nil {
+			return err
+		}
+		for i, it := range list {
+			if it.ID == id {
+				removed = it
+				return save(townRoot, append(list[:i:i], list[i+1:]...))
+			}
+		}
+		return fmt.Errorf("no queued instruction %s", id)
+	})
+	if err != nil {
+		return Item{}, err
+	}
+	return removed, nil
+}
+
+// Clear empties an agent's queue and returns what it held.
+func Clear(townRoot, agent string) ([]Item, error) {
+	var cleared []Item
+	err := locked(townRoot, func() error {
+		list, err := Load(townRoot)
+		if err != nil {
+			return err
+		}
+		var rest []Item
+		for _, it := range list {
+			if sameAgent(it.Agent, agent) {
+				cleared = append(cleared, it)
+			} else {
+				rest = append(rest, it)
+			}
+		}
+		if len(cleared) == 0 {
+			return nil
+		}
+		return save(townRoot, rest)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cleared, nil
+}
+
+// For returns an agent's queue, next first.
+func For(list []Item, agent string) []Item {
+	var out []Item
+	for _, it := range list {
+		if sameAgent(it.Agent, agent) {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// Heads returns the next instruction of each agent with a queue, in the
+// order the agents were first queued for.
+func Heads(list []Item) []Item {
+	seen := make(map[string]bool)
+	var heads []Item
+	for _, it := range list {
+		if key := townlog.AgentKey(it.Agent); !seen[key] {
+			seen[key] = true
+			heads = append(heads, it)
+		}
+	}
+	return heads
+}
+
+func sameAgent(a, b string) bool {
+	return townlog.AgentKey(a) == townlog.AgentKey(b)
+}
+
+// LoadDeliveries returns when each agent last got an instruction, by
+// agent key (see townlog.AgentKey).
+func LoadDeliveries(townRoot string) (map[string]time.Time, error) {
+	data, err := os.ReadFile(DeliveriesPath(townRoot)) //nolint:gosec // G304: path is within the town
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]time.Time{}, nil
+		}
+		return nil, err
+	}
+	deliveries := map[string]time.Time{}
+	if err := json.Unmarshal(data, &deliveries); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", DeliveriesPath(townRoot), err)
+	}
+	return deliveries, nil
+}
+
+func markDelivered(townRoot, agent string, at time.Time) error {
+	return util.WithFileLock(DeliveriesPath(townRoot)+".lock", func() error {
+		deliveries, err := LoadDeliveries(townRoot)
+		if err != nil {
+			return err
+		}
+		deliveries[townlog.AgentKey(agent)] = at
+		return util.AtomicWriteJSON(DeliveriesPath(townRoot), deliveries)
+	})
+}
+
+// Status is what is known of an agent when deciding whether it is ready
+// for its next instruction.
+type Status struct {
+	Running bool      // its session and runtime are up
+	Paused  bool      // paused by hand or preempted: not to be disturbed
+	DND     bool      // in one of the town's DND windows, or muted
+	Asleep  bool      // put to sleep for being idle
+	Prompt  bool      // its pane shows the runtime waiting for input
+	Output  time.Time // when its pane last printed output
+	Done    time.Time // when it last logged a done event
+}
+
+// Ready reports whether an agent in status s is ready for the next
+// instruction of its queue, queued at queued and with the previous one
+// delivered at delivered (zero if none), and says why.
+func Ready(s Status, queued, delivered, now time.Time) (string, bool) {
+	if !s.Running || s.Paused || s.DND {
+		return "", false
+	}
+	since := queued
+	if delivered.After(since) {
+		since = delivered
+	}
+	if s.Done.After(since) {
+		return "finished its task", true
+	}
+	if s.Asleep {
+		return "asleep while idle", true
+	}
+	quiet := s.Output
+	if delivered.After(quiet) {
+		quiet = delivered
+	}
+	if s.Prompt && now.Sub(quiet) >= IdleAfter {
+		return fmt.Sprintf("idle for %dm", int(now.Sub(quiet).Minutes())), true
+	}
+	return "", false
+}
+
+// Check returns the status of an agent.
+func Check(t *tmux.Tmux, townRoot, agent string) (Status, error) {
+	var s Status
+	id, err := session.ParseAddress(agent)
+	if err != nil {
+		return s, err
+	}
+	name := id.SessionName()
+	if ok, err := t.HasSession(name); err != nil || !ok {
+		return s, err
+	}
+	pauses, err := session.LoadPauses(townRoot)
+	if err != nil {
+		return s, err
+	}
+	if p, ok := pauses[name]; ok {
+		s.Asleep = p.By == idle.PausedBy
+		s.Paused = !s.Asleep
+	}
+	s.DND = config.ActiveDNDWindow(townRoot, id.Address()) != nil || muted(townRoot, id)
+	rt := agentrt.ForAgent(townRoot, id.Address())
+	s.Running = rt.Running(t, name)
+	if state, err := t.GetPaneState(name); err == nil {
+		s.Output = state.Activity
+	}
+	if lines, err := t.CapturePaneLines(name, 10); err == nil {
+		s.Prompt = rt.Ready(lines)
+	}
+	events, err := townlog.ReadAgentEvents(townRoot, id.Address())
+	if err != nil {
+		return s, err
+	}
+	for _, e := range events {
+		if e.Type == townlog.EventDone && e.Timestamp.After(s.Done) {
+			s.Done = e.Timestamp
+		}
+	}
+	return s, nil
+}
+
+// muted reports whether the agent's notification level is muted ('gt dnd
+// on'). An agent without a readable agent bead is not muted, as with 'gt
+// nudge'.
+func muted(townRoot string, id *session.AgentIdentity) bool {
+	beadID := beads.AgentBeadID(id.Rig, string(id.Role), id.Name)
+	level, err := beads.New(townRoot).GetAgentNotificationLevel(beadID)
+	return err == nil && level == beads.NotifyMuted
+}
+
+// DeliverReady delivers the next instruction of each agent that is ready
+// for it, and returns those delivered. An instruction whose delivery
+// fails stays queued, to be tried again.
+func DeliverReady(t *tmux.Tmux, townRoot string, now time.Time) ([]Item, error) {
+	list, err := Load(townRoot)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	deliveries, err := LoadDeliveries(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var delivered []Item
+	var errs []string
+	for _, it := range Heads(list) {
+		s, err := Check(t, townRoot, it.Agent)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", it.Agent, err))
+			continue
+		}
+		why, ok := Ready(s, it.CreatedAt, deliveries[townlog.AgentKey(it.Agent)], now)
+		if !ok {
+			continue
+		}
+		if err := Deliver(townRoot, it, why, now); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", it.ID, err))
+			continue
+		}
+		delivered = append(delivered, it)
+	}
+	if len(errs) > 0 {
+		return delivered, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return delivered, nil
+}
+
+// Deliver nudges the item's agent with it, removes it from the queue, and
+// logs the delivery; why says what made the agent ready for it. The nudge
+// is forced past DND: Check has already found the agent out of DND, and
+// an agent in DND is not Ready.
+func Deliver(townRoot string, it Item, why string, now time.Time) error {
+	cmd := exec.Command("gt", "nudge", "--force", it.Agent, it.Nudge()) //nolint:gosec // G204: args come from the town's queues
+	cmd.Dir = townRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if i := strings.LastIndex(msg, "\n"); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return fmt.Errorf("gt nudge: %v: %s", err, msg)
+	}
+	if _, err := Remove(townRoot, it.ID); err != nil {
+		return err
+	}
+	if err := markDelivered(townRoot, it.Agent, now); err != nil {
+		return err
+	}
+	_ = townlog.NewLogger(townRoot).LogEvent(townlog.Event{
+		Timestamp: now,
+		Type:      EventQueue,
+		Agent:     it.Agent,
+		Context:   fmt.Sprintf("%s delivered (%s): %s", it.ID, why, it.Message),
+	})
+	return nil
+}
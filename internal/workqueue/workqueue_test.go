@@ -0,0 +1,140 @@
+package workqueue
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	town := t.TempDir()
+	now := time.Now()
+	for _, it := range []Item{
+		{Agent: "gastown/crew/max", Message: "fix the flaky test next"},
+		{Agent: "gastown/Toast", Message: "rebase onto main"},
+		{Agent: "gastown/crew/max/", Message: "then update the changelog", CreatedBy: "mayor"},
+	} {
+		it.CreatedAt = now
+		if _, err := Add(town, it); err != nil {
+			t.Fatalf("Add(%+v): %v", it, err)
+		}
+	}
+	for _, bad := range []Item{{Agent: "gastown/crew/", Message: "x"}, {Agent: "gastown/crew/max", Message: " "}} {
+		if _, err := Add(town, bad); err == nil {
+			t.Errorf("Add(%+v) succeeded, want an error", bad)
+		}
+	}
+
+	list, err := Load(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	max := For(list, "gastown/crew/max")
+	if len(max) != 2 || max[0].ID != "q-1" || max[1].ID != "q-3" || max[1].Agent != "gastown/crew/max" {
+		t.Errorf("max's queue = %+v", max)
+	}
+	if toast := For(list, "gastown/polecats/Toast"); len(toast) != 1 || toast[0].Agent != "gastown/polecats/Toast" {
+		t.Errorf("Toast's queue = %+v", toast)
+	}
+	heads := Heads(list)
+	if len(heads) != 2 || heads[0].ID != "q-1" || heads[1].ID != "q-2" {
+		t.Errorf("Heads = %+v", heads)
+	}
+	if got := max[1].Nudge(); got != "Next from your work queue (q-3, queued by mayor): then update the changelog" {
+		t.Errorf("Nudge = %q", got)
+	}
+
+	if _, err := Remove(town, "q-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Remove(town, "q-1"); err == nil {
+		t.Error("removing q-1 twice succeeded")
+	}
+	cleared, err := Clear(town, "gastown/crew/max")
+	if err != nil || len(cleared) != 1 || cleared[0].ID != "q-3" {
+		t.Errorf("Clear = %+v, %v", cleared, err)
+	}
+	list, _ = Load(town)
+	if len(list) != 1 || list[0].ID != "q-2" {
+		t.Errorf("after clearing max: %+v", list)
+	}
+	if it, _ := Add(town, Item{Agent: "gastown/crew/max", Message: "again"}); it.ID != "q-3" {
+		t.Errorf("next ID = %s, want q-3", it.ID)
+	}
+}
+
+// TestConcurrentAdd checks that 'gt queue add' run while the daemon
+// removes delivered instructions loses none of them.
+func TestConcurrentAdd(t *testing.T) {
+	town := t.TempDir()
+	for i := 0; i < 10; i++ {
+		if _, err := Add(town, Item{Agent: "gastown/Toast", Message: "old"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := Add(town, Item{Agent: "gastown/crew/max", Message: fmt.Sprintf("task %d", i)}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := Remove(town, fmt.Sprintf("q-%d", i)); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	list, err := Load(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]bool{}
+	for _, it := range For(list, "gastown/crew/max") {
+		ids[it.ID] = true
+	}
+	if len(ids) != 20 {
+		t.Errorf("max has %d distinct queued instructions, want 20: %+v", len(ids), list)
+	}
+	if toast := For(list, "gastown/Toast"); len(toast) != 0 {
+		t.Errorf("Toast's queue = %+v, want empty", toast)
+	}
+}
+
+func TestReady(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	queued := now.Add(-10 * time.Minute)
+	tests := []struct {
+		name      string
+		s         Status
+		delivered time.Time
+		want      string // "" for not ready
+	}{
+		{"not running", Status{Prompt: true, Done: now}, time.Time{}, ""},
+		{"paused", Status{Running: true, Paused: true, Done: now}, time.Time{}, ""},
+		{"in DND", Status{Running: true, DND: true, Done: now}, time.Time{}, ""},
+		{"done since queued", Status{Running: true, Output: now, Done: now.Add(-time.Minute)}, time.Time{}, "finished its task"},
+		{"done before queued", Status{Running: true, Output: now, Done: queued.Add(-time.Minute)}, time.Time{}, ""},
+		{"done before last delivery", Status{Running: true, Output: now, Done: now.Add(-3 * time.Minute)}, now.Add(-2 * time.Minute), ""},
+		{"asleep", Status{Running: true, Asleep: true}, time.Time{}, "asleep while idle"},
+		{"idle at prompt", Status{Running: true, Prompt: true, Output: now.Add(-5 * time.Minute)}, time.Time{}, "idle for 5m"},
+		{"quiet but not at prompt", Status{Running: true, Output: now.Add(-5 * time.Minute)}, time.Time{}, ""},
+		{"printing", Status{Running: true, Prompt: true, Output: now.Add(-time.Minute)}, time.Time{}, ""},
+		{"just delivered", Status{Running: true, Prompt: true, Output: now.Add(-5 * time.Minute)}, now.Add(-time.Minute), ""},
+	}
+	for _, tt := range tests {
+		why, ok := Ready(tt.s, queued, tt.delivered, now)
+		if ok != (tt.want != "") || why != tt.want {
+			t.Errorf("%s: Ready = %q, %v; want %q", tt.name, why, ok, tt.want)
+		}
+	}
+}